@@ -0,0 +1,194 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+// Package chain serves an agent built with an LLM framework such as langchaingo
+// or Eino as an A2A agent. The framework is reached through the small Agent
+// interface rather than imported, so a langchaingo agent executor is wrapped
+// with a few lines:
+//
+//	executor := agents.NewExecutor(agent, agents.WithCallbacksHandler(handler))
+//	processor := chain.NewProcessor(chain.AgentFunc(
+//		func(ctx context.Context, inputs map[string]interface{}) (map[string]interface{}, error) {
+//			return chains.Call(ctx, executor, inputs)
+//		}))
+//
+// and an Eino runnable likewise, with its Invoke method. The tool calls of the
+// agent are reported with ReportStep, typically from the callbacks handler of
+// the framework (HandleAgentAction and HandleToolEnd in langchaingo, OnStart
+// and OnEnd of tool components in Eino).
+package chain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// Default input and output keys, those of the langchaingo agent executor.
+const (
+	DefaultInputKey  = "input"
+	DefaultOutputKey = "output"
+)
+
+// Agent runs an agent, or any chain, on its inputs.
+type Agent interface {
+	Run(ctx context.Context, inputs map[string]interface{}) (map[string]interface{}, error)
+}
+
+// AgentFunc is an adapter to allow the use of ordinary functions as Agent.
+type AgentFunc func(ctx context.Context, inputs map[string]interface{}) (map[string]interface{}, error)
+
+// Run implements Agent.
+func (f AgentFunc) Run(ctx context.Context, inputs map[string]interface{}) (map[string]interface{}, error) {
+	return f(ctx, inputs)
+}
+
+// Step is an intermediate step of an agent run, such as a tool call.
+type Step struct {
+	// Tool is the name of the tool called.
+	Tool string `json:"tool,omitempty"`
+	// Input is the input of the tool.
+	Input string `json:"input,omitempty"`
+	// Observation is the output of the tool, empty while it runs.
+	Observation string `json:"observation,omitempty"`
+	// Log is the reasoning of the agent leading to the step.
+	Log string `json:"log,omitempty"`
+}
+
+// text describes the step to the user.
+func (s Step) text() string {
+	switch {
+	case s.Observation != "":
+		return fmt.Sprintf("Tool %s returned: %s", s.Tool, s.Observation)
+	case s.Tool != "":
+		return fmt.Sprintf("Calling tool %s: %s", s.Tool, s.Input)
+	default:
+		return s.Log
+	}
+}
+
+// stepReporterKey is the context key of the step reporter of a run.
+type stepReporterKey struct{}
+
+// ReportStep reports an intermediate step of the agent run of ctx as a working
+// status update of its task. It does nothing outside of runs of a Processor.
+func ReportStep(ctx context.Context, step Step) error {
+	report, ok := ctx.Value(stepReporterKey{}).(func(Step) error)
+	if !ok {
+		return nil
+	}
+	return report(step)
+}
+
+// Processor is a taskmanager.TaskProcessor running an agent on the message of
+// each task. The text of the message becomes the input of the agent and the
+// fields of a data part holding an object further inputs. Each step reported
+// with ReportStep is sent as a working status update carrying the step as a
+// data part. The output becomes the artifact of the task, which then completes,
+// with any other outputs as a data part.
+type Processor struct {
+	agent     Agent
+	inputKey  string
+	outputKey string
+}
+
+var _ taskmanager.TaskProcessor = (*Processor)(nil)
+
+// NewProcessor creates a processor running agent.
+func NewProcessor(agent Agent, opts ...Option) *Processor {
+	p := &Processor{agent: agent, inputKey: DefaultInputKey, outputKey: DefaultOutputKey}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Process implements taskmanager.TaskProcessor. Errors of the agent fail the task.
+func (p *Processor) Process(
+	ctx context.Context,
+	taskID string,
+	message protocol.Message,
+	handle taskmanager.TaskHandle,
+) error {
+	ctx = context.WithValue(ctx, stepReporterKey{}, func(step Step) error {
+		update := protocol.NewMessage(protocol.MessageRoleAgent, []protocol.Part{
+			protocol.NewTextPart(step.text()),
+			dataPart(step),
+		})
+		return handle.UpdateStatus(protocol.TaskStateWorking, &update)
+	})
+	outputs, err := p.agent.Run(ctx, p.inputs(message))
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		reply := protocol.NewMessage(protocol.MessageRoleAgent, []protocol.Part{protocol.NewTextPart(err.Error())})
+		return handle.UpdateStatus(protocol.TaskStateFailed, &reply)
+	}
+	if parts := p.outputParts(outputs); len(parts) > 0 {
+		name := p.outputKey
+		if err := handle.AddArtifact(protocol.Artifact{Name: &name, Parts: parts}); err != nil {
+			return err
+		}
+	}
+	return handle.UpdateStatus(protocol.TaskStateCompleted, nil)
+}
+
+// inputs returns the agent inputs for message.
+func (p *Processor) inputs(message protocol.Message) map[string]interface{} {
+	inputs := make(map[string]interface{})
+	var texts []string
+	for _, part := range message.Parts {
+		var data interface{}
+		switch v := part.(type) {
+		case protocol.TextPart:
+			texts = append(texts, v.Text)
+		case *protocol.TextPart:
+			texts = append(texts, v.Text)
+		case protocol.DataPart:
+			data = v.Data
+		case *protocol.DataPart:
+			data = v.Data
+		}
+		if fields, ok := data.(map[string]interface{}); ok {
+			for k, v := range fields {
+				inputs[k] = v
+			}
+		}
+	}
+	if len(texts) > 0 {
+		inputs[p.inputKey] = strings.Join(texts, "\n")
+	}
+	return inputs
+}
+
+// outputParts returns the artifact parts for outputs: the output as text,
+// unless it is not a string, and the other outputs as a data part.
+func (p *Processor) outputParts(outputs map[string]interface{}) []protocol.Part {
+	var parts []protocol.Part
+	rest := make(map[string]interface{}, len(outputs))
+	for k, v := range outputs {
+		if k == p.outputKey {
+			if text, ok := v.(string); ok {
+				parts = append(parts, protocol.NewTextPart(text))
+				continue
+			}
+		}
+		rest[k] = v
+	}
+	if len(rest) > 0 {
+		parts = append(parts, dataPart(rest))
+	}
+	return parts
+}
+
+// dataPart returns a data part holding data.
+func dataPart(data interface{}) protocol.DataPart {
+	return protocol.DataPart{Type: protocol.PartTypeData, Data: data}
+}
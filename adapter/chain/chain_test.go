@@ -0,0 +1,87 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package chain
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// calculator is an agent calling a calculator tool once, failing on "fail".
+var calculator = AgentFunc(func(ctx context.Context, inputs map[string]interface{}) (map[string]interface{}, error) {
+	if inputs[DefaultInputKey] == "fail" {
+		return nil, errors.New("agent failed")
+	}
+	if err := ReportStep(ctx, Step{Tool: "calculator", Input: "6*7"}); err != nil {
+		return nil, err
+	}
+	if err := ReportStep(ctx, Step{Tool: "calculator", Input: "6*7", Observation: "42"}); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		DefaultOutputKey: "The answer is 42.",
+		"precision":      inputs["precision"],
+	}, nil
+})
+
+func TestProcessor(t *testing.T) {
+	tm, err := taskmanager.NewMemoryTaskManager(NewProcessor(calculator))
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := tm.OnSendTaskSubscribe(ctx, protocol.SendTaskParams{
+		ID: "task-1",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{
+			protocol.NewTextPart("What is 6 times 7?"),
+			dataPart(map[string]interface{}{"precision": 2}),
+		}),
+	})
+	require.NoError(t, err)
+	var (
+		steps    []string
+		artifact *protocol.Artifact
+	)
+	for event := range events {
+		switch e := event.(type) {
+		case protocol.TaskStatusUpdateEvent:
+			if e.Status.State == protocol.TaskStateWorking && e.Status.Message != nil {
+				steps = append(steps, e.Status.Message.Parts[0].(protocol.TextPart).Text)
+			}
+		case protocol.TaskArtifactUpdateEvent:
+			artifact = &e.Artifact
+		}
+		if status, ok := event.(protocol.TaskStatusUpdateEvent); ok && status.Final {
+			assert.Equal(t, protocol.TaskStateCompleted, status.Status.State)
+			break
+		}
+	}
+	assert.Equal(t, []string{"Calling tool calculator: 6*7", "Tool calculator returned: 42"}, steps)
+	require.NotNil(t, artifact)
+	require.Len(t, artifact.Parts, 2)
+	assert.Equal(t, "The answer is 42.", artifact.Parts[0].(protocol.TextPart).Text)
+	assert.Equal(t, map[string]interface{}{"precision": 2}, artifact.Parts[1].(protocol.DataPart).Data)
+
+	task, err := tm.OnSendTask(context.Background(), protocol.SendTaskParams{
+		ID:      "task-2",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("fail")}),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, protocol.TaskStateFailed, task.Status.State)
+	assert.Equal(t, "agent failed", task.Status.Message.Parts[0].(protocol.TextPart).Text)
+}
+
+func TestReportStep_OutsideRun(t *testing.T) {
+	assert.NoError(t, ReportStep(context.Background(), Step{Tool: "calculator"}))
+}
@@ -0,0 +1,26 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package chain
+
+// Option is a function that configures the Processor.
+type Option func(*Processor)
+
+// WithInputKey sets the input key under which the message text is passed to
+// the agent. Defaults to DefaultInputKey.
+func WithInputKey(key string) Option {
+	return func(p *Processor) {
+		p.inputKey = key
+	}
+}
+
+// WithOutputKey sets the output key of the agent whose value is the answer.
+// Defaults to DefaultOutputKey.
+func WithOutputKey(key string) Option {
+	return func(p *Processor) {
+		p.outputKey = key
+	}
+}
@@ -0,0 +1,151 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"trpc.group/trpc-go/trpc-a2a-go/sse"
+)
+
+// doneData is the data of the event ending a completion stream.
+const doneData = "[DONE]"
+
+// Client calls an OpenAI-compatible chat completions API.
+type Client struct {
+	endpoint   string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewClient creates a client of the API at baseURL, e.g.
+// "https://api.openai.com/v1", to which "/chat/completions" is appended.
+func NewClient(baseURL string, opts ...ClientOption) (*Client, error) {
+	if u, err := url.Parse(baseURL); err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("openai: invalid base URL %q", baseURL)
+	}
+	c := &Client{
+		endpoint:   strings.TrimSuffix(baseURL, "/") + "/chat/completions",
+		httpClient: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// CreateChatCompletion requests a completion, ignoring req.Stream.
+func (c *Client) CreateChatCompletion(
+	ctx context.Context,
+	req ChatCompletionRequest,
+) (*ChatCompletionResponse, error) {
+	req.Stream, req.StreamOptions = false, nil
+	resp, err := c.post(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	var completion ChatCompletionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&completion); err != nil {
+		return nil, fmt.Errorf("openai: failed to decode completion: %w", err)
+	}
+	return &completion, nil
+}
+
+// CreateChatCompletionStream requests a streamed completion, whose chunks are
+// read from the returned stream, which must be closed.
+func (c *Client) CreateChatCompletionStream(ctx context.Context, req ChatCompletionRequest) (*Stream, error) {
+	req.Stream = true
+	if req.StreamOptions == nil {
+		req.StreamOptions = &StreamOptions{IncludeUsage: true}
+	}
+	resp, err := c.post(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return &Stream{body: resp.Body, reader: sse.NewEventReader(resp.Body)}, nil
+}
+
+// post sends req and returns the successful response.
+func (c *Client) post(ctx context.Context, req ChatCompletionRequest) (*http.Response, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("openai: failed to marshal request: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("openai: failed to create http request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("openai: http request failed: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		return nil, apiError(resp)
+	}
+	return resp, nil
+}
+
+// apiError returns the error reported by an unsuccessful response.
+func apiError(resp *http.Response) error {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	var body struct {
+		Error *APIError `json:"error"`
+	}
+	if err := json.Unmarshal(data, &body); err == nil && body.Error != nil {
+		body.Error.StatusCode = resp.StatusCode
+		return body.Error
+	}
+	return &APIError{StatusCode: resp.StatusCode, Message: string(bytes.TrimSpace(data))}
+}
+
+// Stream reads the chunks of a streamed completion.
+type Stream struct {
+	body   io.ReadCloser
+	reader *sse.EventReader
+}
+
+// Recv returns the next chunk, or io.EOF at the end of the completion.
+func (s *Stream) Recv() (*ChatCompletionChunk, error) {
+	for {
+		data, _, err := s.reader.ReadEvent()
+		if len(data) > 0 {
+			if string(data) == doneData {
+				return nil, io.EOF
+			}
+			var chunk ChatCompletionChunk
+			if err := json.Unmarshal(data, &chunk); err != nil {
+				return nil, fmt.Errorf("openai: failed to decode chunk: %w", err)
+			}
+			return &chunk, nil
+		}
+		if errors.Is(err, io.EOF) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return nil, fmt.Errorf("openai: failed to read stream: %w", err)
+		}
+	}
+}
+
+// Close releases the stream.
+func (s *Stream) Close() error {
+	return s.body.Close()
+}
@@ -0,0 +1,113 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package openai
+
+import (
+	"encoding/json"
+	"strings"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// ChatMessages converts A2A messages to chat messages: user messages keep the
+// user role and agent messages take the assistant role. Text parts become
+// text, data parts their JSON encoding and image file parts image content
+// parts. Other file parts are named in the text, as the model cannot read them.
+func ChatMessages(messages []protocol.Message) []ChatMessage {
+	chat := make([]ChatMessage, 0, len(messages))
+	for _, message := range messages {
+		chat = append(chat, ChatMessageOf(message))
+	}
+	return chat
+}
+
+// ChatMessageOf converts an A2A message to a chat message, see ChatMessages.
+func ChatMessageOf(message protocol.Message) ChatMessage {
+	role := RoleUser
+	if message.Role == protocol.MessageRoleAgent {
+		role = RoleAssistant
+	}
+	var (
+		parts  []ContentPart
+		images bool
+	)
+	for _, part := range message.Parts {
+		switch p := part.(type) {
+		case protocol.TextPart:
+			parts = append(parts, textPart(p.Text))
+		case *protocol.TextPart:
+			parts = append(parts, textPart(p.Text))
+		case protocol.DataPart:
+			parts = append(parts, dataPart(p.Data))
+		case *protocol.DataPart:
+			parts = append(parts, dataPart(p.Data))
+		case protocol.FilePart:
+			part := filePart(p.File)
+			images = images || part.Type == ContentPartTypeImageURL
+			parts = append(parts, part)
+		case *protocol.FilePart:
+			part := filePart(p.File)
+			images = images || part.Type == ContentPartTypeImageURL
+			parts = append(parts, part)
+		}
+	}
+	if images {
+		return ChatMessage{Role: role, Parts: parts}
+	}
+	texts := make([]string, 0, len(parts))
+	for _, part := range parts {
+		texts = append(texts, part.Text)
+	}
+	return ChatMessage{Role: role, Content: strings.Join(texts, "\n")}
+}
+
+// textPart returns a text content part.
+func textPart(text string) ContentPart {
+	return ContentPart{Type: ContentPartTypeText, Text: text}
+}
+
+// dataPart returns the JSON encoding of data as a text content part.
+func dataPart(data interface{}) ContentPart {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return textPart("")
+	}
+	return textPart(string(encoded))
+}
+
+// filePart returns an image content part for an image file, and a text
+// content part naming the file otherwise.
+func filePart(file protocol.FileContent) ContentPart {
+	mimeType := deref(file.MimeType)
+	if strings.HasPrefix(mimeType, "image/") {
+		switch {
+		case file.Bytes != nil:
+			return ContentPart{
+				Type:     ContentPartTypeImageURL,
+				ImageURL: &ImageURL{URL: "data:" + mimeType + ";base64," + *file.Bytes},
+			}
+		case file.URI != nil:
+			return ContentPart{Type: ContentPartTypeImageURL, ImageURL: &ImageURL{URL: *file.URI}}
+		}
+	}
+	name := deref(file.Name)
+	if name == "" {
+		name = deref(file.URI)
+	}
+	if label := strings.TrimSpace(name + " " + mimeType); label != "" {
+		return textPart("[file " + label + "]")
+	}
+	return textPart("[file]")
+}
+
+// deref returns *s, or "" if s is nil.
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,165 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// fakeAPI serves completions of deltas, streamed if requested, and records
+// the last request.
+func fakeAPI(t *testing.T, deltas []string, last *ChatCompletionRequest) *httptest.Server {
+	t.Helper()
+	usage := &Usage{PromptTokens: 3, CompletionTokens: len(deltas), TotalTokens: 3 + len(deltas)}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		assert.Equal(t, "Bearer key", r.Header.Get("Authorization"))
+		var req ChatCompletionRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		*last = req
+		if req.Model != "test-model" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":{"message":"model not found","type":"invalid_request_error"}}`)
+			return
+		}
+		if !req.Stream {
+			var content string
+			for _, delta := range deltas {
+				content += delta
+			}
+			w.Header().Set("Content-Type", "application/json")
+			assert.NoError(t, json.NewEncoder(w).Encode(ChatCompletionResponse{
+				ID:      "cmpl-1",
+				Choices: []Choice{{Message: ChatMessage{Role: RoleAssistant, Content: content}}},
+				Usage:   usage,
+			}))
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, delta := range deltas {
+			data, _ := json.Marshal(ChatCompletionChunk{ID: "cmpl-1", Choices: []ChunkChoice{{Delta: Delta{Content: delta}}}})
+			fmt.Fprintf(w, "data: %s\n\n", data)
+		}
+		data, _ := json.Marshal(ChatCompletionChunk{ID: "cmpl-1", Usage: usage})
+		fmt.Fprintf(w, "data: %s\n\ndata: [DONE]\n\n", data)
+	}))
+}
+
+func TestProcessor(t *testing.T) {
+	var last ChatCompletionRequest
+	api := fakeAPI(t, []string{"Hel", "lo", "!"}, &last)
+	defer api.Close()
+	c, err := NewClient(api.URL+"/v1", WithAPIKey("key"))
+	require.NoError(t, err)
+	tm, err := taskmanager.NewMemoryTaskManager(NewProcessor(c, "test-model", WithSystemPrompt("Be brief.")))
+	require.NoError(t, err)
+	params := protocol.SendTaskParams{
+		ID:      "task-1",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("Hi")}),
+	}
+
+	task, err := tm.OnSendTask(context.Background(), params)
+	require.NoError(t, err)
+	assert.Equal(t, protocol.TaskStateCompleted, task.Status.State)
+	require.Len(t, task.Artifacts, 1)
+	assert.Equal(t, "Hello!", task.Artifacts[0].Parts[0].(protocol.TextPart).Text)
+	usage, ok := protocol.UsageFromMetadata(task.Artifacts[0].Metadata)
+	require.True(t, ok)
+	assert.Equal(t, 6, usage.TotalTokens)
+	assert.False(t, last.Stream)
+	assert.Equal(t, []ChatMessage{
+		{Role: RoleSystem, Content: "Be brief."},
+		{Role: RoleUser, Content: "Hi"},
+	}, last.Messages)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	params.ID = "task-2"
+	events, err := tm.OnSendTaskSubscribe(ctx, params)
+	require.NoError(t, err)
+	var chunks []protocol.Artifact
+	for event := range events {
+		if artifact, ok := event.(protocol.TaskArtifactUpdateEvent); ok {
+			chunks = append(chunks, artifact.Artifact)
+		}
+		if status, ok := event.(protocol.TaskStatusUpdateEvent); ok && status.Final {
+			assert.Equal(t, protocol.TaskStateCompleted, status.Status.State)
+			break
+		}
+	}
+	assert.True(t, last.Stream)
+	require.Len(t, chunks, 3)
+	for i, chunk := range chunks {
+		assert.Equal(t, 0, chunk.Index)
+		assert.Equal(t, i > 0, *chunk.Append)
+		assert.Equal(t, i == 2, *chunk.LastChunk)
+	}
+	assert.Equal(t, "!", chunks[2].Parts[0].(protocol.TextPart).Text)
+	_, ok = protocol.UsageFromMetadata(chunks[2].Metadata)
+	assert.True(t, ok)
+}
+
+func TestProcessor_APIError(t *testing.T) {
+	var last ChatCompletionRequest
+	api := fakeAPI(t, []string{"unused"}, &last)
+	defer api.Close()
+	c, err := NewClient(api.URL+"/v1", WithAPIKey("key"))
+	require.NoError(t, err)
+
+	_, err = c.CreateChatCompletion(context.Background(), ChatCompletionRequest{Model: "other"})
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Equal(t, "invalid_request_error", apiErr.Type)
+
+	tm, err := taskmanager.NewMemoryTaskManager(NewProcessor(c, "other"))
+	require.NoError(t, err)
+	task, err := tm.OnSendTask(context.Background(), protocol.SendTaskParams{
+		ID:      "task-1",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("Hi")}),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, protocol.TaskStateFailed, task.Status.State)
+}
+
+func TestChatMessages(t *testing.T) {
+	data := "aGVsbG8="
+	png := "image/png"
+	messages := ChatMessages([]protocol.Message{
+		protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{
+			protocol.NewTextPart("Describe"),
+			protocol.FilePart{Type: protocol.PartTypeFile, File: protocol.FileContent{Bytes: &data, MimeType: &png}},
+		}),
+		protocol.NewMessage(protocol.MessageRoleAgent, []protocol.Part{
+			protocol.NewTextPart("Sure:"),
+			protocol.DataPart{Type: protocol.PartTypeData, Data: map[string]interface{}{"a": 1}},
+		}),
+	})
+	require.Len(t, messages, 2)
+	assert.Equal(t, ChatMessage{Role: RoleUser, Parts: []ContentPart{
+		{Type: ContentPartTypeText, Text: "Describe"},
+		{Type: ContentPartTypeImageURL, ImageURL: &ImageURL{URL: "data:image/png;base64," + data}},
+	}}, messages[0])
+	assert.Equal(t, ChatMessage{Role: RoleAssistant, Content: "Sure:\n{\"a\":1}"}, messages[1])
+
+	encoded, err := json.Marshal(messages)
+	require.NoError(t, err)
+	var decoded []ChatMessage
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, messages, decoded)
+}
@@ -0,0 +1,63 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package openai
+
+import "net/http"
+
+// ClientOption is a function that configures the Client.
+type ClientOption func(*Client)
+
+// WithAPIKey sets the key sent as bearer token with every request.
+func WithAPIKey(key string) ClientOption {
+	return func(c *Client) {
+		c.apiKey = key
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to reach the API.
+// Defaults to http.DefaultClient.
+func WithHTTPClient(client *http.Client) ClientOption {
+	return func(c *Client) {
+		if client != nil {
+			c.httpClient = client
+		}
+	}
+}
+
+// Option is a function that configures the Processor.
+type Option func(*Processor)
+
+// WithSystemPrompt sets the system message sent before the task message.
+func WithSystemPrompt(prompt string) Option {
+	return func(p *Processor) {
+		p.systemPrompt = prompt
+	}
+}
+
+// WithTemperature sets the sampling temperature of the completions.
+// Defaults to the model default.
+func WithTemperature(temperature float64) Option {
+	return func(p *Processor) {
+		p.temperature = &temperature
+	}
+}
+
+// WithMaxTokens bounds the number of tokens of the completions.
+// Defaults to the model default.
+func WithMaxTokens(maxTokens int) Option {
+	return func(p *Processor) {
+		p.maxTokens = &maxTokens
+	}
+}
+
+// WithArtifactName sets the name of the artifact holding the completion.
+// Defaults to "response".
+func WithArtifactName(name string) Option {
+	return func(p *Processor) {
+		p.artifactName = name
+	}
+}
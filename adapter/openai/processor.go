@@ -0,0 +1,185 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package openai
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// defaultArtifactName is the default name of the artifact holding the completion.
+const defaultArtifactName = "response"
+
+// Processor is a taskmanager.TaskProcessor answering each task with a chat
+// completion of its message by a model, so that the model can be served as an
+// A2A agent:
+//
+//	c, _ := openai.NewClient("https://api.openai.com/v1", openai.WithAPIKey(key))
+//	tm, _ := taskmanager.NewMemoryTaskManager(openai.NewProcessor(c, "gpt-4o-mini"))
+//
+// The completion becomes the artifact of the task, which then completes.
+// For tasks/sendSubscribe requests the completion is streamed, each delta
+// being sent as an appended chunk of the artifact. The token usage reported by
+// the API is recorded under protocol.MetadataKeyUsage in the artifact metadata.
+type Processor struct {
+	client       *Client
+	model        string
+	systemPrompt string
+	temperature  *float64
+	maxTokens    *int
+	artifactName string
+}
+
+var _ taskmanager.TaskProcessor = (*Processor)(nil)
+
+// NewProcessor creates a processor answering tasks with completions of model
+// requested through client.
+func NewProcessor(client *Client, model string, opts ...Option) *Processor {
+	p := &Processor{client: client, model: model, artifactName: defaultArtifactName}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Process implements taskmanager.TaskProcessor. Errors of the API fail the task.
+func (p *Processor) Process(
+	ctx context.Context,
+	taskID string,
+	message protocol.Message,
+	handle taskmanager.TaskHandle,
+) error {
+	req := p.request(message)
+	var err error
+	if handle.IsStreamingRequest() {
+		err = p.stream(ctx, req, handle)
+	} else {
+		err = p.complete(ctx, req, handle)
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		reply := protocol.NewMessage(protocol.MessageRoleAgent, []protocol.Part{protocol.NewTextPart(err.Error())})
+		return handle.UpdateStatus(protocol.TaskStateFailed, &reply)
+	}
+	return handle.UpdateStatus(protocol.TaskStateCompleted, nil)
+}
+
+// request returns the completion request for message.
+func (p *Processor) request(message protocol.Message) ChatCompletionRequest {
+	messages := make([]ChatMessage, 0, 2)
+	if p.systemPrompt != "" {
+		messages = append(messages, ChatMessage{Role: RoleSystem, Content: p.systemPrompt})
+	}
+	return ChatCompletionRequest{
+		Model:       p.model,
+		Messages:    append(messages, ChatMessageOf(message)),
+		Temperature: p.temperature,
+		MaxTokens:   p.maxTokens,
+	}
+}
+
+// complete adds the completion of req as the artifact of the task.
+func (p *Processor) complete(ctx context.Context, req ChatCompletionRequest, handle taskmanager.TaskHandle) error {
+	resp, err := p.client.CreateChatCompletion(ctx, req)
+	if err != nil {
+		return err
+	}
+	if len(resp.Choices) == 0 {
+		return errors.New("openai: completion has no choices")
+	}
+	artifact := p.artifact(resp.Choices[0].Message.Content, resp.Usage)
+	return handle.AddArtifact(artifact)
+}
+
+// stream adds the deltas of the streamed completion of req as chunks of the
+// artifact of the task. The stream is read one chunk ahead, so that the last
+// delta is marked as such.
+func (p *Processor) stream(ctx context.Context, req ChatCompletionRequest, handle taskmanager.TaskHandle) error {
+	stream, err := p.client.CreateChatCompletionStream(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+	var (
+		pending *string
+		usage   *Usage
+		chunks  int
+	)
+	flush := func(last bool) error {
+		artifact := p.artifact(*pending, nil)
+		artifact.Append = boolPtr(chunks > 0)
+		artifact.LastChunk = boolPtr(last)
+		if last {
+			artifact.Metadata = usageMetadata(usage)
+		}
+		chunks++
+		return handle.AddArtifact(artifact)
+	}
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if chunk.Usage != nil {
+			usage = chunk.Usage
+		}
+		if len(chunk.Choices) == 0 || chunk.Choices[0].Delta.Content == "" {
+			continue
+		}
+		if pending != nil {
+			if err := flush(false); err != nil {
+				return err
+			}
+		}
+		content := chunk.Choices[0].Delta.Content
+		pending = &content
+	}
+	if pending == nil {
+		empty := ""
+		pending = &empty
+	}
+	return flush(true)
+}
+
+// artifact returns the artifact holding text.
+func (p *Processor) artifact(text string, usage *Usage) protocol.Artifact {
+	name := p.artifactName
+	return protocol.Artifact{
+		Name:     &name,
+		Parts:    []protocol.Part{protocol.NewTextPart(text)},
+		Metadata: usageMetadata(usage),
+	}
+}
+
+// usageMetadata returns the artifact metadata recording usage, or nil if the
+// usage is unknown.
+func usageMetadata(usage *Usage) map[string]interface{} {
+	if usage == nil {
+		return nil
+	}
+	return map[string]interface{}{
+		protocol.MetadataKeyUsage: protocol.TokenUsage{
+			InputTokens:  usage.PromptTokens,
+			OutputTokens: usage.CompletionTokens,
+			TotalTokens:  usage.TotalTokens,
+		},
+	}
+}
+
+// boolPtr returns a pointer to b.
+func boolPtr(b bool) *bool {
+	return &b
+}
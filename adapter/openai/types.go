@@ -0,0 +1,165 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+// Package openai wraps a chat model served through an OpenAI-compatible chat
+// completions API as an A2A agent. Processor converts the A2A message of a task
+// into a chat completion request and the, optionally streamed, completion into
+// artifacts of the task.
+package openai
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Chat roles.
+const (
+	RoleSystem    = "system"
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+// Content part types.
+const (
+	ContentPartTypeText     = "text"
+	ContentPartTypeImageURL = "image_url"
+)
+
+// ImageURL is the image of an image_url content part, either a URL or a
+// base64 data URL.
+type ImageURL struct {
+	URL string `json:"url"`
+}
+
+// ContentPart is part of the content of a multimodal chat message.
+type ContentPart struct {
+	Type     string    `json:"type"`
+	Text     string    `json:"text,omitempty"`
+	ImageURL *ImageURL `json:"image_url,omitempty"`
+}
+
+// ChatMessage is a message of a chat. Its content is sent as a plain string
+// if it only holds text, and as content parts otherwise.
+type ChatMessage struct {
+	Role    string        `json:"role"`
+	Content string        `json:"-"`
+	Parts   []ContentPart `json:"-"`
+}
+
+// chatMessageJSON is the wire form of ChatMessage.
+type chatMessageJSON struct {
+	Role    string          `json:"role"`
+	Content json.RawMessage `json:"content"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (m ChatMessage) MarshalJSON() ([]byte, error) {
+	var (
+		content []byte
+		err     error
+	)
+	if len(m.Parts) > 0 {
+		content, err = json.Marshal(m.Parts)
+	} else {
+		content, err = json.Marshal(m.Content)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(chatMessageJSON{Role: m.Role, Content: content})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (m *ChatMessage) UnmarshalJSON(data []byte) error {
+	var raw chatMessageJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*m = ChatMessage{Role: raw.Role}
+	if len(raw.Content) == 0 || string(raw.Content) == "null" {
+		return nil
+	}
+	if raw.Content[0] == '"' {
+		return json.Unmarshal(raw.Content, &m.Content)
+	}
+	if err := json.Unmarshal(raw.Content, &m.Parts); err != nil {
+		return fmt.Errorf("invalid chat message content: %w", err)
+	}
+	return nil
+}
+
+// StreamOptions configures streamed completions.
+type StreamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+// ChatCompletionRequest is a request of the chat completions API.
+type ChatCompletionRequest struct {
+	Model         string         `json:"model"`
+	Messages      []ChatMessage  `json:"messages"`
+	Temperature   *float64       `json:"temperature,omitempty"`
+	MaxTokens     *int           `json:"max_tokens,omitempty"`
+	Stream        bool           `json:"stream,omitempty"`
+	StreamOptions *StreamOptions `json:"stream_options,omitempty"`
+}
+
+// Usage is the token usage of a completion.
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// Choice is a completion choice.
+type Choice struct {
+	Index        int         `json:"index"`
+	Message      ChatMessage `json:"message"`
+	FinishReason string      `json:"finish_reason"`
+}
+
+// ChatCompletionResponse is the response of a non-streamed completion.
+type ChatCompletionResponse struct {
+	ID      string   `json:"id"`
+	Model   string   `json:"model"`
+	Choices []Choice `json:"choices"`
+	Usage   *Usage   `json:"usage,omitempty"`
+}
+
+// Delta is the increment of a choice carried by a stream chunk.
+type Delta struct {
+	Role    string `json:"role,omitempty"`
+	Content string `json:"content,omitempty"`
+}
+
+// ChunkChoice is a choice of a stream chunk.
+type ChunkChoice struct {
+	Index        int     `json:"index"`
+	Delta        Delta   `json:"delta"`
+	FinishReason *string `json:"finish_reason"`
+}
+
+// ChatCompletionChunk is a chunk of a streamed completion.
+type ChatCompletionChunk struct {
+	ID      string        `json:"id"`
+	Model   string        `json:"model"`
+	Choices []ChunkChoice `json:"choices"`
+	Usage   *Usage        `json:"usage,omitempty"`
+}
+
+// APIError is an error returned by the chat completions API.
+type APIError struct {
+	StatusCode int    `json:"-"`
+	Message    string `json:"message"`
+	Type       string `json:"type"`
+}
+
+// Error implements error.
+func (e *APIError) Error() string {
+	if e.Type != "" {
+		return fmt.Sprintf("openai: http status %d: %s (%s)", e.StatusCode, e.Message, e.Type)
+	}
+	return fmt.Sprintf("openai: http status %d: %s", e.StatusCode, e.Message)
+}
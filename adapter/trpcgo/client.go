@@ -0,0 +1,90 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package trpcgo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/client"
+)
+
+// Node is a node of a service, as listed by a tRPC naming discovery.
+type Node struct {
+	// Address is the host:port of the node.
+	Address string
+	// Metadata holds the metadata registered with the node.
+	Metadata map[string]interface{}
+}
+
+// Discovery lists the nodes of a service, like the discovery of the tRPC naming
+// plugins. A tRPC discovery d adapts to it with:
+//
+//	trpcgo.DiscoveryFunc(func(service string) ([]trpcgo.Node, error) {
+//		nodes, err := d.List(service)
+//		// Convert each *registry.Node to a trpcgo.Node.
+//	})
+type Discovery interface {
+	List(serviceName string) ([]Node, error)
+}
+
+// DiscoveryFunc is an adapter to allow the use of ordinary functions as Discovery.
+type DiscoveryFunc func(serviceName string) ([]Node, error)
+
+// List implements Discovery.
+func (f DiscoveryFunc) List(serviceName string) ([]Node, error) {
+	return f(serviceName)
+}
+
+// NewResolver returns a resolver, for client.WithResolver, polling the nodes
+// of serviceName every interval and addressing each with scheme and path,
+// e.g. "http" and "/".
+func NewResolver(d Discovery, serviceName, scheme, path string, interval time.Duration) *client.PollingResolver {
+	return &client.PollingResolver{
+		Interval: interval,
+		Lookup: func(ctx context.Context) ([]string, error) {
+			nodes, err := d.List(serviceName)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list nodes of %s: %w", serviceName, err)
+			}
+			urls := make([]string, 0, len(nodes))
+			for _, node := range nodes {
+				u := url.URL{Scheme: scheme, Host: node.Address, Path: path}
+				urls = append(urls, u.String())
+			}
+			return urls, nil
+		},
+	}
+}
+
+// Invoker sends an HTTP request of the client.
+type Invoker func(ctx context.Context, req *http.Request) (*http.Response, error)
+
+// ClientFilter intercepts the HTTP requests of a client, like a tRPC client
+// filter: it calls next to send a request, e.g. after injecting trace headers,
+// and can observe the response.
+type ClientFilter func(ctx context.Context, req *http.Request, next Invoker) (*http.Response, error)
+
+// RequestHandler returns a request handler, for client.WithHttpReqHandler,
+// sending requests through filters, first to last.
+func RequestHandler(filters ...ClientFilter) client.HttpReqHandler {
+	return func(ctx context.Context, httpClient *http.Client, req *http.Request) (*http.Response, error) {
+		invoke := Invoker(func(ctx context.Context, req *http.Request) (*http.Response, error) {
+			return httpClient.Do(req.WithContext(ctx))
+		})
+		for i := len(filters) - 1; i >= 0; i-- {
+			filter, next := filters[i], invoke
+			invoke = func(ctx context.Context, req *http.Request) (*http.Response, error) {
+				return filter(ctx, req, next)
+			}
+		}
+		return invoke(ctx, req)
+	}
+}
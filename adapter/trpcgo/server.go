@@ -0,0 +1,147 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+// Package trpcgo integrates A2A agents and clients with the tRPC-Go framework.
+// It mirrors the shapes of the tRPC-Go extension points rather than importing
+// the framework, so that the A2A module stays free of its dependency tree; the
+// glue to tRPC-Go is a few lines of user code.
+//
+// On the server side, NewHandler runs the JSON-RPC requests of an A2A server
+// through a chain of ServerFilters, which adapt the tRPC server filters of the
+// service, and the handler is registered as a tRPC HTTP service:
+//
+//	a2aServer, _ := server.NewA2AServer(card, tm)
+//	handler := trpcgo.NewHandler(a2aServer.Handler(), func(ctx context.Context,
+//		req *trpcgo.Request, next trpcgo.HandleFunc) error {
+//		_, err := tracingFilter(ctx, req, func(ctx context.Context, req interface{}) (interface{}, error) {
+//			return nil, next(ctx, req.(*trpcgo.Request))
+//		})
+//		return err
+//	})
+//	s := trpc.NewServer()
+//	thttp.RegisterNoProtocolServiceMux(s.Service(trpcgo.ServiceName("app", "server", "a2a")), handler)
+//
+// On the client side, NewResolver addresses the agent replicas through a tRPC
+// naming discovery and RequestHandler runs the requests of the client through
+// ClientFilters adapting tRPC client filters, e.g. for tracing and metrics.
+//
+// SkillProcessor serves a unary method of an existing tRPC service as an A2A
+// skill, translating messages with a MessageCodec such as JSONCodec.
+package trpcgo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+)
+
+// Request is the JSON-RPC request of an A2A call, as seen by server filters.
+type Request struct {
+	// Method is the JSON-RPC method, e.g. protocol.MethodTasksSend.
+	Method string
+	// ID is the JSON-RPC request ID.
+	ID interface{}
+	// TaskID is the ID of the task the call is about, if any.
+	TaskID string
+	// HTTP is the HTTP request carrying the call.
+	HTTP *http.Request
+}
+
+// HandleFunc handles a request, writing the response of the A2A server.
+type HandleFunc func(ctx context.Context, req *Request) error
+
+// ServerFilter intercepts requests before they reach the A2A server, like a
+// tRPC server filter: it calls next to pass a request on, possibly with a
+// derived context, or returns an error to reject it. The error is returned to
+// the caller as a JSON-RPC error, internal unless it already is one.
+type ServerFilter func(ctx context.Context, req *Request, next HandleFunc) error
+
+// ServiceName returns the tRPC service name "trpc.app.server.service".
+func ServiceName(app, server, service string) string {
+	return strings.Join([]string{"trpc", app, server, service}, ".")
+}
+
+// NewHandler returns a handler running the JSON-RPC requests to h through
+// filters, first to last. Other requests, such as those for the agent card,
+// reach h directly.
+func NewHandler(h http.Handler, filters ...ServerFilter) http.Handler {
+	if len(filters) == 0 {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req, ok := parseRequest(r)
+		if !ok {
+			h.ServeHTTP(w, r)
+			return
+		}
+		var called bool
+		handle := HandleFunc(func(ctx context.Context, req *Request) error {
+			called = true
+			h.ServeHTTP(w, req.HTTP.WithContext(ctx))
+			return nil
+		})
+		for i := len(filters) - 1; i >= 0; i-- {
+			filter, next := filters[i], handle
+			handle = func(ctx context.Context, req *Request) error {
+				return filter(ctx, req, next)
+			}
+		}
+		err := handle(r.Context(), req)
+		if err == nil {
+			return
+		}
+		if called {
+			log.Warnf("Filter failed after the A2A server handled %s: %v", req.Method, err)
+			return
+		}
+		writeError(w, req.ID, err)
+	})
+}
+
+// parseRequest reads the JSON-RPC method and IDs of r, leaving its body
+// intact. It fails for requests that are not JSON-RPC calls.
+func parseRequest(r *http.Request) (*Request, bool) {
+	if r.Method != http.MethodPost || r.Body == nil {
+		return nil, false
+	}
+	body, err := io.ReadAll(r.Body)
+	r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return nil, false
+	}
+	var call struct {
+		Method string      `json:"method"`
+		ID     interface{} `json:"id"`
+		Params struct {
+			ID string `json:"id"`
+		} `json:"params"`
+	}
+	if err := json.Unmarshal(body, &call); err != nil || call.Method == "" {
+		return nil, false
+	}
+	return &Request{Method: call.Method, ID: call.ID, TaskID: call.Params.ID, HTTP: r}, true
+}
+
+// writeError answers a request rejected by a filter.
+func writeError(w http.ResponseWriter, id interface{}, err error) {
+	var rpcErr *jsonrpc.Error
+	if !errors.As(err, &rpcErr) {
+		rpcErr = jsonrpc.ErrInternalError(err.Error())
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(jsonrpc.NewErrorResponse(id, rpcErr)); err != nil {
+		log.Errorf("Failed to write filter error response: %v", err)
+	}
+}
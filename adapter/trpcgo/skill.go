@@ -0,0 +1,173 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package trpcgo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// skillArtifactName is the name of the artifact holding the response of a skill.
+const skillArtifactName = "response"
+
+// UnaryFunc calls a unary tRPC method, like the handler of a tRPC service
+// method or a call through its client proxy.
+type UnaryFunc func(ctx context.Context, req interface{}) (interface{}, error)
+
+// MessageCodec translates between A2A messages and the request and response
+// messages of a tRPC method.
+type MessageCodec interface {
+	// DecodeRequest returns the request of the method carried by message.
+	DecodeRequest(message protocol.Message) (interface{}, error)
+	// EncodeResponse returns the parts carrying rsp, the response of the method.
+	EncodeResponse(rsp interface{}) ([]protocol.Part, error)
+}
+
+// JSONCodec is a MessageCodec carrying the messages of a method as JSON. The
+// request is decoded from the first data part of the message, or else from
+// its first text part; the response is encoded as a data part. Proto messages
+// are supported by setting Marshal and Unmarshal to wrappers of the protojson
+// functions.
+type JSONCodec struct {
+	// NewRequest returns an empty request to decode into, e.g. a pointer to
+	// the request proto of the method. Required.
+	NewRequest func() interface{}
+	// Marshal encodes responses. Defaults to json.Marshal.
+	Marshal func(v interface{}) ([]byte, error)
+	// Unmarshal decodes requests. Defaults to json.Unmarshal.
+	Unmarshal func(data []byte, v interface{}) error
+}
+
+// DecodeRequest implements MessageCodec.
+func (c JSONCodec) DecodeRequest(message protocol.Message) (interface{}, error) {
+	if c.NewRequest == nil {
+		return nil, errors.New("JSONCodec requires NewRequest")
+	}
+	data, err := requestJSON(message)
+	if err != nil {
+		return nil, err
+	}
+	unmarshal := c.Unmarshal
+	if unmarshal == nil {
+		unmarshal = json.Unmarshal
+	}
+	req := c.NewRequest()
+	if err := unmarshal(data, req); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	return req, nil
+}
+
+// EncodeResponse implements MessageCodec.
+func (c JSONCodec) EncodeResponse(rsp interface{}) ([]protocol.Part, error) {
+	marshal := c.Marshal
+	if marshal == nil {
+		marshal = json.Marshal
+	}
+	data, err := marshal(rsp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode response: %w", err)
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, fmt.Errorf("response is not JSON: %w", err)
+	}
+	return []protocol.Part{protocol.DataPart{Type: protocol.PartTypeData, Data: value}}, nil
+}
+
+// requestJSON returns the JSON of the first data part of message, or else the
+// text of its first text part.
+func requestJSON(message protocol.Message) ([]byte, error) {
+	var text *string
+	for _, part := range message.Parts {
+		switch p := part.(type) {
+		case protocol.DataPart:
+			return json.Marshal(p.Data)
+		case *protocol.DataPart:
+			return json.Marshal(p.Data)
+		case protocol.TextPart:
+			if text == nil {
+				text = &p.Text
+			}
+		case *protocol.TextPart:
+			if text == nil {
+				text = &p.Text
+			}
+		}
+	}
+	if text == nil {
+		return nil, errors.New("message has no data or text part")
+	}
+	return []byte(*text), nil
+}
+
+// SkillProcessor is a taskmanager.TaskProcessor serving a unary tRPC method as
+// an A2A skill, so that existing tRPC services can be exposed to agents
+// without a task manager of their own:
+//
+//	codec := trpcgo.JSONCodec{NewRequest: func() interface{} { return &pb.TranslateRequest{} }}
+//	processor := trpcgo.NewSkillProcessor(func(ctx context.Context, req interface{}) (interface{}, error) {
+//		return proxy.Translate(ctx, req.(*pb.TranslateRequest))
+//	}, codec)
+//	tm, _ := taskmanager.NewMemoryTaskManager(processor)
+//
+// The message of each task is decoded into a request of the method, and the
+// response becomes the artifact of the task, which then completes. Requests
+// that cannot be decoded and errors of the method fail the task.
+type SkillProcessor struct {
+	invoke UnaryFunc
+	codec  MessageCodec
+}
+
+var _ taskmanager.TaskProcessor = (*SkillProcessor)(nil)
+
+// NewSkillProcessor creates a processor calling invoke with the requests
+// translated by codec.
+func NewSkillProcessor(invoke UnaryFunc, codec MessageCodec) *SkillProcessor {
+	return &SkillProcessor{invoke: invoke, codec: codec}
+}
+
+// Process implements taskmanager.TaskProcessor.
+func (p *SkillProcessor) Process(
+	ctx context.Context,
+	taskID string,
+	message protocol.Message,
+	handle taskmanager.TaskHandle,
+) error {
+	parts, err := p.call(ctx, message)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		reply := protocol.NewMessage(protocol.MessageRoleAgent, []protocol.Part{protocol.NewTextPart(err.Error())})
+		return handle.UpdateStatus(protocol.TaskStateFailed, &reply)
+	}
+	name := skillArtifactName
+	if err := handle.AddArtifact(protocol.Artifact{Name: &name, Parts: parts}); err != nil {
+		return err
+	}
+	return handle.UpdateStatus(protocol.TaskStateCompleted, nil)
+}
+
+// call invokes the method with the request of message and returns the parts
+// of its response.
+func (p *SkillProcessor) call(ctx context.Context, message protocol.Message) ([]protocol.Part, error) {
+	req, err := p.codec.DecodeRequest(message)
+	if err != nil {
+		return nil, err
+	}
+	rsp, err := p.invoke(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return p.codec.EncodeResponse(rsp)
+}
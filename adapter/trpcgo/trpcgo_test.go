@@ -0,0 +1,148 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package trpcgo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/client"
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/server"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// completingProcessor completes every task.
+type completingProcessor struct{}
+
+func (completingProcessor) Process(
+	ctx context.Context,
+	taskID string,
+	message protocol.Message,
+	handle taskmanager.TaskHandle,
+) error {
+	return handle.UpdateStatus(protocol.TaskStateCompleted, nil)
+}
+
+func TestIntegration(t *testing.T) {
+	tm, err := taskmanager.NewMemoryTaskManager(completingProcessor{})
+	require.NoError(t, err)
+	a2aServer, err := server.NewA2AServer(server.AgentCard{Name: "agent", Version: "1.0.0"}, tm)
+	require.NoError(t, err)
+
+	var (
+		mu      sync.Mutex
+		methods []string
+	)
+	handler := NewHandler(a2aServer.Handler(), func(ctx context.Context, req *Request, next HandleFunc) error {
+		mu.Lock()
+		methods = append(methods, req.Method+" "+req.TaskID+" "+req.HTTP.Header.Get("Trace-Id"))
+		mu.Unlock()
+		if req.Method == protocol.MethodTasksCancel {
+			return errors.New("cancellation is disabled")
+		}
+		return next(ctx, req)
+	})
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+	address := ts.Listener.Addr().String()
+
+	discovery := DiscoveryFunc(func(service string) ([]Node, error) {
+		assert.Equal(t, "trpc.app.server.a2a", service)
+		return []Node{{Address: address}}, nil
+	})
+	tracing := func(ctx context.Context, req *http.Request, next Invoker) (*http.Response, error) {
+		req.Header.Set("Trace-Id", "trace-1")
+		return next(ctx, req)
+	}
+	c, err := client.NewA2AClient("http://placeholder",
+		client.WithResolver(NewResolver(discovery, ServiceName("app", "server", "a2a"), "http", "/", time.Minute)),
+		client.WithHttpReqHandler(RequestHandler(tracing)),
+	)
+	require.NoError(t, err)
+	defer c.Close()
+
+	ctx := context.Background()
+	msg := protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")})
+	require.Eventually(t, func() bool {
+		task, err := c.SendTasks(ctx, protocol.SendTaskParams{ID: "task-1", Message: msg})
+		return err == nil && task.Status.State == protocol.TaskStateCompleted
+	}, time.Second, 10*time.Millisecond, "the resolved node serves the task")
+	_, err = c.CancelTasks(ctx, protocol.TaskIDParams{ID: "task-1"})
+	var rpcErr *jsonrpc.Error
+	require.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, "cancellation is disabled", rpcErr.Data)
+
+	// The agent card is not a JSON-RPC call and bypasses the filters.
+	resp, err := http.Get((&url.URL{Scheme: "http", Host: address, Path: protocol.AgentCardPath}).String())
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Contains(t, methods, "tasks/send task-1 trace-1")
+	assert.Contains(t, methods, "tasks/cancel task-1 trace-1")
+	assert.Len(t, methods, 2)
+}
+
+// greetRequest and greetResponse are the messages of a tRPC greeting method.
+type greetRequest struct {
+	Name string `json:"name"`
+}
+
+type greetResponse struct {
+	Greeting string `json:"greeting"`
+}
+
+func TestSkillProcessor(t *testing.T) {
+	codec := JSONCodec{NewRequest: func() interface{} { return &greetRequest{} }}
+	processor := NewSkillProcessor(func(ctx context.Context, req interface{}) (interface{}, error) {
+		name := req.(*greetRequest).Name
+		if name == "" {
+			return nil, errors.New("name is required")
+		}
+		return &greetResponse{Greeting: "hello " + name}, nil
+	}, codec)
+	tm, err := taskmanager.NewMemoryTaskManager(processor)
+	require.NoError(t, err)
+	ctx := context.Background()
+	send := func(taskID string, part protocol.Part) *protocol.Task {
+		task, err := tm.OnSendTask(ctx, protocol.SendTaskParams{
+			ID:      taskID,
+			Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{part}),
+		})
+		require.NoError(t, err)
+		return task
+	}
+
+	task := send("data", protocol.DataPart{Type: protocol.PartTypeData, Data: map[string]interface{}{"name": "a2a"}})
+	assert.Equal(t, protocol.TaskStateCompleted, task.Status.State)
+	require.Len(t, task.Artifacts, 1)
+	assert.Equal(t, map[string]interface{}{"greeting": "hello a2a"},
+		task.Artifacts[0].Parts[0].(protocol.DataPart).Data)
+
+	task = send("text", protocol.NewTextPart(`{"name":"trpc"}`))
+	assert.Equal(t, protocol.TaskStateCompleted, task.Status.State)
+
+	task = send("invalid", protocol.NewTextPart("not json"))
+	assert.Equal(t, protocol.TaskStateFailed, task.Status.State)
+	task = send("failed", protocol.NewTextPart(`{}`))
+	assert.Equal(t, protocol.TaskStateFailed, task.Status.State)
+	require.NotNil(t, task.Status.Message)
+	assert.Equal(t, "name is required", task.Status.Message.Parts[0].(protocol.TextPart).Text)
+}
@@ -0,0 +1,180 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+// Package audit records the actions taken on tasks as an append-only log of
+// audit events. Every event carries a sequence number and the hash of the
+// event before it, so the log written to a sink can be checked for removed,
+// reordered or modified events with Verify.
+//
+// Task managers record state transitions and cancellations once configured
+// with a Logger, and taskmanager.PushDeliveryLog records push delivery attempts:
+//
+//	logger := audit.NewLogger(audit.NewStdoutSink())
+//	tm, err := taskmanager.NewMemoryTaskManager(processor, taskmanager.WithAuditLog(logger))
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// EventType is the kind of action recorded by an audit event.
+type EventType string
+
+// Audit event types.
+const (
+	// EventStateTransition records a change of the state of a task.
+	EventStateTransition EventType = "state_transition"
+	// EventCancellation records a request to cancel a task.
+	EventCancellation EventType = "cancellation"
+	// EventPushDelivery records an attempt to deliver a push notification.
+	EventPushDelivery EventType = "push_delivery"
+)
+
+// Event is an audit event. Events are values: sinks receive copies and the
+// logger keeps none, so a recorded event cannot be changed.
+type Event struct {
+	// Sequence numbers the events of a logger from 1, without gaps.
+	Sequence uint64 `json:"sequence"`
+	// Time is when the event was recorded.
+	Time time.Time `json:"time"`
+	// Type is the kind of action recorded.
+	Type EventType `json:"type"`
+	// TaskID is the ID of the task acted on.
+	TaskID string `json:"taskId"`
+	// Caller is the authenticated user who requested the action, if known.
+	Caller string `json:"caller,omitempty"`
+	// From and To are the previous and new states of a state transition.
+	From protocol.TaskState `json:"from,omitempty"`
+	To   protocol.TaskState `json:"to,omitempty"`
+	// Reason is the reason of a cancellation.
+	Reason protocol.CancelReason `json:"reason,omitempty"`
+	// URL is the URL a push notification was sent to.
+	URL string `json:"url,omitempty"`
+	// StatusCode is the HTTP status of a push delivery response.
+	StatusCode int `json:"statusCode,omitempty"`
+	// Duration is how long a push delivery attempt took.
+	Duration time.Duration `json:"duration,omitempty"`
+	// Error describes why the action failed, if it did.
+	Error string `json:"error,omitempty"`
+	// PrevHash is the Hash of the previous event, empty for the first one.
+	PrevHash string `json:"prevHash,omitempty"`
+	// Hash is the SHA-256 of the event with an empty Hash, in hex.
+	Hash string `json:"hash"`
+}
+
+// computeHash returns the hash of the event, ignoring its Hash field.
+func (e Event) computeHash() (string, error) {
+	e.Hash = ""
+	data, err := json.Marshal(e)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Sink stores audit events. Write is called for one event at a time, in
+// sequence order.
+type Sink interface {
+	// Write stores the event.
+	Write(ctx context.Context, event Event) error
+	// Close flushes and releases the sink.
+	Close() error
+}
+
+// Logger numbers, chains and writes audit events to its sinks.
+// A nil *Logger records nothing. It is safe for concurrent use.
+type Logger struct {
+	sinks []Sink
+
+	mu       sync.Mutex
+	sequence uint64
+	lastHash string
+}
+
+// NewLogger creates a logger writing every event to all sinks.
+func NewLogger(sinks ...Sink) *Logger {
+	return &Logger{sinks: sinks}
+}
+
+// Record stamps the event with the time, its sequence number and hashes and
+// writes it to the sinks. Failures of sinks are logged and returned joined;
+// the event is recorded in the chain regardless, so a later Verify on the
+// failed sink reports the gap.
+func (l *Logger) Record(ctx context.Context, event Event) error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sequence++
+	event.Sequence = l.sequence
+	event.Time = time.Now().UTC()
+	event.PrevHash = l.lastHash
+	hash, err := event.computeHash()
+	if err != nil {
+		return fmt.Errorf("failed to hash audit event: %w", err)
+	}
+	event.Hash = hash
+	l.lastHash = hash
+	var errs []error
+	for _, sink := range l.sinks {
+		if err := sink.Write(ctx, event); err != nil {
+			log.Errorf("Failed to write audit event %d of task %s: %v", event.Sequence, event.TaskID, err)
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close closes the sinks.
+func (l *Logger) Close() error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var errs []error
+	for _, sink := range l.sinks {
+		errs = append(errs, sink.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// Verify checks that events, as read back from a sink, form an unbroken
+// chain: consecutive sequence numbers, matching hashes and unmodified contents.
+func Verify(events []Event) error {
+	for i, event := range events {
+		hash, err := event.computeHash()
+		if err != nil {
+			return fmt.Errorf("event %d: %w", event.Sequence, err)
+		}
+		if hash != event.Hash {
+			return fmt.Errorf("event %d: hash mismatch, the event was modified", event.Sequence)
+		}
+		if i == 0 {
+			continue
+		}
+		prev := events[i-1]
+		if event.Sequence != prev.Sequence+1 {
+			return fmt.Errorf("event %d follows event %d, events are missing", event.Sequence, prev.Sequence)
+		}
+		if event.PrevHash != prev.Hash {
+			return fmt.Errorf("event %d: previous hash mismatch", event.Sequence)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// readEvents decodes the JSON lines of data.
+func readEvents(t *testing.T, data []byte) []Event {
+	var events []Event
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		var event Event
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestLogger_ChainAndVerify(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(NewJSONSink(&buf))
+	ctx := context.Background()
+	require.NoError(t, logger.Record(ctx, Event{Type: EventStateTransition, TaskID: "t1",
+		From: protocol.TaskStateSubmitted, To: protocol.TaskStateWorking}))
+	require.NoError(t, logger.Record(ctx, Event{Type: EventCancellation, TaskID: "t1",
+		Caller: "alice", Reason: protocol.CancelReasonUserRequested}))
+	require.NoError(t, logger.Record(ctx, Event{Type: EventPushDelivery, TaskID: "t1",
+		URL: "http://hook", StatusCode: 500}))
+
+	events := readEvents(t, buf.Bytes())
+	require.Len(t, events, 3)
+	assert.Equal(t, uint64(1), events[0].Sequence)
+	assert.Empty(t, events[0].PrevHash)
+	assert.Equal(t, events[0].Hash, events[1].PrevHash)
+	assert.Equal(t, "alice", events[1].Caller)
+	require.NoError(t, Verify(events))
+
+	modified := append([]Event(nil), events...)
+	modified[1].Caller = "mallory"
+	assert.ErrorContains(t, Verify(modified), "modified")
+	assert.ErrorContains(t, Verify([]Event{events[0], events[2]}), "missing")
+
+	var nilLogger *Logger
+	assert.NoError(t, nilLogger.Record(ctx, Event{TaskID: "t1"}))
+	assert.NoError(t, nilLogger.Close())
+}
+
+func TestFileSink_Rotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	sink, err := NewFileSink(path, FileRotation{MaxSize: 400, MaxBackups: 2})
+	require.NoError(t, err)
+	logger := NewLogger(sink)
+	for i := 0; i < 12; i++ {
+		require.NoError(t, logger.Record(context.Background(), Event{Type: EventStateTransition, TaskID: "task"}))
+	}
+	require.NoError(t, logger.Close())
+
+	var all []Event
+	for _, name := range []string{path + ".2", path + ".1", path} {
+		data, err := os.ReadFile(name)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, len(data), 400)
+		all = append(all, readEvents(t, data)...)
+	}
+	_, err = os.Stat(path + ".3")
+	assert.True(t, os.IsNotExist(err))
+	require.NotEmpty(t, all)
+	assert.Equal(t, uint64(12), all[len(all)-1].Sequence)
+	assert.NoError(t, Verify(all))
+
+	// Reopening appends to the existing file.
+	sink, err = NewFileSink(path, FileRotation{MaxSize: 400, MaxBackups: 2})
+	require.NoError(t, err)
+	assert.Positive(t, sink.size)
+	require.NoError(t, sink.Close())
+}
+
+func TestKafkaSink(t *testing.T) {
+	var keys []string
+	producer := KafkaProducerFunc(func(ctx context.Context, topic string, key, value []byte) error {
+		require.NoError(t, ctx.Err())
+		assert.Equal(t, "audit", topic)
+		keys = append(keys, string(key))
+		if string(key) == "bad" {
+			return errors.New("broker unavailable")
+		}
+		return nil
+	})
+	var buf bytes.Buffer
+	logger := NewLogger(NewKafkaSink(producer, "audit"), NewJSONSink(&buf))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.NoError(t, logger.Record(ctx, Event{TaskID: "t1"}))
+	assert.Error(t, logger.Record(ctx, Event{TaskID: "bad"}))
+	assert.Equal(t, []string{"t1", "bad"}, keys)
+	// The other sinks still receive the event.
+	assert.Len(t, readEvents(t, buf.Bytes()), 2)
+}
@@ -0,0 +1,219 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// JSONSink writes events to a writer as JSON lines.
+type JSONSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewJSONSink creates a sink writing one JSON object per line to w.
+// Closing the sink closes w if it is an io.Closer.
+func NewJSONSink(w io.Writer) *JSONSink {
+	return &JSONSink{w: w}
+}
+
+// NewStdoutSink creates a sink writing JSON lines to the standard output.
+func NewStdoutSink() *JSONSink {
+	return NewJSONSink(nopCloser{os.Stdout})
+}
+
+// Write implements Sink.
+func (s *JSONSink) Write(ctx context.Context, event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.w.Write(append(data, '\n'))
+	return err
+}
+
+// Close implements Sink.
+func (s *JSONSink) Close() error {
+	if closer, ok := s.w.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// nopCloser keeps a JSONSink from closing the standard output.
+type nopCloser struct {
+	io.Writer
+}
+
+// Default rotation settings of FileSink.
+const (
+	defaultMaxFileSize = 100 << 20
+	defaultMaxBackups  = 7
+)
+
+// FileRotation configures when a FileSink rotates its file.
+type FileRotation struct {
+	// MaxSize is the size in bytes beyond which the file is rotated before the
+	// next write. Defaults to 100 MiB.
+	MaxSize int64
+	// MaxBackups is the number of rotated files kept, named path.1 (the most
+	// recent) to path.N. Older files are removed. Defaults to 7.
+	MaxBackups int
+}
+
+// FileSink writes events as JSON lines to a file, rotating it by size.
+type FileSink struct {
+	path     string
+	rotation FileRotation
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// NewFileSink opens, or creates, the file at path for appending events.
+func NewFileSink(path string, rotation FileRotation) (*FileSink, error) {
+	if rotation.MaxSize <= 0 {
+		rotation.MaxSize = defaultMaxFileSize
+	}
+	if rotation.MaxBackups <= 0 {
+		rotation.MaxBackups = defaultMaxBackups
+	}
+	s := &FileSink{path: path, rotation: rotation}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// open opens the file for appending.
+func (s *FileSink) open() error {
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat audit file: %w", err)
+	}
+	s.file = file
+	s.size = info.Size()
+	return nil
+}
+
+// Write implements Sink.
+func (s *FileSink) Write(ctx context.Context, event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return os.ErrClosed
+	}
+	if s.size > 0 && s.size+int64(len(data)) > s.rotation.MaxSize {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := s.file.Write(data)
+	s.size += int64(n)
+	return err
+}
+
+// rotate shifts the backups, moves the file to path.1 and opens a new file.
+// The caller must hold mu.
+func (s *FileSink) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return fmt.Errorf("failed to close audit file: %w", err)
+	}
+	s.file = nil
+	for i := s.rotation.MaxBackups - 1; i > 0; i-- {
+		err := os.Rename(s.backupPath(i), s.backupPath(i+1))
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to rotate audit file: %w", err)
+		}
+	}
+	if err := os.Rename(s.path, s.backupPath(1)); err != nil {
+		return fmt.Errorf("failed to rotate audit file: %w", err)
+	}
+	return s.open()
+}
+
+// backupPath returns the path of the i-th most recent rotated file.
+func (s *FileSink) backupPath(i int) string {
+	return fmt.Sprintf("%s.%d", s.path, i)
+}
+
+// Close implements Sink.
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+// KafkaProducer publishes messages to Kafka. It is implemented with a few
+// lines on top of a Kafka client, such as a kafka-go Writer:
+//
+//	audit.KafkaProducerFunc(func(ctx context.Context, topic string, key, value []byte) error {
+//		return writer.WriteMessages(ctx, kafka.Message{Topic: topic, Key: key, Value: value})
+//	})
+type KafkaProducer interface {
+	// Produce publishes a message and returns once it is acknowledged.
+	Produce(ctx context.Context, topic string, key, value []byte) error
+}
+
+// KafkaProducerFunc is an adapter to allow the use of ordinary functions as KafkaProducer.
+type KafkaProducerFunc func(ctx context.Context, topic string, key, value []byte) error
+
+// Produce implements KafkaProducer.
+func (f KafkaProducerFunc) Produce(ctx context.Context, topic string, key, value []byte) error {
+	return f(ctx, topic, key, value)
+}
+
+// KafkaSink publishes events as JSON messages to a Kafka topic, keyed by task
+// ID so the events of a task stay ordered within a partition.
+type KafkaSink struct {
+	producer KafkaProducer
+	topic    string
+}
+
+// NewKafkaSink creates a sink publishing events to topic through producer.
+// The producer is owned by the caller and not closed by the sink.
+func NewKafkaSink(producer KafkaProducer, topic string) *KafkaSink {
+	return &KafkaSink{producer: producer, topic: topic}
+}
+
+// Write implements Sink. The event is published even if ctx is canceled.
+func (s *KafkaSink) Write(ctx context.Context, event Event) error {
+	value, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return s.producer.Produce(context.WithoutCancel(ctx), s.topic, []byte(event.TaskID), value)
+}
+
+// Close implements Sink.
+func (s *KafkaSink) Close() error {
+	return nil
+}
@@ -0,0 +1,287 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package auth
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/metadata"
+)
+
+// Headers of HMAC-signed requests.
+const (
+	// HMACKeyIDHeader identifies the shared secret the request is signed with.
+	HMACKeyIDHeader = "X-A2A-Key-Id"
+	// HMACTimestampHeader holds the signing time in Unix seconds.
+	HMACTimestampHeader = "X-A2A-Timestamp"
+	// HMACNonceHeader holds a random value unique to the request.
+	HMACNonceHeader = "X-A2A-Nonce"
+	// HMACSignatureHeader holds the hex-encoded HMAC-SHA256 signature.
+	HMACSignatureHeader = "X-A2A-Signature"
+)
+
+// Request signing errors.
+var (
+	ErrMissingSignature = errors.New("missing request signature")
+	ErrInvalidSignature = errors.New("invalid request signature")
+	ErrStaleRequest     = errors.New("request timestamp outside the allowed window")
+	ErrReplayedRequest  = errors.New("request already received")
+)
+
+const (
+	// defaultHMACMaxSkew is the default tolerance of HMACVerifier for
+	// timestamps.
+	defaultHMACMaxSkew = 5 * time.Minute
+	// defaultHMACMaxBodyBytes is the default limit of HMACVerifier for the
+	// bodies it reads.
+	defaultHMACMaxBodyBytes = 10 << 20
+)
+
+// HMACSignature returns the hex-encoded HMAC-SHA256 under secret of a request
+// with method and requestURI, the path and query of its URL, signed with
+// keyID at timestamp with nonce, and of its metadata headers, from which
+// servers may resolve the tenant, and its body.
+func HMACSignature(
+	secret []byte,
+	keyID, method, requestURI, timestamp, nonce string,
+	md metadata.MD,
+	body []byte,
+) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(keyID + "\n" + method + "\n" + requestURI + "\n" + timestamp + "\n" + nonce + "\n"))
+	if md.Len() == 0 {
+		md = metadata.MD{}
+	}
+	// Maps are encoded with their keys sorted, so equal metadata have equal
+	// encodings.
+	encoded, _ := json.Marshal(md)
+	mac.Write(append(encoded, '\n'))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// HMACSigner signs the body of outgoing requests with a shared secret, for
+// deployments where requests cannot be protected by TLS end to end.
+type HMACSigner struct {
+	keyID  string
+	secret []byte
+	now    func() time.Time
+}
+
+// NewHMACSigner creates a signer using secret, registered as keyID on the server.
+func NewHMACSigner(keyID string, secret []byte) *HMACSigner {
+	return &HMACSigner{keyID: keyID, secret: secret, now: time.Now}
+}
+
+// Sign sets the signature headers of req, reading and restoring its body. The
+// signature covers the key ID, method, path and query, metadata headers (see
+// metadata.HeaderPrefix) and body of req. Its other headers are not
+// protected and may be altered in transit.
+func (s *HMACSigner) Sign(req *http.Request) error {
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return fmt.Errorf("failed to read request body: %w", err)
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	timestamp := strconv.FormatInt(s.now().Unix(), 10)
+	nonceHex := hex.EncodeToString(nonce)
+	req.Header.Set(HMACKeyIDHeader, s.keyID)
+	req.Header.Set(HMACTimestampHeader, timestamp)
+	req.Header.Set(HMACNonceHeader, nonceHex)
+	req.Header.Set(HMACSignatureHeader,
+		HMACSignature(s.secret, s.keyID, req.Method, req.URL.RequestURI(), timestamp, nonceHex,
+			metadata.FromHeader(req.Header), body))
+	return nil
+}
+
+// ConfigureClient returns a copy of client signing every request.
+func (s *HMACSigner) ConfigureClient(client *http.Client) *http.Client {
+	transport := &hmacSigningTransport{base: client.Transport, signer: s}
+	if transport.base == nil {
+		transport.base = http.DefaultTransport
+	}
+	newClient := *client
+	newClient.Transport = transport
+	return &newClient
+}
+
+// hmacSigningTransport is an http.RoundTripper that signs requests.
+type hmacSigningTransport struct {
+	base   http.RoundTripper
+	signer *HMACSigner
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *hmacSigningTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Clone the request to avoid modifying the original.
+	reqClone := req.Clone(req.Context())
+	if err := t.signer.Sign(reqClone); err != nil {
+		return nil, err
+	}
+	return t.base.RoundTrip(reqClone)
+}
+
+// HMACVerifier verifies the signature of requests signed by an HMACSigner.
+// Requests must be signed within MaxSkew of the server time, and each nonce is
+// accepted once within that window, so captured requests cannot be replayed.
+type HMACVerifier struct {
+	// Keys maps key IDs to shared secrets.
+	Keys map[string][]byte
+	// MaxSkew is the tolerated difference between the signing time and the
+	// server time. Defaults to 5 minutes.
+	MaxSkew time.Duration
+	// MaxBodyBytes limits the bodies read to verify requests, which are
+	// rejected beyond it. Defaults to 10 MiB.
+	MaxBodyBytes int64
+	// now returns the current time; time.Now if nil.
+	now func() time.Time
+
+	mu sync.Mutex
+	// seen holds the nonces received within the window.
+	seen map[string]struct{}
+	// expiries lists the nonces of seen by expiry, soonest first, so that
+	// expired nonces are pruned without scanning seen.
+	expiries []seenNonce
+}
+
+// seenNonce is a nonce remembered by an HMACVerifier until its expiry.
+type seenNonce struct {
+	nonce  string
+	expiry time.Time
+}
+
+// NewHMACVerifier creates a verifier accepting requests signed with keys.
+func NewHMACVerifier(keys map[string][]byte, maxSkew time.Duration) *HMACVerifier {
+	return &HMACVerifier{Keys: keys, MaxSkew: maxSkew}
+}
+
+// Verify checks the signature and timestamp of r and that its nonce was not
+// used before, reading and restoring its body, up to MaxBodyBytes. It returns
+// the key ID the request is signed with, and an *http.MaxBytesError if the body
+// is too large.
+func (v *HMACVerifier) Verify(r *http.Request) (string, error) {
+	keyID := r.Header.Get(HMACKeyIDHeader)
+	timestamp := r.Header.Get(HMACTimestampHeader)
+	nonce := r.Header.Get(HMACNonceHeader)
+	signature := r.Header.Get(HMACSignatureHeader)
+	if keyID == "" || timestamp == "" || nonce == "" || signature == "" {
+		return "", ErrMissingSignature
+	}
+	secret, ok := v.Keys[keyID]
+	if !ok {
+		return "", ErrInvalidSignature
+	}
+	signedAt, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return "", ErrInvalidSignature
+	}
+	now := v.currentTime()
+	maxSkew := v.MaxSkew
+	if maxSkew <= 0 {
+		maxSkew = defaultHMACMaxSkew
+	}
+	if skew := now.Sub(time.Unix(signedAt, 0)); skew > maxSkew || skew < -maxSkew {
+		return "", ErrStaleRequest
+	}
+	var body []byte
+	if r.Body != nil {
+		maxBodyBytes := v.MaxBodyBytes
+		if maxBodyBytes <= 0 {
+			maxBodyBytes = defaultHMACMaxBodyBytes
+		}
+		if body, err = io.ReadAll(http.MaxBytesReader(nil, r.Body, maxBodyBytes)); err != nil {
+			return "", fmt.Errorf("failed to read request body: %w", err)
+		}
+		r.Body.Close()
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	// The request line is checked rather than the URL, which handlers
+	// mounting the verifier under a path prefix may have rewritten.
+	requestURI := r.RequestURI
+	if requestURI == "" {
+		requestURI = r.URL.RequestURI()
+	}
+	expected := HMACSignature(secret, keyID, r.Method, requestURI, timestamp, nonce,
+		metadata.FromHeader(r.Header), body)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return "", ErrInvalidSignature
+	}
+	// Nonces are remembered past the window in both directions of the skew.
+	if !v.remember(keyID+"/"+nonce, now, now.Add(2*maxSkew)) {
+		return "", ErrReplayedRequest
+	}
+	return keyID, nil
+}
+
+// Wrap returns a handler rejecting requests that fail verification with 401
+// Unauthorized, or 413 Request Entity Too Large beyond MaxBodyBytes, before
+// calling next.
+func (v *HMACVerifier) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := v.Verify(r); err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				http.Error(w, "Request Entity Too Large", http.StatusRequestEntityTooLarge)
+				return
+			}
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// remember records nonce until expiry, pruning expired nonces. It returns
+// false if the nonce was already recorded.
+func (v *HMACVerifier) remember(nonce string, now, expiry time.Time) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.seen == nil {
+		v.seen = make(map[string]struct{})
+	}
+	// Expiries are mostly recorded in order, as they are a fixed delay after
+	// the verification; one out of order is only pruned late.
+	for len(v.expiries) > 0 && now.After(v.expiries[0].expiry) {
+		delete(v.seen, v.expiries[0].nonce)
+		v.expiries[0] = seenNonce{}
+		v.expiries = v.expiries[1:]
+	}
+	if _, ok := v.seen[nonce]; ok {
+		return false
+	}
+	v.seen[nonce] = struct{}{}
+	v.expiries = append(v.expiries, seenNonce{nonce: nonce, expiry: expiry})
+	return true
+}
+
+// currentTime returns the current time of the verifier.
+func (v *HMACVerifier) currentTime() time.Time {
+	if v.now != nil {
+		return v.now()
+	}
+	return time.Now()
+}
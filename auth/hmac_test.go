@@ -0,0 +1,122 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package auth_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/auth"
+	"trpc.group/trpc-go/trpc-a2a-go/metadata"
+)
+
+func TestHMACSigning_RoundTrip(t *testing.T) {
+	verifier := auth.NewHMACVerifier(map[string][]byte{"k1": []byte("secret")}, time.Minute)
+	var received string
+	server := httptest.NewServer(verifier.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = string(body)
+	})))
+	defer server.Close()
+
+	client := auth.NewHMACSigner("k1", []byte("secret")).ConfigureClient(&http.Client{})
+	resp, err := client.Post(server.URL, "application/json", strings.NewReader(`{"id":1}`))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, `{"id":1}`, received, "the body is restored for the handler")
+
+	// Identical bodies are signed with distinct nonces.
+	resp, err = client.Post(server.URL, "application/json", strings.NewReader(`{"id":1}`))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	wrongKey := auth.NewHMACSigner("k1", []byte("other")).ConfigureClient(&http.Client{})
+	resp, err = wrongKey.Post(server.URL, "application/json", strings.NewReader(`{"id":1}`))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	resp, err = http.Post(server.URL, "application/json", strings.NewReader(`{"id":1}`))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestHMACVerifier_Verify(t *testing.T) {
+	secret := []byte("secret")
+	verifier := auth.NewHMACVerifier(map[string][]byte{"k1": secret}, time.Minute)
+	newRequest := func(body string, signedAt time.Time, nonce string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/rpc?v=1", strings.NewReader(body))
+		timestamp := strconv.FormatInt(signedAt.Unix(), 10)
+		req.Header.Set(auth.HMACKeyIDHeader, "k1")
+		req.Header.Set(auth.HMACTimestampHeader, timestamp)
+		req.Header.Set(auth.HMACNonceHeader, nonce)
+		req.Header.Set(auth.HMACSignatureHeader, auth.HMACSignature(secret, "k1", http.MethodPost, "/rpc?v=1", timestamp, nonce,
+			metadata.Pairs("tenant", "acme"), []byte(body)))
+		req.Header.Set(metadata.HeaderPrefix+"Tenant", "acme")
+		return req
+	}
+
+	keyID, err := verifier.Verify(newRequest("body", time.Now(), "n1"))
+	require.NoError(t, err)
+	assert.Equal(t, "k1", keyID)
+
+	_, err = verifier.Verify(newRequest("body", time.Now(), "n1"))
+	assert.ErrorIs(t, err, auth.ErrReplayedRequest)
+
+	_, err = verifier.Verify(newRequest("body", time.Now().Add(-2*time.Minute), "n2"))
+	assert.ErrorIs(t, err, auth.ErrStaleRequest)
+
+	tampered := newRequest("body", time.Now(), "n3")
+	tampered.Body = io.NopCloser(strings.NewReader("other"))
+	_, err = verifier.Verify(tampered)
+	assert.ErrorIs(t, err, auth.ErrInvalidSignature)
+
+	otherPath := newRequest("body", time.Now(), "n5")
+	otherPath.RequestURI = "/admin"
+	_, err = verifier.Verify(otherPath)
+	assert.ErrorIs(t, err, auth.ErrInvalidSignature)
+
+	// The request line is verified, not the URL rewritten by mount prefixes.
+	stripped := newRequest("body", time.Now(), "n8")
+	stripped.URL.Path = "/"
+	_, err = verifier.Verify(stripped)
+	assert.NoError(t, err)
+
+	otherTenant := newRequest("body", time.Now(), "n9")
+	otherTenant.Header.Set(metadata.HeaderPrefix+"Tenant", "other")
+	_, err = verifier.Verify(otherTenant)
+	assert.ErrorIs(t, err, auth.ErrInvalidSignature, "metadata headers are signed")
+
+	otherMethod := newRequest("body", time.Now(), "n6")
+	otherMethod.Method = http.MethodPut
+	_, err = verifier.Verify(otherMethod)
+	assert.ErrorIs(t, err, auth.ErrInvalidSignature)
+
+	verifier.MaxBodyBytes = 2
+	_, err = verifier.Verify(newRequest("body", time.Now(), "n7"))
+	var tooLarge *http.MaxBytesError
+	assert.ErrorAs(t, err, &tooLarge)
+
+	unknownKey := newRequest("body", time.Now(), "n4")
+	unknownKey.Header.Set(auth.HMACKeyIDHeader, "k2")
+	_, err = verifier.Verify(unknownKey)
+	assert.ErrorIs(t, err, auth.ErrInvalidSignature)
+
+	_, err = verifier.Verify(httptest.NewRequest(http.MethodPost, "/", nil))
+	assert.ErrorIs(t, err, auth.ErrMissingSignature)
+}
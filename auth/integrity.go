@@ -0,0 +1,252 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// MetadataKeyIntegrity is the artifact metadata key of the integrity record
+// of an artifact chunk.
+const MetadataKeyIntegrity = "integrity"
+
+// IntegrityAlgorithm is the digest algorithm of artifact integrity records.
+const IntegrityAlgorithm = "sha256"
+
+// ArtifactIntegrity lets the receiver of an artifact check that each of its
+// chunks arrived intact and in order. The digest of a chunk is chained with
+// the digests of the chunks before it, so the signature of the last chunk
+// covers the whole artifact.
+type ArtifactIntegrity struct {
+	// Algorithm is the digest algorithm, IntegrityAlgorithm.
+	Algorithm string `json:"alg"`
+	// Chunk is the number of the chunk in the artifact, from 0.
+	Chunk int `json:"chunk"`
+	// Content is the base64url JSON encoding of the chunk, all its fields
+	// but this record included, as the signer encoded it.
+	Content string `json:"content"`
+	// ChunkDigest is the base64url digest of the bytes of Content.
+	ChunkDigest string `json:"chunkDigest"`
+	// Digest is the base64url digest of the previous Digest, empty for the
+	// first chunk, followed by ChunkDigest.
+	Digest string `json:"digest"`
+	// KeyID identifies the key that signed the chunk, if signed.
+	KeyID string `json:"kid,omitempty"`
+	// Signature is the base64url Ed25519 signature of the task ID, artifact
+	// index and Digest, if signed.
+	Signature string `json:"sig,omitempty"`
+}
+
+// ArtifactIntegrityFromMetadata returns the integrity record in the metadata
+// of an artifact chunk, or nil if there is none. It accepts both
+// ArtifactIntegrity values and their decoded JSON form.
+func ArtifactIntegrityFromMetadata(metadata map[string]interface{}) (*ArtifactIntegrity, error) {
+	raw, ok := metadata[MetadataKeyIntegrity]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	switch integrity := raw.(type) {
+	case ArtifactIntegrity:
+		return &integrity, nil
+	case *ArtifactIntegrity:
+		return integrity, nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid artifact integrity: %w", err)
+	}
+	var integrity ArtifactIntegrity
+	if err := json.Unmarshal(data, &integrity); err != nil {
+		return nil, fmt.Errorf("invalid artifact integrity: %w", err)
+	}
+	return &integrity, nil
+}
+
+// chunkContent returns the JSON encoding of chunk without its integrity
+// record.
+func chunkContent(chunk protocol.Artifact) ([]byte, error) {
+	if _, ok := chunk.Metadata[MetadataKeyIntegrity]; ok {
+		metadata := make(map[string]interface{}, len(chunk.Metadata))
+		for k, v := range chunk.Metadata {
+			if k != MetadataKeyIntegrity {
+				metadata[k] = v
+			}
+		}
+		if len(metadata) == 0 {
+			metadata = nil
+		}
+		chunk.Metadata = metadata
+	}
+	data, err := json.Marshal(chunk)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode artifact chunk: %w", err)
+	}
+	return data, nil
+}
+
+// contentDigest returns the digest of the content of a chunk.
+func contentDigest(content []byte) string {
+	sum := sha256.Sum256(content)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// chainDigest returns the digest chaining chunkDigest to prev.
+func chainDigest(prev, chunkDigest string) string {
+	sum := sha256.Sum256([]byte(prev + chunkDigest))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// integrityPayload returns the bytes signed for the digest of the artifact
+// at index of taskID.
+func integrityPayload(taskID string, index int, digest string) []byte {
+	return []byte(taskID + "\n" + strconv.Itoa(index) + "\n" + digest)
+}
+
+// ArtifactSigner computes the integrity records of artifact chunks, signing
+// them if it has a key.
+type ArtifactSigner struct {
+	keyID string
+	key   ed25519.PrivateKey
+}
+
+// NewArtifactSigner creates a signer signing with key under keyID, which
+// verifiers look the public key up by. If key is nil, the records only carry
+// digests, protecting against corruption but not tampering.
+func NewArtifactSigner(keyID string, key ed25519.PrivateKey) *ArtifactSigner {
+	return &ArtifactSigner{keyID: keyID, key: key}
+}
+
+// Integrity returns the integrity record of chunk, the artifact at index of
+// taskID, following the chunk recorded by prev, or starting the artifact if
+// prev is nil. The record carries the encoding of the chunk it covers.
+func (s *ArtifactSigner) Integrity(
+	taskID string,
+	chunk protocol.Artifact,
+	prev *ArtifactIntegrity,
+) (ArtifactIntegrity, error) {
+	content, err := chunkContent(chunk)
+	if err != nil {
+		return ArtifactIntegrity{}, err
+	}
+	chunkDigest := contentDigest(content)
+	integrity := ArtifactIntegrity{
+		Algorithm:   IntegrityAlgorithm,
+		Content:     base64.RawURLEncoding.EncodeToString(content),
+		ChunkDigest: chunkDigest,
+	}
+	var prevDigest string
+	if prev != nil {
+		integrity.Chunk = prev.Chunk + 1
+		prevDigest = prev.Digest
+	}
+	integrity.Digest = chainDigest(prevDigest, chunkDigest)
+	if s.key != nil {
+		integrity.KeyID = s.keyID
+		signature := ed25519.Sign(s.key, integrityPayload(taskID, chunk.Index, integrity.Digest))
+		integrity.Signature = base64.RawURLEncoding.EncodeToString(signature)
+	}
+	return integrity, nil
+}
+
+// VerifyArtifactIntegrity checks integrity, the record of chunk, the artifact
+// at index of taskID, against prev, the record of the previous chunk, or nil
+// for the first, and checks that chunk has the content signed, all its fields
+// included. The digests and signature cover the content as the signer encoded
+// it. If keys is not nil, the record must be signed by one of them.
+func VerifyArtifactIntegrity(
+	ctx context.Context,
+	keys ProvenanceKeyResolver,
+	taskID string,
+	chunk protocol.Artifact,
+	integrity, prev *ArtifactIntegrity,
+) error {
+	if integrity == nil {
+		return errors.New("chunk has no integrity record")
+	}
+	if integrity.Algorithm != IntegrityAlgorithm {
+		return fmt.Errorf("unsupported digest algorithm %q", integrity.Algorithm)
+	}
+	wantChunk, prevDigest := 0, ""
+	if prev != nil {
+		wantChunk, prevDigest = prev.Chunk+1, prev.Digest
+	}
+	if integrity.Chunk != wantChunk {
+		return fmt.Errorf("received chunk %d, expected chunk %d", integrity.Chunk, wantChunk)
+	}
+	content, err := base64.RawURLEncoding.DecodeString(integrity.Content)
+	if err != nil {
+		return fmt.Errorf("invalid content encoding: %w", err)
+	}
+	chunkDigest := contentDigest(content)
+	if chunkDigest != integrity.ChunkDigest {
+		return fmt.Errorf("chunk %d does not match its digest", integrity.Chunk)
+	}
+	if chainDigest(prevDigest, chunkDigest) != integrity.Digest {
+		return fmt.Errorf("chunk %d does not follow the previous chunks", integrity.Chunk)
+	}
+	if err := verifyIntegritySignature(ctx, keys, taskID, chunk.Index, integrity); err != nil {
+		return err
+	}
+	// The chunk received must be the chunk signed, compared in the encoding
+	// of the receiver.
+	var signed protocol.Artifact
+	if err := json.Unmarshal(content, &signed); err != nil {
+		return fmt.Errorf("invalid content of chunk %d: %w", integrity.Chunk, err)
+	}
+	want, err := chunkContent(signed)
+	if err != nil {
+		return err
+	}
+	got, err := chunkContent(chunk)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(got, want) {
+		return fmt.Errorf("chunk %d does not match its signed content", integrity.Chunk)
+	}
+	return nil
+}
+
+// verifyIntegritySignature checks the signature of integrity, the record of
+// the artifact at index of taskID, if keys is not nil.
+func verifyIntegritySignature(
+	ctx context.Context,
+	keys ProvenanceKeyResolver,
+	taskID string,
+	index int,
+	integrity *ArtifactIntegrity,
+) error {
+	if keys == nil {
+		return nil
+	}
+	if integrity.Signature == "" {
+		return fmt.Errorf("chunk %d is not signed", integrity.Chunk)
+	}
+	key, err := keys.ProvenanceKey(ctx, integrity.KeyID)
+	if err != nil {
+		return err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(integrity.Signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature encoding: %w", err)
+	}
+	payload := integrityPayload(taskID, index, integrity.Digest)
+	if len(key) != ed25519.PublicKeySize || !ed25519.Verify(key, payload, signature) {
+		return fmt.Errorf("chunk %d has an invalid signature", integrity.Chunk)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package auth
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+func TestArtifactIntegrity(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	keys := StaticProvenanceKeys{"k1": publicKey}
+	signer := NewArtifactSigner("k1", privateKey)
+	ctx := context.Background()
+	chunk := func(text string) protocol.Artifact {
+		return protocol.Artifact{Index: 1, Parts: []protocol.Part{protocol.NewTextPart(text)}}
+	}
+
+	first, err := signer.Integrity("t1", chunk("hello "), nil)
+	require.NoError(t, err)
+	second, err := signer.Integrity("t1", chunk("world"), &first)
+	require.NoError(t, err)
+	assert.Equal(t, 1, second.Chunk)
+	require.NoError(t, VerifyArtifactIntegrity(ctx, keys, "t1", chunk("hello "), &first, nil))
+	require.NoError(t, VerifyArtifactIntegrity(ctx, keys, "t1", chunk("world"), &second, &first))
+
+	// The records survive a JSON round trip through the artifact metadata.
+	data, err := json.Marshal(map[string]interface{}{MetadataKeyIntegrity: second})
+	require.NoError(t, err)
+	var metadata map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &metadata))
+	decoded, err := ArtifactIntegrityFromMetadata(metadata)
+	require.NoError(t, err)
+	assert.Equal(t, second, *decoded)
+
+	assert.ErrorContains(t, VerifyArtifactIntegrity(ctx, keys, "t1", chunk("w0rld"), &second, &first),
+		"does not match its signed content")
+	last := chunk("world")
+	last.LastChunk = new(bool)
+	*last.LastChunk = true
+	assert.ErrorContains(t, VerifyArtifactIntegrity(ctx, keys, "t1", last, &second, &first),
+		"does not match its signed content", "all the fields of the chunk are signed")
+	forged := second
+	forged.Content = first.Content
+	assert.ErrorContains(t, VerifyArtifactIntegrity(ctx, keys, "t1", chunk("hello "), &forged, &first),
+		"does not match its digest")
+	assert.ErrorContains(t, VerifyArtifactIntegrity(ctx, keys, "t1", chunk("world"), &second, nil),
+		"expected chunk 0")
+	assert.ErrorContains(t, VerifyArtifactIntegrity(ctx, keys, "t2", chunk("hello "), &first, nil),
+		"invalid signature", "records are bound to their task")
+	assert.ErrorContains(t, VerifyArtifactIntegrity(ctx, keys, "t1", chunk("x"), nil, nil), "no integrity record")
+
+	unsigned, err := NewArtifactSigner("", nil).Integrity("t1", chunk("hello "), nil)
+	require.NoError(t, err)
+	assert.NoError(t, VerifyArtifactIntegrity(ctx, nil, "t1", chunk("hello "), &unsigned, nil))
+	assert.ErrorContains(t, VerifyArtifactIntegrity(ctx, keys, "t1", chunk("hello "), &unsigned, nil), "not signed")
+}
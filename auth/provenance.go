@@ -0,0 +1,262 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package auth
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// MetadataKeyProvenance is the metadata key of the provenance chain in the
+// params of tasks/send and tasks/sendSubscribe requests.
+const MetadataKeyProvenance = "provenance"
+
+// ProvenanceContextKey is the context key under which servers store the
+// verified provenance chain of the request being handled.
+const ProvenanceContextKey ContextKey = "provenance"
+
+// defaultMaxProvenanceHops bounds the length of verified chains by default.
+const defaultMaxProvenanceHops = 16
+
+// ProvenanceHop is one signed link of a delegation chain: the agent (or origin
+// client) that sent a task to the next agent.
+type ProvenanceHop struct {
+	// Agent identifies the sender of the hop, e.g. its name or URL.
+	Agent string `json:"agent"`
+	// Caller is the authenticated caller the sender acted for, if any.
+	// For the first hop this is the origin caller.
+	Caller string `json:"caller,omitempty"`
+	// TaskID is the ID of the task the hop sent.
+	TaskID string `json:"taskId"`
+	// Timestamp is when the hop was signed, in RFC 3339 format.
+	Timestamp string `json:"timestamp"`
+	// KeyID identifies the key that signed the hop.
+	KeyID string `json:"kid"`
+	// Prev is the base64url SHA-256 digest of the previous hop, empty for the first.
+	Prev string `json:"prev,omitempty"`
+	// Signature is the base64url Ed25519 signature of the hop without it.
+	Signature string `json:"sig"`
+}
+
+// ProvenanceChain is a delegation chain, from the origin to the latest hop.
+type ProvenanceChain []ProvenanceHop
+
+// Origin returns the first hop of the chain.
+func (c ProvenanceChain) Origin() (ProvenanceHop, bool) {
+	if len(c) == 0 {
+		return ProvenanceHop{}, false
+	}
+	return c[0], true
+}
+
+// Last returns the latest hop of the chain.
+func (c ProvenanceChain) Last() (ProvenanceHop, bool) {
+	if len(c) == 0 {
+		return ProvenanceHop{}, false
+	}
+	return c[len(c)-1], true
+}
+
+// ProvenanceFromMetadata returns the provenance chain recorded in metadata,
+// or nil if there is none. It accepts both ProvenanceChain values and their
+// decoded JSON form.
+func ProvenanceFromMetadata(metadata map[string]interface{}) (ProvenanceChain, error) {
+	raw, ok := metadata[MetadataKeyProvenance]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	if chain, ok := raw.(ProvenanceChain); ok {
+		return chain, nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid provenance chain: %w", err)
+	}
+	var chain ProvenanceChain
+	if err := json.Unmarshal(data, &chain); err != nil {
+		return nil, fmt.Errorf("invalid provenance chain: %w", err)
+	}
+	return chain, nil
+}
+
+// ProvenanceFromContext returns the verified provenance chain of the request
+// being handled, as stored by the server.
+func ProvenanceFromContext(ctx context.Context) ProvenanceChain {
+	chain, _ := ctx.Value(ProvenanceContextKey).(ProvenanceChain)
+	return chain
+}
+
+// ContextWithProvenance returns a context carrying chain.
+func ContextWithProvenance(ctx context.Context, chain ProvenanceChain) context.Context {
+	return context.WithValue(ctx, ProvenanceContextKey, chain)
+}
+
+// ProvenanceSigner appends signed hops to provenance chains.
+type ProvenanceSigner struct {
+	agent string
+	keyID string
+	key   ed25519.PrivateKey
+	now   func() time.Time
+}
+
+// NewProvenanceSigner creates a signer for the agent identified by agent, signing
+// with key under keyID. Verifiers look the public key up by keyID.
+func NewProvenanceSigner(agent, keyID string, key ed25519.PrivateKey) *ProvenanceSigner {
+	return &ProvenanceSigner{agent: agent, keyID: keyID, key: key, now: time.Now}
+}
+
+// Extend returns a copy of chain with a new hop for sending taskID on behalf of caller.
+func (s *ProvenanceSigner) Extend(chain ProvenanceChain, caller, taskID string) (ProvenanceChain, error) {
+	hop := ProvenanceHop{
+		Agent:     s.agent,
+		Caller:    caller,
+		TaskID:    taskID,
+		Timestamp: s.now().UTC().Format(time.RFC3339Nano),
+		KeyID:     s.keyID,
+	}
+	if last, ok := chain.Last(); ok {
+		digest, err := hopDigest(last)
+		if err != nil {
+			return nil, err
+		}
+		hop.Prev = digest
+	}
+	payload, err := signingPayload(hop)
+	if err != nil {
+		return nil, err
+	}
+	hop.Signature = base64.RawURLEncoding.EncodeToString(ed25519.Sign(s.key, payload))
+	extended := make(ProvenanceChain, 0, len(chain)+1)
+	return append(append(extended, chain...), hop), nil
+}
+
+// ProvenanceKeyResolver returns the public key registered under keyID.
+type ProvenanceKeyResolver interface {
+	ProvenanceKey(ctx context.Context, keyID string) (ed25519.PublicKey, error)
+}
+
+// StaticProvenanceKeys is a ProvenanceKeyResolver backed by a map from key ID to key.
+type StaticProvenanceKeys map[string]ed25519.PublicKey
+
+// ProvenanceKey implements ProvenanceKeyResolver.
+func (k StaticProvenanceKeys) ProvenanceKey(ctx context.Context, keyID string) (ed25519.PublicKey, error) {
+	key, ok := k[keyID]
+	if !ok {
+		return nil, fmt.Errorf("unknown provenance key %q", keyID)
+	}
+	return key, nil
+}
+
+// ProvenanceVerifier verifies provenance chains.
+type ProvenanceVerifier struct {
+	// Keys resolves the public keys of the signers.
+	Keys ProvenanceKeyResolver
+	// MaxAge, if positive, rejects chains whose latest hop is older, bounding
+	// how long a captured chain can be replayed.
+	MaxAge time.Duration
+	// MaxHops bounds the chain length. Defaults to 16.
+	MaxHops int
+	// now returns the current time; time.Now if nil.
+	now func() time.Time
+}
+
+// Verify checks the signature of every hop, that each hop links to the
+// previous one, and that timestamps do not go backwards.
+func (v *ProvenanceVerifier) Verify(ctx context.Context, chain ProvenanceChain) error {
+	if v.Keys == nil {
+		return errors.New("no provenance keys configured")
+	}
+	maxHops := v.MaxHops
+	if maxHops <= 0 {
+		maxHops = defaultMaxProvenanceHops
+	}
+	if len(chain) > maxHops {
+		return fmt.Errorf("provenance chain has %d hops, more than the maximum of %d", len(chain), maxHops)
+	}
+	var prevTime time.Time
+	for i, hop := range chain {
+		if err := v.verifyHop(ctx, chain, i); err != nil {
+			return fmt.Errorf("provenance hop %d (%s): %w", i, hop.Agent, err)
+		}
+		ts, _ := time.Parse(time.RFC3339Nano, hop.Timestamp) // Checked by verifyHop.
+		if ts.Before(prevTime) {
+			return fmt.Errorf("provenance hop %d (%s): timestamp precedes the previous hop", i, hop.Agent)
+		}
+		prevTime = ts
+	}
+	if v.MaxAge > 0 && len(chain) > 0 {
+		now := time.Now
+		if v.now != nil {
+			now = v.now
+		}
+		if age := now().Sub(prevTime); age > v.MaxAge {
+			return fmt.Errorf("provenance chain is %v old, more than the maximum of %v", age.Round(time.Second), v.MaxAge)
+		}
+	}
+	return nil
+}
+
+// verifyHop verifies hop i of chain.
+func (v *ProvenanceVerifier) verifyHop(ctx context.Context, chain ProvenanceChain, i int) error {
+	hop := chain[i]
+	if _, err := time.Parse(time.RFC3339Nano, hop.Timestamp); err != nil {
+		return fmt.Errorf("invalid timestamp: %w", err)
+	}
+	wantPrev := ""
+	if i > 0 {
+		digest, err := hopDigest(chain[i-1])
+		if err != nil {
+			return err
+		}
+		wantPrev = digest
+	}
+	if hop.Prev != wantPrev {
+		return errors.New("does not link to the previous hop")
+	}
+	key, err := v.Keys.ProvenanceKey(ctx, hop.KeyID)
+	if err != nil {
+		return err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(hop.Signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature encoding: %w", err)
+	}
+	payload, err := signingPayload(hop)
+	if err != nil {
+		return err
+	}
+	if len(key) != ed25519.PublicKeySize || !ed25519.Verify(key, payload, signature) {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+// signingPayload returns the bytes signed for hop: its JSON encoding without signature.
+func signingPayload(hop ProvenanceHop) ([]byte, error) {
+	hop.Signature = ""
+	payload, err := json.Marshal(hop)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode provenance hop: %w", err)
+	}
+	return payload, nil
+}
+
+// hopDigest returns the digest of a signed hop that the next hop links to.
+func hopDigest(hop ProvenanceHop) (string, error) {
+	data, err := json.Marshal(hop)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode provenance hop: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,113 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package auth_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/auth"
+)
+
+func newTestSigner(t *testing.T, agent, keyID string, keys auth.StaticProvenanceKeys) *auth.ProvenanceSigner {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	keys[keyID] = pub
+	return auth.NewProvenanceSigner(agent, keyID, priv)
+}
+
+func TestProvenanceChain_SignAndVerify(t *testing.T) {
+	keys := auth.StaticProvenanceKeys{}
+	origin := newTestSigner(t, "client", "k1", keys)
+	planner := newTestSigner(t, "planner", "k2", keys)
+	verifier := &auth.ProvenanceVerifier{Keys: keys, MaxAge: time.Minute}
+
+	chain, err := origin.Extend(nil, "alice", "task-1")
+	require.NoError(t, err)
+	chain, err = planner.Extend(chain, "client", "task-2")
+	require.NoError(t, err)
+	require.Len(t, chain, 2)
+	require.NoError(t, verifier.Verify(context.Background(), chain))
+
+	first, _ := chain.Origin()
+	last, _ := chain.Last()
+	assert.Equal(t, "alice", first.Caller)
+	assert.Empty(t, first.Prev)
+	assert.Equal(t, "planner", last.Agent)
+	assert.NotEmpty(t, last.Prev)
+
+	// The chain survives a round trip through request metadata.
+	data, err := json.Marshal(map[string]interface{}{auth.MetadataKeyProvenance: chain})
+	require.NoError(t, err)
+	var metadata map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &metadata))
+	decoded, err := auth.ProvenanceFromMetadata(metadata)
+	require.NoError(t, err)
+	assert.Equal(t, chain, decoded)
+	require.NoError(t, verifier.Verify(context.Background(), decoded))
+
+	ctx := auth.ContextWithProvenance(context.Background(), chain)
+	assert.Equal(t, chain, auth.ProvenanceFromContext(ctx))
+}
+
+func TestProvenanceVerifier_Rejects(t *testing.T) {
+	keys := auth.StaticProvenanceKeys{}
+	origin := newTestSigner(t, "client", "k1", keys)
+	planner := newTestSigner(t, "planner", "k2", keys)
+	rogue := auth.NewProvenanceSigner("rogue", "unknown", ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize)))
+	ctx := context.Background()
+
+	first, err := origin.Extend(nil, "alice", "task-1")
+	require.NoError(t, err)
+	chain, err := planner.Extend(first, "client", "task-2")
+	require.NoError(t, err)
+
+	t.Run("tampered hop", func(t *testing.T) {
+		tampered := append(auth.ProvenanceChain(nil), chain...)
+		tampered[0].Caller = "mallory"
+		err := (&auth.ProvenanceVerifier{Keys: keys}).Verify(ctx, tampered)
+		assert.ErrorContains(t, err, "hop 0")
+	})
+
+	t.Run("broken linkage", func(t *testing.T) {
+		other, err := origin.Extend(nil, "bob", "task-1")
+		require.NoError(t, err)
+		spliced := auth.ProvenanceChain{other[0], chain[1]}
+		err = (&auth.ProvenanceVerifier{Keys: keys}).Verify(ctx, spliced)
+		assert.ErrorContains(t, err, "does not link")
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		forged, err := rogue.Extend(chain, "planner", "task-3")
+		require.NoError(t, err)
+		err = (&auth.ProvenanceVerifier{Keys: keys}).Verify(ctx, forged)
+		assert.ErrorContains(t, err, "unknown provenance key")
+	})
+
+	t.Run("too many hops", func(t *testing.T) {
+		err := (&auth.ProvenanceVerifier{Keys: keys, MaxHops: 1}).Verify(ctx, chain)
+		assert.ErrorContains(t, err, "maximum of 1")
+	})
+
+	t.Run("too old", func(t *testing.T) {
+		time.Sleep(time.Millisecond)
+		err := (&auth.ProvenanceVerifier{Keys: keys, MaxAge: time.Nanosecond}).Verify(ctx, chain)
+		assert.ErrorContains(t, err, "old")
+	})
+
+	t.Run("malformed metadata", func(t *testing.T) {
+		_, err := auth.ProvenanceFromMetadata(map[string]interface{}{auth.MetadataKeyProvenance: "chain"})
+		assert.Error(t, err)
+	})
+}
@@ -11,6 +11,7 @@ import (
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -98,9 +99,15 @@ func (a *PushNotificationAuthenticator) SignPayload(payload []byte) (string, err
 	// Calculate SHA256 hash of payload.
 	hash := sha256.Sum256(payload)
 	payloadHash := fmt.Sprintf("%x", hash)
+	// Generate a nonce for receivers to detect replays.
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
 	// Create token with claims.
 	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
 		"iat":                 time.Now().Unix(),
+		"jti":                 hex.EncodeToString(nonce),
 		"request_body_sha256": payloadHash,
 	})
 	// Set key ID in token header.
@@ -0,0 +1,92 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+// Command a2abench generates load against an A2A agent and prints latency
+// histograms. Without -url it benchmarks an in-process server running the
+// bench.Processor.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http/httptest"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/bench"
+	"trpc.group/trpc-go/trpc-a2a-go/client"
+)
+
+func main() {
+	var (
+		url           = flag.String("url", "", "URL of the agent; an in-process server is started if empty")
+		concurrency   = flag.Int("c", 8, "number of tasks in flight")
+		requests      = flag.Int("n", 1000, "number of tasks to send; 0 to run for -d")
+		duration      = flag.Duration("d", 0, "duration of the run; 0 to send -n tasks")
+		streaming     = flag.Float64("streaming", 0.5, "fraction of tasks sent with tasks/sendSubscribe")
+		artifactSizes = flag.String("artifacts", "1024", "comma-separated artifact sizes in bytes, picked at random")
+		messageBytes  = flag.Int("message", 0, "size of the text sent with each task in bytes")
+		seed          = flag.Int64("seed", 1, "seed of the task mix")
+	)
+	flag.Parse()
+
+	sizes, err := parseSizes(*artifactSizes)
+	if err != nil {
+		log.Fatalf("Invalid -artifacts: %v", err)
+	}
+	agentURL := *url
+	if agentURL == "" {
+		srv, err := bench.NewServer()
+		if err != nil {
+			log.Fatalf("Failed to create server: %v", err)
+		}
+		httpServer := httptest.NewServer(srv.Handler())
+		defer httpServer.Close()
+		agentURL = httpServer.URL
+	}
+	c, err := client.NewA2AClient(agentURL, client.WithTimeout(time.Minute))
+	if err != nil {
+		log.Fatalf("Failed to create client: %v", err)
+	}
+	defer c.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	report, err := bench.Run(ctx, c, bench.LoadConfig{
+		Concurrency:    *concurrency,
+		Requests:       *requests,
+		Duration:       *duration,
+		StreamingRatio: *streaming,
+		ArtifactSizes:  sizes,
+		MessageBytes:   *messageBytes,
+		Seed:           *seed,
+	})
+	if err != nil {
+		log.Fatalf("Load run failed: %v", err)
+	}
+	fmt.Print(report)
+}
+
+// parseSizes parses a comma-separated list of sizes.
+func parseSizes(s string) ([]int, error) {
+	var sizes []int
+	for _, field := range strings.Split(s, ",") {
+		if field = strings.TrimSpace(field); field == "" {
+			continue
+		}
+		size, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, err
+		}
+		sizes = append(sizes, size)
+	}
+	return sizes, nil
+}
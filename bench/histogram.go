@@ -0,0 +1,147 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package bench
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"time"
+)
+
+// histogramSubBuckets is the number of buckets per power of two. Recorded
+// values are accurate to within 1/histogramSubBuckets of their magnitude.
+const histogramSubBuckets = 8
+
+// histogramBuckets is the number of buckets needed to cover all durations.
+const histogramBuckets = 64 * histogramSubBuckets
+
+// Histogram records latencies in log-linear buckets, bounding its memory
+// regardless of the number of samples. It is not safe for concurrent use:
+// record into one histogram per goroutine and Merge them.
+type Histogram struct {
+	counts []int64
+	count  int64
+	sum    time.Duration
+	min    time.Duration
+	max    time.Duration
+}
+
+// NewHistogram creates an empty histogram.
+func NewHistogram() *Histogram {
+	return &Histogram{counts: make([]int64, histogramBuckets)}
+}
+
+// bucketOf returns the index of the bucket holding v nanoseconds.
+func bucketOf(v int64) int {
+	if v < 2*histogramSubBuckets {
+		return int(v)
+	}
+	shift := bits.Len64(uint64(v)) - 4
+	return shift*histogramSubBuckets + int(v>>shift)
+}
+
+// bucketUpperBound returns the largest value held by the bucket i.
+func bucketUpperBound(i int) int64 {
+	if i < 2*histogramSubBuckets {
+		return int64(i)
+	}
+	shift := i/histogramSubBuckets - 1
+	mantissa := int64(i%histogramSubBuckets + histogramSubBuckets)
+	return (mantissa+1)<<shift - 1
+}
+
+// Record adds a sample. Negative durations are recorded as zero.
+func (h *Histogram) Record(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	h.counts[bucketOf(int64(d))]++
+	if h.count == 0 || d < h.min {
+		h.min = d
+	}
+	if d > h.max {
+		h.max = d
+	}
+	h.count++
+	h.sum += d
+}
+
+// Merge adds the samples of other to h.
+func (h *Histogram) Merge(other *Histogram) {
+	if other == nil || other.count == 0 {
+		return
+	}
+	for i, n := range other.counts {
+		h.counts[i] += n
+	}
+	if h.count == 0 || other.min < h.min {
+		h.min = other.min
+	}
+	if other.max > h.max {
+		h.max = other.max
+	}
+	h.count += other.count
+	h.sum += other.sum
+}
+
+// Count returns the number of samples.
+func (h *Histogram) Count() int64 {
+	return h.count
+}
+
+// Min returns the smallest sample.
+func (h *Histogram) Min() time.Duration {
+	return h.min
+}
+
+// Max returns the largest sample.
+func (h *Histogram) Max() time.Duration {
+	return h.max
+}
+
+// Mean returns the average of the samples.
+func (h *Histogram) Mean() time.Duration {
+	if h.count == 0 {
+		return 0
+	}
+	return h.sum / time.Duration(h.count)
+}
+
+// Quantile returns an upper bound of the q-quantile of the samples, q being
+// between 0 and 1.
+func (h *Histogram) Quantile(q float64) time.Duration {
+	if h.count == 0 {
+		return 0
+	}
+	rank := int64(math.Ceil(q * float64(h.count)))
+	if rank < 1 {
+		rank = 1
+	}
+	var seen int64
+	for i, n := range h.counts {
+		seen += n
+		if seen >= rank {
+			upper := time.Duration(bucketUpperBound(i))
+			if upper > h.max {
+				return h.max
+			}
+			if upper < h.min {
+				return h.min
+			}
+			return upper
+		}
+	}
+	return h.max
+}
+
+// String summarizes the distribution of the samples.
+func (h *Histogram) String() string {
+	return fmt.Sprintf("n=%d min=%v mean=%v p50=%v p90=%v p99=%v p99.9=%v max=%v",
+		h.count, h.min, h.Mean(), h.Quantile(0.5), h.Quantile(0.9),
+		h.Quantile(0.99), h.Quantile(0.999), h.max)
+}
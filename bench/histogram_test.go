@@ -0,0 +1,49 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package bench
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistogram(t *testing.T) {
+	h := NewHistogram()
+	assert.Zero(t, h.Quantile(0.5))
+	for i := 1; i <= 1000; i++ {
+		h.Record(time.Duration(i) * time.Millisecond)
+	}
+	assert.Equal(t, int64(1000), h.Count())
+	assert.Equal(t, time.Millisecond, h.Min())
+	assert.Equal(t, time.Second, h.Max())
+	assert.Equal(t, 500500*time.Microsecond, h.Mean())
+	for _, q := range []float64{0.5, 0.9, 0.99} {
+		exact := time.Duration(q*1000) * time.Millisecond
+		got := h.Quantile(q)
+		assert.GreaterOrEqual(t, got, exact, "q=%v", q)
+		assert.LessOrEqual(t, got, exact+exact/histogramSubBuckets, "q=%v", q)
+	}
+	assert.Equal(t, time.Second, h.Quantile(1))
+
+	other := NewHistogram()
+	other.Record(2 * time.Second)
+	h.Merge(other)
+	assert.Equal(t, int64(1001), h.Count())
+	assert.Equal(t, 2*time.Second, h.Max())
+}
+
+func TestHistogram_Buckets(t *testing.T) {
+	for _, v := range []int64{0, 1, 15, 16, 17, 100, 1 << 20, 1<<62 + 12345} {
+		i := bucketOf(v)
+		assert.LessOrEqual(t, v, bucketUpperBound(i), "v=%d", v)
+		if i > 0 {
+			assert.Greater(t, v, bucketUpperBound(i-1), "v=%d", v)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+// Package bench provides reproducible benchmarks and a load generator for A2A
+// servers, reporting latency histograms of unary and streaming tasks.
+package bench
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/client"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// LoadConfig configures a load run.
+type LoadConfig struct {
+	// Concurrency is the number of tasks in flight. Defaults to 1.
+	Concurrency int
+	// Requests is the number of tasks to send. If zero, tasks are sent until
+	// Duration elapses.
+	Requests int
+	// Duration bounds the run. If zero, the run ends after Requests tasks.
+	Duration time.Duration
+	// StreamingRatio is the fraction of tasks sent with tasks/sendSubscribe,
+	// the others being sent with tasks/send.
+	StreamingRatio float64
+	// ArtifactSizes are the sizes of the artifacts requested from the
+	// Processor, picked at random for each task. Defaults to no artifact.
+	ArtifactSizes []int
+	// MessageBytes is the size of the text sent with each task. Defaults to
+	// a short text.
+	MessageBytes int
+	// Seed seeds the choice of the method and artifact size of each task, so
+	// that runs with the same seed send the same sequence of tasks.
+	Seed int64
+}
+
+// Report holds the results of a load run.
+type Report struct {
+	// Unary holds the latencies of tasks/send requests.
+	Unary *Histogram
+	// Streaming holds the latencies of tasks/sendSubscribe requests, up to
+	// the final event.
+	Streaming *Histogram
+	// FirstEvent holds the latencies of tasks/sendSubscribe requests up to
+	// their first event.
+	FirstEvent *Histogram
+	// Errors is the number of failed tasks.
+	Errors int64
+	// Elapsed is the duration of the run.
+	Elapsed time.Duration
+}
+
+// newReport creates an empty report.
+func newReport() *Report {
+	return &Report{Unary: NewHistogram(), Streaming: NewHistogram(), FirstEvent: NewHistogram()}
+}
+
+// merge adds the results of other to r.
+func (r *Report) merge(other *Report) {
+	r.Unary.Merge(other.Unary)
+	r.Streaming.Merge(other.Streaming)
+	r.FirstEvent.Merge(other.FirstEvent)
+	r.Errors += other.Errors
+}
+
+// Throughput returns the number of tasks completed per second.
+func (r *Report) Throughput() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.Unary.Count()+r.Streaming.Count()) / r.Elapsed.Seconds()
+}
+
+// String summarizes the report.
+func (r *Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "elapsed=%v throughput=%.1f/s errors=%d\n", r.Elapsed, r.Throughput(), r.Errors)
+	fmt.Fprintf(&b, "unary:       %v\n", r.Unary)
+	fmt.Fprintf(&b, "streaming:   %v\n", r.Streaming)
+	fmt.Fprintf(&b, "first event: %v\n", r.FirstEvent)
+	return b.String()
+}
+
+// loadTask is a task planned by a load run.
+type loadTask struct {
+	id            string
+	streaming     bool
+	artifactBytes int
+}
+
+// loadPlan hands out the tasks of a load run in the order drawn from its seed.
+type loadPlan struct {
+	cfg    LoadConfig
+	prefix string
+
+	mu   sync.Mutex
+	rng  *rand.Rand
+	sent int
+}
+
+// next returns the next task, or false once Requests tasks were handed out.
+func (p *loadPlan) next() (loadTask, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.cfg.Requests > 0 && p.sent >= p.cfg.Requests {
+		return loadTask{}, false
+	}
+	task := loadTask{
+		id:        fmt.Sprintf("%s-%d", p.prefix, p.sent),
+		streaming: p.rng.Float64() < p.cfg.StreamingRatio,
+	}
+	if len(p.cfg.ArtifactSizes) > 0 {
+		task.artifactBytes = p.cfg.ArtifactSizes[p.rng.Intn(len(p.cfg.ArtifactSizes))]
+	}
+	p.sent++
+	return task, true
+}
+
+// Run sends tasks to the agent of c as configured by cfg and reports their
+// latencies. The agent is expected to run the Processor for the requested
+// artifact sizes to be honoured. Run stops early if ctx is done.
+func Run(ctx context.Context, c *client.A2AClient, cfg LoadConfig) (*Report, error) {
+	if cfg.Requests <= 0 && cfg.Duration <= 0 {
+		return nil, errors.New("bench: either Requests or Duration must be set")
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 1
+	}
+	if cfg.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.Duration)
+		defer cancel()
+	}
+	plan := &loadPlan{
+		cfg:    cfg,
+		prefix: fmt.Sprintf("bench-%d", time.Now().UnixNano()),
+		rng:    rand.New(rand.NewSource(cfg.Seed)),
+	}
+	text := "bench"
+	if cfg.MessageBytes > 0 {
+		text = strings.Repeat("x", cfg.MessageBytes)
+	}
+
+	report := newReport()
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	start := time.Now()
+	for i := 0; i < cfg.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			local := newReport()
+			for ctx.Err() == nil {
+				task, ok := plan.next()
+				if !ok {
+					break
+				}
+				if err := runTask(ctx, c, task, text, local); err != nil && ctx.Err() == nil {
+					local.Errors++
+				}
+			}
+			mu.Lock()
+			report.merge(local)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	report.Elapsed = time.Since(start)
+	return report, nil
+}
+
+// runTask sends task and records its latencies into report.
+func runTask(ctx context.Context, c *client.A2AClient, task loadTask, text string, report *Report) error {
+	params := protocol.SendTaskParams{
+		ID: task.id,
+		Message: protocol.Message{
+			Role:     protocol.MessageRoleUser,
+			Parts:    []protocol.Part{protocol.NewTextPart(text)},
+			Metadata: map[string]interface{}{ArtifactBytesKey: task.artifactBytes},
+		},
+	}
+	start := time.Now()
+	if !task.streaming {
+		result, err := c.SendTasks(ctx, params)
+		if err != nil {
+			return err
+		}
+		if result.Status.State != protocol.TaskStateCompleted {
+			return fmt.Errorf("task %s ended in state %s", task.id, result.Status.State)
+		}
+		report.Unary.Record(time.Since(start))
+		return nil
+	}
+	// Cancelling the request closes the stream once the final event is in.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	events, err := c.StreamTask(ctx, params)
+	if err != nil {
+		return err
+	}
+	first := true
+	for event := range events {
+		if first {
+			report.FirstEvent.Record(time.Since(start))
+			first = false
+		}
+		if !event.IsFinal() {
+			continue
+		}
+		if status, ok := event.(protocol.TaskStatusUpdateEvent); ok &&
+			status.Status.State != protocol.TaskStateCompleted {
+			return fmt.Errorf("task %s ended in state %s", task.id, status.Status.State)
+		}
+		report.Streaming.Record(time.Since(start))
+		return nil
+	}
+	return fmt.Errorf("stream of task %s ended without a final event", task.id)
+}
@@ -0,0 +1,125 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package bench
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/client"
+)
+
+// newBenchClient starts a server running the Processor and returns a client
+// of it.
+func newBenchClient(tb testing.TB) *client.A2AClient {
+	tb.Helper()
+	srv, err := NewServer()
+	require.NoError(tb, err)
+	httpServer := httptest.NewServer(srv.Handler())
+	tb.Cleanup(httpServer.Close)
+	c, err := client.NewA2AClient(httpServer.URL)
+	require.NoError(tb, err)
+	tb.Cleanup(func() { c.Close() })
+	return c
+}
+
+func TestRun(t *testing.T) {
+	c := newBenchClient(t)
+	report, err := Run(context.Background(), c, LoadConfig{
+		Concurrency:    4,
+		Requests:       40,
+		StreamingRatio: 0.5,
+		ArtifactSizes:  []int{0, 1024},
+		Seed:           1,
+	})
+	require.NoError(t, err)
+	assert.Zero(t, report.Errors)
+	assert.Equal(t, int64(40), report.Unary.Count()+report.Streaming.Count())
+	assert.Positive(t, report.Unary.Count())
+	assert.Positive(t, report.Streaming.Count())
+	assert.Equal(t, report.Streaming.Count(), report.FirstEvent.Count())
+	assert.Positive(t, report.Throughput())
+
+	_, err = Run(context.Background(), c, LoadConfig{})
+	assert.Error(t, err, "the run must be bounded")
+}
+
+func TestLoadPlan_Reproducible(t *testing.T) {
+	cfg := LoadConfig{Requests: 20, StreamingRatio: 0.3, ArtifactSizes: []int{1, 2, 3}, Seed: 42}
+	draw := func() []loadTask {
+		plan := &loadPlan{cfg: cfg, prefix: "t", rng: rand.New(rand.NewSource(cfg.Seed))}
+		var tasks []loadTask
+		for task, ok := plan.next(); ok; task, ok = plan.next() {
+			tasks = append(tasks, task)
+		}
+		return tasks
+	}
+	tasks := draw()
+	assert.Len(t, tasks, 20)
+	assert.Equal(t, tasks, draw())
+}
+
+func BenchmarkSendTask(b *testing.B) {
+	for _, size := range []int{0, 1 << 10, 64 << 10} {
+		b.Run(fmt.Sprintf("artifact=%d", size), func(b *testing.B) {
+			c := newBenchClient(b)
+			task := loadTask{artifactBytes: size}
+			report := newReport()
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				task.id = fmt.Sprintf("send-%d", i)
+				if err := runTask(context.Background(), c, task, "bench", report); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkStreamTask(b *testing.B) {
+	// Events must fit in a line of the client's SSE reader.
+	for _, size := range []int{0, 1 << 10, 32 << 10} {
+		b.Run(fmt.Sprintf("artifact=%d", size), func(b *testing.B) {
+			c := newBenchClient(b)
+			task := loadTask{streaming: true, artifactBytes: size}
+			report := newReport()
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				task.id = fmt.Sprintf("stream-%d", i)
+				if err := runTask(context.Background(), c, task, "bench", report); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkSendTaskParallel(b *testing.B) {
+	c := newBenchClient(b)
+	var n atomic.Int64
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		report := newReport()
+		for pb.Next() {
+			task := loadTask{id: fmt.Sprintf("parallel-%d", n.Add(1)), artifactBytes: 1 << 10}
+			if err := runTask(context.Background(), c, task, "bench", report); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
@@ -0,0 +1,75 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package bench
+
+import (
+	"context"
+	"strings"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/server"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// ArtifactBytesKey is the message metadata key holding the size of the
+// artifact the Processor returns.
+const ArtifactBytesKey = "benchArtifactBytes"
+
+// Processor is a taskmanager.TaskProcessor doing no work beyond returning an
+// artifact of the size requested in the message metadata, so that benchmarks
+// measure the overhead of the framework.
+type Processor struct{}
+
+// Process implements taskmanager.TaskProcessor.
+func (Processor) Process(
+	ctx context.Context,
+	taskID string,
+	msg protocol.Message,
+	handle taskmanager.TaskHandle,
+) error {
+	if size := artifactBytes(msg.Metadata); size > 0 {
+		if err := handle.AddArtifact(protocol.Artifact{
+			Parts: []protocol.Part{protocol.NewTextPart(strings.Repeat("x", size))},
+		}); err != nil {
+			return err
+		}
+	}
+	return handle.UpdateStatus(protocol.TaskStateCompleted, nil)
+}
+
+// artifactBytes returns the artifact size requested in metadata.
+func artifactBytes(metadata map[string]interface{}) int {
+	switch v := metadata[ArtifactBytesKey].(type) {
+	case int:
+		return v
+	case float64: // Decoded from JSON.
+		return int(v)
+	}
+	return 0
+}
+
+// AgentCard returns the agent card of a server benchmarking the Processor.
+func AgentCard() server.AgentCard {
+	return server.AgentCard{
+		Name: "Benchmark Agent",
+		Capabilities: server.AgentCapabilities{
+			Streaming: true,
+		},
+		DefaultInputModes:  []string{string(protocol.PartTypeText)},
+		DefaultOutputModes: []string{string(protocol.PartTypeText)},
+	}
+}
+
+// NewServer creates an A2A server running the Processor on a
+// MemoryTaskManager.
+func NewServer(opts ...server.Option) (*server.A2AServer, error) {
+	tm, err := taskmanager.NewMemoryTaskManager(Processor{})
+	if err != nil {
+		return nil, err
+	}
+	return server.NewA2AServer(AgentCard(), tm, opts...)
+}
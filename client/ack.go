@@ -0,0 +1,156 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// finalAckTimeout bounds the acknowledgement sent when a stream ends.
+const finalAckTimeout = 5 * time.Second
+
+// AckEvents acknowledges the events of an event stream processed so far using
+// the tasks/ackEvents extension method. The stream ID is the value of the
+// protocol.StreamIDHeader header of the stream response and the sequence that
+// of the SSE id field of the last event processed. Streams opened by a client
+// created WithEventAcks are acknowledged automatically.
+func (c *A2AClient) AckEvents(
+	ctx context.Context,
+	params protocol.AckEventsParams,
+	opts ...CallOption,
+) (*protocol.AckEventsResult, error) {
+	ctx = withCallOptions(ctx, opts)
+	request := jsonrpc.NewRequest(protocol.MethodTasksAckEvents, params.ID)
+	paramsBytes, err := c.codec.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("a2aClient.AckEvents: failed to marshal params: %w", err)
+	}
+	request.Params = paramsBytes
+	var result protocol.AckEventsResult
+	if err := c.doRequestAndDecode(ctx, request, &result); err != nil {
+		return nil, fmt.Errorf("a2aClient.AckEvents: %w", err)
+	}
+	return &result, nil
+}
+
+// eventAcker periodically acknowledges the events of a stream received by the
+// consumer.
+type eventAcker struct {
+	client   *A2AClient
+	taskID   string
+	streamID string
+	done     chan struct{}
+	stopped  chan struct{}
+
+	mu       sync.Mutex
+	received int64 // Sequence number of the last event received by the consumer.
+	acked    int64 // Sequence number last acknowledged.
+}
+
+// startEventAcker starts acknowledging the events of a stream of taskID every
+// interval, if the server supports acknowledgements for it. It returns nil
+// otherwise.
+func (c *A2AClient) startEventAcker(ctx context.Context, taskID, streamID string) *eventAcker {
+	if c.ackInterval <= 0 || streamID == "" {
+		return nil
+	}
+	a := &eventAcker{
+		client:   c,
+		taskID:   taskID,
+		streamID: streamID,
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+	go a.run(ctx, c.ackInterval)
+	return a
+}
+
+// run acknowledges the received events every interval and once more, with a
+// context that outlives ctx, when the stream ends. The stream ends once its
+// reader has recorded the last event received, even if ctx is canceled.
+func (a *eventAcker) run(ctx context.Context, interval time.Duration) {
+	defer close(a.stopped)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			a.ack(ctx)
+		case <-a.done:
+			a.finalAck(ctx)
+			return
+		}
+	}
+}
+
+// finalAck acknowledges the events received before the stream ended.
+func (a *eventAcker) finalAck(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), finalAckTimeout)
+	defer cancel()
+	a.ack(ctx)
+}
+
+// ack acknowledges the events received since the last acknowledgement.
+func (a *eventAcker) ack(ctx context.Context) {
+	a.mu.Lock()
+	sequence := a.received
+	pending := sequence > a.acked
+	a.mu.Unlock()
+	if !pending {
+		return
+	}
+	result, err := a.client.AckEvents(ctx, protocol.AckEventsParams{
+		ID:       a.taskID,
+		StreamID: a.streamID,
+		Sequence: sequence,
+	})
+	if err != nil {
+		log.Warnf("Failed to acknowledge events of task %s: %v", a.taskID, err)
+		return
+	}
+	log.Debugf("Acknowledged event %d of task %s, lag %d events", result.Acked, a.taskID, result.Lag)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if sequence > a.acked {
+		a.acked = sequence
+	}
+}
+
+// receivedEvent records that the consumer received the event with the SSE
+// event ID eventID. It does nothing for a nil acker.
+func (a *eventAcker) receivedEvent(eventID string) {
+	if a == nil {
+		return
+	}
+	sequence, err := strconv.ParseInt(eventID, 10, 64)
+	if err != nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if sequence > a.received {
+		a.received = sequence
+	}
+}
+
+// stop sends the final acknowledgement and waits for it. It does nothing for a
+// nil acker.
+func (a *eventAcker) stop() {
+	if a == nil {
+		return
+	}
+	close(a.done)
+	<-a.stopped
+}
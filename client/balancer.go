@@ -0,0 +1,337 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// LoadBalancingStrategy selects the replica serving a request when a client
+// targets several agent replicas.
+type LoadBalancingStrategy int
+
+const (
+	// RoundRobin cycles through the healthy replicas. This is the default.
+	RoundRobin LoadBalancingStrategy = iota
+	// LeastPending picks the healthy replica with the fewest requests and
+	// streams in flight from this client.
+	LeastPending
+)
+
+const (
+	// defaultFailureCooldown is how long a replica is avoided after a failure.
+	defaultFailureCooldown = 5 * time.Second
+	// maxStickyTasks bounds the number of task-to-replica bindings kept.
+	maxStickyTasks = 10000
+)
+
+// errNoReplicas is returned when the client has no replica to send a request to.
+var errNoReplicas = errors.New("no agent replicas available")
+
+// HealthCheckConfig configures active health checking of agent replicas. Replicas
+// are also marked unhealthy passively, for FailureCooldown, when a request to
+// them fails at the transport level or with a 502, 503 or 504 status.
+type HealthCheckConfig struct {
+	// Interval between probes of each replica. Zero disables active checks.
+	Interval time.Duration
+	// Path probed with a GET request, relative to the replica base URL.
+	// Defaults to the agent card path.
+	Path string
+	// Timeout of a probe. Defaults to Interval.
+	Timeout time.Duration
+	// FailureCooldown is how long a replica is avoided after a failed request.
+	// Defaults to 5s.
+	FailureCooldown time.Duration
+}
+
+// replica is one agent endpoint of a balancer.
+type replica struct {
+	url            *url.URL
+	pending        atomic.Int64
+	unhealthyUntil atomic.Int64 // Unix nanoseconds; zero if healthy.
+}
+
+// healthy reports whether the replica may receive new requests at now.
+func (r *replica) healthy(now time.Time) bool {
+	return r.unhealthyUntil.Load() <= now.UnixNano()
+}
+
+// balancer spreads requests over agent replicas. Requests about a task are
+// routed to the replica that first served the task, since task state, streams
+// and resubscriptions are usually local to one replica.
+type balancer struct {
+	strategy LoadBalancingStrategy
+	cooldown time.Duration
+	next     atomic.Uint64
+
+	mu       sync.RWMutex
+	replicas []*replica
+	sticky   map[string]*replica
+	order    []string // Ring buffer of bound task IDs in insertion order.
+	oldest   int
+}
+
+// newBalancer creates a balancer over the given base URLs.
+func newBalancer(strategy LoadBalancingStrategy, cooldown time.Duration, urls []*url.URL) *balancer {
+	if cooldown <= 0 {
+		cooldown = defaultFailureCooldown
+	}
+	b := &balancer{
+		strategy: strategy,
+		cooldown: cooldown,
+		sticky:   make(map[string]*replica),
+	}
+	b.update(urls)
+	return b
+}
+
+// update replaces the set of replicas, keeping the state of those that remain.
+// Tasks bound to a removed replica are routed afresh.
+func (b *balancer) update(urls []*url.URL) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	existing := make(map[string]*replica, len(b.replicas))
+	for _, r := range b.replicas {
+		existing[r.url.String()] = r
+	}
+	replicas := make([]*replica, 0, len(urls))
+	kept := make(map[*replica]bool, len(urls))
+	for _, u := range urls {
+		r, ok := existing[u.String()]
+		if !ok {
+			r = &replica{url: u}
+		}
+		if !kept[r] {
+			kept[r] = true
+			replicas = append(replicas, r)
+		}
+	}
+	b.replicas = replicas
+	for taskID, r := range b.sticky {
+		if !kept[r] {
+			delete(b.sticky, taskID)
+		}
+	}
+}
+
+// acquire picks the replica for a request about taskID, which may be empty,
+// and counts the request as pending until release is called.
+func (b *balancer) acquire(taskID string) (*replica, error) {
+	r, err := b.pick(taskID)
+	if err != nil {
+		return nil, err
+	}
+	r.pending.Add(1)
+	return r, nil
+}
+
+// acquireURL returns the replica at u, which need not be one of the replicas
+// balanced over, and counts the request as pending until release is called.
+// Tasks are not bound to it.
+func (b *balancer) acquireURL(u *url.URL) *replica {
+	var r *replica
+	b.mu.RLock()
+	for _, candidate := range b.replicas {
+		if candidate.url.String() == u.String() {
+			r = candidate
+			break
+		}
+	}
+	b.mu.RUnlock()
+	if r == nil {
+		r = &replica{url: u}
+	}
+	r.pending.Add(1)
+	return r
+}
+
+// release ends a request started with acquire or acquireURL. A request that failed because
+// of the replica takes it out of rotation for the failure cooldown.
+func (b *balancer) release(r *replica, failed bool) {
+	r.pending.Add(-1)
+	if failed {
+		r.unhealthyUntil.Store(time.Now().Add(b.cooldown).UnixNano())
+	}
+}
+
+// pick selects a replica for taskID, binding the task to it.
+func (b *balancer) pick(taskID string) (*replica, error) {
+	b.mu.RLock()
+	if r, ok := b.sticky[taskID]; ok && taskID != "" {
+		b.mu.RUnlock()
+		return r, nil
+	}
+	replicas := b.replicas
+	b.mu.RUnlock()
+	if len(replicas) == 0 {
+		return nil, errNoReplicas
+	}
+	now := time.Now()
+	candidates := make([]*replica, 0, len(replicas))
+	for _, r := range replicas {
+		if r.healthy(now) {
+			candidates = append(candidates, r)
+		}
+	}
+	if len(candidates) == 0 {
+		// Every replica failed recently; trying one beats failing outright.
+		candidates = replicas
+	}
+	var chosen *replica
+	switch b.strategy {
+	case LeastPending:
+		start := int(b.next.Add(1) % uint64(len(candidates))) // Rotate among ties.
+		for i := range candidates {
+			r := candidates[(start+i)%len(candidates)]
+			if chosen == nil || r.pending.Load() < chosen.pending.Load() {
+				chosen = r
+			}
+		}
+	default:
+		chosen = candidates[int((b.next.Add(1)-1)%uint64(len(candidates)))]
+	}
+	if taskID != "" {
+		b.bind(taskID, chosen)
+	}
+	return chosen, nil
+}
+
+// bind routes later requests about taskID to r.
+func (b *balancer) bind(taskID string, r *replica) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.sticky[taskID]; ok {
+		return
+	}
+	if len(b.order) < maxStickyTasks {
+		b.order = append(b.order, taskID)
+	} else {
+		delete(b.sticky, b.order[b.oldest])
+		b.order[b.oldest] = taskID
+		b.oldest = (b.oldest + 1) % len(b.order)
+	}
+	b.sticky[taskID] = r
+}
+
+// snapshot returns the current replicas.
+func (b *balancer) snapshot() []*replica {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.replicas
+}
+
+// replicaFailed reports whether a request outcome indicates a replica problem
+// rather than a problem with the request itself. resp is nil if no response
+// was received.
+func replicaFailed(ctx context.Context, resp *http.Response, err error) bool {
+	if resp == nil {
+		return err != nil && ctx.Err() == nil
+	}
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// healthChecker probes the replicas of a balancer in the background.
+type healthChecker struct {
+	balancer *balancer
+	client   *http.Client
+	cfg      HealthCheckConfig
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// startHealthChecker starts probing the replicas of b every cfg.Interval.
+func startHealthChecker(b *balancer, client *http.Client, cfg HealthCheckConfig) *healthChecker {
+	if cfg.Path == "" {
+		cfg.Path = protocol.AgentCardPath
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = cfg.Interval
+	}
+	h := &healthChecker{
+		balancer: b,
+		client:   client,
+		cfg:      cfg,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go h.run()
+	return h
+}
+
+// run probes the replicas until close is called.
+func (h *healthChecker) run() {
+	defer close(h.done)
+	ticker := time.NewTicker(h.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		h.probeAll()
+		select {
+		case <-h.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// probeAll probes every replica concurrently and records the results.
+func (h *healthChecker) probeAll() {
+	var wg sync.WaitGroup
+	for _, r := range h.balancer.snapshot() {
+		wg.Add(1)
+		go func(r *replica) {
+			defer wg.Done()
+			if err := h.probe(r); err != nil {
+				log.Debugf("Health check of agent replica %s failed: %v", r.url, err)
+				r.unhealthyUntil.Store(time.Now().Add(h.cfg.Interval).UnixNano())
+				return
+			}
+			r.unhealthyUntil.Store(0)
+		}(r)
+	}
+	wg.Wait()
+}
+
+// probe sends a health check request to r.
+func (h *healthChecker) probe(r *replica) error {
+	ctx, cancel := context.WithTimeout(context.Background(), h.cfg.Timeout)
+	defer cancel()
+	target := r.url.ResolveReference(&url.URL{Path: strings.TrimPrefix(h.cfg.Path, "/")})
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected http status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// close stops the health checker and waits for it to exit.
+func (h *healthChecker) close() {
+	close(h.stop)
+	<-h.done
+}
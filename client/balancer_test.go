@@ -0,0 +1,209 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+func mustParseURLs(t *testing.T, raw ...string) []*url.URL {
+	urls := make([]*url.URL, 0, len(raw))
+	for _, r := range raw {
+		u, err := parseAgentURL(r)
+		require.NoError(t, err)
+		urls = append(urls, u)
+	}
+	return urls
+}
+
+func TestBalancer_RoundRobinAndSticky(t *testing.T) {
+	b := newBalancer(RoundRobin, 0, mustParseURLs(t, "http://a", "http://b"))
+
+	pickHost := func(taskID string) string {
+		r, err := b.acquire(taskID)
+		require.NoError(t, err)
+		b.release(r, false)
+		return r.url.Host
+	}
+	assert.Equal(t, "a", pickHost(""))
+	assert.Equal(t, "b", pickHost(""))
+	assert.Equal(t, "a", pickHost("t1"))
+	assert.Equal(t, "b", pickHost("t2"))
+	assert.Equal(t, "a", pickHost("t1"), "tasks stick to their replica")
+	assert.Equal(t, "b", pickHost("t2"))
+
+	// Removing a replica rebinds its tasks.
+	b.update(mustParseURLs(t, "http://a", "http://c"))
+	assert.Equal(t, "a", pickHost("t1"))
+	assert.NotEqual(t, "b", pickHost("t2"))
+
+	b.update(nil)
+	_, err := b.acquire("t3")
+	assert.ErrorIs(t, err, errNoReplicas)
+}
+
+func TestBalancer_LeastPendingAndCooldown(t *testing.T) {
+	b := newBalancer(LeastPending, time.Hour, mustParseURLs(t, "http://a", "http://b"))
+
+	busy, err := b.acquire("")
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		r, err := b.acquire("")
+		require.NoError(t, err)
+		assert.NotSame(t, busy, r, "the replica with a pending request is avoided")
+		b.release(r, false)
+	}
+
+	// A failed request takes the replica out of rotation.
+	b.release(busy, true)
+	for i := 0; i < 3; i++ {
+		r, err := b.acquire("")
+		require.NoError(t, err)
+		assert.NotSame(t, busy, r)
+		b.release(r, false)
+	}
+	// With every replica unhealthy, requests are still attempted.
+	other, err := b.acquire("")
+	require.NoError(t, err)
+	b.release(other, true)
+	r, err := b.acquire("")
+	require.NoError(t, err)
+	b.release(r, false)
+}
+
+// replicaServer is an agent replica answering every request with a task.
+type replicaServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []string // Task IDs of JSON-RPC requests received.
+	status   int      // HTTP status of responses, 200 if zero.
+}
+
+func newReplicaServer(t *testing.T) *replicaServer {
+	s := &replicaServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		status := s.status
+		s.mu.Unlock()
+		if r.Method == http.MethodGet {
+			if status != 0 {
+				w.WriteHeader(status)
+			}
+			return
+		}
+		var req jsonrpc.Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		taskID, _ := req.ID.(string)
+		s.mu.Lock()
+		s.requests = append(s.requests, taskID)
+		s.mu.Unlock()
+		if status != 0 {
+			w.WriteHeader(status)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": jsonrpc.Version,
+			"id":      req.ID,
+			"result":  protocol.Task{ID: taskID},
+		})
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *replicaServer) received() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+func (s *replicaServer) setStatus(status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = status
+}
+
+func TestA2AClient_Replicas(t *testing.T) {
+	a, b := newReplicaServer(t), newReplicaServer(t)
+	client, err := NewA2AClient(a.URL, WithReplicas(b.URL))
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := context.Background()
+
+	send := func(taskID string) error {
+		_, err := client.SendTasks(ctx, protocol.SendTaskParams{
+			ID:      taskID,
+			Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")}),
+		})
+		return err
+	}
+	require.NoError(t, send("t1"))
+	require.NoError(t, send("t2"))
+	_, err = client.GetTasks(ctx, protocol.TaskQueryParams{ID: "t1"})
+	require.NoError(t, err)
+	_, err = client.CancelTasks(ctx, protocol.TaskIDParams{ID: "t2"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"t1", "t1"}, a.received())
+	assert.Equal(t, []string{"t2", "t2"}, b.received())
+
+	// An unavailable replica is avoided for new tasks.
+	b.setStatus(http.StatusServiceUnavailable)
+	failures := 0
+	for _, taskID := range []string{"t3", "t4"} {
+		if send(taskID) != nil {
+			failures++
+		}
+	}
+	assert.Equal(t, 1, failures, "round robin reaches the unavailable replica once")
+	require.NoError(t, send("t5"))
+	require.NoError(t, send("t6"))
+	assert.Len(t, a.received(), 5)
+	assert.Len(t, b.received(), 3)
+}
+
+func TestA2AClient_HealthCheck(t *testing.T) {
+	a, b := newReplicaServer(t), newReplicaServer(t)
+	b.setStatus(http.StatusInternalServerError)
+	client, err := NewA2AClient(a.URL, WithReplicas(b.URL), WithHealthCheck(HealthCheckConfig{
+		Interval: time.Hour, // Only the initial probe runs during the test.
+	}))
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.Eventually(t, func() bool {
+		healthy := 0
+		for _, r := range client.balancer.snapshot() {
+			if r.healthy(time.Now()) {
+				healthy++
+			}
+		}
+		return healthy == 1
+	}, time.Second, 10*time.Millisecond)
+	for i := 0; i < 4; i++ {
+		_, err := client.GetTasks(context.Background(), protocol.TaskQueryParams{ID: fmt.Sprintf("t%d", i)})
+		require.NoError(t, err)
+	}
+	assert.Len(t, a.received(), 4)
+	assert.Empty(t, b.received())
+}
@@ -0,0 +1,220 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"sync"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/metadata"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// defaultTaskCacheEntries is the default bound of the tasks cached by GetTasks.
+const defaultTaskCacheEntries = 1024
+
+// TaskCacheConfig configures the cache of the tasks read by GetTasks.
+type TaskCacheConfig struct {
+	// TTL is how long a task read from the agent is served from the cache.
+	TTL time.Duration
+	// MaxEntries bounds the number of tasks cached. Default 1024.
+	MaxEntries int
+}
+
+// taskCacheEntry is a cached result of tasks/get.
+type taskCacheEntry struct {
+	task    *protocol.Task
+	expires time.Time
+}
+
+// taskCache caches the results of tasks/get by task ID, history length and
+// outgoing metadata, which may select the tenant of the request.
+// The cached results of a task are dropped as soon as the client receives an
+// event of the task, or sends or cancels it. The tasks are deep-copied in and
+// out of the cache, so callers cannot modify the cached results.
+type taskCache struct {
+	ttl        time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]map[string]taskCacheEntry // By task ID, then query key.
+	size    int
+	// reads tracks the tasks/get requests in flight by task ID, so that a
+	// result read before an invalidation is not cached after it.
+	reads map[string]*taskCacheReads
+}
+
+// taskCacheReads tracks the tasks/get requests of a task in flight.
+type taskCacheReads struct {
+	count      int    // Requests in flight.
+	generation uint64 // Incremented by each invalidation of the task.
+}
+
+// newTaskCache creates a cache configured by cfg.
+func newTaskCache(cfg TaskCacheConfig) *taskCache {
+	if cfg.MaxEntries <= 0 {
+		cfg.MaxEntries = defaultTaskCacheEntries
+	}
+	return &taskCache{
+		ttl:        cfg.TTL,
+		maxEntries: cfg.MaxEntries,
+		entries:    make(map[string]map[string]taskCacheEntry),
+		reads:      make(map[string]*taskCacheReads),
+	}
+}
+
+// queryKey identifies the history length and version of params, and the
+// outgoing metadata of ctx, among the results of its task. The metadata is
+// part of the key since the agent may answer differently for each tenant or
+// caller it identifies; the metadata of the client is the same for all calls.
+func queryKey(ctx context.Context, params protocol.TaskQueryParams) string {
+	key := "-"
+	if params.HistoryLength != nil {
+		key = strconv.Itoa(*params.HistoryLength)
+	}
+	if params.IfModifiedSince != "" {
+		key += "@" + params.IfModifiedSince
+	}
+	if md, _ := metadata.FromOutgoingContext(ctx); md.Len() > 0 {
+		// Maps are encoded with their keys sorted, so equal metadata have
+		// equal encodings.
+		encoded, _ := json.Marshal(md)
+		key += "#" + string(encoded)
+	}
+	return key
+}
+
+// get returns a copy of the cached result of params for the metadata of ctx,
+// if it did not expire.
+func (c *taskCache) get(ctx context.Context, params protocol.TaskQueryParams) (*protocol.Task, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[params.ID][queryKey(ctx, params)]
+	if !ok || time.Now().After(entry.expires) {
+		return nil, false
+	}
+	return entry.task.Clone(), true
+}
+
+// begin records a tasks/get request of taskID in flight, which end must
+// follow, and returns the generation of the task to pass to put.
+func (c *taskCache) begin(taskID string) uint64 {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	reads, ok := c.reads[taskID]
+	if !ok {
+		reads = &taskCacheReads{}
+		c.reads[taskID] = reads
+	}
+	reads.count++
+	return reads.generation
+}
+
+// end records the end of a tasks/get request of taskID started by begin.
+func (c *taskCache) end(taskID string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	reads := c.reads[taskID]
+	if reads.count--; reads.count == 0 {
+		delete(c.reads, taskID)
+	}
+}
+
+// put caches a copy of task as the result of params for the metadata of ctx,
+// read by a request begun at generation, unless the task was invalidated
+// since. It evicts expired results, or any result, if the cache is full.
+func (c *taskCache) put(
+	ctx context.Context,
+	params protocol.TaskQueryParams,
+	task *protocol.Task,
+	generation uint64,
+) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if reads, ok := c.reads[params.ID]; ok && reads.generation != generation {
+		return // The task changed while it was read.
+	}
+	key := queryKey(ctx, params)
+	if _, ok := c.entries[params.ID][key]; !ok {
+		if c.size >= c.maxEntries {
+			c.evict()
+		}
+		c.size++
+	}
+	variants, ok := c.entries[params.ID]
+	if !ok {
+		variants = make(map[string]taskCacheEntry)
+		c.entries[params.ID] = variants
+	}
+	variants[key] = taskCacheEntry{task: task.Clone(), expires: time.Now().Add(c.ttl)}
+}
+
+// evict drops the expired results, or an arbitrary task if none expired. The
+// caller must hold mu.
+func (c *taskCache) evict() {
+	now := time.Now()
+	for taskID, variants := range c.entries {
+		for key, entry := range variants {
+			if now.After(entry.expires) {
+				delete(variants, key)
+				c.size--
+			}
+		}
+		if len(variants) == 0 {
+			delete(c.entries, taskID)
+		}
+	}
+	if c.size < c.maxEntries {
+		return
+	}
+	for taskID := range c.entries {
+		c.dropLocked(taskID)
+		return
+	}
+}
+
+// invalidate drops the cached results of taskID.
+func (c *taskCache) invalidate(taskID string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if reads, ok := c.reads[taskID]; ok {
+		reads.generation++
+	}
+	c.dropLocked(taskID)
+}
+
+// dropLocked drops the cached results of taskID. The caller must hold mu.
+func (c *taskCache) dropLocked(taskID string) {
+	c.size -= len(c.entries[taskID])
+	delete(c.entries, taskID)
+}
+
+// InvalidateTask drops the results of GetTasks cached for taskID, e.g. after
+// learning through a push notification that the task changed. The cache
+// already drops them when the client receives an event of the task from a
+// stream, or sends or cancels the task.
+func (c *A2AClient) InvalidateTask(taskID string) {
+	c.taskCache.invalidate(taskID)
+}
@@ -0,0 +1,125 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/metadata"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+func TestWithTaskCache(t *testing.T) {
+	var gets atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     interface{} `json:"id"`
+			Method string      `json:"method"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		if req.Method == protocol.MethodTasksGet {
+			gets.Add(1)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      req.ID,
+			"result": protocol.Task{
+				ID:     "cached",
+				Status: protocol.TaskStatus{State: protocol.TaskStateWorking},
+			},
+		})
+	}))
+	defer srv.Close()
+	c, err := NewA2AClient(srv.URL, WithTaskCache(TaskCacheConfig{TTL: 100 * time.Millisecond}))
+	require.NoError(t, err)
+	ctx := context.Background()
+	get := func(params protocol.TaskQueryParams) {
+		task, err := c.GetTasks(ctx, params)
+		require.NoError(t, err)
+		assert.Equal(t, "cached", task.ID)
+	}
+	getAs := func(tenant string) {
+		_, err := c.GetTasks(metadata.AppendToOutgoingContext(ctx, "tenant", tenant), protocol.TaskQueryParams{ID: "cached"})
+		require.NoError(t, err)
+	}
+
+	params := protocol.TaskQueryParams{ID: "cached"}
+	get(params)
+	get(params)
+	assert.Equal(t, int32(1), gets.Load(), "fresh tasks are served from the cache")
+	historyLength := 0
+	get(protocol.TaskQueryParams{ID: "cached", HistoryLength: &historyLength})
+	assert.Equal(t, int32(2), gets.Load(), "history lengths are cached apart")
+	getAs("a")
+	getAs("a")
+	assert.Equal(t, int32(3), gets.Load(), "outgoing metadata is cached apart")
+	getAs("b")
+	assert.Equal(t, int32(4), gets.Load(), "results are not shared across tenants")
+
+	_, err = c.SendTasks(ctx, protocol.SendTaskParams{
+		ID:      "cached",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("more")}),
+	})
+	require.NoError(t, err)
+	get(params)
+	assert.Equal(t, int32(5), gets.Load(), "sending a task drops its cached results")
+
+	c.InvalidateTask("cached")
+	get(params)
+	assert.Equal(t, int32(6), gets.Load())
+
+	time.Sleep(150 * time.Millisecond)
+	get(params)
+	assert.Equal(t, int32(7), gets.Load(), "expired results are read again")
+}
+
+func TestTaskCache_Eviction(t *testing.T) {
+	ctx := context.Background()
+	cache := newTaskCache(TaskCacheConfig{TTL: time.Minute, MaxEntries: 2})
+	for _, id := range []string{"a", "b", "c"} {
+		cache.put(ctx, protocol.TaskQueryParams{ID: id}, &protocol.Task{ID: id}, 0)
+	}
+	assert.Equal(t, 2, cache.size)
+	_, ok := cache.get(ctx, protocol.TaskQueryParams{ID: "c"})
+	assert.True(t, ok)
+}
+
+func TestTaskCache_Isolation(t *testing.T) {
+	ctx := context.Background()
+	cache := newTaskCache(TaskCacheConfig{TTL: time.Minute})
+	params := protocol.TaskQueryParams{ID: "a"}
+	task := &protocol.Task{ID: "a", Metadata: map[string]interface{}{"k": "v"}}
+	cache.put(ctx, params, task, cache.begin("a"))
+	cache.end("a")
+	task.Metadata["k"] = "changed"
+	cached, ok := cache.get(ctx, params)
+	require.True(t, ok)
+	assert.Equal(t, "v", cached.Metadata["k"], "callers cannot modify the cached tasks")
+	cached.Metadata["k"] = "changed"
+	cached, ok = cache.get(ctx, params)
+	require.True(t, ok)
+	assert.Equal(t, "v", cached.Metadata["k"])
+
+	// A result read before an invalidation is not cached after it.
+	cache.invalidate("a")
+	generation := cache.begin("a")
+	cache.invalidate("a")
+	cache.put(ctx, params, task, generation)
+	cache.end("a")
+	_, ok = cache.get(ctx, params)
+	assert.False(t, ok)
+	assert.Empty(t, cache.reads)
+}
@@ -0,0 +1,144 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// CallOption configures a single call of the client, overriding the options
+// the client was created with. Every method of the client making requests to
+// the agent accepts call options.
+type CallOption func(*callOptions)
+
+// callOptions are the options of a call.
+type callOptions struct {
+	header  http.Header   // Headers set on the request of the call.
+	timeout time.Duration // Timeout of the requests of the call, if set.
+	retry   *RetryPolicy  // Retries of the requests of the call, if set.
+	target  string        // Base URL of the agent replica called, if set.
+}
+
+// RetryPolicy configures how the requests of unary calls are retried after
+// failures of the agent replica, i.e. transport errors and 502, 503 and 504
+// statuses. Requests rejected by the agent are never retried. Requests which are
+// not idempotent, such as tasks/send, are only retried when the replica cannot
+// have processed them, i.e. when the connection failed or the replica answered
+// 503. Streams are not retried either; see ResubscribeTask.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts of a request, including
+	// the first. Values below 2 disable retries.
+	MaxAttempts int
+	// Backoff is the wait before the first retry, doubled before each of the
+	// next ones.
+	Backoff time.Duration
+}
+
+// WithCallTimeout sets the timeout of each request of the call, replacing the
+// timeout of the client set by WithTimeout. For streaming calls, it bounds the
+// wait for the response headers of the stream, like the client timeout.
+func WithCallTimeout(timeout time.Duration) CallOption {
+	return func(o *callOptions) {
+		if timeout > 0 {
+			o.timeout = timeout
+		}
+	}
+}
+
+// WithCallRetry sets the retry policy of the call, replacing the policy of the
+// client set by WithRetry.
+func WithCallRetry(policy RetryPolicy) CallOption {
+	return func(o *callOptions) {
+		o.retry = &policy
+	}
+}
+
+// WithCallTarget sends the requests of the call to the agent replica at the
+// base URL agentURL, bypassing load balancing, e.g. to inspect the state of
+// one replica. The replica need not be one the client balances over.
+func WithCallTarget(agentURL string) CallOption {
+	return func(o *callOptions) {
+		o.target = agentURL
+	}
+}
+
+// WithCallHeader sets the HTTP header key to value on the request of the call,
+// replacing the value set by WithHeader, if any. It suits headers varying per
+// call, such as tenant IDs or feature flags.
+func WithCallHeader(key, value string) CallOption {
+	return func(o *callOptions) {
+		if o.header == nil {
+			o.header = make(http.Header)
+		}
+		o.header.Set(key, value)
+	}
+}
+
+// callOptionsKey is the context key of the options of a call.
+type callOptionsKey struct{}
+
+// withCallOptions returns ctx carrying the options of a call, or ctx itself
+// if opts is empty, so that they reach the requests the call makes.
+func withCallOptions(ctx context.Context, opts []CallOption) context.Context {
+	if len(opts) == 0 {
+		return ctx
+	}
+	o := &callOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return context.WithValue(ctx, callOptionsKey{}, o)
+}
+
+// callOptionsFromContext returns the options of the call of ctx, or nil.
+func callOptionsFromContext(ctx context.Context) *callOptions {
+	o, _ := ctx.Value(callOptionsKey{}).(*callOptions)
+	return o
+}
+
+// requestTimeout returns the timeout of the requests of the call of ctx.
+func (c *A2AClient) requestTimeout(ctx context.Context) time.Duration {
+	if o := callOptionsFromContext(ctx); o != nil && o.timeout > 0 {
+		return o.timeout
+	}
+	return c.httpClient.Timeout
+}
+
+// callHTTPClient returns the HTTP client of the unary requests of the call of
+// ctx, with the timeout of the call.
+func (c *A2AClient) callHTTPClient(ctx context.Context) *http.Client {
+	timeout := c.requestTimeout(ctx)
+	if timeout == c.httpClient.Timeout {
+		return c.httpClient
+	}
+	httpClient := *c.httpClient
+	httpClient.Timeout = timeout
+	return &httpClient
+}
+
+// retryPolicy returns the retry policy of the call of ctx.
+func (c *A2AClient) retryPolicy(ctx context.Context) RetryPolicy {
+	if o := callOptionsFromContext(ctx); o != nil && o.retry != nil {
+		return *o.retry
+	}
+	return c.retry
+}
+
+// acquire picks the replica for a request about taskID, which may be empty,
+// or the target of the call of ctx if it has one.
+func (c *A2AClient) acquire(ctx context.Context, taskID string) (*replica, error) {
+	if o := callOptionsFromContext(ctx); o != nil && o.target != "" {
+		target, err := parseAgentURL(o.target)
+		if err != nil {
+			return nil, err
+		}
+		return c.balancer.acquireURL(target), nil
+	}
+	return c.balancer.acquire(taskID)
+}
@@ -0,0 +1,142 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+func TestA2AClient_Headers(t *testing.T) {
+	var mu sync.Mutex
+	var received []http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		received = append(received, r.Header.Clone())
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":"task-1","result":{"id":"task-1","status":{"state":"working"}}}`))
+	}))
+	defer server.Close()
+	last := func() http.Header {
+		mu.Lock()
+		defer mu.Unlock()
+		return received[len(received)-1]
+	}
+
+	c, err := NewA2AClient(server.URL,
+		WithHeader("X-Tenant-ID", "acme"),
+		WithHeader("X-Feature", "beta"),
+		WithTaskCache(TaskCacheConfig{TTL: time.Minute}))
+	require.NoError(t, err)
+	ctx := context.Background()
+	params := protocol.SendTaskParams{
+		ID:      "task-1",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")}),
+	}
+
+	_, err = c.SendTasks(ctx, params)
+	require.NoError(t, err)
+	assert.Equal(t, "acme", last().Get("X-Tenant-ID"))
+	assert.Equal(t, "beta", last().Get("X-Feature"))
+
+	_, err = c.SendTasks(ctx, params, WithCallHeader("X-Tenant-ID", "globex"))
+	require.NoError(t, err)
+	assert.Equal(t, "globex", last().Get("X-Tenant-ID"), "call headers override client headers")
+	assert.Equal(t, "beta", last().Get("X-Feature"))
+
+	query := protocol.TaskQueryParams{ID: "task-1"}
+	_, err = c.GetTasks(ctx, query)
+	require.NoError(t, err)
+	_, err = c.GetTasks(ctx, query, WithCallHeader("X-Tenant-ID", "initech"))
+	require.NoError(t, err)
+	assert.Equal(t, "initech", last().Get("X-Tenant-ID"))
+	mu.Lock()
+	assert.Len(t, received, 4, "calls with options bypass the task cache")
+	mu.Unlock()
+}
+
+func TestA2AClient_CallOptions(t *testing.T) {
+	var attempts atomic.Int32
+	flaky := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":"task-1","result":{"id":"task-1","status":{"state":"working"}}}`))
+	}))
+	defer flaky.Close()
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":"task-1","result":{"id":"task-1","status":{"state":"completed"}}}`))
+	}))
+	defer slow.Close()
+
+	c, err := NewA2AClient(flaky.URL, WithTimeout(50*time.Millisecond))
+	require.NoError(t, err)
+	ctx := context.Background()
+	query := protocol.TaskQueryParams{ID: "task-1"}
+
+	t.Run("retry", func(t *testing.T) {
+		_, err := c.GetTasks(ctx, query)
+		assert.Error(t, err, "requests are not retried by default")
+		attempts.Store(0)
+		task, err := c.GetTasks(ctx, query, WithCallRetry(RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}))
+		require.NoError(t, err)
+		assert.Equal(t, protocol.TaskStateWorking, task.Status.State)
+		assert.Equal(t, int32(3), attempts.Load())
+	})
+
+	t.Run("not idempotent", func(t *testing.T) {
+		var sends atomic.Int32
+		gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if sends.Add(1) < 3 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":"task-1","result":{"id":"task-1","status":{"state":"working"}}}`))
+		}))
+		defer gateway.Close()
+		retry := WithCallRetry(RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond})
+		send := protocol.SendTaskParams{
+			ID:      "task-1",
+			Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")}),
+		}
+		_, err := c.SendTasks(ctx, send, retry, WithCallTarget(gateway.URL))
+		assert.Error(t, err, "the replica may have processed the task behind the gateway")
+		assert.Equal(t, int32(1), sends.Load())
+
+		attempts.Store(0)
+		task, err := c.SendTasks(ctx, send, retry)
+		require.NoError(t, err, "a replica answering 503 did not process the task")
+		assert.Equal(t, protocol.TaskStateWorking, task.Status.State)
+		assert.Equal(t, int32(3), attempts.Load())
+	})
+
+	t.Run("target and timeout", func(t *testing.T) {
+		_, err := c.GetTasks(ctx, query, WithCallTarget(slow.URL))
+		assert.Error(t, err, "the client timeout applies to the target")
+		task, err := c.GetTasks(ctx, query, WithCallTarget(slow.URL), WithCallTimeout(time.Second))
+		require.NoError(t, err)
+		assert.Equal(t, protocol.TaskStateCompleted, task.Status.State)
+	})
+
+	t.Run("invalid target", func(t *testing.T) {
+		_, err := c.CancelTasks(ctx, protocol.TaskIDParams{ID: "task-1"}, WithCallTarget("::"))
+		assert.Error(t, err)
+	})
+}
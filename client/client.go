@@ -10,20 +10,25 @@ package client
 import (
 	"bytes"
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"trpc.group/trpc-go/trpc-a2a-go/auth"
+	"trpc.group/trpc-go/trpc-a2a-go/codec"
 	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
-	"trpc.group/trpc-go/trpc-a2a-go/internal/sse"
+	"trpc.group/trpc-go/trpc-a2a-go/internal/ndjson"
 	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/metadata"
 	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/sse"
 )
 
 const (
@@ -34,11 +39,41 @@ const (
 // A2AClient provides methods to interact with an A2A agent server.
 // It handles making HTTP requests and encoding/decoding JSON-RPC messages.
 type A2AClient struct {
-	baseURL        *url.URL            // Parsed base URL of the agent server.
-	httpClient     *http.Client        // Underlying HTTP client.
-	userAgent      string              // User-Agent header string.
-	authProvider   auth.ClientProvider // Authentication provider.
-	httpReqHandler HttpReqHandler      // Custom HTTP request handler.
+	balancer            *balancer                     // Routes requests to the agent replicas.
+	replicaURLs         []string                      // Additional agent replica base URLs.
+	lbStrategy          LoadBalancingStrategy         // Strategy used to pick replicas.
+	healthCheck         HealthCheckConfig             // Health checking of replicas.
+	healthChecker       *healthChecker                // Active health checker, if enabled.
+	pinnedCertificates  []string                      // SHA-256 fingerprints of accepted server certificates.
+	resolver            Resolver                      // Discovers agent replicas, if set.
+	stopResolver        context.CancelFunc            // Stops watching the resolver.
+	resolverDone        chan struct{}                 // Closed when the resolver watch exits.
+	provenanceSigner    *auth.ProvenanceSigner        // Signs the provenance of sent tasks, if set.
+	deadlinePropagation bool                          // Whether the context deadline is sent as deadline budget.
+	httpClient          *http.Client                  // Underlying HTTP client.
+	timeouts            TimeoutConfig                 // Transport and stream timeouts.
+	userAgent           string                        // User-Agent header string.
+	authProvider        auth.ClientProvider           // Authentication provider.
+	httpReqHandler      HttpReqHandler                // Custom HTTP request handler.
+	codec               codec.Codec                   // JSON codec for requests, responses and SSE events.
+	compression         bool                          // Whether to request and decode compressed responses.
+	partFailurePolicy   *protocol.PartFailurePolicy   // Local validation of message parts, if set.
+	warningHandler      WarningHandler                // Receives the warnings returned by the agent.
+	deprecationHandler  DeprecationHandler            // Receives the deprecation notices of the agent.
+	deprecations        sync.Map                      // Keys of the deprecations already reported.
+	ackInterval         time.Duration                 // Interval of stream event acknowledgements, if enabled.
+	dedup               *eventDeduplicator            // Skips events delivered before, if enabled.
+	taskCache           *taskCache                    // Caches the results of GetTasks, if enabled.
+	coalescer           *taskCoalescer                // Shares concurrent GetTasks requests, if enabled.
+	metadata            metadata.MD                   // Metadata sent with every request.
+	headers             http.Header                   // Headers set on every request.
+	retry               RetryPolicy                   // Retries of unary requests.
+	idGenerator         protocol.IDGenerator          // Generates the IDs of tasks sent without one.
+	pollWait            time.Duration                 // Wait of long polls replacing failed streams, if enabled.
+	pollSessions        chan struct{}                 // Holds a token per long polling session running.
+	ndjsonStreams       bool                          // Whether to prefer NDJSON streams to SSE.
+	artifactPath        string                        // Path of the artifact download endpoint.
+	streamRetry         atomic.Pointer[time.Duration] // Reconnection time last advised by a stream, if any.
 }
 
 // NewA2AClient creates a new A2A client targeting the specified agentURL.
@@ -47,42 +82,97 @@ type A2AClient struct {
 // http.Client or timeout.
 // Returns an error if the agentURL is invalid.
 func NewA2AClient(agentURL string, opts ...Option) (*A2AClient, error) {
-	if !strings.HasSuffix(agentURL, "/") {
-		agentURL += "/" // Ensure base URL ends with a slash for correct path joining.
-	}
-	parsedURL, err := url.ParseRequestURI(agentURL)
-	if err != nil {
-		return nil, fmt.Errorf("invalid agent URL %q: %w", agentURL, err)
-	}
 	client := &A2AClient{
-		baseURL: parsedURL,
 		httpClient: &http.Client{
 			Timeout: defaultTimeout,
 		},
-		userAgent:      defaultUserAgent,
-		httpReqHandler: httpRequestHandler,
+		userAgent:           defaultUserAgent,
+		httpReqHandler:      httpRequestHandler,
+		codec:               codec.Default,
+		compression:         true,
+		deadlinePropagation: true,
+		warningHandler:      logWarnings,
+		deprecationHandler:  logDeprecation,
+		idGenerator:         protocol.DefaultIDGenerator,
+		artifactPath:        protocol.DefaultArtifactPath,
 	}
 	// Apply functional options.
 	for _, opt := range opts {
 		opt(client)
 	}
+	if err := client.applyPinning(); err != nil {
+		return nil, err
+	}
+	if err := client.applyTimeouts(); err != nil {
+		return nil, err
+	}
+	urls := make([]*url.URL, 0, 1+len(client.replicaURLs))
+	for _, raw := range append([]string{agentURL}, client.replicaURLs...) {
+		parsedURL, err := parseAgentURL(raw)
+		if err != nil {
+			return nil, err
+		}
+		urls = append(urls, parsedURL)
+	}
+	client.balancer = newBalancer(client.lbStrategy, client.healthCheck.FailureCooldown, urls)
+	if client.healthCheck.Interval > 0 {
+		client.healthChecker = startHealthChecker(client.balancer, client.httpClient, client.healthCheck)
+	}
+	if client.resolver != nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		client.stopResolver = cancel
+		client.resolverDone = make(chan struct{})
+		go client.watchResolver(ctx, client.resolverDone)
+	}
 	return client, nil
 }
 
+// parseAgentURL parses the base URL of an agent.
+func parseAgentURL(agentURL string) (*url.URL, error) {
+	if !strings.HasSuffix(agentURL, "/") {
+		agentURL += "/" // Ensure base URL ends with a slash for correct path joining.
+	}
+	parsedURL, err := url.ParseRequestURI(agentURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid agent URL %q: %w", agentURL, err)
+	}
+	return parsedURL, nil
+}
+
+// Close stops the background health checks and replica resolution of the
+// client, if any. Requests can still be made after Close.
+func (c *A2AClient) Close() error {
+	if c.healthChecker != nil {
+		c.healthChecker.close()
+		c.healthChecker = nil
+	}
+	if c.stopResolver != nil {
+		c.stopResolver()
+		<-c.resolverDone
+		c.stopResolver = nil
+	}
+	return nil
+}
+
 // SendTasks sends a message using the tasks/send method.
 // It returns the initial task state received from the agent.
+// A task ID is generated if params has none.
 func (c *A2AClient) SendTasks(
 	ctx context.Context,
 	params protocol.SendTaskParams,
+	opts ...CallOption,
 ) (*protocol.Task, error) {
-	request := jsonrpc.NewRequest(protocol.MethodTasksSend, params.ID)
-	paramsBytes, err := json.Marshal(params)
+	ctx = withCallOptions(ctx, opts)
+	params = c.assignTaskID(params)
+	params, err := c.checkMessageParts(params)
 	if err != nil {
-		return nil, fmt.Errorf("a2aClient.SendTasks: failed to marshal params: %w", err)
+		return nil, fmt.Errorf("a2aClient.SendTasks: %w", err)
 	}
-	request.Params = paramsBytes
-	// Execute the request and decode the result field directly into task.
-	task, err := c.doRequestAndDecodeTask(ctx, request)
+	params = c.propagateDeadline(ctx, params)
+	if params, err = c.signProvenance(ctx, params); err != nil {
+		return nil, fmt.Errorf("a2aClient.SendTasks: %w", err)
+	}
+	task, err := c.sendTask(ctx, params)
 	if err != nil {
 		// Return error, potentially wrapping a *jsonrpc.JSONRPCError.
 		return nil, fmt.Errorf("a2aClient.SendTasks: %w", err)
@@ -90,20 +180,75 @@ func (c *A2AClient) SendTasks(
 	return task, nil
 }
 
-// GetTasks retrieves the status of a task using the tasks_get method.
+// sendTask sends params, already checked and signed, with tasks/send.
+func (c *A2AClient) sendTask(ctx context.Context, params protocol.SendTaskParams) (*protocol.Task, error) {
+	request := jsonrpc.NewRequest(protocol.MethodTasksSend, params.ID)
+	paramsBytes, err := c.codec.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal params: %w", err)
+	}
+	request.Params = paramsBytes
+	// Execute the request and decode the result field directly into task.
+	task, err := c.doRequestAndDecodeTask(ctx, request)
+	c.taskCache.invalidate(params.ID)
+	return task, err
+}
+
+// NewTaskID returns a new task ID from the ID generator of the client.
+func (c *A2AClient) NewTaskID() string {
+	return c.idGenerator.NewID()
+}
+
+// assignTaskID sets a generated ID on params if it has none.
+func (c *A2AClient) assignTaskID(params protocol.SendTaskParams) protocol.SendTaskParams {
+	if params.ID == "" {
+		params.ID = c.idGenerator.NewID()
+	}
+	return params
+}
+
+// GetTasks retrieves the status of a task using the tasks_get method. With
+// WithTaskCache, it is served from the cache of the client while fresh, and
+// with WithRequestCoalescing, concurrent identical calls share one request.
+// Calls with options bypass both, as their answer may depend on the options.
 func (c *A2AClient) GetTasks(
 	ctx context.Context,
 	params protocol.TaskQueryParams,
+	opts ...CallOption,
 ) (*protocol.Task, error) {
+	var task *protocol.Task
+	var err error
+	if len(opts) > 0 {
+		task, err = c.getTask(withCallOptions(ctx, opts), params)
+	} else if cached, ok := c.taskCache.get(ctx, params); ok {
+		return cached, nil
+	} else {
+		task, err = c.coalescer.do(ctx, params, func(ctx context.Context) (*protocol.Task, error) {
+			return c.getTask(ctx, params)
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("a2aClient.GetTasks: %w", err)
+	}
+	return task, nil
+}
+
+// getTask reads a task with tasks/get and caches it.
+func (c *A2AClient) getTask(ctx context.Context, params protocol.TaskQueryParams) (*protocol.Task, error) {
+	generation := c.taskCache.begin(params.ID)
+	defer c.taskCache.end(params.ID)
 	request := jsonrpc.NewRequest(protocol.MethodTasksGet, params.ID)
-	paramsBytes, err := json.Marshal(params)
+	paramsBytes, err := c.codec.Marshal(params)
 	if err != nil {
-		return nil, fmt.Errorf("a2aClient.GetTasks: failed to marshal params: %w", err)
+		return nil, fmt.Errorf("failed to marshal params: %w", err)
 	}
 	request.Params = paramsBytes
 	task, err := c.doRequestAndDecodeTask(ctx, request)
 	if err != nil {
-		return nil, fmt.Errorf("a2aClient.GetTasks: %w", err)
+		return nil, err
+	}
+	if callOptionsFromContext(ctx) == nil {
+		c.taskCache.put(ctx, params, task, generation)
 	}
 	return task, nil
 }
@@ -113,14 +258,17 @@ func (c *A2AClient) GetTasks(
 func (c *A2AClient) CancelTasks(
 	ctx context.Context,
 	params protocol.TaskIDParams,
+	opts ...CallOption,
 ) (*protocol.Task, error) {
+	ctx = withCallOptions(ctx, opts)
 	request := jsonrpc.NewRequest(protocol.MethodTasksCancel, params.ID)
-	paramsBytes, err := json.Marshal(params)
+	paramsBytes, err := c.codec.Marshal(params)
 	if err != nil {
 		return nil, fmt.Errorf("a2aClient.CancelTasks: failed to marshal params: %w", err)
 	}
 	request.Params = paramsBytes
 	task, err := c.doRequestAndDecodeTask(ctx, request)
+	c.taskCache.invalidate(params.ID)
 	if err != nil {
 		return nil, fmt.Errorf("a2aClient.CancelTasks: %w", err)
 	}
@@ -130,23 +278,124 @@ func (c *A2AClient) CancelTasks(
 // StreamTask sends a message using tasks_sendSubscribe and returns a channel for receiving SSE events.
 // It handles setting up the SSE connection and parsing events.
 // The returned channel will be closed when the stream ends (task completion, error, or context cancellation).
+// A task ID is generated if params has none.
 func (c *A2AClient) StreamTask(
 	ctx context.Context,
 	params protocol.SendTaskParams,
+	opts ...CallOption,
+) (<-chan protocol.TaskEvent, error) {
+	ctx = withCallOptions(ctx, opts)
+	params = c.assignTaskID(params)
+	params, err := c.checkMessageParts(params)
+	if err != nil {
+		return nil, fmt.Errorf("a2aClient.StreamTask: %w", err)
+	}
+	params = c.propagateDeadline(ctx, params)
+	if params, err = c.signProvenance(ctx, params); err != nil {
+		return nil, fmt.Errorf("a2aClient.StreamTask: %w", err)
+	}
+	events, err := c.stream(ctx, protocol.MethodTasksSendSubscribe, params.ID, params)
+	if c.pollWait > 0 && errors.Is(err, errSSEHandshake) {
+		log.Warnf("Falling back to long polling for task %s: %v", params.ID, err)
+		events, err = c.pollStream(ctx, params.ID, &params)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("a2aClient.StreamTask: %w", err)
+	}
+	return events, nil
+}
+
+// ResubscribeTask reopens the event stream of an existing task using the
+// tasks/resubscribe method. When the client balances over several replicas, the
+// request goes to the replica that served the task.
+// The returned channel will be closed when the stream ends.
+func (c *A2AClient) ResubscribeTask(
+	ctx context.Context,
+	params protocol.TaskIDParams,
+	opts ...CallOption,
+) (<-chan protocol.TaskEvent, error) {
+	ctx = withCallOptions(ctx, opts)
+	events, err := c.stream(ctx, protocol.MethodTasksResubscribe, params.ID, params)
+	if c.pollWait > 0 && errors.Is(err, errSSEHandshake) {
+		log.Warnf("Falling back to long polling for task %s: %v", params.ID, err)
+		events, err = c.pollStream(ctx, params.ID, nil)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("a2aClient.ResubscribeTask: %w", err)
+	}
+	return events, nil
+}
+
+// StreamRetryDelay returns the reconnection time last advised by the agent
+// with the retry field of an event stream, to wait before resubscribing after
+// a stream broke. It returns false if no stream advised one.
+func (c *A2AClient) StreamRetryDelay() (time.Duration, bool) {
+	if delay := c.streamRetry.Load(); delay != nil {
+		return *delay, true
+	}
+	return 0, false
+}
+
+// stream sends a streaming JSON-RPC request about taskID and returns a channel
+// receiving the events of the SSE response.
+func (c *A2AClient) stream(
+	ctx context.Context,
+	method string,
+	taskID string,
+	params interface{},
 ) (<-chan protocol.TaskEvent, error) {
 	// Create the JSON-RPC request.
-	request := jsonrpc.NewRequest(protocol.MethodTasksSendSubscribe, params.ID)
-	paramsBytes, err := json.Marshal(params)
+	request := jsonrpc.NewRequest(method, taskID)
+	paramsBytes, err := c.codec.Marshal(params)
 	if err != nil {
-		return nil, fmt.Errorf("a2aClient.StreamTask: failed to marshal params: %w", err)
+		return nil, fmt.Errorf("failed to marshal params: %w", err)
 	}
 	request.Params = paramsBytes
-	reqBody, err := json.Marshal(request)
+	reqBody, err := c.codec.Marshal(request)
 	if err != nil {
-		return nil, fmt.Errorf("a2aClient.StreamTask: failed to marshal request body: %w", err)
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
 	}
+	target, err := c.acquire(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.openStream(ctx, target, request, reqBody)
+	if err != nil {
+		c.balancer.release(target, replicaFailed(ctx, resp, err))
+		return nil, err
+	}
+	log.Debugf("A2A Client Stream Response <- Status: %d, ID: %v. Stream established.", resp.StatusCode, request.ID)
+	c.checkDeprecationHeaders(method, resp.Header)
+	// Create the channel to send events back to the caller. Acknowledged
+	// streams hand events over unbuffered, so that only events the caller
+	// received are acknowledged.
+	eventsChan := make(chan protocol.TaskEvent, 10) // Buffered channel.
+	acks := c.startEventAcker(ctx, taskID, resp.Header.Get(protocol.StreamIDHeader))
+	if acks != nil {
+		eventsChan = make(chan protocol.TaskEvent)
+	}
+	// Start a goroutine to read from the SSE stream. The stream counts as
+	// pending on the replica while it lasts.
+	go func() {
+		defer c.balancer.release(target, false)
+		defer acks.stop()
+		c.processSSEStream(ctx, resp, method, taskID, eventsChan, acks)
+	}()
+	return eventsChan, nil
+}
+
+// openStream posts a streaming request to target and checks that it was answered
+// with an event stream. If err is nil the response body must be closed by the
+// caller. If the request failed after a response was received, the response is
+// returned, with its body closed, along with the error.
+func (c *A2AClient) openStream(
+	ctx context.Context,
+	target *replica,
+	request *jsonrpc.Request,
+	reqBody []byte,
+) (*http.Response, error) {
 	// Construct the target URL.
-	targetURL := c.baseURL.String()
+	targetURL := target.url.String()
 	req, err := http.NewRequestWithContext(
 		ctx,
 		http.MethodPost,
@@ -154,22 +403,32 @@ func (c *A2AClient) StreamTask(
 		bytes.NewReader(reqBody),
 	)
 	if err != nil {
-		return nil, fmt.Errorf("a2aClient.StreamTask: failed to create http request: %w", err)
+		return nil, fmt.Errorf("failed to create http request: %w", err)
 	}
 	// Set headers, including Accept for event stream.
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
-	req.Header.Set("Accept", "text/event-stream") // Crucial for SSE.
+	if c.ndjsonStreams {
+		// Agents not supporting NDJSON still answer with SSE.
+		req.Header.Set("Accept", protocol.ContentTypeNDJSON+", "+protocol.ContentTypeSSE+";q=0.5")
+	} else {
+		req.Header.Set("Accept", protocol.ContentTypeSSE) // Crucial for SSE.
+	}
+	c.setMetadataHeaders(ctx, req.Header)
 	if c.userAgent != "" {
 		req.Header.Set("User-Agent", c.userAgent)
 	}
+	if c.compression {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
 	log.Debugf("A2A Client Stream Request -> Method: %s, ID: %v, URL: %s", request.Method, request.ID, targetURL)
 	// Make the initial request to establish the stream.
-	resp, err := c.httpReqHandler(ctx, c.httpClient, req)
+	resp, err := c.doStreamRequest(req)
 	if err != nil {
-		return nil, fmt.Errorf("a2aClient.StreamTask: http request failed: %w", err)
+		return nil, err
 	}
-	if resp == nil || resp.Body == nil {
-		return nil, fmt.Errorf("a2aClient.StreamTask: unexpected nil response")
+	if err := decodeResponseBody(resp); err != nil {
+		resp.Body.Close()
+		return resp, err
 	}
 	// Check for non-success HTTP status codes.
 	// For SSE, a successful setup should result in 200 OK.
@@ -177,40 +436,99 @@ func (c *A2AClient) StreamTask(
 		// Read body for error details if possible.
 		bodyBytes, _ := io.ReadAll(resp.Body)
 		resp.Body.Close()
-		return nil, fmt.Errorf(
-			"a2aClient.StreamTask: unexpected http status %d establishing stream: %s",
+		return resp, c.handshakeError(bodyBytes, fmt.Sprintf(
+			"unexpected http status %d establishing stream: %s",
 			resp.StatusCode, string(bodyBytes),
-		)
+		))
 	}
 	// Check if the response is actually an event stream.
-	if !strings.Contains(resp.Header.Get("Content-Type"), "text/event-stream") {
+	if !isEventStream(resp) {
+		bodyBytes, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
 		resp.Body.Close()
-		return nil, fmt.Errorf(
-			"a2aClient.StreamTask: server did not respond with Content-Type 'text/event-stream', got %s",
+		return resp, c.handshakeError(bodyBytes, fmt.Sprintf(
+			"server did not respond with Content-Type 'text/event-stream', got %s",
 			resp.Header.Get("Content-Type"),
-		)
+		))
 	}
-	log.Debugf("A2A Client Stream Response <- Status: %d, ID: %v. Stream established.", resp.StatusCode, request.ID)
-	// Create the channel to send events back to the caller.
-	eventsChan := make(chan protocol.TaskEvent, 10) // Buffered channel.
-	// Start a goroutine to read from the SSE stream.
-	go c.processSSEStream(ctx, resp, params.ID, eventsChan)
-	return eventsChan, nil
+	return resp, nil
+}
+
+// isEventStream reports whether resp is an SSE stream, or an NDJSON stream
+// as requested by WithNDJSONStreams.
+func isEventStream(resp *http.Response) bool {
+	contentType := resp.Header.Get("Content-Type")
+	return strings.Contains(contentType, protocol.ContentTypeSSE) ||
+		strings.Contains(contentType, protocol.ContentTypeNDJSON)
+}
+
+// eventReader reads the events of a stream in its wire format.
+type eventReader interface {
+	ReadEvent() (data []byte, eventType string, err error)
+	LastEventID() string
+	Retry() (time.Duration, bool)
+}
+
+// newEventReader returns a reader of the events of resp according to its
+// content type.
+func newEventReader(resp *http.Response) eventReader {
+	if strings.Contains(resp.Header.Get("Content-Type"), protocol.ContentTypeNDJSON) {
+		return ndjson.NewReader(resp.Body)
+	}
+	return sse.NewEventReader(resp.Body)
 }
 
-// processSSEStream reads Server-Sent Events from the response body and sends them
-// onto the provided channel. It handles closing the channel and response body.
+// handshakeError returns the error of a stream request answered with body
+// rather than an event stream. It wraps the JSON-RPC error of body, if any, or
+// errSSEHandshake otherwise, e.g. if a proxy rejected or buffered the stream.
+func (c *A2AClient) handshakeError(body []byte, msg string) error {
+	if rpcErr := c.responseError(body); rpcErr != nil {
+		return &statusError{msg: msg, err: rpcErr}
+	}
+	return &statusError{msg: msg, err: errSSEHandshake}
+}
+
+// responseError returns the JSON-RPC error of the response body, if any.
+func (c *A2AClient) responseError(body []byte) *jsonrpc.Error {
+	var response jsonrpc.RawResponse
+	if err := c.codec.Unmarshal(body, &response); err != nil || response.JSONRPC != jsonrpc.Version {
+		return nil
+	}
+	return response.Error
+}
+
+// statusError is the error of a non-success response, wrapping the JSON-RPC
+// error it carries, if any.
+type statusError struct {
+	code int
+	msg  string
+	err  error
+}
+
+// Error implements error.
+func (e *statusError) Error() string {
+	return e.msg
+}
+
+// Unwrap returns the wrapped error.
+func (e *statusError) Unwrap() error {
+	return e.err
+}
+
+// processSSEStream reads Server-Sent Events, or NDJSON events, from the response
+// body and sends them onto the provided channel. It handles closing the channel and response body.
 // Runs in its own goroutine.
 func (c *A2AClient) processSSEStream(
 	ctx context.Context,
 	resp *http.Response,
+	method string,
 	taskID string,
 	eventsChan chan<- protocol.TaskEvent,
+	acks *eventAcker,
 ) {
 	// Ensure resources are cleaned up when the goroutine exits.
 	defer resp.Body.Close()
 	defer close(eventsChan)
-	reader := sse.NewEventReader(resp.Body)
+	reader := newEventReader(resp)
 	log.Debugf("SSE Processor started for task %s", taskID)
 	for {
 		select {
@@ -221,9 +539,14 @@ func (c *A2AClient) processSSEStream(
 		default:
 			// Read the next event from the stream.
 			eventBytes, eventType, err := reader.ReadEvent()
+			if delay, ok := reader.Retry(); ok {
+				c.streamRetry.Store(&delay)
+			}
 			if err != nil {
 				if err == io.EOF {
 					log.Debugf("SSE stream ended cleanly (EOF) for task %s", taskID)
+				} else if errors.Is(err, errStreamIdle) {
+					log.Warnf("Closing SSE stream for task %s: %v", taskID, err)
 				} else if errors.Is(err, context.Canceled) ||
 					strings.Contains(err.Error(), "connection reset by peer") {
 					// Client disconnected normally
@@ -249,7 +572,7 @@ func (c *A2AClient) processSSEStream(
 
 			// First, try to unmarshal as a JSON-RPC response
 			var jsonRPCResponse jsonrpc.RawResponse
-			jsonRPCErr := json.Unmarshal(eventBytes, &jsonRPCResponse)
+			jsonRPCErr := c.codec.Unmarshal(eventBytes, &jsonRPCResponse)
 
 			// If this is a valid JSON-RPC response, extract the result for further processing
 			if jsonRPCErr == nil && jsonRPCResponse.JSONRPC == jsonrpc.Version {
@@ -274,7 +597,7 @@ func (c *A2AClient) processSSEStream(
 			switch eventType {
 			case protocol.EventTaskStatusUpdate:
 				var statusEvent protocol.TaskStatusUpdateEvent
-				if err := json.Unmarshal(eventBytes, &statusEvent); err != nil {
+				if err := c.codec.Unmarshal(eventBytes, &statusEvent); err != nil {
 					log.Errorf(
 						"Error unmarshaling TaskStatusUpdateEvent for task %s: %v. Data: %s",
 						taskID, err, string(eventBytes),
@@ -284,7 +607,7 @@ func (c *A2AClient) processSSEStream(
 				taskEvent = statusEvent
 			case protocol.EventTaskArtifactUpdate:
 				var artifactEvent protocol.TaskArtifactUpdateEvent
-				if err := json.Unmarshal(eventBytes, &artifactEvent); err != nil {
+				if err := c.codec.Unmarshal(eventBytes, &artifactEvent); err != nil {
 					log.Errorf(
 						"Error unmarshaling TaskArtifactUpdateEvent for task %s: %v. Data: %s",
 						taskID, err, string(eventBytes),
@@ -299,12 +622,21 @@ func (c *A2AClient) processSSEStream(
 				)
 				continue // Skip unknown event types.
 			}
+			if c.dedup.duplicate(taskID, taskEvent) {
+				log.Debugf("Skipping duplicate event %s of task %s", protocol.EventID(taskEvent), taskID)
+				acks.receivedEvent(reader.LastEventID())
+				continue
+			}
+			c.reportWarnings(method, taskID, protocol.EventWarnings(taskEvent))
+			c.taskCache.invalidate(taskID)
 			// Send the deserialized event to the caller's channel.
 			// Use a select to avoid blocking if the caller isn't reading fast enough
 			// or if the context was canceled concurrently.
 			select {
 			case eventsChan <- taskEvent:
 				// Event sent successfully.
+				c.dedup.delivered(taskID, taskEvent)
+				acks.receivedEvent(reader.LastEventID())
 			case <-ctx.Done():
 				log.Debugf(
 					"SSE context canceled while sending event for task %s: %v",
@@ -337,11 +669,12 @@ func (c *A2AClient) doRequestAndDecodeTask(
 	}
 	// Unmarshal the raw JSON 'result' field directly into the specific target structure provided by the caller.
 	task := &protocol.Task{}
-	if err := json.Unmarshal(fullResponse.Result, task); err != nil {
+	if err := c.codec.Unmarshal(fullResponse.Result, task); err != nil {
 		return nil, fmt.Errorf(
 			"failed to unmarshal rpc result: %w. Raw result: %s", err, string(fullResponse.Result),
 		)
 	}
+	c.reportWarnings(request.Method, task.ID, protocol.TaskWarnings(task))
 	return task, nil
 }
 
@@ -353,14 +686,94 @@ func (c *A2AClient) doRequestAndDecodeTask(
 func (c *A2AClient) doRequest(
 	ctx context.Context, request *jsonrpc.Request,
 ) (*jsonrpc.RawResponse, error) {
-	reqBody, err := json.Marshal(request)
+	// Requests about a task carry the task ID as request ID, which routes them
+	// to the replica serving the task.
+	taskID, _ := request.ID.(string)
+	respBodyBytes, err := c.post(ctx, request, taskID)
+	if err != nil {
+		return nil, err
+	}
+	response := &jsonrpc.RawResponse{}
+	// Decode the full JSON response body into the provided target.
+	if err := c.codec.Unmarshal(respBodyBytes, response); err != nil {
+		// Provide more context in the decode error message.
+		return nil, fmt.Errorf(
+			"a2aClient.doRequest: failed to decode response body: %w. Body: %s",
+			err, string(respBodyBytes),
+		)
+	}
+	return response, nil
+}
+
+// post sends the JSON-RPC request about taskID to the replica serving it and
+// returns the body of the response, failing on non-success HTTP statuses. The
+// request is retried according to the retry policy of the call, although
+// requests which are not idempotent, such as tasks/send, only when the replica
+// cannot have processed them.
+func (c *A2AClient) post(
+	ctx context.Context, request *jsonrpc.Request, taskID string,
+) ([]byte, error) {
+	reqBody, err := c.codec.Marshal(request)
 	if err != nil {
 		// Use a more specific error message prefix.
 		return nil, fmt.Errorf("a2aClient.doRequest: failed to marshal request: %w", err)
 	}
-	// Construct the target URL using the base URL.
-	// Assume the RPC endpoint is at the root of the baseURL.
-	targetURL := c.baseURL.String()
+	policy := c.retryPolicy(ctx)
+	backoff := policy.Backoff
+	for attempt := 1; ; attempt++ {
+		respBodyBytes, failed, err := c.postOnce(ctx, request, reqBody, taskID)
+		if err == nil || !failed || attempt >= policy.MaxAttempts {
+			return respBodyBytes, err
+		}
+		if !idempotentMethods[request.Method] && !requestUnprocessed(err) {
+			return nil, err
+		}
+		log.Debugf("Retrying %s after attempt %d failed: %v", request.Method, attempt, err)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return nil, err
+		}
+		backoff *= 2
+	}
+}
+
+// idempotentMethods are the methods whose requests can be sent again without
+// effect if the replica processed them but its response was lost.
+var idempotentMethods = map[string]bool{
+	protocol.MethodTasksGet:                 true,
+	protocol.MethodTasksCancel:              true,
+	protocol.MethodTasksPushNotificationSet: true,
+	protocol.MethodTasksPushNotificationGet: true,
+	protocol.MethodTasksGraphGet:            true,
+	protocol.MethodSkillsExamplesList:       true,
+	protocol.MethodTasksAckEvents:           true,
+	protocol.MethodTasksPollEvents:          true,
+}
+
+// requestUnprocessed reports whether the failed attempt err of post cannot
+// have been processed by the replica: the connection to the replica could not
+// be established, or the replica answered that it is unavailable.
+func requestUnprocessed(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	var statusErr *statusError
+	return errors.As(err, &statusErr) && statusErr.code == http.StatusServiceUnavailable
+}
+
+// postOnce makes one attempt of post, reporting whether it failed because of
+// the replica.
+func (c *A2AClient) postOnce(
+	ctx context.Context, request *jsonrpc.Request, reqBody []byte, taskID string,
+) ([]byte, bool, error) {
+	target, err := c.acquire(ctx, taskID)
+	if err != nil {
+		return nil, false, fmt.Errorf("a2aClient.doRequest: %w", err)
+	}
+	// Assume the RPC endpoint is at the root of the replica base URL.
+	targetURL := target.url.String()
 	req, err := http.NewRequestWithContext(
 		ctx,
 		http.MethodPost,
@@ -368,21 +781,33 @@ func (c *A2AClient) doRequest(
 		bytes.NewReader(reqBody),
 	)
 	if err != nil {
-		return nil, fmt.Errorf("a2aClient.doRequest: failed to create http request: %w", err)
+		c.balancer.release(target, false)
+		return nil, false, fmt.Errorf("a2aClient.doRequest: failed to create http request: %w", err)
 	}
 	// Set required headers.
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	req.Header.Set("Accept", "application/json")
+	c.setMetadataHeaders(ctx, req.Header)
 	if c.userAgent != "" {
 		req.Header.Set("User-Agent", c.userAgent)
 	}
+	if c.compression {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	httpClient := c.callHTTPClient(ctx)
 	log.Debugf("A2A Client Request -> Method: %s, ID: %v, URL: %s", request.Method, request.ID, targetURL)
-	resp, err := c.httpReqHandler(ctx, c.httpClient, req)
+	resp, err := c.httpReqHandler(ctx, httpClient, req)
+	failed := replicaFailed(ctx, resp, err)
+	c.balancer.release(target, failed)
 	if err != nil {
-		return nil, fmt.Errorf("a2aClient.doRequest: http request failed: %w", err)
+		return nil, failed, fmt.Errorf("a2aClient.doRequest: http request failed: %w", err)
 	}
 	if resp == nil || resp.Body == nil {
-		return nil, fmt.Errorf("a2aClient.doRequest: unexpected nil response")
+		return nil, false, fmt.Errorf("a2aClient.doRequest: unexpected nil response")
+	}
+	if err := decodeResponseBody(resp); err != nil {
+		resp.Body.Close()
+		return nil, false, fmt.Errorf("a2aClient.doRequest: %w", err)
 	}
 
 	// Ensure body is always closed.
@@ -399,21 +824,33 @@ func (c *A2AClient) doRequest(
 	log.Debugf("A2A Client Response <- Status: %d, ID: %v", resp.StatusCode, request.ID)
 	// Check for non-success HTTP status codes. This is separate from JSON-RPC errors.
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf(
+		err := &statusError{code: resp.StatusCode, msg: fmt.Sprintf(
 			"a2aClient.doRequest: unexpected http status %d: %s",
 			resp.StatusCode, string(respBodyBytes),
-		)
+		)}
+		if rpcErr := c.responseError(respBodyBytes); rpcErr != nil {
+			err.err = rpcErr
+		}
+		return nil, failed, err
 	}
-	response := &jsonrpc.RawResponse{}
-	// Decode the full JSON response body into the provided target.
-	if err := json.Unmarshal(respBodyBytes, response); err != nil {
-		// Provide more context in the decode error message.
-		return nil, fmt.Errorf(
-			"a2aClient.doRequest: failed to decode response body (status %d): %w. Body: %s",
-			resp.StatusCode, err, string(respBodyBytes),
-		)
+	c.checkDeprecationHeaders(request.Method, resp.Header)
+	return respBodyBytes, false, nil
+}
+
+// setMetadataHeaders adds the metadata of the client and the outgoing metadata
+// of ctx to the request headers, then sets the headers of the client and of
+// the call of ctx, in that order of precedence.
+func (c *A2AClient) setMetadataHeaders(ctx context.Context, header http.Header) {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	metadata.ToHeader(metadata.Join(c.metadata, md), header)
+	for key, values := range c.headers {
+		header[key] = append([]string(nil), values...)
+	}
+	if o := callOptionsFromContext(ctx); o != nil {
+		for key, values := range o.header {
+			header[key] = append([]string(nil), values...)
+		}
 	}
-	return response, nil
 }
 
 // SetPushNotification configures push notifications for a task.
@@ -421,9 +858,11 @@ func (c *A2AClient) doRequest(
 func (c *A2AClient) SetPushNotification(
 	ctx context.Context,
 	params protocol.TaskPushNotificationConfig,
+	opts ...CallOption,
 ) (*protocol.TaskPushNotificationConfig, error) {
+	ctx = withCallOptions(ctx, opts)
 	request := jsonrpc.NewRequest(protocol.MethodTasksPushNotificationSet, params.ID)
-	paramsBytes, err := json.Marshal(params)
+	paramsBytes, err := c.codec.Marshal(params)
 	if err != nil {
 		return nil, fmt.Errorf("a2aClient.SetPushNotification: failed to marshal params: %w", err)
 	}
@@ -447,7 +886,7 @@ func (c *A2AClient) SetPushNotification(
 
 	// Unmarshal the result into a TaskPushNotificationConfig
 	config := &protocol.TaskPushNotificationConfig{}
-	if err := json.Unmarshal(fullResponse.Result, config); err != nil {
+	if err := c.codec.Unmarshal(fullResponse.Result, config); err != nil {
 		return nil, fmt.Errorf(
 			"failed to unmarshal push notification config: %w. Raw result: %s",
 			err, string(fullResponse.Result),
@@ -461,9 +900,11 @@ func (c *A2AClient) SetPushNotification(
 func (c *A2AClient) GetPushNotification(
 	ctx context.Context,
 	params protocol.TaskIDParams,
+	opts ...CallOption,
 ) (*protocol.TaskPushNotificationConfig, error) {
+	ctx = withCallOptions(ctx, opts)
 	request := jsonrpc.NewRequest(protocol.MethodTasksPushNotificationGet, params.ID)
-	paramsBytes, err := json.Marshal(params)
+	paramsBytes, err := c.codec.Marshal(params)
 	if err != nil {
 		return nil, fmt.Errorf("a2aClient.GetPushNotification: failed to marshal params: %w", err)
 	}
@@ -487,7 +928,7 @@ func (c *A2AClient) GetPushNotification(
 
 	// Unmarshal the result into a TaskPushNotificationConfig
 	config := &protocol.TaskPushNotificationConfig{}
-	if err := json.Unmarshal(fullResponse.Result, config); err != nil {
+	if err := c.codec.Unmarshal(fullResponse.Result, config); err != nil {
 		return nil, fmt.Errorf(
 			"failed to unmarshal push notification config: %w. Raw result: %s",
 			err, string(fullResponse.Result),
@@ -78,6 +78,24 @@ func TestA2AClient_SendTask(t *testing.T) {
 		assert.Equal(t, protocol.TaskStateSubmitted, result.Status.State)
 	})
 
+	t.Run("SendTask Generated ID", func(t *testing.T) {
+		respBody := fmt.Sprintf(`{"jsonrpc":"2.0","id":"%s","result":{"id":"%s","status":{"state":"submitted"}}}`,
+			taskID, taskID)
+		server := httptest.NewServer(createMockServerHandler(
+			t, "tasks/send", expectedRequest, respBody, http.StatusOK, nil,
+		))
+		defer server.Close()
+		generator := protocol.IDGeneratorFunc(func() string { return taskID })
+		client, err := NewA2AClient(server.URL, WithIDGenerator(generator))
+		require.NoError(t, err)
+
+		withoutID := params
+		withoutID.ID = ""
+		result, err := client.SendTasks(context.Background(), withoutID)
+		require.NoError(t, err)
+		assert.Equal(t, taskID, result.ID)
+	})
+
 	t.Run("SendTask HTTP Error", func(t *testing.T) {
 		// Prepare mock server HTTP error response.
 		mockHandler := createMockServerHandler(
@@ -142,7 +160,8 @@ func TestA2AClient_StreamTask(t *testing.T) {
 		})
 
 		// Format the mock SSE stream string.
-		sseStream := fmt.Sprintf("event: task_status_update\ndata: %s\n\n"+
+		sseStream := fmt.Sprintf("retry: 2500\n\n"+
+			"event: task_status_update\ndata: %s\n\n"+
 			"event: task_artifact_update\ndata: %s\n\n"+
 			"event: task_status_update\ndata: %s\n\n",
 			string(sseEvent1Data), string(sseEvent2Data), string(sseEvent3Data))
@@ -194,6 +213,9 @@ func TestA2AClient_StreamTask(t *testing.T) {
 
 		// Assert the content and order of received events.
 		require.Len(t, receivedEvents, 3, "Should receive exactly 3 events")
+		delay, ok := client.StreamRetryDelay()
+		assert.True(t, ok, "The retry field should be recorded")
+		assert.Equal(t, 2500*time.Millisecond, delay)
 		_, ok1 := receivedEvents[0].(protocol.TaskStatusUpdateEvent)
 		_, ok2 := receivedEvents[1].(protocol.TaskArtifactUpdateEvent)
 		_, ok3 := receivedEvents[2].(protocol.TaskStatusUpdateEvent)
@@ -0,0 +1,71 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"context"
+	"sync"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// taskCall is a tasks/get request shared by concurrent callers.
+type taskCall struct {
+	done chan struct{} // Closed once task and err are set.
+	task *protocol.Task
+	err  error
+}
+
+// taskCoalescer collapses the concurrent identical tasks/get requests into a
+// single request whose result all the callers receive.
+type taskCoalescer struct {
+	mu    sync.Mutex
+	calls map[string]*taskCall // By task ID and query key.
+}
+
+// newTaskCoalescer creates a coalescer without calls in flight.
+func newTaskCoalescer() *taskCoalescer {
+	return &taskCoalescer{calls: make(map[string]*taskCall)}
+}
+
+// do returns the result of get for params, joining the call in flight for the
+// same params if there is one. The shared call is detached from the context
+// of the caller that started it, so that each caller only gives up on its own
+// context.
+func (c *taskCoalescer) do(
+	ctx context.Context,
+	params protocol.TaskQueryParams,
+	get func(ctx context.Context) (*protocol.Task, error),
+) (*protocol.Task, error) {
+	if c == nil {
+		return get(ctx)
+	}
+	key := params.ID + "/" + queryKey(ctx, params)
+	c.mu.Lock()
+	call, ok := c.calls[key]
+	if !ok {
+		call = &taskCall{done: make(chan struct{})}
+		c.calls[key] = call
+		go func() {
+			call.task, call.err = get(context.WithoutCancel(ctx))
+			c.mu.Lock()
+			delete(c.calls, key)
+			c.mu.Unlock()
+			close(call.done)
+		}()
+	}
+	c.mu.Unlock()
+	select {
+	case <-call.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if call.err != nil {
+		return nil, call.err
+	}
+	return call.task.Clone(), nil // Callers must not share the task.
+}
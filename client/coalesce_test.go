@@ -0,0 +1,79 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+func TestWithRequestCoalescing(t *testing.T) {
+	var gets atomic.Int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID interface{} `json:"id"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		gets.Add(1)
+		select {
+		case <-release:
+		case <-r.Context().Done():
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      req.ID,
+			"result":  protocol.Task{ID: "watched"},
+		})
+	}))
+	defer srv.Close()
+	c, err := NewA2AClient(srv.URL, WithRequestCoalescing(true))
+	require.NoError(t, err)
+
+	// A caller giving up does not fail the others.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = c.GetTasks(ctx, protocol.TaskQueryParams{ID: "watched"})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	var wg sync.WaitGroup
+	tasks := make([]*protocol.Task, 10)
+	for i := range tasks {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			task, err := c.GetTasks(context.Background(), protocol.TaskQueryParams{ID: "watched"})
+			assert.NoError(t, err)
+			tasks[i] = task
+		}(i)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(1), gets.Load(), "concurrent calls share one request")
+	for _, task := range tasks {
+		require.NotNil(t, task)
+		assert.Equal(t, "watched", task.ID)
+	}
+	assert.NotSame(t, tasks[0], tasks[1], "callers get their own task")
+
+	_, err = c.GetTasks(context.Background(), protocol.TaskQueryParams{ID: "watched"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), gets.Load(), "later calls send a new request")
+}
@@ -0,0 +1,83 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// acceptEncoding is the Accept-Encoding header sent when compression is enabled.
+const acceptEncoding = "gzip, deflate"
+
+// decodedBody closes both the decompressor and the original response body.
+type decodedBody struct {
+	io.Reader
+	decoder io.Closer
+	body    io.Closer
+}
+
+// Close implements io.Closer.
+func (b *decodedBody) Close() error {
+	decErr := b.decoder.Close()
+	if err := b.body.Close(); err != nil {
+		return err
+	}
+	return decErr
+}
+
+// decodeResponseBody replaces resp.Body with a decompressing reader according to
+// its Content-Encoding. Responses already decompressed by net/http are left as is.
+func decodeResponseBody(resp *http.Response) error {
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	var decoder io.ReadCloser
+	switch encoding {
+	case "", "identity":
+		return nil
+	case "gzip", "x-gzip":
+		r, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read gzip response: %w", err)
+		}
+		decoder = r
+	case "deflate":
+		// "deflate" is zlib-wrapped per RFC 9110, but some servers send raw deflate.
+		buffered := bufio.NewReader(resp.Body)
+		header, err := buffered.Peek(2)
+		if err != nil {
+			return fmt.Errorf("failed to read deflate response: %w", err)
+		}
+		if isZlibHeader(header) {
+			r, err := zlib.NewReader(buffered)
+			if err != nil {
+				return fmt.Errorf("failed to read deflate response: %w", err)
+			}
+			decoder = r
+		} else {
+			decoder = flate.NewReader(buffered)
+		}
+	default:
+		return fmt.Errorf("unsupported response Content-Encoding %q", encoding)
+	}
+	resp.Body = &decodedBody{Reader: decoder, decoder: decoder, body: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return nil
+}
+
+// isZlibHeader reports whether b starts with a valid zlib (RFC 1950) header.
+func isZlibHeader(b []byte) bool {
+	return b[0]&0x0f == 8 && (uint16(b[0])<<8|uint16(b[1]))%31 == 0
+}
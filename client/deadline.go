@@ -0,0 +1,39 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"context"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// propagateDeadline records the time left until the deadline of ctx as the
+// deadline budget in the params metadata, unless the metadata already holds a
+// smaller budget. Inside a processor ctx expires with the budget the server
+// received, so each delegation hop passes on what remains of it.
+// The caller's params are not modified.
+func (c *A2AClient) propagateDeadline(ctx context.Context, params protocol.SendTaskParams) protocol.SendTaskParams {
+	if !c.deadlinePropagation {
+		return params
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return params
+	}
+	budget := time.Until(deadline)
+	if current, ok := protocol.DeadlineBudgetFromMetadata(params.Metadata); ok && current <= budget {
+		return params
+	}
+	metadata := make(map[string]interface{}, len(params.Metadata)+1)
+	for k, v := range params.Metadata {
+		metadata[k] = v
+	}
+	params.Metadata = protocol.SetDeadlineBudget(metadata, budget)
+	return params
+}
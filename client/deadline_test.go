@@ -0,0 +1,46 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+func TestPropagateDeadline(t *testing.T) {
+	c, err := NewA2AClient("http://localhost:8080/")
+	require.NoError(t, err)
+	params := protocol.SendTaskParams{ID: "t1", Metadata: map[string]interface{}{"k": "v"}}
+
+	got := c.propagateDeadline(context.Background(), params)
+	assert.Equal(t, params, got, "no deadline, no budget")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	got = c.propagateDeadline(ctx, params)
+	budget, ok := protocol.DeadlineBudgetFromMetadata(got.Metadata)
+	require.True(t, ok)
+	assert.InDelta(t, 10*time.Second, budget, float64(time.Second))
+	assert.Equal(t, "v", got.Metadata["k"])
+	assert.NotContains(t, params.Metadata, protocol.MetadataKeyDeadlineBudget)
+
+	// A smaller budget set by the caller is kept.
+	params.Metadata = protocol.SetDeadlineBudget(map[string]interface{}{}, time.Second)
+	got = c.propagateDeadline(ctx, params)
+	budget, _ = protocol.DeadlineBudgetFromMetadata(got.Metadata)
+	assert.Equal(t, time.Second, budget)
+
+	WithDeadlinePropagation(false)(c)
+	params.Metadata = nil
+	assert.Equal(t, params, c.propagateDeadline(ctx, params))
+}
@@ -0,0 +1,102 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"strconv"
+	"sync"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// defaultDedupTasks is the default bound of the tasks whose last delivered
+// event is remembered.
+const defaultDedupTasks = 4096
+
+// eventDeduplicator remembers the ID of the last event delivered to the
+// consumer for each task, so that events delivered again by a later stream of
+// the task, e.g. replayed after resubscribing, are skipped. It remembers up to
+// maxTasks tasks; the tasks first seen earliest are forgotten first.
+type eventDeduplicator struct {
+	maxTasks int
+	mu       sync.Mutex
+	last     map[string]uint64
+	order    []string // Tasks by first delivery, oldest first, for eviction.
+}
+
+// newEventDeduplicator creates an empty deduplicator remembering up to
+// maxTasks tasks, or defaultDedupTasks if maxTasks is not positive.
+func newEventDeduplicator(maxTasks int) *eventDeduplicator {
+	if maxTasks <= 0 {
+		maxTasks = defaultDedupTasks
+	}
+	return &eventDeduplicator{maxTasks: maxTasks, last: make(map[string]uint64)}
+}
+
+// duplicate reports whether event of taskID was already delivered. Events
+// without an event ID are never duplicates.
+func (d *eventDeduplicator) duplicate(taskID string, event protocol.TaskEvent) bool {
+	if d == nil {
+		return false
+	}
+	seq, ok := protocol.EventSequence(event)
+	if !ok {
+		return false
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return seq <= d.last[taskID]
+}
+
+// delivered records that event of taskID was delivered to the consumer.
+func (d *eventDeduplicator) delivered(taskID string, event protocol.TaskEvent) {
+	if d == nil {
+		return
+	}
+	seq, ok := protocol.EventSequence(event)
+	if !ok {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	last, known := d.last[taskID]
+	if !known {
+		d.order = append(d.order, taskID)
+		if len(d.order) > d.maxTasks {
+			delete(d.last, d.order[0])
+			d.order[0] = ""
+			d.order = d.order[1:]
+		}
+	}
+	if seq > last {
+		d.last[taskID] = seq
+	}
+}
+
+// lastEventID returns the ID of the last event of taskID delivered, or an
+// empty string if none is remembered.
+func (d *eventDeduplicator) lastEventID(taskID string) string {
+	if d == nil {
+		return ""
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	last, ok := d.last[taskID]
+	if !ok {
+		return ""
+	}
+	return strconv.FormatUint(last, 10)
+}
+
+// LastEventID returns the ID of the last event of taskID the client delivered
+// from an event stream, for resubscribing with protocol.TaskIDParams.LastEventID
+// after the stream broke. It returns an empty string if the agent does not
+// number its events, no event was delivered yet or deduplication is not
+// enabled with WithEventDeduplication.
+func (c *A2AClient) LastEventID(taskID string) string {
+	return c.dedup.lastEventID(taskID)
+}
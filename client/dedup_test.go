@@ -0,0 +1,41 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+func TestEventDeduplicator(t *testing.T) {
+	event := func(id int) protocol.TaskEvent {
+		return protocol.WithEventID(protocol.TaskStatusUpdateEvent{ID: "t"}, strconv.Itoa(id))
+	}
+	d := newEventDeduplicator(2)
+	d.delivered("a", event(2))
+	assert.True(t, d.duplicate("a", event(1)))
+	assert.True(t, d.duplicate("a", event(2)))
+	assert.False(t, d.duplicate("a", event(3)))
+	assert.False(t, d.duplicate("a", protocol.TaskStatusUpdateEvent{ID: "t"}), "events without ID are delivered")
+
+	// The tasks first seen earliest are forgotten beyond the bound.
+	d.delivered("b", event(1))
+	d.delivered("c", event(1))
+	assert.Empty(t, d.lastEventID("a"))
+	assert.Equal(t, "1", d.lastEventID("b"))
+	assert.Equal(t, "1", d.lastEventID("c"))
+	assert.Len(t, d.last, 2)
+
+	// Deduplication is disabled by default.
+	c, err := NewA2AClient("http://localhost")
+	assert.NoError(t, err)
+	assert.Nil(t, c.dedup)
+}
@@ -0,0 +1,53 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// DeprecationHandler is called when the agent reports that the client uses a
+// deprecated method, or a deprecated params field of method, as described by
+// warning. It is called once per client for each deprecated method and field.
+type DeprecationHandler func(method string, warning protocol.Warning)
+
+// logDeprecation is the default DeprecationHandler.
+func logDeprecation(method string, warning protocol.Warning) {
+	log.Warnf("Agent deprecation notice for %s: %s", method, warning.Message)
+}
+
+// checkDeprecationHeaders reports a deprecation of method signaled by the
+// Deprecation and Sunset headers of a response.
+func (c *A2AClient) checkDeprecationHeaders(method string, header http.Header) {
+	if header.Get(protocol.DeprecationHeader) == "" {
+		return
+	}
+	message := fmt.Sprintf("method %s is deprecated", method)
+	if sunset, err := http.ParseTime(header.Get(protocol.SunsetHeader)); err == nil {
+		message += fmt.Sprintf("; it may stop working after %s", sunset.UTC().Format(time.RFC3339))
+	}
+	c.reportDeprecation(method, protocol.Warning{Code: protocol.WarningCodeDeprecated, Message: message})
+}
+
+// reportDeprecation passes the deprecation of method described by warning to
+// the deprecation handler, unless it was already reported.
+func (c *A2AClient) reportDeprecation(method string, warning protocol.Warning) {
+	if c.deprecationHandler == nil {
+		return
+	}
+	// Header and warning notices of a method deprecation share their key.
+	key := method + "\x00" + warning.Pointer
+	if _, seen := c.deprecations.LoadOrStore(key, struct{}{}); seen {
+		return
+	}
+	c.deprecationHandler(method, warning)
+}
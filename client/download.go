@@ -0,0 +1,75 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// WithCallRange requests the length bytes of the content at offset from
+// DownloadArtifact, or the content from offset to the end if length is not
+// positive, e.g. to resume an interrupted download.
+func WithCallRange(offset, length int64) CallOption {
+	value := fmt.Sprintf("bytes=%d-", offset)
+	if length > 0 {
+		value += strconv.FormatInt(offset+length-1, 10)
+	}
+	return WithCallHeader("Range", value)
+}
+
+// DownloadArtifact downloads the content of the artifact at index of a task
+// from the artifact download endpoint of the agent, at
+// protocol.DefaultArtifactPath unless set with WithArtifactPath. The caller
+// must close the returned content. The Accept header, set with
+// WithCallHeader, selects between the content of the artifact and its JSON
+// encoding, and WithCallRange requests a part of it. When the client balances
+// over several replicas, the request goes to the replica that served the
+// task. The timeout of the call bounds the wait for the response headers, not
+// the reading of the content.
+func (c *A2AClient) DownloadArtifact(
+	ctx context.Context,
+	taskID string,
+	index int,
+	opts ...CallOption,
+) (io.ReadCloser, error) {
+	ctx = withCallOptions(ctx, opts)
+	target, err := c.acquire(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("a2aClient.DownloadArtifact: %w", err)
+	}
+	endpoint := target.url.ResolveReference(&url.URL{
+		Path: strings.Trim(c.artifactPath, "/") + "/" +
+			url.PathEscape(taskID) + "/" + strconv.Itoa(index),
+	})
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		c.balancer.release(target, false)
+		return nil, fmt.Errorf("a2aClient.DownloadArtifact: failed to create http request: %w", err)
+	}
+	c.setMetadataHeaders(ctx, req.Header)
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	resp, err := c.doStreamRequest(req)
+	c.balancer.release(target, replicaFailed(ctx, resp, err))
+	if err != nil {
+		return nil, fmt.Errorf("a2aClient.DownloadArtifact: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+		resp.Body.Close()
+		return nil, fmt.Errorf("a2aClient.DownloadArtifact: unexpected http status %d: %s",
+			resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return resp.Body, nil
+}
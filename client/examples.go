@@ -0,0 +1,69 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// ListSkillExamples lists the runnable examples of the agent's skills using the
+// skills/examples/list extension method.
+func (c *A2AClient) ListSkillExamples(ctx context.Context, opts ...CallOption) ([]protocol.SkillExampleEntry, error) {
+	ctx = withCallOptions(ctx, opts)
+	// Without a task ID the request is not bound to a replica.
+	request := jsonrpc.NewRequest(protocol.MethodSkillsExamplesList, "")
+	var result protocol.ListSkillExamplesResult
+	if err := c.doRequestAndDecode(ctx, request, &result); err != nil {
+		return nil, fmt.Errorf("a2aClient.ListSkillExamples: %w", err)
+	}
+	return result.Examples, nil
+}
+
+// RunSkillExample runs an example of a skill in a sandbox on the agent using the
+// skills/examples/run extension method, e.g. to smoke test a deployment.
+// A failing example is reported by the result, not as an error.
+func (c *A2AClient) RunSkillExample(
+	ctx context.Context,
+	skillID, exampleID string,
+	opts ...CallOption,
+) (*protocol.RunSkillExampleResult, error) {
+	ctx = withCallOptions(ctx, opts)
+	request := jsonrpc.NewRequest(protocol.MethodSkillsExamplesRun, "")
+	params := protocol.RunSkillExampleParams{SkillID: skillID, ExampleID: exampleID}
+	paramsBytes, err := c.codec.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("a2aClient.RunSkillExample: failed to marshal params: %w", err)
+	}
+	request.Params = paramsBytes
+	var result protocol.RunSkillExampleResult
+	if err := c.doRequestAndDecode(ctx, request, &result); err != nil {
+		return nil, fmt.Errorf("a2aClient.RunSkillExample: %w", err)
+	}
+	return &result, nil
+}
+
+// doRequestAndDecode performs request and decodes its result into out.
+func (c *A2AClient) doRequestAndDecode(ctx context.Context, request *jsonrpc.Request, out interface{}) error {
+	fullResponse, err := c.doRequest(ctx, request)
+	if err != nil {
+		return err
+	}
+	if fullResponse.Error != nil {
+		return fullResponse.Error
+	}
+	if len(fullResponse.Result) == 0 {
+		return fmt.Errorf("rpc response missing required 'result' field for id %v", request.ID)
+	}
+	if err := c.codec.Unmarshal(fullResponse.Result, out); err != nil {
+		return fmt.Errorf("failed to unmarshal rpc result: %w. Raw result: %s", err, string(fullResponse.Result))
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+func TestSkillExamples(t *testing.T) {
+	agent := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req jsonrpc.Request
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		var result interface{}
+		switch req.Method {
+		case protocol.MethodSkillsExamplesList:
+			result = protocol.ListSkillExamplesResult{Examples: []protocol.SkillExampleEntry{{
+				SkillID:      "echo",
+				SkillExample: protocol.SkillExample{ID: "hello"},
+			}}}
+		case protocol.MethodSkillsExamplesRun:
+			var params protocol.RunSkillExampleParams
+			assert.NoError(t, json.Unmarshal(req.Params, &params))
+			result = protocol.RunSkillExampleResult{SkillID: params.SkillID, ExampleID: params.ExampleID, Passed: true}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		assert.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0", "id": req.ID, "result": result,
+		}))
+	}))
+	defer agent.Close()
+
+	c, err := NewA2AClient(agent.URL)
+	require.NoError(t, err)
+	examples, err := c.ListSkillExamples(context.Background())
+	require.NoError(t, err)
+	require.Len(t, examples, 1)
+	assert.Equal(t, "hello", examples[0].ID)
+
+	result, err := c.RunSkillExample(context.Background(), "echo", "hello")
+	require.NoError(t, err)
+	assert.True(t, result.Passed)
+	assert.Equal(t, "echo", result.SkillID)
+}
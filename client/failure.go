@@ -0,0 +1,80 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"errors"
+	"strings"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// TaskFailure returns the structured error of a failed or canceled task, or
+// nil if the task did not fail. For agents that do not report structured
+// errors it is built from the status: a non-retryable internal error with the
+// text of the status message, or the error of the cancellation reason.
+func TaskFailure(task *protocol.Task) *protocol.TaskError {
+	if task == nil {
+		return nil
+	}
+	return statusFailure(task.Status)
+}
+
+// EventFailure returns the structured error carried by a status update event
+// of a failed or canceled task, or nil. See TaskFailure.
+func EventFailure(event protocol.TaskEvent) *protocol.TaskError {
+	switch e := event.(type) {
+	case protocol.TaskStatusUpdateEvent:
+		return statusFailure(e.Status)
+	case *protocol.TaskStatusUpdateEvent:
+		return statusFailure(e.Status)
+	}
+	return nil
+}
+
+// IsRetryable reports whether err is, or wraps, a retryable task error.
+func IsRetryable(err error) bool {
+	var taskErr *protocol.TaskError
+	return errors.As(err, &taskErr) && taskErr.Retryable
+}
+
+// statusFailure returns the structured error of status, if it is failed or canceled.
+func statusFailure(status protocol.TaskStatus) *protocol.TaskError {
+	if status.Error != nil {
+		return status.Error
+	}
+	switch status.State {
+	case protocol.TaskStateFailed:
+		return &protocol.TaskError{
+			Code:    protocol.TaskErrorCodeInternal,
+			Message: messageText(status.Message),
+		}
+	case protocol.TaskStateCanceled:
+		if status.CancelReason != "" {
+			return protocol.NewCancelTaskError(status.CancelReason)
+		}
+		return protocol.NewCancelTaskError(protocol.CancelReasonUserRequested)
+	}
+	return nil
+}
+
+// messageText joins the text parts of msg.
+func messageText(msg *protocol.Message) string {
+	if msg == nil {
+		return ""
+	}
+	var texts []string
+	for _, part := range msg.Parts {
+		switch p := part.(type) {
+		case protocol.TextPart:
+			texts = append(texts, p.Text)
+		case *protocol.TextPart:
+			texts = append(texts, p.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
@@ -0,0 +1,43 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+func TestTaskFailure(t *testing.T) {
+	assert.Nil(t, TaskFailure(nil))
+	assert.Nil(t, TaskFailure(&protocol.Task{Status: protocol.TaskStatus{State: protocol.TaskStateCompleted}}))
+
+	structured := &protocol.TaskError{Code: protocol.TaskErrorCodeUnavailable, Message: "down", Retryable: true}
+	failure := TaskFailure(&protocol.Task{Status: protocol.TaskStatus{State: protocol.TaskStateFailed, Error: structured}})
+	assert.Same(t, structured, failure)
+	assert.True(t, IsRetryable(fmt.Errorf("send: %w", failure)))
+
+	// Agents without structured errors.
+	msg := protocol.NewMessage(protocol.MessageRoleAgent, []protocol.Part{protocol.NewTextPart("boom")})
+	failure = TaskFailure(&protocol.Task{Status: protocol.TaskStatus{State: protocol.TaskStateFailed, Message: &msg}})
+	require.NotNil(t, failure)
+	assert.Equal(t, protocol.TaskErrorCodeInternal, failure.Code)
+	assert.Equal(t, "boom", failure.Message)
+	assert.False(t, IsRetryable(failure))
+
+	failure = EventFailure(protocol.TaskStatusUpdateEvent{Status: protocol.TaskStatus{
+		State: protocol.TaskStateCanceled, CancelReason: protocol.CancelReasonTimeout,
+	}})
+	require.NotNil(t, failure)
+	assert.Equal(t, protocol.TaskErrorCodeCanceled, failure.Code)
+	assert.True(t, failure.Retryable)
+	assert.Nil(t, EventFailure(protocol.TaskArtifactUpdateEvent{}))
+}
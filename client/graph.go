@@ -0,0 +1,58 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// SendTaskGraph submits tasks with dependencies between them using the
+// tasks/graph/send extension method. The agent starts each task once the tasks
+// it depends on completed; the returned graph is its initial state.
+func (c *A2AClient) SendTaskGraph(
+	ctx context.Context,
+	params protocol.SendTaskGraphParams,
+	opts ...CallOption,
+) (*protocol.TaskGraph, error) {
+	ctx = withCallOptions(ctx, opts)
+	request := jsonrpc.NewRequest(protocol.MethodTasksGraphSend, params.ID)
+	paramsBytes, err := c.codec.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("a2aClient.SendTaskGraph: failed to marshal params: %w", err)
+	}
+	request.Params = paramsBytes
+	var graph protocol.TaskGraph
+	if err := c.doRequestAndDecode(ctx, request, &graph); err != nil {
+		return nil, fmt.Errorf("a2aClient.SendTaskGraph: %w", err)
+	}
+	return &graph, nil
+}
+
+// GetTaskGraph returns the state of a task graph using the tasks/graph/get
+// extension method.
+func (c *A2AClient) GetTaskGraph(
+	ctx context.Context,
+	params protocol.TaskIDParams,
+	opts ...CallOption,
+) (*protocol.TaskGraph, error) {
+	ctx = withCallOptions(ctx, opts)
+	request := jsonrpc.NewRequest(protocol.MethodTasksGraphGet, params.ID)
+	paramsBytes, err := c.codec.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("a2aClient.GetTaskGraph: failed to marshal params: %w", err)
+	}
+	request.Params = paramsBytes
+	var graph protocol.TaskGraph
+	if err := c.doRequestAndDecode(ctx, request, &graph); err != nil {
+		return nil, fmt.Errorf("a2aClient.GetTaskGraph: %w", err)
+	}
+	return &graph, nil
+}
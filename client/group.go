@@ -0,0 +1,231 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// ErrStreamGroupClosed is returned when adding streams to a closed StreamGroup.
+var ErrStreamGroupClosed = errors.New("stream group is closed")
+
+// errStreamEnded is the error of streams ending before the final event of
+// their task.
+var errStreamEnded = errors.New("stream ended before the final event")
+
+// GroupEvent is an event of one of the streams of a StreamGroup.
+type GroupEvent struct {
+	// Label is the label the stream was added with.
+	Label string
+	// TaskID is the ID of the task of the stream.
+	TaskID string
+	// Event is the task event, or nil for the last GroupEvent of the stream,
+	// delivered when it ends.
+	Event protocol.TaskEvent
+	// Err is the error that ended the stream, if it did not end with the final
+	// event of its task. It is only set on the last GroupEvent of the stream.
+	Err error
+}
+
+// StreamGroupOption configures a StreamGroup.
+type StreamGroupOption func(*StreamGroup)
+
+// WithStreamGroupFailFast makes the first failing stream of a StreamGroup
+// cancel the others, like an errgroup.Group.
+func WithStreamGroupFailFast(enabled bool) StreamGroupOption {
+	return func(g *StreamGroup) {
+		g.failFast = enabled
+	}
+}
+
+// StreamGroup consumes the event streams of several tasks concurrently,
+// possibly of different agents, and merges their events into one channel
+// labeled with the stream they come from. Each stream ends after the final
+// event of its task, on error, or when the group is canceled.
+//
+// Streams are added with Stream and Resubscribe. Once all the streams are
+// added, Close must be called so that the channel of Events is closed when
+// the last stream ends. Streams may be added while events are consumed, e.g.
+// for follow-up tasks, as long as Close was not called. The events must be
+// consumed until the channel is closed, or the group canceled.
+type StreamGroup struct {
+	ctx      context.Context
+	cancel   context.CancelCauseFunc
+	failFast bool
+	events   chan GroupEvent
+
+	mu     sync.Mutex
+	active int   // Number of streams not yet ended.
+	closed bool  // Whether Close was called.
+	err    error // First stream error.
+}
+
+// NewStreamGroup creates a StreamGroup whose streams are canceled with ctx.
+func NewStreamGroup(ctx context.Context, opts ...StreamGroupOption) *StreamGroup {
+	ctx, cancel := context.WithCancelCause(ctx)
+	g := &StreamGroup{
+		ctx:    ctx,
+		cancel: cancel,
+		events: make(chan GroupEvent),
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// Stream sends a task to the agent of c with StreamTask and adds its stream
+// to the group under label. If the task has no ID, one is generated by c.
+func (g *StreamGroup) Stream(label string, c *A2AClient, params protocol.SendTaskParams) error {
+	params = c.assignTaskID(params)
+	return g.add(label, params.ID, func(ctx context.Context) (<-chan protocol.TaskEvent, error) {
+		return c.StreamTask(ctx, params)
+	})
+}
+
+// Resubscribe adds the stream of an existing task of the agent of c, opened
+// with ResubscribeTask, to the group under label.
+func (g *StreamGroup) Resubscribe(label string, c *A2AClient, params protocol.TaskIDParams) error {
+	return g.add(label, params.ID, func(ctx context.Context) (<-chan protocol.TaskEvent, error) {
+		return c.ResubscribeTask(ctx, params)
+	})
+}
+
+// Events returns the channel of the events of the streams of the group. It
+// is closed once Close was called and every stream ended. After the group is
+// canceled, the remaining events may be dropped.
+func (g *StreamGroup) Events() <-chan GroupEvent {
+	return g.events
+}
+
+// Close declares that no more streams will be added, so that the channel of
+// Events is closed when the last stream ends.
+func (g *StreamGroup) Close() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.closed {
+		return
+	}
+	g.closed = true
+	if g.active == 0 {
+		g.finish()
+	}
+}
+
+// Cancel cancels every stream of the group.
+func (g *StreamGroup) Cancel() {
+	g.cancel(context.Canceled)
+}
+
+// Err returns the error of the first stream of the group that failed, if
+// any. Once the channel of Events is closed, the result is final.
+func (g *StreamGroup) Err() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.err
+}
+
+// add starts consuming the stream opened by open.
+func (g *StreamGroup) add(
+	label, taskID string,
+	open func(ctx context.Context) (<-chan protocol.TaskEvent, error),
+) error {
+	g.mu.Lock()
+	if g.closed {
+		g.mu.Unlock()
+		return ErrStreamGroupClosed
+	}
+	g.active++
+	g.mu.Unlock()
+	go func() {
+		err := g.consume(label, taskID, open)
+		g.done(label, taskID, err)
+	}()
+	return nil
+}
+
+// consume delivers the events of the stream opened by open until the final
+// event of the task.
+func (g *StreamGroup) consume(
+	label, taskID string,
+	open func(ctx context.Context) (<-chan protocol.TaskEvent, error),
+) error {
+	// Streams are not always closed after the final event: the group ends
+	// each stream by canceling its context.
+	ctx, cancel := context.WithCancel(g.ctx)
+	defer cancel()
+	events, err := open(ctx)
+	if err != nil {
+		return err
+	}
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				if err := context.Cause(g.ctx); err != nil {
+					return err
+				}
+				return errStreamEnded
+			}
+			if !g.deliver(GroupEvent{Label: label, TaskID: taskID, Event: event}) {
+				return context.Cause(g.ctx)
+			}
+			if event.IsFinal() {
+				return nil
+			}
+		case <-g.ctx.Done():
+			return context.Cause(g.ctx)
+		}
+	}
+}
+
+// deliver sends event to the consumer of the group. It reports false if the
+// group was canceled first.
+func (g *StreamGroup) deliver(event GroupEvent) bool {
+	select {
+	case g.events <- event:
+		return true
+	case <-g.ctx.Done():
+		return false
+	}
+}
+
+// done records the end of a stream and delivers its last GroupEvent. With
+// fail fast, the first failure cancels the group once it is delivered.
+func (g *StreamGroup) done(label, taskID string, err error) {
+	first := false
+	if err != nil {
+		err = fmt.Errorf("stream %q of task %s: %w", label, taskID, err)
+		g.mu.Lock()
+		if first = g.err == nil; first {
+			g.err = err
+		}
+		g.mu.Unlock()
+	}
+	g.deliver(GroupEvent{Label: label, TaskID: taskID, Err: err})
+	if first && g.failFast {
+		g.cancel(err)
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.active--
+	if g.active == 0 && g.closed {
+		g.finish()
+	}
+}
+
+// finish closes the channel of events and releases the context of the group.
+// The lock must be held.
+func (g *StreamGroup) finish() {
+	close(g.events)
+	g.cancel(nil)
+}
@@ -0,0 +1,158 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// newStreamingAgent starts an agent streaming the tasks it is sent: tasks
+// whose ID starts with "fail" are rejected, tasks whose ID starts with "hang"
+// never complete and the others complete at once. Streams stay open after the
+// final event, like those of the memory task manager.
+func newStreamingAgent(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request jsonrpc.Request
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		var params protocol.SendTaskParams
+		require.NoError(t, json.Unmarshal(request.Params, &params))
+		if strings.HasPrefix(params.ID, "fail") {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			_ = json.NewEncoder(w).Encode(jsonrpc.NewErrorResponse(request.ID, jsonrpc.ErrInternalError("boom")))
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		events := []protocol.TaskStatusUpdateEvent{
+			{ID: params.ID, Status: protocol.TaskStatus{State: protocol.TaskStateWorking}},
+		}
+		if !strings.HasPrefix(params.ID, "hang") {
+			events = append(events, protocol.TaskStatusUpdateEvent{
+				ID: params.ID, Status: protocol.TaskStatus{State: protocol.TaskStateCompleted}, Final: true,
+			})
+		}
+		for _, event := range events {
+			data, err := json.Marshal(event)
+			require.NoError(t, err)
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", protocol.EventTaskStatusUpdate, data)
+		}
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// collectGroup consumes the events of g until its channel is closed.
+func collectGroup(t *testing.T, g *StreamGroup) []GroupEvent {
+	t.Helper()
+	var events []GroupEvent
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case event, ok := <-g.Events():
+			if !ok {
+				return events
+			}
+			events = append(events, event)
+		case <-timeout:
+			t.Fatal("stream group did not end")
+		}
+	}
+}
+
+func TestStreamGroup(t *testing.T) {
+	agentA, agentB := newStreamingAgent(t), newStreamingAgent(t)
+	clientA, err := NewA2AClient(agentA.URL)
+	require.NoError(t, err)
+	clientB, err := NewA2AClient(agentB.URL)
+	require.NoError(t, err)
+	msg := protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")})
+
+	t.Run("merges streams", func(t *testing.T) {
+		g := NewStreamGroup(context.Background())
+		require.NoError(t, g.Stream("a", clientA, protocol.SendTaskParams{ID: "task-a", Message: msg}))
+		require.NoError(t, g.Stream("b", clientB, protocol.SendTaskParams{Message: msg}))
+		g.Close()
+		assert.ErrorIs(t, g.Stream("c", clientA, protocol.SendTaskParams{Message: msg}), ErrStreamGroupClosed)
+
+		events := collectGroup(t, g)
+		require.Len(t, events, 6, "two events and an end per stream")
+		byLabel := make(map[string][]GroupEvent)
+		for _, event := range events {
+			byLabel[event.Label] = append(byLabel[event.Label], event)
+		}
+		for _, label := range []string{"a", "b"} {
+			stream := byLabel[label]
+			require.Len(t, stream, 3)
+			assert.True(t, stream[1].Event.IsFinal())
+			assert.Nil(t, stream[2].Event, "the stream ends after the final event")
+			assert.NoError(t, stream[2].Err)
+			assert.NotEmpty(t, stream[0].TaskID)
+		}
+		assert.Equal(t, "task-a", byLabel["a"][0].TaskID)
+		assert.NoError(t, g.Err())
+	})
+
+	t.Run("stream errors", func(t *testing.T) {
+		g := NewStreamGroup(context.Background())
+		require.NoError(t, g.Stream("ok", clientA, protocol.SendTaskParams{ID: "task-ok", Message: msg}))
+		require.NoError(t, g.Stream("bad", clientB, protocol.SendTaskParams{ID: "fail-1", Message: msg}))
+		g.Close()
+
+		var failed GroupEvent
+		var completed bool
+		for _, event := range collectGroup(t, g) {
+			if event.Event == nil && event.Err != nil {
+				failed = event
+			}
+			if event.Event != nil && event.Event.IsFinal() {
+				completed = true
+			}
+		}
+		assert.Equal(t, "bad", failed.Label)
+		assert.True(t, completed, "other streams are not affected")
+		var rpcErr *jsonrpc.Error
+		require.ErrorAs(t, g.Err(), &rpcErr)
+		assert.Equal(t, jsonrpc.CodeInternalError, rpcErr.Code)
+	})
+
+	t.Run("fail fast", func(t *testing.T) {
+		g := NewStreamGroup(context.Background(), WithStreamGroupFailFast(true))
+		require.NoError(t, g.Stream("hang", clientA, protocol.SendTaskParams{ID: "hang-1", Message: msg}))
+		require.NoError(t, g.Stream("bad", clientB, protocol.SendTaskParams{ID: "fail-1", Message: msg}))
+		g.Close()
+		collectGroup(t, g)
+		assert.Contains(t, g.Err().Error(), `stream "bad"`)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		g := NewStreamGroup(context.Background())
+		require.NoError(t, g.Stream("hang", clientA, protocol.SendTaskParams{ID: "hang-2", Message: msg}))
+		g.Close()
+		event := <-g.Events()
+		assert.Equal(t, protocol.TaskStateWorking, event.Event.(protocol.TaskStatusUpdateEvent).Status.State)
+		g.Cancel()
+		collectGroup(t, g)
+		assert.ErrorIs(t, g.Err(), context.Canceled)
+	})
+}
@@ -0,0 +1,81 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"trpc.group/trpc-go/trpc-a2a-go/auth"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// ErrArtifactIntegrity is returned, wrapped, for artifact chunks failing
+// their integrity check.
+var ErrArtifactIntegrity = errors.New("artifact integrity check failed")
+
+// ArtifactVerifier checks the integrity records attached to the artifact
+// chunks streamed by agents signing them, e.g. with taskmanager.SignArtifacts,
+// detecting corrupted, reordered and missing chunks:
+//
+//	verifier := client.NewArtifactVerifier(auth.StaticProvenanceKeys{"agent-1": publicKey})
+//	for event := range events {
+//		if e, ok := event.(protocol.TaskArtifactUpdateEvent); ok {
+//			if err := verifier.Verify(ctx, e); err != nil {
+//				return err
+//			}
+//		}
+//	}
+//
+// It follows the chunks of every artifact it is given, so give it the events
+// of a stream in order. It is safe for concurrent use.
+type ArtifactVerifier struct {
+	keys auth.ProvenanceKeyResolver
+
+	mu     sync.Mutex
+	chunks map[string]map[int]auth.ArtifactIntegrity // Last chunk by task ID and artifact index.
+}
+
+// NewArtifactVerifier creates a verifier requiring the chunks to be signed by
+// keys, or only checking their digests if keys is nil.
+func NewArtifactVerifier(keys auth.ProvenanceKeyResolver) *ArtifactVerifier {
+	return &ArtifactVerifier{keys: keys, chunks: make(map[string]map[int]auth.ArtifactIntegrity)}
+}
+
+// Verify checks the artifact chunk of event against its integrity record and
+// the chunks of the artifact received before. Chunks without Append start a
+// new artifact.
+func (v *ArtifactVerifier) Verify(ctx context.Context, event protocol.TaskArtifactUpdateEvent) error {
+	chunk := event.Artifact
+	integrity, err := auth.ArtifactIntegrityFromMetadata(chunk.Metadata)
+	if err != nil {
+		return fmt.Errorf("%w: artifact %d of task %s: %v", ErrArtifactIntegrity, chunk.Index, event.ID, err)
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	var prev *auth.ArtifactIntegrity
+	if last, ok := v.chunks[event.ID][chunk.Index]; ok && chunk.Append != nil && *chunk.Append {
+		prev = &last
+	}
+	if err := auth.VerifyArtifactIntegrity(ctx, v.keys, event.ID, chunk, integrity, prev); err != nil {
+		return fmt.Errorf("%w: artifact %d of task %s: %v", ErrArtifactIntegrity, chunk.Index, event.ID, err)
+	}
+	if chunk.LastChunk != nil && *chunk.LastChunk {
+		delete(v.chunks[event.ID], chunk.Index)
+		if len(v.chunks[event.ID]) == 0 {
+			delete(v.chunks, event.ID)
+		}
+		return nil
+	}
+	if v.chunks[event.ID] == nil {
+		v.chunks[event.ID] = make(map[int]auth.ArtifactIntegrity)
+	}
+	v.chunks[event.ID][chunk.Index] = *integrity
+	return nil
+}
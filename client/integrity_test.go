@@ -0,0 +1,56 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"context"
+	"crypto/ed25519"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/auth"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+func TestArtifactVerifier(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	signer := auth.NewArtifactSigner("k1", privateKey)
+	var prev *auth.ArtifactIntegrity
+	signed := func(text string, appended, last bool) protocol.TaskArtifactUpdateEvent {
+		chunk := protocol.Artifact{
+			Parts:     []protocol.Part{protocol.NewTextPart(text)},
+			Append:    &appended,
+			LastChunk: &last,
+		}
+		if !appended {
+			prev = nil
+		}
+		integrity, err := signer.Integrity("t1", chunk, prev)
+		require.NoError(t, err)
+		prev = &integrity
+		chunk.Metadata = map[string]interface{}{auth.MetadataKeyIntegrity: integrity}
+		return protocol.TaskArtifactUpdateEvent{ID: "t1", Artifact: chunk}
+	}
+	ctx := context.Background()
+
+	verifier := NewArtifactVerifier(auth.StaticProvenanceKeys{"k1": publicKey})
+	require.NoError(t, verifier.Verify(ctx, signed("a", false, false)))
+	second := signed("b", true, false)
+	third := signed("c", true, true)
+	err = verifier.Verify(ctx, third)
+	assert.ErrorIs(t, err, ErrArtifactIntegrity, "missing chunks are detected")
+	require.NoError(t, verifier.Verify(ctx, second))
+	require.NoError(t, verifier.Verify(ctx, third))
+	assert.Empty(t, verifier.chunks, "finished artifacts are forgotten")
+
+	corrupted := signed("d", false, true)
+	corrupted.Artifact.Parts = []protocol.Part{protocol.NewTextPart("e")}
+	assert.ErrorIs(t, verifier.Verify(ctx, corrupted), ErrArtifactIntegrity)
+}
@@ -0,0 +1,69 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+)
+
+// Call calls a JSON-RPC method of the agent, such as a custom method
+// advertised in the capabilities of its card, with params, and decodes the
+// result into result unless it is nil. A JSON-RPC error returned by the agent
+// can be inspected with errors.As.
+func (c *A2AClient) Call(
+	ctx context.Context,
+	method string,
+	params, result interface{},
+	opts ...CallOption,
+) error {
+	ctx = withCallOptions(ctx, opts)
+	// Without a task ID the request is not bound to a replica.
+	request := jsonrpc.NewRequest(method, "")
+	if params != nil {
+		paramsBytes, err := c.codec.Marshal(params)
+		if err != nil {
+			return fmt.Errorf("a2aClient.Call: failed to marshal params: %w", err)
+		}
+		request.Params = paramsBytes
+	}
+	fullResponse, err := c.doRequest(ctx, request)
+	if err != nil {
+		return fmt.Errorf("a2aClient.Call: %w", err)
+	}
+	if fullResponse.Error != nil {
+		return fmt.Errorf("a2aClient.Call: %w", fullResponse.Error)
+	}
+	if result == nil {
+		return nil
+	}
+	if err := c.codec.Unmarshal(fullResponse.Result, result); err != nil {
+		return fmt.Errorf("a2aClient.Call: failed to unmarshal rpc result: %w", err)
+	}
+	return nil
+}
+
+// Notify sends a JSON-RPC notification of method with params to the agent.
+// The agent does not respond to notifications, so a nil error only means it
+// accepted the notification.
+func (c *A2AClient) Notify(ctx context.Context, method string, params interface{}, opts ...CallOption) error {
+	ctx = withCallOptions(ctx, opts)
+	request := jsonrpc.NewNotification(method)
+	if params != nil {
+		paramsBytes, err := c.codec.Marshal(params)
+		if err != nil {
+			return fmt.Errorf("a2aClient.Notify: failed to marshal params: %w", err)
+		}
+		request.Params = paramsBytes
+	}
+	if _, err := c.post(ctx, request, ""); err != nil {
+		return fmt.Errorf("a2aClient.Notify: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// SendTaskNotify sends a message using the tasks/send method as a JSON-RPC
+// notification: the request has no ID and the agent acknowledges it without a
+// response, before processing the task. It is meant for callers relying on push
+// notifications, configured beforehand with SetPushNotification or through the
+// task metadata, to learn the outcome of the task. A nil error only means the
+// agent accepted the notification; invalid params and processing errors are
+// not reported.
+func (c *A2AClient) SendTaskNotify(
+	ctx context.Context,
+	params protocol.SendTaskParams,
+	opts ...CallOption,
+) error {
+	ctx = withCallOptions(ctx, opts)
+	params, err := c.checkMessageParts(params)
+	if err != nil {
+		return fmt.Errorf("a2aClient.SendTaskNotify: %w", err)
+	}
+	params = c.propagateDeadline(ctx, params)
+	if params, err = c.signProvenance(ctx, params); err != nil {
+		return fmt.Errorf("a2aClient.SendTaskNotify: %w", err)
+	}
+	request := jsonrpc.NewNotification(protocol.MethodTasksSend)
+	paramsBytes, err := c.codec.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("a2aClient.SendTaskNotify: failed to marshal params: %w", err)
+	}
+	request.Params = paramsBytes
+	// The task ID still routes the notification to the replica serving the task.
+	if _, err := c.post(ctx, request, params.ID); err != nil {
+		return fmt.Errorf("a2aClient.SendTaskNotify: %w", err)
+	}
+	return nil
+}
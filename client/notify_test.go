@@ -0,0 +1,54 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+func TestA2AClient_SendTaskNotify(t *testing.T) {
+	status := http.StatusAccepted
+	agent := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.True(t, jsonrpc.IsNotification(body), "notifications must not carry an id")
+		var req jsonrpc.Request
+		if !assert.NoError(t, json.Unmarshal(body, &req)) {
+			return
+		}
+		assert.Equal(t, protocol.MethodTasksSend, req.Method)
+		var params protocol.SendTaskParams
+		assert.NoError(t, json.Unmarshal(req.Params, &params))
+		assert.Equal(t, "task-1", params.ID)
+		w.WriteHeader(status)
+	}))
+	defer agent.Close()
+
+	c, err := NewA2AClient(agent.URL)
+	require.NoError(t, err)
+	params := protocol.SendTaskParams{
+		ID:      "task-1",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")}),
+	}
+	require.NoError(t, c.SendTaskNotify(context.Background(), params))
+
+	status = http.StatusUnauthorized
+	assert.Error(t, c.SendTaskNotify(context.Background(), params))
+}
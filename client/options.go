@@ -13,6 +13,9 @@ import (
 
 	"golang.org/x/oauth2"
 	"trpc.group/trpc-go/trpc-a2a-go/auth"
+	"trpc.group/trpc-go/trpc-a2a-go/codec"
+	"trpc.group/trpc-go/trpc-a2a-go/metadata"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
 )
 
 // Option is a functional option type for configuring the A2AClient.
@@ -36,6 +39,7 @@ func WithHTTPClient(client *http.Client) Option {
 
 // WithTimeout sets the timeout for the underlying http.Client.
 // If a custom client was provided via WithHTTPClient, this modifies its timeout.
+// Streams are bounded by it until their response headers are received only.
 func WithTimeout(timeout time.Duration) Option {
 	return func(c *A2AClient) {
 		if timeout > 0 && c.httpClient != nil {
@@ -44,6 +48,17 @@ func WithTimeout(timeout time.Duration) Option {
 	}
 }
 
+// WithTimeouts sets the timeouts of the client given by cfg: the request
+// timeout like WithTimeout, so apply it after WithHTTPClient, the idle timeout
+// of streams, and the dial, TLS handshake and response header timeouts on a
+// clone of the client's transport, which must then be an *http.Transport.
+func WithTimeouts(cfg TimeoutConfig) Option {
+	return func(c *A2AClient) {
+		WithTimeout(cfg.Request)(c)
+		c.timeouts = cfg
+	}
+}
+
 // WithUserAgent sets a custom User-Agent header for requests.
 func WithUserAgent(userAgent string) Option {
 	return func(c *A2AClient) {
@@ -51,6 +66,34 @@ func WithUserAgent(userAgent string) Option {
 	}
 }
 
+// WithMetadata sets metadata sent with every request, in addition to the
+// outgoing metadata of the call context (see metadata.NewOutgoingContext).
+func WithMetadata(md metadata.MD) Option {
+	return func(c *A2AClient) {
+		c.metadata = metadata.Join(c.metadata, md)
+	}
+}
+
+// WithHeader sets the HTTP header key to value on every request, such as a
+// tenant ID or a feature flag, without replacing the HTTP client. Calls can
+// override it with WithCallHeader.
+func WithHeader(key, value string) Option {
+	return func(c *A2AClient) {
+		if c.headers == nil {
+			c.headers = make(http.Header)
+		}
+		c.headers.Set(key, value)
+	}
+}
+
+// WithRetry sets the retry policy of the requests of unary calls. By default
+// requests are not retried. Calls can override it with WithCallRetry.
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *A2AClient) {
+		c.retry = policy
+	}
+}
+
 // Authentication options
 
 // WithJWTAuth configures the client to use JWT authentication.
@@ -104,8 +147,269 @@ func WithAuthProvider(provider auth.ClientProvider) Option {
 	}
 }
 
+// WithRequestSigning signs the method, path, metadata and body of every
+// request with secret, registered as keyID in the auth.HMACVerifier of the
+// server. Apply it after WithHTTPClient and WithTransport, which replace the
+// signing transport.
+func WithRequestSigning(keyID string, secret []byte) Option {
+	return func(c *A2AClient) {
+		c.httpClient = auth.NewHMACSigner(keyID, secret).ConfigureClient(c.httpClient)
+	}
+}
+
+// WithCodec sets the JSON codec used to encode requests and to decode
+// responses and SSE events. Defaults to codec.Default (encoding/json).
+func WithCodec(c codec.Codec) Option {
+	return func(cl *A2AClient) {
+		if c != nil {
+			cl.codec = c
+		}
+	}
+}
+
+// WithHttpReqHandler sets a custom handler used to execute HTTP requests.
 func WithHttpReqHandler(handler HttpReqHandler) Option {
 	return func(c *A2AClient) {
 		c.httpReqHandler = handler
 	}
 }
+
+// WithCompression enables or disables requesting gzip/deflate compressed
+// responses, which are decompressed transparently. Enabled by default.
+func WithCompression(enabled bool) Option {
+	return func(c *A2AClient) {
+		c.compression = enabled
+	}
+}
+
+// WithPartFailurePolicy enables validating the parts of outgoing messages in
+// SendTasks and StreamTask before they are sent. With protocol.PartFailureRejectAll
+// a message with any invalid part is not sent; with protocol.PartFailureAcceptValid
+// the invalid parts are dropped and reported as protocol.Warning values under
+// the "warnings" key of the params metadata. A message without any valid part
+// is never sent. Local validation is disabled by default.
+func WithPartFailurePolicy(policy protocol.PartFailurePolicy) Option {
+	return func(c *A2AClient) {
+		c.partFailurePolicy = &policy
+	}
+}
+
+// WithTransport sets the transport of the underlying http.Client. Passing the
+// same transport to many clients lets them share one connection pool.
+// If a custom client was provided via WithHTTPClient, this modifies its transport.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *A2AClient) {
+		if transport != nil && c.httpClient != nil {
+			c.httpClient.Transport = transport
+		}
+	}
+}
+
+// WithTransportConfig gives the client a dedicated transport created by
+// NewTransport with cfg, tuning its connection pooling and keep-alives.
+// Applications creating many clients should prefer WithTransport with a single
+// shared transport, or WithSharedTransport.
+func WithTransportConfig(cfg TransportConfig) Option {
+	return WithTransport(NewTransport(cfg))
+}
+
+// WithSharedTransport makes the client use SharedTransport, so that all the
+// clients created with this option, whichever agents they target, draw from
+// one bounded connection pool instead of each opening their own sockets.
+func WithSharedTransport() Option {
+	return func(c *A2AClient) {
+		WithTransport(SharedTransport())(c)
+	}
+}
+
+// WithReplicas adds base URLs of further replicas of the agent. Requests are
+// spread over the agent URL given to NewA2AClient and these replicas according
+// to the load balancing strategy, and each task sticks to the replica that
+// first served it, so that streams and resubscriptions reach the replica
+// holding the task.
+func WithReplicas(urls ...string) Option {
+	return func(c *A2AClient) {
+		c.replicaURLs = append(c.replicaURLs, urls...)
+	}
+}
+
+// WithLoadBalancing sets the strategy used to choose between agent replicas.
+// The default is RoundRobin.
+func WithLoadBalancing(strategy LoadBalancingStrategy) Option {
+	return func(c *A2AClient) {
+		c.lbStrategy = strategy
+	}
+}
+
+// WithHealthCheck configures health checking of agent replicas. With a positive
+// interval the replicas are probed in the background until Close is called.
+func WithHealthCheck(cfg HealthCheckConfig) Option {
+	return func(c *A2AClient) {
+		c.healthCheck = cfg
+	}
+}
+
+// WithPinnedCertificates makes the client accept only servers whose leaf
+// certificate has one of the given SHA-256 fingerprints, in hex with optional
+// colons (see CertificateFingerprint). The pins replace CA and host name
+// verification, so self-signed agent certificates can be trusted; pin both the
+// current and the next certificate to rotate without downtime.
+// The client's transport must be an *http.Transport; it is cloned, not modified.
+func WithPinnedCertificates(fingerprints ...string) Option {
+	return func(c *A2AClient) {
+		if c.pinnedCertificates == nil {
+			c.pinnedCertificates = []string{} // Pinning without pins is an error.
+		}
+		c.pinnedCertificates = append(c.pinnedCertificates, fingerprints...)
+	}
+}
+
+// WithResolver makes the client balance over the agent replicas reported by r,
+// updating the targets as they change without recreating the client. The agent
+// URL given to NewA2AClient and any WithReplicas URLs are used until r reports
+// its first set. Close stops watching r.
+func WithResolver(r Resolver) Option {
+	return func(c *A2AClient) {
+		c.resolver = r
+	}
+}
+
+// WithProvenanceSigner makes SendTasks and StreamTask append a hop signed by
+// signer to the provenance chain of the request context and send the chain in
+// the params metadata. Inside a processor the context carries the chain
+// verified by the server, so tasks delegated to other agents extend the chain
+// back to the original caller.
+func WithProvenanceSigner(signer *auth.ProvenanceSigner) Option {
+	return func(c *A2AClient) {
+		c.provenanceSigner = signer
+	}
+}
+
+// WithDeadlinePropagation enables or disables sending the time left until the
+// context deadline of SendTasks and StreamTask as the deadline budget of the
+// task (see protocol.MetadataKeyDeadlineBudget), so that the agent, and the
+// agents it delegates to, stop when the caller gives up. Enabled by default.
+func WithDeadlinePropagation(enabled bool) Option {
+	return func(c *A2AClient) {
+		c.deadlinePropagation = enabled
+	}
+}
+
+// WithWarningHandler sets the handler of the warnings the agent returns with
+// tasks and stream events, e.g. about deprecated features or fallbacks. By
+// default they are logged; a nil handler ignores them.
+func WithWarningHandler(handler WarningHandler) Option {
+	return func(c *A2AClient) {
+		c.warningHandler = handler
+	}
+}
+
+// WithDeprecationHandler sets the handler called once for each deprecated
+// method or params field the agent reports the client using. By default the
+// deprecations are logged; a nil handler ignores them.
+func WithDeprecationHandler(handler DeprecationHandler) Option {
+	return func(c *A2AClient) {
+		c.deprecationHandler = handler
+	}
+}
+
+// WithEventAcks makes the client acknowledge, every interval and when the
+// stream ends, the events of its streams received from the returned channel,
+// for agents supporting the tasks/ackEvents extension method.
+// The channels of such streams are unbuffered.
+func WithEventAcks(interval time.Duration) Option {
+	return func(c *A2AClient) {
+		c.ackInterval = interval
+	}
+}
+
+// WithEventDeduplication makes the client skip the events of a task it
+// already delivered from an earlier stream, e.g. when they are replayed after
+// resubscribing, based on the event IDs of the agent. With it, every numbered
+// event reaches the consumer exactly once, provided the agent keeps numbering
+// the events of a task across restarts. The client remembers the last event
+// of up to maxTasks tasks, 4096 if maxTasks is not positive, forgetting the
+// tasks first seen earliest. Deduplication is disabled by default.
+func WithEventDeduplication(maxTasks int) Option {
+	return func(c *A2AClient) {
+		c.dedup = newEventDeduplicator(maxTasks)
+	}
+}
+
+// WithTaskCache serves GetTasks from a read-through cache keeping the tasks
+// read from the agent for cfg.TTL, so that callers polling many tasks do not
+// repeat identical tasks/get requests. Calls with different outgoing metadata,
+// which may select different tenants, are cached apart. The cached results of
+// a task are dropped when the client receives an event of the task, or sends
+// or cancels it, and by InvalidateTask. The cache is disabled by default.
+func WithTaskCache(cfg TaskCacheConfig) Option {
+	return func(c *A2AClient) {
+		if cfg.TTL > 0 {
+			c.taskCache = newTaskCache(cfg)
+		}
+	}
+}
+
+// WithRequestCoalescing sets whether concurrent GetTasks calls for the same task
+// and history length share a single tasks/get request, e.g. when many
+// goroutines watch the same task. Each caller still gives up on its own
+// context only. Disabled by default.
+func WithRequestCoalescing(enabled bool) Option {
+	return func(c *A2AClient) {
+		if enabled {
+			c.coalescer = newTaskCoalescer()
+		} else {
+			c.coalescer = nil
+		}
+	}
+}
+
+// WithIDGenerator sets the generator of the IDs of tasks sent without one.
+// Defaults to protocol.DefaultIDGenerator, which generates UUIDv7 IDs.
+func WithIDGenerator(generator protocol.IDGenerator) Option {
+	return func(c *A2AClient) {
+		if generator != nil {
+			c.idGenerator = generator
+		}
+	}
+}
+
+// WithLongPollingFallback makes StreamTask and ResubscribeTask fall back to
+// long polling with the tasks/pollEvents extension method when the agent does
+// not answer with an event stream nor a JSON-RPC error, e.g. behind proxies
+// blocking or buffering SSE. Each poll waits up to wait for events, 5 seconds
+// if wait is not positive. The task of StreamTask is sent with tasks/send
+// while polling. Polling starts with the current status of the task: events
+// it missed before are not replayed. A client runs up to 64 polling sessions
+// at the same time, for an hour each at most.
+func WithLongPollingFallback(wait time.Duration) Option {
+	return func(c *A2AClient) {
+		if wait <= 0 {
+			wait = defaultPollWait
+		}
+		c.pollWait = wait
+		c.pollSessions = make(chan struct{}, maxPollSessions)
+	}
+}
+
+// WithNDJSONStreams makes StreamTask and ResubscribeTask ask for streams of
+// newline-delimited JSON (protocol.ContentTypeNDJSON) rather than SSE, for
+// environments where SSE is not supported. Agents that do not support NDJSON
+// streams answer with SSE, which is read as usual. Disabled by default.
+func WithNDJSONStreams(enabled bool) Option {
+	return func(c *A2AClient) {
+		c.ndjsonStreams = enabled
+	}
+}
+
+// WithArtifactPath sets the path of the artifact download endpoint of the
+// agent used by DownloadArtifact, relative to the agent URL, for agents
+// serving it elsewhere than at protocol.DefaultArtifactPath with
+// server.WithArtifactDownloads.
+func WithArtifactPath(path string) Option {
+	return func(c *A2AClient) {
+		if path != "" {
+			c.artifactPath = path
+		}
+	}
+}
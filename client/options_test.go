@@ -12,6 +12,8 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"trpc.group/trpc-go/trpc-a2a-go/codec"
 )
 
 func TestWithHTTPClient(t *testing.T) {
@@ -84,3 +86,21 @@ func TestWithUserAgent(t *testing.T) {
 	WithUserAgent("")(client)
 	assert.Equal(t, "", client.userAgent)
 }
+
+// namedCodec is a codec.Codec distinguishable from the default one.
+type namedCodec struct {
+	codec.Codec
+}
+
+func TestWithCodec(t *testing.T) {
+	client, err := NewA2AClient("http://localhost:8080")
+	assert.NoError(t, err)
+	assert.Equal(t, codec.Default, client.codec, "Default codec should be used")
+
+	custom := namedCodec{Codec: codec.Default}
+	WithCodec(custom)(client)
+	assert.Equal(t, codec.Codec(custom), client.codec)
+
+	WithCodec(nil)(client)
+	assert.Equal(t, codec.Codec(custom), client.codec, "Nil codec should not change the existing codec")
+}
@@ -0,0 +1,56 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// checkMessageParts validates the parts of the message in params according to
+// the client's part failure policy. Under protocol.PartFailureAcceptValid the
+// invalid parts are dropped and reported as warnings in the params metadata.
+// The caller's params are not modified.
+func (c *A2AClient) checkMessageParts(params protocol.SendTaskParams) (protocol.SendTaskParams, error) {
+	if c.partFailurePolicy == nil {
+		return params, nil
+	}
+	var (
+		kept     = make([]protocol.Part, 0, len(params.Message.Parts))
+		warnings []protocol.Warning
+		errs     []error
+	)
+	for i, part := range params.Message.Parts {
+		if err := protocol.ValidatePart(part); err != nil {
+			pointer := "/message/parts/" + strconv.Itoa(i)
+			warnings = append(warnings, protocol.Warning{
+				Code:    protocol.WarningCodePartDropped,
+				Message: err.Error(),
+				Pointer: pointer,
+			})
+			errs = append(errs, fmt.Errorf("%s: %w", pointer, err))
+			continue
+		}
+		kept = append(kept, part)
+	}
+	if len(errs) == 0 {
+		return params, nil
+	}
+	if *c.partFailurePolicy != protocol.PartFailureAcceptValid || len(kept) == 0 {
+		return params, fmt.Errorf("invalid message parts: %w", errors.Join(errs...))
+	}
+	metadata := make(map[string]interface{}, len(params.Metadata)+1)
+	for k, v := range params.Metadata {
+		metadata[k] = v
+	}
+	params.Message.Parts = kept
+	params.Metadata = protocol.AppendWarnings(metadata, warnings...)
+	return params, nil
+}
@@ -0,0 +1,65 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+func TestCheckMessageParts(t *testing.T) {
+	params := protocol.SendTaskParams{
+		ID: "t1",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{
+			protocol.NewTextPart("hi"),
+			protocol.FilePart{Type: protocol.PartTypeFile},
+		}),
+		Metadata: map[string]interface{}{"k": "v"},
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		got, err := (&A2AClient{}).checkMessageParts(params)
+		require.NoError(t, err)
+		assert.Equal(t, params, got)
+	})
+
+	t.Run("reject all", func(t *testing.T) {
+		c := &A2AClient{}
+		WithPartFailurePolicy(protocol.PartFailureRejectAll)(c)
+		_, err := c.checkMessageParts(params)
+		assert.ErrorContains(t, err, "/message/parts/1")
+	})
+
+	t.Run("accept valid", func(t *testing.T) {
+		c := &A2AClient{}
+		WithPartFailurePolicy(protocol.PartFailureAcceptValid)(c)
+		got, err := c.checkMessageParts(params)
+		require.NoError(t, err)
+		assert.Equal(t, []protocol.Part{protocol.NewTextPart("hi")}, got.Message.Parts)
+		assert.Equal(t, "v", got.Metadata["k"])
+		warnings := protocol.WarningsFromMetadata(got.Metadata)
+		require.Len(t, warnings, 1)
+		assert.Equal(t, protocol.WarningCodePartDropped, warnings[0].Code)
+		assert.Equal(t, "/message/parts/1", warnings[0].Pointer)
+		// The caller's params are left untouched.
+		assert.Len(t, params.Message.Parts, 2)
+		assert.NotContains(t, params.Metadata, protocol.MetadataKeyWarnings)
+	})
+
+	t.Run("accept valid without valid parts", func(t *testing.T) {
+		c := &A2AClient{}
+		WithPartFailurePolicy(protocol.PartFailureAcceptValid)(c)
+		invalid := params
+		invalid.Message.Parts = params.Message.Parts[1:]
+		_, err := c.checkMessageParts(invalid)
+		assert.Error(t, err)
+	})
+}
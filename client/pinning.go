@@ -0,0 +1,107 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// CertificateFingerprint returns the SHA-256 fingerprint of a certificate as
+// lowercase hex, the form accepted by WithPinnedCertificates.
+func CertificateFingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// parseFingerprint decodes a SHA-256 fingerprint given as hex, optionally
+// separated by colons as printed by openssl.
+func parseFingerprint(fingerprint string) ([]byte, error) {
+	pin, err := hex.DecodeString(strings.ReplaceAll(strings.TrimSpace(fingerprint), ":", ""))
+	if err != nil || len(pin) != sha256.Size {
+		return nil, fmt.Errorf("invalid SHA-256 certificate fingerprint %q", fingerprint)
+	}
+	return pin, nil
+}
+
+// pinnedVerifier verifies that the leaf certificate of a TLS connection
+// matches one of a set of pinned fingerprints.
+type pinnedVerifier struct {
+	pins [][]byte
+}
+
+// newPinnedVerifier parses fingerprints into a verifier.
+func newPinnedVerifier(fingerprints []string) (*pinnedVerifier, error) {
+	if len(fingerprints) == 0 {
+		return nil, errors.New("at least one certificate fingerprint must be pinned")
+	}
+	v := &pinnedVerifier{}
+	for _, fingerprint := range fingerprints {
+		pin, err := parseFingerprint(fingerprint)
+		if err != nil {
+			return nil, err
+		}
+		v.pins = append(v.pins, pin)
+	}
+	return v, nil
+}
+
+// verifyConnection implements tls.Config.VerifyConnection.
+func (v *pinnedVerifier) verifyConnection(cs tls.ConnectionState) error {
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("server presented no certificate")
+	}
+	sum := sha256.Sum256(cs.PeerCertificates[0].Raw)
+	for _, pin := range v.pins {
+		if subtle.ConstantTimeCompare(sum[:], pin) == 1 {
+			return nil
+		}
+	}
+	return fmt.Errorf("server certificate fingerprint %x matches no pinned certificate", sum)
+}
+
+// applyPinning makes the client's transport verify server certificates
+// against the pinned fingerprints. The transport is cloned so that transports
+// shared with other clients are left untouched.
+func (c *A2AClient) applyPinning() error {
+	if c.pinnedCertificates == nil {
+		return nil
+	}
+	verifier, err := newPinnedVerifier(c.pinnedCertificates)
+	if err != nil {
+		return err
+	}
+	var transport *http.Transport
+	switch t := c.httpClient.Transport.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		transport = t.Clone()
+	default:
+		return fmt.Errorf("certificate pinning requires an *http.Transport, got %T", t)
+	}
+	tlsConfig := transport.TLSClientConfig
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{}
+	}
+	// The pins replace the CA chain and host name checks, so that self-signed
+	// certificates can be used; a pinned leaf identifies the server exactly.
+	tlsConfig.InsecureSkipVerify = true
+	tlsConfig.VerifyConnection = verifier.verifyConnection
+	transport.TLSClientConfig = tlsConfig
+	httpClient := *c.httpClient
+	httpClient.Transport = transport
+	c.httpClient = &httpClient
+	return nil
+}
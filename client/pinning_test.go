@@ -0,0 +1,84 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+func TestWithPinnedCertificates(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      "t1",
+			"result":  protocol.Task{ID: "t1"},
+		})
+	}))
+	defer srv.Close()
+	fingerprint := CertificateFingerprint(srv.Certificate())
+	// openssl style: uppercase pairs separated by colons.
+	var pairs []string
+	for i := 0; i < len(fingerprint); i += 2 {
+		pairs = append(pairs, strings.ToUpper(fingerprint[i:i+2]))
+	}
+	colonFingerprint := strings.Join(pairs, ":")
+	otherFingerprint := strings.Repeat("ab", 32)
+
+	get := func(t *testing.T, opts ...Option) error {
+		client, err := NewA2AClient(srv.URL, opts...)
+		require.NoError(t, err)
+		_, err = client.GetTasks(context.Background(), protocol.TaskQueryParams{ID: "t1"})
+		return err
+	}
+
+	t.Run("untrusted without pins", func(t *testing.T) {
+		assert.Error(t, get(t))
+	})
+	t.Run("pinned", func(t *testing.T) {
+		assert.NoError(t, get(t, WithPinnedCertificates(colonFingerprint)))
+	})
+	t.Run("rotation", func(t *testing.T) {
+		assert.NoError(t, get(t, WithPinnedCertificates(otherFingerprint, fingerprint)))
+	})
+	t.Run("mismatch", func(t *testing.T) {
+		err := get(t, WithPinnedCertificates(otherFingerprint))
+		assert.ErrorContains(t, err, "matches no pinned certificate")
+	})
+	t.Run("shared transport is not modified", func(t *testing.T) {
+		assert.NoError(t, get(t, WithSharedTransport(), WithPinnedCertificates(fingerprint)))
+		if cfg := SharedTransport().TLSClientConfig; cfg != nil {
+			assert.False(t, cfg.InsecureSkipVerify)
+			assert.Nil(t, cfg.VerifyConnection)
+		}
+	})
+
+	t.Run("invalid pins", func(t *testing.T) {
+		_, err := NewA2AClient(srv.URL, WithPinnedCertificates("not-hex"))
+		assert.Error(t, err)
+		_, err = NewA2AClient(srv.URL, WithPinnedCertificates())
+		assert.Error(t, err)
+		_, err = NewA2AClient(srv.URL, WithTransport(roundTripperFunc(nil)), WithPinnedCertificates(fingerprint))
+		assert.Error(t, err)
+	})
+}
+
+// roundTripperFunc adapts a function to http.RoundTripper.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
@@ -0,0 +1,41 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// errMissingPlan is returned when a dry run response carries no task plan.
+var errMissingPlan = errors.New("response holds no task plan")
+
+// PlanTask sends params as a dry run of tasks/send: the agent checks the request
+// as it would for SendTasks, including its token budget and routing, and
+// reports the resulting plan without running the task. Rejections are returned
+// as the errors SendTasks would return, so expensive requests can be checked
+// before they are sent.
+func (c *A2AClient) PlanTask(
+	ctx context.Context,
+	params protocol.SendTaskParams,
+	opts ...CallOption,
+) (*protocol.TaskPlan, error) {
+	ctx = withCallOptions(ctx, opts)
+	params.DryRun = true
+	task, err := c.SendTasks(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	plan, ok := protocol.TaskPlanFromMetadata(task.Metadata)
+	if !ok {
+		return nil, fmt.Errorf("a2aClient.PlanTask: %w", errMissingPlan)
+	}
+	return plan, nil
+}
@@ -0,0 +1,54 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+func TestA2AClient_PlanTask(t *testing.T) {
+	agent := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req jsonrpc.Request
+		if !assert.NoError(t, json.NewDecoder(r.Body).Decode(&req)) {
+			return
+		}
+		var params protocol.SendTaskParams
+		if !assert.NoError(t, json.Unmarshal(req.Params, &params)) {
+			return
+		}
+		assert.Equal(t, protocol.MethodTasksSend, req.Method)
+		assert.True(t, params.DryRun)
+		task := protocol.NewTask(params.ID, nil)
+		task.Metadata = map[string]interface{}{
+			protocol.MetadataKeyTaskPlan: protocol.TaskPlan{Skill: "echo", InputTokens: 4},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		assert.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0", "id": req.ID, "result": task,
+		}))
+	}))
+	defer agent.Close()
+
+	c, err := NewA2AClient(agent.URL)
+	require.NoError(t, err)
+	plan, err := c.PlanTask(context.Background(), protocol.SendTaskParams{
+		ID:      "task-1",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")}),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &protocol.TaskPlan{Skill: "echo", InputTokens: 4}, plan)
+}
@@ -0,0 +1,198 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+const (
+	// defaultPollWait is the wait of long polls unless configured.
+	defaultPollWait = 5 * time.Second
+	// pollRetryInterval is the interval of polls for a task being sent.
+	pollRetryInterval = 50 * time.Millisecond
+	// maxPollSessions bounds the long polling sessions of a client run at
+	// the same time.
+	maxPollSessions = 64
+	// maxPollDuration bounds the duration of a long polling session.
+	maxPollDuration = time.Hour
+	// maxErrorBodyBytes bounds the body read from unexpected responses.
+	maxErrorBodyBytes = 64 << 10
+	// errCodeTaskNotFound is the code of the JSON-RPC error of agents for
+	// unknown tasks, as taskmanager.ErrCodeTaskNotFound.
+	errCodeTaskNotFound = -32001
+)
+
+// errTooManyPolls is returned when a stream cannot fall back to long polling
+// because the client already runs maxPollSessions.
+var errTooManyPolls = fmt.Errorf("too many long polling sessions, at most %d", maxPollSessions)
+
+// errSSEHandshake is wrapped by the errors of stream requests not answered
+// with an event stream nor a JSON-RPC error, which long polling may get around.
+var errSSEHandshake = errors.New("SSE handshake failed")
+
+// PollEvents returns the events of a task since the cursor of params using
+// the tasks/pollEvents extension method, waiting up to params.WaitMs for some.
+func (c *A2AClient) PollEvents(
+	ctx context.Context,
+	params protocol.PollEventsParams,
+	opts ...CallOption,
+) (*protocol.PollEventsResult, error) {
+	ctx = withCallOptions(ctx, opts)
+	request := jsonrpc.NewRequest(protocol.MethodTasksPollEvents, params.ID)
+	paramsBytes, err := c.codec.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("a2aClient.PollEvents: failed to marshal params: %w", err)
+	}
+	request.Params = paramsBytes
+	var result protocol.PollEventsResult
+	if err := c.doRequestAndDecode(ctx, request, &result); err != nil {
+		return nil, fmt.Errorf("a2aClient.PollEvents: %w", err)
+	}
+	return &result, nil
+}
+
+// isTaskNotFound reports whether err is the JSON-RPC error of an unknown task.
+func isTaskNotFound(err error) bool {
+	var rpcErr *jsonrpc.Error
+	return errors.As(err, &rpcErr) && rpcErr.Code == errCodeTaskNotFound
+}
+
+// pollStream returns a channel receiving the events of taskID read by long
+// polling, like the channel of a stream, for maxPollDuration at most. If send
+// is not nil, it is sent with tasks/send while polling, the task being
+// unknown until the agent received it.
+func (c *A2AClient) pollStream(
+	ctx context.Context,
+	taskID string,
+	send *protocol.SendTaskParams,
+) (<-chan protocol.TaskEvent, error) {
+	select {
+	case c.pollSessions <- struct{}{}:
+	default:
+		return nil, errTooManyPolls
+	}
+	ctx, cancel := context.WithTimeout(ctx, maxPollDuration)
+	var sent chan error
+	if send != nil {
+		sent = make(chan error, 1)
+		go func() {
+			_, err := c.sendTask(ctx, *send)
+			sent <- err
+		}()
+	}
+	params := protocol.PollEventsParams{ID: taskID, WaitMs: c.pollWait.Milliseconds()}
+	result, err := c.PollEvents(ctx, params)
+	if err != nil && sent != nil && isTaskNotFound(err) {
+		result, err = nil, nil
+	}
+	if err != nil {
+		cancel()
+		<-c.pollSessions
+		return nil, err
+	}
+	eventsChan := make(chan protocol.TaskEvent, 10)
+	go func() {
+		defer func() { <-c.pollSessions }()
+		defer cancel()
+		c.runPolls(ctx, params, result, sent, eventsChan)
+	}()
+	return eventsChan, nil
+}
+
+// runPolls delivers the events of result, then of the following polls, until
+// the final event. While sent is not nil, the task is being sent: polls for
+// an unknown task are retried and the end of the task is polled again once it
+// is sent.
+func (c *A2AClient) runPolls(
+	ctx context.Context,
+	params protocol.PollEventsParams,
+	result *protocol.PollEventsResult,
+	sent chan error,
+	eventsChan chan<- protocol.TaskEvent,
+) {
+	defer close(eventsChan)
+	for {
+		if sent != nil {
+			select {
+			case err := <-sent:
+				if err != nil {
+					log.Errorf("Failed to send task %s polled for events: %v", params.ID, err)
+					return
+				}
+				sent = nil
+			default:
+			}
+		}
+		if result == nil {
+			var err error
+			result, err = c.PollEvents(ctx, params)
+			if err != nil && sent != nil && isTaskNotFound(err) {
+				select {
+				case err := <-sent:
+					if err != nil {
+						log.Errorf("Failed to send task %s polled for events: %v", params.ID, err)
+						return
+					}
+					sent = nil
+				case <-time.After(pollRetryInterval):
+				case <-ctx.Done():
+					return
+				}
+				continue
+			}
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Errorf("Error polling events of task %s: %v", params.ID, err)
+				}
+				return
+			}
+		}
+		if result.Done && sent != nil {
+			// The task ended before it was sent again: poll the same events
+			// once the agent processed the task.
+			select {
+			case err := <-sent:
+				if err != nil {
+					log.Errorf("Failed to send task %s polled for events: %v", params.ID, err)
+					return
+				}
+				sent = nil
+			case <-ctx.Done():
+				return
+			}
+			result = nil
+			continue
+		}
+		for _, polled := range result.Events {
+			event := polled.Event
+			if c.dedup.duplicate(params.ID, event) {
+				continue
+			}
+			c.reportWarnings(protocol.MethodTasksPollEvents, params.ID, protocol.EventWarnings(event))
+			c.taskCache.invalidate(params.ID)
+			select {
+			case eventsChan <- event:
+				c.dedup.delivered(params.ID, event)
+			case <-ctx.Done():
+				return
+			}
+		}
+		if result.Done {
+			return
+		}
+		params.Cursor = result.Cursor
+		result = nil
+	}
+}
@@ -0,0 +1,43 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"trpc.group/trpc-go/trpc-a2a-go/auth"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// signProvenance appends a hop signed by the client's provenance signer to the
+// provenance chain found in ctx, or starts a new chain, and records it in the
+// params metadata. The caller of the hop is the authenticated user in ctx, if
+// any. The caller's params are not modified.
+func (c *A2AClient) signProvenance(
+	ctx context.Context,
+	params protocol.SendTaskParams,
+) (protocol.SendTaskParams, error) {
+	if c.provenanceSigner == nil {
+		return params, nil
+	}
+	var caller string
+	if user, ok := ctx.Value(auth.AuthUserKey).(*auth.User); ok && user != nil {
+		caller = user.ID
+	}
+	chain, err := c.provenanceSigner.Extend(auth.ProvenanceFromContext(ctx), caller, params.ID)
+	if err != nil {
+		return params, fmt.Errorf("failed to sign provenance: %w", err)
+	}
+	metadata := make(map[string]interface{}, len(params.Metadata)+1)
+	for k, v := range params.Metadata {
+		metadata[k] = v
+	}
+	metadata[auth.MetadataKeyProvenance] = chain
+	params.Metadata = metadata
+	return params, nil
+}
@@ -0,0 +1,49 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"context"
+	"crypto/ed25519"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/auth"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+func TestSignProvenance(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	keys := auth.StaticProvenanceKeys{"k1": pub}
+	origin, err := auth.NewProvenanceSigner("client", "k1", priv).Extend(nil, "", "parent")
+	require.NoError(t, err)
+
+	c := &A2AClient{}
+	WithProvenanceSigner(auth.NewProvenanceSigner("planner", "k1", priv))(c)
+
+	// Inside a processor the context carries the verified chain and the user.
+	ctx := auth.ContextWithProvenance(context.Background(), origin)
+	ctx = context.WithValue(ctx, auth.AuthUserKey, &auth.User{ID: "alice"})
+	params := protocol.SendTaskParams{ID: "child", Metadata: map[string]interface{}{"k": "v"}}
+
+	got, err := c.signProvenance(ctx, params)
+	require.NoError(t, err)
+	assert.Equal(t, "v", got.Metadata["k"])
+	assert.NotContains(t, params.Metadata, auth.MetadataKeyProvenance)
+
+	chain, err := auth.ProvenanceFromMetadata(got.Metadata)
+	require.NoError(t, err)
+	require.Len(t, chain, 2)
+	last, _ := chain.Last()
+	assert.Equal(t, "planner", last.Agent)
+	assert.Equal(t, "alice", last.Caller)
+	assert.Equal(t, "child", last.TaskID)
+	assert.NoError(t, (&auth.ProvenanceVerifier{Keys: keys}).Verify(ctx, chain))
+}
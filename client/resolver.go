@@ -0,0 +1,182 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+)
+
+const (
+	// defaultResolveInterval is the default polling interval of polling resolvers.
+	defaultResolveInterval = 30 * time.Second
+	// minResolverRetry and maxResolverRetry bound the backoff between failed watches.
+	minResolverRetry = time.Second
+	maxResolverRetry = 30 * time.Second
+)
+
+// Resolver discovers the base URLs of the replicas of an agent, e.g. from DNS
+// SRV records, Kubernetes Endpoints or etcd keys. A client created with
+// WithResolver balances over the latest set of URLs it reports.
+type Resolver interface {
+	// Watch calls update with the complete set of agent base URLs initially
+	// and whenever it changes, until ctx is done. It returns ctx.Err() when ctx
+	// is done and any other error if watching fails, in which case the client
+	// calls Watch again after a backoff.
+	Watch(ctx context.Context, update func(urls []string)) error
+}
+
+// ResolverFunc adapts a function to the Resolver interface.
+type ResolverFunc func(ctx context.Context, update func(urls []string)) error
+
+// Watch implements Resolver.
+func (f ResolverFunc) Watch(ctx context.Context, update func(urls []string)) error {
+	return f(ctx, update)
+}
+
+// PollingResolver turns a lookup function into a Resolver by calling it at a
+// fixed interval and reporting the result when it changes. It suits sources
+// without change notifications, such as DNS.
+type PollingResolver struct {
+	// Lookup returns the current agent base URLs.
+	Lookup func(ctx context.Context) ([]string, error)
+	// Interval between lookups. Defaults to 30s.
+	Interval time.Duration
+}
+
+// Watch implements Resolver. Failed lookups are logged and retried at the next interval.
+func (r *PollingResolver) Watch(ctx context.Context, update func(urls []string)) error {
+	interval := r.Interval
+	if interval <= 0 {
+		interval = defaultResolveInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var last []string
+	for {
+		urls, err := r.Lookup(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			log.Warnf("Failed to resolve agent replicas: %v", err)
+		} else {
+			sort.Strings(urls)
+			if !equalStrings(urls, last) {
+				last = urls
+				update(urls)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// NewDNSSRVResolver returns a resolver polling the DNS SRV records of
+// _service._proto.name, such as those of a Kubernetes headless service with a
+// named port. Each target is turned into a base URL with the given scheme and
+// path, e.g. "http" and "/". A nil net.Resolver uses net.DefaultResolver.
+func NewDNSSRVResolver(
+	resolver *net.Resolver,
+	service, proto, name string,
+	scheme, path string,
+	interval time.Duration,
+) *PollingResolver {
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	return &PollingResolver{
+		Interval: interval,
+		Lookup: func(ctx context.Context) ([]string, error) {
+			_, records, err := resolver.LookupSRV(ctx, service, proto, name)
+			if err != nil {
+				return nil, fmt.Errorf("failed to look up SRV records of %s: %w", name, err)
+			}
+			return srvURLs(records, scheme, path), nil
+		},
+	}
+}
+
+// srvURLs converts SRV records into agent base URLs.
+func srvURLs(records []*net.SRV, scheme, path string) []string {
+	urls := make([]string, 0, len(records))
+	for _, record := range records {
+		host := strings.TrimSuffix(record.Target, ".")
+		u := url.URL{
+			Scheme: scheme,
+			Host:   net.JoinHostPort(host, strconv.Itoa(int(record.Port))),
+			Path:   path,
+		}
+		urls = append(urls, u.String())
+	}
+	return urls
+}
+
+// watchResolver keeps the balancer targets in sync with the resolver until ctx is done.
+func (c *A2AClient) watchResolver(ctx context.Context, done chan<- struct{}) {
+	defer close(done)
+	retry := minResolverRetry
+	for {
+		err := c.resolver.Watch(ctx, c.updateTargets)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Warnf("Agent replica resolver failed, retrying in %v: %v", retry, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retry):
+		}
+		if retry *= 2; retry > maxResolverRetry {
+			retry = maxResolverRetry
+		}
+	}
+}
+
+// updateTargets replaces the replicas the client balances over. Invalid URLs
+// are skipped, and an empty set is ignored so that a resolver hiccup does not
+// leave the client without targets.
+func (c *A2AClient) updateTargets(rawURLs []string) {
+	urls := make([]*url.URL, 0, len(rawURLs))
+	for _, raw := range rawURLs {
+		u, err := parseAgentURL(raw)
+		if err != nil {
+			log.Warnf("Ignoring resolved agent replica: %v", err)
+			continue
+		}
+		urls = append(urls, u)
+	}
+	if len(urls) == 0 {
+		log.Warnf("Resolver reported no agent replicas, keeping the current ones")
+		return
+	}
+	c.balancer.update(urls)
+}
+
+// equalStrings reports whether two string slices are equal.
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,109 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// chanResolver reports the URL sets sent on its channel.
+type chanResolver chan []string
+
+func (r chanResolver) Watch(ctx context.Context, update func(urls []string)) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case urls := <-r:
+			update(urls)
+		}
+	}
+}
+
+func TestWithResolver(t *testing.T) {
+	a, b := newReplicaServer(t), newReplicaServer(t)
+	resolver := make(chanResolver)
+	client, err := NewA2AClient(a.URL, WithResolver(resolver))
+	require.NoError(t, err)
+
+	get := func(taskID string) {
+		_, err := client.GetTasks(context.Background(), protocol.TaskQueryParams{ID: taskID})
+		require.NoError(t, err)
+	}
+	get("t1")
+	assert.Len(t, a.received(), 1, "the agent URL is used until the resolver reports")
+
+	resolver <- []string{b.URL}
+	require.Eventually(t, func() bool {
+		replicas := client.balancer.snapshot()
+		return len(replicas) == 1 && replicas[0].url.String() == b.URL+"/"
+	}, time.Second, 5*time.Millisecond)
+	get("t2")
+	assert.Len(t, b.received(), 1)
+
+	// Empty and invalid sets keep the current targets.
+	resolver <- []string{}
+	resolver <- []string{"::not a url"}
+	get("t3")
+	assert.Len(t, b.received(), 2)
+
+	require.NoError(t, client.Close())
+}
+
+func TestPollingResolver(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		results = [][]string{{"http://b", "http://a"}, {"http://a", "http://b"}, nil, {"http://c"}}
+		calls   int
+	)
+	r := &PollingResolver{
+		Interval: time.Millisecond,
+		Lookup: func(ctx context.Context) ([]string, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if calls >= len(results) {
+				return []string{"http://c"}, nil
+			}
+			result := results[calls]
+			calls++
+			if result == nil {
+				return nil, errors.New("lookup failed")
+			}
+			return result, nil
+		},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	updates := make(chan []string, 10)
+	done := make(chan error)
+	go func() { done <- r.Watch(ctx, func(urls []string) { updates <- urls }) }()
+
+	assert.Equal(t, []string{"http://a", "http://b"}, <-updates)
+	assert.Equal(t, []string{"http://c"}, <-updates, "unchanged sets and failures are not reported")
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestSRVURLs(t *testing.T) {
+	urls := srvURLs([]*net.SRV{
+		{Target: "agent-0.agents.default.svc.cluster.local.", Port: 8080},
+		{Target: "10.0.0.1", Port: 9090},
+	}, "http", "/a2a/")
+	assert.Equal(t, []string{
+		"http://agent-0.agents.default.svc.cluster.local:8080/a2a/",
+		"http://10.0.0.1:9090/a2a/",
+	}, urls)
+}
@@ -0,0 +1,270 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// maxResultFileBytes bounds the size of the result files downloaded by
+// SaveResultFiles.
+const maxResultFileBytes = 256 << 20
+
+// ErrNoResult is returned by ResultJSON when the task has no data part.
+var ErrNoResult = errors.New("task has no data result")
+
+// ResultText returns the text of the text parts of the artifacts of task, such
+// as the Markdown answer of an agent. The text of the chunks of an artifact is
+// joined as is, and the text of different artifacts is separated by a newline.
+func ResultText(task *protocol.Task) string {
+	if task == nil {
+		return ""
+	}
+	var texts []string
+	for _, artifact := range joinArtifacts(task.Artifacts) {
+		var b strings.Builder
+		for _, part := range artifact.Parts {
+			switch p := part.(type) {
+			case protocol.TextPart:
+				b.WriteString(p.Text)
+			case *protocol.TextPart:
+				b.WriteString(p.Text)
+			}
+		}
+		if b.Len() > 0 {
+			texts = append(texts, b.String())
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// ResultJSON decodes the data of the first data part of the artifacts of task
+// into v, as json.Unmarshal does, or returns ErrNoResult if there is none.
+func ResultJSON(task *protocol.Task, v interface{}) error {
+	if task != nil {
+		for _, artifact := range task.Artifacts {
+			for _, part := range artifact.Parts {
+				var data interface{}
+				switch p := part.(type) {
+				case protocol.DataPart:
+					data = p.Data
+				case *protocol.DataPart:
+					data = p.Data
+				default:
+					continue
+				}
+				encoded, err := json.Marshal(data)
+				if err != nil {
+					return fmt.Errorf("failed to encode data result: %w", err)
+				}
+				if err := json.Unmarshal(encoded, v); err != nil {
+					return fmt.Errorf("failed to decode data result: %w", err)
+				}
+				return nil
+			}
+		}
+	}
+	return ErrNoResult
+}
+
+// SaveResultFiles writes the files of the file parts of the artifacts of task
+// to dir, created if needed, and returns their paths. The bytes of the file
+// parts are decoded, and the files referenced by URI are downloaded, up to
+// 256 MiB each, with the HTTP client of c if the agent serves them and without
+// its credentials otherwise. A file part continuing an artifact streamed in
+// chunks is appended to the file of the previous chunk. Files are named after
+// the file parts, or else after their artifacts, and existing files are
+// replaced.
+func (c *A2AClient) SaveResultFiles(ctx context.Context, task *protocol.Task, dir string) ([]string, error) {
+	if task == nil {
+		return nil, nil
+	}
+	files := resultFiles(task.Artifacts)
+	if len(files) == 0 {
+		return nil, nil
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create result directory: %w", err)
+	}
+	paths := make([]string, 0, len(files))
+	for _, file := range files {
+		path := filepath.Join(dir, file.name)
+		if err := c.saveResultFile(ctx, path, file); err != nil {
+			return paths, fmt.Errorf("failed to save %s: %w", file.name, err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// saveResultFile writes file to path.
+func (c *A2AClient) saveResultFile(ctx context.Context, path string, file resultFile) error {
+	if file.uri == "" {
+		var data []byte
+		for _, chunk := range file.chunks {
+			decoded, err := base64.StdEncoding.DecodeString(chunk)
+			if err != nil {
+				return fmt.Errorf("invalid file bytes: %w", err)
+			}
+			data = append(data, decoded...)
+		}
+		return os.WriteFile(path, data, 0o644)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, file.uri, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.resultFileClient(ctx, req.URL).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected http status %d", resp.StatusCode)
+	}
+	if resp.ContentLength > maxResultFileBytes {
+		return fmt.Errorf("file of %d bytes exceeds the limit of %d bytes", resp.ContentLength, maxResultFileBytes)
+	}
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	n, err := io.Copy(out, io.LimitReader(resp.Body, maxResultFileBytes+1))
+	if err == nil && n > maxResultFileBytes {
+		err = fmt.Errorf("file exceeds the limit of %d bytes", maxResultFileBytes)
+	}
+	if err != nil {
+		out.Close()
+		os.Remove(path)
+		return err
+	}
+	return out.Close()
+}
+
+// resultFileClient returns the HTTP client downloading the result file at u.
+// The file URI is chosen by the agent, so the HTTP client of c, which may
+// authenticate its requests, is only used for the origins of the agent; other
+// files are downloaded without credentials.
+func (c *A2AClient) resultFileClient(ctx context.Context, u *url.URL) *http.Client {
+	for _, r := range c.balancer.snapshot() {
+		if strings.EqualFold(r.url.Scheme, u.Scheme) && strings.EqualFold(r.url.Host, u.Host) {
+			return c.callHTTPClient(ctx)
+		}
+	}
+	return &http.Client{Timeout: c.requestTimeout(ctx)}
+}
+
+// resultFile is a file of the artifacts of a task.
+type resultFile struct {
+	name   string   // Name of the file in the result directory.
+	chunks []string // Base64 bytes of the file, if not referenced by URI.
+	uri    string   // URI of the file.
+}
+
+// resultFiles returns the files of artifacts, with unique names.
+func resultFiles(artifacts []protocol.Artifact) []resultFile {
+	var files []resultFile
+	used := make(map[string]bool)
+	lastFile := make(map[int]int) // Position of the last file of each artifact.
+	for _, artifact := range artifacts {
+		for i, part := range artifact.Parts {
+			var file protocol.FileContent
+			switch p := part.(type) {
+			case protocol.FilePart:
+				file = p.File
+			case *protocol.FilePart:
+				file = p.File
+			default:
+				continue
+			}
+			if file.Bytes == nil && file.URI == nil {
+				continue
+			}
+			// The first part of an appended chunk continues the file of the
+			// previous chunk of the artifact.
+			if last, ok := lastFile[artifact.Index]; ok && i == 0 && file.Bytes != nil &&
+				artifact.Append != nil && *artifact.Append && files[last].uri == "" {
+				files[last].chunks = append(files[last].chunks, *file.Bytes)
+				continue
+			}
+			name := uniqueFileName(used, resultFileName(artifact, file))
+			lastFile[artifact.Index] = len(files)
+			if file.URI != nil {
+				files = append(files, resultFile{name: name, uri: *file.URI})
+			} else {
+				files = append(files, resultFile{name: name, chunks: []string{*file.Bytes}})
+			}
+		}
+	}
+	return files
+}
+
+// resultFileName returns the name of file, a file of artifact, with no
+// directory: its own name, or else the name of the artifact or its index,
+// with the extension of its media type.
+func resultFileName(artifact protocol.Artifact, file protocol.FileContent) string {
+	if file.Name != nil {
+		if name := filepath.Base(filepath.Clean("/" + *file.Name)); name != "/" {
+			return name
+		}
+	}
+	name := "artifact-" + strconv.Itoa(artifact.Index)
+	if artifact.Name != nil && *artifact.Name != "" {
+		if base := filepath.Base(filepath.Clean("/" + *artifact.Name)); base != "/" {
+			name = base
+		}
+	}
+	if filepath.Ext(name) == "" && file.MimeType != nil {
+		if exts, _ := mime.ExtensionsByType(*file.MimeType); len(exts) > 0 {
+			name += exts[0]
+		}
+	}
+	return name
+}
+
+// uniqueFileName returns name, numbered if it is in used, and adds it to used.
+func uniqueFileName(used map[string]bool, name string) string {
+	unique := name
+	ext := filepath.Ext(name)
+	for n := 2; used[unique]; n++ {
+		unique = strings.TrimSuffix(name, ext) + "-" + strconv.Itoa(n) + ext
+	}
+	used[unique] = true
+	return unique
+}
+
+// joinArtifacts returns artifacts with the parts of the chunks of each
+// artifact joined, in the order of their first chunk.
+func joinArtifacts(artifacts []protocol.Artifact) []protocol.Artifact {
+	var joined []protocol.Artifact
+	position := make(map[int]int)
+	for _, chunk := range artifacts {
+		i, ok := position[chunk.Index]
+		if !ok {
+			position[chunk.Index] = len(joined)
+			chunk.Parts = append([]protocol.Part(nil), chunk.Parts...)
+			joined = append(joined, chunk)
+			continue
+		}
+		joined[i].Parts = append(joined[i].Parts, chunk.Parts...)
+	}
+	return joined
+}
@@ -0,0 +1,117 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+func TestResultHelpers(t *testing.T) {
+	filePart := func(name string, content string) protocol.Part {
+		encoded := base64.StdEncoding.EncodeToString([]byte(content))
+		part := protocol.FilePart{Type: protocol.PartTypeFile, File: protocol.FileContent{Bytes: &encoded}}
+		if name != "" {
+			part.File.Name = &name
+		}
+		return part
+	}
+	appended := true
+	reportName := "report"
+	pdf := "application/pdf"
+	remote := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("remote content"))
+	}))
+	defer remote.Close()
+	remoteURI := remote.URL + "/data.csv"
+	task := &protocol.Task{Artifacts: []protocol.Artifact{
+		{Index: 0, Parts: []protocol.Part{protocol.NewTextPart("# Title\n")}},
+		{Index: 1, Parts: []protocol.Part{protocol.DataPart{
+			Type: protocol.PartTypeData, Data: map[string]interface{}{"score": 0.5, "label": "ok"},
+		}}},
+		{Index: 0, Append: &appended, Parts: []protocol.Part{protocol.NewTextPart("body")}},
+		{Index: 2, Name: &reportName, Parts: []protocol.Part{protocol.FilePart{
+			Type: protocol.PartTypeFile, File: protocol.FileContent{MimeType: &pdf, Bytes: new(string)},
+		}}},
+		{Index: 3, Parts: []protocol.Part{filePart("../notes.txt", "part 1, ")}},
+		{Index: 2, Append: &appended, Parts: []protocol.Part{filePart("", "%PDF")}},
+		{Index: 3, Append: &appended, Parts: []protocol.Part{filePart("", "part 2"), filePart("notes.txt", "other")}},
+		{Index: 4, Parts: []protocol.Part{protocol.FilePart{
+			Type: protocol.PartTypeFile, File: protocol.FileContent{URI: &remoteURI},
+		}}, Name: &reportName},
+		{Index: 5, Parts: []protocol.Part{protocol.NewTextPart("conclusion")}},
+	}}
+
+	assert.Equal(t, "# Title\nbody\nconclusion", ResultText(task))
+
+	var result struct {
+		Score float64 `json:"score"`
+		Label string  `json:"label"`
+	}
+	require.NoError(t, ResultJSON(task, &result))
+	assert.Equal(t, 0.5, result.Score)
+	assert.Equal(t, "ok", result.Label)
+	assert.ErrorIs(t, ResultJSON(&protocol.Task{}, &result), ErrNoResult)
+
+	c, err := NewA2AClient("http://localhost:8080")
+	require.NoError(t, err)
+	dir := filepath.Join(t.TempDir(), "results")
+	paths, err := c.SaveResultFiles(context.Background(), task, dir)
+	require.NoError(t, err)
+	want := map[string]string{
+		"report.pdf":  "%PDF",
+		"notes.txt":   "part 1, part 2",
+		"notes-2.txt": "other",
+		"report":      "remote content",
+	}
+	require.Len(t, paths, len(want))
+	for _, path := range paths {
+		assert.Equal(t, dir, filepath.Dir(path))
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, want[filepath.Base(path)], string(data), path)
+	}
+}
+
+func TestSaveResultFiles_Credentials(t *testing.T) {
+	var agentKey, otherKey string
+	agent := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		agentKey = r.Header.Get("X-API-Key")
+		_, _ = w.Write([]byte("agent file"))
+	}))
+	defer agent.Close()
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		otherKey = r.Header.Get("X-API-Key")
+		_, _ = w.Write([]byte("other file"))
+	}))
+	defer other.Close()
+	filePart := func(uri string) protocol.Part {
+		return protocol.FilePart{Type: protocol.PartTypeFile, File: protocol.FileContent{URI: &uri}}
+	}
+	task := &protocol.Task{Artifacts: []protocol.Artifact{
+		{Index: 0, Parts: []protocol.Part{filePart(agent.URL + "/a.txt")}},
+		{Index: 1, Parts: []protocol.Part{filePart(other.URL + "/b.txt")}},
+	}}
+
+	c, err := NewA2AClient(agent.URL, WithAPIKeyAuth("secret", "X-API-Key"))
+	require.NoError(t, err)
+	paths, err := c.SaveResultFiles(context.Background(), task, t.TempDir())
+	require.NoError(t, err)
+	assert.Len(t, paths, 2)
+	assert.Equal(t, "secret", agentKey)
+	assert.Empty(t, otherKey)
+}
@@ -0,0 +1,38 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"context"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// Streamer starts tasks and cancels them through a client with fixed call
+// options. It implements taskmanager.TaskStreamer, so that processors
+// delegate subtasks to the agent of the client with taskmanager.Delegate.
+type Streamer struct {
+	client *A2AClient
+	opts   []CallOption
+}
+
+// Streamer returns a Streamer making its calls with opts. Options varying
+// per call, such as the headers of a tenant, are rather set on the context of
+// the calls, e.g. with metadata.AppendToOutgoingContext.
+func (c *A2AClient) Streamer(opts ...CallOption) *Streamer {
+	return &Streamer{client: c, opts: opts}
+}
+
+// StreamTask starts a task and streams its events like A2AClient.StreamTask.
+func (s *Streamer) StreamTask(ctx context.Context, params protocol.SendTaskParams) (<-chan protocol.TaskEvent, error) {
+	return s.client.StreamTask(ctx, params, s.opts...)
+}
+
+// CancelTasks cancels a task like A2AClient.CancelTasks.
+func (s *Streamer) CancelTasks(ctx context.Context, params protocol.TaskIDParams) (*protocol.Task, error) {
+	return s.client.CancelTasks(ctx, params, s.opts...)
+}
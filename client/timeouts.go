@@ -0,0 +1,157 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// errStreamIdle ends the streams that received no data for the idle timeout.
+var errStreamIdle = errors.New("stream idle timeout")
+
+// TimeoutConfig splits the timeouts of the client, so that streams can last
+// for hours while unary calls fail fast. Zero fields keep the current
+// timeouts.
+type TimeoutConfig struct {
+	// Request bounds unary calls, from sending the request to reading the
+	// whole response, like WithTimeout. Streams are bounded by it until their
+	// response headers are received only. Default 60s.
+	Request time.Duration
+	// Dial bounds establishing a TCP connection.
+	Dial time.Duration
+	// TLSHandshake bounds the TLS handshake.
+	TLSHandshake time.Duration
+	// ResponseHeader bounds waiting for the response headers once the request
+	// is sent, for unary calls and streams alike.
+	ResponseHeader time.Duration
+	// StreamIdle ends the streams that receive no data, keep-alive comments
+	// included, for that long. Streams are never idle by default.
+	StreamIdle time.Duration
+}
+
+// transportTimeouts reports whether cfg sets timeouts of the transport.
+func (cfg TimeoutConfig) transportTimeouts() bool {
+	return cfg.Dial > 0 || cfg.TLSHandshake > 0 || cfg.ResponseHeader > 0
+}
+
+// applyTimeouts sets the timeouts of the client's transport. The transport is
+// cloned so that transports shared with other clients are left untouched.
+func (c *A2AClient) applyTimeouts() error {
+	if !c.timeouts.transportTimeouts() {
+		return nil
+	}
+	var transport *http.Transport
+	switch t := c.httpClient.Transport.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		transport = t.Clone()
+	default:
+		return fmt.Errorf("transport timeouts require an *http.Transport, got %T", t)
+	}
+	if c.timeouts.Dial > 0 {
+		transport.DialContext = (&net.Dialer{
+			Timeout:   c.timeouts.Dial,
+			KeepAlive: defaultKeepAlive,
+		}).DialContext
+	}
+	if c.timeouts.TLSHandshake > 0 {
+		transport.TLSHandshakeTimeout = c.timeouts.TLSHandshake
+	}
+	if c.timeouts.ResponseHeader > 0 {
+		transport.ResponseHeaderTimeout = c.timeouts.ResponseHeader
+	}
+	httpClient := *c.httpClient
+	httpClient.Transport = transport
+	c.httpClient = &httpClient
+	return nil
+}
+
+// doStreamRequest sends req, the request of a stream. Unlike the timeout of
+// the HTTP client, which would cut the stream, the request timeout bounds the
+// wait for the response headers only. The body of the response ends the
+// stream when it is idle.
+func (c *A2AClient) doStreamRequest(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithCancel(req.Context())
+	req = req.WithContext(ctx)
+	httpClient := *c.httpClient
+	httpClient.Timeout = 0
+	var headerTimer *time.Timer
+	timeout := c.requestTimeout(req.Context())
+	if timeout > 0 {
+		headerTimer = time.AfterFunc(timeout, cancel)
+	}
+	resp, err := c.httpReqHandler(ctx, &httpClient, req)
+	if headerTimer != nil && !headerTimer.Stop() {
+		cancel()
+		if err == nil && resp != nil && resp.Body != nil {
+			resp.Body.Close()
+		}
+		return nil, fmt.Errorf("http request failed: no response headers within %v", timeout)
+	}
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("http request failed: %w", err)
+	}
+	if resp == nil || resp.Body == nil {
+		cancel()
+		return nil, fmt.Errorf("unexpected nil response")
+	}
+	resp.Body = newStreamBody(resp.Body, cancel, c.timeouts.StreamIdle)
+	return resp, nil
+}
+
+// streamBody is the body of a stream response, which cancels the request of
+// the stream when closed or idle.
+type streamBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+	idle   time.Duration
+	timer  *time.Timer // Fires when the stream is idle, if idle is positive.
+	idled  atomic.Bool
+}
+
+// newStreamBody wraps body, the stream of a request canceled by cancel.
+func newStreamBody(body io.ReadCloser, cancel context.CancelFunc, idle time.Duration) *streamBody {
+	b := &streamBody{ReadCloser: body, cancel: cancel, idle: idle}
+	if idle > 0 {
+		b.timer = time.AfterFunc(idle, func() {
+			b.idled.Store(true)
+			cancel()
+		})
+	}
+	return b
+}
+
+// Read implements io.Reader, failing with errStreamIdle once the stream was
+// idle.
+func (b *streamBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if b.idled.Load() {
+		return n, fmt.Errorf("%w: no data for %v", errStreamIdle, b.idle)
+	}
+	if n > 0 && b.timer != nil {
+		b.timer.Reset(b.idle)
+	}
+	return n, err
+}
+
+// Close implements io.Closer.
+func (b *streamBody) Close() error {
+	if b.timer != nil {
+		b.timer.Stop()
+	}
+	b.cancel()
+	return b.ReadCloser.Close()
+}
@@ -0,0 +1,131 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// slowStreamHandler waits for delay before answering with an event stream of
+// events status updates, one every interval, unless the client goes away.
+func slowStreamHandler(delay time.Duration, events int, interval time.Duration) http.HandlerFunc {
+	wait := func(r *http.Request, d time.Duration) bool {
+		select {
+		case <-time.After(d):
+			return true
+		case <-r.Context().Done():
+			return false
+		}
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !wait(r, delay) {
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		for i := 0; i < events; i++ {
+			if !wait(r, interval) {
+				return
+			}
+			fmt.Fprintf(w, "event: task_status_update\ndata: {\"id\":\"task\",\"status\":{\"state\":\"working\"},\"final\":%t}\n\n",
+				i == events-1)
+			w.(http.Flusher).Flush()
+		}
+	}
+}
+
+func TestA2AClient_StreamOutlastsRequestTimeout(t *testing.T) {
+	server := httptest.NewServer(slowStreamHandler(0, 5, 60*time.Millisecond))
+	defer server.Close()
+	client, err := NewA2AClient(server.URL, WithTimeout(100*time.Millisecond))
+	require.NoError(t, err)
+
+	events, err := client.StreamTask(context.Background(), protocol.SendTaskParams{
+		ID:      "task",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")}),
+	})
+	require.NoError(t, err)
+	received := 0
+	for event := range events {
+		received++
+		if event.IsFinal() {
+			break
+		}
+	}
+	assert.Equal(t, 5, received, "the request timeout does not cut the stream")
+}
+
+func TestA2AClient_StreamTimeouts(t *testing.T) {
+	params := protocol.SendTaskParams{
+		ID:      "task",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")}),
+	}
+
+	t.Run("response headers", func(t *testing.T) {
+		server := httptest.NewServer(slowStreamHandler(time.Second, 1, 0))
+		defer server.Close()
+		client, err := NewA2AClient(server.URL, WithTimeouts(TimeoutConfig{Request: 100 * time.Millisecond}))
+		require.NoError(t, err)
+		start := time.Now()
+		_, err = client.StreamTask(context.Background(), params)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no response headers within 100ms")
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("idle", func(t *testing.T) {
+		// No event is sent before the stream is idle.
+		server := httptest.NewServer(slowStreamHandler(0, 2, time.Hour))
+		defer server.Close()
+		client, err := NewA2AClient(server.URL, WithTimeouts(TimeoutConfig{StreamIdle: 100 * time.Millisecond}))
+		require.NoError(t, err)
+		events, err := client.StreamTask(context.Background(), params)
+		require.NoError(t, err)
+		select {
+		case _, ok := <-events:
+			assert.False(t, ok, "no event is received before the stream is idle")
+		case <-time.After(2 * time.Second):
+			t.Fatal("idle stream not closed")
+		}
+	})
+}
+
+func TestWithTimeouts(t *testing.T) {
+	shared := NewTransport(TransportConfig{})
+	client, err := NewA2AClient("http://localhost:8080", WithTransport(shared), WithTimeouts(TimeoutConfig{
+		Request:        5 * time.Second,
+		TLSHandshake:   2 * time.Second,
+		ResponseHeader: 3 * time.Second,
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Second, client.httpClient.Timeout)
+	transport, ok := client.httpClient.Transport.(*http.Transport)
+	require.True(t, ok)
+	assert.NotSame(t, shared, transport, "shared transports are cloned")
+	assert.Equal(t, 2*time.Second, transport.TLSHandshakeTimeout)
+	assert.Equal(t, 3*time.Second, transport.ResponseHeaderTimeout)
+	assert.Zero(t, shared.ResponseHeaderTimeout)
+
+	// Without transport timeouts any transport is accepted.
+	_, err = NewA2AClient("http://localhost:8080", WithAPIKeyAuth("key", "X-API-Key"),
+		WithTimeouts(TimeoutConfig{StreamIdle: time.Minute}))
+	require.NoError(t, err)
+	_, err = NewA2AClient("http://localhost:8080", WithAPIKeyAuth("key", "X-API-Key"),
+		WithTimeouts(TimeoutConfig{Dial: time.Second}))
+	assert.Error(t, err)
+}
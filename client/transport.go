@@ -0,0 +1,124 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Defaults used by NewTransport for unset TransportConfig fields.
+const (
+	defaultMaxIdleConns        = 100
+	defaultMaxIdleConnsPerHost = 16
+	defaultIdleConnTimeout     = 90 * time.Second
+	defaultDialTimeout         = 30 * time.Second
+	defaultKeepAlive           = 30 * time.Second
+	defaultTLSHandshakeTimeout = 10 * time.Second
+)
+
+// TransportConfig tunes connection pooling and keep-alive of an HTTP transport
+// created by NewTransport. Zero fields select the defaults.
+type TransportConfig struct {
+	// MaxIdleConns limits idle connections across all hosts. Default 100.
+	MaxIdleConns int
+	// MaxIdleConnsPerHost limits idle connections kept per host. Default 16,
+	// well above net/http's default of 2, which makes clients issuing concurrent
+	// requests to one agent open and close connections continuously.
+	MaxIdleConnsPerHost int
+	// MaxConnsPerHost limits connections per host, including active ones.
+	// Zero means no limit.
+	MaxConnsPerHost int
+	// IdleConnTimeout is how long an idle connection is kept in the pool. Default 90s.
+	IdleConnTimeout time.Duration
+	// DialTimeout bounds establishing a TCP connection. Default 30s.
+	DialTimeout time.Duration
+	// KeepAlive is the TCP keep-alive period. Default 30s; negative disables TCP keep-alives.
+	KeepAlive time.Duration
+	// TLSHandshakeTimeout bounds the TLS handshake. Default 10s.
+	TLSHandshakeTimeout time.Duration
+	// ResponseHeaderTimeout bounds waiting for the response headers once the
+	// request is sent. Zero means no limit.
+	ResponseHeaderTimeout time.Duration
+	// DisableKeepAlives disables HTTP keep-alives, using each connection for a single request.
+	DisableKeepAlives bool
+	// DisableHTTP2 prevents negotiating HTTP/2 over TLS. By default HTTP/2 is
+	// attempted, multiplexing requests to an agent over a single connection.
+	DisableHTTP2 bool
+	// TLSClientConfig is the optional TLS configuration.
+	TLSClientConfig *tls.Config
+}
+
+// NewTransport creates an HTTP transport configured by cfg. A transport pools
+// connections across all the hosts it talks to, so one transport can be shared
+// by many A2AClients via WithTransport.
+func NewTransport(cfg TransportConfig) *http.Transport {
+	cfg = cfg.withDefaults()
+	t := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   cfg.DialTimeout,
+			KeepAlive: cfg.KeepAlive,
+		}).DialContext,
+		MaxIdleConns:          cfg.MaxIdleConns,
+		MaxIdleConnsPerHost:   cfg.MaxIdleConnsPerHost,
+		MaxConnsPerHost:       cfg.MaxConnsPerHost,
+		IdleConnTimeout:       cfg.IdleConnTimeout,
+		TLSHandshakeTimeout:   cfg.TLSHandshakeTimeout,
+		ResponseHeaderTimeout: cfg.ResponseHeaderTimeout,
+		ExpectContinueTimeout: time.Second,
+		DisableKeepAlives:     cfg.DisableKeepAlives,
+		ForceAttemptHTTP2:     !cfg.DisableHTTP2,
+		TLSClientConfig:       cfg.TLSClientConfig,
+	}
+	if cfg.DisableHTTP2 {
+		// A non-nil empty map disables the transport's built-in HTTP/2 support.
+		t.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+	return t
+}
+
+// withDefaults returns cfg with unset fields replaced by their defaults.
+func (cfg TransportConfig) withDefaults() TransportConfig {
+	if cfg.MaxIdleConns == 0 {
+		cfg.MaxIdleConns = defaultMaxIdleConns
+	}
+	if cfg.MaxIdleConnsPerHost == 0 {
+		cfg.MaxIdleConnsPerHost = defaultMaxIdleConnsPerHost
+	}
+	if cfg.IdleConnTimeout == 0 {
+		cfg.IdleConnTimeout = defaultIdleConnTimeout
+	}
+	if cfg.DialTimeout == 0 {
+		cfg.DialTimeout = defaultDialTimeout
+	}
+	if cfg.KeepAlive == 0 {
+		cfg.KeepAlive = defaultKeepAlive
+	}
+	if cfg.TLSHandshakeTimeout == 0 {
+		cfg.TLSHandshakeTimeout = defaultTLSHandshakeTimeout
+	}
+	return cfg
+}
+
+var (
+	sharedTransportOnce sync.Once
+	sharedTransport     *http.Transport
+)
+
+// SharedTransport returns the process-wide transport used by clients created
+// with WithSharedTransport. It is created on first use with the default
+// TransportConfig.
+func SharedTransport() *http.Transport {
+	sharedTransportOnce.Do(func() {
+		sharedTransport = NewTransport(TransportConfig{})
+	})
+	return sharedTransport
+}
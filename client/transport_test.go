@@ -0,0 +1,85 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTransport(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		tr := NewTransport(TransportConfig{})
+		assert.Equal(t, defaultMaxIdleConns, tr.MaxIdleConns)
+		assert.Equal(t, defaultMaxIdleConnsPerHost, tr.MaxIdleConnsPerHost)
+		assert.Equal(t, 0, tr.MaxConnsPerHost)
+		assert.Equal(t, defaultIdleConnTimeout, tr.IdleConnTimeout)
+		assert.Equal(t, defaultTLSHandshakeTimeout, tr.TLSHandshakeTimeout)
+		assert.True(t, tr.ForceAttemptHTTP2)
+		assert.Nil(t, tr.TLSNextProto)
+	})
+
+	t.Run("custom", func(t *testing.T) {
+		tr := NewTransport(TransportConfig{
+			MaxIdleConns:        10,
+			MaxIdleConnsPerHost: 5,
+			MaxConnsPerHost:     8,
+			IdleConnTimeout:     time.Minute,
+			DisableKeepAlives:   true,
+			DisableHTTP2:        true,
+		})
+		assert.Equal(t, 10, tr.MaxIdleConns)
+		assert.Equal(t, 5, tr.MaxIdleConnsPerHost)
+		assert.Equal(t, 8, tr.MaxConnsPerHost)
+		assert.Equal(t, time.Minute, tr.IdleConnTimeout)
+		assert.True(t, tr.DisableKeepAlives)
+		assert.False(t, tr.ForceAttemptHTTP2)
+		assert.NotNil(t, tr.TLSNextProto)
+		assert.Empty(t, tr.TLSNextProto)
+	})
+}
+
+func TestWithTransport(t *testing.T) {
+	tr := NewTransport(TransportConfig{})
+	client, err := NewA2AClient("http://localhost:8080", WithTransport(tr))
+	require.NoError(t, err)
+	assert.Same(t, tr, client.httpClient.Transport)
+
+	// A custom HTTP client gets the transport too.
+	custom := &http.Client{}
+	client, err = NewA2AClient("http://localhost:8080", WithHTTPClient(custom), WithTransport(tr))
+	require.NoError(t, err)
+	assert.Same(t, tr, custom.Transport)
+
+	// A nil transport is ignored.
+	client, err = NewA2AClient("http://localhost:8080", WithTransport(tr), WithTransport(nil))
+	require.NoError(t, err)
+	assert.Same(t, tr, client.httpClient.Transport)
+}
+
+func TestWithTransportConfig(t *testing.T) {
+	client, err := NewA2AClient("http://localhost:8080", WithTransportConfig(TransportConfig{MaxIdleConnsPerHost: 3}))
+	require.NoError(t, err)
+	tr, ok := client.httpClient.Transport.(*http.Transport)
+	require.True(t, ok)
+	assert.Equal(t, 3, tr.MaxIdleConnsPerHost)
+	assert.NotSame(t, SharedTransport(), tr)
+}
+
+func TestWithSharedTransport(t *testing.T) {
+	a, err := NewA2AClient("http://agent-a:8080", WithSharedTransport())
+	require.NoError(t, err)
+	b, err := NewA2AClient("http://agent-b:8080", WithSharedTransport())
+	require.NoError(t, err)
+	assert.Same(t, SharedTransport(), a.httpClient.Transport)
+	assert.Same(t, a.httpClient.Transport, b.httpClient.Transport)
+	assert.NotSame(t, a.httpClient, b.httpClient)
+}
@@ -0,0 +1,216 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+const (
+	// defaultUploadChunkSize is the default size of the chunks of uploads.
+	defaultUploadChunkSize = 4 << 20
+)
+
+// defaultUploadRetry is the retry policy of the chunks of uploads when the
+// call has none, since uploads can always be resumed.
+var defaultUploadRetry = RetryPolicy{MaxAttempts: 3, Backoff: 500 * time.Millisecond}
+
+// UploadOptions configures Upload.
+type UploadOptions struct {
+	// Path of the uploads endpoint, relative to the agent base URL. Defaults
+	// to protocol.DefaultUploadPath.
+	Path string
+	// Name is the optional file name of the upload.
+	Name string
+	// MimeType is the optional media type of the upload.
+	MimeType string
+	// ChunkSize is the size of the chunks sent. Defaults to 4 MiB.
+	ChunkSize int64
+	// URI resumes the upload at URI, returned by an interrupted Upload,
+	// rather than starting a new one.
+	URI string
+	// Progress, if set, is called after each chunk with the number of bytes
+	// received by the agent and the size of the upload.
+	Progress func(sent, total int64)
+}
+
+// Upload uploads the size bytes of content to the resumable uploads endpoint
+// of the agent and returns the URI of the upload, to send in a file part:
+//
+//	uri, err := c.Upload(ctx, file, info.Size(), client.UploadOptions{MimeType: "video/mp4"})
+//	part := protocol.FilePart{Type: protocol.PartTypeFile, File: protocol.FileContent{URI: &uri}}
+//
+// The content is sent in chunks. Chunks failing because of the network or of
+// the agent are retried according to the retry policy of the call, or three
+// times if it has none, from the offset the agent reports. If Upload fails
+// with a URI, the upload can be resumed by calling Upload again with it.
+func (c *A2AClient) Upload(
+	ctx context.Context,
+	content io.ReaderAt,
+	size int64,
+	opts UploadOptions,
+	callOpts ...CallOption,
+) (string, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	if opts.ChunkSize <= 0 {
+		opts.ChunkSize = defaultUploadChunkSize
+	}
+	policy := c.retryPolicy(ctx)
+	if policy.MaxAttempts < 2 {
+		policy = defaultUploadRetry
+	}
+	uri, offset := opts.URI, int64(0)
+	var err error
+	if uri == "" {
+		if uri, err = c.createUpload(ctx, size, opts); err != nil {
+			return "", fmt.Errorf("a2aClient.Upload: %w", err)
+		}
+	} else if offset, err = c.uploadOffset(ctx, uri); err != nil {
+		return uri, fmt.Errorf("a2aClient.Upload: %w", err)
+	}
+	attempt, backoff := 1, policy.Backoff
+	for offset < size {
+		chunk := opts.ChunkSize
+		if remaining := size - offset; chunk > remaining {
+			chunk = remaining
+		}
+		next, err := c.uploadChunk(ctx, uri, offset, io.NewSectionReader(content, offset, chunk), chunk)
+		if err != nil {
+			if ctx.Err() != nil || attempt >= policy.MaxAttempts {
+				return uri, fmt.Errorf("a2aClient.Upload: %w", err)
+			}
+			log.Debugf("Resuming upload %s after attempt %d failed: %v", uri, attempt, err)
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				return uri, fmt.Errorf("a2aClient.Upload: %w", err)
+			}
+			attempt, backoff = attempt+1, backoff*2
+			if next, err = c.uploadOffset(ctx, uri); err != nil {
+				continue
+			}
+		} else {
+			attempt, backoff = 1, policy.Backoff
+		}
+		offset = next
+		if opts.Progress != nil {
+			opts.Progress(offset, size)
+		}
+	}
+	return uri, nil
+}
+
+// createUpload starts an upload of size bytes and returns its URI.
+func (c *A2AClient) createUpload(ctx context.Context, size int64, opts UploadOptions) (string, error) {
+	target, err := c.acquire(ctx, "")
+	if err != nil {
+		return "", err
+	}
+	uploadPath := opts.Path
+	if uploadPath == "" {
+		uploadPath = protocol.DefaultUploadPath
+	}
+	endpoint := target.url.ResolveReference(&url.URL{Path: strings.TrimPrefix(uploadPath, "/")})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.String(), nil)
+	if err != nil {
+		c.balancer.release(target, false)
+		return "", fmt.Errorf("failed to create http request: %w", err)
+	}
+	req.Header.Set(protocol.UploadLengthHeader, strconv.FormatInt(size, 10))
+	if opts.Name != "" {
+		req.Header.Set(protocol.UploadNameHeader, opts.Name)
+	}
+	if opts.MimeType != "" {
+		req.Header.Set("Content-Type", opts.MimeType)
+	}
+	resp, err := c.doUploadRequest(ctx, req)
+	c.balancer.release(target, replicaFailed(ctx, resp, err))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("failed to create upload: unexpected http status %d", resp.StatusCode)
+	}
+	location, err := resp.Location()
+	if err != nil {
+		return "", fmt.Errorf("failed to create upload: %w", err)
+	}
+	return location.String(), nil
+}
+
+// uploadOffset returns the number of bytes of the upload at uri received by
+// the agent.
+func (c *A2AClient) uploadOffset(ctx context.Context, uri string) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, uri, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create http request: %w", err)
+	}
+	resp, err := c.doUploadRequest(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("failed to resume upload: unexpected http status %d", resp.StatusCode)
+	}
+	return parseUploadOffset(resp)
+}
+
+// uploadChunk sends the chunk of size bytes at offset of the upload at uri
+// and returns the new offset of the upload.
+func (c *A2AClient) uploadChunk(ctx context.Context, uri string, offset int64, chunk io.Reader, size int64) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, uri, chunk)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create http request: %w", err)
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", protocol.ContentTypeUploadChunk)
+	req.Header.Set(protocol.UploadOffsetHeader, strconv.FormatInt(offset, 10))
+	resp, err := c.doUploadRequest(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+	if resp.StatusCode != http.StatusNoContent {
+		return 0, fmt.Errorf("failed to upload chunk: unexpected http status %d", resp.StatusCode)
+	}
+	return parseUploadOffset(resp)
+}
+
+// doUploadRequest sends a request of the uploads endpoint with the headers of
+// the client and of the call of ctx, and discards the response body.
+func (c *A2AClient) doUploadRequest(ctx context.Context, req *http.Request) (*http.Response, error) {
+	c.setMetadataHeaders(ctx, req.Header)
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	httpClient := c.callHTTPClient(ctx)
+	resp, err := c.httpReqHandler(ctx, httpClient, req)
+	if err != nil {
+		return nil, fmt.Errorf("http request failed: %w", err)
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorBodyBytes))
+	resp.Body.Close()
+	return resp, nil
+}
+
+// parseUploadOffset returns the upload offset reported by resp.
+func parseUploadOffset(resp *http.Response) (int64, error) {
+	offset, err := strconv.ParseInt(resp.Header.Get(protocol.UploadOffsetHeader), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s header: %w", protocol.UploadOffsetHeader, err)
+	}
+	return offset, nil
+}
@@ -0,0 +1,42 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// WarningHandler is called with the warnings the agent returned about a task,
+// in a task response or a stream event, except for deprecation notices, which
+// go to the DeprecationHandler. See protocol.TaskWarnings and
+// protocol.EventWarnings to read them from a given response instead.
+type WarningHandler func(taskID string, warnings []protocol.Warning)
+
+// logWarnings is the default WarningHandler.
+func logWarnings(taskID string, warnings []protocol.Warning) {
+	for _, w := range warnings {
+		log.Warnf("Agent warning %s for task %s: %s", w.Code, taskID, w.Message)
+	}
+}
+
+// reportWarnings passes the deprecation notices among the warnings returned
+// for a request of method to the deprecation handler, and the others to the
+// warning handler.
+func (c *A2AClient) reportWarnings(method, taskID string, warnings []protocol.Warning) {
+	var others []protocol.Warning
+	for _, w := range warnings {
+		if w.Code == protocol.WarningCodeDeprecated {
+			c.reportDeprecation(method, w)
+		} else {
+			others = append(others, w)
+		}
+	}
+	if len(others) > 0 && c.warningHandler != nil {
+		c.warningHandler(taskID, others)
+	}
+}
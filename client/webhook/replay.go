@@ -0,0 +1,114 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package webhook
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"trpc.group/trpc-go/trpc-a2a-go/auth"
+)
+
+const (
+	// defaultReplayMaxSkew is the tolerance for notification timestamps by
+	// default.
+	defaultReplayMaxSkew = 5 * time.Minute
+	// defaultReplayCacheSize is the maximum number of nonces remembered by
+	// default.
+	defaultReplayCacheSize = 10000
+)
+
+// errReplayCacheFull reports that a nonce cannot be remembered.
+var errReplayCacheFull = errors.New("too many notifications within the replay window")
+
+// WithReplayProtection makes the receiver reject notifications whose
+// timestamp is more than maxSkew away from the local time, 5 minutes if not
+// positive, or whose nonce was already received within that window. The nonce
+// and timestamp of signed notifications are the jti and iat claims of their
+// JWT; those of other notifications are the auth.HMACNonceHeader and
+// auth.HMACTimestampHeader headers, which are only as trustworthy as the token
+// authenticating them. At most cacheSize nonces are remembered, 10000 if not
+// positive: beyond, notifications are rejected with 503 Service Unavailable
+// until older nonces expire.
+func WithReplayProtection(maxSkew time.Duration, cacheSize int) Option {
+	return func(r *Receiver) {
+		if maxSkew <= 0 {
+			maxSkew = defaultReplayMaxSkew
+		}
+		if cacheSize <= 0 {
+			cacheSize = defaultReplayCacheSize
+		}
+		r.replays = &replayCache{maxSkew: maxSkew, size: cacheSize, seen: make(map[string]time.Time)}
+	}
+}
+
+// replayCache remembers the nonces received within the replay window.
+type replayCache struct {
+	maxSkew time.Duration
+	size    int
+
+	mu   sync.Mutex
+	seen map[string]time.Time // Nonces by expiry.
+}
+
+// check verifies that timestamp is within the window around now and that
+// nonce was not received before, remembering it.
+func (c *replayCache) check(nonce string, timestamp, now time.Time) error {
+	if nonce == "" {
+		return auth.ErrMissingSignature
+	}
+	if skew := now.Sub(timestamp); skew > c.maxSkew || skew < -c.maxSkew {
+		return auth.ErrStaleRequest
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.seen[nonce]; ok {
+		return auth.ErrReplayedRequest
+	}
+	if len(c.seen) >= c.size {
+		for n, expiry := range c.seen {
+			if now.After(expiry) {
+				delete(c.seen, n)
+			}
+		}
+		if len(c.seen) >= c.size {
+			return errReplayCacheFull
+		}
+	}
+	// Nonces are remembered past the window in both directions of the skew.
+	c.seen[nonce] = now.Add(2 * c.maxSkew)
+	return nil
+}
+
+// replayToken returns the nonce and timestamp of a notification.
+func (r *Receiver) replayToken(req *http.Request) (string, time.Time, error) {
+	if r.verifier == nil {
+		timestamp, err := strconv.ParseInt(req.Header.Get(auth.HMACTimestampHeader), 10, 64)
+		if err != nil {
+			return "", time.Time{}, auth.ErrMissingSignature
+		}
+		return req.Header.Get(auth.HMACNonceHeader), time.Unix(timestamp, 0), nil
+	}
+	// The token was verified by authenticate.
+	tokenString, _ := strings.CutPrefix(req.Header.Get("Authorization"), string(auth.TokenTypeBearer)+" ")
+	claims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(tokenString, claims); err != nil {
+		return "", time.Time{}, err
+	}
+	nonce, _ := claims["jti"].(string)
+	issuedAt, err := claims.GetIssuedAt()
+	if err != nil || issuedAt == nil {
+		return "", time.Time{}, auth.ErrMissingSignature
+	}
+	return nonce, issuedAt.Time, nil
+}
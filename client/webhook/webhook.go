@@ -0,0 +1,316 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+// Package webhook receives the push notifications of A2A agents: a Receiver is
+// an http.Handler, mounted on an existing server or listening on its own,
+// that authenticates the notifications, decodes them and surfaces them on a
+// channel.
+package webhook
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/auth"
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+const (
+	// notifyEventMethod is the JSON-RPC method of the push notifications of
+	// task events.
+	notifyEventMethod = "tasks/notifyEvent"
+	// defaultMaxBodySize is the maximum size of notifications by default.
+	defaultMaxBodySize = 10 << 20
+	// defaultBufferSize is the capacity of the notification channel by default.
+	defaultBufferSize = 64
+)
+
+// ErrReceiverClosed is returned by Listen once the receiver is closed.
+var ErrReceiverClosed = errors.New("webhook receiver closed")
+
+// Notification is a push notification received from an agent.
+type Notification struct {
+	// TaskID is the ID of the task.
+	TaskID string
+	// EventType is protocol.EventTaskStatusUpdate or
+	// protocol.EventTaskArtifactUpdate for event notifications, empty for task
+	// notifications.
+	EventType string
+	// Event is the event of event notifications.
+	Event protocol.TaskEvent
+	// Task is the task of task notifications, whose body is the task itself.
+	Task *protocol.Task
+}
+
+// Option configures a Receiver.
+type Option func(*Receiver)
+
+// WithJWKS makes the receiver verify the JWT signing each notification, in its
+// Authorization header, with the keys published by the agent at jwksURL.
+func WithJWKS(jwksURL string) Option {
+	return func(r *Receiver) {
+		r.verifier = auth.NewPushNotificationAuthenticator()
+		r.verifier.SetJWKSClient(jwksURL)
+	}
+}
+
+// WithToken makes the receiver require the bearer token of the push
+// notification configuration, as sent by agents not signing notifications.
+func WithToken(token string) Option {
+	return func(r *Receiver) {
+		r.token = token
+	}
+}
+
+// WithBufferSize sets the capacity of the notification channel, 64 by
+// default. Notifications are rejected with 503 Service Unavailable, for the
+// agent to retry them, while the channel is full until the request ends.
+func WithBufferSize(size int) Option {
+	return func(r *Receiver) {
+		if size >= 0 {
+			r.bufferSize = size
+		}
+	}
+}
+
+// WithMaxBodySize sets the maximum size of notifications, 10 MiB by default.
+func WithMaxBodySize(size int64) Option {
+	return func(r *Receiver) {
+		if size > 0 {
+			r.maxBodySize = size
+		}
+	}
+}
+
+// Receiver receives push notifications. It is safe for concurrent use.
+type Receiver struct {
+	verifier    *auth.PushNotificationAuthenticator
+	token       string
+	bufferSize  int
+	maxBodySize int64
+	replays     *replayCache
+
+	verifyMu      sync.Mutex // Serializes the JWKS fetches of verifications.
+	notifications chan Notification
+
+	mu     sync.RWMutex
+	closed bool
+	done   chan struct{}
+	server *http.Server
+}
+
+// NewReceiver creates a receiver. Without WithJWKS nor WithToken,
+// notifications are not authenticated.
+func NewReceiver(opts ...Option) *Receiver {
+	r := &Receiver{
+		bufferSize:  defaultBufferSize,
+		maxBodySize: defaultMaxBodySize,
+		done:        make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	r.notifications = make(chan Notification, r.bufferSize)
+	return r
+}
+
+// Notifications returns the channel of the notifications received, closed by
+// Close.
+func (r *Receiver) Notifications() <-chan Notification {
+	return r.notifications
+}
+
+// Listen starts serving the receiver on addr, such as "127.0.0.1:0", in the
+// background, and returns the URL to set in push notification configurations.
+func (r *Receiver) Listen(addr string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return "", ErrReceiverClosed
+	}
+	if r.server != nil {
+		return "", errors.New("webhook receiver already listening")
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return "", fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	r.server = &http.Server{Handler: r}
+	go func(server *http.Server) {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Errorf("Webhook receiver stopped: %v", err)
+		}
+	}(r.server)
+	return "http://" + listener.Addr().String(), nil
+}
+
+// Close stops the server started by Listen, if any, and closes the
+// notification channel once the requests in progress are done.
+func (r *Receiver) Close() error {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return nil
+	}
+	r.closed = true
+	close(r.done)
+	server := r.server
+	r.mu.Unlock()
+	var err error
+	if server != nil {
+		err = server.Shutdown(context.Background())
+	}
+	// Wait for the handlers delivering a notification.
+	r.mu.Lock()
+	close(r.notifications)
+	r.mu.Unlock()
+	return err
+}
+
+// ServeHTTP implements http.Handler.
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(req.Body, r.maxBodySize+1))
+	if err != nil {
+		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		return
+	}
+	if int64(len(body)) > r.maxBodySize {
+		http.Error(w, "Notification too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err := r.authenticate(req, body); err != nil {
+		log.Warnf("Rejected push notification: %v", err)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if err := r.checkReplay(req); err != nil {
+		log.Warnf("Rejected push notification: %v", err)
+		if errors.Is(err, errReplayCacheFull) {
+			http.Error(w, "Receiver unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	notification, err := decodeNotification(body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid notification: %v", err), http.StatusBadRequest)
+		return
+	}
+	if !r.deliver(req.Context(), notification) {
+		http.Error(w, "Receiver unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// authenticate verifies the signature or the token of a notification.
+func (r *Receiver) authenticate(req *http.Request, body []byte) error {
+	if r.verifier != nil {
+		r.verifyMu.Lock()
+		defer r.verifyMu.Unlock()
+		return r.verifier.VerifyPushNotification(req, body)
+	}
+	if r.token == "" {
+		return nil
+	}
+	token, ok := strings.CutPrefix(req.Header.Get("Authorization"), string(auth.TokenTypeBearer)+" ")
+	if !ok {
+		return auth.ErrMissingToken
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(r.token)) != 1 {
+		return auth.ErrInvalidToken
+	}
+	return nil
+}
+
+// checkReplay rejects stale and replayed notifications, if replay protection
+// is enabled.
+func (r *Receiver) checkReplay(req *http.Request) error {
+	if r.replays == nil {
+		return nil
+	}
+	nonce, timestamp, err := r.replayToken(req)
+	if err != nil {
+		return err
+	}
+	return r.replays.check(nonce, timestamp, time.Now())
+}
+
+// deliver sends notification on the channel, unless ctx is done or the
+// receiver is closed first.
+func (r *Receiver) deliver(ctx context.Context, notification Notification) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.closed {
+		return false
+	}
+	select {
+	case r.notifications <- notification:
+		return true
+	case <-ctx.Done():
+	case <-r.done:
+	}
+	return false
+}
+
+// decodeNotification decodes a tasks/notifyEvent JSON-RPC request or a task.
+func decodeNotification(body []byte) (Notification, error) {
+	var request struct {
+		Method string `json:"method"`
+		Params struct {
+			ID        string          `json:"id"`
+			EventType string          `json:"eventType"`
+			Event     json.RawMessage `json:"event"`
+		} `json:"params"`
+	}
+	if err := json.Unmarshal(body, &request); err != nil {
+		return Notification{}, err
+	}
+	if request.Method == "" {
+		var task protocol.Task
+		if err := json.Unmarshal(body, &task); err != nil {
+			return Notification{}, err
+		}
+		if task.ID == "" {
+			return Notification{}, errors.New("missing task ID")
+		}
+		return Notification{TaskID: task.ID, Task: &task}, nil
+	}
+	if request.Method != notifyEventMethod {
+		return Notification{}, fmt.Errorf("unsupported method %q", request.Method)
+	}
+	// Decode the event like the events of tasks/pollEvents.
+	raw, err := json.Marshal(map[string]interface{}{
+		"type":  request.Params.EventType,
+		"event": request.Params.Event,
+	})
+	if err != nil {
+		return Notification{}, err
+	}
+	var polled protocol.PolledEvent
+	if err := json.Unmarshal(raw, &polled); err != nil {
+		return Notification{}, err
+	}
+	if request.Params.ID == "" {
+		return Notification{}, errors.New("missing task ID")
+	}
+	return Notification{TaskID: request.Params.ID, EventType: polled.Type, Event: polled.Event}, nil
+}
@@ -0,0 +1,179 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/auth"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// next returns the next notification of r.
+func next(t *testing.T, r *Receiver) Notification {
+	t.Helper()
+	select {
+	case notification := <-r.Notifications():
+		return notification
+	case <-time.After(5 * time.Second):
+		t.Fatal("no notification received")
+		return Notification{}
+	}
+}
+
+// completer completes the tasks it processes.
+type completer struct{}
+
+func (completer) Process(ctx context.Context, taskID string, msg protocol.Message, handle taskmanager.TaskHandle) error {
+	return handle.UpdateStatus(protocol.TaskStateCompleted, nil)
+}
+
+func TestReceiver_JWKS(t *testing.T) {
+	agentAuth := auth.NewPushNotificationAuthenticator()
+	require.NoError(t, agentAuth.GenerateKeyPair())
+	jwks := httptest.NewServer(http.HandlerFunc(agentAuth.HandleJWKS))
+	defer jwks.Close()
+	receiver := NewReceiver(WithJWKS(jwks.URL))
+	defer receiver.Close()
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	post := func(body []byte, signed []byte) int {
+		req, err := http.NewRequest(http.MethodPost, server.URL, bytes.NewReader(body))
+		require.NoError(t, err)
+		header, err := agentAuth.CreateAuthorizationHeader(signed)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", header)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	event, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  "tasks/notifyEvent",
+		"params": map[string]interface{}{
+			"id":        "task-1",
+			"eventType": protocol.EventTaskStatusUpdate,
+			"event": protocol.TaskStatusUpdateEvent{
+				ID: "task-1", Status: protocol.TaskStatus{State: protocol.TaskStateCompleted}, Final: true,
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNoContent, post(event, event))
+	notification := next(t, receiver)
+	assert.Equal(t, "task-1", notification.TaskID)
+	assert.Equal(t, protocol.EventTaskStatusUpdate, notification.EventType)
+	status, ok := notification.Event.(protocol.TaskStatusUpdateEvent)
+	require.True(t, ok)
+	assert.Equal(t, protocol.TaskStateCompleted, status.Status.State)
+	assert.Nil(t, notification.Task)
+
+	task, err := json.Marshal(protocol.Task{ID: "task-2", Status: protocol.TaskStatus{State: protocol.TaskStateFailed}})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNoContent, post(task, task))
+	notification = next(t, receiver)
+	assert.Equal(t, "task-2", notification.TaskID)
+	require.NotNil(t, notification.Task)
+	assert.Equal(t, protocol.TaskStateFailed, notification.Task.Status.State)
+
+	assert.Equal(t, http.StatusUnauthorized, post(task, event), "the signature covers the body")
+	assert.Equal(t, http.StatusBadRequest, post([]byte(`{"method":"tasks/other"}`), []byte(`{"method":"tasks/other"}`)))
+}
+
+func TestReceiver_PushDelivery(t *testing.T) {
+	receiver := NewReceiver(WithToken("secret"), WithBufferSize(0), WithReplayProtection(0, 0))
+	url, err := receiver.Listen("127.0.0.1:0")
+	require.NoError(t, err)
+
+	tm, err := taskmanager.NewMemoryTaskManager(completer{}, taskmanager.WithPushDelivery(taskmanager.PushDeliveryConfig{
+		RetryInterval: 10 * time.Millisecond,
+	}))
+	require.NoError(t, err)
+	defer tm.Close()
+	ctx := context.Background()
+	msg := protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")})
+	// Tasks have to exist before their push configuration is set.
+	tm.Tasks["task-1"] = protocol.NewTask("task-1", nil)
+	config := protocol.PushNotificationConfig{URL: url, Token: "secret"}
+	_, err = tm.OnPushNotificationSet(ctx, protocol.TaskPushNotificationConfig{ID: "task-1", PushNotificationConfig: config})
+	require.NoError(t, err)
+	_, err = tm.OnSendTask(ctx, protocol.SendTaskParams{ID: "task-1", Message: msg})
+	require.NoError(t, err)
+
+	notification := next(t, receiver)
+	assert.Equal(t, "task-1", notification.TaskID)
+	assert.True(t, notification.Event.IsFinal())
+
+	resp, err := http.Post(url, "application/json", bytes.NewReader([]byte(`{"id":"task-1"}`)))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode, "the token is required")
+
+	require.NoError(t, receiver.Close())
+	_, ok := <-receiver.Notifications()
+	assert.False(t, ok)
+	_, err = receiver.Listen("127.0.0.1:0")
+	assert.ErrorIs(t, err, ErrReceiverClosed)
+}
+
+func TestReceiver_ReplayProtection(t *testing.T) {
+	agentAuth := auth.NewPushNotificationAuthenticator()
+	require.NoError(t, agentAuth.GenerateKeyPair())
+	jwks := httptest.NewServer(http.HandlerFunc(agentAuth.HandleJWKS))
+	defer jwks.Close()
+	task := []byte(`{"id":"task-1","status":{"state":"completed"}}`)
+
+	t.Run("signed notifications", func(t *testing.T) {
+		receiver := NewReceiver(WithJWKS(jwks.URL), WithReplayProtection(0, 0))
+		defer receiver.Close()
+		header, err := agentAuth.CreateAuthorizationHeader(task)
+		require.NoError(t, err)
+		post := func() int {
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(task))
+			req.Header.Set("Authorization", header)
+			w := httptest.NewRecorder()
+			receiver.ServeHTTP(w, req)
+			return w.Code
+		}
+		assert.Equal(t, http.StatusNoContent, post())
+		assert.Equal(t, http.StatusUnauthorized, post(), "a captured request cannot be replayed")
+	})
+
+	t.Run("token notifications", func(t *testing.T) {
+		receiver := NewReceiver(WithToken("secret"), WithReplayProtection(time.Minute, 1))
+		defer receiver.Close()
+		post := func(nonce string, timestamp time.Time) int {
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(task))
+			req.Header.Set("Authorization", "Bearer secret")
+			req.Header.Set(auth.HMACNonceHeader, nonce)
+			req.Header.Set(auth.HMACTimestampHeader, strconv.FormatInt(timestamp.Unix(), 10))
+			w := httptest.NewRecorder()
+			receiver.ServeHTTP(w, req)
+			return w.Code
+		}
+		now := time.Now()
+		assert.Equal(t, http.StatusUnauthorized, post("n1", now.Add(-2*time.Minute)), "stale")
+		assert.Equal(t, http.StatusUnauthorized, post("", now), "missing nonce")
+		assert.Equal(t, http.StatusNoContent, post("n1", now))
+		assert.Equal(t, http.StatusUnauthorized, post("n1", now), "replayed")
+		assert.Equal(t, http.StatusServiceUnavailable, post("n2", now), "the cache is full")
+	})
+}
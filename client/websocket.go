@@ -0,0 +1,260 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"trpc.group/trpc-go/trpc-a2a-go/codec"
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/internal/websocket"
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+)
+
+// ErrSessionClosed is returned by the calls of a WebSocketSession that ended.
+var ErrSessionClosed = errors.New("websocket session closed")
+
+// NotificationHandler handles the params of a JSON-RPC notification sent by
+// the agent.
+type NotificationHandler func(params json.RawMessage)
+
+// WebSocketSession is a JSON-RPC session with the agent over a WebSocket
+// connection, opened with DialWebSocket on an agent serving WebSocket. Calls
+// and notifications can be sent concurrently. The notifications of the agent
+// are dispatched to the handlers registered with HandleNotification, one at a
+// time in the order they are received, so handlers must not wait for the
+// response of a call of the session.
+type WebSocketSession struct {
+	conn  *websocket.Conn
+	codec codec.Codec
+
+	mu       sync.Mutex
+	nextID   uint64
+	pending  map[string]chan *jsonrpc.RawResponse
+	handlers map[string]NotificationHandler
+	done     chan struct{}
+	err      error // Why the session ended, set before done is closed.
+}
+
+// wsMessage is a message received by a WebSocketSession: a response or a
+// notification.
+type wsMessage struct {
+	jsonrpc.RawResponse
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+// DialWebSocket opens a JSON-RPC session with the agent over WebSocket. The
+// handshake carries the metadata of the client and of ctx, which bounds the
+// handshake only.
+func (c *A2AClient) DialWebSocket(ctx context.Context, opts ...CallOption) (*WebSocketSession, error) {
+	ctx = withCallOptions(ctx, opts)
+	target, err := c.acquire(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("a2aClient.DialWebSocket: %w", err)
+	}
+	conn, err := c.dialWebSocket(ctx, target.url.String())
+	c.balancer.release(target, err != nil && ctx.Err() == nil)
+	if err != nil {
+		return nil, fmt.Errorf("a2aClient.DialWebSocket: %w", err)
+	}
+	session := &WebSocketSession{
+		conn:     conn,
+		codec:    c.codec,
+		pending:  make(map[string]chan *jsonrpc.RawResponse),
+		handlers: make(map[string]NotificationHandler),
+		done:     make(chan struct{}),
+	}
+	go session.readLoop()
+	return session, nil
+}
+
+// dialWebSocket performs the opening handshake with targetURL.
+func (c *A2AClient) dialWebSocket(ctx context.Context, targetURL string) (*websocket.Conn, error) {
+	if timeout := c.requestTimeout(ctx); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, targetURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create http request: %w", err)
+	}
+	key, err := websocket.SetHandshakeHeaders(req.Header)
+	if err != nil {
+		return nil, err
+	}
+	c.setMetadataHeaders(ctx, req.Header)
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	// The connection is returned as the response body only without a client
+	// timeout, which the context enforces instead.
+	httpClient := *c.httpClient
+	httpClient.Timeout = 0
+	resp, err := c.httpReqHandler(ctx, &httpClient, req)
+	if err != nil {
+		return nil, fmt.Errorf("http request failed: %w", err)
+	}
+	return websocket.NewClientConn(resp, key)
+}
+
+// HandleNotification registers handler for the notifications of method sent
+// by the agent, replacing the handler registered before, if any.
+// Notifications without handler are ignored.
+func (s *WebSocketSession) HandleNotification(method string, handler NotificationHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[method] = handler
+}
+
+// Call calls method with params over the session and decodes the result into
+// result unless it is nil, like A2AClient.Call.
+func (s *WebSocketSession) Call(ctx context.Context, method string, params, result interface{}) error {
+	s.mu.Lock()
+	if s.err != nil {
+		s.mu.Unlock()
+		return fmt.Errorf("webSocketSession.Call: %w", s.err)
+	}
+	s.nextID++
+	id := strconv.FormatUint(s.nextID, 10)
+	responses := make(chan *jsonrpc.RawResponse, 1)
+	s.pending[id] = responses
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.pending, id)
+		s.mu.Unlock()
+	}()
+
+	if err := s.send(jsonrpc.NewRequest(method, id), params); err != nil {
+		return fmt.Errorf("webSocketSession.Call: %w", err)
+	}
+	select {
+	case response := <-responses:
+		if response.Error != nil {
+			return fmt.Errorf("webSocketSession.Call: %w", response.Error)
+		}
+		if result == nil {
+			return nil
+		}
+		if err := s.codec.Unmarshal(response.Result, result); err != nil {
+			return fmt.Errorf("webSocketSession.Call: failed to unmarshal rpc result: %w", err)
+		}
+		return nil
+	case <-s.done:
+		return fmt.Errorf("webSocketSession.Call: %w", s.Err())
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Notify sends a notification of method with params over the session.
+func (s *WebSocketSession) Notify(method string, params interface{}) error {
+	if err := s.send(jsonrpc.NewNotification(method), params); err != nil {
+		return fmt.Errorf("webSocketSession.Notify: %w", err)
+	}
+	return nil
+}
+
+// send marshals and sends request with params.
+func (s *WebSocketSession) send(request *jsonrpc.Request, params interface{}) error {
+	if params != nil {
+		paramsBytes, err := s.codec.Marshal(params)
+		if err != nil {
+			return fmt.Errorf("failed to marshal params: %w", err)
+		}
+		request.Params = paramsBytes
+	}
+	data, err := s.codec.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+	if err := s.conn.WriteMessage(data); err != nil {
+		if errors.Is(err, websocket.ErrClosed) {
+			return ErrSessionClosed
+		}
+		return err
+	}
+	return nil
+}
+
+// readLoop dispatches the messages received until the connection ends.
+func (s *WebSocketSession) readLoop() {
+	for {
+		data, err := s.conn.ReadMessage()
+		if err != nil {
+			s.end(err)
+			return
+		}
+		var message wsMessage
+		if err := s.codec.Unmarshal(data, &message); err != nil {
+			log.Warnf("Ignoring invalid WebSocket message: %v", err)
+			continue
+		}
+		if message.Method != "" {
+			s.mu.Lock()
+			handler := s.handlers[message.Method]
+			s.mu.Unlock()
+			if handler == nil {
+				log.Debugf("Ignoring notification %s without handler", message.Method)
+				continue
+			}
+			handler(message.Params)
+			continue
+		}
+		id := fmt.Sprint(message.ID)
+		s.mu.Lock()
+		responses, ok := s.pending[id]
+		s.mu.Unlock()
+		if !ok {
+			log.Debugf("Ignoring response to unknown request %v", message.ID)
+			continue
+		}
+		select {
+		case responses <- &message.RawResponse:
+		default:
+			// The call already got a response with the same ID.
+			log.Debugf("Ignoring duplicate response to request %v", message.ID)
+		}
+	}
+}
+
+// end ends the session after err.
+func (s *WebSocketSession) end(err error) {
+	log.Debugf("WebSocket session ended: %v", err)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err == nil {
+		s.err = ErrSessionClosed
+		close(s.done)
+	}
+}
+
+// Done returns a channel closed when the session ends, after the agent or
+// Close closed the connection.
+func (s *WebSocketSession) Done() <-chan struct{} {
+	return s.done
+}
+
+// Err returns ErrSessionClosed once the session ended, or nil.
+func (s *WebSocketSession) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Close closes the session.
+func (s *WebSocketSession) Close() error {
+	return s.conn.Close()
+}
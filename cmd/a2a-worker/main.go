@@ -0,0 +1,62 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+// Package main implements a2a-worker, the worker tier of an agent whose API
+// tier enqueues the tasks, see config.QueueConfig. It reads the configuration
+// file of the API servers and processes the tasks by running a command:
+//
+//	a2a-worker -config agent.yaml -- python3 agent.py
+//
+// The command receives the text of the message of the task on its standard
+// input, and the task ID in the A2A_TASK_ID environment variable. Its
+// standard output becomes the text artifact of the completed task; the task
+// fails if the command exits with an error. Agents written in Go build their
+// worker with config.BuildWorker and their own processor instead.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"trpc.group/trpc-go/trpc-a2a-go/config"
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager/redis"
+)
+
+func main() {
+	configPath := flag.String("config", "a2a.yaml", "path of the configuration file")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [-config file] -- command [args...]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	processor := &commandProcessor{command: flag.Args()}
+	worker, err := config.BuildWorker(cfg, processor, config.WithQueue("redis", redis.QueueFactory))
+	if err != nil {
+		log.Fatalf("Failed to build worker: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	log.Infof("Worker processing the tasks of the %s queue with %v", cfg.Queue.Type, flag.Args())
+	if err := worker.Run(ctx); err != nil {
+		log.Fatalf("Worker failed: %v", err)
+	}
+	log.Infof("Worker stopped")
+}
@@ -0,0 +1,61 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// commandProcessor processes the tasks by running a command.
+type commandProcessor struct {
+	command []string
+}
+
+// Process implements taskmanager.TaskProcessor.
+func (p *commandProcessor) Process(
+	ctx context.Context,
+	taskID string,
+	message protocol.Message,
+	handle taskmanager.TaskHandle,
+) error {
+	var input strings.Builder
+	for _, part := range message.Parts {
+		switch text := part.(type) {
+		case protocol.TextPart:
+			input.WriteString(text.Text)
+		case *protocol.TextPart:
+			input.WriteString(text.Text)
+		}
+	}
+	cmd := exec.CommandContext(ctx, p.command[0], p.command[1:]...)
+	cmd.Env = append(os.Environ(), "A2A_TASK_ID="+taskID)
+	cmd.Stdin = strings.NewReader(input.String())
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		if detail := strings.TrimSpace(stderr.String()); detail != "" {
+			return fmt.Errorf("%s: %w: %s", p.command[0], err, detail)
+		}
+		return fmt.Errorf("%s: %w", p.command[0], err)
+	}
+	lastChunk := true
+	if err := handle.AddArtifact(protocol.Artifact{
+		Parts:     []protocol.Part{protocol.NewTextPart(stdout.String())},
+		LastChunk: &lastChunk,
+	}); err != nil {
+		return err
+	}
+	return handle.UpdateStatus(protocol.TaskStateCompleted, nil)
+}
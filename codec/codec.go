@@ -0,0 +1,86 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+// Package codec defines the JSON codec abstraction used on the hot paths of the
+// client, the server and the SSE writer, allowing encoding/json to be replaced
+// by a faster implementation.
+//
+// A json-iterator based codec is provided by the codec/jsoniter module; other
+// libraries such as sonic can be plugged in by implementing Codec.
+package codec
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// Codec marshals and unmarshals JSON payloads.
+// Implementations must be safe for concurrent use and must honor the
+// json.Marshaler and json.Unmarshaler interfaces implemented by protocol types.
+type Codec interface {
+	// Name returns a short identifier of the codec, e.g. "encoding/json".
+	Name() string
+	// Marshal returns the JSON encoding of v.
+	Marshal(v interface{}) ([]byte, error)
+	// Unmarshal parses the JSON-encoded data and stores the result in v.
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// StreamDecoder is implemented by codecs able to decode a value from a reader
+// rather than from a byte slice. The server uses it to parse request bodies
+// when available, sparing the copy of the body read before decoding it. It
+// does not bound the memory used: decoders may buffer the whole value, as
+// encoding/json does.
+type StreamDecoder interface {
+	// Decode parses the single JSON value read from r and stores the result
+	// in v. Data after the value is an error.
+	Decode(r io.Reader, v interface{}) error
+}
+
+// Default is the codec used when none is configured. It is backed by encoding/json.
+var Default Codec = stdCodec{}
+
+// stdCodec implements Codec using the standard library.
+type stdCodec struct{}
+
+// Name implements Codec.
+func (stdCodec) Name() string {
+	return "encoding/json"
+}
+
+// Marshal implements Codec.
+func (stdCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal implements Codec.
+func (stdCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// Decode implements StreamDecoder.
+func (stdCodec) Decode(r io.Reader, v interface{}) error {
+	dec := json.NewDecoder(r)
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		if err == nil {
+			err = errors.New("invalid data after top-level value")
+		}
+		return err
+	}
+	return nil
+}
+
+// OrDefault returns c, or Default if c is nil.
+func OrDefault(c Codec) Codec {
+	if c == nil {
+		return Default
+	}
+	return c
+}
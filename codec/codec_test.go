@@ -0,0 +1,60 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package codec
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+func TestDefaultCodec(t *testing.T) {
+	assert.Equal(t, "encoding/json", Default.Name())
+	msg := protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")})
+
+	data, err := Default.Marshal(msg)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"role":"user","parts":[{"type":"text","text":"hi"}]}`, string(data))
+
+	var decoded protocol.Message
+	require.NoError(t, Default.Unmarshal(data, &decoded))
+	assert.Equal(t, msg, decoded)
+}
+
+func TestOrDefault(t *testing.T) {
+	assert.Equal(t, Default, OrDefault(nil))
+	custom := stdCodec{}
+	assert.Equal(t, Codec(custom), OrDefault(custom))
+}
+
+func BenchmarkDefaultMarshalArtifactEvent(b *testing.B) {
+	event := protocol.TaskArtifactUpdateEvent{
+		ID: "task-1",
+		Artifact: protocol.Artifact{
+			Parts: []protocol.Part{protocol.NewTextPart(strings.Repeat("token ", 4096))},
+		},
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := Default.Marshal(event); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestDefaultCodec_Decode(t *testing.T) {
+	decoder, ok := Default.(StreamDecoder)
+	require.True(t, ok)
+	var v map[string]int
+	require.NoError(t, decoder.Decode(strings.NewReader(`{"a":1}`), &v))
+	assert.Equal(t, map[string]int{"a": 1}, v)
+	assert.Error(t, decoder.Decode(strings.NewReader(`{"a":1} {}`), &v), "trailing data")
+}
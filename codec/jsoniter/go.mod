@@ -0,0 +1,21 @@
+module trpc.group/trpc-go/trpc-a2a-go/codec/jsoniter
+
+go 1.23.0
+
+toolchain go1.23.7
+
+replace trpc.group/trpc-go/trpc-a2a-go => ../../
+
+require (
+	github.com/json-iterator/go v1.1.12
+	github.com/stretchr/testify v1.10.0
+	trpc.group/trpc-go/trpc-a2a-go v0.0.0-00010101000000-000000000000
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/modern-go/concurrent v0.0.0-20180228061459-e0a39a4cb421 // indirect
+	github.com/modern-go/reflect2 v1.0.2 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
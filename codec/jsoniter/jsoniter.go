@@ -0,0 +1,48 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+// Package jsoniter provides a codec.Codec backed by github.com/json-iterator/go.
+// It lives in its own module so the dependency is only pulled in when used.
+package jsoniter
+
+import (
+	jsoniterator "github.com/json-iterator/go"
+
+	"trpc.group/trpc-go/trpc-a2a-go/codec"
+)
+
+// Codec implements codec.Codec using json-iterator configured to be
+// compatible with encoding/json, so custom (un)marshalers on protocol types keep working.
+type Codec struct {
+	api jsoniterator.API
+}
+
+var _ codec.Codec = (*Codec)(nil)
+
+// New creates a json-iterator codec compatible with the standard library.
+func New() *Codec {
+	return &Codec{api: jsoniterator.ConfigCompatibleWithStandardLibrary}
+}
+
+// NewWithConfig creates a json-iterator codec from a custom configuration.
+func NewWithConfig(config jsoniterator.Config) *Codec {
+	return &Codec{api: config.Froze()}
+}
+
+// Name implements codec.Codec.
+func (c *Codec) Name() string {
+	return "jsoniter"
+}
+
+// Marshal implements codec.Codec.
+func (c *Codec) Marshal(v interface{}) ([]byte, error) {
+	return c.api.Marshal(v)
+}
+
+// Unmarshal implements codec.Codec.
+func (c *Codec) Unmarshal(data []byte, v interface{}) error {
+	return c.api.Unmarshal(data, v)
+}
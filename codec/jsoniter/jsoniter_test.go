@@ -0,0 +1,96 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package jsoniter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/codec"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// largeArtifactEvent builds an artifact event resembling a large streamed chunk.
+func largeArtifactEvent() protocol.TaskArtifactUpdateEvent {
+	name := "report"
+	parts := make([]protocol.Part, 0, 16)
+	for i := 0; i < 16; i++ {
+		parts = append(parts, protocol.NewTextPart(strings.Repeat("token ", 512)))
+	}
+	return protocol.TaskArtifactUpdateEvent{
+		ID:       "task-1",
+		Artifact: protocol.Artifact{Name: &name, Parts: parts},
+	}
+}
+
+func TestCodec_CompatibleWithStandardLibrary(t *testing.T) {
+	c := New()
+	assert.Equal(t, "jsoniter", c.Name())
+	event := largeArtifactEvent()
+
+	data, err := c.Marshal(event)
+	require.NoError(t, err)
+	stdData, err := codec.Default.Marshal(event)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(stdData), string(data))
+
+	// Unmarshal must go through protocol.Artifact's custom UnmarshalJSON.
+	var decoded protocol.TaskArtifactUpdateEvent
+	require.NoError(t, c.Unmarshal(data, &decoded))
+	require.Len(t, decoded.Artifact.Parts, 16)
+	_, ok := decoded.Artifact.Parts[0].(protocol.TextPart)
+	assert.True(t, ok)
+}
+
+func benchmarkMarshal(b *testing.B, c codec.Codec) {
+	event := largeArtifactEvent()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := c.Marshal(event); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMarshal_Std(b *testing.B) {
+	benchmarkMarshal(b, codec.Default)
+}
+
+func BenchmarkMarshal_Jsoniter(b *testing.B) {
+	benchmarkMarshal(b, New())
+}
+
+func benchmarkUnmarshalTask(b *testing.B, c codec.Codec) {
+	task := protocol.Task{ID: "task-1", Metadata: map[string]interface{}{}}
+	for i := 0; i < 64; i++ {
+		task.Metadata[strings.Repeat("k", i+1)] = strings.Repeat("v", 256)
+	}
+	data, err := codec.Default.Marshal(task)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var out protocol.Task
+		if err := c.Unmarshal(data, &out); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnmarshalTask_Std(b *testing.B) {
+	benchmarkUnmarshalTask(b, codec.Default)
+}
+
+func BenchmarkUnmarshalTask_Jsoniter(b *testing.B) {
+	benchmarkUnmarshalTask(b, New())
+}
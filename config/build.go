@@ -0,0 +1,405 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/auth"
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/redact"
+	"trpc.group/trpc-go/trpc-a2a-go/server"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// DefaultAddress is the address the server listens on if none is configured.
+const DefaultAddress = ":8080"
+
+// TaskStoreMemory is the type of the built-in in-memory task store.
+const TaskStoreMemory = "memory"
+
+// TaskStoreFactory creates the task manager of a task store type from its
+// configuration, running tasks with processor.
+type TaskStoreFactory func(
+	cfg TaskStoreConfig,
+	processor taskmanager.TaskProcessor,
+) (taskmanager.TaskManager, error)
+
+// QueueFactory creates the work queue and event bus of a queue type from its
+// configuration.
+type QueueFactory func(cfg QueueConfig) (taskmanager.WorkQueue, taskmanager.EventBus, error)
+
+// Server is an A2A server built from a configuration, with the address it
+// listens on.
+type Server struct {
+	*server.A2AServer
+	// Address is the configured listen address.
+	Address string
+	// TaskManager is the task manager of the configured task store.
+	TaskManager taskmanager.TaskManager
+	// Logger is the logger set with WithLogger, or log.Default, redacting its
+	// messages if configured to. Build does not install it as log.Default.
+	Logger log.Logger
+
+	config *Config
+	// queue is the configured work queue, if any.
+	queue taskmanager.WorkQueue
+}
+
+// ListenAndServe starts the server on its configured address. It blocks
+// until the server is stopped via Stop or an error occurs.
+func (s *Server) ListenAndServe() error {
+	return s.Start(s.Address)
+}
+
+// Stop stops the server gracefully, then closes the configured work queue if
+// it is an io.Closer.
+func (s *Server) Stop(ctx context.Context) error {
+	err := s.A2AServer.Stop(ctx)
+	if closer, ok := s.queue.(io.Closer); ok {
+		if closeErr := closer.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// Build builds the server described by cfg, running tasks with processor.
+// If a queue is configured, the server enqueues the tasks for the workers
+// built with BuildWorker instead, and processor may be nil.
+func Build(cfg *Config, processor taskmanager.TaskProcessor, opts ...Option) (*Server, error) {
+	b := newBuilder(opts)
+	var queue taskmanager.WorkQueue
+	if cfg.Queue != nil {
+		var bus taskmanager.EventBus
+		var err error
+		if queue, bus, err = b.queue(cfg); err != nil {
+			return nil, err
+		}
+		processor = taskmanager.NewQueueProcessor(queue, bus)
+	}
+	if processor == nil {
+		return nil, fmt.Errorf("config: processor is required")
+	}
+	serverOpts, err := serverOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+	redactor, err := b.redactor(cfg)
+	if err != nil {
+		return nil, err
+	}
+	logger := b.logger
+	if logger == nil {
+		logger = log.Default
+	}
+	if redactor != nil {
+		// Redacting what the processor reports covers every task store.
+		processor = redactor.Processor(processor)
+		if cfg.Redaction != nil && cfg.Redaction.Logs {
+			logger = redactor.Logger(logger)
+		}
+	}
+	tm, err := b.taskManager(cfg, processor)
+	if err != nil {
+		return nil, err
+	}
+	a2aServer, err := server.NewA2AServer(cfg.AgentCard, tm, append(serverOpts, b.serverOpts...)...)
+	if err != nil {
+		return nil, err
+	}
+	address := cfg.Address
+	if address == "" {
+		address = DefaultAddress
+	}
+	return &Server{
+		A2AServer:   a2aServer,
+		Address:     address,
+		TaskManager: tm,
+		Logger:      logger,
+		config:      cfg,
+		queue:       queue,
+	}, nil
+}
+
+// BuildWorker builds the worker described by cfg, processing the tasks
+// enqueued by the servers built from the same configuration with processor.
+func BuildWorker(cfg *Config, processor taskmanager.TaskProcessor, opts ...Option) (*taskmanager.Worker, error) {
+	if cfg.Queue == nil {
+		return nil, fmt.Errorf("config: a worker requires a queue")
+	}
+	queue, bus, err := newBuilder(opts).queue(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return taskmanager.NewWorker(queue, bus, processor, taskmanager.WorkerConfig{
+		Concurrency: cfg.Queue.Concurrency,
+		Heartbeat:   time.Duration(cfg.Queue.Heartbeat),
+		RetryDelay:  time.Duration(cfg.Queue.RetryDelay),
+	})
+}
+
+// LoadAndBuild loads the configuration file at path and builds its server.
+func LoadAndBuild(path string, processor taskmanager.TaskProcessor, opts ...Option) (*Server, error) {
+	cfg, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	return Build(cfg, processor, opts...)
+}
+
+// builder holds the options of Build.
+type builder struct {
+	stores     map[string]TaskStoreFactory
+	queues     map[string]QueueFactory
+	tokenizer  taskmanager.Tokenizer
+	detectors  []redact.Detector
+	logger     log.Logger
+	serverOpts []server.Option
+}
+
+// newBuilder returns the builder configured by opts.
+func newBuilder(opts []Option) *builder {
+	b := &builder{stores: map[string]TaskStoreFactory{}, queues: map[string]QueueFactory{}}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// queue creates the work queue and event bus of the configured queue.
+func (b *builder) queue(cfg *Config) (taskmanager.WorkQueue, taskmanager.EventBus, error) {
+	factory, ok := b.queues[cfg.Queue.Type]
+	if !ok {
+		return nil, nil, fmt.Errorf("config: unknown queue type %q", cfg.Queue.Type)
+	}
+	queue, bus, err := factory(*cfg.Queue)
+	if err != nil {
+		return nil, nil, fmt.Errorf("config: failed to create %s queue: %w", cfg.Queue.Type, err)
+	}
+	return queue, bus, nil
+}
+
+// taskManager creates the task manager of the configured task store.
+func (b *builder) taskManager(
+	cfg *Config,
+	processor taskmanager.TaskProcessor,
+) (taskmanager.TaskManager, error) {
+	storeType := cfg.TaskStore.Type
+	if storeType == "" {
+		storeType = TaskStoreMemory
+	}
+	// Store factories get the recovery policy with the configuration of the
+	// store, once validated.
+	if cfg.TaskStore.Recovery != "" {
+		if _, err := taskmanager.ParseRecoveryPolicy(cfg.TaskStore.Recovery); err != nil {
+			return nil, fmt.Errorf("config: %w", err)
+		}
+	}
+	if factory, ok := b.stores[storeType]; ok {
+		tm, err := factory(cfg.TaskStore, processor)
+		if err != nil {
+			return nil, fmt.Errorf("config: failed to create %s task store: %w", storeType, err)
+		}
+		return tm, nil
+	}
+	if storeType != TaskStoreMemory {
+		return nil, fmt.Errorf("config: unknown task store type %q", storeType)
+	}
+	if cfg.TaskStore.Recovery != "" {
+		// The memory store starts empty: there is nothing to recover.
+		return nil, fmt.Errorf("config: taskStore.recovery requires a persistent task store, not %s", storeType)
+	}
+	subscriptionLimits, err := cfg.Limits.subscriptionLimits()
+	if err != nil {
+		return nil, err
+	}
+	slowConsumers, err := cfg.Limits.slowConsumerConfig()
+	if err != nil {
+		return nil, err
+	}
+	memoryOpts := []taskmanager.MemoryTaskManagerOption{
+		taskmanager.WithSubscriptionLimits(subscriptionLimits),
+		taskmanager.WithSlowConsumerPolicy(slowConsumers),
+	}
+	if limits := cfg.Limits; limits.MaxTaskTokens > 0 {
+		tokenizer := b.tokenizer
+		if tokenizer == nil {
+			tokenizer = taskmanager.TokenizerFunc(countWords)
+		}
+		memoryOpts = append(memoryOpts, taskmanager.WithTokenAccounting(taskmanager.TokenAccounting{
+			Tokenizer:     tokenizer,
+			MaxTaskTokens: limits.MaxTaskTokens,
+		}))
+	}
+	return taskmanager.NewMemoryTaskManager(processor, memoryOpts...)
+}
+
+// redactor returns the configured redactor, or nil if redaction is not
+// configured.
+func (b *builder) redactor(cfg *Config) (*redact.Redactor, error) {
+	if cfg.Redaction == nil && len(b.detectors) == 0 {
+		return nil, nil
+	}
+	detectors := append([]redact.Detector(nil), b.detectors...)
+	if rc := cfg.Redaction; rc != nil {
+		for _, name := range rc.Detectors {
+			detector, ok := redact.Builtin(name)
+			if !ok {
+				return nil, fmt.Errorf("config: unknown redaction detector %q", name)
+			}
+			detectors = append(detectors, detector)
+		}
+		kinds := make([]string, 0, len(rc.Patterns))
+		for kind := range rc.Patterns {
+			kinds = append(kinds, kind)
+		}
+		sort.Strings(kinds)
+		for _, kind := range kinds {
+			re, err := regexp.Compile(rc.Patterns[kind])
+			if err != nil {
+				return nil, fmt.Errorf("config: invalid redaction pattern of %q: %w", kind, err)
+			}
+			detectors = append(detectors, redact.Regexp(kind, re))
+		}
+	}
+	return redact.New(detectors...), nil
+}
+
+// serverOptions returns the server options configured by cfg.
+func serverOptions(cfg *Config) ([]server.Option, error) {
+	var opts []server.Option
+	if cfg.TLS != nil {
+		if cfg.TLS.CertFile == "" || cfg.TLS.KeyFile == "" {
+			return nil, fmt.Errorf("config: tls requires certFile and keyFile")
+		}
+		opts = append(opts, server.WithTLS(cfg.TLS.CertFile, cfg.TLS.KeyFile))
+	}
+	if cfg.CORS != nil {
+		opts = append(opts, server.WithCORSEnabled(*cfg.CORS))
+	}
+	if cfg.JSONRPCPath != "" {
+		opts = append(opts, server.WithJSONRPCEndpoint(cfg.JSONRPCPath))
+	}
+	if cfg.ReadTimeout > 0 {
+		opts = append(opts, server.WithReadTimeout(time.Duration(cfg.ReadTimeout)))
+	}
+	if cfg.WriteTimeout > 0 {
+		opts = append(opts, server.WithWriteTimeout(time.Duration(cfg.WriteTimeout)))
+	}
+	if cfg.IdleTimeout > 0 {
+		opts = append(opts, server.WithIdleTimeout(time.Duration(cfg.IdleTimeout)))
+	}
+	if cfg.ReadinessPath != "" {
+		opts = append(opts, server.WithReadinessEndpoint(cfg.ReadinessPath))
+	}
+	if cfg.StartupSelfTest {
+		opts = append(opts, server.WithStartupSelfTest())
+	}
+	if cfg.Limits.MinDeadlineBudget > 0 {
+		opts = append(opts, server.WithMinDeadlineBudget(time.Duration(cfg.Limits.MinDeadlineBudget)))
+	}
+	if cfg.Limits.SSEWriteTimeout > 0 {
+		opts = append(opts, server.WithSSEWriteTimeout(time.Duration(cfg.Limits.SSEWriteTimeout)))
+	}
+	provider, err := cfg.Auth.provider()
+	if err != nil {
+		return nil, err
+	}
+	if provider != nil {
+		opts = append(opts, server.WithAuthProvider(provider))
+	}
+	if cfg.Auth != nil && cfg.Auth.JWKS != nil {
+		opts = append(opts, server.WithJWKSEndpoint(true, cfg.Auth.JWKS.Path))
+	}
+	return opts, nil
+}
+
+// provider returns the authentication provider configured by the auth section,
+// or nil if authentication is not configured.
+func (cfg *AuthConfig) provider() (auth.Provider, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	var providers []auth.Provider
+	if cfg.JWT != nil {
+		if cfg.JWT.Secret == "" {
+			return nil, fmt.Errorf("config: auth.jwt requires a secret")
+		}
+		providers = append(providers, auth.NewJWTAuthProvider(
+			[]byte(cfg.JWT.Secret), cfg.JWT.Audience, cfg.JWT.Issuer, time.Duration(cfg.JWT.Lifetime),
+		))
+	}
+	if cfg.APIKey != nil {
+		if len(cfg.APIKey.Keys) == 0 {
+			return nil, fmt.Errorf("config: auth.apiKey requires keys")
+		}
+		providers = append(providers, auth.NewAPIKeyAuthProvider(cfg.APIKey.Keys, cfg.APIKey.Header))
+	}
+	switch len(providers) {
+	case 0:
+		return nil, nil
+	case 1:
+		return providers[0], nil
+	default:
+		return auth.NewChainAuthProvider(providers...), nil
+	}
+}
+
+// subscriptionLimits returns the configured subscription limits.
+func (limits LimitsConfig) subscriptionLimits() (taskmanager.SubscriptionLimits, error) {
+	overflow, err := overflowPolicy(limits.SubscriptionOverflow)
+	if err != nil {
+		return taskmanager.SubscriptionLimits{}, err
+	}
+	return taskmanager.SubscriptionLimits{
+		MaxPerTask:   limits.MaxSubscriptionsPerTask,
+		MaxPerCaller: limits.MaxSubscriptionsPerCaller,
+		Overflow:     overflow,
+	}, nil
+}
+
+// overflowPolicy parses a subscription overflow policy name.
+func overflowPolicy(name string) (taskmanager.SubscriptionOverflowPolicy, error) {
+	switch strings.ToLower(name) {
+	case "", "reject":
+		return taskmanager.SubscriptionOverflowReject, nil
+	case "evictoldest", "evict_oldest", "evict-oldest":
+		return taskmanager.SubscriptionOverflowEvictOldest, nil
+	}
+	return 0, fmt.Errorf("config: unknown subscription overflow policy %q", name)
+}
+
+// slowConsumerConfig returns the configured slow consumer handling.
+func (limits LimitsConfig) slowConsumerConfig() (taskmanager.SlowConsumerConfig, error) {
+	var policy taskmanager.SlowConsumerPolicy
+	switch strings.ToLower(limits.SlowConsumerPolicy) {
+	case "", "dropnewest", "drop_newest", "drop-newest":
+		policy = taskmanager.SlowConsumerDropNewest
+	case "dropoldest", "drop_oldest", "drop-oldest":
+		policy = taskmanager.SlowConsumerDropOldest
+	case "disconnect":
+		policy = taskmanager.SlowConsumerDisconnect
+	case "buffertodisk", "buffer_to_disk", "buffer-to-disk":
+		policy = taskmanager.SlowConsumerBufferToDisk
+	default:
+		return taskmanager.SlowConsumerConfig{}, fmt.Errorf("config: unknown slow consumer policy %q", limits.SlowConsumerPolicy)
+	}
+	return taskmanager.SlowConsumerConfig{Policy: policy, MaxQueued: limits.SlowConsumerQueue}, nil
+}
+
+// countWords approximates the token count of text by its word count, for
+// token budgets configured without a tokenizer.
+func countWords(text string) (int, error) {
+	return len(strings.Fields(text)), nil
+}
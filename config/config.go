@@ -0,0 +1,354 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+// Package config builds a fully wired A2A server from a YAML or JSON file, so
+// that the listen address, TLS, authentication, CORS, task store, limits and
+// agent card of an agent can be managed as configuration rather than code.
+//
+// A configuration file looks like:
+//
+//	address: ":8080"
+//	tls:
+//	  certFile: /etc/agent/tls.crt
+//	  keyFile: /etc/agent/tls.key
+//	cors: false
+//	readTimeout: 10s
+//	auth:
+//	  jwt:
+//	    secret: ${JWT_SECRET}
+//	    audience: agents
+//	  apiKey:
+//	    header: X-API-Key
+//	    keys:
+//	      k-123: ops
+//	taskStore:
+//	  type: redis
+//	  options:
+//	    address: redis:6379
+//	  recovery: fail
+//	queue:
+//	  type: redis
+//	  options:
+//	    address: redis:6379
+//	  concurrency: 4
+//	limits:
+//	  maxSubscriptionsPerTask: 8
+//	  minDeadlineBudget: 2s
+//	redaction:
+//	  detectors: [email, cardNumber]
+//	  patterns:
+//	    ticket: 'TKT-\d+'
+//	agentCard:
+//	  name: Echo
+//	  url: https://agent.example.com/
+//	  version: 1.0.0
+//	  capabilities:
+//	    streaming: true
+//
+// References to environment variables such as ${JWT_SECRET} are expanded
+// before parsing, and environment variables named after a field path, such as
+// A2A_ADDRESS or A2A_AUTH_JWT_AUDIENCE, override the field they name. The
+// agent card uses the JSON field names of server.AgentCard.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"trpc.group/trpc-go/trpc-a2a-go/server"
+)
+
+// DefaultEnvPrefix is the prefix of the environment variables overriding
+// configuration fields.
+const DefaultEnvPrefix = "A2A_"
+
+// Config is the configuration of an A2A server.
+type Config struct {
+	// Address is the network address the server listens on, ":8080" by default.
+	Address string `json:"address,omitempty"`
+	// TLS, if set, makes the server serve HTTPS.
+	TLS *TLSConfig `json:"tls,omitempty"`
+	// CORS enables CORS headers. Enabled if unset.
+	CORS *bool `json:"cors,omitempty"`
+	// JSONRPCPath is the path of the JSON-RPC endpoint, "/" by default.
+	JSONRPCPath string `json:"jsonrpcPath,omitempty"`
+	// ReadTimeout is the HTTP server read timeout.
+	ReadTimeout Duration `json:"readTimeout,omitempty"`
+	// WriteTimeout is the HTTP server write timeout.
+	WriteTimeout Duration `json:"writeTimeout,omitempty"`
+	// IdleTimeout is the HTTP server idle timeout.
+	IdleTimeout Duration `json:"idleTimeout,omitempty"`
+	// ReadinessPath, if set, is the path of the readiness endpoint.
+	ReadinessPath string `json:"readinessPath,omitempty"`
+	// StartupSelfTest makes the server run a self test before listening.
+	StartupSelfTest bool `json:"startupSelfTest,omitempty"`
+	// Auth configures authentication. Requests are not authenticated if unset.
+	Auth *AuthConfig `json:"auth,omitempty"`
+	// TaskStore configures the task manager storing the tasks.
+	TaskStore TaskStoreConfig `json:"taskStore,omitempty"`
+	// Queue, if set, splits the agent into an API tier and a worker tier:
+	// the server built with Build enqueues the tasks, and the workers built
+	// with BuildWorker from the same configuration process them.
+	Queue *QueueConfig `json:"queue,omitempty"`
+	// Limits configures request and resource limits.
+	Limits LimitsConfig `json:"limits,omitempty"`
+	// Redaction, if set, redacts sensitive data from the status messages and
+	// artifacts reported by the processor, before they are stored, returned,
+	// streamed or pushed, whatever the task store.
+	Redaction *RedactionConfig `json:"redaction,omitempty"`
+	// AgentCard is the card describing the agent.
+	AgentCard server.AgentCard `json:"agentCard"`
+}
+
+// TLSConfig configures HTTPS.
+type TLSConfig struct {
+	// CertFile is the PEM file of the server certificate.
+	CertFile string `json:"certFile"`
+	// KeyFile is the PEM file of the private key of the certificate.
+	KeyFile string `json:"keyFile"`
+}
+
+// AuthConfig configures authentication. If several methods are configured,
+// a request is accepted if it passes any of them.
+type AuthConfig struct {
+	// JWT authenticates bearer tokens signed with a shared secret.
+	JWT *JWTConfig `json:"jwt,omitempty"`
+	// APIKey authenticates API keys sent in a request header.
+	APIKey *APIKeyConfig `json:"apiKey,omitempty"`
+	// JWKS enables the JWKS endpoint used to verify push notifications.
+	JWKS *JWKSConfig `json:"jwks,omitempty"`
+}
+
+// JWTConfig configures JWT authentication.
+type JWTConfig struct {
+	// Secret is the HMAC secret validating the tokens.
+	Secret string `json:"secret"`
+	// Audience is the expected audience of the tokens.
+	Audience string `json:"audience,omitempty"`
+	// Issuer is the expected issuer of the tokens.
+	Issuer string `json:"issuer,omitempty"`
+	// Lifetime is the lifetime of the tokens, 24h by default.
+	Lifetime Duration `json:"lifetime,omitempty"`
+}
+
+// APIKeyConfig configures API key authentication.
+type APIKeyConfig struct {
+	// Header is the request header carrying the key, "X-API-Key" by default.
+	Header string `json:"header,omitempty"`
+	// Keys maps the accepted API keys to the IDs of their users.
+	Keys map[string]string `json:"keys"`
+}
+
+// JWKSConfig configures the JWKS endpoint.
+type JWKSConfig struct {
+	// Path is the path of the endpoint, "/.well-known/jwks.json" by default.
+	Path string `json:"path,omitempty"`
+}
+
+// TaskStoreConfig selects the task manager storing the tasks.
+type TaskStoreConfig struct {
+	// Type is the type of the store, "memory" by default. Other types must be
+	// registered with WithTaskStore.
+	Type string `json:"type,omitempty"`
+	// Options holds the settings of the store, such as the address of a
+	// database, interpreted by the store factory.
+	Options map[string]interface{} `json:"options,omitempty"`
+	// Recovery, if set, is what happens on start to the tasks a persistent
+	// store holds as working since the previous process stopped: "fail",
+	// "requeue" or "resume", see taskmanager.RecoveryPolicy. Build validates
+	// it and store factories apply it, parsing it with
+	// taskmanager.ParseRecoveryPolicy. The memory store rejects it.
+	Recovery string `json:"recovery,omitempty"`
+}
+
+// QueueConfig selects the work queue and event bus between the API tier and
+// the worker tier, see taskmanager.QueueProcessor and taskmanager.Worker.
+type QueueConfig struct {
+	// Type is the type of the queue, registered with WithQueue.
+	Type string `json:"type"`
+	// Options holds the settings of the queue, such as the address of a
+	// broker, interpreted by the queue factory.
+	Options map[string]interface{} `json:"options,omitempty"`
+	// Concurrency is the number of tasks a worker processes concurrently,
+	// 1 by default.
+	Concurrency int `json:"concurrency,omitempty"`
+	// Heartbeat is the interval at which workers extend the tasks they
+	// process, 10s by default.
+	Heartbeat Duration `json:"heartbeat,omitempty"`
+	// RetryDelay is the delay of workers before retrying after a queue
+	// failure, 1s by default.
+	RetryDelay Duration `json:"retryDelay,omitempty"`
+}
+
+// LimitsConfig configures request and resource limits. Zero values mean no limit.
+type LimitsConfig struct {
+	// MaxSubscriptionsPerTask bounds the concurrent streams of a task.
+	MaxSubscriptionsPerTask int `json:"maxSubscriptionsPerTask,omitempty"`
+	// MaxSubscriptionsPerCaller bounds the concurrent streams of a caller.
+	MaxSubscriptionsPerCaller int `json:"maxSubscriptionsPerCaller,omitempty"`
+	// SubscriptionOverflow is what happens to a stream over the limits,
+	// "reject" (the default) or "evictOldest".
+	SubscriptionOverflow string `json:"subscriptionOverflow,omitempty"`
+	// SlowConsumerPolicy is what happens to the events of a stream read too
+	// slowly: "dropNewest" (the default), "dropOldest", "disconnect" or
+	// "bufferToDisk".
+	SlowConsumerPolicy string `json:"slowConsumerPolicy,omitempty"`
+	// SlowConsumerQueue is the number of events queued per slow stream.
+	SlowConsumerQueue int `json:"slowConsumerQueue,omitempty"`
+	// MaxTaskTokens is the token budget of a task.
+	MaxTaskTokens int `json:"maxTaskTokens,omitempty"`
+	// MinDeadlineBudget rejects send requests with a smaller deadline budget.
+	MinDeadlineBudget Duration `json:"minDeadlineBudget,omitempty"`
+	// SSEWriteTimeout is the write deadline of every SSE event.
+	SSEWriteTimeout Duration `json:"sseWriteTimeout,omitempty"`
+}
+
+// RedactionConfig configures the redaction of sensitive data, see the redact
+// package.
+type RedactionConfig struct {
+	// Detectors names the built-in detectors: "email", "phone" and "cardNumber".
+	Detectors []string `json:"detectors,omitempty"`
+	// Patterns maps kinds of sensitive data to the regular expressions
+	// detecting them.
+	Patterns map[string]string `json:"patterns,omitempty"`
+	// Logs redacts the messages of Server.Logger too. Install it as
+	// log.Default to redact the logs of the library.
+	Logs bool `json:"logs,omitempty"`
+}
+
+// Duration is a time.Duration read from a string such as "10s", or from a
+// number of nanoseconds.
+type Duration time.Duration
+
+// MarshalJSON implements json.Marshaler.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	switch v := v.(type) {
+	case float64:
+		*d = Duration(v)
+	case string:
+		parsed, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q: %w", v, err)
+		}
+		*d = Duration(parsed)
+	default:
+		return fmt.Errorf("invalid duration %s", data)
+	}
+	return nil
+}
+
+// Load reads the configuration file at path, applying the environment
+// overrides with DefaultEnvPrefix.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	return Parse(data, DefaultEnvPrefix)
+}
+
+// Parse parses a YAML or JSON configuration, JSON being a subset of YAML.
+// Environment variables referenced as ${VAR} are expanded first, and those
+// named after a field path with envPrefix, if not empty, override the field.
+func Parse(data []byte, envPrefix string) (*Config, error) {
+	var tree map[string]interface{}
+	if err := yaml.Unmarshal([]byte(os.ExpandEnv(string(data))), &tree); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	if tree == nil {
+		tree = map[string]interface{}{}
+	}
+	if envPrefix != "" {
+		if err := applyEnv(tree, envPrefix, os.Environ()); err != nil {
+			return nil, err
+		}
+	}
+	// Round trip through JSON so that the json tags, shared with the protocol
+	// types of the agent card, define the field names.
+	encoded, err := json.Marshal(tree)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	var cfg Config
+	if err := json.Unmarshal(encoded, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	return &cfg, nil
+}
+
+// applyEnv sets the fields of tree named by the environment variables with
+// prefix. The variable path is split on "_" and matched case-insensitively,
+// so A2A_TLS_CERTFILE sets tls.certFile. Values are parsed as YAML scalars.
+func applyEnv(tree map[string]interface{}, prefix string, environ []string) error {
+	for _, kv := range environ {
+		name, value, ok := strings.Cut(kv, "=")
+		if !ok || !strings.HasPrefix(name, prefix) || len(name) == len(prefix) {
+			continue
+		}
+		path := strings.Split(strings.ToLower(name[len(prefix):]), "_")
+		var parsed interface{}
+		if err := yaml.Unmarshal([]byte(value), &parsed); err != nil || isCollection(parsed) {
+			parsed = value
+		}
+		if err := setPath(tree, path, parsed); err != nil {
+			return fmt.Errorf("invalid environment override %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// setPath sets the value at path in tree, creating the missing maps.
+func setPath(tree map[string]interface{}, path []string, value interface{}) error {
+	key := lookupKey(tree, path[0])
+	if len(path) == 1 {
+		tree[key] = value
+		return nil
+	}
+	next, ok := tree[key]
+	if !ok || next == nil {
+		next = map[string]interface{}{}
+		tree[key] = next
+	}
+	child, ok := next.(map[string]interface{})
+	if !ok {
+		return errors.New("field " + key + " is not an object")
+	}
+	return setPath(child, path[1:], value)
+}
+
+// lookupKey returns the key of tree matching name case-insensitively, or name.
+func lookupKey(tree map[string]interface{}, name string) string {
+	for key := range tree {
+		if strings.EqualFold(key, name) {
+			return key
+		}
+	}
+	return name
+}
+
+// isCollection reports whether a parsed YAML value is a list or a map.
+func isCollection(v interface{}) bool {
+	switch v.(type) {
+	case []interface{}, map[string]interface{}:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,275 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/server"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+const testConfig = `
+address: ":9090"
+cors: false
+readTimeout: 5s
+auth:
+  apiKey:
+    header: X-Key
+    keys:
+      ${TEST_API_KEY}: ops
+taskStore:
+  type: memory
+limits:
+  maxSubscriptionsPerTask: 2
+  subscriptionOverflow: evictOldest
+agentCard:
+  name: Echo
+  url: http://localhost:9090/
+  version: 1.0.0
+  capabilities:
+    streaming: true
+`
+
+// echoProcessor completes every task.
+type echoProcessor struct{}
+
+func (echoProcessor) Process(
+	ctx context.Context,
+	taskID string,
+	message protocol.Message,
+	handle taskmanager.TaskHandle,
+) error {
+	return handle.UpdateStatus(protocol.TaskStateCompleted, nil)
+}
+
+func TestParse(t *testing.T) {
+	t.Setenv("TEST_API_KEY", "secret-key")
+	t.Setenv("A2A_ADDRESS", ":7070")
+	t.Setenv("A2A_LIMITS_MAXSUBSCRIPTIONSPERTASK", "4")
+	t.Setenv("A2A_AUTH_JWT_SECRET", "jwt-secret")
+	t.Setenv("A2A_AGENTCARD_CAPABILITIES_PUSHNOTIFICATIONS", "true")
+
+	cfg, err := Parse([]byte(testConfig), DefaultEnvPrefix)
+	require.NoError(t, err)
+	assert.Equal(t, ":7070", cfg.Address)
+	require.NotNil(t, cfg.CORS)
+	assert.False(t, *cfg.CORS)
+	assert.Equal(t, Duration(5*time.Second), cfg.ReadTimeout)
+	require.NotNil(t, cfg.Auth)
+	require.NotNil(t, cfg.Auth.APIKey)
+	assert.Equal(t, map[string]string{"secret-key": "ops"}, cfg.Auth.APIKey.Keys)
+	require.NotNil(t, cfg.Auth.JWT)
+	assert.Equal(t, "jwt-secret", cfg.Auth.JWT.Secret)
+	assert.Equal(t, 4, cfg.Limits.MaxSubscriptionsPerTask)
+	assert.Equal(t, "Echo", cfg.AgentCard.Name)
+	assert.True(t, cfg.AgentCard.Capabilities.Streaming)
+	assert.True(t, cfg.AgentCard.Capabilities.PushNotifications)
+
+	// Without a prefix, environment variables are only expanded.
+	cfg, err = Parse([]byte(testConfig), "")
+	require.NoError(t, err)
+	assert.Equal(t, ":9090", cfg.Address)
+	assert.Nil(t, cfg.Auth.JWT)
+
+	// JSON is accepted as well.
+	cfg, err = Parse([]byte(`{"address": ":1", "writeTimeout": "1m"}`), "")
+	require.NoError(t, err)
+	assert.Equal(t, ":1", cfg.Address)
+	assert.Equal(t, Duration(time.Minute), cfg.WriteTimeout)
+
+	_, err = Parse([]byte("readTimeout: soon"), "")
+	assert.ErrorContains(t, err, "invalid duration")
+}
+
+func TestBuild(t *testing.T) {
+	t.Setenv("TEST_API_KEY", "secret-key")
+	path := filepath.Join(t.TempDir(), "agent.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(testConfig), 0o600))
+
+	srv, err := LoadAndBuild(path, echoProcessor{})
+	require.NoError(t, err)
+	assert.Equal(t, ":9090", srv.Address)
+	testServer := httptest.NewServer(srv.Handler())
+	defer testServer.Close()
+
+	resp, err := http.Get(testServer.URL + protocol.AgentCardPath)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Empty(t, resp.Header.Get("Access-Control-Allow-Origin"))
+	var card server.AgentCard
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&card))
+	assert.Equal(t, "Echo", card.Name)
+
+	// The JSON-RPC endpoint requires the configured API key.
+	resp, err = http.Post(testServer.URL+"/", "application/json", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestBuild_TaskStore(t *testing.T) {
+	cfg := &Config{TaskStore: TaskStoreConfig{Type: "custom", Options: map[string]interface{}{"addr": "db:1"}}}
+	_, err := Build(cfg, echoProcessor{})
+	assert.ErrorContains(t, err, `unknown task store type "custom"`)
+
+	var got TaskStoreConfig
+	_, err = Build(cfg, echoProcessor{}, WithTaskStore("custom", func(
+		cfg TaskStoreConfig,
+		processor taskmanager.TaskProcessor,
+	) (taskmanager.TaskManager, error) {
+		got = cfg
+		return taskmanager.NewMemoryTaskManager(processor)
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "db:1", got.Options["addr"])
+
+	_, err = Build(&Config{Limits: LimitsConfig{MaxSubscriptionsPerTask: 1, SubscriptionOverflow: "drop"}}, echoProcessor{})
+	assert.ErrorContains(t, err, "unknown subscription overflow policy")
+	_, err = Build(&Config{Limits: LimitsConfig{SlowConsumerPolicy: "block"}}, echoProcessor{})
+	assert.ErrorContains(t, err, "unknown slow consumer policy")
+	_, err = Build(&Config{TLS: &TLSConfig{CertFile: "cert.pem"}}, echoProcessor{})
+	assert.ErrorContains(t, err, "tls requires certFile and keyFile")
+	_, err = Build(&Config{TaskStore: TaskStoreConfig{Recovery: "restart"}}, echoProcessor{})
+	assert.ErrorContains(t, err, "unknown recovery policy")
+	_, err = Build(&Config{TaskStore: TaskStoreConfig{Recovery: "fail"}}, echoProcessor{})
+	assert.ErrorContains(t, err, "requires a persistent task store")
+	cfg.TaskStore.Recovery = "requeue"
+	_, err = Build(cfg, echoProcessor{}, WithTaskStore("custom", func(
+		cfg TaskStoreConfig,
+		processor taskmanager.TaskProcessor,
+	) (taskmanager.TaskManager, error) {
+		got = cfg
+		return taskmanager.NewMemoryTaskManager(processor)
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "requeue", got.Recovery, "factories apply the recovery policy")
+}
+
+// replyProcessor completes every task replying with its message.
+type replyProcessor struct{}
+
+func (replyProcessor) Process(
+	ctx context.Context,
+	taskID string,
+	message protocol.Message,
+	handle taskmanager.TaskHandle,
+) error {
+	return handle.UpdateStatus(protocol.TaskStateCompleted, &message)
+}
+
+func TestBuild_Redaction(t *testing.T) {
+	cfg, err := Parse([]byte(`
+redaction:
+  detectors: [email]
+  patterns:
+    ticket: 'TKT-\d+'
+agentCard:
+  name: Echo
+`), "")
+	require.NoError(t, err)
+	srv, err := Build(cfg, replyProcessor{})
+	require.NoError(t, err)
+
+	events, err := srv.TaskManager.OnSendTaskSubscribe(context.Background(), protocol.SendTaskParams{
+		ID: "redacted",
+		Message: protocol.NewMessage(protocol.MessageRoleUser,
+			[]protocol.Part{protocol.NewTextPart("a@b.io about TKT-12")}),
+	})
+	require.NoError(t, err)
+	for event := range events {
+		if e, ok := event.(protocol.TaskStatusUpdateEvent); ok && e.Final {
+			require.NotNil(t, e.Status.Message)
+			assert.Equal(t, "[REDACTED:email] about [REDACTED:ticket]",
+				e.Status.Message.Parts[0].(protocol.TextPart).Text)
+			break
+		}
+	}
+	task, err := srv.TaskManager.OnGetTask(context.Background(), protocol.TaskQueryParams{ID: "redacted"})
+	require.NoError(t, err)
+	assert.Equal(t, "[REDACTED:email] about [REDACTED:ticket]",
+		task.Status.Message.Parts[0].(protocol.TextPart).Text, "stored tasks are redacted")
+
+	// Task stores created by factories are redacted too.
+	cfg.TaskStore.Type = "custom"
+	srv, err = Build(cfg, replyProcessor{}, WithTaskStore("custom", func(
+		cfg TaskStoreConfig, processor taskmanager.TaskProcessor,
+	) (taskmanager.TaskManager, error) {
+		return taskmanager.NewMemoryTaskManager(processor)
+	}))
+	require.NoError(t, err)
+	task, err = srv.TaskManager.OnSendTask(context.Background(), protocol.SendTaskParams{
+		ID:      "custom",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("a@b.io")}),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "[REDACTED:email]", task.Status.Message.Parts[0].(protocol.TextPart).Text)
+
+	// The logs are redacted by the logger of the server, not log.Default.
+	cfg.TaskStore.Type = ""
+	cfg.Redaction.Logs = true
+	defaultLogger := log.Default
+	srv, err = Build(cfg, replyProcessor{})
+	require.NoError(t, err)
+	assert.Same(t, defaultLogger, log.Default)
+	assert.NotSame(t, defaultLogger, srv.Logger)
+
+	_, err = Build(&Config{Redaction: &RedactionConfig{Detectors: []string{"ssn"}}}, echoProcessor{})
+	assert.ErrorContains(t, err, `unknown redaction detector "ssn"`)
+	_, err = Build(&Config{Redaction: &RedactionConfig{Patterns: map[string]string{"x": "("}}}, echoProcessor{})
+	assert.ErrorContains(t, err, "invalid redaction pattern")
+}
+
+func TestBuild_Queue(t *testing.T) {
+	cfg, err := Parse([]byte(`
+queue:
+  type: memory
+  concurrency: 2
+agentCard:
+  name: Echo
+`), "")
+	require.NoError(t, err)
+	_, err = Build(cfg, nil)
+	assert.ErrorContains(t, err, `unknown queue type "memory"`)
+
+	queue, bus := taskmanager.NewMemoryWorkQueue(), taskmanager.NewMemoryEventBus()
+	withQueue := WithQueue("memory", func(cfg QueueConfig) (taskmanager.WorkQueue, taskmanager.EventBus, error) {
+		return queue, bus, nil
+	})
+	srv, err := Build(cfg, nil, withQueue)
+	require.NoError(t, err)
+	worker, err := BuildWorker(cfg, replyProcessor{}, withQueue)
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go worker.Run(ctx)
+
+	// The server enqueues the tasks processed by the worker.
+	task, err := srv.TaskManager.OnSendTask(ctx, protocol.SendTaskParams{
+		ID:      "queued",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hello")}),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, protocol.TaskStateCompleted, task.Status.State)
+	require.NotNil(t, task.Status.Message)
+	assert.Equal(t, "hello", task.Status.Message.Parts[0].(protocol.TextPart).Text)
+
+	_, err = BuildWorker(&Config{}, replyProcessor{})
+	assert.ErrorContains(t, err, "a worker requires a queue")
+}
@@ -0,0 +1,65 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package config
+
+import (
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/redact"
+	"trpc.group/trpc-go/trpc-a2a-go/server"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// Option configures Build.
+type Option func(*builder)
+
+// WithTaskStore registers the factory of a task store type, such as "redis",
+// selectable with taskStore.type. It can also replace the "memory" type.
+func WithTaskStore(storeType string, factory TaskStoreFactory) Option {
+	return func(b *builder) {
+		b.stores[storeType] = factory
+	}
+}
+
+// WithQueue registers the factory of a queue type, such as "redis",
+// selectable with queue.type.
+func WithQueue(queueType string, factory QueueFactory) Option {
+	return func(b *builder) {
+		b.queues[queueType] = factory
+	}
+}
+
+// WithTokenizer sets the tokenizer enforcing limits.maxTaskTokens. Without
+// it, tokens are approximated by words.
+func WithTokenizer(tokenizer taskmanager.Tokenizer) Option {
+	return func(b *builder) {
+		b.tokenizer = tokenizer
+	}
+}
+
+// WithRedactionDetectors adds custom detectors to the redaction of sensitive
+// data, enabling it if the configuration does not.
+func WithRedactionDetectors(detectors ...redact.Detector) Option {
+	return func(b *builder) {
+		b.detectors = append(b.detectors, detectors...)
+	}
+}
+
+// WithLogger sets the logger returned in Server.Logger, redacted if
+// redaction.logs is set. Default log.Default.
+func WithLogger(logger log.Logger) Option {
+	return func(b *builder) {
+		b.logger = logger
+	}
+}
+
+// WithServerOptions adds server options that cannot be expressed in the
+// configuration file. They are applied after the configured ones.
+func WithServerOptions(opts ...server.Option) Option {
+	return func(b *builder) {
+		b.serverOpts = append(b.serverOpts, opts...)
+	}
+}
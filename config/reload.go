@@ -0,0 +1,186 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"syscall"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// ReloadHook is called with the previous and the next configuration after a
+// reload applied the new one, so that custom components can pick up their
+// settings. An error is reported by Reload but does not roll the reload back.
+type ReloadHook func(ctx context.Context, prev, next *Config) error
+
+// subscriptionLimitSetter is implemented by task managers whose subscription
+// limits can change at runtime, such as taskmanager.MemoryTaskManager.
+type subscriptionLimitSetter interface {
+	SetSubscriptionLimits(limits taskmanager.SubscriptionLimits)
+}
+
+// Reloader reloads the configuration file of a Server while it runs. A reload
+// replaces the agent card, the authentication keys and the limits of the
+// server without dropping live SSE connections; the other settings, such as
+// the address, TLS or task store, still need a restart to change.
+type Reloader struct {
+	srv  *Server
+	path string
+
+	mu      sync.Mutex // Serializes reloads.
+	current *Config
+	hooks   []ReloadHook
+}
+
+// NewReloader creates a Reloader of srv, built from the configuration file at path.
+func NewReloader(srv *Server, path string) *Reloader {
+	return &Reloader{srv: srv, path: path, current: srv.config}
+}
+
+// OnReload registers a hook called after every successful reload.
+func (r *Reloader) OnReload(hook ReloadHook) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hooks = append(r.hooks, hook)
+}
+
+// Config returns the configuration currently applied.
+func (r *Reloader) Config() *Config {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.current
+}
+
+// Reload reads the configuration file again and applies it. If the file is
+// invalid, the current configuration is kept and the error returned.
+func (r *Reloader) Reload(ctx context.Context) error {
+	cfg, err := Load(r.path)
+	if err != nil {
+		return err
+	}
+	provider, err := cfg.Auth.provider()
+	if err != nil {
+		return err
+	}
+	limits, err := cfg.Limits.subscriptionLimits()
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	prev := r.current
+	if prev != nil {
+		warnRestartRequired(prev, cfg)
+	}
+	srv := r.srv
+	srv.SetAgentCard(cfg.AgentCard)
+	srv.SetAuthProvider(provider)
+	srv.SetMinDeadlineBudget(time.Duration(cfg.Limits.MinDeadlineBudget))
+	srv.SetSSEWriteTimeout(time.Duration(cfg.Limits.SSEWriteTimeout))
+	if setter, ok := srv.TaskManager.(subscriptionLimitSetter); ok {
+		setter.SetSubscriptionLimits(limits)
+	}
+	r.current = cfg
+	var errs []error
+	for _, hook := range r.hooks {
+		if err := hook(ctx, prev, cfg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("config: reload hook failed: %w", err)
+	}
+	log.Infof("Reloaded configuration from %s", r.path)
+	return nil
+}
+
+// WatchSignals reloads the configuration whenever the process receives one of
+// signals, SIGHUP if none is given, until ctx is done. Failed reloads are logged.
+func (r *Reloader) WatchSignals(ctx context.Context, signals ...os.Signal) {
+	if len(signals) == 0 {
+		signals = []os.Signal{syscall.SIGHUP}
+	}
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, signals...)
+	defer signal.Stop(ch)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ch:
+			r.reloadAndLog(ctx)
+		}
+	}
+}
+
+// WatchFile polls the configuration file every interval and reloads it when
+// its modification time or size changes, until ctx is done. Failed reloads
+// are logged.
+func (r *Reloader) WatchFile(ctx context.Context, interval time.Duration) {
+	last, _ := os.Stat(r.path)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		info, err := os.Stat(r.path)
+		if err != nil {
+			log.Warnf("Failed to stat configuration %s: %v", r.path, err)
+			continue
+		}
+		if last != nil && info.ModTime().Equal(last.ModTime()) && info.Size() == last.Size() {
+			continue
+		}
+		last = info
+		r.reloadAndLog(ctx)
+	}
+}
+
+// reloadAndLog reloads the configuration, logging failures.
+func (r *Reloader) reloadAndLog(ctx context.Context) {
+	if err := r.Reload(ctx); err != nil {
+		log.Errorf("Failed to reload configuration from %s: %v", r.path, err)
+	}
+}
+
+// warnRestartRequired logs the changed settings that a reload cannot apply.
+func warnRestartRequired(prev, next *Config) {
+	changed := func(name string, a, b interface{}) {
+		if !reflect.DeepEqual(a, b) {
+			log.Warnf("Configuration %s changed, restart the server to apply it", name)
+		}
+	}
+	changed("address", prev.Address, next.Address)
+	changed("tls", prev.TLS, next.TLS)
+	changed("cors", prev.CORS, next.CORS)
+	changed("jsonrpcPath", prev.JSONRPCPath, next.JSONRPCPath)
+	changed("timeouts", [3]Duration{prev.ReadTimeout, prev.WriteTimeout, prev.IdleTimeout},
+		[3]Duration{next.ReadTimeout, next.WriteTimeout, next.IdleTimeout})
+	changed("taskStore", prev.TaskStore, next.TaskStore)
+	changed("limits.maxTaskTokens", prev.Limits.MaxTaskTokens, next.Limits.MaxTaskTokens)
+	var prevJWKS, nextJWKS *JWKSConfig
+	if prev.Auth != nil {
+		prevJWKS = prev.Auth.JWKS
+	}
+	if next.Auth != nil {
+		nextJWKS = next.Auth.JWKS
+	}
+	changed("auth.jwks", prevJWKS, nextJWKS)
+}
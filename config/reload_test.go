@@ -0,0 +1,87 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/server"
+)
+
+const reloadConfig = `
+auth:
+  apiKey:
+    keys:
+      %s: ops
+agentCard:
+  name: %s
+  url: http://localhost/
+  version: 1.0.0
+`
+
+func TestReloader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agent.yaml")
+	writeConfig := func(key, name string) {
+		require.NoError(t, os.WriteFile(path, []byte(fmt.Sprintf(reloadConfig, key, name)), 0o600))
+	}
+	writeConfig("key-1", "Echo")
+	srv, err := LoadAndBuild(path, echoProcessor{})
+	require.NoError(t, err)
+	testServer := httptest.NewServer(srv.Handler())
+	defer testServer.Close()
+
+	cardName := func() string {
+		resp, err := http.Get(testServer.URL + protocol.AgentCardPath)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var card server.AgentCard
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&card))
+		return card.Name
+	}
+	status := func(key string) int {
+		req, err := http.NewRequest(http.MethodPost, testServer.URL+"/", nil)
+		require.NoError(t, err)
+		req.Header.Set("X-API-Key", key)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	assert.Equal(t, "Echo", cardName())
+	assert.NotEqual(t, http.StatusUnauthorized, status("key-1"))
+
+	reloader := NewReloader(srv, path)
+	var prevName, nextName string
+	reloader.OnReload(func(ctx context.Context, prev, next *Config) error {
+		prevName, nextName = prev.AgentCard.Name, next.AgentCard.Name
+		return nil
+	})
+	writeConfig("key-2", "Echo v2")
+	require.NoError(t, reloader.Reload(context.Background()))
+	assert.Equal(t, "Echo", prevName)
+	assert.Equal(t, "Echo v2", nextName)
+	assert.Equal(t, "Echo v2", cardName())
+	assert.Equal(t, http.StatusUnauthorized, status("key-1"))
+	assert.NotEqual(t, http.StatusUnauthorized, status("key-2"))
+
+	// An invalid file keeps the current configuration.
+	require.NoError(t, os.WriteFile(path, []byte("auth: {apiKey: {keys: {}}}"), 0o600))
+	assert.ErrorContains(t, reloader.Reload(context.Background()), "auth.apiKey requires keys")
+	assert.Equal(t, "Echo v2", reloader.Config().AgentCard.Name)
+	assert.NotEqual(t, http.StatusUnauthorized, status("key-2"))
+}
@@ -0,0 +1,279 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sync"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/client"
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/server"
+)
+
+const (
+	defaultTTL = 5 * time.Minute
+	// defaultIdleTTLs is the default idle timeout, in TTLs.
+	defaultIdleTTLs = 10
+	// minCheckInterval bounds how often the cache looks for cards to refresh.
+	minCheckInterval = 10 * time.Millisecond
+)
+
+// CardChange describes how the card of an agent changed on refresh.
+type CardChange struct {
+	// AgentURL is the base URL of the agent.
+	AgentURL string
+	// Old and New are the previous and the refreshed agent cards.
+	Old, New *server.AgentCard
+	// CapabilitiesChanged reports whether the declared capabilities changed.
+	CapabilitiesChanged bool
+	// AddedSkills and RemovedSkills list the IDs of the skills that appeared
+	// and disappeared.
+	AddedSkills, RemovedSkills []string
+}
+
+// cacheEntry is the cached card of one agent.
+type cacheEntry struct {
+	card       *server.AgentCard
+	fetchedAt  time.Time
+	lastRead   time.Time
+	refreshing bool
+}
+
+// CardCache caches agent cards and keeps the cards in use warm: each card is
+// refreshed in the background shortly before it expires, so reads rarely wait
+// for a fetch, and registered handlers learn about capability changes as soon
+// as a refresh sees them. Cards that are not read for the idle timeout are dropped.
+// It is safe for concurrent use. Returned cards are shared and must not be modified.
+type CardCache struct {
+	ttl          time.Duration
+	refreshAhead time.Duration
+	idleTimeout  time.Duration
+	fetcher      Fetcher
+	handlers     []func(CardChange)
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+
+	notifyMu  sync.Mutex // Serializes change handlers.
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+	refreshes sync.WaitGroup
+}
+
+// NewCardCache creates a card cache and starts its background refresher.
+// Close stops it.
+func NewCardCache(opts ...CacheOption) *CardCache {
+	c := &CardCache{
+		ttl:     defaultTTL,
+		fetcher: &HTTPFetcher{},
+		entries: make(map[string]*cacheEntry),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.refreshAhead == 0 {
+		c.refreshAhead = c.ttl / 5
+	}
+	if c.refreshAhead > c.ttl {
+		c.refreshAhead = c.ttl
+	}
+	if c.idleTimeout == 0 {
+		c.idleTimeout = defaultIdleTTLs * c.ttl
+	}
+	go c.run()
+	return c
+}
+
+// Get returns the card of the agent at agentURL, fetching it if it is not
+// cached or has expired. Fetched cards are kept warm from then on.
+func (c *CardCache) Get(ctx context.Context, agentURL string) (*server.AgentCard, error) {
+	now := time.Now()
+	c.mu.Lock()
+	if e, ok := c.entries[agentURL]; ok && now.Before(e.fetchedAt.Add(c.ttl)) {
+		e.lastRead = now
+		card := e.card
+		c.mu.Unlock()
+		return card, nil
+	}
+	c.mu.Unlock()
+	card, err := c.fetcher.FetchAgentCard(ctx, agentURL)
+	if err != nil {
+		return nil, err
+	}
+	c.store(agentURL, card, true)
+	return card, nil
+}
+
+// Warm fetches the cards of the given agents ahead of their first use.
+func (c *CardCache) Warm(ctx context.Context, agentURLs ...string) error {
+	var errs []error
+	for _, agentURL := range agentURLs {
+		if _, err := c.Get(ctx, agentURL); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Invalidate drops the cached card of the agent at agentURL.
+func (c *CardCache) Invalidate(agentURL string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, agentURL)
+}
+
+// Close stops the background refresher and waits for running refreshes.
+func (c *CardCache) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.stop)
+		<-c.done
+		c.refreshes.Wait()
+	})
+	return nil
+}
+
+// run refreshes the cards due for refresh until Close is called.
+func (c *CardCache) run() {
+	defer close(c.done)
+	interval := c.refreshAhead / 4
+	if interval < minCheckInterval {
+		interval = minCheckInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.stop:
+			return
+		case now := <-ticker.C:
+			c.refreshDue(now)
+		}
+	}
+}
+
+// refreshDue starts a refresh of every card close to expiry and drops idle cards.
+func (c *CardCache) refreshDue(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for agentURL, e := range c.entries {
+		if now.Sub(e.lastRead) > c.idleTimeout {
+			delete(c.entries, agentURL)
+			continue
+		}
+		if e.refreshing || now.Before(e.fetchedAt.Add(c.ttl-c.refreshAhead)) {
+			continue
+		}
+		e.refreshing = true
+		c.refreshes.Add(1)
+		go c.refresh(agentURL)
+	}
+}
+
+// refresh fetches the card of agentURL again.
+func (c *CardCache) refresh(agentURL string) {
+	defer c.refreshes.Done()
+	ctx, cancel := context.WithTimeout(context.Background(), c.refreshAhead)
+	defer cancel()
+	card, err := c.fetcher.FetchAgentCard(ctx, agentURL)
+	if err != nil {
+		log.Warnf("Failed to refresh agent card of %s: %v", agentURL, err)
+		c.mu.Lock()
+		if e, ok := c.entries[agentURL]; ok {
+			e.refreshing = false
+		}
+		c.mu.Unlock()
+		return
+	}
+	c.store(agentURL, card, false)
+}
+
+// store caches card for agentURL and notifies the handlers if it changed.
+// Reads count as use of the card, refreshes do not.
+func (c *CardCache) store(agentURL string, card *server.AgentCard, read bool) {
+	now := time.Now()
+	c.mu.Lock()
+	e, ok := c.entries[agentURL]
+	if !ok {
+		if !read {
+			// Dropped while it was being refreshed.
+			c.mu.Unlock()
+			return
+		}
+		e = &cacheEntry{}
+		c.entries[agentURL] = e
+	}
+	old := e.card
+	e.card = card
+	e.fetchedAt = now
+	e.refreshing = false
+	if read {
+		e.lastRead = now
+	}
+	c.mu.Unlock()
+	if old == nil || len(c.handlers) == 0 || reflect.DeepEqual(old, card) {
+		return
+	}
+	change := diffCards(agentURL, old, card)
+	c.notifyMu.Lock()
+	defer c.notifyMu.Unlock()
+	for _, handler := range c.handlers {
+		handler(change)
+	}
+}
+
+// diffCards describes the changes between two cards of an agent.
+func diffCards(agentURL string, old, card *server.AgentCard) CardChange {
+	change := CardChange{
+		AgentURL:            agentURL,
+		Old:                 old,
+		New:                 card,
+		CapabilitiesChanged: !reflect.DeepEqual(old.Capabilities, card.Capabilities),
+	}
+	oldSkills := make(map[string]bool, len(old.Skills))
+	for _, skill := range old.Skills {
+		oldSkills[skill.ID] = true
+	}
+	newSkills := make(map[string]bool, len(card.Skills))
+	for _, skill := range card.Skills {
+		newSkills[skill.ID] = true
+		if !oldSkills[skill.ID] {
+			change.AddedSkills = append(change.AddedSkills, skill.ID)
+		}
+	}
+	for _, skill := range old.Skills {
+		if !newSkills[skill.ID] {
+			change.RemovedSkills = append(change.RemovedSkills, skill.ID)
+		}
+	}
+	return change
+}
+
+// Follow keeps the cached card of the agent at agentURL up to date with the
+// protocol.MethodAgentCardUpdated notifications the agent sends over session,
+// so that long-lived callers learn about new skills or authentication
+// requirements without waiting for the next refresh. Changes are reported to
+// the change handlers like those found by refreshes. Notifications received
+// while the card is not cached, e.g. after it was dropped as idle, are ignored.
+func (c *CardCache) Follow(agentURL string, session *client.WebSocketSession) {
+	session.HandleNotification(protocol.MethodAgentCardUpdated, func(params json.RawMessage) {
+		card, err := decodeCard(params)
+		if err != nil {
+			log.Warnf("Ignoring agent card update of %s: %v", agentURL, err)
+			return
+		}
+		c.store(agentURL, card, false)
+	})
+}
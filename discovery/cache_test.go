@@ -0,0 +1,231 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/client"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/server"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// fakeFetcher serves a configurable card and counts fetches.
+type fakeFetcher struct {
+	mu    sync.Mutex
+	card  *server.AgentCard
+	err   error
+	calls atomic.Int32
+}
+
+func (f *fakeFetcher) FetchAgentCard(ctx context.Context, agentURL string) (*server.AgentCard, error) {
+	f.calls.Add(1)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	card := *f.card
+	return &card, nil
+}
+
+func (f *fakeFetcher) set(card *server.AgentCard, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.card, f.err = card, err
+}
+
+func TestCardCache_Get(t *testing.T) {
+	fetcher := &fakeFetcher{card: &server.AgentCard{Name: "agent"}}
+	cache := NewCardCache(WithFetcher(fetcher), WithTTL(time.Hour))
+	defer cache.Close()
+
+	card, err := cache.Get(context.Background(), "http://agent")
+	require.NoError(t, err)
+	assert.Equal(t, "agent", card.Name)
+	_, err = cache.Get(context.Background(), "http://agent")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), fetcher.calls.Load(), "fresh cards are served from the cache")
+
+	cache.Invalidate("http://agent")
+	_, err = cache.Get(context.Background(), "http://agent")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), fetcher.calls.Load())
+
+	fetcher.set(nil, errors.New("unreachable"))
+	assert.Error(t, cache.Warm(context.Background(), "http://other"))
+}
+
+func TestCardCache_BackgroundRefresh(t *testing.T) {
+	fetcher := &fakeFetcher{card: &server.AgentCard{
+		Name:   "agent",
+		Skills: []server.AgentSkill{{ID: "search"}, {ID: "summarize"}},
+	}}
+	changes := make(chan CardChange, 1)
+	cache := NewCardCache(
+		WithFetcher(fetcher),
+		WithTTL(200*time.Millisecond),
+		WithRefreshAhead(150*time.Millisecond),
+		WithChangeHandler(func(change CardChange) { changes <- change }),
+	)
+	defer cache.Close()
+	require.NoError(t, cache.Warm(context.Background(), "http://agent"))
+
+	fetcher.set(&server.AgentCard{
+		Name:         "agent",
+		Capabilities: server.AgentCapabilities{Streaming: true},
+		Skills:       []server.AgentSkill{{ID: "search"}, {ID: "translate"}},
+	}, nil)
+	select {
+	case change := <-changes:
+		assert.Equal(t, "http://agent", change.AgentURL)
+		assert.True(t, change.CapabilitiesChanged)
+		assert.Equal(t, []string{"translate"}, change.AddedSkills)
+		assert.Equal(t, []string{"summarize"}, change.RemovedSkills)
+	case <-time.After(time.Second):
+		t.Fatal("no change was reported")
+	}
+	// The refreshed card is served without a fetch on the request path.
+	calls := fetcher.calls.Load()
+	card, err := cache.Get(context.Background(), "http://agent")
+	require.NoError(t, err)
+	assert.True(t, card.Capabilities.Streaming)
+	assert.Equal(t, calls, fetcher.calls.Load())
+}
+
+// nopProcessor leaves the tasks it receives submitted.
+type nopProcessor struct{}
+
+func (nopProcessor) Process(context.Context, string, protocol.Message, taskmanager.TaskHandle) error {
+	return nil
+}
+
+func TestCardCache_Follow(t *testing.T) {
+	tm, err := taskmanager.NewMemoryTaskManager(nopProcessor{})
+	require.NoError(t, err)
+	a2aServer, err := server.NewA2AServer(server.AgentCard{
+		Name:   "agent",
+		Skills: []server.AgentSkill{{ID: "search"}},
+	}, tm, server.WithWebSocket())
+	require.NoError(t, err)
+	testServer := httptest.NewServer(a2aServer.Handler())
+	defer testServer.Close()
+
+	changes := make(chan CardChange, 1)
+	cache := NewCardCache(
+		WithTTL(time.Hour),
+		WithChangeHandler(func(change CardChange) { changes <- change }),
+	)
+	defer cache.Close()
+	_, err = cache.Get(context.Background(), testServer.URL)
+	require.NoError(t, err)
+
+	c, err := client.NewA2AClient(testServer.URL)
+	require.NoError(t, err)
+	session, err := c.DialWebSocket(context.Background())
+	require.NoError(t, err)
+	defer session.Close()
+	cache.Follow(testServer.URL, session)
+	require.Eventually(t, func() bool {
+		return a2aServer.NotifyAgentCardUpdated() == 1
+	}, 2*time.Second, 10*time.Millisecond)
+
+	a2aServer.SetAgentCard(server.AgentCard{
+		Name:   "agent",
+		Skills: []server.AgentSkill{{ID: "search"}, {ID: "translate"}},
+	})
+	select {
+	case change := <-changes:
+		assert.Equal(t, testServer.URL, change.AgentURL)
+		assert.Equal(t, []string{"translate"}, change.AddedSkills)
+	case <-time.After(2 * time.Second):
+		t.Fatal("no change was reported")
+	}
+	card, err := cache.Get(context.Background(), testServer.URL)
+	require.NoError(t, err)
+	assert.Len(t, card.Skills, 2, "the cache serves the notified card")
+}
+
+func TestCardCache_IdleCardsAreDropped(t *testing.T) {
+	fetcher := &fakeFetcher{card: &server.AgentCard{Name: "agent"}}
+	cache := NewCardCache(
+		WithFetcher(fetcher),
+		WithTTL(40*time.Millisecond),
+		WithIdleTimeout(50*time.Millisecond),
+	)
+	defer cache.Close()
+	require.NoError(t, cache.Warm(context.Background(), "http://agent"))
+	require.Eventually(t, func() bool {
+		cache.mu.Lock()
+		defer cache.mu.Unlock()
+		return len(cache.entries) == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestHTTPFetcher(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/agent"+protocol.AgentCardPath {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(server.AgentCard{Name: "remote", URL: "http://remote"})
+	}))
+	defer srv.Close()
+
+	card, err := (&HTTPFetcher{}).FetchAgentCard(context.Background(), srv.URL+"/agent")
+	require.NoError(t, err)
+	assert.Equal(t, "remote", card.Name)
+
+	_, err = (&HTTPFetcher{}).FetchAgentCard(context.Background(), srv.URL+"/missing")
+	assert.Error(t, err)
+}
+
+func TestHTTPFetcher_Revalidation(t *testing.T) {
+	var fetches, notModified atomic.Int32
+	cacheControl := "no-cache"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Cache-Control", cacheControl)
+		_ = json.NewEncoder(w).Encode(server.AgentCard{Name: "remote"})
+	}))
+	defer srv.Close()
+
+	fetcher := &HTTPFetcher{}
+	for i := 0; i < 3; i++ {
+		card, err := fetcher.FetchAgentCard(context.Background(), srv.URL)
+		require.NoError(t, err)
+		assert.Equal(t, "remote", card.Name)
+	}
+	assert.Equal(t, int32(3), fetches.Load(), "no-cache cards are revalidated")
+	assert.Equal(t, int32(2), notModified.Load(), "unchanged cards are not downloaded again")
+
+	cacheControl = "max-age=60"
+	fetcher = &HTTPFetcher{}
+	for i := 0; i < 3; i++ {
+		_, err := fetcher.FetchAgentCard(context.Background(), srv.URL)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(4), fetches.Load(), "fresh cards are used without requests")
+}
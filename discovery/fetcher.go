@@ -0,0 +1,187 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+// Package discovery resolves and caches the agent cards of A2A agents.
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/server"
+)
+
+// maxCardSize bounds the size of a fetched agent card.
+const maxCardSize = 1 << 20
+
+// Fetcher retrieves the agent card of the agent at a base URL.
+type Fetcher interface {
+	FetchAgentCard(ctx context.Context, agentURL string) (*server.AgentCard, error)
+}
+
+// FetcherFunc adapts a function to the Fetcher interface.
+type FetcherFunc func(ctx context.Context, agentURL string) (*server.AgentCard, error)
+
+// FetchAgentCard implements Fetcher.
+func (f FetcherFunc) FetchAgentCard(ctx context.Context, agentURL string) (*server.AgentCard, error) {
+	return f(ctx, agentURL)
+}
+
+// HTTPFetcher fetches agent cards from the well-known agent card path of each
+// agent. It caches the cards according to their Cache-Control header and
+// revalidates them with conditional requests, using their ETag or
+// Last-Modified header, so that unchanged cards are not downloaded again.
+// The zero value is ready to use; an HTTPFetcher must not be copied.
+type HTTPFetcher struct {
+	// Client is the HTTP client used. http.DefaultClient is used if nil.
+	Client *http.Client
+
+	mu    sync.Mutex
+	cards map[string]*fetchedCard // By card URL.
+}
+
+// fetchedCard is a card cached by an HTTPFetcher.
+type fetchedCard struct {
+	body         []byte
+	etag         string
+	lastModified string
+	expires      time.Time // Until when the card is used without revalidation.
+}
+
+// FetchAgentCard implements Fetcher.
+func (f *HTTPFetcher) FetchAgentCard(ctx context.Context, agentURL string) (*server.AgentCard, error) {
+	cardURL, err := AgentCardURL(agentURL)
+	if err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	cached := f.cards[cardURL]
+	f.mu.Unlock()
+	if cached != nil && time.Now().Before(cached.expires) {
+		return decodeCard(cached.body)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, cardURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create agent card request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if cached != nil {
+		if cached.etag != "" {
+			req.Header.Set("If-None-Match", cached.etag)
+		}
+		if cached.lastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.lastModified)
+		}
+	}
+	client := f.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch agent card: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		f.remember(cardURL, cached.body, resp.Header, cached)
+		return decodeCard(cached.body)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch agent card: unexpected http status %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCardSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch agent card: %w", err)
+	}
+	card, err := decodeCard(body)
+	if err != nil {
+		return nil, err
+	}
+	f.remember(cardURL, body, resp.Header, nil)
+	return card, nil
+}
+
+// remember caches the card body of cardURL with the caching headers of its
+// response, keeping the validators of previous if the response has none, or
+// forgets it if it must not be cached.
+func (f *HTTPFetcher) remember(cardURL string, body []byte, header http.Header, previous *fetchedCard) {
+	card := &fetchedCard{
+		body:         body,
+		etag:         header.Get("ETag"),
+		lastModified: header.Get("Last-Modified"),
+		expires:      time.Now(),
+	}
+	if previous != nil {
+		if card.etag == "" {
+			card.etag = previous.etag
+		}
+		if card.lastModified == "" {
+			card.lastModified = previous.lastModified
+		}
+	}
+	maxAge, store := cacheControl(header.Get("Cache-Control"))
+	card.expires = card.expires.Add(maxAge)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !store || (maxAge == 0 && card.etag == "" && card.lastModified == "") {
+		delete(f.cards, cardURL)
+		return
+	}
+	if f.cards == nil {
+		f.cards = make(map[string]*fetchedCard)
+	}
+	f.cards[cardURL] = card
+}
+
+// cacheControl returns how long a response may be used without revalidation
+// according to its Cache-Control header, and whether it may be stored at all.
+func cacheControl(value string) (time.Duration, bool) {
+	var maxAge time.Duration
+	for _, directive := range strings.Split(value, ",") {
+		name, arg, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		switch strings.ToLower(name) {
+		case "no-store":
+			return 0, false
+		case "no-cache":
+			return 0, true
+		case "max-age":
+			if seconds, err := strconv.Atoi(strings.Trim(arg, `"`)); err == nil && seconds > 0 {
+				maxAge = time.Duration(seconds) * time.Second
+			}
+		}
+	}
+	return maxAge, true
+}
+
+// decodeCard decodes an agent card.
+func decodeCard(body []byte) (*server.AgentCard, error) {
+	var card server.AgentCard
+	if err := json.Unmarshal(body, &card); err != nil {
+		return nil, fmt.Errorf("failed to decode agent card: %w", err)
+	}
+	return &card, nil
+}
+
+// AgentCardURL returns the URL of the agent card of the agent at agentURL.
+func AgentCardURL(agentURL string) (string, error) {
+	if !strings.HasSuffix(agentURL, "/") {
+		agentURL += "/"
+	}
+	base, err := url.ParseRequestURI(agentURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid agent URL %q: %w", agentURL, err)
+	}
+	return base.ResolveReference(&url.URL{Path: strings.TrimPrefix(protocol.AgentCardPath, "/")}).String(), nil
+}
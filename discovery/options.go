@@ -0,0 +1,62 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package discovery
+
+import "time"
+
+// CacheOption is a function that configures the CardCache.
+type CacheOption func(*CardCache)
+
+// WithTTL sets how long a fetched agent card is considered fresh. Default 5m.
+func WithTTL(ttl time.Duration) CacheOption {
+	return func(c *CardCache) {
+		if ttl > 0 {
+			c.ttl = ttl
+		}
+	}
+}
+
+// WithRefreshAhead sets how long before expiry a card is refreshed in the
+// background. It defaults to a fifth of the TTL and is capped at the TTL.
+func WithRefreshAhead(d time.Duration) CacheOption {
+	return func(c *CardCache) {
+		if d > 0 {
+			c.refreshAhead = d
+		}
+	}
+}
+
+// WithFetcher sets how agent cards are fetched. Defaults to an HTTPFetcher
+// using http.DefaultClient.
+func WithFetcher(f Fetcher) CacheOption {
+	return func(c *CardCache) {
+		if f != nil {
+			c.fetcher = f
+		}
+	}
+}
+
+// WithChangeHandler registers a callback invoked when a refresh finds that the
+// card of a cached agent changed. Handlers run on the refreshing goroutine, one
+// change at a time, and should return quickly.
+func WithChangeHandler(handler func(CardChange)) CacheOption {
+	return func(c *CardCache) {
+		if handler != nil {
+			c.handlers = append(c.handlers, handler)
+		}
+	}
+}
+
+// WithIdleTimeout sets how long a card that is not read keeps being refreshed
+// before it is dropped from the cache. Default 10 times the TTL.
+func WithIdleTimeout(d time.Duration) CacheOption {
+	return func(c *CardCache) {
+		if d > 0 {
+			c.idleTimeout = d
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+// Package agents provides reference agents built only on the public server
+// and taskmanager APIs: an echo agent, a proxy to an LLM chat completion API
+// and a file converter. Their tests run them end to end with the client, so
+// that the examples keep compiling and working as the APIs evolve.
+package agents
+
+import (
+	"fmt"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/server"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// Agent is a reference agent: its card and its task processor.
+type Agent struct {
+	// Card describes the agent. Its URL is set by NewServer callers.
+	Card server.AgentCard
+	// Processor processes the tasks of the agent.
+	Processor taskmanager.TaskProcessor
+}
+
+// NewServer creates a server for agent, served at url, with an in-memory
+// task manager.
+func NewServer(agent Agent, url string, opts ...server.Option) (*server.A2AServer, error) {
+	tm, err := taskmanager.NewMemoryTaskManager(agent.Processor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create task manager: %w", err)
+	}
+	card := agent.Card
+	card.URL = url
+	return server.NewA2AServer(card, tm, opts...)
+}
+
+// newCard returns the card of a reference agent with a single skill.
+func newCard(name, description string, streaming bool, skill server.AgentSkill) server.AgentCard {
+	return server.AgentCard{
+		Name:        name,
+		Description: &description,
+		Version:     "1.0.0",
+		Provider:    &server.AgentProvider{Name: "tRPC-A2A-Go Example"},
+		Capabilities: server.AgentCapabilities{
+			Streaming:              streaming,
+			StateTransitionHistory: true,
+		},
+		DefaultInputModes:  skill.InputModes,
+		DefaultOutputModes: skill.OutputModes,
+		Skills:             []server.AgentSkill{skill},
+	}
+}
+
+// textOf returns the concatenated text parts of message.
+func textOf(message protocol.Message) string {
+	var text string
+	for _, part := range message.Parts {
+		if textPart, ok := part.(protocol.TextPart); ok {
+			text += textPart.Text
+		}
+	}
+	return text
+}
+
+// fail marks the task failed with reason. The failure is reported by the
+// state of the task rather than as an error of the request.
+func fail(handle taskmanager.TaskHandle, reason string) error {
+	message := protocol.NewMessage(protocol.MessageRoleAgent, []protocol.Part{protocol.NewTextPart(reason)})
+	if err := handle.UpdateStatus(protocol.TaskStateFailed, &message); err != nil {
+		return fmt.Errorf("failed to update task status: %w", err)
+	}
+	return nil
+}
+
+// stringPtr returns a pointer to s.
+func stringPtr(s string) *string {
+	return &s
+}
+
+// boolPtr returns a pointer to b.
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -0,0 +1,172 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package agents
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/client"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/server"
+)
+
+// startAgent serves agent and returns a client of it and the URL of its card.
+func startAgent(t *testing.T, agent Agent) (*client.A2AClient, string) {
+	t.Helper()
+	mux := http.NewServeMux()
+	ts := httptest.NewServer(mux)
+	t.Cleanup(ts.Close)
+	srv, err := NewServer(agent, ts.URL+"/")
+	require.NoError(t, err)
+	mux.Handle("/", srv.Handler())
+	c, err := client.NewA2AClient(ts.URL + "/")
+	require.NoError(t, err)
+	return c, ts.URL + "/.well-known/agent.json"
+}
+
+// streamTask streams a task until its final event.
+func streamTask(t *testing.T, c *client.A2AClient, params protocol.SendTaskParams) []protocol.TaskEvent {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	events, err := c.StreamTask(ctx, params)
+	require.NoError(t, err)
+	var received []protocol.TaskEvent
+	for event := range events {
+		received = append(received, event)
+		if event.IsFinal() {
+			return received
+		}
+	}
+	t.Fatal("stream ended before the final event")
+	return nil
+}
+
+// textMessage returns a user message with text.
+func textMessage(text string) protocol.Message {
+	return protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart(text)})
+}
+
+func TestEcho(t *testing.T) {
+	c, cardURL := startAgent(t, Echo())
+	ctx := context.Background()
+
+	resp, err := http.Get(cardURL)
+	require.NoError(t, err)
+	var card server.AgentCard
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&card))
+	resp.Body.Close()
+	assert.Equal(t, "Echo Agent", card.Name)
+	assert.True(t, card.Capabilities.Streaming)
+
+	task, err := c.SendTasks(ctx, protocol.SendTaskParams{Message: textMessage("hello")})
+	require.NoError(t, err)
+	assert.Equal(t, protocol.TaskStateCompleted, task.Status.State)
+	require.Len(t, task.Artifacts, 1)
+	assert.Equal(t, "hello", task.Artifacts[0].Parts[0].(protocol.TextPart).Text)
+
+	events := streamTask(t, c, protocol.SendTaskParams{Message: textMessage("streamed")})
+	var artifacts []string
+	for _, event := range events {
+		if artifact, ok := event.(protocol.TaskArtifactUpdateEvent); ok {
+			artifacts = append(artifacts, artifact.Artifact.Parts[0].(protocol.TextPart).Text)
+		}
+	}
+	assert.Equal(t, []string{"streamed"}, artifacts)
+
+	task, err = c.SendTasks(ctx, protocol.SendTaskParams{
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{
+			protocol.DataPart{Type: protocol.PartTypeData, Data: map[string]interface{}{}},
+		}),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, protocol.TaskStateFailed, task.Status.State)
+}
+
+func TestLLMProxy(t *testing.T) {
+	llm := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		assert.Equal(t, "Bearer key", r.Header.Get("Authorization"))
+		var request struct {
+			Model    string        `json:"model"`
+			Messages []chatMessage `json:"messages"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		assert.Equal(t, "test-model", request.Model)
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, word := range strings.Fields("you said " + request.Messages[0].Content) {
+			fmt.Fprintf(w, "data: {\"choices\":[{\"delta\":{\"content\":%q}}]}\n\n", word+" ")
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer llm.Close()
+	c, _ := startAgent(t, LLMProxy(LLMProxyConfig{BaseURL: llm.URL + "/v1/", APIKey: "key", Model: "test-model"}))
+
+	events := streamTask(t, c, protocol.SendTaskParams{Message: textMessage("hi")})
+	var chunks []string
+	for _, event := range events {
+		if artifact, ok := event.(protocol.TaskArtifactUpdateEvent); ok {
+			chunks = append(chunks, artifact.Artifact.Parts[0].(protocol.TextPart).Text)
+		}
+	}
+	assert.Equal(t, []string{"you ", "said ", "hi "}, chunks)
+	final := events[len(events)-1].(protocol.TaskStatusUpdateEvent)
+	assert.Equal(t, protocol.TaskStateCompleted, final.Status.State)
+	assert.Equal(t, "you said hi ", final.Status.Message.Parts[0].(protocol.TextPart).Text)
+
+	down, _ := startAgent(t, LLMProxy(LLMProxyConfig{BaseURL: "http://127.0.0.1:1", Model: "test-model"}))
+	task, err := down.SendTasks(context.Background(), protocol.SendTaskParams{Message: textMessage("hi")})
+	require.NoError(t, err)
+	assert.Equal(t, protocol.TaskStateFailed, task.Status.State)
+}
+
+func TestFileConverter(t *testing.T) {
+	c, _ := startAgent(t, FileConverter())
+	file := func(name, mimeType, content string) protocol.Part {
+		encoded := base64.StdEncoding.EncodeToString([]byte(content))
+		return protocol.FilePart{
+			Type: protocol.PartTypeFile,
+			File: protocol.FileContent{Name: &name, MimeType: &mimeType, Bytes: &encoded},
+		}
+	}
+	task, err := c.SendTasks(context.Background(), protocol.SendTaskParams{
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{
+			file("people.csv", MimeTypeCSV, "name,age\nada,36\n"),
+			file("people.json", MimeTypeJSON, `[{"name":"alan","age":41}]`),
+		}),
+	})
+	require.NoError(t, err)
+	require.Equal(t, protocol.TaskStateCompleted, task.Status.State)
+	require.Len(t, task.Artifacts, 2)
+
+	converted := make(map[string]string)
+	for _, artifact := range task.Artifacts {
+		part := artifact.Parts[0].(protocol.FilePart)
+		data, err := base64.StdEncoding.DecodeString(*part.File.Bytes)
+		require.NoError(t, err)
+		converted[*part.File.Name] = string(data)
+	}
+	assert.JSONEq(t, `[{"name":"ada","age":"36"}]`, converted["people.json"])
+	assert.Equal(t, "age,name\n41,alan\n", converted["people.csv"])
+
+	task, err = c.SendTasks(context.Background(), protocol.SendTaskParams{
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{file("notes.txt", "text/plain", "hi")}),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, protocol.TaskStateFailed, task.Status.State)
+}
@@ -0,0 +1,68 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+// Package main runs one of the reference agents.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/examples/agents"
+)
+
+func main() {
+	name := flag.String("agent", "echo", "Agent to run: echo, llm-proxy or file-converter")
+	host := flag.String("host", "localhost", "Host to listen on")
+	port := flag.Int("port", 8080, "Port to listen on")
+	llmURL := flag.String("llm-url", "https://api.openai.com/v1", "Base URL of the chat completion API")
+	llmModel := flag.String("llm-model", "gpt-4o-mini", "Model of the chat completion API")
+	flag.Parse()
+
+	var agent agents.Agent
+	switch *name {
+	case "echo":
+		agent = agents.Echo()
+	case "llm-proxy":
+		agent = agents.LLMProxy(agents.LLMProxyConfig{
+			BaseURL: *llmURL,
+			APIKey:  os.Getenv("LLM_API_KEY"),
+			Model:   *llmModel,
+		})
+	case "file-converter":
+		agent = agents.FileConverter()
+	default:
+		log.Fatalf("Unknown agent %q", *name)
+	}
+
+	addr := fmt.Sprintf("%s:%d", *host, *port)
+	srv, err := agents.NewServer(agent, fmt.Sprintf("http://%s/", addr))
+	if err != nil {
+		log.Fatalf("Failed to create server: %v", err)
+	}
+	go func() {
+		log.Printf("Starting %s on %s...", agent.Card.Name, addr)
+		if err := srv.Start(addr); err != nil {
+			log.Fatalf("Server failed: %v", err)
+		}
+	}()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	sig := <-sigChan
+	log.Printf("Received signal %v, shutting down...", sig)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Stop(ctx); err != nil {
+		log.Printf("Failed to stop server: %v", err)
+	}
+}
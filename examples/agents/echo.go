@@ -0,0 +1,60 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package agents
+
+import (
+	"context"
+	"fmt"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/server"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// Echo returns an agent replying with the text it receives, as an artifact.
+func Echo() Agent {
+	return Agent{
+		Card: newCard("Echo Agent", "Replies with the text it receives", true, server.AgentSkill{
+			ID:          "echo",
+			Name:        "Echo",
+			Description: stringPtr("Echoes the input text"),
+			Tags:        []string{"text"},
+			Examples:    []string{"Hello, world!"},
+			InputModes:  []string{string(protocol.PartTypeText)},
+			OutputModes: []string{string(protocol.PartTypeText)},
+		}),
+		Processor: echoProcessor{},
+	}
+}
+
+// echoProcessor implements taskmanager.TaskProcessor for Echo.
+type echoProcessor struct{}
+
+// Process implements taskmanager.TaskProcessor.
+func (echoProcessor) Process(
+	ctx context.Context,
+	taskID string,
+	message protocol.Message,
+	handle taskmanager.TaskHandle,
+) error {
+	text := textOf(message)
+	if text == "" {
+		return fail(handle, "input message must contain text")
+	}
+	if err := handle.UpdateStatus(protocol.TaskStateWorking, nil); err != nil {
+		return fmt.Errorf("failed to update task status: %w", err)
+	}
+	if err := handle.AddArtifact(protocol.Artifact{
+		Name:      stringPtr("echo"),
+		Parts:     []protocol.Part{protocol.NewTextPart(text)},
+		LastChunk: boolPtr(true),
+	}); err != nil {
+		return fmt.Errorf("failed to add artifact: %w", err)
+	}
+	reply := protocol.NewMessage(protocol.MessageRoleAgent, []protocol.Part{protocol.NewTextPart(text)})
+	return handle.UpdateStatus(protocol.TaskStateCompleted, &reply)
+}
@@ -0,0 +1,179 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package agents
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/server"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// MIME types converted by the file converter agent.
+const (
+	MimeTypeCSV  = "text/csv"
+	MimeTypeJSON = "application/json"
+)
+
+// FileConverter returns an agent converting the CSV files it receives to JSON
+// arrays of objects keyed by the CSV header, and JSON arrays of objects back to
+// CSV, returning an artifact per file.
+func FileConverter() Agent {
+	return Agent{
+		Card: newCard("File Converter Agent", "Converts files between CSV and JSON", false, server.AgentSkill{
+			ID:          "convert",
+			Name:        "Convert",
+			Description: stringPtr("Converts CSV files to JSON and JSON files to CSV"),
+			Tags:        []string{"file", "conversion"},
+			InputModes:  []string{MimeTypeCSV, MimeTypeJSON},
+			OutputModes: []string{MimeTypeJSON, MimeTypeCSV},
+		}),
+		Processor: fileConverterProcessor{},
+	}
+}
+
+// fileConverterProcessor implements taskmanager.TaskProcessor for
+// FileConverter.
+type fileConverterProcessor struct{}
+
+// Process implements taskmanager.TaskProcessor.
+func (fileConverterProcessor) Process(
+	ctx context.Context,
+	taskID string,
+	message protocol.Message,
+	handle taskmanager.TaskHandle,
+) error {
+	var files []protocol.FilePart
+	for _, part := range message.Parts {
+		if filePart, ok := part.(protocol.FilePart); ok {
+			files = append(files, filePart)
+		}
+	}
+	if len(files) == 0 {
+		return fail(handle, "input message must contain files")
+	}
+	if err := handle.UpdateStatus(protocol.TaskStateWorking, nil); err != nil {
+		return fmt.Errorf("failed to update task status: %w", err)
+	}
+	for i, file := range files {
+		converted, err := convertFile(file.File)
+		if err != nil {
+			return fail(handle, fmt.Sprintf("failed to convert file %d: %v", i, err))
+		}
+		if err := handle.AddArtifact(protocol.Artifact{
+			Name:      converted.Name,
+			Parts:     []protocol.Part{protocol.FilePart{Type: protocol.PartTypeFile, File: converted}},
+			Index:     i,
+			LastChunk: boolPtr(true),
+		}); err != nil {
+			return fmt.Errorf("failed to add artifact: %w", err)
+		}
+	}
+	return handle.UpdateStatus(protocol.TaskStateCompleted, nil)
+}
+
+// convertFile converts an inline CSV or JSON file.
+func convertFile(file protocol.FileContent) (protocol.FileContent, error) {
+	if file.Bytes == nil {
+		return protocol.FileContent{}, fmt.Errorf("only inline files are supported")
+	}
+	data, err := base64.StdEncoding.DecodeString(*file.Bytes)
+	if err != nil {
+		return protocol.FileContent{}, fmt.Errorf("invalid file content: %w", err)
+	}
+	var mimeType string
+	if file.MimeType != nil {
+		mimeType = *file.MimeType
+	}
+	var name string
+	if file.Name != nil {
+		name = strings.TrimSuffix(strings.TrimSuffix(*file.Name, ".csv"), ".json")
+	}
+	var out []byte
+	switch mimeType {
+	case MimeTypeCSV:
+		out, err = csvToJSON(data)
+		mimeType, name = MimeTypeJSON, name+".json"
+	case MimeTypeJSON:
+		out, err = jsonToCSV(data)
+		mimeType, name = MimeTypeCSV, name+".csv"
+	default:
+		return protocol.FileContent{}, fmt.Errorf("unsupported MIME type %q", mimeType)
+	}
+	if err != nil {
+		return protocol.FileContent{}, err
+	}
+	encoded := base64.StdEncoding.EncodeToString(out)
+	return protocol.FileContent{Name: &name, MimeType: &mimeType, Bytes: &encoded}, nil
+}
+
+// csvToJSON converts CSV records to a JSON array of objects keyed by the
+// header.
+func csvToJSON(data []byte) ([]byte, error) {
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("missing CSV header")
+	}
+	rows := make([]map[string]string, 0, len(records)-1)
+	for _, record := range records[1:] {
+		row := make(map[string]string, len(record))
+		for i, value := range record {
+			row[records[0][i]] = value
+		}
+		rows = append(rows, row)
+	}
+	return json.Marshal(rows)
+}
+
+// jsonToCSV converts a JSON array of objects to CSV records, with the sorted
+// keys of the objects as the header.
+func jsonToCSV(data []byte) ([]byte, error) {
+	var rows []map[string]interface{}
+	if err := json.Unmarshal(data, &rows); err != nil {
+		return nil, fmt.Errorf("invalid JSON array of objects: %w", err)
+	}
+	keys := make(map[string]bool)
+	for _, row := range rows {
+		for key := range row {
+			keys[key] = true
+		}
+	}
+	header := make([]string, 0, len(keys))
+	for key := range keys {
+		header = append(header, key)
+	}
+	sort.Strings(header)
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		record := make([]string, len(header))
+		for i, key := range header {
+			if value, ok := row[key]; ok && value != nil {
+				record[i] = fmt.Sprint(value)
+			}
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
@@ -0,0 +1,151 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package agents
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/server"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// LLMProxyConfig configures the LLM proxy agent.
+type LLMProxyConfig struct {
+	// BaseURL is the base URL of an OpenAI compatible API, such as
+	// "https://api.openai.com/v1".
+	BaseURL string
+	// APIKey is the bearer token of the API, if any.
+	APIKey string
+	// Model is the model to use.
+	Model string
+	// Client sends the requests. Defaults to a client with a 2 minute
+	// timeout.
+	Client *http.Client
+}
+
+// LLMProxy returns an agent forwarding the text it receives to the chat
+// completion API of cfg and streaming the completion back as artifact chunks.
+func LLMProxy(cfg LLMProxyConfig) Agent {
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: 2 * time.Minute}
+	}
+	return Agent{
+		Card: newCard("LLM Proxy Agent", "Answers prompts with a large language model", true, server.AgentSkill{
+			ID:          "chat",
+			Name:        "Chat",
+			Description: stringPtr("Completes the input prompt with " + cfg.Model),
+			Tags:        []string{"llm", "chat"},
+			Examples:    []string{"Write a haiku about agents."},
+			InputModes:  []string{string(protocol.PartTypeText)},
+			OutputModes: []string{string(protocol.PartTypeText)},
+		}),
+		Processor: &llmProxyProcessor{cfg: cfg},
+	}
+}
+
+// llmProxyProcessor implements taskmanager.TaskProcessor for LLMProxy.
+type llmProxyProcessor struct {
+	cfg LLMProxyConfig
+}
+
+// chatMessage is a message of the chat completion API.
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// chatChunk is a streamed chunk of the chat completion API.
+type chatChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	} `json:"choices"`
+}
+
+// Process implements taskmanager.TaskProcessor.
+func (p *llmProxyProcessor) Process(
+	ctx context.Context,
+	taskID string,
+	message protocol.Message,
+	handle taskmanager.TaskHandle,
+) error {
+	prompt := textOf(message)
+	if prompt == "" {
+		return fail(handle, "input message must contain text")
+	}
+	if err := handle.UpdateStatus(protocol.TaskStateWorking, nil); err != nil {
+		return fmt.Errorf("failed to update task status: %w", err)
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"model":    p.cfg.Model,
+		"messages": []chatMessage{{Role: "user", Content: prompt}},
+		"stream":   true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal completion request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		strings.TrimSuffix(p.cfg.BaseURL, "/")+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create completion request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.cfg.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.cfg.APIKey)
+	}
+	resp, err := p.cfg.Client.Do(req)
+	if err != nil {
+		return fail(handle, fmt.Sprintf("completion request failed: %v", err))
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+		return fail(handle, fmt.Sprintf("completion request failed with status %d: %s", resp.StatusCode, detail))
+	}
+
+	// Forward each chunk of the completion as an artifact chunk.
+	var completion strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok || data == "[DONE]" {
+			continue
+		}
+		var chunk chatChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return fail(handle, fmt.Sprintf("invalid completion chunk: %v", err))
+		}
+		for _, choice := range chunk.Choices {
+			if choice.Delta.Content == "" {
+				continue
+			}
+			completion.WriteString(choice.Delta.Content)
+			if err := handle.AddArtifact(protocol.Artifact{
+				Name:   stringPtr("completion"),
+				Parts:  []protocol.Part{protocol.NewTextPart(choice.Delta.Content)},
+				Append: boolPtr(completion.Len() > len(choice.Delta.Content)),
+			}); err != nil {
+				return fmt.Errorf("failed to add artifact: %w", err)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fail(handle, fmt.Sprintf("failed to read completion: %v", err))
+	}
+	reply := protocol.NewMessage(protocol.MessageRoleAgent, []protocol.Part{protocol.NewTextPart(completion.String())})
+	return handle.UpdateStatus(protocol.TaskStateCompleted, &reply)
+}
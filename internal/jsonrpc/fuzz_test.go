@@ -0,0 +1,60 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package jsonrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"reflect"
+	"testing"
+)
+
+// FuzzDecodeRequest checks that the streaming decoding of requests agrees with
+// their buffered decoding, as the server uses either depending on the codec.
+func FuzzDecodeRequest(f *testing.F) {
+	for _, seed := range []string{
+		`{"jsonrpc":"2.0","id":7,"method":"tasks/get","params":{"id":"t"}}`,
+		`{"jsonrpc":"2.0","id":null,"method":"tasks/send"}`,
+		`{"jsonrpc":"2.0","method":"tasks/send","params":[]}`,
+		`{"jsonrpc":"2.0","id":{"nested":[1,2]},"method":""}`,
+		`{"jsonrpc":"2.0","id":"a","method":"tasks/get","id":"b"}`,
+		`{"id":1e400}`,
+		`[{"jsonrpc":"2.0"}]`,
+		`{"jsonrpc":"2.0","method":"tasks/get"} trailing`,
+		`{"method":"\ud800"}`,
+		`null`,
+		``,
+	} {
+		f.Add([]byte(seed))
+	}
+	decode := func(r io.Reader, v interface{}) error {
+		return json.NewDecoder(r).Decode(v)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		streamed, notification, err := DecodeRequest(bytes.NewReader(data), decode)
+		if err != nil || !json.Valid(data) {
+			return
+		}
+		var buffered Request
+		if err := json.Unmarshal(data, &buffered); err != nil {
+			return
+		}
+		if streamed.Method != buffered.Method {
+			t.Errorf("method %q, buffered %q", streamed.Method, buffered.Method)
+		}
+		if !bytes.Equal(streamed.Params, buffered.Params) {
+			t.Errorf("params %s, buffered %s", streamed.Params, buffered.Params)
+		}
+		if !reflect.DeepEqual(streamed.ID, buffered.ID) {
+			t.Errorf("id %#v, buffered %#v", streamed.ID, buffered.ID)
+		}
+		if notification && streamed.ID != nil {
+			t.Errorf("notification with id %#v", streamed.ID)
+		}
+	})
+}
@@ -9,6 +9,8 @@ package jsonrpc
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -435,3 +437,35 @@ func TestNewNotificationResponse(t *testing.T) {
 		})
 	}
 }
+
+func TestIsNotification(t *testing.T) {
+	data, err := json.Marshal(NewNotification("tasks/send"))
+	require.NoError(t, err)
+	assert.True(t, IsNotification(data))
+	assert.False(t, IsNotification([]byte(`{"jsonrpc":"2.0","id":null,"method":"tasks/send"}`)))
+	assert.False(t, IsNotification([]byte(`{"jsonrpc":"2.0","id":"1","method":"tasks/send"}`)))
+	assert.False(t, IsNotification([]byte(`not json`)))
+}
+
+func TestDecodeRequest(t *testing.T) {
+	decode := func(r io.Reader, v interface{}) error {
+		return json.NewDecoder(r).Decode(v)
+	}
+	request, notification, err := DecodeRequest(strings.NewReader(
+		`{"jsonrpc":"2.0","id":7,"method":"tasks/get","params":{"id":"t"}}`), decode)
+	require.NoError(t, err)
+	assert.False(t, notification)
+	assert.Equal(t, float64(7), request.ID)
+	assert.Equal(t, "tasks/get", request.Method)
+	assert.JSONEq(t, `{"id":"t"}`, string(request.Params))
+
+	_, notification, err = DecodeRequest(strings.NewReader(`{"jsonrpc":"2.0","id":null,"method":"tasks/send"}`), decode)
+	require.NoError(t, err)
+	assert.False(t, notification)
+	_, notification, err = DecodeRequest(strings.NewReader(`{"jsonrpc":"2.0","method":"tasks/send"}`), decode)
+	require.NoError(t, err)
+	assert.True(t, notification)
+
+	_, _, err = DecodeRequest(strings.NewReader(`not json`), decode)
+	assert.Error(t, err)
+}
@@ -6,7 +6,10 @@
 
 package jsonrpc
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"io"
+)
 
 // Request represents a JSON-RPC request object.
 type Request struct {
@@ -29,3 +32,51 @@ func NewRequest(method string, id interface{}) *Request {
 		Method: method,
 	}
 }
+
+// NewNotification creates a new JSON-RPC notification, a request without ID
+// to which the server does not respond.
+func NewNotification(method string) *Request {
+	return NewRequest(method, nil)
+}
+
+// IsNotification reports whether the raw request is a notification, i.e. has
+// no id member. A request with a null id is not a notification.
+func IsNotification(data []byte) bool {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return false
+	}
+	_, hasID := members["id"]
+	return !hasID
+}
+
+// requestEnvelope is the wire form of a Request, keeping the raw id to tell
+// notifications from requests with a null id.
+type requestEnvelope struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+}
+
+// DecodeRequest decodes a single request from r with decode, such as the
+// Decode method of a codec.StreamDecoder, and returns the request and whether
+// it is a notification. decode may buffer the whole request, and the params
+// of the request are held raw in memory.
+func DecodeRequest(r io.Reader, decode func(r io.Reader, v interface{}) error) (Request, bool, error) {
+	var envelope requestEnvelope
+	if err := decode(r, &envelope); err != nil {
+		return Request{}, false, err
+	}
+	request := Request{
+		Message: Message{JSONRPC: envelope.JSONRPC},
+		Method:  envelope.Method,
+		Params:  envelope.Params,
+	}
+	if envelope.ID != nil {
+		if err := json.Unmarshal(envelope.ID, &request.ID); err != nil {
+			return Request{}, false, err
+		}
+	}
+	return request, envelope.ID == nil, nil
+}
@@ -0,0 +1,152 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+// Package ndjson writes and reads the newline-delimited JSON event streams
+// that the server sends instead of SSE to clients accepting
+// protocol.ContentTypeNDJSON.
+package ndjson
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/codec"
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+)
+
+// Writer writes the events of a stream to an HTTP response as
+// newline-delimited JSON: one JSON-RPC response per line, carrying the event
+// type and ID in the "event" and "eventId" members. Like sse.Writer, each line
+// is sent with a single write followed by a single flush.
+// A Writer is not safe for concurrent use.
+type Writer struct {
+	w            http.ResponseWriter
+	rc           *http.ResponseController
+	codec        codec.Codec
+	writeTimeout time.Duration
+}
+
+// NewWriter creates a Writer for w, with the write timeout semantics of
+// sse.NewWriter.
+func NewWriter(w http.ResponseWriter, c codec.Codec, writeTimeout time.Duration) *Writer {
+	return &Writer{
+		w:            w,
+		rc:           http.NewResponseController(w),
+		codec:        codec.OrDefault(c),
+		writeTimeout: writeTimeout,
+	}
+}
+
+// WriteJSONRPCEventWithID writes a line holding a JSON-RPC response with the
+// given id and the already encoded result, tagged with eventType and with
+// eventID unless it is empty.
+func (w *Writer) WriteJSONRPCEventWithID(eventID, eventType string, id interface{}, result []byte) error {
+	var buf bytes.Buffer
+	buf.WriteString("{\"jsonrpc\":\"")
+	buf.WriteString(jsonrpc.Version)
+	buf.WriteByte('"')
+	if id != nil {
+		idData, err := w.codec.Marshal(id)
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON-RPC NDJSON event id: %w", err)
+		}
+		buf.WriteString(",\"id\":")
+		buf.Write(idData)
+	}
+	typeData, err := json.Marshal(eventType)
+	if err != nil {
+		return fmt.Errorf("failed to marshal NDJSON event type: %w", err)
+	}
+	buf.WriteString(",\"event\":")
+	buf.Write(typeData)
+	if eventID != "" {
+		idData, err := json.Marshal(eventID)
+		if err != nil {
+			return fmt.Errorf("failed to marshal NDJSON event ID: %w", err)
+		}
+		buf.WriteString(",\"eventId\":")
+		buf.Write(idData)
+	}
+	buf.WriteString(",\"result\":")
+	buf.Write(result)
+	buf.WriteString("}\n")
+	return w.send(buf.Bytes())
+}
+
+// send writes a complete line and flushes it.
+func (w *Writer) send(line []byte) error {
+	if w.writeTimeout > 0 {
+		err := w.rc.SetWriteDeadline(time.Now().Add(w.writeTimeout))
+		if err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return fmt.Errorf("failed to set NDJSON write deadline: %w", err)
+		}
+	}
+	if _, err := w.w.Write(line); err != nil {
+		return fmt.Errorf("failed to write NDJSON event: %w", err)
+	}
+	if err := w.rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return fmt.Errorf("failed to flush NDJSON event: %w", err)
+	}
+	return nil
+}
+
+// Reader reads the events of a newline-delimited JSON stream written by
+// Writer. Unlike sse.EventReader, it does not limit the length of events.
+type Reader struct {
+	reader      *bufio.Reader
+	lastEventID string
+}
+
+// NewReader creates a reader of the NDJSON events of r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{reader: bufio.NewReader(r)}
+}
+
+// LastEventID returns the event ID of the last event read, if it had one.
+func (r *Reader) LastEventID() string {
+	return r.lastEventID
+}
+
+// Retry implements the same method of sse.EventReader. NDJSON streams do not
+// advise a reconnection time.
+func (r *Reader) Retry() (time.Duration, bool) {
+	return 0, false
+}
+
+// ReadEvent reads the next event of the stream. It returns the line holding
+// the event, which is the JSON-RPC response wrapping it, and its event type.
+// Blank lines are skipped as keep-alives. The error is io.EOF at the end of the
+// stream.
+func (r *Reader) ReadEvent() (data []byte, eventType string, err error) {
+	for {
+		line, err := r.reader.ReadBytes('\n')
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			if err != nil {
+				return nil, "", err
+			}
+			continue
+		}
+		if err != nil && err != io.EOF {
+			return nil, "", err
+		}
+		var header struct {
+			Event   string `json:"event"`
+			EventID string `json:"eventId"`
+		}
+		if err := json.Unmarshal(line, &header); err != nil {
+			return nil, "", fmt.Errorf("malformed NDJSON event: %w", err)
+		}
+		r.lastEventID = header.EventID
+		return line, header.Event, nil
+	}
+}
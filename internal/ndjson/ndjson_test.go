@@ -0,0 +1,93 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package ndjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+)
+
+// countingRecorder counts writes and flushes made to a response.
+type countingRecorder struct {
+	*httptest.ResponseRecorder
+	writes, flushes int
+}
+
+func (r *countingRecorder) Write(p []byte) (int, error) {
+	r.writes++
+	return r.ResponseRecorder.Write(p)
+}
+
+func (r *countingRecorder) Flush() {
+	r.flushes++
+	r.ResponseRecorder.Flush()
+}
+
+func TestWriter_WriteJSONRPCEventWithID(t *testing.T) {
+	rec := &countingRecorder{ResponseRecorder: httptest.NewRecorder()}
+	w := NewWriter(rec, nil, time.Second)
+	require.NoError(t, w.WriteJSONRPCEventWithID("7", "task_status_update", "req-1", []byte(`{"id":"t1"}`)))
+	require.NoError(t, w.WriteJSONRPCEventWithID("", "close", nil, []byte(`{"taskId":"t1"}`)))
+	assert.Equal(t, 2, rec.writes, "each event should be written at once")
+	assert.Equal(t, 2, rec.flushes, "each event should be flushed once")
+
+	lines := strings.Split(strings.TrimSuffix(rec.Body.String(), "\n"), "\n")
+	require.Len(t, lines, 2, "one line per event")
+	var resp jsonrpc.RawResponse
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &resp))
+	assert.Equal(t, jsonrpc.Version, resp.JSONRPC)
+	assert.Equal(t, "req-1", resp.ID)
+	assert.JSONEq(t, `{"id":"t1"}`, string(resp.Result))
+	assert.JSONEq(t,
+		`{"jsonrpc":"2.0","event":"close","result":{"taskId":"t1"}}`, lines[1])
+
+	reader := NewReader(bytes.NewReader(rec.Body.Bytes()))
+	data, eventType, err := reader.ReadEvent()
+	require.NoError(t, err)
+	assert.Equal(t, "task_status_update", eventType)
+	assert.Equal(t, "7", reader.LastEventID())
+	assert.Equal(t, lines[0], string(data))
+	_, eventType, err = reader.ReadEvent()
+	require.NoError(t, err)
+	assert.Equal(t, "close", eventType)
+	assert.Empty(t, reader.LastEventID())
+	_, _, err = reader.ReadEvent()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestReader_ReadEvent(t *testing.T) {
+	large := strings.Repeat("x", 128<<10)
+	stream := "\n" +
+		`{"jsonrpc":"2.0","event":"task_artifact_update","result":{"text":"` + large + `"}}` + "\n" +
+		"\r\n" +
+		`{"jsonrpc":"2.0","event":"close","result":{}}`
+	reader := NewReader(strings.NewReader(stream))
+
+	data, eventType, err := reader.ReadEvent()
+	require.NoError(t, err, "blank lines are skipped and long lines are read whole")
+	assert.Equal(t, "task_artifact_update", eventType)
+	assert.Contains(t, string(data), large)
+
+	_, eventType, err = reader.ReadEvent()
+	require.NoError(t, err, "the last line needs no newline")
+	assert.Equal(t, "close", eventType)
+	_, _, err = reader.ReadEvent()
+	assert.Equal(t, io.EOF, err)
+
+	_, _, err = NewReader(strings.NewReader("not json\n")).ReadEvent()
+	assert.Error(t, err)
+}
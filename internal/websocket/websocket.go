@@ -0,0 +1,386 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+// Package websocket implements the subset of the WebSocket protocol (RFC 6455)
+// carrying JSON-RPC messages: the opening handshake over net/http, text and
+// binary messages, possibly fragmented, and the ping, pong and close control
+// frames. Extensions and subprotocols are not supported.
+package websocket
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Frame opcodes.
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+)
+
+// Close status codes.
+const (
+	closeNormal        = 1000
+	closeProtocolError = 1002
+	closeTooLarge      = 1009
+)
+
+// acceptGUID is appended to the handshake key to compute the accept key.
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// DefaultMaxMessageSize is the default limit of the size of received messages.
+const DefaultMaxMessageSize = 10 << 20
+
+var (
+	// ErrMessageTooLarge is returned when a message exceeds the limit set by
+	// SetMaxMessageSize. The connection is then closed.
+	ErrMessageTooLarge = errors.New("websocket: message too large")
+	// ErrClosed is returned when writing to a connection already closed.
+	ErrClosed = errors.New("websocket: connection closed")
+	// errProtocol is returned for frames violating the protocol.
+	errProtocol = errors.New("websocket: protocol error")
+)
+
+// IsUpgrade reports whether r asks to upgrade the connection to WebSocket.
+func IsUpgrade(r *http.Request) bool {
+	return r.Method == http.MethodGet &&
+		headerHasToken(r.Header, "Connection", "upgrade") &&
+		headerHasToken(r.Header, "Upgrade", "websocket")
+}
+
+// headerHasToken reports whether a comma-separated header contains token,
+// case-insensitively.
+func headerHasToken(h http.Header, name, token string) bool {
+	for _, value := range h.Values(name) {
+		for _, t := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// acceptKey returns the Sec-WebSocket-Accept value for key.
+func acceptKey(key string) string {
+	sum := sha1.Sum([]byte(key + acceptGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// Upgrade completes the opening handshake of r and returns the server side
+// of the connection. On failure, an HTTP error has been written to w.
+func Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	if !IsUpgrade(r) {
+		http.Error(w, "WebSocket upgrade required", http.StatusBadRequest)
+		return nil, errors.New("websocket: not a WebSocket handshake")
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "unsupported WebSocket version", http.StatusUpgradeRequired)
+		return nil, errors.New("websocket: unsupported version")
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		http.Error(w, "missing Sec-WebSocket-Key", http.StatusBadRequest)
+		return nil, errors.New("websocket: missing key")
+	}
+	netConn, brw, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		http.Error(w, "WebSocket not supported", http.StatusInternalServerError)
+		return nil, fmt.Errorf("websocket: failed to hijack connection: %w", err)
+	}
+	// Clear the deadlines of the HTTP server, which do not apply to the
+	// long-lived connection.
+	_ = netConn.SetDeadline(time.Time{})
+	_, _ = brw.WriteString("HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + acceptKey(key) + "\r\n\r\n")
+	if err := brw.Flush(); err != nil {
+		netConn.Close()
+		return nil, fmt.Errorf("websocket: failed to write handshake: %w", err)
+	}
+	return newConn(netConn, brw.Reader, false), nil
+}
+
+// SetHandshakeHeaders sets the headers of the opening handshake on h, the
+// headers of a GET request, and returns the key to pass to NewClientConn.
+func SetHandshakeHeaders(h http.Header) (string, error) {
+	var nonce [16]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return "", fmt.Errorf("websocket: failed to generate key: %w", err)
+	}
+	key := base64.StdEncoding.EncodeToString(nonce[:])
+	h.Set("Connection", "Upgrade")
+	h.Set("Upgrade", "websocket")
+	h.Set("Sec-WebSocket-Version", "13")
+	h.Set("Sec-WebSocket-Key", key)
+	return key, nil
+}
+
+// NewClientConn returns the client side of the connection opened by resp, the
+// response to a handshake request with headers set by SetHandshakeHeaders.
+// Net/http returns the connection as the body of 101 responses, unless the
+// http.Client sending the request has a Timeout, so the handshake should be
+// bounded by the context of the request instead. The body of resp is closed on
+// failure.
+func NewClientConn(resp *http.Response, key string) (*Conn, error) {
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		resp.Body.Close()
+		return nil, fmt.Errorf("websocket: handshake failed with status %d", resp.StatusCode)
+	}
+	if !headerHasToken(resp.Header, "Upgrade", "websocket") ||
+		resp.Header.Get("Sec-WebSocket-Accept") != acceptKey(key) {
+		resp.Body.Close()
+		return nil, errors.New("websocket: invalid handshake response")
+	}
+	rwc, ok := resp.Body.(io.ReadWriteCloser)
+	if !ok {
+		resp.Body.Close()
+		return nil, errors.New("websocket: response body is not writable")
+	}
+	return newConn(rwc, bufio.NewReader(rwc), true), nil
+}
+
+// Conn is a WebSocket connection. ReadMessage must not be called
+// concurrently; WriteMessage and Close may be called concurrently with each
+// other and with ReadMessage.
+type Conn struct {
+	rwc            io.ReadWriteCloser
+	br             *bufio.Reader
+	client         bool // Whether frames are sent masked, as clients do.
+	maxMessageSize int64
+
+	writeMu   sync.Mutex
+	closeSent bool
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// newConn creates a connection reading from br and writing to rwc.
+func newConn(rwc io.ReadWriteCloser, br *bufio.Reader, client bool) *Conn {
+	return &Conn{rwc: rwc, br: br, client: client, maxMessageSize: DefaultMaxMessageSize}
+}
+
+// SetMaxMessageSize limits the size of received messages. Non-positive
+// values select DefaultMaxMessageSize.
+func (c *Conn) SetMaxMessageSize(size int64) {
+	if size <= 0 {
+		size = DefaultMaxMessageSize
+	}
+	c.maxMessageSize = size
+}
+
+// ReadMessage reads the next text or binary message. Pings are answered while
+// reading. It returns io.EOF once the peer closed the connection.
+func (c *Conn) ReadMessage() ([]byte, error) {
+	var message []byte
+	started := false
+	for {
+		fin, op, payload, err := c.readFrame(c.maxMessageSize - int64(len(message)))
+		if err != nil {
+			switch {
+			case errors.Is(err, ErrMessageTooLarge):
+				c.fail(closeTooLarge)
+			case errors.Is(err, errProtocol):
+				c.fail(closeProtocolError)
+			}
+			return nil, err
+		}
+		switch op {
+		case opPing:
+			if err := c.writeFrame(true, opPong, payload); err != nil && !errors.Is(err, ErrClosed) {
+				return nil, err
+			}
+			continue
+		case opPong:
+			continue
+		case opClose:
+			// Echo the status code of the peer and close.
+			if len(payload) >= 2 {
+				payload = payload[:2]
+			}
+			_ = c.writeFrame(true, opClose, payload)
+			c.closeConn()
+			return nil, io.EOF
+		case opText, opBinary:
+			if started {
+				c.fail(closeProtocolError)
+				return nil, fmt.Errorf("%w: new message within a fragmented message", errProtocol)
+			}
+			started = true
+		case opContinuation:
+			if !started {
+				c.fail(closeProtocolError)
+				return nil, fmt.Errorf("%w: unexpected continuation frame", errProtocol)
+			}
+		default:
+			c.fail(closeProtocolError)
+			return nil, fmt.Errorf("%w: unknown opcode %d", errProtocol, op)
+		}
+		message = append(message, payload...)
+		if fin {
+			return message, nil
+		}
+	}
+}
+
+// readFrame reads a frame whose payload, unless it is a control frame, is at
+// most limit bytes.
+func (c *Conn) readFrame(limit int64) (fin bool, op byte, payload []byte, err error) {
+	var header [2]byte
+	if _, err := io.ReadFull(c.br, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin, op = header[0]&0x80 != 0, header[0]&0x0F
+	if header[0]&0x70 != 0 {
+		return false, 0, nil, fmt.Errorf("%w: reserved bits set", errProtocol)
+	}
+	if masked := header[1]&0x80 != 0; masked == c.client {
+		return false, 0, nil, fmt.Errorf("%w: invalid masking", errProtocol)
+	}
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if op >= opClose {
+		if !fin || length > 125 {
+			return false, 0, nil, fmt.Errorf("%w: invalid control frame", errProtocol)
+		}
+	} else if length > uint64(limit) {
+		return false, 0, nil, ErrMessageTooLarge
+	}
+	var mask [4]byte
+	if !c.client {
+		if _, err := io.ReadFull(c.br, mask[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(c.br, payload); err != nil {
+		return false, 0, nil, err
+	}
+	if !c.client {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return fin, op, payload, nil
+}
+
+// SetWriteDeadline sets the deadline of the writes of the connection, if the
+// underlying connection supports deadlines. A zero t clears it.
+func (c *Conn) SetWriteDeadline(t time.Time) error {
+	if conn, ok := c.rwc.(interface{ SetWriteDeadline(time.Time) error }); ok {
+		return conn.SetWriteDeadline(t)
+	}
+	return nil
+}
+
+// WriteMessage writes data as a text message.
+func (c *Conn) WriteMessage(data []byte) error {
+	return c.writeFrame(true, opText, data)
+}
+
+// writeFrame writes a frame with a single write. Messages are always sent in
+// a single final frame.
+func (c *Conn) writeFrame(fin bool, op byte, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if c.closeSent {
+		return ErrClosed
+	}
+	frame := make([]byte, 0, 14+len(payload))
+	if fin {
+		frame = append(frame, 0x80|op)
+	} else {
+		frame = append(frame, op)
+	}
+	var maskBit byte
+	if c.client {
+		maskBit = 0x80
+	}
+	switch n := len(payload); {
+	case n <= 125:
+		frame = append(frame, maskBit|byte(n))
+	case n <= 0xFFFF:
+		frame = append(frame, maskBit|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(n))
+	default:
+		frame = append(frame, maskBit|127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+	if c.client {
+		var mask [4]byte
+		if _, err := rand.Read(mask[:]); err != nil {
+			return fmt.Errorf("websocket: failed to generate mask: %w", err)
+		}
+		frame = append(frame, mask[:]...)
+		start := len(frame)
+		frame = append(frame, payload...)
+		for i := range frame[start:] {
+			frame[start+i] ^= mask[i%4]
+		}
+	} else {
+		frame = append(frame, payload...)
+	}
+	if op == opClose {
+		c.closeSent = true
+	}
+	if _, err := c.rwc.Write(frame); err != nil {
+		return fmt.Errorf("websocket: failed to write frame: %w", err)
+	}
+	return nil
+}
+
+// fail closes the connection with status code.
+func (c *Conn) fail(code uint16) {
+	_ = c.writeFrame(true, opClose, binary.BigEndian.AppendUint16(nil, code))
+	c.closeConn()
+}
+
+// closeConn closes the underlying connection once.
+func (c *Conn) closeConn() {
+	c.closeOnce.Do(func() {
+		c.closeErr = c.rwc.Close()
+		if errors.Is(c.closeErr, net.ErrClosed) {
+			c.closeErr = nil
+		}
+	})
+}
+
+// Close sends a close frame, unless one was sent, and closes the connection.
+// A pending ReadMessage then fails.
+func (c *Conn) Close() error {
+	_ = c.writeFrame(true, opClose, binary.BigEndian.AppendUint16(nil, closeNormal))
+	c.closeConn()
+	return c.closeErr
+}
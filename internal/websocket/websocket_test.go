@@ -0,0 +1,155 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package websocket
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dial opens a client connection to server.
+func dial(t *testing.T, client *http.Client, url string) *Conn {
+	t.Helper()
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+	require.NoError(t, err)
+	key, err := SetHandshakeHeaders(req.Header)
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	conn, err := NewClientConn(resp, key)
+	require.NoError(t, err)
+	return conn
+}
+
+// echoServer echoes the messages it receives, prefixed with "echo: ".
+func echoServer(t *testing.T, maxMessageSize int64) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := Upgrade(w, r)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.SetMaxMessageSize(maxMessageSize)
+		for {
+			message, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if err := conn.WriteMessage(append([]byte("echo: "), message...)); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestConn_Echo(t *testing.T) {
+	server := echoServer(t, 0)
+	conn := dial(t, http.DefaultClient, server.URL)
+	defer conn.Close()
+
+	for _, message := range []string{"hello", strings.Repeat("a", 200), strings.Repeat("b", 70000)} {
+		require.NoError(t, conn.WriteMessage([]byte(message)))
+		reply, err := conn.ReadMessage()
+		require.NoError(t, err)
+		assert.Equal(t, "echo: "+message, string(reply))
+	}
+
+	// A client with a timeout does not return the connection.
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	key, err := SetHandshakeHeaders(req.Header)
+	require.NoError(t, err)
+	resp, err := (&http.Client{Timeout: time.Minute}).Do(req)
+	require.NoError(t, err)
+	_, err = NewClientConn(resp, key)
+	assert.Error(t, err)
+
+	require.NoError(t, conn.Close())
+	assert.ErrorIs(t, conn.WriteMessage([]byte("closed")), ErrClosed)
+}
+
+func TestConn_FragmentsAndControlFrames(t *testing.T) {
+	server := echoServer(t, 0)
+	conn := dial(t, http.DefaultClient, server.URL)
+	defer conn.Close()
+
+	// A message fragmented around a ping, which is answered with a pong.
+	require.NoError(t, conn.writeFrame(false, opText, []byte("frag")))
+	require.NoError(t, conn.writeFrame(true, opPing, []byte("p")))
+	require.NoError(t, conn.writeFrame(true, opContinuation, []byte("mented")))
+	fin, op, payload, err := conn.readFrame(1 << 20)
+	require.NoError(t, err)
+	assert.True(t, fin)
+	assert.Equal(t, byte(opPong), op)
+	assert.Equal(t, "p", string(payload))
+	reply, err := conn.ReadMessage()
+	require.NoError(t, err)
+	assert.Equal(t, "echo: fragmented", string(reply))
+
+	// A continuation frame without a message is a protocol error, which
+	// closes the connection.
+	require.NoError(t, conn.writeFrame(true, opContinuation, []byte("x")))
+	_, err = conn.ReadMessage()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestConn_MessageTooLarge(t *testing.T) {
+	server := echoServer(t, 8)
+	conn := dial(t, http.DefaultClient, server.URL)
+	defer conn.Close()
+	require.NoError(t, conn.WriteMessage([]byte("12345678")))
+	_, err := conn.ReadMessage()
+	require.NoError(t, err)
+	require.NoError(t, conn.WriteMessage([]byte("123456789")))
+	_, err = conn.ReadMessage()
+	assert.Equal(t, io.EOF, err, "the server closes the connection")
+}
+
+func TestUpgrade_Errors(t *testing.T) {
+	server := echoServer(t, 0)
+	resp, err := http.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	_, err = SetHandshakeHeaders(req.Header)
+	require.NoError(t, err)
+	req.Header.Set("Sec-WebSocket-Version", "8")
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	_, err = NewClientConn(resp, "")
+	assert.ErrorContains(t, err, "426")
+}
+
+func TestReadFrame_RejectsUnmaskedClientFrames(t *testing.T) {
+	// Servers only accept masked frames.
+	frame := []byte{0x81, 0x01, 'a'}
+	conn := newConn(nopConn{}, bufio.NewReader(bytes.NewReader(frame)), false)
+	_, err := conn.ReadMessage()
+	assert.ErrorIs(t, err, errProtocol)
+}
+
+// nopConn is a connection discarding writes.
+type nopConn struct{}
+
+func (nopConn) Read([]byte) (int, error)    { return 0, io.EOF }
+func (nopConn) Write(p []byte) (int, error) { return len(p), nil }
+func (nopConn) Close() error                { return nil }
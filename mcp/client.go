@@ -0,0 +1,204 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/sse"
+)
+
+// Client calls the tools of an MCP server over the streamable HTTP transport.
+// It initializes the session on first use. It is safe for concurrent use.
+type Client struct {
+	endpoint   string
+	httpClient *http.Client
+	info       Implementation
+	nextID     atomic.Int64
+
+	mu          sync.Mutex
+	initialized bool
+	sessionID   string
+	server      InitializeResult
+}
+
+// NewClient creates a client of the MCP server at endpoint, the URL of its
+// streamable HTTP endpoint.
+func NewClient(endpoint string, opts ...ClientOption) (*Client, error) {
+	if u, err := url.Parse(endpoint); err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("mcp: invalid server URL %q", endpoint)
+	}
+	c := &Client{
+		endpoint:   endpoint,
+		httpClient: http.DefaultClient,
+		info:       Implementation{Name: "trpc-a2a-go", Version: ProtocolVersion},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// Initialize initializes the MCP session, if not done yet, and returns what
+// the server reported about itself.
+func (c *Client) Initialize(ctx context.Context) (*InitializeResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.initialized {
+		result := c.server
+		return &result, nil
+	}
+	params := InitializeParams{
+		ProtocolVersion: ProtocolVersion,
+		Capabilities:    map[string]interface{}{},
+		ClientInfo:      c.info,
+	}
+	var result InitializeResult
+	resp, err := c.post(ctx, MethodInitialize, params, &result)
+	if err != nil {
+		return nil, fmt.Errorf("mcp: initialize failed: %w", err)
+	}
+	c.sessionID = resp.Header.Get(SessionIDHeader)
+	if _, err := c.post(ctx, MethodInitialized, nil, nil); err != nil {
+		return nil, fmt.Errorf("mcp: initialized notification failed: %w", err)
+	}
+	c.initialized = true
+	c.server = result
+	return &result, nil
+}
+
+// ListTools returns all the tools of the server, following pagination.
+func (c *Client) ListTools(ctx context.Context) ([]Tool, error) {
+	var tools []Tool
+	params := ListToolsParams{}
+	for {
+		var result ListToolsResult
+		if err := c.call(ctx, MethodToolsList, params, &result); err != nil {
+			return nil, fmt.Errorf("mcp: tools/list failed: %w", err)
+		}
+		tools = append(tools, result.Tools...)
+		if result.NextCursor == "" {
+			return tools, nil
+		}
+		params.Cursor = result.NextCursor
+	}
+}
+
+// CallTool calls the tool name with arguments. Failures of the tool itself are
+// reported by the IsError field of the result, not as errors.
+func (c *Client) CallTool(ctx context.Context, name string, arguments map[string]interface{}) (*CallToolResult, error) {
+	var result CallToolResult
+	params := CallToolParams{Name: name, Arguments: arguments}
+	if err := c.call(ctx, MethodToolsCall, params, &result); err != nil {
+		return nil, fmt.Errorf("mcp: tools/call %s failed: %w", name, err)
+	}
+	return &result, nil
+}
+
+// call sends a request in the initialized session and decodes its result into out.
+func (c *Client) call(ctx context.Context, method string, params, out interface{}) error {
+	if _, err := c.Initialize(ctx); err != nil {
+		return err
+	}
+	_, err := c.post(ctx, method, params, out)
+	return err
+}
+
+// post sends a request, or a notification if out is nil, and decodes the
+// result of the response into out. The response may be a JSON body or an SSE
+// stream holding it.
+func (c *Client) post(ctx context.Context, method string, params, out interface{}) (*http.Response, error) {
+	var id interface{}
+	if out != nil {
+		id = c.nextID.Add(1)
+	}
+	request := jsonrpc.NewRequest(method, id)
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal params: %w", err)
+		}
+		request.Params = data
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create http request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	if c.sessionID != "" {
+		req.Header.Set(SessionIDHeader, c.sessionID)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("http request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("unexpected http status %d: %s", resp.StatusCode, bytes.TrimSpace(data))
+	}
+	if out == nil {
+		return resp, nil
+	}
+	response, err := readResponse(resp, request.ID)
+	if err != nil {
+		return nil, err
+	}
+	if response.Error != nil {
+		return nil, response.Error
+	}
+	if err := json.Unmarshal(response.Result, out); err != nil {
+		return nil, fmt.Errorf("failed to decode %s result: %w", method, err)
+	}
+	return resp, nil
+}
+
+// readResponse reads the response to the request with id from resp, skipping
+// the server requests and notifications an SSE stream may carry first.
+func readResponse(resp *http.Response, id interface{}) (*jsonrpc.RawResponse, error) {
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "text/event-stream" {
+		var response jsonrpc.RawResponse
+		if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		return &response, nil
+	}
+	want := fmt.Sprint(id)
+	reader := sse.NewEventReader(resp.Body)
+	for {
+		data, _, err := reader.ReadEvent()
+		if len(data) > 0 {
+			var response jsonrpc.RawResponse
+			if json.Unmarshal(data, &response) == nil && fmt.Sprint(response.ID) == want {
+				return &response, nil
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("stream ended without a response")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read event stream: %w", err)
+		}
+	}
+}
@@ -0,0 +1,260 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package mcp
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"trpc.group/trpc-go/trpc-a2a-go/client"
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/orchestrator"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/server"
+)
+
+// messageArgument is the argument of the tools served by NewHandler.
+const messageArgument = "message"
+
+// messageSchema is the input schema of the tools served by NewHandler.
+var messageSchema = json.RawMessage(`{"type":"object","properties":{"message":` +
+	`{"type":"string","description":"The message to send to the agent."}},"required":["message"]}`)
+
+// handler serves the skills of an A2A agent as MCP tools.
+type handler struct {
+	agent *client.A2AClient
+	card  server.AgentCard
+	info  Implementation
+}
+
+// NewHandler returns an MCP streamable HTTP endpoint serving each skill of card
+// as a tool taking a message for the agent, which is reached through agent.
+// Calling a tool sends the message as a new task naming the skill under
+// orchestrator.MetadataKeySkill, and returns the artifacts and final status
+// message of the task; failed and canceled tasks are reported as tool errors.
+// Responses are plain JSON; server-initiated streams are not supported.
+func NewHandler(agent *client.A2AClient, card server.AgentCard, opts ...HandlerOption) http.Handler {
+	h := &handler{
+		agent: agent,
+		card:  card,
+		info:  Implementation{Name: card.Name, Version: card.Version},
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// ServeHTTP implements http.Handler.
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var request jsonrpc.Request
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.writeResponse(w, jsonrpc.NewErrorResponse(nil, jsonrpc.ErrParseError(err.Error())))
+		return
+	}
+	if request.ID == nil {
+		// Notifications, e.g. notifications/initialized, need no answer.
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	var (
+		result interface{}
+		rpcErr *jsonrpc.Error
+	)
+	switch request.Method {
+	case MethodInitialize:
+		sessionID, err := newID()
+		if err != nil {
+			rpcErr = jsonrpc.ErrInternalError(err.Error())
+			break
+		}
+		w.Header().Set(SessionIDHeader, sessionID)
+		result = InitializeResult{
+			ProtocolVersion: ProtocolVersion,
+			Capabilities:    map[string]interface{}{"tools": map[string]interface{}{}},
+			ServerInfo:      h.info,
+			Instructions:    deref(h.card.Description),
+		}
+	case MethodPing:
+		result = struct{}{}
+	case MethodToolsList:
+		result = ListToolsResult{Tools: h.tools()}
+	case MethodToolsCall:
+		result, rpcErr = h.callTool(r, request.Params)
+	default:
+		rpcErr = jsonrpc.ErrMethodNotFound(request.Method)
+	}
+	if rpcErr != nil {
+		h.writeResponse(w, jsonrpc.NewErrorResponse(request.ID, rpcErr))
+		return
+	}
+	h.writeResponse(w, jsonrpc.NewResponse(request.ID, result))
+}
+
+// tools returns the tools of the skills of the agent.
+func (h *handler) tools() []Tool {
+	tools := make([]Tool, 0, len(h.card.Skills))
+	for _, skill := range h.card.Skills {
+		description := skill.Name
+		if skill.Description != nil && *skill.Description != "" {
+			description = *skill.Description
+		}
+		tools = append(tools, Tool{Name: skill.ID, Description: description, InputSchema: messageSchema})
+	}
+	return tools
+}
+
+// callTool runs the tools/call request with params as a task of the agent.
+func (h *handler) callTool(r *http.Request, params json.RawMessage) (*CallToolResult, *jsonrpc.Error) {
+	var call CallToolParams
+	if err := json.Unmarshal(params, &call); err != nil {
+		return nil, jsonrpc.ErrInvalidParams(err.Error())
+	}
+	if !h.hasSkill(call.Name) {
+		return nil, jsonrpc.ErrInvalidParams(fmt.Sprintf("unknown tool %q", call.Name))
+	}
+	text, ok := call.Arguments[messageArgument].(string)
+	if !ok {
+		return nil, jsonrpc.ErrInvalidParams(fmt.Sprintf("argument %q must be a string", messageArgument))
+	}
+	taskID, err := newID()
+	if err != nil {
+		return nil, jsonrpc.ErrInternalError(err.Error())
+	}
+	skill := map[string]interface{}{orchestrator.MetadataKeySkill: call.Name}
+	message := protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart(text)})
+	message.Metadata = skill
+	task, err := h.agent.SendTasks(r.Context(), protocol.SendTaskParams{
+		ID:       taskID,
+		Message:  message,
+		Metadata: skill,
+	})
+	if err != nil {
+		log.Infof("Agent failed tool call %s: %v", call.Name, err)
+		return &CallToolResult{Content: []Content{TextContent(err.Error())}, IsError: true}, nil
+	}
+	return taskResult(task), nil
+}
+
+// hasSkill reports whether the agent card declares the skill skillID.
+func (h *handler) hasSkill(skillID string) bool {
+	for _, skill := range h.card.Skills {
+		if skill.ID == skillID {
+			return true
+		}
+	}
+	return false
+}
+
+// writeResponse writes a JSON-RPC response.
+func (h *handler) writeResponse(w http.ResponseWriter, response *jsonrpc.Response) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Errorf("Failed to write MCP response: %v", err)
+	}
+}
+
+// taskResult converts the artifacts and status message of task to a tool result.
+func taskResult(task *protocol.Task) *CallToolResult {
+	result := &CallToolResult{Content: []Content{}}
+	for _, artifact := range task.Artifacts {
+		result.Content = append(result.Content, partsContent(artifact.Parts)...)
+	}
+	if task.Status.Message != nil {
+		result.Content = append(result.Content, partsContent(task.Status.Message.Parts)...)
+	}
+	switch task.Status.State {
+	case protocol.TaskStateFailed, protocol.TaskStateCanceled:
+		result.IsError = true
+	}
+	return result
+}
+
+// partsContent converts message parts to tool result content.
+func partsContent(parts []protocol.Part) []Content {
+	content := make([]Content, 0, len(parts))
+	for _, part := range parts {
+		switch p := part.(type) {
+		case protocol.TextPart:
+			content = append(content, TextContent(p.Text))
+		case *protocol.TextPart:
+			content = append(content, TextContent(p.Text))
+		case protocol.FilePart:
+			content = append(content, fileContent(p.File))
+		case *protocol.FilePart:
+			content = append(content, fileContent(p.File))
+		case protocol.DataPart:
+			content = append(content, dataContent(p.Data))
+		case *protocol.DataPart:
+			content = append(content, dataContent(p.Data))
+		}
+	}
+	return content
+}
+
+// fileContent converts a file to tool result content: images and audio inline,
+// other files as embedded resources.
+func fileContent(file protocol.FileContent) Content {
+	mimeType := deref(file.MimeType)
+	if file.Bytes != nil {
+		switch {
+		case strings.HasPrefix(mimeType, "image/"):
+			return Content{Type: ContentTypeImage, Data: *file.Bytes, MimeType: mimeType}
+		case strings.HasPrefix(mimeType, "audio/"):
+			return Content{Type: ContentTypeAudio, Data: *file.Bytes, MimeType: mimeType}
+		}
+	}
+	uri := deref(file.URI)
+	if file.Bytes == nil {
+		// Resources must be embedded, so linked files are passed as their URI.
+		return TextContent(uri)
+	}
+	if uri == "" {
+		uri = "file:///" + deref(file.Name)
+	}
+	return Content{Type: ContentTypeResource, Resource: &Resource{
+		URI:      uri,
+		MimeType: mimeType,
+		Blob:     *file.Bytes,
+	}}
+}
+
+// dataContent converts structured data to text content holding its JSON.
+func dataContent(data interface{}) Content {
+	text, err := json.Marshal(data)
+	if err != nil {
+		return TextContent(fmt.Sprint(data))
+	}
+	return TextContent(string(text))
+}
+
+// newID returns a random hex identifier.
+func newID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate ID: %w", err)
+	}
+	return hex.EncodeToString(b[:]), nil
+}
+
+// deref returns *s, or "" if s is nil.
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
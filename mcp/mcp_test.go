@@ -0,0 +1,173 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/client"
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/server"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// echoProcessor echoes the message text as an artifact, failing on "fail".
+type echoProcessor struct{}
+
+func (echoProcessor) Process(
+	ctx context.Context,
+	taskID string,
+	message protocol.Message,
+	handle taskmanager.TaskHandle,
+) error {
+	text := message.Parts[0].(protocol.TextPart).Text
+	if text == "fail" {
+		reply := protocol.NewMessage(protocol.MessageRoleAgent, []protocol.Part{protocol.NewTextPart("failed")})
+		return handle.UpdateStatus(protocol.TaskStateFailed, &reply)
+	}
+	if err := handle.AddArtifact(protocol.Artifact{
+		Parts: []protocol.Part{protocol.NewTextPart("echo: " + text)},
+	}); err != nil {
+		return err
+	}
+	return handle.UpdateStatus(protocol.TaskStateCompleted, nil)
+}
+
+// startAgent serves processor as an A2A agent with card and returns its client.
+func startAgent(t *testing.T, card server.AgentCard, processor taskmanager.TaskProcessor) *client.A2AClient {
+	t.Helper()
+	tm, err := taskmanager.NewMemoryTaskManager(processor)
+	require.NoError(t, err)
+	a2aServer, err := server.NewA2AServer(card, tm)
+	require.NoError(t, err)
+	ts := httptest.NewServer(a2aServer.Handler())
+	t.Cleanup(ts.Close)
+	c, err := client.NewA2AClient(ts.URL)
+	require.NoError(t, err)
+	return c
+}
+
+func TestBridge(t *testing.T) {
+	description := "Echoes the message."
+	card := server.AgentCard{
+		Name:    "echo-agent",
+		Version: "1.0.0",
+		Skills:  []server.AgentSkill{{ID: "echo", Name: "Echo", Description: &description}},
+	}
+	agent := startAgent(t, card, echoProcessor{})
+	mcpServer := httptest.NewServer(NewHandler(agent, card))
+	defer mcpServer.Close()
+	ctx := context.Background()
+
+	// The A2A agent as an MCP server.
+	c, err := NewClient(mcpServer.URL)
+	require.NoError(t, err)
+	info, err := c.Initialize(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "echo-agent", info.ServerInfo.Name)
+	tools, err := c.ListTools(ctx)
+	require.NoError(t, err)
+	require.Len(t, tools, 1)
+	assert.Equal(t, "echo", tools[0].Name)
+	assert.Equal(t, description, tools[0].Description)
+
+	result, err := c.CallTool(ctx, "echo", map[string]interface{}{"message": "hi"})
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Equal(t, []Content{TextContent("echo: hi")}, result.Content)
+
+	result, err = c.CallTool(ctx, "echo", map[string]interface{}{"message": "fail"})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+
+	_, err = c.CallTool(ctx, "sing", map[string]interface{}{"message": "hi"})
+	var rpcErr *jsonrpc.Error
+	require.True(t, errors.As(err, &rpcErr))
+	assert.Equal(t, jsonrpc.CodeInvalidParams, rpcErr.Code)
+
+	// The MCP server back as an A2A agent.
+	processor, err := NewToolProcessor(ctx, c)
+	require.NoError(t, err)
+	skills := processor.Skills()
+	require.Len(t, skills, 1)
+	assert.Equal(t, "echo", skills[0].ID)
+	bridged := startAgent(t, server.AgentCard{Name: "bridged", Version: "1.0.0", Skills: skills}, processor)
+	task, err := bridged.SendTasks(ctx, protocol.SendTaskParams{
+		ID:      "task-1",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hello")}),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, protocol.TaskStateCompleted, task.Status.State)
+	require.Len(t, task.Artifacts, 1)
+	assert.Equal(t, "echo: hello", task.Artifacts[0].Parts[0].(protocol.TextPart).Text)
+}
+
+func TestClient_EventStreamResponse(t *testing.T) {
+	mcpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request jsonrpc.Request
+		if !assert.NoError(t, json.NewDecoder(r.Body).Decode(&request)) {
+			return
+		}
+		if request.ID == nil {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		var result interface{} = InitializeResult{ProtocolVersion: ProtocolVersion}
+		if request.Method == MethodToolsList {
+			result = ListToolsResult{Tools: []Tool{{Name: "add", InputSchema: json.RawMessage(`{}`)}}}
+		}
+		data, err := json.Marshal(jsonrpc.NewResponse(request.ID, result))
+		if !assert.NoError(t, err) {
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"jsonrpc\":\"2.0\",\"method\":\"notifications/progress\"}\n\n")
+		fmt.Fprintf(w, "data: %s\n\n", data)
+	}))
+	defer mcpServer.Close()
+
+	c, err := NewClient(mcpServer.URL)
+	require.NoError(t, err)
+	tools, err := c.ListTools(context.Background())
+	require.NoError(t, err)
+	require.Len(t, tools, 1)
+	assert.Equal(t, "add", tools[0].Name)
+}
+
+func TestToolArguments(t *testing.T) {
+	text := protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart(`{"a":1}`)})
+	single := Tool{Name: "search", InputSchema: json.RawMessage(`{"type":"object","properties":{"query":{"type":"string"}}}`)}
+	multi := Tool{Name: "add", InputSchema: json.RawMessage(`{"type":"object","properties":{"a":{},"b":{}}}`)}
+
+	arguments, err := toolArguments(single, text)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"query": `{"a":1}`}, arguments)
+
+	arguments, err = toolArguments(multi, text)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a": float64(1)}, arguments)
+
+	data := protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{
+		protocol.DataPart{Type: protocol.PartTypeData, Data: map[string]interface{}{"b": 2}},
+	})
+	arguments, err = toolArguments(multi, data)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"b": 2}, arguments)
+
+	_, err = toolArguments(multi, protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("x")}))
+	assert.Error(t, err)
+}
@@ -0,0 +1,40 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package mcp
+
+import "net/http"
+
+// ClientOption is a function that configures the MCP Client.
+type ClientOption func(*Client)
+
+// WithHTTPClient sets the HTTP client used to reach the MCP server.
+// Defaults to http.DefaultClient.
+func WithHTTPClient(client *http.Client) ClientOption {
+	return func(c *Client) {
+		if client != nil {
+			c.httpClient = client
+		}
+	}
+}
+
+// WithClientInfo sets the client name and version sent on initialization.
+func WithClientInfo(name, version string) ClientOption {
+	return func(c *Client) {
+		c.info = Implementation{Name: name, Version: version}
+	}
+}
+
+// HandlerOption is a function that configures the handler created by NewHandler.
+type HandlerOption func(*handler)
+
+// WithServerInfo sets the server name and version reported on initialization.
+// Defaults to the name and version of the agent card.
+func WithServerInfo(name, version string) HandlerOption {
+	return func(h *handler) {
+		h.info = Implementation{Name: name, Version: version}
+	}
+}
@@ -0,0 +1,235 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"trpc.group/trpc-go/trpc-a2a-go/orchestrator"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/server"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// ToolProcessor is a taskmanager.TaskProcessor running each task as a call of
+// a tool of an MCP server, so that the server can be served as an A2A agent
+// whose skills, see Skills, are its tools.
+//
+// The tool is the one named under orchestrator.MetadataKeySkill in the message
+// metadata, or the only tool of the server. Its arguments are taken from the
+// first data part of the message holding an object, or else from the text of
+// the message: passed as the only string argument of the tool if it has one,
+// or parsed as a JSON object otherwise.
+type ToolProcessor struct {
+	client *Client
+
+	mu    sync.RWMutex
+	tools map[string]Tool
+	order []string
+}
+
+var _ taskmanager.TaskProcessor = (*ToolProcessor)(nil)
+
+// NewToolProcessor creates a processor calling the tools of the server of c,
+// which it lists right away.
+func NewToolProcessor(ctx context.Context, c *Client) (*ToolProcessor, error) {
+	p := &ToolProcessor{client: c}
+	if err := p.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Refresh lists the tools of the server again, e.g. after it reported a change.
+func (p *ToolProcessor) Refresh(ctx context.Context) error {
+	tools, err := p.client.ListTools(ctx)
+	if err != nil {
+		return err
+	}
+	byName := make(map[string]Tool, len(tools))
+	order := make([]string, 0, len(tools))
+	for _, tool := range tools {
+		if _, ok := byName[tool.Name]; !ok {
+			order = append(order, tool.Name)
+		}
+		byName[tool.Name] = tool
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.tools, p.order = byName, order
+	return nil
+}
+
+// Skills returns one agent skill per tool, for the card of the agent.
+func (p *ToolProcessor) Skills() []server.AgentSkill {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	skills := make([]server.AgentSkill, 0, len(p.order))
+	for _, name := range p.order {
+		tool := p.tools[name]
+		skill := server.AgentSkill{
+			ID:          tool.Name,
+			Name:        tool.Name,
+			InputModes:  []string{string(protocol.PartTypeData), string(protocol.PartTypeText)},
+			OutputModes: []string{string(protocol.PartTypeText), string(protocol.PartTypeFile)},
+		}
+		if tool.Description != "" {
+			description := tool.Description
+			skill.Description = &description
+		}
+		skills = append(skills, skill)
+	}
+	return skills
+}
+
+// Process implements taskmanager.TaskProcessor. The tool result becomes an
+// artifact of the task, which completes, or fails if the tool reported an error.
+func (p *ToolProcessor) Process(
+	ctx context.Context,
+	taskID string,
+	message protocol.Message,
+	handle taskmanager.TaskHandle,
+) error {
+	tool, err := p.tool(message)
+	if err != nil {
+		return failTask(handle, err.Error())
+	}
+	arguments, err := toolArguments(tool, message)
+	if err != nil {
+		return failTask(handle, err.Error())
+	}
+	result, err := p.client.CallTool(ctx, tool.Name, arguments)
+	if err != nil {
+		return err
+	}
+	parts := contentParts(result.Content)
+	if result.IsError {
+		reply := protocol.NewMessage(protocol.MessageRoleAgent, parts)
+		return handle.UpdateStatus(protocol.TaskStateFailed, &reply)
+	}
+	if len(parts) > 0 {
+		name := tool.Name
+		if err := handle.AddArtifact(protocol.Artifact{Name: &name, Parts: parts}); err != nil {
+			return err
+		}
+	}
+	return handle.UpdateStatus(protocol.TaskStateCompleted, nil)
+}
+
+// tool returns the tool message asks for.
+func (p *ToolProcessor) tool(message protocol.Message) (Tool, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if name, ok := message.Metadata[orchestrator.MetadataKeySkill].(string); ok && name != "" {
+		tool, ok := p.tools[name]
+		if !ok {
+			return Tool{}, fmt.Errorf("unknown tool %q", name)
+		}
+		return tool, nil
+	}
+	if len(p.order) != 1 {
+		return Tool{}, fmt.Errorf("message metadata must name one of the %d tools under %q",
+			len(p.order), orchestrator.MetadataKeySkill)
+	}
+	return p.tools[p.order[0]], nil
+}
+
+// toolArguments returns the arguments of the call of tool for message.
+func toolArguments(tool Tool, message protocol.Message) (map[string]interface{}, error) {
+	var texts []string
+	for _, part := range message.Parts {
+		switch p := part.(type) {
+		case protocol.DataPart:
+			if arguments, ok := p.Data.(map[string]interface{}); ok {
+				return arguments, nil
+			}
+		case *protocol.DataPart:
+			if arguments, ok := p.Data.(map[string]interface{}); ok {
+				return arguments, nil
+			}
+		case protocol.TextPart:
+			texts = append(texts, p.Text)
+		case *protocol.TextPart:
+			texts = append(texts, p.Text)
+		}
+	}
+	text := strings.Join(texts, "\n")
+	if name, ok := stringArgument(tool.InputSchema); ok {
+		return map[string]interface{}{name: text}, nil
+	}
+	var arguments map[string]interface{}
+	if err := json.Unmarshal([]byte(text), &arguments); err != nil {
+		return nil, fmt.Errorf("tool %s takes a JSON object of arguments: %v", tool.Name, err)
+	}
+	return arguments, nil
+}
+
+// stringArgument returns the name of the only property of the input schema if
+// it is a string.
+func stringArgument(schema json.RawMessage) (string, bool) {
+	var s struct {
+		Properties map[string]struct {
+			Type string `json:"type"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(schema, &s); err != nil || len(s.Properties) != 1 {
+		return "", false
+	}
+	for name, property := range s.Properties {
+		return name, property.Type == "string"
+	}
+	return "", false
+}
+
+// contentParts converts the content of a tool result to message parts.
+func contentParts(content []Content) []protocol.Part {
+	parts := make([]protocol.Part, 0, len(content))
+	for _, item := range content {
+		switch item.Type {
+		case ContentTypeText:
+			parts = append(parts, protocol.NewTextPart(item.Text))
+		case ContentTypeImage, ContentTypeAudio:
+			data, mimeType := item.Data, item.MimeType
+			parts = append(parts, protocol.FilePart{
+				Type: protocol.PartTypeFile,
+				File: protocol.FileContent{Bytes: &data, MimeType: nonEmpty(mimeType)},
+			})
+		case ContentTypeResource:
+			if item.Resource == nil {
+				continue
+			}
+			res := *item.Resource
+			if res.Blob == "" {
+				parts = append(parts, protocol.NewTextPart(res.Text))
+				continue
+			}
+			parts = append(parts, protocol.FilePart{
+				Type: protocol.PartTypeFile,
+				File: protocol.FileContent{Bytes: &res.Blob, URI: nonEmpty(res.URI), MimeType: nonEmpty(res.MimeType)},
+			})
+		}
+	}
+	return parts
+}
+
+// failTask fails the task being processed with handle, explaining why in text.
+func failTask(handle taskmanager.TaskHandle, text string) error {
+	reply := protocol.NewMessage(protocol.MessageRoleAgent, []protocol.Part{protocol.NewTextPart(text)})
+	return handle.UpdateStatus(protocol.TaskStateFailed, &reply)
+}
+
+// nonEmpty returns a pointer to s, or nil if s is empty.
+func nonEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
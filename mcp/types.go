@@ -0,0 +1,119 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+// Package mcp bridges A2A and the Model Context Protocol (MCP). A ToolProcessor
+// serves the tools of an MCP server as the skills of an A2A agent, and
+// NewHandler serves the skills of an A2A agent as the tools of an MCP server.
+// Both speak the MCP streamable HTTP transport.
+package mcp
+
+import "encoding/json"
+
+// ProtocolVersion is the MCP protocol version spoken by this package.
+const ProtocolVersion = "2025-03-26"
+
+// MCP methods used by the bridge.
+const (
+	MethodInitialize  = "initialize"
+	MethodInitialized = "notifications/initialized"
+	MethodPing        = "ping"
+	MethodToolsList   = "tools/list"
+	MethodToolsCall   = "tools/call"
+)
+
+// SessionIDHeader is the HTTP header carrying the MCP session ID.
+const SessionIDHeader = "Mcp-Session-Id"
+
+// Content types of tool results.
+const (
+	ContentTypeText     = "text"
+	ContentTypeImage    = "image"
+	ContentTypeAudio    = "audio"
+	ContentTypeResource = "resource"
+)
+
+// Implementation names an MCP client or server.
+type Implementation struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// InitializeParams are the params of the initialize request.
+type InitializeParams struct {
+	ProtocolVersion string                 `json:"protocolVersion"`
+	Capabilities    map[string]interface{} `json:"capabilities"`
+	ClientInfo      Implementation         `json:"clientInfo"`
+}
+
+// InitializeResult is the result of the initialize request.
+type InitializeResult struct {
+	ProtocolVersion string                 `json:"protocolVersion"`
+	Capabilities    map[string]interface{} `json:"capabilities"`
+	ServerInfo      Implementation         `json:"serverInfo"`
+	Instructions    string                 `json:"instructions,omitempty"`
+}
+
+// Tool describes a tool of an MCP server.
+type Tool struct {
+	// Name identifies the tool.
+	Name string `json:"name"`
+	// Description tells models what the tool does.
+	Description string `json:"description,omitempty"`
+	// InputSchema is the JSON schema of the tool arguments.
+	InputSchema json.RawMessage `json:"inputSchema"`
+}
+
+// ListToolsParams are the params of the tools/list request.
+type ListToolsParams struct {
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// ListToolsResult is the result of the tools/list request.
+type ListToolsResult struct {
+	Tools      []Tool `json:"tools"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// CallToolParams are the params of the tools/call request.
+type CallToolParams struct {
+	Name      string                 `json:"name"`
+	Arguments map[string]interface{} `json:"arguments,omitempty"`
+}
+
+// Resource is a resource embedded in a tool result, with either text or a
+// base64 encoded blob.
+type Resource struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType,omitempty"`
+	Text     string `json:"text,omitempty"`
+	Blob     string `json:"blob,omitempty"`
+}
+
+// Content is an item of a tool result.
+type Content struct {
+	// Type is one of the ContentType constants.
+	Type string `json:"type"`
+	// Text is the content of a text item.
+	Text string `json:"text,omitempty"`
+	// Data is the base64 encoded content of an image or audio item.
+	Data string `json:"data,omitempty"`
+	// MimeType is the MIME type of an image or audio item.
+	MimeType string `json:"mimeType,omitempty"`
+	// Resource is the content of a resource item.
+	Resource *Resource `json:"resource,omitempty"`
+}
+
+// TextContent returns a text content item.
+func TextContent(text string) Content {
+	return Content{Type: ContentTypeText, Text: text}
+}
+
+// CallToolResult is the result of the tools/call request. Tool failures are
+// reported with IsError rather than as JSON-RPC errors, so that models can see them.
+type CallToolResult struct {
+	Content []Content `json:"content"`
+	IsError bool      `json:"isError,omitempty"`
+}
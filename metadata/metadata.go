@@ -0,0 +1,171 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+// Package metadata carries key/value metadata alongside A2A requests, with the
+// semantics of gRPC metadata: clients attach metadata to the context of a call
+// with NewOutgoingContext or AppendToOutgoingContext, it is transported as HTTP
+// headers prefixed with HeaderPrefix, and servers expose it to handlers and
+// task managers through FromIncomingContext.
+//
+// Keys are case-insensitive and stored in lower case. Values are sent as is, so
+// they must be valid HTTP header values.
+package metadata
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// HeaderPrefix is the prefix of the HTTP headers transporting metadata.
+const HeaderPrefix = "A2a-Metadata-"
+
+// MD maps lower-case keys to their values.
+type MD map[string][]string
+
+// New creates an MD from a map of keys to single values.
+func New(m map[string]string) MD {
+	md := make(MD, len(m))
+	for k, v := range m {
+		md.Append(k, v)
+	}
+	return md
+}
+
+// Pairs creates an MD from alternating keys and values. It panics if the
+// number of arguments is odd.
+func Pairs(kv ...string) MD {
+	if len(kv)%2 == 1 {
+		panic("metadata: Pairs got an odd number of arguments")
+	}
+	md := make(MD, len(kv)/2)
+	for i := 0; i < len(kv); i += 2 {
+		md.Append(kv[i], kv[i+1])
+	}
+	return md
+}
+
+// Join merges the given MDs, appending the values of duplicate keys.
+func Join(mds ...MD) MD {
+	out := MD{}
+	for _, md := range mds {
+		for k, v := range md {
+			out[k] = append(out[k], v...)
+		}
+	}
+	return out
+}
+
+// Len returns the number of keys.
+func (md MD) Len() int {
+	return len(md)
+}
+
+// Copy returns a copy of md.
+func (md MD) Copy() MD {
+	return Join(md)
+}
+
+// Get returns the values of key.
+func (md MD) Get(key string) []string {
+	return md[strings.ToLower(key)]
+}
+
+// Set replaces the values of key.
+func (md MD) Set(key string, values ...string) {
+	if len(values) == 0 {
+		return
+	}
+	md[strings.ToLower(key)] = values
+}
+
+// Append adds values to key.
+func (md MD) Append(key string, values ...string) {
+	if len(values) == 0 {
+		return
+	}
+	k := strings.ToLower(key)
+	md[k] = append(md[k], values...)
+}
+
+// Delete removes key.
+func (md MD) Delete(key string) {
+	delete(md, strings.ToLower(key))
+}
+
+type outgoingKey struct{}
+
+type incomingKey struct{}
+
+// NewOutgoingContext returns a context carrying md as the metadata sent by
+// client calls made with it, replacing any previous outgoing metadata.
+func NewOutgoingContext(ctx context.Context, md MD) context.Context {
+	return context.WithValue(ctx, outgoingKey{}, md)
+}
+
+// AppendToOutgoingContext returns a context carrying the outgoing metadata of
+// ctx with the alternating keys and values added. It panics if the number of
+// arguments is odd.
+func AppendToOutgoingContext(ctx context.Context, kv ...string) context.Context {
+	md, _ := FromOutgoingContext(ctx)
+	return NewOutgoingContext(ctx, Join(md, Pairs(kv...)))
+}
+
+// FromOutgoingContext returns a copy of the outgoing metadata of ctx.
+func FromOutgoingContext(ctx context.Context) (MD, bool) {
+	md, ok := ctx.Value(outgoingKey{}).(MD)
+	if !ok {
+		return nil, false
+	}
+	return md.Copy(), true
+}
+
+// NewIncomingContext returns a context carrying md as the metadata received
+// with the request being handled. Servers call it; tests may too.
+func NewIncomingContext(ctx context.Context, md MD) context.Context {
+	return context.WithValue(ctx, incomingKey{}, md)
+}
+
+// FromIncomingContext returns a copy of the metadata received with the request
+// being handled.
+func FromIncomingContext(ctx context.Context) (MD, bool) {
+	md, ok := ctx.Value(incomingKey{}).(MD)
+	if !ok {
+		return nil, false
+	}
+	return md.Copy(), true
+}
+
+// ValueFromIncomingContext returns the values of key in the metadata received
+// with the request being handled.
+func ValueFromIncomingContext(ctx context.Context, key string) []string {
+	md, _ := ctx.Value(incomingKey{}).(MD)
+	values := md.Get(key)
+	if values == nil {
+		return nil
+	}
+	return append([]string(nil), values...)
+}
+
+// ToHeader adds md to h as HeaderPrefix headers.
+func ToHeader(md MD, h http.Header) {
+	for k, values := range md {
+		for _, v := range values {
+			h.Add(HeaderPrefix+k, v)
+		}
+	}
+}
+
+// FromHeader returns the metadata transported by the HeaderPrefix headers of h.
+func FromHeader(h http.Header) MD {
+	md := MD{}
+	for name, values := range h {
+		if len(name) > len(HeaderPrefix) && strings.EqualFold(name[:len(HeaderPrefix)], HeaderPrefix) {
+			md.Append(name[len(HeaderPrefix):], values...)
+		}
+	}
+	return md
+}
@@ -0,0 +1,60 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package metadata
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMD(t *testing.T) {
+	md := Pairs("Tenant", "acme", "trace", "a", "TRACE", "b")
+	assert.Equal(t, []string{"acme"}, md.Get("tenant"))
+	assert.Equal(t, []string{"a", "b"}, md.Get("Trace"))
+
+	md.Set("trace", "c")
+	assert.Equal(t, []string{"c"}, md.Get("trace"))
+	md.Delete("Trace")
+	assert.Nil(t, md.Get("trace"))
+	assert.Equal(t, 1, md.Len())
+
+	joined := Join(New(map[string]string{"k": "1"}), Pairs("k", "2"))
+	assert.Equal(t, []string{"1", "2"}, joined.Get("k"))
+	assert.Panics(t, func() { Pairs("odd") })
+}
+
+func TestContext(t *testing.T) {
+	ctx := NewOutgoingContext(context.Background(), Pairs("a", "1"))
+	ctx = AppendToOutgoingContext(ctx, "b", "2")
+	md, ok := FromOutgoingContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, MD{"a": {"1"}, "b": {"2"}}, md)
+
+	// The returned metadata is a copy.
+	md.Set("a", "changed")
+	md, _ = FromOutgoingContext(ctx)
+	assert.Equal(t, []string{"1"}, md.Get("a"))
+
+	_, ok = FromIncomingContext(ctx)
+	assert.False(t, ok, "outgoing metadata is not incoming metadata")
+	ctx = NewIncomingContext(context.Background(), Pairs("a", "1"))
+	assert.Equal(t, []string{"1"}, ValueFromIncomingContext(ctx, "A"))
+	assert.Nil(t, ValueFromIncomingContext(context.Background(), "a"))
+}
+
+func TestHeader(t *testing.T) {
+	h := http.Header{}
+	h.Set("Content-Type", "application/json")
+	ToHeader(Pairs("tenant", "acme", "trace", "a", "trace", "b"), h)
+	assert.Equal(t, "acme", h.Get("A2A-Metadata-Tenant"))
+
+	md := FromHeader(h)
+	assert.Equal(t, MD{"tenant": {"acme"}, "trace": {"a", "b"}}, md)
+}
@@ -0,0 +1,21 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package orchestrator
+
+// Option is a function that configures the TaskManager.
+type Option func(*TaskManager)
+
+// WithMaxBindings bounds how many tasks are remembered with the agent they
+// were routed to. Requests about forgotten tasks fail with task not found.
+// Default 10000.
+func WithMaxBindings(n int) Option {
+	return func(m *TaskManager) {
+		if n > 0 {
+			m.maxBindings = n
+		}
+	}
+}
@@ -0,0 +1,324 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+// Package orchestrator provides a TaskManager that routes tasks to downstream
+// A2A agents and proxies their responses and event streams back to the caller,
+// so that a single A2A server can front a team of agents.
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"trpc.group/trpc-go/trpc-a2a-go/client"
+	"trpc.group/trpc-go/trpc-a2a-go/discovery"
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/server"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+const (
+	// defaultMaxBindings bounds the number of task-to-agent bindings kept by default.
+	defaultMaxBindings = 10000
+	// relayBuffer is the buffer size of relayed event streams.
+	relayBuffer = 10
+)
+
+// Agent is a downstream agent tasks can be routed to.
+type Agent struct {
+	// Card describes the agent; routers match tasks against it.
+	Card server.AgentCard
+	// Client sends tasks to the agent.
+	Client *client.A2AClient
+}
+
+// NewAgent fetches the agent card of the agent at agentURL and creates a client
+// for it with opts.
+func NewAgent(ctx context.Context, agentURL string, opts ...client.Option) (*Agent, error) {
+	card, err := (&discovery.HTTPFetcher{}).FetchAgentCard(ctx, agentURL)
+	if err != nil {
+		return nil, err
+	}
+	c, err := client.NewA2AClient(agentURL, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Agent{Card: *card, Client: c}, nil
+}
+
+// TaskManager is a TaskManager forwarding each new task to the downstream agent
+// chosen by its router. Later requests about the task, such as tasks/get,
+// tasks/cancel and tasks/resubscribe, go to the same agent. Errors reported by
+// the agents are passed through to the caller.
+type TaskManager struct {
+	router      Router
+	maxBindings int
+
+	mu       sync.RWMutex
+	agents   []*Agent
+	bindings map[string]*Agent
+	order    []string // Bound task IDs, oldest first from position oldest.
+	oldest   int
+}
+
+var (
+	_ taskmanager.TaskManager = (*TaskManager)(nil)
+	_ taskmanager.TaskPlanner = (*TaskManager)(nil)
+)
+
+// NewTaskManager creates an orchestrating task manager routing tasks to agents
+// with router.
+func NewTaskManager(router Router, agents []*Agent, opts ...Option) (*TaskManager, error) {
+	if router == nil {
+		return nil, errors.New("orchestrator: router cannot be nil")
+	}
+	m := &TaskManager{
+		router:      router,
+		maxBindings: defaultMaxBindings,
+		bindings:    make(map[string]*Agent),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	m.SetAgents(agents)
+	return m, nil
+}
+
+// SetAgents replaces the downstream agents new tasks are routed to. Tasks
+// already bound to an agent keep using it.
+func (m *TaskManager) SetAgents(agents []*Agent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.agents = append([]*Agent(nil), agents...)
+}
+
+// OnSendTask implements taskmanager.TaskManager.
+func (m *TaskManager) OnSendTask(ctx context.Context, params protocol.SendTaskParams) (*protocol.Task, error) {
+	agent, err := m.route(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	task, err := agent.Client.SendTasks(ctx, params)
+	if err != nil {
+		return nil, downstreamError(agent, err)
+	}
+	return task, nil
+}
+
+// PlanTask implements taskmanager.TaskPlanner. It routes the task without
+// binding it and forwards the dry run to the chosen agent, whose plan is
+// returned with the agent name and, if the agent did not report one, the
+// skill of its card that fits the task best. The downstream agents must
+// support dry runs, since agents ignoring the flag would run the task.
+func (m *TaskManager) PlanTask(ctx context.Context, params protocol.SendTaskParams) (*protocol.TaskPlan, error) {
+	agent, _, err := m.choose(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := agent.Client.PlanTask(ctx, params)
+	if err != nil {
+		return nil, downstreamError(agent, err)
+	}
+	plan.Agent = agent.Card.Name
+	if plan.Skill == "" {
+		plan.Skill = selectSkill(&agent.Card, params)
+	}
+	return plan, nil
+}
+
+// OnSendTaskSubscribe implements taskmanager.TaskManager. The events of the
+// downstream stream are relayed as they arrive, until the final status update.
+func (m *TaskManager) OnSendTaskSubscribe(
+	ctx context.Context,
+	params protocol.SendTaskParams,
+) (<-chan protocol.TaskEvent, error) {
+	agent, err := m.route(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	events, err := agent.Client.StreamTask(ctx, params)
+	if err != nil {
+		cancel()
+		return nil, downstreamError(agent, err)
+	}
+	return relay(ctx, cancel, events), nil
+}
+
+// OnGetTask implements taskmanager.TaskManager.
+func (m *TaskManager) OnGetTask(ctx context.Context, params protocol.TaskQueryParams) (*protocol.Task, error) {
+	agent, err := m.boundAgent(params.ID)
+	if err != nil {
+		return nil, err
+	}
+	task, err := agent.Client.GetTasks(ctx, params)
+	if err != nil {
+		return nil, downstreamError(agent, err)
+	}
+	return task, nil
+}
+
+// OnCancelTask implements taskmanager.TaskManager.
+func (m *TaskManager) OnCancelTask(ctx context.Context, params protocol.TaskIDParams) (*protocol.Task, error) {
+	agent, err := m.boundAgent(params.ID)
+	if err != nil {
+		return nil, err
+	}
+	task, err := agent.Client.CancelTasks(ctx, params)
+	if err != nil {
+		return nil, downstreamError(agent, err)
+	}
+	return task, nil
+}
+
+// OnPushNotificationSet implements taskmanager.TaskManager.
+func (m *TaskManager) OnPushNotificationSet(
+	ctx context.Context,
+	params protocol.TaskPushNotificationConfig,
+) (*protocol.TaskPushNotificationConfig, error) {
+	agent, err := m.boundAgent(params.ID)
+	if err != nil {
+		return nil, err
+	}
+	config, err := agent.Client.SetPushNotification(ctx, params)
+	if err != nil {
+		return nil, downstreamError(agent, err)
+	}
+	return config, nil
+}
+
+// OnPushNotificationGet implements taskmanager.TaskManager.
+func (m *TaskManager) OnPushNotificationGet(
+	ctx context.Context,
+	params protocol.TaskIDParams,
+) (*protocol.TaskPushNotificationConfig, error) {
+	agent, err := m.boundAgent(params.ID)
+	if err != nil {
+		return nil, err
+	}
+	config, err := agent.Client.GetPushNotification(ctx, params)
+	if err != nil {
+		return nil, downstreamError(agent, err)
+	}
+	return config, nil
+}
+
+// OnResubscribe implements taskmanager.TaskManager.
+func (m *TaskManager) OnResubscribe(ctx context.Context, params protocol.TaskIDParams) (<-chan protocol.TaskEvent, error) {
+	agent, err := m.boundAgent(params.ID)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	events, err := agent.Client.ResubscribeTask(ctx, params)
+	if err != nil {
+		cancel()
+		return nil, downstreamError(agent, err)
+	}
+	return relay(ctx, cancel, events), nil
+}
+
+// relay forwards the events of a downstream stream until its final status
+// update, then closes the returned channel and, with cancel, the downstream stream.
+func relay(ctx context.Context, cancel context.CancelFunc, events <-chan protocol.TaskEvent) <-chan protocol.TaskEvent {
+	out := make(chan protocol.TaskEvent, relayBuffer)
+	go func() {
+		defer close(out)
+		defer cancel()
+		for event := range events {
+			select {
+			case out <- event:
+			case <-ctx.Done():
+				return
+			}
+			if status, ok := event.(protocol.TaskStatusUpdateEvent); ok && status.Final {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// route returns the agent of the task in params: the agent it is bound to, or
+// else the agent chosen by the router, to which it is then bound.
+func (m *TaskManager) route(ctx context.Context, params protocol.SendTaskParams) (*Agent, error) {
+	agent, bound, err := m.choose(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	if !bound {
+		log.Debugf("Routing task %s to agent %s", params.ID, agent.Card.Name)
+		m.bind(params.ID, agent)
+	}
+	return agent, nil
+}
+
+// choose returns the agent the task in params is bound to, or else the agent
+// chosen by the router, and whether the task was bound.
+func (m *TaskManager) choose(ctx context.Context, params protocol.SendTaskParams) (*Agent, bool, error) {
+	m.mu.RLock()
+	agent, ok := m.bindings[params.ID]
+	agents := m.agents
+	m.mu.RUnlock()
+	if ok {
+		return agent, true, nil
+	}
+	if len(agents) == 0 {
+		return nil, false, jsonrpc.ErrInternalError(ErrNoRoute.Error())
+	}
+	agent, err := m.router.Route(ctx, params, agents)
+	if err != nil {
+		if errors.Is(err, ErrNoRoute) {
+			return nil, false, jsonrpc.ErrInvalidParams(err.Error())
+		}
+		return nil, false, jsonrpc.ErrInternalError(fmt.Sprintf("failed to route task: %v", err))
+	}
+	return agent, false, nil
+}
+
+// boundAgent returns the agent taskID was routed to.
+func (m *TaskManager) boundAgent(taskID string) (*Agent, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	agent, ok := m.bindings[taskID]
+	if !ok {
+		return nil, taskmanager.ErrTaskNotFound(taskID)
+	}
+	return agent, nil
+}
+
+// bind routes later requests about taskID to agent, forgetting the oldest
+// binding once maxBindings are kept.
+func (m *TaskManager) bind(taskID string, agent *Agent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.bindings[taskID]; ok {
+		return
+	}
+	if len(m.order) < m.maxBindings {
+		m.order = append(m.order, taskID)
+	} else {
+		delete(m.bindings, m.order[m.oldest])
+		m.order[m.oldest] = taskID
+		m.oldest = (m.oldest + 1) % len(m.order)
+	}
+	m.bindings[taskID] = agent
+}
+
+// downstreamError passes JSON-RPC errors of agent through unchanged, so that
+// e.g. task not found errors reach the caller as such, and reports any other
+// failure as an internal error.
+func downstreamError(agent *Agent, err error) error {
+	var rpcErr *jsonrpc.Error
+	if errors.As(err, &rpcErr) {
+		return rpcErr
+	}
+	return jsonrpc.ErrInternalError(fmt.Sprintf("agent %s failed: %v", agent.Card.Name, err))
+}
@@ -0,0 +1,166 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/client"
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/server"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// namedProcessor completes tasks with a reply naming the agent.
+type namedProcessor struct {
+	name string
+}
+
+func (p *namedProcessor) Process(
+	ctx context.Context,
+	taskID string,
+	message protocol.Message,
+	handle taskmanager.TaskHandle,
+) error {
+	if err := handle.AddArtifact(protocol.Artifact{
+		Parts: []protocol.Part{protocol.NewTextPart(p.name)},
+	}); err != nil {
+		return err
+	}
+	reply := protocol.NewMessage(protocol.MessageRoleAgent, []protocol.Part{protocol.NewTextPart(p.name)})
+	return handle.UpdateStatus(protocol.TaskStateCompleted, &reply)
+}
+
+// newTestAgent starts a downstream agent with one skill.
+func newTestAgent(t *testing.T, name string, skill server.AgentSkill) *Agent {
+	t.Helper()
+	tm, err := taskmanager.NewMemoryTaskManager(&namedProcessor{name: name})
+	require.NoError(t, err)
+	card := server.AgentCard{Name: name, Version: "1.0.0", Skills: []server.AgentSkill{skill}}
+	a2aServer, err := server.NewA2AServer(card, tm)
+	require.NoError(t, err)
+	ts := httptest.NewServer(a2aServer.Handler())
+	t.Cleanup(ts.Close)
+	card.URL = ts.URL
+	c, err := client.NewA2AClient(ts.URL)
+	require.NoError(t, err)
+	return &Agent{Card: card, Client: c}
+}
+
+func replyText(t *testing.T, task *protocol.Task) string {
+	t.Helper()
+	require.NotNil(t, task.Status.Message)
+	return task.Status.Message.Parts[0].(protocol.TextPart).Text
+}
+
+func sendParams(taskID, text string, metadata map[string]interface{}) protocol.SendTaskParams {
+	return protocol.SendTaskParams{
+		ID:       taskID,
+		Message:  protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart(text)}),
+		Metadata: metadata,
+	}
+}
+
+func TestTaskManager_SkillRouting(t *testing.T) {
+	fx := newTestAgent(t, "fx", server.AgentSkill{ID: "convert", Name: "Convert", Tags: []string{"currency"}})
+	img := newTestAgent(t, "img", server.AgentSkill{ID: "draw", Name: "Draw", Tags: []string{"picture"}})
+	m, err := NewTaskManager(NewSkillRouter(nil), []*Agent{fx, img})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	task, err := m.OnSendTask(ctx, sendParams("t1", "draw a picture of a cat", nil))
+	require.NoError(t, err)
+	assert.Equal(t, "img", replyText(t, task))
+
+	task, err = m.OnSendTask(ctx, sendParams("t2", "hello", map[string]interface{}{MetadataKeySkill: "convert"}))
+	require.NoError(t, err)
+	assert.Equal(t, "fx", replyText(t, task))
+
+	// Later requests go to the agent holding the task.
+	task, err = m.OnGetTask(ctx, protocol.TaskQueryParams{ID: "t1"})
+	require.NoError(t, err)
+	assert.Equal(t, "img", replyText(t, task))
+
+	_, err = m.OnSendTask(ctx, sendParams("t3", "hi", map[string]interface{}{MetadataKeySkill: "sing"}))
+	var rpcErr *jsonrpc.Error
+	require.True(t, errors.As(err, &rpcErr))
+	assert.Equal(t, jsonrpc.CodeInvalidParams, rpcErr.Code)
+
+	_, err = m.OnGetTask(ctx, protocol.TaskQueryParams{ID: "unknown"})
+	require.True(t, errors.As(err, &rpcErr))
+	assert.Equal(t, taskmanager.ErrCodeTaskNotFound, rpcErr.Code)
+
+	// Downstream errors pass through: the task is final on the agent.
+	_, err = m.OnCancelTask(ctx, protocol.TaskIDParams{ID: "t1"})
+	require.Error(t, err)
+	_, ok := err.(*jsonrpc.Error)
+	assert.True(t, ok, "got %T", err)
+}
+
+func TestTaskManager_StreamingAndRoundRobin(t *testing.T) {
+	a := newTestAgent(t, "a", server.AgentSkill{ID: "x", Name: "X"})
+	b := newTestAgent(t, "b", server.AgentSkill{ID: "x", Name: "X"})
+	m, err := NewTaskManager(NewRoundRobinRouter(), []*Agent{a, b})
+	require.NoError(t, err)
+
+	var names []string
+	for _, taskID := range []string{"s1", "s2"} {
+		events, err := m.OnSendTaskSubscribe(context.Background(), sendParams(taskID, "go", nil))
+		require.NoError(t, err)
+		var artifact string
+		var final bool
+		for event := range events {
+			switch e := event.(type) {
+			case protocol.TaskArtifactUpdateEvent:
+				artifact = e.Artifact.Parts[0].(protocol.TextPart).Text
+			case protocol.TaskStatusUpdateEvent:
+				final = e.Final
+			}
+		}
+		assert.True(t, final)
+		names = append(names, artifact)
+	}
+	assert.Equal(t, []string{"a", "b"}, names)
+
+	_, err = NewTaskManager(nil, nil)
+	assert.Error(t, err)
+	empty, err := NewTaskManager(NewRoundRobinRouter(), nil)
+	require.NoError(t, err)
+	_, err = empty.OnSendTask(context.Background(), sendParams("t", "go", nil))
+	assert.Error(t, err)
+}
+
+func TestTaskManager_PlanTask(t *testing.T) {
+	fx := newTestAgent(t, "fx", server.AgentSkill{ID: "convert", Name: "Convert", Tags: []string{"currency"}})
+	img := newTestAgent(t, "img", server.AgentSkill{ID: "draw", Name: "Draw", Tags: []string{"picture"}})
+	m, err := NewTaskManager(NewSkillRouter(nil), []*Agent{fx, img})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	plan, err := m.PlanTask(ctx, sendParams("t1", "draw a picture of a cat", nil))
+	require.NoError(t, err)
+	assert.Equal(t, "img", plan.Agent)
+	assert.Equal(t, "draw", plan.Skill)
+
+	// Neither the orchestrator nor the agent keeps the planned task.
+	_, err = m.OnGetTask(ctx, protocol.TaskQueryParams{ID: "t1"})
+	assert.Error(t, err)
+	_, err = img.Client.GetTasks(ctx, protocol.TaskQueryParams{ID: "t1"})
+	assert.Error(t, err)
+
+	_, err = m.PlanTask(ctx, sendParams("t2", "hi", map[string]interface{}{MetadataKeySkill: "sing"}))
+	var rpcErr *jsonrpc.Error
+	require.True(t, errors.As(err, &rpcErr))
+	assert.Equal(t, jsonrpc.CodeInvalidParams, rpcErr.Code)
+}
@@ -0,0 +1,173 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/server"
+)
+
+// MetadataKeySkill is the params metadata key naming the skill a task asks for.
+// The skill router sends such tasks only to agents declaring the skill.
+const MetadataKeySkill = server.MetadataKeySkill
+
+// ErrNoRoute is returned when no downstream agent can take a task.
+var ErrNoRoute = errors.New("no agent can handle the task")
+
+// Router chooses the downstream agent of a new task.
+type Router interface {
+	// Route returns one of agents, which is never empty, to forward params to.
+	Route(ctx context.Context, params protocol.SendTaskParams, agents []*Agent) (*Agent, error)
+}
+
+// RouterFunc adapts a function to the Router interface.
+type RouterFunc func(ctx context.Context, params protocol.SendTaskParams, agents []*Agent) (*Agent, error)
+
+// Route implements Router.
+func (f RouterFunc) Route(ctx context.Context, params protocol.SendTaskParams, agents []*Agent) (*Agent, error) {
+	return f(ctx, params, agents)
+}
+
+// roundRobinRouter cycles through the agents.
+type roundRobinRouter struct {
+	next atomic.Uint64
+}
+
+// NewRoundRobinRouter returns a router spreading tasks evenly over the agents.
+func NewRoundRobinRouter() Router {
+	return &roundRobinRouter{}
+}
+
+// Route implements Router.
+func (r *roundRobinRouter) Route(ctx context.Context, params protocol.SendTaskParams, agents []*Agent) (*Agent, error) {
+	return agents[(r.next.Add(1)-1)%uint64(len(agents))], nil
+}
+
+// skillRouter sends tasks to the agents whose skills fit them best.
+type skillRouter struct {
+	fallback Router
+}
+
+// NewSkillRouter returns a router matching tasks to the skills in the agent
+// cards. A task naming a skill under MetadataKeySkill goes to an agent declaring
+// it, or fails with ErrNoRoute. Otherwise the agents whose skill names and tags
+// occur most often in the message text are preferred. The fallback router, round
+// robin if nil, chooses between equally good agents.
+func NewSkillRouter(fallback Router) Router {
+	if fallback == nil {
+		fallback = NewRoundRobinRouter()
+	}
+	return &skillRouter{fallback: fallback}
+}
+
+// Route implements Router.
+func (r *skillRouter) Route(ctx context.Context, params protocol.SendTaskParams, agents []*Agent) (*Agent, error) {
+	if skillID, ok := params.Metadata[MetadataKeySkill].(string); ok && skillID != "" {
+		var candidates []*Agent
+		for _, agent := range agents {
+			if hasSkill(&agent.Card, skillID) {
+				candidates = append(candidates, agent)
+			}
+		}
+		if len(candidates) == 0 {
+			return nil, fmt.Errorf("%w: no agent has skill %q", ErrNoRoute, skillID)
+		}
+		return r.fallback.Route(ctx, params, candidates)
+	}
+	text := strings.ToLower(messageText(params.Message))
+	var (
+		best      []*Agent
+		bestScore int
+	)
+	for _, agent := range agents {
+		score := skillScore(&agent.Card, text)
+		switch {
+		case score > bestScore:
+			best, bestScore = []*Agent{agent}, score
+		case score == bestScore && score > 0:
+			best = append(best, agent)
+		}
+	}
+	if len(best) == 0 {
+		best = agents
+	}
+	return r.fallback.Route(ctx, params, best)
+}
+
+// hasSkill reports whether card declares the skill skillID.
+func hasSkill(card *server.AgentCard, skillID string) bool {
+	for _, skill := range card.Skills {
+		if skill.ID == skillID {
+			return true
+		}
+	}
+	return false
+}
+
+// skillScore counts the skill names and tags of card occurring in text, in lower case.
+func skillScore(card *server.AgentCard, text string) int {
+	score := 0
+	for _, skill := range card.Skills {
+		score += termScore(skill, text)
+	}
+	return score
+}
+
+// termScore counts the name and tags of skill occurring in text, in lower case.
+func termScore(skill server.AgentSkill, text string) int {
+	if text == "" {
+		return 0
+	}
+	score := 0
+	for _, term := range append([]string{skill.Name}, skill.Tags...) {
+		if term != "" && strings.Contains(text, strings.ToLower(term)) {
+			score++
+		}
+	}
+	return score
+}
+
+// selectSkill returns the ID of the skill of card fitting params best: the
+// skill named under MetadataKeySkill, or else the first of the skills whose
+// name and tags occur most often in the message text. It returns "" if no
+// skill fits.
+func selectSkill(card *server.AgentCard, params protocol.SendTaskParams) string {
+	if skillID, ok := params.Metadata[MetadataKeySkill].(string); ok && skillID != "" {
+		if hasSkill(card, skillID) {
+			return skillID
+		}
+		return ""
+	}
+	text := strings.ToLower(messageText(params.Message))
+	best, bestScore := "", 0
+	for _, skill := range card.Skills {
+		if score := termScore(skill, text); score > bestScore {
+			best, bestScore = skill.ID, score
+		}
+	}
+	return best
+}
+
+// messageText concatenates the text parts of message.
+func messageText(message protocol.Message) string {
+	var texts []string
+	for _, part := range message.Parts {
+		switch p := part.(type) {
+		case protocol.TextPart:
+			texts = append(texts, p.Text)
+		case *protocol.TextPart:
+			texts = append(texts, p.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
@@ -0,0 +1,46 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package protocol
+
+// MethodTasksAckEvents acknowledges the events of a task event stream processed
+// by the client. It is an extension method, not part of the A2A specification.
+const MethodTasksAckEvents = "tasks/ackEvents"
+
+// StreamIDHeader is the HTTP response header identifying an event stream whose
+// events can be acknowledged with tasks/ackEvents. The events of such a stream
+// carry their sequence number, starting at 1, in the SSE id field.
+const StreamIDHeader = "A2A-Stream-Id"
+
+// AckEventsParams are the params of tasks/ackEvents.
+type AckEventsParams struct {
+	// ID is the ID of the task.
+	ID string `json:"id"`
+	// StreamID identifies the stream, as sent in the StreamIDHeader.
+	StreamID string `json:"streamId"`
+	// Sequence is the sequence number of the last event processed; it
+	// acknowledges every event up to it.
+	Sequence int64 `json:"sequence"`
+	// Metadata is optional metadata.
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// AckEventsResult is the result of tasks/ackEvents.
+type AckEventsResult struct {
+	// ID is the ID of the task.
+	ID string `json:"id"`
+	// StreamID identifies the stream.
+	StreamID string `json:"streamId"`
+	// Acked is the sequence number acknowledged so far.
+	Acked int64 `json:"acked"`
+	// Sent is the sequence number of the last event sent on the stream.
+	Sent int64 `json:"sent"`
+	// Lag is the number of events sent but not yet acknowledged.
+	Lag int64 `json:"lag"`
+	// LatencyMs is the time from sending the acknowledged event to receiving the
+	// acknowledgement, in milliseconds, or zero if unknown.
+	LatencyMs int64 `json:"latencyMs"`
+}
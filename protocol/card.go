@@ -0,0 +1,13 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package protocol
+
+// MethodAgentCardUpdated is the notification sent by a server to the clients
+// connected over WebSocket when its agent card changes, e.g. its skills or its
+// authentication requirements. Its params are the new agent card. It is an
+// extension method, not part of the A2A specification.
+const MethodAgentCardUpdated = "agent/cardUpdated"
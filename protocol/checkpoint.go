@@ -0,0 +1,75 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package protocol
+
+// MetadataKeyCheckpoint is the task metadata key under which the last
+// checkpoint saved by the processor of a task is recorded in the task store.
+// Task managers remove it from the tasks they return to clients, and from the
+// store once the task ends.
+const MetadataKeyCheckpoint = "checkpoint"
+
+// TaskCheckpoint is the intermediate state of a task saved by its processor,
+// from which the processing restarts when the task is retried or resumed.
+type TaskCheckpoint struct {
+	// Step is the index of the step the processing restarts from.
+	Step int `json:"step"`
+	// Output is the output produced so far, if any.
+	Output string `json:"output,omitempty"`
+	// State holds the other values the processing needs to restart, which
+	// must be encodable as JSON.
+	State map[string]interface{} `json:"state,omitempty"`
+}
+
+// CheckpointFromMetadata returns the checkpoint recorded in metadata and
+// whether there was any. It accepts both TaskCheckpoint values and their
+// decoded JSON form.
+func CheckpointFromMetadata(metadata map[string]interface{}) (TaskCheckpoint, bool) {
+	raw, ok := metadata[MetadataKeyCheckpoint]
+	if !ok || raw == nil {
+		return TaskCheckpoint{}, false
+	}
+	if checkpoint, ok := raw.(TaskCheckpoint); ok {
+		return checkpoint, true
+	}
+	var checkpoint TaskCheckpoint
+	return checkpoint, decodeMetadata(raw, &checkpoint)
+}
+
+// WithCheckpoint returns a copy of metadata recording checkpoint, replacing
+// the previous one. The metadata is copied rather than modified.
+func WithCheckpoint(metadata map[string]interface{}, checkpoint TaskCheckpoint) map[string]interface{} {
+	updated := make(map[string]interface{}, len(metadata)+1)
+	for k, v := range metadata {
+		updated[k] = v
+	}
+	updated[MetadataKeyCheckpoint] = checkpoint
+	return updated
+}
+
+// WithoutCheckpoint returns metadata without checkpoint. The metadata is
+// copied rather than modified if it records one.
+func WithoutCheckpoint(metadata map[string]interface{}) map[string]interface{} {
+	if _, ok := metadata[MetadataKeyCheckpoint]; !ok {
+		return metadata
+	}
+	if len(metadata) == 1 {
+		return nil
+	}
+	updated := make(map[string]interface{}, len(metadata)-1)
+	for k, v := range metadata {
+		if k != MetadataKeyCheckpoint {
+			updated[k] = v
+		}
+	}
+	return updated
+}
+
+// Checkpoint returns the last checkpoint saved for the task and whether any
+// was.
+func (t Task) Checkpoint() (TaskCheckpoint, bool) {
+	return CheckpointFromMetadata(t.Metadata)
+}
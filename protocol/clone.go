@@ -0,0 +1,126 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package protocol
+
+// Clone returns a deep copy of the task, sharing none of its maps and slices,
+// so that the copy can be read or changed while the task changes. Metadata
+// and data values other than maps and slices of JSON values are shared.
+func (t *Task) Clone() *Task {
+	if t == nil {
+		return nil
+	}
+	clone := *t
+	clone.Status = t.Status.clone()
+	if t.Artifacts != nil {
+		clone.Artifacts = make([]Artifact, len(t.Artifacts))
+		for i, artifact := range t.Artifacts {
+			clone.Artifacts[i] = artifact.clone()
+		}
+	}
+	if t.History != nil {
+		clone.History = make([]Message, len(t.History))
+		for i, message := range t.History {
+			clone.History[i] = message.clone()
+		}
+	}
+	clone.Metadata = cloneMetadata(t.Metadata)
+	return &clone
+}
+
+// clone returns a deep copy of the status.
+func (s TaskStatus) clone() TaskStatus {
+	if s.Message != nil {
+		message := s.Message.clone()
+		s.Message = &message
+	}
+	if s.Error != nil {
+		taskError := *s.Error
+		taskError.Causes = append([]string(nil), s.Error.Causes...)
+		s.Error = &taskError
+	}
+	return s
+}
+
+// clone returns a deep copy of the message.
+func (m Message) clone() Message {
+	m.Parts = cloneParts(m.Parts)
+	m.Metadata = cloneMetadata(m.Metadata)
+	return m
+}
+
+// clone returns a deep copy of the artifact.
+func (a Artifact) clone() Artifact {
+	a.Parts = cloneParts(a.Parts)
+	a.Metadata = cloneMetadata(a.Metadata)
+	return a
+}
+
+// cloneParts returns a deep copy of parts.
+func cloneParts(parts []Part) []Part {
+	if parts == nil {
+		return nil
+	}
+	clones := make([]Part, len(parts))
+	for i, part := range parts {
+		switch p := part.(type) {
+		case TextPart:
+			p.Metadata = cloneMetadata(p.Metadata)
+			clones[i] = p
+		case *TextPart:
+			clone := *p
+			clone.Metadata = cloneMetadata(p.Metadata)
+			clones[i] = &clone
+		case FilePart:
+			p.Metadata = cloneMetadata(p.Metadata)
+			clones[i] = p
+		case *FilePart:
+			clone := *p
+			clone.Metadata = cloneMetadata(p.Metadata)
+			clones[i] = &clone
+		case DataPart:
+			p.Data = cloneValue(p.Data)
+			p.Metadata = cloneMetadata(p.Metadata)
+			clones[i] = p
+		case *DataPart:
+			clone := *p
+			clone.Data = cloneValue(p.Data)
+			clone.Metadata = cloneMetadata(p.Metadata)
+			clones[i] = &clone
+		default:
+			clones[i] = part
+		}
+	}
+	return clones
+}
+
+// cloneMetadata returns a deep copy of metadata.
+func cloneMetadata(metadata map[string]interface{}) map[string]interface{} {
+	if metadata == nil {
+		return nil
+	}
+	clone := make(map[string]interface{}, len(metadata))
+	for k, v := range metadata {
+		clone[k] = cloneValue(v)
+	}
+	return clone
+}
+
+// cloneValue returns a deep copy of v if it is a map or a slice of JSON
+// values, and v otherwise.
+func cloneValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		return cloneMetadata(value)
+	case []interface{}:
+		clone := make([]interface{}, len(value))
+		for i, item := range value {
+			clone[i] = cloneValue(item)
+		}
+		return clone
+	}
+	return v
+}
@@ -0,0 +1,59 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package protocol
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// MetadataKeyDeadlineBudget is the params metadata key under which the time
+// left to the originator's deadline is sent, in milliseconds. A relative budget
+// rather than an absolute deadline keeps the hops independent of clock skew.
+const MetadataKeyDeadlineBudget = "deadlineBudgetMs"
+
+// DeadlineBudgetFromMetadata returns the deadline budget recorded in metadata
+// and whether there was any. It accepts durations, integers and decoded JSON
+// numbers. A negative budget is returned as zero.
+func DeadlineBudgetFromMetadata(metadata map[string]interface{}) (time.Duration, bool) {
+	var ms float64
+	switch v := metadata[MetadataKeyDeadlineBudget].(type) {
+	case time.Duration:
+		ms = float64(v) / float64(time.Millisecond)
+	case int:
+		ms = float64(v)
+	case int64:
+		ms = float64(v)
+	case float64:
+		ms = v
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return 0, false
+		}
+		ms = f
+	default:
+		return 0, false
+	}
+	if ms < 0 {
+		return 0, true
+	}
+	return time.Duration(ms * float64(time.Millisecond)), true
+}
+
+// SetDeadlineBudget records budget in metadata, rounded down to milliseconds,
+// and returns the metadata, allocating it if nil.
+func SetDeadlineBudget(metadata map[string]interface{}, budget time.Duration) map[string]interface{} {
+	if metadata == nil {
+		metadata = make(map[string]interface{})
+	}
+	if budget < 0 {
+		budget = 0
+	}
+	metadata[MetadataKeyDeadlineBudget] = budget.Milliseconds()
+	return metadata
+}
@@ -0,0 +1,42 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package protocol
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeadlineBudget(t *testing.T) {
+	_, ok := DeadlineBudgetFromMetadata(nil)
+	assert.False(t, ok)
+
+	metadata := SetDeadlineBudget(nil, 1500*time.Millisecond+time.Microsecond)
+	budget, ok := DeadlineBudgetFromMetadata(metadata)
+	require.True(t, ok)
+	assert.Equal(t, 1500*time.Millisecond, budget)
+
+	// The budget survives a JSON round trip.
+	data, err := json.Marshal(metadata)
+	require.NoError(t, err)
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	budget, ok = DeadlineBudgetFromMetadata(decoded)
+	require.True(t, ok)
+	assert.Equal(t, 1500*time.Millisecond, budget)
+
+	budget, ok = DeadlineBudgetFromMetadata(map[string]interface{}{MetadataKeyDeadlineBudget: -5})
+	assert.True(t, ok)
+	assert.Zero(t, budget)
+
+	_, ok = DeadlineBudgetFromMetadata(map[string]interface{}{MetadataKeyDeadlineBudget: "soon"})
+	assert.False(t, ok)
+}
@@ -0,0 +1,120 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package protocol
+
+import (
+	"encoding/json"
+)
+
+// MetadataKeyTaskVersion is the metadata key of the version of the tasks
+// returned by tasks/get with TaskQueryParams.IfModifiedSince, an opaque string
+// to send back as TaskQueryParams.IfModifiedSince.
+const MetadataKeyTaskVersion = "taskVersion"
+
+// TaskVersionAny is the TaskQueryParams.IfModifiedSince of the first
+// tasks/get of a client without version yet: the task is returned whole, with
+// its version.
+const TaskVersionAny = "*"
+
+// MetadataKeyTaskDelta is the metadata key of the TaskDelta of the tasks
+// returned by tasks/get with TaskQueryParams.IfModifiedSince.
+const MetadataKeyTaskDelta = "taskDelta"
+
+// TaskDelta describes a task returned by tasks/get as a difference from the
+// version of TaskQueryParams.IfModifiedSince. The status and metadata of the
+// task are always complete.
+type TaskDelta struct {
+	// Since is the version the task is a difference from.
+	Since string `json:"since"`
+	// NotModified reports that the task did not change: it carries no
+	// history or artifacts.
+	NotModified bool `json:"notModified,omitempty"`
+	// HistoryOffset is the number of messages of the history at Since that
+	// precede the history of the task; 0 if its history replaces it.
+	HistoryOffset int `json:"historyOffset"`
+	// ArtifactOffset is the number of artifacts at Since that precede the
+	// artifacts of the task; 0 if its artifacts replace them.
+	ArtifactOffset int `json:"artifactOffset"`
+}
+
+// TaskVersion returns the version recorded in the metadata of task, or "".
+func TaskVersion(task *Task) string {
+	if task == nil {
+		return ""
+	}
+	version, _ := task.Metadata[MetadataKeyTaskVersion].(string)
+	return version
+}
+
+// TaskDeltaFromMetadata returns the TaskDelta recorded in metadata, or nil if
+// there is none. It accepts both TaskDelta values and their decoded JSON form.
+func TaskDeltaFromMetadata(metadata map[string]interface{}) *TaskDelta {
+	raw, ok := metadata[MetadataKeyTaskDelta]
+	if !ok || raw == nil {
+		return nil
+	}
+	switch delta := raw.(type) {
+	case TaskDelta:
+		return &delta
+	case *TaskDelta:
+		return delta
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil
+	}
+	var delta TaskDelta
+	if err := json.Unmarshal(data, &delta); err != nil {
+		return nil
+	}
+	return &delta
+}
+
+// MergeTaskDelta returns the task described by task, as returned by tasks/get,
+// applied to base, the task at the version task is a difference from. A
+// complete task is returned as is.
+func MergeTaskDelta(base, task *Task) *Task {
+	delta := TaskDeltaFromMetadata(task.Metadata)
+	if delta == nil || base == nil {
+		return task
+	}
+	merged := *task
+	merged.History, merged.Artifacts = base.History, base.Artifacts
+	if !delta.NotModified {
+		merged.History = mergeHistory(base.History, delta.HistoryOffset, task.History)
+		merged.Artifacts = mergeArtifacts(base.Artifacts, delta.ArtifactOffset, task.Artifacts)
+	}
+	merged.Metadata = make(map[string]interface{}, len(task.Metadata))
+	for k, v := range task.Metadata {
+		if k != MetadataKeyTaskDelta {
+			merged.Metadata[k] = v
+		}
+	}
+	return &merged
+}
+
+// mergeHistory returns the first offset messages of base followed by tail.
+func mergeHistory(base []Message, offset int, tail []Message) []Message {
+	if offset > len(base) {
+		offset = len(base)
+	}
+	if offset == 0 {
+		return tail
+	}
+	return append(append(make([]Message, 0, offset+len(tail)), base[:offset]...), tail...)
+}
+
+// mergeArtifacts returns the first offset artifacts of base followed by tail.
+func mergeArtifacts(base []Artifact, offset int, tail []Artifact) []Artifact {
+	if offset > len(base) {
+		offset = len(base)
+	}
+	if offset == 0 {
+		return tail
+	}
+	return append(append(make([]Artifact, 0, offset+len(tail)), base[:offset]...), tail...)
+}
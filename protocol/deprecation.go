@@ -0,0 +1,55 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package protocol
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// HTTP headers signaling that a requested method is deprecated (RFC 9745) and
+// when it stops working (RFC 8594).
+const (
+	DeprecationHeader = "Deprecation"
+	SunsetHeader      = "Sunset"
+)
+
+// Deprecation marks a JSON-RPC method, or a field of its params, as deprecated.
+type Deprecation struct {
+	// Method is the deprecated method, or the method whose field is deprecated.
+	Method string
+	// Field is the RFC 6901 JSON pointer of the deprecated params field, e.g.
+	// "/historyLength". Empty deprecates the whole method.
+	Field string
+	// Replacement optionally names what to use instead.
+	Replacement string
+	// Sunset is the optional time after which the method or field may stop working.
+	Sunset time.Time
+}
+
+// Warning returns the WarningCodeDeprecated warning reporting the use of d.
+func (d Deprecation) Warning() Warning {
+	return Warning{Code: WarningCodeDeprecated, Message: d.String(), Pointer: d.Field}
+}
+
+// String describes the deprecation.
+func (d Deprecation) String() string {
+	var b strings.Builder
+	if d.Field != "" {
+		fmt.Fprintf(&b, "params field %s of method %s is deprecated", d.Field, d.Method)
+	} else {
+		fmt.Fprintf(&b, "method %s is deprecated", d.Method)
+	}
+	if d.Replacement != "" {
+		fmt.Fprintf(&b, "; use %s instead", d.Replacement)
+	}
+	if !d.Sunset.IsZero() {
+		fmt.Fprintf(&b, "; it may stop working after %s", d.Sunset.UTC().Format(time.RFC3339))
+	}
+	return b.String()
+}
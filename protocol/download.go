@@ -0,0 +1,15 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package protocol
+
+// DefaultArtifactPath is the default path of the artifact download endpoint,
+// an extension of the A2A specification. GET {path}/{taskID}/{index} returns
+// the content of the artifact at index of the task, with its chunks joined:
+// the text of text parts, the bytes of file parts, or else the JSON encoding
+// of the artifact, which clients can also ask for with the Accept header.
+// Range requests are supported.
+const DefaultArtifactPath = "/artifacts"
@@ -0,0 +1,64 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package protocol
+
+// Skill example extension methods. They are not part of the A2A specification.
+const (
+	// MethodSkillsExamplesList lists the runnable examples of the agent's skills.
+	MethodSkillsExamplesList = "skills/examples/list"
+	// MethodSkillsExamplesRun runs a skill example in a sandbox.
+	MethodSkillsExamplesRun = "skills/examples/run"
+)
+
+// SkillExample is a runnable example input of a skill, declared in the agent card.
+type SkillExample struct {
+	// ID identifies the example within its skill.
+	ID string `json:"id"`
+	// Description optionally describes what the example shows.
+	Description string `json:"description,omitempty"`
+	// Input is the message sent to the agent when the example runs.
+	Input Message `json:"input"`
+	// ExpectedState is the state the example task should end in.
+	// Defaults to TaskStateCompleted.
+	ExpectedState TaskState `json:"expectedState,omitempty"`
+}
+
+// SkillExampleEntry is a SkillExample together with the skill it belongs to.
+type SkillExampleEntry struct {
+	// SkillID is the ID of the skill.
+	SkillID string `json:"skillId"`
+	SkillExample
+}
+
+// ListSkillExamplesResult is the result of skills/examples/list.
+type ListSkillExamplesResult struct {
+	// Examples are the examples of all skills, in agent card order.
+	Examples []SkillExampleEntry `json:"examples"`
+}
+
+// RunSkillExampleParams are the params of skills/examples/run.
+type RunSkillExampleParams struct {
+	// SkillID is the ID of the skill.
+	SkillID string `json:"skillId"`
+	// ExampleID is the ID of the example to run.
+	ExampleID string `json:"exampleId"`
+}
+
+// RunSkillExampleResult is the result of skills/examples/run.
+type RunSkillExampleResult struct {
+	// SkillID and ExampleID identify the example that ran.
+	SkillID   string `json:"skillId"`
+	ExampleID string `json:"exampleId"`
+	// Task is the example task in its final state. It is discarded after the run.
+	Task *Task `json:"task,omitempty"`
+	// Passed reports whether the task ended in the expected state.
+	Passed bool `json:"passed"`
+	// Error is the processing error, if any.
+	Error string `json:"error,omitempty"`
+	// DurationMs is how long the example took to run, in milliseconds.
+	DurationMs int64 `json:"durationMs"`
+}
@@ -0,0 +1,103 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package protocol
+
+// Task graph extension methods, not part of the A2A specification.
+const (
+	// MethodTasksGraphSend submits a set of tasks with dependencies between them.
+	MethodTasksGraphSend = "tasks/graph/send"
+	// MethodTasksGraphGet returns the state of a task graph.
+	MethodTasksGraphGet = "tasks/graph/get"
+)
+
+// TaskErrorCodeDependencyFailed is used for the tasks of a graph that were not
+// run because a task they depend on failed or was canceled.
+const TaskErrorCodeDependencyFailed TaskErrorCode = "dependency-failed"
+
+// TaskGraphNodeParams is a task of a graph with the tasks it depends on.
+type TaskGraphNodeParams struct {
+	// Task is the task, sent as with tasks/send once its dependencies completed.
+	Task SendTaskParams `json:"task"`
+	// DependsOn lists the IDs of the tasks of the graph that must complete
+	// before this one starts.
+	DependsOn []string `json:"dependsOn,omitempty"`
+}
+
+// SendTaskGraphParams are the params of tasks/graph/send. The dependencies
+// must form a directed acyclic graph.
+type SendTaskGraphParams struct {
+	// ID is the ID of the graph.
+	ID string `json:"id"`
+	// Tasks are the tasks of the graph.
+	Tasks []TaskGraphNodeParams `json:"tasks"`
+	// Metadata is optional metadata.
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// TaskGraphNodeState is the scheduling state of a task of a graph.
+type TaskGraphNodeState string
+
+// TaskGraphNodeState constants.
+const (
+	// TaskGraphNodePending is used for tasks waiting for their dependencies.
+	TaskGraphNodePending TaskGraphNodeState = "pending"
+	// TaskGraphNodeRunning is used for tasks being processed.
+	TaskGraphNodeRunning TaskGraphNodeState = "running"
+	// TaskGraphNodeCompleted is used for tasks that completed.
+	TaskGraphNodeCompleted TaskGraphNodeState = "completed"
+	// TaskGraphNodeFailed is used for tasks that failed.
+	TaskGraphNodeFailed TaskGraphNodeState = "failed"
+	// TaskGraphNodeCanceled is used for tasks that were canceled, or not run
+	// because a dependency failed or was canceled.
+	TaskGraphNodeCanceled TaskGraphNodeState = "canceled"
+	// TaskGraphNodeBlocked is used for tasks that stopped without completing,
+	// such as tasks requiring input; the tasks depending on them stay pending.
+	TaskGraphNodeBlocked TaskGraphNodeState = "blocked"
+)
+
+// TaskGraphState is the overall state of a task graph.
+type TaskGraphState string
+
+// TaskGraphState constants.
+const (
+	// TaskGraphRunning is used while tasks are pending or running.
+	TaskGraphRunning TaskGraphState = "running"
+	// TaskGraphCompleted is used once every task completed.
+	TaskGraphCompleted TaskGraphState = "completed"
+	// TaskGraphFailed is used once every task ended and one of them failed.
+	TaskGraphFailed TaskGraphState = "failed"
+	// TaskGraphCanceled is used once every task ended, one was canceled and none failed.
+	TaskGraphCanceled TaskGraphState = "canceled"
+	// TaskGraphBlocked is used when no task can progress and a task is blocked.
+	TaskGraphBlocked TaskGraphState = "blocked"
+)
+
+// TaskGraphNode is the state of a task of a graph.
+type TaskGraphNode struct {
+	// ID is the ID of the task.
+	ID string `json:"id"`
+	// DependsOn lists the IDs of the tasks this one depends on.
+	DependsOn []string `json:"dependsOn,omitempty"`
+	// State is the scheduling state of the task.
+	State TaskGraphNodeState `json:"state"`
+	// Status is the status of the task, once it ran.
+	Status *TaskStatus `json:"status,omitempty"`
+	// Error is the error of a failed or canceled task.
+	Error *TaskError `json:"error,omitempty"`
+}
+
+// TaskGraph is the result of tasks/graph/send and tasks/graph/get.
+type TaskGraph struct {
+	// ID is the ID of the graph.
+	ID string `json:"id"`
+	// State is the overall state of the graph.
+	State TaskGraphState `json:"state"`
+	// Nodes are the tasks of the graph, in submission order.
+	Nodes []TaskGraphNode `json:"nodes"`
+	// Metadata is the metadata the graph was sent with.
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
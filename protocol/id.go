@@ -0,0 +1,189 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package protocol
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// TaskIDHeader is the HTTP response header carrying the ID of the task of a
+// tasks/send or tasks/sendSubscribe request, notably when the server generated
+// it, so that streaming clients know it before the first event.
+const TaskIDHeader = "A2A-Task-Id"
+
+// IDGenerator generates the IDs of tasks and requests.
+type IDGenerator interface {
+	// NewID returns a new unique ID.
+	NewID() string
+}
+
+// IDGeneratorFunc adapts a function to an IDGenerator.
+type IDGeneratorFunc func() string
+
+// NewID implements IDGenerator.
+func (f IDGeneratorFunc) NewID() string {
+	return f()
+}
+
+// DefaultIDGenerator is the IDGenerator used when none is configured.
+var DefaultIDGenerator IDGenerator = NewUUIDv7Generator()
+
+// randomBytes fills b with random bytes.
+func randomBytes(b []byte) {
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("protocol: failed to read random bytes: %v", err))
+	}
+}
+
+// monotonicClock returns increasing millisecond timestamps with a counter
+// distinguishing the IDs generated within a millisecond. The timestamp moves
+// ahead of the wall clock when the counter overflows, and does not follow the
+// wall clock backwards, so that IDs keep sorting in generation order.
+type monotonicClock struct {
+	now     func() time.Time
+	maxSeq  uint64
+	mu      sync.Mutex
+	lastMs  int64
+	seq     uint64
+	started bool
+}
+
+// next returns the timestamp and counter of a new ID, and whether the
+// timestamp moved forward since the previous ID.
+func (c *monotonicClock) next() (int64, uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ms := c.now().UnixMilli()
+	if c.started && ms <= c.lastMs {
+		if c.seq < c.maxSeq {
+			c.seq++
+			return c.lastMs, c.seq, false
+		}
+		ms = c.lastMs + 1
+	}
+	c.started = true
+	c.lastMs = ms
+	c.seq = 0
+	return ms, 0, true
+}
+
+// UUIDv7Generator generates time-ordered UUIDs of version 7 (RFC 9562). The
+// 12 bits following the millisecond timestamp count the IDs generated within
+// a millisecond, so that the IDs of a generator sort in generation order.
+type UUIDv7Generator struct {
+	clock monotonicClock
+}
+
+// NewUUIDv7Generator creates a UUIDv7 generator.
+func NewUUIDv7Generator() *UUIDv7Generator {
+	return &UUIDv7Generator{clock: monotonicClock{now: time.Now, maxSeq: 1<<12 - 1}}
+}
+
+// NewID implements IDGenerator.
+func (g *UUIDv7Generator) NewID() string {
+	ms, seq, _ := g.clock.next()
+	var b [16]byte
+	randomBytes(b[8:])
+	binary.BigEndian.PutUint64(b[:8], uint64(ms)<<16|0x7<<12|seq)
+	b[8] = b[8]&0x3f | 0x80 // RFC 9562 variant.
+	var s [36]byte
+	hex.Encode(s[0:8], b[0:4])
+	s[8] = '-'
+	hex.Encode(s[9:13], b[4:6])
+	s[13] = '-'
+	hex.Encode(s[14:18], b[6:8])
+	s[18] = '-'
+	hex.Encode(s[19:23], b[8:10])
+	s[23] = '-'
+	hex.Encode(s[24:], b[10:])
+	return string(s[:])
+}
+
+// crockfordAlphabet is the Crockford base32 alphabet of ULIDs.
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ULIDGenerator generates ULIDs: a millisecond timestamp followed by 80
+// random bits, encoded in 26 Crockford base32 characters. Within a
+// millisecond, the random bits of the previous ID are incremented, so that the
+// IDs of a generator sort in generation order.
+type ULIDGenerator struct {
+	clock  monotonicClock
+	mu     sync.Mutex
+	hi, lo uint64 // Random bits of the last ID: 16 in hi and 64 in lo.
+}
+
+// NewULIDGenerator creates a ULID generator.
+func NewULIDGenerator() *ULIDGenerator {
+	return &ULIDGenerator{clock: monotonicClock{now: time.Now, maxSeq: 1<<63 - 1}}
+}
+
+// NewID implements IDGenerator.
+func (g *ULIDGenerator) NewID() string {
+	g.mu.Lock()
+	ms, _, fresh := g.clock.next()
+	if fresh {
+		var b [10]byte
+		randomBytes(b[:])
+		g.hi = uint64(binary.BigEndian.Uint16(b[:2]))
+		g.lo = binary.BigEndian.Uint64(b[2:])
+	} else if g.lo++; g.lo == 0 {
+		// The random bits would overflow in the unlikely case they are all
+		// ones: carry into hi, which wraps around within 16 bits.
+		g.hi = (g.hi + 1) & 0xffff
+	}
+	hi := uint64(ms)<<16 | g.hi
+	lo := g.lo
+	g.mu.Unlock()
+
+	var s [26]byte
+	for i := len(s) - 1; i >= 0; i-- {
+		s[i] = crockfordAlphabet[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(s[:])
+}
+
+// snowflakeEpoch is the origin of the timestamps of snowflake IDs.
+var snowflakeEpoch = time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// maxSnowflakeNode is the largest node number of a snowflake generator.
+const maxSnowflakeNode = 1<<10 - 1
+
+// SnowflakeGenerator generates snowflake IDs: decimal 63-bit integers made of
+// a 41-bit millisecond timestamp since 2025-01-01 UTC, a 10-bit node number
+// and a 12-bit counter of the IDs generated within a millisecond. Each
+// generator of a deployment must have a distinct node number.
+type SnowflakeGenerator struct {
+	clock monotonicClock
+	node  int64
+}
+
+// NewSnowflakeGenerator creates a snowflake generator for node, which must be
+// between 0 and 1023.
+func NewSnowflakeGenerator(node int64) (*SnowflakeGenerator, error) {
+	if node < 0 || node > maxSnowflakeNode {
+		return nil, fmt.Errorf("snowflake node %d is out of range [0, %d]", node, maxSnowflakeNode)
+	}
+	return &SnowflakeGenerator{
+		clock: monotonicClock{now: time.Now, maxSeq: 1<<12 - 1},
+		node:  node,
+	}, nil
+}
+
+// NewID implements IDGenerator.
+func (g *SnowflakeGenerator) NewID() string {
+	ms, seq, _ := g.clock.next()
+	id := (ms-snowflakeEpoch.UnixMilli())<<22 | g.node<<12 | int64(seq)
+	return strconv.FormatInt(id, 10)
+}
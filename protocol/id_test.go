@@ -0,0 +1,88 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package protocol
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// generateIDs returns n IDs of g, checking that they are distinct.
+func generateIDs(t *testing.T, g IDGenerator, n int) []string {
+	t.Helper()
+	ids := make([]string, n)
+	seen := make(map[string]bool, n)
+	for i := range ids {
+		ids[i] = g.NewID()
+		require.False(t, seen[ids[i]], "duplicate ID %s", ids[i])
+		seen[ids[i]] = true
+	}
+	return ids
+}
+
+func TestUUIDv7Generator(t *testing.T) {
+	ids := generateIDs(t, NewUUIDv7Generator(), 10000)
+	pattern := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	for _, id := range ids[:10] {
+		assert.Regexp(t, pattern, id)
+	}
+	assert.True(t, sort.StringsAreSorted(ids), "IDs sort in generation order")
+
+	ms, err := strconv.ParseInt(ids[0][:8]+ids[0][9:13], 16, 64)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), time.UnixMilli(ms), time.Minute)
+}
+
+func TestULIDGenerator(t *testing.T) {
+	ids := generateIDs(t, NewULIDGenerator(), 10000)
+	pattern := regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Z]{25}$`)
+	for _, id := range ids[:10] {
+		assert.Regexp(t, pattern, id)
+	}
+	assert.True(t, sort.StringsAreSorted(ids), "IDs sort in generation order")
+}
+
+func TestSnowflakeGenerator(t *testing.T) {
+	_, err := NewSnowflakeGenerator(1024)
+	assert.Error(t, err)
+	_, err = NewSnowflakeGenerator(-1)
+	assert.Error(t, err)
+
+	g, err := NewSnowflakeGenerator(5)
+	require.NoError(t, err)
+	ids := generateIDs(t, g, 10000)
+	prev := int64(-1)
+	for _, id := range ids {
+		n, err := strconv.ParseInt(id, 10, 64)
+		require.NoError(t, err)
+		require.Greater(t, n, prev)
+		assert.Equal(t, int64(5), n>>12&maxSnowflakeNode)
+		prev = n
+	}
+}
+
+func TestMonotonicClock(t *testing.T) {
+	now := time.UnixMilli(1000)
+	clock := monotonicClock{now: func() time.Time { return now }, maxSeq: 1}
+	next := func() [2]int64 {
+		ms, seq, _ := clock.next()
+		return [2]int64{ms, int64(seq)}
+	}
+	assert.Equal(t, [2]int64{1000, 0}, next())
+	assert.Equal(t, [2]int64{1000, 1}, next())
+	assert.Equal(t, [2]int64{1001, 0}, next(), "the counter overflow moves the timestamp ahead")
+	now = time.UnixMilli(500)
+	assert.Equal(t, [2]int64{1001, 1}, next(), "the timestamp does not go backwards")
+	now = time.UnixMilli(2000)
+	assert.Equal(t, [2]int64{2000, 0}, next())
+}
@@ -0,0 +1,60 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package protocol
+
+import "encoding/json"
+
+// MetadataKeyTaskPlan is the metadata key under which the task returned by a
+// dry run of tasks/send holds its TaskPlan.
+const MetadataKeyTaskPlan = "plan"
+
+// TaskPlan describes how an agent would handle a task, as reported by a dry run
+// of tasks/send once the request passed validation, authentication, quota and
+// routing checks.
+type TaskPlan struct {
+	// Agent is the name of the downstream agent the task would be routed to,
+	// if the agent delegates it.
+	Agent string `json:"agent,omitempty"`
+	// Skill is the ID of the skill selected for the task, if known.
+	Skill string `json:"skill,omitempty"`
+	// InputTokens is the number of tokens the message would be charged.
+	InputTokens int `json:"inputTokens,omitempty"`
+	// UsedTokens is the number of tokens the task has already used.
+	UsedTokens int `json:"usedTokens,omitempty"`
+	// MaxTaskTokens is the token budget of the task; zero means no budget.
+	MaxTaskTokens int `json:"maxTaskTokens,omitempty"`
+	// DeadlineBudgetMs is the time the task would have to finish, in
+	// milliseconds; zero means no deadline.
+	DeadlineBudgetMs int64 `json:"deadlineBudgetMs,omitempty"`
+	// Warnings lists the non-fatal issues found in the request, such as
+	// dropped message parts.
+	Warnings []Warning `json:"warnings,omitempty"`
+}
+
+// TaskPlanFromMetadata returns the task plan recorded in metadata and whether
+// there was any. It accepts both TaskPlan values and their decoded JSON form.
+func TaskPlanFromMetadata(metadata map[string]interface{}) (*TaskPlan, bool) {
+	raw, ok := metadata[MetadataKeyTaskPlan]
+	if !ok || raw == nil {
+		return nil, false
+	}
+	switch plan := raw.(type) {
+	case *TaskPlan:
+		return plan, true
+	case TaskPlan:
+		return &plan, true
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, false
+	}
+	var plan TaskPlan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, false
+	}
+	return &plan, true
+}
@@ -0,0 +1,102 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package protocol
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// MethodTasksPollEvents returns the events of a task since a cursor, waiting
+// for some if there are none yet. It is a long-polling alternative to
+// tasks/resubscribe for environments where SSE streams are blocked or buffered.
+// It is an extension method, not part of the A2A specification.
+const MethodTasksPollEvents = "tasks/pollEvents"
+
+// PollEventsParams are the params of tasks/pollEvents.
+type PollEventsParams struct {
+	// ID is the ID of the task.
+	ID string `json:"id"`
+	// Cursor is the cursor returned by the previous poll. Without one, a new
+	// poll session starts with the current status of the task.
+	Cursor string `json:"cursor,omitempty"`
+	// WaitMs is how long the server may wait for events if there are none,
+	// in milliseconds. The server may wait less.
+	WaitMs int64 `json:"waitMs,omitempty"`
+	// MaxEvents bounds the number of events returned, if positive.
+	MaxEvents int `json:"maxEvents,omitempty"`
+	// Metadata is optional metadata.
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// PollEventsResult is the result of tasks/pollEvents.
+type PollEventsResult struct {
+	// ID is the ID of the task.
+	ID string `json:"id"`
+	// Events are the events since the cursor, oldest first. It is empty if
+	// none arrived within the wait.
+	Events []PolledEvent `json:"events"`
+	// Cursor is the cursor to pass to the next poll.
+	Cursor string `json:"cursor"`
+	// Done reports that the events end with the final event of the task: no
+	// more polls are needed.
+	Done bool `json:"done"`
+}
+
+// PolledEvent is a task event returned by tasks/pollEvents, tagged with its
+// type like the events of SSE streams.
+type PolledEvent struct {
+	// Type is EventTaskStatusUpdate or EventTaskArtifactUpdate.
+	Type string `json:"type"`
+	// Event is the event.
+	Event TaskEvent `json:"event"`
+}
+
+// NewPolledEvent tags event with its type. Events of other types than
+// TaskStatusUpdateEvent and TaskArtifactUpdateEvent are not supported.
+func NewPolledEvent(event TaskEvent) (PolledEvent, error) {
+	switch e := event.(type) {
+	case TaskStatusUpdateEvent:
+		return PolledEvent{Type: EventTaskStatusUpdate, Event: e}, nil
+	case *TaskStatusUpdateEvent:
+		return PolledEvent{Type: EventTaskStatusUpdate, Event: *e}, nil
+	case TaskArtifactUpdateEvent:
+		return PolledEvent{Type: EventTaskArtifactUpdate, Event: e}, nil
+	case *TaskArtifactUpdateEvent:
+		return PolledEvent{Type: EventTaskArtifactUpdate, Event: *e}, nil
+	}
+	return PolledEvent{}, fmt.Errorf("unsupported event type %T", event)
+}
+
+// UnmarshalJSON decodes the event according to its type.
+func (e *PolledEvent) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Type  string          `json:"type"`
+		Event json.RawMessage `json:"event"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	switch raw.Type {
+	case EventTaskStatusUpdate:
+		var event TaskStatusUpdateEvent
+		if err := json.Unmarshal(raw.Event, &event); err != nil {
+			return err
+		}
+		e.Event = event
+	case EventTaskArtifactUpdate:
+		var event TaskArtifactUpdateEvent
+		if err := json.Unmarshal(raw.Event, &event); err != nil {
+			return err
+		}
+		e.Event = event
+	default:
+		return fmt.Errorf("unknown event type %q", raw.Type)
+	}
+	e.Type = raw.Type
+	return nil
+}
@@ -0,0 +1,89 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package protocol
+
+import "encoding/json"
+
+// MetadataKeyProgress is the metadata key under which the progress of a task is
+// recorded, in the metadata of status update events and of the task itself.
+const MetadataKeyProgress = "progress"
+
+// TaskProgress is the structured progress of a task, for rendering progress bars.
+type TaskProgress struct {
+	// Percent is the completed part of the work, from 0 to 100.
+	Percent float64 `json:"percent"`
+	// Step is the current step, from 1, if the work is made of steps.
+	Step int `json:"step,omitempty"`
+	// TotalSteps is the number of steps, if known.
+	TotalSteps int `json:"totalSteps,omitempty"`
+	// Description describes the current step.
+	Description string `json:"description,omitempty"`
+}
+
+// NewStepProgress returns the progress of the given step out of total, the
+// steps before it being complete.
+func NewStepProgress(step, total int, description string) TaskProgress {
+	progress := TaskProgress{Step: step, TotalSteps: total, Description: description}
+	if total > 0 && step > 0 {
+		progress.Percent = clampPercent(float64(step-1) * 100 / float64(total))
+	}
+	return progress
+}
+
+// clampPercent bounds percent to [0, 100].
+func clampPercent(percent float64) float64 {
+	switch {
+	case percent < 0:
+		return 0
+	case percent > 100:
+		return 100
+	}
+	return percent
+}
+
+// ProgressFromMetadata returns the progress recorded in metadata and whether
+// there was any. It accepts both TaskProgress values and their decoded JSON form.
+func ProgressFromMetadata(metadata map[string]interface{}) (TaskProgress, bool) {
+	raw, ok := metadata[MetadataKeyProgress]
+	if !ok || raw == nil {
+		return TaskProgress{}, false
+	}
+	if progress, ok := raw.(TaskProgress); ok {
+		return progress, true
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return TaskProgress{}, false
+	}
+	var progress TaskProgress
+	if err := json.Unmarshal(data, &progress); err != nil {
+		return TaskProgress{}, false
+	}
+	return progress, true
+}
+
+// WithProgress returns a copy of metadata recording progress, with Percent
+// bounded to [0, 100]. The metadata is copied rather than modified.
+func WithProgress(metadata map[string]interface{}, progress TaskProgress) map[string]interface{} {
+	progress.Percent = clampPercent(progress.Percent)
+	updated := make(map[string]interface{}, len(metadata)+1)
+	for k, v := range metadata {
+		updated[k] = v
+	}
+	updated[MetadataKeyProgress] = progress
+	return updated
+}
+
+// Progress returns the progress reported by the event and whether it reports any.
+func (e TaskStatusUpdateEvent) Progress() (TaskProgress, bool) {
+	return ProgressFromMetadata(e.Metadata)
+}
+
+// Progress returns the last progress reported for the task and whether any was.
+func (t Task) Progress() (TaskProgress, bool) {
+	return ProgressFromMetadata(t.Metadata)
+}
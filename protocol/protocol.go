@@ -27,6 +27,18 @@ const (
 	EventClose = "close"
 )
 
+// Media types of the event streams of tasks/sendSubscribe and
+// tasks/resubscribe, negotiated with the Accept request header.
+const (
+	// ContentTypeSSE is the media type of Server-Sent Events streams, the default.
+	ContentTypeSSE = "text/event-stream"
+	// ContentTypeNDJSON is the media type of newline-delimited JSON streams,
+	// for clients that cannot consume SSE. Each line is a JSON-RPC response
+	// whose result is an event, with two extension members: "event", the
+	// event type, and "eventId", the event ID if any.
+	ContentTypeNDJSON = "application/x-ndjson"
+)
+
 // A2A HTTP Endpoint Paths define the standard paths used in the A2A protocol.
 const (
 	// AgentCardPath is the path for the agent metadata JSON endpoint.
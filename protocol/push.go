@@ -0,0 +1,55 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package protocol
+
+// PushEventKind is a kind of task events sent as push notifications, as
+// selected by PushNotificationConfig.Events.
+type PushEventKind string
+
+const (
+	// PushEventFinal selects the final status update of the task.
+	PushEventFinal PushEventKind = "final"
+	// PushEventStatus selects every status update, including the final one.
+	PushEventStatus PushEventKind = "status"
+	// PushEventArtifact selects the artifact updates.
+	PushEventArtifact PushEventKind = "artifact"
+)
+
+// DefaultPushEvents are the events notified when none are selected.
+var DefaultPushEvents = []PushEventKind{PushEventFinal}
+
+// PushEventSelected reports whether kinds select event, DefaultPushEvents if
+// kinds is empty.
+func PushEventSelected(kinds []PushEventKind, event TaskEvent) bool {
+	if len(kinds) == 0 {
+		kinds = DefaultPushEvents
+	}
+	var status, artifact bool
+	switch event.(type) {
+	case TaskStatusUpdateEvent, *TaskStatusUpdateEvent:
+		status = true
+	case TaskArtifactUpdateEvent, *TaskArtifactUpdateEvent:
+		artifact = true
+	}
+	for _, kind := range kinds {
+		switch kind {
+		case PushEventFinal:
+			if status && event.IsFinal() {
+				return true
+			}
+		case PushEventStatus:
+			if status {
+				return true
+			}
+		case PushEventArtifact:
+			if artifact {
+				return true
+			}
+		}
+	}
+	return false
+}
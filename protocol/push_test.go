@@ -0,0 +1,39 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package protocol
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPushEventSelected(t *testing.T) {
+	working := TaskStatusUpdateEvent{Status: TaskStatus{State: TaskStateWorking}}
+	completed := TaskStatusUpdateEvent{Status: TaskStatus{State: TaskStateCompleted}, Final: true}
+	artifact := TaskArtifactUpdateEvent{Artifact: Artifact{}}
+	tests := []struct {
+		name  string
+		kinds []PushEventKind
+		event TaskEvent
+		want  bool
+	}{
+		{name: "default final", kinds: nil, event: completed, want: true},
+		{name: "default not working", kinds: nil, event: working, want: false},
+		{name: "default not artifact", kinds: nil, event: artifact, want: false},
+		{name: "status", kinds: []PushEventKind{PushEventStatus}, event: working, want: true},
+		{name: "status not artifact", kinds: []PushEventKind{PushEventStatus}, event: artifact, want: false},
+		{name: "artifact", kinds: []PushEventKind{PushEventFinal, PushEventArtifact}, event: artifact, want: true},
+		{name: "artifact not working", kinds: []PushEventKind{PushEventArtifact}, event: working, want: false},
+		{name: "unknown kind", kinds: []PushEventKind{"everything"}, event: completed, want: false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, PushEventSelected(tc.kinds, tc.event))
+		})
+	}
+}
@@ -0,0 +1,66 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package protocol
+
+import "strconv"
+
+// MetadataKeyEventID is the event metadata key under which task managers record
+// the ID of each event: the decimal sequence number of the event among those of
+// its task, from 1. Servers also send it in the SSE id field, unless the stream
+// is acknowledged (see StreamIDHeader). A client passes the ID of the last event
+// it received as TaskIDParams.LastEventID to tasks/resubscribe to have the
+// events it missed replayed, if the task manager keeps the event history.
+const MetadataKeyEventID = "eventId"
+
+// EventID returns the ID recorded in the metadata of event, or an empty string
+// if it has none.
+func EventID(event TaskEvent) string {
+	var metadata map[string]interface{}
+	switch e := event.(type) {
+	case TaskStatusUpdateEvent:
+		metadata = e.Metadata
+	case *TaskStatusUpdateEvent:
+		metadata = e.Metadata
+	case TaskArtifactUpdateEvent:
+		metadata = e.Metadata
+	case *TaskArtifactUpdateEvent:
+		metadata = e.Metadata
+	}
+	id, _ := metadata[MetadataKeyEventID].(string)
+	return id
+}
+
+// WithEventID returns a copy of event with id recorded in its metadata. The
+// metadata is copied rather than modified. Events of other types are returned as is.
+func WithEventID(event TaskEvent, id string) TaskEvent {
+	switch e := event.(type) {
+	case TaskStatusUpdateEvent:
+		e.Metadata = withEventID(e.Metadata, id)
+		return e
+	case TaskArtifactUpdateEvent:
+		e.Metadata = withEventID(e.Metadata, id)
+		return e
+	}
+	return event
+}
+
+// withEventID returns a copy of metadata recording id.
+func withEventID(metadata map[string]interface{}, id string) map[string]interface{} {
+	updated := make(map[string]interface{}, len(metadata)+1)
+	for k, v := range metadata {
+		updated[k] = v
+	}
+	updated[MetadataKeyEventID] = id
+	return updated
+}
+
+// EventSequence returns the sequence number in the ID of event, and whether
+// the event has a valid one.
+func EventSequence(event TaskEvent) (uint64, bool) {
+	seq, err := strconv.ParseUint(EventID(event), 10, 64)
+	return seq, err == nil && seq > 0
+}
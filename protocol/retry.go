@@ -0,0 +1,82 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package protocol
+
+import "encoding/json"
+
+const (
+	// MetadataKeyAttempts is the task metadata key under which the failed
+	// attempts of a retried task are recorded, as a list of TaskAttempt.
+	MetadataKeyAttempts = "attempts"
+	// MetadataKeyRetry is the metadata key of the status update event announcing
+	// a retry, holding the TaskAttempt that failed.
+	MetadataKeyRetry = "retry"
+)
+
+// TaskAttempt records a failed attempt at processing a task that is retried.
+type TaskAttempt struct {
+	// Attempt is the number of the failed attempt, from 1.
+	Attempt int `json:"attempt"`
+	// Error is the error the attempt failed with.
+	Error *TaskError `json:"error,omitempty"`
+	// FailedAt is the ISO 8601 timestamp of the failure.
+	FailedAt string `json:"failedAt"`
+	// RetryDelayMs is the backoff before the next attempt, in milliseconds.
+	RetryDelayMs int64 `json:"retryDelayMs"`
+}
+
+// AttemptsFromMetadata returns the failed attempts recorded in metadata. It
+// accepts both TaskAttempt values and their decoded JSON form.
+func AttemptsFromMetadata(metadata map[string]interface{}) []TaskAttempt {
+	raw, ok := metadata[MetadataKeyAttempts]
+	if !ok || raw == nil {
+		return nil
+	}
+	if attempts, ok := raw.([]TaskAttempt); ok {
+		return attempts
+	}
+	var attempts []TaskAttempt
+	if !decodeMetadata(raw, &attempts) {
+		return nil
+	}
+	return attempts
+}
+
+// RetryFromMetadata returns the failed attempt announced by the metadata of a
+// status update event, and whether it announces one.
+func RetryFromMetadata(metadata map[string]interface{}) (TaskAttempt, bool) {
+	raw, ok := metadata[MetadataKeyRetry]
+	if !ok || raw == nil {
+		return TaskAttempt{}, false
+	}
+	if attempt, ok := raw.(TaskAttempt); ok {
+		return attempt, true
+	}
+	var attempt TaskAttempt
+	return attempt, decodeMetadata(raw, &attempt)
+}
+
+// WithAttempt returns a copy of metadata with attempt appended to the recorded
+// attempts. The metadata is copied rather than modified.
+func WithAttempt(metadata map[string]interface{}, attempt TaskAttempt) map[string]interface{} {
+	attempts := AttemptsFromMetadata(metadata)
+	updated := make(map[string]interface{}, len(metadata)+1)
+	for k, v := range metadata {
+		updated[k] = v
+	}
+	updated[MetadataKeyAttempts] = append(attempts[:len(attempts):len(attempts)], attempt)
+	return updated
+}
+
+// decodeMetadata decodes the JSON form of a metadata value into v.
+func decodeMetadata(raw interface{}, v interface{}) bool {
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, v) == nil
+}
@@ -0,0 +1,56 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package protocol
+
+// TaskErrorCode classifies the error of a failed or canceled task.
+type TaskErrorCode string
+
+// TaskErrorCode constants define the standardized task error codes. Agents may
+// use their own codes too.
+const (
+	// TaskErrorCodeInternal is used for processing errors without a more specific code.
+	TaskErrorCodeInternal TaskErrorCode = "internal"
+	// TaskErrorCodeDeadlineExceeded is used when the task ran out of time.
+	TaskErrorCodeDeadlineExceeded TaskErrorCode = "deadline-exceeded"
+	// TaskErrorCodeUnavailable is used when a dependency of the agent was unavailable.
+	TaskErrorCodeUnavailable TaskErrorCode = "unavailable"
+	// TaskErrorCodeInvalidInput is used when the input of the task cannot be processed.
+	TaskErrorCodeInvalidInput TaskErrorCode = "invalid-input"
+	// TaskErrorCodeCanceled is used for canceled tasks; TaskStatus.CancelReason
+	// tells why.
+	TaskErrorCodeCanceled TaskErrorCode = "canceled"
+)
+
+// TaskError is the structured error of a failed or canceled task, recorded in
+// TaskStatus.Error next to the human-readable status message, so clients can
+// decide programmatically whether to retry.
+type TaskError struct {
+	// Code classifies the error.
+	Code TaskErrorCode `json:"code"`
+	// Message describes the error.
+	Message string `json:"message"`
+	// Retryable tells whether sending the task again may succeed.
+	Retryable bool `json:"retryable"`
+	// Causes are the messages of the errors that caused this one, from the
+	// outermost to the root cause.
+	Causes []string `json:"causes,omitempty"`
+}
+
+// Error implements error, so a TaskError can be returned as is.
+func (e *TaskError) Error() string {
+	return string(e.Code) + ": " + e.Message
+}
+
+// NewCancelTaskError returns the error recorded for a task canceled for reason.
+// Tasks canceled because they timed out are retryable.
+func NewCancelTaskError(reason CancelReason) *TaskError {
+	return &TaskError{
+		Code:      TaskErrorCodeCanceled,
+		Message:   "task canceled: " + string(reason),
+		Retryable: reason == CancelReasonTimeout,
+	}
+}
@@ -48,6 +48,31 @@ const (
 	MessageRoleAgent MessageRole = "agent"
 )
 
+// CancelReason describes why a task was canceled, so downstream systems and
+// metrics can distinguish cancellations.
+type CancelReason string
+
+// CancelReason constants define the standardized cancellation reasons.
+const (
+	// CancelReasonUserRequested is used when a client explicitly requested cancellation.
+	CancelReasonUserRequested CancelReason = "user-requested"
+	// CancelReasonTimeout is used when the task exceeded its allotted time.
+	CancelReasonTimeout CancelReason = "timeout"
+	// CancelReasonPolicy is used when a server-side policy (quota, limits, admin action) canceled the task.
+	CancelReasonPolicy CancelReason = "policy"
+	// CancelReasonSuperseded is used when the task was replaced by a newer task.
+	CancelReasonSuperseded CancelReason = "superseded"
+)
+
+// IsValid reports whether r is one of the standardized cancellation reasons.
+func (r CancelReason) IsValid() bool {
+	switch r {
+	case CancelReasonUserRequested, CancelReasonTimeout, CancelReasonPolicy, CancelReasonSuperseded:
+		return true
+	}
+	return false
+}
+
 // PartType indicates the type of content within a message part.
 // See A2A Spec section on Message Parts.
 type PartType string
@@ -176,6 +201,23 @@ type PushNotificationConfig struct {
 	Authentication *AuthenticationInfo `json:"authentication,omitempty"`
 	// Metadata is optional additional configuration data.
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	// Events selects the events notified, DefaultPushEvents if empty. It is
+	// an extension of the A2A specification.
+	Events []PushEventKind `json:"events,omitempty"`
+	// Template, if set, is a Go text/template rendering the body of the
+	// notifications instead of the default JSON-RPC request, for receivers
+	// expecting another shape. It is executed with the notification, with
+	// the fields TaskID, EventType and Event, and a json function encoding its
+	// argument. It is an extension of the A2A specification.
+	Template string `json:"template,omitempty"`
+	// Headers are additional HTTP headers of the notification requests, for
+	// receivers authenticating callbacks with custom headers. It is an
+	// extension of the A2A specification.
+	Headers map[string]string `json:"headers,omitempty"`
+	// ClientCertificate, if set, names the client certificate registered
+	// with the agent presented to the URL over mutual TLS. It is an extension
+	// of the A2A specification.
+	ClientCertificate string `json:"clientCertificate,omitempty"`
 }
 
 // TaskPushNotificationConfig associates a task ID with push notification settings.
@@ -326,6 +368,10 @@ type TaskStatus struct {
 	Message *Message `json:"message,omitempty"`
 	// Timestamp is the ISO 8601 timestamp of the status change.
 	Timestamp string `json:"timestamp"`
+	// CancelReason is the reason the task was canceled, set only for the canceled state.
+	CancelReason CancelReason `json:"cancelReason,omitempty"`
+	// Error is the structured error of a failed or canceled task.
+	Error *TaskError `json:"error,omitempty"`
 }
 
 // Task represents a unit of work being processed by the agent.
@@ -343,6 +389,10 @@ type Task struct {
 	History []Message `json:"history,omitempty"`
 	// Metadata is the optional metadata associated with the task.
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	// Version is incremented by the task store on every change of the task,
+	// from 1 when it is created. Writers pass it back to detect concurrent
+	// changes, e.g. in the If-Match header of the admin API.
+	Version uint64 `json:"version,omitempty"`
 }
 
 // TaskEvent is an interface for events published during task execution (streaming).
@@ -410,6 +460,9 @@ type SendTaskParams struct {
 	HistoryLength *int `json:"historyLength,omitempty"`
 	// Metadata is the optional metadata.
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	// DryRun asks tasks/send to check the request and report its TaskPlan,
+	// under MetadataKeyTaskPlan of the returned task, without running it.
+	DryRun bool `json:"dryRun,omitempty"`
 }
 
 // TaskQueryParams defines the parameters for the tasks_get RPC method.
@@ -419,6 +472,13 @@ type TaskQueryParams struct {
 	ID string `json:"id"`
 	// HistoryLength is the requested message history length.
 	HistoryLength *int `json:"historyLength,omitempty"`
+	// IfModifiedSince is the optional version of the task returned by a
+	// previous tasks/get (see TaskVersion), or TaskVersionAny to get a first
+	// version. The task is then returned with its version and as a TaskDelta
+	// from IfModifiedSince: not modified, or with only the messages and
+	// artifacts added since. Use MergeTaskDelta to apply it. Tasks are
+	// returned without version if it is empty.
+	IfModifiedSince string `json:"ifModifiedSince,omitempty"`
 }
 
 // TaskIDParams defines parameters for methods needing only a task ID (e.g., tasks_cancel).
@@ -426,6 +486,13 @@ type TaskQueryParams struct {
 type TaskIDParams struct {
 	// ID is the ID of the task.
 	ID string `json:"id"`
+	// Reason is the optional cancellation reason, only used by tasks/cancel.
+	// Defaults to CancelReasonUserRequested when empty.
+	Reason CancelReason `json:"reason,omitempty"`
+	// LastEventID is the optional ID of the last event received, only used by
+	// tasks/resubscribe: the events emitted after it are replayed before live
+	// events, if the server keeps the event history (see MetadataKeyEventID).
+	LastEventID string `json:"lastEventId,omitempty"`
 }
 
 // --- Factory Functions ---
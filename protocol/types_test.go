@@ -121,3 +121,20 @@ func TestTaskEvent_IsFinal(t *testing.T) {
 		})
 	}
 }
+
+func TestTask_Clone(t *testing.T) {
+	task := NewTask("task-1", nil)
+	task.Metadata = map[string]interface{}{"labels": []interface{}{"a"}}
+	task.Status.Message = &Message{Role: MessageRoleAgent, Parts: []Part{NewTextPart("working")}}
+	task.Artifacts = []Artifact{{Parts: []Part{DataPart{Type: PartTypeData, Data: map[string]interface{}{"n": 1}}}}}
+
+	clone := task.Clone()
+	require.Equal(t, task, clone)
+	clone.Metadata["labels"].([]interface{})[0] = "b"
+	clone.Status.Message.Parts[0] = NewTextPart("done")
+	clone.Artifacts[0].Parts[0].(DataPart).Data.(map[string]interface{})["n"] = 2
+	assert.Equal(t, []interface{}{"a"}, task.Metadata["labels"])
+	assert.Equal(t, NewTextPart("working"), task.Status.Message.Parts[0])
+	assert.Equal(t, 1, task.Artifacts[0].Parts[0].(DataPart).Data.(map[string]interface{})["n"])
+	assert.Nil(t, (*Task)(nil).Clone())
+}
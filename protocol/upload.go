@@ -0,0 +1,32 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package protocol
+
+// Resumable uploads carry inputs too large for a JSON-RPC request. It is an
+// extension of the A2A specification, modeled on the tus protocol:
+//
+//	POST  {uploads}       creates an upload of Upload-Length bytes, named by
+//	                      Upload-Name, whose URI is in the Location header
+//	HEAD  {uploads}/{id}  reports the Upload-Offset received so far
+//	PATCH {uploads}/{id}  appends the body at Upload-Offset, which must be the
+//	                      offset received so far, and reports the new one
+//	GET   {uploads}/{id}  returns the content of a complete upload
+//
+// The URI of a complete upload is then sent in the FileContent of a FilePart.
+const (
+	// DefaultUploadPath is the default path of the uploads endpoint.
+	DefaultUploadPath = "/uploads"
+	// UploadLengthHeader is the header of the total size of an upload, in bytes.
+	UploadLengthHeader = "Upload-Length"
+	// UploadOffsetHeader is the header of the number of bytes of an upload
+	// received by the agent.
+	UploadOffsetHeader = "Upload-Offset"
+	// UploadNameHeader is the header of the optional file name of an upload.
+	UploadNameHeader = "Upload-Name"
+	// ContentTypeUploadChunk is the media type of the chunks of PATCH requests.
+	ContentTypeUploadChunk = "application/offset+octet-stream"
+)
@@ -0,0 +1,52 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package protocol
+
+import "encoding/json"
+
+// MetadataKeyUsage is the task metadata key under which token usage is recorded.
+const MetadataKeyUsage = "usage"
+
+// TokenUsage is the number of tokens counted in the text parts exchanged for a task.
+type TokenUsage struct {
+	// InputTokens counts the tokens of the messages sent to the agent.
+	InputTokens int `json:"inputTokens"`
+	// OutputTokens counts the tokens of the status messages and artifacts produced by the agent.
+	OutputTokens int `json:"outputTokens"`
+	// TotalTokens is the sum of InputTokens and OutputTokens.
+	TotalTokens int `json:"totalTokens"`
+}
+
+// Add returns the sum of u and other.
+func (u TokenUsage) Add(other TokenUsage) TokenUsage {
+	return TokenUsage{
+		InputTokens:  u.InputTokens + other.InputTokens,
+		OutputTokens: u.OutputTokens + other.OutputTokens,
+		TotalTokens:  u.TotalTokens + other.TotalTokens,
+	}
+}
+
+// UsageFromMetadata returns the token usage recorded in metadata and whether
+// there was any. It accepts both TokenUsage values and their decoded JSON form.
+func UsageFromMetadata(metadata map[string]interface{}) (TokenUsage, bool) {
+	raw, ok := metadata[MetadataKeyUsage]
+	if !ok || raw == nil {
+		return TokenUsage{}, false
+	}
+	if usage, ok := raw.(TokenUsage); ok {
+		return usage, true
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return TokenUsage{}, false
+	}
+	var usage TokenUsage
+	if err := json.Unmarshal(data, &usage); err != nil {
+		return TokenUsage{}, false
+	}
+	return usage, true
+}
@@ -0,0 +1,146 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package protocol
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// MetadataKeyWarnings is the metadata key under which warnings are recorded
+// on requests and tasks.
+const MetadataKeyWarnings = "warnings"
+
+// Warning codes.
+const (
+	// WarningCodePartDropped reports a message part that was invalid and
+	// removed from the message instead of failing the request.
+	WarningCodePartDropped = "part_dropped"
+	// WarningCodeDeprecated reports the use of a deprecated method, parameter
+	// or skill that still works but may be removed.
+	WarningCodeDeprecated = "deprecated"
+	// WarningCodeFallback reports that the request was served by a fallback,
+	// e.g. a smaller model or a cached result, instead of the usual path.
+	WarningCodeFallback = "fallback"
+)
+
+// Warning describes a non-fatal issue found while handling a request.
+type Warning struct {
+	// Code identifies the kind of warning, e.g. WarningCodePartDropped.
+	Code string `json:"code"`
+	// Message describes the issue.
+	Message string `json:"message"`
+	// Pointer is the optional RFC 6901 JSON pointer to the offending field,
+	// relative to the request params as originally sent.
+	Pointer string `json:"pointer,omitempty"`
+}
+
+// PartFailurePolicy determines how a message with some invalid parts is handled.
+type PartFailurePolicy int
+
+const (
+	// PartFailureRejectAll rejects the whole message if any part is invalid.
+	PartFailureRejectAll PartFailurePolicy = iota
+	// PartFailureAcceptValid drops the invalid parts, records a WarningCodePartDropped
+	// warning for each in the metadata, and accepts the message if at least one
+	// part remains.
+	PartFailureAcceptValid
+)
+
+// WarningsFromMetadata returns the warnings recorded in metadata. It accepts
+// both []Warning values and their decoded JSON form.
+func WarningsFromMetadata(metadata map[string]interface{}) []Warning {
+	raw, ok := metadata[MetadataKeyWarnings]
+	if !ok || raw == nil {
+		return nil
+	}
+	if warnings, ok := raw.([]Warning); ok {
+		return warnings
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil
+	}
+	var warnings []Warning
+	if err := json.Unmarshal(data, &warnings); err != nil {
+		return nil
+	}
+	return warnings
+}
+
+// AppendWarnings adds warnings to those already recorded in metadata and returns
+// the metadata, allocating it if nil.
+func AppendWarnings(metadata map[string]interface{}, warnings ...Warning) map[string]interface{} {
+	if metadata == nil {
+		metadata = make(map[string]interface{})
+	}
+	metadata[MetadataKeyWarnings] = append(WarningsFromMetadata(metadata), warnings...)
+	return metadata
+}
+
+// TaskWarnings returns the warnings recorded in the metadata of task.
+func TaskWarnings(task *Task) []Warning {
+	if task == nil {
+		return nil
+	}
+	return WarningsFromMetadata(task.Metadata)
+}
+
+// EventWarnings returns the warnings recorded in the metadata of event.
+func EventWarnings(event TaskEvent) []Warning {
+	switch e := event.(type) {
+	case TaskStatusUpdateEvent:
+		return WarningsFromMetadata(e.Metadata)
+	case *TaskStatusUpdateEvent:
+		return WarningsFromMetadata(e.Metadata)
+	case TaskArtifactUpdateEvent:
+		return WarningsFromMetadata(e.Metadata)
+	case *TaskArtifactUpdateEvent:
+		return WarningsFromMetadata(e.Metadata)
+	}
+	return nil
+}
+
+// ValidatePart checks that a part is well formed: its type matches its kind and
+// a file part carries either bytes or a URI.
+func ValidatePart(part Part) error {
+	switch p := part.(type) {
+	case TextPart:
+		return checkPartType(p.Type, PartTypeText)
+	case *TextPart:
+		return checkPartType(p.Type, PartTypeText)
+	case FilePart:
+		return validateFilePart(p)
+	case *FilePart:
+		return validateFilePart(*p)
+	case DataPart:
+		return checkPartType(p.Type, PartTypeData)
+	case *DataPart:
+		return checkPartType(p.Type, PartTypeData)
+	default:
+		return fmt.Errorf("unsupported part type %T", part)
+	}
+}
+
+// checkPartType reports a mismatch between a part's declared and actual type.
+func checkPartType(got, want PartType) error {
+	if got != want {
+		return fmt.Errorf("type must be %q, got %q", want, got)
+	}
+	return nil
+}
+
+// validateFilePart checks a file part.
+func validateFilePart(p FilePart) error {
+	if err := checkPartType(p.Type, PartTypeFile); err != nil {
+		return err
+	}
+	if p.File.Bytes == nil && p.File.URI == nil {
+		return fmt.Errorf("file must contain either bytes or uri")
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package protocol
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWarningsFromMetadata(t *testing.T) {
+	warning := Warning{Code: WarningCodePartDropped, Message: "bad part", Pointer: "/message/parts/1"}
+
+	assert.Nil(t, WarningsFromMetadata(nil))
+
+	md := AppendWarnings(nil, warning)
+	assert.Equal(t, []Warning{warning}, WarningsFromMetadata(md))
+
+	// Warnings survive a JSON round trip, e.g. in a task returned by a server.
+	data, err := json.Marshal(md)
+	require.NoError(t, err)
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, []Warning{warning}, WarningsFromMetadata(decoded))
+
+	other := Warning{Code: "other", Message: "second"}
+	assert.Equal(t, []Warning{warning, other}, WarningsFromMetadata(AppendWarnings(decoded, other)))
+}
+
+func TestValidatePart(t *testing.T) {
+	uri := "https://example.com/a.txt"
+	tests := []struct {
+		name    string
+		part    Part
+		wantErr bool
+	}{
+		{name: "text", part: NewTextPart("hi")},
+		{name: "text pointer", part: &TextPart{Type: PartTypeText, Text: "hi"}},
+		{name: "text with wrong type", part: TextPart{Type: PartTypeData, Text: "hi"}, wantErr: true},
+		{name: "file with uri", part: FilePart{Type: PartTypeFile, File: FileContent{URI: &uri}}},
+		{name: "file without content", part: &FilePart{Type: PartTypeFile}, wantErr: true},
+		{name: "data", part: DataPart{Type: PartTypeData, Data: map[string]interface{}{"n": 1}}},
+		{name: "data without type", part: DataPart{}, wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidatePart(tc.part)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
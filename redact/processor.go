@@ -0,0 +1,121 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package redact
+
+import (
+	"context"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// Processor returns a processor running p with handles redacting the status
+// messages and artifacts it reports before the task manager records them.
+// Unlike Transformer, which only redacts the events sent, the data is
+// redacted in the task store, and thus in the tasks returned by tasks/get,
+// tasks/send and tasks/cancel, whatever the task manager. The processor
+// implements taskmanager.TaskResumer if p does.
+func (r *Redactor) Processor(p taskmanager.TaskProcessor) taskmanager.TaskProcessor {
+	if resumer, ok := p.(taskmanager.TaskResumer); ok {
+		return &resumingProcessor{processor: processor{p: p, r: r}, resumer: resumer}
+	}
+	return &processor{p: p, r: r}
+}
+
+// RedactMessage returns a copy of msg with its parts redacted, or nil if msg
+// is nil.
+func (r *Redactor) RedactMessage(msg *protocol.Message) *protocol.Message {
+	if msg == nil {
+		return nil
+	}
+	redacted := *msg
+	redacted.Parts = r.redactParts(msg.Parts)
+	return &redacted
+}
+
+// redactParts returns a copy of parts redacted.
+func (r *Redactor) redactParts(parts []protocol.Part) []protocol.Part {
+	if parts == nil {
+		return nil
+	}
+	redacted := make([]protocol.Part, len(parts))
+	for i, part := range parts {
+		redacted[i] = r.RedactPart(part)
+	}
+	return redacted
+}
+
+// processor is a taskmanager.TaskProcessor redacting what its processor
+// reports.
+type processor struct {
+	p taskmanager.TaskProcessor
+	r *Redactor
+}
+
+// Process implements taskmanager.TaskProcessor.
+func (p *processor) Process(
+	ctx context.Context,
+	taskID string,
+	msg protocol.Message,
+	handle taskmanager.TaskHandle,
+) error {
+	return p.p.Process(ctx, taskID, msg, &taskHandle{handle: handle, r: p.r})
+}
+
+// resumingProcessor is a processor whose processor resumes tasks.
+type resumingProcessor struct {
+	processor
+	resumer taskmanager.TaskResumer
+}
+
+// ResumeTask implements taskmanager.TaskResumer.
+func (p *resumingProcessor) ResumeTask(ctx context.Context, task *protocol.Task, handle taskmanager.TaskHandle) error {
+	return p.resumer.ResumeTask(ctx, task, &taskHandle{handle: handle, r: p.r})
+}
+
+// taskHandle is a taskmanager.TaskHandle redacting the status messages and
+// artifacts reported to its handle. It keeps the checkpoints and status
+// metadata of its handle, if supported.
+type taskHandle struct {
+	handle taskmanager.TaskHandle
+	r      *Redactor
+}
+
+// UpdateStatus implements taskmanager.TaskHandle.
+func (h *taskHandle) UpdateStatus(state protocol.TaskState, msg *protocol.Message) error {
+	return h.handle.UpdateStatus(state, h.r.RedactMessage(msg))
+}
+
+// AddArtifact implements taskmanager.TaskHandle.
+func (h *taskHandle) AddArtifact(artifact protocol.Artifact) error {
+	artifact.Parts = h.r.redactParts(artifact.Parts)
+	return h.handle.AddArtifact(artifact)
+}
+
+// IsStreamingRequest implements taskmanager.TaskHandle.
+func (h *taskHandle) IsStreamingRequest() bool {
+	return h.handle.IsStreamingRequest()
+}
+
+// UpdateStatusWithMetadata implements taskmanager.StatusMetadataUpdater.
+func (h *taskHandle) UpdateStatusWithMetadata(
+	state protocol.TaskState,
+	msg *protocol.Message,
+	metadata map[string]interface{},
+) error {
+	return taskmanager.UpdateStatusWithMetadata(h.handle, state, h.r.RedactMessage(msg), metadata)
+}
+
+// SaveCheckpoint implements taskmanager.Checkpointer.
+func (h *taskHandle) SaveCheckpoint(checkpoint protocol.TaskCheckpoint) error {
+	return taskmanager.SaveCheckpoint(h.handle, checkpoint)
+}
+
+// LoadCheckpoint implements taskmanager.Checkpointer.
+func (h *taskHandle) LoadCheckpoint() (protocol.TaskCheckpoint, bool, error) {
+	return taskmanager.LoadCheckpoint(h.handle)
+}
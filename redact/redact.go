@@ -0,0 +1,256 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+// Package redact removes sensitive data, such as e-mail addresses and card
+// numbers, from the messages, artifacts and logs of an agent. A Redactor
+// replaces the data found by its detectors with the kind of the data, e.g.
+// "[REDACTED:email]":
+//
+//	r := redact.New(redact.Email, redact.Regexp("ticket", regexp.MustCompile(`TKT-\d+`)))
+//	tm, _ := taskmanager.NewMemoryTaskManager(r.Processor(processor))
+//	log.Default = r.Logger(log.Default)
+package redact
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// Match is sensitive data found in a text.
+type Match struct {
+	// Start and End are the byte offsets of the data in the text.
+	Start, End int
+	// Kind is the kind of the data, e.g. "email".
+	Kind string
+}
+
+// Detector finds sensitive data in texts.
+type Detector interface {
+	// Detect returns the sensitive data in text, in any order.
+	Detect(text string) []Match
+}
+
+// DetectorFunc is an adapter to allow the use of ordinary functions as Detector.
+type DetectorFunc func(text string) []Match
+
+// Detect implements Detector.
+func (f DetectorFunc) Detect(text string) []Match {
+	return f(text)
+}
+
+// Regexp returns a detector reporting the matches of re as data of kind.
+func Regexp(kind string, re *regexp.Regexp) Detector {
+	return DetectorFunc(func(text string) []Match {
+		var matches []Match
+		for _, loc := range re.FindAllStringIndex(text, -1) {
+			matches = append(matches, Match{Start: loc[0], End: loc[1], Kind: kind})
+		}
+		return matches
+	})
+}
+
+// The built-in detectors.
+var (
+	// Email detects e-mail addresses.
+	Email = Regexp("email", regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`))
+	// Phone detects international phone numbers, starting with a plus sign.
+	Phone = Regexp("phone", regexp.MustCompile(`\+\d[\d ()-]{6,}\d`))
+	// CardNumber detects payment card numbers of 13 to 19 digits, possibly
+	// grouped with spaces or dashes, that pass the Luhn check.
+	CardNumber Detector = DetectorFunc(detectCardNumbers)
+)
+
+// cardNumberPattern matches the candidate card numbers.
+var cardNumberPattern = regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`)
+
+// detectCardNumbers implements CardNumber.
+func detectCardNumbers(text string) []Match {
+	var matches []Match
+	for _, loc := range cardNumberPattern.FindAllStringIndex(text, -1) {
+		if luhn(text[loc[0]:loc[1]]) {
+			matches = append(matches, Match{Start: loc[0], End: loc[1], Kind: "cardNumber"})
+		}
+	}
+	return matches
+}
+
+// luhn reports whether the digits of number pass the Luhn check.
+func luhn(number string) bool {
+	sum, double := 0, false
+	for i := len(number) - 1; i >= 0; i-- {
+		c := number[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if double {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// Builtin returns the built-in detector named name: "email", "phone" or
+// "cardNumber".
+func Builtin(name string) (Detector, bool) {
+	switch name {
+	case "email":
+		return Email, true
+	case "phone":
+		return Phone, true
+	case "cardNumber":
+		return CardNumber, true
+	}
+	return nil, false
+}
+
+// Redactor replaces the sensitive data found by its detectors.
+type Redactor struct {
+	detectors []Detector
+}
+
+// New creates a redactor using detectors.
+func New(detectors ...Detector) *Redactor {
+	return &Redactor{detectors: detectors}
+}
+
+// Redact returns text with its sensitive data replaced by "[REDACTED:kind]".
+// Overlapping data is replaced once, as the kind of the first.
+func (r *Redactor) Redact(text string) string {
+	var matches []Match
+	for _, detector := range r.detectors {
+		matches = append(matches, detector.Detect(text)...)
+	}
+	if len(matches) == 0 {
+		return text
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Start < matches[j].Start })
+	var b strings.Builder
+	end := 0
+	for _, match := range matches {
+		if match.End <= end {
+			continue
+		}
+		if match.Start >= end {
+			b.WriteString(text[end:match.Start])
+			b.WriteString("[REDACTED:" + match.Kind + "]")
+		}
+		end = match.End
+	}
+	b.WriteString(text[end:])
+	return b.String()
+}
+
+// RedactPart returns a copy of part with the sensitive data of its text, or of
+// the strings of its data, replaced. File parts are returned unchanged.
+func (r *Redactor) RedactPart(part protocol.Part) protocol.Part {
+	switch p := part.(type) {
+	case protocol.TextPart:
+		p.Text = r.Redact(p.Text)
+		return p
+	case *protocol.TextPart:
+		redacted := *p
+		redacted.Text = r.Redact(p.Text)
+		return &redacted
+	case protocol.DataPart:
+		p.Data = r.redactValue(p.Data)
+		return p
+	case *protocol.DataPart:
+		redacted := *p
+		redacted.Data = r.redactValue(p.Data)
+		return &redacted
+	}
+	return part
+}
+
+// redactValue returns a copy of a decoded JSON value with its strings redacted.
+func (r *Redactor) redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return r.Redact(v)
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			redacted[key] = r.redactValue(item)
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, item := range v {
+			redacted[i] = r.redactValue(item)
+		}
+		return redacted
+	}
+	return value
+}
+
+// Transformer returns an event transformer, for
+// taskmanager.WithEventTransformers, redacting the status messages and
+// artifacts sent to subscribers and push notification URLs.
+func (r *Redactor) Transformer() taskmanager.EventTransformer {
+	return taskmanager.TransformParts(r.RedactPart)
+}
+
+// Logger returns a logger redacting the messages it passes to l.
+func (r *Redactor) Logger(l log.Logger) log.Logger {
+	return &logger{l: l, r: r}
+}
+
+// logger is a log.Logger redacting its messages.
+type logger struct {
+	l log.Logger
+	r *Redactor
+}
+
+// Debug implements log.Logger.
+func (l *logger) Debug(args ...interface{}) { l.l.Debug(l.r.Redact(fmt.Sprint(args...))) }
+
+// Debugf implements log.Logger.
+func (l *logger) Debugf(format string, args ...interface{}) {
+	l.l.Debug(l.r.Redact(fmt.Sprintf(format, args...)))
+}
+
+// Info implements log.Logger.
+func (l *logger) Info(args ...interface{}) { l.l.Info(l.r.Redact(fmt.Sprint(args...))) }
+
+// Infof implements log.Logger.
+func (l *logger) Infof(format string, args ...interface{}) {
+	l.l.Info(l.r.Redact(fmt.Sprintf(format, args...)))
+}
+
+// Warn implements log.Logger.
+func (l *logger) Warn(args ...interface{}) { l.l.Warn(l.r.Redact(fmt.Sprint(args...))) }
+
+// Warnf implements log.Logger.
+func (l *logger) Warnf(format string, args ...interface{}) {
+	l.l.Warn(l.r.Redact(fmt.Sprintf(format, args...)))
+}
+
+// Error implements log.Logger.
+func (l *logger) Error(args ...interface{}) { l.l.Error(l.r.Redact(fmt.Sprint(args...))) }
+
+// Errorf implements log.Logger.
+func (l *logger) Errorf(format string, args ...interface{}) {
+	l.l.Error(l.r.Redact(fmt.Sprintf(format, args...)))
+}
+
+// Fatal implements log.Logger.
+func (l *logger) Fatal(args ...interface{}) { l.l.Fatal(l.r.Redact(fmt.Sprint(args...))) }
+
+// Fatalf implements log.Logger.
+func (l *logger) Fatalf(format string, args ...interface{}) {
+	l.l.Fatal(l.r.Redact(fmt.Sprintf(format, args...)))
+}
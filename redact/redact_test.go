@@ -0,0 +1,123 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package redact
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+func TestRedactor_Redact(t *testing.T) {
+	r := New(Email, Phone, CardNumber, Regexp("ticket", regexp.MustCompile(`TKT-\d+`)))
+	tests := []struct {
+		in, want string
+	}{
+		{"mail jane.doe@example.co.uk now", "mail [REDACTED:email] now"},
+		{"call +1 (555) 010-0199", "call [REDACTED:phone]"},
+		{"card 4111 1111 1111 1111.", "card [REDACTED:cardNumber]."},
+		{"order 4111 1111 1111 1112", "order 4111 1111 1111 1112"},
+		{"see TKT-42 and TKT-7", "see [REDACTED:ticket] and [REDACTED:ticket]"},
+		{"nothing here", "nothing here"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, r.Redact(tt.in), tt.in)
+	}
+
+	overlapping := New(Regexp("a", regexp.MustCompile(`abc`)), Regexp("b", regexp.MustCompile(`bcd`)))
+	assert.Equal(t, "x[REDACTED:a]x", overlapping.Redact("xabcdx"))
+}
+
+func TestRedactor_RedactPart(t *testing.T) {
+	r := New(Email)
+	text := r.RedactPart(protocol.NewTextPart("a@b.io")).(protocol.TextPart)
+	assert.Equal(t, "[REDACTED:email]", text.Text)
+
+	data := map[string]interface{}{"to": []interface{}{"a@b.io", 1.0}}
+	part := r.RedactPart(protocol.DataPart{Type: protocol.PartTypeData, Data: data}).(protocol.DataPart)
+	assert.Equal(t, map[string]interface{}{"to": []interface{}{"[REDACTED:email]", 1.0}}, part.Data)
+	assert.Equal(t, "a@b.io", data["to"].([]interface{})[0], "the original part is not modified")
+}
+
+// recordingLogger records the messages logged at info level.
+type recordingLogger struct {
+	messages []string
+}
+
+func (l *recordingLogger) Debug(args ...interface{})                 {}
+func (l *recordingLogger) Debugf(format string, args ...interface{}) {}
+func (l *recordingLogger) Info(args ...interface{}) {
+	l.messages = append(l.messages, fmt.Sprint(args...))
+}
+func (l *recordingLogger) Infof(format string, args ...interface{}) {
+	l.messages = append(l.messages, fmt.Sprintf(format, args...))
+}
+func (l *recordingLogger) Warn(args ...interface{})                  {}
+func (l *recordingLogger) Warnf(format string, args ...interface{})  {}
+func (l *recordingLogger) Error(args ...interface{})                 {}
+func (l *recordingLogger) Errorf(format string, args ...interface{}) {}
+func (l *recordingLogger) Fatal(args ...interface{})                 {}
+func (l *recordingLogger) Fatalf(format string, args ...interface{}) {}
+
+func TestRedactor_Logger(t *testing.T) {
+	recorder := &recordingLogger{}
+	l := New(Email).Logger(recorder)
+	l.Infof("message from %s: %d%%", "a@b.io", 100)
+	l.Info("to ", "c@d.io")
+	assert.Equal(t, []string{"message from [REDACTED:email]: 100%", "to [REDACTED:email]"}, recorder.messages)
+}
+
+// replyingProcessor reports an e-mail address when it processes or resumes a
+// task, and checkpoints the task.
+type replyingProcessor struct{}
+
+func (replyingProcessor) Process(
+	ctx context.Context,
+	taskID string,
+	msg protocol.Message,
+	handle taskmanager.TaskHandle,
+) error {
+	if err := taskmanager.SaveCheckpoint(handle, protocol.TaskCheckpoint{Step: 1}); err != nil {
+		return err
+	}
+	if err := handle.AddArtifact(protocol.Artifact{Parts: msg.Parts}); err != nil {
+		return err
+	}
+	return handle.UpdateStatus(protocol.TaskStateCompleted, &msg)
+}
+
+func (p replyingProcessor) ResumeTask(ctx context.Context, task *protocol.Task, handle taskmanager.TaskHandle) error {
+	return nil
+}
+
+func TestRedactor_Processor(t *testing.T) {
+	processor := New(Email).Processor(replyingProcessor{})
+	_, ok := processor.(taskmanager.TaskResumer)
+	assert.True(t, ok, "resumers are kept")
+	tm, err := taskmanager.NewMemoryTaskManager(processor)
+	require.NoError(t, err)
+
+	task, err := tm.OnSendTask(context.Background(), protocol.SendTaskParams{
+		ID:      "redacted",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("to a@b.io")}),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, protocol.TaskStateCompleted, task.Status.State)
+	assert.Equal(t, "to [REDACTED:email]", task.Status.Message.Parts[0].(protocol.TextPart).Text)
+	require.Len(t, task.Artifacts, 1)
+	assert.Equal(t, "to [REDACTED:email]", task.Artifacts[0].Parts[0].(protocol.TextPart).Text)
+
+	_, ok = New(Email).Processor(taskmanager.TaskProcessor(nil)).(taskmanager.TaskResumer)
+	assert.False(t, ok)
+}
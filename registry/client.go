@@ -0,0 +1,254 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package registry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/client"
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/server"
+)
+
+const (
+	// minHeartbeatInterval bounds how often KeepRegistered sends heartbeats.
+	minHeartbeatInterval = 10 * time.Millisecond
+	// deregisterTimeout bounds the deregistration when KeepRegistered stops.
+	deregisterTimeout = 5 * time.Second
+	// maxErrorBodySize bounds how much of an error response is reported.
+	maxErrorBodySize = 1 << 10
+)
+
+// ErrNoMatch is returned by Connect when no registered agent matches the query.
+var ErrNoMatch = errors.New("no registered agent matches the query")
+
+// Client talks to a registry served by NewHandler.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient creates a client for the registry at baseURL, the URL the registry
+// handler is mounted at.
+func NewClient(baseURL string, opts ...ClientOption) (*Client, error) {
+	if _, err := url.ParseRequestURI(baseURL); err != nil {
+		return nil, fmt.Errorf("invalid registry URL %q: %w", baseURL, err)
+	}
+	c := &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// Register registers the agent described by card, or updates its card.
+func (c *Client) Register(ctx context.Context, card server.AgentCard) (*Entry, error) {
+	var entry Entry
+	if err := c.do(ctx, http.MethodPost, AgentsPath, card, &entry); err != nil {
+		return nil, fmt.Errorf("registry.Register: %w", err)
+	}
+	return &entry, nil
+}
+
+// Heartbeat renews the registration of the agent at agentURL. It returns an
+// error wrapping ErrNotRegistered if the registration expired.
+func (c *Client) Heartbeat(ctx context.Context, agentURL string) (*Entry, error) {
+	var entry Entry
+	if err := c.do(ctx, http.MethodPost, HeartbeatPath, heartbeatRequest{URL: agentURL}, &entry); err != nil {
+		return nil, fmt.Errorf("registry.Heartbeat: %w", notRegistered(err, agentURL))
+	}
+	return &entry, nil
+}
+
+// Deregister removes the agent at agentURL from the registry.
+func (c *Client) Deregister(ctx context.Context, agentURL string) error {
+	path := AgentsPath + "?" + url.Values{paramURL: {agentURL}}.Encode()
+	if err := c.do(ctx, http.MethodDelete, path, nil, nil); err != nil {
+		return fmt.Errorf("registry.Deregister: %w", notRegistered(err, agentURL))
+	}
+	return nil
+}
+
+// Search returns the registered agents matching q. An empty query lists all agents.
+func (c *Client) Search(ctx context.Context, q Query) ([]Entry, error) {
+	path := AgentsPath
+	if values := q.values(); len(values) > 0 {
+		path += "?" + values.Encode()
+	}
+	var resp listResponse
+	if err := c.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return nil, fmt.Errorf("registry.Search: %w", err)
+	}
+	return resp.Agents, nil
+}
+
+// KeepRegistered registers card and renews the registration in the background,
+// at a third of the registry's heartbeat TTL, until ctx is done or stop is
+// called. A registration lost e.g. to a registry restart is recreated. The
+// agent is deregistered when it stops; stop waits for that.
+func (c *Client) KeepRegistered(ctx context.Context, card server.AgentCard) (stop func(), err error) {
+	entry, err := c.Register(ctx, card)
+	if err != nil {
+		return nil, err
+	}
+	interval := entry.ExpiresAt.Sub(entry.LastHeartbeat) / 3
+	if interval < minHeartbeatInterval {
+		interval = minHeartbeatInterval
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.heartbeat(ctx, card, interval)
+		deregisterCtx, cancel := context.WithTimeout(context.Background(), deregisterTimeout)
+		defer cancel()
+		if err := c.Deregister(deregisterCtx, card.URL); err != nil {
+			log.Warnf("Failed to deregister agent %s: %v", card.URL, err)
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			cancel()
+			<-done
+		})
+	}, nil
+}
+
+// heartbeat renews the registration of card every interval until ctx is done.
+func (c *Client) heartbeat(ctx context.Context, card server.AgentCard, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		_, err := c.Heartbeat(ctx, card.URL)
+		if errors.Is(err, ErrNotRegistered) {
+			_, err = c.Register(ctx, card)
+		}
+		if err != nil && ctx.Err() == nil {
+			log.Warnf("Failed to renew registration of agent %s: %v", card.URL, err)
+		}
+	}
+}
+
+// Resolver returns a resolver reporting the URLs of the agents matching q,
+// polled at interval (30s if not positive). Passed to client.WithResolver, it
+// keeps an A2A client balanced over the agents currently able to serve q.
+func (c *Client) Resolver(q Query, interval time.Duration) client.Resolver {
+	return &client.PollingResolver{
+		Interval: interval,
+		Lookup: func(ctx context.Context) ([]string, error) {
+			entries, err := c.Search(ctx, q)
+			if err != nil {
+				return nil, err
+			}
+			return entryURLs(entries), nil
+		},
+	}
+}
+
+// Connect finds the agents matching q and returns an A2A client balancing over
+// them, which follows the registry as agents come and go. It returns an error
+// wrapping ErrNoMatch if no agent matches. Close the client when done.
+func (c *Client) Connect(ctx context.Context, q Query, opts ...client.Option) (*client.A2AClient, error) {
+	entries, err := c.Search(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("registry.Connect: %w", ErrNoMatch)
+	}
+	urls := entryURLs(entries)
+	opts = append([]client.Option{
+		client.WithReplicas(urls[1:]...),
+		client.WithResolver(c.Resolver(q, 0)),
+	}, opts...)
+	return client.NewA2AClient(urls[0], opts...)
+}
+
+// do sends a registry request with the JSON encoded body, if not nil, and
+// decodes the response into out, if not nil.
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		return &statusError{status: resp.StatusCode, message: strings.TrimSpace(string(msg))}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// statusError is an unsuccessful registry response.
+type statusError struct {
+	status  int
+	message string
+}
+
+// Error implements error.
+func (e *statusError) Error() string {
+	return fmt.Sprintf("unexpected http status %d: %s", e.status, e.message)
+}
+
+// notRegistered maps a not found response for agentURL to ErrNotRegistered.
+func notRegistered(err error, agentURL string) error {
+	var se *statusError
+	if errors.As(err, &se) && se.status == http.StatusNotFound {
+		return fmt.Errorf("%w: %s", ErrNotRegistered, agentURL)
+	}
+	return err
+}
+
+// entryURLs returns the agent URLs of entries.
+func entryURLs(entries []Entry) []string {
+	urls := make([]string, len(entries))
+	for i, e := range entries {
+		urls[i] = e.Card.URL
+	}
+	return urls
+}
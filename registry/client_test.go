@@ -0,0 +1,99 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package registry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/server"
+)
+
+func newTestRegistry(t *testing.T, opts ...Option) (*Registry, *Client) {
+	t.Helper()
+	r := NewRegistry(opts...)
+	ts := httptest.NewServer(http.StripPrefix("/registry", NewHandler(r)))
+	t.Cleanup(ts.Close)
+	c, err := NewClient(ts.URL + "/registry/")
+	require.NoError(t, err)
+	return r, c
+}
+
+func TestClient(t *testing.T) {
+	_, c := newTestRegistry(t)
+	ctx := context.Background()
+
+	entry, err := c.Register(ctx, testCard("fx", "http://fx/", server.AgentSkill{ID: "convert", Name: "Convert"}))
+	require.NoError(t, err)
+	assert.Equal(t, "fx", entry.Card.Name)
+
+	_, err = c.Register(ctx, testCard("invalid", ""))
+	assert.Error(t, err)
+
+	entries, err := c.Search(ctx, Query{Skill: "convert"})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "http://fx/", entries[0].Card.URL)
+
+	_, err = c.Heartbeat(ctx, "http://fx/")
+	require.NoError(t, err)
+	require.NoError(t, c.Deregister(ctx, "http://fx/"))
+	_, err = c.Heartbeat(ctx, "http://fx/")
+	assert.ErrorIs(t, err, ErrNotRegistered)
+	assert.ErrorIs(t, c.Deregister(ctx, "http://fx/"), ErrNotRegistered)
+
+	entries, err = c.Search(ctx, Query{})
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	_, err = c.Connect(ctx, Query{Skill: "convert"})
+	assert.ErrorIs(t, err, ErrNoMatch)
+}
+
+func TestClient_KeepRegistered(t *testing.T) {
+	r, c := newTestRegistry(t, WithHeartbeatTTL(150*time.Millisecond))
+	card := testCard("fx", "http://fx/")
+
+	stop, err := c.KeepRegistered(context.Background(), card)
+	require.NoError(t, err)
+	time.Sleep(300 * time.Millisecond)
+	assert.Len(t, r.List(), 1, "heartbeats keep the registration alive")
+
+	// A lost registration is recreated.
+	require.NoError(t, r.Deregister(card.URL))
+	assert.Eventually(t, func() bool { return len(r.List()) == 1 }, time.Second, 10*time.Millisecond)
+
+	stop()
+	assert.Empty(t, r.List(), "stopping deregisters the agent")
+}
+
+func TestClient_Connect(t *testing.T) {
+	agent := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":"t1","result":{"id":"t1","status":{"state":"completed"}}}`))
+	}))
+	defer agent.Close()
+
+	_, c := newTestRegistry(t)
+	ctx := context.Background()
+	_, err := c.Register(ctx, testCard("fx", agent.URL, server.AgentSkill{ID: "convert", Name: "Convert"}))
+	require.NoError(t, err)
+
+	a2aClient, err := c.Connect(ctx, Query{Skill: "convert"})
+	require.NoError(t, err)
+	defer a2aClient.Close()
+	task, err := a2aClient.GetTasks(ctx, protocol.TaskQueryParams{ID: "t1"})
+	require.NoError(t, err)
+	assert.Equal(t, "t1", task.ID)
+}
@@ -0,0 +1,169 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package registry
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/server"
+)
+
+const (
+	// AgentsPath lists and searches agents (GET), registers them (POST) and
+	// deregisters them (DELETE with a url query parameter).
+	AgentsPath = "/agents"
+	// HeartbeatPath renews the registration of an agent (POST).
+	HeartbeatPath = "/agents/heartbeat"
+)
+
+// maxRequestSize bounds the size of registry requests, including agent cards.
+const maxRequestSize = 1 << 20
+
+// Search query parameters.
+const (
+	paramSkill      = "skill"
+	paramTag        = "tag"
+	paramInputMode  = "inputMode"
+	paramOutputMode = "outputMode"
+	paramText       = "q"
+	paramURL        = "url"
+)
+
+// heartbeatRequest is the body of heartbeat requests.
+type heartbeatRequest struct {
+	URL string `json:"url"`
+}
+
+// listResponse is the body of list and search responses.
+type listResponse struct {
+	Agents []Entry `json:"agents"`
+}
+
+// handler serves a Registry over HTTP.
+type handler struct {
+	registry *Registry
+}
+
+// NewHandler returns an HTTP handler serving registry over AgentsPath and
+// HeartbeatPath. Mount it under a prefix with http.StripPrefix, and wrap it
+// with an auth middleware to restrict who can register agents.
+func NewHandler(registry *Registry) http.Handler {
+	h := &handler{registry: registry}
+	mux := http.NewServeMux()
+	mux.HandleFunc(AgentsPath, h.handleAgents)
+	mux.HandleFunc(HeartbeatPath, h.handleHeartbeat)
+	return mux
+}
+
+// handleAgents serves AgentsPath.
+func (h *handler) handleAgents(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, listResponse{Agents: h.registry.Search(queryFromValues(r.URL.Query()))})
+	case http.MethodPost:
+		var card server.AgentCard
+		if !decodeBody(w, r, &card) {
+			return
+		}
+		entry, err := h.registry.Register(card)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		log.Infof("Registered agent %s at %s", card.Name, card.URL)
+		writeJSON(w, http.StatusOK, entry)
+	case http.MethodDelete:
+		agentURL := r.URL.Query().Get(paramURL)
+		if err := h.registry.Deregister(agentURL); err != nil {
+			writeError(w, err)
+			return
+		}
+		log.Infof("Deregistered agent at %s", agentURL)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
+
+// handleHeartbeat serves HeartbeatPath.
+func (h *handler) handleHeartbeat(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	var req heartbeatRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	entry, err := h.registry.Heartbeat(req.URL)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, entry)
+}
+
+// decodeBody decodes the JSON request body into v, replying with an error and
+// returning false if it is invalid.
+func decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxRequestSize)).Decode(v); err != nil {
+		http.Error(w, "invalid request body: "+err.Error(), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// writeError replies with the status matching err.
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusBadRequest
+	if errors.Is(err, ErrNotRegistered) {
+		status = http.StatusNotFound
+	}
+	http.Error(w, err.Error(), status)
+}
+
+// writeJSON replies with v encoded as JSON.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Errorf("Failed to encode registry response: %v", err)
+	}
+}
+
+// values encodes q as search query parameters.
+func (q Query) values() url.Values {
+	values := url.Values{}
+	for key, value := range map[string]string{
+		paramSkill:      q.Skill,
+		paramTag:        q.Tag,
+		paramInputMode:  q.InputMode,
+		paramOutputMode: q.OutputMode,
+		paramText:       q.Text,
+	} {
+		if value != "" {
+			values.Set(key, value)
+		}
+	}
+	return values
+}
+
+// queryFromValues decodes search query parameters.
+func queryFromValues(values url.Values) Query {
+	return Query{
+		Skill:      values.Get(paramSkill),
+		Tag:        values.Get(paramTag),
+		InputMode:  values.Get(paramInputMode),
+		OutputMode: values.Get(paramOutputMode),
+		Text:       values.Get(paramText),
+	}
+}
@@ -0,0 +1,38 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package registry
+
+import (
+	"net/http"
+	"time"
+)
+
+// Option is a function that configures the Registry.
+type Option func(*Registry)
+
+// WithHeartbeatTTL sets how long a registration lasts without heartbeats.
+// Default 30s.
+func WithHeartbeatTTL(ttl time.Duration) Option {
+	return func(r *Registry) {
+		if ttl > 0 {
+			r.heartbeatTTL = ttl
+		}
+	}
+}
+
+// ClientOption is a function that configures the registry Client.
+type ClientOption func(*Client)
+
+// WithHTTPClient sets the HTTP client used to reach the registry.
+// Defaults to http.DefaultClient.
+func WithHTTPClient(client *http.Client) ClientOption {
+	return func(c *Client) {
+		if client != nil {
+			c.httpClient = client
+		}
+	}
+}
@@ -0,0 +1,260 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+// Package registry provides an agent registry: a small service where agents
+// register their cards and keep them alive with heartbeats, and a client that
+// finds the agents able to do a task, e.g. by skill, and connects to them.
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/server"
+)
+
+// defaultHeartbeatTTL is how long a registration lasts without heartbeats by default.
+const defaultHeartbeatTTL = 30 * time.Second
+
+// ErrNotRegistered is returned for agents that are not registered, or whose
+// registration expired.
+var ErrNotRegistered = errors.New("agent not registered")
+
+// Entry is a registered agent.
+type Entry struct {
+	// Card is the agent card the agent registered.
+	Card server.AgentCard `json:"card"`
+	// RegisteredAt is when the agent first registered.
+	RegisteredAt time.Time `json:"registeredAt"`
+	// LastHeartbeat is when the registration was last renewed.
+	LastHeartbeat time.Time `json:"lastHeartbeat"`
+	// ExpiresAt is when the registration expires without further heartbeats.
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// Query selects registered agents. Empty fields match every agent; the
+// skill-level fields Skill, Tag, InputMode and OutputMode must all be matched
+// by a single skill of the agent. Matching is case-insensitive.
+type Query struct {
+	// Skill matches the ID or the name of a skill.
+	Skill string
+	// Tag matches a tag of a skill.
+	Tag string
+	// InputMode and OutputMode match the modes of a skill, which default to
+	// those of the agent.
+	InputMode  string
+	OutputMode string
+	// Text matches a substring of the agent name or description, or of the
+	// name, description or examples of one of its skills.
+	Text string
+}
+
+// Registry stores the cards of registered agents. Registrations expire unless
+// renewed by a heartbeat within the heartbeat TTL. It is safe for concurrent use.
+type Registry struct {
+	heartbeatTTL time.Duration
+	now          func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*Entry
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry(opts ...Option) *Registry {
+	r := &Registry{
+		heartbeatTTL: defaultHeartbeatTTL,
+		now:          time.Now,
+		entries:      make(map[string]*Entry),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// HeartbeatTTL returns how long a registration lasts without heartbeats.
+func (r *Registry) HeartbeatTTL() time.Duration {
+	return r.heartbeatTTL
+}
+
+// Register adds the agent described by card, or replaces the card of an agent
+// registered with the same URL, and renews its registration.
+func (r *Registry) Register(card server.AgentCard) (Entry, error) {
+	key, err := agentKey(card.URL)
+	if err != nil {
+		return Entry{}, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := r.expireLocked()
+	e, ok := r.entries[key]
+	if !ok {
+		e = &Entry{RegisteredAt: now}
+		r.entries[key] = e
+	}
+	e.Card = card
+	r.renewLocked(e, now)
+	return *e, nil
+}
+
+// Heartbeat renews the registration of the agent at agentURL.
+func (r *Registry) Heartbeat(agentURL string) (Entry, error) {
+	key, err := agentKey(agentURL)
+	if err != nil {
+		return Entry{}, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := r.expireLocked()
+	e, ok := r.entries[key]
+	if !ok {
+		return Entry{}, fmt.Errorf("%w: %s", ErrNotRegistered, agentURL)
+	}
+	r.renewLocked(e, now)
+	return *e, nil
+}
+
+// Deregister removes the agent at agentURL.
+func (r *Registry) Deregister(agentURL string) error {
+	key, err := agentKey(agentURL)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.expireLocked()
+	if _, ok := r.entries[key]; !ok {
+		return fmt.Errorf("%w: %s", ErrNotRegistered, agentURL)
+	}
+	delete(r.entries, key)
+	return nil
+}
+
+// List returns all registered agents, ordered by name and URL.
+func (r *Registry) List() []Entry {
+	return r.Search(Query{})
+}
+
+// Search returns the registered agents matching q, ordered by name and URL.
+func (r *Registry) Search(q Query) []Entry {
+	r.mu.Lock()
+	r.expireLocked()
+	matches := make([]Entry, 0, len(r.entries))
+	for _, e := range r.entries {
+		if q.Matches(&e.Card) {
+			matches = append(matches, *e)
+		}
+	}
+	r.mu.Unlock()
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Card.Name != matches[j].Card.Name {
+			return matches[i].Card.Name < matches[j].Card.Name
+		}
+		return matches[i].Card.URL < matches[j].Card.URL
+	})
+	return matches
+}
+
+// renewLocked extends the registration of e from now.
+func (r *Registry) renewLocked(e *Entry, now time.Time) {
+	e.LastHeartbeat = now
+	e.ExpiresAt = now.Add(r.heartbeatTTL)
+}
+
+// expireLocked drops the expired registrations and returns the current time.
+func (r *Registry) expireLocked() time.Time {
+	now := r.now()
+	for key, e := range r.entries {
+		if !now.Before(e.ExpiresAt) {
+			delete(r.entries, key)
+		}
+	}
+	return now
+}
+
+// agentKey returns the key of the agent at agentURL, ignoring a trailing slash.
+func agentKey(agentURL string) (string, error) {
+	if agentURL == "" {
+		return "", errors.New("agent URL is required")
+	}
+	if _, err := url.ParseRequestURI(agentURL); err != nil {
+		return "", fmt.Errorf("invalid agent URL %q: %w", agentURL, err)
+	}
+	return strings.TrimSuffix(agentURL, "/"), nil
+}
+
+// Matches reports whether card matches q.
+func (q Query) Matches(card *server.AgentCard) bool {
+	if q.Text != "" && !cardContains(card, strings.ToLower(q.Text)) {
+		return false
+	}
+	if q.Skill == "" && q.Tag == "" && q.InputMode == "" && q.OutputMode == "" {
+		return true
+	}
+	for i := range card.Skills {
+		if q.matchesSkill(card, &card.Skills[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesSkill reports whether skill of card matches the skill-level fields of q.
+func (q Query) matchesSkill(card *server.AgentCard, skill *server.AgentSkill) bool {
+	if q.Skill != "" && !strings.EqualFold(skill.ID, q.Skill) && !strings.EqualFold(skill.Name, q.Skill) {
+		return false
+	}
+	if q.Tag != "" && !containsFold(skill.Tags, q.Tag) {
+		return false
+	}
+	inputModes, outputModes := skill.InputModes, skill.OutputModes
+	if len(inputModes) == 0 {
+		inputModes = card.DefaultInputModes
+	}
+	if len(outputModes) == 0 {
+		outputModes = card.DefaultOutputModes
+	}
+	if q.InputMode != "" && !containsFold(inputModes, q.InputMode) {
+		return false
+	}
+	return q.OutputMode == "" || containsFold(outputModes, q.OutputMode)
+}
+
+// cardContains reports whether the texts describing card contain text, in lower case.
+func cardContains(card *server.AgentCard, text string) bool {
+	texts := []string{card.Name}
+	if card.Description != nil {
+		texts = append(texts, *card.Description)
+	}
+	for _, skill := range card.Skills {
+		texts = append(texts, skill.Name)
+		if skill.Description != nil {
+			texts = append(texts, *skill.Description)
+		}
+		texts = append(texts, skill.Examples...)
+	}
+	for _, t := range texts {
+		if strings.Contains(strings.ToLower(t), text) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsFold reports whether values contains value, ignoring case.
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package registry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/server"
+)
+
+func testCard(name, url string, skills ...server.AgentSkill) server.AgentCard {
+	return server.AgentCard{
+		Name:               name,
+		URL:                url,
+		Version:            "1.0.0",
+		DefaultInputModes:  []string{"text"},
+		DefaultOutputModes: []string{"text"},
+		Skills:             skills,
+	}
+}
+
+func TestRegistry_Expiry(t *testing.T) {
+	now := time.Unix(1000, 0)
+	r := NewRegistry(WithHeartbeatTTL(10 * time.Second))
+	r.now = func() time.Time { return now }
+
+	entry, err := r.Register(testCard("a", "http://a/"))
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(10*time.Second), entry.ExpiresAt)
+
+	now = now.Add(8 * time.Second)
+	entry, err = r.Heartbeat("http://a") // Trailing slashes are ignored.
+	require.NoError(t, err)
+	assert.Equal(t, time.Unix(1000, 0), entry.RegisteredAt)
+	assert.Equal(t, now, entry.LastHeartbeat)
+
+	now = now.Add(10 * time.Second)
+	assert.Empty(t, r.List())
+	_, err = r.Heartbeat("http://a/")
+	assert.ErrorIs(t, err, ErrNotRegistered)
+
+	_, err = r.Register(testCard("b", ""))
+	assert.Error(t, err)
+	assert.ErrorIs(t, r.Deregister("http://b/"), ErrNotRegistered)
+}
+
+func TestRegistry_Search(t *testing.T) {
+	desc := "Converts currencies"
+	r := NewRegistry()
+	_, err := r.Register(testCard("fx", "http://fx/", server.AgentSkill{
+		ID: "convert", Name: "Convert", Description: &desc, Tags: []string{"finance"},
+	}))
+	require.NoError(t, err)
+	_, err = r.Register(testCard("images", "http://img/", server.AgentSkill{
+		ID: "draw", Name: "Draw", Tags: []string{"art"}, OutputModes: []string{"image/png"},
+	}))
+	require.NoError(t, err)
+	_, err = r.Register(testCard("chat", "http://chat/"))
+	require.NoError(t, err)
+
+	names := func(entries []Entry) []string {
+		var out []string
+		for _, e := range entries {
+			out = append(out, e.Card.Name)
+		}
+		return out
+	}
+	assert.Equal(t, []string{"chat", "fx", "images"}, names(r.List()))
+	assert.Equal(t, []string{"fx"}, names(r.Search(Query{Skill: "CONVERT"})))
+	assert.Equal(t, []string{"images"}, names(r.Search(Query{Tag: "art"})))
+	assert.Equal(t, []string{"images"}, names(r.Search(Query{OutputMode: "image/png"})))
+	assert.Equal(t, []string{"fx"}, names(r.Search(Query{OutputMode: "text"})))
+	assert.Empty(t, r.Search(Query{Skill: "draw", Tag: "finance"}))
+	assert.Equal(t, []string{"fx"}, names(r.Search(Query{Text: "currenc"})))
+
+	require.NoError(t, r.Deregister("http://fx"))
+	assert.Empty(t, r.Search(Query{Skill: "convert"}))
+}
@@ -0,0 +1,217 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+const (
+	// maxPendingAckTimes bounds the send times kept per stream for events not
+	// yet acknowledged. Older ones are dropped, leaving their latency unknown.
+	maxPendingAckTimes = 1024
+	// maxEndedAckStreams bounds the ended streams kept for late acknowledgements.
+	maxEndedAckStreams = 1024
+)
+
+// EventAckObserver is notified of every accepted event acknowledgement, e.g. to
+// export the consumption lag of the clients as metrics.
+type EventAckObserver func(ack protocol.AckEventsResult)
+
+// ackStream tracks the events sent on an SSE stream and their acknowledgement.
+type ackStream struct {
+	id     string
+	taskID string
+
+	mu     sync.Mutex
+	sent   int64
+	acked  int64
+	ended  bool
+	first  int64       // Sequence number of sentAt[0].
+	sentAt []time.Time // Send times of the events from first on.
+}
+
+// next numbers the next event of the stream and returns its SSE event ID.
+// It returns "" for a nil stream, i.e. if acknowledgements are disabled.
+func (st *ackStream) next(now time.Time) string {
+	if st == nil {
+		return ""
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.sent++
+	if len(st.sentAt) == 0 {
+		st.first = st.sent
+	}
+	st.sentAt = append(st.sentAt, now)
+	if len(st.sentAt) > maxPendingAckTimes {
+		st.sentAt = st.sentAt[1:]
+		st.first++
+	}
+	return strconv.FormatInt(st.sent, 10)
+}
+
+// ack acknowledges the events up to sequence at now.
+func (st *ackStream) ack(sequence int64, now time.Time) (protocol.AckEventsResult, bool, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if sequence > st.sent {
+		return protocol.AckEventsResult{}, false,
+			fmt.Errorf("sequence %d is beyond the %d events sent", sequence, st.sent)
+	}
+	result := protocol.AckEventsResult{ID: st.taskID, StreamID: st.id}
+	if sequence > st.acked {
+		if i := sequence - st.first; i >= 0 && i < int64(len(st.sentAt)) {
+			result.LatencyMs = now.Sub(st.sentAt[i]).Milliseconds()
+			st.sentAt = st.sentAt[i+1:]
+			st.first = sequence + 1
+		}
+		st.acked = sequence
+	}
+	result.Acked, result.Sent, result.Lag = st.acked, st.sent, st.sent-st.acked
+	return result, st.ended && st.acked == st.sent, nil
+}
+
+// ackTracker tracks the SSE streams whose events can be acknowledged.
+type ackTracker struct {
+	observer EventAckObserver
+
+	mu      sync.Mutex
+	streams map[string]*ackStream
+	ended   []string // Ring buffer of ended stream IDs, oldest first from position oldest.
+	oldest  int
+}
+
+// newAckTracker creates a tracker notifying observer, which may be nil.
+func newAckTracker(observer EventAckObserver) *ackTracker {
+	return &ackTracker{observer: observer, streams: make(map[string]*ackStream)}
+}
+
+// open starts tracking a stream of the events of taskID.
+func (t *ackTracker) open(taskID string) (*ackStream, error) {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return nil, err
+	}
+	st := &ackStream{id: hex.EncodeToString(b[:]), taskID: taskID}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.streams[st.id] = st
+	return st, nil
+}
+
+// end marks st as ended. It is kept for late acknowledgements until all its
+// events are acknowledged or maxEndedAckStreams later streams have ended.
+func (t *ackTracker) end(st *ackStream) {
+	st.mu.Lock()
+	st.ended = true
+	done := st.acked == st.sent
+	st.mu.Unlock()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if done {
+		delete(t.streams, st.id)
+		return
+	}
+	if len(t.ended) < maxEndedAckStreams {
+		t.ended = append(t.ended, st.id)
+		return
+	}
+	delete(t.streams, t.ended[t.oldest])
+	t.ended[t.oldest] = st.id
+	t.oldest = (t.oldest + 1) % len(t.ended)
+}
+
+// ack applies an acknowledgement.
+func (t *ackTracker) ack(params protocol.AckEventsParams, now time.Time) (protocol.AckEventsResult, error) {
+	t.mu.Lock()
+	st, ok := t.streams[params.StreamID]
+	t.mu.Unlock()
+	if !ok || st.taskID != params.ID {
+		return protocol.AckEventsResult{}, fmt.Errorf("unknown stream %q of task %s", params.StreamID, params.ID)
+	}
+	result, done, err := st.ack(params.Sequence, now)
+	if err != nil {
+		return protocol.AckEventsResult{}, err
+	}
+	if done {
+		t.mu.Lock()
+		delete(t.streams, st.id)
+		t.mu.Unlock()
+	}
+	return result, nil
+}
+
+// openAckStream starts tracking the acknowledgements of an SSE stream of taskID
+// and announces it in the response headers. It returns nil if acknowledgements
+// are disabled or the stream cannot be tracked, in which case its events are
+// sent without sequence numbers.
+func (s *A2AServer) openAckStream(w http.ResponseWriter, taskID string) *ackStream {
+	if s.acks == nil {
+		return nil
+	}
+	st, err := s.acks.open(taskID)
+	if err != nil {
+		log.Errorf("Failed to track event acknowledgements of task %s: %v", taskID, err)
+		return nil
+	}
+	w.Header().Set(protocol.StreamIDHeader, st.id)
+	return st
+}
+
+// closeAckStream ends the tracking of st, if any.
+func (s *A2AServer) closeAckStream(st *ackStream) {
+	if st != nil {
+		s.acks.end(st)
+	}
+}
+
+// handleTasksAckEvents handles the tasks/ackEvents extension method.
+func (s *A2AServer) handleTasksAckEvents(ctx context.Context, w http.ResponseWriter, request jsonrpc.Request) {
+	if s.acks == nil {
+		s.writeJSONRPCError(w, request.ID,
+			jsonrpc.ErrMethodNotFound("event acknowledgements are not enabled on this agent"))
+		return
+	}
+	var params protocol.AckEventsParams
+	if err := s.unmarshalParams(request.Params, &params); err != nil {
+		s.writeJSONRPCError(w, request.ID, err)
+		return
+	}
+	result, err := s.acks.ack(params, time.Now())
+	if err != nil {
+		s.writeJSONRPCError(w, request.ID, jsonrpc.ErrInvalidParams(err.Error()))
+		return
+	}
+	if acknowledger, ok := s.taskManager.(taskmanager.EventAcknowledger); ok {
+		if err := acknowledger.OnAckEvents(ctx, result); err != nil {
+			var rpcErr *jsonrpc.Error
+			if !errors.As(err, &rpcErr) {
+				rpcErr = jsonrpc.ErrInternalError(fmt.Sprintf("failed to acknowledge events: %v", err))
+			}
+			s.writeJSONRPCError(w, request.ID, rpcErr)
+			return
+		}
+	}
+	if s.acks.observer != nil {
+		s.acks.observer(result)
+	}
+	s.writeJSONRPCResponse(w, request.ID, result)
+}
@@ -0,0 +1,145 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/client"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// ackingTaskManager records the acknowledgements it is passed.
+type ackingTaskManager struct {
+	*taskmanager.MemoryTaskManager
+
+	mu   sync.Mutex
+	acks []protocol.AckEventsResult
+}
+
+func (m *ackingTaskManager) OnAckEvents(ctx context.Context, ack protocol.AckEventsResult) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.acks = append(m.acks, ack)
+	return nil
+}
+
+func (m *ackingTaskManager) lastAck() (protocol.AckEventsResult, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.acks) == 0 {
+		return protocol.AckEventsResult{}, false
+	}
+	return m.acks[len(m.acks)-1], true
+}
+
+func TestA2AServer_EventAcks(t *testing.T) {
+	memory, err := taskmanager.NewMemoryTaskManager(&countingProcessor{})
+	require.NoError(t, err)
+	tm := &ackingTaskManager{MemoryTaskManager: memory}
+	var observed sync.WaitGroup
+	observed.Add(1)
+	var once sync.Once
+	a2aServer, err := NewA2AServer(defaultAgentCard(), tm, WithEventAcks(func(ack protocol.AckEventsResult) {
+		if ack.Lag == 0 {
+			once.Do(observed.Done)
+		}
+	}))
+	require.NoError(t, err)
+	testServer := httptest.NewServer(a2aServer.Handler())
+	defer testServer.Close()
+
+	c, err := client.NewA2AClient(testServer.URL, client.WithEventAcks(time.Hour))
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	msg := protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")})
+	events, err := c.StreamTask(ctx, protocol.SendTaskParams{ID: "task-1", Message: msg})
+	require.NoError(t, err)
+	received := 0
+	for event := range events {
+		received++
+		if event.IsFinal() {
+			break
+		}
+	}
+	// Ending the stream sends the final acknowledgement.
+	cancel()
+	observed.Wait()
+	ack, ok := tm.lastAck()
+	require.True(t, ok)
+	assert.Equal(t, "task-1", ack.ID)
+	assert.NotEmpty(t, ack.StreamID)
+	assert.Equal(t, int64(received), ack.Acked)
+	assert.Equal(t, int64(received), ack.Sent)
+	assert.Zero(t, ack.Lag)
+
+	// The stream is forgotten once fully acknowledged and ended.
+	assert.Eventually(t, func() bool {
+		_, err := c.AckEvents(context.Background(), protocol.AckEventsParams{
+			ID: "task-1", StreamID: ack.StreamID, Sequence: ack.Acked,
+		})
+		return err != nil && strings.Contains(err.Error(), "unknown stream")
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestAckTracker(t *testing.T) {
+	tracker := newAckTracker(nil)
+	st, err := tracker.open("task-1")
+	require.NoError(t, err)
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		st.next(start.Add(time.Duration(i) * time.Second))
+	}
+
+	_, err = tracker.ack(protocol.AckEventsParams{ID: "task-2", StreamID: st.id, Sequence: 1}, start)
+	assert.Error(t, err, "stream of another task")
+	_, err = tracker.ack(protocol.AckEventsParams{ID: "task-1", StreamID: st.id, Sequence: 4}, start)
+	assert.Error(t, err, "sequence beyond the events sent")
+
+	result, err := tracker.ack(protocol.AckEventsParams{ID: "task-1", StreamID: st.id, Sequence: 2},
+		start.Add(1500*time.Millisecond))
+	require.NoError(t, err)
+	assert.Equal(t, protocol.AckEventsResult{
+		ID: "task-1", StreamID: st.id, Acked: 2, Sent: 3, Lag: 1, LatencyMs: 500,
+	}, result)
+
+	// Stale acknowledgements change nothing.
+	result, err = tracker.ack(protocol.AckEventsParams{ID: "task-1", StreamID: st.id, Sequence: 1}, start)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), result.Acked)
+	assert.Zero(t, result.LatencyMs)
+
+	// Ended streams accept late acknowledgements until fully acknowledged.
+	tracker.end(st)
+	_, err = tracker.ack(protocol.AckEventsParams{ID: "task-1", StreamID: st.id, Sequence: 3}, start)
+	require.NoError(t, err)
+	_, err = tracker.ack(protocol.AckEventsParams{ID: "task-1", StreamID: st.id, Sequence: 3}, start)
+	assert.Error(t, err)
+}
+
+func TestA2AServer_EventAcksDisabled(t *testing.T) {
+	tm, err := taskmanager.NewMemoryTaskManager(&countingProcessor{})
+	require.NoError(t, err)
+	a2aServer, err := NewA2AServer(defaultAgentCard(), tm)
+	require.NoError(t, err)
+	testServer := httptest.NewServer(a2aServer.Handler())
+	defer testServer.Close()
+
+	c, err := client.NewA2AClient(testServer.URL)
+	require.NoError(t, err)
+	_, err = c.AckEvents(context.Background(), protocol.AckEventsParams{ID: "task-1", StreamID: "s", Sequence: 1})
+	assert.ErrorContains(t, err, "event acknowledgements are not enabled")
+}
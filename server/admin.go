@@ -0,0 +1,439 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"trpc.group/trpc-go/trpc-a2a-go/auth"
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// MetricsSnapshot returns a JSON-encodable snapshot of metrics for the
+// metrics endpoint of the admin API.
+type MetricsSnapshot func() interface{}
+
+// adminAPI holds the admin API settings.
+type adminAPI struct {
+	prefix         string                            // Path prefix of the API on Handler, if mounted.
+	provider       auth.Provider                     // Authenticates admin requests.
+	metrics        map[string]MetricsSnapshot        // Additional metrics by name.
+	pushDeliveries taskmanager.PushDeliveryInspector // Records push delivery attempts, if set.
+	usage          taskmanager.UsageReporter         // Reports the usage of principals, if set.
+}
+
+// adminAPI returns the admin API settings, creating them if needed.
+func (s *A2AServer) adminAPI() *adminAPI {
+	if s.admin == nil {
+		s.admin = &adminAPI{metrics: map[string]MetricsSnapshot{}}
+	}
+	return s.admin
+}
+
+// subscriptionStatser is implemented by task managers reporting subscription counters.
+type subscriptionStatser interface {
+	SubscriptionStats() taskmanager.SubscriptionStats
+}
+
+// slowConsumerStatser is implemented by task managers reporting slow consumer counters.
+type slowConsumerStatser interface {
+	SlowConsumerStats() taskmanager.SlowConsumerStats
+}
+
+// taskSpillStatser is implemented by task managers spilling task data to disk.
+type taskSpillStatser interface {
+	TaskSpillStats() taskmanager.TaskSpillStats
+}
+
+// AdminHandler returns the handler of the admin API, for serving it on a
+// separate listener. Every request must be authenticated by provider; the API
+// refuses all requests if provider is nil. The API answers JSON at:
+//
+//	GET  /tasks?sessionId=&state=&limit=  tasks across sessions
+//	GET  /tasks/{id}                      a task with its history
+//	POST /tasks/{id}/cancel               force-cancel a task
+//	POST /tasks/{id}/requeue              run a failed task again
+//	GET  /tasks/{id}/pushDeliveries       push notification delivery attempts
+//	GET  /tasks/{id}/export               snapshot of a task, to import elsewhere
+//	POST /tasks/import                    import a task snapshot
+//	GET  /tasks/changes                   stream of the task changes, as NDJSON
+//	GET  /metrics                         metrics snapshot
+//	GET  /usage                           usage of every principal
+//	GET  /usage/{principal}               usage of a principal
+//
+// Tasks are returned with their version as ETag. Canceling and requeuing a
+// task requires the version the operator saw in the If-Match header, or "*"
+// to change the task at any version, and fails with 412 Precondition Failed
+// if the task changed since. Importing a task requires If-Match too, "0" for
+// a task that must not exist.
+//
+// Listing, requeuing, exporting, importing and watching tasks requires a task
+// manager implementing taskmanager.TaskLister, taskmanager.TaskRequeuer,
+// taskmanager.TaskSnapshotter and taskmanager.TaskWatcher. Usage is reported by
+// the reporter set with WithUsageReporter.
+func (s *A2AServer) AdminHandler(provider auth.Provider) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /tasks", s.handleAdminListTasks)
+	mux.HandleFunc("GET /tasks/{id}", s.handleAdminGetTask)
+	mux.HandleFunc("POST /tasks/{id}/cancel", s.handleAdminCancelTask)
+	mux.HandleFunc("POST /tasks/{id}/requeue", s.handleAdminRequeueTask)
+	mux.HandleFunc("GET /tasks/{id}/pushDeliveries", s.handleAdminPushDeliveries)
+	mux.HandleFunc("GET /tasks/{id}/export", s.handleAdminExportTask)
+	mux.HandleFunc("POST /tasks/import", s.handleAdminImportTask)
+	mux.HandleFunc("GET /tasks/changes", s.handleAdminWatchTasks)
+	mux.HandleFunc("GET /metrics", s.handleAdminMetrics)
+	mux.HandleFunc("GET /usage", s.handleAdminListUsage)
+	mux.HandleFunc("GET /usage/{principal}", s.handleAdminGetUsage)
+	if provider == nil {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		})
+	}
+	return auth.NewMiddleware(provider).Wrap(mux)
+}
+
+// handleAdminListTasks lists the tasks selected by the query parameters.
+func (s *A2AServer) handleAdminListTasks(w http.ResponseWriter, r *http.Request) {
+	lister, ok := s.taskManager.(taskmanager.TaskLister)
+	if !ok {
+		s.writeAdminError(w, http.StatusNotImplemented, errors.New("the task manager cannot list tasks"))
+		return
+	}
+	query := r.URL.Query()
+	filter := taskmanager.TaskFilter{SessionID: query.Get("sessionId")}
+	for _, states := range query["state"] {
+		for _, state := range strings.Split(states, ",") {
+			filter.States = append(filter.States, protocol.TaskState(state))
+		}
+	}
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 0 {
+			s.writeAdminError(w, http.StatusBadRequest, errors.New("invalid limit "+limit))
+			return
+		}
+		filter.Limit = n
+	}
+	tasks, err := lister.ListTasks(r.Context(), filter)
+	if err != nil {
+		s.writeAdminTaskError(w, err)
+		return
+	}
+	s.writeAdminJSON(w, map[string]interface{}{"tasks": tasks})
+}
+
+// handleAdminGetTask returns a task with its full history.
+func (s *A2AServer) handleAdminGetTask(w http.ResponseWriter, r *http.Request) {
+	historyLength := 0
+	task, err := s.taskManager.OnGetTask(r.Context(), protocol.TaskQueryParams{
+		ID:            r.PathValue("id"),
+		HistoryLength: &historyLength,
+	})
+	if err != nil {
+		s.writeAdminTaskError(w, err)
+		return
+	}
+	s.writeAdminTask(w, task)
+}
+
+// handleAdminCancelTask cancels a task on behalf of the operator.
+func (s *A2AServer) handleAdminCancelTask(w http.ResponseWriter, r *http.Request) {
+	ctx, ok := s.adminMutationContext(w, r)
+	if !ok {
+		return
+	}
+	taskID := r.PathValue("id")
+	task, err := s.taskManager.OnCancelTask(ctx, protocol.TaskIDParams{
+		ID:     taskID,
+		Reason: protocol.CancelReasonPolicy,
+	})
+	if err != nil {
+		s.writeAdminTaskError(w, err)
+		return
+	}
+	log.Infof("Admin canceled task %s", taskID)
+	s.writeAdminTask(w, task)
+}
+
+// handleAdminRequeueTask runs a failed task again.
+func (s *A2AServer) handleAdminRequeueTask(w http.ResponseWriter, r *http.Request) {
+	requeuer, ok := s.taskManager.(taskmanager.TaskRequeuer)
+	if !ok {
+		s.writeAdminError(w, http.StatusNotImplemented, errors.New("the task manager cannot requeue tasks"))
+		return
+	}
+	ctx, ok := s.adminMutationContext(w, r)
+	if !ok {
+		return
+	}
+	task, err := requeuer.RequeueTask(ctx, r.PathValue("id"))
+	if err != nil {
+		s.writeAdminTaskError(w, err)
+		return
+	}
+	s.writeAdminTask(w, task)
+}
+
+// handleAdminPushDeliveries returns the push delivery attempts of a task.
+func (s *A2AServer) handleAdminPushDeliveries(w http.ResponseWriter, r *http.Request) {
+	inspector := s.pushDeliveryInspector()
+	if inspector == nil {
+		s.writeAdminError(w, http.StatusNotImplemented, errors.New("push delivery attempts are not recorded"))
+		return
+	}
+	attempts, err := inspector.PushDeliveryAttempts(r.Context(), r.PathValue("id"))
+	if err != nil {
+		s.writeAdminTaskError(w, err)
+		return
+	}
+	if attempts == nil {
+		attempts = []taskmanager.PushDeliveryAttempt{}
+	}
+	s.writeAdminJSON(w, map[string]interface{}{"attempts": attempts})
+}
+
+// handleAdminExportTask returns a snapshot of a task.
+func (s *A2AServer) handleAdminExportTask(w http.ResponseWriter, r *http.Request) {
+	snapshotter, ok := s.taskManager.(taskmanager.TaskSnapshotter)
+	if !ok {
+		s.writeAdminError(w, http.StatusNotImplemented, errors.New("the task manager cannot export tasks"))
+		return
+	}
+	snapshot, err := snapshotter.ExportTask(r.Context(), r.PathValue("id"))
+	if err != nil {
+		s.writeAdminTaskError(w, err)
+		return
+	}
+	s.writeAdminJSON(w, snapshot)
+}
+
+// handleAdminImportTask imports the task snapshot in the request body.
+func (s *A2AServer) handleAdminImportTask(w http.ResponseWriter, r *http.Request) {
+	snapshotter, ok := s.taskManager.(taskmanager.TaskSnapshotter)
+	if !ok {
+		s.writeAdminError(w, http.StatusNotImplemented, errors.New("the task manager cannot import tasks"))
+		return
+	}
+	if s.maxRequestBytes > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, s.maxRequestBytes)
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		s.writeAdminError(w, http.StatusBadRequest, err)
+		return
+	}
+	var snapshot taskmanager.TaskSnapshot
+	if err := s.codec.Unmarshal(body, &snapshot); err != nil {
+		s.writeAdminError(w, http.StatusBadRequest, errors.New("invalid task snapshot: "+err.Error()))
+		return
+	}
+	ctx, ok := s.adminMutationContext(w, r)
+	if !ok {
+		return
+	}
+	task, err := snapshotter.ImportTask(ctx, &snapshot)
+	if err != nil {
+		s.writeAdminTaskError(w, err)
+		return
+	}
+	log.Infof("Admin imported task %s", task.ID)
+	s.writeAdminTask(w, task)
+}
+
+// handleAdminWatchTasks streams the changes of the tasks as newline-delimited
+// JSON until the client disconnects. The stream ends early if the client
+// reads too slowly.
+func (s *A2AServer) handleAdminWatchTasks(w http.ResponseWriter, r *http.Request) {
+	watcher, ok := s.taskManager.(taskmanager.TaskWatcher)
+	if !ok {
+		s.writeAdminError(w, http.StatusNotImplemented, errors.New("the task manager cannot watch tasks"))
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		s.writeAdminError(w, http.StatusInternalServerError, errors.New("streaming not supported"))
+		return
+	}
+	changes, err := watcher.WatchTasks(r.Context())
+	if err != nil {
+		s.writeAdminTaskError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", protocol.ContentTypeNDJSON)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	for change := range changes {
+		if err := s.encodeJSON(w, change); err != nil {
+			log.Errorf("Failed to write task change: %v", err)
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+// handleAdminMetrics returns a snapshot of the metrics of the server.
+func (s *A2AServer) handleAdminMetrics(w http.ResponseWriter, r *http.Request) {
+	snapshot := map[string]interface{}{}
+	if statser, ok := s.taskManager.(subscriptionStatser); ok {
+		snapshot["subscriptions"] = statser.SubscriptionStats()
+	}
+	if statser, ok := s.taskManager.(slowConsumerStatser); ok {
+		snapshot["slowConsumers"] = statser.SlowConsumerStats()
+	}
+	if statser, ok := s.taskManager.(taskSpillStatser); ok {
+		snapshot["taskSpill"] = statser.TaskSpillStats()
+	}
+	if lister, ok := s.taskManager.(taskmanager.TaskLister); ok {
+		tasks, err := lister.ListTasks(r.Context(), taskmanager.TaskFilter{})
+		if err != nil {
+			s.writeAdminTaskError(w, err)
+			return
+		}
+		states := map[protocol.TaskState]int{}
+		for _, task := range tasks {
+			states[task.Status.State]++
+		}
+		snapshot["tasks"] = states
+	}
+	if s.admin != nil {
+		for name, metrics := range s.admin.metrics {
+			snapshot[name] = metrics()
+		}
+	}
+	s.writeAdminJSON(w, snapshot)
+}
+
+// handleAdminListUsage returns the usage of every principal.
+func (s *A2AServer) handleAdminListUsage(w http.ResponseWriter, r *http.Request) {
+	reporter := s.usageReporter()
+	if reporter == nil {
+		s.writeAdminError(w, http.StatusNotImplemented, errors.New("usage is not accounted"))
+		return
+	}
+	usage, err := reporter.ListUsage(r.Context())
+	if err != nil {
+		s.writeAdminTaskError(w, err)
+		return
+	}
+	s.writeAdminJSON(w, map[string]interface{}{"usage": usage})
+}
+
+// handleAdminGetUsage returns the usage of a principal.
+func (s *A2AServer) handleAdminGetUsage(w http.ResponseWriter, r *http.Request) {
+	reporter := s.usageReporter()
+	if reporter == nil {
+		s.writeAdminError(w, http.StatusNotImplemented, errors.New("usage is not accounted"))
+		return
+	}
+	usage, err := reporter.Usage(r.Context(), r.PathValue("principal"))
+	if err != nil {
+		s.writeAdminTaskError(w, err)
+		return
+	}
+	s.writeAdminJSON(w, usage)
+}
+
+// usageReporter returns the configured usage reporter, or the task manager if
+// it is one.
+func (s *A2AServer) usageReporter() taskmanager.UsageReporter {
+	if s.admin != nil && s.admin.usage != nil {
+		return s.admin.usage
+	}
+	reporter, _ := s.taskManager.(taskmanager.UsageReporter)
+	return reporter
+}
+
+// pushDeliveryInspector returns the configured push delivery inspector, or
+// the task manager if it is one.
+func (s *A2AServer) pushDeliveryInspector() taskmanager.PushDeliveryInspector {
+	if s.admin != nil && s.admin.pushDeliveries != nil {
+		return s.admin.pushDeliveries
+	}
+	inspector, _ := s.taskManager.(taskmanager.PushDeliveryInspector)
+	return inspector
+}
+
+// adminMutationContext returns the context of a change of a task, expecting
+// the version of the If-Match header. It answers the request if the header is
+// missing or invalid.
+func (s *A2AServer) adminMutationContext(w http.ResponseWriter, r *http.Request) (context.Context, bool) {
+	match := strings.TrimSpace(r.Header.Get("If-Match"))
+	switch match {
+	case "":
+		s.writeAdminError(w, http.StatusPreconditionRequired,
+			errors.New("the If-Match header must hold the version of the task, or *"))
+		return nil, false
+	case "*":
+		return r.Context(), true
+	}
+	version, err := strconv.ParseUint(strings.Trim(match, `"`), 10, 64)
+	if err != nil {
+		s.writeAdminError(w, http.StatusBadRequest, errors.New("invalid If-Match header "+match))
+		return nil, false
+	}
+	return taskmanager.ContextWithExpectedVersion(r.Context(), version), true
+}
+
+// writeAdminTask writes task as a JSON response of the admin API, with its
+// version as ETag.
+func (s *A2AServer) writeAdminTask(w http.ResponseWriter, task *protocol.Task) {
+	if task.Version > 0 {
+		w.Header().Set("ETag", `"`+strconv.FormatUint(task.Version, 10)+`"`)
+	}
+	s.writeAdminJSON(w, task)
+}
+
+// writeAdminJSON writes v as a JSON response of the admin API.
+func (s *A2AServer) writeAdminJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if err := s.encodeJSON(w, v); err != nil {
+		log.Errorf("Failed to write admin response: %v", err)
+	}
+}
+
+// writeAdminError writes an error response of the admin API.
+func (s *A2AServer) writeAdminError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if err := s.encodeJSON(w, map[string]string{"error": err.Error()}); err != nil {
+		log.Errorf("Failed to write admin error response: %v", err)
+	}
+}
+
+// writeAdminTaskError writes the error of a task manager call, mapping the
+// task errors to HTTP statuses.
+func (s *A2AServer) writeAdminTaskError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	var rpcErr *jsonrpc.Error
+	if errors.As(err, &rpcErr) {
+		switch rpcErr.Code {
+		case taskmanager.ErrCodeTaskNotFound:
+			status = http.StatusNotFound
+		case taskmanager.ErrCodeTaskFinal, taskmanager.ErrCodeTaskNotRequeueable, taskmanager.ErrCodeTaskExists:
+			status = http.StatusConflict
+		case taskmanager.ErrCodeTaskVersionConflict:
+			status = http.StatusPreconditionFailed
+		case jsonrpc.CodeInvalidParams:
+			status = http.StatusBadRequest
+		case jsonrpc.CodeMethodNotFound:
+			status = http.StatusNotImplemented
+		}
+		if data, ok := rpcErr.Data.(string); ok && data != "" {
+			err = errors.New(rpcErr.Message + ": " + data)
+		}
+	}
+	s.writeAdminError(w, status, err)
+}
@@ -0,0 +1,298 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/auth"
+	"trpc.group/trpc-go/trpc-a2a-go/client"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// flakyProcessor fails the first task it processes and completes the others.
+type flakyProcessor struct {
+	calls atomic.Int32
+}
+
+func (p *flakyProcessor) Process(
+	ctx context.Context,
+	taskID string,
+	message protocol.Message,
+	handle taskmanager.TaskHandle,
+) error {
+	if p.calls.Add(1) == 1 {
+		return errors.New("backend unavailable")
+	}
+	return handle.UpdateStatus(protocol.TaskStateCompleted, nil)
+}
+
+func TestA2AServer_AdminAPI(t *testing.T) {
+	tm, err := taskmanager.NewMemoryTaskManager(&flakyProcessor{})
+	require.NoError(t, err)
+	deliveries := taskmanager.NewPushDeliveryLog(0)
+	deliveries.Record(taskmanager.PushDeliveryAttempt{TaskID: "task-1", URL: "http://hook", StatusCode: 502})
+	a2aServer, err := NewA2AServer(defaultAgentCard(), tm,
+		WithAdminAPI("/admin", auth.NewAPIKeyAuthProvider(map[string]string{"admin-key": "ops"}, "")),
+		WithAdminMetrics("build", func() interface{} { return "v1" }),
+		WithPushDeliveryInspector(deliveries),
+	)
+	require.NoError(t, err)
+	testServer := httptest.NewServer(a2aServer.Handler())
+	defer testServer.Close()
+
+	do := func(method, path, key string, out interface{}) int {
+		req, err := http.NewRequest(method, testServer.URL+"/admin"+path, nil)
+		require.NoError(t, err)
+		if key != "" {
+			req.Header.Set("X-API-Key", key)
+		}
+		req.Header.Set("If-Match", "*")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		if out != nil && resp.StatusCode == http.StatusOK {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+		}
+		return resp.StatusCode
+	}
+
+	c, err := client.NewA2AClient(testServer.URL)
+	require.NoError(t, err)
+	session := "session-1"
+	msg := protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")})
+	_, err = c.SendTasks(context.Background(), protocol.SendTaskParams{ID: "task-1", SessionID: &session, Message: msg})
+	require.Error(t, err)
+	_, err = c.SendTasks(context.Background(), protocol.SendTaskParams{ID: "task-2", Message: msg})
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusUnauthorized, do(http.MethodGet, "/tasks", "", nil))
+	assert.Equal(t, http.StatusUnauthorized, do(http.MethodGet, "/tasks", "wrong", nil))
+
+	var list struct{ Tasks []protocol.Task }
+	require.Equal(t, http.StatusOK, do(http.MethodGet, "/tasks?sessionId=session-1", "admin-key", &list))
+	require.Len(t, list.Tasks, 1)
+	assert.Equal(t, "task-1", list.Tasks[0].ID)
+	assert.Equal(t, protocol.TaskStateFailed, list.Tasks[0].Status.State)
+	require.Equal(t, http.StatusOK, do(http.MethodGet, "/tasks?state=completed,failed", "admin-key", &list))
+	assert.Len(t, list.Tasks, 2)
+
+	var metrics map[string]json.RawMessage
+	require.Equal(t, http.StatusOK, do(http.MethodGet, "/metrics", "admin-key", &metrics))
+	assert.JSONEq(t, `{"completed": 1, "failed": 1}`, string(metrics["tasks"]))
+	assert.JSONEq(t, `"v1"`, string(metrics["build"]))
+	assert.Contains(t, metrics, "subscriptions")
+
+	var attempts struct {
+		Attempts []taskmanager.PushDeliveryAttempt
+	}
+	require.Equal(t, http.StatusOK, do(http.MethodGet, "/tasks/task-1/pushDeliveries", "admin-key", &attempts))
+	require.Len(t, attempts.Attempts, 1)
+	assert.Equal(t, 502, attempts.Attempts[0].StatusCode)
+
+	// Only failed tasks can be requeued, and requeued tasks run again.
+	assert.Equal(t, http.StatusConflict, do(http.MethodPost, "/tasks/task-2/requeue", "admin-key", nil))
+	assert.Equal(t, http.StatusNotFound, do(http.MethodPost, "/tasks/missing/requeue", "admin-key", nil))
+	require.Equal(t, http.StatusOK, do(http.MethodPost, "/tasks/task-1/requeue", "admin-key", nil))
+	require.Eventually(t, func() bool {
+		var task protocol.Task
+		do(http.MethodGet, "/tasks/task-1", "admin-key", &task)
+		return task.Status.State == protocol.TaskStateCompleted
+	}, time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, http.StatusConflict, do(http.MethodPost, "/tasks/task-1/cancel", "admin-key", nil))
+}
+
+func TestA2AServer_AdminTaskVersions(t *testing.T) {
+	tm, err := taskmanager.NewMemoryTaskManager(&flakyProcessor{})
+	require.NoError(t, err)
+	a2aServer, err := NewA2AServer(defaultAgentCard(), tm,
+		WithAdminAPI("/admin", auth.NewAPIKeyAuthProvider(map[string]string{"admin-key": "ops"}, "")),
+	)
+	require.NoError(t, err)
+	testServer := httptest.NewServer(a2aServer.Handler())
+	defer testServer.Close()
+
+	do := func(method, path, match string) *http.Response {
+		req, err := http.NewRequest(method, testServer.URL+"/admin"+path, nil)
+		require.NoError(t, err)
+		req.Header.Set("X-API-Key", "admin-key")
+		if match != "" {
+			req.Header.Set("If-Match", match)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp
+	}
+
+	c, err := client.NewA2AClient(testServer.URL)
+	require.NoError(t, err)
+	msg := protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")})
+	_, err = c.SendTasks(context.Background(), protocol.SendTaskParams{ID: "task-1", Message: msg})
+	require.Error(t, err)
+
+	resp := do(http.MethodGet, "/tasks/task-1", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	etag := resp.Header.Get("ETag")
+	require.NotEmpty(t, etag)
+
+	assert.Equal(t, http.StatusPreconditionRequired, do(http.MethodPost, "/tasks/task-1/requeue", "").StatusCode)
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/tasks/task-1/requeue", "latest").StatusCode)
+	assert.Equal(t, http.StatusPreconditionFailed, do(http.MethodPost, "/tasks/task-1/requeue", `"999"`).StatusCode)
+	resp = do(http.MethodPost, "/tasks/task-1/requeue", etag)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.NotEqual(t, etag, resp.Header.Get("ETag"))
+	assert.Equal(t, http.StatusPreconditionFailed, do(http.MethodPost, "/tasks/task-1/cancel", etag).StatusCode,
+		"the task changed since it was read")
+}
+
+func TestA2AServer_AdminUsage(t *testing.T) {
+	tm, err := taskmanager.NewMemoryTaskManager(&flakyProcessor{})
+	require.NoError(t, err)
+	accountant := taskmanager.NewUsageAccountant(taskmanager.QuotaConfig{})
+	accountant.RecordCompute("alice", 3*time.Second)
+	a2aServer, err := NewA2AServer(defaultAgentCard(), tm,
+		WithAdminAPI("/admin", auth.NewAPIKeyAuthProvider(map[string]string{"admin-key": "ops"}, "")),
+		WithUsageReporter(accountant),
+	)
+	require.NoError(t, err)
+	testServer := httptest.NewServer(a2aServer.Handler())
+	defer testServer.Close()
+
+	get := func(path string, out interface{}) int {
+		req, err := http.NewRequest(http.MethodGet, testServer.URL+"/admin"+path, nil)
+		require.NoError(t, err)
+		req.Header.Set("X-API-Key", "admin-key")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+		return resp.StatusCode
+	}
+
+	var all struct {
+		Usage map[string]taskmanager.PrincipalUsage
+	}
+	require.Equal(t, http.StatusOK, get("/usage", &all))
+	assert.Equal(t, map[string]taskmanager.PrincipalUsage{"alice": {ComputeSeconds: 3}}, all.Usage)
+	var usage taskmanager.PrincipalUsage
+	require.Equal(t, http.StatusOK, get("/usage/bob", &usage))
+	assert.Equal(t, taskmanager.PrincipalUsage{}, usage)
+}
+
+func TestA2AServer_AdminSnapshots(t *testing.T) {
+	newServer := func(processor taskmanager.TaskProcessor) *httptest.Server {
+		tm, err := taskmanager.NewMemoryTaskManager(processor)
+		require.NoError(t, err)
+		a2aServer, err := NewA2AServer(defaultAgentCard(), tm,
+			WithAdminAPI("/admin", auth.NewAPIKeyAuthProvider(map[string]string{"admin-key": "ops"}, "")),
+		)
+		require.NoError(t, err)
+		return httptest.NewServer(a2aServer.Handler())
+	}
+	recovered := &flakyProcessor{}
+	recovered.calls.Store(1)
+	source, target := newServer(&flakyProcessor{}), newServer(recovered)
+	defer source.Close()
+	defer target.Close()
+
+	do := func(server *httptest.Server, method, path string, body []byte) (int, []byte) {
+		req, err := http.NewRequest(method, server.URL+"/admin"+path, bytes.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("X-API-Key", "admin-key")
+		req.Header.Set("If-Match", "*")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var buf bytes.Buffer
+		_, err = buf.ReadFrom(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, buf.Bytes()
+	}
+
+	c, err := client.NewA2AClient(source.URL)
+	require.NoError(t, err)
+	msg := protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")})
+	_, err = c.SendTasks(context.Background(), protocol.SendTaskParams{ID: "stuck", Message: msg})
+	require.Error(t, err)
+
+	status, snapshot := do(source, http.MethodGet, "/tasks/stuck/export", nil)
+	require.Equal(t, http.StatusOK, status, string(snapshot))
+	req, err := http.NewRequest(http.MethodPost, target.URL+"/admin/tasks/import", bytes.NewReader(snapshot))
+	require.NoError(t, err)
+	req.Header.Set("X-API-Key", "admin-key")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusPreconditionRequired, resp.StatusCode, "imports require If-Match")
+	status, _ = do(target, http.MethodPost, "/tasks/import", snapshot)
+	require.Equal(t, http.StatusOK, status)
+	status, _ = do(target, http.MethodPost, "/tasks/import", snapshot)
+	assert.Equal(t, http.StatusConflict, status, "tasks are imported once")
+	status, _ = do(target, http.MethodPost, "/tasks/import", []byte(`{"version": 99}`))
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	// The imported task can be requeued on the target.
+	status, _ = do(target, http.MethodPost, "/tasks/stuck/requeue", nil)
+	require.Equal(t, http.StatusOK, status)
+	require.Eventually(t, func() bool {
+		_, body := do(target, http.MethodGet, "/tasks/stuck", nil)
+		var task protocol.Task
+		return json.Unmarshal(body, &task) == nil && task.Status.State == protocol.TaskStateCompleted
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestA2AServer_AdminWatchTasks(t *testing.T) {
+	tm, err := taskmanager.NewMemoryTaskManager(&flakyProcessor{})
+	require.NoError(t, err)
+	a2aServer, err := NewA2AServer(defaultAgentCard(), tm,
+		WithAdminAPI("/admin", auth.NewAPIKeyAuthProvider(map[string]string{"admin-key": "ops"}, "")),
+	)
+	require.NoError(t, err)
+	testServer := httptest.NewServer(a2aServer.Handler())
+	defer testServer.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, testServer.URL+"/admin/tasks/changes", nil)
+	require.NoError(t, err)
+	req.Header.Set("X-API-Key", "admin-key")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, protocol.ContentTypeNDJSON, resp.Header.Get("Content-Type"))
+
+	c, err := client.NewA2AClient(testServer.URL)
+	require.NoError(t, err)
+	msg := protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")})
+	_, err = c.SendTasks(context.Background(), protocol.SendTaskParams{ID: "watched", Message: msg})
+	require.Error(t, err)
+
+	dec := json.NewDecoder(resp.Body)
+	var changes []taskmanager.TaskChange
+	for len(changes) == 0 || changes[len(changes)-1].Task.Status.State != protocol.TaskStateFailed {
+		var change taskmanager.TaskChange
+		require.NoError(t, dec.Decode(&change))
+		changes = append(changes, change)
+	}
+	assert.Equal(t, taskmanager.TaskCreated, changes[0].Type)
+	assert.Equal(t, "watched", changes[0].TaskID)
+}
@@ -0,0 +1,309 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/metadata"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// Media types of artifact downloads.
+const (
+	contentTypeJSON        = "application/json"
+	contentTypeText        = "text/plain; charset=utf-8"
+	contentTypeOctetStream = "application/octet-stream"
+)
+
+// artifactHandler returns the handler of the artifact download endpoint,
+// mounted at s.artifactPath.
+func (s *A2AServer) artifactHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+s.artifactPath+"/{taskID}/{index}", s.handleDownloadArtifact)
+	return mux
+}
+
+// handleDownloadArtifact serves the content of an artifact of a task, read
+// through the task manager so that its access checks apply.
+func (s *A2AServer) handleDownloadArtifact(w http.ResponseWriter, r *http.Request) {
+	index, err := strconv.Atoi(r.PathValue("index"))
+	if err != nil || index < 0 {
+		http.Error(w, "invalid artifact index", http.StatusBadRequest)
+		return
+	}
+	ctx := r.Context()
+	if md := metadata.FromHeader(r.Header); md.Len() > 0 {
+		ctx = metadata.NewIncomingContext(ctx, md)
+	}
+	historyLength := 0
+	task, err := s.taskManager.OnGetTask(ctx, protocol.TaskQueryParams{
+		ID:            r.PathValue("taskID"),
+		HistoryLength: &historyLength,
+	})
+	if err != nil {
+		s.writeAdminTaskError(w, err)
+		return
+	}
+	artifact, ok := joinArtifactChunks(task.Artifacts, index)
+	if !ok {
+		http.Error(w, fmt.Sprintf("task %s has no artifact %d", task.ID, index), http.StatusNotFound)
+		return
+	}
+	content, contentType, encoded, err := s.artifactContent(artifact, r.Header.Get("Accept"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotAcceptable)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	// The content type is set by the agent, which may not control the content.
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if task.Version > 0 {
+		// The version of the task changes with its artifacts, and costs
+		// nothing to compute, unlike a digest of the content.
+		representation := "raw"
+		if encoded {
+			representation = "json"
+		}
+		w.Header().Set("ETag", fmt.Sprintf(`"%d-%s"`, task.Version, representation))
+	}
+	// The artifact may still grow while the task runs.
+	w.Header().Set("Cache-Control", "no-cache")
+	// Added to the Vary of the compression of the server, if enabled.
+	w.Header().Add("Vary", "Accept")
+	http.ServeContent(w, r, "", time.Time{}, content)
+}
+
+// joinArtifactChunks returns the artifact at index of artifacts, with the
+// parts of its chunks joined, or false if there is none.
+func joinArtifactChunks(artifacts []protocol.Artifact, index int) (protocol.Artifact, bool) {
+	var joined protocol.Artifact
+	found := false
+	for _, chunk := range artifacts {
+		if chunk.Index != index {
+			continue
+		}
+		if !found {
+			joined = chunk
+			joined.Parts = nil
+			joined.Append, joined.LastChunk = nil, nil
+			found = true
+		}
+		joined.Parts = append(joined.Parts, chunk.Parts...)
+	}
+	return joined, found
+}
+
+// artifactContent returns the content of artifact and its media type,
+// negotiated with the Accept header accept: the text of its parts if they
+// are all text parts, the bytes of its parts if they are all file parts with
+// bytes, or else its JSON encoding, in which case encoded is true.
+func (s *A2AServer) artifactContent(
+	artifact protocol.Artifact,
+	accept string,
+) (content io.ReadSeeker, contentType string, encoded bool, err error) {
+	content, contentType, ok := rawArtifactContent(artifact)
+	if ok && accepts(accept, contentType) {
+		return content, contentType, false, nil
+	}
+	if accepts(accept, contentTypeJSON) {
+		data, err := s.codec.Marshal(artifact)
+		if err != nil {
+			return nil, "", false, fmt.Errorf("failed to encode artifact: %w", err)
+		}
+		return bytes.NewReader(data), contentTypeJSON, true, nil
+	}
+	if ok {
+		return nil, "", false, fmt.Errorf("the artifact is available as %s or %s", contentType, contentTypeJSON)
+	}
+	return nil, "", false, errors.New("the artifact is only available as " + contentTypeJSON)
+}
+
+// rawArtifactContent returns the text or the file bytes of artifact, or false
+// if its parts are not all text parts or all file parts with bytes. The file
+// bytes are decoded as they are read, so that range requests only decode the
+// part of the content they return.
+func rawArtifactContent(artifact protocol.Artifact) (io.ReadSeeker, string, bool) {
+	var texts, files []string
+	mimeType := ""
+	for _, part := range artifact.Parts {
+		switch p := part.(type) {
+		case protocol.TextPart:
+			texts = append(texts, p.Text)
+		case *protocol.TextPart:
+			texts = append(texts, p.Text)
+		case protocol.FilePart:
+			if p.File.Bytes == nil {
+				return nil, "", false
+			}
+			files = append(files, *p.File.Bytes)
+			if mimeType == "" && p.File.MimeType != nil {
+				mimeType = *p.File.MimeType
+			}
+		case *protocol.FilePart:
+			if p.File.Bytes == nil {
+				return nil, "", false
+			}
+			files = append(files, *p.File.Bytes)
+			if mimeType == "" && p.File.MimeType != nil {
+				mimeType = *p.File.MimeType
+			}
+		default:
+			return nil, "", false
+		}
+	}
+	var content joinedContent
+	switch {
+	case len(texts) > 0 && len(files) == 0:
+		for _, text := range texts {
+			content.add(strings.NewReader(text), int64(len(text)))
+		}
+		return content.reader(), contentTypeText, true
+	case len(files) > 0 && len(texts) == 0:
+		for _, encoded := range files {
+			part, size, ok := newBase64Content(encoded)
+			if !ok {
+				return nil, "", false
+			}
+			content.add(part, size)
+		}
+		if mimeType == "" {
+			mimeType = contentTypeOctetStream
+		}
+		return content.reader(), mimeType, true
+	}
+	return nil, "", false
+}
+
+// joinedContent is the content of the parts of an artifact, one after the
+// other.
+type joinedContent struct {
+	parts []io.ReaderAt
+	sizes []int64
+	size  int64
+}
+
+// add appends part, of size bytes.
+func (c *joinedContent) add(part io.ReaderAt, size int64) {
+	c.parts = append(c.parts, part)
+	c.sizes = append(c.sizes, size)
+	c.size += size
+}
+
+// reader returns a reader of the content.
+func (c *joinedContent) reader() io.ReadSeeker {
+	return io.NewSectionReader(c, 0, c.size)
+}
+
+// ReadAt implements io.ReaderAt.
+func (c *joinedContent) ReadAt(p []byte, off int64) (int, error) {
+	n := 0
+	for i, part := range c.parts {
+		if len(p) == 0 {
+			break
+		}
+		if off >= c.sizes[i] {
+			off -= c.sizes[i]
+			continue
+		}
+		want := int(min(int64(len(p)), c.sizes[i]-off))
+		read, err := part.ReadAt(p[:want], off)
+		n += read
+		if read < want {
+			if err == nil || err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return n, err
+		}
+		p = p[read:]
+		off = 0
+	}
+	if len(p) > 0 {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// base64Content is the content of a file part, decoded from its standard
+// base64 encoding as it is read.
+type base64Content string
+
+// newBase64Content returns the content encoded and its size, or false if
+// encoded is not padded base64 without line breaks, which is decoded at
+// once.
+func newBase64Content(encoded string) (io.ReaderAt, int64, bool) {
+	if len(encoded)%4 != 0 || strings.ContainsAny(encoded, "\r\n") {
+		decoded, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, 0, false
+		}
+		return bytes.NewReader(decoded), int64(len(decoded)), true
+	}
+	size := len(encoded) / 4 * 3
+	if strings.HasSuffix(encoded, "==") {
+		size -= 2
+	} else if strings.HasSuffix(encoded, "=") {
+		size--
+	}
+	return base64Content(encoded), int64(size), true
+}
+
+// ReadAt implements io.ReaderAt, decoding the 3-byte groups holding the bytes
+// read.
+func (c base64Content) ReadAt(p []byte, off int64) (int, error) {
+	first := off / 3
+	last := min((off+int64(len(p))+2)/3, int64(len(c))/4)
+	if first >= last {
+		return 0, io.EOF
+	}
+	decoded := make([]byte, (last-first)*3)
+	n, err := base64.StdEncoding.Decode(decoded, []byte(c[first*4:last*4]))
+	if err != nil {
+		return 0, fmt.Errorf("invalid file content: %w", err)
+	}
+	start := off - first*3
+	if start >= int64(n) {
+		return 0, io.EOF
+	}
+	read := copy(p, decoded[start:n])
+	if read < len(p) {
+		return read, io.EOF
+	}
+	return read, nil
+}
+
+// accepts reports whether the Accept header accept admits contentType. An
+// empty header admits any media type.
+func accepts(accept, contentType string) bool {
+	if strings.TrimSpace(accept) == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = contentType
+	}
+	major, _, _ := strings.Cut(mediaType, "/")
+	for _, item := range strings.Split(accept, ",") {
+		accepted, params, err := mime.ParseMediaType(strings.TrimSpace(item))
+		if err != nil || params["q"] == "0" {
+			continue
+		}
+		if accepted == "*/*" || accepted == mediaType || accepted == major+"/*" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,164 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/client"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+func TestA2AServer_DownloadArtifact(t *testing.T) {
+	bytesB64 := base64.StdEncoding.EncodeToString([]byte("binary content"))
+	mimeType := "image/png"
+	tm := newMockTaskManager()
+	tm.tasks["task-1"] = &protocol.Task{
+		ID:      "task-1",
+		Version: 3,
+		Artifacts: []protocol.Artifact{
+			{Index: 0, Parts: []protocol.Part{protocol.NewTextPart("hello, ")}},
+			{Index: 1, Parts: []protocol.Part{protocol.FilePart{
+				Type: protocol.PartTypeFile,
+				File: protocol.FileContent{MimeType: &mimeType, Bytes: &bytesB64},
+			}}},
+			{Index: 0, Parts: []protocol.Part{protocol.NewTextPart("world")}},
+			{Index: 2, Parts: []protocol.Part{protocol.DataPart{Type: protocol.PartTypeData, Data: map[string]interface{}{"a": 1}}}},
+		},
+	}
+	a2aServer, err := NewA2AServer(defaultAgentCard(), tm, WithArtifactDownloads(""))
+	require.NoError(t, err)
+	testServer := httptest.NewServer(a2aServer.Handler())
+	defer testServer.Close()
+	c, err := client.NewA2AClient(testServer.URL)
+	require.NoError(t, err)
+
+	download := func(t *testing.T, index int, opts ...client.CallOption) (string, error) {
+		content, err := c.DownloadArtifact(context.Background(), "task-1", index, opts...)
+		if err != nil {
+			return "", err
+		}
+		defer content.Close()
+		data, err := io.ReadAll(content)
+		require.NoError(t, err)
+		return string(data), nil
+	}
+
+	t.Run("text chunks are joined", func(t *testing.T) {
+		content, err := download(t, 0)
+		require.NoError(t, err)
+		assert.Equal(t, "hello, world", content)
+	})
+
+	t.Run("range", func(t *testing.T) {
+		content, err := download(t, 0, client.WithCallRange(7, 3))
+		require.NoError(t, err)
+		assert.Equal(t, "wor", content)
+		content, err = download(t, 0, client.WithCallRange(7, 0))
+		require.NoError(t, err)
+		assert.Equal(t, "world", content)
+	})
+
+	t.Run("file bytes", func(t *testing.T) {
+		resp, err := http.Get(testServer.URL + protocol.DefaultArtifactPath + "/task-1/1")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, mimeType, resp.Header.Get("Content-Type"))
+		assert.Equal(t, "nosniff", resp.Header.Get("X-Content-Type-Options"))
+		assert.Equal(t, `"3-raw"`, resp.Header.Get("ETag"))
+		assert.Equal(t, "bytes", resp.Header.Get("Accept-Ranges"))
+		data, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "binary content", string(data))
+
+		// Ranges are decoded from any offset.
+		for offset := int64(0); offset < 14; offset++ {
+			for length := int64(1); offset+length <= 14; length++ {
+				content, err := download(t, 1, client.WithCallRange(offset, length))
+				require.NoError(t, err)
+				assert.Equal(t, "binary content"[offset:offset+length], content)
+			}
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		content, err := download(t, 0, client.WithCallHeader("Accept", "application/json"))
+		require.NoError(t, err)
+		var artifact protocol.Artifact
+		require.NoError(t, json.Unmarshal([]byte(content), &artifact))
+		assert.Len(t, artifact.Parts, 2)
+
+		content, err = download(t, 2)
+		require.NoError(t, err, "data parts are only available as JSON")
+		assert.Contains(t, content, `"a":1`)
+
+		_, err = download(t, 2, client.WithCallHeader("Accept", "text/plain"))
+		assert.ErrorContains(t, err, "406")
+	})
+
+	t.Run("path", func(t *testing.T) {
+		a2aServer, err := NewA2AServer(defaultAgentCard(), tm, WithArtifactDownloads("/files/"))
+		require.NoError(t, err)
+		testServer := httptest.NewServer(a2aServer.Handler())
+		defer testServer.Close()
+		c, err := client.NewA2AClient(testServer.URL, client.WithArtifactPath("/files"))
+		require.NoError(t, err)
+		content, err := c.DownloadArtifact(context.Background(), "task-1", 0)
+		require.NoError(t, err)
+		defer content.Close()
+		data, err := io.ReadAll(content)
+		require.NoError(t, err)
+		assert.Equal(t, "hello, world", string(data))
+	})
+
+	t.Run("compressed", func(t *testing.T) {
+		large := strings.Repeat("a2a ", compressionMinSize)
+		tm.tasks["task-2"] = &protocol.Task{
+			ID:        "task-2",
+			Version:   1,
+			Artifacts: []protocol.Artifact{{Parts: []protocol.Part{protocol.NewTextPart(large)}}},
+		}
+		a2aServer, err := NewA2AServer(defaultAgentCard(), tm, WithArtifactDownloads(""), WithCompression(-1))
+		require.NoError(t, err)
+		testServer := httptest.NewServer(a2aServer.Handler())
+		defer testServer.Close()
+		get := func(acceptEncoding string) *http.Response {
+			req, err := http.NewRequest(http.MethodGet, testServer.URL+protocol.DefaultArtifactPath+"/task-2/0", nil)
+			require.NoError(t, err)
+			req.Header.Set("Accept-Encoding", acceptEncoding)
+			resp, err := http.DefaultTransport.RoundTrip(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+			return resp
+		}
+		// Each encoding has its own validator.
+		compressed := get("gzip")
+		assert.Equal(t, "gzip", compressed.Header.Get("Content-Encoding"))
+		assert.Equal(t, `"1-raw-gzip"`, compressed.Header.Get("ETag"))
+		assert.ElementsMatch(t, []string{"Accept-Encoding", "Accept"}, compressed.Header.Values("Vary"))
+		identity := get("identity")
+		assert.Empty(t, identity.Header.Get("Content-Encoding"))
+		assert.Equal(t, `"1-raw"`, identity.Header.Get("ETag"))
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := download(t, 5)
+		assert.ErrorContains(t, err, "404")
+		_, err = c.DownloadArtifact(context.Background(), "missing", 0)
+		assert.ErrorContains(t, err, "404")
+	})
+}
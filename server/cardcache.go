@@ -0,0 +1,36 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// cardValidators derives the validators of the agent card served, for the
+// conditional requests of the clients caching it: the ETag is a hash of the
+// encoded card, and the card is considered modified when its ETag changes.
+type cardValidators struct {
+	mu       sync.Mutex
+	etag     string
+	modified time.Time
+}
+
+// validate returns the ETag and modification time of the encoded card body.
+func (v *cardValidators) validate(body []byte) (string, time.Time) {
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if etag != v.etag {
+		v.etag = etag
+		v.modified = time.Now().UTC()
+	}
+	return v.etag, v.modified
+}
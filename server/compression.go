@@ -0,0 +1,263 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+const (
+	encodingGzip    = "gzip"
+	encodingDeflate = "deflate"
+
+	// compressionMinSize is the response size below which compressing is not worth
+	// the overhead. SSE streams are always compressed once negotiated.
+	compressionMinSize = 1024
+)
+
+// flushWriteCloser is implemented by both gzip.Writer and zlib.Writer.
+type flushWriteCloser interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// compressor negotiates response compression and pools the encoders.
+type compressor struct {
+	level int
+	pools map[string]*sync.Pool
+}
+
+// newCompressor creates a compressor using the given compress/flate level.
+func newCompressor(level int) (*compressor, error) {
+	// Validate the level once up front so the pools cannot fail.
+	if _, err := gzip.NewWriterLevel(io.Discard, level); err != nil {
+		return nil, err
+	}
+	c := &compressor{level: level}
+	c.pools = map[string]*sync.Pool{
+		encodingGzip: {New: func() interface{} {
+			w, _ := gzip.NewWriterLevel(io.Discard, c.level)
+			return w
+		}},
+		encodingDeflate: {New: func() interface{} {
+			w, _ := zlib.NewWriterLevel(io.Discard, c.level)
+			return w
+		}},
+	}
+	return c, nil
+}
+
+// wrap returns a handler compressing the responses of next for clients that accept it.
+func (c *compressor) wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+		if encoding == "" || r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+		cw := &compressResponseWriter{ResponseWriter: w, compressor: c, encoding: encoding}
+		defer cw.close()
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// negotiateEncoding picks gzip or deflate from an Accept-Encoding header
+// according to the quality values, preferring gzip on ties.
+// It returns an empty string if neither is acceptable.
+func negotiateEncoding(header string) string {
+	if header == "" {
+		return ""
+	}
+	quality := map[string]float64{}
+	wildcard := -1.0
+	for _, item := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(item), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if name == "*" {
+			wildcard = q
+		} else {
+			quality[name] = q
+		}
+	}
+	best, bestQ := "", 0.0
+	for _, encoding := range []string{encodingGzip, encodingDeflate} {
+		q, ok := quality[encoding]
+		if !ok {
+			q = wildcard
+		}
+		if q > bestQ {
+			best, bestQ = encoding, q
+		}
+	}
+	return best
+}
+
+// compressResponseWriter compresses the response body once it is known to be
+// worth it: immediately for SSE streams, and after compressionMinSize bytes otherwise.
+type compressResponseWriter struct {
+	http.ResponseWriter
+	compressor *compressor
+	encoding   string
+
+	status  int
+	decided bool             // Whether headers were sent and compression decided.
+	pending []byte           // Body buffered until the decision is made.
+	encoder flushWriteCloser // Non-nil if the body is being compressed.
+}
+
+// WriteHeader records the status code. It is sent with the first body bytes,
+// once the Content-Encoding is known.
+func (w *compressResponseWriter) WriteHeader(status int) {
+	if w.decided || w.status != 0 {
+		return
+	}
+	w.status = status
+}
+
+// Write implements http.ResponseWriter.
+func (w *compressResponseWriter) Write(p []byte) (int, error) {
+	if !w.decided {
+		if !w.compressible() {
+			w.start(false)
+		} else if w.isStream() || len(w.pending)+len(p) >= compressionMinSize {
+			w.start(true)
+		} else {
+			w.pending = append(w.pending, p...)
+			return len(p), nil
+		}
+		if err := w.writePending(); err != nil {
+			return 0, err
+		}
+	}
+	if w.encoder != nil {
+		return w.encoder.Write(p)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// Flush implements http.Flusher, flushing compressed data to the client.
+func (w *compressResponseWriter) Flush() {
+	if !w.decided {
+		w.start(w.compressible() && w.isStream())
+		if err := w.writePending(); err != nil {
+			return
+		}
+	}
+	if w.encoder != nil {
+		if err := w.encoder.Flush(); err != nil {
+			return
+		}
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying writer for http.ResponseController.
+func (w *compressResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// compressible reports whether the response may be compressed at all. Partial
+// content is not: its Content-Range counts the bytes of the uncompressed body.
+func (w *compressResponseWriter) compressible() bool {
+	h := w.Header()
+	if h.Get("Content-Encoding") != "" || h.Get("Content-Range") != "" {
+		return false
+	}
+	status := w.status
+	return status != http.StatusNoContent && status != http.StatusNotModified &&
+		status != http.StatusPartialContent && (status == 0 || status >= http.StatusOK)
+}
+
+// isStream reports whether the response is an SSE or NDJSON event stream.
+func (w *compressResponseWriter) isStream() bool {
+	contentType := w.Header().Get("Content-Type")
+	return strings.HasPrefix(contentType, protocol.ContentTypeSSE) ||
+		strings.HasPrefix(contentType, protocol.ContentTypeNDJSON)
+}
+
+// start sends the headers, switching to compressed output if requested.
+func (w *compressResponseWriter) start(compress bool) {
+	w.decided = true
+	if compress {
+		h := w.Header()
+		h.Set("Content-Encoding", w.encoding)
+		h.Del("Content-Length")
+		if etag := h.Get("ETag"); etag != "" {
+			h.Set("ETag", encodedETag(etag, w.encoding))
+		}
+		w.encoder = w.compressor.pools[w.encoding].Get().(flushWriteCloser)
+		w.encoder.Reset(w.ResponseWriter)
+	}
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+}
+
+// encodedETag returns etag for the representation of its response compressed
+// with encoding, so that validators of the compressed and uncompressed bodies
+// differ, as they must for If-Range and If-None-Match. Conditional requests
+// with the ETag of a compressed body thus never match, and get the full body.
+func encodedETag(etag, encoding string) string {
+	if !strings.HasSuffix(etag, `"`) || len(etag) < 2 {
+		return etag
+	}
+	return etag[:len(etag)-1] + "-" + encoding + `"`
+}
+
+// writePending writes the buffered body through the selected output.
+func (w *compressResponseWriter) writePending() error {
+	if len(w.pending) == 0 {
+		return nil
+	}
+	var err error
+	if w.encoder != nil {
+		_, err = w.encoder.Write(w.pending)
+	} else {
+		_, err = w.ResponseWriter.Write(w.pending)
+	}
+	w.pending = nil
+	return err
+}
+
+// close finishes the response, sending small bodies uncompressed.
+func (w *compressResponseWriter) close() {
+	if !w.decided {
+		if len(w.pending) == 0 && w.status == 0 {
+			return // Nothing was written; let net/http send its default response.
+		}
+		w.start(false)
+		_ = w.writePending()
+		return
+	}
+	if w.encoder != nil {
+		_ = w.encoder.Close()
+		w.encoder.Reset(io.Discard)
+		w.compressor.pools[w.encoding].Put(w.encoder)
+		w.encoder = nil
+	}
+}
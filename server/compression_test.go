@@ -0,0 +1,182 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/client"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", ""},
+		{"identity", ""},
+		{"gzip", "gzip"},
+		{"deflate", "deflate"},
+		{"deflate, gzip", "gzip"},
+		{"gzip;q=0.5, deflate", "deflate"},
+		{"gzip;q=0, *", "deflate"},
+		{"*;q=0", ""},
+		{"br, GZIP", "gzip"},
+	}
+	for _, tc := range tests {
+		assert.Equal(t, tc.want, negotiateEncoding(tc.header), "Accept-Encoding: %q", tc.header)
+	}
+}
+
+func TestCompressor_Wrap(t *testing.T) {
+	c, err := newCompressor(gzip.DefaultCompression)
+	require.NoError(t, err)
+	large := strings.Repeat("a2a ", compressionMinSize)
+	handler := c.wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = io.WriteString(w, r.URL.Query().Get("prefix"))
+		if r.URL.Query().Get("large") != "" {
+			_, _ = io.WriteString(w, large)
+		}
+	}))
+
+	serve := func(target, acceptEncoding string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, target, nil)
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("small responses are not compressed", func(t *testing.T) {
+		rec := serve("/?prefix=ok", "gzip")
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+		assert.Empty(t, rec.Header().Get("Content-Encoding"))
+		assert.Equal(t, "ok", rec.Body.String())
+		assert.Equal(t, "Accept-Encoding", rec.Header().Get("Vary"))
+	})
+
+	t.Run("gzip", func(t *testing.T) {
+		rec := serve("/?prefix=ok&large=1", "gzip")
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+		assert.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+		r, err := gzip.NewReader(rec.Body)
+		require.NoError(t, err)
+		body, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, "ok"+large, string(body))
+	})
+
+	t.Run("deflate", func(t *testing.T) {
+		rec := serve("/?large=1", "deflate")
+		assert.Equal(t, "deflate", rec.Header().Get("Content-Encoding"))
+		r, err := zlib.NewReader(rec.Body)
+		require.NoError(t, err)
+		body, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, large, string(body))
+	})
+
+	t.Run("not accepted", func(t *testing.T) {
+		rec := serve("/?large=1", "")
+		assert.Empty(t, rec.Header().Get("Content-Encoding"))
+		assert.Equal(t, large, rec.Body.String())
+	})
+}
+
+func TestCompressor_Range(t *testing.T) {
+	c, err := newCompressor(gzip.DefaultCompression)
+	require.NoError(t, err)
+	content := strings.Repeat("0123456789", compressionMinSize)
+	handler := c.wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "", time.Time{}, strings.NewReader(content))
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("Range", "bytes=10-4095")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusPartialContent, rec.Code)
+	assert.Empty(t, rec.Header().Get("Content-Encoding"), "ranges count uncompressed bytes")
+	assert.Equal(t, "bytes 10-4095/10240", rec.Header().Get("Content-Range"))
+	assert.Equal(t, content[10:4096], rec.Body.String())
+}
+
+func TestNewA2AServer_InvalidCompressionLevel(t *testing.T) {
+	_, err := NewA2AServer(defaultAgentCard(), newMockTaskManager(), WithCompression(42))
+	assert.Error(t, err)
+}
+
+func TestA2AServer_CompressedStream(t *testing.T) {
+	taskID := "compressed-stream"
+	mockTM := newMockTaskManager()
+	mockTM.SubscribeEvents = []protocol.TaskEvent{
+		protocol.TaskArtifactUpdateEvent{
+			ID:       taskID,
+			Artifact: protocol.Artifact{Parts: []protocol.Part{protocol.NewTextPart(strings.Repeat("x", 4096))}},
+		},
+		protocol.TaskStatusUpdateEvent{
+			ID:     taskID,
+			Status: protocol.TaskStatus{State: protocol.TaskStateCompleted},
+			Final:  true,
+		},
+	}
+	a2aServer, err := NewA2AServer(defaultAgentCard(), mockTM, WithCompression(gzip.BestSpeed))
+	require.NoError(t, err)
+
+	testServer := httptest.NewServer(a2aServer.Handler())
+	defer testServer.Close()
+
+	transport := &encodingRecorder{RoundTripper: http.DefaultTransport}
+	a2aClient, err := client.NewA2AClient(testServer.URL, client.WithHTTPClient(&http.Client{Transport: transport}))
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	events, err := a2aClient.StreamTask(ctx, protocol.SendTaskParams{
+		ID:      taskID,
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")}),
+	})
+	require.NoError(t, err)
+
+	var received []protocol.TaskEvent
+	for event := range events {
+		received = append(received, event)
+	}
+	require.Len(t, received, 2)
+	artifact, ok := received[0].(protocol.TaskArtifactUpdateEvent)
+	require.True(t, ok, "unexpected event type %T", received[0])
+	assert.Len(t, artifact.Artifact.Parts[0].(protocol.TextPart).Text, 4096)
+	assert.True(t, received[1].IsFinal())
+	assert.Equal(t, "gzip", transport.encoding)
+}
+
+// encodingRecorder records the Content-Encoding of the last response.
+type encodingRecorder struct {
+	http.RoundTripper
+	encoding string
+}
+
+func (r *encodingRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := r.RoundTripper.RoundTrip(req)
+	if err == nil {
+		r.encoding = resp.Header.Get("Content-Encoding")
+	}
+	return resp, err
+}
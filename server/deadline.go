@@ -0,0 +1,36 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"context"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// applyDeadlineBudget bounds ctx by the deadline budget sent in the params of a
+// send request, so that the processor, and any client call it delegates with
+// ctx, stops when the originator's deadline passes. Requests whose budget is
+// less than the minimum deadline budget are rejected. The returned cancel
+// function must be called once the request is handled.
+func (s *A2AServer) applyDeadlineBudget(
+	ctx context.Context,
+	params protocol.SendTaskParams,
+) (context.Context, context.CancelFunc, *jsonrpc.Error) {
+	budget, ok := protocol.DeadlineBudgetFromMetadata(params.Metadata)
+	if !ok {
+		return ctx, func() {}, nil
+	}
+	minBudget := s.currentMinDeadlineBudget()
+	if budget <= 0 || budget < minBudget {
+		return ctx, nil, taskmanager.ErrDeadlineBudgetExhausted(params.ID, budget, minBudget)
+	}
+	ctx, cancel := context.WithTimeout(ctx, budget)
+	return ctx, cancel, nil
+}
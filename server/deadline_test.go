@@ -0,0 +1,67 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// deadlineTaskManager records the deadline of the context seen by OnSendTask.
+type deadlineTaskManager struct {
+	*mockTaskManager
+	deadline    time.Time
+	hasDeadline bool
+}
+
+func (m *deadlineTaskManager) OnSendTask(
+	ctx context.Context,
+	params protocol.SendTaskParams,
+) (*protocol.Task, error) {
+	m.deadline, m.hasDeadline = ctx.Deadline()
+	return &protocol.Task{ID: params.ID, Status: protocol.TaskStatus{State: protocol.TaskStateWorking}}, nil
+}
+
+func TestA2AServer_DeadlineBudget(t *testing.T) {
+	tm := &deadlineTaskManager{mockTaskManager: newMockTaskManager()}
+	a2aServer, err := NewA2AServer(defaultAgentCard(), tm, WithMinDeadlineBudget(time.Second))
+	require.NoError(t, err)
+	testServer := httptest.NewServer(http.HandlerFunc(a2aServer.handleJSONRPC))
+	defer testServer.Close()
+
+	msg := protocol.Message{Role: protocol.MessageRoleUser, Parts: []protocol.Part{protocol.NewTextPart("hi")}}
+	send := func(metadata map[string]interface{}) int {
+		params := protocol.SendTaskParams{ID: "task-1", Message: msg, Metadata: metadata}
+		resp := performJSONRPCRequest(t, testServer, protocol.MethodTasksSend, params, "task-1")
+		if resp.Error != nil {
+			return resp.Error.Code
+		}
+		return 0
+	}
+
+	code := send(nil)
+	assert.Zero(t, code)
+	assert.False(t, tm.hasDeadline, "requests without budget keep the request deadline")
+
+	start := time.Now()
+	code = send(protocol.SetDeadlineBudget(nil, 10*time.Second))
+	assert.Zero(t, code)
+	require.True(t, tm.hasDeadline)
+	assert.WithinDuration(t, start.Add(10*time.Second), tm.deadline, time.Second)
+
+	code = send(protocol.SetDeadlineBudget(nil, 500*time.Millisecond))
+	assert.Equal(t, taskmanager.ErrCodeDeadlineBudgetExhausted, code)
+}
@@ -0,0 +1,152 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// taskVersionPrefix starts the versions of tasks, identifying their format.
+const taskVersionPrefix = "v1"
+
+// taskVersion is the version of a task returned by tasks/get. It records the
+// lengths and digests of the history and artifacts of the task, so that a
+// later tasks/get can tell whether they were only appended to, and the digest
+// of the rest of the task.
+type taskVersion struct {
+	history         int
+	historyDigest   string
+	artifacts       int
+	artifactsDigest string
+	stateDigest     string
+}
+
+// String encodes v, as sent in MetadataKeyTaskVersion.
+func (v taskVersion) String() string {
+	return strings.Join([]string{
+		taskVersionPrefix,
+		strconv.Itoa(v.history), v.historyDigest,
+		strconv.Itoa(v.artifacts), v.artifactsDigest,
+		v.stateDigest,
+	}, ".")
+}
+
+// parseTaskVersion decodes a version encoded by taskVersion.String.
+func parseTaskVersion(s string) (taskVersion, bool) {
+	fields := strings.Split(s, ".")
+	if len(fields) != 6 || fields[0] != taskVersionPrefix {
+		return taskVersion{}, false
+	}
+	history, err1 := strconv.Atoi(fields[1])
+	artifacts, err2 := strconv.Atoi(fields[3])
+	if err1 != nil || err2 != nil || history < 0 || artifacts < 0 {
+		return taskVersion{}, false
+	}
+	return taskVersion{
+		history:         history,
+		historyDigest:   fields[2],
+		artifacts:       artifacts,
+		artifactsDigest: fields[4],
+		stateDigest:     fields[5],
+	}, true
+}
+
+// versionDigest returns a short digest of the JSON encoding of v.
+func versionDigest(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:9]), nil
+}
+
+// computeTaskVersion returns the version of task, ignoring the metadata of
+// the response.
+func computeTaskVersion(task *protocol.Task) (taskVersion, error) {
+	v := taskVersion{history: len(task.History), artifacts: len(task.Artifacts)}
+	var err error
+	if v.historyDigest, err = versionDigest(task.History); err != nil {
+		return taskVersion{}, err
+	}
+	if v.artifactsDigest, err = versionDigest(task.Artifacts); err != nil {
+		return taskVersion{}, err
+	}
+	metadata := make(map[string]interface{}, len(task.Metadata))
+	for k, val := range task.Metadata {
+		switch k {
+		case protocol.MetadataKeyTaskVersion, protocol.MetadataKeyTaskDelta, protocol.MetadataKeyWarnings:
+		default:
+			metadata[k] = val
+		}
+	}
+	v.stateDigest, err = versionDigest(struct {
+		SessionID *string                `json:"sessionId"`
+		Status    protocol.TaskStatus    `json:"status"`
+		Metadata  map[string]interface{} `json:"metadata"`
+	}{task.SessionID, task.Status, metadata})
+	if err != nil {
+		return taskVersion{}, err
+	}
+	return v, nil
+}
+
+// withTaskVersion returns task, as returned by tasks/get, as is if since is
+// empty, and otherwise a copy with its version in its metadata and, unless
+// since is protocol.TaskVersionAny, as a TaskDelta from since. The version is
+// only computed when requested, as it digests the history and artifacts.
+func withTaskVersion(task *protocol.Task, since string) *protocol.Task {
+	if since == "" {
+		return task
+	}
+	version, err := computeTaskVersion(task)
+	if err != nil {
+		log.Warnf("Failed to compute the version of task %s: %v", task.ID, err)
+		return task
+	}
+	versioned := *task
+	versioned.Metadata = make(map[string]interface{}, len(task.Metadata)+2)
+	for k, v := range task.Metadata {
+		versioned.Metadata[k] = v
+	}
+	versioned.Metadata[protocol.MetadataKeyTaskVersion] = version.String()
+	if since == protocol.TaskVersionAny {
+		return &versioned
+	}
+	delta := protocol.TaskDelta{Since: since}
+	if since == version.String() {
+		delta.NotModified = true
+		versioned.History, versioned.Artifacts = nil, nil
+	} else if prev, ok := parseTaskVersion(since); ok {
+		// The history and artifacts only appended to since are sent from
+		// their length at since, and otherwise replaced.
+		if n := prev.history; n > 0 && n <= len(task.History) && hasDigest(task.History[:n], prev.historyDigest) {
+			delta.HistoryOffset = n
+			versioned.History = task.History[n:]
+		}
+		if n := prev.artifacts; n > 0 && n <= len(task.Artifacts) &&
+			hasDigest(task.Artifacts[:n], prev.artifactsDigest) {
+			delta.ArtifactOffset = n
+			versioned.Artifacts = task.Artifacts[n:]
+		}
+	}
+	versioned.Metadata[protocol.MetadataKeyTaskDelta] = delta
+	return &versioned
+}
+
+// hasDigest reports whether the digest of v is digest.
+func hasDigest(v interface{}, digest string) bool {
+	d, err := versionDigest(v)
+	return err == nil && d == digest
+}
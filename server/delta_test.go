@@ -0,0 +1,104 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/client"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// conversationProcessor answers each message with an artifact and asks for
+// more input.
+type conversationProcessor struct{}
+
+func (conversationProcessor) Process(
+	ctx context.Context,
+	taskID string,
+	msg protocol.Message,
+	handle taskmanager.TaskHandle,
+) error {
+	if err := handle.AddArtifact(protocol.Artifact{Parts: msg.Parts}); err != nil {
+		return err
+	}
+	return handle.UpdateStatus(protocol.TaskStateInputRequired, nil)
+}
+
+func TestA2AServer_TaskDelta(t *testing.T) {
+	tm, err := taskmanager.NewMemoryTaskManager(conversationProcessor{})
+	require.NoError(t, err)
+	a2aServer, err := NewA2AServer(defaultAgentCard(), tm)
+	require.NoError(t, err)
+	testServer := httptest.NewServer(a2aServer.Handler())
+	defer testServer.Close()
+	c, err := client.NewA2AClient(testServer.URL)
+	require.NoError(t, err)
+	ctx := context.Background()
+	send := func(text string) {
+		_, err := c.SendTasks(ctx, protocol.SendTaskParams{
+			ID:      "chat",
+			Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart(text)}),
+		})
+		require.NoError(t, err)
+	}
+	historyLength := 0
+	get := func(since string) *protocol.Task {
+		task, err := c.GetTasks(ctx, protocol.TaskQueryParams{
+			ID: "chat", HistoryLength: &historyLength, IfModifiedSince: since,
+		})
+		require.NoError(t, err)
+		return task
+	}
+
+	send("first")
+	assert.Empty(t, protocol.TaskVersion(get("")), "versions are only computed when requested")
+	base := get(protocol.TaskVersionAny)
+	version := protocol.TaskVersion(base)
+	require.NotEmpty(t, version)
+	assert.Nil(t, protocol.TaskDeltaFromMetadata(base.Metadata))
+	require.Len(t, base.History, 1)
+	require.Len(t, base.Artifacts, 1)
+
+	unchanged := get(version)
+	delta := protocol.TaskDeltaFromMetadata(unchanged.Metadata)
+	require.NotNil(t, delta)
+	assert.True(t, delta.NotModified)
+	assert.Empty(t, unchanged.History)
+	assert.Empty(t, unchanged.Artifacts)
+	assert.Equal(t, version, protocol.TaskVersion(unchanged))
+
+	send("second")
+	changed := get(version)
+	delta = protocol.TaskDeltaFromMetadata(changed.Metadata)
+	require.NotNil(t, delta)
+	assert.False(t, delta.NotModified)
+	assert.Equal(t, 1, delta.HistoryOffset)
+	assert.Equal(t, 1, delta.ArtifactOffset)
+	require.Len(t, changed.History, 1, "only the new message is sent")
+	require.Len(t, changed.Artifacts, 1, "only the new artifact is sent")
+
+	merged := protocol.MergeTaskDelta(base, changed)
+	full := get(protocol.TaskVersionAny)
+	assert.Equal(t, full.History, merged.History)
+	assert.Equal(t, full.Artifacts, merged.Artifacts)
+	assert.Equal(t, full.Metadata, merged.Metadata)
+
+	// Unknown versions return the whole task.
+	replaced := get("v0.unknown")
+	delta = protocol.TaskDeltaFromMetadata(replaced.Metadata)
+	require.NotNil(t, delta)
+	assert.Zero(t, delta.HistoryOffset)
+	assert.Len(t, replaced.History, 2)
+	assert.Len(t, replaced.Artifacts, 2)
+}
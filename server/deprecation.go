@@ -0,0 +1,84 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// signalDeprecations reports the deprecated method and params fields used by
+// request as warnings of the request. Deprecated methods are also signaled with
+// the Deprecation and Sunset HTTP headers, so that responses carrying no task
+// report them too.
+func (s *A2AServer) signalDeprecations(ctx context.Context, w http.ResponseWriter, request jsonrpc.Request) {
+	var (
+		params  interface{}
+		decoded bool
+	)
+	for _, d := range s.deprecations {
+		if d.Method != request.Method {
+			continue
+		}
+		if d.Field == "" {
+			w.Header().Set(protocol.DeprecationHeader, "true")
+			if !d.Sunset.IsZero() {
+				w.Header().Set(protocol.SunsetHeader, d.Sunset.UTC().Format(http.TimeFormat))
+			}
+		} else {
+			if !decoded {
+				decoded = true
+				if err := json.Unmarshal(request.Params, &params); err != nil {
+					params = nil
+				}
+			}
+			if !pointerExists(params, d.Field) {
+				continue
+			}
+		}
+		log.Debugf("Request (ID: %v) uses deprecated feature: %s", request.ID, d)
+		taskmanager.Warn(ctx, d.Warning())
+	}
+}
+
+// pointerExists reports whether the RFC 6901 JSON pointer resolves to a value in doc.
+func pointerExists(doc interface{}, pointer string) bool {
+	if pointer == "" {
+		return true
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return false
+	}
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch v := doc.(type) {
+		case map[string]interface{}:
+			next, ok := v[token]
+			if !ok {
+				return false
+			}
+			doc = next
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(v) {
+				return false
+			}
+			doc = v[i]
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,83 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/client"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+func TestA2AServer_Deprecations(t *testing.T) {
+	tm, err := taskmanager.NewMemoryTaskManager(&countingProcessor{})
+	require.NoError(t, err)
+	sunset := time.Date(2030, 1, 2, 0, 0, 0, 0, time.UTC)
+	a2aServer, err := NewA2AServer(defaultAgentCard(), tm, WithDeprecations(
+		protocol.Deprecation{Method: protocol.MethodTasksSend, Field: "/historyLength"},
+		protocol.Deprecation{Method: protocol.MethodTasksGet, Replacement: "tasks/resubscribe", Sunset: sunset},
+	))
+	require.NoError(t, err)
+	testServer := httptest.NewServer(a2aServer.Handler())
+	defer testServer.Close()
+
+	type notice struct {
+		method  string
+		warning protocol.Warning
+	}
+	var notices []notice
+	c, err := client.NewA2AClient(testServer.URL, client.WithDeprecationHandler(
+		func(method string, warning protocol.Warning) {
+			notices = append(notices, notice{method, warning})
+		},
+	))
+	require.NoError(t, err)
+	ctx := context.Background()
+	msg := protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")})
+
+	historyLength := 1
+	for i := 0; i < 2; i++ {
+		task, err := c.SendTasks(ctx, protocol.SendTaskParams{
+			ID: fmt.Sprintf("deprecated-%d", i), Message: msg, HistoryLength: &historyLength,
+		})
+		require.NoError(t, err)
+		assert.Len(t, protocol.TaskWarnings(task), 1, "the warning comes with every response")
+	}
+	task, err := c.SendTasks(ctx, protocol.SendTaskParams{ID: "task-2", Message: msg})
+	require.NoError(t, err)
+	assert.Empty(t, protocol.TaskWarnings(task))
+	require.Len(t, notices, 1, "each deprecation is reported once")
+	assert.Equal(t, protocol.MethodTasksSend, notices[0].method)
+	assert.Equal(t, "/historyLength", notices[0].warning.Pointer)
+
+	for i := 0; i < 2; i++ {
+		_, err = c.GetTasks(ctx, protocol.TaskQueryParams{ID: "deprecated-0"})
+		require.NoError(t, err)
+	}
+	require.Len(t, notices, 2)
+	assert.Equal(t, protocol.MethodTasksGet, notices[1].method)
+	assert.Contains(t, notices[1].warning.Message, "2030-01-02")
+}
+
+func TestPointerExists(t *testing.T) {
+	doc := map[string]interface{}{
+		"message": map[string]interface{}{"parts": []interface{}{map[string]interface{}{"a/b": 1}}},
+	}
+	assert.True(t, pointerExists(doc, ""))
+	assert.True(t, pointerExists(doc, "/message/parts/0/a~1b"))
+	assert.False(t, pointerExists(doc, "/message/parts/1"))
+	assert.False(t, pointerExists(doc, "/metadata"))
+	assert.False(t, pointerExists(doc, "message"))
+}
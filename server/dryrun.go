@@ -0,0 +1,43 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// planTask handles a dry run of tasks/send, whose params have already passed
+// validation and the deadline budget and provenance checks. It returns the task
+// as it would be submitted, with the plan of the task manager, if it implements
+// taskmanager.TaskPlanner, completed with the deadline budget and warnings of
+// params. The processor is not run and no task is stored.
+func (s *A2AServer) planTask(ctx context.Context, params protocol.SendTaskParams) (*protocol.Task, *jsonrpc.Error) {
+	plan := &protocol.TaskPlan{}
+	if planner, ok := s.taskManager.(taskmanager.TaskPlanner); ok {
+		var err error
+		if plan, err = planner.PlanTask(ctx, params); err != nil {
+			log.Infof("Dry run of task %s failed: %v", params.ID, err)
+			if rpcErr, ok := err.(*jsonrpc.Error); ok {
+				return nil, rpcErr
+			}
+			return nil, jsonrpc.ErrInternalError(fmt.Sprintf("task planning failed: %v", err))
+		}
+	}
+	if budget, ok := protocol.DeadlineBudgetFromMetadata(params.Metadata); ok && plan.DeadlineBudgetMs == 0 {
+		plan.DeadlineBudgetMs = budget.Milliseconds()
+	}
+	plan.Warnings = append(protocol.WarningsFromMetadata(params.Metadata), plan.Warnings...)
+	task := protocol.NewTask(params.ID, params.SessionID)
+	task.Metadata = map[string]interface{}{protocol.MetadataKeyTaskPlan: plan}
+	return task, nil
+}
@@ -0,0 +1,86 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// countingProcessor counts the tasks it processes.
+type countingProcessor struct {
+	calls int
+}
+
+func (p *countingProcessor) Process(
+	ctx context.Context,
+	taskID string,
+	message protocol.Message,
+	handle taskmanager.TaskHandle,
+) error {
+	p.calls++
+	return handle.UpdateStatus(protocol.TaskStateCompleted, nil)
+}
+
+func TestA2AServer_DryRun(t *testing.T) {
+	processor := &countingProcessor{}
+	words := taskmanager.TokenizerFunc(func(text string) (int, error) {
+		return len(strings.Fields(text)), nil
+	})
+	tm, err := taskmanager.NewMemoryTaskManager(processor, taskmanager.WithTokenAccounting(
+		taskmanager.TokenAccounting{Tokenizer: words, MaxTaskTokens: 3},
+	))
+	require.NoError(t, err)
+	a2aServer, err := NewA2AServer(defaultAgentCard(), tm)
+	require.NoError(t, err)
+	testServer := httptest.NewServer(http.HandlerFunc(a2aServer.handleJSONRPC))
+	defer testServer.Close()
+
+	params := protocol.SendTaskParams{
+		ID:       "task-1",
+		Message:  protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hello there")}),
+		Metadata: protocol.SetDeadlineBudget(nil, 5*time.Second),
+		DryRun:   true,
+	}
+	resp := performJSONRPCRequest(t, testServer, protocol.MethodTasksSend, params, "task-1")
+	require.Nil(t, resp.Error)
+	resultBytes, err := json.Marshal(resp.Result)
+	require.NoError(t, err)
+	var task protocol.Task
+	require.NoError(t, json.Unmarshal(resultBytes, &task))
+	assert.Equal(t, protocol.TaskStateSubmitted, task.Status.State)
+	plan, ok := protocol.TaskPlanFromMetadata(task.Metadata)
+	require.True(t, ok)
+	assert.Equal(t, 2, plan.InputTokens)
+	assert.Equal(t, 3, plan.MaxTaskTokens)
+	assert.InDelta(t, 5000, plan.DeadlineBudgetMs, 100)
+	assert.Zero(t, processor.calls, "dry runs do not process the task")
+	_, err = tm.OnGetTask(context.Background(), protocol.TaskQueryParams{ID: "task-1"})
+	assert.Error(t, err, "dry runs do not store the task")
+
+	// Requests failing the checks fail their dry run the same way.
+	params.Message.Parts = []protocol.Part{protocol.NewTextPart("far too many words here")}
+	resp = performJSONRPCRequest(t, testServer, protocol.MethodTasksSend, params, "task-1")
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, taskmanager.ErrCodeTokenBudgetExceeded, resp.Error.Code)
+
+	resp = performJSONRPCRequest(t, testServer, protocol.MethodTasksSendSubscribe, params, "task-1")
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, jsonrpc.CodeInvalidParams, resp.Error.Code)
+}
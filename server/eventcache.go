@@ -0,0 +1,110 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"reflect"
+	"sync"
+
+	"trpc.group/trpc-go/trpc-a2a-go/codec"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// defaultEventCacheSize is the number of encoded events kept by an eventCache.
+const defaultEventCacheSize = 256
+
+// eventKey identifies a published task event by the ID its task manager
+// numbered it with.
+type eventKey struct {
+	kind    byte
+	taskID  string
+	eventID string
+}
+
+// cacheEntry holds an encoded event, and the event to check that a later
+// event with the same key is the same.
+type cacheEntry struct {
+	event interface{}
+	data  []byte
+}
+
+// eventCache encodes task events once and shares the result between all the
+// streams delivering the same event, identified by its task and event ID, see
+// protocol.EventID. Events without ID are encoded every time. It is safe for
+// concurrent use.
+type eventCache struct {
+	codec codec.Codec
+
+	mu      sync.Mutex
+	entries map[eventKey]cacheEntry
+	order   []eventKey // Ring buffer of keys in insertion order.
+	next    int
+}
+
+// newEventCache creates a cache of up to size encoded events using c.
+// A non-positive size selects a default.
+func newEventCache(c codec.Codec, size int) *eventCache {
+	if size <= 0 {
+		size = defaultEventCacheSize
+	}
+	return &eventCache{
+		codec:   codec.OrDefault(c),
+		entries: make(map[eventKey]cacheEntry, size),
+		order:   make([]eventKey, 0, size),
+	}
+}
+
+// encode returns the encoding of event, reusing a previous encoding of the same
+// published event when available. The returned slice must not be modified.
+func (c *eventCache) encode(event interface{}) ([]byte, error) {
+	key, ok := keyOf(event)
+	if !ok {
+		return c.codec.Marshal(event)
+	}
+	c.mu.Lock()
+	entry, hit := c.entries[key]
+	c.mu.Unlock()
+	// Comparing the events guards against event IDs numbered again, e.g. for
+	// a task created again after it was deleted, and is cheaper than encoding.
+	if hit && reflect.DeepEqual(entry.event, event) {
+		return entry.data, nil
+	}
+	data, err := c.codec.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, exists := c.entries[key]; exists {
+		c.entries[key] = cacheEntry{event: event, data: data}
+	} else {
+		if len(c.order) < cap(c.order) {
+			c.order = append(c.order, key)
+		} else {
+			delete(c.entries, c.order[c.next])
+			c.order[c.next] = key
+			c.next = (c.next + 1) % len(c.order)
+		}
+		c.entries[key] = cacheEntry{event: event, data: data}
+	}
+	return data, nil
+}
+
+// keyOf returns the cache key of the supported task event types, or false if
+// event is not numbered.
+func keyOf(event interface{}) (eventKey, bool) {
+	var key eventKey
+	switch e := event.(type) {
+	case protocol.TaskStatusUpdateEvent:
+		key = eventKey{kind: 's', taskID: e.ID, eventID: protocol.EventID(e)}
+	case protocol.TaskArtifactUpdateEvent:
+		key = eventKey{kind: 'a', taskID: e.ID, eventID: protocol.EventID(e)}
+	default:
+		return eventKey{}, false
+	}
+	return key, key.eventID != ""
+}
@@ -0,0 +1,69 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/sse"
+)
+
+func TestEventCache(t *testing.T) {
+	counter := &countingCodec{}
+	cache := newEventCache(counter, 2)
+	msg := &protocol.Message{Role: protocol.MessageRoleAgent, Parts: []protocol.Part{protocol.NewTextPart("hi")}}
+	event := protocol.WithEventID(protocol.TaskStatusUpdateEvent{
+		ID:     "t1",
+		Status: protocol.TaskStatus{State: protocol.TaskStateWorking, Message: msg},
+	}, "1")
+
+	first, err := cache.encode(event)
+	require.NoError(t, err)
+	// Streams may deliver copies of the event.
+	second, err := cache.encode(protocol.WithEventID(protocol.TaskStatusUpdateEvent{
+		ID: "t1",
+		Status: protocol.TaskStatus{State: protocol.TaskStateWorking, Message: &protocol.Message{
+			Role: protocol.MessageRoleAgent, Parts: []protocol.Part{protocol.NewTextPart("hi")},
+		}},
+	}, "1"))
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+	assert.Equal(t, 1, counter.marshal, "identical events should be marshaled once")
+
+	// A different event with the same ID, e.g. of a task created again, is
+	// encoded again.
+	other := event.(protocol.TaskStatusUpdateEvent)
+	other.Status.Message = &protocol.Message{Role: protocol.MessageRoleAgent, Parts: []protocol.Part{protocol.NewTextPart("bye")}}
+	data, err := cache.encode(other)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "bye")
+	assert.Equal(t, 2, counter.marshal)
+
+	// Events without ID and unknown event types are always marshaled.
+	unnumbered := protocol.TaskStatusUpdateEvent{ID: "t1"}
+	_, err = cache.encode(unnumbered)
+	require.NoError(t, err)
+	_, err = cache.encode(unnumbered)
+	require.NoError(t, err)
+	_, err = cache.encode(sse.CloseEventData{TaskID: "t1"})
+	require.NoError(t, err)
+	assert.Equal(t, 5, counter.marshal)
+
+	// Old entries are evicted once the cache is full.
+	for _, id := range []string{"2", "3"} {
+		_, err = cache.encode(protocol.WithEventID(
+			protocol.TaskArtifactUpdateEvent{ID: "t1", Artifact: protocol.Artifact{Parts: msg.Parts}}, id))
+		require.NoError(t, err)
+	}
+	_, err = cache.encode(other)
+	require.NoError(t, err)
+	assert.Equal(t, 8, counter.marshal)
+}
@@ -0,0 +1,127 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// defaultExampleTimeout bounds how long a skill example may run.
+const defaultExampleTimeout = 30 * time.Second
+
+// handleSkillsExamplesList handles the skills/examples/list extension method.
+func (s *A2AServer) handleSkillsExamplesList(ctx context.Context, w http.ResponseWriter, request jsonrpc.Request) {
+	result := protocol.ListSkillExamplesResult{Examples: []protocol.SkillExampleEntry{}}
+	for _, skill := range s.AgentCard().Skills {
+		for _, example := range skill.RunnableExamples {
+			result.Examples = append(result.Examples, protocol.SkillExampleEntry{
+				SkillID:      skill.ID,
+				SkillExample: example,
+			})
+		}
+	}
+	s.writeJSONRPCResponse(w, request.ID, result)
+}
+
+// handleSkillsExamplesRun handles the skills/examples/run extension method.
+// The example runs in a fresh in-memory task manager with a sandboxed context,
+// so it neither shows up in nor affects the tasks of the agent.
+func (s *A2AServer) handleSkillsExamplesRun(ctx context.Context, w http.ResponseWriter, request jsonrpc.Request) {
+	if s.exampleProcessor == nil {
+		s.writeJSONRPCError(w, request.ID,
+			jsonrpc.ErrMethodNotFound("running skill examples is not enabled on this agent"))
+		return
+	}
+	var params protocol.RunSkillExampleParams
+	if err := s.unmarshalParams(request.Params, &params); err != nil {
+		s.writeJSONRPCError(w, request.ID, err)
+		return
+	}
+	example, ok := s.findSkillExample(params.SkillID, params.ExampleID)
+	if !ok {
+		s.writeJSONRPCError(w, request.ID, jsonrpc.ErrInvalidParams(
+			fmt.Sprintf("skill %q has no example %q", params.SkillID, params.ExampleID)))
+		return
+	}
+	result, err := s.runSkillExample(ctx, params, example)
+	if err != nil {
+		s.writeJSONRPCError(w, request.ID, jsonrpc.ErrInternalError(err.Error()))
+		return
+	}
+	s.writeJSONRPCResponse(w, request.ID, result)
+}
+
+// findSkillExample returns the example exampleID of the skill skillID.
+func (s *A2AServer) findSkillExample(skillID, exampleID string) (protocol.SkillExample, bool) {
+	for _, skill := range s.AgentCard().Skills {
+		if skill.ID != skillID {
+			continue
+		}
+		for _, example := range skill.RunnableExamples {
+			if example.ID == exampleID {
+				return example, true
+			}
+		}
+	}
+	return protocol.SkillExample{}, false
+}
+
+// runSkillExample runs example in a sandbox and reports the outcome.
+func (s *A2AServer) runSkillExample(
+	ctx context.Context,
+	params protocol.RunSkillExampleParams,
+	example protocol.SkillExample,
+) (*protocol.RunSkillExampleResult, error) {
+	tm, err := taskmanager.NewMemoryTaskManager(s.exampleProcessor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create example task manager: %w", err)
+	}
+	taskID, err := exampleTaskID(params)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(taskmanager.ContextWithSandbox(ctx), defaultExampleTimeout)
+	defer cancel()
+	start := time.Now()
+	task, err := tm.OnSendTask(ctx, protocol.SendTaskParams{ID: taskID, Message: example.Input})
+	result := &protocol.RunSkillExampleResult{
+		SkillID:    params.SkillID,
+		ExampleID:  params.ExampleID,
+		Task:       task,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	expected := example.ExpectedState
+	if expected == "" {
+		expected = protocol.TaskStateCompleted
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	result.Passed = task != nil && task.Status.State == expected
+	log.Infof("Ran example %s of skill %s in %dms (passed: %t)",
+		params.ExampleID, params.SkillID, result.DurationMs, result.Passed)
+	return result, nil
+}
+
+// exampleTaskID returns a unique ID for a run of a skill example.
+func exampleTaskID(params protocol.RunSkillExampleParams) (string, error) {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate example task ID: %w", err)
+	}
+	return fmt.Sprintf("example-%s-%s-%s", params.SkillID, params.ExampleID, hex.EncodeToString(b[:])), nil
+}
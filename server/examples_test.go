@@ -0,0 +1,106 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// exampleProcessor echoes text inputs and fails on "fail".
+type exampleProcessor struct {
+	sandboxed bool
+}
+
+func (p *exampleProcessor) Process(
+	ctx context.Context,
+	taskID string,
+	message protocol.Message,
+	handle taskmanager.TaskHandle,
+) error {
+	p.sandboxed = taskmanager.IsSandboxed(ctx)
+	text := message.Parts[0].(protocol.TextPart).Text
+	if text == "fail" {
+		return errors.New("example failed")
+	}
+	reply := protocol.NewMessage(protocol.MessageRoleAgent, []protocol.Part{protocol.NewTextPart(text)})
+	return handle.UpdateStatus(protocol.TaskStateCompleted, &reply)
+}
+
+func TestA2AServer_SkillExamples(t *testing.T) {
+	card := defaultAgentCard()
+	card.Skills = []AgentSkill{{
+		ID:   "echo",
+		Name: "Echo",
+		RunnableExamples: []protocol.SkillExample{
+			{ID: "hello", Input: protocol.NewMessage(protocol.MessageRoleUser,
+				[]protocol.Part{protocol.NewTextPart("hello")})},
+			{ID: "broken", Input: protocol.NewMessage(protocol.MessageRoleUser,
+				[]protocol.Part{protocol.NewTextPart("fail")})},
+		},
+	}}
+	processor := &exampleProcessor{}
+	mockTM := newMockTaskManager()
+	a2aServer, err := NewA2AServer(card, mockTM, WithSkillExamples(processor))
+	require.NoError(t, err)
+	testServer := httptest.NewServer(http.HandlerFunc(a2aServer.handleJSONRPC))
+	defer testServer.Close()
+
+	decode := func(resp *jsonrpc.Response, out interface{}) {
+		require.Nil(t, resp.Error)
+		data, err := json.Marshal(resp.Result)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(data, out))
+	}
+
+	var list protocol.ListSkillExamplesResult
+	decode(performJSONRPCRequest(t, testServer, protocol.MethodSkillsExamplesList, nil, "1"), &list)
+	require.Len(t, list.Examples, 2)
+	assert.Equal(t, "echo", list.Examples[0].SkillID)
+	assert.Equal(t, "hello", list.Examples[0].ID)
+
+	var result protocol.RunSkillExampleResult
+	params := protocol.RunSkillExampleParams{SkillID: "echo", ExampleID: "hello"}
+	decode(performJSONRPCRequest(t, testServer, protocol.MethodSkillsExamplesRun, params, "2"), &result)
+	assert.True(t, result.Passed)
+	require.NotNil(t, result.Task)
+	assert.Equal(t, protocol.TaskStateCompleted, result.Task.Status.State)
+	assert.True(t, processor.sandboxed)
+	assert.Empty(t, mockTM.tasks, "example tasks stay out of the agent's task manager")
+
+	params.ExampleID = "broken"
+	result = protocol.RunSkillExampleResult{}
+	decode(performJSONRPCRequest(t, testServer, protocol.MethodSkillsExamplesRun, params, "3"), &result)
+	assert.False(t, result.Passed)
+	assert.Equal(t, "example failed", result.Error)
+
+	params.ExampleID = "missing"
+	resp := performJSONRPCRequest(t, testServer, protocol.MethodSkillsExamplesRun, params, "4")
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, jsonrpc.CodeInvalidParams, resp.Error.Code)
+
+	// Running examples is opt-in.
+	plain, err := NewA2AServer(card, mockTM)
+	require.NoError(t, err)
+	plainServer := httptest.NewServer(http.HandlerFunc(plain.handleJSONRPC))
+	defer plainServer.Close()
+	params.ExampleID = "hello"
+	resp = performJSONRPCRequest(t, plainServer, protocol.MethodSkillsExamplesRun, params, "5")
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, jsonrpc.CodeMethodNotFound, resp.Error.Code)
+}
@@ -0,0 +1,62 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// FuzzValidateParams checks that the validation of hostile params only ever
+// fails with invalid params errors, and that the params accepted by dropping
+// invalid parts are then valid.
+func FuzzValidateParams(f *testing.F) {
+	methods := make([]string, 0, len(methodValidators))
+	for method := range methodValidators {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	for _, seed := range []string{
+		`{"id":"t1","message":{"role":"user","parts":[{"type":"text","text":"hi"}]}}`,
+		`{"id":"t1","message":{"role":"user","parts":[{"type":"text","text":"hi"},{"type":"file","file":{}}]}}`,
+		`{"id":"t1","message":{"role":"user","parts":[{"type":"data"}]},"metadata":null}`,
+		`{"id":"t1","pushNotificationConfig":{"url":"http://x","authentication":{"schemes":[1]}}}`,
+		`{"id":"g1","tasks":[{"task":{"id":"a"},"dependsOn":["a","b"]}]}`,
+		`{"id":1e999,"historyLength":-1}`,
+		`{"id":"\ud800","message":{"parts":{}}}`,
+		`[]`,
+		`null`,
+		``,
+	} {
+		for i := range methods {
+			f.Add(uint8(i), []byte(seed))
+		}
+	}
+	f.Fuzz(func(t *testing.T, index uint8, params []byte) {
+		method := methods[int(index)%len(methods)]
+		if err := validateParams(method, params); err != nil && err.Code != jsonrpc.CodeInvalidParams {
+			t.Fatalf("validation of %s failed with code %d", method, err.Code)
+		}
+		rewritten, err := checkParams(method, params, protocol.PartFailureAcceptValid)
+		if err != nil {
+			if err.Code != jsonrpc.CodeInvalidParams {
+				t.Fatalf("validation of %s failed with code %d", method, err.Code)
+			}
+			return
+		}
+		if !json.Valid(rewritten) {
+			t.Fatalf("invalid rewritten params %q", rewritten)
+		}
+		if err := validateParams(method, rewritten); err != nil {
+			t.Fatalf("rewritten params %s of %s are invalid: %v", rewritten, method, err.Data)
+		}
+	})
+}
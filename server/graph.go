@@ -0,0 +1,73 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// handleTasksGraphSend handles the tasks/graph/send extension method.
+func (s *A2AServer) handleTasksGraphSend(ctx context.Context, w http.ResponseWriter, request jsonrpc.Request) {
+	runner, ok := s.taskManager.(taskmanager.TaskGraphRunner)
+	if !ok {
+		s.writeJSONRPCError(w, request.ID,
+			jsonrpc.ErrMethodNotFound("task graphs are not supported by this agent"))
+		return
+	}
+	var params protocol.SendTaskGraphParams
+	if err := s.unmarshalParams(request.Params, &params); err != nil {
+		s.writeJSONRPCError(w, request.ID, err)
+		return
+	}
+	graph, err := runner.OnSendTaskGraph(ctx, params)
+	if err != nil {
+		s.writeTaskGraphError(w, request.ID, params.ID, "send", err)
+		return
+	}
+	s.writeJSONRPCResponse(w, request.ID, graph)
+}
+
+// handleTasksGraphGet handles the tasks/graph/get extension method.
+func (s *A2AServer) handleTasksGraphGet(ctx context.Context, w http.ResponseWriter, request jsonrpc.Request) {
+	runner, ok := s.taskManager.(taskmanager.TaskGraphRunner)
+	if !ok {
+		s.writeJSONRPCError(w, request.ID,
+			jsonrpc.ErrMethodNotFound("task graphs are not supported by this agent"))
+		return
+	}
+	var params protocol.TaskIDParams
+	if err := s.unmarshalParams(request.Params, &params); err != nil {
+		s.writeJSONRPCError(w, request.ID, err)
+		return
+	}
+	graph, err := runner.OnGetTaskGraph(ctx, params)
+	if err != nil {
+		s.writeTaskGraphError(w, request.ID, params.ID, "get", err)
+		return
+	}
+	s.writeJSONRPCResponse(w, request.ID, graph)
+}
+
+// writeTaskGraphError writes the error of a task graph operation, wrapping
+// errors that are not JSON-RPC errors as internal errors.
+func (s *A2AServer) writeTaskGraphError(w http.ResponseWriter, id interface{}, graphID, op string, err error) {
+	if rpcErr, ok := err.(*jsonrpc.Error); ok {
+		log.Errorf("Error calling %s for task graph %s: %v", op, graphID, rpcErr)
+		s.writeJSONRPCError(w, id, rpcErr)
+		return
+	}
+	log.Errorf("Unexpected error calling %s for task graph %s: %v", op, graphID, err)
+	s.writeJSONRPCError(w, id,
+		jsonrpc.ErrInternalError(fmt.Sprintf("failed to %s task graph: %v", op, err)))
+}
@@ -0,0 +1,65 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// messageParams holds the messages carried by the params of send methods:
+// the message of tasks/send and tasks/sendSubscribe, and those of the tasks
+// of tasks/graph/send.
+type messageParams struct {
+	Message *protocol.Message `json:"message"`
+	Tasks   []struct {
+		Message *protocol.Message `json:"message"`
+	} `json:"tasks"`
+}
+
+// checkPartSizes rejects requests carrying a message part larger than limit.
+// Params that cannot be decoded are left to the method handler.
+func checkPartSizes(request jsonrpc.Request, limit int64) *jsonrpc.Error {
+	if limit <= 0 || len(request.Params) == 0 {
+		return nil
+	}
+	switch request.Method {
+	case protocol.MethodTasksSend, protocol.MethodTasksSendSubscribe, protocol.MethodTasksGraphSend:
+	default:
+		return nil
+	}
+	var params messageParams
+	if err := json.Unmarshal(request.Params, &params); err != nil {
+		return nil
+	}
+	if err := checkMessagePartSizes("message", params.Message, limit); err != nil {
+		return err
+	}
+	for i, task := range params.Tasks {
+		if err := checkMessagePartSizes(fmt.Sprintf("tasks[%d].message", i), task.Message, limit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkMessagePartSizes rejects msg if one of its parts is larger than limit.
+func checkMessagePartSizes(name string, msg *protocol.Message, limit int64) *jsonrpc.Error {
+	if msg == nil {
+		return nil
+	}
+	for i, part := range msg.Parts {
+		if taskmanager.PartSize(part) > limit {
+			return taskmanager.ErrPayloadTooLarge(fmt.Sprintf("Part %d of %s", i, name), limit)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+func TestA2AServer_PayloadLimits(t *testing.T) {
+	mockTM := newMockTaskManager()
+	mockTM.SendResponse = &protocol.Task{ID: "limited"}
+	testServer, _ := setupTestServer(t, mockTM, WithMaxRequestSize(512), WithMaxPartSize(16))
+
+	send := func(text string) (int, *jsonrpc.Response) {
+		request := jsonrpc.NewRequest(protocol.MethodTasksSend, "req-1")
+		params, err := json.Marshal(protocol.SendTaskParams{
+			ID:      "limited",
+			Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart(text)}),
+		})
+		require.NoError(t, err)
+		request.Params = params
+		body, err := json.Marshal(request)
+		require.NoError(t, err)
+		resp, err := http.Post(testServer.URL, "application/json", bytes.NewReader(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var rpcResp jsonrpc.Response
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&rpcResp))
+		return resp.StatusCode, &rpcResp
+	}
+
+	status, resp := send("small")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Nil(t, resp.Error)
+
+	status, resp = send(strings.Repeat("x", 17))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, status)
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, taskmanager.ErrCodePayloadTooLarge, resp.Error.Code)
+	assert.Equal(t, "Part 0 of message exceeds the limit of 16 bytes.", resp.Error.Data)
+
+	status, resp = send(strings.Repeat("x", 1024))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, status)
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, "Request body exceeds the limit of 512 bytes.", resp.Error.Data)
+}
@@ -0,0 +1,56 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/client"
+	"trpc.group/trpc-go/trpc-a2a-go/metadata"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// metadataProcessor records the incoming metadata of the tasks it processes.
+type metadataProcessor struct {
+	received metadata.MD
+}
+
+func (p *metadataProcessor) Process(
+	ctx context.Context,
+	taskID string,
+	message protocol.Message,
+	handle taskmanager.TaskHandle,
+) error {
+	p.received, _ = metadata.FromIncomingContext(ctx)
+	return handle.UpdateStatus(protocol.TaskStateCompleted, nil)
+}
+
+func TestA2AServer_Metadata(t *testing.T) {
+	processor := &metadataProcessor{}
+	tm, err := taskmanager.NewMemoryTaskManager(processor)
+	require.NoError(t, err)
+	a2aServer, err := NewA2AServer(defaultAgentCard(), tm)
+	require.NoError(t, err)
+	testServer := httptest.NewServer(a2aServer.Handler())
+	defer testServer.Close()
+
+	c, err := client.NewA2AClient(testServer.URL, client.WithMetadata(metadata.Pairs("tenant", "acme")))
+	require.NoError(t, err)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "Trace-ID", "t-1")
+	_, err = c.SendTasks(ctx, protocol.SendTaskParams{
+		ID:      "with-metadata",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")}),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, metadata.MD{"tenant": {"acme"}, "trace-id": {"t-1"}}, processor.received)
+}
@@ -0,0 +1,146 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// builtinMethods are the methods served by the server itself, which custom
+// methods cannot replace.
+var builtinMethods = map[string]bool{
+	protocol.MethodTasksSend:                true,
+	protocol.MethodTasksSendSubscribe:       true,
+	protocol.MethodTasksGet:                 true,
+	protocol.MethodTasksCancel:              true,
+	protocol.MethodTasksPushNotificationSet: true,
+	protocol.MethodTasksPushNotificationGet: true,
+	protocol.MethodTasksResubscribe:         true,
+	protocol.MethodSkillsExamplesList:       true,
+	protocol.MethodSkillsExamplesRun:        true,
+	protocol.MethodTasksAckEvents:           true,
+	protocol.MethodTasksPollEvents:          true,
+	protocol.MethodTasksGraphSend:           true,
+	protocol.MethodTasksGraphGet:            true,
+}
+
+// MethodHandler handles the calls of a custom JSON-RPC method. decode decodes
+// the params of the call into v, leaving v unchanged if the call has none, and
+// fails with an invalid params error. The result is returned as the result of
+// the response. Errors created with NewMethodError, or returned by decode, are
+// returned as they are, and other errors as internal errors.
+type MethodHandler func(ctx context.Context, decode func(v interface{}) error) (interface{}, error)
+
+// TypedMethod adapts handler, taking params of type P and returning a result
+// of type R, to a MethodHandler.
+func TypedMethod[P, R any](handler func(ctx context.Context, params P) (R, error)) MethodHandler {
+	return func(ctx context.Context, decode func(v interface{}) error) (interface{}, error) {
+		var params P
+		if err := decode(&params); err != nil {
+			return nil, err
+		}
+		return handler(ctx, params)
+	}
+}
+
+// Method is a custom JSON-RPC method, such as "agent/stats" or a vendor
+// extension, served alongside the task methods and advertised in the methods
+// of the agent card.
+type Method struct {
+	// Name is the name of the method. It must not be the name of a method of
+	// the A2A protocol or of the extensions of the server.
+	Name string
+	// Description describes the method in the agent card.
+	Description string
+	// Handler handles the calls of the method.
+	Handler MethodHandler
+}
+
+// NewMethodError creates the JSON-RPC error with which a custom method fails.
+// Codes from -32000 to -32099 are reserved for the errors of the server
+// implementation, and should not be used by applications.
+func NewMethodError(code int, message string, data interface{}) *jsonrpc.Error {
+	return &jsonrpc.Error{Code: code, Message: message, Data: data}
+}
+
+// RegisterMethod registers the custom method m. It fails if m has no name or
+// handler, or if a method of that name is already served. Methods can be
+// registered while the server is running.
+func (s *A2AServer) RegisterMethod(m Method) error {
+	if m.Name == "" {
+		return errors.New("custom method requires a name")
+	}
+	if m.Handler == nil {
+		return fmt.Errorf("custom method %s requires a handler", m.Name)
+	}
+	if builtinMethods[m.Name] {
+		return fmt.Errorf("method %s is a built-in method", m.Name)
+	}
+	s.liveMu.Lock()
+	defer s.liveMu.Unlock()
+	if _, exists := s.methods[m.Name]; exists {
+		return fmt.Errorf("method %s is already registered", m.Name)
+	}
+	if s.methods == nil {
+		s.methods = make(map[string]Method)
+	}
+	s.methods[m.Name] = m
+	return nil
+}
+
+// method returns the custom method named name, if registered.
+func (s *A2AServer) method(name string) (Method, bool) {
+	s.liveMu.RLock()
+	defer s.liveMu.RUnlock()
+	m, ok := s.methods[name]
+	return m, ok
+}
+
+// methodDescriptors returns the descriptors of the custom methods, sorted by
+// name.
+func (s *A2AServer) methodDescriptors() []AgentMethod {
+	descriptors := make([]AgentMethod, 0, len(s.methods))
+	for _, m := range s.methods {
+		descriptors = append(descriptors, AgentMethod{Name: m.Name, Description: m.Description})
+	}
+	sort.Slice(descriptors, func(i, j int) bool { return descriptors[i].Name < descriptors[j].Name })
+	return descriptors
+}
+
+// handleCustomMethod handles a call of the custom method m.
+func (s *A2AServer) handleCustomMethod(ctx context.Context, w http.ResponseWriter, request jsonrpc.Request, m Method) {
+	decode := func(v interface{}) error {
+		params := bytes.TrimSpace(request.Params)
+		if len(params) == 0 || bytes.Equal(params, []byte("null")) {
+			return nil
+		}
+		if err := s.unmarshalParams(params, v); err != nil {
+			return err
+		}
+		return nil
+	}
+	result, err := m.Handler(ctx, decode)
+	if err != nil {
+		var rpcErr *jsonrpc.Error
+		if !errors.As(err, &rpcErr) {
+			log.Errorf("Custom method %s failed (Request ID: %v): %v", m.Name, request.ID, err)
+			rpcErr = jsonrpc.ErrInternalError(fmt.Sprintf("%s failed: %v", m.Name, err))
+		}
+		s.writeJSONRPCError(w, request.ID, rpcErr)
+		return
+	}
+	s.writeJSONRPCResponse(w, request.ID, result)
+}
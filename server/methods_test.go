@@ -0,0 +1,112 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+type statsParams struct {
+	Window string `json:"window"`
+}
+
+type statsResult struct {
+	Window string `json:"window"`
+	Tasks  int    `json:"tasks"`
+}
+
+func TestA2AServer_CustomMethods(t *testing.T) {
+	stats := Method{
+		Name:        "agent/stats",
+		Description: "Returns task statistics",
+		Handler: TypedMethod(func(ctx context.Context, params statsParams) (statsResult, error) {
+			switch params.Window {
+			case "":
+				params.Window = "1h"
+			case "forever":
+				return statsResult{}, NewMethodError(-31000, "window too large", params.Window)
+			case "crash":
+				return statsResult{}, errors.New("stats unavailable")
+			}
+			return statsResult{Window: params.Window, Tasks: 3}, nil
+		}),
+	}
+	a2aServer, err := NewA2AServer(defaultAgentCard(), newMockTaskManager(), WithMethods(stats))
+	require.NoError(t, err)
+	testServer := httptest.NewServer(http.HandlerFunc(a2aServer.handleJSONRPC))
+	defer testServer.Close()
+
+	resp := performJSONRPCRequest(t, testServer, "agent/stats", statsParams{Window: "5m"}, "1")
+	require.Nil(t, resp.Error)
+	assert.Equal(t, map[string]interface{}{"window": "5m", "tasks": float64(3)}, resp.Result)
+
+	resp = performJSONRPCRequest(t, testServer, "agent/stats", nil, "2")
+	require.Nil(t, resp.Error)
+	assert.Equal(t, "1h", resp.Result.(map[string]interface{})["window"], "calls without params get zero params")
+
+	resp = performJSONRPCRequest(t, testServer, "agent/stats", []int{1}, "3")
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, jsonrpc.CodeInvalidParams, resp.Error.Code)
+
+	resp = performJSONRPCRequest(t, testServer, "agent/stats", statsParams{Window: "forever"}, "4")
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, -31000, resp.Error.Code)
+	assert.Equal(t, "forever", resp.Error.Data)
+
+	resp = performJSONRPCRequest(t, testServer, "agent/stats", statsParams{Window: "crash"}, "5")
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, jsonrpc.CodeInternalError, resp.Error.Code)
+
+	// Methods can be registered while serving, and are advertised in the card.
+	require.NoError(t, a2aServer.RegisterMethod(Method{
+		Name: "vendor/ping",
+		Handler: func(ctx context.Context, decode func(v interface{}) error) (interface{}, error) {
+			return "pong", nil
+		},
+	}))
+	resp = performJSONRPCRequest(t, testServer, "vendor/ping", nil, "6")
+	require.Nil(t, resp.Error)
+	assert.Equal(t, "pong", resp.Result)
+	assert.Equal(t, []AgentMethod{
+		{Name: "agent/stats", Description: "Returns task statistics"},
+		{Name: "vendor/ping"},
+	}, a2aServer.AgentCard().Methods)
+	assert.True(t, a2aServer.AgentCard().Capabilities == defaultAgentCard().Capabilities,
+		"capabilities stay comparable")
+
+	resp = performJSONRPCRequest(t, testServer, "vendor/unknown", nil, "7")
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, jsonrpc.CodeMethodNotFound, resp.Error.Code)
+}
+
+func TestA2AServer_RegisterMethodErrors(t *testing.T) {
+	handler := func(ctx context.Context, decode func(v interface{}) error) (interface{}, error) {
+		return nil, nil
+	}
+	a2aServer, err := NewA2AServer(defaultAgentCard(), newMockTaskManager())
+	require.NoError(t, err)
+	assert.Error(t, a2aServer.RegisterMethod(Method{Handler: handler}), "missing name")
+	assert.Error(t, a2aServer.RegisterMethod(Method{Name: "agent/stats"}), "missing handler")
+	assert.Error(t, a2aServer.RegisterMethod(Method{Name: protocol.MethodTasksGet, Handler: handler}),
+		"built-in method")
+	require.NoError(t, a2aServer.RegisterMethod(Method{Name: "agent/stats", Handler: handler}))
+	assert.Error(t, a2aServer.RegisterMethod(Method{Name: "agent/stats", Handler: handler}), "duplicate")
+
+	_, err = NewA2AServer(defaultAgentCard(), newMockTaskManager(),
+		WithMethods(Method{Name: protocol.MethodTasksSend, Handler: handler}))
+	assert.Error(t, err)
+}
@@ -0,0 +1,51 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// HandlerWithPrefix returns the handler of the server for mounting under the
+// path prefix of an existing HTTP application: the agent card is then served
+// at prefix + protocol.AgentCardPath and the JSON-RPC endpoint at prefix + the
+// path set with WithJSONRPCEndpoint. Requests outside the prefix are not found.
+//
+// The handler is a plain http.Handler, so it mounts in any router:
+//
+//	h := s.HandlerWithPrefix("/agents/echo")
+//	mux.Handle("/agents/echo/", h)                // net/http
+//	r.Mount("/agents/echo", h)                    // chi
+//	router.Any("/agents/echo/*path", gin.WrapH(h)) // gin
+//	e.Any("/agents/echo/*", echo.WrapHandler(h))  // echo
+func (s *A2AServer) HandlerWithPrefix(prefix string) http.Handler {
+	prefix = strings.TrimSuffix(prefix, "/")
+	h := s.Handler()
+	if prefix == "" {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, ok := strings.CutPrefix(r.URL.Path, prefix)
+		if !ok || (path != "" && path[0] != '/') {
+			http.NotFound(w, r)
+			return
+		}
+		if path == "" {
+			path = "/"
+		}
+		// Strip the prefix from a copy of the request, like http.StripPrefix.
+		r2 := new(http.Request)
+		*r2 = *r
+		r2.URL = new(url.URL)
+		*r2.URL = *r.URL
+		r2.URL.Path = path
+		r2.URL.RawPath = ""
+		h.ServeHTTP(w, r2)
+	})
+}
@@ -0,0 +1,52 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/client"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+func TestA2AServer_HandlerWithPrefix(t *testing.T) {
+	tm, err := taskmanager.NewMemoryTaskManager(&countingProcessor{})
+	require.NoError(t, err)
+	a2aServer, err := NewA2AServer(defaultAgentCard(), tm)
+	require.NoError(t, err)
+	mux := http.NewServeMux()
+	mux.Handle("/agents/echo/", a2aServer.HandlerWithPrefix("/agents/echo/"))
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {})
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	for path, want := range map[string]int{
+		"/agents/echo" + protocol.AgentCardPath:   http.StatusOK,
+		"/agents/echoes" + protocol.AgentCardPath: http.StatusNotFound,
+		protocol.AgentCardPath:                    http.StatusNotFound,
+		"/health":                                 http.StatusOK,
+	} {
+		resp, err := http.Get(testServer.URL + path)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, want, resp.StatusCode, path)
+	}
+
+	c, err := client.NewA2AClient(testServer.URL + "/agents/echo/")
+	require.NoError(t, err)
+	msg := protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")})
+	task, err := c.SendTasks(context.Background(), protocol.SendTaskParams{ID: "task-1", Message: msg})
+	require.NoError(t, err)
+	assert.Equal(t, protocol.TaskStateCompleted, task.Status.State)
+}
@@ -0,0 +1,69 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"context"
+	"mime"
+	"strconv"
+	"strings"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// eventStreamWriter writes the JSON-RPC events of a stream in its wire format.
+type eventStreamWriter interface {
+	WriteJSONRPCEventWithID(eventID, eventType string, id interface{}, result []byte) error
+}
+
+// ndjsonStreamKey marks the contexts of requests whose streams are written
+// as newline-delimited JSON rather than SSE.
+type ndjsonStreamKey struct{}
+
+// withStreamFormat marks ctx for NDJSON streams if the Accept header values
+// prefer protocol.ContentTypeNDJSON to protocol.ContentTypeSSE.
+func withStreamFormat(ctx context.Context, accept []string) context.Context {
+	if prefersNDJSON(accept) {
+		return context.WithValue(ctx, ndjsonStreamKey{}, true)
+	}
+	return ctx
+}
+
+// ndjsonStream reports whether the stream of the request of ctx is written as
+// newline-delimited JSON.
+func ndjsonStream(ctx context.Context) bool {
+	ndjson, _ := ctx.Value(ndjsonStreamKey{}).(bool)
+	return ndjson
+}
+
+// prefersNDJSON reports whether the Accept header values rank
+// protocol.ContentTypeNDJSON above protocol.ContentTypeSSE. Ties go to SSE,
+// the default, and wildcards are ignored.
+func prefersNDJSON(accept []string) bool {
+	ndjson, sse := 0.0, 0.0
+	for _, value := range accept {
+		for _, mediaRange := range strings.Split(value, ",") {
+			mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+			if err != nil {
+				continue
+			}
+			q := 1.0
+			if v, ok := params["q"]; ok {
+				if q, err = strconv.ParseFloat(v, 64); err != nil {
+					continue
+				}
+			}
+			switch mediaType {
+			case protocol.ContentTypeNDJSON:
+				ndjson = max(ndjson, q)
+			case protocol.ContentTypeSSE:
+				sse = max(sse, q)
+			}
+		}
+	}
+	return ndjson > sse
+}
@@ -0,0 +1,111 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/client"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+func TestPrefersNDJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		accept []string
+		want   bool
+	}{
+		{name: "none", accept: nil, want: false},
+		{name: "sse", accept: []string{"text/event-stream"}, want: false},
+		{name: "ndjson", accept: []string{"application/x-ndjson"}, want: true},
+		{name: "ndjson preferred", accept: []string{"application/x-ndjson, text/event-stream;q=0.5"}, want: true},
+		{name: "sse preferred", accept: []string{"application/x-ndjson;q=0.2", "text/event-stream"}, want: false},
+		{name: "tie", accept: []string{"text/event-stream, application/x-ndjson"}, want: false},
+		{name: "refused", accept: []string{"application/x-ndjson;q=0"}, want: false},
+		{name: "wildcard", accept: []string{"*/*"}, want: false},
+		{name: "malformed q", accept: []string{"application/x-ndjson;q=high"}, want: false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, prefersNDJSON(tc.accept))
+		})
+	}
+}
+
+func TestA2AServer_NDJSONStreams(t *testing.T) {
+	mockTM := newMockTaskManager()
+	testServer, _ := setupTestServer(t, mockTM)
+	taskID := "task-ndjson"
+	msg := protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")})
+	mockTM.SubscribeEvents = []protocol.TaskEvent{
+		protocol.TaskStatusUpdateEvent{ID: taskID, Status: protocol.TaskStatus{State: protocol.TaskStateWorking}},
+		protocol.TaskArtifactUpdateEvent{ID: taskID, Artifact: protocol.Artifact{
+			Parts: []protocol.Part{protocol.NewTextPart(strings.Repeat("x", 128<<10))},
+		}},
+		protocol.TaskStatusUpdateEvent{
+			ID: taskID, Status: protocol.TaskStatus{State: protocol.TaskStateCompleted}, Final: true,
+		},
+	}
+
+	t.Run("wire format", func(t *testing.T) {
+		req, _ := createJSONRPCRequest(t, protocol.MethodTasksSendSubscribe,
+			protocol.SendTaskParams{ID: taskID, Message: msg}, "req-1")
+		req.Header.Set("Accept", protocol.ContentTypeNDJSON)
+		resp := executeRequest(t, testServer, req, testServer.URL)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, protocol.ContentTypeNDJSON, resp.Header.Get("Content-Type"))
+
+		var types []string
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(nil, 1<<20)
+		for scanner.Scan() {
+			var line struct {
+				JSONRPC string          `json:"jsonrpc"`
+				ID      string          `json:"id"`
+				Event   string          `json:"event"`
+				Result  json.RawMessage `json:"result"`
+			}
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &line), "each line is a JSON object")
+			assert.Equal(t, "2.0", line.JSONRPC)
+			assert.Equal(t, "req-1", line.ID)
+			assert.NotEmpty(t, line.Result)
+			types = append(types, line.Event)
+		}
+		require.NoError(t, scanner.Err())
+		assert.Equal(t, []string{
+			protocol.EventTaskStatusUpdate,
+			protocol.EventTaskArtifactUpdate,
+			protocol.EventTaskStatusUpdate,
+			protocol.EventClose,
+		}, types)
+	})
+
+	t.Run("client", func(t *testing.T) {
+		c, err := client.NewA2AClient(testServer.URL, client.WithNDJSONStreams(true))
+		require.NoError(t, err)
+		events, err := c.StreamTask(context.Background(), protocol.SendTaskParams{ID: taskID, Message: msg})
+		require.NoError(t, err)
+		var received []protocol.TaskEvent
+		for event := range events {
+			received = append(received, event)
+		}
+		require.Len(t, received, 3, "events longer than the SSE line limit are read")
+		artifact, ok := received[1].(protocol.TaskArtifactUpdateEvent)
+		require.True(t, ok)
+		assert.Len(t, artifact.Artifact.Parts, 1)
+		assert.True(t, received[2].IsFinal())
+	})
+}
@@ -0,0 +1,71 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// handleNotification handles a JSON-RPC notification, a request without ID.
+// The server does not respond to notifications: the HTTP request is
+// acknowledged with 202 Accepted before the method runs, with the values of
+// the request context but without its cancellation, and errors are only
+// logged. Streaming methods are ignored as their events could not be delivered.
+func (s *A2AServer) handleNotification(ctx context.Context, w http.ResponseWriter, request jsonrpc.Request) {
+	w.WriteHeader(http.StatusAccepted)
+	switch request.Method {
+	case protocol.MethodTasksSendSubscribe, protocol.MethodTasksResubscribe:
+		log.Warnf("Ignoring notification of streaming method %s", request.Method)
+		return
+	}
+	log.Infof("Received JSON-RPC notification (Method: %s)", request.Method)
+	ctx = taskmanager.ContextWithWarnings(context.WithoutCancel(ctx))
+	go func() {
+		discard := &notificationWriter{header: make(http.Header)}
+		s.routeJSONRPCMethod(ctx, discard, request)
+		if discard.status >= http.StatusBadRequest {
+			log.Warnf("JSON-RPC notification (Method: %s) failed with status %d: %s",
+				request.Method, discard.status, bytes.TrimSpace(discard.body.Bytes()))
+		}
+	}()
+}
+
+// notificationWriter is the http.ResponseWriter of notification handlers, and
+// of requests received over WebSocket. It keeps the status and body of the
+// response, which is not sent over HTTP.
+type notificationWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+// Header implements http.ResponseWriter.
+func (w *notificationWriter) Header() http.Header {
+	return w.header
+}
+
+// Write implements http.ResponseWriter.
+func (w *notificationWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.body.Write(p)
+}
+
+// WriteHeader implements http.ResponseWriter.
+func (w *notificationWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
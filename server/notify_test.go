@@ -0,0 +1,67 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/client"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+func TestA2AServer_Notifications(t *testing.T) {
+	tm, err := taskmanager.NewMemoryTaskManager(&countingProcessor{})
+	require.NoError(t, err)
+	a2aServer, err := NewA2AServer(defaultAgentCard(), tm)
+	require.NoError(t, err)
+	testServer := httptest.NewServer(a2aServer.Handler())
+	defer testServer.Close()
+
+	c, err := client.NewA2AClient(testServer.URL)
+	require.NoError(t, err)
+	ctx := context.Background()
+	require.NoError(t, c.SendTaskNotify(ctx, protocol.SendTaskParams{
+		ID:      "notified",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")}),
+	}))
+	require.Eventually(t, func() bool {
+		task, err := c.GetTasks(ctx, protocol.TaskQueryParams{ID: "notified"})
+		return err == nil && task.Status.State == protocol.TaskStateCompleted
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// Invalid notifications get no response either.
+	for _, body := range []string{
+		`{"jsonrpc":"2.0","method":"tasks/send","params":{}}`,
+		`{"jsonrpc":"2.0","method":"tasks/sendSubscribe","params":{}}`,
+		`{"jsonrpc":"2.0","method":"vendor/unknown"}`,
+	} {
+		resp, err := http.Post(testServer.URL, "application/json", bytes.NewBufferString(body))
+		require.NoError(t, err)
+		respBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusAccepted, resp.StatusCode, body)
+		assert.Empty(t, respBody, body)
+	}
+
+	// A request with a null id is not a notification.
+	resp, err := http.Post(testServer.URL, "application/json",
+		bytes.NewBufferString(`{"jsonrpc":"2.0","id":null,"method":"vendor/unknown"}`))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
@@ -7,9 +7,13 @@
 package server
 
 import (
+	"strings"
 	"time"
 
 	"trpc.group/trpc-go/trpc-a2a-go/auth"
+	"trpc.group/trpc-go/trpc-a2a-go/codec"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
 )
 
 const (
@@ -65,6 +69,17 @@ func WithAuthProvider(provider auth.Provider) Option {
 	}
 }
 
+// WithRequestSigning requires requests to the JSON-RPC, upload and artifact
+// download endpoints to be signed by an auth.HMACSigner with one of the
+// secrets of verifier, rejecting unsigned, tampered, stale and replayed
+// requests with 401 Unauthorized. It protects requests where TLS termination
+// is out of the agent's control and combines with WithAuthProvider.
+func WithRequestSigning(verifier *auth.HMACVerifier) Option {
+	return func(s *A2AServer) {
+		s.hmacVerifier = verifier
+	}
+}
+
 // WithJWKSEndpoint enables the JWKS endpoint for push notification authentication.
 // This is used for providing public keys for JWT verification.
 // The path defaults to "/.well-known/jwks.json".
@@ -76,3 +91,299 @@ func WithJWKSEndpoint(enabled bool, path string) Option {
 		}
 	}
 }
+
+// WithParamsValidation enables or disables validation of JSON-RPC params against
+// the A2A schema before requests reach the TaskManager. Enabled by default.
+// Violations are reported as invalid-params errors whose data lists the
+// JSON pointer of each offending field.
+func WithParamsValidation(enabled bool) Option {
+	return func(s *A2AServer) {
+		s.validateParams = enabled
+	}
+}
+
+// WithPartFailurePolicy sets how params validation handles a tasks/send or
+// tasks/sendSubscribe message in which only some parts are invalid. With
+// protocol.PartFailureAcceptValid the invalid parts are dropped and reported as
+// protocol.Warning values under the "warnings" key of the params metadata,
+// which the task managers merge into the task metadata. The default is
+// protocol.PartFailureRejectAll. It has no effect if params validation is disabled.
+func WithPartFailurePolicy(policy protocol.PartFailurePolicy) Option {
+	return func(s *A2AServer) {
+		s.partFailurePolicy = policy
+	}
+}
+
+// WithMaxRequestSize rejects requests whose body is larger than maxBytes with
+// a taskmanager.ErrPayloadTooLarge error, without reading more than maxBytes.
+// Request bodies are unlimited by default.
+func WithMaxRequestSize(maxBytes int64) Option {
+	return func(s *A2AServer) {
+		s.maxRequestBytes = maxBytes
+	}
+}
+
+// WithMaxPartSize rejects send requests carrying a message part larger than
+// maxBytes with a taskmanager.ErrPayloadTooLarge error. The size of a part is
+// the length of its text, its decoded file bytes or its JSON data, as computed
+// by taskmanager.PartSize. Parts are unlimited by default.
+func WithMaxPartSize(maxBytes int64) Option {
+	return func(s *A2AServer) {
+		s.maxPartBytes = maxBytes
+	}
+}
+
+// WithCodec sets the JSON codec used to decode request params and to encode
+// responses and SSE events. Defaults to codec.Default (encoding/json).
+func WithCodec(c codec.Codec) Option {
+	return func(s *A2AServer) {
+		if c != nil {
+			s.codec = c
+		}
+	}
+}
+
+// WithSSEWriteTimeout sets a write deadline applied before every SSE event.
+// It bounds how long a stalled client can block its stream and, since the
+// deadline is renewed per event, lets streams outlive the server write timeout.
+// Zero (the default) keeps the server write timeout for the whole stream.
+func WithSSEWriteTimeout(timeout time.Duration) Option {
+	return func(s *A2AServer) {
+		s.sseWriteTimeout = timeout
+	}
+}
+
+// WithCompression enables gzip and deflate compression of responses, including
+// SSE streams, for clients that advertise support in Accept-Encoding.
+// The level is a compress/flate level such as gzip.DefaultCompression.
+// Responses smaller than 1 KiB and partial content are sent uncompressed,
+// and the ETags of compressed responses get the encoding as suffix.
+func WithCompression(level int) Option {
+	return func(s *A2AServer) {
+		s.compression = true
+		s.compressLevel = level
+	}
+}
+
+// WithProvenanceVerifier verifies the signed provenance chain that delegating
+// agents attach to tasks/send and tasks/sendSubscribe params (see
+// auth.ProvenanceSigner). Requests with an invalid chain, or without one if
+// required is true, are rejected as invalid params. A verified chain is recorded
+// in the task metadata and made available to the processor through
+// auth.ProvenanceFromContext.
+func WithProvenanceVerifier(verifier *auth.ProvenanceVerifier, required bool) Option {
+	return func(s *A2AServer) {
+		s.provenanceVerifier = verifier
+		s.provenanceRequired = required
+	}
+}
+
+// WithMinDeadlineBudget makes the server reject tasks/send and
+// tasks/sendSubscribe requests whose deadline budget (see
+// protocol.MetadataKeyDeadlineBudget) is below min, because they cannot finish
+// before their originator gives up. Requests with an exhausted budget are
+// always rejected, and those without a budget are always accepted. The
+// processor context of accepted requests expires with their budget.
+func WithMinDeadlineBudget(min time.Duration) Option {
+	return func(s *A2AServer) {
+		s.minDeadlineBudget = min
+	}
+}
+
+// WithSkillExamples enables the skills/examples/run extension method, which runs
+// the RunnableExamples declared in the agent card skills with processor,
+// usually the processor of the agent. Each run uses a fresh in-memory task
+// manager, so example tasks stay out of the agent's task store, and a context
+// marked by taskmanager.ContextWithSandbox, so the processor can skip side effects.
+func WithSkillExamples(processor taskmanager.TaskProcessor) Option {
+	return func(s *A2AServer) {
+		s.exampleProcessor = processor
+	}
+}
+
+// WithDeprecations marks methods, or fields of their params, as deprecated.
+// Requests using them still succeed, but get a protocol.WarningCodeDeprecated
+// warning with the task they return, and requests for a deprecated method get
+// the Deprecation and, if a sunset is set, Sunset HTTP headers in their response.
+func WithDeprecations(deprecations ...protocol.Deprecation) Option {
+	return func(s *A2AServer) {
+		s.deprecations = append(s.deprecations, deprecations...)
+	}
+}
+
+// WithMethods registers custom JSON-RPC methods, as RegisterMethod does.
+// NewA2AServer fails if one of them is invalid.
+func WithMethods(methods ...Method) Option {
+	return func(s *A2AServer) {
+		s.pendingMethods = append(s.pendingMethods, methods...)
+	}
+}
+
+// WithWebSocket enables JSON-RPC over WebSocket on the JSON-RPC endpoint. GET
+// requests upgrading to the WebSocket protocol open a connection on which each
+// message is a JSON-RPC request or notification, and the server sends the
+// responses and its own notifications, with Broadcast or the Notifier returned
+// by NotifierFromContext, as well as protocol.MethodAgentCardUpdated when the
+// agent card changes. Streaming methods are only served over HTTP.
+//
+// Browsers may only open connections from the origin of the server or from
+// allowedOrigins, such as "https://app.example.com", or from any origin if
+// one of them is "*". Each connection handles up to 32 requests at a time,
+// and notifications that a slow client cannot take are dropped.
+func WithWebSocket(allowedOrigins ...string) Option {
+	return func(s *A2AServer) {
+		s.webSocket = true
+		s.wsOrigins = allowedOrigins
+	}
+}
+
+// WithEventAcks enables the tasks/ackEvents extension method. The events of
+// every SSE stream are then numbered in their SSE id field and the stream is
+// identified by the protocol.StreamIDHeader response header, so that clients
+// can acknowledge the events they processed. Each acknowledgement reports the
+// consumption lag of the stream to observer, if not nil, and to the task
+// manager if it implements taskmanager.EventAcknowledger.
+func WithEventAcks(observer EventAckObserver) Option {
+	return func(s *A2AServer) {
+		s.acks = newAckTracker(observer)
+	}
+}
+
+// WithStartupSelfTest makes Start run SelfTest before listening and fail if it
+// does, so that a misconfigured task manager or storage backend is caught
+// before traffic arrives. The readiness endpoint, if enabled, then only
+// reports ready once a self test passed.
+func WithStartupSelfTest() Option {
+	return func(s *A2AServer) {
+		s.startupSelfTest = true
+	}
+}
+
+// WithReadinessEndpoint serves a readiness probe at path, answering 200 OK
+// unless the last SelfTest failed, or no self test passed yet while one is
+// required at startup, in which case it answers 503 Service Unavailable.
+func WithReadinessEndpoint(path string) Option {
+	return func(s *A2AServer) {
+		s.readinessPath = path
+	}
+}
+
+// WithTLS makes Start serve HTTPS with the certificate and private key read
+// from the given PEM files.
+func WithTLS(certFile, keyFile string) Option {
+	return func(s *A2AServer) {
+		s.tlsCertFile = certFile
+		s.tlsKeyFile = keyFile
+	}
+}
+
+// WithAdminAPI mounts the admin API (see AdminHandler) on Handler under the
+// path prefix, such as "/admin", with requests authenticated by provider. It
+// is independent of the authentication of the JSON-RPC endpoint. To serve the
+// API on a separate listener instead, leave prefix empty and use AdminHandler.
+func WithAdminAPI(prefix string, provider auth.Provider) Option {
+	return func(s *A2AServer) {
+		s.adminAPI().prefix = strings.TrimSuffix(prefix, "/")
+		s.adminAPI().provider = provider
+	}
+}
+
+// WithAdminMetrics adds the snapshot returned by metrics under name to the
+// metrics endpoint of the admin API.
+func WithAdminMetrics(name string, metrics MetricsSnapshot) Option {
+	return func(s *A2AServer) {
+		s.adminAPI().metrics[name] = metrics
+	}
+}
+
+// WithPushDeliveryInspector makes the admin API report push notification
+// delivery attempts from inspector, such as the taskmanager.PushDeliveryLog
+// of the push sender, rather than from the task manager.
+func WithPushDeliveryInspector(inspector taskmanager.PushDeliveryInspector) Option {
+	return func(s *A2AServer) {
+		s.adminAPI().pushDeliveries = inspector
+	}
+}
+
+// WithUsageReporter makes the admin API report the usage of principals from
+// reporter, typically the taskmanager.UsageAccountant of the task manager, for
+// billing integrations.
+func WithUsageReporter(reporter taskmanager.UsageReporter) Option {
+	return func(s *A2AServer) {
+		s.adminAPI().usage = reporter
+	}
+}
+
+// WithIDGenerator sets the generator of the IDs assigned to tasks sent with
+// tasks/send or tasks/sendSubscribe without one. Defaults to
+// protocol.DefaultIDGenerator, which generates UUIDv7 IDs.
+// See WithAutoTaskIDs.
+func WithIDGenerator(generator protocol.IDGenerator) Option {
+	return func(s *A2AServer) {
+		if generator != nil {
+			s.idGenerator = generator
+		}
+	}
+}
+
+// WithAutoTaskIDs sets whether tasks sent with tasks/send or
+// tasks/sendSubscribe without an ID get one from the ID generator, for thin
+// clients that do not manage task IDs. The ID is returned in the task, in the
+// events of the stream and in the protocol.TaskIDHeader response header.
+// When disabled, such sends are rejected as invalid params. Enabled by default.
+func WithAutoTaskIDs(enabled bool) Option {
+	return func(s *A2AServer) {
+		s.autoTaskIDs = enabled
+	}
+}
+
+// WithAgentCardMaxAge lets clients use the agent card they fetched for maxAge
+// before revalidating it, with the Cache-Control header of the card. By default
+// clients must revalidate it before each use, which the ETag and Last-Modified
+// headers of the card make cheap: unchanged cards are answered with 304 Not
+// Modified.
+func WithAgentCardMaxAge(maxAge time.Duration) Option {
+	return func(s *A2AServer) {
+		s.agentCardMaxAge = maxAge
+	}
+}
+
+// WithUploads serves resumable uploads (see protocol.DefaultUploadPath) at
+// path on Handler, or at protocol.DefaultUploadPath if path is empty, keeping
+// them in store. Clients upload the inputs too large for a JSON-RPC request
+// there, e.g. with client.A2AClient.Upload, and send their URI in file parts.
+// The endpoint requires the authentication and request signing of the
+// JSON-RPC endpoint.
+func WithUploads(path string, store UploadStore) Option {
+	return func(s *A2AServer) {
+		if path == "" {
+			path = protocol.DefaultUploadPath
+		}
+		s.uploads = &uploads{path: strings.TrimSuffix(path, "/"), store: store}
+	}
+}
+
+// WithMaxUploadSize limits the size of the uploads enabled by WithUploads,
+// to 1 GiB by default; a negative size removes the limit. Larger uploads are
+// rejected with 413 Request Entity Too Large.
+func WithMaxUploadSize(maxBytes int64) Option {
+	return func(s *A2AServer) {
+		s.maxUploadBytes = maxBytes
+	}
+}
+
+// WithArtifactDownloads serves the artifacts of tasks (see
+// protocol.DefaultArtifactPath) at path on Handler, or at
+// protocol.DefaultArtifactPath if path is empty, so that clients can download
+// large artifacts, or parts of them with range requests, rather than read
+// them from tasks/get. The endpoint requires the authentication of the
+// JSON-RPC endpoint, and reads the tasks with OnGetTask of the task manager,
+// which applies its access checks.
+func WithArtifactDownloads(path string) Option {
+	return func(s *A2AServer) {
+		if path == "" {
+			path = protocol.DefaultArtifactPath
+		}
+		s.artifactPath = strings.TrimSuffix(path, "/")
+	}
+}
@@ -0,0 +1,314 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+const (
+	// maxPollWait bounds the wait of a poll if the server has no write timeout.
+	maxPollWait = 30 * time.Second
+	// defaultMaxPolledEvents bounds the events returned by a poll.
+	defaultMaxPolledEvents = 100
+	// maxPollBuffer bounds the events buffered per poll session. Older ones
+	// are dropped if the client does not poll fast enough.
+	maxPollBuffer = 1024
+	// pollSessionIdleTimeout is how long a poll session outlives its last poll.
+	pollSessionIdleTimeout = time.Minute
+)
+
+// errUnknownPollCursor is returned for cursors of unknown or expired sessions.
+var errUnknownPollCursor = errors.New("unknown or expired poll cursor")
+
+// pollSession buffers the events of a task between the polls of a client.
+// Events are numbered from 1; a cursor names the session and the last event
+// the client received, acknowledging the events up to it.
+type pollSession struct {
+	id     string
+	taskID string
+	cancel context.CancelFunc
+	idle   *time.Timer
+
+	mu     sync.Mutex
+	events []protocol.TaskEvent // Events not yet acknowledged, from first on.
+	first  uint64               // Number of events[0].
+	next   uint64               // Number of the next event.
+	ended  bool                 // Whether all the events of the session are buffered.
+	notify chan struct{}        // Closed when events are buffered or the session ends.
+}
+
+// pump buffers the events of ch until the final event, the end of ch or the
+// cancellation of ctx.
+func (ps *pollSession) pump(ctx context.Context, ch <-chan protocol.TaskEvent) {
+	defer ps.end()
+	for {
+		select {
+		case event, ok := <-ch:
+			if !ok {
+				return
+			}
+			ps.push(event)
+			if event.IsFinal() {
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// push buffers event.
+func (ps *pollSession) push(event protocol.TaskEvent) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	ps.events = append(ps.events, event)
+	ps.next++
+	if len(ps.events) > maxPollBuffer {
+		log.Warnf("Dropping the oldest event of poll session %s of task %s: the client is too slow",
+			ps.id, ps.taskID)
+		ps.events = ps.events[1:]
+		ps.first++
+	}
+	close(ps.notify)
+	ps.notify = make(chan struct{})
+}
+
+// end marks that no more events will be buffered.
+func (ps *pollSession) end() {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	ps.ended = true
+	close(ps.notify)
+	ps.notify = make(chan struct{})
+}
+
+// poll acknowledges the events up to after and returns at most limit of the
+// following ones, waiting up to wait for some. It reports whether the events
+// returned are the last ones of the session.
+func (ps *pollSession) poll(
+	ctx context.Context,
+	after uint64,
+	wait time.Duration,
+	limit int,
+) ([]protocol.TaskEvent, uint64, bool, error) {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	for {
+		ps.mu.Lock()
+		if after >= ps.next {
+			ps.mu.Unlock()
+			return nil, 0, false, errUnknownPollCursor
+		}
+		if after >= ps.first {
+			acked := after - ps.first + 1
+			ps.events = ps.events[acked:]
+			ps.first += acked
+		}
+		if len(ps.events) > 0 || ps.ended {
+			events := ps.events
+			if len(events) > limit {
+				events = events[:limit]
+			}
+			events = append([]protocol.TaskEvent(nil), events...)
+			last := ps.first + uint64(len(events)) - 1
+			done := ps.ended && len(events) == len(ps.events)
+			ps.mu.Unlock()
+			return events, last, done, nil
+		}
+		notify := ps.notify
+		ps.mu.Unlock()
+		select {
+		case <-notify:
+		case <-timer.C:
+			return nil, after, false, nil
+		case <-ctx.Done():
+			return nil, after, false, nil
+		}
+	}
+}
+
+// pollSessions tracks the poll sessions of a server.
+type pollSessions struct {
+	mu       sync.Mutex
+	sessions map[string]*pollSession
+}
+
+// newPollSessions creates an empty set of poll sessions.
+func newPollSessions() *pollSessions {
+	return &pollSessions{sessions: make(map[string]*pollSession)}
+}
+
+// open starts a session delivering the events of taskID, starting with its
+// current status. The session keeps the values of ctx, e.g. the caller
+// identity, but not its cancellation.
+func (p *pollSessions) open(
+	ctx context.Context,
+	tm taskmanager.TaskManager,
+	taskID string,
+) (*pollSession, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return nil, err
+	}
+	sessionCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	ch, err := tm.OnResubscribe(sessionCtx, protocol.TaskIDParams{ID: taskID})
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	ps := &pollSession{
+		id:     hex.EncodeToString(b[:]),
+		taskID: taskID,
+		cancel: cancel,
+		first:  1,
+		next:   1,
+		notify: make(chan struct{}),
+	}
+	ps.idle = time.AfterFunc(pollSessionIdleTimeout, func() { p.close(ps) })
+	p.mu.Lock()
+	p.sessions[ps.id] = ps
+	p.mu.Unlock()
+	go ps.pump(sessionCtx, ch)
+	return ps, nil
+}
+
+// get returns the session named by cursor for taskID and the number of the
+// last event the client received.
+func (p *pollSessions) get(taskID, cursor string) (*pollSession, uint64, error) {
+	id, seq, ok := strings.Cut(cursor, ":")
+	if !ok {
+		return nil, 0, errUnknownPollCursor
+	}
+	after, err := strconv.ParseUint(seq, 10, 64)
+	if err != nil {
+		return nil, 0, errUnknownPollCursor
+	}
+	p.mu.Lock()
+	ps, ok := p.sessions[id]
+	p.mu.Unlock()
+	if !ok || ps.taskID != taskID {
+		return nil, 0, errUnknownPollCursor
+	}
+	ps.idle.Reset(pollSessionIdleTimeout)
+	return ps, after, nil
+}
+
+// close stops ps and forgets it.
+func (p *pollSessions) close(ps *pollSession) {
+	ps.idle.Stop()
+	ps.cancel()
+	p.mu.Lock()
+	delete(p.sessions, ps.id)
+	p.mu.Unlock()
+}
+
+// closeAll stops every session.
+func (p *pollSessions) closeAll() {
+	p.mu.Lock()
+	sessions := make([]*pollSession, 0, len(p.sessions))
+	for _, ps := range p.sessions {
+		sessions = append(sessions, ps)
+	}
+	p.mu.Unlock()
+	for _, ps := range sessions {
+		p.close(ps)
+	}
+}
+
+// pollWait returns how long a poll asking to wait waitMs may wait, leaving
+// time to write the response within the write timeout.
+func (s *A2AServer) pollWait(waitMs int64) time.Duration {
+	limit := maxPollWait
+	if s.writeTimeout > 0 && s.writeTimeout/2 < limit {
+		limit = s.writeTimeout / 2
+	}
+	wait := time.Duration(waitMs) * time.Millisecond
+	if wait < 0 {
+		return 0
+	}
+	if wait > limit {
+		return limit
+	}
+	return wait
+}
+
+// handleTasksPollEvents handles the tasks/pollEvents extension method.
+func (s *A2AServer) handleTasksPollEvents(ctx context.Context, w http.ResponseWriter, request jsonrpc.Request) {
+	var params protocol.PollEventsParams
+	if err := s.unmarshalParams(request.Params, &params); err != nil {
+		s.writeJSONRPCError(w, request.ID, err)
+		return
+	}
+	if params.ID == "" {
+		s.writeJSONRPCError(w, request.ID, jsonrpc.ErrInvalidParams("task ID is required"))
+		return
+	}
+	var (
+		session *pollSession
+		after   uint64
+		err     error
+	)
+	if params.Cursor == "" {
+		session, err = s.polls.open(ctx, s.taskManager, params.ID)
+	} else {
+		session, after, err = s.polls.get(params.ID, params.Cursor)
+	}
+	if err != nil {
+		var rpcErr *jsonrpc.Error
+		switch {
+		case errors.As(err, &rpcErr):
+		case errors.Is(err, errUnknownPollCursor):
+			rpcErr = jsonrpc.ErrInvalidParams(err.Error())
+		default:
+			rpcErr = jsonrpc.ErrInternalError(fmt.Sprintf("failed to poll task events: %v", err))
+		}
+		s.writeJSONRPCError(w, request.ID, rpcErr)
+		return
+	}
+	limit := params.MaxEvents
+	if limit <= 0 || limit > defaultMaxPolledEvents {
+		limit = defaultMaxPolledEvents
+	}
+	events, last, done, err := session.poll(ctx, after, s.pollWait(params.WaitMs), limit)
+	if err != nil {
+		s.writeJSONRPCError(w, request.ID, jsonrpc.ErrInvalidParams(err.Error()))
+		return
+	}
+	result := protocol.PollEventsResult{
+		ID:     params.ID,
+		Events: make([]protocol.PolledEvent, 0, len(events)),
+		Cursor: session.id + ":" + strconv.FormatUint(last, 10),
+		Done:   done,
+	}
+	for _, event := range events {
+		polled, err := protocol.NewPolledEvent(event)
+		if err != nil {
+			log.Warnf("Skipping event of task %s in poll: %v", params.ID, err)
+			continue
+		}
+		result.Events = append(result.Events, polled)
+	}
+	if done {
+		s.polls.close(session)
+	}
+	s.writeJSONRPCResponse(w, request.ID, result)
+}
@@ -0,0 +1,181 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/client"
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// gatedProcessor reports progress and an artifact, then completes the task
+// once released.
+type gatedProcessor struct {
+	release chan struct{}
+}
+
+func (p *gatedProcessor) Process(
+	ctx context.Context,
+	taskID string,
+	message protocol.Message,
+	handle taskmanager.TaskHandle,
+) error {
+	if err := handle.UpdateStatus(protocol.TaskStateWorking, nil); err != nil {
+		return err
+	}
+	<-p.release
+	if err := handle.AddArtifact(protocol.Artifact{
+		Parts: []protocol.Part{protocol.NewTextPart("result")},
+	}); err != nil {
+		return err
+	}
+	return handle.UpdateStatus(protocol.TaskStateCompleted, nil)
+}
+
+// pollEvents polls the events of a task.
+func pollEvents(t *testing.T, server *httptest.Server, params protocol.PollEventsParams) (*protocol.PollEventsResult, *jsonrpc.Error) {
+	t.Helper()
+	resp := performJSONRPCRequest(t, server, protocol.MethodTasksPollEvents, params, params.ID)
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	data, err := json.Marshal(resp.Result)
+	require.NoError(t, err)
+	var result protocol.PollEventsResult
+	require.NoError(t, json.Unmarshal(data, &result))
+	return &result, nil
+}
+
+func TestA2AServer_PollEvents(t *testing.T) {
+	processor := &gatedProcessor{release: make(chan struct{})}
+	tm, err := taskmanager.NewMemoryTaskManager(processor)
+	require.NoError(t, err)
+	testServer, _ := setupTestServer(t, tm)
+
+	_, rpcErr := pollEvents(t, testServer, protocol.PollEventsParams{ID: "missing", WaitMs: 100})
+	require.NotNil(t, rpcErr)
+	assert.Equal(t, taskmanager.ErrCodeTaskNotFound, rpcErr.Code)
+
+	msg := protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")})
+	_, err = tm.OnSendTaskSubscribe(context.Background(), protocol.SendTaskParams{ID: "task-1", Message: msg})
+	require.NoError(t, err)
+
+	// The first poll starts with the current status.
+	result, rpcErr := pollEvents(t, testServer, protocol.PollEventsParams{ID: "task-1", WaitMs: 1000})
+	require.Nil(t, rpcErr)
+	require.NotEmpty(t, result.Events)
+	assert.Equal(t, protocol.EventTaskStatusUpdate, result.Events[0].Type)
+	assert.False(t, result.Done)
+
+	// Without events, a poll returns once its wait elapses.
+	start := time.Now()
+	idle, rpcErr := pollEvents(t, testServer, protocol.PollEventsParams{
+		ID: "task-1", Cursor: result.Cursor, WaitMs: 100,
+	})
+	require.Nil(t, rpcErr)
+	assert.Empty(t, idle.Events)
+	assert.Equal(t, result.Cursor, idle.Cursor)
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+
+	// A waiting poll returns as soon as events arrive.
+	time.AfterFunc(50*time.Millisecond, func() { close(processor.release) })
+	var events []protocol.PolledEvent
+	cursor := result.Cursor
+	for done := false; !done; {
+		result, rpcErr = pollEvents(t, testServer, protocol.PollEventsParams{
+			ID: "task-1", Cursor: cursor, WaitMs: 5000,
+		})
+		require.Nil(t, rpcErr)
+		events = append(events, result.Events...)
+		cursor, done = result.Cursor, result.Done
+	}
+	require.Len(t, events, 2)
+	assert.Equal(t, protocol.EventTaskArtifactUpdate, events[0].Type)
+	assert.True(t, events[1].Event.IsFinal())
+
+	// The session ends with its final event.
+	_, rpcErr = pollEvents(t, testServer, protocol.PollEventsParams{ID: "task-1", Cursor: cursor})
+	require.NotNil(t, rpcErr)
+	assert.Equal(t, jsonrpc.CodeInvalidParams, rpcErr.Code)
+}
+
+// sseBlockingProxy rejects the stream requests of the JSON-RPC endpoint like
+// a proxy not supporting SSE.
+func sseBlockingProxy(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var request jsonrpc.Request
+		_ = json.Unmarshal(body, &request)
+		if request.Method == protocol.MethodTasksSendSubscribe || request.Method == protocol.MethodTasksResubscribe {
+			http.Error(w, "streaming not allowed", http.StatusBadGateway)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		next.ServeHTTP(w, r)
+	})
+}
+
+func TestA2AServer_LongPollingFallback(t *testing.T) {
+	tm, err := taskmanager.NewMemoryTaskManager(&gatedProcessor{release: make(chan struct{})})
+	require.NoError(t, err)
+	close(tm.Processor.(*gatedProcessor).release)
+	a2aServer, err := NewA2AServer(defaultAgentCard(), tm)
+	require.NoError(t, err)
+	testServer := httptest.NewServer(sseBlockingProxy(a2aServer.Handler()))
+	defer testServer.Close()
+	msg := protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")})
+
+	plain, err := client.NewA2AClient(testServer.URL)
+	require.NoError(t, err)
+	_, err = plain.StreamTask(context.Background(), protocol.SendTaskParams{ID: "task-1", Message: msg})
+	require.Error(t, err, "no fallback unless enabled")
+
+	c, err := client.NewA2AClient(testServer.URL, client.WithLongPollingFallback(time.Second))
+	require.NoError(t, err)
+	events, err := c.StreamTask(context.Background(), protocol.SendTaskParams{ID: "task-2", Message: msg})
+	require.NoError(t, err)
+	var last protocol.TaskEvent
+	for event := range events {
+		last = event
+	}
+	require.NotNil(t, last)
+	status, ok := last.(protocol.TaskStatusUpdateEvent)
+	require.True(t, ok)
+	assert.Equal(t, protocol.TaskStateCompleted, status.Status.State)
+
+	// A task known to the agent is sent again rather than only polled: the
+	// agent refuses to send a completed task again, ending the stream.
+	events, err = c.StreamTask(context.Background(), protocol.SendTaskParams{ID: "task-2", Message: msg})
+	require.NoError(t, err)
+	var resent []protocol.TaskEvent
+	for event := range events {
+		resent = append(resent, event)
+	}
+	assert.Empty(t, resent)
+
+	events, err = c.ResubscribeTask(context.Background(), protocol.TaskIDParams{ID: "task-2"})
+	require.NoError(t, err)
+	var count int
+	for event := range events {
+		count++
+		assert.True(t, event.IsFinal())
+	}
+	assert.Equal(t, 1, count)
+}
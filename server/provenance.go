@@ -0,0 +1,53 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"trpc.group/trpc-go/trpc-a2a-go/auth"
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// checkProvenance verifies the provenance chain in the params of a send request
+// and returns a context carrying it, so that processors delegating the task can
+// extend it. The chain itself is recorded in the task metadata with the rest of
+// the params metadata.
+func (s *A2AServer) checkProvenance(
+	ctx context.Context,
+	params protocol.SendTaskParams,
+) (context.Context, *jsonrpc.Error) {
+	if s.provenanceVerifier == nil {
+		return ctx, nil
+	}
+	chain, err := auth.ProvenanceFromMetadata(params.Metadata)
+	if err != nil {
+		return ctx, jsonrpc.ErrInvalidParams(err.Error())
+	}
+	last, ok := chain.Last()
+	if !ok {
+		if s.provenanceRequired {
+			return ctx, jsonrpc.ErrInvalidParams("provenance chain is required")
+		}
+		return ctx, nil
+	}
+	if err := s.provenanceVerifier.Verify(ctx, chain); err != nil {
+		log.Warnf("Rejecting task %s with invalid provenance: %v", params.ID, err)
+		return ctx, jsonrpc.ErrInvalidParams(fmt.Sprintf("invalid provenance chain: %v", err))
+	}
+	if last.TaskID != params.ID {
+		return ctx, jsonrpc.ErrInvalidParams(
+			fmt.Sprintf("provenance chain was issued for task %q, not %q", last.TaskID, params.ID))
+	}
+	origin, _ := chain.Origin()
+	log.Infof("Verified provenance of task %s: origin %s (caller %q), %d hops, last %s",
+		params.ID, origin.Agent, origin.Caller, len(chain), last.Agent)
+	return auth.ContextWithProvenance(ctx, chain), nil
+}
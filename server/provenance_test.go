@@ -0,0 +1,90 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"context"
+	"crypto/ed25519"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/auth"
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// provenanceTaskManager records the provenance chain seen by OnSendTask.
+type provenanceTaskManager struct {
+	*mockTaskManager
+	chain auth.ProvenanceChain
+}
+
+func (m *provenanceTaskManager) OnSendTask(
+	ctx context.Context,
+	params protocol.SendTaskParams,
+) (*protocol.Task, error) {
+	m.chain = auth.ProvenanceFromContext(ctx)
+	return &protocol.Task{ID: params.ID, Status: protocol.TaskStatus{State: protocol.TaskStateWorking}}, nil
+}
+
+var _ taskmanager.TaskManager = (*provenanceTaskManager)(nil)
+
+func TestA2AServer_Provenance(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	signer := auth.NewProvenanceSigner("planner", "k1", priv)
+	verifier := &auth.ProvenanceVerifier{Keys: auth.StaticProvenanceKeys{"k1": pub}}
+
+	tm := &provenanceTaskManager{mockTaskManager: newMockTaskManager()}
+	a2aServer, err := NewA2AServer(defaultAgentCard(), tm, WithProvenanceVerifier(verifier, true))
+	require.NoError(t, err)
+	testServer := httptest.NewServer(http.HandlerFunc(a2aServer.handleJSONRPC))
+	defer testServer.Close()
+
+	msg := protocol.Message{Role: protocol.MessageRoleUser, Parts: []protocol.Part{protocol.NewTextPart("hi")}}
+	send := func(taskID string, chain auth.ProvenanceChain) *jsonrpc.Response {
+		params := protocol.SendTaskParams{ID: taskID, Message: msg}
+		if chain != nil {
+			params.Metadata = map[string]interface{}{auth.MetadataKeyProvenance: chain}
+		}
+		return performJSONRPCRequest(t, testServer, protocol.MethodTasksSend, params, taskID)
+	}
+
+	chain, err := signer.Extend(nil, "alice", "task-1")
+	require.NoError(t, err)
+
+	t.Run("valid chain", func(t *testing.T) {
+		resp := send("task-1", chain)
+		require.Nil(t, resp.Error)
+		assert.Equal(t, chain, tm.chain)
+	})
+
+	t.Run("missing chain", func(t *testing.T) {
+		resp := send("task-2", nil)
+		require.NotNil(t, resp.Error)
+		assert.Equal(t, jsonrpc.CodeInvalidParams, resp.Error.Code)
+	})
+
+	t.Run("tampered chain", func(t *testing.T) {
+		tampered := append(auth.ProvenanceChain(nil), chain...)
+		tampered[0].Caller = "mallory"
+		resp := send("task-1", tampered)
+		require.NotNil(t, resp.Error)
+		assert.Equal(t, jsonrpc.CodeInvalidParams, resp.Error.Code)
+	})
+
+	t.Run("chain for another task", func(t *testing.T) {
+		resp := send("task-3", chain)
+		require.NotNil(t, resp.Error)
+		assert.Contains(t, resp.Error.Data, "task-1")
+	})
+}
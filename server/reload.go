@@ -0,0 +1,125 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/auth"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// AgentCard returns the agent card currently served, advertising the custom
+// methods registered and the skills of the task manager,
+// if it is a SkillProvider.
+func (s *A2AServer) AgentCard() AgentCard {
+	s.liveMu.RLock()
+	defer s.liveMu.RUnlock()
+	card := s.agentCard
+	if provider, ok := s.taskManager.(SkillProvider); ok {
+		card.Skills = mergeSkills(card.Skills, provider.Skills())
+	}
+	if len(s.methods) > 0 {
+		methods := make([]AgentMethod, 0, len(card.Methods)+len(s.methods))
+		methods = append(methods, card.Methods...)
+		card.Methods = append(methods, s.methodDescriptors()...)
+	}
+	return card
+}
+
+// agentCardBroadcastTimeout bounds the notification of the clients of a new
+// agent card by SetAgentCard.
+const agentCardBroadcastTimeout = 10 * time.Second
+
+// SetAgentCard replaces the agent card served, without restarting the server,
+// and notifies the clients connected over WebSocket of the new card in the
+// background, giving up on those not notified within 10 seconds.
+func (s *A2AServer) SetAgentCard(card AgentCard) {
+	s.liveMu.Lock()
+	s.agentCard = card
+	s.liveMu.Unlock()
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), agentCardBroadcastTimeout)
+		defer cancel()
+		// The card is read once the previous notifications are sent, so that
+		// the clients end with the last card when it changes meanwhile.
+		s.cardBroadcastMu.Lock()
+		defer s.cardBroadcastMu.Unlock()
+		s.broadcast(ctx, protocol.MethodAgentCardUpdated, s.AgentCard())
+	}()
+}
+
+// NotifyAgentCardUpdated sends the agent card currently served to the clients
+// connected over WebSocket in a protocol.MethodAgentCardUpdated notification,
+// so that they do not need to poll it, and returns the number of clients
+// notified. SetAgentCard does the same in the background; it is meant for
+// changes of the card made otherwise, such as skills registered with the
+// SkillRouter of the server.
+func (s *A2AServer) NotifyAgentCardUpdated() int {
+	return s.Broadcast(protocol.MethodAgentCardUpdated, s.AgentCard())
+}
+
+// SetAuthProvider replaces the authentication provider of the JSON-RPC
+// endpoint, or disables authentication if provider is nil. It applies to new
+// requests; streams already open are not affected.
+func (s *A2AServer) SetAuthProvider(provider auth.Provider) {
+	var middleware *auth.Middleware
+	if provider != nil {
+		middleware = auth.NewMiddleware(provider)
+	}
+	s.liveMu.Lock()
+	defer s.liveMu.Unlock()
+	s.authProvider = provider
+	s.authMiddleware = middleware
+}
+
+// SetMinDeadlineBudget changes the minimum deadline budget set by
+// WithMinDeadlineBudget for new requests.
+func (s *A2AServer) SetMinDeadlineBudget(min time.Duration) {
+	s.liveMu.Lock()
+	defer s.liveMu.Unlock()
+	s.minDeadlineBudget = min
+}
+
+// SetSSEWriteTimeout changes the SSE write timeout set by WithSSEWriteTimeout
+// for streams opened from now on.
+func (s *A2AServer) SetSSEWriteTimeout(timeout time.Duration) {
+	s.liveMu.Lock()
+	defer s.liveMu.Unlock()
+	s.sseWriteTimeout = timeout
+}
+
+// authenticated wraps next with the authentication middleware current at the
+// time of each request, so that the auth provider can change at runtime.
+func (s *A2AServer) authenticated(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.liveMu.RLock()
+		middleware := s.authMiddleware
+		s.liveMu.RUnlock()
+		if middleware == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		middleware.Wrap(next).ServeHTTP(w, r)
+	})
+}
+
+// currentMinDeadlineBudget returns the minimum deadline budget of send requests.
+func (s *A2AServer) currentMinDeadlineBudget() time.Duration {
+	s.liveMu.RLock()
+	defer s.liveMu.RUnlock()
+	return s.minDeadlineBudget
+}
+
+// currentSSEWriteTimeout returns the write timeout of new SSE streams.
+func (s *A2AServer) currentSSEWriteTimeout() time.Duration {
+	s.liveMu.RLock()
+	defer s.liveMu.RUnlock()
+	return s.sseWriteTimeout
+}
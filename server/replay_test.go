@@ -0,0 +1,78 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/client"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+func TestA2AServer_EventIDsAndDeduplication(t *testing.T) {
+	tm, err := taskmanager.NewMemoryTaskManager(&countingProcessor{},
+		taskmanager.WithEventReplay(taskmanager.NewMemoryEventHistory(0)))
+	require.NoError(t, err)
+	a2aServer, err := NewA2AServer(defaultAgentCard(), tm)
+	require.NoError(t, err)
+	testServer := httptest.NewServer(a2aServer.Handler())
+	defer testServer.Close()
+
+	c, err := client.NewA2AClient(testServer.URL, client.WithEventDeduplication(0))
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	msg := protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")})
+	events, err := c.StreamTask(ctx, protocol.SendTaskParams{ID: "task-1", Message: msg})
+	require.NoError(t, err)
+	var ids []string
+	for event := range events {
+		ids = append(ids, protocol.EventID(event))
+		if event.IsFinal() {
+			break
+		}
+	}
+	cancel()
+	assert.Equal(t, []string{"1", "2"}, ids)
+	assert.Equal(t, "2", c.LastEventID("task-1"))
+
+	// Replayed events the client already delivered are skipped.
+	replayed, err := c.ResubscribeTask(context.Background(), protocol.TaskIDParams{ID: "task-1", LastEventID: "0"})
+	require.NoError(t, err)
+	for event := range replayed {
+		t.Errorf("duplicate event delivered: %+v", event)
+	}
+
+	// Without deduplication, the replayed events are delivered.
+	other, err := client.NewA2AClient(testServer.URL)
+	require.NoError(t, err)
+	replayed, err = other.ResubscribeTask(context.Background(), protocol.TaskIDParams{ID: "task-1", LastEventID: "1"})
+	require.NoError(t, err)
+	ids = nil
+	for event := range replayed {
+		ids = append(ids, protocol.EventID(event))
+	}
+	assert.Equal(t, []string{"2"}, ids)
+	assert.Empty(t, other.LastEventID("task-1"))
+
+	// The event IDs are sent in the SSE id field.
+	resp, err := http.Post(testServer.URL, "application/json", strings.NewReader(
+		`{"jsonrpc":"2.0","id":1,"method":"tasks/resubscribe","params":{"id":"task-1","lastEventId":"1"}}`))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "id: 2\n")
+}
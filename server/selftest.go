@@ -0,0 +1,194 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// defaultSelfTestTimeout bounds a self test whose context has no deadline.
+const defaultSelfTestTimeout = 10 * time.Second
+
+// Self test steps.
+const (
+	SelfTestStepSend   = "send"
+	SelfTestStepStream = "stream"
+	SelfTestStepGet    = "get"
+	SelfTestStepCancel = "cancel"
+)
+
+// SelfTestError reports the step of a self test that failed.
+type SelfTestError struct {
+	// Step is the failed step, one of the SelfTestStep constants.
+	Step string
+	// Err is the cause of the failure.
+	Err error
+}
+
+// Error implements error.
+func (e *SelfTestError) Error() string {
+	return fmt.Sprintf("self test failed at %s: %v", e.Step, e.Err)
+}
+
+// Unwrap returns the cause of the failure.
+func (e *SelfTestError) Unwrap() error {
+	return e.Err
+}
+
+// readiness holds the outcome of the last self test.
+type readiness struct {
+	mu     sync.RWMutex
+	tested bool
+	err    error
+}
+
+// SelfTest runs a send, stream, get and cancel cycle against the task manager
+// and returns a *SelfTestError for the first step that breaks, e.g. because
+// the storage backend is misconfigured. The tasks are processed with a context
+// marked by taskmanager.ContextWithSandbox, so processors can skip side
+// effects, and are prefixed with "selftest-". They are deleted afterwards if
+// the task manager implements taskmanager.TaskDeleter. The outcome is
+// reported by the readiness endpoint, see WithReadinessEndpoint.
+func (s *A2AServer) SelfTest(ctx context.Context) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultSelfTestTimeout)
+		defer cancel()
+	}
+	err := s.selfTest(taskmanager.ContextWithSandbox(ctx))
+	s.readiness.mu.Lock()
+	s.readiness.tested, s.readiness.err = true, err
+	s.readiness.mu.Unlock()
+	if err != nil {
+		log.Errorf("A2A server %v", err)
+		return err
+	}
+	log.Info("A2A server self test passed.")
+	return nil
+}
+
+// selfTest runs the steps of SelfTest.
+func (s *A2AServer) selfTest(ctx context.Context) error {
+	sendID, err := selfTestTaskID()
+	if err != nil {
+		return &SelfTestError{Step: SelfTestStepSend, Err: err}
+	}
+	streamID, err := selfTestTaskID()
+	if err != nil {
+		return &SelfTestError{Step: SelfTestStepStream, Err: err}
+	}
+	defer s.deleteSelfTestTasks(ctx, sendID, streamID)
+	message := protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("self test")})
+
+	task, err := s.taskManager.OnSendTask(ctx, protocol.SendTaskParams{ID: sendID, Message: message})
+	if err == nil && (task == nil || task.ID != sendID) {
+		err = fmt.Errorf("task manager did not return task %s", sendID)
+	}
+	if err != nil {
+		return &SelfTestError{Step: SelfTestStepSend, Err: err}
+	}
+
+	if err := s.selfTestStream(ctx, streamID, message); err != nil {
+		return &SelfTestError{Step: SelfTestStepStream, Err: err}
+	}
+
+	task, err = s.taskManager.OnGetTask(ctx, protocol.TaskQueryParams{ID: sendID})
+	if err == nil && (task == nil || task.ID != sendID) {
+		err = fmt.Errorf("task manager did not return task %s", sendID)
+	}
+	if err != nil {
+		return &SelfTestError{Step: SelfTestStepGet, Err: err}
+	}
+
+	// The streamed task may have finished already, which is fine as long as
+	// the task manager found it.
+	_, err = s.taskManager.OnCancelTask(ctx, protocol.TaskIDParams{ID: streamID})
+	var rpcErr *jsonrpc.Error
+	if err != nil && !(errors.As(err, &rpcErr) && rpcErr.Code == taskmanager.ErrCodeTaskFinal) {
+		return &SelfTestError{Step: SelfTestStepCancel, Err: err}
+	}
+	return nil
+}
+
+// deleteSelfTestTasks deletes the tasks of a self test, if the task manager
+// can, even once the self test timed out. Failures are only logged: they do
+// not make the task manager unfit for traffic.
+func (s *A2AServer) deleteSelfTestTasks(ctx context.Context, taskIDs ...string) {
+	deleter, ok := s.taskManager.(taskmanager.TaskDeleter)
+	if !ok {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), defaultSelfTestTimeout)
+	defer cancel()
+	for _, taskID := range taskIDs {
+		err := deleter.DeleteTask(ctx, taskID)
+		var rpcErr *jsonrpc.Error
+		if err != nil && !(errors.As(err, &rpcErr) && rpcErr.Code == taskmanager.ErrCodeTaskNotFound) {
+			log.Warnf("Failed to delete self test task %s: %v", taskID, err)
+		}
+	}
+}
+
+// selfTestStream sends a streamed task and waits for its first event.
+func (s *A2AServer) selfTestStream(ctx context.Context, taskID string, message protocol.Message) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	events, err := s.taskManager.OnSendTaskSubscribe(ctx, protocol.SendTaskParams{ID: taskID, Message: message})
+	if err != nil {
+		return err
+	}
+	select {
+	case _, ok := <-events:
+		if !ok {
+			return errors.New("event stream closed without events")
+		}
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("no event received: %w", ctx.Err())
+	}
+}
+
+// selfTestTaskID returns a random self test task ID.
+func selfTestTaskID() (string, error) {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return "selftest-" + hex.EncodeToString(b[:]), nil
+}
+
+// handleReadiness reports whether the server is ready to take traffic: once
+// a self test passed, or if none is required at startup and none failed.
+func (s *A2AServer) handleReadiness(w http.ResponseWriter, r *http.Request) {
+	s.readiness.mu.RLock()
+	tested, err := s.readiness.tested, s.readiness.err
+	s.readiness.mu.RUnlock()
+	if err == nil && !tested && s.startupSelfTest {
+		err = errors.New("self test has not run")
+	}
+	w.Header().Set("Content-Type", "application/json")
+	body := map[string]interface{}{"ready": err == nil}
+	if err != nil {
+		body["error"] = err.Error()
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	if err := s.encodeJSON(w, body); err != nil {
+		log.Errorf("Failed to write readiness response: %v", err)
+	}
+}
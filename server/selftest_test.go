@@ -0,0 +1,67 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// brokenStoreTaskManager loses every task it is sent.
+type brokenStoreTaskManager struct {
+	*taskmanager.MemoryTaskManager
+}
+
+func (m brokenStoreTaskManager) OnGetTask(ctx context.Context, params protocol.TaskQueryParams) (*protocol.Task, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestA2AServer_SelfTest(t *testing.T) {
+	tm, err := taskmanager.NewMemoryTaskManager(&countingProcessor{})
+	require.NoError(t, err)
+	a2aServer, err := NewA2AServer(defaultAgentCard(), tm, WithStartupSelfTest(), WithReadinessEndpoint("/ready"))
+	require.NoError(t, err)
+	testServer := httptest.NewServer(a2aServer.Handler())
+	defer testServer.Close()
+
+	ready := func() int {
+		resp, err := http.Get(testServer.URL + "/ready")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+	assert.Equal(t, http.StatusServiceUnavailable, ready(), "not ready before the startup self test")
+	require.NoError(t, a2aServer.SelfTest(context.Background()))
+	assert.Equal(t, http.StatusOK, ready())
+	tasks, err := tm.ListTasks(context.Background(), taskmanager.TaskFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, tasks, "self test tasks deleted")
+
+	memory, err := taskmanager.NewMemoryTaskManager(&countingProcessor{})
+	require.NoError(t, err)
+	broken, err := NewA2AServer(defaultAgentCard(), brokenStoreTaskManager{memory}, WithReadinessEndpoint("/ready"))
+	require.NoError(t, err)
+	brokenServer := httptest.NewServer(broken.Handler())
+	defer brokenServer.Close()
+	err = broken.SelfTest(context.Background())
+	var selfTestErr *SelfTestError
+	require.ErrorAs(t, err, &selfTestErr)
+	assert.Equal(t, SelfTestStepGet, selfTestErr.Step)
+	resp, err := http.Get(brokenServer.URL + "/ready")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+}
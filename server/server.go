@@ -8,6 +8,7 @@
 package server
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -15,31 +16,74 @@ import (
 	"io"
 	"mime"
 	"net/http"
+	"sync"
 	"time"
 
 	"trpc.group/trpc-go/trpc-a2a-go/auth"
+	"trpc.group/trpc-go/trpc-a2a-go/codec"
 	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
-	"trpc.group/trpc-go/trpc-a2a-go/internal/sse"
+	"trpc.group/trpc-go/trpc-a2a-go/internal/ndjson"
+	"trpc.group/trpc-go/trpc-a2a-go/internal/websocket"
 	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/metadata"
 	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/sse"
 	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
 )
 
 // A2AServer implements the HTTP server for the A2A protocol.
 // It handles agent card requests and routes JSON-RPC calls to the TaskManager.
 type A2AServer struct {
-	agentCard       AgentCard               // Metadata for this agent.
-	taskManager     taskmanager.TaskManager // Handles task logic.
-	httpServer      *http.Server            // Underlying HTTP server.
-	corsEnabled     bool                    // Flag to enable/disable CORS headers.
-	jsonRPCEndpoint string                  // Path for the JSON-RPC endpoint.
-	readTimeout     time.Duration           // HTTP server read timeout.
-	writeTimeout    time.Duration           // HTTP server write timeout.
-	idleTimeout     time.Duration           // HTTP server idle timeout.
+	agentCard          AgentCard                  // Metadata for this agent.
+	taskManager        taskmanager.TaskManager    // Handles task logic.
+	httpServer         *http.Server               // Underlying HTTP server.
+	corsEnabled        bool                       // Flag to enable/disable CORS headers.
+	jsonRPCEndpoint    string                     // Path for the JSON-RPC endpoint.
+	readTimeout        time.Duration              // HTTP server read timeout.
+	writeTimeout       time.Duration              // HTTP server write timeout.
+	idleTimeout        time.Duration              // HTTP server idle timeout.
+	validateParams     bool                       // Flag to enable/disable schema validation of params.
+	codec              codec.Codec                // JSON codec for requests, responses and SSE events.
+	sseWriteTimeout    time.Duration              // Per-event write deadline for SSE streams.
+	sseEvents          *eventCache                // Shares event encodings between SSE streams.
+	compression        bool                       // Flag to enable/disable response compression.
+	compressLevel      int                        // Compression level for gzip/deflate responses.
+	partFailurePolicy  protocol.PartFailurePolicy // How messages with some invalid parts are handled.
+	maxRequestBytes    int64                      // Maximum size of request bodies, if positive.
+	maxPartBytes       int64                      // Maximum size of message parts, if positive.
+	provenanceVerifier *auth.ProvenanceVerifier   // Verifies provenance chains of send requests, if set.
+	provenanceRequired bool                       // Whether send requests must carry a provenance chain.
+	minDeadlineBudget  time.Duration              // Minimum deadline budget accepted for send requests.
+	exampleProcessor   taskmanager.TaskProcessor  // Runs skill examples, if set.
+	deprecations       []protocol.Deprecation     // Deprecated methods and params fields.
+	acks               *ackTracker                // Tracks event acknowledgements, if enabled.
+	polls              *pollSessions              // Buffers task events between long polls.
+	startupSelfTest    bool                       // Whether Start runs a self test before serving.
+	readinessPath      string                     // Path of the readiness endpoint, if enabled.
+	readiness          readiness                  // Outcome of the last self test.
+	compressor         *compressor                // Compresses responses when enabled.
+	tlsCertFile        string                     // TLS certificate file, if serving HTTPS.
+	tlsKeyFile         string                     // TLS private key file, if serving HTTPS.
+	liveMu             sync.RWMutex               // Guards the settings changed by reloads.
+	cardBroadcastMu    sync.Mutex                 // Serializes the notifications of agent card changes.
+	admin              *adminAPI                  // Admin API settings, if enabled.
+	idGenerator        protocol.IDGenerator       // Generates the IDs of tasks sent without one.
+	autoTaskIDs        bool                       // Whether tasks sent without ID get a generated one.
+	methods            map[string]Method          // Custom JSON-RPC methods, by name.
+	pendingMethods     []Method                   // Custom methods of WithMethods, registered by NewA2AServer.
+	webSocket          bool                       // Whether JSON-RPC over WebSocket is enabled.
+	wsOrigins          []string                   // Origins allowed to connect over WebSocket besides the server's.
+	wsPeers            wsPeers                    // Clients connected over WebSocket.
+	agentCardMaxAge    time.Duration              // How long clients may use the agent card without revalidating it.
+	cardValidators     cardValidators             // ETag and modification time of the agent card.
+	uploads            *uploads                   // Resumable uploads endpoint, if enabled.
+	maxUploadBytes     int64                      // Maximum size of uploads; 0 for the default, negative for none.
+	artifactPath       string                     // Path of the artifact download endpoint, if enabled.
 
 	// Authentication related fields
 	authProvider   auth.Provider                       // Authentication provider.
 	authMiddleware *auth.Middleware                    // Authentication middleware.
+	hmacVerifier   *auth.HMACVerifier                  // Verifies request signatures, if set.
 	pushAuth       *auth.PushNotificationAuthenticator // Push notification authenticator.
 	jwksEnabled    bool                                // Flag to enable/disable JWKS endpoint.
 	jwksEndpoint   string                              // Path for the JWKS endpoint.
@@ -60,12 +104,30 @@ func NewA2AServer(agentCard AgentCard, taskManager taskmanager.TaskManager, opts
 		readTimeout:     defaultReadTimeout,
 		writeTimeout:    defaultWriteTimeout,
 		idleTimeout:     defaultIdleTimeout,
+		validateParams:  true,
+		codec:           codec.Default,
 		jwksEnabled:     false,
 		jwksEndpoint:    protocol.JWKSPath,
+		idGenerator:     protocol.DefaultIDGenerator,
+		autoTaskIDs:     true,
+		polls:           newPollSessions(),
 	}
 	for _, opt := range opts {
 		opt(server)
 	}
+	server.sseEvents = newEventCache(server.codec, 0)
+	for _, m := range server.pendingMethods {
+		if err := server.RegisterMethod(m); err != nil {
+			return nil, fmt.Errorf("invalid custom method: %w", err)
+		}
+	}
+	if server.compression {
+		c, err := newCompressor(server.compressLevel)
+		if err != nil {
+			return nil, fmt.Errorf("invalid compression level %d: %w", server.compressLevel, err)
+		}
+		server.compressor = c
+	}
 	// Initialize authentication components if auth provider is set.
 	if server.authProvider != nil {
 		server.authMiddleware = auth.NewMiddleware(server.authProvider)
@@ -82,7 +144,14 @@ func NewA2AServer(agentCard AgentCard, taskManager taskmanager.TaskManager, opts
 
 // Start begins listening for HTTP requests on the specified network address.
 // It blocks until the server is stopped via Stop() or an error occurs.
+// With WithStartupSelfTest, it first runs SelfTest and fails if it does.
+// With WithTLS, it serves HTTPS.
 func (s *A2AServer) Start(address string) error {
+	if s.startupSelfTest {
+		if err := s.SelfTest(context.Background()); err != nil {
+			return err
+		}
+	}
 	s.httpServer = &http.Server{
 		Addr:         address,
 		Handler:      s.Handler(),
@@ -93,7 +162,13 @@ func (s *A2AServer) Start(address string) error {
 
 	log.Infof("Starting A2A server listening on %s...", address)
 	// ListenAndServe blocks. It returns http.ErrServerClosed on graceful shutdown.
-	if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+	var err error
+	if s.tlsCertFile != "" {
+		err = s.httpServer.ListenAndServeTLS(s.tlsCertFile, s.tlsKeyFile)
+	} else {
+		err = s.httpServer.ListenAndServe()
+	}
+	if err != nil && err != http.ErrServerClosed {
 		return fmt.Errorf("http server ListenAndServe error: %w", err)
 	}
 	log.Info("A2A server stopped.")
@@ -107,6 +182,9 @@ func (s *A2AServer) Stop(ctx context.Context) error {
 		return errors.New("A2A server not running")
 	}
 	log.Info("Attempting graceful shutdown of A2A server...")
+	defer s.polls.closeAll()
+	// Shutdown does not close the hijacked WebSocket connections.
+	s.wsPeers.closeAll()
 	if err := s.httpServer.Shutdown(ctx); err != nil {
 		return fmt.Errorf("http server shutdown failed: %w", err)
 	}
@@ -124,17 +202,44 @@ func (s *A2AServer) Handler() http.Handler {
 	if s.jwksEnabled && s.pushAuth != nil {
 		router.HandleFunc(s.jwksEndpoint, s.pushAuth.HandleJWKS)
 	}
+	// Readiness endpoint reporting the self test outcome, if enabled.
+	if s.readinessPath != "" {
+		router.HandleFunc(s.readinessPath, s.handleReadiness)
+	}
+	// Admin API under its own path prefix and authentication, if enabled.
+	if s.admin != nil && s.admin.prefix != "" {
+		router.Handle(s.admin.prefix+"/", http.StripPrefix(s.admin.prefix, s.AdminHandler(s.admin.provider)))
+	}
+	// Resumable uploads endpoint, with the authentication and request signing
+	// of the JSON-RPC endpoint.
+	if s.uploads != nil {
+		uploads := s.protected(s.uploadHandler())
+		router.Handle(s.uploads.path, uploads)
+		router.Handle(s.uploads.path+"/", uploads)
+	}
+	// Artifact download endpoint, with the authentication and request signing
+	// of the JSON-RPC endpoint.
+	if s.artifactPath != "" {
+		router.Handle(s.artifactPath+"/", s.protected(s.artifactHandler()))
+	}
 	// Main JSON-RPC endpoint (configurable path) with optional authentication.
-	if s.authMiddleware != nil {
-		// Apply authentication middleware to JSON-RPC endpoint.
-		router.Handle(s.jsonRPCEndpoint, s.authMiddleware.Wrap(http.HandlerFunc(s.handleJSONRPC)))
-	} else {
-		// No authentication required.
-		router.HandleFunc(s.jsonRPCEndpoint, s.handleJSONRPC)
+	router.Handle(s.jsonRPCEndpoint, s.protected(http.HandlerFunc(s.handleJSONRPC)))
+	if s.compressor != nil {
+		return s.compressor.wrap(router)
 	}
 	return router
 }
 
+// protected wraps next with the authentication of the server and, if enabled,
+// the verification of request signatures.
+func (s *A2AServer) protected(next http.Handler) http.Handler {
+	handler := s.authenticated(next)
+	if s.hmacVerifier != nil {
+		handler = s.hmacVerifier.Wrap(handler)
+	}
+	return handler
+}
+
 // handleAgentCard serves the agent's metadata card as JSON.
 // Corresponds to GET /.well-known/agent.json in A2A Spec.
 func (s *A2AServer) handleAgentCard(w http.ResponseWriter, r *http.Request) {
@@ -145,12 +250,25 @@ func (s *A2AServer) handleAgentCard(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
 		return
 	}
-	w.Header().Set("Content-Type", "application/json; charset=utf-8")
-	if err := json.NewEncoder(w).Encode(s.agentCard); err != nil {
+	body, err := s.codec.Marshal(s.AgentCard())
+	if err != nil {
 		log.Errorf("Failed to encode agent card: %v", err)
 		// Avoid writing JSON-RPC error here; it's a standard HTTP endpoint.
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
 	}
+	body = append(body, '\n')
+	// Clients revalidate their cached card with If-None-Match or
+	// If-Modified-Since, answered by ServeContent with 304 Not Modified.
+	etag, modified := s.cardValidators.validate(body)
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("ETag", etag)
+	if s.agentCardMaxAge > 0 {
+		w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%d", int(s.agentCardMaxAge.Seconds())))
+	} else {
+		w.Header().Set("Cache-Control", "no-cache")
+	}
+	http.ServeContent(w, r, "", modified, bytes.NewReader(body))
 }
 
 // handleJSONRPC is the main handler for all JSON-RPC 2.0 requests.
@@ -166,19 +284,37 @@ func (s *A2AServer) handleJSONRPC(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if s.webSocket && websocket.IsUpgrade(r) {
+		s.handleWebSocket(w, r)
+		return
+	}
+
 	// Validate request basics
 	if !s.validateJSONRPCRequest(w, r) {
 		return
 	}
 
 	// Read and parse JSON-RPC request
-	request, err := s.parseJSONRPCRequest(w, r.Body)
+	if s.maxRequestBytes > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, s.maxRequestBytes)
+	}
+	request, notification, err := s.parseJSONRPCRequest(w, r.Body)
 	if err != nil {
 		return
 	}
+	// Expose the metadata headers to handlers and the task manager.
+	ctx := r.Context()
+	if md := metadata.FromHeader(r.Header); md.Len() > 0 {
+		ctx = metadata.NewIncomingContext(ctx, md)
+	}
+	ctx = withStreamFormat(ctx, r.Header.Values("Accept"))
+	if notification {
+		s.handleNotification(ctx, w, request)
+		return
+	}
 
 	// Route to appropriate handler based on method
-	s.routeJSONRPCMethod(r.Context(), w, request)
+	s.routeJSONRPCMethod(taskmanager.ContextWithWarnings(ctx), w, request)
 }
 
 // validateJSONRPCRequest validates basic HTTP requirements for JSON-RPC.
@@ -206,42 +342,74 @@ func (s *A2AServer) validateJSONRPCRequest(w http.ResponseWriter, r *http.Reques
 }
 
 // parseJSONRPCRequest reads the request body and parses it into a JSON-RPC request.
-// Returns the request and nil if successful, or nil and error if parsing failed.
-func (s *A2AServer) parseJSONRPCRequest(w http.ResponseWriter, body io.ReadCloser) (jsonrpc.Request, error) {
-	var request jsonrpc.Request
-
-	// Read the request body
-	bodyBytes, err := io.ReadAll(body)
-	if err != nil {
-		s.writeJSONRPCError(w, nil,
-			jsonrpc.ErrParseError(fmt.Sprintf("failed to read request body: %v", err)))
-		return request, err
-	}
-
-	// It's important to close the body, even though ReadAll consumes it
+// Returns the request, whether it is a notification and nil if successful, or
+// an error if parsing failed.
+func (s *A2AServer) parseJSONRPCRequest(w http.ResponseWriter, body io.ReadCloser) (jsonrpc.Request, bool, error) {
 	defer body.Close()
 
 	// Parse the JSON request
-	if err := json.Unmarshal(bodyBytes, &request); err != nil {
+	request, notification, err := s.decodeJSONRPCRequest(body)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			s.writeJSONRPCError(w, nil, taskmanager.ErrPayloadTooLarge("Request body", tooLarge.Limit))
+			return request, false, err
+		}
 		s.writeJSONRPCError(w, nil,
 			jsonrpc.ErrParseError(fmt.Sprintf("failed to parse JSON request: %v", err)))
-		return request, err
+		return request, false, err
 	}
 
 	// Validate JSON-RPC version
 	if request.JSONRPC != jsonrpc.Version {
 		s.writeJSONRPCError(w, request.ID,
 			jsonrpc.ErrInvalidRequest(fmt.Sprintf("jsonrpc field must be '%s'", jsonrpc.Version)))
-		return request, fmt.Errorf("invalid JSON-RPC version")
+		return request, false, fmt.Errorf("invalid JSON-RPC version")
 	}
 
-	return request, nil
+	return request, notification, nil
+}
+
+// decodeJSONRPCRequest decodes body into a JSON-RPC request. With a codec
+// implementing codec.StreamDecoder, the body is decoded from the reader rather
+// than read whole first; the decoder still buffers the request, and its params
+// are kept raw for the method handlers.
+func (s *A2AServer) decodeJSONRPCRequest(body io.Reader) (jsonrpc.Request, bool, error) {
+	if decoder, ok := s.codec.(codec.StreamDecoder); ok {
+		return jsonrpc.DecodeRequest(body, decoder.Decode)
+	}
+	bodyBytes, err := io.ReadAll(body)
+	if err != nil {
+		return jsonrpc.Request{}, false, err
+	}
+	var request jsonrpc.Request
+	if err := s.codec.Unmarshal(bodyBytes, &request); err != nil {
+		return request, false, err
+	}
+	return request, jsonrpc.IsNotification(bodyBytes), nil
 }
 
 // routeJSONRPCMethod routes the request to the appropriate handler based on the method.
 func (s *A2AServer) routeJSONRPCMethod(ctx context.Context, w http.ResponseWriter, request jsonrpc.Request) {
 	log.Infof("Received JSON-RPC request (ID: %v, Method: %s)", request.ID, request.Method)
 
+	// Reject params that violate the A2A schema before they reach the task manager.
+	if s.validateParams {
+		params, err := checkParams(request.Method, request.Params, s.partFailurePolicy)
+		if err != nil {
+			log.Warnf("Rejecting invalid params (ID: %v, Method: %s): %v", request.ID, request.Method, err.Data)
+			s.writeJSONRPCError(w, request.ID, err)
+			return
+		}
+		request.Params = params
+	}
+	if err := checkPartSizes(request, s.maxPartBytes); err != nil {
+		log.Warnf("Rejecting oversized params (ID: %v, Method: %s): %v", request.ID, request.Method, err.Data)
+		s.writeJSONRPCError(w, request.ID, err)
+		return
+	}
+	s.signalDeprecations(ctx, w, request)
+
 	switch request.Method {
 	case protocol.MethodTasksSend: // A2A Spec: tasks/send
 		s.handleTasksSend(ctx, w, request)
@@ -257,7 +425,23 @@ func (s *A2AServer) routeJSONRPCMethod(ctx context.Context, w http.ResponseWrite
 		s.handleTasksPushNotificationGet(ctx, w, request)
 	case protocol.MethodTasksResubscribe: // A2A Spec: tasks/resubscribe
 		s.handleTasksResubscribe(ctx, w, request)
+	case protocol.MethodSkillsExamplesList: // Extension: skills/examples/list
+		s.handleSkillsExamplesList(ctx, w, request)
+	case protocol.MethodSkillsExamplesRun: // Extension: skills/examples/run
+		s.handleSkillsExamplesRun(ctx, w, request)
+	case protocol.MethodTasksAckEvents: // Extension: tasks/ackEvents
+		s.handleTasksAckEvents(ctx, w, request)
+	case protocol.MethodTasksPollEvents: // Extension: tasks/pollEvents
+		s.handleTasksPollEvents(ctx, w, request)
+	case protocol.MethodTasksGraphSend: // Extension: tasks/graph/send
+		s.handleTasksGraphSend(ctx, w, request)
+	case protocol.MethodTasksGraphGet: // Extension: tasks/graph/get
+		s.handleTasksGraphGet(ctx, w, request)
 	default:
+		if m, ok := s.method(request.Method); ok {
+			s.handleCustomMethod(ctx, w, request, m)
+			return
+		}
 		log.Warnf("Method not found: %s (Request ID: %v)", request.Method, request.ID)
 		s.writeJSONRPCError(w, request.ID,
 			jsonrpc.ErrMethodNotFound(fmt.Sprintf("method '%s' not supported", request.Method)))
@@ -267,7 +451,7 @@ func (s *A2AServer) routeJSONRPCMethod(ctx context.Context, w http.ResponseWrite
 // unmarshalParams is a helper function to unmarshal JSON-RPC params into the provided struct.
 // It returns an error if unmarshalling fails, which is already formatted as a JSON-RPC error.
 func (s *A2AServer) unmarshalParams(params json.RawMessage, v interface{}) *jsonrpc.Error {
-	if err := json.Unmarshal(params, v); err != nil {
+	if err := s.codec.Unmarshal(params, v); err != nil {
 		return jsonrpc.ErrInvalidParams(fmt.Sprintf("failed to parse params: %v", err))
 	}
 	return nil
@@ -280,6 +464,30 @@ func (s *A2AServer) handleTasksSend(ctx context.Context, w http.ResponseWriter,
 		s.writeJSONRPCError(w, request.ID, err)
 		return
 	}
+	if rpcErr := s.assignTaskID(w, &params); rpcErr != nil {
+		s.writeJSONRPCError(w, request.ID, rpcErr)
+		return
+	}
+	ctx, cancel, rpcErr := s.applyDeadlineBudget(ctx, params)
+	if rpcErr != nil {
+		s.writeJSONRPCError(w, request.ID, rpcErr)
+		return
+	}
+	defer cancel()
+	ctx, rpcErr = s.checkProvenance(ctx, params)
+	if rpcErr != nil {
+		s.writeJSONRPCError(w, request.ID, rpcErr)
+		return
+	}
+	if params.DryRun {
+		task, rpcErr := s.planTask(ctx, params)
+		if rpcErr != nil {
+			s.writeJSONRPCError(w, request.ID, rpcErr)
+			return
+		}
+		s.writeJSONRPCResponse(w, request.ID, withWarnings(ctx, task))
+		return
+	}
 	// Delegate to the task manager.
 	task, err := s.taskManager.OnSendTask(ctx, params)
 	if err != nil {
@@ -294,7 +502,7 @@ func (s *A2AServer) handleTasksSend(ctx context.Context, w http.ResponseWriter,
 		}
 		return
 	}
-	s.writeJSONRPCResponse(w, request.ID, task)
+	s.writeJSONRPCResponse(w, request.ID, withWarnings(ctx, task))
 }
 
 // handleTasksGet handles the tasks_get method.
@@ -318,7 +526,8 @@ func (s *A2AServer) handleTasksGet(ctx context.Context, w http.ResponseWriter, r
 		}
 		return
 	}
-	s.writeJSONRPCResponse(w, request.ID, task)
+	task = withTaskVersion(task, params.IfModifiedSince)
+	s.writeJSONRPCResponse(w, request.ID, withWarnings(ctx, task))
 }
 
 // handleTasksCancel handles the tasks_cancel method.
@@ -340,11 +549,12 @@ func (s *A2AServer) handleTasksCancel(ctx context.Context, w http.ResponseWriter
 		}
 		return
 	}
-	s.writeJSONRPCResponse(w, request.ID, task)
+	s.writeJSONRPCResponse(w, request.ID, withWarnings(ctx, task))
 }
 
 // handleSSEStream handles an SSE stream for a task, including setup and event forwarding.
 // It sets the appropriate headers, logs connection status, and forwards events to the client.
+// Requests preferring newline-delimited JSON get the events in that format instead.
 func (s *A2AServer) handleSSEStream(
 	ctx context.Context,
 	w http.ResponseWriter,
@@ -355,12 +565,19 @@ func (s *A2AServer) handleSSEStream(
 	isResubscribe bool,
 ) {
 	// Set headers for SSE.
-	w.Header().Set("Content-Type", "text/event-stream")
+	useNDJSON := ndjsonStream(ctx)
+	if useNDJSON {
+		w.Header().Set("Content-Type", protocol.ContentTypeNDJSON)
+	} else {
+		w.Header().Set("Content-Type", protocol.ContentTypeSSE)
+	}
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
 	if s.corsEnabled {
 		s.setCORSHeaders(w)
 	}
+	acks := s.openAckStream(w, taskID)
+	defer s.closeAckStream(acks)
 
 	// Indicate successful subscription setup.
 	w.WriteHeader(http.StatusOK)
@@ -375,6 +592,10 @@ func (s *A2AServer) handleSSEStream(
 
 	// Use request context to detect client disconnection.
 	clientClosed := ctx.Done()
+	var sw eventStreamWriter = sse.NewWriter(w, s.codec, s.currentSSEWriteTimeout())
+	if useNDJSON {
+		sw = ndjson.NewWriter(w, s.codec, s.currentSSEWriteTimeout())
+	}
 
 	// --- Event Forwarding Loop ---
 	for {
@@ -389,10 +610,8 @@ func (s *A2AServer) handleSSEStream(
 					Reason: "task ended",
 				}
 				// Use JSON-RPC format for the close event
-				if err := sse.FormatJSONRPCEvent(w, protocol.EventClose, requestID, closeData); err != nil {
+				if err := s.writeSSEEvent(sw, "", protocol.EventClose, requestID, closeData); err != nil {
 					log.Errorf("Error writing SSE JSON-RPC close event for task %s: %v", taskID, err)
-				} else {
-					flusher.Flush()
 				}
 				return // End the handler.
 			}
@@ -409,15 +628,20 @@ func (s *A2AServer) handleSSEStream(
 				continue // Skip unknown event types
 			}
 
-			// Write the event to the SSE stream using JSON-RPC format.
-			if err := sse.FormatJSONRPCEvent(w, eventType, requestID, event); err != nil {
+			// Write and flush the event to the SSE stream using JSON-RPC format.
+			event = eventWithWarnings(ctx, event)
+			// Acknowledged streams number their events for tasks/ackEvents;
+			// other streams carry the task event IDs.
+			eventID := acks.next(time.Now())
+			if eventID == "" {
+				eventID = protocol.EventID(event)
+			}
+			if err := s.writeSSEEvent(sw, eventID, eventType, requestID, event); err != nil {
 				// Error writing, likely client disconnected.
 				log.Errorf("Error writing SSE JSON-RPC event for task %s (client likely disconnected): %v. "+
 					"Closing stream.", taskID, err)
 				return // Exit the handler.
 			}
-			// Flush the buffer to ensure the event is sent immediately.
-			flusher.Flush()
 		case <-clientClosed:
 			// Client disconnected (request context canceled).
 			log.Infof("SSE client disconnected for task %s (Request ID: %v). Closing stream.", taskID, requestID)
@@ -426,6 +650,20 @@ func (s *A2AServer) handleSSEStream(
 	}
 }
 
+// assignTaskID generates the ID of a task sent without one, unless automatic
+// task IDs are disabled, and reports the ID of the task in the
+// protocol.TaskIDHeader response header.
+func (s *A2AServer) assignTaskID(w http.ResponseWriter, params *protocol.SendTaskParams) *jsonrpc.Error {
+	if params.ID == "" {
+		if !s.autoTaskIDs {
+			return jsonrpc.ErrInvalidParams("task ID is required")
+		}
+		params.ID = s.idGenerator.NewID()
+	}
+	w.Header().Set(protocol.TaskIDHeader, params.ID)
+	return nil
+}
+
 // handleTasksSendSubscribe handles the tasks_sendSubscribe method using Server-Sent Events (SSE).
 func (s *A2AServer) handleTasksSendSubscribe(ctx context.Context, w http.ResponseWriter, request jsonrpc.Request) {
 	var params protocol.SendTaskParams
@@ -434,22 +672,33 @@ func (s *A2AServer) handleTasksSendSubscribe(ctx context.Context, w http.Respons
 		return
 	}
 
-	// Validate required fields.
-	if params.ID == "" {
-		s.writeJSONRPCError(w, request.ID, jsonrpc.ErrInvalidParams("task ID is required"))
+	if rpcErr := s.assignTaskID(w, &params); rpcErr != nil {
+		s.writeJSONRPCError(w, request.ID, rpcErr)
 		return
 	}
+	// Validate required fields.
 	if params.Message.Role == "" || len(params.Message.Parts) == 0 {
 		s.writeJSONRPCError(w, request.ID, jsonrpc.ErrInvalidParams("message with at least one part is required"))
 		return
 	}
+	if params.DryRun {
+		s.writeJSONRPCError(w, request.ID, jsonrpc.ErrInvalidParams("dry runs are only supported by tasks/send"))
+		return
+	}
+	ctx, cancel, rpcErr := s.applyDeadlineBudget(ctx, params)
+	if rpcErr != nil {
+		s.writeJSONRPCError(w, request.ID, rpcErr)
+		return
+	}
+	defer cancel()
+	ctx, rpcErr = s.checkProvenance(ctx, params)
+	if rpcErr != nil {
+		s.writeJSONRPCError(w, request.ID, rpcErr)
+		return
+	}
 
-	// Check if client supports SSE.
-	// Since we're in a JSON-RPC context, we can't directly access the HTTP Accept header.
-	// We'll assume the client wants SSE since they called the sendSubscribe method.
-	// In a real implementation, this could be determined by looking at the HTTP request directly.
-
-	// Client wants SSE response.
+	// The client wants a stream since it called the sendSubscribe method: SSE
+	// unless its Accept header prefers NDJSON, see withStreamFormat.
 	flusher, ok := w.(http.Flusher)
 	if !ok {
 		log.Error("Streaming is not supported by the underlying http responseWriter")
@@ -461,8 +710,12 @@ func (s *A2AServer) handleTasksSendSubscribe(ctx context.Context, w http.Respons
 	eventsChan, err := s.taskManager.OnSendTaskSubscribe(ctx, params)
 	if err != nil {
 		log.Errorf("Error calling OnSendTaskSubscribe for task %s: %v", params.ID, err)
-		s.writeJSONRPCError(w, request.ID,
-			jsonrpc.ErrInternalError(fmt.Sprintf("failed to subscribe to task events: %v", err)))
+		if rpcErr, ok := err.(*jsonrpc.Error); ok {
+			s.writeJSONRPCError(w, request.ID, rpcErr)
+		} else {
+			s.writeJSONRPCError(w, request.ID,
+				jsonrpc.ErrInternalError(fmt.Sprintf("failed to subscribe to task events: %v", err)))
+		}
 		return
 	}
 
@@ -470,12 +723,28 @@ func (s *A2AServer) handleTasksSendSubscribe(ctx context.Context, w http.Respons
 	s.handleSSEStream(ctx, w, flusher, eventsChan, params.ID, request.ID, false)
 }
 
+// writeSSEEvent writes event as a JSON-RPC stream event with the event ID
+// eventID, if not empty. The event is encoded once and the encoding is shared
+// with every other stream delivering it.
+func (s *A2AServer) writeSSEEvent(
+	sw eventStreamWriter,
+	eventID, eventType string,
+	requestID interface{},
+	event interface{},
+) error {
+	data, err := s.sseEvents.encode(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON-RPC SSE event data: %w", err)
+	}
+	return sw.WriteJSONRPCEventWithID(eventID, eventType, requestID, data)
+}
+
 // writeJSONRPCResponse encodes and writes a successful JSON-RPC response.
 func (s *A2AServer) writeJSONRPCResponse(w http.ResponseWriter, id interface{}, result interface{}) {
 	response := jsonrpc.NewResponse(id, result)
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusOK) // Success is always 200 OK for JSON-RPC itself.
-	if err := json.NewEncoder(w).Encode(response); err != nil {
+	if err := s.encodeJSON(w, response); err != nil {
 		// Log error, but can't change response if headers are already sent.
 		log.Errorf("Failed to write JSON-RPC success response (ID: %v): %v", id, err)
 	}
@@ -502,15 +771,35 @@ func (s *A2AServer) writeJSONRPCError(w http.ResponseWriter, id interface{}, err
 		httpStatus = http.StatusNotFound
 	case jsonrpc.CodeInvalidParams:
 		httpStatus = http.StatusBadRequest
+	case taskmanager.ErrCodeSubscriptionLimit, taskmanager.ErrCodeTokenBudgetExceeded,
+		taskmanager.ErrCodeTenantQuotaExceeded, taskmanager.ErrCodeQuotaExceeded:
+		httpStatus = http.StatusTooManyRequests
+	case taskmanager.ErrCodeTenantRequired:
+		httpStatus = http.StatusUnauthorized
+	case taskmanager.ErrCodeDeadlineBudgetExhausted:
+		httpStatus = http.StatusRequestTimeout
+	case taskmanager.ErrCodePayloadTooLarge:
+		httpStatus = http.StatusRequestEntityTooLarge
 		// Add other mappings for custom server errors (-32000 to -32099) if desired.
 	}
 	w.WriteHeader(httpStatus)
-	if encodeErr := json.NewEncoder(w).Encode(response); encodeErr != nil {
+	if encodeErr := s.encodeJSON(w, response); encodeErr != nil {
 		// Log error, but can't change response now.
 		log.Errorf("Failed to write JSON-RPC error response (ID: %v, Code: %d): %v", id, err.Code, encodeErr)
 	}
 }
 
+// encodeJSON marshals v with the configured codec and writes it followed by a
+// newline, mirroring json.Encoder.Encode.
+func (s *A2AServer) encodeJSON(w io.Writer, v interface{}) error {
+	data, err := s.codec.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
 // setCORSHeaders adds permissive CORS headers for development/testing.
 // WARNING: This is insecure for production. Configure origins explicitly.
 func (s *A2AServer) setCORSHeaders(w http.ResponseWriter) {
@@ -593,7 +882,7 @@ func (s *A2AServer) handleTasksPushNotificationSet(
 // composeJWKSURL returns the fully qualified URL to the JWKS endpoint.
 func (s *A2AServer) composeJWKSURL() string {
 	// Extract the base URL from the agent card.
-	baseURL := s.agentCard.URL
+	baseURL := s.AgentCard().URL
 	// If the URL already has a scheme, use it directly.
 	if baseURL == "" {
 		// This is a fallback, but ideally the agent card should have a proper URL.
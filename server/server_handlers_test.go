@@ -15,6 +15,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"sync"
 	"testing"
 	"time"
 
@@ -22,6 +23,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"trpc.group/trpc-go/trpc-a2a-go/auth"
+	"trpc.group/trpc-go/trpc-a2a-go/codec"
 	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
 	"trpc.group/trpc-go/trpc-a2a-go/protocol"
 	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
@@ -505,3 +507,87 @@ func TestA2AServer_StartStop(t *testing.T) {
 		t.Fatal("Timed out waiting for server to stop")
 	}
 }
+
+// countingCodec wraps the default codec and counts calls.
+type countingCodec struct {
+	mu        sync.Mutex
+	marshal   int
+	unmarshal int
+}
+
+func (c *countingCodec) Name() string { return "counting" }
+
+func (c *countingCodec) Marshal(v interface{}) ([]byte, error) {
+	c.mu.Lock()
+	c.marshal++
+	c.mu.Unlock()
+	return codec.Default.Marshal(v)
+}
+
+func (c *countingCodec) Unmarshal(data []byte, v interface{}) error {
+	c.mu.Lock()
+	c.unmarshal++
+	c.mu.Unlock()
+	return codec.Default.Unmarshal(data, v)
+}
+
+// TestA2AServer_WithCodec verifies that the configured codec is used on the request path.
+func TestA2AServer_WithCodec(t *testing.T) {
+	mockTM := newMockTaskManager()
+	mockTM.SendResponse = &protocol.Task{ID: "codec-task"}
+	c := &countingCodec{}
+	testServer, _ := setupTestServer(t, mockTM, WithCodec(c))
+
+	params := protocol.SendTaskParams{
+		ID:      "codec-task",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")}),
+	}
+	resp := performJSONRPCRequest(t, testServer, protocol.MethodTasksSend, params, "req-codec")
+	require.Nil(t, resp.Error)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	assert.Equal(t, 2, c.unmarshal, "request envelope and params should be decoded by the codec")
+	assert.Equal(t, 1, c.marshal, "response should be encoded by the codec")
+}
+
+// TestA2AServer_AgentCardCaching tests the conditional requests of the agent card.
+func TestA2AServer_AgentCardCaching(t *testing.T) {
+	a2aServer, err := NewA2AServer(defaultAgentCard(), newMockTaskManager(), WithAgentCardMaxAge(time.Minute))
+	require.NoError(t, err)
+	testServer := httptest.NewServer(a2aServer.Handler())
+	defer testServer.Close()
+
+	get := func(header http.Header) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, testServer.URL+protocol.AgentCardPath, nil)
+		require.NoError(t, err)
+		for name, values := range header {
+			req.Header[name] = values
+		}
+		resp, err := testServer.Client().Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp
+	}
+
+	resp := get(nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	etag := resp.Header.Get("ETag")
+	require.NotEmpty(t, etag)
+	lastModified := resp.Header.Get("Last-Modified")
+	require.NotEmpty(t, lastModified)
+	assert.Equal(t, "max-age=60", resp.Header.Get("Cache-Control"))
+
+	resp = get(http.Header{"If-None-Match": {etag}})
+	assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+	resp = get(http.Header{"If-Modified-Since": {lastModified}})
+	assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+
+	// Changed cards get a new ETag.
+	card := defaultAgentCard()
+	card.Name = "renamed"
+	a2aServer.SetAgentCard(card)
+	resp = get(http.Header{"If-None-Match": {etag}})
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.NotEqual(t, etag, resp.Header.Get("ETag"))
+}
@@ -7,6 +7,7 @@
 package server
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -22,9 +23,11 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"trpc.group/trpc-go/trpc-a2a-go/auth"
+	"trpc.group/trpc-go/trpc-a2a-go/client"
 	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
-	"trpc.group/trpc-go/trpc-a2a-go/internal/sse"
 	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/sse"
 	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
 )
 
@@ -390,6 +393,124 @@ func getCurrentTimestamp() string {
 	return time.Now().UTC().Format(time.RFC3339)
 }
 
+func TestA2AServer_RequestSigning(t *testing.T) {
+	verifier := auth.NewHMACVerifier(map[string][]byte{"client-1": []byte("secret")}, time.Minute)
+	store, err := NewFileUploadStore(t.TempDir())
+	require.NoError(t, err)
+	tm := newMockTaskManager()
+	tm.tasks["done"] = &protocol.Task{
+		ID:        "done",
+		Artifacts: []protocol.Artifact{{Parts: []protocol.Part{protocol.NewTextPart("result")}}},
+	}
+	a2aServer, err := NewA2AServer(defaultAgentCard(), tm, WithRequestSigning(verifier),
+		WithUploads("", store), WithArtifactDownloads(""))
+	require.NoError(t, err)
+	testServer := httptest.NewServer(a2aServer.Handler())
+	defer testServer.Close()
+
+	params := protocol.SendTaskParams{
+		ID:      "signed-task",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")}),
+	}
+	signed, err := client.NewA2AClient(testServer.URL, client.WithRequestSigning("client-1", []byte("secret")))
+	require.NoError(t, err)
+	_, err = signed.SendTasks(context.Background(), params)
+	require.NoError(t, err)
+
+	unsigned, err := client.NewA2AClient(testServer.URL)
+	require.NoError(t, err)
+	_, err = unsigned.SendTasks(context.Background(), params)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "401")
+
+	// The upload and artifact endpoints require signatures too.
+	content, err := signed.DownloadArtifact(context.Background(), "done", 0)
+	require.NoError(t, err)
+	content.Close()
+	_, err = signed.Upload(context.Background(), strings.NewReader("input"), 5, client.UploadOptions{Name: "input.txt"})
+	require.NoError(t, err)
+	_, err = unsigned.DownloadArtifact(context.Background(), "done", 0)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "401")
+	_, err = unsigned.Upload(context.Background(), strings.NewReader("input"), 5, client.UploadOptions{Name: "input.txt"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "401")
+
+	// The signature covers the path of the request as sent, before the mount
+	// prefix is stripped.
+	mux := http.NewServeMux()
+	mux.Handle("/a2a/", a2aServer.HandlerWithPrefix("/a2a"))
+	mounted := httptest.NewServer(mux)
+	defer mounted.Close()
+	signed, err = client.NewA2AClient(mounted.URL+"/a2a/", client.WithRequestSigning("client-1", []byte("secret")))
+	require.NoError(t, err)
+	params.ID = "mounted-task"
+	_, err = signed.SendTasks(context.Background(), params)
+	require.NoError(t, err)
+}
+
+func TestA2AServer_GeneratesTaskIDs(t *testing.T) {
+	generator := protocol.IDGeneratorFunc(func() string { return "generated-task" })
+	params := map[string]interface{}{
+		"message": protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")}),
+	}
+
+	t.Run("send", func(t *testing.T) {
+		testServer, _ := setupTestServer(t, newMockTaskManager(), WithIDGenerator(generator))
+		resp := performJSONRPCRequest(t, testServer, protocol.MethodTasksSend, params, "req-1")
+		require.Nil(t, resp.Error)
+		resultBytes, err := json.Marshal(resp.Result)
+		require.NoError(t, err)
+		var task protocol.Task
+		require.NoError(t, json.Unmarshal(resultBytes, &task))
+		assert.Equal(t, "generated-task", task.ID)
+	})
+
+	t.Run("sendSubscribe", func(t *testing.T) {
+		tm, err := taskmanager.NewMemoryTaskManager(&countingProcessor{})
+		require.NoError(t, err)
+		testServer, _ := setupTestServer(t, tm, WithIDGenerator(generator))
+		_, body := createJSONRPCRequest(t, protocol.MethodTasksSendSubscribe, params, "req-1")
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, testServer.URL, bytes.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "text/event-stream")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, "generated-task", resp.Header.Get(protocol.TaskIDHeader))
+
+		// The events of the stream carry the generated ID.
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+			if !ok {
+				continue
+			}
+			var event struct {
+				Result struct {
+					ID string `json:"id"`
+				} `json:"result"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(data), &event))
+			assert.Equal(t, "generated-task", event.Result.ID)
+			return
+		}
+		t.Fatal("no event received")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		testServer, _ := setupTestServer(t, newMockTaskManager(), WithAutoTaskIDs(false))
+		for _, method := range []string{protocol.MethodTasksSend, protocol.MethodTasksSendSubscribe} {
+			resp := performJSONRPCRequest(t, testServer, method, params, "req-1")
+			require.NotNil(t, resp.Error, method)
+			assert.Equal(t, jsonrpc.CodeInvalidParams, resp.Error.Code, method)
+		}
+	})
+}
+
 // mockTaskManager implements the taskmanager.TaskManager interface for testing.
 type mockTaskManager struct {
 	mu sync.Mutex
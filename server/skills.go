@@ -0,0 +1,558 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// MetadataKeySkill is the metadata key naming the skill a task asks for, in
+// the message or the params of tasks/send.
+const MetadataKeySkill = "skillId"
+
+// SkillProvider is implemented by task managers handling skills of their own,
+// such as SkillRouter. The server adds their skills to its agent card, after
+// the skills the card declares itself.
+type SkillProvider interface {
+	// Skills returns the skills handled by the task manager.
+	Skills() []AgentSkill
+}
+
+// SkillRouter hosts several skills on one server, each handled by its own
+// task manager. New tasks go to the task manager of the skill named under
+// MetadataKeySkill in their message, or else in their params, and tasks
+// naming no skill go to the default skill. The other requests of a task go to
+// the task manager that created it.
+//
+// It implements SkillProvider, so the agent card of the server lists the
+// registered skills. It also implements the optional interfaces of the task
+// managers, such as TaskPlanner, TaskLister, TaskSnapshotter or TaskWatcher,
+// by delegating to the task managers of the skills, and fails with a method
+// not found error when the task manager concerned does not implement them.
+// Task graphs are run by the router itself, so that their tasks may use
+// different skills.
+type SkillRouter struct {
+	graphs *taskmanager.GraphScheduler
+
+	mu           sync.RWMutex
+	skills       []AgentSkill
+	managers     map[string]taskmanager.TaskManager // By skill ID.
+	defaultSkill string
+	maxRoutes    int
+	routes       map[string]string // Skill ID by task ID.
+	routeOrder   []string          // Task IDs of routes, oldest first.
+}
+
+// defaultMaxSkillRoutes is the number of routes of tasks a router remembers
+// by default.
+const defaultMaxSkillRoutes = 10000
+
+// NewSkillRouter creates a router without skills.
+func NewSkillRouter() *SkillRouter {
+	r := &SkillRouter{
+		managers:  make(map[string]taskmanager.TaskManager),
+		maxRoutes: defaultMaxSkillRoutes,
+		routes:    make(map[string]string),
+	}
+	r.graphs = taskmanager.NewGraphScheduler(r)
+	return r
+}
+
+// Register adds skill, whose tasks are handled by tm. The first skill
+// registered is the default skill.
+func (r *SkillRouter) Register(skill AgentSkill, tm taskmanager.TaskManager) error {
+	if skill.ID == "" {
+		return errors.New("skill ID must not be empty")
+	}
+	if tm == nil {
+		return fmt.Errorf("skill %q requires a non-nil task manager", skill.ID)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.managers[skill.ID]; exists {
+		return fmt.Errorf("skill %q is already registered", skill.ID)
+	}
+	r.skills = append(r.skills, skill)
+	r.managers[skill.ID] = tm
+	if r.defaultSkill == "" {
+		r.defaultSkill = skill.ID
+	}
+	return nil
+}
+
+// SetDefaultSkill makes skillID the skill of the tasks naming none, or rejects
+// them if skillID is empty.
+func (r *SkillRouter) SetDefaultSkill(skillID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.managers[skillID]; skillID != "" && !exists {
+		return fmt.Errorf("skill %q is not registered", skillID)
+	}
+	r.defaultSkill = skillID
+	return nil
+}
+
+// SetMaxRoutes sets the number of routes of tasks the router remembers, 10000
+// by default. The oldest routes are forgotten beyond it: the tasks they lead
+// to are then looked up in the task manager of each skill.
+func (r *SkillRouter) SetMaxRoutes(maxRoutes int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.maxRoutes = maxRoutes
+	r.evictRoutes()
+}
+
+// Skills implements SkillProvider.
+func (r *SkillRouter) Skills() []AgentSkill {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]AgentSkill(nil), r.skills...)
+}
+
+// requestedSkill returns the skill named by params, if any.
+func requestedSkill(params protocol.SendTaskParams) string {
+	if skillID, ok := params.Message.Metadata[MetadataKeySkill].(string); ok && skillID != "" {
+		return skillID
+	}
+	skillID, _ := params.Metadata[MetadataKeySkill].(string)
+	return skillID
+}
+
+// route returns the skill and task manager of the task sent with params. A
+// task keeps the skill it was created with.
+func (r *SkillRouter) route(ctx context.Context, params protocol.SendTaskParams) (string, taskmanager.TaskManager, error) {
+	requested := requestedSkill(params)
+	if skillID, tm, err := r.owner(ctx, params.ID); err == nil {
+		if requested != "" && requested != skillID {
+			return "", nil, jsonrpc.ErrInvalidParams(fmt.Sprintf(
+				"task '%s' belongs to skill '%s', not '%s'", params.ID, skillID, requested))
+		}
+		return skillID, tm, nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	skillID := requested
+	if skillID == "" {
+		skillID = r.defaultSkill
+	}
+	if skillID == "" {
+		return "", nil, jsonrpc.ErrInvalidParams(fmt.Sprintf(
+			"the message must name a skill under the metadata key '%s'", MetadataKeySkill))
+	}
+	tm, ok := r.managers[skillID]
+	if !ok {
+		return "", nil, jsonrpc.ErrInvalidParams(fmt.Sprintf("unknown skill '%s'", skillID))
+	}
+	return skillID, tm, nil
+}
+
+// owner returns the skill and task manager of the existing task taskID. Tasks
+// not created through the router, e.g. before a restart, are looked up in
+// each task manager.
+func (r *SkillRouter) owner(ctx context.Context, taskID string) (string, taskmanager.TaskManager, error) {
+	r.mu.RLock()
+	skillID, ok := r.routes[taskID]
+	skills := append([]AgentSkill(nil), r.skills...)
+	managers := make([]taskmanager.TaskManager, 0, len(skills))
+	for _, skill := range skills {
+		managers = append(managers, r.managers[skill.ID])
+	}
+	tm := r.managers[skillID]
+	r.mu.RUnlock()
+	if ok {
+		return skillID, tm, nil
+	}
+	historyLength := 0
+	for i, tm := range managers {
+		if _, err := tm.OnGetTask(ctx, protocol.TaskQueryParams{ID: taskID, HistoryLength: &historyLength}); err == nil {
+			r.remember(taskID, skills[i].ID)
+			return skills[i].ID, tm, nil
+		}
+	}
+	return "", nil, taskmanager.ErrTaskNotFound(taskID)
+}
+
+// remember records that taskID belongs to skillID.
+func (r *SkillRouter) remember(taskID, skillID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.routes[taskID]; ok {
+		r.routes[taskID] = skillID
+		return
+	}
+	r.routes[taskID] = skillID
+	r.routeOrder = append(r.routeOrder, taskID)
+	r.evictRoutes()
+}
+
+// evictRoutes forgets the oldest routes beyond the maximum. The order of the
+// routes may still list forgotten tasks, which are skipped, and is compacted
+// once they make up most of it.
+func (r *SkillRouter) evictRoutes() {
+	for len(r.routes) > r.maxRoutes && len(r.routeOrder) > 0 {
+		delete(r.routes, r.routeOrder[0])
+		r.routeOrder = r.routeOrder[1:]
+	}
+	if len(r.routeOrder) > 2*len(r.routes)+16 {
+		order := make([]string, 0, len(r.routes))
+		seen := make(map[string]bool, len(r.routes))
+		for _, taskID := range r.routeOrder {
+			if _, ok := r.routes[taskID]; ok && !seen[taskID] {
+				seen[taskID] = true
+				order = append(order, taskID)
+			}
+		}
+		r.routeOrder = order
+	}
+}
+
+// forget drops the route of taskID once its task manager no longer knows it,
+// e.g. after the task expired.
+func (r *SkillRouter) forget(taskID string, err error) {
+	var rpcErr *jsonrpc.Error
+	if !errors.As(err, &rpcErr) || rpcErr.Code != taskmanager.ErrCodeTaskNotFound {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.routes, taskID)
+}
+
+// OnSendTask implements taskmanager.TaskManager.
+func (r *SkillRouter) OnSendTask(ctx context.Context, params protocol.SendTaskParams) (*protocol.Task, error) {
+	skillID, tm, err := r.route(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	task, err := tm.OnSendTask(ctx, params)
+	if task != nil {
+		r.remember(params.ID, skillID)
+	}
+	return task, err
+}
+
+// OnSendTaskSubscribe implements taskmanager.TaskManager.
+func (r *SkillRouter) OnSendTaskSubscribe(
+	ctx context.Context,
+	params protocol.SendTaskParams,
+) (<-chan protocol.TaskEvent, error) {
+	skillID, tm, err := r.route(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	events, err := tm.OnSendTaskSubscribe(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	r.remember(params.ID, skillID)
+	return events, nil
+}
+
+// OnGetTask implements taskmanager.TaskManager.
+func (r *SkillRouter) OnGetTask(ctx context.Context, params protocol.TaskQueryParams) (*protocol.Task, error) {
+	_, tm, err := r.owner(ctx, params.ID)
+	if err != nil {
+		return nil, err
+	}
+	task, err := tm.OnGetTask(ctx, params)
+	r.forget(params.ID, err)
+	return task, err
+}
+
+// OnCancelTask implements taskmanager.TaskManager.
+func (r *SkillRouter) OnCancelTask(ctx context.Context, params protocol.TaskIDParams) (*protocol.Task, error) {
+	_, tm, err := r.owner(ctx, params.ID)
+	if err != nil {
+		return nil, err
+	}
+	task, err := tm.OnCancelTask(ctx, params)
+	r.forget(params.ID, err)
+	return task, err
+}
+
+// OnPushNotificationSet implements taskmanager.TaskManager.
+func (r *SkillRouter) OnPushNotificationSet(
+	ctx context.Context,
+	params protocol.TaskPushNotificationConfig,
+) (*protocol.TaskPushNotificationConfig, error) {
+	_, tm, err := r.owner(ctx, params.ID)
+	if err != nil {
+		return nil, err
+	}
+	return tm.OnPushNotificationSet(ctx, params)
+}
+
+// OnPushNotificationGet implements taskmanager.TaskManager.
+func (r *SkillRouter) OnPushNotificationGet(
+	ctx context.Context,
+	params protocol.TaskIDParams,
+) (*protocol.TaskPushNotificationConfig, error) {
+	_, tm, err := r.owner(ctx, params.ID)
+	if err != nil {
+		return nil, err
+	}
+	return tm.OnPushNotificationGet(ctx, params)
+}
+
+// OnResubscribe implements taskmanager.TaskManager.
+func (r *SkillRouter) OnResubscribe(
+	ctx context.Context,
+	params protocol.TaskIDParams,
+) (<-chan protocol.TaskEvent, error) {
+	_, tm, err := r.owner(ctx, params.ID)
+	if err != nil {
+		return nil, err
+	}
+	return tm.OnResubscribe(ctx, params)
+}
+
+// PlanTask implements taskmanager.TaskPlanner, reporting the skill selected.
+func (r *SkillRouter) PlanTask(ctx context.Context, params protocol.SendTaskParams) (*protocol.TaskPlan, error) {
+	skillID, tm, err := r.route(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	plan := &protocol.TaskPlan{}
+	if planner, ok := tm.(taskmanager.TaskPlanner); ok {
+		if plan, err = planner.PlanTask(ctx, params); err != nil {
+			return nil, err
+		}
+	}
+	plan.Skill = skillID
+	return plan, nil
+}
+
+// ListTasks implements taskmanager.TaskLister, merging the tasks of the task
+// managers that can list theirs.
+func (r *SkillRouter) ListTasks(ctx context.Context, filter taskmanager.TaskFilter) ([]protocol.Task, error) {
+	r.mu.RLock()
+	var listers []taskmanager.TaskLister
+	for _, skill := range r.skills {
+		if lister, ok := r.managers[skill.ID].(taskmanager.TaskLister); ok {
+			listers = append(listers, lister)
+		}
+	}
+	r.mu.RUnlock()
+	if len(listers) == 0 {
+		return nil, errors.New("no task manager of the skills can list tasks")
+	}
+	var tasks []protocol.Task
+	for _, lister := range listers {
+		listed, err := lister.ListTasks(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, listed...)
+	}
+	sort.SliceStable(tasks, func(i, j int) bool {
+		if tasks[i].Status.Timestamp != tasks[j].Status.Timestamp {
+			return tasks[i].Status.Timestamp < tasks[j].Status.Timestamp
+		}
+		return tasks[i].ID < tasks[j].ID
+	})
+	if filter.Limit > 0 && len(tasks) > filter.Limit {
+		tasks = tasks[:filter.Limit]
+	}
+	return tasks, nil
+}
+
+// mergeSkills appends to declared the provided skills it does not declare.
+func mergeSkills(declared, provided []AgentSkill) []AgentSkill {
+	if len(provided) == 0 {
+		return declared
+	}
+	merged := append(make([]AgentSkill, 0, len(declared)+len(provided)), declared...)
+	for _, skill := range provided {
+		seen := false
+		for _, d := range declared {
+			if d.ID == skill.ID {
+				seen = true
+				break
+			}
+		}
+		if !seen {
+			merged = append(merged, skill)
+		}
+	}
+	return merged
+}
+
+// RequeueTask implements taskmanager.TaskRequeuer.
+func (r *SkillRouter) RequeueTask(ctx context.Context, taskID string) (*protocol.Task, error) {
+	skillID, tm, err := r.owner(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	requeuer, ok := tm.(taskmanager.TaskRequeuer)
+	if !ok {
+		return nil, unsupportedBySkill(skillID, "requeue tasks")
+	}
+	return requeuer.RequeueTask(ctx, taskID)
+}
+
+// DeleteTask implements taskmanager.TaskDeleter.
+func (r *SkillRouter) DeleteTask(ctx context.Context, taskID string) error {
+	skillID, tm, err := r.owner(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	deleter, ok := tm.(taskmanager.TaskDeleter)
+	if !ok {
+		return unsupportedBySkill(skillID, "delete tasks")
+	}
+	if err := deleter.DeleteTask(ctx, taskID); err != nil {
+		r.forget(taskID, err)
+		return err
+	}
+	r.mu.Lock()
+	delete(r.routes, taskID)
+	r.mu.Unlock()
+	return nil
+}
+
+// ExportTask implements taskmanager.TaskSnapshotter.
+func (r *SkillRouter) ExportTask(ctx context.Context, taskID string) (*taskmanager.TaskSnapshot, error) {
+	skillID, tm, err := r.owner(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	snapshotter, ok := tm.(taskmanager.TaskSnapshotter)
+	if !ok {
+		return nil, unsupportedBySkill(skillID, "export tasks")
+	}
+	return snapshotter.ExportTask(ctx, taskID)
+}
+
+// ImportTask implements taskmanager.TaskSnapshotter. The task goes to the
+// skill named under MetadataKeySkill in its metadata, or else to the default
+// skill.
+func (r *SkillRouter) ImportTask(ctx context.Context, snapshot *taskmanager.TaskSnapshot) (*protocol.Task, error) {
+	skillID, tm, err := r.route(ctx, protocol.SendTaskParams{ID: snapshot.Task.ID, Metadata: snapshot.Task.Metadata})
+	if err != nil {
+		return nil, err
+	}
+	snapshotter, ok := tm.(taskmanager.TaskSnapshotter)
+	if !ok {
+		return nil, unsupportedBySkill(skillID, "import tasks")
+	}
+	task, err := snapshotter.ImportTask(ctx, snapshot)
+	if err != nil {
+		return nil, err
+	}
+	r.remember(task.ID, skillID)
+	return task, nil
+}
+
+// PushDeliveryAttempts implements taskmanager.PushDeliveryInspector.
+func (r *SkillRouter) PushDeliveryAttempts(
+	ctx context.Context,
+	taskID string,
+) ([]taskmanager.PushDeliveryAttempt, error) {
+	skillID, tm, err := r.owner(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	inspector, ok := tm.(taskmanager.PushDeliveryInspector)
+	if !ok {
+		return nil, unsupportedBySkill(skillID, "record push deliveries")
+	}
+	return inspector.PushDeliveryAttempts(ctx, taskID)
+}
+
+// OnAckEvents implements taskmanager.EventAcknowledger, passing the
+// acknowledgement on to the task manager of the task if it is one.
+func (r *SkillRouter) OnAckEvents(ctx context.Context, ack protocol.AckEventsResult) error {
+	_, tm, err := r.owner(ctx, ack.ID)
+	if err != nil {
+		return err
+	}
+	if acknowledger, ok := tm.(taskmanager.EventAcknowledger); ok {
+		return acknowledger.OnAckEvents(ctx, ack)
+	}
+	return nil
+}
+
+// OnSendTaskGraph implements taskmanager.TaskGraphRunner. Each task of the
+// graph is routed to its skill.
+func (r *SkillRouter) OnSendTaskGraph(
+	ctx context.Context,
+	params protocol.SendTaskGraphParams,
+) (*protocol.TaskGraph, error) {
+	return r.graphs.Send(ctx, params)
+}
+
+// OnGetTaskGraph implements taskmanager.TaskGraphRunner.
+func (r *SkillRouter) OnGetTaskGraph(
+	ctx context.Context,
+	params protocol.TaskIDParams,
+) (*protocol.TaskGraph, error) {
+	return r.graphs.Get(params.ID)
+}
+
+// WatchTasks implements taskmanager.TaskWatcher, merging the changes of the
+// task managers that can be watched. The changes of different task managers
+// are not ordered with each other. The channel is closed as soon as the
+// watch of any task manager ends, so that the watcher resyncs.
+func (r *SkillRouter) WatchTasks(ctx context.Context) (<-chan taskmanager.TaskChange, error) {
+	r.mu.RLock()
+	var watchers []taskmanager.TaskWatcher
+	for _, skill := range r.skills {
+		if watcher, ok := r.managers[skill.ID].(taskmanager.TaskWatcher); ok {
+			watchers = append(watchers, watcher)
+		}
+	}
+	r.mu.RUnlock()
+	if len(watchers) == 0 {
+		return nil, jsonrpc.ErrMethodNotFound("no task manager of the skills can watch tasks")
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	sources := make([]<-chan taskmanager.TaskChange, 0, len(watchers))
+	for _, watcher := range watchers {
+		changes, err := watcher.WatchTasks(ctx)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		sources = append(sources, changes)
+	}
+	merged := make(chan taskmanager.TaskChange)
+	var wg sync.WaitGroup
+	for _, changes := range sources {
+		wg.Add(1)
+		go func(changes <-chan taskmanager.TaskChange) {
+			defer wg.Done()
+			defer cancel()
+			for change := range changes {
+				select {
+				case merged <- change:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(changes)
+	}
+	go func(cancel context.CancelFunc) {
+		wg.Wait()
+		cancel()
+		close(merged)
+	}(cancel)
+	return merged, nil
+}
+
+// unsupportedBySkill returns the error of an operation the task manager of
+// skillID does not support.
+func unsupportedBySkill(skillID, operation string) error {
+	return jsonrpc.ErrMethodNotFound(fmt.Sprintf("the task manager of skill '%s' cannot %s", skillID, operation))
+}
@@ -0,0 +1,194 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// skillProcessor completes tasks with the name of its skill.
+type skillProcessor struct {
+	skill string
+}
+
+func (p skillProcessor) Process(
+	ctx context.Context,
+	taskID string,
+	message protocol.Message,
+	handle taskmanager.TaskHandle,
+) error {
+	reply := protocol.NewMessage(protocol.MessageRoleAgent, []protocol.Part{protocol.NewTextPart(p.skill)})
+	return handle.UpdateStatus(protocol.TaskStateCompleted, &reply)
+}
+
+func TestSkillRouter(t *testing.T) {
+	router := NewSkillRouter()
+	for _, skill := range []string{"translate", "summarize"} {
+		tm, err := taskmanager.NewMemoryTaskManager(skillProcessor{skill: skill})
+		require.NoError(t, err)
+		require.NoError(t, router.Register(AgentSkill{ID: skill, Name: skill}, tm))
+	}
+	assert.Error(t, router.Register(AgentSkill{ID: "translate"}, router), "skills are unique")
+	ctx := context.Background()
+	send := func(taskID string, metadata map[string]interface{}) (*protocol.Task, error) {
+		message := protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("text")})
+		message.Metadata = metadata
+		return router.OnSendTask(ctx, protocol.SendTaskParams{ID: taskID, Message: message})
+	}
+	reply := func(task *protocol.Task) string {
+		require.NotNil(t, task.Status.Message)
+		return task.Status.Message.Parts[0].(protocol.TextPart).Text
+	}
+
+	task, err := send("t1", map[string]interface{}{MetadataKeySkill: "summarize"})
+	require.NoError(t, err)
+	assert.Equal(t, "summarize", reply(task))
+	task, err = send("t2", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "translate", reply(task), "the first skill is the default")
+
+	_, err = send("t3", map[string]interface{}{MetadataKeySkill: "unknown"})
+	var rpcErr *jsonrpc.Error
+	require.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, jsonrpc.CodeInvalidParams, rpcErr.Code)
+	_, err = send("t1", map[string]interface{}{MetadataKeySkill: "translate"})
+	assert.Error(t, err, "tasks keep their skill")
+
+	task, err = router.OnGetTask(ctx, protocol.TaskQueryParams{ID: "t1"})
+	require.NoError(t, err)
+	assert.Equal(t, "summarize", reply(task))
+	_, err = router.OnGetTask(ctx, protocol.TaskQueryParams{ID: "missing"})
+	require.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, taskmanager.ErrCodeTaskNotFound, rpcErr.Code)
+
+	plan, err := router.PlanTask(ctx, protocol.SendTaskParams{
+		ID:       "t4",
+		Message:  protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("text")}),
+		Metadata: map[string]interface{}{MetadataKeySkill: "summarize"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "summarize", plan.Skill)
+	tasks, err := router.ListTasks(ctx, taskmanager.TaskFilter{})
+	require.NoError(t, err)
+	assert.Len(t, tasks, 2)
+
+	require.NoError(t, router.SetDefaultSkill(""))
+	_, err = send("t5", nil)
+	assert.Error(t, err, "tasks must name a skill without default")
+}
+
+func TestSkillRouter_Routes(t *testing.T) {
+	router := NewSkillRouter()
+	for _, skill := range []string{"translate", "summarize"} {
+		tm, err := taskmanager.NewMemoryTaskManager(skillProcessor{skill: skill})
+		require.NoError(t, err)
+		require.NoError(t, router.Register(AgentSkill{ID: skill, Name: skill}, tm))
+	}
+	router.SetMaxRoutes(2)
+	ctx := context.Background()
+	for _, taskID := range []string{"t1", "t2", "t3"} {
+		_, err := router.OnSendTask(ctx, protocol.SendTaskParams{
+			ID:       taskID,
+			Message:  protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("text")}),
+			Metadata: map[string]interface{}{MetadataKeySkill: "summarize"},
+		})
+		require.NoError(t, err)
+	}
+	router.mu.RLock()
+	assert.Len(t, router.routes, 2)
+	assert.NotContains(t, router.routes, "t1", "the oldest route is forgotten")
+	router.mu.RUnlock()
+	skillID, _, err := router.owner(ctx, "t1")
+	require.NoError(t, err)
+	assert.Equal(t, "summarize", skillID, "forgotten tasks are looked up")
+
+	snapshot, err := router.ExportTask(ctx, "t2")
+	require.NoError(t, err)
+	snapshot.Task.ID = "imported"
+	snapshot.Task.Metadata = map[string]interface{}{MetadataKeySkill: "summarize"}
+	_, err = router.ImportTask(ctx, snapshot)
+	require.NoError(t, err)
+	skillID, _, err = router.owner(ctx, "imported")
+	require.NoError(t, err)
+	assert.Equal(t, "summarize", skillID)
+
+	graph, err := router.OnSendTaskGraph(ctx, protocol.SendTaskGraphParams{
+		ID: "g1",
+		Tasks: []protocol.TaskGraphNodeParams{
+			{Task: protocol.SendTaskParams{
+				ID:       "g1-a",
+				Message:  protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("a")}),
+				Metadata: map[string]interface{}{MetadataKeySkill: "summarize"},
+			}},
+			{Task: protocol.SendTaskParams{
+				ID:      "g1-b",
+				Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("b")}),
+			}, DependsOn: []string{"g1-a"}},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "g1", graph.ID)
+	require.Eventually(t, func() bool {
+		_, err := router.OnGetTask(ctx, protocol.TaskQueryParams{ID: "g1-b"})
+		return err == nil
+	}, time.Second, 5*time.Millisecond)
+	skillID, _, err = router.owner(ctx, "g1-b")
+	require.NoError(t, err)
+	assert.Equal(t, "translate", skillID, "the tasks of graphs are routed to their skill")
+
+	watchCtx, stopWatch := context.WithCancel(ctx)
+	changes, err := router.WatchTasks(watchCtx)
+	require.NoError(t, err)
+	_, err = router.OnSendTask(ctx, protocol.SendTaskParams{
+		ID:      "watched",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("text")}),
+	})
+	require.NoError(t, err)
+	select {
+	case change := <-changes:
+		assert.Equal(t, "watched", change.TaskID)
+	case <-time.After(time.Second):
+		t.Fatal("no change of the task watched")
+	}
+	stopWatch()
+	for range changes {
+	}
+
+	unsupported := NewSkillRouter()
+	bare := struct{ taskmanager.TaskManager }{router}
+	require.NoError(t, unsupported.Register(AgentSkill{ID: "other"}, bare))
+	_, err = unsupported.RequeueTask(ctx, "t2")
+	var rpcErr *jsonrpc.Error
+	require.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, jsonrpc.CodeMethodNotFound, rpcErr.Code)
+}
+
+func TestA2AServer_SkillRouterCard(t *testing.T) {
+	router := NewSkillRouter()
+	tm, err := taskmanager.NewMemoryTaskManager(skillProcessor{skill: "translate"})
+	require.NoError(t, err)
+	require.NoError(t, router.Register(AgentSkill{ID: "translate", Name: "Translate"}, tm))
+	require.NoError(t, router.Register(AgentSkill{ID: "summarize", Name: "Summarize"}, tm))
+	card := defaultAgentCard()
+	card.Skills = []AgentSkill{{ID: "translate", Name: "Declared"}}
+	a2aServer, err := NewA2AServer(card, router)
+	require.NoError(t, err)
+
+	skills := a2aServer.AgentCard().Skills
+	require.Len(t, skills, 2)
+	assert.Equal(t, "Declared", skills[0].Name, "the skills of the card take precedence")
+	assert.Equal(t, "summarize", skills[1].ID)
+}
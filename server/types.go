@@ -7,6 +7,8 @@
 // Package server contains the A2A server implementation and related types.
 package server
 
+import "trpc.group/trpc-go/trpc-a2a-go/protocol"
+
 // AgentCapabilities defines the capabilities supported by an agent.
 type AgentCapabilities struct {
 	// Streaming is a flag indicating if the agent supports streaming responses.
@@ -17,6 +19,14 @@ type AgentCapabilities struct {
 	StateTransitionHistory bool `json:"stateTransitionHistory"`
 }
 
+// AgentMethod describes a custom JSON-RPC method served by the agent.
+type AgentMethod struct {
+	// Name is the name of the method.
+	Name string `json:"name"`
+	// Description is an optional description of the method.
+	Description string `json:"description,omitempty"`
+}
+
 // AgentSkill describes a specific capability or function of the agent.
 type AgentSkill struct {
 	// ID is the unique identifier for the skill.
@@ -33,6 +43,9 @@ type AgentSkill struct {
 	InputModes []string `json:"inputModes,omitempty"`
 	// OutputModes are the supported output data modes/types.
 	OutputModes []string `json:"outputModes,omitempty"`
+	// RunnableExamples are optional example inputs that clients can run
+	// through the skills/examples/run extension method.
+	RunnableExamples []protocol.SkillExample `json:"runnableExamples,omitempty"`
 }
 
 // AgentProvider contains information about the agent's provider or developer.
@@ -78,4 +91,7 @@ type AgentCard struct {
 	DefaultOutputModes []string `json:"defaultOutputModes"`
 	// Skills are optional list of specific skills.
 	Skills []AgentSkill `json:"skills,omitempty"`
+	// Methods are the custom JSON-RPC methods served by the agent besides
+	// the A2A methods.
+	Methods []AgentMethod `json:"methods,omitempty"`
 }
@@ -0,0 +1,490 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// ErrUploadNotFound is returned by UploadStore for unknown uploads.
+var ErrUploadNotFound = errors.New("upload not found")
+
+// ErrUploadOffset is returned by UploadStore.Append when the offset of the
+// chunk is not the offset of the upload.
+var ErrUploadOffset = errors.New("upload offset mismatch")
+
+// Upload describes a resumable upload.
+type Upload struct {
+	// ID identifies the upload.
+	ID string `json:"id"`
+	// Length is the total size of the upload, in bytes.
+	Length int64 `json:"length"`
+	// Offset is the number of bytes received so far.
+	Offset int64 `json:"offset"`
+	// Name is the optional file name of the upload.
+	Name string `json:"name,omitempty"`
+	// MimeType is the optional media type of the upload.
+	MimeType string `json:"mimeType,omitempty"`
+}
+
+// Complete reports whether every byte of the upload was received.
+func (u Upload) Complete() bool {
+	return u.Offset == u.Length
+}
+
+// UploadStore keeps the content of resumable uploads.
+type UploadStore interface {
+	// Create starts upload, assigning its ID, and returns it.
+	Create(ctx context.Context, upload Upload) (Upload, error)
+	// Get returns the upload id, or ErrUploadNotFound.
+	Get(ctx context.Context, id string) (Upload, error)
+	// Append appends the data read from r to the upload id, whose offset must
+	// be offset, without exceeding its length, and returns the upload. The
+	// data read before a failure of r is kept, so that the client can resume
+	// after it.
+	Append(ctx context.Context, id string, offset int64, r io.Reader) (Upload, error)
+	// Open returns the content of the complete upload id.
+	Open(ctx context.Context, id string) (io.ReadCloser, error)
+}
+
+// DefaultUploadExpiration is the time FileUploadStore keeps incomplete uploads
+// after their last chunk.
+const DefaultUploadExpiration = 24 * time.Hour
+
+// defaultMaxUploadBytes bounds the uploads of the servers without
+// WithMaxUploadSize.
+const defaultMaxUploadBytes = 1 << 30
+
+// FileUploadStore is an UploadStore keeping each upload in a file of a
+// directory, along with a file describing it. Incomplete uploads are deleted
+// once they received no chunk for their expiration.
+type FileUploadStore struct {
+	dir string
+
+	mu         sync.Mutex // Guards the fields below.
+	locks      map[string]*uploadLock
+	expiration time.Duration
+	lastExpiry time.Time
+}
+
+// uploadLock serializes the appends to an upload.
+type uploadLock struct {
+	sync.Mutex
+	refs int // Number of appends holding or waiting for the lock.
+}
+
+// NewFileUploadStore creates a store keeping the uploads in dir, created if
+// needed, and deleting the incomplete uploads after DefaultUploadExpiration.
+func NewFileUploadStore(dir string) (*FileUploadStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create upload directory: %w", err)
+	}
+	return &FileUploadStore{
+		dir:        dir,
+		locks:      make(map[string]*uploadLock),
+		expiration: DefaultUploadExpiration,
+		lastExpiry: time.Now(),
+	}, nil
+}
+
+// SetExpiration sets the time incomplete uploads are kept after their last
+// chunk.
+func (s *FileUploadStore) SetExpiration(expiration time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expiration = expiration
+}
+
+// lock locks the upload id against other appends and returns the function
+// unlocking it.
+func (s *FileUploadStore) lock(id string) func() {
+	s.mu.Lock()
+	l, ok := s.locks[id]
+	if !ok {
+		l = &uploadLock{}
+		s.locks[id] = l
+	}
+	l.refs++
+	s.mu.Unlock()
+	l.Lock()
+	return func() {
+		l.Unlock()
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if l.refs--; l.refs == 0 {
+			delete(s.locks, id)
+		}
+	}
+}
+
+// validUploadID reports whether id is an ID generated by FileUploadStore,
+// which keeps IDs from escaping its directory.
+func validUploadID(id string) bool {
+	if len(id) != 32 {
+		return false
+	}
+	_, err := hex.DecodeString(id)
+	return err == nil
+}
+
+// dataPath and infoPath return the files of upload id.
+func (s *FileUploadStore) dataPath(id string) string { return filepath.Join(s.dir, id+".data") }
+func (s *FileUploadStore) infoPath(id string) string { return filepath.Join(s.dir, id+".json") }
+
+// Create implements UploadStore. It deletes the expired uploads first, at
+// most once per expiration.
+func (s *FileUploadStore) Create(ctx context.Context, upload Upload) (Upload, error) {
+	s.mu.Lock()
+	expiration := s.expiration
+	due := time.Since(s.lastExpiry) >= expiration
+	if due {
+		s.lastExpiry = time.Now()
+	}
+	s.mu.Unlock()
+	if due {
+		if _, err := s.Expire(ctx, time.Now().Add(-expiration)); err != nil {
+			log.Warnf("Failed to delete expired uploads: %v", err)
+		}
+	}
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return Upload{}, err
+	}
+	upload.ID, upload.Offset = hex.EncodeToString(id[:]), 0
+	file, err := os.OpenFile(s.dataPath(upload.ID), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return Upload{}, fmt.Errorf("failed to create upload: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return Upload{}, err
+	}
+	if err := s.writeInfo(upload); err != nil {
+		return Upload{}, err
+	}
+	return upload, nil
+}
+
+// writeInfo saves the description of upload.
+func (s *FileUploadStore) writeInfo(upload Upload) error {
+	data, err := json.Marshal(upload)
+	if err != nil {
+		return err
+	}
+	tmp := s.infoPath(upload.ID) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to save upload: %w", err)
+	}
+	return os.Rename(tmp, s.infoPath(upload.ID))
+}
+
+// Get implements UploadStore.
+func (s *FileUploadStore) Get(ctx context.Context, id string) (Upload, error) {
+	if !validUploadID(id) {
+		return Upload{}, ErrUploadNotFound
+	}
+	data, err := os.ReadFile(s.infoPath(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return Upload{}, ErrUploadNotFound
+	}
+	if err != nil {
+		return Upload{}, err
+	}
+	var upload Upload
+	if err := json.Unmarshal(data, &upload); err != nil {
+		return Upload{}, fmt.Errorf("invalid upload %s: %w", id, err)
+	}
+	return upload, nil
+}
+
+// Append implements UploadStore. Appends to different uploads run
+// concurrently.
+func (s *FileUploadStore) Append(ctx context.Context, id string, offset int64, r io.Reader) (Upload, error) {
+	if !validUploadID(id) {
+		return Upload{}, ErrUploadNotFound
+	}
+	defer s.lock(id)()
+	upload, err := s.Get(ctx, id)
+	if err != nil {
+		return Upload{}, err
+	}
+	if offset != upload.Offset {
+		return upload, ErrUploadOffset
+	}
+	file, err := os.OpenFile(s.dataPath(id), os.O_WRONLY, 0o600)
+	if err != nil {
+		return Upload{}, err
+	}
+	defer file.Close()
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return Upload{}, err
+	}
+	n, copyErr := io.Copy(file, io.LimitReader(r, upload.Length-offset))
+	upload.Offset += n
+	if err := s.writeInfo(upload); err != nil {
+		return Upload{}, err
+	}
+	return upload, copyErr
+}
+
+// Open implements UploadStore.
+func (s *FileUploadStore) Open(ctx context.Context, id string) (io.ReadCloser, error) {
+	upload, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !upload.Complete() {
+		return nil, fmt.Errorf("upload %s is incomplete", id)
+	}
+	return os.Open(s.dataPath(id))
+}
+
+// Delete deletes the upload id, if any.
+func (s *FileUploadStore) Delete(ctx context.Context, id string) error {
+	if !validUploadID(id) {
+		return nil
+	}
+	defer s.lock(id)()
+	return s.remove(id)
+}
+
+// Expire deletes the incomplete uploads which received no chunk since before
+// and returns their number.
+func (s *FileUploadStore) Expire(ctx context.Context, before time.Time) (int, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return 0, err
+	}
+	expired := 0
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok || !validUploadID(id) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || !info.ModTime().Before(before) {
+			continue
+		}
+		deleted, err := s.expire(ctx, id, before)
+		if err != nil {
+			return expired, err
+		}
+		if deleted {
+			expired++
+		}
+	}
+	return expired, nil
+}
+
+// expire deletes the upload id if it is incomplete and received no chunk
+// since before, and reports whether it did.
+func (s *FileUploadStore) expire(ctx context.Context, id string, before time.Time) (bool, error) {
+	defer s.lock(id)()
+	upload, err := s.Get(ctx, id)
+	if errors.Is(err, ErrUploadNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	// The modification time is read again under the lock, since a chunk may
+	// have been appended meanwhile.
+	info, err := os.Stat(s.infoPath(id))
+	if err != nil || upload.Complete() || !info.ModTime().Before(before) {
+		return false, nil
+	}
+	return true, s.remove(id)
+}
+
+// remove deletes the files of upload id. The caller must hold its lock.
+func (s *FileUploadStore) remove(id string) error {
+	for _, file := range []string{s.dataPath(id), s.infoPath(id)} {
+		if err := os.Remove(file); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// UploadID returns the ID of the upload at uri, a URI returned by the uploads
+// endpoint, so that processors can read the uploads sent in file parts from
+// the UploadStore of the server rather than over HTTP.
+func UploadID(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return ""
+	}
+	return path.Base(u.Path)
+}
+
+// uploads holds the settings of the uploads endpoint.
+type uploads struct {
+	path  string      // Path of the endpoint on Handler.
+	store UploadStore // Keeps the uploads.
+}
+
+// uploadHandler returns the handler of the uploads endpoint, mounted at
+// s.uploads.path.
+func (s *A2AServer) uploadHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST "+s.uploads.path, s.handleCreateUpload)
+	mux.HandleFunc("HEAD "+s.uploads.path+"/{id}", s.handleUploadOffset)
+	mux.HandleFunc("PATCH "+s.uploads.path+"/{id}", s.handleAppendUpload)
+	mux.HandleFunc("GET "+s.uploads.path+"/{id}", s.handleGetUpload)
+	return mux
+}
+
+// handleCreateUpload creates an upload of the length of the request.
+func (s *A2AServer) handleCreateUpload(w http.ResponseWriter, r *http.Request) {
+	length, err := strconv.ParseInt(r.Header.Get(protocol.UploadLengthHeader), 10, 64)
+	if err != nil || length < 0 {
+		http.Error(w, "invalid "+protocol.UploadLengthHeader+" header", http.StatusBadRequest)
+		return
+	}
+	maxBytes := s.maxUploadBytes
+	if maxBytes == 0 {
+		maxBytes = defaultMaxUploadBytes
+	}
+	if maxBytes > 0 && length > maxBytes {
+		http.Error(w, fmt.Sprintf("uploads are limited to %d bytes", maxBytes),
+			http.StatusRequestEntityTooLarge)
+		return
+	}
+	upload, err := s.uploads.store.Create(r.Context(), Upload{
+		Length:   length,
+		Name:     r.Header.Get(protocol.UploadNameHeader),
+		MimeType: r.Header.Get("Content-Type"),
+	})
+	if err != nil {
+		log.Errorf("Failed to create upload: %v", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Location", s.uploads.path+"/"+upload.ID)
+	writeUploadHeaders(w, upload)
+	w.WriteHeader(http.StatusCreated)
+}
+
+// handleUploadOffset reports the offset of an upload, to resume it.
+func (s *A2AServer) handleUploadOffset(w http.ResponseWriter, r *http.Request) {
+	upload, ok := s.getUpload(w, r)
+	if !ok {
+		return
+	}
+	writeUploadHeaders(w, upload)
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleAppendUpload appends a chunk to an upload.
+func (s *A2AServer) handleAppendUpload(w http.ResponseWriter, r *http.Request) {
+	offset, err := strconv.ParseInt(r.Header.Get(protocol.UploadOffsetHeader), 10, 64)
+	if err != nil || offset < 0 {
+		http.Error(w, "invalid "+protocol.UploadOffsetHeader+" header", http.StatusBadRequest)
+		return
+	}
+	upload, err := s.uploads.store.Append(r.Context(), r.PathValue("id"), offset, r.Body)
+	switch {
+	case errors.Is(err, ErrUploadNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case errors.Is(err, ErrUploadOffset):
+		writeUploadHeaders(w, upload)
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case err != nil:
+		log.Warnf("Failed to append to upload %s: %v", r.PathValue("id"), err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	writeUploadHeaders(w, upload)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleGetUpload returns the content of a complete upload.
+func (s *A2AServer) handleGetUpload(w http.ResponseWriter, r *http.Request) {
+	upload, ok := s.getUpload(w, r)
+	if !ok {
+		return
+	}
+	if !upload.Complete() {
+		writeUploadHeaders(w, upload)
+		http.Error(w, "upload is incomplete", http.StatusConflict)
+		return
+	}
+	content, err := s.uploads.store.Open(r.Context(), upload.ID)
+	if err != nil {
+		log.Errorf("Failed to open upload %s: %v", upload.ID, err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	defer content.Close()
+	if upload.MimeType != "" {
+		w.Header().Set("Content-Type", upload.MimeType)
+	} else {
+		w.Header().Set("Content-Type", "application/octet-stream")
+	}
+	// The content is the client's, so browsers must not render it as the
+	// server's.
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Disposition", contentDisposition(upload.Name))
+	w.Header().Set("Content-Length", strconv.FormatInt(upload.Length, 10))
+	if _, err := io.Copy(w, content); err != nil {
+		log.Debugf("Failed to send upload %s: %v", upload.ID, err)
+	}
+}
+
+// getUpload returns the upload of the request, answering it if not found.
+func (s *A2AServer) getUpload(w http.ResponseWriter, r *http.Request) (Upload, bool) {
+	upload, err := s.uploads.store.Get(r.Context(), r.PathValue("id"))
+	if errors.Is(err, ErrUploadNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return Upload{}, false
+	}
+	if err != nil {
+		log.Errorf("Failed to read upload %s: %v", r.PathValue("id"), err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return Upload{}, false
+	}
+	return upload, true
+}
+
+// writeUploadHeaders sets the headers describing the progress of upload.
+func writeUploadHeaders(w http.ResponseWriter, upload Upload) {
+	w.Header().Set(protocol.UploadOffsetHeader, strconv.FormatInt(upload.Offset, 10))
+	w.Header().Set(protocol.UploadLengthHeader, strconv.FormatInt(upload.Length, 10))
+	w.Header().Set("Cache-Control", "no-store")
+}
+
+// contentDisposition returns the Content-Disposition header of a download
+// named name, which may be empty.
+func contentDisposition(name string) string {
+	if name == "" {
+		return "attachment"
+	}
+	if header := mime.FormatMediaType("attachment", map[string]string{"filename": filepath.Base(name)}); header != "" {
+		return header
+	}
+	return "attachment"
+}
@@ -0,0 +1,157 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/client"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+func TestA2AServer_Uploads(t *testing.T) {
+	store, err := NewFileUploadStore(t.TempDir())
+	require.NoError(t, err)
+	a2aServer, err := NewA2AServer(defaultAgentCard(), newMockTaskManager(),
+		WithUploads("", store), WithMaxUploadSize(1<<20))
+	require.NoError(t, err)
+	testServer := httptest.NewServer(a2aServer.Handler())
+	defer testServer.Close()
+	c, err := client.NewA2AClient(testServer.URL)
+	require.NoError(t, err)
+	content := bytes.Repeat([]byte("0123456789"), 100)
+
+	t.Run("upload", func(t *testing.T) {
+		var progress []int64
+		uri, err := c.Upload(context.Background(), bytes.NewReader(content), int64(len(content)), client.UploadOptions{
+			Name:      "digits.txt",
+			MimeType:  "text/plain",
+			ChunkSize: 300,
+			Progress:  func(sent, total int64) { progress = append(progress, sent) },
+		})
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(uri, testServer.URL+protocol.DefaultUploadPath+"/"), uri)
+		assert.Equal(t, []int64{300, 600, 900, 1000}, progress)
+
+		resp, err := http.Get(uri)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, "text/plain", resp.Header.Get("Content-Type"))
+		assert.Equal(t, "nosniff", resp.Header.Get("X-Content-Type-Options"))
+		assert.Equal(t, `attachment; filename=digits.txt`, resp.Header.Get("Content-Disposition"))
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, content, body)
+
+		upload, err := store.Get(context.Background(), UploadID(uri))
+		require.NoError(t, err)
+		assert.Equal(t, "digits.txt", upload.Name)
+		assert.True(t, upload.Complete())
+	})
+
+	t.Run("resume", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		uri, err := c.Upload(ctx, bytes.NewReader(content), int64(len(content)), client.UploadOptions{
+			ChunkSize: 400,
+			Progress:  func(sent, total int64) { cancel() },
+		})
+		require.Error(t, err)
+		require.NotEmpty(t, uri)
+		resp, err := http.Get(uri)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusConflict, resp.StatusCode, "incomplete uploads cannot be read")
+
+		uri2, err := c.Upload(context.Background(), bytes.NewReader(content), int64(len(content)),
+			client.UploadOptions{ChunkSize: 400, URI: uri})
+		require.NoError(t, err)
+		assert.Equal(t, uri, uri2)
+		reader, err := store.Open(context.Background(), UploadID(uri))
+		require.NoError(t, err)
+		defer reader.Close()
+		body, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Equal(t, content, body)
+	})
+
+	t.Run("offset mismatch", func(t *testing.T) {
+		upload, err := store.Create(context.Background(), Upload{Length: 10})
+		require.NoError(t, err)
+		req, err := http.NewRequest(http.MethodPatch, testServer.URL+protocol.DefaultUploadPath+"/"+upload.ID,
+			strings.NewReader("56789"))
+		require.NoError(t, err)
+		req.Header.Set(protocol.UploadOffsetHeader, "5")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+		assert.Equal(t, "0", resp.Header.Get(protocol.UploadOffsetHeader))
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		_, err := c.Upload(context.Background(), bytes.NewReader(nil), 2<<20, client.UploadOptions{})
+		assert.ErrorContains(t, err, "413")
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		// The escaped slashes keep the path from being cleaned before it
+		// reaches the store.
+		resp, err := http.Get(testServer.URL + protocol.DefaultUploadPath + "/..%2F..%2Fetc%2Fpasswd")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, ErrUploadNotFound.Error(), strings.TrimSpace(string(body)))
+	})
+}
+
+func TestFileUploadStore_Expire(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewFileUploadStore(t.TempDir())
+	require.NoError(t, err)
+	incomplete, err := store.Create(ctx, Upload{Length: 10})
+	require.NoError(t, err)
+	_, err = store.Append(ctx, incomplete.ID, 0, strings.NewReader("01234"))
+	require.NoError(t, err)
+	complete, err := store.Create(ctx, Upload{Length: 5})
+	require.NoError(t, err)
+	_, err = store.Append(ctx, complete.ID, 0, strings.NewReader("01234"))
+	require.NoError(t, err)
+
+	expired, err := store.Expire(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 0, expired, "recent uploads are kept")
+	expired, err = store.Expire(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, expired, "complete uploads are kept")
+	_, err = store.Get(ctx, incomplete.ID)
+	assert.ErrorIs(t, err, ErrUploadNotFound)
+	_, err = store.Get(ctx, complete.ID)
+	assert.NoError(t, err)
+
+	// Uploads expire on the creation of others.
+	store.SetExpiration(0)
+	_, err = store.Create(ctx, Upload{Length: 10})
+	require.NoError(t, err)
+	_, err = store.Get(ctx, complete.ID)
+	assert.NoError(t, err)
+
+	require.NoError(t, store.Delete(ctx, complete.ID))
+	_, err = store.Get(ctx, complete.ID)
+	assert.ErrorIs(t, err, ErrUploadNotFound)
+}
@@ -0,0 +1,575 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// ValidationError describes a single violation of the A2A schema found in
+// the params of an incoming JSON-RPC request.
+type ValidationError struct {
+	// Pointer is the RFC 6901 JSON pointer to the offending field, relative to params.
+	Pointer string `json:"pointer"`
+	// Message describes why the field is invalid.
+	Message string `json:"message"`
+}
+
+// paramsValidator walks decoded JSON params and collects schema violations.
+type paramsValidator struct {
+	errs []ValidationError
+}
+
+// validateParams checks the raw params of a request against the A2A schema for
+// the given method. Unknown methods are not validated.
+// It returns an invalid-params error carrying all violations, or nil.
+func validateParams(method string, params json.RawMessage) *jsonrpc.Error {
+	_, err := checkParams(method, params, protocol.PartFailureRejectAll)
+	return err
+}
+
+// checkParams validates params like validateParams. With PartFailureAcceptValid,
+// a message whose only violations are in some of its parts is accepted: the
+// invalid parts are dropped, a warning is recorded for each violation in the
+// params metadata, and the rewritten params are returned.
+func checkParams(
+	method string,
+	params json.RawMessage,
+	policy protocol.PartFailurePolicy,
+) (json.RawMessage, *jsonrpc.Error) {
+	check, ok := methodValidators[method]
+	if !ok {
+		return params, nil
+	}
+	var v paramsValidator
+	dec := json.NewDecoder(bytes.NewReader(params))
+	dec.UseNumber() // Preserve numbers to distinguish integers from floats.
+	var doc interface{}
+	if len(bytes.TrimSpace(params)) == 0 {
+		v.fail("", "params are required")
+	} else if err := dec.Decode(&doc); err != nil {
+		v.fail("", fmt.Sprintf("params are not valid JSON: %v", err))
+	} else if _, err := dec.Token(); err != io.EOF {
+		v.fail("", "params are not valid JSON: unexpected data after the params")
+	} else {
+		check(&v, doc)
+	}
+	if len(v.errs) == 0 {
+		return params, nil
+	}
+	if policy == protocol.PartFailureAcceptValid && acceptsPartialMessage(method) {
+		if rewritten, ok := dropInvalidParts(doc, v.errs); ok {
+			return rewritten, nil
+		}
+	}
+	return nil, jsonrpc.ErrInvalidParams(v.errs)
+}
+
+// acceptsPartialMessage reports whether the params of method carry a message
+// that may be accepted with some of its parts dropped.
+func acceptsPartialMessage(method string) bool {
+	return method == protocol.MethodTasksSend || method == protocol.MethodTasksSendSubscribe
+}
+
+// partsPointer is the JSON pointer to the parts of the message in send params.
+const partsPointer = "/message/parts/"
+
+// dropInvalidParts removes the parts of the message in doc that have violations,
+// recording them as warnings in the params metadata. It fails if a violation is
+// not within a part or if no valid part would remain.
+func dropInvalidParts(doc interface{}, errs []ValidationError) (json.RawMessage, bool) {
+	invalid := make(map[int]bool)
+	for _, e := range errs {
+		index, ok := partIndex(e.Pointer)
+		if !ok {
+			return nil, false
+		}
+		invalid[index] = true
+	}
+	obj := doc.(map[string]interface{})
+	message := obj["message"].(map[string]interface{})
+	parts := message["parts"].([]interface{})
+	kept := make([]interface{}, 0, len(parts))
+	for i, part := range parts {
+		if !invalid[i] {
+			kept = append(kept, part)
+		}
+	}
+	if len(kept) == 0 {
+		return nil, false
+	}
+	message["parts"] = kept
+	metadata, _ := obj["metadata"].(map[string]interface{})
+	warnings := make([]protocol.Warning, 0, len(errs))
+	for _, e := range errs {
+		warnings = append(warnings, protocol.Warning{
+			Code:    protocol.WarningCodePartDropped,
+			Message: e.Message,
+			Pointer: e.Pointer,
+		})
+	}
+	obj["metadata"] = protocol.AppendWarnings(metadata, warnings...)
+	rewritten, err := json.Marshal(obj)
+	if err != nil {
+		return nil, false
+	}
+	return rewritten, true
+}
+
+// partIndex returns the index of the message part a send params pointer refers to.
+func partIndex(pointer string) (int, bool) {
+	rest, ok := strings.CutPrefix(pointer, partsPointer)
+	if !ok {
+		return 0, false
+	}
+	index, _, _ := strings.Cut(rest, "/")
+	i, err := strconv.Atoi(index)
+	if err != nil {
+		return 0, false
+	}
+	return i, true
+}
+
+// methodValidators maps A2A methods to the validator for their params.
+var methodValidators = map[string]func(v *paramsValidator, doc interface{}){
+	protocol.MethodTasksSend:                (*paramsValidator).sendTaskParams,
+	protocol.MethodTasksSendSubscribe:       (*paramsValidator).sendTaskParams,
+	protocol.MethodTasksGet:                 (*paramsValidator).taskQueryParams,
+	protocol.MethodTasksCancel:              (*paramsValidator).cancelTaskParams,
+	protocol.MethodTasksResubscribe:         (*paramsValidator).taskIDParams,
+	protocol.MethodTasksPushNotificationGet: (*paramsValidator).taskIDParams,
+	protocol.MethodTasksPushNotificationSet: (*paramsValidator).taskPushNotificationConfig,
+	protocol.MethodTasksAckEvents:           (*paramsValidator).ackEventsParams,
+	protocol.MethodTasksPollEvents:          (*paramsValidator).pollEventsParams,
+	protocol.MethodTasksGraphSend:           (*paramsValidator).sendTaskGraphParams,
+	protocol.MethodTasksGraphGet:            (*paramsValidator).taskIDParams,
+}
+
+// fail records a violation at the given pointer.
+func (v *paramsValidator) fail(pointer, message string) {
+	v.errs = append(v.errs, ValidationError{Pointer: pointer, Message: message})
+}
+
+// object asserts that doc is a JSON object, recording a violation otherwise.
+func (v *paramsValidator) object(pointer string, doc interface{}) (map[string]interface{}, bool) {
+	obj, ok := doc.(map[string]interface{})
+	if !ok {
+		v.fail(pointer, fmt.Sprintf("must be an object, got %s", jsonType(doc)))
+	}
+	return obj, ok
+}
+
+// requiredString checks that obj[key] exists and is a non-empty string.
+func (v *paramsValidator) requiredString(pointer string, obj map[string]interface{}, key string) (string, bool) {
+	raw, exists := obj[key]
+	if !exists {
+		v.fail(join(pointer, key), "is required")
+		return "", false
+	}
+	s, ok := raw.(string)
+	if !ok {
+		v.fail(join(pointer, key), fmt.Sprintf("must be a string, got %s", jsonType(raw)))
+		return "", false
+	}
+	if s == "" {
+		v.fail(join(pointer, key), "must not be empty")
+		return "", false
+	}
+	return s, true
+}
+
+// optionalString checks that obj[key], if present, is a string.
+func (v *paramsValidator) optionalString(pointer string, obj map[string]interface{}, key string) {
+	raw, exists := obj[key]
+	if !exists || raw == nil {
+		return
+	}
+	if _, ok := raw.(string); !ok {
+		v.fail(join(pointer, key), fmt.Sprintf("must be a string, got %s", jsonType(raw)))
+	}
+}
+
+// optionalObject checks that obj[key], if present, is an object.
+func (v *paramsValidator) optionalObject(pointer string, obj map[string]interface{}, key string) {
+	raw, exists := obj[key]
+	if !exists || raw == nil {
+		return
+	}
+	v.object(join(pointer, key), raw)
+}
+
+// optionalBool checks that obj[key], if present, is a boolean.
+func (v *paramsValidator) optionalBool(pointer string, obj map[string]interface{}, key string) {
+	raw, exists := obj[key]
+	if !exists || raw == nil {
+		return
+	}
+	if _, ok := raw.(bool); !ok {
+		v.fail(join(pointer, key), fmt.Sprintf("must be a boolean, got %s", jsonType(raw)))
+	}
+}
+
+// optionalNonNegativeInt checks that obj[key], if present, is an integer >= 0.
+func (v *paramsValidator) optionalNonNegativeInt(pointer string, obj map[string]interface{}, key string) {
+	raw, exists := obj[key]
+	if !exists || raw == nil {
+		return
+	}
+	num, ok := raw.(json.Number)
+	if !ok {
+		v.fail(join(pointer, key), fmt.Sprintf("must be an integer, got %s", jsonType(raw)))
+		return
+	}
+	n, err := num.Int64()
+	if err != nil {
+		v.fail(join(pointer, key), "must be an integer")
+		return
+	}
+	if n < 0 {
+		v.fail(join(pointer, key), "must not be negative")
+	}
+}
+
+// taskIDParams validates protocol.TaskIDParams.
+func (v *paramsValidator) taskIDParams(doc interface{}) {
+	obj, ok := v.object("", doc)
+	if !ok {
+		return
+	}
+	v.requiredString("", obj, "id")
+	v.optionalObject("", obj, "metadata")
+}
+
+// cancelTaskParams validates protocol.TaskIDParams for tasks/cancel,
+// including the optional cancellation reason.
+func (v *paramsValidator) cancelTaskParams(doc interface{}) {
+	v.taskIDParams(doc)
+	obj, ok := doc.(map[string]interface{})
+	if !ok {
+		return
+	}
+	raw, exists := obj["reason"]
+	if !exists || raw == nil {
+		return
+	}
+	reason, ok := raw.(string)
+	if !ok {
+		v.fail("/reason", fmt.Sprintf("must be a string, got %s", jsonType(raw)))
+		return
+	}
+	if !protocol.CancelReason(reason).IsValid() {
+		v.fail("/reason", fmt.Sprintf("must be one of %q, %q, %q, %q, got %q",
+			protocol.CancelReasonUserRequested, protocol.CancelReasonTimeout,
+			protocol.CancelReasonPolicy, protocol.CancelReasonSuperseded, reason))
+	}
+}
+
+// ackEventsParams validates protocol.AckEventsParams.
+func (v *paramsValidator) ackEventsParams(doc interface{}) {
+	obj, ok := v.object("", doc)
+	if !ok {
+		return
+	}
+	v.requiredString("", obj, "id")
+	v.requiredString("", obj, "streamId")
+	v.optionalNonNegativeInt("", obj, "sequence")
+	v.optionalObject("", obj, "metadata")
+}
+
+// pollEventsParams validates protocol.PollEventsParams.
+func (v *paramsValidator) pollEventsParams(doc interface{}) {
+	obj, ok := v.object("", doc)
+	if !ok {
+		return
+	}
+	v.requiredString("", obj, "id")
+	v.optionalString("", obj, "cursor")
+	v.optionalNonNegativeInt("", obj, "waitMs")
+	v.optionalNonNegativeInt("", obj, "maxEvents")
+	v.optionalObject("", obj, "metadata")
+}
+
+// taskQueryParams validates protocol.TaskQueryParams.
+func (v *paramsValidator) taskQueryParams(doc interface{}) {
+	obj, ok := v.object("", doc)
+	if !ok {
+		return
+	}
+	v.requiredString("", obj, "id")
+	v.optionalNonNegativeInt("", obj, "historyLength")
+}
+
+// sendTaskParams validates protocol.SendTaskParams. The task ID may be
+// omitted, in which case the server generates one.
+func (v *paramsValidator) sendTaskParams(doc interface{}) {
+	v.sendTask("", doc, false)
+}
+
+// sendTask validates protocol.SendTaskParams at the given pointer.
+func (v *paramsValidator) sendTask(pointer string, doc interface{}, requireID bool) {
+	obj, ok := v.object(pointer, doc)
+	if !ok {
+		return
+	}
+	if requireID {
+		v.requiredString(pointer, obj, "id")
+	} else {
+		v.optionalString(pointer, obj, "id")
+	}
+	v.optionalString(pointer, obj, "sessionId")
+	v.optionalNonNegativeInt(pointer, obj, "historyLength")
+	v.optionalObject(pointer, obj, "metadata")
+	v.optionalBool(pointer, obj, "dryRun")
+	raw, exists := obj["message"]
+	if !exists {
+		v.fail(join(pointer, "message"), "is required")
+		return
+	}
+	v.message(join(pointer, "message"), raw)
+}
+
+// sendTaskGraphParams validates protocol.SendTaskGraphParams. Dependencies
+// are checked by the task manager.
+func (v *paramsValidator) sendTaskGraphParams(doc interface{}) {
+	obj, ok := v.object("", doc)
+	if !ok {
+		return
+	}
+	v.requiredString("", obj, "id")
+	v.optionalObject("", obj, "metadata")
+	raw, exists := obj["tasks"]
+	if !exists {
+		v.fail("/tasks", "is required")
+		return
+	}
+	tasks, ok := raw.([]interface{})
+	if !ok {
+		v.fail("/tasks", fmt.Sprintf("must be an array, got %s", jsonType(raw)))
+		return
+	}
+	if len(tasks) == 0 {
+		v.fail("/tasks", "must contain at least one task")
+		return
+	}
+	for i, task := range tasks {
+		pointer := join("/tasks", strconv.Itoa(i))
+		node, ok := v.object(pointer, task)
+		if !ok {
+			continue
+		}
+		if raw, exists := node["task"]; exists {
+			v.sendTask(join(pointer, "task"), raw, true)
+		} else {
+			v.fail(join(pointer, "task"), "is required")
+		}
+		v.stringArray(pointer, node, "dependsOn")
+	}
+}
+
+// stringArray checks that obj[key], if present, is an array of strings.
+func (v *paramsValidator) stringArray(pointer string, obj map[string]interface{}, key string) {
+	raw, exists := obj[key]
+	if !exists || raw == nil {
+		return
+	}
+	items, ok := raw.([]interface{})
+	if !ok {
+		v.fail(join(pointer, key), fmt.Sprintf("must be an array, got %s", jsonType(raw)))
+		return
+	}
+	for i, item := range items {
+		if _, ok := item.(string); !ok {
+			v.fail(join(pointer, key, strconv.Itoa(i)), fmt.Sprintf("must be a string, got %s", jsonType(item)))
+		}
+	}
+}
+
+// message validates protocol.Message.
+func (v *paramsValidator) message(pointer string, doc interface{}) {
+	obj, ok := v.object(pointer, doc)
+	if !ok {
+		return
+	}
+	if role, ok := v.requiredString(pointer, obj, "role"); ok {
+		switch protocol.MessageRole(role) {
+		case protocol.MessageRoleUser, protocol.MessageRoleAgent:
+		default:
+			v.fail(join(pointer, "role"), fmt.Sprintf("must be one of %q, %q, got %q",
+				protocol.MessageRoleUser, protocol.MessageRoleAgent, role))
+		}
+	}
+	v.optionalObject(pointer, obj, "metadata")
+	raw, exists := obj["parts"]
+	if !exists {
+		v.fail(join(pointer, "parts"), "is required")
+		return
+	}
+	parts, ok := raw.([]interface{})
+	if !ok {
+		v.fail(join(pointer, "parts"), fmt.Sprintf("must be an array, got %s", jsonType(raw)))
+		return
+	}
+	if len(parts) == 0 {
+		v.fail(join(pointer, "parts"), "must contain at least one part")
+		return
+	}
+	for i, part := range parts {
+		v.part(join(pointer, "parts", strconv.Itoa(i)), part)
+	}
+}
+
+// part validates a single message part according to its type.
+func (v *paramsValidator) part(pointer string, doc interface{}) {
+	obj, ok := v.object(pointer, doc)
+	if !ok {
+		return
+	}
+	v.optionalObject(pointer, obj, "metadata")
+	partType, ok := v.requiredString(pointer, obj, "type")
+	if !ok {
+		return
+	}
+	switch protocol.PartType(partType) {
+	case protocol.PartTypeText:
+		raw, exists := obj["text"]
+		if !exists {
+			v.fail(join(pointer, "text"), "is required")
+		} else if _, ok := raw.(string); !ok {
+			v.fail(join(pointer, "text"), fmt.Sprintf("must be a string, got %s", jsonType(raw)))
+		}
+	case protocol.PartTypeFile:
+		raw, exists := obj["file"]
+		if !exists {
+			v.fail(join(pointer, "file"), "is required")
+			return
+		}
+		v.fileContent(join(pointer, "file"), raw)
+	case protocol.PartTypeData:
+		if _, exists := obj["data"]; !exists {
+			v.fail(join(pointer, "data"), "is required")
+		}
+	default:
+		v.fail(join(pointer, "type"), fmt.Sprintf("must be one of %q, %q, %q, got %q",
+			protocol.PartTypeText, protocol.PartTypeFile, protocol.PartTypeData, partType))
+	}
+}
+
+// fileContent validates protocol.FileContent.
+func (v *paramsValidator) fileContent(pointer string, doc interface{}) {
+	obj, ok := v.object(pointer, doc)
+	if !ok {
+		return
+	}
+	for _, key := range []string{"name", "mimeType", "bytes", "uri"} {
+		v.optionalString(pointer, obj, key)
+	}
+	_, hasBytes := obj["bytes"]
+	_, hasURI := obj["uri"]
+	if !hasBytes && !hasURI {
+		v.fail(pointer, "must contain either bytes or uri")
+	}
+}
+
+// taskPushNotificationConfig validates protocol.TaskPushNotificationConfig.
+func (v *paramsValidator) taskPushNotificationConfig(doc interface{}) {
+	obj, ok := v.object("", doc)
+	if !ok {
+		return
+	}
+	v.requiredString("", obj, "id")
+	raw, exists := obj["pushNotificationConfig"]
+	if !exists {
+		v.fail("/pushNotificationConfig", "is required")
+		return
+	}
+	config, ok := v.object("/pushNotificationConfig", raw)
+	if !ok {
+		return
+	}
+	v.requiredString("/pushNotificationConfig", config, "url")
+	v.optionalString("/pushNotificationConfig", config, "token")
+	v.optionalObject("/pushNotificationConfig", config, "metadata")
+	v.optionalString("/pushNotificationConfig", config, "template")
+	v.optionalString("/pushNotificationConfig", config, "clientCertificate")
+	if rawHeaders, exists := config["headers"]; exists && rawHeaders != nil {
+		if headers, ok := v.object("/pushNotificationConfig/headers", rawHeaders); ok {
+			for name := range headers {
+				v.optionalString("/pushNotificationConfig/headers", headers, name)
+			}
+		}
+	}
+	if rawEvents, exists := config["events"]; exists && rawEvents != nil {
+		events, ok := rawEvents.([]interface{})
+		if !ok {
+			v.fail("/pushNotificationConfig/events", fmt.Sprintf("must be an array, got %s", jsonType(rawEvents)))
+		}
+		for i, event := range events {
+			if _, ok := event.(string); !ok {
+				v.fail(join("/pushNotificationConfig/events", strconv.Itoa(i)),
+					fmt.Sprintf("must be a string, got %s", jsonType(event)))
+			}
+		}
+	}
+	if rawAuth, exists := config["authentication"]; exists && rawAuth != nil {
+		authPointer := "/pushNotificationConfig/authentication"
+		authObj, ok := v.object(authPointer, rawAuth)
+		if !ok {
+			return
+		}
+		schemes, ok := authObj["schemes"].([]interface{})
+		if !ok {
+			v.fail(join(authPointer, "schemes"), "is required and must be an array")
+			return
+		}
+		for i, scheme := range schemes {
+			if _, ok := scheme.(string); !ok {
+				v.fail(join(authPointer, "schemes", strconv.Itoa(i)),
+					fmt.Sprintf("must be a string, got %s", jsonType(scheme)))
+			}
+		}
+	}
+}
+
+// join appends escaped reference tokens to a JSON pointer.
+func join(pointer string, tokens ...string) string {
+	var b strings.Builder
+	b.WriteString(pointer)
+	for _, token := range tokens {
+		b.WriteByte('/')
+		token = strings.ReplaceAll(token, "~", "~0")
+		b.WriteString(strings.ReplaceAll(token, "/", "~1"))
+	}
+	return b.String()
+}
+
+// jsonType returns the JSON type name of a decoded value for error messages.
+func jsonType(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number, float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
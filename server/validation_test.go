@@ -0,0 +1,268 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+func TestValidateParams(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		params       string
+		wantPointers []string
+	}{
+		{
+			name:   "valid send",
+			method: protocol.MethodTasksSend,
+			params: `{"id":"t1","message":{"role":"user","parts":[{"type":"text","text":"hi"}]}}`,
+		},
+		{
+			name:         "missing message",
+			method:       protocol.MethodTasksSend,
+			params:       `{}`,
+			wantPointers: []string{"/message"},
+		},
+		{
+			name:         "non-string id",
+			method:       protocol.MethodTasksSend,
+			params:       `{"id":1,"message":{"role":"user","parts":[{"type":"text","text":"hi"}]}}`,
+			wantPointers: []string{"/id"},
+		},
+		{
+			name:         "invalid role",
+			method:       protocol.MethodTasksSendSubscribe,
+			params:       `{"id":"t1","message":{"role":"robot","parts":[{"type":"text","text":"hi"}]}}`,
+			wantPointers: []string{"/message/role"},
+		},
+		{
+			name:   "invalid parts",
+			method: protocol.MethodTasksSend,
+			params: `{"id":"t1","message":{"role":"user","parts":[
+				{"type":"text","text":1},
+				{"type":"file","file":{"name":"a.txt"}},
+				{"type":"data"},
+				{"type":"video"}
+			]}}`,
+			wantPointers: []string{
+				"/message/parts/0/text",
+				"/message/parts/1/file",
+				"/message/parts/2/data",
+				"/message/parts/3/type",
+			},
+		},
+		{
+			name:         "negative history length",
+			method:       protocol.MethodTasksGet,
+			params:       `{"id":"t1","historyLength":-1}`,
+			wantPointers: []string{"/historyLength"},
+		},
+		{
+			name:         "fractional history length",
+			method:       protocol.MethodTasksGet,
+			params:       `{"id":"t1","historyLength":1.5}`,
+			wantPointers: []string{"/historyLength"},
+		},
+		{
+			name:         "params not an object",
+			method:       protocol.MethodTasksCancel,
+			params:       `[]`,
+			wantPointers: []string{""},
+		},
+		{
+			name:         "unknown cancel reason",
+			method:       protocol.MethodTasksCancel,
+			params:       `{"id":"t1","reason":"bored"}`,
+			wantPointers: []string{"/reason"},
+		},
+		{
+			name:   "valid cancel reason",
+			method: protocol.MethodTasksCancel,
+			params: `{"id":"t1","reason":"timeout"}`,
+		},
+		{
+			name:         "push config without url",
+			method:       protocol.MethodTasksPushNotificationSet,
+			params:       `{"id":"t1","pushNotificationConfig":{"authentication":{"schemes":[1]}}}`,
+			wantPointers: []string{"/pushNotificationConfig/url", "/pushNotificationConfig/authentication/schemes/0"},
+		},
+		{
+			name:   "push config events and template",
+			method: protocol.MethodTasksPushNotificationSet,
+			params: `{"id":"t1","pushNotificationConfig":{"url":"http://x","events":["status",2],
+				"template":true}}`,
+			wantPointers: []string{"/pushNotificationConfig/template", "/pushNotificationConfig/events/1"},
+		},
+		{
+			name:   "push config headers and client certificate",
+			method: protocol.MethodTasksPushNotificationSet,
+			params: `{"id":"t1","pushNotificationConfig":{"url":"http://x","headers":{"X-Api-Key":1},
+				"clientCertificate":{}}}`,
+			wantPointers: []string{"/pushNotificationConfig/clientCertificate", "/pushNotificationConfig/headers/X-Api-Key"},
+		},
+		{
+			name:   "valid task graph",
+			method: protocol.MethodTasksGraphSend,
+			params: `{"id":"g1","tasks":[
+				{"task":{"id":"a","message":{"role":"user","parts":[{"type":"text","text":"hi"}]}}},
+				{"task":{"id":"b","message":{"role":"user","parts":[{"type":"text","text":"hi"}]}},"dependsOn":["a"]}
+			]}`,
+		},
+		{
+			name:   "invalid task graph nodes",
+			method: protocol.MethodTasksGraphSend,
+			params: `{"id":"g1","tasks":[
+				{"task":{"id":"a","message":{"role":"robot","parts":[{"type":"text","text":"hi"}]}},"dependsOn":[1]},
+				{"dependsOn":"a"}
+			]}`,
+			wantPointers: []string{"/tasks/0/task/message/role", "/tasks/0/dependsOn/0", "/tasks/1/task", "/tasks/1/dependsOn"},
+		},
+		{
+			name:   "unknown method is not validated",
+			method: "vendor/custom",
+			params: `"anything"`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateParams(tc.method, json.RawMessage(tc.params))
+			if len(tc.wantPointers) == 0 {
+				assert.Nil(t, err)
+				return
+			}
+			require.NotNil(t, err)
+			assert.Equal(t, jsonrpc.CodeInvalidParams, err.Code)
+			violations, ok := err.Data.([]ValidationError)
+			require.True(t, ok, "error data should list validation errors")
+			var pointers []string
+			for _, violation := range violations {
+				pointers = append(pointers, violation.Pointer)
+			}
+			assert.Equal(t, tc.wantPointers, pointers)
+		})
+	}
+}
+
+func TestJoinEscapesPointerTokens(t *testing.T) {
+	assert.Equal(t, "/metadata/a~1b/c~0d", join("/metadata", "a/b", "c~d"))
+}
+
+func TestA2AServer_ParamsValidation(t *testing.T) {
+	body := `{"jsonrpc":"2.0","method":"tasks/send","id":"req-1",
+		"params":{"id":"t1","message":{"role":"user","parts":[{"type":"text"}]}}}`
+
+	post := func(t *testing.T, opts ...Option) (int, jsonrpc.Response) {
+		mockTM := newMockTaskManager()
+		mockTM.SendResponse = &protocol.Task{ID: "t1"}
+		testServer, _ := setupTestServer(t, mockTM, opts...)
+		resp, err := http.Post(testServer.URL, "application/json", bytes.NewBufferString(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		data, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		var rpcResp jsonrpc.Response
+		require.NoError(t, json.Unmarshal(data, &rpcResp))
+		return resp.StatusCode, rpcResp
+	}
+
+	t.Run("enabled by default", func(t *testing.T) {
+		status, resp := post(t)
+		assert.Equal(t, http.StatusBadRequest, status)
+		require.NotNil(t, resp.Error)
+		assert.Equal(t, jsonrpc.CodeInvalidParams, resp.Error.Code)
+		assert.Contains(t, resp.Error.Data, map[string]interface{}{
+			"pointer": "/message/parts/0/text",
+			"message": "is required",
+		})
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		status, resp := post(t, WithParamsValidation(false))
+		assert.Equal(t, http.StatusOK, status)
+		assert.Nil(t, resp.Error)
+	})
+}
+
+func TestCheckParams_AcceptValid(t *testing.T) {
+	mixed := `{"id":"t1","metadata":{"warnings":[{"code":"earlier","message":"kept"}]},
+		"message":{"role":"user","parts":[
+			{"type":"text","text":"hi"},
+			{"type":"file","file":{"name":"a.txt"}},
+			{"type":"data","data":{"n":1}}
+		]}}`
+
+	t.Run("reject all by default", func(t *testing.T) {
+		_, err := checkParams(protocol.MethodTasksSend, json.RawMessage(mixed), protocol.PartFailureRejectAll)
+		require.NotNil(t, err)
+		assert.Equal(t, jsonrpc.CodeInvalidParams, err.Code)
+	})
+
+	t.Run("drops invalid parts with warnings", func(t *testing.T) {
+		params, err := checkParams(protocol.MethodTasksSend, json.RawMessage(mixed), protocol.PartFailureAcceptValid)
+		require.Nil(t, err)
+		var sendParams protocol.SendTaskParams
+		require.NoError(t, json.Unmarshal(params, &sendParams))
+		require.Len(t, sendParams.Message.Parts, 2)
+		assert.IsType(t, protocol.TextPart{}, sendParams.Message.Parts[0])
+		assert.IsType(t, protocol.DataPart{}, sendParams.Message.Parts[1])
+		assert.Equal(t, []protocol.Warning{
+			{Code: "earlier", Message: "kept"},
+			{
+				Code:    protocol.WarningCodePartDropped,
+				Message: "must contain either bytes or uri",
+				Pointer: "/message/parts/1/file",
+			},
+		}, protocol.WarningsFromMetadata(sendParams.Metadata))
+	})
+
+	t.Run("valid params are unchanged", func(t *testing.T) {
+		valid := json.RawMessage(`{"id":"t1","message":{"role":"user","parts":[{"type":"text","text":"hi"}]}}`)
+		params, err := checkParams(protocol.MethodTasksSendSubscribe, valid, protocol.PartFailureAcceptValid)
+		require.Nil(t, err)
+		assert.Equal(t, valid, params)
+	})
+
+	rejected := []struct {
+		name   string
+		method string
+		params string
+	}{
+		{
+			name:   "all parts invalid",
+			method: protocol.MethodTasksSend,
+			params: `{"id":"t1","message":{"role":"user","parts":[{"type":"text"},{"type":"video"}]}}`,
+		},
+		{
+			name:   "violation outside parts",
+			method: protocol.MethodTasksSend,
+			params: `{"id":"t1","message":{"role":"robot","parts":[{"type":"text","text":"hi"},{"type":"text"}]}}`,
+		},
+		{
+			name:   "method without message",
+			method: protocol.MethodTasksGet,
+			params: `{"id":"t1","historyLength":-1}`,
+		},
+	}
+	for _, tc := range rejected {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := checkParams(tc.method, json.RawMessage(tc.params), protocol.PartFailureAcceptValid)
+			require.NotNil(t, err)
+			assert.Equal(t, jsonrpc.CodeInvalidParams, err.Code)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"context"
+
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// withWarnings returns task with the warnings reported for the request of ctx
+// (see taskmanager.Warn) added to its metadata. The task is copied rather than
+// modified, since it may be the one held by the task manager.
+func withWarnings(ctx context.Context, task *protocol.Task) *protocol.Task {
+	warnings := taskmanager.TakeWarnings(ctx)
+	if task == nil || len(warnings) == 0 {
+		return task
+	}
+	logWarnings(task.ID, warnings)
+	withWarnings := *task
+	withWarnings.Metadata = appendWarnings(task.Metadata, warnings)
+	return &withWarnings
+}
+
+// eventWithWarnings returns event with the warnings reported for the request
+// of ctx since the previous event added to a copy of its metadata.
+func eventWithWarnings(ctx context.Context, event protocol.TaskEvent) protocol.TaskEvent {
+	warnings := taskmanager.TakeWarnings(ctx)
+	if len(warnings) == 0 {
+		return event
+	}
+	switch e := event.(type) {
+	case protocol.TaskStatusUpdateEvent:
+		logWarnings(e.ID, warnings)
+		e.Metadata = appendWarnings(e.Metadata, warnings)
+		return e
+	case protocol.TaskArtifactUpdateEvent:
+		logWarnings(e.ID, warnings)
+		e.Metadata = appendWarnings(e.Metadata, warnings)
+		return e
+	}
+	return event
+}
+
+// appendWarnings returns a copy of metadata with warnings appended to those
+// it records.
+func appendWarnings(metadata map[string]interface{}, warnings []protocol.Warning) map[string]interface{} {
+	updated := make(map[string]interface{}, len(metadata)+1)
+	for k, v := range metadata {
+		updated[k] = v
+	}
+	return protocol.AppendWarnings(updated, warnings...)
+}
+
+// logWarnings logs the warnings returned for a task.
+func logWarnings(taskID string, warnings []protocol.Warning) {
+	for _, w := range warnings {
+		log.Infof("Returning warning %s for task %s: %s", w.Code, taskID, w.Message)
+	}
+}
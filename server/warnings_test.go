@@ -0,0 +1,88 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"context"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/client"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// fallbackProcessor reports serving every task with a fallback.
+type fallbackProcessor struct{}
+
+func (fallbackProcessor) Process(
+	ctx context.Context,
+	taskID string,
+	message protocol.Message,
+	handle taskmanager.TaskHandle,
+) error {
+	taskmanager.Warn(ctx, protocol.Warning{Code: protocol.WarningCodeFallback, Message: "served from cache"})
+	return handle.UpdateStatus(protocol.TaskStateCompleted, nil)
+}
+
+func TestA2AServer_Warnings(t *testing.T) {
+	tm, err := taskmanager.NewMemoryTaskManager(fallbackProcessor{})
+	require.NoError(t, err)
+	a2aServer, err := NewA2AServer(defaultAgentCard(), tm)
+	require.NoError(t, err)
+	testServer := httptest.NewServer(a2aServer.Handler())
+	defer testServer.Close()
+
+	var (
+		mu       sync.Mutex
+		received []protocol.Warning
+	)
+	c, err := client.NewA2AClient(testServer.URL, client.WithWarningHandler(
+		func(taskID string, warnings []protocol.Warning) {
+			mu.Lock()
+			defer mu.Unlock()
+			received = append(received, warnings...)
+		},
+	))
+	require.NoError(t, err)
+	ctx := context.Background()
+	msg := protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")})
+	want := []protocol.Warning{{Code: protocol.WarningCodeFallback, Message: "served from cache"}}
+
+	task, err := c.SendTasks(ctx, protocol.SendTaskParams{ID: "task-1", Message: msg})
+	require.NoError(t, err)
+	assert.Equal(t, want, protocol.TaskWarnings(task))
+	assert.Equal(t, want, received)
+
+	// Request warnings are not stored with the task.
+	task, err = c.GetTasks(ctx, protocol.TaskQueryParams{ID: "task-1"})
+	require.NoError(t, err)
+	assert.Empty(t, protocol.TaskWarnings(task))
+
+	mu.Lock()
+	received = nil
+	mu.Unlock()
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	events, err := c.StreamTask(streamCtx, protocol.SendTaskParams{ID: "task-2", Message: msg})
+	require.NoError(t, err)
+	var streamed []protocol.Warning
+	for event := range events {
+		streamed = append(streamed, protocol.EventWarnings(event)...)
+		if event.IsFinal() {
+			break
+		}
+	}
+	assert.Equal(t, want, streamed)
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, want, received)
+}
@@ -0,0 +1,326 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/codec"
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/internal/websocket"
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/metadata"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// Notifier sends JSON-RPC notifications from the server to a client connected
+// over WebSocket.
+type Notifier interface {
+	// Notify sends a notification of method with params, marshaled with the
+	// codec of the server.
+	Notify(method string, params interface{}) error
+}
+
+// notifierKey is the context key of the Notifier of a WebSocket connection.
+type notifierKey struct{}
+
+// NotifierFromContext returns the Notifier of the client connection through
+// which the request of ctx was received, if it was received over WebSocket.
+// It is available to the handlers of custom methods and to task managers.
+func NotifierFromContext(ctx context.Context) (Notifier, bool) {
+	notifier, ok := ctx.Value(notifierKey{}).(Notifier)
+	return notifier, ok
+}
+
+const (
+	// wsMaxConcurrentRequests bounds the requests of a WebSocket connection
+	// handled at the same time. The connection is not read while it has
+	// that many.
+	wsMaxConcurrentRequests = 32
+	// wsSendQueueSize bounds the messages queued for a WebSocket connection.
+	wsSendQueueSize = 64
+	// wsWriteTimeout bounds the write of a message to a WebSocket connection,
+	// which is closed if the client does not read it in time.
+	wsWriteTimeout = 10 * time.Second
+)
+
+// errWSPeerBusy is returned by the notifications of a WebSocket client that
+// does not read the messages queued for it.
+var errWSPeerBusy = errors.New("websocket client not keeping up, notification dropped")
+
+// wsPeer is a client connected over WebSocket. Its messages are queued and
+// written by writeLoop, so that a slow client delays no one else.
+type wsPeer struct {
+	conn  *websocket.Conn
+	codec codec.Codec
+	queue chan []byte
+	done  chan struct{} // Closed when the connection ends.
+}
+
+// newWSPeer creates the peer of conn.
+func newWSPeer(conn *websocket.Conn, codec codec.Codec) *wsPeer {
+	return &wsPeer{
+		conn:  conn,
+		codec: codec,
+		queue: make(chan []byte, wsSendQueueSize),
+		done:  make(chan struct{}),
+	}
+}
+
+// Notify implements Notifier. The notification is dropped with an error if
+// the queue of the client is full.
+func (p *wsPeer) Notify(method string, params interface{}) error {
+	paramsBytes, err := p.codec.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification params: %w", err)
+	}
+	notification := jsonrpc.NewNotification(method)
+	notification.Params = paramsBytes
+	data, err := p.codec.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("failed to marshal WebSocket message: %w", err)
+	}
+	select {
+	case p.queue <- data:
+		return nil
+	case <-p.done:
+		return websocket.ErrClosed
+	default:
+		return errWSPeerBusy
+	}
+}
+
+// send marshals and queues a message, waiting for room in the queue.
+func (p *wsPeer) send(message interface{}) error {
+	data, err := p.codec.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal WebSocket message: %w", err)
+	}
+	return p.sendData(data)
+}
+
+// sendData queues data, waiting for room in the queue.
+func (p *wsPeer) sendData(data []byte) error {
+	select {
+	case p.queue <- data:
+		return nil
+	case <-p.done:
+		return websocket.ErrClosed
+	}
+}
+
+// writeLoop writes the queued messages until the connection ends. A write
+// failing or exceeding wsWriteTimeout closes the connection.
+func (p *wsPeer) writeLoop() {
+	for {
+		select {
+		case data := <-p.queue:
+			_ = p.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+			if err := p.conn.WriteMessage(data); err != nil {
+				log.Debugf("Closing WebSocket connection after failed write: %v", err)
+				p.conn.Close()
+				return
+			}
+		case <-p.done:
+			return
+		}
+	}
+}
+
+// wsPeers tracks the clients connected over WebSocket.
+type wsPeers struct {
+	mu    sync.Mutex
+	peers map[*wsPeer]struct{}
+}
+
+// add tracks peer.
+func (p *wsPeers) add(peer *wsPeer) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.peers == nil {
+		p.peers = make(map[*wsPeer]struct{})
+	}
+	p.peers[peer] = struct{}{}
+}
+
+// remove stops tracking peer.
+func (p *wsPeers) remove(peer *wsPeer) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.peers, peer)
+}
+
+// list returns the connected peers.
+func (p *wsPeers) list() []*wsPeer {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	peers := make([]*wsPeer, 0, len(p.peers))
+	for peer := range p.peers {
+		peers = append(peers, peer)
+	}
+	return peers
+}
+
+// closeAll closes the connections of the peers.
+func (p *wsPeers) closeAll() {
+	for _, peer := range p.list() {
+		peer.conn.Close()
+	}
+}
+
+// Broadcast sends a notification of method with params to every client
+// connected over WebSocket and returns the number of clients it was sent to.
+func (s *A2AServer) Broadcast(method string, params interface{}) int {
+	return s.broadcast(context.Background(), method, params)
+}
+
+// broadcast implements Broadcast, stopping once ctx is done.
+func (s *A2AServer) broadcast(ctx context.Context, method string, params interface{}) int {
+	sent := 0
+	for _, peer := range s.wsPeers.list() {
+		if ctx.Err() != nil {
+			log.Warnf("Gave up sending notification %s over WebSocket: %v", method, ctx.Err())
+			break
+		}
+		if err := peer.Notify(method, params); err != nil {
+			log.Debugf("Failed to send notification %s over WebSocket: %v", method, err)
+			continue
+		}
+		sent++
+	}
+	return sent
+}
+
+// allowedWebSocketOrigin reports whether r may open a WebSocket connection:
+// it was not sent by a browser, or from the origin of the server or one
+// allowed by WithWebSocket.
+func (s *A2AServer) allowedWebSocketOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	for _, allowed := range s.wsOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// handleWebSocket serves JSON-RPC over the WebSocket connection upgraded from
+// r. Each message received is a request or a notification, handled
+// concurrently like those received over HTTP, up to wsMaxConcurrentRequests
+// at a time; the responses are sent back as messages as they complete.
+func (s *A2AServer) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	if !s.allowedWebSocketOrigin(r) {
+		log.Warnf("Rejecting WebSocket connection from origin %s", r.Header.Get("Origin"))
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return
+	}
+	conn, err := websocket.Upgrade(w, r)
+	if err != nil {
+		log.Warnf("Rejecting WebSocket connection: %v", err)
+		return
+	}
+	defer conn.Close()
+	if s.maxRequestBytes > 0 {
+		conn.SetMaxMessageSize(s.maxRequestBytes)
+	}
+	peer := newWSPeer(conn, s.codec)
+	defer close(peer.done)
+	go peer.writeLoop()
+	s.wsPeers.add(peer)
+	defer s.wsPeers.remove(peer)
+	log.Infof("WebSocket connection opened from %s", r.RemoteAddr)
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	if md := metadata.FromHeader(r.Header); md.Len() > 0 {
+		ctx = metadata.NewIncomingContext(ctx, md)
+	}
+	ctx = context.WithValue(ctx, notifierKey{}, Notifier(peer))
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	slots := make(chan struct{}, wsMaxConcurrentRequests)
+	for {
+		message, err := conn.ReadMessage()
+		if err != nil {
+			if err != io.EOF {
+				log.Debugf("WebSocket connection from %s failed: %v", r.RemoteAddr, err)
+			}
+			log.Infof("WebSocket connection closed from %s", r.RemoteAddr)
+			cancel()
+			return
+		}
+		slots <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			s.handleWebSocketMessage(ctx, peer, message)
+		}()
+	}
+}
+
+// handleWebSocketMessage handles a message received over WebSocket.
+func (s *A2AServer) handleWebSocketMessage(ctx context.Context, peer *wsPeer, message []byte) {
+	var request jsonrpc.Request
+	if err := s.codec.Unmarshal(message, &request); err != nil {
+		s.sendWebSocketError(peer, nil, jsonrpc.ErrParseError(fmt.Sprintf("failed to parse JSON request: %v", err)))
+		return
+	}
+	notification := jsonrpc.IsNotification(message)
+	if request.JSONRPC != jsonrpc.Version {
+		if !notification {
+			s.sendWebSocketError(peer, request.ID,
+				jsonrpc.ErrInvalidRequest(fmt.Sprintf("jsonrpc field must be '%s'", jsonrpc.Version)))
+		}
+		return
+	}
+	switch request.Method {
+	case protocol.MethodTasksSendSubscribe, protocol.MethodTasksResubscribe:
+		// Streams are served over HTTP only.
+		if !notification {
+			s.sendWebSocketError(peer, request.ID, jsonrpc.ErrMethodNotFound(
+				fmt.Sprintf("method '%s' is not supported over WebSocket, use SSE", request.Method)))
+		}
+		return
+	}
+	// The handlers write their response as they would over HTTP.
+	response := &notificationWriter{header: make(http.Header)}
+	s.routeJSONRPCMethod(taskmanager.ContextWithWarnings(ctx), response, request)
+	if notification {
+		if response.status >= http.StatusBadRequest {
+			log.Warnf("JSON-RPC notification (Method: %s) failed with status %d: %s",
+				request.Method, response.status, bytes.TrimSpace(response.body.Bytes()))
+		}
+		return
+	}
+	if err := peer.sendData(bytes.TrimSpace(response.body.Bytes())); err != nil {
+		log.Debugf("Failed to send JSON-RPC response (ID: %v) over WebSocket: %v", request.ID, err)
+	}
+}
+
+// sendWebSocketError sends an error response over WebSocket.
+func (s *A2AServer) sendWebSocketError(peer *wsPeer, id interface{}, err *jsonrpc.Error) {
+	if sendErr := peer.send(jsonrpc.NewErrorResponse(id, err)); sendErr != nil {
+		log.Debugf("Failed to send JSON-RPC error (ID: %v) over WebSocket: %v", id, sendErr)
+	}
+}
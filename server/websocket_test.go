@@ -0,0 +1,177 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/client"
+	"trpc.group/trpc-go/trpc-a2a-go/codec"
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/internal/websocket"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+func TestA2AServer_WebSocket(t *testing.T) {
+	var pings atomic.Int32
+	methods := []Method{
+		{
+			Name: "vendor/echo",
+			Handler: TypedMethod(func(ctx context.Context, params statsParams) (statsParams, error) {
+				if notifier, ok := NotifierFromContext(ctx); ok {
+					if err := notifier.Notify("vendor/progress", params); err != nil {
+						return statsParams{}, err
+					}
+				}
+				return params, nil
+			}),
+		},
+		{
+			Name: "vendor/ping",
+			Handler: func(ctx context.Context, decode func(v interface{}) error) (interface{}, error) {
+				pings.Add(1)
+				return nil, nil
+			},
+		},
+	}
+	a2aServer, err := NewA2AServer(defaultAgentCard(), newMockTaskManager(),
+		WithWebSocket(), WithMethods(methods...))
+	require.NoError(t, err)
+	testServer := httptest.NewServer(a2aServer.Handler())
+	defer testServer.Close()
+
+	c, err := client.NewA2AClient(testServer.URL, client.WithTimeout(5*time.Second))
+	require.NoError(t, err)
+
+	// Plain HTTP requests are still served, without a notifier.
+	var result statsParams
+	require.NoError(t, c.Call(context.Background(), "vendor/echo", statsParams{Window: "http"}, &result))
+	assert.Equal(t, "http", result.Window)
+	require.NoError(t, c.Notify(context.Background(), "vendor/ping", nil))
+	assert.Equal(t, int32(1), pings.Load())
+
+	// The dial context bounds the handshake only.
+	dialCtx, cancel := context.WithCancel(context.Background())
+	session, err := c.DialWebSocket(dialCtx)
+	cancel()
+	require.NoError(t, err)
+	defer session.Close()
+
+	progress := make(chan string, 1)
+	session.HandleNotification("vendor/progress", func(params json.RawMessage) {
+		var p statsParams
+		if assert.NoError(t, json.Unmarshal(params, &p)) {
+			progress <- p.Window
+		}
+	})
+	broadcasts := make(chan json.RawMessage, 1)
+	session.HandleNotification("vendor/announce", func(params json.RawMessage) {
+		broadcasts <- params
+	})
+
+	ctx := context.Background()
+	require.NoError(t, session.Call(ctx, "vendor/echo", statsParams{Window: "ws"}, &result))
+	assert.Equal(t, "ws", result.Window)
+	assert.Equal(t, "ws", <-progress, "handlers notify the client of the request")
+
+	require.NoError(t, session.Notify("vendor/ping", nil))
+	require.Eventually(t, func() bool { return pings.Load() == 2 }, 2*time.Second, 10*time.Millisecond)
+
+	// Task methods are served, but streams are not.
+	var task protocol.Task
+	require.NoError(t, session.Call(ctx, protocol.MethodTasksSend, protocol.SendTaskParams{
+		ID:      "ws-task",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")}),
+	}, &task))
+	assert.Equal(t, "ws-task", task.ID)
+	err = session.Call(ctx, protocol.MethodTasksSendSubscribe, protocol.SendTaskParams{ID: "ws-task"}, nil)
+	var rpcErr *jsonrpc.Error
+	require.True(t, errors.As(err, &rpcErr), "got %v", err)
+	assert.Equal(t, jsonrpc.CodeMethodNotFound, rpcErr.Code)
+	err = session.Call(ctx, "vendor/unknown", nil, nil)
+	require.True(t, errors.As(err, &rpcErr), "got %v", err)
+	assert.Equal(t, jsonrpc.CodeMethodNotFound, rpcErr.Code)
+
+	assert.Equal(t, 1, a2aServer.Broadcast("vendor/announce", map[string]string{"card": "changed"}))
+	select {
+	case params := <-broadcasts:
+		assert.JSONEq(t, `{"card":"changed"}`, string(params))
+	case <-time.After(2 * time.Second):
+		t.Fatal("broadcast not received")
+	}
+
+	require.NoError(t, session.Close())
+	select {
+	case <-session.Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("session not ended")
+	}
+	assert.ErrorIs(t, session.Call(ctx, "vendor/echo", nil, nil), client.ErrSessionClosed)
+	require.Eventually(t, func() bool {
+		return a2aServer.Broadcast("vendor/announce", nil) == 0
+	}, 2*time.Second, 10*time.Millisecond, "closed connections are forgotten")
+}
+
+func TestA2AServer_WebSocketDisabled(t *testing.T) {
+	a2aServer, err := NewA2AServer(defaultAgentCard(), newMockTaskManager())
+	require.NoError(t, err)
+	testServer := httptest.NewServer(a2aServer.Handler())
+	defer testServer.Close()
+
+	c, err := client.NewA2AClient(testServer.URL)
+	require.NoError(t, err)
+	_, err = c.DialWebSocket(context.Background())
+	assert.Error(t, err)
+}
+
+func TestA2AServer_WebSocketOrigin(t *testing.T) {
+	a2aServer, err := NewA2AServer(defaultAgentCard(), newMockTaskManager(),
+		WithWebSocket("https://app.example.com"))
+	require.NoError(t, err)
+	testServer := httptest.NewServer(a2aServer.Handler())
+	defer testServer.Close()
+
+	for origin, status := range map[string]int{
+		"":                         http.StatusSwitchingProtocols,
+		testServer.URL:             http.StatusSwitchingProtocols,
+		"https://app.example.com":  http.StatusSwitchingProtocols,
+		"https://evil.example.com": http.StatusForbidden,
+	} {
+		req, err := http.NewRequest(http.MethodGet, testServer.URL, nil)
+		require.NoError(t, err)
+		_, err = websocket.SetHandshakeHeaders(req.Header)
+		require.NoError(t, err)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, status, resp.StatusCode, "origin %q", origin)
+	}
+}
+
+func TestWSPeer_NotifyBusy(t *testing.T) {
+	// Without writeLoop, nothing is written and the queue fills up.
+	peer := newWSPeer(nil, codec.Default)
+	for i := 0; i < wsSendQueueSize; i++ {
+		require.NoError(t, peer.Notify("vendor/announce", i))
+	}
+	assert.ErrorIs(t, peer.Notify("vendor/announce", nil), errWSPeerBusy)
+	close(peer.done)
+	assert.ErrorIs(t, peer.Notify("vendor/announce", nil), websocket.ErrClosed)
+}
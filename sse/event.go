@@ -0,0 +1,103 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package sse
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ContentType is the media type of event streams.
+const ContentType = "text/event-stream"
+
+// ErrInvalidEvent is returned when writing an event whose ID or type cannot be
+// represented in an event stream.
+var ErrInvalidEvent = errors.New("invalid SSE event")
+
+// Event is an event of an event stream.
+type Event struct {
+	// ID is the event ID, which readers report as their last event ID and
+	// send back when reconnecting. It must not contain CR, LF or NUL.
+	ID string
+	// Type is the event type. Readers default it to "message" when empty.
+	// It must not contain CR or LF.
+	Type string
+	// Data is the payload of the event. Its lines are sent as separate data
+	// fields and joined back with LF by readers, so CRLF and CR line endings
+	// within the data are read as LF.
+	Data []byte
+	// Retry, if positive, advises readers to wait this long before
+	// reconnecting after the stream breaks. It is sent in milliseconds.
+	Retry time.Duration
+}
+
+// WriteTo writes the event to w with a single Write call. It implements
+// io.WriterTo.
+func (e Event) WriteTo(w io.Writer) (int64, error) {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	if err := appendEvent(buf, e); err != nil {
+		return 0, err
+	}
+	n, err := w.Write(buf.Bytes())
+	return int64(n), err
+}
+
+// appendEvent appends the complete frame of e to buf.
+func appendEvent(buf *bytes.Buffer, e Event) error {
+	if strings.ContainsAny(e.ID, "\r\n\x00") {
+		return fmt.Errorf("%w: ID %q contains a line break or NUL", ErrInvalidEvent, e.ID)
+	}
+	if strings.ContainsAny(e.Type, "\r\n") {
+		return fmt.Errorf("%w: type %q contains a line break", ErrInvalidEvent, e.Type)
+	}
+	if e.ID != "" {
+		buf.WriteString("id: ")
+		buf.WriteString(e.ID)
+		buf.WriteByte('\n')
+	}
+	if e.Type != "" {
+		buf.WriteString("event: ")
+		buf.WriteString(e.Type)
+		buf.WriteByte('\n')
+	}
+	if e.Retry > 0 {
+		buf.WriteString("retry: ")
+		buf.WriteString(strconv.FormatInt(e.Retry.Milliseconds(), 10))
+		buf.WriteByte('\n')
+	}
+	data := e.Data
+	for {
+		buf.WriteString("data: ")
+		i := bytes.IndexAny(data, "\r\n")
+		if i < 0 {
+			buf.Write(data)
+			buf.WriteString("\n\n")
+			return nil
+		}
+		buf.Write(data[:i])
+		buf.WriteByte('\n')
+		if data[i] == '\r' && i+1 < len(data) && data[i+1] == '\n' {
+			i++
+		}
+		data = data[i+1:]
+	}
+}
+
+// SetHeaders sets the headers of an event stream response on h: its content
+// type and the directives keeping caches and proxies from buffering it.
+func SetHeaders(h http.Header) {
+	h.Set("Content-Type", ContentType)
+	h.Set("Cache-Control", "no-cache")
+	h.Set("Connection", "keep-alive")
+}
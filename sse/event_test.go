@@ -0,0 +1,70 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package sse
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventWriteTo(t *testing.T) {
+	var buf bytes.Buffer
+	event := Event{ID: "7", Type: "update", Data: []byte("a\nb\r\nc\rd"), Retry: 1500 * time.Millisecond}
+	n, err := event.WriteTo(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, int64(buf.Len()), n)
+	assert.Equal(t, "id: 7\nevent: update\nretry: 1500\ndata: a\ndata: b\ndata: c\ndata: d\n\n", buf.String())
+
+	// The event reads back with its lines joined with LF.
+	r := NewEventReader(&buf)
+	read, err := r.Next()
+	require.NoError(t, err)
+	assert.Equal(t, Event{ID: "7", Type: "update", Data: []byte("a\nb\nc\nd")}, read)
+	retry, ok := r.Retry()
+	assert.True(t, ok)
+	assert.Equal(t, 1500*time.Millisecond, retry)
+
+	buf.Reset()
+	_, err = Event{Data: []byte("x")}.WriteTo(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, "data: x\n\n", buf.String(), "empty fields are omitted")
+
+	for _, invalid := range []Event{{ID: "a\nb"}, {ID: "a\x00"}, {Type: "a\rb"}} {
+		_, err := invalid.WriteTo(&buf)
+		assert.ErrorIs(t, err, ErrInvalidEvent)
+	}
+}
+
+func TestWriterOpenSendComment(t *testing.T) {
+	rec := &countingRecorder{ResponseRecorder: httptest.NewRecorder()}
+	w := NewWriter(rec, nil, 0)
+	require.NoError(t, w.Open())
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, ContentType, rec.Header().Get("Content-Type"))
+	assert.Equal(t, "no-cache", rec.Header().Get("Cache-Control"))
+	assert.Equal(t, 1, rec.flushes, "headers are flushed")
+
+	require.NoError(t, w.WriteComment("keep-alive\nping"))
+	require.NoError(t, w.WriteComment(""))
+	require.NoError(t, w.Send(Event{Type: "tick", Data: []byte("1")}))
+	assert.Error(t, w.Send(Event{Type: "a\nb"}))
+	assert.Equal(t, ": keep-alive\n: ping\n:\nevent: tick\ndata: 1\n\n", rec.Body.String())
+	assert.Equal(t, 3, rec.writes)
+	assert.Equal(t, 4, rec.flushes)
+
+	rec = &countingRecorder{ResponseRecorder: httptest.NewRecorder()}
+	rec.Header().Set("Content-Type", "text/event-stream; charset=utf-8")
+	require.NoError(t, NewWriter(rec, nil, 0).Open())
+	assert.Equal(t, "text/event-stream; charset=utf-8", rec.Header().Get("Content-Type"),
+		"a content type already set is kept")
+}
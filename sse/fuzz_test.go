@@ -0,0 +1,84 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package sse
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// FuzzEventReader checks that reading hostile streams terminates without
+// panicking. Malformed event seeds are in testdata/fuzz/FuzzEventReader.
+func FuzzEventReader(f *testing.F) {
+	for _, seed := range []string{
+		"event: task_status_update\ndata: {\"id\":\"t\"}\n\n",
+		"id: 1\nevent: a\ndata: line1\ndata: line2\n\n",
+		": keep-alive\n\n\n\ndata: x\n\n",
+		"data:no-space\r\n\r\n",
+		"retry: 10\nid:\ndata\n\n",
+	} {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, stream []byte) {
+		r := NewEventReader(bytes.NewReader(stream))
+		// Each event consumes at least one byte of the stream.
+		for i := 0; i <= len(stream)+1; i++ {
+			data, eventType, err := r.ReadEvent()
+			if err != nil {
+				if data != nil {
+					t.Errorf("data %q returned with error %v", data, err)
+				}
+				return
+			}
+			if eventType == "" {
+				t.Errorf("event without type")
+			}
+		}
+		t.Fatal("reader did not reach the end of the stream")
+	})
+}
+
+// FuzzEventRoundTrip checks that the events written by FormatEvent are read
+// back unchanged.
+func FuzzEventRoundTrip(f *testing.F) {
+	f.Add("task_status_update", `{"id":"t"}`)
+	f.Add("message", "multi\nline\r\ndata")
+	f.Add(" padded ", "")
+	f.Add("custom", "\x00\u2028data: injected\n\nevent: x")
+	f.Fuzz(func(t *testing.T, eventType, payload string) {
+		if strings.ContainsAny(eventType, "\r\n") || len(payload) > 16<<10 || !utf8.ValidString(payload) {
+			// Event types are constants of the server, lines are limited by
+			// the reader and invalid UTF-8 is replaced by the encoder.
+			t.Skip()
+		}
+		var buf bytes.Buffer
+		if err := FormatEvent(&buf, eventType, payload); err != nil {
+			t.Fatal(err)
+		}
+		data, gotType, err := NewEventReader(&buf).ReadEvent()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := eventType
+		if want == "" {
+			want = "message"
+		}
+		if gotType != want {
+			t.Errorf("event type %q, want %q", gotType, want)
+		}
+		var got string
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("invalid data %q: %v", data, err)
+		}
+		if got != payload {
+			t.Errorf("payload %q, want %q", got, payload)
+		}
+	})
+}
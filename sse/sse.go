@@ -0,0 +1,224 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+// Package sse reads and writes Server-Sent Events (SSE) streams compatible with
+// the streams of the A2A server and client.
+//
+// Writers send Events, or the JSON-RPC events of A2A streams, to HTTP
+// responses, flushing each event. Readers parse streams following the WHATWG
+// rules, and Stream reconnects like a browser EventSource, resuming streams
+// after the last event received. Servers get that event ID with LastEventID.
+package sse
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/codec"
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+)
+
+// CloseEventData represents the data payload for a close event.
+// Used when formatting SSE messages indicating stream closure.
+type CloseEventData struct {
+	TaskID string `json:"taskId"`
+	Reason string `json:"reason"`
+}
+
+// EventReader helps parse text/event-stream formatted data, following the
+// parsing rules of the WHATWG HTML specification: lines end with CRLF, LF or
+// CR, a leading byte order mark is ignored, the data lines of an event are
+// joined with LF, unknown fields and comments are ignored and an event not
+// terminated by an empty line before the end of the stream is discarded.
+type EventReader struct {
+	scanner     *bufio.Scanner
+	started     bool // Whether the first line, with a possible BOM, was read.
+	skipLF      bool // Whether the previous line ended with CR.
+	lastEventID string
+	retry       time.Duration
+	hasRetry    bool
+}
+
+// byteOrderMark is the UTF-8 byte order mark.
+var byteOrderMark = []byte("\xEF\xBB\xBF")
+
+// NewEventReader creates a new reader for SSE events.
+// Exported function.
+func NewEventReader(r io.Reader) *EventReader {
+	reader := &EventReader{scanner: bufio.NewScanner(r)}
+	reader.scanner.Split(reader.scanLines)
+	return reader
+}
+
+// scanLines is a bufio.SplitFunc splitting lines ending with CRLF, LF or CR.
+// A line ending with CR is returned at once, without waiting for a following
+// LF, which is then skipped.
+func (r *EventReader) scanLines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	skip := 0
+	if r.skipLF && len(data) > 0 {
+		r.skipLF = false
+		if data[0] == '\n' {
+			skip = 1
+		}
+	}
+	if i := bytes.IndexAny(data[skip:], "\r\n"); i >= 0 {
+		r.skipLF = data[skip+i] == '\r'
+		return skip + i + 1, data[skip : skip+i], nil
+	}
+	if atEOF {
+		if len(data) > skip {
+			return len(data), data[skip:], nil
+		}
+		return len(data), nil, nil
+	}
+	// Request more data, keeping the pending LF to skip it with the line.
+	r.skipLF = skip > 0
+	return 0, nil, nil
+}
+
+// LastEventID returns the value of the last id field read, which per the SSE
+// specification applies to the event being read and those following it.
+func (r *EventReader) LastEventID() string {
+	return r.lastEventID
+}
+
+// Retry returns the reconnection time last set by a retry field, if any.
+func (r *EventReader) Retry() (time.Duration, bool) {
+	return r.retry, r.hasRetry
+}
+
+// Next reads the next event like ReadEvent and returns it with the last event
+// ID. Its Retry is not set: the reconnection time is reported by Retry.
+func (r *EventReader) Next() (Event, error) {
+	data, eventType, err := r.ReadEvent()
+	if err != nil {
+		return Event{}, err
+	}
+	return Event{ID: r.lastEventID, Type: eventType, Data: data}, nil
+}
+
+// ReadEvent reads the next complete event from the stream.
+// It returns the event data, event type, and any error (including io.EOF).
+// Exported method.
+func (r *EventReader) ReadEvent() (data []byte, eventType string, err error) {
+	var dataBuffer bytes.Buffer
+	for r.scanner.Scan() {
+		line := r.scanner.Bytes()
+		if !r.started {
+			r.started = true
+			line = bytes.TrimPrefix(line, byteOrderMark)
+		}
+		if len(line) == 0 {
+			// An empty line dispatches the event, if it has data.
+			if dataBuffer.Len() == 0 {
+				// Double newline without data is just a keep-alive tick.
+				eventType = ""
+				continue
+			}
+			if eventType == "" {
+				eventType = "message" // Default event type per SSE spec.
+			}
+			// Remove the last newline added after each data line.
+			d := dataBuffer.Bytes()
+			return d[:len(d)-1], eventType, nil
+		}
+		if line[0] == ':' {
+			// Comment line, ignore.
+			continue
+		}
+		field, value := line, []byte(nil)
+		if i := bytes.IndexByte(line, ':'); i >= 0 {
+			field, value = line[:i], bytes.TrimPrefix(line[i+1:], []byte(" "))
+		}
+		switch string(field) {
+		case "event":
+			eventType = string(value)
+		case "data":
+			// Preserve newlines between the data lines.
+			dataBuffer.Write(value)
+			dataBuffer.WriteByte('\n')
+		case "id":
+			if bytes.IndexByte(value, 0) < 0 {
+				r.lastEventID = string(value)
+			}
+		case "retry":
+			// Values that are not only ASCII digits are ignored.
+			if ms, err := strconv.ParseUint(string(value), 10, 32); err == nil {
+				r.retry, r.hasRetry = time.Duration(ms)*time.Millisecond, true
+			}
+		default:
+			log.Debugf("Ignoring unknown SSE field %q", field)
+		}
+	}
+	// Scanner finished, check for errors.
+	if err := r.scanner.Err(); err != nil {
+		return nil, "", err
+	}
+	// An event not terminated by an empty line is discarded.
+	return nil, "", io.EOF
+}
+
+// FormatEvent marshals the given data to JSON and writes it to the writer
+// in the standard SSE format (event: type\\ndata: json\\n\\n).
+// It handles potential JSON marshaling errors.
+// Exported function.
+func FormatEvent(w io.Writer, eventType string, data interface{}) error {
+	return FormatEventWithCodec(w, codec.Default, eventType, data)
+}
+
+// FormatEventWithCodec is like FormatEvent but marshals data with the given codec.
+// Exported function.
+func FormatEventWithCodec(w io.Writer, c codec.Codec, eventType string, data interface{}) error {
+	jsonData, err := c.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal SSE event data: %w", err)
+	}
+	// Format according to text/event-stream specification.
+	// event: <eventType>
+	// data: <jsonData>
+	// <empty line>
+	if err := writeFrame(w, eventType, jsonData); err != nil {
+		return fmt.Errorf("failed to write SSE event: %w", err)
+	}
+	return nil
+}
+
+// FormatJSONRPCEvent marshals the given data as a JSON-RPC response and writes
+// it to the writer in SSE format. The event type is used as the SSE event type.
+// The id parameter allows correlation with the original request.
+// It handles potential JSON marshaling errors.
+// Exported function.
+func FormatJSONRPCEvent(w io.Writer, eventType string, id interface{}, data interface{}) error {
+	return FormatJSONRPCEventWithCodec(w, codec.Default, eventType, id, data)
+}
+
+// FormatJSONRPCEventWithCodec is like FormatJSONRPCEvent but marshals the
+// JSON-RPC envelope with the given codec.
+// Exported function.
+func FormatJSONRPCEventWithCodec(
+	w io.Writer, c codec.Codec, eventType string, id interface{}, data interface{},
+) error {
+	// Create a JSON-RPC response with the data as the result
+	response := jsonrpc.NewNotificationResponse(id, data)
+	// Marshal the entire JSON-RPC envelope
+	jsonData, err := c.Marshal(response)
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON-RPC SSE event data: %w", err)
+	}
+	// Format according to text/event-stream specification
+	// event: <eventType>
+	// data: <jsonrpc_envelope>
+	// <empty line>
+	if err := writeFrame(w, eventType, jsonData); err != nil {
+		return fmt.Errorf("failed to write JSON-RPC SSE event: %w", err)
+	}
+	return nil
+}
@@ -7,8 +7,10 @@ import (
 	"io"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
 )
 
@@ -75,9 +77,8 @@ func TestReadEvent(t *testing.T) {
 		{
 			name:          "data with EOF without final newline",
 			input:         "data: test data",
-			expectedData:  "test data",
-			expectedType:  "message",
 			expectedError: io.EOF,
+			expectNoEvent: true,
 		},
 		{
 			name:          "empty input",
@@ -88,7 +89,7 @@ func TestReadEvent(t *testing.T) {
 		{
 			name:          "unrecognized line prefix",
 			input:         "unknown: value\ndata: test\n\n",
-			expectedData:  "unknown: value\ntest",
+			expectedData:  "test",
 			expectedType:  "message",
 			expectedError: nil,
 		},
@@ -290,3 +291,126 @@ func TestFormatJSONRPCEvent(t *testing.T) {
 	assert.Equal(t, "value1", resultMap["key1"], "Value for key1 should match")
 	assert.Equal(t, "value2", resultMap["key2"], "Value for key2 should match")
 }
+
+func TestReadEventCompliance(t *testing.T) {
+	type event struct{ eventType, data string }
+	tests := []struct {
+		name        string
+		input       string
+		events      []event
+		lastEventID string
+		retry       time.Duration
+	}{
+		{
+			name:   "CRLF line endings",
+			input:  "event: a\r\ndata: 1\r\n\r\ndata: 2\r\n\r\n",
+			events: []event{{"a", "1"}, {"message", "2"}},
+		},
+		{
+			name:   "CR line endings",
+			input:  "event: a\rdata: 1\r\rdata: 2\r\r",
+			events: []event{{"a", "1"}, {"message", "2"}},
+		},
+		{
+			name:   "mixed line endings",
+			input:  "data: 1\r\ndata: 2\rdata: 3\n\r\n",
+			events: []event{{"message", "1\n2\n3"}},
+		},
+		{
+			name:   "leading byte order mark",
+			input:  "\xEF\xBB\xBFdata: 1\n\n\xEF\xBB\xBFdata: 2\n\ndata: 3\n\n",
+			events: []event{{"message", "1"}, {"message", "3"}},
+		},
+		{
+			name:   "multi-line and empty data",
+			input:  "data: a\ndata\ndata:\ndata: b\n\ndata\n\n",
+			events: []event{{"message", "a\n\n\nb"}, {"message", ""}},
+		},
+		{
+			name:   "only one leading space is removed",
+			input:  "data:  two\nevent:  x\n\n",
+			events: []event{{" x", " two"}},
+		},
+		{
+			name:   "event type is reset after each event",
+			input:  "event: a\n\ndata: 1\n\nevent: b\ndata: 2\n\ndata: 3\n\n",
+			events: []event{{"message", "1"}, {"b", "2"}, {"message", "3"}},
+		},
+		{
+			name:   "comments and unknown fields",
+			input:  ":comment\ndata: 1\n: another\nfoo: bar\nData: x\n\n",
+			events: []event{{"message", "1"}},
+		},
+		{
+			name:        "ids",
+			input:       "id: 1\ndata: a\n\nid: 2\x003\ndata: b\n\n",
+			events:      []event{{"message", "a"}, {"message", "b"}},
+			lastEventID: "1",
+		},
+		{
+			name:   "empty id resets the last event ID",
+			input:  "id: 1\ndata: a\n\nid\ndata: b\n\n",
+			events: []event{{"message", "a"}, {"message", "b"}},
+		},
+		{
+			name:   "retry",
+			input:  "retry: 1500\ndata: a\n\nretry: 10a\nretry: -5\nretry:\ndata: b\n\n",
+			events: []event{{"message", "a"}, {"message", "b"}},
+			retry:  1500 * time.Millisecond,
+		},
+		{
+			name:   "incomplete event at the end is discarded",
+			input:  "data: 1\n\ndata: 2\n",
+			events: []event{{"message", "1"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewEventReader(strings.NewReader(tt.input))
+			var events []event
+			for {
+				data, eventType, err := r.ReadEvent()
+				if err == io.EOF {
+					break
+				}
+				if !assert.NoError(t, err) {
+					return
+				}
+				events = append(events, event{eventType, string(data)})
+			}
+			assert.Equal(t, tt.events, events)
+			assert.Equal(t, tt.lastEventID, r.LastEventID())
+			retry, ok := r.Retry()
+			assert.Equal(t, tt.retry, retry)
+			assert.Equal(t, tt.retry != 0, ok)
+		})
+	}
+}
+
+func TestReadEventCRWithoutFollowingData(t *testing.T) {
+	// An event ending with CR is dispatched without waiting for more data.
+	pr, pw := io.Pipe()
+	defer pw.Close()
+	go func() {
+		_, _ = pw.Write([]byte("data: a\r\r"))
+	}()
+	r := NewEventReader(pr)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		data, _, err := r.ReadEvent()
+		assert.NoError(t, err)
+		assert.Equal(t, "a", string(data))
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("event not dispatched")
+	}
+	go func() {
+		_, _ = pw.Write([]byte("\ndata: b\n\n"))
+	}()
+	data, _, err := r.ReadEvent()
+	require.NoError(t, err)
+	assert.Equal(t, "b", string(data), "the LF following the CR is skipped")
+}
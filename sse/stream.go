@@ -0,0 +1,176 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package sse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+)
+
+// DefaultRetry is the reconnection time of a Stream until the server advises
+// one with a retry field.
+const DefaultRetry = 3 * time.Second
+
+// LastEventIDHeader is the header in which clients reconnecting to an event
+// stream send the ID of the last event they received.
+const LastEventIDHeader = "Last-Event-ID"
+
+// LastEventID returns the ID of the last event received by a client
+// reconnecting with r, or an empty string for a new stream. Servers resume the
+// stream after that event.
+func LastEventID(r *http.Request) string {
+	return r.Header.Get(LastEventIDHeader)
+}
+
+// ConnectFunc opens an event stream, resumed after lastEventID unless it is
+// empty. Network errors, as net.Error, make the Stream connect again after the
+// reconnection time; the other errors it returns end the Stream, and io.EOF,
+// e.g. when the server answers 204 No Content, ends it cleanly.
+type ConnectFunc func(ctx context.Context, lastEventID string) (io.ReadCloser, error)
+
+// ConnectHTTP returns a ConnectFunc sending the requests created by
+// newRequest with client, or http.DefaultClient if nil, with the Last-Event-ID
+// header. Responses other than 200 with an event stream fail the connection,
+// except 204 which ends the stream.
+func ConnectHTTP(
+	client *http.Client,
+	newRequest func(ctx context.Context) (*http.Request, error),
+) ConnectFunc {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return func(ctx context.Context, lastEventID string) (io.ReadCloser, error) {
+		req, err := newRequest(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create SSE request: %w", err)
+		}
+		req.Header.Set("Accept", ContentType)
+		req.Header.Set("Cache-Control", "no-cache")
+		if lastEventID != "" {
+			req.Header.Set(LastEventIDHeader, lastEventID)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to SSE stream: %w", err)
+		}
+		if resp.StatusCode == http.StatusNoContent {
+			resp.Body.Close()
+			return nil, io.EOF
+		}
+		mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+		if resp.StatusCode != http.StatusOK || mediaType != ContentType {
+			resp.Body.Close()
+			return nil, fmt.Errorf("SSE stream failed with status %d and Content-Type %q",
+				resp.StatusCode, resp.Header.Get("Content-Type"))
+		}
+		return resp.Body, nil
+	}
+}
+
+// Stream reads the events of an event stream, reconnecting like a browser
+// EventSource when the stream ends, breaks or cannot be reached: after the
+// reconnection time, the stream is opened again, resumed after the last event
+// ID.
+// A Stream is not safe for concurrent use.
+type Stream struct {
+	connect     ConnectFunc
+	retry       time.Duration
+	body        io.ReadCloser
+	reader      *EventReader
+	lastEventID string
+}
+
+// NewStream creates a Stream opened with connect. A non-positive retry
+// selects DefaultRetry as the reconnection time until the server advises one.
+// The stream is opened by the first call to Next.
+func NewStream(connect ConnectFunc, retry time.Duration) *Stream {
+	if retry <= 0 {
+		retry = DefaultRetry
+	}
+	return &Stream{connect: connect, retry: retry}
+}
+
+// Next returns the next event of the stream, reconnecting as needed. It
+// returns the error of ctx, or the error other than a network error with which
+// connect failed.
+func (s *Stream) Next(ctx context.Context) (Event, error) {
+	for {
+		event, err := s.next(ctx)
+		if err == nil {
+			return event, nil
+		}
+		if ctx.Err() != nil {
+			return Event{}, ctx.Err()
+		}
+		var netErr net.Error
+		if s.reader == nil && !errors.As(err, &netErr) {
+			return Event{}, err
+		}
+		// The ID of an event cut off by the end of the stream is not kept.
+		s.Close()
+		log.Debugf("SSE stream ended (%v), reconnecting in %v", err, s.retry)
+		timer := time.NewTimer(s.retry)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return Event{}, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// next connects if needed and reads the next event. The reader is nil if the
+// connection failed.
+func (s *Stream) next(ctx context.Context) (Event, error) {
+	if s.reader == nil {
+		body, err := s.connect(ctx, s.lastEventID)
+		if err != nil {
+			return Event{}, err
+		}
+		s.body, s.reader = body, NewEventReader(body)
+		// The last event ID carries over to the new connection.
+		s.reader.lastEventID = s.lastEventID
+	}
+	event, err := s.reader.Next()
+	if retry, ok := s.reader.Retry(); ok {
+		s.retry = retry
+	}
+	if err != nil {
+		return Event{}, err
+	}
+	s.lastEventID = event.ID
+	return event, nil
+}
+
+// LastEventID returns the ID of the last event read, with which the stream is
+// resumed when reconnecting.
+func (s *Stream) LastEventID() string {
+	return s.lastEventID
+}
+
+// Retry returns the current reconnection time.
+func (s *Stream) Retry() time.Duration {
+	return s.retry
+}
+
+// Close closes the current connection of the stream, if any.
+func (s *Stream) Close() error {
+	if s.body == nil {
+		return nil
+	}
+	err := s.body.Close()
+	s.body, s.reader = nil, nil
+	return err
+}
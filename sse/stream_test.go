@@ -0,0 +1,112 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package sse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamReconnects(t *testing.T) {
+	var mu sync.Mutex
+	var lastEventIDs []string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, ContentType, r.Header.Get("Accept"))
+		mu.Lock()
+		lastEventIDs = append(lastEventIDs, LastEventID(r))
+		connection := len(lastEventIDs)
+		mu.Unlock()
+		w := NewWriter(rw, nil, 0)
+		switch connection {
+		case 1:
+			require.NoError(t, w.Open())
+			require.NoError(t, w.Send(Event{ID: "1", Data: []byte("a"), Retry: 10 * time.Millisecond}))
+			// The connection ends with an incomplete event, which is discarded.
+			_, _ = rw.Write([]byte("id: 2\ndata: lost\n"))
+		case 2:
+			require.NoError(t, w.Open())
+			require.NoError(t, w.Send(Event{Type: "update", Data: []byte("b")}))
+		default:
+			rw.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	stream := NewStream(ConnectHTTP(nil, func(ctx context.Context) (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	}), time.Minute)
+	defer stream.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	event, err := stream.Next(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, Event{ID: "1", Type: "message", Data: []byte("a")}, event)
+	event, err = stream.Next(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, Event{ID: "1", Type: "update", Data: []byte("b")}, event,
+		"the last event ID carries over to the new connection")
+	assert.Equal(t, 10*time.Millisecond, stream.Retry())
+	_, err = stream.Next(ctx)
+	assert.Equal(t, io.EOF, err, "204 No Content ends the stream")
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"", "1", "1"}, lastEventIDs)
+}
+
+func TestStreamConnectionFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "not a stream", http.StatusOK)
+	}))
+	defer server.Close()
+	stream := NewStream(ConnectHTTP(server.Client(), func(ctx context.Context) (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	}), 0)
+	assert.Equal(t, DefaultRetry, stream.Retry())
+	_, err := stream.Next(context.Background())
+	assert.ErrorContains(t, err, "text/plain")
+}
+
+func TestStreamNetworkError(t *testing.T) {
+	var lastEventIDs []string
+	stream := NewStream(func(ctx context.Context, lastEventID string) (io.ReadCloser, error) {
+		lastEventIDs = append(lastEventIDs, lastEventID)
+		if len(lastEventIDs) == 2 {
+			return nil, &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+		}
+		return io.NopCloser(strings.NewReader(fmt.Sprintf("id: %d\ndata: x\n\n", len(lastEventIDs)))), nil
+	}, time.Millisecond)
+	for _, id := range []string{"1", "3"} {
+		event, err := stream.Next(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, id, event.ID)
+	}
+	assert.Equal(t, []string{"", "1", "1"}, lastEventIDs,
+		"network errors are retried with the last event ID")
+}
+
+func TestStreamContextCanceled(t *testing.T) {
+	stream := NewStream(func(ctx context.Context, lastEventID string) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader("")), nil
+	}, time.Hour)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := stream.Next(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "waiting to reconnect ends with the context")
+}
@@ -0,0 +1,188 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package sse
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/codec"
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+)
+
+// maxPooledBufferSize is the largest buffer returned to the pool, so that a
+// single huge event does not pin memory for the lifetime of the process.
+const maxPooledBufferSize = 64 << 10
+
+// bufferPool holds buffers used to assemble SSE frames.
+var bufferPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+// getBuffer returns an empty buffer from the pool.
+func getBuffer() *bytes.Buffer {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+// putBuffer returns buf to the pool unless it grew too large.
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() <= maxPooledBufferSize {
+		bufferPool.Put(buf)
+	}
+}
+
+// writeFrame writes "event: <eventType>\ndata: <data>\n\n" to w with a single Write call.
+func writeFrame(w io.Writer, eventType string, data []byte) error {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	appendFrame(buf, eventType, data)
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// appendFrame appends a complete SSE frame to buf.
+func appendFrame(buf *bytes.Buffer, eventType string, data []byte) {
+	buf.Grow(len("event: \ndata: \n\n") + len(eventType) + len(data))
+	buf.WriteString("event: ")
+	buf.WriteString(eventType)
+	buf.WriteString("\ndata: ")
+	buf.Write(data)
+	buf.WriteString("\n\n")
+}
+
+// Writer writes SSE events to an HTTP response. Each event is assembled in a
+// pooled buffer and sent with a single write followed by a single flush.
+// A Writer is not safe for concurrent use.
+type Writer struct {
+	w            http.ResponseWriter
+	rc           *http.ResponseController
+	codec        codec.Codec
+	writeTimeout time.Duration
+}
+
+// NewWriter creates a Writer for w. Payload-independent values such as request
+// IDs are marshaled with c. If writeTimeout is positive, a write deadline of
+// writeTimeout is set before every event, replacing any server-wide write timeout
+// for the remainder of the stream.
+func NewWriter(w http.ResponseWriter, c codec.Codec, writeTimeout time.Duration) *Writer {
+	return &Writer{
+		w:            w,
+		rc:           http.NewResponseController(w),
+		codec:        codec.OrDefault(c),
+		writeTimeout: writeTimeout,
+	}
+}
+
+// Open starts the event stream: it sets the headers of SetHeaders, unless a
+// content type was already set, writes the status 200 and flushes, so that the
+// client sees the stream open before the first event.
+func (w *Writer) Open() error {
+	if w.w.Header().Get("Content-Type") == "" {
+		SetHeaders(w.w.Header())
+	}
+	w.w.WriteHeader(http.StatusOK)
+	if err := w.rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return fmt.Errorf("failed to flush SSE headers: %w", err)
+	}
+	return nil
+}
+
+// Send writes event and flushes it.
+func (w *Writer) Send(event Event) error {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	if err := appendEvent(buf, event); err != nil {
+		return err
+	}
+	return w.send(buf.Bytes())
+}
+
+// WriteComment writes a comment line, which readers ignore. Comments keep idle
+// streams from being closed by proxies. The lines of comment are written as
+// separate comments.
+func (w *Writer) WriteComment(comment string) error {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	for _, line := range strings.FieldsFunc(comment, func(r rune) bool { return r == '\r' || r == '\n' }) {
+		buf.WriteString(": ")
+		buf.WriteString(line)
+		buf.WriteByte('\n')
+	}
+	if buf.Len() == 0 {
+		buf.WriteString(":\n")
+	}
+	return w.send(buf.Bytes())
+}
+
+// WriteEvent writes an event whose data is the already encoded payload.
+func (w *Writer) WriteEvent(eventType string, data []byte) error {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	appendFrame(buf, eventType, data)
+	return w.send(buf.Bytes())
+}
+
+// WriteJSONRPCEvent writes an event whose data is a JSON-RPC response with the
+// given id and the already encoded result. The envelope is assembled around
+// result without re-marshaling it, so one encoding can be shared by many streams.
+func (w *Writer) WriteJSONRPCEvent(eventType string, id interface{}, result []byte) error {
+	return w.WriteJSONRPCEventWithID("", eventType, id, result)
+}
+
+// WriteJSONRPCEventWithID writes a JSON-RPC event like WriteJSONRPCEvent,
+// preceded by an SSE id field holding eventID unless it is empty.
+func (w *Writer) WriteJSONRPCEventWithID(eventID, eventType string, id interface{}, result []byte) error {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	if eventID != "" {
+		buf.WriteString("id: ")
+		buf.WriteString(eventID)
+		buf.WriteByte('\n')
+	}
+	buf.WriteString("event: ")
+	buf.WriteString(eventType)
+	buf.WriteString("\ndata: {\"jsonrpc\":\"")
+	buf.WriteString(jsonrpc.Version)
+	buf.WriteByte('"')
+	if id != nil {
+		idData, err := w.codec.Marshal(id)
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON-RPC SSE event id: %w", err)
+		}
+		buf.WriteString(",\"id\":")
+		buf.Write(idData)
+	}
+	buf.WriteString(",\"result\":")
+	buf.Write(result)
+	buf.WriteString("}\n\n")
+	return w.send(buf.Bytes())
+}
+
+// send writes a complete frame and flushes it.
+func (w *Writer) send(frame []byte) error {
+	if w.writeTimeout > 0 {
+		err := w.rc.SetWriteDeadline(time.Now().Add(w.writeTimeout))
+		if err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return fmt.Errorf("failed to set SSE write deadline: %w", err)
+		}
+	}
+	if _, err := w.w.Write(frame); err != nil {
+		return fmt.Errorf("failed to write SSE event: %w", err)
+	}
+	if err := w.rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return fmt.Errorf("failed to flush SSE event: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package sse
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+)
+
+// countingRecorder counts writes and flushes made to a response.
+type countingRecorder struct {
+	*httptest.ResponseRecorder
+	writes, flushes int
+}
+
+func (r *countingRecorder) Write(p []byte) (int, error) {
+	r.writes++
+	return r.ResponseRecorder.Write(p)
+}
+
+func (r *countingRecorder) Flush() {
+	r.flushes++
+	r.ResponseRecorder.Flush()
+}
+
+func TestWriter_WriteJSONRPCEvent(t *testing.T) {
+	rec := &countingRecorder{ResponseRecorder: httptest.NewRecorder()}
+	w := NewWriter(rec, nil, time.Second)
+	require.NoError(t, w.WriteJSONRPCEvent("task_status_update", "req-1", []byte(`{"id":"t1"}`)))
+	assert.Equal(t, 1, rec.writes, "event should be written at once")
+	assert.Equal(t, 1, rec.flushes, "event should be flushed once")
+
+	reader := NewEventReader(bytes.NewReader(rec.Body.Bytes()))
+	data, eventType, err := reader.ReadEvent()
+	require.NoError(t, err)
+	assert.Equal(t, "task_status_update", eventType)
+	var resp jsonrpc.RawResponse
+	require.NoError(t, json.Unmarshal(data, &resp))
+	assert.Equal(t, jsonrpc.Version, resp.JSONRPC)
+	assert.Equal(t, "req-1", resp.ID)
+	assert.JSONEq(t, `{"id":"t1"}`, string(resp.Result))
+
+	// The spliced envelope matches what the marshaled envelope would produce.
+	var expected bytes.Buffer
+	require.NoError(t, FormatJSONRPCEvent(&expected, "close", nil, json.RawMessage(`{"id":"t1"}`)))
+	rec.Body.Reset()
+	require.NoError(t, w.WriteJSONRPCEvent("close", nil, []byte(`{"id":"t1"}`)))
+	assert.Equal(t, expected.String(), rec.Body.String())
+}
+
+func TestWriter_WriteJSONRPCEventWithID(t *testing.T) {
+	rec := httptest.NewRecorder()
+	w := NewWriter(rec, nil, 0)
+	require.NoError(t, w.WriteJSONRPCEventWithID("1", "task_status_update", "req-1", []byte(`{}`)))
+	require.NoError(t, w.WriteJSONRPCEventWithID("2", "task_status_update", "req-1", []byte(`{}`)))
+
+	reader := NewEventReader(bytes.NewReader(rec.Body.Bytes()))
+	for _, id := range []string{"1", "2"} {
+		_, _, err := reader.ReadEvent()
+		require.NoError(t, err)
+		assert.Equal(t, id, reader.LastEventID())
+	}
+}
+
+func TestWriter_WriteEvent(t *testing.T) {
+	rec := httptest.NewRecorder()
+	require.NoError(t, NewWriter(rec, nil, 0).WriteEvent("message", []byte(`"hi"`)))
+	assert.Equal(t, "event: message\ndata: \"hi\"\n\n", rec.Body.String())
+}
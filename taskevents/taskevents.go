@@ -0,0 +1,360 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+// Package taskevents publishes the lifecycle events of tasks, and the
+// metadata of their artifacts, to Kafka topics for analytics pipelines. A
+// Publisher follows the changes of a task manager implementing
+// taskmanager.TaskWatcher, such as the MemoryTaskManager:
+//
+//	publisher, err := taskevents.NewPublisher(taskevents.Config{
+//		Producer: audit.KafkaProducerFunc(func(ctx context.Context, topic string, key, value []byte) error {
+//			return writer.WriteMessages(ctx, kafka.Message{Topic: topic, Key: key, Value: value})
+//		}),
+//		Topic: "agent-tasks",
+//	})
+//	go publisher.Run(ctx, tm)
+//
+// Events are keyed by task ID, so the events of a task stay ordered within a
+// partition. Only the metadata of artifacts is published, not their content.
+package taskevents
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/audit"
+	"trpc.group/trpc-go/trpc-a2a-go/codec"
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// EventType is the kind of a task event.
+type EventType string
+
+// EventType constants define the events published.
+const (
+	// EventCreated is published when a task is created.
+	EventCreated EventType = "task.created"
+	// EventUpdated is published when a task changes without reaching a final
+	// state.
+	EventUpdated EventType = "task.updated"
+	// EventCompleted is published when a task reaches a final state, whose
+	// State tells whether it completed, failed or was canceled.
+	EventCompleted EventType = "task.completed"
+	// EventDeleted is published when a task is deleted.
+	EventDeleted EventType = "task.deleted"
+	// EventArtifact is published for each artifact, or artifact chunk, added
+	// to a task.
+	EventArtifact EventType = "task.artifact"
+)
+
+// Event is a task event.
+type Event struct {
+	// Type is the kind of event.
+	Type EventType `json:"type"`
+	// TaskID is the ID of the task.
+	TaskID string `json:"taskId"`
+	// SessionID is the session of the task, if any.
+	SessionID string `json:"sessionId,omitempty"`
+	// State is the state of the task, empty for deletions.
+	State protocol.TaskState `json:"state,omitempty"`
+	// Version is the version of the task, see protocol.Task.Version.
+	Version uint64 `json:"version,omitempty"`
+	// Revision is the revision of the change of the task store.
+	Revision uint64 `json:"revision"`
+	// Time is when the change was made.
+	Time time.Time `json:"time"`
+	// Artifacts is the number of artifact chunks of the task.
+	Artifacts int `json:"artifacts,omitempty"`
+	// Usage is the token usage of the task, if accounted.
+	Usage *protocol.TokenUsage `json:"usage,omitempty"`
+	// Error is the error of a failed or canceled task.
+	Error *protocol.TaskError `json:"error,omitempty"`
+	// Artifact describes the artifact of EventArtifact events.
+	Artifact *ArtifactInfo `json:"artifact,omitempty"`
+}
+
+// ArtifactInfo is the metadata of an artifact chunk.
+type ArtifactInfo struct {
+	// Index is the index of the artifact.
+	Index int `json:"index"`
+	// Name is the name of the artifact, if any.
+	Name string `json:"name,omitempty"`
+	// Description is the description of the artifact, if any.
+	Description string `json:"description,omitempty"`
+	// Append reports whether the chunk continues the artifact.
+	Append bool `json:"append,omitempty"`
+	// LastChunk reports whether the chunk ends the artifact.
+	LastChunk bool `json:"lastChunk,omitempty"`
+	// Parts is the number of parts of the chunk.
+	Parts int `json:"parts"`
+	// Bytes is the size of the content of the chunk.
+	Bytes int64 `json:"bytes"`
+	// MimeTypes are the media types of the parts, without duplicates.
+	MimeTypes []string `json:"mimeTypes,omitempty"`
+}
+
+// NewArtifactInfo returns the metadata of artifact.
+func NewArtifactInfo(artifact protocol.Artifact) ArtifactInfo {
+	info := ArtifactInfo{
+		Index:     artifact.Index,
+		Append:    artifact.Append != nil && *artifact.Append,
+		LastChunk: artifact.LastChunk != nil && *artifact.LastChunk,
+		Parts:     len(artifact.Parts),
+		Bytes:     taskmanager.ArtifactSize(artifact),
+	}
+	if artifact.Name != nil {
+		info.Name = *artifact.Name
+	}
+	if artifact.Description != nil {
+		info.Description = *artifact.Description
+	}
+	seen := map[string]bool{}
+	for _, part := range artifact.Parts {
+		if mimeType := partMimeType(part); mimeType != "" && !seen[mimeType] {
+			seen[mimeType] = true
+			info.MimeTypes = append(info.MimeTypes, mimeType)
+		}
+	}
+	return info
+}
+
+// partMimeType returns the media type of part, or "" if unknown.
+func partMimeType(part protocol.Part) string {
+	switch p := part.(type) {
+	case protocol.TextPart, *protocol.TextPart:
+		return "text/plain"
+	case protocol.DataPart, *protocol.DataPart:
+		return "application/json"
+	case protocol.FilePart:
+		return fileMimeType(p.File)
+	case *protocol.FilePart:
+		return fileMimeType(p.File)
+	}
+	return ""
+}
+
+// fileMimeType returns the media type of file, or "" if unknown.
+func fileMimeType(file protocol.FileContent) string {
+	if file.MimeType == nil {
+		return ""
+	}
+	return *file.MimeType
+}
+
+// Serializer encodes the events published.
+type Serializer interface {
+	// Serialize returns the message value of event.
+	Serialize(event Event) ([]byte, error)
+}
+
+// SerializerFunc is an adapter to allow the use of ordinary functions as Serializer.
+type SerializerFunc func(event Event) ([]byte, error)
+
+// Serialize implements Serializer.
+func (f SerializerFunc) Serialize(event Event) ([]byte, error) {
+	return f(event)
+}
+
+// CodecSerializer returns a serializer encoding events as JSON with c.
+func CodecSerializer(c codec.Codec) Serializer {
+	return SerializerFunc(func(event Event) ([]byte, error) {
+		return c.Marshal(event)
+	})
+}
+
+// Config configures a Publisher.
+type Config struct {
+	// Producer publishes the messages. It is owned by the caller.
+	Producer audit.KafkaProducer
+	// Topic is the topic of the events without topic in Topics.
+	Topic string
+	// Topics, if set, maps event types to their topics, e.g. to publish
+	// artifact events apart. Events mapped to "" are not published.
+	Topics map[EventType]string
+	// Serializer encodes the events. Defaults to JSON with codec.Default.
+	Serializer Serializer
+}
+
+// Publisher publishes the changes of tasks as events.
+type Publisher struct {
+	cfg Config
+
+	mu            sync.Mutex // Guards the fields below.
+	tasks         map[string]taskProgress
+	finished      map[string]int // Artifact chunks published by finished task.
+	finishedOrder []string       // IDs of the finished tasks, oldest first.
+}
+
+// maxFinishedTasks is the number of finished tasks a Publisher remembers, so
+// that the changes made to them shortly after they finished, e.g. to their
+// metadata, are not published as their completion again.
+const maxFinishedTasks = 1024
+
+// taskProgress is what a Publisher knows of a task to tell the events of its
+// changes.
+type taskProgress struct {
+	final     bool // The task is in a final state.
+	artifacts int  // Number of artifact chunks published.
+}
+
+// NewPublisher creates a publisher.
+func NewPublisher(cfg Config) (*Publisher, error) {
+	if cfg.Producer == nil {
+		return nil, errors.New("taskevents: producer is required")
+	}
+	if cfg.Topic == "" && len(cfg.Topics) == 0 {
+		return nil, errors.New("taskevents: topic is required")
+	}
+	if cfg.Serializer == nil {
+		cfg.Serializer = CodecSerializer(codec.Default)
+	}
+	return &Publisher{
+		cfg:      cfg,
+		tasks:    make(map[string]taskProgress),
+		finished: make(map[string]int),
+	}, nil
+}
+
+// Run publishes the changes of the tasks of watcher until ctx is done. If it
+// falls behind the changes, the events missed are lost and it watches again.
+// Events failing to be published are logged and dropped.
+func (p *Publisher) Run(ctx context.Context, watcher taskmanager.TaskWatcher) error {
+	for {
+		changes, err := watcher.WatchTasks(ctx)
+		if err != nil {
+			return err
+		}
+		for change := range changes {
+			if err := p.Publish(ctx, change); err != nil {
+				log.Warnf("Failed to publish the events of task %s: %v", change.TaskID, err)
+			}
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		log.Warnf("Task event publisher fell behind, watching the tasks again")
+	}
+}
+
+// Publish publishes the events of change: the event of the task, followed
+// by an event for each artifact chunk added since the last change published.
+// The events are published even if ctx is canceled.
+func (p *Publisher) Publish(ctx context.Context, change taskmanager.TaskChange) error {
+	ctx = context.WithoutCancel(ctx)
+	var errs []error
+	for _, event := range p.events(change) {
+		if err := p.publish(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// events returns the events of change, and records the progress of its task
+// until it finishes.
+func (p *Publisher) events(change taskmanager.TaskChange) []Event {
+	event := Event{TaskID: change.TaskID, Revision: change.Revision, Time: change.Time}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if change.Type == taskmanager.TaskDeleted || change.Task == nil {
+		delete(p.tasks, change.TaskID)
+		delete(p.finished, change.TaskID)
+		event.Type = EventDeleted
+		return []Event{event}
+	}
+	task := change.Task
+	if task.SessionID != nil {
+		event.SessionID = *task.SessionID
+	}
+	event.State, event.Version, event.Error = task.Status.State, task.Version, task.Status.Error
+	event.Artifacts = len(task.Artifacts)
+	if usage, ok := protocol.UsageFromMetadata(task.Metadata); ok {
+		event.Usage = &usage
+	}
+	progress, ok := p.tasks[change.TaskID]
+	if artifacts, finished := p.finished[change.TaskID]; !ok && finished {
+		progress = taskProgress{final: true, artifacts: artifacts}
+	}
+	final := isFinalState(task.Status.State)
+	switch {
+	case change.Type == taskmanager.TaskCreated:
+		event.Type = EventCreated
+	case final && !progress.final:
+		event.Type = EventCompleted
+	default:
+		event.Type = EventUpdated
+	}
+	events := []Event{event}
+	if progress.artifacts > len(task.Artifacts) {
+		progress.artifacts = 0
+	}
+	for _, artifact := range task.Artifacts[progress.artifacts:] {
+		info := NewArtifactInfo(artifact)
+		artifactEvent := event
+		artifactEvent.Type, artifactEvent.Artifact = EventArtifact, &info
+		events = append(events, artifactEvent)
+	}
+	if final {
+		delete(p.tasks, change.TaskID)
+		p.finish(change.TaskID, len(task.Artifacts))
+	} else {
+		delete(p.finished, change.TaskID)
+		p.tasks[change.TaskID] = taskProgress{artifacts: len(task.Artifacts)}
+	}
+	return events
+}
+
+// finish records that taskID finished with the given number of artifact
+// chunks published, forgetting the oldest finished tasks beyond
+// maxFinishedTasks.
+func (p *Publisher) finish(taskID string, artifacts int) {
+	if _, ok := p.finished[taskID]; !ok {
+		p.finishedOrder = append(p.finishedOrder, taskID)
+	}
+	p.finished[taskID] = artifacts
+	for len(p.finished) > maxFinishedTasks && len(p.finishedOrder) > 0 {
+		delete(p.finished, p.finishedOrder[0])
+		p.finishedOrder = p.finishedOrder[1:]
+	}
+	if len(p.finishedOrder) > 2*maxFinishedTasks {
+		order := make([]string, 0, len(p.finished))
+		seen := make(map[string]bool, len(p.finished))
+		for _, id := range p.finishedOrder {
+			if _, ok := p.finished[id]; ok && !seen[id] {
+				seen[id] = true
+				order = append(order, id)
+			}
+		}
+		p.finishedOrder = order
+	}
+}
+
+// publish sends event to its topic.
+func (p *Publisher) publish(ctx context.Context, event Event) error {
+	topic, ok := p.cfg.Topics[event.Type]
+	if !ok {
+		topic = p.cfg.Topic
+	}
+	if topic == "" {
+		return nil
+	}
+	value, err := p.cfg.Serializer.Serialize(event)
+	if err != nil {
+		return err
+	}
+	return p.cfg.Producer.Produce(ctx, topic, []byte(event.TaskID), value)
+}
+
+// isFinalState reports whether state is a terminal state.
+func isFinalState(state protocol.TaskState) bool {
+	return state == protocol.TaskStateCompleted ||
+		state == protocol.TaskStateFailed ||
+		state == protocol.TaskStateCanceled
+}
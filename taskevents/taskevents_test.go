@@ -0,0 +1,157 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskevents
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/audit"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// reportProcessor produces a report artifact and completes the task.
+type reportProcessor struct{}
+
+func (reportProcessor) Process(
+	ctx context.Context,
+	taskID string,
+	message protocol.Message,
+	handle taskmanager.TaskHandle,
+) error {
+	name, lastChunk := "report", true
+	if err := handle.AddArtifact(protocol.Artifact{
+		Name:      &name,
+		Parts:     []protocol.Part{protocol.NewTextPart("all good")},
+		LastChunk: &lastChunk,
+	}); err != nil {
+		return err
+	}
+	return handle.UpdateStatus(protocol.TaskStateCompleted, nil)
+}
+
+// message is a message produced to Kafka.
+type message struct {
+	topic, key string
+	event      Event
+}
+
+func TestPublisher(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		messages []message
+	)
+	producer := audit.KafkaProducerFunc(func(ctx context.Context, topic string, key, value []byte) error {
+		var event Event
+		require.NoError(t, json.Unmarshal(value, &event))
+		mu.Lock()
+		defer mu.Unlock()
+		messages = append(messages, message{topic: topic, key: string(key), event: event})
+		return nil
+	})
+	publisher, err := NewPublisher(Config{
+		Producer: producer,
+		Topic:    "tasks",
+		Topics:   map[EventType]string{EventArtifact: "artifacts", EventUpdated: ""},
+	})
+	require.NoError(t, err)
+	tm, err := taskmanager.NewMemoryTaskManager(reportProcessor{})
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- publisher.Run(ctx, tm) }()
+	// Let the publisher watch the tasks.
+	time.Sleep(20 * time.Millisecond)
+
+	_, err = tm.OnSendTask(context.Background(), protocol.SendTaskParams{
+		ID:      "task-1",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("report")}),
+	})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(messages) == 3
+	}, time.Second, 10*time.Millisecond)
+	cancel()
+	require.NoError(t, <-done)
+
+	// Updates are not published, per the topics.
+	assert.Equal(t, "tasks", messages[0].topic)
+	assert.Equal(t, EventCreated, messages[0].event.Type)
+	assert.Equal(t, protocol.TaskStateSubmitted, messages[0].event.State)
+	assert.Equal(t, "artifacts", messages[1].topic)
+	assert.Equal(t, EventArtifact, messages[1].event.Type)
+	assert.Equal(t, &ArtifactInfo{
+		Name:      "report",
+		LastChunk: true,
+		Parts:     1,
+		Bytes:     8,
+		MimeTypes: []string{"text/plain"},
+	}, messages[1].event.Artifact)
+	assert.Equal(t, "tasks", messages[2].topic)
+	assert.Equal(t, EventCompleted, messages[2].event.Type)
+	assert.Equal(t, protocol.TaskStateCompleted, messages[2].event.State)
+	assert.Equal(t, 1, messages[2].event.Artifacts)
+	for i, message := range messages {
+		assert.Equal(t, "task-1", message.key)
+		if i > 0 {
+			assert.GreaterOrEqual(t, message.event.Version, messages[i-1].event.Version)
+		}
+	}
+
+	// Finished tasks are only remembered to publish their later changes as
+	// updates.
+	publisher.mu.Lock()
+	assert.Empty(t, publisher.tasks)
+	assert.Equal(t, map[string]int{"task-1": 1}, publisher.finished)
+	publisher.mu.Unlock()
+	task, err := tm.OnGetTask(context.Background(), protocol.TaskQueryParams{ID: "task-1"})
+	require.NoError(t, err)
+	events := publisher.events(taskmanager.TaskChange{Type: taskmanager.TaskUpdated, TaskID: "task-1", Task: task})
+	require.Len(t, events, 1)
+	assert.Equal(t, EventUpdated, events[0].Type)
+}
+
+func TestPublisher_Serializer(t *testing.T) {
+	var values []string
+	publisher, err := NewPublisher(Config{
+		Producer: audit.KafkaProducerFunc(func(ctx context.Context, topic string, key, value []byte) error {
+			values = append(values, string(value))
+			return nil
+		}),
+		Topic: "tasks",
+		Serializer: SerializerFunc(func(event Event) ([]byte, error) {
+			return []byte(string(event.Type) + " " + event.TaskID), nil
+		}),
+	})
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	publish := func(changeType taskmanager.TaskChangeType, task *protocol.Task) {
+		require.NoError(t, publisher.Publish(ctx, taskmanager.TaskChange{Type: changeType, TaskID: "task-1", Task: task}))
+	}
+	task := protocol.NewTask("task-1", nil)
+	publish(taskmanager.TaskUpdated, task)
+	task.Status.State = protocol.TaskStateFailed
+	publish(taskmanager.TaskUpdated, task)
+	publish(taskmanager.TaskUpdated, task)
+	publish(taskmanager.TaskDeleted, nil)
+	assert.Equal(t, []string{
+		"task.updated task-1", "task.completed task-1", "task.updated task-1", "task.deleted task-1",
+	}, values)
+
+	_, err = NewPublisher(Config{Topic: "tasks"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,22 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// EventAcknowledger is implemented by task managers that want to learn how far
+// clients have consumed the event streams of their tasks, e.g. to trim the
+// events they keep for resubscribing clients. When event acknowledgements are
+// enabled on the server, each accepted tasks/ackEvents request is passed on.
+type EventAcknowledger interface {
+	// OnAckEvents is called with the outcome of an acknowledgement.
+	OnAckEvents(ctx context.Context, ack protocol.AckEventsResult) error
+}
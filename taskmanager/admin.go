@@ -0,0 +1,153 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/audit"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// TaskFilter selects the tasks returned by TaskLister.ListTasks.
+type TaskFilter struct {
+	// SessionID, if set, selects the tasks of a session.
+	SessionID string
+	// States, if set, selects the tasks in one of the states.
+	States []protocol.TaskState
+	// Limit, if positive, bounds the number of tasks returned.
+	Limit int
+}
+
+// Matches reports whether task is selected by the filter, ignoring Limit.
+func (f TaskFilter) Matches(task *protocol.Task) bool {
+	if f.SessionID != "" && (task.SessionID == nil || *task.SessionID != f.SessionID) {
+		return false
+	}
+	if len(f.States) == 0 {
+		return true
+	}
+	for _, state := range f.States {
+		if task.Status.State == state {
+			return true
+		}
+	}
+	return false
+}
+
+// TaskLister is implemented by task managers that can enumerate their tasks
+// across sessions, as used by the admin API of the server.
+type TaskLister interface {
+	// ListTasks returns the tasks selected by filter, without their history,
+	// in the order of their last status update.
+	ListTasks(ctx context.Context, filter TaskFilter) ([]protocol.Task, error)
+}
+
+// TaskRequeuer is implemented by task managers that can run a failed task
+// again, as used by the admin API of the server.
+type TaskRequeuer interface {
+	// RequeueTask resubmits the last user message of a failed task to the
+	// processor and returns the task once it is submitted again. Tasks that
+	// did not fail are rejected with ErrTaskNotRequeueable.
+	RequeueTask(ctx context.Context, taskID string) (*protocol.Task, error)
+}
+
+// TaskDeleter is implemented by task managers that can delete their tasks,
+// e.g. those created by the self test of the server.
+type TaskDeleter interface {
+	// DeleteTask cancels the processing of the task taskID, if any, ends its
+	// streams and deletes the task with its message history and push
+	// notification configuration. It fails with ErrTaskNotFound if there is
+	// no such task.
+	DeleteTask(ctx context.Context, taskID string) error
+}
+
+// PushDeliveryAttempt is an attempt to deliver a push notification.
+type PushDeliveryAttempt struct {
+	// TaskID is the ID of the task notified.
+	TaskID string `json:"taskId"`
+	// URL is the URL the notification was sent to.
+	URL string `json:"url"`
+	// Time is when the attempt started.
+	Time time.Time `json:"time"`
+	// Duration is how long the attempt took.
+	Duration time.Duration `json:"duration"`
+	// StatusCode is the HTTP status of the response, zero if none was received.
+	StatusCode int `json:"statusCode,omitempty"`
+	// Error describes why the attempt failed, if it did.
+	Error string `json:"error,omitempty"`
+}
+
+// PushDeliveryInspector is implemented by task managers, or the push
+// notification senders they use, that record their delivery attempts.
+type PushDeliveryInspector interface {
+	// PushDeliveryAttempts returns the recorded attempts for taskID, oldest first.
+	PushDeliveryAttempts(ctx context.Context, taskID string) ([]PushDeliveryAttempt, error)
+}
+
+// defaultPushDeliveryLogSize is the number of attempts kept per task by default.
+const defaultPushDeliveryLogSize = 16
+
+// PushDeliveryLog records the latest push delivery attempts of each task for
+// inspection. It implements PushDeliveryInspector and is safe for concurrent use.
+type PushDeliveryLog struct {
+	size     int
+	auditLog *audit.Logger
+
+	mu       sync.Mutex
+	attempts map[string][]PushDeliveryAttempt
+}
+
+// NewPushDeliveryLog creates a log keeping the last size attempts of each
+// task, 16 if size is not positive.
+func NewPushDeliveryLog(size int) *PushDeliveryLog {
+	if size <= 0 {
+		size = defaultPushDeliveryLogSize
+	}
+	return &PushDeliveryLog{size: size, attempts: make(map[string][]PushDeliveryAttempt)}
+}
+
+// SetAuditLog makes the log also record every attempt to logger as an
+// audit.EventPushDelivery. It must be called before the log is used.
+func (l *PushDeliveryLog) SetAuditLog(logger *audit.Logger) {
+	l.auditLog = logger
+}
+
+// Record records an attempt, dropping the oldest attempt of its task if full.
+func (l *PushDeliveryLog) Record(attempt PushDeliveryAttempt) {
+	_ = l.auditLog.Record(context.Background(), audit.Event{
+		Type:       audit.EventPushDelivery,
+		TaskID:     attempt.TaskID,
+		URL:        attempt.URL,
+		StatusCode: attempt.StatusCode,
+		Duration:   attempt.Duration,
+		Error:      attempt.Error,
+	})
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	attempts := append(l.attempts[attempt.TaskID], attempt)
+	if len(attempts) > l.size {
+		attempts = attempts[len(attempts)-l.size:]
+	}
+	l.attempts[attempt.TaskID] = attempts
+}
+
+// Forget drops the attempts recorded for taskID.
+func (l *PushDeliveryLog) Forget(taskID string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.attempts, taskID)
+}
+
+// PushDeliveryAttempts implements PushDeliveryInspector.
+func (l *PushDeliveryLog) PushDeliveryAttempts(ctx context.Context, taskID string) ([]PushDeliveryAttempt, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]PushDeliveryAttempt(nil), l.attempts[taskID]...), nil
+}
@@ -0,0 +1,207 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"encoding/base64"
+	"errors"
+	"sync"
+	"unicode/utf8"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// ErrArtifactWriterClosed is returned by writes to a closed ArtifactWriter.
+var ErrArtifactWriterClosed = errors.New("artifact writer is closed")
+
+// ArtifactWriter streams the bytes written to it as the chunks of an artifact.
+// Every chunk is sent through TaskHandle.AddArtifact as an artifact with the
+// index of the writer; all chunks but the first have Append set and the chunk
+// sent by Close has LastChunk set. Text is never split inside a UTF-8 sequence.
+//
+//	w := taskmanager.NewArtifactWriter(handle, 0, taskmanager.WithArtifactName("answer"))
+//	for token := range tokens {
+//		if _, err := io.WriteString(w, token); err != nil {
+//			return err
+//		}
+//	}
+//	return w.Close()
+//
+// It is safe for concurrent use.
+type ArtifactWriter struct {
+	handle      TaskHandle
+	index       int
+	name        *string
+	description *string
+	mimeType    *string
+	chunkSize   int
+
+	mu     sync.Mutex
+	buf    []byte
+	chunks int
+	closed bool
+}
+
+// ArtifactWriterOption configures an ArtifactWriter.
+type ArtifactWriterOption func(*ArtifactWriter)
+
+// WithArtifactName sets the name of the artifact.
+func WithArtifactName(name string) ArtifactWriterOption {
+	return func(w *ArtifactWriter) {
+		w.name = &name
+	}
+}
+
+// WithArtifactDescription sets the description of the artifact.
+func WithArtifactDescription(description string) ArtifactWriterOption {
+	return func(w *ArtifactWriter) {
+		w.description = &description
+	}
+}
+
+// WithChunkSize buffers written bytes and sends them in chunks of size bytes,
+// the remainder being sent by Flush or Close. By default every Write is sent
+// as a chunk as is, which suits streaming LLM tokens.
+func WithChunkSize(size int) ArtifactWriterOption {
+	return func(w *ArtifactWriter) {
+		w.chunkSize = size
+	}
+}
+
+// WithFileContent sends the bytes as base64-encoded file parts of the given
+// MIME type instead of text parts, for binary content.
+func WithFileContent(mimeType string) ArtifactWriterOption {
+	return func(w *ArtifactWriter) {
+		w.mimeType = &mimeType
+	}
+}
+
+// NewArtifactWriter creates a writer streaming the artifact at index of the
+// task of handle. Give every artifact of a task its own index.
+func NewArtifactWriter(handle TaskHandle, index int, opts ...ArtifactWriterOption) *ArtifactWriter {
+	w := &ArtifactWriter{handle: handle, index: index}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+// Write implements io.Writer. The bytes are buffered even if sending a chunk
+// fails, so a failed Write returns len(p) with the error.
+func (w *ArtifactWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, ErrArtifactWriterClosed
+	}
+	w.buf = append(w.buf, p...)
+	if w.chunkSize <= 0 {
+		return len(p), w.send(w.complete(len(w.buf)), false)
+	}
+	for len(w.buf) >= w.chunkSize {
+		n := w.complete(w.chunkSize)
+		if n == 0 {
+			// A UTF-8 sequence longer than the chunk size: send it whole.
+			n = w.complete(len(w.buf))
+		}
+		if err := w.send(n, false); err != nil {
+			return len(p), err
+		}
+	}
+	return len(p), nil
+}
+
+// WriteString implements io.StringWriter.
+func (w *ArtifactWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush sends the buffered bytes as a chunk, if any.
+func (w *ArtifactWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return ErrArtifactWriterClosed
+	}
+	return w.send(w.complete(len(w.buf)), false)
+}
+
+// Close sends the buffered bytes as the last chunk of the artifact. If nothing
+// is buffered, the last chunk has no parts. Closing again does nothing.
+func (w *ArtifactWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	return w.send(len(w.buf), true)
+}
+
+// complete returns the length of the longest prefix of the first n buffered
+// bytes that does not end inside a UTF-8 sequence. File content is not text,
+// so any prefix is complete.
+func (w *ArtifactWriter) complete(n int) int {
+	if w.mimeType != nil {
+		return n
+	}
+	for i := n - 1; i >= 0 && i >= n-utf8.UTFMax; i-- {
+		if utf8.RuneStart(w.buf[i]) {
+			if !utf8.FullRune(w.buf[i:n]) {
+				return i
+			}
+			return n
+		}
+	}
+	return n
+}
+
+// send sends the first n buffered bytes as a chunk. Empty chunks are only
+// sent as the last one. The caller must hold mu.
+func (w *ArtifactWriter) send(n int, last bool) error {
+	if n == 0 && !last {
+		return nil
+	}
+	artifact := protocol.Artifact{
+		Name:        w.name,
+		Description: w.description,
+		Parts:       []protocol.Part{},
+		Index:       w.index,
+	}
+	if n > 0 {
+		artifact.Parts = append(artifact.Parts, w.part(w.buf[:n]))
+	}
+	if w.chunks > 0 {
+		artifact.Append = boolPtr(true)
+	}
+	if last {
+		artifact.LastChunk = boolPtr(true)
+	}
+	if err := w.handle.AddArtifact(artifact); err != nil {
+		return err
+	}
+	w.chunks++
+	w.buf = w.buf[:copy(w.buf, w.buf[n:])]
+	return nil
+}
+
+// part returns the part carrying data.
+func (w *ArtifactWriter) part(data []byte) protocol.Part {
+	if w.mimeType == nil {
+		return protocol.NewTextPart(string(data))
+	}
+	encoded := base64.StdEncoding.EncodeToString(data)
+	return protocol.FilePart{
+		Type: protocol.PartTypeFile,
+		File: protocol.FileContent{MimeType: w.mimeType, Bytes: &encoded},
+	}
+}
+
+// boolPtr returns a pointer to b.
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -0,0 +1,130 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"encoding/base64"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// artifactRecorder is a TaskHandle recording the artifacts added.
+type artifactRecorder struct {
+	artifacts []protocol.Artifact
+	err       error
+}
+
+func (r *artifactRecorder) UpdateStatus(protocol.TaskState, *protocol.Message) error { return nil }
+
+func (r *artifactRecorder) AddArtifact(artifact protocol.Artifact) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.artifacts = append(r.artifacts, artifact)
+	return nil
+}
+
+func (r *artifactRecorder) IsStreamingRequest() bool { return true }
+
+// texts returns the text of each recorded chunk.
+func (r *artifactRecorder) texts() []string {
+	var texts []string
+	for _, artifact := range r.artifacts {
+		text := ""
+		for _, part := range artifact.Parts {
+			text += part.(protocol.TextPart).Text
+		}
+		texts = append(texts, text)
+	}
+	return texts
+}
+
+func TestArtifactWriter_Tokens(t *testing.T) {
+	handle := &artifactRecorder{}
+	w := NewArtifactWriter(handle, 2, WithArtifactName("answer"))
+	for _, token := range []string{"Hello", ", ", "", "world"} {
+		_, err := io.WriteString(w, token)
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	require.NoError(t, w.Close())
+	_, err := w.Write([]byte("late"))
+	assert.ErrorIs(t, err, ErrArtifactWriterClosed)
+
+	assert.Equal(t, []string{"Hello", ", ", "world", ""}, handle.texts())
+	for i, artifact := range handle.artifacts {
+		assert.Equal(t, 2, artifact.Index)
+		assert.Equal(t, "answer", *artifact.Name)
+		assert.Equal(t, i > 0, artifact.Append != nil && *artifact.Append, i)
+		assert.Equal(t, i == 3, artifact.LastChunk != nil && *artifact.LastChunk, i)
+	}
+}
+
+func TestArtifactWriter_ChunkSize(t *testing.T) {
+	handle := &artifactRecorder{}
+	w := NewArtifactWriter(handle, 0, WithChunkSize(4))
+	_, err := io.Copy(w, strings.NewReader("héllo wörld"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	texts := handle.texts()
+	assert.Equal(t, "héllo wörld", strings.Join(texts, ""))
+	for _, text := range texts[:len(texts)-1] {
+		assert.LessOrEqual(t, len(text), 4)
+		assert.NotEmpty(t, text)
+	}
+	last := handle.artifacts[len(handle.artifacts)-1]
+	assert.True(t, *last.LastChunk)
+}
+
+func TestArtifactWriter_SplitRune(t *testing.T) {
+	handle := &artifactRecorder{}
+	w := NewArtifactWriter(handle, 0)
+	euro := []byte("€")
+	_, err := w.Write(euro[:1])
+	require.NoError(t, err)
+	assert.Empty(t, handle.artifacts)
+	_, err = w.Write(euro[1:])
+	require.NoError(t, err)
+	require.NoError(t, w.Flush())
+	require.NoError(t, w.Close())
+	assert.Equal(t, []string{"€", ""}, handle.texts())
+}
+
+func TestArtifactWriter_File(t *testing.T) {
+	handle := &artifactRecorder{}
+	w := NewArtifactWriter(handle, 1, WithFileContent("application/octet-stream"), WithChunkSize(3))
+	_, err := w.Write([]byte{0, 1, 2, 3, 4})
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	require.Len(t, handle.artifacts, 2)
+	var content []byte
+	for _, artifact := range handle.artifacts {
+		part := artifact.Parts[0].(protocol.FilePart)
+		assert.Equal(t, "application/octet-stream", *part.File.MimeType)
+		data, err := base64.StdEncoding.DecodeString(*part.File.Bytes)
+		require.NoError(t, err)
+		content = append(content, data...)
+	}
+	assert.Equal(t, []byte{0, 1, 2, 3, 4}, content)
+}
+
+func TestArtifactWriter_Error(t *testing.T) {
+	handle := &artifactRecorder{err: errors.New("task not found")}
+	w := NewArtifactWriter(handle, 0)
+	_, err := w.Write([]byte("text"))
+	assert.Error(t, err)
+	assert.Error(t, w.Close())
+}
@@ -0,0 +1,38 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package bolt
+
+import "time"
+
+// Option is a function that configures the TaskStore.
+type Option func(*TaskStore)
+
+// WithTTL deletes the tasks not updated for ttl, except those submitted or
+// working. Tasks are kept forever by default.
+func WithTTL(ttl time.Duration) Option {
+	return func(s *TaskStore) {
+		s.ttl = ttl
+	}
+}
+
+// WithCleanupInterval sets how often the tasks expired according to WithTTL
+// are deleted. Default 1 minute.
+func WithCleanupInterval(interval time.Duration) Option {
+	return func(s *TaskStore) {
+		if interval > 0 {
+			s.cleanupInterval = interval
+		}
+	}
+}
+
+// WithCompactInterval compacts the database every interval, see
+// TaskStore.Compact. The database is not compacted by default.
+func WithCompactInterval(interval time.Duration) Option {
+	return func(s *TaskStore) {
+		s.compactInterval = interval
+	}
+}
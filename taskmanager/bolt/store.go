@@ -0,0 +1,394 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+// Package bolt provides a TaskStore keeping the tasks of a
+// taskmanager.MemoryTaskManager in an embedded bbolt database, for
+// single-binary deployments that must not lose their tasks on restart.
+package bolt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go.etcd.io/bbolt"
+
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+const (
+	// tasksBucket is the bucket of the tasks, keyed by task ID.
+	tasksBucket = "tasks"
+	// compactSuffix is the suffix of the file the database is compacted into.
+	compactSuffix = ".compact"
+	// compactTxSize bounds the size of the transactions copying the database
+	// when it is compacted.
+	compactTxSize = 64 << 20
+	// defaultCleanupInterval is how often expired tasks are deleted.
+	defaultCleanupInterval = time.Minute
+	// openTimeout is how long opening waits for the lock of the file, held by
+	// another process using the database.
+	openTimeout = time.Second
+)
+
+// record is a task stored in the database.
+type record struct {
+	// Task is the task.
+	Task protocol.Task `json:"task"`
+	// UpdatedAt is when the task was last stored.
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TaskStore is a taskmanager.TaskStore keeping the tasks in a bbolt database
+// file. Each change of a task is committed to the file before it is reported,
+// so tasks survive crashes. The tasks that were submitted or working when the
+// process stopped are kept as they are, for the task manager to recover them
+// with taskmanager.WithTaskRecovery.
+// It is safe for concurrent use, but the file can only be used by one process
+// at a time.
+type TaskStore struct {
+	// path is the path of the database file.
+	path string
+	// ttl is how long tasks are kept after their last update, if positive.
+	ttl time.Duration
+	// cleanupInterval is how often expired tasks are deleted.
+	cleanupInterval time.Duration
+	// compactInterval is how often the database is compacted, if positive.
+	compactInterval time.Duration
+
+	// mu guards db and dbErr, replaced when the database is compacted.
+	mu sync.RWMutex
+	// db is the database, nil if it could not be reopened after compaction.
+	db *bbolt.DB
+	// dbErr is why db could not be reopened.
+	dbErr error
+
+	// stop stops the maintenance goroutine.
+	stop chan struct{}
+	// done is closed once the maintenance goroutine returns.
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewTaskStore opens the database at path, creating it if needed.
+func NewTaskStore(path string, opts ...Option) (*TaskStore, error) {
+	s := &TaskStore{
+		path:            path,
+		cleanupInterval: defaultCleanupInterval,
+		stop:            make(chan struct{}),
+		done:            make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	// A leftover compaction file is an incomplete copy: the database is intact.
+	if err := os.Remove(path + compactSuffix); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to remove incomplete compaction: %w", err)
+	}
+	db, err := open(path)
+	if err != nil {
+		return nil, err
+	}
+	s.db = db
+	go s.maintain()
+	return s, nil
+}
+
+// open opens the database at path and creates its buckets.
+func open(path string) (*bbolt.DB, error) {
+	db, err := bbolt.Open(path, 0o600, &bbolt.Options{Timeout: openTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open task database: %w", err)
+	}
+	if err := db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(tasksBucket))
+		return err
+	}); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create task bucket: %w", err)
+	}
+	return db, nil
+}
+
+// Get implements taskmanager.TaskStore.
+func (s *TaskStore) Get(ctx context.Context, taskID string) (*protocol.Task, error) {
+	var task *protocol.Task
+	err := s.view(func(b *bbolt.Bucket) error {
+		rec, err := s.get(b, taskID)
+		if err != nil {
+			return err
+		}
+		task = &rec.Task
+		return nil
+	})
+	return task, err
+}
+
+// Put implements taskmanager.TaskStore.
+func (s *TaskStore) Put(ctx context.Context, task *protocol.Task) error {
+	return s.update(func(b *bbolt.Bucket) error {
+		return put(b, task)
+	})
+}
+
+// Create implements taskmanager.TaskCreator. Expired tasks are replaced.
+func (s *TaskStore) Create(ctx context.Context, task *protocol.Task) error {
+	return s.update(func(b *bbolt.Bucket) error {
+		_, err := s.get(b, task.ID)
+		switch {
+		case err == nil:
+			return taskmanager.ErrTaskExists(task.ID)
+		case !taskmanager.IsTaskNotFound(err):
+			return err
+		}
+		return put(b, task)
+	})
+}
+
+// List implements taskmanager.TaskStore.
+func (s *TaskStore) List(ctx context.Context, filter taskmanager.TaskFilter) ([]protocol.Task, error) {
+	var tasks []protocol.Task
+	err := s.view(func(b *bbolt.Bucket) error {
+		return b.ForEach(func(k, v []byte) error {
+			var rec record
+			if err := json.Unmarshal(v, &rec); err != nil {
+				return fmt.Errorf("failed to decode task %s: %w", k, err)
+			}
+			if !s.expired(rec) && filter.Matches(&rec.Task) {
+				tasks = append(tasks, rec.Task)
+			}
+			return nil
+		})
+	})
+	return tasks, err
+}
+
+// Delete implements taskmanager.TaskStore.
+func (s *TaskStore) Delete(ctx context.Context, taskID string) error {
+	return s.update(func(b *bbolt.Bucket) error {
+		return b.Delete([]byte(taskID))
+	})
+}
+
+// Update implements taskmanager.TaskStore. update runs in the write
+// transaction of the database, which serializes the changes of all tasks.
+func (s *TaskStore) Update(
+	ctx context.Context,
+	taskID string,
+	update func(task *protocol.Task) error,
+) (*protocol.Task, error) {
+	var task *protocol.Task
+	err := s.update(func(b *bbolt.Bucket) error {
+		rec, err := s.get(b, taskID)
+		if err != nil {
+			return err
+		}
+		if err := update(&rec.Task); err != nil {
+			return err
+		}
+		task = &rec.Task
+		return put(b, task)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// DeleteExpired deletes the tasks not updated within the TTL and returns
+// their number. Expired tasks are hidden until they are deleted. Tasks
+// submitted or working do not expire.
+func (s *TaskStore) DeleteExpired() (int, error) {
+	if s.ttl <= 0 {
+		return 0, nil
+	}
+	var deleted int
+	err := s.update(func(b *bbolt.Bucket) error {
+		c := b.Cursor()
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			var rec record
+			if err := json.Unmarshal(v, &rec); err != nil {
+				return fmt.Errorf("failed to decode task %s: %w", k, err)
+			}
+			if !s.expired(rec) {
+				continue
+			}
+			if err := c.Delete(); err != nil {
+				return err
+			}
+			deleted++
+		}
+		return nil
+	})
+	return deleted, err
+}
+
+// Compact rewrites the database into a new file to return the space of
+// deleted tasks to the file system, which bbolt otherwise only reuses. The
+// store is blocked while it runs. If the database cannot be reopened once
+// compacted, the store fails until a later compaction reopens it.
+func (s *TaskStore) Compact() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.db == nil {
+		return s.reopen()
+	}
+	tmpPath := s.path + compactSuffix
+	dst, err := bbolt.Open(tmpPath, 0o600, &bbolt.Options{Timeout: openTimeout})
+	if err != nil {
+		return fmt.Errorf("failed to create compacted database: %w", err)
+	}
+	if err := bbolt.Compact(dst, s.db, compactTxSize); err != nil {
+		dst.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to compact task database: %w", err)
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to close compacted database: %w", err)
+	}
+	if err := s.db.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to close task database: %w", err)
+	}
+	// Renaming replaces the file atomically: a crash leaves either database.
+	renameErr := os.Rename(tmpPath, s.path)
+	if renameErr != nil {
+		os.Remove(tmpPath)
+	}
+	s.db = nil
+	if err := s.reopen(); err != nil {
+		return err
+	}
+	if renameErr != nil {
+		return fmt.Errorf("failed to replace task database: %w", renameErr)
+	}
+	return nil
+}
+
+// reopen opens the database closed by Compact. s.mu must be held.
+func (s *TaskStore) reopen() error {
+	db, err := open(s.path)
+	if err != nil {
+		s.dbErr = err
+		return err
+	}
+	s.db, s.dbErr = db, nil
+	return nil
+}
+
+// Close stops the maintenance of the database and closes it.
+func (s *TaskStore) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.stop)
+	})
+	<-s.done
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.db == nil {
+		return nil
+	}
+	return s.db.Close()
+}
+
+// view runs fn in a read transaction on the tasks bucket.
+func (s *TaskStore) view(fn func(b *bbolt.Bucket) error) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.db == nil {
+		return fmt.Errorf("task database unavailable: %w", s.dbErr)
+	}
+	return s.db.View(func(tx *bbolt.Tx) error {
+		return fn(tx.Bucket([]byte(tasksBucket)))
+	})
+}
+
+// update runs fn in a write transaction on the tasks bucket.
+func (s *TaskStore) update(fn func(b *bbolt.Bucket) error) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.db == nil {
+		return fmt.Errorf("task database unavailable: %w", s.dbErr)
+	}
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		return fn(tx.Bucket([]byte(tasksBucket)))
+	})
+}
+
+// get reads the task taskID from b, or returns taskmanager.ErrTaskNotFound if
+// it is missing or expired.
+func (s *TaskStore) get(b *bbolt.Bucket, taskID string) (*record, error) {
+	v := b.Get([]byte(taskID))
+	if v == nil {
+		return nil, taskmanager.ErrTaskNotFound(taskID)
+	}
+	var rec record
+	if err := json.Unmarshal(v, &rec); err != nil {
+		return nil, fmt.Errorf("failed to decode task %s: %w", taskID, err)
+	}
+	if s.expired(rec) {
+		return nil, taskmanager.ErrTaskNotFound(taskID)
+	}
+	return &rec, nil
+}
+
+// put writes task to b.
+func put(b *bbolt.Bucket, task *protocol.Task) error {
+	data, err := json.Marshal(record{Task: *task, UpdatedAt: time.Now()})
+	if err != nil {
+		return fmt.Errorf("failed to encode task %s: %w", task.ID, err)
+	}
+	return b.Put([]byte(task.ID), data)
+}
+
+// expired reports whether rec was not updated within the TTL. Tasks being
+// processed never expire, however long their processing.
+func (s *TaskStore) expired(rec record) bool {
+	switch rec.Task.Status.State {
+	case protocol.TaskStateSubmitted, protocol.TaskStateWorking:
+		return false
+	}
+	return s.ttl > 0 && time.Since(rec.UpdatedAt) > s.ttl
+}
+
+// maintain deletes expired tasks and compacts the database periodically
+// until the store is closed.
+func (s *TaskStore) maintain() {
+	defer close(s.done)
+	var cleanup, compact <-chan time.Time
+	if s.ttl > 0 {
+		ticker := time.NewTicker(s.cleanupInterval)
+		defer ticker.Stop()
+		cleanup = ticker.C
+	}
+	if s.compactInterval > 0 {
+		ticker := time.NewTicker(s.compactInterval)
+		defer ticker.Stop()
+		compact = ticker.C
+	}
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-cleanup:
+			if n, err := s.DeleteExpired(); err != nil {
+				log.Errorf("Failed to delete expired tasks: %v", err)
+			} else if n > 0 {
+				log.Debugf("Deleted %d expired tasks", n)
+			}
+		case <-compact:
+			if err := s.Compact(); err != nil {
+				log.Errorf("Failed to compact task database: %v", err)
+			}
+		}
+	}
+}
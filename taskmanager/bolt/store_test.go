@@ -0,0 +1,206 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package bolt
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// echoProcessor completes its tasks with an artifact echoing their message.
+type echoProcessor struct{}
+
+func (echoProcessor) Process(
+	ctx context.Context,
+	taskID string,
+	msg protocol.Message,
+	handle taskmanager.TaskHandle,
+) error {
+	if err := handle.AddArtifact(protocol.Artifact{Parts: msg.Parts}); err != nil {
+		return err
+	}
+	return handle.UpdateStatus(protocol.TaskStateCompleted, nil)
+}
+
+func newTask(id string, state protocol.TaskState) *protocol.Task {
+	task := protocol.NewTask(id, nil)
+	task.Status.State = state
+	return task
+}
+
+func TestTaskStore(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewTaskStore(filepath.Join(t.TempDir(), "tasks.db"))
+	require.NoError(t, err)
+	defer store.Close()
+
+	_, err = store.Get(ctx, "missing")
+	assert.True(t, taskmanager.IsTaskNotFound(err))
+
+	require.NoError(t, store.Put(ctx, newTask("a", protocol.TaskStateSubmitted)))
+	require.NoError(t, store.Put(ctx, newTask("b", protocol.TaskStateCompleted)))
+	task, err := store.Update(ctx, "a", func(task *protocol.Task) error {
+		task.Status.State = protocol.TaskStateWorking
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, protocol.TaskStateWorking, task.Status.State)
+
+	_, err = store.Update(ctx, "a", func(task *protocol.Task) error {
+		task.Status.State = protocol.TaskStateFailed
+		return errors.New("rejected")
+	})
+	assert.EqualError(t, err, "rejected")
+	task, err = store.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, protocol.TaskStateWorking, task.Status.State, "failed updates are not stored")
+
+	tasks, err := store.List(ctx, taskmanager.TaskFilter{States: []protocol.TaskState{protocol.TaskStateCompleted}})
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, "b", tasks[0].ID)
+
+	require.NoError(t, store.Delete(ctx, "b"))
+	_, err = store.Get(ctx, "b")
+	assert.True(t, taskmanager.IsTaskNotFound(err))
+
+	require.NoError(t, store.Create(ctx, newTask("b", protocol.TaskStateSubmitted)))
+	err = store.Create(ctx, newTask("b", protocol.TaskStateWorking))
+	var rpcErr *jsonrpc.Error
+	require.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, taskmanager.ErrCodeTaskExists, rpcErr.Code)
+	task, err = store.Get(ctx, "b")
+	require.NoError(t, err)
+	assert.Equal(t, protocol.TaskStateSubmitted, task.Status.State, "existing tasks are not replaced")
+}
+
+func TestTaskStore_TaskManager(t *testing.T) {
+	store, err := NewTaskStore(filepath.Join(t.TempDir(), "tasks.db"))
+	require.NoError(t, err)
+	defer store.Close()
+	tm, err := taskmanager.NewMemoryTaskManager(echoProcessor{}, taskmanager.WithTaskStore(store))
+	require.NoError(t, err)
+
+	task, err := tm.OnSendTask(context.Background(), protocol.SendTaskParams{
+		ID:      "echo",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")}),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, protocol.TaskStateCompleted, task.Status.State)
+	stored, err := store.Get(context.Background(), "echo")
+	require.NoError(t, err)
+	require.Len(t, stored.Artifacts, 1)
+	assert.Equal(t, protocol.NewTextPart("hi"), stored.Artifacts[0].Parts[0])
+}
+
+func TestTaskStore_Recovery(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "tasks.db")
+	store, err := NewTaskStore(path)
+	require.NoError(t, err)
+	for id, state := range map[string]protocol.TaskState{
+		"submitted": protocol.TaskStateSubmitted,
+		"working":   protocol.TaskStateWorking,
+		"input":     protocol.TaskStateInputRequired,
+		"completed": protocol.TaskStateCompleted,
+	} {
+		require.NoError(t, store.Put(ctx, newTask(id, state)))
+	}
+	require.NoError(t, store.Close())
+
+	store, err = NewTaskStore(path)
+	require.NoError(t, err)
+	defer store.Close()
+	task, err := store.Get(ctx, "working")
+	require.NoError(t, err)
+	assert.Equal(t, protocol.TaskStateWorking, task.Status.State, "the store leaves recovery to the task manager")
+
+	_, err = taskmanager.NewMemoryTaskManager(echoProcessor{},
+		taskmanager.WithTaskStore(store), taskmanager.WithTaskRecovery(taskmanager.RecoveryFail))
+	require.NoError(t, err)
+	for id, state := range map[string]protocol.TaskState{
+		"submitted": protocol.TaskStateFailed,
+		"working":   protocol.TaskStateFailed,
+		"input":     protocol.TaskStateInputRequired,
+		"completed": protocol.TaskStateCompleted,
+	} {
+		task, err := store.Get(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, state, task.Status.State, id)
+	}
+	task, err = store.Get(ctx, "working")
+	require.NoError(t, err)
+	require.NotNil(t, task.Status.Error)
+	assert.True(t, task.Status.Error.Retryable)
+}
+
+func TestTaskStore_Expiry(t *testing.T) {
+	ctx := context.Background()
+	// The expired tasks are deleted by the test rather than in the background.
+	store, err := NewTaskStore(filepath.Join(t.TempDir(), "tasks.db"),
+		WithTTL(50*time.Millisecond), WithCleanupInterval(time.Hour))
+	require.NoError(t, err)
+	defer store.Close()
+
+	require.NoError(t, store.Put(ctx, newTask("old", protocol.TaskStateCompleted)))
+	require.NoError(t, store.Put(ctx, newTask("working", protocol.TaskStateWorking)))
+	time.Sleep(60 * time.Millisecond)
+	require.NoError(t, store.Put(ctx, newTask("recent", protocol.TaskStateCompleted)))
+	_, err = store.Get(ctx, "old")
+	assert.True(t, taskmanager.IsTaskNotFound(err), "expired tasks are hidden")
+
+	deleted, err := store.DeleteExpired()
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+	require.NoError(t, store.view(func(b *bbolt.Bucket) error {
+		assert.Nil(t, b.Get([]byte("old")), "expired tasks are deleted")
+		return nil
+	}))
+	for _, id := range []string{"working", "recent"} {
+		_, err := store.Get(ctx, id)
+		assert.NoError(t, err, "working tasks and recent tasks are kept")
+	}
+	deleted, err = store.DeleteExpired()
+	require.NoError(t, err)
+	assert.Zero(t, deleted)
+}
+
+func TestTaskStore_Compact(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewTaskStore(filepath.Join(t.TempDir(), "tasks.db"))
+	require.NoError(t, err)
+	defer store.Close()
+
+	require.NoError(t, store.Put(ctx, newTask("kept", protocol.TaskStateCompleted)))
+	require.NoError(t, store.Compact())
+	task, err := store.Get(ctx, "kept")
+	require.NoError(t, err)
+	assert.Equal(t, protocol.TaskStateCompleted, task.Status.State)
+	require.NoError(t, store.Put(ctx, newTask("new", protocol.TaskStateSubmitted)))
+
+	// A database that could not be reopened fails until the next compaction.
+	store.mu.Lock()
+	require.NoError(t, store.db.Close())
+	store.db, store.dbErr = nil, errors.New("locked")
+	store.mu.Unlock()
+	_, err = store.Get(ctx, "kept")
+	assert.ErrorContains(t, err, "locked")
+	require.NoError(t, store.Compact())
+	_, err = store.Get(ctx, "kept")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,65 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// CancelError is the cause attached to a processor's context when its task is canceled.
+// Processors can retrieve the reason with CancelReasonFromContext.
+type CancelError struct {
+	// TaskID is the ID of the canceled task.
+	TaskID string
+	// Reason is the standardized cancellation reason.
+	Reason protocol.CancelReason
+}
+
+// Error implements error.
+func (e *CancelError) Error() string {
+	return fmt.Sprintf("task %s canceled: %s", e.TaskID, e.Reason)
+}
+
+// Is reports context.Canceled as a match so callers checking for plain
+// cancellation keep working.
+func (e *CancelError) Is(target error) bool {
+	return target == context.Canceled
+}
+
+// CancelReasonFromContext returns the cancellation reason attached to a processor
+// context, and whether one was found.
+func CancelReasonFromContext(ctx context.Context) (protocol.CancelReason, bool) {
+	var cancelErr *CancelError
+	if errors.As(context.Cause(ctx), &cancelErr) {
+		return cancelErr.Reason, true
+	}
+	return "", false
+}
+
+// NormalizeCancelReason returns reason, or CancelReasonUserRequested if it is empty.
+func NormalizeCancelReason(reason protocol.CancelReason) protocol.CancelReason {
+	if reason == "" {
+		return protocol.CancelReasonUserRequested
+	}
+	return reason
+}
+
+// NewCancelMessage creates the agent message recorded when a task is canceled.
+func NewCancelMessage(taskID string, reason protocol.CancelReason) *protocol.Message {
+	text := fmt.Sprintf("Task %s was canceled by user request", taskID)
+	if reason != protocol.CancelReasonUserRequested {
+		text = fmt.Sprintf("Task %s was canceled (reason: %s)", taskID, reason)
+	}
+	return &protocol.Message{
+		Role:  protocol.MessageRoleAgent,
+		Parts: []protocol.Part{protocol.NewTextPart(text)},
+	}
+}
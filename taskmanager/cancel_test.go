@@ -0,0 +1,155 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+func TestCancelReasonFromContext(t *testing.T) {
+	_, ok := CancelReasonFromContext(context.Background())
+	assert.False(t, ok)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, ok = CancelReasonFromContext(ctx)
+	assert.False(t, ok, "plain cancellation carries no reason")
+
+	ctx, cancelCause := context.WithCancelCause(context.Background())
+	cancelCause(&CancelError{TaskID: "t1", Reason: protocol.CancelReasonTimeout})
+	reason, ok := CancelReasonFromContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, protocol.CancelReasonTimeout, reason)
+	assert.True(t, errors.Is(context.Cause(ctx), context.Canceled))
+}
+
+func TestNormalizeCancelReason(t *testing.T) {
+	assert.Equal(t, protocol.CancelReasonUserRequested, NormalizeCancelReason(""))
+	assert.Equal(t, protocol.CancelReasonPolicy, NormalizeCancelReason(protocol.CancelReasonPolicy))
+}
+
+func TestMemoryTaskManager_OnCancelTask_Reason(t *testing.T) {
+	seen := make(chan protocol.CancelReason, 1)
+	processor := &mockProcessor{
+		processFunc: func(ctx context.Context, taskID string, msg protocol.Message, handle TaskHandle) error {
+			<-ctx.Done()
+			reason, _ := CancelReasonFromContext(ctx)
+			seen <- reason
+			return ctx.Err()
+		},
+	}
+	tm, err := NewMemoryTaskManager(processor)
+	require.NoError(t, err)
+
+	taskID := "cancel-with-reason"
+	_, err = tm.OnSendTaskSubscribe(context.Background(), protocol.SendTaskParams{
+		ID:      taskID,
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")}),
+	})
+	require.NoError(t, err)
+	time.Sleep(20 * time.Millisecond)
+
+	task, err := tm.OnCancelTask(context.Background(), protocol.TaskIDParams{
+		ID:     taskID,
+		Reason: protocol.CancelReasonSuperseded,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, protocol.TaskStateCanceled, task.Status.State)
+	assert.Equal(t, protocol.CancelReasonSuperseded, task.Status.CancelReason)
+	require.NotNil(t, task.Status.Message)
+	assert.Contains(t, task.Status.Message.Parts[0].(protocol.TextPart).Text, "superseded")
+
+	select {
+	case reason := <-seen:
+		assert.Equal(t, protocol.CancelReasonSuperseded, reason)
+	case <-time.After(time.Second):
+		t.Fatal("processor was not canceled")
+	}
+
+	// The processor returning an error after cancellation must not mark the task failed.
+	time.Sleep(20 * time.Millisecond)
+	task, err = tm.OnGetTask(context.Background(), protocol.TaskQueryParams{ID: taskID})
+	require.NoError(t, err)
+	assert.Equal(t, protocol.TaskStateCanceled, task.Status.State)
+}
+
+func TestMemoryTaskManager_Contexts(t *testing.T) {
+	seen := make(chan error, 1)
+	processor := &mockProcessor{
+		processFunc: func(ctx context.Context, taskID string, msg protocol.Message, handle TaskHandle) error {
+			<-ctx.Done()
+			seen <- ctx.Err()
+			return ctx.Err()
+		},
+	}
+	tm, err := NewMemoryTaskManager(processor)
+	require.NoError(t, err)
+	_, err = tm.OnSendTaskSubscribe(context.Background(), protocol.SendTaskParams{
+		ID:      "canceled",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")}),
+	})
+	require.NoError(t, err)
+
+	// The cancellation functions of Contexts still cancel the processing.
+	var cancel context.CancelFunc
+	require.Eventually(t, func() bool {
+		tm.ContextsMutex.RLock()
+		defer tm.ContextsMutex.RUnlock()
+		cancel = tm.Contexts["canceled"]
+		return cancel != nil
+	}, time.Second, 5*time.Millisecond)
+	cancel()
+	select {
+	case err := <-seen:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(time.Second):
+		t.Fatal("processor was not canceled")
+	}
+}
+
+func TestMemoryTaskManager_DeleteTask(t *testing.T) {
+	ctx := context.Background()
+	processor := &mockProcessor{
+		processFunc: func(ctx context.Context, taskID string, msg protocol.Message, handle TaskHandle) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}
+	tm, err := NewMemoryTaskManager(processor)
+	require.NoError(t, err)
+	events, err := tm.OnSendTaskSubscribe(ctx, protocol.SendTaskParams{
+		ID:      "deleted",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")}),
+	})
+	require.NoError(t, err)
+	<-events
+
+	require.NoError(t, tm.DeleteTask(ctx, "deleted"))
+	// The stream ends and the processing stops without bringing the task back.
+	for range events {
+	}
+	require.Eventually(t, func() bool {
+		tm.ContextsMutex.RLock()
+		defer tm.ContextsMutex.RUnlock()
+		return tm.Contexts["deleted"] == nil
+	}, time.Second, 5*time.Millisecond)
+	_, err = tm.OnGetTask(ctx, protocol.TaskQueryParams{ID: "deleted"})
+	var rpcErr *jsonrpc.Error
+	require.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, ErrCodeTaskNotFound, rpcErr.Code)
+	require.ErrorAs(t, tm.DeleteTask(ctx, "deleted"), &rpcErr)
+	assert.Equal(t, ErrCodeTaskNotFound, rpcErr.Code)
+}
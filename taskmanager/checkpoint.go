@@ -0,0 +1,66 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// Checkpointer is implemented by task handles that can persist the
+// intermediate state of their task, so that long-running processors retried
+// by the RetryPolicy, requeued or resumed after a restart continue from their
+// last checkpoint rather than from scratch. The handles of the memory and
+// Redis task managers implement it, recording the checkpoint in the task
+// metadata under protocol.MetadataKeyCheckpoint, and thus in the task store,
+// hidden from clients and removed once the task ends.
+type Checkpointer interface {
+	// SaveCheckpoint saves checkpoint, replacing the previous checkpoint of
+	// the task.
+	SaveCheckpoint(checkpoint protocol.TaskCheckpoint) error
+	// LoadCheckpoint returns the last checkpoint of the task and whether
+	// there was any.
+	LoadCheckpoint() (protocol.TaskCheckpoint, bool, error)
+}
+
+// SaveCheckpoint saves checkpoint for the task of handle. If handle cannot
+// persist checkpoints, it does nothing and the task restarts from scratch.
+func SaveCheckpoint(handle TaskHandle, checkpoint protocol.TaskCheckpoint) error {
+	if checkpointer, ok := handle.(Checkpointer); ok {
+		return checkpointer.SaveCheckpoint(checkpoint)
+	}
+	return nil
+}
+
+// LoadCheckpoint returns the last checkpoint saved for the task of handle and
+// whether there was any. There is none if handle cannot persist checkpoints.
+func LoadCheckpoint(handle TaskHandle) (protocol.TaskCheckpoint, bool, error) {
+	if checkpointer, ok := handle.(Checkpointer); ok {
+		return checkpointer.LoadCheckpoint()
+	}
+	return protocol.TaskCheckpoint{}, false, nil
+}
+
+// SaveCheckpoint implements Checkpointer.
+func (h *memoryTaskHandle) SaveCheckpoint(checkpoint protocol.TaskCheckpoint) error {
+	_, err := h.manager.store.Update(context.Background(), h.taskID, func(task *protocol.Task) error {
+		task.Metadata = protocol.WithCheckpoint(task.Metadata, checkpoint)
+		return nil
+	})
+	return err
+}
+
+// LoadCheckpoint implements Checkpointer.
+func (h *memoryTaskHandle) LoadCheckpoint() (protocol.TaskCheckpoint, bool, error) {
+	task, err := h.manager.store.Get(context.Background(), h.taskID)
+	if err != nil {
+		return protocol.TaskCheckpoint{}, false, err
+	}
+	checkpoint, ok := task.Checkpoint()
+	return checkpoint, ok, nil
+}
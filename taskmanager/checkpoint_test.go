@@ -0,0 +1,95 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// stepProcessor processes tasks in steps, saving a checkpoint after each step
+// and failing retryably after the step failAfter once.
+type stepProcessor struct {
+	steps     int
+	failAfter int
+	// ran records the steps run, across attempts.
+	ran []int
+}
+
+func (p *stepProcessor) Process(ctx context.Context, taskID string, msg protocol.Message, handle TaskHandle) error {
+	checkpoint, _, err := LoadCheckpoint(handle)
+	if err != nil {
+		return err
+	}
+	for step := checkpoint.Step; step < p.steps; step++ {
+		p.ran = append(p.ran, step)
+		checkpoint = protocol.TaskCheckpoint{Step: step + 1, Output: checkpoint.Output + "."}
+		if err := SaveCheckpoint(handle, checkpoint); err != nil {
+			return err
+		}
+		if step == p.failAfter {
+			p.failAfter = -1
+			return NewProcessingError(protocol.TaskErrorCodeUnavailable, true, errors.New("step failed"))
+		}
+	}
+	return handle.UpdateStatus(protocol.TaskStateCompleted, &protocol.Message{
+		Role:  protocol.MessageRoleAgent,
+		Parts: []protocol.Part{protocol.NewTextPart(checkpoint.Output)},
+	})
+}
+
+func TestCheckpoints(t *testing.T) {
+	processor := &stepProcessor{steps: 4, failAfter: 1}
+	tm, err := NewMemoryTaskManager(processor,
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}))
+	require.NoError(t, err)
+
+	task, err := tm.OnSendTask(context.Background(), protocol.SendTaskParams{
+		ID:      "steps",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("go")}),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, protocol.TaskStateCompleted, task.Status.State)
+	assert.Equal(t, []int{0, 1, 2, 3}, processor.ran, "the retry continues from the checkpoint")
+	assert.Equal(t, protocol.NewTextPart("...."), task.Status.Message.Parts[0])
+	// The checkpoint is removed once the task ends.
+	_, ok := task.Checkpoint()
+	assert.False(t, ok)
+	stored, err := tm.store.Get(context.Background(), "steps")
+	require.NoError(t, err)
+	_, ok = stored.Checkpoint()
+	assert.False(t, ok)
+
+	// Checkpoints are hidden from clients while the task runs.
+	running := protocol.NewTask("running", nil)
+	running.Status.State = protocol.TaskStateWorking
+	require.NoError(t, tm.store.Put(context.Background(), running))
+	handle := &memoryTaskHandle{taskID: "running", manager: tm}
+	checkpoint := protocol.TaskCheckpoint{Step: 1, Output: "."}
+	require.NoError(t, SaveCheckpoint(handle, checkpoint))
+	loaded, ok, err := LoadCheckpoint(handle)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, checkpoint, loaded)
+	running, err = tm.OnGetTask(context.Background(), protocol.TaskQueryParams{ID: "running"})
+	require.NoError(t, err)
+	assert.NotContains(t, running.Metadata, protocol.MetadataKeyCheckpoint)
+
+	// Handles without checkpoints restart from scratch.
+	recording := &recordingHandle{}
+	require.NoError(t, SaveCheckpoint(recording, checkpoint))
+	_, ok, err = LoadCheckpoint(recording)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
@@ -0,0 +1,166 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+const (
+	// MetadataKeyDelegatedTask is the artifact metadata key under which Delegate
+	// records the ID of the subtask that produced a relayed artifact.
+	MetadataKeyDelegatedTask = "delegatedTaskId"
+	// delegateCancelTimeout bounds canceling a subtask whose parent gave up.
+	delegateCancelTimeout = 5 * time.Second
+)
+
+// TaskStreamer starts a task on another agent and streams its events. The
+// Streamer method of *client.A2AClient returns one.
+type TaskStreamer interface {
+	StreamTask(ctx context.Context, params protocol.SendTaskParams) (<-chan protocol.TaskEvent, error)
+}
+
+// taskCanceler is implemented by streamers that can cancel the tasks they started.
+type taskCanceler interface {
+	CancelTasks(ctx context.Context, params protocol.TaskIDParams) (*protocol.Task, error)
+}
+
+// DelegationError reports a subtask that failed or was canceled.
+type DelegationError struct {
+	// TaskID is the ID of the subtask.
+	TaskID string
+	// Status is the final status of the subtask.
+	Status protocol.TaskStatus
+}
+
+// Error implements error.
+func (e *DelegationError) Error() string {
+	msg := fmt.Sprintf("delegated task %s %s", e.TaskID, e.Status.State)
+	if e.Status.Message != nil {
+		for _, part := range e.Status.Message.Parts {
+			if text, ok := part.(protocol.TextPart); ok && text.Text != "" {
+				return msg + ": " + text.Text
+			}
+		}
+	}
+	return msg
+}
+
+// Delegate forwards a subtask to another agent through streamer and relays its
+// events into the parent task through emitter, the parent's TaskHandle:
+// artifacts are added to the parent, tagged with the subtask ID under
+// MetadataKeyDelegatedTask, and status messages of the working subtask are
+// reported as working status updates of the parent. Events carry the parent's
+// task ID from then on, since emitter reports them for the parent.
+//
+// Delegate returns when the subtask ends. A completed subtask returns its final
+// status, leaving the parent to decide its own final state. A subtask requiring
+// input is reported as input required on the parent and its status returned.
+// A failed or canceled subtask returns a *DelegationError. If ctx is done first,
+// the subtask is canceled when streamer supports it, and ctx.Err() is returned.
+//
+// Called with the processor context, the subtask inherits the deadline budget
+// and provenance chain of the parent when the streamer is an A2A client. The
+// headers of the requests, such as the tenant, are set on ctx, e.g. with
+// metadata.AppendToOutgoingContext, and apply to the cancellation too.
+func Delegate(
+	ctx context.Context,
+	streamer TaskStreamer,
+	params protocol.SendTaskParams,
+	emitter TaskHandle,
+) (protocol.TaskStatus, error) {
+	if params.ID == "" {
+		return protocol.TaskStatus{}, errors.New("delegate: subtask ID is required")
+	}
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel() // Agents may keep streams open past the final event.
+	events, err := streamer.StreamTask(streamCtx, params)
+	if err != nil {
+		return protocol.TaskStatus{}, fmt.Errorf("delegate: failed to start subtask %s: %w", params.ID, err)
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			cancelSubtask(ctx, streamer, params.ID)
+			return protocol.TaskStatus{}, ctx.Err()
+		case event, ok := <-events:
+			if !ok {
+				if ctx.Err() != nil {
+					cancelSubtask(ctx, streamer, params.ID)
+					return protocol.TaskStatus{}, ctx.Err()
+				}
+				return protocol.TaskStatus{}, fmt.Errorf("delegate: stream of subtask %s ended before it finished", params.ID)
+			}
+			status, done, err := relayEvent(params.ID, event, emitter)
+			if err != nil || done {
+				return status, err
+			}
+		}
+	}
+}
+
+// relayEvent relays one event of subtask taskID into the parent task and
+// reports whether the subtask ended, with its final status.
+func relayEvent(taskID string, event protocol.TaskEvent, emitter TaskHandle) (protocol.TaskStatus, bool, error) {
+	switch e := event.(type) {
+	case protocol.TaskArtifactUpdateEvent:
+		artifact := e.Artifact
+		metadata := make(map[string]interface{}, len(artifact.Metadata)+1)
+		for k, v := range artifact.Metadata {
+			metadata[k] = v
+		}
+		metadata[MetadataKeyDelegatedTask] = taskID
+		artifact.Metadata = metadata
+		if err := emitter.AddArtifact(artifact); err != nil {
+			return protocol.TaskStatus{}, false, fmt.Errorf("delegate: failed to relay artifact of subtask %s: %w", taskID, err)
+		}
+		return protocol.TaskStatus{}, false, nil
+	case protocol.TaskStatusUpdateEvent:
+		switch e.Status.State {
+		case protocol.TaskStateCompleted:
+			return e.Status, true, nil
+		case protocol.TaskStateFailed, protocol.TaskStateCanceled:
+			return e.Status, true, &DelegationError{TaskID: taskID, Status: e.Status}
+		case protocol.TaskStateInputRequired:
+			if err := emitter.UpdateStatus(protocol.TaskStateInputRequired, e.Status.Message); err != nil {
+				return e.Status, true, fmt.Errorf("delegate: failed to relay status of subtask %s: %w", taskID, err)
+			}
+			return e.Status, true, nil
+		case protocol.TaskStateWorking:
+			if e.Status.Message == nil {
+				return protocol.TaskStatus{}, false, nil // The parent is already working.
+			}
+			if err := emitter.UpdateStatus(protocol.TaskStateWorking, e.Status.Message); err != nil {
+				return protocol.TaskStatus{}, false, fmt.Errorf("delegate: failed to relay status of subtask %s: %w", taskID, err)
+			}
+		}
+		if e.Final {
+			return e.Status, true, &DelegationError{TaskID: taskID, Status: e.Status}
+		}
+	}
+	return protocol.TaskStatus{}, false, nil
+}
+
+// cancelSubtask cancels subtask taskID if streamer supports it, with the
+// values of ctx, which is done.
+func cancelSubtask(ctx context.Context, streamer TaskStreamer, taskID string) {
+	canceler, ok := streamer.(taskCanceler)
+	if !ok {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), delegateCancelTimeout)
+	defer cancel()
+	if _, err := canceler.CancelTasks(ctx, protocol.TaskIDParams{ID: taskID}); err != nil {
+		log.Warnf("Failed to cancel delegated task %s: %v", taskID, err)
+	}
+}
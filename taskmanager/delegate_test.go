@@ -0,0 +1,134 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/client"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+var _ TaskStreamer = (*client.Streamer)(nil)
+
+// fakeStreamer streams preset events and records cancellations.
+type fakeStreamer struct {
+	events   []protocol.TaskEvent
+	keepOpen bool
+	params   protocol.SendTaskParams
+	canceled []string
+}
+
+func (s *fakeStreamer) StreamTask(ctx context.Context, params protocol.SendTaskParams) (<-chan protocol.TaskEvent, error) {
+	s.params = params
+	ch := make(chan protocol.TaskEvent, len(s.events))
+	for _, e := range s.events {
+		ch <- e
+	}
+	if !s.keepOpen {
+		close(ch)
+	}
+	return ch, nil
+}
+
+func (s *fakeStreamer) CancelTasks(ctx context.Context, params protocol.TaskIDParams) (*protocol.Task, error) {
+	s.canceled = append(s.canceled, params.ID)
+	return nil, nil
+}
+
+// recordingHandle records what is reported for the parent task.
+type recordingHandle struct {
+	states    []protocol.TaskState
+	artifacts []protocol.Artifact
+}
+
+func (h *recordingHandle) UpdateStatus(state protocol.TaskState, msg *protocol.Message) error {
+	h.states = append(h.states, state)
+	return nil
+}
+
+func (h *recordingHandle) AddArtifact(artifact protocol.Artifact) error {
+	h.artifacts = append(h.artifacts, artifact)
+	return nil
+}
+
+func (h *recordingHandle) IsStreamingRequest() bool { return true }
+
+func statusEvent(state protocol.TaskState, text string, final bool) protocol.TaskStatusUpdateEvent {
+	status := protocol.TaskStatus{State: state}
+	if text != "" {
+		msg := protocol.NewMessage(protocol.MessageRoleAgent, []protocol.Part{protocol.NewTextPart(text)})
+		status.Message = &msg
+	}
+	return protocol.TaskStatusUpdateEvent{ID: "sub", Status: status, Final: final}
+}
+
+func TestDelegate(t *testing.T) {
+	ctx := context.Background()
+	params := protocol.SendTaskParams{ID: "sub", Message: protocol.NewMessage(protocol.MessageRoleUser, nil)}
+
+	t.Run("completed", func(t *testing.T) {
+		streamer := &fakeStreamer{keepOpen: true, events: []protocol.TaskEvent{
+			statusEvent(protocol.TaskStateWorking, "", false),
+			statusEvent(protocol.TaskStateWorking, "halfway", false),
+			protocol.TaskArtifactUpdateEvent{ID: "sub", Artifact: protocol.Artifact{
+				Parts: []protocol.Part{protocol.NewTextPart("result")},
+			}},
+			statusEvent(protocol.TaskStateCompleted, "done", true),
+		}}
+		handle := &recordingHandle{}
+		status, err := Delegate(ctx, streamer, params, handle)
+		require.NoError(t, err)
+		assert.Equal(t, protocol.TaskStateCompleted, status.State)
+		assert.Equal(t, []protocol.TaskState{protocol.TaskStateWorking}, handle.states)
+		require.Len(t, handle.artifacts, 1)
+		assert.Equal(t, "sub", handle.artifacts[0].Metadata[MetadataKeyDelegatedTask])
+		assert.Equal(t, "sub", streamer.params.ID)
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		streamer := &fakeStreamer{events: []protocol.TaskEvent{
+			statusEvent(protocol.TaskStateFailed, "boom", true),
+		}}
+		_, err := Delegate(ctx, streamer, params, &recordingHandle{})
+		var delegationErr *DelegationError
+		require.True(t, errors.As(err, &delegationErr))
+		assert.Equal(t, protocol.TaskStateFailed, delegationErr.Status.State)
+		assert.Contains(t, err.Error(), "boom")
+	})
+
+	t.Run("input required", func(t *testing.T) {
+		streamer := &fakeStreamer{keepOpen: true, events: []protocol.TaskEvent{
+			statusEvent(protocol.TaskStateInputRequired, "which currency?", false),
+		}}
+		handle := &recordingHandle{}
+		status, err := Delegate(ctx, streamer, params, handle)
+		require.NoError(t, err)
+		assert.Equal(t, protocol.TaskStateInputRequired, status.State)
+		assert.Equal(t, []protocol.TaskState{protocol.TaskStateInputRequired}, handle.states)
+	})
+
+	t.Run("stream ends early", func(t *testing.T) {
+		streamer := &fakeStreamer{events: []protocol.TaskEvent{statusEvent(protocol.TaskStateWorking, "", false)}}
+		_, err := Delegate(ctx, streamer, params, &recordingHandle{})
+		assert.ErrorContains(t, err, "ended before")
+	})
+
+	t.Run("parent canceled", func(t *testing.T) {
+		streamer := &fakeStreamer{keepOpen: true}
+		canceledCtx, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err := Delegate(canceledCtx, streamer, params, &recordingHandle{})
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, []string{"sub"}, streamer.canceled)
+	})
+}
@@ -0,0 +1,120 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+)
+
+// ErrRequestEnded is the cause with which the disconnect policy cancels the
+// context of a task whose request ended, see context.Cause.
+var ErrRequestEnded = errors.New("task request ended")
+
+// DisconnectAction is what happens to a task whose request ends before it,
+// e.g. when the client disconnects.
+type DisconnectAction int
+
+const (
+	// DisconnectCancel cancels the context of the task as soon as its request
+	// ends.
+	DisconnectCancel DisconnectAction = iota
+	// DisconnectContinue continues the task detached from its request. Its
+	// result can be fetched with tasks/get or tasks/resubscribe.
+	DisconnectContinue
+	// DisconnectGrace cancels the context of the task if it is still running
+	// once the grace period has passed after its request ended, so that
+	// clients reconnecting in time can resubscribe to it.
+	DisconnectGrace
+)
+
+// DisconnectPolicy decides what happens to the tasks whose request ends before
+// them. The policy cancels the context of the processor with ErrRequestEnded,
+// and the processor decides how the task ends, as when its context expires.
+// Whatever the action, the deadline of the request, e.g. the deadline budget
+// sent by the client, still bounds the task.
+type DisconnectPolicy struct {
+	// Action is the action taken when the request ends. Default DisconnectCancel.
+	Action DisconnectAction
+	// GracePeriod is how long tasks outlive their request with DisconnectGrace.
+	GracePeriod time.Duration
+}
+
+// gracePeriod returns how long a task outlives its request before it is
+// canceled, or false if it is never canceled.
+func (p DisconnectPolicy) gracePeriod() (time.Duration, bool) {
+	switch p.Action {
+	case DisconnectContinue:
+		return 0, false
+	case DisconnectGrace:
+		return p.GracePeriod, true
+	default:
+		return 0, true
+	}
+}
+
+// taskContext returns the context in which the task taskID, sent with ctx, is
+// processed according to policy. The returned function must be called once the
+// processing ends.
+func taskContext(
+	ctx context.Context,
+	taskID string,
+	policy DisconnectPolicy,
+) (context.Context, context.CancelCauseFunc) {
+	taskCtx := context.WithoutCancel(ctx)
+	stopDeadline := context.CancelFunc(func() {})
+	if deadline, ok := ctx.Deadline(); ok {
+		taskCtx, stopDeadline = context.WithDeadline(taskCtx, deadline)
+	}
+	taskCtx, cancel := context.WithCancelCause(taskCtx)
+	grace, cancels := policy.gracePeriod()
+	if !cancels {
+		return taskCtx, func(cause error) {
+			cancel(cause)
+			stopDeadline()
+		}
+	}
+
+	var (
+		mu    sync.Mutex
+		timer *time.Timer
+		ended bool
+	)
+	stopWatching := context.AfterFunc(ctx, func() {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return // The task context expires on its own.
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if ended {
+			return
+		}
+		if grace <= 0 {
+			cancel(ErrRequestEnded)
+			return
+		}
+		log.Debugf("Request of task %s ended, canceling the task in %v", taskID, grace)
+		timer = time.AfterFunc(grace, func() {
+			cancel(ErrRequestEnded)
+		})
+	})
+	return taskCtx, func(cause error) {
+		stopWatching()
+		mu.Lock()
+		ended = true
+		if timer != nil {
+			timer.Stop()
+		}
+		mu.Unlock()
+		cancel(cause)
+		stopDeadline()
+	}
+}
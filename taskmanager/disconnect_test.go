@@ -0,0 +1,168 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// disconnectProcessor completes its tasks once done is closed, or fails with
+// the cause of their context.
+func disconnectProcessor(done <-chan struct{}, causes chan<- error) *mockProcessor {
+	return &mockProcessor{
+		processFunc: func(ctx context.Context, taskID string, msg protocol.Message, handle TaskHandle) error {
+			select {
+			case <-done:
+				causes <- nil
+				return handle.UpdateStatus(protocol.TaskStateCompleted, nil)
+			case <-ctx.Done():
+				causes <- context.Cause(ctx)
+				return ctx.Err()
+			}
+		},
+	}
+}
+
+func TestMemoryTaskManager_DisconnectPolicy(t *testing.T) {
+	send := func(t *testing.T, policy DisconnectPolicy, done chan struct{}) (context.CancelFunc, chan error) {
+		causes := make(chan error, 1)
+		tm, err := NewMemoryTaskManager(disconnectProcessor(done, causes), WithDisconnectPolicy(policy))
+		require.NoError(t, err)
+		ctx, cancel := context.WithCancel(context.Background())
+		_, err = tm.OnSendTaskSubscribe(ctx, protocol.SendTaskParams{
+			ID:      "disconnected",
+			Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")}),
+		})
+		require.NoError(t, err)
+		return cancel, causes
+	}
+
+	t.Run("cancel", func(t *testing.T) {
+		cancel, causes := send(t, DisconnectPolicy{}, make(chan struct{}))
+		cancel()
+		select {
+		case cause := <-causes:
+			assert.ErrorIs(t, cause, ErrRequestEnded)
+		case <-time.After(time.Second):
+			t.Fatal("task not canceled")
+		}
+	})
+
+	t.Run("continue", func(t *testing.T) {
+		done := make(chan struct{})
+		cancel, causes := send(t, DisconnectPolicy{Action: DisconnectContinue}, done)
+		cancel()
+		select {
+		case cause := <-causes:
+			t.Fatalf("task canceled: %v", cause)
+		case <-time.After(50 * time.Millisecond):
+		}
+		close(done)
+		assert.NoError(t, <-causes)
+	})
+
+	t.Run("grace", func(t *testing.T) {
+		cancel, causes := send(t, DisconnectPolicy{Action: DisconnectGrace, GracePeriod: 100 * time.Millisecond},
+			make(chan struct{}))
+		start := time.Now()
+		cancel()
+		select {
+		case cause := <-causes:
+			assert.ErrorIs(t, cause, ErrRequestEnded)
+			assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+		case <-time.After(time.Second):
+			t.Fatal("task not canceled after the grace period")
+		}
+
+		// Tasks completing within the grace period complete.
+		done := make(chan struct{})
+		cancel, causes = send(t, DisconnectPolicy{Action: DisconnectGrace, GracePeriod: time.Second}, done)
+		cancel()
+		close(done)
+		assert.NoError(t, <-causes)
+	})
+}
+
+func TestMemoryTaskManager_DisconnectKeepsDeadline(t *testing.T) {
+	causes := make(chan error, 1)
+	tm, err := NewMemoryTaskManager(disconnectProcessor(make(chan struct{}), causes),
+		WithDisconnectPolicy(DisconnectPolicy{Action: DisconnectContinue}))
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	task, err := tm.OnSendTask(ctx, protocol.SendTaskParams{
+		ID:      "bounded",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")}),
+	})
+	require.Error(t, err)
+	assert.ErrorIs(t, <-causes, context.DeadlineExceeded)
+	assert.Equal(t, protocol.TaskStateFailed, task.Status.State)
+}
+
+func TestMemoryTaskManager_DisconnectContinueReplay(t *testing.T) {
+	proceed := make(chan struct{})
+	processor := &mockProcessor{
+		processFunc: func(ctx context.Context, taskID string, msg protocol.Message, handle TaskHandle) error {
+			select {
+			case <-proceed:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			if err := handle.AddArtifact(protocol.Artifact{
+				Parts: []protocol.Part{protocol.NewTextPart("result")},
+			}); err != nil {
+				return err
+			}
+			return handle.UpdateStatus(protocol.TaskStateCompleted, nil)
+		},
+	}
+	tm, err := NewMemoryTaskManager(processor,
+		WithDisconnectPolicy(DisconnectPolicy{Action: DisconnectContinue}),
+		WithEventReplay(NewMemoryEventHistory(0)))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := tm.OnSendTaskSubscribe(ctx, protocol.SendTaskParams{
+		ID:      "detached",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")}),
+	})
+	require.NoError(t, err)
+	// The client receives the working status, then disconnects.
+	first := <-events
+	assert.Equal(t, "1", protocol.EventID(first))
+	cancel()
+	require.Eventually(t, func() bool {
+		tm.SubMutex.RLock()
+		defer tm.SubMutex.RUnlock()
+		return len(tm.Subscribers["detached"]) == 0
+	}, time.Second, 5*time.Millisecond, "the subscriber is removed")
+
+	// The task completes without its client.
+	close(proceed)
+	require.Eventually(t, func() bool {
+		task, err := tm.OnGetTask(context.Background(), protocol.TaskQueryParams{ID: "detached"})
+		return err == nil && task.Status.State == protocol.TaskStateCompleted
+	}, time.Second, 5*time.Millisecond)
+
+	// Resubscribing replays the events the client missed.
+	replayed, err := tm.OnResubscribe(context.Background(), protocol.TaskIDParams{ID: "detached", LastEventID: "1"})
+	require.NoError(t, err)
+	var missed []protocol.TaskEvent
+	for event := range replayed {
+		missed = append(missed, event)
+	}
+	require.Len(t, missed, 2)
+	assert.IsType(t, protocol.TaskArtifactUpdateEvent{}, missed[0])
+	assert.True(t, missed[1].IsFinal())
+}
@@ -0,0 +1,208 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"sync"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// StatusEmitter rate limits the status updates of a task. Updates arriving
+// faster than the rate are coalesced: the pending update keeps the latest
+// state and the messages are merged, the parts of later messages being
+// appended and adjacent text parts joined. Coalesced updates are sent once the
+// rate allows it, so token-level progress reaches subscribers and webhooks as
+// a few larger events.
+//
+// Updates that change the state, and updates to a final state, are never
+// delayed: the pending update is sent first, then the new one.
+//
+//	emitter := taskmanager.NewStatusEmitter(handle, 5)
+//	defer emitter.Close()
+//	for token := range tokens {
+//		msg := protocol.NewMessage(protocol.MessageRoleAgent, []protocol.Part{protocol.NewTextPart(token)})
+//		if err := emitter.Update(protocol.TaskStateWorking, &msg); err != nil {
+//			return err
+//		}
+//	}
+//
+// It is safe for concurrent use.
+type StatusEmitter struct {
+	handle   TaskHandle
+	interval time.Duration
+
+	mu        sync.Mutex
+	last      time.Time          // When the last update was sent.
+	lastState protocol.TaskState // The state of the last update sent.
+	pending   bool               // Whether state and msg hold an update to send.
+	state     protocol.TaskState
+	msg       *protocol.Message
+	timer     *time.Timer
+	timers    uint64 // The number of timers started, identifying the current one.
+	err       error  // The error of a delayed update, reported by the next call.
+	closed    bool
+}
+
+// NewStatusEmitter creates an emitter sending at most maxPerSecond status
+// updates per second through handle. A non-positive rate disables coalescing.
+func NewStatusEmitter(handle TaskHandle, maxPerSecond float64) *StatusEmitter {
+	e := &StatusEmitter{handle: handle}
+	if maxPerSecond > 0 {
+		e.interval = time.Duration(float64(time.Second) / maxPerSecond)
+	}
+	return e
+}
+
+// Update sends or coalesces a status update. It returns the error of a
+// previously delayed update, if sending it failed.
+func (e *StatusEmitter) Update(state protocol.TaskState, msg *protocol.Message) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if err := e.takeError(); err != nil {
+		return err
+	}
+	if e.closed {
+		return e.handle.UpdateStatus(state, msg)
+	}
+	if e.pending && state != e.state {
+		if err := e.flush(); err != nil {
+			return err
+		}
+	}
+	e.merge(state, msg)
+	wait := e.interval - time.Since(e.last)
+	if wait <= 0 || state != e.lastState || isFinalState(state) {
+		return e.flush()
+	}
+	if e.timer == nil {
+		e.timers++
+		generation := e.timers
+		e.timer = time.AfterFunc(wait, func() { e.flushLater(generation) })
+	}
+	return nil
+}
+
+// Flush sends the pending update now, if any.
+func (e *StatusEmitter) Flush() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if err := e.takeError(); err != nil {
+		return err
+	}
+	return e.flush()
+}
+
+// Close sends the pending update, if any, and stops coalescing: later updates
+// are sent immediately.
+func (e *StatusEmitter) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.closed = true
+	if err := e.takeError(); err != nil {
+		return err
+	}
+	return e.flush()
+}
+
+// merge folds an update into the pending one. The caller must hold mu.
+func (e *StatusEmitter) merge(state protocol.TaskState, msg *protocol.Message) {
+	e.state = state
+	if !e.pending {
+		e.pending = true
+		e.msg = copyMessage(msg)
+		return
+	}
+	if msg == nil {
+		return
+	}
+	if e.msg == nil {
+		e.msg = copyMessage(msg)
+		return
+	}
+	e.msg.Role = msg.Role
+	for _, part := range msg.Parts {
+		e.msg.Parts = appendPart(e.msg.Parts, part)
+	}
+	for k, v := range msg.Metadata {
+		if e.msg.Metadata == nil {
+			e.msg.Metadata = make(map[string]interface{}, len(msg.Metadata))
+		}
+		e.msg.Metadata[k] = v
+	}
+}
+
+// flush sends the pending update. The caller must hold mu.
+func (e *StatusEmitter) flush() error {
+	if e.timer != nil {
+		e.timer.Stop()
+		e.timer = nil
+	}
+	if !e.pending {
+		return nil
+	}
+	state, msg := e.state, e.msg
+	e.pending = false
+	e.msg = nil
+	e.last = time.Now()
+	e.lastState = state
+	return e.handle.UpdateStatus(state, msg)
+}
+
+// flushLater sends the pending update once the rate allows it, unless the
+// timer of the given generation was stopped meanwhile.
+func (e *StatusEmitter) flushLater(generation uint64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.timer == nil || e.timers != generation {
+		return
+	}
+	if err := e.flush(); err != nil && e.err == nil {
+		e.err = err
+	}
+}
+
+// takeError returns and clears the error of a delayed update. The caller must hold mu.
+func (e *StatusEmitter) takeError() error {
+	err := e.err
+	e.err = nil
+	return err
+}
+
+// copyMessage returns a copy of msg whose parts and metadata can be extended
+// without changing msg.
+func copyMessage(msg *protocol.Message) *protocol.Message {
+	if msg == nil {
+		return nil
+	}
+	cp := *msg
+	cp.Parts = append([]protocol.Part(nil), msg.Parts...)
+	if msg.Metadata != nil {
+		cp.Metadata = make(map[string]interface{}, len(msg.Metadata))
+		for k, v := range msg.Metadata {
+			cp.Metadata[k] = v
+		}
+	}
+	return &cp
+}
+
+// appendPart appends part to parts, joining it to a trailing text part if it is
+// text too.
+func appendPart(parts []protocol.Part, part protocol.Part) []protocol.Part {
+	text, ok := part.(protocol.TextPart)
+	if !ok || len(parts) == 0 {
+		return append(parts, part)
+	}
+	last, ok := parts[len(parts)-1].(protocol.TextPart)
+	if !ok || last.Metadata != nil || text.Metadata != nil {
+		return append(parts, part)
+	}
+	last.Text += text.Text
+	parts[len(parts)-1] = last
+	return parts
+}
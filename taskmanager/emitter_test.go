@@ -0,0 +1,103 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// statusRecorder is a TaskHandle recording the status updates.
+type statusRecorder struct {
+	artifactRecorder
+	mu       sync.Mutex
+	states   []protocol.TaskState
+	messages []*protocol.Message
+	err      error
+}
+
+func (r *statusRecorder) UpdateStatus(state protocol.TaskState, msg *protocol.Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return r.err
+	}
+	r.states = append(r.states, state)
+	r.messages = append(r.messages, msg)
+	return nil
+}
+
+// count returns the number of updates recorded.
+func (r *statusRecorder) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.states)
+}
+
+// agentText returns a pointer to an agent message with a text part.
+func agentText(text string) *protocol.Message {
+	msg := protocol.NewMessage(protocol.MessageRoleAgent, []protocol.Part{protocol.NewTextPart(text)})
+	return &msg
+}
+
+func TestStatusEmitter_Coalesces(t *testing.T) {
+	handle := &statusRecorder{}
+	emitter := NewStatusEmitter(handle, 10)
+	for _, token := range []string{"a", "b", "c", "d"} {
+		require.NoError(t, emitter.Update(protocol.TaskStateWorking, agentText(token)))
+	}
+	// The first update is sent at once, the others are merged.
+	assert.Equal(t, 1, handle.count())
+	require.Eventually(t, func() bool { return handle.count() == 2 }, time.Second, 5*time.Millisecond)
+
+	handle.mu.Lock()
+	assert.Equal(t, "a", handle.messages[0].Parts[0].(protocol.TextPart).Text)
+	require.Len(t, handle.messages[1].Parts, 1)
+	assert.Equal(t, "bcd", handle.messages[1].Parts[0].(protocol.TextPart).Text)
+	handle.mu.Unlock()
+	require.NoError(t, emitter.Close())
+	assert.Equal(t, 2, handle.count())
+}
+
+func TestStatusEmitter_StateChanges(t *testing.T) {
+	handle := &statusRecorder{}
+	emitter := NewStatusEmitter(handle, 1)
+	require.NoError(t, emitter.Update(protocol.TaskStateWorking, agentText("a")))
+	require.NoError(t, emitter.Update(protocol.TaskStateWorking, agentText("b")))
+	require.NoError(t, emitter.Update(protocol.TaskStateInputRequired, agentText("c")))
+	require.NoError(t, emitter.Update(protocol.TaskStateCompleted, nil))
+	assert.Equal(t, []protocol.TaskState{
+		protocol.TaskStateWorking, protocol.TaskStateWorking,
+		protocol.TaskStateInputRequired, protocol.TaskStateCompleted,
+	}, handle.states)
+	require.NoError(t, emitter.Close())
+	assert.Equal(t, 4, handle.count())
+
+	// Closed emitters send updates at once.
+	require.NoError(t, emitter.Update(protocol.TaskStateWorking, nil))
+	require.NoError(t, emitter.Update(protocol.TaskStateWorking, nil))
+	assert.Equal(t, 6, handle.count())
+}
+
+func TestStatusEmitter_DelayedError(t *testing.T) {
+	handle := &statusRecorder{}
+	emitter := NewStatusEmitter(handle, 50)
+	require.NoError(t, emitter.Update(protocol.TaskStateWorking, nil))
+	handle.mu.Lock()
+	handle.err = errors.New("task not found")
+	handle.mu.Unlock()
+	require.NoError(t, emitter.Update(protocol.TaskStateWorking, agentText("late")))
+	time.Sleep(50 * time.Millisecond)
+	assert.Error(t, emitter.Update(protocol.TaskStateWorking, nil))
+}
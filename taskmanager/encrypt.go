@@ -0,0 +1,414 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// dataKeySize is the size of the AES-256 data keys generated by
+// StaticKeyProvider.
+const dataKeySize = 32
+
+// encryptedMetadataKey is the metadata key under which an encrypted task
+// store keeps the encrypted artifacts and history of a task.
+const encryptedMetadataKey = "encryptedPayload"
+
+// KeyProvider supplies the data keys encrypting task data at rest, following
+// the envelope encryption scheme of key management services: the data of a
+// task is encrypted with AES-GCM under a data key of the task, which is stored
+// along with the data encrypted by a key encryption key that never leaves the
+// provider.
+type KeyProvider interface {
+	// GenerateDataKey returns a new AES data key and its encrypted form.
+	GenerateDataKey(ctx context.Context) (plaintext, encrypted []byte, err error)
+	// DecryptDataKey returns the data key of its encrypted form.
+	DecryptDataKey(ctx context.Context, encrypted []byte) ([]byte, error)
+}
+
+// StaticKeyProvider is a KeyProvider encrypting the data keys with a fixed
+// key, e.g. read from a secret file. A KeyProvider backed by a key management
+// service keeps the key encryption key out of the process.
+type StaticKeyProvider struct {
+	aead cipher.AEAD
+}
+
+// NewStaticKeyProvider creates a provider encrypting the data keys with key,
+// of 16, 24 or 32 bytes.
+func NewStaticKeyProvider(key []byte) (*StaticKeyProvider, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	return &StaticKeyProvider{aead: aead}, nil
+}
+
+// GenerateDataKey implements KeyProvider.
+func (p *StaticKeyProvider) GenerateDataKey(ctx context.Context) ([]byte, []byte, error) {
+	key := make([]byte, dataKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, nil, err
+	}
+	nonce := make([]byte, p.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, err
+	}
+	return key, p.aead.Seal(nonce, nonce, key, nil), nil
+}
+
+// DecryptDataKey implements KeyProvider.
+func (p *StaticKeyProvider) DecryptDataKey(ctx context.Context, encrypted []byte) ([]byte, error) {
+	size := p.aead.NonceSize()
+	if len(encrypted) < size {
+		return nil, errors.New("encrypted data key is too short")
+	}
+	return p.aead.Open(nil, encrypted[:size], encrypted[size:], nil)
+}
+
+// newGCM returns the AES-GCM cipher of key.
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// envelope is a payload encrypted under a data key, with the data key
+// encrypted by a KeyProvider.
+type envelope struct {
+	Key   []byte `json:"key"`
+	Nonce []byte `json:"nonce"`
+	Data  []byte `json:"data"`
+}
+
+// DataKey is a data key of a KeyProvider. The data of a task is encrypted
+// under the same data key, so that its updates do not call the provider.
+type DataKey struct {
+	// Plaintext is the AES key.
+	Plaintext []byte
+	// Encrypted is the key encrypted by the provider, stored with the data.
+	Encrypted []byte
+}
+
+// GenerateDataKey returns a new data key of keys.
+func GenerateDataKey(ctx context.Context, keys KeyProvider) (DataKey, error) {
+	plaintext, encrypted, err := keys.GenerateDataKey(ctx)
+	if err != nil {
+		return DataKey{}, fmt.Errorf("failed to generate data key: %w", err)
+	}
+	return DataKey{Plaintext: plaintext, Encrypted: encrypted}, nil
+}
+
+// DecryptDataKey returns the data key of keys encrypted as encrypted.
+func DecryptDataKey(ctx context.Context, keys KeyProvider, encrypted []byte) (DataKey, error) {
+	plaintext, err := keys.DecryptDataKey(ctx, encrypted)
+	if err != nil {
+		return DataKey{}, fmt.Errorf("failed to decrypt data key: %w", err)
+	}
+	return DataKey{Plaintext: plaintext, Encrypted: encrypted}, nil
+}
+
+// Seal encrypts plaintext under k, bound to aad, such as the ID of the task
+// it belongs to, and returns it with k encrypted, as decrypted by Open.
+func (k DataKey) Seal(aad string, plaintext []byte) ([]byte, error) {
+	env, err := k.seal(aad, plaintext)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(env)
+}
+
+// Open decrypts sealed, encrypted by Seal under k with aad.
+func (k DataKey) Open(aad string, sealed []byte) ([]byte, error) {
+	var env envelope
+	if err := json.Unmarshal(sealed, &env); err != nil {
+		return nil, fmt.Errorf("invalid encrypted data: %w", err)
+	}
+	return k.open(&env, aad)
+}
+
+// IsSealed reports whether data was encrypted by DataKey.Seal.
+func IsSealed(data []byte) bool {
+	var env envelope
+	return json.Unmarshal(data, &env) == nil && len(env.Key) > 0 && len(env.Nonce) > 0
+}
+
+// seal encrypts plaintext under k, bound to aad.
+func (k DataKey) seal(aad string, plaintext []byte) (*envelope, error) {
+	aead, err := newGCM(k.Plaintext)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return &envelope{Key: k.Encrypted, Nonce: nonce, Data: aead.Seal(nil, nonce, plaintext, []byte(aad))}, nil
+}
+
+// open decrypts the payload of e, sealed under k with aad.
+func (k DataKey) open(e *envelope, aad string) ([]byte, error) {
+	aead, err := newGCM(k.Plaintext)
+	if err != nil {
+		return nil, err
+	}
+	if len(e.Nonce) != aead.NonceSize() {
+		return nil, errors.New("invalid nonce")
+	}
+	return aead.Open(nil, e.Nonce, e.Data, []byte(aad))
+}
+
+// seal encrypts plaintext under a new data key of keys, bound to aad.
+func seal(ctx context.Context, keys KeyProvider, aad string, plaintext []byte) (*envelope, error) {
+	key, err := GenerateDataKey(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+	return key.seal(aad, plaintext)
+}
+
+// open decrypts the payload of e, sealed with aad, with its data key of keys.
+func (e *envelope) open(ctx context.Context, keys KeyProvider, aad string) ([]byte, error) {
+	key, err := DecryptDataKey(ctx, keys, e.Key)
+	if err != nil {
+		return nil, err
+	}
+	return key.open(e, aad)
+}
+
+// encryptedPayload is the task data encrypted by an encrypted task store.
+type encryptedPayload struct {
+	Artifacts []protocol.Artifact `json:"artifacts,omitempty"`
+	History   []protocol.Message  `json:"history,omitempty"`
+}
+
+// EncryptTask returns a copy of task with its artifacts and history encrypted
+// under key, kept under a metadata key of the task, as decrypted by
+// DecryptTask.
+func EncryptTask(task *protocol.Task, key DataKey) (*protocol.Task, error) {
+	encrypted := *task
+	if len(task.Artifacts) == 0 && len(task.History) == 0 {
+		return &encrypted, nil
+	}
+	data, err := json.Marshal(encryptedPayload{Artifacts: task.Artifacts, History: task.History})
+	if err != nil {
+		return nil, err
+	}
+	env, err := key.seal(task.ID, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt task %s: %w", task.ID, err)
+	}
+	encrypted.Artifacts, encrypted.History = nil, nil
+	encrypted.Metadata = make(map[string]interface{}, len(task.Metadata)+1)
+	for k, v := range task.Metadata {
+		encrypted.Metadata[k] = v
+	}
+	encrypted.Metadata[encryptedMetadataKey] = env
+	return &encrypted, nil
+}
+
+// TaskDataKey returns the data key of keys encrypting task, as read from a
+// store, and false if it is not encrypted.
+func TaskDataKey(ctx context.Context, keys KeyProvider, task *protocol.Task) (DataKey, bool, error) {
+	env, ok, err := taskEnvelope(task)
+	if !ok || err != nil {
+		return DataKey{}, ok, err
+	}
+	key, err := DecryptDataKey(ctx, keys, env.Key)
+	if err != nil {
+		return DataKey{}, true, fmt.Errorf("failed to decrypt task %s: %w", task.ID, err)
+	}
+	return key, true, nil
+}
+
+// DecryptTask restores the artifacts and history of task, as read from a
+// store, encrypted by EncryptTask under key. Tasks that are not encrypted are
+// left as they are.
+func DecryptTask(task *protocol.Task, key DataKey) error {
+	env, ok, err := taskEnvelope(task)
+	if !ok || err != nil {
+		return err
+	}
+	data, err := key.open(env, task.ID)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt task %s: %w", task.ID, err)
+	}
+	var payload encryptedPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return err
+	}
+	metadata := make(map[string]interface{}, len(task.Metadata)-1)
+	for k, v := range task.Metadata {
+		if k != encryptedMetadataKey {
+			metadata[k] = v
+		}
+	}
+	if len(metadata) == 0 {
+		metadata = nil
+	}
+	task.Metadata = metadata
+	task.Artifacts, task.History = payload.Artifacts, payload.History
+	return nil
+}
+
+// taskEnvelope returns the envelope of the encrypted data of task and whether
+// it has any.
+func taskEnvelope(task *protocol.Task) (*envelope, bool, error) {
+	raw, ok := task.Metadata[encryptedMetadataKey]
+	if !ok {
+		return nil, false, nil
+	}
+	if env, ok := raw.(*envelope); ok {
+		return env, true, nil
+	}
+	// Stores keeping the tasks as JSON return the envelope decoded.
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, true, err
+	}
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, true, fmt.Errorf("invalid encrypted payload of task %s: %w", task.ID, err)
+	}
+	return &env, true, nil
+}
+
+// encryptedTaskStore encrypts the artifacts and history of the tasks of the
+// store it wraps.
+type encryptedTaskStore struct {
+	store TaskStore
+	keys  KeyProvider
+}
+
+// NewEncryptedTaskStore wraps store, e.g. a database, so that the artifacts
+// and history of the tasks it stores are encrypted under a data key of keys
+// per task. They are kept under a metadata key of the stored tasks, and
+// decrypted transparently by the reads of the returned store; the other
+// fields of the tasks, such as their status, stay readable by store to filter
+// them. Task managers keeping the history of tasks apart from their store,
+// such as the MemoryTaskManager, do not store it.
+func NewEncryptedTaskStore(store TaskStore, keys KeyProvider) TaskStore {
+	return &encryptedTaskStore{store: store, keys: keys}
+}
+
+// decrypt restores the artifacts and history of task, as read from the store,
+// and returns its data key, if it is encrypted.
+func (s *encryptedTaskStore) decrypt(ctx context.Context, task *protocol.Task) (DataKey, bool, error) {
+	key, ok, err := TaskDataKey(ctx, s.keys, task)
+	if !ok || err != nil {
+		return key, ok, err
+	}
+	return key, true, DecryptTask(task, key)
+}
+
+// Get implements TaskStore.
+func (s *encryptedTaskStore) Get(ctx context.Context, taskID string) (*protocol.Task, error) {
+	task, err := s.store.Get(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := s.decrypt(ctx, task); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// Put implements TaskStore.
+func (s *encryptedTaskStore) Put(ctx context.Context, task *protocol.Task) error {
+	if len(task.Artifacts) == 0 && len(task.History) == 0 {
+		return s.store.Put(ctx, task)
+	}
+	key, err := GenerateDataKey(ctx, s.keys)
+	if err != nil {
+		return err
+	}
+	encrypted, err := EncryptTask(task, key)
+	if err != nil {
+		return err
+	}
+	return s.store.Put(ctx, encrypted)
+}
+
+// Create implements TaskCreator, creating the task atomically if store
+// implements it.
+func (s *encryptedTaskStore) Create(ctx context.Context, task *protocol.Task) error {
+	if len(task.Artifacts) == 0 && len(task.History) == 0 {
+		return CreateTask(ctx, s.store, task)
+	}
+	key, err := GenerateDataKey(ctx, s.keys)
+	if err != nil {
+		return err
+	}
+	encrypted, err := EncryptTask(task, key)
+	if err != nil {
+		return err
+	}
+	return CreateTask(ctx, s.store, encrypted)
+}
+
+// List implements TaskStore.
+func (s *encryptedTaskStore) List(ctx context.Context, filter TaskFilter) ([]protocol.Task, error) {
+	tasks, err := s.store.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	for i := range tasks {
+		if _, _, err := s.decrypt(ctx, &tasks[i]); err != nil {
+			return nil, err
+		}
+	}
+	return tasks, nil
+}
+
+// Delete implements TaskStore.
+func (s *encryptedTaskStore) Delete(ctx context.Context, taskID string) error {
+	return s.store.Delete(ctx, taskID)
+}
+
+// Update implements TaskStore. The task stays encrypted under its data key.
+func (s *encryptedTaskStore) Update(
+	ctx context.Context,
+	taskID string,
+	update func(task *protocol.Task) error,
+) (*protocol.Task, error) {
+	var updated *protocol.Task
+	_, err := s.store.Update(ctx, taskID, func(task *protocol.Task) error {
+		key, encrypted, err := s.decrypt(ctx, task)
+		if err != nil {
+			return err
+		}
+		if err := update(task); err != nil {
+			return err
+		}
+		if !encrypted && (len(task.Artifacts) > 0 || len(task.History) > 0) {
+			if key, err = GenerateDataKey(ctx, s.keys); err != nil {
+				return err
+			}
+		}
+		encryptedTask, err := EncryptTask(task, key)
+		if err != nil {
+			return err
+		}
+		updatedCopy := *task
+		updated = &updatedCopy
+		*task = *encryptedTask
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
@@ -0,0 +1,116 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// artifactProcessor adds an artifact holding the text of the task ID.
+var artifactProcessor = &mockProcessor{
+	processFunc: func(ctx context.Context, taskID string, msg protocol.Message, handle TaskHandle) error {
+		return handle.AddArtifact(protocol.Artifact{
+			Parts: []protocol.Part{protocol.NewTextPart(strings.Repeat("secret-"+taskID, 50))},
+		})
+	},
+}
+
+// countingKeys is a KeyProvider counting the data keys generated.
+type countingKeys struct {
+	KeyProvider
+	generated int
+}
+
+// GenerateDataKey implements KeyProvider.
+func (k *countingKeys) GenerateDataKey(ctx context.Context) ([]byte, []byte, error) {
+	k.generated++
+	return k.KeyProvider.GenerateDataKey(ctx)
+}
+
+func TestEncryptedTaskStore(t *testing.T) {
+	static, err := NewStaticKeyProvider(bytes.Repeat([]byte{7}, 32))
+	require.NoError(t, err)
+	keys := &countingKeys{KeyProvider: static}
+	inner := &mapTaskStore{tasks: make(map[string]protocol.Task)}
+	tm, err := NewMemoryTaskManager(artifactProcessor, WithTaskStore(NewEncryptedTaskStore(inner, keys)))
+	require.NoError(t, err)
+	ctx := context.Background()
+	_, err = tm.OnSendTask(ctx, tenantParams("t1"))
+	require.NoError(t, err)
+	require.NoError(t, tm.AddArtifact("t1", protocol.Artifact{
+		Index: 1,
+		Parts: []protocol.Part{protocol.NewTextPart("more")},
+	}))
+	assert.Equal(t, 1, keys.generated, "the updates of a task reuse its data key")
+
+	stored, err := inner.Get(ctx, "t1")
+	require.NoError(t, err)
+	assert.Empty(t, stored.Artifacts, "artifacts are not stored in clear")
+	data, err := json.Marshal(stored)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "secret-t1")
+	assert.NotEmpty(t, stored.Status.State, "the status is stored in clear")
+
+	task, err := tm.OnGetTask(ctx, protocol.TaskQueryParams{ID: "t1"})
+	require.NoError(t, err)
+	require.Len(t, task.Artifacts, 2)
+	assert.Equal(t, strings.Repeat("secret-t1", 50), artifactText(t, task.Artifacts[0]))
+	assert.NotContains(t, task.Metadata, encryptedMetadataKey)
+
+	// The payloads are bound to their task.
+	var decoded protocol.Task
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	decoded.ID = "t2"
+	require.NoError(t, inner.Put(ctx, &decoded))
+	_, err = tm.OnGetTask(ctx, protocol.TaskQueryParams{ID: "t2"})
+	assert.ErrorContains(t, err, "failed to decrypt task t2")
+
+	other, err := NewStaticKeyProvider(bytes.Repeat([]byte{8}, 32))
+	require.NoError(t, err)
+	_, err = NewEncryptedTaskStore(inner, other).Get(ctx, "t1")
+	assert.Error(t, err, "the data key cannot be decrypted with another key")
+}
+
+func TestMemoryTaskManager_EncryptedTaskSpill(t *testing.T) {
+	keys, err := NewStaticKeyProvider(bytes.Repeat([]byte{7}, 16))
+	require.NoError(t, err)
+	dir := t.TempDir()
+	tm, err := NewMemoryTaskManager(artifactProcessor,
+		WithTaskSpill(TaskSpillConfig{Dir: dir, MaxResidentBytes: 100, Keys: keys}))
+	require.NoError(t, err)
+	defer tm.Close()
+	ctx := context.Background()
+	for _, id := range []string{"t1", "t2"} {
+		_, err := tm.OnSendTask(ctx, tenantParams(id))
+		require.NoError(t, err)
+	}
+	require.Positive(t, tm.TaskSpillStats().Spills)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*", "*"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "secret-")
+	}
+	task, err := tm.OnGetTask(ctx, protocol.TaskQueryParams{ID: "t1"})
+	require.NoError(t, err)
+	require.Len(t, task.Artifacts, 1)
+	assert.Equal(t, strings.Repeat("secret-t1", 50), artifactText(t, task.Artifacts[0]))
+}
@@ -9,6 +9,7 @@ package taskmanager
 
 import (
 	"fmt"
+	"time"
 
 	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
 	"trpc.group/trpc-go/trpc-a2a-go/protocol"
@@ -19,6 +20,20 @@ const (
 	ErrCodeTaskNotFound                  int = -32001 // Custom server error code range.
 	ErrCodeTaskFinal                     int = -32002
 	ErrCodePushNotificationNotConfigured int = -32003
+	ErrCodeSubscriptionLimit             int = -32004
+	ErrCodeTokenBudgetExceeded           int = -32005
+	ErrCodeDeadlineBudgetExhausted       int = -32006
+	ErrCodeTaskNotRequeueable            int = -32007
+	ErrCodeEventNotReplayable            int = -32008
+	ErrCodeTaskGraphNotFound             int = -32009
+	ErrCodeInvalidTaskGraph              int = -32010
+	ErrCodeTenantRequired                int = -32011
+	ErrCodeTenantQuotaExceeded           int = -32012
+	ErrCodeQuotaExceeded                 int = -32013
+	ErrCodePayloadTooLarge               int = -32014
+	ErrCodeInvalidStateTransition        int = -32015
+	ErrCodeTaskExists                    int = -32016
+	ErrCodeTaskVersionConflict           int = -32017
 )
 
 // ErrTaskNotFound creates a JSON-RPC error for task not found.
@@ -52,3 +67,160 @@ func ErrPushNotificationNotConfigured(taskID string) *jsonrpc.Error {
 		Data:    fmt.Sprintf("Task '%s' does not have push notifications configured.", taskID),
 	}
 }
+
+// ErrSubscriptionLimitExceeded creates a JSON-RPC error for a subscription rejected
+// because the per-task or per-caller limit was reached.
+// Exported function.
+func ErrSubscriptionLimitExceeded(taskID, scope string, limit int) *jsonrpc.Error {
+	return &jsonrpc.Error{
+		Code:    ErrCodeSubscriptionLimit,
+		Message: "Subscription limit exceeded",
+		Data:    fmt.Sprintf("Task '%s' already has the maximum of %d subscriptions per %s.", taskID, limit, scope),
+	}
+}
+
+// ErrTokenBudgetExceeded creates a JSON-RPC error for a message rejected because
+// its tokens would take the task over its token budget.
+// Exported function.
+func ErrTokenBudgetExceeded(taskID string, limit, used, requested int) *jsonrpc.Error {
+	return &jsonrpc.Error{
+		Code:    ErrCodeTokenBudgetExceeded,
+		Message: "Token budget exceeded",
+		Data: fmt.Sprintf("Task '%s' has used %d of its %d tokens and cannot accept %d more.",
+			taskID, used, limit, requested),
+	}
+}
+
+// ErrDeadlineBudgetExhausted creates a JSON-RPC error for a task rejected because
+// the remaining deadline budget of its originator is too short to complete it.
+// Exported function.
+func ErrDeadlineBudgetExhausted(taskID string, remaining, required time.Duration) *jsonrpc.Error {
+	return &jsonrpc.Error{
+		Code:    ErrCodeDeadlineBudgetExhausted,
+		Message: "Deadline budget exhausted",
+		Data: fmt.Sprintf("Task '%s' has %v left of its deadline budget, less than the required %v.",
+			taskID, remaining, required),
+	}
+}
+
+// ErrTaskNotRequeueable creates a JSON-RPC error for a requeue of a task that
+// did not fail.
+// Exported function.
+func ErrTaskNotRequeueable(taskID string, state protocol.TaskState) *jsonrpc.Error {
+	return &jsonrpc.Error{
+		Code:    ErrCodeTaskNotRequeueable,
+		Message: "Task cannot be requeued",
+		Data:    fmt.Sprintf("Task '%s' is in state '%s', only failed tasks can be requeued.", taskID, state),
+	}
+}
+
+// ErrEventNotReplayable creates a JSON-RPC error for a resubscription asking to
+// replay the events after an event that is not in the event history of the task.
+// Exported function.
+func ErrEventNotReplayable(taskID, eventID string) *jsonrpc.Error {
+	return &jsonrpc.Error{
+		Code:    ErrCodeEventNotReplayable,
+		Message: "Events cannot be replayed",
+		Data: fmt.Sprintf("Event '%s' of task '%s' is unknown or no longer kept, get the task instead.",
+			eventID, taskID),
+	}
+}
+
+// ErrTaskGraphNotFound creates a JSON-RPC error for task graph not found.
+// Exported function.
+func ErrTaskGraphNotFound(graphID string) *jsonrpc.Error {
+	return &jsonrpc.Error{
+		Code:    ErrCodeTaskGraphNotFound,
+		Message: "Task graph not found",
+		Data:    fmt.Sprintf("Task graph with ID '%s' was not found.", graphID),
+	}
+}
+
+// ErrInvalidTaskGraph creates a JSON-RPC error for a task graph that cannot be
+// scheduled, such as one with a dependency cycle.
+// Exported function.
+func ErrInvalidTaskGraph(graphID, reason string) *jsonrpc.Error {
+	return &jsonrpc.Error{
+		Code:    ErrCodeInvalidTaskGraph,
+		Message: "Invalid task graph",
+		Data:    fmt.Sprintf("Task graph '%s' cannot be scheduled: %s.", graphID, reason),
+	}
+}
+
+// ErrTenantRequired creates a JSON-RPC error for a request whose tenant cannot
+// be determined by a TenantTaskManager.
+// Exported function.
+func ErrTenantRequired(reason string) *jsonrpc.Error {
+	return &jsonrpc.Error{
+		Code:    ErrCodeTenantRequired,
+		Message: "Tenant required",
+		Data:    reason,
+	}
+}
+
+// ErrTenantQuotaExceeded creates a JSON-RPC error for a task rejected because
+// its tenant has too many active tasks.
+// Exported function.
+func ErrTenantQuotaExceeded(tenant string, maxActive int) *jsonrpc.Error {
+	return &jsonrpc.Error{
+		Code:    ErrCodeTenantQuotaExceeded,
+		Message: "Tenant quota exceeded",
+		Data:    fmt.Sprintf("Tenant '%s' already has %d active tasks.", tenant, maxActive),
+	}
+}
+
+// ErrQuotaExceeded creates a JSON-RPC error for a task rejected because its
+// principal used up its quota of resource.
+// Exported function.
+func ErrQuotaExceeded(principal, resource string) *jsonrpc.Error {
+	return &jsonrpc.Error{
+		Code:    ErrCodeQuotaExceeded,
+		Message: "Quota exceeded",
+		Data:    fmt.Sprintf("Principal '%s' exceeded its quota of %s.", principal, resource),
+	}
+}
+
+// ErrPayloadTooLarge creates a JSON-RPC error for a payload, such as a request
+// body, a message part or an artifact, larger than the configured limit.
+// Exported function.
+func ErrPayloadTooLarge(payload string, limit int64) *jsonrpc.Error {
+	return &jsonrpc.Error{
+		Code:    ErrCodePayloadTooLarge,
+		Message: "Payload too large",
+		Data:    fmt.Sprintf("%s exceeds the limit of %d bytes.", payload, limit),
+	}
+}
+
+// ErrInvalidStateTransition creates a JSON-RPC error for a task status update
+// that the state machine of the task manager does not allow.
+// Exported function.
+func ErrInvalidStateTransition(taskID string, from, to protocol.TaskState) *jsonrpc.Error {
+	return &jsonrpc.Error{
+		Code:    ErrCodeInvalidStateTransition,
+		Message: "Invalid task state transition",
+		Data:    fmt.Sprintf("Task '%s' cannot move from state '%s' to state '%s'.", taskID, from, to),
+	}
+}
+
+// ErrTaskExists creates a JSON-RPC error for a task imported, or otherwise
+// created, with the ID of an existing task.
+// Exported function.
+func ErrTaskExists(taskID string) *jsonrpc.Error {
+	return &jsonrpc.Error{
+		Code:    ErrCodeTaskExists,
+		Message: "Task already exists",
+		Data:    fmt.Sprintf("Task with ID '%s' already exists.", taskID),
+	}
+}
+
+// ErrTaskVersionConflict creates a JSON-RPC error for a change of a task
+// expecting a version the task is no longer at, because it was changed
+// concurrently.
+// Exported function.
+func ErrTaskVersionConflict(taskID string, expected, actual uint64) *jsonrpc.Error {
+	return &jsonrpc.Error{
+		Code:    ErrCodeTaskVersionConflict,
+		Message: "Task version conflict",
+		Data:    fmt.Sprintf("Task '%s' is at version %d, not %d.", taskID, actual, expected),
+	}
+}
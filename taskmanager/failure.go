@@ -0,0 +1,92 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"errors"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// ProcessingError classifies an error returned by a TaskProcessor. Task
+// managers record its code and retryable flag in the protocol.TaskError of the
+// failed task. Processors may wrap it further.
+type ProcessingError struct {
+	// Code classifies the error.
+	Code protocol.TaskErrorCode
+	// Retryable tells whether sending the task again may succeed.
+	Retryable bool
+	// Err is the underlying error.
+	Err error
+}
+
+// NewProcessingError returns an error failing the task with code.
+func NewProcessingError(code protocol.TaskErrorCode, retryable bool, err error) *ProcessingError {
+	return &ProcessingError{Code: code, Retryable: retryable, Err: err}
+}
+
+// Error implements error.
+func (e *ProcessingError) Error() string {
+	if e.Err == nil {
+		return string(e.Code)
+	}
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *ProcessingError) Unwrap() error {
+	return e.Err
+}
+
+// TaskErrorFrom builds the structured error of a task failed with err. The code
+// and retryable flag come from the first ProcessingError or protocol.TaskError
+// in the chain of err; without one, deadline errors are retryable with
+// protocol.TaskErrorCodeDeadlineExceeded and others are not, with
+// protocol.TaskErrorCodeInternal. The messages of the wrapped errors are
+// recorded as causes.
+func TaskErrorFrom(err error) *protocol.TaskError {
+	if err == nil {
+		return nil
+	}
+	taskErr := &protocol.TaskError{Code: protocol.TaskErrorCodeInternal, Message: err.Error()}
+	var processingErr *ProcessingError
+	var remoteErr *protocol.TaskError
+	switch {
+	case errors.As(err, &processingErr):
+		taskErr.Code = processingErr.Code
+		taskErr.Retryable = processingErr.Retryable
+	case errors.As(err, &remoteErr):
+		taskErr.Code = remoteErr.Code
+		taskErr.Retryable = remoteErr.Retryable
+	case errors.Is(err, context.DeadlineExceeded):
+		taskErr.Code = protocol.TaskErrorCodeDeadlineExceeded
+		taskErr.Retryable = true
+	}
+	last := taskErr.Message
+	for cause := errors.Unwrap(err); cause != nil; cause = errors.Unwrap(cause) {
+		// Skip wrappers repeating the message of the error they wrap.
+		if msg := cause.Error(); msg != last {
+			taskErr.Causes = append(taskErr.Causes, msg)
+			last = msg
+		}
+	}
+	return taskErr
+}
+
+// FailedStatus returns the status of a task whose processor failed with err:
+// the failed state, a message with the text of err and the structured error.
+func FailedStatus(err error) protocol.TaskStatus {
+	return protocol.TaskStatus{
+		State: protocol.TaskStateFailed,
+		Message: &protocol.Message{
+			Role:  protocol.MessageRoleAgent,
+			Parts: []protocol.Part{protocol.NewTextPart(err.Error())},
+		},
+		Error: TaskErrorFrom(err),
+	}
+}
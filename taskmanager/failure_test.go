@@ -0,0 +1,61 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+func TestTaskErrorFrom(t *testing.T) {
+	assert.Nil(t, TaskErrorFrom(nil))
+
+	root := errors.New("connection refused")
+	err := fmt.Errorf("calling model: %w",
+		NewProcessingError(protocol.TaskErrorCodeUnavailable, true, fmt.Errorf("dial: %w", root)))
+	taskErr := TaskErrorFrom(err)
+	assert.Equal(t, protocol.TaskErrorCodeUnavailable, taskErr.Code)
+	assert.True(t, taskErr.Retryable)
+	assert.Equal(t, "calling model: dial: connection refused", taskErr.Message)
+	assert.Equal(t, []string{"dial: connection refused", "connection refused"}, taskErr.Causes)
+
+	taskErr = TaskErrorFrom(fmt.Errorf("model: %w", context.DeadlineExceeded))
+	assert.Equal(t, protocol.TaskErrorCodeDeadlineExceeded, taskErr.Code)
+	assert.True(t, taskErr.Retryable)
+
+	taskErr = TaskErrorFrom(errors.New("boom"))
+	assert.Equal(t, protocol.TaskErrorCodeInternal, taskErr.Code)
+	assert.False(t, taskErr.Retryable)
+	assert.Empty(t, taskErr.Causes)
+}
+
+func TestMemoryTaskManager_FailedTaskError(t *testing.T) {
+	processor := &mockProcessor{
+		processFunc: func(ctx context.Context, taskID string, msg protocol.Message, handle TaskHandle) error {
+			return NewProcessingError(protocol.TaskErrorCodeInvalidInput, false, errors.New("unsupported language"))
+		},
+	}
+	tm, err := NewMemoryTaskManager(processor)
+	require.NoError(t, err)
+	task, err := tm.OnSendTask(context.Background(), protocol.SendTaskParams{
+		ID:      "failing",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")}),
+	})
+	require.Error(t, err)
+	require.NotNil(t, task)
+	assert.Equal(t, protocol.TaskStateFailed, task.Status.State)
+	require.NotNil(t, task.Status.Error)
+	assert.Equal(t, protocol.TaskErrorCodeInvalidInput, task.Status.Error.Code)
+	assert.Equal(t, "unsupported language", task.Status.Message.Parts[0].(protocol.TextPart).Text)
+}
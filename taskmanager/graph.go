@@ -0,0 +1,289 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// TaskGraphRunner is implemented by task managers that run graphs of tasks
+// with dependencies, served as the tasks/graph/send and tasks/graph/get
+// extension methods.
+type TaskGraphRunner interface {
+	// OnSendTaskGraph schedules the tasks of the graph and returns its initial state.
+	OnSendTaskGraph(ctx context.Context, params protocol.SendTaskGraphParams) (*protocol.TaskGraph, error)
+	// OnGetTaskGraph returns the current state of the graph with the given ID.
+	OnGetTaskGraph(ctx context.Context, params protocol.TaskIDParams) (*protocol.TaskGraph, error)
+}
+
+// GraphScheduler runs task graphs on a TaskManager. A task starts, through
+// OnSendTask, once all the tasks it depends on completed. When a task fails or
+// is canceled, the tasks depending on it, directly or not, are canceled
+// without being run, with a protocol.TaskErrorCodeDependencyFailed error.
+// Graphs are kept in memory. It is safe for concurrent use.
+type GraphScheduler struct {
+	tm TaskManager
+
+	mu     sync.Mutex
+	graphs map[string]*taskGraph
+}
+
+// NewGraphScheduler creates a scheduler running the tasks of graphs on tm.
+func NewGraphScheduler(tm TaskManager) *GraphScheduler {
+	return &GraphScheduler{tm: tm, graphs: make(map[string]*taskGraph)}
+}
+
+// taskGraph is a graph being run. Its nodes are guarded by the mutex of the scheduler.
+type taskGraph struct {
+	id       string
+	metadata map[string]interface{}
+	order    []string // Task IDs in submission order.
+	nodes    map[string]*graphNode
+}
+
+// graphNode is a task of a graph.
+type graphNode struct {
+	params     protocol.TaskGraphNodeParams
+	dependents []string
+	waiting    int // Number of dependencies not completed yet.
+	state      protocol.TaskGraphNodeState
+	status     *protocol.TaskStatus
+	err        *protocol.TaskError
+}
+
+// Send validates the graph, starts the tasks without dependencies and returns
+// the initial state of the graph. Tasks run with the values of ctx but are
+// not canceled with it.
+func (s *GraphScheduler) Send(ctx context.Context, params protocol.SendTaskGraphParams) (*protocol.TaskGraph, error) {
+	g, err := newTaskGraph(params)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	if _, exists := s.graphs[g.id]; exists {
+		s.mu.Unlock()
+		return nil, ErrInvalidTaskGraph(g.id, "a graph with this ID already exists")
+	}
+	s.graphs[g.id] = g
+	var ready []string
+	for _, id := range g.order {
+		if node := g.nodes[id]; node.waiting == 0 {
+			node.state = protocol.TaskGraphNodeRunning
+			ready = append(ready, id)
+		}
+	}
+	snapshot := g.snapshot()
+	s.mu.Unlock()
+	log.Infof("Scheduled task graph %s with %d tasks", g.id, len(g.order))
+	runCtx := context.WithoutCancel(ctx)
+	for _, id := range ready {
+		go s.run(runCtx, g, id)
+	}
+	return snapshot, nil
+}
+
+// Get returns the current state of the graph.
+func (s *GraphScheduler) Get(graphID string) (*protocol.TaskGraph, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	g, ok := s.graphs[graphID]
+	if !ok {
+		return nil, ErrTaskGraphNotFound(graphID)
+	}
+	return g.snapshot(), nil
+}
+
+// run sends the task and starts the tasks it unblocks.
+func (s *GraphScheduler) run(ctx context.Context, g *taskGraph, id string) {
+	task, err := s.tm.OnSendTask(ctx, g.nodes[id].params.Task)
+	s.mu.Lock()
+	ready := g.finish(id, task, err)
+	s.mu.Unlock()
+	for _, next := range ready {
+		go s.run(ctx, g, next)
+	}
+}
+
+// newTaskGraph builds the graph of params, checking that its task IDs are
+// unique, its dependencies known and acyclic.
+func newTaskGraph(params protocol.SendTaskGraphParams) (*taskGraph, error) {
+	if params.ID == "" {
+		return nil, ErrInvalidTaskGraph(params.ID, "the graph ID is empty")
+	}
+	if len(params.Tasks) == 0 {
+		return nil, ErrInvalidTaskGraph(params.ID, "the graph has no tasks")
+	}
+	g := &taskGraph{
+		id:       params.ID,
+		metadata: params.Metadata,
+		order:    make([]string, 0, len(params.Tasks)),
+		nodes:    make(map[string]*graphNode, len(params.Tasks)),
+	}
+	for _, node := range params.Tasks {
+		id := node.Task.ID
+		if id == "" {
+			return nil, ErrInvalidTaskGraph(params.ID, "a task ID is empty")
+		}
+		if _, exists := g.nodes[id]; exists {
+			return nil, ErrInvalidTaskGraph(params.ID, fmt.Sprintf("task %s is listed twice", id))
+		}
+		g.order = append(g.order, id)
+		g.nodes[id] = &graphNode{params: node, state: protocol.TaskGraphNodePending}
+	}
+	for _, id := range g.order {
+		node := g.nodes[id]
+		seen := make(map[string]bool, len(node.params.DependsOn))
+		for _, dep := range node.params.DependsOn {
+			upstream, ok := g.nodes[dep]
+			if !ok {
+				return nil, ErrInvalidTaskGraph(params.ID, fmt.Sprintf("task %s depends on unknown task %s", id, dep))
+			}
+			if seen[dep] {
+				continue
+			}
+			seen[dep] = true
+			upstream.dependents = append(upstream.dependents, id)
+			node.waiting++
+		}
+	}
+	if g.hasCycle() {
+		return nil, ErrInvalidTaskGraph(params.ID, "the dependencies form a cycle")
+	}
+	return g, nil
+}
+
+// hasCycle reports whether the dependencies form a cycle, by removing the
+// tasks without dependencies until none is left.
+func (g *taskGraph) hasCycle() bool {
+	waiting := make(map[string]int, len(g.nodes))
+	var queue []string
+	for _, id := range g.order {
+		waiting[id] = g.nodes[id].waiting
+		if waiting[id] == 0 {
+			queue = append(queue, id)
+		}
+	}
+	removed := 0
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		removed++
+		for _, next := range g.nodes[id].dependents {
+			if waiting[next]--; waiting[next] == 0 {
+				queue = append(queue, next)
+			}
+		}
+	}
+	return removed < len(g.nodes)
+}
+
+// finish records the outcome of the task and returns the tasks it unblocked,
+// marked running. The caller must hold the scheduler mutex.
+func (g *taskGraph) finish(id string, task *protocol.Task, err error) []string {
+	node := g.nodes[id]
+	if task == nil {
+		node.state = protocol.TaskGraphNodeFailed
+		node.err = TaskErrorFrom(err)
+		if node.err == nil {
+			node.err = &protocol.TaskError{Code: protocol.TaskErrorCodeInternal, Message: "task returned no result"}
+		}
+		g.cancelDependents(id)
+		return nil
+	}
+	status := task.Status
+	node.status = &status
+	switch status.State {
+	case protocol.TaskStateCompleted:
+		node.state = protocol.TaskGraphNodeCompleted
+	case protocol.TaskStateFailed:
+		node.state = protocol.TaskGraphNodeFailed
+		node.err = status.Error
+		if node.err == nil {
+			node.err = TaskErrorFrom(err)
+		}
+		g.cancelDependents(id)
+		return nil
+	case protocol.TaskStateCanceled:
+		node.state = protocol.TaskGraphNodeCanceled
+		node.err = status.Error
+		if node.err == nil {
+			node.err = protocol.NewCancelTaskError(NormalizeCancelReason(status.CancelReason))
+		}
+		g.cancelDependents(id)
+		return nil
+	default:
+		node.state = protocol.TaskGraphNodeBlocked
+		return nil
+	}
+	var ready []string
+	for _, next := range node.dependents {
+		dependent := g.nodes[next]
+		if dependent.waiting--; dependent.waiting == 0 && dependent.state == protocol.TaskGraphNodePending {
+			dependent.state = protocol.TaskGraphNodeRunning
+			ready = append(ready, next)
+		}
+	}
+	return ready
+}
+
+// cancelDependents cancels the pending tasks depending on the task, directly
+// or not. The caller must hold the scheduler mutex.
+func (g *taskGraph) cancelDependents(id string) {
+	for _, next := range g.nodes[id].dependents {
+		dependent := g.nodes[next]
+		if dependent.state != protocol.TaskGraphNodePending {
+			continue
+		}
+		dependent.state = protocol.TaskGraphNodeCanceled
+		dependent.err = &protocol.TaskError{
+			Code:    protocol.TaskErrorCodeDependencyFailed,
+			Message: fmt.Sprintf("task %s did not complete", id),
+		}
+		g.cancelDependents(next)
+	}
+}
+
+// snapshot returns the state of the graph. The caller must hold the scheduler mutex.
+func (g *taskGraph) snapshot() *protocol.TaskGraph {
+	graph := &protocol.TaskGraph{
+		ID:       g.id,
+		Nodes:    make([]protocol.TaskGraphNode, 0, len(g.order)),
+		Metadata: g.metadata,
+	}
+	counts := make(map[protocol.TaskGraphNodeState]int)
+	for _, id := range g.order {
+		node := g.nodes[id]
+		counts[node.state]++
+		graph.Nodes = append(graph.Nodes, protocol.TaskGraphNode{
+			ID:        id,
+			DependsOn: node.params.DependsOn,
+			State:     node.state,
+			Status:    node.status,
+			Error:     node.err,
+		})
+	}
+	switch {
+	case counts[protocol.TaskGraphNodeRunning] > 0:
+		graph.State = protocol.TaskGraphRunning
+	case counts[protocol.TaskGraphNodeBlocked] > 0:
+		graph.State = protocol.TaskGraphBlocked
+	case counts[protocol.TaskGraphNodePending] > 0:
+		graph.State = protocol.TaskGraphRunning
+	case counts[protocol.TaskGraphNodeFailed] > 0:
+		graph.State = protocol.TaskGraphFailed
+	case counts[protocol.TaskGraphNodeCanceled] > 0:
+		graph.State = protocol.TaskGraphCanceled
+	default:
+		graph.State = protocol.TaskGraphCompleted
+	}
+	return graph
+}
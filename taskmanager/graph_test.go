@@ -0,0 +1,175 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// graphTask returns the params of a graph task depending on deps.
+func graphTask(id string, deps ...string) protocol.TaskGraphNodeParams {
+	return protocol.TaskGraphNodeParams{
+		Task: protocol.SendTaskParams{
+			ID: id,
+			Message: protocol.Message{
+				Role:  protocol.MessageRoleUser,
+				Parts: []protocol.Part{protocol.NewTextPart(id)},
+			},
+		},
+		DependsOn: deps,
+	}
+}
+
+// waitForGraph waits until the graph is no longer running.
+func waitForGraph(t *testing.T, tm *MemoryTaskManager, graphID string) *protocol.TaskGraph {
+	t.Helper()
+	var graph *protocol.TaskGraph
+	require.Eventually(t, func() bool {
+		var err error
+		graph, err = tm.OnGetTaskGraph(context.Background(), protocol.TaskIDParams{ID: graphID})
+		require.NoError(t, err)
+		return graph.State != protocol.TaskGraphRunning
+	}, 2*time.Second, 10*time.Millisecond)
+	return graph
+}
+
+func TestMemoryTaskManager_TaskGraph(t *testing.T) {
+	var mu sync.Mutex
+	var order []string
+	processor := &mockProcessor{
+		processFunc: func(ctx context.Context, taskID string, msg protocol.Message, handle TaskHandle) error {
+			mu.Lock()
+			order = append(order, taskID)
+			mu.Unlock()
+			return handle.UpdateStatus(protocol.TaskStateCompleted, nil)
+		},
+	}
+	tm, err := NewMemoryTaskManager(processor)
+	require.NoError(t, err)
+
+	graph, err := tm.OnSendTaskGraph(context.Background(), protocol.SendTaskGraphParams{
+		ID: "diamond",
+		Tasks: []protocol.TaskGraphNodeParams{
+			graphTask("d", "b", "c"),
+			graphTask("b", "a"),
+			graphTask("c", "a"),
+			graphTask("a"),
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, protocol.TaskGraphRunning, graph.State)
+	require.Len(t, graph.Nodes, 4)
+	assert.Equal(t, protocol.TaskGraphNodePending, graph.Nodes[0].State)
+	assert.Equal(t, protocol.TaskGraphNodeRunning, graph.Nodes[3].State)
+
+	graph = waitForGraph(t, tm, "diamond")
+	assert.Equal(t, protocol.TaskGraphCompleted, graph.State)
+	for _, node := range graph.Nodes {
+		assert.Equal(t, protocol.TaskGraphNodeCompleted, node.State, node.ID)
+		require.NotNil(t, node.Status)
+		assert.Equal(t, protocol.TaskStateCompleted, node.Status.State)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, order, 4)
+	assert.Equal(t, "a", order[0])
+	assert.Equal(t, "d", order[3])
+}
+
+func TestMemoryTaskManager_TaskGraphFailure(t *testing.T) {
+	processor := &mockProcessor{
+		processFunc: func(ctx context.Context, taskID string, msg protocol.Message, handle TaskHandle) error {
+			switch taskID {
+			case "fetch":
+				return NewProcessingError(protocol.TaskErrorCodeUnavailable, true, errors.New("source down"))
+			case "ask":
+				return handle.UpdateStatus(protocol.TaskStateInputRequired, nil)
+			}
+			return handle.UpdateStatus(protocol.TaskStateCompleted, nil)
+		},
+	}
+	tm, err := NewMemoryTaskManager(processor)
+	require.NoError(t, err)
+
+	_, err = tm.OnSendTaskGraph(context.Background(), protocol.SendTaskGraphParams{
+		ID: "failing",
+		Tasks: []protocol.TaskGraphNodeParams{
+			graphTask("fetch"),
+			graphTask("parse", "fetch"),
+			graphTask("report", "parse"),
+			graphTask("other"),
+		},
+	})
+	require.NoError(t, err)
+	graph := waitForGraph(t, tm, "failing")
+	assert.Equal(t, protocol.TaskGraphFailed, graph.State)
+
+	fetch, parse, report, other := graph.Nodes[0], graph.Nodes[1], graph.Nodes[2], graph.Nodes[3]
+	assert.Equal(t, protocol.TaskGraphNodeFailed, fetch.State)
+	require.NotNil(t, fetch.Error)
+	assert.Equal(t, protocol.TaskErrorCodeUnavailable, fetch.Error.Code)
+	for _, node := range []protocol.TaskGraphNode{parse, report} {
+		assert.Equal(t, protocol.TaskGraphNodeCanceled, node.State, node.ID)
+		require.NotNil(t, node.Error)
+		assert.Equal(t, protocol.TaskErrorCodeDependencyFailed, node.Error.Code)
+		assert.Nil(t, node.Status, "dependents must not run")
+	}
+	assert.Equal(t, protocol.TaskGraphNodeCompleted, other.State)
+
+	// A task requiring input blocks the tasks depending on it.
+	_, err = tm.OnSendTaskGraph(context.Background(), protocol.SendTaskGraphParams{
+		ID:    "blocked",
+		Tasks: []protocol.TaskGraphNodeParams{graphTask("ask"), graphTask("answer", "ask")},
+	})
+	require.NoError(t, err)
+	graph = waitForGraph(t, tm, "blocked")
+	assert.Equal(t, protocol.TaskGraphBlocked, graph.State)
+	assert.Equal(t, protocol.TaskGraphNodeBlocked, graph.Nodes[0].State)
+	assert.Equal(t, protocol.TaskGraphNodePending, graph.Nodes[1].State)
+}
+
+func TestMemoryTaskManager_TaskGraphValidation(t *testing.T) {
+	tm, err := NewMemoryTaskManager(&mockProcessor{})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	tests := map[string][]protocol.TaskGraphNodeParams{
+		"no tasks":       nil,
+		"duplicate task": {graphTask("a"), graphTask("a")},
+		"unknown dep":    {graphTask("a", "missing")},
+		"self dep":       {graphTask("a", "a")},
+		"cycle":          {graphTask("a", "c"), graphTask("b", "a"), graphTask("c", "b"), graphTask("d")},
+	}
+	for name, tasks := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := tm.OnSendTaskGraph(ctx, protocol.SendTaskGraphParams{ID: "g", Tasks: tasks})
+			var rpcErr *jsonrpc.Error
+			require.ErrorAs(t, err, &rpcErr)
+			assert.Equal(t, ErrCodeInvalidTaskGraph, rpcErr.Code)
+		})
+	}
+
+	_, err = tm.OnSendTaskGraph(ctx, protocol.SendTaskGraphParams{ID: "g", Tasks: []protocol.TaskGraphNodeParams{graphTask("a")}})
+	require.NoError(t, err)
+	_, err = tm.OnSendTaskGraph(ctx, protocol.SendTaskGraphParams{ID: "g", Tasks: []protocol.TaskGraphNodeParams{graphTask("b")}})
+	assert.Error(t, err, "graph IDs must be unique")
+
+	_, err = tm.OnGetTaskGraph(ctx, protocol.TaskIDParams{ID: "unknown"})
+	var rpcErr *jsonrpc.Error
+	require.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, ErrCodeTaskGraphNotFound, rpcErr.Code)
+}
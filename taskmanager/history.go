@@ -0,0 +1,176 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"fmt"
+
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// HistoryPolicy bounds the message history of tasks, so that the history of
+// long-running sessions does not grow without limit.
+type HistoryPolicy interface {
+	// Apply returns the history to keep of the history of taskID, oldest
+	// message first, e.g. its newest messages preceded by a summary of the
+	// others. It is called in the background after messages are added to
+	// the history, once at a time per task, so the history may exceed the
+	// policy until it returns. It must not modify history.
+	Apply(ctx context.Context, taskID string, history []protocol.Message) ([]protocol.Message, error)
+}
+
+// HistoryPolicyFunc is an adapter to allow the use of ordinary functions as
+// HistoryPolicy.
+type HistoryPolicyFunc func(ctx context.Context, taskID string, history []protocol.Message) ([]protocol.Message, error)
+
+// Apply implements HistoryPolicy.
+func (f HistoryPolicyFunc) Apply(
+	ctx context.Context,
+	taskID string,
+	history []protocol.Message,
+) ([]protocol.Message, error) {
+	return f(ctx, taskID, history)
+}
+
+// Summarizer returns a message summarizing messages, the oldest messages of
+// the history of taskID, e.g. written by an LLM. A summary kept in the
+// history is itself summarized with the messages following it.
+type Summarizer func(ctx context.Context, taskID string, messages []protocol.Message) (protocol.Message, error)
+
+// HistoryLimits is a HistoryPolicy keeping the newest messages of histories
+// within its limits.
+type HistoryLimits struct {
+	// MaxMessages is the maximum number of messages of a history, if positive.
+	MaxMessages int
+	// MaxBytes is the maximum size of the parts of the messages of a history,
+	// as counted by PartSize, if positive. The newest message is kept even if
+	// larger.
+	MaxBytes int64
+	// Summarize, if set, replaces the messages dropped by a summary of them,
+	// kept as the oldest message. To summarize less often, histories going
+	// over the limits are then reduced to half of them.
+	Summarize Summarizer
+}
+
+// Apply implements HistoryPolicy.
+func (l HistoryLimits) Apply(
+	ctx context.Context,
+	taskID string,
+	history []protocol.Message,
+) ([]protocol.Message, error) {
+	if l.within(history, l.MaxMessages, l.MaxBytes) {
+		return history, nil
+	}
+	if l.Summarize == nil {
+		return history[l.keptFrom(history, l.MaxMessages, l.MaxBytes):], nil
+	}
+	// Leave room for the summary, and for the messages to come.
+	maxMessages, maxBytes := (l.MaxMessages-1)/2, l.MaxBytes/2
+	if l.MaxMessages > 0 && maxMessages < 1 {
+		maxMessages = 1
+	}
+	start := l.keptFrom(history, maxMessages, maxBytes)
+	if start == 0 {
+		return history, nil
+	}
+	summary, err := l.Summarize(ctx, taskID, history[:start])
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize history of task %s: %w", taskID, err)
+	}
+	kept := make([]protocol.Message, 0, len(history)-start+1)
+	kept = append(kept, summary)
+	return append(kept, history[start:]...), nil
+}
+
+// within reports whether history is within maxMessages and maxBytes.
+func (l HistoryLimits) within(history []protocol.Message, maxMessages int, maxBytes int64) bool {
+	return (maxMessages <= 0 || len(history) <= maxMessages) &&
+		(maxBytes <= 0 || historySize(history...) <= maxBytes)
+}
+
+// keptFrom returns the index of the oldest message of history kept within
+// maxMessages and maxBytes. The newest message is always kept.
+func (l HistoryLimits) keptFrom(history []protocol.Message, maxMessages int, maxBytes int64) int {
+	start := 0
+	if maxMessages > 0 && len(history) > maxMessages {
+		start = len(history) - maxMessages
+	}
+	if maxBytes > 0 {
+		size := historySize(history[start:]...)
+		for size > maxBytes && start < len(history)-1 {
+			size -= historySize(history[start])
+			start++
+		}
+	}
+	return start
+}
+
+// scheduleHistoryPolicy applies the history policy to the history of taskID
+// in the background, since summarizing may be slow. The runs of a task are
+// serialized, so that only the messages added while the policy runs follow
+// the history it returns; messages added meanwhile trigger another run.
+func (m *MemoryTaskManager) scheduleHistoryPolicy(taskID string) {
+	m.historyMu.Lock()
+	defer m.historyMu.Unlock()
+	if _, running := m.historyRuns[taskID]; running {
+		m.historyRuns[taskID] = true
+		return
+	}
+	if m.historyRuns == nil {
+		m.historyRuns = make(map[string]bool)
+	}
+	m.historyRuns[taskID] = false
+	go func() {
+		for {
+			m.applyHistoryPolicy(taskID)
+			m.historyMu.Lock()
+			if !m.historyRuns[taskID] {
+				delete(m.historyRuns, taskID)
+				m.historyMu.Unlock()
+				return
+			}
+			m.historyRuns[taskID] = false
+			m.historyMu.Unlock()
+		}
+	}()
+}
+
+// applyHistoryPolicy bounds the stored history of taskID with the history
+// policy. The policy runs outside MessagesMutex.
+func (m *MemoryTaskManager) applyHistoryPolicy(taskID string) {
+	m.MessagesMutex.RLock()
+	history, exists := m.Messages[taskID]
+	if !exists {
+		history, exists = m.readSpilledHistory(taskID)
+	}
+	history = append([]protocol.Message(nil), history...)
+	m.MessagesMutex.RUnlock()
+	if !exists {
+		return
+	}
+	kept, err := m.history.Apply(context.Background(), taskID, history)
+	if err != nil {
+		log.Warnf("Failed to apply the history policy to task %s: %v", taskID, err)
+		return
+	}
+	m.MessagesMutex.Lock()
+	defer m.MessagesMutex.Unlock()
+	m.restoreHistory(taskID)
+	current, exists := m.Messages[taskID]
+	if !exists || len(current) < len(history) {
+		return // The history was replaced meanwhile, e.g. by a snapshot.
+	}
+	updated := make([]protocol.Message, 0, len(kept)+len(current)-len(history))
+	updated = append(updated, kept...)
+	updated = append(updated, current[len(history):]...)
+	m.Messages[taskID] = updated
+	if m.spill != nil {
+		m.spill.grow(spillKey{taskID, spillHistory}, historySize(updated...)-historySize(current...))
+	}
+}
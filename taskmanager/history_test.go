@@ -0,0 +1,116 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// historyTexts returns the text of the first part of each message of history.
+func historyTexts(history []protocol.Message) []string {
+	texts := make([]string, len(history))
+	for i, msg := range history {
+		texts[i] = msg.Parts[0].(protocol.TextPart).Text
+	}
+	return texts
+}
+
+func TestHistoryLimits(t *testing.T) {
+	var history []protocol.Message
+	for i := 0; i < 10; i++ {
+		history = append(history, protocol.NewMessage(protocol.MessageRoleUser,
+			[]protocol.Part{protocol.NewTextPart(fmt.Sprintf("m%d", i))}))
+	}
+	ctx := context.Background()
+
+	kept, err := HistoryLimits{MaxMessages: 20}.Apply(ctx, "t", history)
+	require.NoError(t, err)
+	assert.Len(t, kept, 10)
+
+	kept, err = HistoryLimits{MaxMessages: 3}.Apply(ctx, "t", history)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"m7", "m8", "m9"}, historyTexts(kept))
+
+	kept, err = HistoryLimits{MaxBytes: 5}.Apply(ctx, "t", history)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"m8", "m9"}, historyTexts(kept))
+
+	var summarized []string
+	summarize := func(ctx context.Context, taskID string, messages []protocol.Message) (protocol.Message, error) {
+		summarized = historyTexts(messages)
+		return protocol.NewMessage(protocol.MessageRoleAgent,
+			[]protocol.Part{protocol.NewTextPart("summary of " + strings.Join(summarized, ","))}), nil
+	}
+	kept, err = HistoryLimits{MaxMessages: 5, Summarize: summarize}.Apply(ctx, "t", history)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"m0", "m1", "m2", "m3", "m4", "m5", "m6", "m7"}, summarized)
+	assert.Equal(t, []string{"summary of m0,m1,m2,m3,m4,m5,m6,m7", "m8", "m9"}, historyTexts(kept))
+
+	_, err = HistoryLimits{MaxMessages: 5, Summarize: func(
+		context.Context, string, []protocol.Message,
+	) (protocol.Message, error) {
+		return protocol.Message{}, errors.New("unavailable")
+	}}.Apply(ctx, "t", history)
+	assert.ErrorContains(t, err, "unavailable")
+}
+
+func TestMemoryTaskManager_HistoryPolicy(t *testing.T) {
+	var summaries atomic.Int32
+	policy := HistoryLimits{
+		MaxMessages: 4,
+		Summarize: func(ctx context.Context, taskID string, messages []protocol.Message) (protocol.Message, error) {
+			n := summaries.Add(1)
+			return protocol.NewMessage(protocol.MessageRoleAgent,
+				[]protocol.Part{protocol.NewTextPart(fmt.Sprintf("summary %d", n))}), nil
+		},
+	}
+	processor := &mockProcessor{processFunc: func(
+		ctx context.Context, taskID string, msg protocol.Message, handle TaskHandle,
+	) error {
+		for i := 0; i < 6; i++ {
+			msg := protocol.NewMessage(protocol.MessageRoleAgent,
+				[]protocol.Part{protocol.NewTextPart(fmt.Sprintf("step %d", i))})
+			if err := handle.UpdateStatus(protocol.TaskStateWorking, &msg); err != nil {
+				return err
+			}
+		}
+		return handle.UpdateStatus(protocol.TaskStateCompleted, nil)
+	}}
+	tm, err := NewMemoryTaskManager(processor, WithHistoryPolicy(policy))
+	require.NoError(t, err)
+
+	_, err = tm.OnSendTask(context.Background(), protocol.SendTaskParams{
+		ID:      "task-1",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("start")}),
+	})
+	require.NoError(t, err)
+	// The history is summarized in the background once over the limit, and
+	// the summary is followed by the newest messages.
+	historyLength := 0
+	var texts []string
+	require.Eventually(t, func() bool {
+		task, err := tm.OnGetTask(context.Background(), protocol.TaskQueryParams{ID: "task-1", HistoryLength: &historyLength})
+		require.NoError(t, err)
+		texts = historyTexts(task.History)
+		return len(texts) <= 4
+	}, time.Second, 5*time.Millisecond)
+	require.NotEmpty(t, texts)
+	assert.Regexp(t, "^summary ", texts[0])
+	assert.Equal(t, "step 5", texts[len(texts)-1])
+	assert.Positive(t, summaries.Load())
+}
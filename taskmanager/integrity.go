@@ -0,0 +1,91 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"sync"
+
+	"trpc.group/trpc-go/trpc-a2a-go/auth"
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// artifactChains holds the integrity record of the last chunk sent of each
+// artifact being streamed, by task ID and artifact index.
+type artifactChains struct {
+	mu     sync.Mutex
+	chunks map[string]map[int]auth.ArtifactIntegrity
+}
+
+// SignArtifacts returns a transformer, for WithEventTransformers, recording
+// an integrity record computed by signer under auth.MetadataKeyIntegrity in
+// the metadata of every artifact chunk sent, so that clients can verify the
+// chunks on receipt, e.g. with client.ArtifactVerifier. Chunks without Append
+// start a new artifact. Register it after the transformers changing artifacts.
+func SignArtifacts(signer *auth.ArtifactSigner) EventTransformer {
+	chains := &artifactChains{chunks: make(map[string]map[int]auth.ArtifactIntegrity)}
+	return func(taskID string, event protocol.TaskEvent) protocol.TaskEvent {
+		switch e := event.(type) {
+		case protocol.TaskArtifactUpdateEvent:
+			integrity, err := chains.next(signer, taskID, e.Artifact)
+			if err != nil {
+				log.Errorf("Failed to sign chunk of artifact %d of task %s: %v", e.Artifact.Index, taskID, err)
+				return event
+			}
+			metadata := make(map[string]interface{}, len(e.Artifact.Metadata)+1)
+			for k, v := range e.Artifact.Metadata {
+				metadata[k] = v
+			}
+			metadata[auth.MetadataKeyIntegrity] = integrity
+			e.Artifact.Metadata = metadata
+			return e
+		case protocol.TaskStatusUpdateEvent:
+			if e.Final {
+				chains.forget(taskID)
+			}
+		}
+		return event
+	}
+}
+
+// next returns the integrity record of chunk and records it as the last
+// chunk of its artifact, until its last chunk.
+func (c *artifactChains) next(
+	signer *auth.ArtifactSigner,
+	taskID string,
+	chunk protocol.Artifact,
+) (auth.ArtifactIntegrity, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var prev *auth.ArtifactIntegrity
+	if last, ok := c.chunks[taskID][chunk.Index]; ok && chunk.Append != nil && *chunk.Append {
+		prev = &last
+	}
+	integrity, err := signer.Integrity(taskID, chunk, prev)
+	if err != nil {
+		return auth.ArtifactIntegrity{}, err
+	}
+	if chunk.LastChunk != nil && *chunk.LastChunk {
+		delete(c.chunks[taskID], chunk.Index)
+		if len(c.chunks[taskID]) == 0 {
+			delete(c.chunks, taskID)
+		}
+		return integrity, nil
+	}
+	if c.chunks[taskID] == nil {
+		c.chunks[taskID] = make(map[int]auth.ArtifactIntegrity)
+	}
+	c.chunks[taskID][chunk.Index] = integrity
+	return integrity, nil
+}
+
+// forget drops the chains of the artifacts of taskID.
+func (c *artifactChains) forget(taskID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.chunks, taskID)
+}
@@ -0,0 +1,66 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"crypto/ed25519"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/auth"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+func TestSignArtifacts(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	processor := &mockProcessor{
+		processFunc: func(ctx context.Context, taskID string, msg protocol.Message, handle TaskHandle) error {
+			w := NewArtifactWriter(handle, 0)
+			for _, chunk := range []string{"one ", "two ", "three"} {
+				if _, err := io.WriteString(w, chunk); err != nil {
+					return err
+				}
+			}
+			if err := w.Close(); err != nil {
+				return err
+			}
+			return handle.UpdateStatus(protocol.TaskStateCompleted, nil)
+		},
+	}
+	tm, err := NewMemoryTaskManager(processor,
+		WithEventTransformers(SignArtifacts(auth.NewArtifactSigner("k1", privateKey))))
+	require.NoError(t, err)
+	events, err := tm.OnSendTaskSubscribe(context.Background(), tenantParams("signed"))
+	require.NoError(t, err)
+
+	keys := auth.StaticProvenanceKeys{"k1": publicKey}
+	var (
+		prev   *auth.ArtifactIntegrity
+		chunks int
+	)
+	for event := range events {
+		if e, ok := event.(protocol.TaskArtifactUpdateEvent); ok {
+			integrity, err := auth.ArtifactIntegrityFromMetadata(e.Artifact.Metadata)
+			require.NoError(t, err)
+			require.NoError(t, auth.VerifyArtifactIntegrity(context.Background(), keys, "signed", e.Artifact, integrity, prev))
+			prev = integrity
+			chunks++
+		}
+		if event.IsFinal() {
+			break
+		}
+	}
+	assert.Equal(t, 4, chunks)
+	task, err := tm.OnGetTask(context.Background(), protocol.TaskQueryParams{ID: "signed"})
+	require.NoError(t, err)
+	assert.NotContains(t, task.Artifacts[0].Metadata, auth.MetadataKeyIntegrity)
+}
@@ -0,0 +1,263 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"sync"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+)
+
+var (
+	// ErrLeaseHeld is returned when acquiring a lease held by another owner.
+	ErrLeaseHeld = errors.New("lease held by another owner")
+	// ErrLeaseLost is the cause of the cancellation of the context of a lease
+	// that could not be renewed before it expired, and is returned by the
+	// changes fenced by a lease no longer held.
+	ErrLeaseLost = errors.New("lease lost")
+)
+
+const (
+	// defaultLeaseTTL is the default time to live of leases.
+	defaultLeaseTTL = 30 * time.Second
+)
+
+// Leaser grants exclusive leases on names, such as task IDs, to owners, such
+// as the replicas of a task manager sharing a distributed store. A lease
+// expires unless its owner renews it before its time to live elapses, so
+// that another owner can take over when the owner dies.
+type Leaser interface {
+	// Acquire acquires the lease of name for owner for ttl, reporting false
+	// if another owner holds it. The owner holding the lease renews it.
+	Acquire(ctx context.Context, name, owner string, ttl time.Duration) (bool, error)
+	// Renew extends the lease of name held by owner for ttl, reporting false
+	// if owner no longer holds it.
+	Renew(ctx context.Context, name, owner string, ttl time.Duration) (bool, error)
+	// Release releases the lease of name if owner holds it.
+	Release(ctx context.Context, name, owner string) error
+}
+
+// LeaseConfig configures the leases held by a replica.
+type LeaseConfig struct {
+	// Owner identifies the replica. Defaults to the host name, process ID
+	// and a random suffix.
+	Owner string
+	// TTL is the time after which the leases of a dead replica expire, 30s
+	// by default.
+	TTL time.Duration
+	// Heartbeat is the interval of the renewals of the leases, a third of
+	// TTL by default.
+	Heartbeat time.Duration
+}
+
+// WithDefaults returns cfg with the defaults set.
+func (cfg LeaseConfig) WithDefaults() LeaseConfig {
+	if cfg.Owner == "" {
+		host, _ := os.Hostname()
+		cfg.Owner = fmt.Sprintf("%s-%d-%06x", host, os.Getpid(), rand.Intn(1<<24))
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = defaultLeaseTTL
+	}
+	if cfg.Heartbeat <= 0 || cfg.Heartbeat >= cfg.TTL {
+		cfg.Heartbeat = cfg.TTL / 3
+	}
+	return cfg
+}
+
+// Lease is a lease held by a replica, renewed at every heartbeat until it is
+// released.
+type Lease struct {
+	leaser Leaser
+	name   string
+	cfg    LeaseConfig
+
+	ctx      context.Context
+	cancel   context.CancelCauseFunc
+	released chan struct{}
+	done     chan struct{}
+	once     sync.Once
+}
+
+// AcquireLease acquires the lease of name with leaser, returning
+// ErrLeaseHeld if another owner holds it. The context of the lease derives
+// from ctx, but the lease is renewed until released even after ctx is done.
+func AcquireLease(ctx context.Context, leaser Leaser, name string, cfg LeaseConfig) (*Lease, error) {
+	cfg = cfg.WithDefaults()
+	ok, err := leaser.Acquire(ctx, name, cfg.Owner, cfg.TTL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire lease %s: %w", name, err)
+	}
+	if !ok {
+		return nil, ErrLeaseHeld
+	}
+	leaseCtx, cancel := context.WithCancelCause(ctx)
+	l := &Lease{
+		leaser:   leaser,
+		name:     name,
+		cfg:      cfg,
+		ctx:      leaseCtx,
+		cancel:   cancel,
+		released: make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go l.renew(context.WithoutCancel(ctx))
+	return l, nil
+}
+
+// renew renews the lease at every heartbeat until it is released or lost.
+// Renewals failing are retried until the lease expires.
+func (l *Lease) renew(ctx context.Context) {
+	defer close(l.done)
+	ticker := time.NewTicker(l.cfg.Heartbeat)
+	defer ticker.Stop()
+	renewed := time.Now()
+	for {
+		select {
+		case <-l.released:
+			return
+		case <-ticker.C:
+		}
+		ok, err := l.leaser.Renew(ctx, l.name, l.cfg.Owner, l.cfg.TTL)
+		switch {
+		case err == nil && ok:
+			renewed = time.Now()
+			continue
+		case err != nil && time.Since(renewed)+l.cfg.Heartbeat < l.cfg.TTL:
+			log.Warnf("Failed to renew lease %s, retrying: %v", l.name, err)
+			continue
+		}
+		log.Errorf("Lost lease %s of %s: %v", l.name, l.cfg.Owner, err)
+		l.cancel(ErrLeaseLost)
+		return
+	}
+}
+
+// Name returns the name of the lease.
+func (l *Lease) Name() string {
+	return l.name
+}
+
+// Owner returns the owner of the lease.
+func (l *Lease) Owner() string {
+	return l.cfg.Owner
+}
+
+// Context returns the context of the lease, canceled with the cause
+// ErrLeaseLost when the lease is lost.
+func (l *Lease) Context() context.Context {
+	return l.ctx
+}
+
+// Held reports whether the lease is still held, as far as its renewals tell.
+func (l *Lease) Held() bool {
+	return !errors.Is(context.Cause(l.ctx), ErrLeaseLost)
+}
+
+// Release stops renewing the lease and releases it.
+func (l *Lease) Release(ctx context.Context) error {
+	var err error
+	l.once.Do(func() {
+		close(l.released)
+		<-l.done
+		l.cancel(nil)
+		if l.Held() {
+			err = l.leaser.Release(ctx, l.name, l.cfg.Owner)
+		}
+	})
+	return err
+}
+
+// RunLeader elects a leader among the replicas sharing leaser through the
+// lease of name: while the replica holds it, lead runs with the context of
+// the lease, canceled if the leadership is lost. lead is run again each time
+// the replica wins the election, until ctx is done.
+func RunLeader(
+	ctx context.Context,
+	leaser Leaser,
+	name string,
+	cfg LeaseConfig,
+	lead func(ctx context.Context),
+) error {
+	cfg = cfg.WithDefaults()
+	for {
+		lease, err := AcquireLease(ctx, leaser, name, cfg)
+		switch {
+		case err == nil:
+			log.Infof("Replica %s leads %s", cfg.Owner, name)
+			lead(lease.Context())
+			if err := lease.Release(context.WithoutCancel(ctx)); err != nil {
+				log.Warnf("Failed to release lease %s: %v", name, err)
+			}
+		case !errors.Is(err, ErrLeaseHeld) && ctx.Err() == nil:
+			log.Warnf("Failed to run for %s: %v", name, err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(cfg.Heartbeat):
+		}
+	}
+}
+
+// MemoryLeaser is a Leaser for the owners of a single process, such as
+// tests. It is safe for concurrent use.
+type MemoryLeaser struct {
+	mu     sync.Mutex
+	leases map[string]memoryLease
+}
+
+// memoryLease is a lease of a MemoryLeaser.
+type memoryLease struct {
+	owner   string
+	expires time.Time
+}
+
+// NewMemoryLeaser creates a leaser without leases.
+func NewMemoryLeaser() *MemoryLeaser {
+	return &MemoryLeaser{leases: make(map[string]memoryLease)}
+}
+
+// Acquire implements Leaser.
+func (l *MemoryLeaser) Acquire(ctx context.Context, name, owner string, ttl time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	if lease, ok := l.leases[name]; ok && lease.owner != owner && now.Before(lease.expires) {
+		return false, nil
+	}
+	l.leases[name] = memoryLease{owner: owner, expires: now.Add(ttl)}
+	return true, nil
+}
+
+// Renew implements Leaser.
+func (l *MemoryLeaser) Renew(ctx context.Context, name, owner string, ttl time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	if lease, ok := l.leases[name]; !ok || lease.owner != owner || !now.Before(lease.expires) {
+		return false, nil
+	}
+	l.leases[name] = memoryLease{owner: owner, expires: now.Add(ttl)}
+	return true, nil
+}
+
+// Release implements Leaser.
+func (l *MemoryLeaser) Release(ctx context.Context, name, owner string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if lease, ok := l.leases[name]; ok && lease.owner == owner {
+		delete(l.leases, name)
+	}
+	return nil
+}
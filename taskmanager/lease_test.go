@@ -0,0 +1,81 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcquireLease(t *testing.T) {
+	leaser := NewMemoryLeaser()
+	ctx := context.Background()
+	cfg := LeaseConfig{Owner: "replica-1", TTL: 60 * time.Millisecond}
+	lease, err := AcquireLease(ctx, leaser, "task-1", cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "replica-1", lease.Owner())
+
+	// The lease is renewed past its TTL while held.
+	time.Sleep(100 * time.Millisecond)
+	_, err = AcquireLease(ctx, leaser, "task-1", LeaseConfig{Owner: "replica-2"})
+	assert.ErrorIs(t, err, ErrLeaseHeld)
+	assert.True(t, lease.Held())
+	require.NoError(t, lease.Release(ctx))
+	other, err := AcquireLease(ctx, leaser, "task-1", LeaseConfig{Owner: "replica-2", TTL: time.Minute})
+	require.NoError(t, err)
+
+	// A lease taken over once expired is lost by its former owner.
+	lease, err = AcquireLease(ctx, leaser, "task-2", cfg)
+	require.NoError(t, err)
+	require.NoError(t, leaser.Release(ctx, "task-2", "replica-1"))
+	_, err = leaser.Acquire(ctx, "task-2", "replica-2", time.Minute)
+	require.NoError(t, err)
+	<-lease.Context().Done()
+	assert.True(t, errors.Is(context.Cause(lease.Context()), ErrLeaseLost))
+	assert.False(t, lease.Held())
+	require.NoError(t, lease.Release(ctx))
+	ok, err := leaser.Renew(ctx, "task-2", "replica-2", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok, "releasing a lost lease leaves the new owner")
+	require.NoError(t, other.Release(ctx))
+}
+
+func TestRunLeader(t *testing.T) {
+	leaser := NewMemoryLeaser()
+	leaders := make(chan string, 2)
+	run := func(ctx context.Context, owner string) chan error {
+		done := make(chan error)
+		go func() {
+			done <- RunLeader(ctx, leaser, "leader", LeaseConfig{Owner: owner, TTL: 60 * time.Millisecond},
+				func(ctx context.Context) {
+					leaders <- owner
+					<-ctx.Done()
+				})
+		}()
+		return done
+	}
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	done1 := run(ctx1, "replica-1")
+	assert.Equal(t, "replica-1", <-leaders)
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	defer cancel2()
+	done2 := run(ctx2, "replica-2")
+
+	// The other replica leads once the leader stops.
+	time.Sleep(50 * time.Millisecond)
+	assert.Empty(t, leaders)
+	cancel1()
+	require.NoError(t, <-done1)
+	assert.Equal(t, "replica-2", <-leaders)
+	cancel2()
+	require.NoError(t, <-done2)
+}
@@ -0,0 +1,71 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// CheckArtifactSize returns ErrPayloadTooLarge if artifact, as added to
+// taskID, is larger than limit. A non-positive limit accepts every artifact.
+func CheckArtifactSize(taskID string, artifact protocol.Artifact, limit int64) error {
+	if limit > 0 && ArtifactSize(artifact) > limit {
+		return ErrPayloadTooLarge(fmt.Sprintf("Artifact %d of task %s", artifact.Index, taskID), limit)
+	}
+	return nil
+}
+
+// ArtifactSize returns the total size in bytes of the parts of artifact.
+func ArtifactSize(artifact protocol.Artifact) int64 {
+	var size int64
+	for _, part := range artifact.Parts {
+		size += PartSize(part)
+	}
+	return size
+}
+
+// PartSize returns the size in bytes of the content of part: the text, the
+// decoded file bytes or the JSON encoding of the data.
+func PartSize(part protocol.Part) int64 {
+	switch p := part.(type) {
+	case protocol.TextPart:
+		return int64(len(p.Text))
+	case *protocol.TextPart:
+		return int64(len(p.Text))
+	case protocol.FilePart:
+		return fileSize(p.File)
+	case *protocol.FilePart:
+		return fileSize(p.File)
+	case protocol.DataPart:
+		return dataSize(p.Data)
+	case *protocol.DataPart:
+		return dataSize(p.Data)
+	}
+	return 0
+}
+
+// fileSize returns the size of the inline bytes of file.
+func fileSize(file protocol.FileContent) int64 {
+	if file.Bytes == nil {
+		return 0
+	}
+	return int64(base64.RawStdEncoding.DecodedLen(len(strings.TrimRight(*file.Bytes, "="))))
+}
+
+// dataSize returns the size of the JSON encoding of data.
+func dataSize(data interface{}) int64 {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return 0
+	}
+	return int64(len(encoded))
+}
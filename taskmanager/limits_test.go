@@ -0,0 +1,48 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+func TestCheckArtifactSize(t *testing.T) {
+	artifact := protocol.Artifact{Index: 2, Parts: []protocol.Part{protocol.NewTextPart("12345")}}
+	assert.NoError(t, CheckArtifactSize("t", artifact, 0))
+	assert.NoError(t, CheckArtifactSize("t", artifact, 5))
+	rpcErr := requireRPCCode(t, CheckArtifactSize("t", artifact, 4), ErrCodePayloadTooLarge)
+	assert.Equal(t, "Artifact 2 of task t exceeds the limit of 4 bytes.", rpcErr.Data)
+}
+
+func TestMemoryTaskManager_MaxArtifactSize(t *testing.T) {
+	var addErr error
+	processor := &mockProcessor{
+		processFunc: func(ctx context.Context, taskID string, msg protocol.Message, handle TaskHandle) error {
+			addErr = handle.AddArtifact(protocol.Artifact{
+				Parts: []protocol.Part{protocol.NewTextPart(strings.Repeat("x", 11))},
+			})
+			return handle.AddArtifact(protocol.Artifact{
+				Parts: []protocol.Part{protocol.NewTextPart("small")},
+			})
+		},
+	}
+	tm, err := NewMemoryTaskManager(processor, WithMaxArtifactSize(10))
+	require.NoError(t, err)
+
+	task, err := tm.OnSendTask(context.Background(), tenantParams("task-1"))
+	require.NoError(t, err)
+	requireRPCCode(t, addErr, ErrCodePayloadTooLarge)
+	require.Len(t, task.Artifacts, 1)
+	assert.Equal(t, "small", task.Artifacts[0].Parts[0].(protocol.TextPart).Text)
+}
@@ -11,14 +11,20 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"hash/fnv"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	"trpc.group/trpc-go/trpc-a2a-go/audit"
 	"trpc.group/trpc-go/trpc-a2a-go/log"
 	"trpc.group/trpc-go/trpc-a2a-go/protocol"
 )
 
+// eventLockShards is the number of locks serializing the events of tasks.
+const eventLockShards = 64
+
 // MemoryTaskManager provides a concrete, memory-based implementation of the
 // TaskManager interface. It manages tasks, messages, and subscribers in memory.
 // It requires a TaskProcessor to handle the actual agent logic.
@@ -26,7 +32,7 @@ import (
 type MemoryTaskManager struct {
 	// Processor is the agent logic processor.
 	Processor TaskProcessor
-	// Tasks is a map of task IDs to tasks.
+	// Tasks is a map of task IDs to tasks, unused with WithTaskStore.
 	Tasks map[string]*protocol.Task
 	// TasksMutex is a mutex for the Tasks map.
 	TasksMutex sync.RWMutex
@@ -46,21 +52,129 @@ type MemoryTaskManager struct {
 	PushNotifications map[string]protocol.PushNotificationConfig
 	// PushNotificationsMutex is a mutex for the PushNotifications map.
 	PushNotificationsMutex sync.RWMutex
+
+	// cancelCauses is a map of task IDs to the cancellation functions of
+	// Contexts taking a cause, a *CancelError carrying the cancellation
+	// reason. Guarded by ContextsMutex.
+	cancelCauses map[string]context.CancelCauseFunc
+	// subscriptions enforces subscription limits. Guarded by SubMutex for
+	// consistency with the Subscribers map.
+	subscriptions *SubscriptionLimiter
+	// usage counts the tokens exchanged for tasks. Task usage is guarded by TasksMutex.
+	usage *UsageMeter
+	// slowConsumers delivers events to subscribers that read too slowly.
+	slowConsumers *SlowConsumerHandler
+	// retry determines whether failed tasks are processed again.
+	retry RetryPolicy
+	// graphs runs the task graphs sent through OnSendTaskGraph.
+	graphs *GraphScheduler
+	// quotas accounts the usage of principals and enforces their quotas, if set.
+	quotas *UsageAccountant
+	// maxArtifactBytes bounds the size of artifacts, if positive.
+	maxArtifactBytes int64
+	// spill moves artifacts and histories to disk beyond its memory budget,
+	// if set. Spilled artifacts are removed from Tasks and spilled histories
+	// from Messages.
+	spill *taskSpiller
+	// auditLog records state transitions and cancellations, if set.
+	auditLog *audit.Logger
+	// events keeps the events of tasks for replay, if set. Events are
+	// recorded under the event lock of their task.
+	events EventHistory
+	// eventLocks serialize the numbering and delivery of the events of the
+	// tasks hashed to them, so that subscribers receive them in ID order
+	// without holding SubMutex while they are recorded.
+	eventLocks [eventLockShards]sync.Mutex
+	// eventIDs numbers the events of tasks if there is no event history.
+	eventIDs *eventSequencer
+	// push delivers the final status events of tasks to their push
+	// notification URLs, if set.
+	push *pushDeliverer
+	// disconnect decides what happens to tasks whose request ends before them.
+	disconnect DisconnectPolicy
+	// states validates the status updates of processors and runs the
+	// transition hooks.
+	states *StateMachine
+	// store stores the tasks, by default in Tasks. It is watch, wrapping the
+	// store of the options.
+	store TaskStore
+	// watch streams the changes of the tasks to their watchers.
+	watch *WatchableTaskStore
+	// transformers rewrite the events sent to subscribers and push URLs.
+	transformers []EventTransformer
+	// history bounds the histories of the tasks, if set.
+	history HistoryPolicy
+	// historyMu guards historyRuns.
+	historyMu sync.Mutex
+	// historyRuns records the tasks whose history the policy is applied to,
+	// and whether messages were added since the run started.
+	historyRuns map[string]bool
+	// recovery is applied on creation to the tasks left working in the
+	// store, if set.
+	recovery *RecoveryPolicy
 }
 
 // NewMemoryTaskManager creates a new instance with the provided TaskProcessor.
-func NewMemoryTaskManager(processor TaskProcessor) (*MemoryTaskManager, error) {
+func NewMemoryTaskManager(processor TaskProcessor, opts ...MemoryTaskManagerOption) (*MemoryTaskManager, error) {
 	if processor == nil {
 		return nil, errors.New("task processor cannot be nil")
 	}
-	return &MemoryTaskManager{
+	m := &MemoryTaskManager{
 		Processor:         processor,
 		Tasks:             make(map[string]*protocol.Task),
 		Messages:          make(map[string][]protocol.Message),
 		Subscribers:       make(map[string][]chan<- protocol.TaskEvent),
 		Contexts:          make(map[string]context.CancelFunc),
+		cancelCauses:      make(map[string]context.CancelCauseFunc),
 		PushNotifications: make(map[string]protocol.PushNotificationConfig),
-	}, nil
+		subscriptions:     NewSubscriptionLimiter(SubscriptionLimits{}),
+		usage:             NewUsageMeter(TokenAccounting{}),
+		slowConsumers:     NewSlowConsumerHandler(SlowConsumerConfig{}),
+		eventIDs:          newEventSequencer(),
+		states:            NewStateMachine(),
+	}
+	m.graphs = NewGraphScheduler(m)
+	m.store = memoryTaskStore{m: m}
+	for _, opt := range opts {
+		opt(m)
+	}
+	if _, ok := m.store.(memoryTaskStore); !ok && m.spill != nil {
+		return nil, errors.New("task spill cannot be used with a task store")
+	}
+	m.watch = NewWatchableTaskStore(m.store)
+	m.store = m.watch
+	if m.push != nil {
+		if err := m.restorePushConfigs(context.Background()); err != nil {
+			return nil, err
+		}
+	}
+	if m.recovery != nil {
+		recovered, err := m.RecoverTasks(context.Background(), *m.recovery)
+		if err != nil {
+			return nil, fmt.Errorf("failed to recover tasks: %w", err)
+		}
+		if recovered > 0 {
+			log.Infof("Recovered %d tasks interrupted by a restart with policy %s", recovered, *m.recovery)
+		}
+	}
+	return m, nil
+}
+
+// SubscriptionStats returns a snapshot of the subscription counters.
+func (m *MemoryTaskManager) SubscriptionStats() SubscriptionStats {
+	return m.subscriptions.Stats()
+}
+
+// SlowConsumerStats returns the counters of events queued, dropped and spilled
+// for slow subscribers and of slow subscribers disconnected.
+func (m *MemoryTaskManager) SlowConsumerStats() SlowConsumerStats {
+	return m.slowConsumers.Stats()
+}
+
+// SetSubscriptionLimits replaces the limits set by WithSubscriptionLimits, for
+// instance when the configuration is reloaded. Existing streams are kept.
+func (m *MemoryTaskManager) SetSubscriptionLimits(limits SubscriptionLimits) {
+	m.subscriptions.SetLimits(limits)
 }
 
 // processTaskWithProcessor handles the common task processing logic.
@@ -69,10 +183,23 @@ func (m *MemoryTaskManager) processTaskWithProcessor(
 	ctx context.Context,
 	taskID string,
 	message protocol.Message,
+) error {
+	return m.processTask(ctx, taskID, func(handle TaskHandle) error {
+		return m.runProcessor(ctx, taskID, message, handle)
+	})
+}
+
+// processTask sets the task working and runs process with its handle, failing
+// the task if process fails.
+func (m *MemoryTaskManager) processTask(
+	ctx context.Context,
+	taskID string,
+	process func(handle TaskHandle) error,
 ) error {
 	handle := &memoryTaskHandle{
-		taskID:  taskID,
-		manager: m,
+		taskID:    taskID,
+		manager:   m,
+		principal: m.quotas.Principal(ctx),
 	}
 
 	// Set initial status to Working before calling Process
@@ -82,14 +209,14 @@ func (m *MemoryTaskManager) processTaskWithProcessor(
 	}
 
 	// Delegate the actual processing to the injected processor
-	if err := m.Processor.Process(ctx, taskID, message, handle); err != nil {
+	if err := process(handle); err != nil {
 		log.Errorf("Processor failed for task %s: %v", taskID, err)
-		errMsg := &protocol.Message{
-			Role:  protocol.MessageRoleAgent,
-			Parts: []protocol.Part{protocol.NewTextPart(err.Error())},
+		if _, canceled := CancelReasonFromContext(ctx); canceled {
+			// The task was canceled through OnCancelTask, which already set the final state.
+			return err
 		}
 		// Log update error while still handling the processor error
-		if updateErr := m.UpdateTaskStatus(taskID, protocol.TaskStateFailed, errMsg); updateErr != nil {
+		if updateErr := m.setTaskStatus(taskID, FailedStatus(err), nil); updateErr != nil {
 			log.Errorf("Failed to update task %s status to failed: %v", taskID, updateErr)
 		}
 		return err
@@ -98,33 +225,59 @@ func (m *MemoryTaskManager) processTaskWithProcessor(
 	return nil
 }
 
+// runProcessor runs the processor on the task, running it again on retryable
+// failures according to the retry policy.
+func (m *MemoryTaskManager) runProcessor(
+	ctx context.Context,
+	taskID string,
+	message protocol.Message,
+	handle TaskHandle,
+) error {
+	return m.runAttempts(ctx, taskID, func() error {
+		return m.Processor.Process(ctx, taskID, message, handle)
+	})
+}
+
+// runAttempts runs process, the processing of a task, again on retryable
+// failures according to the retry policy.
+func (m *MemoryTaskManager) runAttempts(ctx context.Context, taskID string, process func() error) error {
+	start := time.Now()
+	defer func() {
+		m.quotas.RecordCompute(m.quotas.Principal(ctx), time.Since(start))
+	}()
+	return m.retry.Run(ctx, process, func(attempt protocol.TaskAttempt) error {
+		log.Warnf("Retrying task %s after attempt %d failed: %s", taskID, attempt.Attempt, attempt.Error.Message)
+		status, metadata := RetryStatus(attempt, m.retry.MaxAttempts)
+		return m.setTaskStatus(taskID, status, metadata)
+	})
+}
+
 // startTaskSubscribe starts processing a task in a goroutine that sends events to subscribers.
 // It returns immediately, with the processing continuing asynchronously.
 func (m *MemoryTaskManager) startTaskSubscribe(
 	ctx context.Context,
+	cancel context.CancelCauseFunc,
 	taskID string,
 	message protocol.Message,
 ) {
 	// Create a handle for the processor to interact with the task
 	handle := &memoryTaskHandle{
-		taskID:  taskID,
-		manager: m,
+		taskID:    taskID,
+		manager:   m,
+		principal: m.quotas.Principal(ctx),
 	}
 
 	log.Debugf("SSE Processor started for task %s", taskID)
 
 	// Start the processor in a goroutine
 	go func() {
+		defer cancel(nil)
 		var err error
-		if err = m.Processor.Process(ctx, taskID, message, handle); err != nil {
+		if err = m.runProcessor(ctx, taskID, message, handle); err != nil {
 			log.Errorf("Processor failed for task %s in subscribe: %v", taskID, err)
 			if ctx.Err() != context.Canceled {
 				// Only update to failed if not already cancelled
-				errMsg := &protocol.Message{
-					Role:  protocol.MessageRoleAgent,
-					Parts: []protocol.Part{protocol.NewTextPart(err.Error())},
-				}
-				if updateErr := m.UpdateTaskStatus(taskID, protocol.TaskStateFailed, errMsg); updateErr != nil {
+				if updateErr := m.setTaskStatus(taskID, FailedStatus(err), nil); updateErr != nil {
 					log.Errorf("Failed to update task %s status to failed: %v", taskID, updateErr)
 				}
 			}
@@ -133,6 +286,7 @@ func (m *MemoryTaskManager) startTaskSubscribe(
 		// Clean up the context regardless of how we finish
 		m.ContextsMutex.Lock()
 		delete(m.Contexts, taskID)
+		delete(m.cancelCauses, taskID)
 		m.ContextsMutex.Unlock()
 
 		log.Debugf("Processor finished for task %s in subscribe (Error: %v). Goroutine exiting.", taskID, err)
@@ -142,15 +296,29 @@ func (m *MemoryTaskManager) startTaskSubscribe(
 // OnSendTask handles the creation or retrieval of a task and initiates synchronous processing.
 // It implements the TaskManager interface.
 func (m *MemoryTaskManager) OnSendTask(ctx context.Context, params protocol.SendTaskParams) (*protocol.Task, error) {
-	_ = m.upsertTask(params)                  // Get or create task entry. Ignore return.
+	if err := m.quotas.Admit(ctx, params.ID); err != nil {
+		return nil, err
+	}
+	// Claim the processing of the task with a cancellable context, which
+	// outlives the request according to the disconnect policy, unless the
+	// task is being processed.
+	taskCtx, cancel, claimed := m.claimProcessing(ctx, params.ID, m.disconnect)
+	if claimed {
+		defer m.releaseProcessing(params.ID, cancel)
+	}
+	// Get or create task entry.
+	_, action, err := m.upsertTask(params, !claimed)
+	if err != nil {
+		return nil, err
+	}
 	m.storeMessage(params.ID, params.Message) // Store the initial user message.
-
-	// Create a cancellable context for this specific task processing
-	taskCtx, cancel := context.WithCancel(ctx)
-	defer cancel() // Ensure context is cancelled eventually
+	if action == SendAppend {
+		// The task is being processed: the message only joins its history.
+		return m.getTaskInternal(params.ID)
+	}
 
 	// Process the task
-	err := m.processTaskWithProcessor(taskCtx, params.ID, params.Message)
+	err = m.processTaskWithProcessor(taskCtx, params.ID, params.Message)
 
 	// Return the latest task state after processing
 	finalTask, e := m.getTaskInternal(params.ID)
@@ -162,6 +330,37 @@ func (m *MemoryTaskManager) OnSendTask(ctx context.Context, params protocol.Send
 	return finalTask, err
 }
 
+// PlanTask implements TaskPlanner. It checks the message against the token
+// budget of the task, which need not exist yet.
+func (m *MemoryTaskManager) PlanTask(ctx context.Context, params protocol.SendTaskParams) (*protocol.TaskPlan, error) {
+	var metadata map[string]interface{}
+	task, err := m.store.Get(ctx, params.ID)
+	switch {
+	case err == nil:
+		metadata = task.Metadata
+	case !IsTaskNotFound(err):
+		return nil, err
+	}
+	return m.usage.Plan(params.ID, metadata, params.Message.Parts)
+}
+
+// OnSendTaskGraph implements TaskGraphRunner. The tasks of the graph are
+// sent through OnSendTask once their dependencies completed.
+func (m *MemoryTaskManager) OnSendTaskGraph(
+	ctx context.Context,
+	params protocol.SendTaskGraphParams,
+) (*protocol.TaskGraph, error) {
+	return m.graphs.Send(ctx, params)
+}
+
+// OnGetTaskGraph implements TaskGraphRunner.
+func (m *MemoryTaskManager) OnGetTaskGraph(
+	ctx context.Context,
+	params protocol.TaskIDParams,
+) (*protocol.TaskGraph, error) {
+	return m.graphs.Get(params.ID)
+}
+
 // OnSendTaskSubscribe handles a tasks/sendSubscribe request with streaming response.
 // It creates or updates a task based on the parameters, then returns a channel for status updates.
 // The channel will receive events until the task completes, fails, is cancelled, or the context expires.
@@ -169,35 +368,61 @@ func (m *MemoryTaskManager) OnSendTaskSubscribe(
 	ctx context.Context,
 	params protocol.SendTaskParams,
 ) (<-chan protocol.TaskEvent, error) {
-	// Create a new task or update an existing one
-	task := m.upsertTask(params)
-	// Store the message that came with the request
-	m.storeMessage(params.ID, params.Message)
-
-	// Create event channel for this specific subscriber
+	// Create event channel for this specific subscriber.
+	// Subscribe first so a rejected subscription leaves the task untouched.
 	eventChan := make(chan protocol.TaskEvent, 10) // Buffered to prevent blocking sends
-	m.addSubscriber(params.ID, eventChan)
+	if err := m.addSubscriber(ctx, params.ID, eventChan); err != nil {
+		return nil, err
+	}
+	if err := m.quotas.Admit(ctx, params.ID); err != nil {
+		if m.removeSubscriber(params.ID, eventChan) {
+			close(eventChan)
+		}
+		return nil, err
+	}
 
-	// Create a cancellable context for the processor
-	processorCtx, cancel := context.WithCancel(ctx)
+	// Claim the processing of the task with a cancellable context for the
+	// processor, which outlives the request according to the disconnect
+	// policy, unless the task is being processed.
+	processorCtx, cancel, claimed := m.claimProcessing(ctx, params.ID, m.disconnect)
 
-	// Store the cancel function
-	m.ContextsMutex.Lock()
-	m.Contexts[params.ID] = cancel
-	m.ContextsMutex.Unlock()
+	// Create a new task or update an existing one
+	task, action, err := m.upsertTask(params, !claimed)
+	if err != nil {
+		if claimed {
+			m.releaseProcessing(params.ID, cancel)
+		}
+		if m.removeSubscriber(params.ID, eventChan) {
+			close(eventChan)
+		}
+		return nil, err
+	}
+	// Store the message that came with the request
+	m.storeMessage(params.ID, params.Message)
+	if action == SendAppend {
+		// The task is being processed: the message only joins its history,
+		// and the stream follows the current processing.
+		m.unsubscribeOnDone(ctx, params.ID, eventChan)
+		return eventChan, nil
+	}
 
 	// Set initial state if new (submitted -> working)
 	// This will generate the first event for subscribers
 	if task.Status.State == protocol.TaskStateSubmitted {
 		if err := m.UpdateTaskStatus(params.ID, protocol.TaskStateWorking, nil); err != nil {
-			m.removeSubscriber(params.ID, eventChan)
-			close(eventChan)
+			m.releaseProcessing(params.ID, cancel)
+			if m.removeSubscriber(params.ID, eventChan) {
+				close(eventChan)
+			}
 			return nil, err
 		}
 	}
 
 	// Start the processor in a goroutine
-	m.startTaskSubscribe(processorCtx, params.ID, params.Message)
+	m.startTaskSubscribe(processorCtx, cancel, params.ID, params.Message)
+	// Stop delivering events once the client disconnected; it can resubscribe
+	// to the task.
+	m.unsubscribeOnDone(ctx, params.ID, eventChan)
 
 	// Return the channel for events
 	return eventChan, nil
@@ -223,6 +448,9 @@ func (m *MemoryTaskManager) OnGetTask(ctx context.Context, params protocol.TaskQ
 		// historyLength == nil means "don't include history"
 		m.MessagesMutex.RLock()
 		messages, historyExists := m.Messages[params.ID]
+		if !historyExists {
+			messages, historyExists = m.readSpilledHistory(params.ID)
+		}
 		m.MessagesMutex.RUnlock()
 		if historyExists {
 			historyLen := len(messages)
@@ -248,45 +476,49 @@ func (m *MemoryTaskManager) OnGetTask(ctx context.Context, params protocol.TaskQ
 // OnCancelTask attempts to cancel an ongoing task.
 // It implements the TaskManager interface.
 func (m *MemoryTaskManager) OnCancelTask(ctx context.Context, params protocol.TaskIDParams) (*protocol.Task, error) {
-	m.TasksMutex.Lock()
-	task, exists := m.Tasks[params.ID]
-	if !exists {
-		m.TasksMutex.Unlock()
-		return nil, ErrTaskNotFound(params.ID)
-	}
-	m.TasksMutex.Unlock() // Release lock before potential context cancel / status update.
-	// Check if task is already in a final state (read lock again).
-	m.TasksMutex.RLock()
-	alreadyFinal := isFinalState(task.Status.State)
-	m.TasksMutex.RUnlock()
-	if alreadyFinal {
+	task, err := m.store.Get(ctx, params.ID)
+	if err != nil {
+		return nil, err
+	}
+	// A conflict takes precedence over the final state of the task.
+	if err := CheckTaskVersion(ctx, params.ID, task); err != nil {
+		return nil, err
+	}
+	// Check if task is already in a final state.
+	if isFinalState(task.Status.State) {
 		return task, ErrTaskFinalState(params.ID, task.Status.State)
 	}
+	reason := NormalizeCancelReason(params.Reason)
+	_ = m.auditLog.Record(ctx, audit.Event{
+		Type:   audit.EventCancellation,
+		TaskID: params.ID,
+		Caller: CallerFromContext(ctx),
+		Reason: reason,
+	})
+	// Update state to Cancelled, recording the reason, before canceling the
+	// processor: the update checks the expected version and that the task did
+	// not end since, atomically.
+	status := protocol.TaskStatus{
+		State:        protocol.TaskStateCanceled,
+		Message:      NewCancelMessage(params.ID, reason),
+		CancelReason: reason,
+		Error:        protocol.NewCancelTaskError(reason),
+	}
+	if err := m.transitionTask(ctx, params.ID, status, nil, false); err != nil {
+		log.Errorf("Error updating status to Cancelled for task %s: %v", params.ID, err)
+		return nil, err
+	}
 	// Find and call the context cancel func stored for this taskID.
-	var cancelFound bool
 	m.ContextsMutex.Lock()
-	cancel, exists := m.Contexts[params.ID]
+	cancel, exists := m.cancelCauses[params.ID]
 	if exists {
-		cancel() // Call the cancel function.
-		cancelFound = true
+		cancel(&CancelError{TaskID: params.ID, Reason: reason}) // Call the cancel function.
 		// Don't delete the context here - let the processor goroutine clean up.
 	}
 	m.ContextsMutex.Unlock()
-	if !cancelFound {
+	if !exists {
 		log.Warnf("Warning: No cancellation function found for task %s", params.ID)
 	}
-	// Create a cancellation message.
-	cancelMsg := &protocol.Message{
-		Role: protocol.MessageRoleAgent,
-		Parts: []protocol.Part{
-			protocol.NewTextPart(fmt.Sprintf("Task %s was canceled by user request", params.ID)),
-		},
-	}
-	// Update state to Cancelled.
-	if err := m.UpdateTaskStatus(params.ID, protocol.TaskStateCanceled, cancelMsg); err != nil {
-		log.Errorf("Error updating status to Cancelled for task %s: %v", params.ID, err)
-		return nil, err
-	}
 	// Fetch the updated task state to return.
 	updatedTask, err := m.getTaskInternal(params.ID)
 	if err != nil {
@@ -295,100 +527,404 @@ func (m *MemoryTaskManager) OnCancelTask(ctx context.Context, params protocol.Ta
 	return updatedTask, nil
 }
 
+// ListTasks implements TaskLister.
+func (m *MemoryTaskManager) ListTasks(ctx context.Context, filter TaskFilter) ([]protocol.Task, error) {
+	tasks, err := m.store.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	for i := range tasks {
+		tasks[i].History = nil
+		tasks[i].Metadata = protocol.WithoutCheckpoint(tasks[i].Metadata)
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		if tasks[i].Status.Timestamp != tasks[j].Status.Timestamp {
+			return tasks[i].Status.Timestamp < tasks[j].Status.Timestamp
+		}
+		return tasks[i].ID < tasks[j].ID
+	})
+	if filter.Limit > 0 && len(tasks) > filter.Limit {
+		tasks = tasks[:filter.Limit]
+	}
+	return tasks, nil
+}
+
+// RequeueTask implements TaskRequeuer. The task is processed again in the
+// background, detached from ctx.
+func (m *MemoryTaskManager) RequeueTask(ctx context.Context, taskID string) (*protocol.Task, error) {
+	task, err := m.getTaskInternal(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.Status.State != protocol.TaskStateFailed {
+		return nil, ErrTaskNotRequeueable(taskID, task.Status.State)
+	}
+	message, ok := m.lastUserMessage(taskID)
+	if !ok {
+		return nil, fmt.Errorf("task %s has no user message to requeue", taskID)
+	}
+	if err := m.requeueTask(ctx, taskID, message); err != nil {
+		return nil, err
+	}
+	log.Infof("Requeued task %s", taskID)
+	return m.getTaskInternal(taskID)
+}
+
+// DeleteTask implements TaskDeleter.
+func (m *MemoryTaskManager) DeleteTask(ctx context.Context, taskID string) error {
+	if _, err := m.store.Get(ctx, taskID); err != nil {
+		return err
+	}
+	m.ContextsMutex.Lock()
+	if cancel, ok := m.cancelCauses[taskID]; ok {
+		cancel(&CancelError{TaskID: taskID, Reason: protocol.CancelReasonUserRequested})
+	}
+	m.ContextsMutex.Unlock()
+	if err := m.store.Delete(ctx, taskID); err != nil {
+		return err
+	}
+	m.MessagesMutex.Lock()
+	delete(m.Messages, taskID)
+	m.MessagesMutex.Unlock()
+	m.PushNotificationsMutex.Lock()
+	delete(m.PushNotifications, taskID)
+	m.PushNotificationsMutex.Unlock()
+	eventLock := m.eventLock(taskID)
+	eventLock.Lock()
+	m.SubMutex.Lock()
+	for _, ch := range m.Subscribers[taskID] {
+		if m.subscriptions.Release(taskID, ch) {
+			m.slowConsumers.Finish(ch)
+		}
+	}
+	delete(m.Subscribers, taskID)
+	m.SubMutex.Unlock()
+	m.eventIDs.Forget(taskID)
+	eventLock.Unlock()
+	log.Infof("Deleted task %s", taskID)
+	return nil
+}
+
+// requeueTask submits taskID again and processes it from message in the
+// background.
+func (m *MemoryTaskManager) requeueTask(ctx context.Context, taskID string, message protocol.Message) error {
+	status := protocol.TaskStatus{State: protocol.TaskStateSubmitted}
+	if err := m.transitionTask(ctx, taskID, status, nil, false); err != nil {
+		return err
+	}
+	m.processInBackground(ctx, taskID, func(ctx context.Context) error {
+		return m.processTaskWithProcessor(ctx, taskID, message)
+	})
+	return nil
+}
+
+// processInBackground runs process, the processing of taskID, in the
+// background, detached from ctx but canceled by OnCancelTask, unless the task
+// is being processed.
+func (m *MemoryTaskManager) processInBackground(
+	ctx context.Context,
+	taskID string,
+	process func(ctx context.Context) error,
+) {
+	taskCtx, cancel, claimed := m.claimProcessing(context.WithoutCancel(withoutExpectedVersion(ctx)), taskID,
+		DisconnectPolicy{Action: DisconnectContinue})
+	if !claimed {
+		log.Warnf("Not processing task %s in the background, which is being processed", taskID)
+		return
+	}
+	go func() {
+		defer m.releaseProcessing(taskID, cancel)
+		if err := process(taskCtx); err != nil {
+			log.Warnf("Task %s processed in the background failed: %v", taskID, err)
+		}
+	}()
+}
+
+// lastUserMessage returns the last message of the user in the task history.
+func (m *MemoryTaskManager) lastUserMessage(taskID string) (protocol.Message, bool) {
+	m.MessagesMutex.RLock()
+	defer m.MessagesMutex.RUnlock()
+	messages, exists := m.Messages[taskID]
+	if !exists {
+		messages, _ = m.readSpilledHistory(taskID)
+	}
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == protocol.MessageRoleUser {
+			return messages[i], true
+		}
+	}
+	return protocol.Message{}, false
+}
+
 // UpdateTaskStatus updates the task's state and notifies any subscribers.
 // Returns an error if the task does not exist.
 // Exported method (used by memoryTaskHandle).
 func (m *MemoryTaskManager) UpdateTaskStatus(taskID string, state protocol.TaskState, message *protocol.Message) error {
-	m.TasksMutex.Lock()
-	task, exists := m.Tasks[taskID]
-	if !exists {
-		m.TasksMutex.Unlock()
-		log.Warnf("Warning: UpdateTaskStatus called for non-existent task %s", taskID)
-		return ErrTaskNotFound(taskID)
-	}
-	// Update status fields.
-	task.Status = protocol.TaskStatus{
-		State:     state,
-		Message:   message,
-		Timestamp: time.Now().UTC().Format(time.RFC3339),
-	}
-	// Create a copy for notification before unlocking.
-	taskCopy := *task
-	m.TasksMutex.Unlock() // Unlock before potentially blocking on channel send.
+	return m.setTaskStatus(taskID, protocol.TaskStatus{State: state, Message: message}, nil)
+}
+
+// UpdateTaskStatusWithMetadata updates the task's state like UpdateTaskStatus,
+// sending metadata with the status update event. Progress recorded in metadata
+// is also recorded in the task metadata.
+// Exported method (used by memoryTaskHandle).
+func (m *MemoryTaskManager) UpdateTaskStatusWithMetadata(
+	taskID string,
+	state protocol.TaskState,
+	message *protocol.Message,
+	metadata map[string]interface{},
+) error {
+	return m.setTaskStatus(taskID, protocol.TaskStatus{State: state, Message: message}, metadata)
+}
+
+// setTaskStatus replaces the task's status, stamping it with the current time,
+// records the status message in history and notifies subscribers, sending them
+// metadata with the event.
+func (m *MemoryTaskManager) setTaskStatus(
+	taskID string,
+	status protocol.TaskStatus,
+	metadata map[string]interface{},
+) error {
+	return m.transitionTask(context.Background(), taskID, status, metadata, false)
+}
+
+// transitionTask implements setTaskStatus, rejecting the transitions the state
+// machine does not allow if validate is set, as for the status updates of
+// processors. The task is updated with ctx, which may expect its version.
+func (m *MemoryTaskManager) transitionTask(
+	ctx context.Context,
+	taskID string,
+	status protocol.TaskStatus,
+	metadata map[string]interface{},
+	validate bool,
+) error {
+	var transition Transition
+	taskCopy, err := m.store.Update(ctx, taskID, func(task *protocol.Task) error {
+		transition = Transition{TaskID: taskID, From: task.Status.State, To: status.State, Status: status}
+		if status.State == protocol.TaskStateCanceled && isFinalState(task.Status.State) {
+			return ErrTaskFinalState(taskID, task.Status.State)
+		}
+		if validate {
+			if err := m.states.validate(transition); err != nil {
+				log.Warnf("Rejected status update of task %s: %v", taskID, err)
+				return err
+			}
+		}
+		if err := m.states.runBefore(transition); err != nil {
+			return err
+		}
+		// Update status fields.
+		status.Timestamp = time.Now().UTC().Format(time.RFC3339)
+		task.Status = status
+		if isFinalState(status.State) {
+			task.Metadata = protocol.WithoutCheckpoint(task.Metadata)
+		}
+		if status.Message != nil {
+			m.chargeOutput(task, status.Message.Parts)
+		}
+		if progress, ok := protocol.ProgressFromMetadata(metadata); ok {
+			task.Metadata = protocol.WithProgress(task.Metadata, progress)
+		}
+		if attempt, ok := protocol.RetryFromMetadata(metadata); ok {
+			task.Metadata = protocol.WithAttempt(task.Metadata, attempt)
+		}
+		return nil
+	})
+	if err != nil {
+		if IsTaskNotFound(err) {
+			log.Warnf("Warning: UpdateTaskStatus called for non-existent task %s", taskID)
+		}
+		return err
+	}
+	from := transition.From
+	_ = m.auditLog.Record(context.Background(), audit.Event{
+		Type:   audit.EventStateTransition,
+		TaskID: taskID,
+		From:   from,
+		To:     status.State,
+	})
 	// Store the message in history if provided
-	if message != nil {
+	if status.Message != nil {
 		// Convert TaskStatus Message (which is a pointer) to a Message value for history
-		m.storeMessage(taskID, *message)
+		m.storeMessage(taskID, *status.Message)
 	}
 	// Notify subscribers outside the lock.
-	m.notifySubscribers(taskID, protocol.TaskStatusUpdateEvent{
-		ID:     taskID,
-		Status: taskCopy.Status,
-		Final:  isFinalState(state),
-	})
+	event := protocol.TaskStatusUpdateEvent{
+		ID:       taskID,
+		Status:   taskCopy.Status,
+		Final:    isFinalState(status.State),
+		Metadata: metadata,
+	}
+	m.publish(taskID, event)
+	transition.Status = taskCopy.Status
+	m.states.runAfter(transition)
 	return nil
 }
 
 // AddArtifact adds an artifact to the task and notifies subscribers.
-// Returns an error if the task does not exist.
+// Returns an error if the task does not exist or the artifact is too large.
 // Exported method (used by memoryTaskHandle).
 func (m *MemoryTaskManager) AddArtifact(taskID string, artifact protocol.Artifact) error {
-	m.TasksMutex.Lock()
-	task, exists := m.Tasks[taskID]
-	if !exists {
-		m.TasksMutex.Unlock()
-		log.Warnf("Warning: AddArtifact called for non-existent task %s", taskID)
-		return ErrTaskNotFound(taskID)
+	if err := CheckArtifactSize(taskID, artifact, m.maxArtifactBytes); err != nil {
+		return err
 	}
-	// Append the artifact.
-	if task.Artifacts == nil {
-		task.Artifacts = make([]protocol.Artifact, 0, 1)
+	_, err := m.store.Update(context.Background(), taskID, func(task *protocol.Task) error {
+		// Append the artifact.
+		m.restoreArtifacts(task)
+		if task.Artifacts == nil {
+			task.Artifacts = make([]protocol.Artifact, 0, 1)
+		}
+		task.Artifacts = append(task.Artifacts, artifact)
+		m.chargeOutput(task, artifact.Parts)
+		if m.spill != nil {
+			m.spill.grow(spillKey{taskID, spillArtifacts}, ArtifactSize(artifact))
+		}
+		return nil
+	})
+	if err != nil {
+		if IsTaskNotFound(err) {
+			log.Warnf("Warning: AddArtifact called for non-existent task %s", taskID)
+		}
+		return err
 	}
-	task.Artifacts = append(task.Artifacts, artifact)
-	// Create copies for notification before unlocking.
-	m.TasksMutex.Unlock() // Unlock before potentially blocking on channel send.
+	m.enforceSpillBudget()
 	// Notify subscribers outside the lock.
 	finalEvent := artifact.LastChunk != nil && *artifact.LastChunk
-	m.notifySubscribers(taskID, protocol.TaskArtifactUpdateEvent{
+	event := protocol.TaskArtifactUpdateEvent{
 		ID:       taskID,
 		Artifact: artifact,
 		Final:    finalEvent,
-	})
+	}
+	m.publish(taskID, event)
 	return nil
 }
 
 // --- Internal Helper Methods (Unexported) ---
 
-// upsertTask creates a new task or updates metadata if it already exists.
-// Assumes locks are handled by the caller if needed, but acquires its own lock.
-func (m *MemoryTaskManager) upsertTask(params protocol.SendTaskParams) *protocol.Task {
-	m.TasksMutex.Lock()
-	defer m.TasksMutex.Unlock()
-	task, exists := m.Tasks[params.ID]
-	if !exists {
-		task = protocol.NewTask(params.ID, params.SessionID)
-		m.Tasks[params.ID] = task
-		log.Infof("Created new task %s (Session: %v)", params.ID, params.SessionID)
-	} else {
-		log.Debugf("Updating existing task %s", params.ID)
+// claimProcessing registers the processing of taskID with a context derived
+// from ctx according to policy, and returns it, unless the task is being
+// processed already. The claim ends with releaseProcessing.
+func (m *MemoryTaskManager) claimProcessing(
+	ctx context.Context,
+	taskID string,
+	policy DisconnectPolicy,
+) (context.Context, context.CancelCauseFunc, bool) {
+	m.ContextsMutex.Lock()
+	defer m.ContextsMutex.Unlock()
+	if _, processing := m.Contexts[taskID]; processing {
+		return nil, nil, false
 	}
-	// Update metadata if provided.
-	if params.Metadata != nil {
-		if task.Metadata == nil {
-			task.Metadata = make(map[string]interface{})
+	taskCtx, cancel := taskContext(ctx, taskID, policy)
+	m.Contexts[taskID] = func() { cancel(nil) }
+	m.cancelCauses[taskID] = cancel
+	return taskCtx, cancel, true
+}
+
+// releaseProcessing cancels the processing of taskID claimed with
+// claimProcessing and forgets it.
+func (m *MemoryTaskManager) releaseProcessing(taskID string, cancel context.CancelCauseFunc) {
+	cancel(nil)
+	m.ContextsMutex.Lock()
+	delete(m.Contexts, taskID)
+	delete(m.cancelCauses, taskID)
+	m.ContextsMutex.Unlock()
+}
+
+// upsertTask creates a new task or updates metadata if it already exists,
+// charging the tokens of the message to the task, and returns the action of
+// the request (see ResendAction), processing telling whether the task is
+// being processed. It fails without touching the task if the task is final
+// or the message would exceed the task's token budget.
+// It returns a copy of the task.
+func (m *MemoryTaskManager) upsertTask(
+	params protocol.SendTaskParams,
+	processing bool,
+) (*protocol.Task, SendAction, error) {
+	tokens, err := m.usage.Count(params.Message.Parts)
+	if err != nil {
+		return nil, SendProcess, err
+	}
+	action := SendProcess
+	charge := func(task *protocol.Task) error {
+		metadata, err := m.usage.Charge(params.ID, task.Metadata, InputUsage(tokens))
+		if err != nil {
+			return err
 		}
-		for k, v := range params.Metadata {
-			task.Metadata[k] = v
+		task.Metadata = metadata
+		// Update metadata if provided.
+		if params.Metadata != nil {
+			if task.Metadata == nil {
+				task.Metadata = make(map[string]interface{})
+			}
+			for k, v := range params.Metadata {
+				task.Metadata[k] = v
+			}
 		}
+		return nil
+	}
+	update := func(task *protocol.Task) error {
+		var err error
+		if action, err = ResendAction(task, processing); err != nil {
+			return err
+		}
+		return charge(task)
+	}
+	ctx := context.Background()
+	for attempt := 1; ; attempt++ {
+		task, err := m.store.Update(ctx, params.ID, update)
+		if err == nil {
+			log.Debugf("Updating existing task %s", params.ID)
+			return task, action, nil
+		}
+		if !IsTaskNotFound(err) {
+			return nil, SendProcess, err
+		}
+		// Task doesn't exist, create new one unless another send does first.
+		task = protocol.NewTask(params.ID, params.SessionID)
+		if err := charge(task); err != nil {
+			return nil, SendProcess, err
+		}
+		err = CreateTask(ctx, m.store, task)
+		if err == nil {
+			log.Infof("Created new task %s (Session: %v)", params.ID, params.SessionID)
+			// Another send of the task may have claimed its processing while
+			// this one created it.
+			action, err = ResendAction(task, processing)
+			return task, action, err
+		}
+		if !isTaskExists(err) || attempt == maxTaskCreateAttempts {
+			return nil, SendProcess, err
+		}
+	}
+}
+
+// chargeOutput charges the tokens of parts produced by the agent to task.
+// The caller must hold TasksMutex.
+func (m *MemoryTaskManager) chargeOutput(task *protocol.Task, parts []protocol.Part) {
+	tokens, err := m.usage.Count(parts)
+	if err != nil {
+		log.Warnf("Failed to account output tokens of task %s: %v", task.ID, err)
+		return
 	}
-	return task
+	// Output is never rejected, so Charge cannot fail.
+	metadata, _ := m.usage.Charge(task.ID, task.Metadata, OutputUsage(tokens))
+	task.Metadata = metadata
 }
 
 // storeMessage adds a message to the task's history.
 // Assumes locks are handled by the caller if needed, but acquires its own lock.
 func (m *MemoryTaskManager) storeMessage(taskID string, message protocol.Message) {
+	defer m.enforceSpillBudget()
+	m.appendMessage(taskID, message)
+	if m.history != nil {
+		m.scheduleHistoryPolicy(taskID)
+	}
+}
+
+// appendMessage appends a copy of message to the history of taskID.
+func (m *MemoryTaskManager) appendMessage(taskID string, message protocol.Message) {
 	m.MessagesMutex.Lock()
 	defer m.MessagesMutex.Unlock()
+	m.restoreHistory(taskID)
 	if _, exists := m.Messages[taskID]; !exists {
 		m.Messages[taskID] = make([]protocol.Message, 0, 1) // Initialize with capacity.
 	}
@@ -403,23 +939,83 @@ func (m *MemoryTaskManager) storeMessage(taskID string, message protocol.Message
 		copy(messageCopy.Parts, message.Parts)
 	}
 	m.Messages[taskID] = append(m.Messages[taskID], messageCopy)
+	if m.spill != nil {
+		m.spill.grow(spillKey{taskID, spillHistory}, historySize(messageCopy))
+	}
 }
 
-// addSubscriber adds a channel to the list of subscribers for a task.
-func (m *MemoryTaskManager) addSubscriber(taskID string, ch chan<- protocol.TaskEvent) {
+// addSubscriber adds a channel to the list of subscribers for a task, subject to
+// the subscription limits. Subscriptions evicted to make room are closed.
+// The initial events are delivered to ch before any other event.
+func (m *MemoryTaskManager) addSubscriber(
+	ctx context.Context,
+	taskID string,
+	ch chan<- protocol.TaskEvent,
+	initial ...protocol.TaskEvent,
+) error {
 	m.SubMutex.Lock()
 	defer m.SubMutex.Unlock()
-	if _, exists := m.Subscribers[taskID]; !exists {
-		m.Subscribers[taskID] = make([]chan<- protocol.TaskEvent, 0, 1)
+	return m.acquireSubscriber(ctx, taskID, ch, initial...)
+}
+
+// acquireSubscriber implements addSubscriber. The caller must hold SubMutex.
+func (m *MemoryTaskManager) acquireSubscriber(
+	ctx context.Context,
+	taskID string,
+	ch chan<- protocol.TaskEvent,
+	initial ...protocol.TaskEvent,
+) error {
+	evicted, err := m.subscriptions.Acquire(ctx, taskID, ch)
+	if err != nil {
+		log.Warnf("Rejected subscriber for task %s: %v", taskID, err)
+		return err
+	}
+	for _, old := range evicted {
+		m.detachSubscriber(taskID, old)
+		// Closing under the write lock is safe since events are only sent under the lock.
+		close(old)
+		log.Infof("Evicted oldest subscriber for task %s", taskID)
+	}
+	for _, event := range initial {
+		select {
+		case ch <- event:
+		default:
+			log.Warnf("Warning: Dropping initial event for task %s subscriber - channel full.", taskID)
+		}
 	}
+	m.slowConsumers.Attach(ch)
 	m.Subscribers[taskID] = append(m.Subscribers[taskID], ch)
 	log.Debugf("Added subscriber for task %s", taskID)
+	return nil
 }
 
 // removeSubscriber removes a specific channel from the list of subscribers for a task.
-func (m *MemoryTaskManager) removeSubscriber(taskID string, ch chan<- protocol.TaskEvent) {
+// It reports whether the channel was still subscribed; false means it was already
+// removed, evicted or ended with its task, in which case the caller must not
+// close it.
+func (m *MemoryTaskManager) removeSubscriber(taskID string, ch chan<- protocol.TaskEvent) bool {
 	m.SubMutex.Lock()
 	defer m.SubMutex.Unlock()
+	if !m.subscriptions.Release(taskID, ch) {
+		// Stop delivering the events left to a stream that ended with its task.
+		m.slowConsumers.Detach(ch)
+		return false
+	}
+	m.detachSubscriber(taskID, ch)
+	return true
+}
+
+// unsubscribeOnDone removes the subscription of ch to taskID once ctx is
+// done, unless it ended before with the task.
+func (m *MemoryTaskManager) unsubscribeOnDone(ctx context.Context, taskID string, ch chan<- protocol.TaskEvent) {
+	context.AfterFunc(ctx, func() {
+		m.removeSubscriber(taskID, ch)
+	})
+}
+
+// detachSubscriber removes ch from the Subscribers map. The caller must hold SubMutex.
+func (m *MemoryTaskManager) detachSubscriber(taskID string, ch chan<- protocol.TaskEvent) {
+	m.slowConsumers.Detach(ch)
 	channels, exists := m.Subscribers[taskID]
 	if !exists {
 		return // No subscribers for this task.
@@ -439,31 +1035,78 @@ func (m *MemoryTaskManager) removeSubscriber(taskID string, ch chan<- protocol.T
 	log.Debugf("Removed subscriber for task %s", taskID)
 }
 
+// numberEvent records event in the event history, if any, and returns it with
+// its ID. The caller must hold the event lock of taskID.
+func (m *MemoryTaskManager) numberEvent(taskID string, event protocol.TaskEvent) protocol.TaskEvent {
+	if m.events == nil {
+		return protocol.WithEventID(event, m.eventIDs.Next(taskID))
+	}
+	id, err := m.events.Append(context.Background(), taskID, event)
+	if err != nil {
+		log.Warnf("Failed to record event of task %s for replay: %v", taskID, err)
+		return event
+	}
+	return protocol.WithEventID(event, id)
+}
+
+// eventLock returns the lock serializing the events of taskID.
+func (m *MemoryTaskManager) eventLock(taskID string) *sync.Mutex {
+	h := fnv.New32a()
+	h.Write([]byte(taskID))
+	return &m.eventLocks[h.Sum32()%eventLockShards]
+}
+
+// publish sends an event of a task, once transformed, to its subscribers and
+// push notification URL.
+func (m *MemoryTaskManager) publish(taskID string, event protocol.TaskEvent) {
+	event, ok := m.transformEvent(taskID, event)
+	if !ok {
+		return
+	}
+	m.notifySubscribers(taskID, event)
+	m.notifyPush(taskID, event)
+}
+
 // notifySubscribers sends an event to all current subscribers of a task.
 func (m *MemoryTaskManager) notifySubscribers(taskID string, event protocol.TaskEvent) {
-	m.SubMutex.RLock()
+	// Events are numbered and sent under the event lock of the task, so
+	// subscribers receive them in ID order, and sent under SubMutex, so evicted
+	// channels cannot be closed meanwhile. Sends never block, so holding
+	// SubMutex is cheap; recording the event in the history is done before.
+	eventLock := m.eventLock(taskID)
+	eventLock.Lock()
+	defer eventLock.Unlock()
+	event = m.numberEvent(taskID, event)
+	m.SubMutex.Lock()
+	defer m.SubMutex.Unlock()
 	subs, exists := m.Subscribers[taskID]
 	if !exists || len(subs) == 0 {
-		m.SubMutex.RUnlock()
 		return // No subscribers to notify.
 	}
-	// Copy the slice of channels under read lock.
-	subsCopy := make([]chan<- protocol.TaskEvent, len(subs))
-	copy(subsCopy, subs)
-	m.SubMutex.RUnlock()
 	log.Debugf("Notifying %d subscribers for task %s (Event Type: %T, Final: %t)",
-		len(subsCopy), taskID, event, event.IsFinal())
-	// Send events outside the lock.
-	for _, ch := range subsCopy {
-		// Use a select with a default case for a non-blocking send.
-		// This prevents one slow/blocked subscriber from delaying others.
-		select {
-		case ch <- event:
-			// Event sent successfully.
-		default:
-			// Channel buffer is full or channel is closed.
-			log.Warnf("Warning: Dropping event for task %s subscriber - channel full or closed.", taskID)
+		len(subs), taskID, event, event.IsFinal())
+	var slow []chan<- protocol.TaskEvent
+	for _, ch := range subs {
+		if !m.slowConsumers.Send(taskID, ch, event) {
+			slow = append(slow, ch)
+		}
+	}
+	for _, ch := range slow {
+		if m.subscriptions.Release(taskID, ch) {
+			m.detachSubscriber(taskID, ch)
+			// Closing under the lock is safe since events are only sent under it.
+			close(ch)
+		}
+	}
+	if taskEnded(event) {
+		// The streams end with their task, which releases their subscriptions,
+		// once the events queued for them, including this one, are delivered.
+		for _, ch := range m.Subscribers[taskID] {
+			if m.subscriptions.Release(taskID, ch) {
+				m.slowConsumers.Finish(ch)
+			}
 		}
+		delete(m.Subscribers, taskID)
 	}
 }
 
@@ -473,31 +1116,48 @@ func (m *MemoryTaskManager) OnPushNotificationSet(
 	ctx context.Context,
 	params protocol.TaskPushNotificationConfig,
 ) (*protocol.TaskPushNotificationConfig, error) {
-	m.TasksMutex.RLock()
-	_, exists := m.Tasks[params.ID]
-	m.TasksMutex.RUnlock()
-	if !exists {
-		return nil, ErrTaskNotFound(params.ID)
+	if _, err := m.store.Get(ctx, params.ID); err != nil {
+		return nil, err
+	}
+	if err := m.setPushConfig(ctx, params.ID, params.PushNotificationConfig); err != nil {
+		return nil, err
 	}
-	// Store the push notification configuration.
-	m.PushNotificationsMutex.Lock()
-	m.PushNotifications[params.ID] = params.PushNotificationConfig
-	m.PushNotificationsMutex.Unlock()
 	log.Infof("Set push notification for task %s to URL: %s", params.ID, params.PushNotificationConfig.URL)
 	// Return the stored configuration as confirmation.
 	return &params, nil
 }
 
+// setPushConfig checks and stores the push notification configuration of
+// taskID, persisting it first if push delivery is enabled.
+func (m *MemoryTaskManager) setPushConfig(
+	ctx context.Context,
+	taskID string,
+	config protocol.PushNotificationConfig,
+) error {
+	if err := CheckPushConfig(config); err != nil {
+		return err
+	}
+	if m.push != nil {
+		if err := m.push.check(config); err != nil {
+			return err
+		}
+		if err := m.push.save(ctx, taskID, PushConfigRecord{Config: config}); err != nil {
+			return fmt.Errorf("failed to persist push notification config: %w", err)
+		}
+	}
+	m.PushNotificationsMutex.Lock()
+	m.PushNotifications[taskID] = config
+	m.PushNotificationsMutex.Unlock()
+	return nil
+}
+
 // OnPushNotificationGet implements TaskManager.OnPushNotificationGet.
 // It retrieves the push notification configuration for a task.
 func (m *MemoryTaskManager) OnPushNotificationGet(
 	ctx context.Context, params protocol.TaskIDParams,
 ) (*protocol.TaskPushNotificationConfig, error) {
-	m.TasksMutex.RLock()
-	_, exists := m.Tasks[params.ID]
-	m.TasksMutex.RUnlock()
-	if !exists {
-		return nil, ErrTaskNotFound(params.ID)
+	if _, err := m.store.Get(ctx, params.ID); err != nil {
+		return nil, err
 	}
 	// Retrieve the push notification configuration.
 	m.PushNotificationsMutex.RLock()
@@ -517,16 +1177,17 @@ func (m *MemoryTaskManager) OnPushNotificationGet(
 // OnResubscribe implements TaskManager.OnResubscribe.
 // It allows a client to reestablish an SSE stream for an existing task.
 func (m *MemoryTaskManager) OnResubscribe(ctx context.Context, params protocol.TaskIDParams) (<-chan protocol.TaskEvent, error) {
-	m.TasksMutex.RLock()
-	task, exists := m.Tasks[params.ID]
-	m.TasksMutex.RUnlock()
-	if !exists {
-		return nil, ErrTaskNotFound(params.ID)
+	// Work on a copy so the status can be read while the processor updates the task.
+	task, err := m.getTaskWithValidation(params.ID)
+	if err != nil {
+		return nil, err
+	}
+	if params.LastEventID != "" && m.events != nil {
+		return m.resubscribeWithReplay(ctx, task, params.LastEventID)
 	}
-	// Create a channel for events.
-	eventChan := make(chan protocol.TaskEvent)
 	// For tasks in final state, just send a status update event and close.
 	if isFinalState(task.Status.State) {
+		eventChan := make(chan protocol.TaskEvent)
 		go func() {
 			// Send a task status update event.
 			event := protocol.TaskStatusUpdateEvent{
@@ -547,32 +1208,57 @@ func (m *MemoryTaskManager) OnResubscribe(ctx context.Context, params protocol.T
 		}()
 		return eventChan, nil
 	}
-	// For tasks still in progress, add this as a subscriber.
-	m.addSubscriber(params.ID, eventChan)
+	// For tasks still in progress, add this as a subscriber,
+	// sending the current status as the first event.
+	eventChan := make(chan protocol.TaskEvent, 10) // Buffered to prevent blocking sends.
+	initial := protocol.TaskStatusUpdateEvent{
+		ID:     task.ID,
+		Status: task.Status,
+		Final:  isFinalState(task.Status.State),
+	}
+	if err := m.addSubscriber(ctx, params.ID, eventChan, initial); err != nil {
+		return nil, err
+	}
+	log.Debugf("Sent initial status to resubscribed client for task %s: %s", task.ID, task.Status.State)
 	// Ensure we remove the subscriber when the context is canceled.
-	go func() {
-		<-ctx.Done()
-		m.removeSubscriber(params.ID, eventChan)
-		// Don't close the channel here - that should happen in the task processing goroutine.
-	}()
-	// Send the current status as the first event.
-	go func() {
-		event := protocol.TaskStatusUpdateEvent{
-			ID:     task.ID,
-			Status: task.Status,
-			Final:  isFinalState(task.Status.State),
-		}
-		select {
-		case eventChan <- event:
-			// Successfully sent initial status.
-			log.Debugf("Sent initial status to resubscribed client for task %s: %s", task.ID, task.Status.State)
-		case <-ctx.Done():
-			// Context was canceled.
-			log.Debugf("Context done before sending initial status for task %s", task.ID)
-			m.removeSubscriber(params.ID, eventChan)
-			close(eventChan)
+	m.unsubscribeOnDone(ctx, params.ID, eventChan)
+	return eventChan, nil
+}
+
+// resubscribeWithReplay subscribes to the events of task, first delivering
+// those emitted after lastEventID. The history is read under the event lock of
+// the task, which its events are recorded under, so no event is missed or
+// delivered twice.
+func (m *MemoryTaskManager) resubscribeWithReplay(
+	ctx context.Context,
+	task *protocol.Task,
+	lastEventID string,
+) (<-chan protocol.TaskEvent, error) {
+	eventLock := m.eventLock(task.ID)
+	eventLock.Lock()
+	defer eventLock.Unlock()
+	missed, final, err := ReplayEvents(ctx, m.events, task, lastEventID)
+	if err != nil {
+		return nil, err
+	}
+	if final {
+		eventChan := make(chan protocol.TaskEvent, len(missed))
+		for _, event := range missed {
+			eventChan <- event
 		}
-	}()
+		close(eventChan)
+		log.Debugf("Replayed %d events to resubscribed client of ended task %s", len(missed), task.ID)
+		return eventChan, nil
+	}
+	eventChan := make(chan protocol.TaskEvent, len(missed)+10) // Room for the replay and live events.
+	m.SubMutex.Lock()
+	err = m.acquireSubscriber(ctx, task.ID, eventChan, missed...)
+	m.SubMutex.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("Replayed %d events to resubscribed client of task %s", len(missed), task.ID)
+	m.unsubscribeOnDone(ctx, task.ID, eventChan)
 	return eventChan, nil
 }
 
@@ -593,13 +1279,11 @@ func processError(err error) error {
 // getTaskWithValidation gets a task and validates it exists.
 // Returns task and nil if found, nil and error if not found.
 func (m *MemoryTaskManager) getTaskWithValidation(taskID string) (*protocol.Task, error) {
-	m.TasksMutex.RLock()
-	task, exists := m.Tasks[taskID]
-	m.TasksMutex.RUnlock()
-
-	if !exists {
-		return nil, ErrTaskNotFound(taskID)
+	task, err := m.store.Get(context.Background(), taskID)
+	if err != nil {
+		return nil, err
 	}
-	taskCopy := *task // Return a copy.
-	return &taskCopy, nil
+	// Checkpoints are internal to the processing.
+	task.Metadata = protocol.WithoutCheckpoint(task.Metadata)
+	return task, nil
 }
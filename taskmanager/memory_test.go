@@ -7,7 +7,9 @@
 package taskmanager
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"sync"
 	"testing"
@@ -17,6 +19,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"trpc.group/trpc-go/trpc-a2a-go/audit"
 	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
 	"trpc.group/trpc-go/trpc-a2a-go/protocol"
 )
@@ -887,3 +890,55 @@ func TestMemoryTaskManager_OnResubscribe(t *testing.T) {
 	assert.Equal(t, protocol.TaskStateCompleted, completedStatusEvent.Status.State)
 	assert.True(t, completedStatusEvent.Final)
 }
+
+func TestMemoryTaskManager_AuditLog(t *testing.T) {
+	var buf bytes.Buffer
+	logger := audit.NewLogger(audit.NewJSONSink(&buf))
+	started := make(chan struct{})
+	processor := &mockProcessor{
+		processFunc: func(ctx context.Context, taskID string, msg protocol.Message, handle TaskHandle) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}
+	tm, err := NewMemoryTaskManager(processor, WithAuditLog(logger))
+	require.NoError(t, err)
+	deliveries := NewPushDeliveryLog(0)
+	deliveries.SetAuditLog(logger)
+
+	_, err = tm.OnSendTaskSubscribe(context.Background(), protocol.SendTaskParams{
+		ID:      "audited",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")}),
+	})
+	require.NoError(t, err)
+	<-started
+	_, err = tm.OnCancelTask(context.Background(), protocol.TaskIDParams{
+		ID:     "audited",
+		Reason: protocol.CancelReasonTimeout,
+	})
+	require.NoError(t, err)
+	deliveries.Record(PushDeliveryAttempt{TaskID: "audited", URL: "http://hook", StatusCode: 200})
+
+	var events []audit.Event
+	decoder := json.NewDecoder(&buf)
+	for decoder.More() {
+		var event audit.Event
+		require.NoError(t, decoder.Decode(&event))
+		events = append(events, event)
+	}
+	require.NoError(t, audit.Verify(events))
+	var types []audit.EventType
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+	assert.Equal(t, []audit.EventType{
+		audit.EventStateTransition, audit.EventCancellation,
+		audit.EventStateTransition, audit.EventPushDelivery,
+	}, types)
+	assert.Equal(t, protocol.TaskStateSubmitted, events[0].From)
+	assert.Equal(t, protocol.TaskStateWorking, events[0].To)
+	assert.Equal(t, protocol.CancelReasonTimeout, events[1].Reason)
+	assert.Equal(t, protocol.TaskStateCanceled, events[2].To)
+	assert.Equal(t, "http://hook", events[3].URL)
+}
@@ -0,0 +1,255 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+// Package natsjs implements taskmanager.WorkQueue and taskmanager.EventBus
+// over NATS JetStream, so that agent workers scale horizontally: the tasks
+// are published to a work queue stream consumed by a durable consumer shared
+// by the workers, and the events are published to a stream subscribed to by
+// the API servers.
+//
+// The package does not depend on a NATS client: Client is implemented with a
+// few lines on top of one, such as nats.go. The streams are created by the
+// operator, e.g. with the nats CLI:
+//
+//	nats stream add A2A_TASKS --subjects "a2a.tasks" --retention work
+//	nats consumer add A2A_TASKS a2a-workers --pull --ack explicit --max-deliver 5
+//	nats stream add A2A_EVENTS --subjects "a2a.events.>" --max-age 1h
+package natsjs
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+const (
+	// DefaultSubjectPrefix is the default prefix of the subjects.
+	DefaultSubjectPrefix = "a2a"
+	// DefaultConsumer is the default name of the durable consumer of the
+	// workers.
+	DefaultConsumer = "a2a-workers"
+)
+
+// Msg is a message delivered by a JetStream consumer. The jetstream.Msg of
+// nats.go implements it.
+type Msg interface {
+	// Data returns the payload of the message.
+	Data() []byte
+	// Ack acknowledges the message.
+	Ack() error
+	// NakWithDelay asks for the message to be delivered again after delay.
+	NakWithDelay(delay time.Duration) error
+	// InProgress resets the ack wait of the message.
+	InProgress() error
+}
+
+// Client is the subset of a JetStream client used by the package. With the
+// jetstream package of nats.go:
+//
+//	func (c *client) Publish(ctx context.Context, subject string, data []byte) error {
+//		_, err := c.js.Publish(ctx, subject, data)
+//		return err
+//	}
+//
+//	func (c *client) Next(ctx context.Context, consumer, subject string) (natsjs.Msg, error) {
+//		cons, err := c.js.Consumer(ctx, "A2A_TASKS", consumer)
+//		if err != nil {
+//			return nil, err
+//		}
+//		return cons.Next(jetstream.FetchContext(ctx))
+//	}
+//
+// where Subscribe consumes an ordered consumer of the events stream, with the
+// DeliverNewPolicy.
+type Client interface {
+	// Publish publishes data to subject and returns once a stream stored it.
+	Publish(ctx context.Context, subject string, data []byte) error
+	// Next waits for the next message of subject for the durable consumer
+	// named consumer. The callers sharing a consumer form a group: each
+	// message is delivered to one of them, and delivered again if it is not
+	// acknowledged within the ack wait of the consumer.
+	Next(ctx context.Context, consumer, subject string) (Msg, error)
+	// Subscribe returns the messages of subject published after the call,
+	// delivered to every subscriber, until ctx is done, when the channel is
+	// closed.
+	Subscribe(ctx context.Context, subject string) (<-chan Msg, error)
+}
+
+// Config configures WorkQueue and EventBus.
+type Config struct {
+	// SubjectPrefix prefixes the subjects: tasks are published to
+	// "<prefix>.tasks" and the events of a task to "<prefix>.events.<id>",
+	// where id is the base64url encoding of the task ID. Defaults to
+	// DefaultSubjectPrefix.
+	SubjectPrefix string
+	// Consumer is the name of the durable consumer of the workers. Defaults
+	// to DefaultConsumer.
+	Consumer string
+}
+
+// withDefaults returns cfg with the defaults set.
+func (cfg Config) withDefaults() Config {
+	if cfg.SubjectPrefix == "" {
+		cfg.SubjectPrefix = DefaultSubjectPrefix
+	}
+	if cfg.Consumer == "" {
+		cfg.Consumer = DefaultConsumer
+	}
+	return cfg
+}
+
+// WorkQueue is a taskmanager.WorkQueue over a JetStream work queue stream.
+// It is safe for concurrent use.
+type WorkQueue struct {
+	client Client
+	cfg    Config
+}
+
+// NewWorkQueue creates a queue publishing and consuming the tasks through
+// client.
+func NewWorkQueue(client Client, cfg Config) *WorkQueue {
+	return &WorkQueue{client: client, cfg: cfg.withDefaults()}
+}
+
+// subject returns the subject of the tasks.
+func (q *WorkQueue) subject() string {
+	return q.cfg.SubjectPrefix + ".tasks"
+}
+
+// Enqueue implements taskmanager.WorkQueue.
+func (q *WorkQueue) Enqueue(ctx context.Context, task taskmanager.QueuedTask) error {
+	if task.EnqueuedAt.IsZero() {
+		task.EnqueuedAt = time.Now().UTC()
+	}
+	data, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("failed to encode task %s: %w", task.Params.ID, err)
+	}
+	if err := q.client.Publish(ctx, q.subject(), data); err != nil {
+		return fmt.Errorf("failed to enqueue task %s: %w", task.Params.ID, err)
+	}
+	return nil
+}
+
+// Dequeue implements taskmanager.WorkQueue. Messages that are not tasks are
+// acknowledged and skipped, so they are not delivered again.
+func (q *WorkQueue) Dequeue(ctx context.Context) (taskmanager.QueueDelivery, error) {
+	for {
+		msg, err := q.client.Next(ctx, q.cfg.Consumer, q.subject())
+		if err != nil {
+			return nil, err
+		}
+		var task taskmanager.QueuedTask
+		if err := json.Unmarshal(msg.Data(), &task); err != nil || task.Params.ID == "" {
+			log.Errorf("Dropping invalid task message of %s: %v", q.subject(), err)
+			if err := msg.Ack(); err != nil {
+				log.Warnf("Failed to acknowledge invalid task message: %v", err)
+			}
+			continue
+		}
+		return &delivery{msg: msg, task: task}, nil
+	}
+}
+
+// delivery is a taskmanager.QueueDelivery of a JetStream message.
+type delivery struct {
+	msg  Msg
+	task taskmanager.QueuedTask
+}
+
+// Task implements taskmanager.QueueDelivery.
+func (d *delivery) Task() taskmanager.QueuedTask {
+	return d.task
+}
+
+// Ack implements taskmanager.QueueDelivery.
+func (d *delivery) Ack(ctx context.Context) error {
+	return d.msg.Ack()
+}
+
+// Nack implements taskmanager.QueueDelivery.
+func (d *delivery) Nack(ctx context.Context, delay time.Duration) error {
+	return d.msg.NakWithDelay(delay)
+}
+
+// Extend implements taskmanager.QueueDelivery.
+func (d *delivery) Extend(ctx context.Context) error {
+	return d.msg.InProgress()
+}
+
+// EventBus is a taskmanager.EventBus over a JetStream stream. It is safe for
+// concurrent use.
+type EventBus struct {
+	client Client
+	cfg    Config
+}
+
+// NewEventBus creates a bus publishing and subscribing to the events through
+// client.
+func NewEventBus(client Client, cfg Config) *EventBus {
+	return &EventBus{client: client, cfg: cfg.withDefaults()}
+}
+
+// subject returns the subject of the events of taskID. Task IDs are encoded
+// since they may hold characters not allowed in subject tokens.
+func (b *EventBus) subject(taskID string) string {
+	return b.cfg.SubjectPrefix + ".events." + base64.RawURLEncoding.EncodeToString([]byte(taskID))
+}
+
+// Publish implements taskmanager.EventBus.
+func (b *EventBus) Publish(ctx context.Context, taskID string, event protocol.TaskEvent) error {
+	tagged, err := protocol.NewPolledEvent(event)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(tagged)
+	if err != nil {
+		return fmt.Errorf("failed to encode event of task %s: %w", taskID, err)
+	}
+	if err := b.client.Publish(ctx, b.subject(taskID), data); err != nil {
+		return fmt.Errorf("failed to publish event of task %s: %w", taskID, err)
+	}
+	return nil
+}
+
+// Subscribe implements taskmanager.EventBus. Messages that are not events
+// are skipped.
+func (b *EventBus) Subscribe(ctx context.Context, taskID string) (<-chan protocol.TaskEvent, error) {
+	msgs, err := b.client.Subscribe(ctx, b.subject(taskID))
+	if err != nil {
+		return nil, err
+	}
+	if msgs == nil {
+		return nil, errors.New("natsjs: client returned no subscription")
+	}
+	events := make(chan protocol.TaskEvent)
+	go func() {
+		defer close(events)
+		for msg := range msgs {
+			var tagged protocol.PolledEvent
+			if err := json.Unmarshal(msg.Data(), &tagged); err != nil {
+				log.Errorf("Dropping invalid event message of task %s: %v", taskID, err)
+				continue
+			}
+			select {
+			case events <- tagged.Event:
+			case <-ctx.Done():
+				// Drain the messages until the client closes the channel.
+				for range msgs {
+				}
+				return
+			}
+		}
+	}()
+	return events, nil
+}
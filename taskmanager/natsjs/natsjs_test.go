@@ -0,0 +1,178 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package natsjs
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// fakeJetStream is a Client keeping the messages in memory, with the work
+// queue semantics of JetStream for Next.
+type fakeJetStream struct {
+	mu          sync.Mutex
+	pending     map[string][][]byte // Messages not delivered, by subject.
+	ready       chan struct{}       // Signaled when a message is pending.
+	subscribers map[string][]chan Msg
+}
+
+func newFakeJetStream() *fakeJetStream {
+	return &fakeJetStream{
+		pending:     make(map[string][][]byte),
+		ready:       make(chan struct{}, 1),
+		subscribers: make(map[string][]chan Msg),
+	}
+}
+
+func (js *fakeJetStream) Publish(ctx context.Context, subject string, data []byte) error {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+	js.pending[subject] = append(js.pending[subject], data)
+	for _, ch := range js.subscribers[subject] {
+		ch <- &fakeMsg{data: data}
+	}
+	select {
+	case js.ready <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (js *fakeJetStream) Next(ctx context.Context, consumer, subject string) (Msg, error) {
+	for {
+		js.mu.Lock()
+		if queue := js.pending[subject]; len(queue) > 0 {
+			js.pending[subject] = queue[1:]
+			js.mu.Unlock()
+			return &fakeMsg{data: queue[0], js: js, subject: subject}, nil
+		}
+		js.mu.Unlock()
+		select {
+		case <-js.ready:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (js *fakeJetStream) Subscribe(ctx context.Context, subject string) (<-chan Msg, error) {
+	ch := make(chan Msg, 16)
+	js.mu.Lock()
+	js.subscribers[subject] = append(js.subscribers[subject], ch)
+	js.mu.Unlock()
+	context.AfterFunc(ctx, func() {
+		js.mu.Lock()
+		defer js.mu.Unlock()
+		subscribers := js.subscribers[subject]
+		for i, sub := range subscribers {
+			if sub == ch {
+				js.subscribers[subject] = append(subscribers[:i], subscribers[i+1:]...)
+				close(ch)
+			}
+		}
+	})
+	return ch, nil
+}
+
+// fakeMsg is a message of fakeJetStream.
+type fakeMsg struct {
+	data    []byte
+	js      *fakeJetStream
+	subject string
+}
+
+func (m *fakeMsg) Data() []byte      { return m.data }
+func (m *fakeMsg) Ack() error        { return nil }
+func (m *fakeMsg) InProgress() error { return nil }
+
+func (m *fakeMsg) NakWithDelay(delay time.Duration) error {
+	return m.js.Publish(context.Background(), m.subject, m.data)
+}
+
+func TestWorkQueue(t *testing.T) {
+	js := newFakeJetStream()
+	producer, worker := NewWorkQueue(js, Config{}), NewWorkQueue(js, Config{})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	require.NoError(t, js.Publish(ctx, "a2a.tasks", []byte("not a task")))
+	params := protocol.SendTaskParams{
+		ID:      "task-1",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")}),
+	}
+	require.NoError(t, producer.Enqueue(ctx, taskmanager.QueuedTask{Params: params}))
+
+	// Invalid messages are skipped; nacked tasks are delivered again.
+	delivery, err := worker.Dequeue(ctx)
+	require.NoError(t, err)
+	task := delivery.Task()
+	assert.Equal(t, "task-1", task.Params.ID)
+	assert.False(t, task.EnqueuedAt.IsZero())
+	require.NoError(t, delivery.Nack(ctx, 0))
+	delivery, err = worker.Dequeue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "task-1", delivery.Task().Params.ID)
+	require.NoError(t, delivery.Extend(ctx))
+	require.NoError(t, delivery.Ack(ctx))
+
+	empty, cancelEmpty := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancelEmpty()
+	_, err = worker.Dequeue(empty)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestEventBus(t *testing.T) {
+	js := newFakeJetStream()
+	bus := NewEventBus(js, Config{SubjectPrefix: "agents"})
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := bus.Subscribe(ctx, "task.1")
+	require.NoError(t, err)
+	others, err := bus.Subscribe(ctx, "task.2")
+	require.NoError(t, err)
+
+	require.NoError(t, bus.Publish(ctx, "task.1", protocol.TaskStatusUpdateEvent{
+		ID:     "task.1",
+		Status: protocol.TaskStatus{State: protocol.TaskStateWorking},
+	}))
+	require.NoError(t, bus.Publish(ctx, "task.1", protocol.TaskArtifactUpdateEvent{
+		ID:       "task.1",
+		Artifact: protocol.Artifact{Parts: []protocol.Part{protocol.NewTextPart("done")}},
+	}))
+	status, ok := (<-events).(protocol.TaskStatusUpdateEvent)
+	require.True(t, ok)
+	assert.Equal(t, protocol.TaskStateWorking, status.Status.State)
+	artifact, ok := (<-events).(protocol.TaskArtifactUpdateEvent)
+	require.True(t, ok)
+	assert.Len(t, artifact.Artifact.Parts, 1)
+	// The events of task.1 are not delivered to the subscriber of task.2,
+	// which receives its own event first.
+	require.NoError(t, bus.Publish(ctx, "task.2", protocol.TaskStatusUpdateEvent{
+		ID:     "task.2",
+		Status: protocol.TaskStatus{State: protocol.TaskStateCompleted},
+	}))
+	select {
+	case event := <-others:
+		status, ok := event.(protocol.TaskStatusUpdateEvent)
+		require.True(t, ok, "event type %T", event)
+		assert.Equal(t, "task.2", status.ID)
+	case <-time.After(time.Second):
+		t.Fatal("no event for task.2")
+	}
+	assert.Contains(t, js.pending, "agents.events.dGFzay4x")
+
+	cancel()
+	for range events {
+	}
+}
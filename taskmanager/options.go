@@ -0,0 +1,172 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import "trpc.group/trpc-go/trpc-a2a-go/audit"
+
+// MemoryTaskManagerOption is a function that configures the MemoryTaskManager.
+type MemoryTaskManagerOption func(*MemoryTaskManager)
+
+// WithSubscriptionLimits limits the number of simultaneous event streams per task
+// and per caller. By default subscriptions are unlimited.
+func WithSubscriptionLimits(limits SubscriptionLimits) MemoryTaskManagerOption {
+	return func(m *MemoryTaskManager) {
+		m.subscriptions = NewSubscriptionLimiter(limits)
+	}
+}
+
+// WithTokenAccounting counts the tokens of the text parts exchanged for each task,
+// records them as protocol.TokenUsage in the task metadata and enforces the
+// optional per-task token budget. Accounting is disabled by default.
+func WithTokenAccounting(cfg TokenAccounting) MemoryTaskManagerOption {
+	return func(m *MemoryTaskManager) {
+		m.usage = NewUsageMeter(cfg)
+	}
+}
+
+// WithSlowConsumerPolicy sets how events are delivered to subscribers that read
+// their stream too slowly. By default events that do not fit in the channel of
+// a subscriber are dropped.
+func WithSlowConsumerPolicy(cfg SlowConsumerConfig) MemoryTaskManagerOption {
+	return func(m *MemoryTaskManager) {
+		m.slowConsumers = NewSlowConsumerHandler(cfg)
+	}
+}
+
+// WithRetryPolicy runs the processor of a task again, with backoff, when it
+// fails with a retryable error. Failed tasks are not retried by default.
+func WithRetryPolicy(policy RetryPolicy) MemoryTaskManagerOption {
+	return func(m *MemoryTaskManager) {
+		m.retry = policy
+	}
+}
+
+// WithAuditLog records every state transition and cancellation of the tasks
+// to logger. Auditing is disabled by default.
+func WithAuditLog(logger *audit.Logger) MemoryTaskManagerOption {
+	return func(m *MemoryTaskManager) {
+		m.auditLog = logger
+	}
+}
+
+// WithEventReplay records the events of the tasks in history, so that clients
+// resubscribing with the ID of the last event they received get the events
+// they missed replayed before live events. Replay is disabled by default.
+func WithEventReplay(history EventHistory) MemoryTaskManagerOption {
+	return func(m *MemoryTaskManager) {
+		m.events = history
+	}
+}
+
+// WithUsageAccounting counts the tasks submitted, compute time and artifact
+// bytes of each principal in accountant and rejects the messages of principals
+// over their quota. Accounting is disabled by default.
+func WithUsageAccounting(accountant *UsageAccountant) MemoryTaskManagerOption {
+	return func(m *MemoryTaskManager) {
+		m.quotas = accountant
+	}
+}
+
+// WithMaxArtifactSize rejects artifacts larger than maxBytes, as computed by
+// ArtifactSize, with an ErrPayloadTooLarge error returned to the processor.
+// Each chunk of a streamed artifact is checked separately. Artifacts are
+// unlimited by default.
+func WithMaxArtifactSize(maxBytes int64) MemoryTaskManagerOption {
+	return func(m *MemoryTaskManager) {
+		m.maxArtifactBytes = maxBytes
+	}
+}
+
+// WithTaskSpill bounds the memory used by the artifacts and histories of the
+// tasks: beyond cfg.MaxResidentBytes, those updated least recently are moved
+// to files on disk, so that a few giant artifacts cannot exhaust the memory of
+// the server. Reads of spilled data are served from disk and updates bring it
+// back into memory, transparently to callers, except for the exported Tasks
+// and Messages fields that only hold the data in memory. Call Close to remove
+// the files. Spilling is disabled by default.
+func WithTaskSpill(cfg TaskSpillConfig) MemoryTaskManagerOption {
+	return func(m *MemoryTaskManager) {
+		m.spill = newTaskSpiller(cfg)
+	}
+}
+
+// WithPushDelivery makes the task manager deliver the events of each task
+// with a push notification configuration to its URL: the events selected by
+// the configuration, or by cfg.Events, by default the final status update. The
+// body of notifications is a tasks/notifyEvent JSON-RPC request unless the
+// configuration has a template or cfg.Transform is set. With cfg.Store, the
+// configurations set by OnPushNotificationSet are persisted and reloaded by
+// NewMemoryTaskManager, and the final notifications left pending, because
+// their delivery failed or the server stopped before it completed, are sent
+// again. Requests carry the headers of the configuration and its token, or
+// the credentials of its bearer authentication, and present the client
+// certificate it names among cfg.ClientCertificates. Push delivery is
+// disabled by default.
+func WithPushDelivery(cfg PushDeliveryConfig) MemoryTaskManagerOption {
+	return func(m *MemoryTaskManager) {
+		m.push = newPushDeliverer(cfg)
+	}
+}
+
+// WithDisconnectPolicy sets what happens to the tasks whose request ends
+// before them, e.g. when the client disconnects. By default their context is
+// canceled at once. With DisconnectContinue and WithEventReplay, clients
+// disconnected from the stream of a task can resubscribe and receive the
+// events they missed.
+func WithDisconnectPolicy(policy DisconnectPolicy) MemoryTaskManagerOption {
+	return func(m *MemoryTaskManager) {
+		m.disconnect = policy
+	}
+}
+
+// WithStateMachine sets the state machine validating the status updates of
+// processors and running the transition hooks. Defaults to NewStateMachine.
+func WithStateMachine(sm *StateMachine) MemoryTaskManagerOption {
+	return func(m *MemoryTaskManager) {
+		if sm != nil {
+			m.states = sm
+		}
+	}
+}
+
+// WithTaskStore stores the tasks in store instead of the Tasks field of the
+// task manager, e.g. to keep them in a database. It cannot be combined with
+// WithTaskSpill.
+func WithTaskStore(store TaskStore) MemoryTaskManagerOption {
+	return func(m *MemoryTaskManager) {
+		m.store = store
+	}
+}
+
+// WithEventTransformers runs the events of the tasks through transformers, in
+// order, before they are sent to subscribers, and thus to SSE and WebSocket
+// streams, and to push notification URLs.
+func WithEventTransformers(transformers ...EventTransformer) MemoryTaskManagerOption {
+	return func(m *MemoryTaskManager) {
+		m.transformers = append(m.transformers, transformers...)
+	}
+}
+
+// WithHistoryPolicy bounds the message histories of the tasks with policy,
+// e.g. a HistoryLimits, applied in the background as messages are added to
+// them. By default histories are unbounded.
+func WithHistoryPolicy(policy HistoryPolicy) MemoryTaskManagerOption {
+	return func(m *MemoryTaskManager) {
+		m.history = policy
+	}
+}
+
+// WithTaskRecovery applies policy to the tasks left working in the store set
+// by WithTaskStore when the task manager is created, see RecoverTasks. The
+// message histories of the tasks are kept in memory, so after a restart
+// RecoveryRequeue fails the tasks, and RecoveryResume resumes them without
+// history. Tasks are left as they are by default.
+func WithTaskRecovery(policy RecoveryPolicy) MemoryTaskManagerOption {
+	return func(m *MemoryTaskManager) {
+		m.recovery = &policy
+	}
+}
@@ -0,0 +1,21 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// TaskPlanner is implemented by task managers that support dry runs of
+// tasks/send. PlanTask runs the checks OnSendTask would run, such as the token
+// budget or routing, and reports the outcome without creating, updating or
+// processing the task. It returns the error OnSendTask would fail with, if any.
+type TaskPlanner interface {
+	PlanTask(ctx context.Context, params protocol.SendTaskParams) (*protocol.TaskPlan, error)
+}
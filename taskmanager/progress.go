@@ -0,0 +1,44 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// StatusMetadataUpdater is implemented by task handles that can attach
+// metadata to status updates. The handles of the memory and Redis task
+// managers implement it.
+type StatusMetadataUpdater interface {
+	// UpdateStatusWithMetadata updates the task's state and optional message
+	// like TaskHandle.UpdateStatus, sending metadata with the status update
+	// event. Progress recorded under protocol.MetadataKeyProgress is also
+	// recorded in the task metadata; other entries are only sent with the event.
+	// The metadata must not be modified afterwards.
+	UpdateStatusWithMetadata(state protocol.TaskState, msg *protocol.Message, metadata map[string]interface{}) error
+}
+
+// UpdateStatusWithMetadata updates the status of the task of handle, sending
+// metadata with the status update event. If handle cannot attach metadata, the
+// status is updated without it.
+func UpdateStatusWithMetadata(
+	handle TaskHandle,
+	state protocol.TaskState,
+	msg *protocol.Message,
+	metadata map[string]interface{},
+) error {
+	if updater, ok := handle.(StatusMetadataUpdater); ok {
+		return updater.UpdateStatusWithMetadata(state, msg, metadata)
+	}
+	return handle.UpdateStatus(state, msg)
+}
+
+// ReportProgress reports the progress of the working task of handle, with an
+// optional message describing it.
+func ReportProgress(handle TaskHandle, progress protocol.TaskProgress, msg *protocol.Message) error {
+	return UpdateStatusWithMetadata(handle, protocol.TaskStateWorking, msg, protocol.WithProgress(nil, progress))
+}
@@ -0,0 +1,79 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+func TestMemoryTaskManager_ReportProgress(t *testing.T) {
+	processor := &mockProcessor{
+		processFunc: func(ctx context.Context, taskID string, msg protocol.Message, handle TaskHandle) error {
+			for step := 1; step <= 2; step++ {
+				if err := ReportProgress(handle, protocol.NewStepProgress(step, 2, "step"), nil); err != nil {
+					return err
+				}
+			}
+			return UpdateStatusWithMetadata(handle, protocol.TaskStateCompleted, nil,
+				map[string]interface{}{"trace": "abc"})
+		},
+	}
+	tm, err := NewMemoryTaskManager(processor)
+	require.NoError(t, err)
+	events, err := tm.OnSendTaskSubscribe(context.Background(), protocol.SendTaskParams{
+		ID:      "progress",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")}),
+	})
+	require.NoError(t, err)
+
+	var percents []float64
+	var last protocol.TaskStatusUpdateEvent
+	for event := range events {
+		status := event.(protocol.TaskStatusUpdateEvent)
+		if progress, ok := status.Progress(); ok {
+			percents = append(percents, progress.Percent)
+		}
+		last = status
+		if status.Final {
+			break
+		}
+	}
+	assert.Equal(t, []float64{0, 50}, percents)
+	assert.Equal(t, "abc", last.Metadata["trace"])
+
+	task, err := tm.OnGetTask(context.Background(), protocol.TaskQueryParams{ID: "progress"})
+	require.NoError(t, err)
+	progress, ok := task.Progress()
+	require.True(t, ok)
+	assert.Equal(t, 2, progress.Step)
+	assert.NotContains(t, task.Metadata, "trace")
+}
+
+func TestProgressFromMetadata(t *testing.T) {
+	metadata := protocol.WithProgress(nil, protocol.TaskProgress{Percent: 150, Description: "done"})
+	data, err := json.Marshal(metadata)
+	require.NoError(t, err)
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	progress, ok := protocol.ProgressFromMetadata(decoded)
+	require.True(t, ok)
+	assert.Equal(t, protocol.TaskProgress{Percent: 100, Description: "done"}, progress)
+
+	_, ok = protocol.ProgressFromMetadata(map[string]interface{}{"progress": "half"})
+	assert.False(t, ok)
+	// Handles without metadata support still get the status.
+	handle := &statusRecorder{}
+	require.NoError(t, ReportProgress(handle, protocol.TaskProgress{Percent: 10}, nil))
+	assert.Equal(t, []protocol.TaskState{protocol.TaskStateWorking}, handle.states)
+}
@@ -0,0 +1,543 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/auth"
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+const (
+	// pushNotifyMethod is the JSON-RPC method of push notifications, as sent
+	// by the Redis task manager.
+	pushNotifyMethod = "tasks/notifyEvent"
+	// defaultPushAttempts is the number of delivery attempts by default.
+	defaultPushAttempts = 3
+	// defaultPushRetryInterval is the wait before the first retry by default.
+	defaultPushRetryInterval = time.Second
+	// defaultPushTimeout is the timeout of delivery requests by default.
+	defaultPushTimeout = 10 * time.Second
+)
+
+// PushConfigRecord is the persisted push notification state of a task.
+type PushConfigRecord struct {
+	// Config is the push notification configuration of the task.
+	Config protocol.PushNotificationConfig `json:"config"`
+	// Pending is the final status event of the task while its notification
+	// is not delivered.
+	Pending *protocol.TaskStatusUpdateEvent `json:"pending,omitempty"`
+}
+
+// PushConfigStore persists the push notification configurations of tasks
+// and their pending deliveries, so that they survive restarts.
+type PushConfigStore interface {
+	// SavePushConfig records the push notification state of taskID,
+	// replacing the previous one.
+	SavePushConfig(ctx context.Context, taskID string, record PushConfigRecord) error
+	// LoadPushConfigs returns the push notification states of all the tasks.
+	LoadPushConfigs(ctx context.Context) (map[string]PushConfigRecord, error)
+}
+
+// FilePushConfigStore is a PushConfigStore keeping a JSON file per task in a
+// directory. It is safe for concurrent use.
+type FilePushConfigStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewFilePushConfigStore creates a store in dir, creating the directory if
+// needed.
+func NewFilePushConfigStore(dir string) (*FilePushConfigStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create push config directory: %w", err)
+	}
+	return &FilePushConfigStore{dir: dir}, nil
+}
+
+// SavePushConfig implements PushConfigStore. Files are replaced atomically.
+func (s *FilePushConfigStore) SavePushConfig(ctx context.Context, taskID string, record PushConfigRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal push config of task %s: %w", taskID, err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tmp, err := os.CreateTemp(s.dir, ".push-*")
+	if err != nil {
+		return fmt.Errorf("failed to save push config of task %s: %w", taskID, err)
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), filepath.Join(s.dir, url.PathEscape(taskID)+".json"))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save push config of task %s: %w", taskID, err)
+	}
+	return nil
+}
+
+// LoadPushConfigs implements PushConfigStore.
+func (s *FilePushConfigStore) LoadPushConfigs(ctx context.Context) (map[string]PushConfigRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list push configs: %w", err)
+	}
+	records := make(map[string]PushConfigRecord)
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok || entry.IsDir() {
+			continue
+		}
+		taskID, err := url.PathUnescape(name)
+		if err != nil {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(s.dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read push config of task %s: %w", taskID, err)
+		}
+		var record PushConfigRecord
+		if err := json.Unmarshal(data, &record); err != nil {
+			log.Warnf("Skipping corrupt push config of task %s: %v", taskID, err)
+			continue
+		}
+		records[taskID] = record
+	}
+	return records, nil
+}
+
+// PushNotification is a push notification of a task event, as passed to
+// payload templates and transforms.
+type PushNotification struct {
+	// TaskID is the ID of the task.
+	TaskID string
+	// EventType is protocol.EventTaskStatusUpdate or
+	// protocol.EventTaskArtifactUpdate.
+	EventType string
+	// Event is the event.
+	Event protocol.TaskEvent
+}
+
+// PushTransform renders the body of a push notification.
+type PushTransform func(notification PushNotification) ([]byte, error)
+
+// PushDeliveryConfig configures the delivery of push notifications by a
+// MemoryTaskManager.
+type PushDeliveryConfig struct {
+	// Store persists the push notification configurations and the pending
+	// deliveries. If nil, they are only kept in memory.
+	Store PushConfigStore
+	// Client sends the notifications. Defaults to a client with a 10 second
+	// timeout.
+	Client *http.Client
+	// Attempts is the number of attempts to deliver a notification before
+	// giving up. Final notifications are then left pending until the next
+	// start. Defaults to 3.
+	Attempts int
+	// RetryInterval is the wait before the first retry, doubled after each
+	// failed attempt. Defaults to 1 second.
+	RetryInterval time.Duration
+	// Log records the delivery attempts, if set.
+	Log *PushDeliveryLog
+	// Events selects the events notified for configurations selecting none.
+	// Defaults to protocol.DefaultPushEvents.
+	Events []protocol.PushEventKind
+	// Transform, if set, renders the body of the notifications for
+	// configurations without a template, instead of the default
+	// tasks/notifyEvent JSON-RPC request.
+	Transform PushTransform
+	// ClientCertificates are the client certificates that configurations can
+	// name to call their URL over mutual TLS. The certificates are added to
+	// the TLS configuration of the transport of Client if it is an
+	// *http.Transport, or of http.DefaultTransport otherwise.
+	ClientCertificates map[string]tls.Certificate
+}
+
+// pushFuncs are the functions of push payload templates.
+var pushFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// parsePushTemplate parses the payload template of a push configuration.
+func parsePushTemplate(text string) (*template.Template, error) {
+	return template.New("push").Funcs(pushFuncs).Option("missingkey=error").Parse(text)
+}
+
+// CheckPushConfig validates the extensions of config: its event kinds and
+// its payload template. It returns a JSON-RPC invalid params error.
+func CheckPushConfig(config protocol.PushNotificationConfig) error {
+	for _, kind := range config.Events {
+		switch kind {
+		case protocol.PushEventFinal, protocol.PushEventStatus, protocol.PushEventArtifact:
+		default:
+			return jsonrpc.ErrInvalidParams(fmt.Sprintf("unknown push event kind %q", kind))
+		}
+	}
+	if config.Template != "" {
+		if _, err := parsePushTemplate(config.Template); err != nil {
+			return jsonrpc.ErrInvalidParams(fmt.Sprintf("invalid push payload template: %v", err))
+		}
+	}
+	for name, value := range config.Headers {
+		if name == "" || strings.ContainsAny(name, " \t\r\n:") || strings.ContainsAny(value, "\r\n") {
+			return jsonrpc.ErrInvalidParams(fmt.Sprintf("invalid push header %q", name))
+		}
+	}
+	return nil
+}
+
+// pushBearerToken returns the bearer token of config: its token, or the
+// credentials of its authentication if it has the bearer scheme.
+func pushBearerToken(config protocol.PushNotificationConfig) string {
+	if config.Token != "" || config.Authentication == nil {
+		return config.Token
+	}
+	for _, scheme := range config.Authentication.Schemes {
+		if strings.EqualFold(scheme, "bearer") {
+			return config.Authentication.Credentials
+		}
+	}
+	return ""
+}
+
+// pushJob is a notification to deliver.
+type pushJob struct {
+	config protocol.PushNotificationConfig
+	event  protocol.TaskEvent
+}
+
+// pushDeliverer sends the events of tasks to their push notification URLs,
+// in order for each task. Final status events are recorded as pending in the
+// store until delivered.
+type pushDeliverer struct {
+	cfg    PushDeliveryConfig
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu     sync.Mutex
+	queues map[string][]pushJob // Jobs of the tasks being notified.
+
+	clients map[string]*http.Client // Clients by client certificate name.
+}
+
+// newPushDeliverer creates a deliverer for cfg.
+func newPushDeliverer(cfg PushDeliveryConfig) *pushDeliverer {
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: defaultPushTimeout}
+	}
+	if cfg.Attempts <= 0 {
+		cfg.Attempts = defaultPushAttempts
+	}
+	if cfg.RetryInterval <= 0 {
+		cfg.RetryInterval = defaultPushRetryInterval
+	}
+	clients := make(map[string]*http.Client, len(cfg.ClientCertificates))
+	for name, cert := range cfg.ClientCertificates {
+		base, ok := cfg.Client.Transport.(*http.Transport)
+		if !ok {
+			base = http.DefaultTransport.(*http.Transport)
+		}
+		transport := base.Clone()
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{}
+		}
+		transport.TLSClientConfig.Certificates = []tls.Certificate{cert}
+		client := *cfg.Client
+		client.Transport = transport
+		clients[name] = &client
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &pushDeliverer{
+		cfg:     cfg,
+		ctx:     ctx,
+		cancel:  cancel,
+		queues:  make(map[string][]pushJob),
+		clients: clients,
+	}
+}
+
+// check validates the client certificate named by config.
+func (d *pushDeliverer) check(config protocol.PushNotificationConfig) error {
+	if _, ok := d.clients[config.ClientCertificate]; config.ClientCertificate != "" && !ok {
+		return jsonrpc.ErrInvalidParams(fmt.Sprintf("unknown push client certificate %q", config.ClientCertificate))
+	}
+	return nil
+}
+
+// client returns the client sending the notifications of config.
+func (d *pushDeliverer) client(config protocol.PushNotificationConfig) (*http.Client, error) {
+	if config.ClientCertificate == "" {
+		return d.cfg.Client, nil
+	}
+	client, ok := d.clients[config.ClientCertificate]
+	if !ok {
+		return nil, fmt.Errorf("unknown client certificate %q", config.ClientCertificate)
+	}
+	return client, nil
+}
+
+// save persists the push notification state of taskID, if there is a store.
+func (d *pushDeliverer) save(ctx context.Context, taskID string, record PushConfigRecord) error {
+	if d.cfg.Store == nil {
+		return nil
+	}
+	return d.cfg.Store.SavePushConfig(ctx, taskID, record)
+}
+
+// load returns the persisted push notification states, if there is a store.
+func (d *pushDeliverer) load(ctx context.Context) (map[string]PushConfigRecord, error) {
+	if d.cfg.Store == nil {
+		return nil, nil
+	}
+	return d.cfg.Store.LoadPushConfigs(ctx)
+}
+
+// notify delivers event to config if config selects it. Final status events
+// are recorded as pending first.
+func (d *pushDeliverer) notify(taskID string, config protocol.PushNotificationConfig, event protocol.TaskEvent) {
+	kinds := config.Events
+	if len(kinds) == 0 {
+		kinds = d.cfg.Events
+	}
+	if !protocol.PushEventSelected(kinds, event) {
+		return
+	}
+	if status, ok := event.(protocol.TaskStatusUpdateEvent); ok && status.Final {
+		record := PushConfigRecord{Config: config, Pending: &status}
+		if err := d.save(d.ctx, taskID, record); err != nil {
+			log.Errorf("Failed to record the pending push notification of task %s: %v", taskID, err)
+		}
+	}
+	d.enqueue(taskID, pushJob{config: config, event: event})
+}
+
+// enqueue queues job for delivery after the previous jobs of taskID.
+func (d *pushDeliverer) enqueue(taskID string, job pushJob) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	queue, running := d.queues[taskID]
+	d.queues[taskID] = append(queue, job)
+	if running {
+		return
+	}
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		d.run(taskID)
+	}()
+}
+
+// run delivers the jobs of taskID until its queue is empty.
+func (d *pushDeliverer) run(taskID string) {
+	for {
+		d.mu.Lock()
+		queue := d.queues[taskID]
+		if len(queue) == 0 || d.ctx.Err() != nil {
+			delete(d.queues, taskID)
+			d.mu.Unlock()
+			return
+		}
+		job := queue[0]
+		d.queues[taskID] = queue[1:]
+		d.mu.Unlock()
+		d.deliver(taskID, job)
+	}
+}
+
+// deliver attempts to deliver job. Once a final status event is delivered,
+// it is no longer pending.
+func (d *pushDeliverer) deliver(taskID string, job pushJob) {
+	body, err := d.render(taskID, job)
+	if err != nil {
+		log.Errorf("Failed to render the push notification of task %s: %v", taskID, err)
+		return
+	}
+	wait := d.cfg.RetryInterval
+	for attempt := 1; ; attempt++ {
+		err := d.send(taskID, job.config, body)
+		if err == nil {
+			break
+		}
+		if attempt == d.cfg.Attempts {
+			log.Errorf("Failed to deliver the push notification of task %s after %d attempts: %v",
+				taskID, attempt, err)
+			return
+		}
+		select {
+		case <-time.After(wait):
+			wait *= 2
+		case <-d.ctx.Done():
+			return
+		}
+	}
+	if job.event.IsFinal() {
+		if _, ok := job.event.(protocol.TaskStatusUpdateEvent); ok {
+			record := PushConfigRecord{Config: job.config}
+			if err := d.save(d.ctx, taskID, record); err != nil {
+				log.Errorf("Failed to record the delivery of the push notification of task %s: %v", taskID, err)
+			}
+		}
+	}
+}
+
+// render returns the body of the notification of job: the rendering of the
+// template of its configuration or of the transform of the deliverer, or the
+// default tasks/notifyEvent JSON-RPC request.
+func (d *pushDeliverer) render(taskID string, job pushJob) ([]byte, error) {
+	polled, err := protocol.NewPolledEvent(job.event)
+	if err != nil {
+		return nil, err
+	}
+	notification := PushNotification{TaskID: taskID, EventType: polled.Type, Event: polled.Event}
+	switch {
+	case job.config.Template != "":
+		tmpl, err := parsePushTemplate(job.config.Template)
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, notification); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case d.cfg.Transform != nil:
+		return d.cfg.Transform(notification)
+	}
+	return json.Marshal(map[string]interface{}{
+		"jsonrpc": jsonrpc.Version,
+		"method":  pushNotifyMethod,
+		"params": map[string]interface{}{
+			"id":        taskID,
+			"eventType": notification.EventType,
+			"event":     notification.Event,
+		},
+	})
+}
+
+// send makes a delivery attempt of body to config.
+func (d *pushDeliverer) send(taskID string, config protocol.PushNotificationConfig, body []byte) error {
+	attempt := PushDeliveryAttempt{TaskID: taskID, URL: config.URL, Time: time.Now()}
+	err := func() error {
+		req, err := http.NewRequestWithContext(d.ctx, http.MethodPost, config.URL, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		for name, value := range config.Headers {
+			req.Header.Set(name, value)
+		}
+		if req.Header.Get("Content-Type") == "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		// Each attempt carries a fresh nonce for receivers to detect replays.
+		nonce := make([]byte, 16)
+		if _, err := rand.Read(nonce); err != nil {
+			return fmt.Errorf("failed to generate nonce: %w", err)
+		}
+		req.Header.Set(auth.HMACNonceHeader, hex.EncodeToString(nonce))
+		req.Header.Set(auth.HMACTimestampHeader, strconv.FormatInt(attempt.Time.Unix(), 10))
+		if token := pushBearerToken(config); token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		client, err := d.client(config)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+		attempt.StatusCode = resp.StatusCode
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("unexpected status %d", resp.StatusCode)
+		}
+		return nil
+	}()
+	attempt.Duration = time.Since(attempt.Time)
+	if err != nil {
+		attempt.Error = err.Error()
+	}
+	if d.cfg.Log != nil {
+		d.cfg.Log.Record(attempt)
+	}
+	return err
+}
+
+// stop cancels the deliveries in progress and waits for them to return.
+func (d *pushDeliverer) stop() {
+	if d == nil {
+		return
+	}
+	d.cancel()
+	d.wg.Wait()
+}
+
+// restorePushConfigs loads the persisted push notification configurations
+// and resumes the deliveries left pending.
+func (m *MemoryTaskManager) restorePushConfigs(ctx context.Context) error {
+	records, err := m.push.load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load push configs: %w", err)
+	}
+	m.PushNotificationsMutex.Lock()
+	for taskID, record := range records {
+		m.PushNotifications[taskID] = record.Config
+	}
+	m.PushNotificationsMutex.Unlock()
+	for taskID, record := range records {
+		if record.Pending != nil {
+			log.Infof("Resuming the pending push notification of task %s", taskID)
+			m.push.enqueue(taskID, pushJob{config: record.Config, event: *record.Pending})
+		}
+	}
+	return nil
+}
+
+// notifyPush delivers event to the push notification URL of taskID, if
+// configured and selected.
+func (m *MemoryTaskManager) notifyPush(taskID string, event protocol.TaskEvent) {
+	if m.push == nil {
+		return
+	}
+	m.PushNotificationsMutex.RLock()
+	config, ok := m.PushNotifications[taskID]
+	m.PushNotificationsMutex.RUnlock()
+	if ok {
+		m.push.notify(taskID, config, event)
+	}
+}
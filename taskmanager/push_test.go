@@ -0,0 +1,267 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// pushReceiver receives push notifications, failing while failing is set.
+type pushReceiver struct {
+	*httptest.Server
+	failing  atomic.Bool
+	received chan map[string]interface{}
+}
+
+func newPushReceiver(t *testing.T) *pushReceiver {
+	r := &pushReceiver{received: make(chan map[string]interface{}, 10)}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if r.failing.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		assert.Equal(t, "Bearer secret", req.Header.Get("Authorization"))
+		var notification map[string]interface{}
+		assert.NoError(t, json.NewDecoder(req.Body).Decode(&notification))
+		r.received <- notification
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+// next returns the next notification received.
+func (r *pushReceiver) next(t *testing.T) map[string]interface{} {
+	t.Helper()
+	select {
+	case notification := <-r.received:
+		return notification
+	case <-time.After(5 * time.Second):
+		t.Fatal("no push notification received")
+		return nil
+	}
+}
+
+func TestFilePushConfigStore(t *testing.T) {
+	store, err := NewFilePushConfigStore(t.TempDir())
+	require.NoError(t, err)
+	ctx := context.Background()
+	config := protocol.PushNotificationConfig{URL: "http://example.com/hook", Token: "secret"}
+	require.NoError(t, store.SavePushConfig(ctx, "tenant/task-1", PushConfigRecord{Config: config}))
+	pending := protocol.TaskStatusUpdateEvent{
+		ID: "task-2", Status: protocol.TaskStatus{State: protocol.TaskStateCompleted}, Final: true,
+	}
+	require.NoError(t, store.SavePushConfig(ctx, "task-2", PushConfigRecord{Config: config}))
+	require.NoError(t, store.SavePushConfig(ctx, "task-2", PushConfigRecord{Config: config, Pending: &pending}))
+
+	records, err := store.LoadPushConfigs(ctx)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, config, records["tenant/task-1"].Config)
+	assert.Nil(t, records["tenant/task-1"].Pending)
+	require.NotNil(t, records["task-2"].Pending)
+	assert.Equal(t, protocol.TaskStateCompleted, records["task-2"].Pending.Status.State)
+}
+
+func TestMemoryTaskManager_PushDelivery(t *testing.T) {
+	receiver := newPushReceiver(t)
+	store, err := NewFilePushConfigStore(t.TempDir())
+	require.NoError(t, err)
+	release := make(chan struct{})
+	processor := &mockProcessor{processFunc: func(ctx context.Context, taskID string, msg protocol.Message, handle TaskHandle) error {
+		<-release
+		return handle.UpdateStatus(protocol.TaskStateCompleted, nil)
+	}}
+	deliveries := NewPushDeliveryLog(0)
+	cfg := PushDeliveryConfig{Store: store, RetryInterval: 10 * time.Millisecond, Attempts: 2, Log: deliveries}
+	tm, err := NewMemoryTaskManager(processor, WithPushDelivery(cfg))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	msg := protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")})
+	_, err = tm.OnSendTaskSubscribe(ctx, protocol.SendTaskParams{ID: "task-1", Message: msg})
+	require.NoError(t, err)
+	config := protocol.PushNotificationConfig{URL: receiver.URL, Token: "secret"}
+	_, err = tm.OnPushNotificationSet(ctx, protocol.TaskPushNotificationConfig{ID: "task-1", PushNotificationConfig: config})
+	require.NoError(t, err)
+
+	// The notification is retried while the receiver fails, then left pending.
+	receiver.failing.Store(true)
+	close(release)
+	require.Eventually(t, func() bool {
+		attempts, _ := deliveries.PushDeliveryAttempts(ctx, "task-1")
+		return len(attempts) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, tm.Close())
+	records, err := store.LoadPushConfigs(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, records["task-1"].Pending, "undelivered notifications stay pending")
+
+	// A restarted manager reloads the configuration and resumes the delivery.
+	receiver.failing.Store(false)
+	restarted, err := NewMemoryTaskManager(&mockProcessor{}, WithPushDelivery(cfg))
+	require.NoError(t, err)
+	defer restarted.Close()
+	restarted.PushNotificationsMutex.RLock()
+	assert.Equal(t, config, restarted.PushNotifications["task-1"])
+	restarted.PushNotificationsMutex.RUnlock()
+
+	notification := receiver.next(t)
+	assert.Equal(t, "tasks/notifyEvent", notification["method"])
+	params := notification["params"].(map[string]interface{})
+	assert.Equal(t, "task-1", params["id"])
+	assert.Equal(t, protocol.EventTaskStatusUpdate, params["eventType"])
+	event := params["event"].(map[string]interface{})
+	assert.Equal(t, true, event["final"])
+	require.Eventually(t, func() bool {
+		records, err := store.LoadPushConfigs(ctx)
+		return err == nil && records["task-1"].Pending == nil
+	}, 5*time.Second, 10*time.Millisecond, "delivered notifications are no longer pending")
+}
+
+func TestMemoryTaskManager_PushFiltersAndTemplates(t *testing.T) {
+	receiver := newPushReceiver(t)
+	processor := &mockProcessor{processFunc: func(ctx context.Context, taskID string, msg protocol.Message, handle TaskHandle) error {
+		if err := handle.UpdateStatus(protocol.TaskStateWorking, nil); err != nil {
+			return err
+		}
+		if err := handle.AddArtifact(protocol.Artifact{Parts: []protocol.Part{protocol.NewTextPart("result")}}); err != nil {
+			return err
+		}
+		return handle.UpdateStatus(protocol.TaskStateCompleted, nil)
+	}}
+	transform := func(n PushNotification) ([]byte, error) {
+		return json.Marshal(map[string]interface{}{"transformed": n.TaskID, "type": n.EventType})
+	}
+	tm, err := NewMemoryTaskManager(processor, WithPushDelivery(PushDeliveryConfig{
+		Events:    []protocol.PushEventKind{protocol.PushEventArtifact},
+		Transform: transform,
+	}))
+	require.NoError(t, err)
+	defer tm.Close()
+	ctx := context.Background()
+	msg := protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")})
+
+	// Tasks have to exist before their push configuration is set.
+	start := func(taskID string, config protocol.PushNotificationConfig) {
+		tm.TasksMutex.Lock()
+		tm.Tasks[taskID] = &protocol.Task{ID: taskID, Status: protocol.TaskStatus{State: protocol.TaskStateSubmitted}}
+		tm.TasksMutex.Unlock()
+		_, err := tm.OnPushNotificationSet(ctx, protocol.TaskPushNotificationConfig{ID: taskID, PushNotificationConfig: config})
+		require.NoError(t, err)
+		_, err = tm.OnSendTask(ctx, protocol.SendTaskParams{ID: taskID, Message: msg})
+		require.NoError(t, err)
+	}
+
+	start("templated", protocol.PushNotificationConfig{
+		URL:      receiver.URL,
+		Token:    "secret",
+		Events:   []protocol.PushEventKind{protocol.PushEventStatus, protocol.PushEventArtifact},
+		Template: `{"task":{{json .TaskID}},"type":{{json .EventType}},"final":{{.Event.IsFinal}}}`,
+	})
+	var types []string
+	for i := 0; i < 4; i++ {
+		notification := receiver.next(t)
+		assert.Equal(t, "templated", notification["task"])
+		types = append(types, notification["type"].(string))
+		assert.Equal(t, i == 3, notification["final"])
+	}
+	assert.Equal(t, []string{
+		protocol.EventTaskStatusUpdate, // Submitted.
+		protocol.EventTaskStatusUpdate, // Working.
+		protocol.EventTaskArtifactUpdate,
+		protocol.EventTaskStatusUpdate, // Completed.
+	}, types, "notifications of a task are delivered in order")
+
+	// Without events nor a template, the defaults of the manager apply.
+	start("transformed", protocol.PushNotificationConfig{URL: receiver.URL, Token: "secret"})
+	notification := receiver.next(t)
+	assert.Equal(t, "transformed", notification["transformed"])
+	assert.Equal(t, protocol.EventTaskArtifactUpdate, notification["type"])
+	select {
+	case extra := <-receiver.received:
+		t.Fatalf("unexpected notification %v", extra)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	for _, config := range []protocol.PushNotificationConfig{
+		{URL: receiver.URL, Events: []protocol.PushEventKind{"everything"}},
+		{URL: receiver.URL, Template: "{{.TaskID"},
+	} {
+		_, err := tm.OnPushNotificationSet(ctx, protocol.TaskPushNotificationConfig{ID: "templated", PushNotificationConfig: config})
+		var rpcErr *jsonrpc.Error
+		require.ErrorAs(t, err, &rpcErr)
+		assert.Equal(t, jsonrpc.CodeInvalidParams, rpcErr.Code)
+	}
+}
+
+func TestMemoryTaskManager_PushAuthentication(t *testing.T) {
+	received := make(chan *http.Request, 1)
+	receiver := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		received <- req
+	}))
+	receiver.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	receiver.StartTLS()
+	defer receiver.Close()
+
+	processor := &mockProcessor{processFunc: func(ctx context.Context, taskID string, msg protocol.Message, handle TaskHandle) error {
+		return handle.UpdateStatus(protocol.TaskStateCompleted, nil)
+	}}
+	// The certificate of the receiver doubles as the client certificate.
+	tm, err := NewMemoryTaskManager(processor, WithPushDelivery(PushDeliveryConfig{
+		Client:             receiver.Client(),
+		ClientCertificates: map[string]tls.Certificate{"agent": receiver.TLS.Certificates[0]},
+	}))
+	require.NoError(t, err)
+	defer tm.Close()
+	ctx := context.Background()
+	tm.Tasks["task-1"] = &protocol.Task{ID: "task-1", Status: protocol.TaskStatus{State: protocol.TaskStateSubmitted}}
+
+	for _, config := range []protocol.PushNotificationConfig{
+		{URL: receiver.URL, ClientCertificate: "unknown"},
+		{URL: receiver.URL, Headers: map[string]string{"X-Bad": "a\r\nb"}},
+	} {
+		_, err := tm.OnPushNotificationSet(ctx, protocol.TaskPushNotificationConfig{ID: "task-1", PushNotificationConfig: config})
+		var rpcErr *jsonrpc.Error
+		require.ErrorAs(t, err, &rpcErr)
+		assert.Equal(t, jsonrpc.CodeInvalidParams, rpcErr.Code)
+	}
+
+	config := protocol.PushNotificationConfig{
+		URL:               receiver.URL,
+		Authentication:    &protocol.AuthenticationInfo{Schemes: []string{"Bearer"}, Credentials: "secret"},
+		Headers:           map[string]string{"X-Api-Key": "key"},
+		ClientCertificate: "agent",
+	}
+	_, err = tm.OnPushNotificationSet(ctx, protocol.TaskPushNotificationConfig{ID: "task-1", PushNotificationConfig: config})
+	require.NoError(t, err)
+	msg := protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")})
+	_, err = tm.OnSendTask(ctx, protocol.SendTaskParams{ID: "task-1", Message: msg})
+	require.NoError(t, err)
+
+	select {
+	case req := <-received:
+		assert.Equal(t, "Bearer secret", req.Header.Get("Authorization"))
+		assert.Equal(t, "key", req.Header.Get("X-Api-Key"))
+		assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+		require.Len(t, req.TLS.PeerCertificates, 1, "the client certificate is presented")
+	case <-time.After(5 * time.Second):
+		t.Fatal("no push notification received")
+	}
+}
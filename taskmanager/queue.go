@@ -0,0 +1,238 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// QueuedTask is a task submission carried by a WorkQueue.
+type QueuedTask struct {
+	// Params are the parameters of the tasks/send or tasks/sendSubscribe
+	// request submitting the task.
+	Params protocol.SendTaskParams `json:"params"`
+	// EnqueuedAt is when the task was enqueued.
+	EnqueuedAt time.Time `json:"enqueuedAt"`
+	// Streaming reports whether the task was submitted by a streaming
+	// request, see TaskHandle.IsStreamingRequest.
+	Streaming bool `json:"streaming,omitempty"`
+}
+
+// QueueDelivery is a task delivered by a WorkQueue to a worker. The worker
+// acknowledges it once processed; deliveries neither acknowledged nor
+// extended in time are delivered again, possibly to another worker.
+type QueueDelivery interface {
+	// Task returns the task delivered.
+	Task() QueuedTask
+	// Ack removes the task from the queue.
+	Ack(ctx context.Context) error
+	// Nack returns the task to the queue, to be delivered again after delay.
+	Nack(ctx context.Context, delay time.Duration) error
+	// Extend tells the queue the task is still being processed, postponing
+	// its redelivery.
+	Extend(ctx context.Context) error
+}
+
+// WorkQueue carries task submissions from the API servers accepting them to
+// the workers processing them. Delivery is at least once: workers sharing a
+// queue each receive different tasks, and a task is delivered again if its
+// worker does not acknowledge it, e.g. because it died.
+type WorkQueue interface {
+	// Enqueue submits task.
+	Enqueue(ctx context.Context, task QueuedTask) error
+	// Dequeue waits for the next task until ctx is done.
+	Dequeue(ctx context.Context) (QueueDelivery, error)
+}
+
+// EventBus carries the events of tasks from the workers processing them to
+// the API servers streaming them to clients. Subscriptions must not lose
+// events, e.g. while reconnecting to a broker: a QueueProcessor missing the
+// result of a worker waits for it until its task is canceled.
+type EventBus interface {
+	// Publish publishes event of taskID to its subscribers.
+	Publish(ctx context.Context, taskID string, event protocol.TaskEvent) error
+	// Subscribe returns the events of taskID published after the call, in
+	// order, until ctx is done, when the channel is closed.
+	Subscribe(ctx context.Context, taskID string) (<-chan protocol.TaskEvent, error)
+}
+
+// MemoryWorkQueue is an in-process WorkQueue, for workers running in the
+// process of the server, and for tests. Tasks are not redelivered when a
+// delivery is neither acknowledged nor extended, since its worker can only
+// die with the process. It is safe for concurrent use.
+type MemoryWorkQueue struct {
+	mu    sync.Mutex
+	tasks []QueuedTask
+	ready chan struct{} // Signaled when tasks are enqueued.
+}
+
+// NewMemoryWorkQueue creates an empty queue.
+func NewMemoryWorkQueue() *MemoryWorkQueue {
+	return &MemoryWorkQueue{ready: make(chan struct{}, 1)}
+}
+
+// Enqueue implements WorkQueue.
+func (q *MemoryWorkQueue) Enqueue(ctx context.Context, task QueuedTask) error {
+	if task.EnqueuedAt.IsZero() {
+		task.EnqueuedAt = time.Now().UTC()
+	}
+	q.mu.Lock()
+	q.tasks = append(q.tasks, task)
+	q.mu.Unlock()
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Dequeue implements WorkQueue.
+func (q *MemoryWorkQueue) Dequeue(ctx context.Context) (QueueDelivery, error) {
+	for {
+		q.mu.Lock()
+		if len(q.tasks) > 0 {
+			task := q.tasks[0]
+			q.tasks = q.tasks[1:]
+			more := len(q.tasks) > 0
+			q.mu.Unlock()
+			if more {
+				// Wake up another worker for the next task.
+				select {
+				case q.ready <- struct{}{}:
+				default:
+				}
+			}
+			return &memoryDelivery{queue: q, task: task}, nil
+		}
+		q.mu.Unlock()
+		select {
+		case <-q.ready:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// memoryDelivery is a QueueDelivery of a MemoryWorkQueue.
+type memoryDelivery struct {
+	queue *MemoryWorkQueue
+	task  QueuedTask
+}
+
+// Task implements QueueDelivery.
+func (d *memoryDelivery) Task() QueuedTask {
+	return d.task
+}
+
+// Ack implements QueueDelivery.
+func (d *memoryDelivery) Ack(ctx context.Context) error {
+	return nil
+}
+
+// Nack implements QueueDelivery.
+func (d *memoryDelivery) Nack(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return d.queue.Enqueue(ctx, d.task)
+	}
+	time.AfterFunc(delay, func() {
+		_ = d.queue.Enqueue(context.Background(), d.task)
+	})
+	return nil
+}
+
+// Extend implements QueueDelivery.
+func (d *memoryDelivery) Extend(ctx context.Context) error {
+	return nil
+}
+
+// MemoryEventBus is an in-process EventBus. It is safe for concurrent use.
+type MemoryEventBus struct {
+	mu          sync.Mutex
+	subscribers map[string][]*busSubscription
+}
+
+// busSubscription is a subscription to the events of a task of a
+// MemoryEventBus. Events are sent to in, which is never closed, and
+// forwarded to out, which is closed when the subscription ends.
+type busSubscription struct {
+	ctx context.Context
+	in  chan protocol.TaskEvent
+	out chan protocol.TaskEvent
+}
+
+// NewMemoryEventBus creates a bus without subscribers.
+func NewMemoryEventBus() *MemoryEventBus {
+	return &MemoryEventBus{subscribers: make(map[string][]*busSubscription)}
+}
+
+// Publish implements EventBus. It waits for the subscribers to receive the
+// event, so a slow subscriber slows down the publisher.
+func (b *MemoryEventBus) Publish(ctx context.Context, taskID string, event protocol.TaskEvent) error {
+	b.mu.Lock()
+	subscribers := append([]*busSubscription(nil), b.subscribers[taskID]...)
+	b.mu.Unlock()
+	for _, sub := range subscribers {
+		select {
+		case sub.in <- event:
+		case <-sub.ctx.Done():
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Subscribe implements EventBus.
+func (b *MemoryEventBus) Subscribe(ctx context.Context, taskID string) (<-chan protocol.TaskEvent, error) {
+	sub := &busSubscription{
+		ctx: ctx,
+		in:  make(chan protocol.TaskEvent),
+		out: make(chan protocol.TaskEvent),
+	}
+	b.mu.Lock()
+	b.subscribers[taskID] = append(b.subscribers[taskID], sub)
+	b.mu.Unlock()
+	go func() {
+		defer close(sub.out)
+		defer b.unsubscribe(taskID, sub)
+		for {
+			select {
+			case event := <-sub.in:
+				select {
+				case sub.out <- event:
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return sub.out, nil
+}
+
+// unsubscribe removes sub from the subscribers of taskID.
+func (b *MemoryEventBus) unsubscribe(taskID string, sub *busSubscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	subscribers := b.subscribers[taskID]
+	for i, s := range subscribers {
+		if s == sub {
+			subscribers = append(subscribers[:i], subscribers[i+1:]...)
+			break
+		}
+	}
+	if len(subscribers) == 0 {
+		delete(b.subscribers, taskID)
+	} else {
+		b.subscribers[taskID] = subscribers
+	}
+}
@@ -0,0 +1,246 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// PrincipalUsage holds the usage counters of a principal, the authenticated
+// caller on whose behalf tasks run.
+type PrincipalUsage struct {
+	// TasksSubmitted counts the messages sent to tasks, each running the processor.
+	TasksSubmitted int64 `json:"tasksSubmitted"`
+	// ComputeSeconds is the time spent in the processor.
+	ComputeSeconds float64 `json:"computeSeconds"`
+	// ArtifactBytes is the size of the artifacts produced.
+	ArtifactBytes int64 `json:"artifactBytes"`
+}
+
+// Add returns the sum of u and delta.
+func (u PrincipalUsage) Add(delta PrincipalUsage) PrincipalUsage {
+	return PrincipalUsage{
+		TasksSubmitted: u.TasksSubmitted + delta.TasksSubmitted,
+		ComputeSeconds: u.ComputeSeconds + delta.ComputeSeconds,
+		ArtifactBytes:  u.ArtifactBytes + delta.ArtifactBytes,
+	}
+}
+
+// UsageStore stores the usage counters of principals, e.g. in a database
+// shared by the replicas of an agent. It must be safe for concurrent use.
+type UsageStore interface {
+	// AddUsage adds delta to the usage of principal and returns the new total.
+	AddUsage(ctx context.Context, principal string, delta PrincipalUsage) (PrincipalUsage, error)
+	// GetUsage returns the usage of principal, zero if it has none.
+	GetUsage(ctx context.Context, principal string) (PrincipalUsage, error)
+	// ListUsage returns the usage of every principal.
+	ListUsage(ctx context.Context) (map[string]PrincipalUsage, error)
+	// ResetUsage clears the usage of principal, e.g. at the start of a billing period.
+	ResetUsage(ctx context.Context, principal string) error
+}
+
+// MemoryUsageStore is a UsageStore keeping the counters in memory.
+type MemoryUsageStore struct {
+	mu    sync.Mutex
+	usage map[string]PrincipalUsage
+}
+
+// NewMemoryUsageStore creates an empty in-memory usage store.
+func NewMemoryUsageStore() *MemoryUsageStore {
+	return &MemoryUsageStore{usage: make(map[string]PrincipalUsage)}
+}
+
+// AddUsage implements UsageStore.
+func (s *MemoryUsageStore) AddUsage(
+	ctx context.Context,
+	principal string,
+	delta PrincipalUsage,
+) (PrincipalUsage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	total := s.usage[principal].Add(delta)
+	s.usage[principal] = total
+	return total, nil
+}
+
+// GetUsage implements UsageStore.
+func (s *MemoryUsageStore) GetUsage(ctx context.Context, principal string) (PrincipalUsage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.usage[principal], nil
+}
+
+// ListUsage implements UsageStore.
+func (s *MemoryUsageStore) ListUsage(ctx context.Context) (map[string]PrincipalUsage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	usage := make(map[string]PrincipalUsage, len(s.usage))
+	for principal, u := range s.usage {
+		usage[principal] = u
+	}
+	return usage, nil
+}
+
+// ResetUsage implements UsageStore.
+func (s *MemoryUsageStore) ResetUsage(ctx context.Context, principal string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.usage, principal)
+	return nil
+}
+
+// Quota bounds the usage of a principal. Zero fields are unlimited.
+type Quota struct {
+	// MaxTasks bounds TasksSubmitted.
+	MaxTasks int64 `json:"maxTasks,omitempty"`
+	// MaxComputeSeconds bounds ComputeSeconds.
+	MaxComputeSeconds float64 `json:"maxComputeSeconds,omitempty"`
+	// MaxArtifactBytes bounds ArtifactBytes.
+	MaxArtifactBytes int64 `json:"maxArtifactBytes,omitempty"`
+}
+
+// QuotaConfig configures a UsageAccountant.
+type QuotaConfig struct {
+	// Store keeps the counters. Defaults to a MemoryUsageStore.
+	Store UsageStore
+	// PrincipalFunc identifies the principal of a request. Requests without
+	// principal are neither counted nor limited. Defaults to CallerFromContext.
+	PrincipalFunc func(ctx context.Context) string
+	// Default is the quota of principals without an entry in Quotas.
+	Default Quota
+	// Quotas holds the quotas of specific principals.
+	Quotas map[string]Quota
+}
+
+// UsageReporter reports the usage of principals, as used by the admin API of
+// the server for billing integrations. *UsageAccountant implements it.
+type UsageReporter interface {
+	// Usage returns the usage of principal.
+	Usage(ctx context.Context, principal string) (PrincipalUsage, error)
+	// ListUsage returns the usage of every principal.
+	ListUsage(ctx context.Context) (map[string]PrincipalUsage, error)
+}
+
+// UsageAccountant counts the tasks submitted, compute time and artifact bytes
+// of each principal and enforces their quotas. Task managers call Admit before
+// accepting a message and record the processing of the task; tasks admitted
+// concurrently may take a principal slightly over its quota. Its methods may
+// be called on a nil *UsageAccountant, which counts nothing.
+type UsageAccountant struct {
+	cfg QuotaConfig
+}
+
+// NewUsageAccountant creates an accountant for cfg.
+func NewUsageAccountant(cfg QuotaConfig) *UsageAccountant {
+	if cfg.Store == nil {
+		cfg.Store = NewMemoryUsageStore()
+	}
+	if cfg.PrincipalFunc == nil {
+		cfg.PrincipalFunc = CallerFromContext
+	}
+	return &UsageAccountant{cfg: cfg}
+}
+
+// Principal returns the principal of the request of ctx.
+func (a *UsageAccountant) Principal(ctx context.Context) string {
+	if a == nil {
+		return ""
+	}
+	return a.cfg.PrincipalFunc(ctx)
+}
+
+// QuotaOf returns the quota of principal.
+func (a *UsageAccountant) QuotaOf(principal string) Quota {
+	if a == nil {
+		return Quota{}
+	}
+	if quota, ok := a.cfg.Quotas[principal]; ok {
+		return quota
+	}
+	return a.cfg.Default
+}
+
+// Admit checks the quota of the principal of ctx before a message is sent to
+// taskID and counts the submission. It returns ErrQuotaExceeded if the
+// principal used up any of its quota.
+func (a *UsageAccountant) Admit(ctx context.Context, taskID string) error {
+	principal := a.Principal(ctx)
+	if principal == "" {
+		return nil
+	}
+	if resource := a.exhausted(ctx, principal); resource != "" {
+		log.Infof("Rejecting task %s: principal %s exceeded its quota of %s", taskID, principal, resource)
+		return ErrQuotaExceeded(principal, resource)
+	}
+	a.record(principal, PrincipalUsage{TasksSubmitted: 1})
+	return nil
+}
+
+// exhausted returns the resource of which principal used up its quota, if any.
+func (a *UsageAccountant) exhausted(ctx context.Context, principal string) string {
+	quota := a.QuotaOf(principal)
+	if quota == (Quota{}) {
+		return ""
+	}
+	used, err := a.cfg.Store.GetUsage(ctx, principal)
+	if err != nil {
+		// Fail open: an unavailable store must not take the agent down.
+		log.Errorf("Failed to get usage of %s: %v", principal, err)
+		return ""
+	}
+	switch {
+	case quota.MaxTasks > 0 && used.TasksSubmitted >= quota.MaxTasks:
+		return "tasks"
+	case quota.MaxComputeSeconds > 0 && used.ComputeSeconds >= quota.MaxComputeSeconds:
+		return "compute seconds"
+	case quota.MaxArtifactBytes > 0 && used.ArtifactBytes >= quota.MaxArtifactBytes:
+		return "artifact bytes"
+	}
+	return ""
+}
+
+// RecordCompute adds the time spent processing a task of principal.
+func (a *UsageAccountant) RecordCompute(principal string, elapsed time.Duration) {
+	a.record(principal, PrincipalUsage{ComputeSeconds: elapsed.Seconds()})
+}
+
+// RecordArtifact adds the size of an artifact produced for principal.
+func (a *UsageAccountant) RecordArtifact(principal string, artifact protocol.Artifact) {
+	a.record(principal, PrincipalUsage{ArtifactBytes: ArtifactSize(artifact)})
+}
+
+// record adds delta to the usage of principal.
+func (a *UsageAccountant) record(principal string, delta PrincipalUsage) {
+	if a == nil || principal == "" || delta == (PrincipalUsage{}) {
+		return
+	}
+	// Usage is recorded after the fact, so it is not tied to the request context.
+	if _, err := a.cfg.Store.AddUsage(context.Background(), principal, delta); err != nil {
+		log.Errorf("Failed to record usage of %s: %v", principal, err)
+	}
+}
+
+// Usage implements UsageReporter.
+func (a *UsageAccountant) Usage(ctx context.Context, principal string) (PrincipalUsage, error) {
+	if a == nil {
+		return PrincipalUsage{}, nil
+	}
+	return a.cfg.Store.GetUsage(ctx, principal)
+}
+
+// ListUsage implements UsageReporter.
+func (a *UsageAccountant) ListUsage(ctx context.Context) (map[string]PrincipalUsage, error) {
+	if a == nil {
+		return map[string]PrincipalUsage{}, nil
+	}
+	return a.cfg.Store.ListUsage(ctx)
+}
@@ -0,0 +1,107 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+func TestUsageAccountant_Admit(t *testing.T) {
+	a := NewUsageAccountant(QuotaConfig{
+		Default: Quota{MaxTasks: 2},
+		Quotas:  map[string]Quota{"vip": {}},
+	})
+	alice := callerContext("alice")
+
+	require.NoError(t, a.Admit(alice, "t1"))
+	require.NoError(t, a.Admit(alice, "t2"))
+	requireRPCCode(t, a.Admit(alice, "t3"), ErrCodeQuotaExceeded)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, a.Admit(callerContext("vip"), "t"), "vip is unlimited")
+	}
+	require.NoError(t, a.Admit(context.Background(), "t"), "anonymous requests are not limited")
+
+	usage, err := a.ListUsage(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]PrincipalUsage{
+		"alice": {TasksSubmitted: 2},
+		"vip":   {TasksSubmitted: 3},
+	}, usage)
+
+	a.RecordCompute("bob", 2*time.Second)
+	a.RecordArtifact("bob", protocol.Artifact{})
+	bob, err := a.Usage(context.Background(), "bob")
+	require.NoError(t, err)
+	assert.Equal(t, PrincipalUsage{ComputeSeconds: 2}, bob)
+
+	var nilAccountant *UsageAccountant
+	assert.NoError(t, nilAccountant.Admit(alice, "t"))
+	nilAccountant.RecordCompute("alice", time.Second)
+}
+
+func TestUsageAccountant_ArtifactBytes(t *testing.T) {
+	a := NewUsageAccountant(QuotaConfig{Default: Quota{MaxArtifactBytes: 10}})
+	encoded := base64.StdEncoding.EncodeToString([]byte("12345678"))
+	a.RecordArtifact("alice", protocol.Artifact{Parts: []protocol.Part{
+		protocol.NewTextPart("hello"),
+		protocol.FilePart{Type: protocol.PartTypeFile, File: protocol.FileContent{Bytes: &encoded}},
+		protocol.DataPart{Type: protocol.PartTypeData, Data: map[string]int{"n": 1}},
+	}})
+	usage, err := a.Usage(context.Background(), "alice")
+	require.NoError(t, err)
+	assert.Equal(t, int64(5+8+len(`{"n":1}`)), usage.ArtifactBytes)
+	requireRPCCode(t, a.Admit(callerContext("alice"), "t"), ErrCodeQuotaExceeded)
+}
+
+func TestMemoryTaskManager_UsageAccounting(t *testing.T) {
+	processor := &mockProcessor{
+		processFunc: func(ctx context.Context, taskID string, msg protocol.Message, handle TaskHandle) error {
+			if err := handle.AddArtifact(protocol.Artifact{
+				Parts: []protocol.Part{protocol.NewTextPart("result")},
+			}); err != nil {
+				return err
+			}
+			time.Sleep(10 * time.Millisecond)
+			return handle.UpdateStatus(protocol.TaskStateCompleted, nil)
+		},
+	}
+	accountant := NewUsageAccountant(QuotaConfig{Default: Quota{MaxTasks: 2}})
+	tm, err := NewMemoryTaskManager(processor, WithUsageAccounting(accountant))
+	require.NoError(t, err)
+	alice := callerContext("alice")
+
+	_, err = tm.OnSendTask(alice, tenantParams("task-1"))
+	require.NoError(t, err)
+	events, err := tm.OnSendTaskSubscribe(alice, tenantParams("task-2"))
+	require.NoError(t, err)
+	for event := range events {
+		if event.IsFinal() {
+			break
+		}
+	}
+	_, err = tm.OnSendTaskSubscribe(alice, tenantParams("task-3"))
+	requireRPCCode(t, err, ErrCodeQuotaExceeded)
+	_, err = tm.OnGetTask(alice, protocol.TaskQueryParams{ID: "task-3"})
+	requireRPCCode(t, err, ErrCodeTaskNotFound)
+
+	require.Eventually(t, func() bool {
+		usage, _ := accountant.Usage(context.Background(), "alice")
+		return usage.ComputeSeconds >= 0.02
+	}, time.Second, 5*time.Millisecond)
+	usage, err := accountant.Usage(context.Background(), "alice")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), usage.TasksSubmitted)
+	assert.Equal(t, int64(2*len("result")), usage.ArtifactBytes)
+}
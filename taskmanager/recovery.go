@@ -0,0 +1,130 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// ErrTaskInterrupted is the error of the tasks failed by RecoveryFail, whose
+// processing was interrupted by a restart of the server. It is retryable.
+var ErrTaskInterrupted = NewProcessingError(
+	protocol.TaskErrorCodeUnavailable, true, errors.New("task interrupted by a restart of the server"))
+
+// RecoveryPolicy decides what happens, when a task manager keeping its tasks
+// in a persistent store starts, to the tasks left submitted or working by a
+// process that stopped before it finished processing them. Without recovery
+// they stay submitted or working forever.
+type RecoveryPolicy int
+
+const (
+	// RecoveryFail fails the tasks with ErrTaskInterrupted, so that clients
+	// can send them again.
+	RecoveryFail RecoveryPolicy = iota
+	// RecoveryRequeue processes the tasks again from the last message of the
+	// user. Tasks without user message are failed.
+	RecoveryRequeue
+	// RecoveryResume resumes the tasks with the processor if it implements
+	// TaskResumer, typically from the checkpoint it saved with SaveCheckpoint,
+	// and requeues them otherwise.
+	RecoveryResume
+)
+
+// String returns the name of the policy, as accepted by ParseRecoveryPolicy.
+func (p RecoveryPolicy) String() string {
+	switch p {
+	case RecoveryFail:
+		return "fail"
+	case RecoveryRequeue:
+		return "requeue"
+	case RecoveryResume:
+		return "resume"
+	}
+	return "unknown"
+}
+
+// ParseRecoveryPolicy parses the name of a recovery policy: "fail", "requeue"
+// or "resume".
+func ParseRecoveryPolicy(name string) (RecoveryPolicy, error) {
+	for _, policy := range []RecoveryPolicy{RecoveryFail, RecoveryRequeue, RecoveryResume} {
+		if name == policy.String() {
+			return policy, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown recovery policy %q", name)
+}
+
+// TaskResumer is implemented by the processors that can resume the tasks
+// interrupted by a restart with RecoveryResume, rather than process them again
+// from scratch.
+type TaskResumer interface {
+	// ResumeTask continues the processing of task, which is working, with its
+	// status, metadata and artifacts as the previous process left them, and
+	// its message history as far as the task manager keeps it. It reports
+	// through handle like TaskProcessor.Process.
+	ResumeTask(ctx context.Context, task *protocol.Task, handle TaskHandle) error
+}
+
+// RecoverTasks applies policy to the tasks of the store left submitted or
+// working by a previous process and returns their number. The tasks requeued or resumed
+// are processed in the background. WithTaskRecovery makes
+// NewMemoryTaskManager call it; calling it while the task manager processes
+// tasks would process them twice.
+func (m *MemoryTaskManager) RecoverTasks(ctx context.Context, policy RecoveryPolicy) (int, error) {
+	tasks, err := m.store.List(ctx, TaskFilter{
+		States: []protocol.TaskState{protocol.TaskStateSubmitted, protocol.TaskStateWorking},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list unfinished tasks: %w", err)
+	}
+	for _, task := range tasks {
+		m.recoverTask(ctx, task.ID, policy)
+	}
+	return len(tasks), nil
+}
+
+// recoverTask applies policy to the unfinished task taskID.
+func (m *MemoryTaskManager) recoverTask(ctx context.Context, taskID string, policy RecoveryPolicy) {
+	switch policy {
+	case RecoveryResume:
+		if resumer, ok := m.Processor.(TaskResumer); ok {
+			all := 0
+			task, err := m.OnGetTask(ctx, protocol.TaskQueryParams{ID: taskID, HistoryLength: &all})
+			if err != nil {
+				log.Errorf("Failed to resume task %s: %v", taskID, err)
+				return
+			}
+			log.Infof("Resuming task %s", taskID)
+			m.processInBackground(ctx, taskID, func(ctx context.Context) error {
+				return m.processTask(ctx, taskID, func(handle TaskHandle) error {
+					return m.runAttempts(ctx, taskID, func() error {
+						return resumer.ResumeTask(ctx, task, handle)
+					})
+				})
+			})
+			return
+		}
+		fallthrough
+	case RecoveryRequeue:
+		if message, ok := m.lastUserMessage(taskID); ok {
+			log.Infof("Requeuing task %s", taskID)
+			if err := m.requeueTask(ctx, taskID, message); err != nil {
+				log.Errorf("Failed to requeue task %s: %v", taskID, err)
+			}
+			return
+		}
+		log.Warnf("Failing task %s without user message to requeue", taskID)
+	}
+	if err := m.transitionTask(ctx, taskID, FailedStatus(ErrTaskInterrupted), nil, false); err != nil {
+		log.Errorf("Failed to fail interrupted task %s: %v", taskID, err)
+	}
+}
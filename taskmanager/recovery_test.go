@@ -0,0 +1,122 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// resumingProcessor is a mockProcessor resuming tasks from the step recorded
+// in their metadata.
+type resumingProcessor struct {
+	mockProcessor
+}
+
+// ResumeTask implements TaskResumer.
+func (p *resumingProcessor) ResumeTask(ctx context.Context, task *protocol.Task, handle TaskHandle) error {
+	if err := handle.AddArtifact(protocol.Artifact{
+		Parts: []protocol.Part{protocol.NewTextPart(fmt.Sprintf("resumed at step %v", task.Metadata["step"]))},
+	}); err != nil {
+		return err
+	}
+	return handle.UpdateStatus(protocol.TaskStateCompleted, nil)
+}
+
+func TestMemoryTaskManager_RecoverTasks(t *testing.T) {
+	ctx := context.Background()
+	newStore := func() *mapTaskStore {
+		working := protocol.NewTask("working", nil)
+		working.Status.State = protocol.TaskStateWorking
+		working.Metadata = map[string]interface{}{"step": 2}
+		submitted := protocol.NewTask("submitted", nil)
+		completed := protocol.NewTask("completed", nil)
+		completed.Status.State = protocol.TaskStateCompleted
+		return &mapTaskStore{tasks: map[string]protocol.Task{
+			"working": *working, "submitted": *submitted, "completed": *completed,
+		}}
+	}
+	waitForTaskState := func(t *testing.T, store TaskStore, taskID string, state protocol.TaskState) *protocol.Task {
+		var task *protocol.Task
+		require.Eventually(t, func() bool {
+			var err error
+			task, err = store.Get(ctx, taskID)
+			return err == nil && task.Status.State == state
+		}, time.Second, 5*time.Millisecond)
+		return task
+	}
+	waitForState := func(t *testing.T, store TaskStore, state protocol.TaskState) *protocol.Task {
+		return waitForTaskState(t, store, "working", state)
+	}
+	processor := &mockProcessor{
+		processFunc: func(ctx context.Context, taskID string, msg protocol.Message, handle TaskHandle) error {
+			if err := handle.AddArtifact(protocol.Artifact{Parts: msg.Parts}); err != nil {
+				return err
+			}
+			return handle.UpdateStatus(protocol.TaskStateCompleted, nil)
+		},
+	}
+
+	t.Run("Fail", func(t *testing.T) {
+		store := newStore()
+		_, err := NewMemoryTaskManager(processor, WithTaskStore(store), WithTaskRecovery(RecoveryFail))
+		require.NoError(t, err)
+		task := waitForState(t, store, protocol.TaskStateFailed)
+		require.NotNil(t, task.Status.Error)
+		assert.Equal(t, protocol.TaskErrorCodeUnavailable, task.Status.Error.Code)
+		assert.True(t, task.Status.Error.Retryable)
+		waitForTaskState(t, store, "submitted", protocol.TaskStateFailed)
+		completed, err := store.Get(ctx, "completed")
+		require.NoError(t, err)
+		assert.Equal(t, protocol.TaskStateCompleted, completed.Status.State)
+	})
+
+	t.Run("Requeue", func(t *testing.T) {
+		store := newStore()
+		tm, err := NewMemoryTaskManager(processor, WithTaskStore(store))
+		require.NoError(t, err)
+		// The history of a task is in memory, and thus lost with its process.
+		recovered, err := tm.RecoverTasks(ctx, RecoveryRequeue)
+		require.NoError(t, err)
+		assert.Equal(t, 2, recovered)
+		waitForState(t, store, protocol.TaskStateFailed)
+
+		store = newStore()
+		tm, err = NewMemoryTaskManager(processor, WithTaskStore(store))
+		require.NoError(t, err)
+		tm.Messages["working"] = []protocol.Message{
+			protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("again")}),
+		}
+		_, err = tm.RecoverTasks(ctx, RecoveryRequeue)
+		require.NoError(t, err)
+		task := waitForState(t, store, protocol.TaskStateCompleted)
+		require.Len(t, task.Artifacts, 1)
+		assert.Equal(t, protocol.NewTextPart("again"), task.Artifacts[0].Parts[0])
+	})
+
+	t.Run("Resume", func(t *testing.T) {
+		store := newStore()
+		_, err := NewMemoryTaskManager(&resumingProcessor{}, WithTaskStore(store), WithTaskRecovery(RecoveryResume))
+		require.NoError(t, err)
+		task := waitForState(t, store, protocol.TaskStateCompleted)
+		require.Len(t, task.Artifacts, 1)
+		assert.Equal(t, protocol.NewTextPart("resumed at step 2"), task.Artifacts[0].Parts[0])
+	})
+
+	policy, err := ParseRecoveryPolicy("resume")
+	require.NoError(t, err)
+	assert.Equal(t, RecoveryResume, policy)
+	_, err = ParseRecoveryPolicy("retry")
+	assert.Error(t, err)
+}
@@ -0,0 +1,182 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+const (
+	// dataKeyPrefix is the key prefix of the encrypted data keys of tasks.
+	dataKeyPrefix = "datakey:"
+	// maxCachedDataKeys bounds the decrypted data keys kept in memory.
+	maxCachedDataKeys = 1024
+)
+
+// dataKeyCache keeps the decrypted data keys of the tasks used last, so that
+// the key provider is only called once per task.
+type dataKeyCache struct {
+	mu    sync.Mutex
+	keys  map[string]taskmanager.DataKey
+	order []string // Task IDs in insertion order.
+}
+
+// get returns the data key of taskID, if cached.
+func (c *dataKeyCache) get(taskID string) (taskmanager.DataKey, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key, ok := c.keys[taskID]
+	return key, ok
+}
+
+// put caches key for taskID, forgetting the key cached first when full.
+func (c *dataKeyCache) put(taskID string, key taskmanager.DataKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.keys == nil {
+		c.keys = make(map[string]taskmanager.DataKey)
+	}
+	if _, ok := c.keys[taskID]; !ok {
+		if len(c.order) >= maxCachedDataKeys {
+			delete(c.keys, c.order[0])
+			c.order = c.order[1:]
+		}
+		c.order = append(c.order, taskID)
+	}
+	c.keys[taskID] = key
+}
+
+// forget forgets the data key of taskID, if cached.
+func (c *dataKeyCache) forget(taskID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.keys[taskID]; !ok {
+		return
+	}
+	delete(c.keys, taskID)
+	for i, id := range c.order {
+		if id == taskID {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// dataKey returns the data key encrypting the data of taskID. Unless create
+// is set, it returns a zero key if the task has none yet.
+func (m *TaskManager) dataKey(ctx context.Context, taskID string, create bool) (taskmanager.DataKey, error) {
+	if key, ok := m.dataKeys.get(taskID); ok {
+		return key, nil
+	}
+	redisKey := dataKeyPrefix + taskID
+	encrypted, err := m.client.Get(ctx, redisKey).Bytes()
+	if err == redis.Nil {
+		if !create {
+			return taskmanager.DataKey{}, nil
+		}
+		key, err := taskmanager.GenerateDataKey(ctx, m.keys)
+		if err != nil {
+			return taskmanager.DataKey{}, err
+		}
+		created, err := m.client.SetNX(ctx, redisKey, key.Encrypted, m.expiration).Result()
+		if err != nil {
+			return taskmanager.DataKey{}, fmt.Errorf("failed to store data key of task %s in Redis: %w", taskID, err)
+		}
+		if created {
+			m.dataKeys.put(taskID, key)
+			return key, nil
+		}
+		// Another writer, such as another replica, created it first.
+		encrypted, err = m.client.Get(ctx, redisKey).Bytes()
+	}
+	if err != nil {
+		return taskmanager.DataKey{}, fmt.Errorf("failed to retrieve data key of task %s from Redis: %w", taskID, err)
+	}
+	key, err := taskmanager.DecryptDataKey(ctx, m.keys, encrypted)
+	if err != nil {
+		return taskmanager.DataKey{}, err
+	}
+	m.dataKeys.put(taskID, key)
+	return key, nil
+}
+
+// encodeTask serializes task to store it, with its artifacts encrypted if
+// encryption is enabled.
+func (m *TaskManager) encodeTask(ctx context.Context, task *protocol.Task) ([]byte, error) {
+	if m.keys != nil {
+		key, err := m.dataKey(ctx, task.ID, true)
+		if err != nil {
+			return nil, err
+		}
+		if task, err = taskmanager.EncryptTask(task, key); err != nil {
+			return nil, err
+		}
+	}
+	data, err := json.Marshal(task)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize task: %w", err)
+	}
+	return data, nil
+}
+
+// decodeTask deserializes a stored task, decrypting its artifacts if they are
+// encrypted.
+func (m *TaskManager) decodeTask(ctx context.Context, data []byte) (*protocol.Task, error) {
+	var task protocol.Task
+	if err := json.Unmarshal(data, &task); err != nil {
+		return nil, fmt.Errorf("failed to deserialize task: %w", err)
+	}
+	if m.keys != nil {
+		key, err := m.dataKey(ctx, task.ID, false)
+		if err != nil {
+			return nil, err
+		}
+		if err := taskmanager.DecryptTask(&task, key); err != nil {
+			return nil, err
+		}
+	}
+	return &task, nil
+}
+
+// encodeMessage serializes a message of the history of taskID to store it,
+// encrypted if encryption is enabled.
+func (m *TaskManager) encodeMessage(ctx context.Context, taskID string, message protocol.Message) ([]byte, error) {
+	data, err := json.Marshal(message)
+	if err != nil || m.keys == nil {
+		return data, err
+	}
+	key, err := m.dataKey(ctx, taskID, true)
+	if err != nil {
+		return nil, err
+	}
+	return key.Seal(taskID, data)
+}
+
+// decodeMessage deserializes a stored message of the history of taskID,
+// decrypting it if it is encrypted.
+func (m *TaskManager) decodeMessage(ctx context.Context, taskID string, data []byte) (protocol.Message, error) {
+	var message protocol.Message
+	if m.keys != nil && taskmanager.IsSealed(data) {
+		key, err := m.dataKey(ctx, taskID, false)
+		if err != nil {
+			return message, err
+		}
+		if data, err = key.Open(taskID, data); err != nil {
+			return message, fmt.Errorf("failed to decrypt message of task %s: %w", taskID, err)
+		}
+	}
+	err := json.Unmarshal(data, &message)
+	return message, err
+}
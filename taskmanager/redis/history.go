@@ -0,0 +1,204 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+const (
+	// Key prefixes of the event history.
+	eventsPrefix   = "events:"
+	eventSeqPrefix = "eventseq:"
+
+	// defaultMaxEvents is the number of events kept per task by default.
+	defaultMaxEvents = 256
+)
+
+// storedEvent is the form of an event in the history list.
+type storedEvent struct {
+	ID    uint64          `json:"id"`
+	Type  string          `json:"type"`
+	Event json.RawMessage `json:"event"`
+}
+
+// EventHistory is a taskmanager.EventHistory persisted in Redis, so events can
+// be replayed by any replica and survive restarts. Event IDs are the sequence
+// numbers of the events of each task. It is safe for concurrent use.
+type EventHistory struct {
+	client     redis.UniversalClient
+	maxEvents  int
+	expiration time.Duration
+}
+
+// NewEventHistory creates a history keeping the last maxEvents events of each
+// task, 256 if maxEvents is not positive, for expiration after the last event
+// (30 days if not positive).
+func NewEventHistory(client redis.UniversalClient, maxEvents int, expiration time.Duration) *EventHistory {
+	if maxEvents <= 0 {
+		maxEvents = defaultMaxEvents
+	}
+	if expiration <= 0 {
+		expiration = defaultExpiration
+	}
+	return &EventHistory{client: client, maxEvents: maxEvents, expiration: expiration}
+}
+
+// Append implements taskmanager.EventHistory.
+func (h *EventHistory) Append(ctx context.Context, taskID string, event protocol.TaskEvent) (string, error) {
+	var eventType string
+	switch event.(type) {
+	case protocol.TaskStatusUpdateEvent:
+		eventType = protocol.EventTaskStatusUpdate
+	case protocol.TaskArtifactUpdateEvent:
+		eventType = protocol.EventTaskArtifactUpdate
+	default:
+		return "", fmt.Errorf("unsupported event type: %T", event)
+	}
+	seqKey := eventSeqPrefix + taskID
+	seq, err := nextEventSequence(ctx, h.client, seqKey, h.expiration)
+	if err != nil {
+		return "", err
+	}
+	id := strconv.FormatInt(seq, 10)
+	data, err := json.Marshal(protocol.WithEventID(event, id))
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize event: %w", err)
+	}
+	entry, err := json.Marshal(storedEvent{ID: uint64(seq), Type: eventType, Event: data})
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize event: %w", err)
+	}
+	key := eventsPrefix + taskID
+	_, err = h.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.RPush(ctx, key, entry)
+		pipe.LTrim(ctx, key, int64(-h.maxEvents), -1)
+		pipe.Expire(ctx, key, h.expiration)
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to store event: %w", err)
+	}
+	return id, nil
+}
+
+// Since implements taskmanager.EventHistory.
+func (h *EventHistory) Since(ctx context.Context, taskID, lastEventID string) ([]protocol.TaskEvent, error) {
+	seen, err := strconv.ParseUint(lastEventID, 10, 64)
+	if err != nil {
+		return nil, taskmanager.ErrEventNotReplayable(taskID, lastEventID)
+	}
+	last, err := h.client.Get(ctx, eventSeqPrefix+taskID).Uint64()
+	if errors.Is(err, redis.Nil) {
+		return nil, taskmanager.ErrEventNotReplayable(taskID, lastEventID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event sequence: %w", err)
+	}
+	if seen > last {
+		return nil, taskmanager.ErrEventNotReplayable(taskID, lastEventID)
+	}
+	entries, err := h.client.LRange(ctx, eventsPrefix+taskID, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get events: %w", err)
+	}
+	var missed []storedEvent
+	for _, raw := range entries {
+		var entry storedEvent
+		if err := json.Unmarshal([]byte(raw), &entry); err != nil {
+			return nil, fmt.Errorf("failed to deserialize event: %w", err)
+		}
+		if entry.ID > seen && entry.ID <= last {
+			missed = append(missed, entry)
+		}
+	}
+	// Concurrent appends may store events out of order.
+	sort.Slice(missed, func(i, j int) bool { return missed[i].ID < missed[j].ID })
+	if uint64(len(missed)) != last-seen {
+		return nil, taskmanager.ErrEventNotReplayable(taskID, lastEventID)
+	}
+	events := make([]protocol.TaskEvent, 0, len(missed))
+	for _, entry := range missed {
+		event, err := decodeEvent(entry)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// decodeEvent decodes a stored event.
+func decodeEvent(entry storedEvent) (protocol.TaskEvent, error) {
+	switch entry.Type {
+	case protocol.EventTaskStatusUpdate:
+		var event protocol.TaskStatusUpdateEvent
+		if err := json.Unmarshal(entry.Event, &event); err != nil {
+			return nil, fmt.Errorf("failed to deserialize status event: %w", err)
+		}
+		return event, nil
+	case protocol.EventTaskArtifactUpdate:
+		var event protocol.TaskArtifactUpdateEvent
+		if err := json.Unmarshal(entry.Event, &event); err != nil {
+			return nil, fmt.Errorf("failed to deserialize artifact event: %w", err)
+		}
+		return event, nil
+	}
+	return nil, fmt.Errorf("unsupported event type %q", entry.Type)
+}
+
+// nextEventSequence increments the event sequence at seqKey, extending its
+// expiration, and returns the new value.
+func nextEventSequence(
+	ctx context.Context,
+	client redis.UniversalClient,
+	seqKey string,
+	expiration time.Duration,
+) (int64, error) {
+	var incr *redis.IntCmd
+	_, err := client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.Incr(ctx, seqKey)
+		pipe.Expire(ctx, seqKey, expiration)
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to number event: %w", err)
+	}
+	return incr.Val(), nil
+}
+
+// eventSequence numbers the events of tasks in Redis without keeping them, so
+// that event IDs survive restarts and are shared by replicas.
+type eventSequence struct {
+	client     redis.UniversalClient
+	expiration time.Duration
+}
+
+// Append implements taskmanager.EventHistory.
+func (s eventSequence) Append(ctx context.Context, taskID string, event protocol.TaskEvent) (string, error) {
+	seq, err := nextEventSequence(ctx, s.client, eventSeqPrefix+taskID, s.expiration)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(seq, 10), nil
+}
+
+// Since implements taskmanager.EventHistory. No event is kept for replay.
+func (s eventSequence) Since(ctx context.Context, taskID, lastEventID string) ([]protocol.TaskEvent, error) {
+	return nil, taskmanager.ErrEventNotReplayable(taskID, lastEventID)
+}
@@ -0,0 +1,377 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+const (
+	// leasePrefix prefixes the keys of the leases, holding their owner.
+	leasePrefix = "lease:"
+	// taskLeasePrefix prefixes the names of the leases of tasks.
+	taskLeasePrefix = "task:"
+	// takeoverLease is the name of the lease of the leader taking over the
+	// tasks of dead replicas.
+	takeoverLease = "takeover"
+	// processingKey is the set of the tasks processed under a lease, which
+	// the leader takes over once their lease expired.
+	processingKey = "tasks:processing"
+)
+
+// errNoFinalState fails the tasks whose processor returned without setting a
+// final state or requesting input, which would otherwise stay working.
+var errNoFinalState = errors.New("processor returned without a final task state")
+
+var (
+	// acquireLeaseScript sets the owner of a free lease, or extends the lease
+	// of the owner.
+	acquireLeaseScript = redis.NewScript(`
+if redis.call('SET', KEYS[1], ARGV[1], 'NX', 'PX', ARGV[2]) then
+	return 1
+end
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+	return 1
+end
+return 0
+`)
+	// renewLeaseScript extends the lease of the owner.
+	renewLeaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+	return 1
+end
+return 0
+`)
+	// addProcessingScript adds a task to the processing set if the owner
+	// holds its lease.
+	addProcessingScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('SADD', KEYS[2], ARGV[2])
+end
+return 0
+`)
+	// removeProcessingScript removes a task from the processing set if the
+	// owner holds its lease, which another replica may have taken over.
+	removeProcessingScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('SREM', KEYS[2], ARGV[2])
+end
+return 0
+`)
+	// releaseLeaseScript deletes the lease of the owner.
+	releaseLeaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+)
+
+// Leaser is a taskmanager.Leaser storing the leases in Redis keys expiring
+// with them. It is safe for concurrent use.
+type Leaser struct {
+	client redis.UniversalClient
+}
+
+// NewLeaser creates a leaser storing the leases with client.
+func NewLeaser(client redis.UniversalClient) *Leaser {
+	return &Leaser{client: client}
+}
+
+// Acquire implements taskmanager.Leaser.
+func (l *Leaser) Acquire(ctx context.Context, name, owner string, ttl time.Duration) (bool, error) {
+	return acquireLeaseScript.Run(ctx, l.client, []string{leasePrefix + name}, owner, ttl.Milliseconds()).Bool()
+}
+
+// Renew implements taskmanager.Leaser.
+func (l *Leaser) Renew(ctx context.Context, name, owner string, ttl time.Duration) (bool, error) {
+	return renewLeaseScript.Run(ctx, l.client, []string{leasePrefix + name}, owner, ttl.Milliseconds()).Bool()
+}
+
+// Release implements taskmanager.Leaser.
+func (l *Leaser) Release(ctx context.Context, name, owner string) error {
+	return releaseLeaseScript.Run(ctx, l.client, []string{leasePrefix + name}, owner).Err()
+}
+
+// leaseKey is the context key of the lease fencing the changes of a task.
+type leaseKey struct{}
+
+// contextWithLease returns a context for the changes of a task processed
+// under lease, which fail with taskmanager.ErrLeaseLost once another replica
+// holds the lease.
+func contextWithLease(ctx context.Context, lease *taskmanager.Lease) context.Context {
+	if lease == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, leaseKey{}, lease)
+}
+
+// leaseFromContext returns the lease set by contextWithLease, or nil.
+func leaseFromContext(ctx context.Context) *taskmanager.Lease {
+	lease, _ := ctx.Value(leaseKey{}).(*taskmanager.Lease)
+	return lease
+}
+
+// checkLease returns taskmanager.ErrLeaseLost if the lease of ctx, if any, is
+// not held by its owner in tx, which watches its key.
+func checkLease(ctx context.Context, tx *redis.Tx) error {
+	lease := leaseFromContext(ctx)
+	if lease == nil {
+		return nil
+	}
+	owner, err := tx.Get(ctx, leasePrefix+lease.Name()).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return fmt.Errorf("failed to check lease %s: %w", lease.Name(), err)
+	}
+	if owner != lease.Owner() {
+		return taskmanager.ErrLeaseLost
+	}
+	return nil
+}
+
+// claimTask acquires the lease of the processing of taskID if leases are
+// enabled, returning taskmanager.ErrLeaseHeld if another replica processes
+// it, and records the task in the processing set. The lease is nil if leases
+// are disabled.
+func (m *TaskManager) claimTask(ctx context.Context, taskID string) (*taskmanager.Lease, error) {
+	if m.leases == nil {
+		return nil, nil
+	}
+	lease, err := taskmanager.AcquireLease(ctx, m.leases, taskLeasePrefix+taskID, m.leaseConfig)
+	if err != nil {
+		return nil, err
+	}
+	keys := []string{leasePrefix + lease.Name(), processingKey}
+	if err := addProcessingScript.Run(ctx, m.client, keys, lease.Owner(), taskID).Err(); err != nil {
+		m.releaseTask(lease)
+		return nil, fmt.Errorf("failed to record the processing of task %s: %w", taskID, err)
+	}
+	return lease, nil
+}
+
+// releaseTask removes the task of lease from the processing set and releases
+// the lease, if any. The task stays in the set if the lease was lost, so that
+// the replica which took it over keeps it there.
+func (m *TaskManager) releaseTask(lease *taskmanager.Lease) {
+	if lease == nil {
+		return
+	}
+	ctx := context.Background()
+	taskID := strings.TrimPrefix(lease.Name(), taskLeasePrefix)
+	keys := []string{leasePrefix + lease.Name(), processingKey}
+	if err := removeProcessingScript.Run(ctx, m.client, keys, lease.Owner(), taskID).Err(); err != nil {
+		log.Warnf("Failed to remove task %s from the processing set: %v", taskID, err)
+	}
+	if err := lease.Release(ctx); err != nil {
+		log.Warnf("Failed to release lease %s: %v", lease.Name(), err)
+	}
+}
+
+// endProcessing records the end of the processing of taskID under lease,
+// which returned err: the task is failed if err is not nil, or if leases are
+// enabled and the processor left it working, since the task would then stay
+// working without being taken over.
+func (m *TaskManager) endProcessing(
+	lease *taskmanager.Lease,
+	changeCtx, processingCtx context.Context,
+	taskID string,
+	err error,
+) {
+	if err != nil {
+		m.failTask(changeCtx, processingCtx, taskID, err)
+		return
+	}
+	if lease == nil {
+		return
+	}
+	task, getErr := m.getTaskInternal(changeCtx, taskID)
+	if getErr != nil {
+		log.Warnf("Failed to check the state of task %s: %v", taskID, getErr)
+		return
+	}
+	if task.Status.State == protocol.TaskStateSubmitted || task.Status.State == protocol.TaskStateWorking {
+		m.failTask(changeCtx, processingCtx, taskID, errNoFinalState)
+	}
+}
+
+// RunTakeover elects a leader among the replicas sharing the Redis of the
+// task manager, which takes over the tasks of the replicas that died while
+// processing them: every lease TTL, the tasks of the processing set whose
+// lease expired are recovered by the leader with the policy of WithTaskRecovery, or processed
+// again from their last user message by default. It returns nil once ctx is
+// done. It requires WithTaskLeases.
+func (m *TaskManager) RunTakeover(ctx context.Context) error {
+	if m.leases == nil {
+		return errors.New("task leases are not enabled")
+	}
+	return taskmanager.RunLeader(ctx, m.leases, takeoverLease, m.leaseConfig, func(ctx context.Context) {
+		ticker := time.NewTicker(m.leaseConfig.TTL)
+		defer ticker.Stop()
+		for {
+			if err := m.TakeOverTasks(ctx); err != nil && ctx.Err() == nil {
+				log.Errorf("Failed to take over tasks: %v", err)
+			}
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+}
+
+// TakeOverTasks recovers the tasks being processed whose lease expired, and returns
+// once their processing started. RunTakeover calls it on the leader.
+func (m *TaskManager) TakeOverTasks(ctx context.Context) error {
+	if m.leases == nil {
+		return errors.New("task leases are not enabled")
+	}
+	policy := taskmanager.RecoveryRequeue
+	if m.recovery != nil {
+		policy = *m.recovery
+	}
+	_, err := m.RecoverTasks(ctx, policy)
+	return err
+}
+
+// RecoverTasks applies policy to the tasks of the processing set whose lease
+// expired, i.e. those left submitted or working by the replicas that stopped
+// while processing them, and returns their number. The tasks requeued or
+// resumed are processed in the background. WithTaskRecovery makes
+// NewRedisTaskManager call it. It requires WithTaskLeases.
+func (m *TaskManager) RecoverTasks(ctx context.Context, policy taskmanager.RecoveryPolicy) (int, error) {
+	if m.leases == nil {
+		return 0, errors.New("task leases are not enabled")
+	}
+	taskIDs, err := m.client.SMembers(ctx, processingKey).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list the tasks being processed: %w", err)
+	}
+	var recovered int
+	for _, taskID := range taskIDs {
+		// The processing outlives the recovery, e.g. the leadership.
+		lease, err := m.claimTask(context.WithoutCancel(ctx), taskID)
+		if errors.Is(err, taskmanager.ErrLeaseHeld) {
+			continue
+		}
+		if err != nil {
+			return recovered, err
+		}
+		task, err := m.getTaskInternal(ctx, taskID)
+		if err != nil && !taskmanager.IsTaskNotFound(err) {
+			m.releaseTask(lease)
+			log.Warnf("Failed to check task %s for recovery: %v", taskID, err)
+			continue
+		}
+		if err != nil || (task.Status.State != protocol.TaskStateSubmitted &&
+			task.Status.State != protocol.TaskStateWorking) {
+			// The processing ended, but not its record.
+			m.releaseTask(lease)
+			continue
+		}
+		m.recoverTask(ctx, lease, task, policy)
+		recovered++
+	}
+	return recovered, nil
+}
+
+// recoverTask applies policy to the working task under lease, which is
+// released once the task is recovered.
+func (m *TaskManager) recoverTask(
+	ctx context.Context,
+	lease *taskmanager.Lease,
+	task *protocol.Task,
+	policy taskmanager.RecoveryPolicy,
+) {
+	switch policy {
+	case taskmanager.RecoveryResume:
+		if resumer, ok := m.processor.(taskmanager.TaskResumer); ok {
+			history, err := m.getMessageHistory(ctx, task.ID, math.MaxInt32)
+			if err != nil {
+				log.Warnf("Resuming task %s without history: %v", task.ID, err)
+			}
+			task.History = history
+			log.Infof("Resuming task %s", task.ID)
+			go m.restartTask(lease, task.ID, func(ctx context.Context, handle taskmanager.TaskHandle) error {
+				return m.runAttempts(ctx, task.ID, func() error {
+					return resumer.ResumeTask(ctx, task, handle)
+				})
+			})
+			return
+		}
+		fallthrough
+	case taskmanager.RecoveryRequeue:
+		if message, ok := m.lastUserMessage(ctx, task.ID); ok {
+			log.Infof("Requeuing task %s", task.ID)
+			go m.restartTask(lease, task.ID, func(ctx context.Context, handle taskmanager.TaskHandle) error {
+				return m.runProcessor(ctx, task.ID, message, handle)
+			})
+			return
+		}
+		log.Warnf("Failing task %s without user message to requeue", task.ID)
+	}
+	defer m.releaseTask(lease)
+	status := taskmanager.FailedStatus(taskmanager.ErrTaskInterrupted)
+	if err := m.transitionTask(contextWithLease(context.Background(), lease), task.ID, status, nil); err != nil {
+		log.Errorf("Failed to fail interrupted task %s: %v", task.ID, err)
+	}
+}
+
+// lastUserMessage returns the last message of the user in the history of
+// taskID.
+func (m *TaskManager) lastUserMessage(ctx context.Context, taskID string) (protocol.Message, bool) {
+	history, err := m.getMessageHistory(ctx, taskID, math.MaxInt32)
+	if err != nil {
+		log.Warnf("Failed to retrieve message history of task %s: %v", taskID, err)
+		return protocol.Message{}, false
+	}
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].Role == protocol.MessageRoleUser {
+			return history[i], true
+		}
+	}
+	return protocol.Message{}, false
+}
+
+// restartTask processes taskID again with process under lease, which it
+// releases once done.
+func (m *TaskManager) restartTask(
+	lease *taskmanager.Lease,
+	taskID string,
+	process func(ctx context.Context, handle taskmanager.TaskHandle) error,
+) {
+	defer m.releaseTask(lease)
+	claimCtx, release, claimed := m.claimProcessing(taskID)
+	if !claimed {
+		log.Warnf("Not restarting task %s, which is being processed", taskID)
+		return
+	}
+	defer m.releaseProcessing(taskID, release)
+	parent := context.Background()
+	if lease != nil {
+		parent = lease.Context()
+	}
+	ctx, cancel := context.WithCancelCause(parent)
+	defer cancel(nil)
+	// OnCancelTask cancels the claim.
+	defer context.AfterFunc(claimCtx, func() { cancel(context.Cause(claimCtx)) })()
+	handle := &redisTaskHandle{taskID: taskID, manager: m, ctx: contextWithLease(context.Background(), lease)}
+	m.endProcessing(lease, handle.ctx, ctx, taskID, process(ctx, handle))
+}
@@ -8,6 +8,9 @@ package redis
 
 import (
 	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/audit"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
 )
 
 // Option is a function that configures the RedisTaskManager.
@@ -19,3 +22,100 @@ func WithExpiration(expiration time.Duration) Option {
 		o.expiration = expiration
 	}
 }
+
+// WithSubscriptionLimits limits the number of simultaneous event streams per task
+// and per caller. By default subscriptions are unlimited.
+func WithSubscriptionLimits(limits taskmanager.SubscriptionLimits) Option {
+	return func(o *TaskManager) {
+		o.subscriptions = taskmanager.NewSubscriptionLimiter(limits)
+	}
+}
+
+// WithTokenAccounting counts the tokens of the text parts exchanged for each task,
+// records them as protocol.TokenUsage in the task metadata and enforces the
+// optional per-task token budget. Accounting is disabled by default.
+func WithTokenAccounting(cfg taskmanager.TokenAccounting) Option {
+	return func(o *TaskManager) {
+		o.usage = taskmanager.NewUsageMeter(cfg)
+	}
+}
+
+// WithSlowConsumerPolicy sets how events are delivered to subscribers that read
+// their stream too slowly. By default events that do not fit in the channel of
+// a subscriber are dropped.
+func WithSlowConsumerPolicy(cfg taskmanager.SlowConsumerConfig) Option {
+	return func(o *TaskManager) {
+		o.slowConsumers = taskmanager.NewSlowConsumerHandler(cfg)
+	}
+}
+
+// WithRetryPolicy runs the processor of a task again, with backoff, when it
+// fails with a retryable error. Failed tasks are not retried by default.
+func WithRetryPolicy(policy taskmanager.RetryPolicy) Option {
+	return func(o *TaskManager) {
+		o.retry = policy
+	}
+}
+
+// WithAuditLog records every state transition and cancellation of the tasks
+// to logger. Auditing is disabled by default.
+func WithAuditLog(logger *audit.Logger) Option {
+	return func(o *TaskManager) {
+		o.auditLog = logger
+	}
+}
+
+// WithEventReplay records the events of the tasks in history, typically an
+// EventHistory in the same Redis, so that clients resubscribing with the ID of
+// the last event they received get the events they missed replayed before live
+// events. Replay is disabled by default.
+func WithEventReplay(history taskmanager.EventHistory) Option {
+	return func(o *TaskManager) {
+		o.events = history
+	}
+}
+
+// WithUsageAccounting counts the tasks submitted, compute time and artifact
+// bytes of each principal in accountant, whose store may be shared by the
+// instances, and rejects the messages of principals over their quota.
+// Accounting is disabled by default.
+func WithUsageAccounting(accountant *taskmanager.UsageAccountant) Option {
+	return func(o *TaskManager) {
+		o.quotas = accountant
+	}
+}
+
+// WithTaskLeases makes the replicas of the task manager sharing a Redis
+// process each task exactly once: a replica processes a task only while it
+// holds its lease, renewed by heartbeats, and the changes of the processing
+// fail with taskmanager.ErrLeaseLost once another replica holds the lease.
+// Run RunTakeover on every replica to process again the tasks of the
+// replicas that died while processing them.
+func WithTaskLeases(cfg taskmanager.LeaseConfig) Option {
+	return func(m *TaskManager) {
+		m.leases = NewLeaser(m.client)
+		m.leaseConfig = cfg.WithDefaults()
+	}
+}
+
+// WithTaskRecovery applies policy to the tasks left working by the processes
+// that stopped while processing them when the task manager is created, see
+// RecoverTasks, and makes RunTakeover apply it rather than requeue the tasks
+// of the replicas that died. It requires WithTaskLeases, which record the
+// tasks being processed: NewRedisTaskManager fails without them.
+func WithTaskRecovery(policy taskmanager.RecoveryPolicy) Option {
+	return func(m *TaskManager) {
+		m.recovery = &policy
+	}
+}
+
+// WithEncryption encrypts the artifacts and message history of the tasks
+// stored in Redis under a data key of keys per task, itself stored encrypted
+// by keys next to the task, and decrypts them transparently. The other fields
+// of the tasks, such as their status, stay in clear. Tasks stored before are
+// still read. Encryption is disabled by default.
+func WithEncryption(keys taskmanager.KeyProvider) Option {
+	return func(o *TaskManager) {
+		o.keys = keys
+	}
+}
@@ -0,0 +1,105 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+const (
+	// pushConfigPrefix prefixes the keys of the push notification states.
+	pushConfigPrefix = "pushcfg:"
+	// pushConfigIndexKey is the set of the IDs of the tasks with a push
+	// notification state.
+	pushConfigIndexKey = "pushcfgs"
+)
+
+// PushConfigStore is a taskmanager.PushConfigStore persisted in Redis, for use
+// with taskmanager.WithPushDelivery, so that push notification configurations
+// and pending deliveries survive restarts. It is safe for concurrent use.
+type PushConfigStore struct {
+	client     redis.UniversalClient
+	expiration time.Duration
+}
+
+// NewPushConfigStore creates a store whose entries expire after expiration
+// since their last update, 30 days if not positive.
+func NewPushConfigStore(client redis.UniversalClient, expiration time.Duration) *PushConfigStore {
+	if expiration <= 0 {
+		expiration = defaultExpiration
+	}
+	return &PushConfigStore{client: client, expiration: expiration}
+}
+
+// SavePushConfig implements taskmanager.PushConfigStore.
+func (s *PushConfigStore) SavePushConfig(
+	ctx context.Context,
+	taskID string,
+	record taskmanager.PushConfigRecord,
+) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal push config of task %s: %w", taskID, err)
+	}
+	if err := s.client.Set(ctx, pushConfigPrefix+taskID, data, s.expiration).Err(); err != nil {
+		return fmt.Errorf("failed to store push config of task %s: %w", taskID, err)
+	}
+	if err := s.client.SAdd(ctx, pushConfigIndexKey, taskID).Err(); err != nil {
+		return fmt.Errorf("failed to index push config of task %s: %w", taskID, err)
+	}
+	return nil
+}
+
+// LoadPushConfigs implements taskmanager.PushConfigStore. Expired entries are
+// removed from the index.
+func (s *PushConfigStore) LoadPushConfigs(ctx context.Context) (map[string]taskmanager.PushConfigRecord, error) {
+	taskIDs, err := s.client.SMembers(ctx, pushConfigIndexKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list push configs: %w", err)
+	}
+	pipe := s.client.Pipeline()
+	gets := make([]*redis.StringCmd, len(taskIDs))
+	for i, taskID := range taskIDs {
+		gets[i] = pipe.Get(ctx, pushConfigPrefix+taskID)
+	}
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, fmt.Errorf("failed to read push configs: %w", err)
+	}
+	records := make(map[string]taskmanager.PushConfigRecord, len(taskIDs))
+	var expired []interface{}
+	for i, taskID := range taskIDs {
+		data, err := gets[i].Bytes()
+		if errors.Is(err, redis.Nil) {
+			expired = append(expired, taskID)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read push config of task %s: %w", taskID, err)
+		}
+		var record taskmanager.PushConfigRecord
+		if err := json.Unmarshal(data, &record); err != nil {
+			log.Warnf("Skipping corrupt push config of task %s: %v", taskID, err)
+			continue
+		}
+		records[taskID] = record
+	}
+	if len(expired) > 0 {
+		if err := s.client.SRem(ctx, pushConfigIndexKey, expired...).Err(); err != nil {
+			log.Warnf("Failed to remove expired push configs from the index: %v", err)
+		}
+	}
+	return records, nil
+}
@@ -0,0 +1,468 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"trpc.group/trpc-go/trpc-a2a-go/config"
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+const (
+	// Keys of the work queue, hash tagged to share a cluster slot.
+	queueStreamKey  = "{queue}:tasks"
+	queueDelayedKey = "{queue}:delayed"
+	// queueTaskField is the field of the stream entries holding the task.
+	queueTaskField = "task"
+	// taskEventsPrefix prefixes the streams of the events of tasks.
+	taskEventsPrefix = "taskevents:"
+	// eventField is the field of the stream entries holding the event.
+	eventField = "event"
+	// maxTaskEvents is the approximate number of events kept per task.
+	maxTaskEvents = 1000
+	// maxEventsRead bounds the events read from a stream at once.
+	maxEventsRead = 100
+	// eventStreamExpiration is how long the events of a task are kept after
+	// its last event.
+	eventStreamExpiration = 24 * time.Hour
+
+	// DefaultQueueGroup is the default consumer group of the workers.
+	DefaultQueueGroup = "a2a-workers"
+	// defaultVisibilityTimeout is the default time after which a task
+	// neither acknowledged nor extended is delivered again.
+	defaultVisibilityTimeout = 30 * time.Second
+	// queuePollInterval bounds the time a dequeue blocks on Redis, so that
+	// delayed and abandoned tasks are picked up.
+	queuePollInterval = time.Second
+	// maxDelayedPromotions bounds the delayed tasks moved to the stream at once.
+	maxDelayedPromotions = 16
+)
+
+// promoteDelayedScript moves the delayed tasks that are due to the stream.
+var promoteDelayedScript = redis.NewScript(`
+local due = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, tonumber(ARGV[2]))
+for _, task in ipairs(due) do
+	redis.call('ZREM', KEYS[1], task)
+	redis.call('XADD', KEYS[2], '*', 'task', task)
+end
+return #due
+`)
+
+// WorkQueueOptions configures a WorkQueue.
+type WorkQueueOptions struct {
+	// Group is the consumer group shared by the workers, DefaultQueueGroup
+	// by default.
+	Group string
+	// Consumer is the name of the worker in the group, the host name and
+	// process ID by default.
+	Consumer string
+	// VisibilityTimeout is the time after which a task neither acknowledged
+	// nor extended is delivered to another worker, 30s by default.
+	VisibilityTimeout time.Duration
+}
+
+// WorkQueue is a taskmanager.WorkQueue over a Redis stream consumed by a
+// consumer group. Tasks returned to the queue with a delay wait in a sorted
+// set. It is safe for concurrent use.
+type WorkQueue struct {
+	client redis.UniversalClient
+	opts   WorkQueueOptions
+	// ownsClient makes Close close the client, created by QueueFactory.
+	ownsClient bool
+
+	mu      sync.Mutex // Guards grouped.
+	grouped bool       // The consumer group exists.
+}
+
+// NewWorkQueue creates a queue of the tasks stored with client.
+func NewWorkQueue(client redis.UniversalClient, opts WorkQueueOptions) *WorkQueue {
+	if opts.Group == "" {
+		opts.Group = DefaultQueueGroup
+	}
+	if opts.Consumer == "" {
+		host, _ := os.Hostname()
+		opts.Consumer = fmt.Sprintf("%s-%d", host, os.Getpid())
+	}
+	if opts.VisibilityTimeout <= 0 {
+		opts.VisibilityTimeout = defaultVisibilityTimeout
+	}
+	return &WorkQueue{client: client, opts: opts}
+}
+
+// Close closes the client of the queue if it was created by QueueFactory,
+// which shares it with the event bus. Queues created with NewWorkQueue leave
+// their client open.
+func (q *WorkQueue) Close() error {
+	if !q.ownsClient {
+		return nil
+	}
+	return q.client.Close()
+}
+
+// Enqueue implements taskmanager.WorkQueue.
+func (q *WorkQueue) Enqueue(ctx context.Context, task taskmanager.QueuedTask) error {
+	if task.EnqueuedAt.IsZero() {
+		task.EnqueuedAt = time.Now().UTC()
+	}
+	data, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("failed to encode task %s: %w", task.Params.ID, err)
+	}
+	err = q.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: queueStreamKey,
+		Values: map[string]interface{}{queueTaskField: data},
+	}).Err()
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task %s: %w", task.Params.ID, err)
+	}
+	return nil
+}
+
+// Dequeue implements taskmanager.WorkQueue. Tasks abandoned by their worker
+// are claimed before new tasks are read.
+func (q *WorkQueue) Dequeue(ctx context.Context) (taskmanager.QueueDelivery, error) {
+	if err := q.ensureGroup(ctx); err != nil {
+		return nil, err
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		err := promoteDelayedScript.Run(ctx, q.client,
+			[]string{queueDelayedKey, queueStreamKey},
+			time.Now().UnixMilli(), maxDelayedPromotions,
+		).Err()
+		if err != nil {
+			return nil, fmt.Errorf("failed to promote delayed tasks: %w", err)
+		}
+		claimed, _, err := q.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+			Stream:   queueStreamKey,
+			Group:    q.opts.Group,
+			Consumer: q.opts.Consumer,
+			MinIdle:  q.opts.VisibilityTimeout,
+			Start:    "0",
+			Count:    1,
+		}).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to claim abandoned tasks: %w", err)
+		}
+		if len(claimed) > 0 {
+			if delivery := q.delivery(ctx, claimed[0]); delivery != nil {
+				return delivery, nil
+			}
+			continue
+		}
+		streams, err := q.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    q.opts.Group,
+			Consumer: q.opts.Consumer,
+			Streams:  []string{queueStreamKey, ">"},
+			Count:    1,
+			Block:    pollDuration(ctx),
+		}).Result()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, fmt.Errorf("failed to read tasks: %w", err)
+		}
+		for _, stream := range streams {
+			for _, msg := range stream.Messages {
+				if delivery := q.delivery(ctx, msg); delivery != nil {
+					return delivery, nil
+				}
+			}
+		}
+	}
+}
+
+// ensureGroup creates the consumer group of the workers if needed.
+func (q *WorkQueue) ensureGroup(ctx context.Context) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.grouped {
+		return nil
+	}
+	err := q.client.XGroupCreateMkStream(ctx, queueStreamKey, q.opts.Group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("failed to create consumer group %s: %w", q.opts.Group, err)
+	}
+	q.grouped = true
+	return nil
+}
+
+// delivery returns the delivery of msg, or nil if msg is not a task, in
+// which case it is removed from the queue.
+func (q *WorkQueue) delivery(ctx context.Context, msg redis.XMessage) *queueDelivery {
+	data, _ := msg.Values[queueTaskField].(string)
+	var task taskmanager.QueuedTask
+	if err := json.Unmarshal([]byte(data), &task); err != nil || task.Params.ID == "" {
+		log.Errorf("Dropping invalid task entry %s of %s: %v", msg.ID, queueStreamKey, err)
+		if err := q.remove(ctx, msg.ID, nil); err != nil {
+			log.Warnf("Failed to remove invalid task entry %s: %v", msg.ID, err)
+		}
+		return nil
+	}
+	return &queueDelivery{queue: q, id: msg.ID, task: task}
+}
+
+// remove acknowledges and deletes the entry id, running then in the same
+// transaction if not nil.
+func (q *WorkQueue) remove(ctx context.Context, id string, then func(pipe redis.Pipeliner)) error {
+	_, err := q.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.XAck(ctx, queueStreamKey, q.opts.Group, id)
+		pipe.XDel(ctx, queueStreamKey, id)
+		if then != nil {
+			then(pipe)
+		}
+		return nil
+	})
+	return err
+}
+
+// pollDuration returns how long to block on Redis before ctx is done.
+func pollDuration(ctx context.Context) time.Duration {
+	block := queuePollInterval
+	if deadline, ok := ctx.Deadline(); ok {
+		if until := time.Until(deadline); until < block {
+			block = until
+		}
+	}
+	// A zero block would block forever.
+	if block < time.Millisecond {
+		block = time.Millisecond
+	}
+	return block
+}
+
+// queueDelivery is a taskmanager.QueueDelivery of a WorkQueue.
+type queueDelivery struct {
+	queue *WorkQueue
+	id    string
+	task  taskmanager.QueuedTask
+}
+
+// Task implements taskmanager.QueueDelivery.
+func (d *queueDelivery) Task() taskmanager.QueuedTask {
+	return d.task
+}
+
+// Ack implements taskmanager.QueueDelivery.
+func (d *queueDelivery) Ack(ctx context.Context) error {
+	return d.queue.remove(ctx, d.id, nil)
+}
+
+// Nack implements taskmanager.QueueDelivery.
+func (d *queueDelivery) Nack(ctx context.Context, delay time.Duration) error {
+	data, err := json.Marshal(d.task)
+	if err != nil {
+		return fmt.Errorf("failed to encode task %s: %w", d.task.Params.ID, err)
+	}
+	return d.queue.remove(ctx, d.id, func(pipe redis.Pipeliner) {
+		if delay <= 0 {
+			pipe.XAdd(ctx, &redis.XAddArgs{
+				Stream: queueStreamKey,
+				Values: map[string]interface{}{queueTaskField: data},
+			})
+			return
+		}
+		pipe.ZAdd(ctx, queueDelayedKey, redis.Z{
+			Score:  float64(time.Now().Add(delay).UnixMilli()),
+			Member: data,
+		})
+	})
+}
+
+// Extend implements taskmanager.QueueDelivery, resetting the idle time of
+// the entry.
+func (d *queueDelivery) Extend(ctx context.Context) error {
+	return d.queue.client.XClaimJustID(ctx, &redis.XClaimArgs{
+		Stream:   queueStreamKey,
+		Group:    d.queue.opts.Group,
+		Consumer: d.queue.opts.Consumer,
+		Messages: []string{d.id},
+	}).Err()
+}
+
+// EventBus is a taskmanager.EventBus over a Redis stream per task, read from
+// the position of the subscription, so that the events published while a
+// subscriber reconnects are not lost. The streams keep their last
+// maxTaskEvents events and expire eventStreamExpiration after their last
+// event. It is safe for concurrent use.
+type EventBus struct {
+	client redis.UniversalClient
+}
+
+// NewEventBus creates a bus publishing the events through client.
+func NewEventBus(client redis.UniversalClient) *EventBus {
+	return &EventBus{client: client}
+}
+
+// Publish implements taskmanager.EventBus.
+func (b *EventBus) Publish(ctx context.Context, taskID string, event protocol.TaskEvent) error {
+	tagged, err := protocol.NewPolledEvent(event)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(tagged)
+	if err != nil {
+		return fmt.Errorf("failed to encode event of task %s: %w", taskID, err)
+	}
+	key := taskEventsPrefix + taskID
+	if _, err := b.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.XAdd(ctx, &redis.XAddArgs{
+			Stream: key,
+			MaxLen: maxTaskEvents,
+			Approx: true,
+			Values: map[string]interface{}{eventField: data},
+		})
+		pipe.Expire(ctx, key, eventStreamExpiration)
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to publish event of task %s: %w", taskID, err)
+	}
+	return nil
+}
+
+// Subscribe implements taskmanager.EventBus. The events are read from the
+// last event of the stream when Subscribe is called, and read again from
+// there after Redis failures.
+func (b *EventBus) Subscribe(ctx context.Context, taskID string) (<-chan protocol.TaskEvent, error) {
+	key := taskEventsPrefix + taskID
+	last, err := b.client.XRevRangeN(ctx, key, "+", "-", 1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe to the events of task %s: %w", taskID, err)
+	}
+	position := "0-0"
+	if len(last) > 0 {
+		position = last[0].ID
+	}
+	events := make(chan protocol.TaskEvent)
+	go func() {
+		defer close(events)
+		for ctx.Err() == nil {
+			streams, err := b.client.XRead(ctx, &redis.XReadArgs{
+				Streams: []string{key, position},
+				Count:   maxEventsRead,
+				Block:   queuePollInterval,
+			}).Result()
+			if errors.Is(err, redis.Nil) {
+				continue
+			}
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Warnf("Failed to read the events of task %s, retrying: %v", taskID, err)
+					sleep(ctx, queuePollInterval)
+				}
+				continue
+			}
+			for _, stream := range streams {
+				for _, msg := range stream.Messages {
+					position = msg.ID
+					data, _ := msg.Values[eventField].(string)
+					var tagged protocol.PolledEvent
+					if err := json.Unmarshal([]byte(data), &tagged); err != nil {
+						log.Errorf("Dropping invalid event message of task %s: %v", taskID, err)
+						continue
+					}
+					select {
+					case events <- tagged.Event:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}
+	}()
+	return events, nil
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
+
+// QueueFactory is a config.QueueFactory of a Redis work queue and event bus:
+//
+//	srv, err := config.Build(cfg, nil, config.WithQueue("redis", redis.QueueFactory))
+//
+// The options of the queue are address ("localhost:6379" by default),
+// password, db, group and visibilityTimeout, e.g. "30s". Closing the queue
+// closes the Redis client it shares with the bus.
+func QueueFactory(cfg config.QueueConfig) (taskmanager.WorkQueue, taskmanager.EventBus, error) {
+	redisOpts := &redis.Options{Addr: "localhost:6379"}
+	var opts WorkQueueOptions
+	for name, value := range cfg.Options {
+		var err error
+		switch name {
+		case "address":
+			redisOpts.Addr, err = stringOption(name, value)
+		case "password":
+			redisOpts.Password, err = stringOption(name, value)
+		case "db":
+			redisOpts.DB, err = intOption(name, value)
+		case "group":
+			opts.Group, err = stringOption(name, value)
+		case "visibilityTimeout":
+			var timeout string
+			if timeout, err = stringOption(name, value); err == nil {
+				opts.VisibilityTimeout, err = time.ParseDuration(timeout)
+			}
+		default:
+			err = fmt.Errorf("unknown option %q", name)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	client := redis.NewClient(redisOpts)
+	queue := NewWorkQueue(client, opts)
+	queue.ownsClient = true
+	return queue, NewEventBus(client), nil
+}
+
+// stringOption returns the string value of the option name.
+func stringOption(name string, value interface{}) (string, error) {
+	s, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("option %q must be a string", name)
+	}
+	return s, nil
+}
+
+// intOption returns the integer value of the option name.
+func intOption(name string, value interface{}) (int, error) {
+	switch v := value.(type) {
+	case float64:
+		return int(v), nil
+	case int:
+		return v, nil
+	case string:
+		return strconv.Atoi(v)
+	}
+	return 0, fmt.Errorf("option %q must be a number", name)
+}
@@ -12,11 +12,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"hash/fnv"
 	"sync"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 
+	"trpc.group/trpc-go/trpc-a2a-go/audit"
 	"trpc.group/trpc-go/trpc-a2a-go/auth"
 	"trpc.group/trpc-go/trpc-a2a-go/log"
 	"trpc.group/trpc-go/trpc-a2a-go/protocol"
@@ -32,6 +34,12 @@ const (
 
 	// Default expiration time for Redis keys (30 days).
 	defaultExpiration = 30 * 24 * time.Hour
+
+	// maxTaskUpdateAttempts bounds the attempts of a task update while other
+	// writers, such as other replicas, change the task concurrently.
+	maxTaskUpdateAttempts = 10
+	// eventLockShards is the number of locks serializing the events of tasks.
+	eventLockShards = 64
 )
 
 // TaskManager provides a concrete, Redis-based implementation of the
@@ -50,11 +58,49 @@ type TaskManager struct {
 	subMu sync.RWMutex
 	// subscribers is a map of task IDs to subscriber channels.
 	subscribers map[string][]chan<- protocol.TaskEvent
+	// subscriptions enforces subscription limits. Guarded by subMu.
+	subscriptions *taskmanager.SubscriptionLimiter
+	// usage counts the tokens exchanged for tasks.
+	usage *taskmanager.UsageMeter
+	// slowConsumers delivers events to subscribers that read too slowly.
+	slowConsumers *taskmanager.SlowConsumerHandler
+	// retry determines whether failed tasks are processed again.
+	retry taskmanager.RetryPolicy
+	// graphs runs the task graphs sent through OnSendTaskGraph. Graphs are
+	// kept in the memory of this instance.
+	graphs *taskmanager.GraphScheduler
+	// quotas accounts the usage of principals and enforces their quotas, if set.
+	quotas *taskmanager.UsageAccountant
+	// maxArtifactBytes bounds the size of artifacts, if positive.
+	maxArtifactBytes int64
+	// auditLog records state transitions and cancellations, if set.
+	auditLog *audit.Logger
+	// events keeps the events of tasks for replay, if set. Events are
+	// recorded under the event lock of their task.
+	events taskmanager.EventHistory
+	// eventLocks serialize the numbering and delivery of the events of the
+	// tasks hashed to them, so that subscribers receive them in ID order
+	// without holding subMu while they are recorded in Redis.
+	eventLocks [eventLockShards]sync.Mutex
+	// leases, if set, makes the processing of a task exclusive to the
+	// replica holding its lease.
+	leases taskmanager.Leaser
+	// leaseConfig configures the leases held by this replica.
+	leaseConfig taskmanager.LeaseConfig
+	// recovery is applied on creation to the tasks left working, and by
+	// TakeOverTasks to the tasks of dead replicas, if set.
+	recovery *taskmanager.RecoveryPolicy
 
 	// cancelMu is a mutex for the cancels map.
 	cancelMu sync.RWMutex
 	// cancels is a map of task IDs to cancellation functions.
-	cancels map[string]context.CancelFunc
+	// The cause passed to the function is a *taskmanager.CancelError.
+	cancels map[string]context.CancelCauseFunc
+
+	// keys encrypts the artifacts and message history of the tasks, if set.
+	keys taskmanager.KeyProvider
+	// dataKeys caches the data keys of the tasks when keys is set.
+	dataKeys dataKeyCache
 
 	// pushAuth is the push notification authenticator.
 	pushAuth *auth.PushNotificationAuthenticator
@@ -77,32 +123,110 @@ func NewRedisTaskManager(
 	}
 	expiration := defaultExpiration
 	manager := &TaskManager{
-		processor:   processor,
-		client:      client,
-		expiration:  expiration,
-		subscribers: make(map[string][]chan<- protocol.TaskEvent),
-		cancels:     make(map[string]context.CancelFunc),
+		processor:     processor,
+		client:        client,
+		expiration:    expiration,
+		subscribers:   make(map[string][]chan<- protocol.TaskEvent),
+		subscriptions: taskmanager.NewSubscriptionLimiter(taskmanager.SubscriptionLimits{}),
+		usage:         taskmanager.NewUsageMeter(taskmanager.TokenAccounting{}),
+		slowConsumers: taskmanager.NewSlowConsumerHandler(taskmanager.SlowConsumerConfig{}),
+		cancels:       make(map[string]context.CancelCauseFunc),
 	}
+	manager.graphs = taskmanager.NewGraphScheduler(manager)
 	for _, opt := range opts {
 		opt(manager)
 	}
+	if manager.recovery != nil && manager.leases == nil {
+		return nil, errors.New("task recovery requires task leases, set with WithTaskLeases")
+	}
+	if manager.recovery != nil {
+		recovered, err := manager.RecoverTasks(context.Background(), *manager.recovery)
+		if err != nil {
+			return nil, fmt.Errorf("failed to recover tasks: %w", err)
+		}
+		if recovered > 0 {
+			log.Infof("Recovered %d tasks interrupted by a restart with policy %s", recovered, *manager.recovery)
+		}
+	}
 	return manager, nil
 }
 
+// SubscriptionStats returns a snapshot of the subscription counters.
+func (m *TaskManager) SubscriptionStats() taskmanager.SubscriptionStats {
+	return m.subscriptions.Stats()
+}
+
+// SlowConsumerStats returns the counters of events queued, dropped and spilled
+// for slow subscribers and of slow subscribers disconnected.
+func (m *TaskManager) SlowConsumerStats() taskmanager.SlowConsumerStats {
+	return m.slowConsumers.Stats()
+}
+
+// SetSubscriptionLimits replaces the limits set by WithSubscriptionLimits, for
+// instance when the configuration is reloaded. Existing streams are kept.
+func (m *TaskManager) SetSubscriptionLimits(limits taskmanager.SubscriptionLimits) {
+	m.subscriptions.SetLimits(limits)
+}
+
 // redisTaskHandle implements the TaskHandle interface for Redis.
 type redisTaskHandle struct {
 	taskID  string
 	manager *TaskManager
+	// principal is charged for the artifacts of the task, if usage is accounted.
+	principal string
+	// ctx is the context of the changes of the task, fenced by the lease of
+	// its processing if leases are enabled.
+	ctx context.Context
+}
+
+// context returns the context of the changes of the task.
+func (h *redisTaskHandle) context() context.Context {
+	if h.ctx == nil {
+		return context.Background()
+	}
+	return h.ctx
 }
 
 // UpdateStatus implements TaskHandle.
 func (h *redisTaskHandle) UpdateStatus(state protocol.TaskState, msg *protocol.Message) error {
-	return h.manager.UpdateTaskStatus(h.taskID, state, msg)
+	return h.manager.transitionTask(h.context(), h.taskID, protocol.TaskStatus{State: state, Message: msg}, nil)
+}
+
+// UpdateStatusWithMetadata implements taskmanager.StatusMetadataUpdater.
+func (h *redisTaskHandle) UpdateStatusWithMetadata(
+	state protocol.TaskState,
+	msg *protocol.Message,
+	metadata map[string]interface{},
+) error {
+	return h.manager.transitionTask(h.context(), h.taskID, protocol.TaskStatus{State: state, Message: msg}, metadata)
 }
 
 // AddArtifact implements TaskHandle
 func (h *redisTaskHandle) AddArtifact(artifact protocol.Artifact) error {
-	return h.manager.AddArtifact(h.taskID, artifact)
+	if err := h.manager.addArtifact(h.context(), h.taskID, artifact); err != nil {
+		return err
+	}
+	h.manager.quotas.RecordArtifact(h.principal, artifact)
+	return nil
+}
+
+// SaveCheckpoint implements taskmanager.Checkpointer.
+func (h *redisTaskHandle) SaveCheckpoint(checkpoint protocol.TaskCheckpoint) error {
+	_, err := h.manager.updateTask(h.context(), h.taskID, func(task *protocol.Task) error {
+		task.Metadata = protocol.WithCheckpoint(task.Metadata, checkpoint)
+		return nil
+	})
+	return err
+}
+
+// LoadCheckpoint implements taskmanager.Checkpointer.
+func (h *redisTaskHandle) LoadCheckpoint() (protocol.TaskCheckpoint, bool, error) {
+	task, err := h.manager.readTask(h.context(), h.taskID)
+	if err != nil {
+		return protocol.TaskCheckpoint{}, false, err
+	}
+	checkpoint, ok := task.Checkpoint()
+	return checkpoint, ok, nil
 }
 
 // IsStreamingRequest implements TaskHandle.
@@ -116,39 +240,89 @@ func (h *redisTaskHandle) IsStreamingRequest() bool {
 	return exists && len(subscribers) > 0
 }
 
+// runProcessor runs the processor on the task, running it again on retryable
+// failures according to the retry policy.
+func (m *TaskManager) runProcessor(
+	ctx context.Context,
+	taskID string,
+	message protocol.Message,
+	handle taskmanager.TaskHandle,
+) error {
+	return m.runAttempts(ctx, taskID, func() error {
+		return m.processor.Process(ctx, taskID, message, handle)
+	})
+}
+
+// runAttempts runs process, the processing of a task, again on retryable
+// failures according to the retry policy.
+func (m *TaskManager) runAttempts(ctx context.Context, taskID string, process func() error) error {
+	start := time.Now()
+	defer func() {
+		m.quotas.RecordCompute(m.quotas.Principal(ctx), time.Since(start))
+	}()
+	return m.retry.Run(ctx, process, func(attempt protocol.TaskAttempt) error {
+		log.Warnf("Retrying task %s after attempt %d failed: %s", taskID, attempt.Attempt, attempt.Error.Message)
+		status, metadata := taskmanager.RetryStatus(attempt, m.retry.MaxAttempts)
+		return m.setTaskStatus(taskID, status, metadata)
+	})
+}
+
 // OnSendTask handles the creation or retrieval of a task and initiates synchronous processing.
 func (m *TaskManager) OnSendTask(ctx context.Context, params protocol.SendTaskParams) (*protocol.Task, error) {
+	if err := m.quotas.Admit(ctx, params.ID); err != nil {
+		return nil, err
+	}
+	// Claim the processing of the task on this replica, unless it is being
+	// processed.
+	claimCtx, release, claimed := m.claimProcessing(params.ID)
+	if claimed {
+		defer m.releaseProcessing(params.ID, release)
+	}
 	// Create or update task
-	_ = m.upsertTask(ctx, params)
+	_, action, err := m.upsertTask(ctx, params, !claimed)
+	if err != nil {
+		return nil, err
+	}
 	// Store the initial message
 	m.storeMessage(ctx, params.ID, params.Message)
-	// Create a cancellable context for this specific task processing
-	taskCtx, cancel := context.WithCancel(ctx)
-	defer cancel() // Ensure context is cancelled eventually.
+	if action == taskmanager.SendAppend {
+		// The task is being processed: the message only joins its history.
+		return m.getTaskInternal(ctx, params.ID)
+	}
+	lease, err := m.claimTask(ctx, params.ID)
+	if errors.Is(err, taskmanager.ErrLeaseHeld) {
+		// Another replica processes the task: the message only joins its history.
+		return m.getTaskInternal(ctx, params.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer m.releaseTask(lease)
+	// Create a cancellable context for this specific task processing, which
+	// is canceled if the lease of the processing is lost.
+	processingCtx := ctx
+	if lease != nil {
+		processingCtx = lease.Context()
+	}
+	taskCtx, cancel := context.WithCancelCause(processingCtx)
+	defer cancel(nil) // Ensure context is cancelled eventually.
+	// OnCancelTask cancels the claim.
+	defer context.AfterFunc(claimCtx, func() { cancel(context.Cause(claimCtx)) })()
 	handle := &redisTaskHandle{
-		taskID:  params.ID,
-		manager: m,
+		taskID:    params.ID,
+		manager:   m,
+		principal: m.quotas.Principal(ctx),
+		ctx:       contextWithLease(context.Background(), lease),
 	}
 	// Set initial status to Working *before* calling Process.
-	if err := m.UpdateTaskStatus(params.ID, protocol.TaskStateWorking, nil); err != nil {
+	if err := handle.UpdateStatus(protocol.TaskStateWorking, nil); err != nil {
 		log.Errorf("Error setting initial Working status for task %s: %v", params.ID, err)
 		// Return the task state as it exists, but also the error.
 		latestTask, _ := m.getTaskInternal(ctx, params.ID) // Ignore get error for now.
 		return latestTask, fmt.Errorf("failed to set initial working status: %w", err)
 	}
 	// Delegate the actual processing to the injected processor (synchronously).
-	var processorErr error
-	if processorErr = m.processor.Process(taskCtx, params.ID, params.Message, handle); processorErr != nil {
-		log.Errorf("Processor failed for task %s: %v", params.ID, processorErr)
-		errMsg := &protocol.Message{
-			Role:  protocol.MessageRoleAgent,
-			Parts: []protocol.Part{protocol.NewTextPart(processorErr.Error())},
-		}
-		// Log update error while still handling the processor error.
-		if updateErr := m.UpdateTaskStatus(params.ID, protocol.TaskStateFailed, errMsg); updateErr != nil {
-			log.Errorf("Failed to update task %s status to failed: %v", params.ID, updateErr)
-		}
-	}
+	m.endProcessing(lease, handle.ctx, taskCtx, params.ID, m.runProcessor(taskCtx, params.ID, params.Message, handle))
 	// Return the latest task state after processing.
 	finalTask, getErr := m.getTaskInternal(ctx, params.ID)
 	if getErr != nil {
@@ -159,68 +333,166 @@ func (m *TaskManager) OnSendTask(ctx context.Context, params protocol.SendTaskPa
 	return finalTask, nil
 }
 
+// failTask records the failure of the processing of taskID with err, unless
+// the task was canceled through OnCancelTask, which already set its final
+// state, or another replica took over its processing. The status is set with
+// changeCtx, the context of the changes of the task.
+func (m *TaskManager) failTask(changeCtx, processingCtx context.Context, taskID string, err error) {
+	log.Errorf("Processor failed for task %s: %v", taskID, err)
+	if _, canceled := taskmanager.CancelReasonFromContext(processingCtx); canceled {
+		return
+	}
+	if errors.Is(context.Cause(processingCtx), taskmanager.ErrLeaseLost) {
+		log.Warnf("Not failing task %s, whose lease was lost", taskID)
+		return
+	}
+	if updateErr := m.transitionTask(changeCtx, taskID, taskmanager.FailedStatus(err), nil); updateErr != nil {
+		log.Errorf("Failed to update task %s status to failed: %v", taskID, updateErr)
+	}
+}
+
+// PlanTask implements taskmanager.TaskPlanner. It checks the message against
+// the token budget of the task, which need not exist yet.
+func (m *TaskManager) PlanTask(ctx context.Context, params protocol.SendTaskParams) (*protocol.TaskPlan, error) {
+	var metadata map[string]interface{}
+	taskBytes, err := m.client.Get(ctx, taskPrefix+params.ID).Bytes()
+	switch {
+	case err == nil:
+		task, err := m.decodeTask(ctx, taskBytes)
+		if err != nil {
+			return nil, err
+		}
+		metadata = task.Metadata
+	case err != redis.Nil:
+		return nil, fmt.Errorf("failed to retrieve task from Redis: %w", err)
+	}
+	return m.usage.Plan(params.ID, metadata, params.Message.Parts)
+}
+
+// OnSendTaskGraph implements taskmanager.TaskGraphRunner. The tasks of the
+// graph are sent through OnSendTask once their dependencies completed.
+func (m *TaskManager) OnSendTaskGraph(
+	ctx context.Context,
+	params protocol.SendTaskGraphParams,
+) (*protocol.TaskGraph, error) {
+	return m.graphs.Send(ctx, params)
+}
+
+// OnGetTaskGraph implements taskmanager.TaskGraphRunner.
+func (m *TaskManager) OnGetTaskGraph(
+	ctx context.Context,
+	params protocol.TaskIDParams,
+) (*protocol.TaskGraph, error) {
+	return m.graphs.Get(params.ID)
+}
+
 // OnSendTaskSubscribe creates a new task and returns a channel for receiving TaskEvent updates.
 func (m *TaskManager) OnSendTaskSubscribe(
 	ctx context.Context,
 	params protocol.SendTaskParams,
 ) (<-chan protocol.TaskEvent, error) {
+	// Create event channel for this specific subscriber.
+	// Subscribe first so a rejected subscription leaves the task untouched.
+	eventChan := make(chan protocol.TaskEvent, 10) // Buffered to prevent blocking sends.
+	if err := m.addSubscriber(ctx, params.ID, eventChan); err != nil {
+		return nil, err
+	}
+	if err := m.quotas.Admit(ctx, params.ID); err != nil {
+		if m.removeSubscriber(params.ID, eventChan) {
+			close(eventChan)
+		}
+		return nil, err
+	}
+	// Claim the processing of the task on this replica, unless it is being
+	// processed.
+	claimCtx, release, claimed := m.claimProcessing(params.ID)
 	// Create a new task or update an existing one.
-	task := m.upsertTask(ctx, params)
+	task, action, err := m.upsertTask(ctx, params, !claimed)
+	if err != nil {
+		if claimed {
+			m.releaseProcessing(params.ID, release)
+		}
+		if m.removeSubscriber(params.ID, eventChan) {
+			close(eventChan)
+		}
+		return nil, err
+	}
 	// Store the message that came with the request.
 	m.storeMessage(ctx, params.ID, params.Message)
-	// Create event channel for this specific subscriber.
-	eventChan := make(chan protocol.TaskEvent, 10) // Buffered to prevent blocking sends.
-	m.addSubscriber(params.ID, eventChan)
-	// Create a cancellable context for the processor.
-	processorCtx, cancel := context.WithCancel(ctx)
-	// Store the cancel function.
-	m.cancelMu.Lock()
-	m.cancels[params.ID] = cancel
-	m.cancelMu.Unlock()
+	if action == taskmanager.SendAppend {
+		// The task is being processed: the message only joins its history,
+		// and the stream follows the current processing.
+		m.unsubscribeOnDone(ctx, params.ID, eventChan)
+		return eventChan, nil
+	}
+	lease, err := m.claimTask(ctx, params.ID)
+	if errors.Is(err, taskmanager.ErrLeaseHeld) {
+		// Another replica processes the task: the message only joins its history.
+		m.releaseProcessing(params.ID, release)
+		m.unsubscribeOnDone(ctx, params.ID, eventChan)
+		return eventChan, nil
+	}
+	if err != nil {
+		m.releaseProcessing(params.ID, release)
+		if m.removeSubscriber(params.ID, eventChan) {
+			close(eventChan)
+		}
+		return nil, err
+	}
+	// Create a cancellable context for the processor, which is canceled if
+	// the lease of the processing is lost.
+	processingCtx := ctx
+	if lease != nil {
+		processingCtx = lease.Context()
+	}
+	processorCtx, cancel := context.WithCancelCause(processingCtx)
+	// OnCancelTask cancels the claim.
+	stop := context.AfterFunc(claimCtx, func() { cancel(context.Cause(claimCtx)) })
+	// Create a handle for the processor to interact with the task.
+	handle := &redisTaskHandle{
+		taskID:    params.ID,
+		manager:   m,
+		principal: m.quotas.Principal(processorCtx),
+		ctx:       contextWithLease(context.Background(), lease),
+	}
 	// Set initial state if new (submitted -> working).
 	// This will generate the first event for subscribers.
 	if task.Status.State == protocol.TaskStateSubmitted {
-		if err := m.UpdateTaskStatus(params.ID, protocol.TaskStateWorking, nil); err != nil {
-			m.removeSubscriber(params.ID, eventChan)
-			close(eventChan)
+		if err := handle.UpdateStatus(protocol.TaskStateWorking, nil); err != nil {
+			stop()
+			cancel(nil)
+			m.releaseProcessing(params.ID, release)
+			m.releaseTask(lease)
+			if m.removeSubscriber(params.ID, eventChan) {
+				close(eventChan)
+			}
 			return nil, err
 		}
 	}
+	// Stop delivering events once the client disconnected; it can resubscribe
+	// to the task.
+	m.unsubscribeOnDone(ctx, params.ID, eventChan)
 	// Start the processor in a goroutine.
 	go func() {
-		// Create a handle for the processor to interact with the task.
-		handle := &redisTaskHandle{
-			taskID:  params.ID,
-			manager: m,
-		}
+		defer m.releaseTask(lease)
+		defer cancel(nil)
 		log.Debugf("SSE Processor started for task %s", params.ID)
-		var err error
-		if err = m.processor.Process(processorCtx, params.ID, params.Message, handle); err != nil {
+		err := m.runProcessor(processorCtx, params.ID, params.Message, handle)
+		if err != nil && processorCtx.Err() == context.Canceled {
+			// Canceled tasks already have their final state, if any.
 			log.Errorf("Processor failed for task %s in subscribe: %v", params.ID, err)
-			if processorCtx.Err() != context.Canceled {
-				// Only update to failed if not already cancelled.
-				errMsg := &protocol.Message{
-					Role:  protocol.MessageRoleAgent,
-					Parts: []protocol.Part{protocol.NewTextPart(err.Error())},
-				}
-				if updateErr := m.UpdateTaskStatus(
-					params.ID,
-					protocol.TaskStateFailed,
-					errMsg,
-				); updateErr != nil {
-					log.Errorf("Failed to update task %s status to failed: %v", params.ID, updateErr)
-				}
-			}
+		} else {
+			m.endProcessing(lease, handle.ctx, processorCtx, params.ID, err)
 		}
 		// Clean up the context regardless of how we finish.
-		m.cancelMu.Lock()
-		delete(m.cancels, params.ID)
-		m.cancelMu.Unlock()
+		stop()
+		m.releaseProcessing(params.ID, release)
 		log.Debugf("Processor finished for task %s in subscribe (Error: %v). Goroutine exiting.", params.ID, err)
 		// Close event channel and clean up subscriber.
 		log.Debugf("Closing event channel and removing subscriber for task %s.", params.ID)
-		m.removeSubscriber(params.ID, eventChan)
-		close(eventChan)
+		if m.removeSubscriber(params.ID, eventChan) {
+			close(eventChan)
+		}
 	}()
 	// Return the channel for events.
 	return eventChan, nil
@@ -257,35 +529,45 @@ func (m *TaskManager) OnCancelTask(
 	if err != nil {
 		return nil, err
 	}
+	// A conflict takes precedence over the final state of the task.
+	if err := taskmanager.CheckTaskVersion(ctx, params.ID, task); err != nil {
+		return nil, err
+	}
 	// Check if task is already in a final state.
 	if isFinalState(task.Status.State) {
 		return task, taskmanager.ErrTaskFinalState(params.ID, task.Status.State)
 	}
-	var cancelFound bool
+	reason := taskmanager.NormalizeCancelReason(params.Reason)
+	_ = m.auditLog.Record(ctx, audit.Event{
+		Type:   audit.EventCancellation,
+		TaskID: params.ID,
+		Caller: taskmanager.CallerFromContext(ctx),
+		Reason: reason,
+	})
+	// Update state to Cancelled, recording the reason, before canceling the
+	// processor: the update checks the expected version and that the task did
+	// not end since, atomically.
+	status := protocol.TaskStatus{
+		State:        protocol.TaskStateCanceled,
+		Message:      taskmanager.NewCancelMessage(params.ID, reason),
+		CancelReason: reason,
+		Error:        protocol.NewCancelTaskError(reason),
+	}
+	if err := m.transitionTask(ctx, params.ID, status, nil); err != nil {
+		log.Errorf("Error updating status to Cancelled for task %s: %v", params.ID, err)
+		return nil, err
+	}
 	m.cancelMu.Lock()
 	cancel, exists := m.cancels[params.ID]
 	if exists {
-		cancel() // Call the cancel function.
-		cancelFound = true
+		cancel(&taskmanager.CancelError{TaskID: params.ID, Reason: reason}) // Call the cancel function.
 		// Don't delete the context here - let the processor goroutine clean up.
 	}
 	m.cancelMu.Unlock()
 	// If no cancellation function was found, log a warning.
-	if !cancelFound {
+	if !exists {
 		log.Warnf("Warning: No cancellation function found for task %s", params.ID)
 	}
-	// Create a cancellation message.
-	cancelMsg := &protocol.Message{
-		Role: protocol.MessageRoleAgent,
-		Parts: []protocol.Part{
-			protocol.NewTextPart(fmt.Sprintf("Task %s was canceled by user request", params.ID)),
-		},
-	}
-	// Update state to Cancelled.
-	if err := m.UpdateTaskStatus(params.ID, protocol.TaskStateCanceled, cancelMsg); err != nil {
-		log.Errorf("Error updating status to Cancelled for task %s: %v", params.ID, err)
-		return nil, err
-	}
 	// Fetch the updated task state to return.
 	updatedTask, err := m.getTaskInternal(ctx, params.ID)
 	if err != nil {
@@ -295,6 +577,48 @@ func (m *TaskManager) OnCancelTask(
 	return updatedTask, nil
 }
 
+// DeleteTask implements taskmanager.TaskDeleter. The task is deleted from
+// Redis with its history, push notification configuration, data key and
+// events; only the processing and streams of this replica are ended.
+func (m *TaskManager) DeleteTask(ctx context.Context, taskID string) error {
+	exists, err := m.client.Exists(ctx, taskPrefix+taskID).Result()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve task from Redis: %w", err)
+	}
+	if exists == 0 {
+		return taskmanager.ErrTaskNotFound(taskID)
+	}
+	m.cancelMu.Lock()
+	if cancel, ok := m.cancels[taskID]; ok {
+		cancel(&taskmanager.CancelError{TaskID: taskID, Reason: protocol.CancelReasonUserRequested})
+	}
+	m.cancelMu.Unlock()
+	if err := m.client.Del(ctx,
+		taskPrefix+taskID,
+		messagePrefix+taskID,
+		pushNotificationPrefix+taskID,
+		dataKeyPrefix+taskID,
+		eventsPrefix+taskID,
+		eventSeqPrefix+taskID,
+	).Err(); err != nil {
+		return fmt.Errorf("failed to delete task %s from Redis: %w", taskID, err)
+	}
+	m.dataKeys.forget(taskID)
+	eventLock := m.eventLock(taskID)
+	eventLock.Lock()
+	m.subMu.Lock()
+	for _, ch := range m.subscribers[taskID] {
+		if m.subscriptions.Release(taskID, ch) {
+			m.slowConsumers.Finish(ch)
+		}
+	}
+	delete(m.subscribers, taskID)
+	m.subMu.Unlock()
+	eventLock.Unlock()
+	log.Infof("Deleted task %s", taskID)
+	return nil
+}
+
 // OnPushNotificationSet configures push notifications for a specific task
 func (m *TaskManager) OnPushNotificationSet(
 	ctx context.Context,
@@ -358,10 +682,12 @@ func (m *TaskManager) OnResubscribe(
 	if err != nil {
 		return nil, err
 	}
-	// Create a channel for events.
-	eventChan := make(chan protocol.TaskEvent)
+	if params.LastEventID != "" && m.events != nil {
+		return m.resubscribeWithReplay(ctx, task, params.LastEventID)
+	}
 	// For tasks in final state, just send a status update event and close.
 	if isFinalState(task.Status.State) {
+		eventChan := make(chan protocol.TaskEvent)
 		go func() {
 			// Send a task status update event
 			event := protocol.TaskStatusUpdateEvent{
@@ -382,32 +708,57 @@ func (m *TaskManager) OnResubscribe(
 		}()
 		return eventChan, nil
 	}
-	// For tasks still in progress, add this as a subscriber.
-	m.addSubscriber(params.ID, eventChan)
+	// For tasks still in progress, add this as a subscriber,
+	// sending the current status as the first event.
+	eventChan := make(chan protocol.TaskEvent, 10) // Buffered to prevent blocking sends.
+	initial := protocol.TaskStatusUpdateEvent{
+		ID:     task.ID,
+		Status: task.Status,
+		Final:  isFinalState(task.Status.State),
+	}
+	if err := m.addSubscriber(ctx, params.ID, eventChan, initial); err != nil {
+		return nil, err
+	}
+	log.Debugf("Sent initial status to resubscribed client for task %s: %s", task.ID, task.Status.State)
 	// Ensure we remove the subscriber when the context is canceled.
-	go func() {
-		<-ctx.Done()
-		m.removeSubscriber(params.ID, eventChan)
-		// Don't close the channel here - that should happen in the task processing goroutine.
-	}()
-	// Send the current status as the first event.
-	go func() {
-		event := protocol.TaskStatusUpdateEvent{
-			ID:     task.ID,
-			Status: task.Status,
-			Final:  isFinalState(task.Status.State),
-		}
-		select {
-		case eventChan <- event:
-			// Successfully sent initial status.
-			log.Debugf("Sent initial status to resubscribed client for task %s: %s", task.ID, task.Status.State)
-		case <-ctx.Done():
-			// Context was canceled.
-			log.Debugf("Context done before sending initial status for task %s", task.ID)
-			m.removeSubscriber(params.ID, eventChan)
-			close(eventChan)
+	m.unsubscribeOnDone(ctx, params.ID, eventChan)
+	return eventChan, nil
+}
+
+// resubscribeWithReplay subscribes to the events of task, first delivering
+// those emitted after lastEventID. The history is read under the event lock
+// of the task, which events are recorded and sent under, so no event is
+// missed or delivered twice.
+func (m *TaskManager) resubscribeWithReplay(
+	ctx context.Context,
+	task *protocol.Task,
+	lastEventID string,
+) (<-chan protocol.TaskEvent, error) {
+	eventLock := m.eventLock(task.ID)
+	eventLock.Lock()
+	defer eventLock.Unlock()
+	missed, final, err := taskmanager.ReplayEvents(ctx, m.events, task, lastEventID)
+	if err != nil {
+		return nil, err
+	}
+	if final {
+		eventChan := make(chan protocol.TaskEvent, len(missed))
+		for _, event := range missed {
+			eventChan <- event
 		}
-	}()
+		close(eventChan)
+		log.Debugf("Replayed %d events to resubscribed client of ended task %s", len(missed), task.ID)
+		return eventChan, nil
+	}
+	eventChan := make(chan protocol.TaskEvent, len(missed)+10) // Room for the replay and live events.
+	m.subMu.Lock()
+	err = m.acquireSubscriber(ctx, task.ID, eventChan, missed...)
+	m.subMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("Replayed %d events to resubscribed client of task %s", len(missed), task.ID)
+	m.unsubscribeOnDone(ctx, task.ID, eventChan)
 	return eventChan, nil
 }
 
@@ -417,61 +768,115 @@ func (m *TaskManager) UpdateTaskStatus(
 	state protocol.TaskState,
 	message *protocol.Message,
 ) error {
-	ctx := context.Background()
-	task, err := m.getTaskInternal(ctx, taskID)
+	return m.setTaskStatus(taskID, protocol.TaskStatus{State: state, Message: message}, nil)
+}
+
+// UpdateTaskStatusWithMetadata updates the task's state like UpdateTaskStatus,
+// sending metadata with the status update event. Progress recorded in metadata
+// is also recorded in the task metadata.
+func (m *TaskManager) UpdateTaskStatusWithMetadata(
+	taskID string,
+	state protocol.TaskState,
+	message *protocol.Message,
+	metadata map[string]interface{},
+) error {
+	return m.setTaskStatus(taskID, protocol.TaskStatus{State: state, Message: message}, metadata)
+}
+
+// setTaskStatus replaces the task's status, stamping it with the current time,
+// persists it, records the status message in history and notifies subscribers,
+// sending them metadata with the event.
+func (m *TaskManager) setTaskStatus(
+	taskID string,
+	status protocol.TaskStatus,
+	metadata map[string]interface{},
+) error {
+	return m.transitionTask(context.Background(), taskID, status, metadata)
+}
+
+// transitionTask implements setTaskStatus, updating the task with ctx, which
+// may expect its version.
+func (m *TaskManager) transitionTask(
+	ctx context.Context,
+	taskID string,
+	status protocol.TaskStatus,
+	metadata map[string]interface{},
+) error {
+	var from protocol.TaskState
+	message := status.Message
+	task, err := m.updateTask(ctx, taskID, func(task *protocol.Task) error {
+		if status.State == protocol.TaskStateCanceled && isFinalState(task.Status.State) {
+			return taskmanager.ErrTaskFinalState(taskID, task.Status.State)
+		}
+		// Update status fields.
+		from = task.Status.State
+		status.Timestamp = time.Now().UTC().Format(time.RFC3339)
+		task.Status = status
+		if isFinalState(status.State) {
+			task.Metadata = protocol.WithoutCheckpoint(task.Metadata)
+		}
+		if message != nil {
+			m.chargeOutput(task, message.Parts)
+		}
+		if progress, ok := protocol.ProgressFromMetadata(metadata); ok {
+			task.Metadata = protocol.WithProgress(task.Metadata, progress)
+		}
+		if attempt, ok := protocol.RetryFromMetadata(metadata); ok {
+			task.Metadata = protocol.WithAttempt(task.Metadata, attempt)
+		}
+		return nil
+	})
 	if err != nil {
-		log.Warnf("Warning: UpdateTaskStatus called for non-existent task %s", taskID)
+		if taskmanager.IsTaskNotFound(err) {
+			log.Warnf("Warning: UpdateTaskStatus called for non-existent task %s", taskID)
+		}
 		return err
 	}
-	// Update status fields.
-	task.Status = protocol.TaskStatus{
-		State:     state,
-		Message:   message,
-		Timestamp: time.Now().UTC().Format(time.RFC3339),
-	}
-	// Store updated task.
-	taskKey := taskPrefix + taskID
-	taskBytes, err := json.Marshal(task)
-	if err != nil {
-		return fmt.Errorf("failed to serialize task: %w", err)
-	}
-	if err := m.client.Set(ctx, taskKey, taskBytes, m.expiration).Err(); err != nil {
-		return fmt.Errorf("failed to update task status: %w", err)
-	}
+	_ = m.auditLog.Record(context.Background(), audit.Event{
+		Type:   audit.EventStateTransition,
+		TaskID: taskID,
+		From:   from,
+		To:     status.State,
+	})
 	// Store the message in history if provided.
 	if message != nil {
-		m.storeMessage(ctx, taskID, *message)
+		m.storeMessage(context.Background(), taskID, *message)
 	}
 	// Notify subscribers.
 	m.notifySubscribers(taskID, protocol.TaskStatusUpdateEvent{
-		ID:     taskID,
-		Status: task.Status,
-		Final:  isFinalState(state),
+		ID:       taskID,
+		Status:   task.Status,
+		Final:    isFinalState(status.State),
+		Metadata: metadata,
 	})
 	return nil
 }
 
 // AddArtifact adds an artifact to the task and notifies subscribers.
+// Returns an error if the task does not exist or the artifact is too large.
 func (m *TaskManager) AddArtifact(taskID string, artifact protocol.Artifact) error {
-	ctx := context.Background()
-	task, err := m.getTaskInternal(ctx, taskID)
-	if err != nil {
-		log.Warnf("Warning: AddArtifact called for non-existent task %s", taskID)
+	return m.addArtifact(context.Background(), taskID, artifact)
+}
+
+// addArtifact implements AddArtifact, updating the task with ctx.
+func (m *TaskManager) addArtifact(ctx context.Context, taskID string, artifact protocol.Artifact) error {
+	if err := taskmanager.CheckArtifactSize(taskID, artifact, m.maxArtifactBytes); err != nil {
 		return err
 	}
-	// Append the artifact.
-	if task.Artifacts == nil {
-		task.Artifacts = make([]protocol.Artifact, 0, 1)
-	}
-	task.Artifacts = append(task.Artifacts, artifact)
-	// Store updated task
-	taskKey := taskPrefix + taskID
-	taskBytes, err := json.Marshal(task)
+	_, err := m.updateTask(ctx, taskID, func(task *protocol.Task) error {
+		// Append the artifact.
+		if task.Artifacts == nil {
+			task.Artifacts = make([]protocol.Artifact, 0, 1)
+		}
+		task.Artifacts = append(task.Artifacts, artifact)
+		m.chargeOutput(task, artifact.Parts)
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to serialize task: %w", err)
-	}
-	if err := m.client.Set(ctx, taskKey, taskBytes, m.expiration).Err(); err != nil {
-		return fmt.Errorf("failed to update task artifacts: %w", err)
+		if taskmanager.IsTaskNotFound(err) {
+			log.Warnf("Warning: AddArtifact called for non-existent task %s", taskID)
+		}
+		return err
 	}
 	// Notify subscribers.
 	finalEvent := artifact.LastChunk != nil && *artifact.LastChunk
@@ -492,8 +897,25 @@ func isFinalState(state protocol.TaskState) bool {
 		state == protocol.TaskStateCanceled
 }
 
-// getTaskInternal retrieves a task from Redis.
+// taskEnded reports whether event is the status update ending its task.
+func taskEnded(event protocol.TaskEvent) bool {
+	status, ok := event.(protocol.TaskStatusUpdateEvent)
+	return ok && isFinalState(status.Status.State)
+}
+
+// getTaskInternal retrieves a task from Redis, as returned to clients.
 func (m *TaskManager) getTaskInternal(ctx context.Context, taskID string) (*protocol.Task, error) {
+	task, err := m.readTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	// Checkpoints are internal to the processing.
+	task.Metadata = protocol.WithoutCheckpoint(task.Metadata)
+	return task, nil
+}
+
+// readTask retrieves a task from Redis as stored.
+func (m *TaskManager) readTask(ctx context.Context, taskID string) (*protocol.Task, error) {
 	taskKey := taskPrefix + taskID
 	taskBytes, err := m.client.Get(ctx, taskKey).Bytes()
 	if err != nil {
@@ -502,57 +924,180 @@ func (m *TaskManager) getTaskInternal(ctx context.Context, taskID string) (*prot
 		}
 		return nil, fmt.Errorf("failed to retrieve task from Redis: %w", err)
 	}
-	var task protocol.Task
-	if err := json.Unmarshal(taskBytes, &task); err != nil {
-		return nil, fmt.Errorf("failed to deserialize task: %w", err)
-	}
-	return &task, nil
+	return m.decodeTask(ctx, taskBytes)
 }
 
-// upsertTask creates a new task or updates metadata if it already exists.
-func (m *TaskManager) upsertTask(ctx context.Context, params protocol.SendTaskParams) *protocol.Task {
-	taskKey := taskPrefix + params.ID
-	// Try to get existing task.
-	existingTaskBytes, err := m.client.Get(ctx, taskKey).Bytes()
+// updateTask applies update to the stored task taskID and increments its
+// version. The task is read and written in a transaction watching it, retried
+// if another writer, such as another replica, changes the task in between.
+// With a context of taskmanager.ContextWithExpectedVersion, the update fails
+// with taskmanager.ErrTaskVersionConflict instead if the task is at another
+// version, and with a context of contextWithLease, it fails with
+// taskmanager.ErrLeaseLost if another replica holds the lease.
+func (m *TaskManager) updateTask(
+	ctx context.Context,
+	taskID string,
+	update func(task *protocol.Task) error,
+) (*protocol.Task, error) {
+	taskKey := taskPrefix + taskID
+	keys := []string{taskKey}
+	if lease := leaseFromContext(ctx); lease != nil {
+		keys = append(keys, leasePrefix+lease.Name())
+	}
 	var task *protocol.Task
-	if err == nil {
-		// Task exists, deserialize it.
-		task = &protocol.Task{}
-		if err := json.Unmarshal(existingTaskBytes, task); err != nil {
-			log.Errorf("Failed to deserialize existing task %s: %v", params.ID, err)
-			// Fall back to creating a new task.
-			task = protocol.NewTask(params.ID, params.SessionID)
-		}
-		log.Debugf("Updating existing task %s", params.ID)
-	} else if err == redis.Nil {
-		// Task doesn't exist, create new one.
-		task = protocol.NewTask(params.ID, params.SessionID)
-		log.Infof("Created new task %s (Session: %v)", params.ID, params.SessionID)
-	} else {
-		// Redis error.
-		log.Errorf("Redis error when retrieving task %s: %v", params.ID, err)
-		// Fall back to creating a new task.
-		task = protocol.NewTask(params.ID, params.SessionID)
+	txf := func(tx *redis.Tx) error {
+		if err := checkLease(ctx, tx); err != nil {
+			return err
+		}
+		taskBytes, err := tx.Get(ctx, taskKey).Bytes()
+		if err == redis.Nil {
+			return taskmanager.ErrTaskNotFound(taskID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to retrieve task from Redis: %w", err)
+		}
+		if task, err = m.decodeTask(ctx, taskBytes); err != nil {
+			return err
+		}
+		if err := taskmanager.CheckTaskVersion(ctx, taskID, task); err != nil {
+			return err
+		}
+		if err := update(task); err != nil {
+			return err
+		}
+		task.Version++
+		if taskBytes, err = m.encodeTask(ctx, task); err != nil {
+			return err
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, taskKey, taskBytes, m.expiration)
+			if m.keys != nil {
+				// The data key of the task lives as long as the task.
+				pipe.Expire(ctx, dataKeyPrefix+taskID, m.expiration)
+			}
+			return nil
+		})
+		return err
 	}
-	// Update metadata if provided.
-	if params.Metadata != nil {
-		if task.Metadata == nil {
-			task.Metadata = make(map[string]interface{})
+	for attempt := 0; attempt < maxTaskUpdateAttempts; attempt++ {
+		err := m.client.Watch(ctx, txf, keys...)
+		if errors.Is(err, redis.TxFailedErr) {
+			continue
 		}
-		for k, v := range params.Metadata {
-			task.Metadata[k] = v
+		if err != nil {
+			return nil, err
 		}
+		return task, nil
 	}
-	// Store the task.
-	taskBytes, err := json.Marshal(task)
+	return nil, fmt.Errorf("failed to update task %s: changed concurrently %d times", taskID, maxTaskUpdateAttempts)
+}
+
+// claimProcessing registers the processing of taskID on this replica and
+// returns the context OnCancelTask cancels, unless the replica is processing
+// the task already. The claim ends with releaseProcessing. The processing of
+// other replicas is detected by the leases of WithTaskLeases.
+func (m *TaskManager) claimProcessing(taskID string) (context.Context, context.CancelCauseFunc, bool) {
+	m.cancelMu.Lock()
+	defer m.cancelMu.Unlock()
+	if _, processing := m.cancels[taskID]; processing {
+		return nil, nil, false
+	}
+	ctx, cancel := context.WithCancelCause(context.Background())
+	m.cancels[taskID] = cancel
+	return ctx, cancel, true
+}
+
+// releaseProcessing ends the claim of claimProcessing on taskID.
+func (m *TaskManager) releaseProcessing(taskID string, cancel context.CancelCauseFunc) {
+	cancel(nil)
+	m.cancelMu.Lock()
+	delete(m.cancels, taskID)
+	m.cancelMu.Unlock()
+}
+
+// upsertTask creates a new task or updates metadata if it already exists,
+// charging the tokens of the message to the task, and returns the action of
+// the request (see taskmanager.ResendAction), processing telling whether the
+// replica is processing the task. It fails without storing the task if the
+// task is final or the message would exceed the task's token budget.
+func (m *TaskManager) upsertTask(
+	ctx context.Context,
+	params protocol.SendTaskParams,
+	processing bool,
+) (*protocol.Task, taskmanager.SendAction, error) {
+	tokens, err := m.usage.Count(params.Message.Parts)
 	if err != nil {
-		log.Errorf("Failed to serialize task %s: %v", params.ID, err)
-		return task
+		return nil, taskmanager.SendProcess, err
+	}
+	// charge charges the message to the task and merges its metadata.
+	charge := func(task *protocol.Task) error {
+		metadata, err := m.usage.Charge(params.ID, task.Metadata, taskmanager.InputUsage(tokens))
+		if err != nil {
+			return err
+		}
+		task.Metadata = metadata
+		// Update metadata if provided.
+		if params.Metadata != nil {
+			if task.Metadata == nil {
+				task.Metadata = make(map[string]interface{})
+			}
+			for k, v := range params.Metadata {
+				task.Metadata[k] = v
+			}
+		}
+		return nil
+	}
+	action := taskmanager.SendProcess
+	for attempt := 0; attempt < maxTaskUpdateAttempts; attempt++ {
+		task, err := m.updateTask(ctx, params.ID, func(task *protocol.Task) error {
+			var err error
+			if action, err = taskmanager.ResendAction(task, processing); err != nil {
+				return err
+			}
+			return charge(task)
+		})
+		if err == nil {
+			log.Debugf("Updated existing task %s", params.ID)
+			return task, action, nil
+		}
+		if !taskmanager.IsTaskNotFound(err) {
+			return nil, action, err
+		}
+		// Task doesn't exist, create new one unless another writer does first.
+		task = protocol.NewTask(params.ID, params.SessionID)
+		if err := charge(task); err != nil {
+			return nil, taskmanager.SendProcess, err
+		}
+		task.Version = 1
+		taskBytes, err := m.encodeTask(ctx, task)
+		if err != nil {
+			return nil, taskmanager.SendProcess, err
+		}
+		created, err := m.client.SetNX(ctx, taskPrefix+params.ID, taskBytes, m.expiration).Result()
+		if err != nil {
+			return nil, taskmanager.SendProcess, fmt.Errorf("failed to store task %s in Redis: %w", params.ID, err)
+		}
+		if created {
+			log.Infof("Created new task %s (Session: %v)", params.ID, params.SessionID)
+			// Another send of the task may have claimed its processing while
+			// this one created it.
+			action, err = taskmanager.ResendAction(task, processing)
+			return task, action, err
+		}
 	}
-	if err := m.client.Set(ctx, taskKey, taskBytes, m.expiration).Err(); err != nil {
-		log.Errorf("Failed to store task %s in Redis: %v", params.ID, err)
+	return nil, action, fmt.Errorf("failed to store task %s: changed concurrently %d times", params.ID, maxTaskUpdateAttempts)
+}
+
+// chargeOutput charges the tokens of parts produced by the agent to task.
+func (m *TaskManager) chargeOutput(task *protocol.Task, parts []protocol.Part) {
+	tokens, err := m.usage.Count(parts)
+	if err != nil {
+		log.Warnf("Failed to account output tokens of task %s: %v", task.ID, err)
+		return
 	}
-	return task
+	// Output is never rejected, so Charge cannot fail.
+	metadata, _ := m.usage.Charge(task.ID, task.Metadata, taskmanager.OutputUsage(tokens))
+	task.Metadata = metadata
 }
 
 // storeMessage adds a message to the task's history in Redis.
@@ -568,7 +1113,7 @@ func (m *TaskManager) storeMessage(ctx context.Context, taskID string, message p
 		copy(messageCopy.Parts, message.Parts)
 	}
 	// Serialize the message.
-	messageBytes, err := json.Marshal(messageCopy)
+	messageBytes, err := m.encodeMessage(ctx, taskID, messageCopy)
 	if err != nil {
 		log.Errorf("Failed to serialize message for task %s: %v", taskID, err)
 		return
@@ -580,6 +1125,9 @@ func (m *TaskManager) storeMessage(ctx context.Context, taskID string, message p
 	}
 	// Set expiration on the message list.
 	m.client.Expire(ctx, messagesKey, m.expiration)
+	if m.keys != nil {
+		m.client.Expire(ctx, dataKeyPrefix+taskID, m.expiration)
+	}
 }
 
 // getMessageHistory retrieves message history for a task.
@@ -610,8 +1158,8 @@ func (m *TaskManager) getMessageHistory(
 	// Deserialize messages.
 	messages := make([]protocol.Message, 0, len(messagesBytesRaw))
 	for _, msgBytes := range messagesBytesRaw {
-		var msg protocol.Message
-		if err := json.Unmarshal([]byte(msgBytes), &msg); err != nil {
+		msg, err := m.decodeMessage(ctx, taskID, []byte(msgBytes))
+		if err != nil {
 			log.Errorf("Failed to deserialize message for task %s: %v", taskID, err)
 			continue // Skip invalid messages.
 		}
@@ -620,23 +1168,79 @@ func (m *TaskManager) getMessageHistory(
 	return messages, nil
 }
 
-// addSubscriber adds a channel to the list of subscribers for a task.
-func (m *TaskManager) addSubscriber(taskID string, ch chan<- protocol.TaskEvent) {
+// addSubscriber adds a channel to the list of subscribers for a task, subject to
+// the subscription limits. Subscriptions evicted to make room are closed.
+// The initial events are delivered to ch before any other event.
+func (m *TaskManager) addSubscriber(
+	ctx context.Context,
+	taskID string,
+	ch chan<- protocol.TaskEvent,
+	initial ...protocol.TaskEvent,
+) error {
 	m.subMu.Lock()
 	defer m.subMu.Unlock()
-	// If the task has no subscribers, create a new list.
-	if _, exists := m.subscribers[taskID]; !exists {
-		m.subscribers[taskID] = make([]chan<- protocol.TaskEvent, 0, 1)
+	return m.acquireSubscriber(ctx, taskID, ch, initial...)
+}
+
+// acquireSubscriber implements addSubscriber. The caller must hold subMu.
+func (m *TaskManager) acquireSubscriber(
+	ctx context.Context,
+	taskID string,
+	ch chan<- protocol.TaskEvent,
+	initial ...protocol.TaskEvent,
+) error {
+	evicted, err := m.subscriptions.Acquire(ctx, taskID, ch)
+	if err != nil {
+		log.Warnf("Rejected subscriber for task %s: %v", taskID, err)
+		return err
+	}
+	for _, old := range evicted {
+		m.detachSubscriber(taskID, old)
+		// Closing under the write lock is safe since events are only sent under the lock.
+		close(old)
+		log.Infof("Evicted oldest subscriber for task %s", taskID)
+	}
+	for _, event := range initial {
+		select {
+		case ch <- event:
+		default:
+			log.Warnf("Warning: Dropping initial event for task %s subscriber - channel full.", taskID)
+		}
 	}
 	// Add the new subscriber.
+	m.slowConsumers.Attach(ch)
 	m.subscribers[taskID] = append(m.subscribers[taskID], ch)
 	log.Debugf("Added subscriber for task %s", taskID)
+	return nil
 }
 
 // removeSubscriber removes a specific channel from the list of subscribers for a task.
-func (m *TaskManager) removeSubscriber(taskID string, ch chan<- protocol.TaskEvent) {
+// It reports whether the channel was still subscribed; false means it was already
+// removed, evicted or ended with its task, in which case the caller must not
+// close it.
+func (m *TaskManager) removeSubscriber(taskID string, ch chan<- protocol.TaskEvent) bool {
 	m.subMu.Lock()
 	defer m.subMu.Unlock()
+	if !m.subscriptions.Release(taskID, ch) {
+		// Stop delivering the events left to a stream that ended with its task.
+		m.slowConsumers.Detach(ch)
+		return false
+	}
+	m.detachSubscriber(taskID, ch)
+	return true
+}
+
+// unsubscribeOnDone removes the subscription of ch to taskID once ctx is
+// done, unless it ended before with the task.
+func (m *TaskManager) unsubscribeOnDone(ctx context.Context, taskID string, ch chan<- protocol.TaskEvent) {
+	context.AfterFunc(ctx, func() {
+		m.removeSubscriber(taskID, ch)
+	})
+}
+
+// detachSubscriber removes ch from the subscribers map. The caller must hold subMu.
+func (m *TaskManager) detachSubscriber(taskID string, ch chan<- protocol.TaskEvent) {
+	m.slowConsumers.Detach(ch)
 	channels, exists := m.subscribers[taskID]
 	if !exists {
 		return // No subscribers for this task.
@@ -657,31 +1261,70 @@ func (m *TaskManager) removeSubscriber(taskID string, ch chan<- protocol.TaskEve
 	log.Debugf("Removed subscriber for task %s", taskID)
 }
 
+// eventLock returns the lock serializing the events of taskID.
+func (m *TaskManager) eventLock(taskID string) *sync.Mutex {
+	h := fnv.New32a()
+	h.Write([]byte(taskID))
+	return &m.eventLocks[h.Sum32()%eventLockShards]
+}
+
+// numberEvent records event in the event history, or only numbers it if there
+// is none, and returns it with its ID. The caller must hold the event lock of
+// taskID.
+func (m *TaskManager) numberEvent(taskID string, event protocol.TaskEvent) protocol.TaskEvent {
+	history := m.events
+	if history == nil {
+		history = eventSequence{client: m.client, expiration: m.expiration}
+	}
+	id, err := history.Append(context.Background(), taskID, event)
+	if err != nil {
+		log.Warnf("Failed to record event of task %s for replay: %v", taskID, err)
+		return event
+	}
+	return protocol.WithEventID(event, id)
+}
+
 // notifySubscribers sends an event to all current subscribers of a task.
 func (m *TaskManager) notifySubscribers(taskID string, event protocol.TaskEvent) {
-	m.subMu.RLock()
+	// Events are numbered and sent under the event lock of the task, so
+	// subscribers receive them in ID order, and sent under subMu, so evicted
+	// channels cannot be closed meanwhile. Sends never block, so holding subMu
+	// is cheap; numbering the event in Redis is done before.
+	eventLock := m.eventLock(taskID)
+	eventLock.Lock()
+	defer eventLock.Unlock()
+	event = m.numberEvent(taskID, event)
+	m.subMu.Lock()
+	defer m.subMu.Unlock()
 	subs, exists := m.subscribers[taskID]
 	if !exists || len(subs) == 0 {
-		m.subMu.RUnlock()
 		return // No subscribers to notify.
 	}
-	// Copy the slice of channels under read lock
-	subsCopy := make([]chan<- protocol.TaskEvent, len(subs))
-	copy(subsCopy, subs)
-	m.subMu.RUnlock()
 	log.Debugf("Notifying %d subscribers for task %s (Event Type: %T, Final: %t)",
-		len(subsCopy), taskID, event, event.IsFinal())
-	// Send events outside the lock.
-	for _, ch := range subsCopy {
-		// Use a select with a default case for a non-blocking send.
-		select {
-		case ch <- event:
-			// Event sent successfully.
-		default:
-			// Channel buffer is full or channel is closed.
-			log.Warnf("Warning: Dropping event for task %s subscriber - channel full or closed.", taskID)
+		len(subs), taskID, event, event.IsFinal())
+	var slow []chan<- protocol.TaskEvent
+	for _, ch := range subs {
+		if !m.slowConsumers.Send(taskID, ch, event) {
+			slow = append(slow, ch)
 		}
 	}
+	for _, ch := range slow {
+		if m.subscriptions.Release(taskID, ch) {
+			m.detachSubscriber(taskID, ch)
+			// Closing under the lock is safe since events are only sent under it.
+			close(ch)
+		}
+	}
+	if taskEnded(event) {
+		// The streams end with their task, which releases their subscriptions,
+		// once the events queued for them, including this one, are delivered.
+		for _, ch := range m.subscribers[taskID] {
+			if m.subscriptions.Release(taskID, ch) {
+				m.slowConsumers.Finish(ch)
+			}
+		}
+		delete(m.subscribers, taskID)
+	}
 }
 
 // Close closes the Redis client and cleans up resources.
@@ -689,15 +1332,16 @@ func (m *TaskManager) Close() error {
 	// Cancel all active contexts.
 	m.cancelMu.Lock()
 	for _, cancel := range m.cancels {
-		cancel()
+		cancel(nil)
 	}
-	m.cancels = make(map[string]context.CancelFunc)
+	m.cancels = make(map[string]context.CancelCauseFunc)
 	m.cancelMu.Unlock()
 
 	// Close all subscriber channels.
 	m.subMu.Lock()
 	for taskID, channels := range m.subscribers {
 		for _, ch := range channels {
+			m.slowConsumers.Detach(ch)
 			// Try to notify of closing but don't block
 			select {
 			case ch <- protocol.TaskStatusUpdateEvent{
@@ -717,3 +1361,12 @@ func (m *TaskManager) Close() error {
 	// Close the Redis client.
 	return m.client.Close()
 }
+
+// WithMaxArtifactSize rejects artifacts larger than maxBytes, as computed by
+// taskmanager.ArtifactSize, with a taskmanager.ErrPayloadTooLarge error
+// returned to the processor. Artifacts are unlimited by default.
+func WithMaxArtifactSize(maxBytes int64) Option {
+	return func(o *TaskManager) {
+		o.maxArtifactBytes = maxBytes
+	}
+}
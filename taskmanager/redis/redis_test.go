@@ -7,6 +7,7 @@ package redis
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"testing"
@@ -17,6 +18,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
 	"trpc.group/trpc-go/trpc-a2a-go/protocol"
 	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
 )
@@ -546,6 +548,570 @@ func TestE2E_InputRequired(t *testing.T) {
 	require.NotNil(t, retrievedTask.Status.Message, "Input request message should be available")
 }
 
+func TestE2E_Resend(t *testing.T) {
+	manager, mr := setupRedisTest(t)
+	defer mr.Close()
+	defer manager.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	send := func(text string) (*protocol.Task, error) {
+		return manager.OnSendTask(ctx, protocol.SendTaskParams{
+			ID: "test-resend",
+			Message: protocol.Message{
+				Role:  protocol.MessageRoleUser,
+				Parts: []protocol.Part{protocol.NewTextPart(text)},
+			},
+		})
+	}
+
+	task, err := send("input-required:more data needed")
+	require.NoError(t, err)
+	assert.Equal(t, protocol.TaskStateInputRequired, task.Status.State)
+
+	// The answer continues the conversation.
+	task, err = send("the data")
+	require.NoError(t, err)
+	assert.Equal(t, protocol.TaskStateCompleted, task.Status.State)
+
+	// Completed tasks reject new messages.
+	_, err = send("more")
+	var rpcErr *jsonrpc.Error
+	require.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, taskmanager.ErrCodeTaskFinal, rpcErr.Code)
+}
+
+func TestE2E_TaskVersions(t *testing.T) {
+	manager, mr := setupRedisTest(t)
+	defer mr.Close()
+	defer manager.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	task, err := manager.OnSendTask(ctx, protocol.SendTaskParams{
+		ID: "test-versions",
+		Message: protocol.Message{
+			Role:  protocol.MessageRoleUser,
+			Parts: []protocol.Part{protocol.NewTextPart("input-required:more data needed")},
+		},
+	})
+	require.NoError(t, err)
+	// Created, working, input required.
+	assert.Equal(t, uint64(3), task.Version)
+
+	_, err = manager.OnCancelTask(
+		taskmanager.ContextWithExpectedVersion(ctx, 2), protocol.TaskIDParams{ID: "test-versions"},
+	)
+	var rpcErr *jsonrpc.Error
+	require.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, taskmanager.ErrCodeTaskVersionConflict, rpcErr.Code)
+
+	task, err = manager.OnCancelTask(
+		taskmanager.ContextWithExpectedVersion(ctx, 3), protocol.TaskIDParams{ID: "test-versions"},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, protocol.TaskStateCanceled, task.Status.State)
+	assert.Equal(t, uint64(4), task.Version)
+}
+
+// processorFunc is an adapter to use functions as task processors.
+type processorFunc func(ctx context.Context, taskID string, msg protocol.Message, handle taskmanager.TaskHandle) error
+
+func (f processorFunc) Process(
+	ctx context.Context,
+	taskID string,
+	msg protocol.Message,
+	handle taskmanager.TaskHandle,
+) error {
+	return f(ctx, taskID, msg, handle)
+}
+
+// Test a task is processed by the replica holding its lease, and taken over
+// by another replica once the lease is lost.
+func TestE2E_TaskLeases(t *testing.T) {
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	defer mr.Close()
+	client := redis.NewUniversalClient(&redis.UniversalOptions{Addrs: []string{mr.Addr()}})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	message := protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")})
+
+	// The first replica hangs until its lease is lost.
+	lost := make(chan error, 1)
+	replica1, err := NewRedisTaskManager(client, processorFunc(func(
+		ctx context.Context, taskID string, msg protocol.Message, handle taskmanager.TaskHandle,
+	) error {
+		<-ctx.Done()
+		lost <- handle.UpdateStatus(protocol.TaskStateCompleted, nil)
+		return ctx.Err()
+	}), WithTaskLeases(taskmanager.LeaseConfig{Owner: "replica-1", TTL: 90 * time.Millisecond}))
+	require.NoError(t, err)
+	replica2, err := NewRedisTaskManager(client, processorFunc(func(
+		ctx context.Context, taskID string, msg protocol.Message, handle taskmanager.TaskHandle,
+	) error {
+		return handle.UpdateStatus(protocol.TaskStateCompleted, &msg)
+	}), WithTaskLeases(taskmanager.LeaseConfig{Owner: "replica-2", TTL: time.Minute}))
+	require.NoError(t, err)
+
+	// Tasks whose lease is held elsewhere are not processed.
+	_, err = NewLeaser(client).Acquire(ctx, "task:held", "replica-3", time.Minute)
+	require.NoError(t, err)
+	task, err := replica2.OnSendTask(ctx, protocol.SendTaskParams{ID: "held", Message: message})
+	require.NoError(t, err)
+	assert.Equal(t, protocol.TaskStateSubmitted, task.Status.State)
+
+	_, err = replica1.OnSendTaskSubscribe(ctx, protocol.SendTaskParams{ID: "leased", Message: message})
+	require.NoError(t, err)
+	owner, err := client.Get(ctx, "lease:task:leased").Result()
+	require.NoError(t, err)
+	assert.Equal(t, "replica-1", owner)
+	// The lease expires while held, as when its replica dies.
+	mr.Del("lease:task:leased")
+	assert.ErrorIs(t, <-lost, taskmanager.ErrLeaseLost)
+
+	require.NoError(t, replica2.TakeOverTasks(ctx))
+	require.Eventually(t, func() bool {
+		task, err := replica2.OnGetTask(ctx, protocol.TaskQueryParams{ID: "leased"})
+		return err == nil && task.Status.State == protocol.TaskStateCompleted
+	}, time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool {
+		return !mr.Exists("lease:task:leased")
+	}, time.Second, 10*time.Millisecond, "the lease is released once processed")
+}
+
+// Test a task whose processor returned without final state is failed rather
+// than left working, where no replica would take it over.
+func TestE2E_TaskLeases_NoFinalState(t *testing.T) {
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	defer mr.Close()
+	client := redis.NewUniversalClient(&redis.UniversalOptions{Addrs: []string{mr.Addr()}})
+	manager, err := NewRedisTaskManager(client, processorFunc(func(
+		ctx context.Context, taskID string, msg protocol.Message, handle taskmanager.TaskHandle,
+	) error {
+		return nil
+	}), WithTaskLeases(taskmanager.LeaseConfig{Owner: "replica-1", TTL: time.Minute}))
+	require.NoError(t, err)
+
+	task, err := manager.OnSendTask(context.Background(), protocol.SendTaskParams{
+		ID:      "unfinished",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")}),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, protocol.TaskStateFailed, task.Status.State)
+	assert.False(t, mr.Exists("tasks:processing"))
+	assert.False(t, mr.Exists("lease:task:unfinished"))
+}
+
+// resumingProcessor is a processorFunc resuming tasks with an artifact
+// listing their history.
+type resumingProcessor struct {
+	processorFunc
+}
+
+// ResumeTask implements taskmanager.TaskResumer.
+func (p resumingProcessor) ResumeTask(
+	ctx context.Context,
+	task *protocol.Task,
+	handle taskmanager.TaskHandle,
+) error {
+	if err := handle.AddArtifact(protocol.Artifact{
+		Parts: []protocol.Part{protocol.NewTextPart(fmt.Sprintf("resumed after %d messages", len(task.History)))},
+	}); err != nil {
+		return err
+	}
+	return handle.UpdateStatus(protocol.TaskStateCompleted, nil)
+}
+
+// Test the tasks left working by a stopped task manager are recovered by the
+// next one.
+func TestE2E_TaskRecovery(t *testing.T) {
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	defer mr.Close()
+	client := redis.NewUniversalClient(&redis.UniversalOptions{Addrs: []string{mr.Addr()}})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	message := protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")})
+
+	// The processing of the stopped task manager never ends.
+	stopped, err := NewRedisTaskManager(client, processorFunc(func(
+		ctx context.Context, taskID string, msg protocol.Message, handle taskmanager.TaskHandle,
+	) error {
+		<-ctx.Done()
+		return nil
+	}), WithTaskLeases(taskmanager.LeaseConfig{Owner: "stopped", TTL: time.Minute}))
+	require.NoError(t, err)
+	start := func(taskID string) {
+		_, err := stopped.OnSendTaskSubscribe(ctx, protocol.SendTaskParams{ID: taskID, Message: message})
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			task, err := stopped.OnGetTask(ctx, protocol.TaskQueryParams{ID: taskID})
+			return err == nil && task.Status.State == protocol.TaskStateWorking
+		}, time.Second, 10*time.Millisecond)
+		// The lease expires, as when the task manager stopped.
+		mr.Del("lease:task:" + taskID)
+	}
+	leases := WithTaskLeases(taskmanager.LeaseConfig{Owner: "restarted", TTL: time.Minute})
+	_, err = NewRedisTaskManager(client, resumingProcessor{}, WithTaskRecovery(taskmanager.RecoveryResume))
+	assert.ErrorContains(t, err, "requires task leases", "recovery is refused without leases")
+
+	start("resumed")
+	restarted, err := NewRedisTaskManager(client, resumingProcessor{}, leases,
+		WithTaskRecovery(taskmanager.RecoveryResume))
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		task, err := restarted.OnGetTask(ctx, protocol.TaskQueryParams{ID: "resumed"})
+		return err == nil && task.Status.State == protocol.TaskStateCompleted
+	}, time.Second, 10*time.Millisecond)
+	task, err := restarted.OnGetTask(ctx, protocol.TaskQueryParams{ID: "resumed"})
+	require.NoError(t, err)
+	require.Len(t, task.Artifacts, 1)
+	assert.Equal(t, protocol.NewTextPart("resumed after 1 messages"), task.Artifacts[0].Parts[0])
+
+	start("failed")
+	recovered, err := restarted.RecoverTasks(ctx, taskmanager.RecoveryFail)
+	require.NoError(t, err)
+	assert.Equal(t, 1, recovered)
+	task, err = restarted.OnGetTask(ctx, protocol.TaskQueryParams{ID: "failed"})
+	require.NoError(t, err)
+	assert.Equal(t, protocol.TaskStateFailed, task.Status.State)
+	require.NotNil(t, task.Status.Error)
+	assert.Equal(t, protocol.TaskErrorCodeUnavailable, task.Status.Error.Code)
+	assert.False(t, mr.Exists("tasks:processing"), "the recovered tasks are no longer processed")
+}
+
+// Test a retried task continues from the checkpoint saved by its processor.
+func TestE2E_Checkpoints(t *testing.T) {
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	defer mr.Close()
+	client := redis.NewUniversalClient(&redis.UniversalOptions{Addrs: []string{mr.Addr()}})
+	var attempts []int
+	var manager *TaskManager
+	var visible bool
+	manager, err = NewRedisTaskManager(client, processorFunc(func(
+		ctx context.Context, taskID string, msg protocol.Message, handle taskmanager.TaskHandle,
+	) error {
+		checkpoint, _, err := taskmanager.LoadCheckpoint(handle)
+		if err != nil {
+			return err
+		}
+		attempts = append(attempts, checkpoint.Step)
+		task, err := manager.OnGetTask(ctx, protocol.TaskQueryParams{ID: taskID})
+		if err != nil {
+			return err
+		}
+		_, visible = task.Checkpoint()
+		if checkpoint.Step == 0 {
+			if err := taskmanager.SaveCheckpoint(handle, protocol.TaskCheckpoint{Step: 1, Output: "half"}); err != nil {
+				return err
+			}
+			return taskmanager.NewProcessingError(protocol.TaskErrorCodeUnavailable, true, errors.New("step failed"))
+		}
+		return handle.UpdateStatus(protocol.TaskStateCompleted, nil)
+	}), WithRetryPolicy(taskmanager.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}))
+	require.NoError(t, err)
+
+	task, err := manager.OnSendTask(context.Background(), protocol.SendTaskParams{
+		ID:      "steps",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("go")}),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, protocol.TaskStateCompleted, task.Status.State)
+	assert.Equal(t, []int{0, 1}, attempts)
+	assert.False(t, visible, "checkpoints are hidden from clients")
+	// The checkpoint is removed once the task ends.
+	stored, err := manager.readTask(context.Background(), "steps")
+	require.NoError(t, err)
+	_, ok := stored.Checkpoint()
+	assert.False(t, ok)
+}
+
+// countingKeys is a taskmanager.KeyProvider counting the data keys generated.
+type countingKeys struct {
+	taskmanager.KeyProvider
+	generated int
+}
+
+// GenerateDataKey implements taskmanager.KeyProvider.
+func (k *countingKeys) GenerateDataKey(ctx context.Context) ([]byte, []byte, error) {
+	k.generated++
+	return k.KeyProvider.GenerateDataKey(ctx)
+}
+
+func TestE2E_Encryption(t *testing.T) {
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	defer mr.Close()
+	client := redis.NewUniversalClient(&redis.UniversalOptions{Addrs: []string{mr.Addr()}})
+	static, err := taskmanager.NewStaticKeyProvider([]byte("0123456789abcdef0123456789abcdef"))
+	require.NoError(t, err)
+	keys := &countingKeys{KeyProvider: static}
+	manager, err := NewRedisTaskManager(client, processorFunc(func(
+		ctx context.Context, taskID string, msg protocol.Message, handle taskmanager.TaskHandle,
+	) error {
+		for i := 0; i < 3; i++ {
+			if err := handle.AddArtifact(protocol.Artifact{
+				Index: i,
+				Parts: []protocol.Part{protocol.NewTextPart("secret artifact")},
+			}); err != nil {
+				return err
+			}
+		}
+		return handle.UpdateStatus(protocol.TaskStateCompleted, nil)
+	}), WithEncryption(keys))
+	require.NoError(t, err)
+	defer manager.Close()
+
+	_, err = manager.OnSendTask(context.Background(), protocol.SendTaskParams{
+		ID:      "sealed",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("secret question")}),
+	})
+	require.NoError(t, err)
+	stored, err := mr.Get(taskPrefix + "sealed")
+	require.NoError(t, err)
+	assert.NotContains(t, stored, "secret")
+	assert.Contains(t, stored, string(protocol.TaskStateCompleted), "the status is stored in clear")
+	messages, err := mr.List(messagePrefix + "sealed")
+	require.NoError(t, err)
+	require.NotEmpty(t, messages)
+	for _, message := range messages {
+		assert.NotContains(t, message, "secret")
+	}
+	assert.Equal(t, 1, keys.generated, "the task is encrypted under a single data key")
+
+	task, err := manager.OnGetTask(context.Background(), protocol.TaskQueryParams{ID: "sealed", HistoryLength: intPtr(10)})
+	require.NoError(t, err)
+	require.Len(t, task.Artifacts, 3)
+	assert.Equal(t, protocol.NewTextPart("secret artifact"), task.Artifacts[0].Parts[0])
+	require.NotEmpty(t, task.History)
+	assert.Equal(t, protocol.NewTextPart("secret question"), task.History[0].Parts[0])
+
+	// Another replica decrypts the task with the same key provider.
+	other, err := NewRedisTaskManager(client, newTestProcessor(), WithEncryption(static))
+	require.NoError(t, err)
+	defer other.Close()
+	task, err = other.OnGetTask(context.Background(), protocol.TaskQueryParams{ID: "sealed", HistoryLength: intPtr(10)})
+	require.NoError(t, err)
+	assert.Len(t, task.Artifacts, 3)
+	assert.NotEmpty(t, task.History)
+}
+
 func intPtr(i int) *int {
 	return &i
 }
+
+// Test subscription limits evict the oldest stream when configured to.
+func TestE2E_SubscriptionLimits(t *testing.T) {
+	mr, err := miniredis.Run()
+	require.NoError(t, err, "Failed to create miniredis server")
+	defer mr.Close()
+	client := redis.NewUniversalClient(&redis.UniversalOptions{Addrs: []string{mr.Addr()}})
+	manager, err := NewRedisTaskManager(client, newTestProcessor(), WithSubscriptionLimits(
+		taskmanager.SubscriptionLimits{
+			MaxPerTask: 1,
+			Overflow:   taskmanager.SubscriptionOverflowEvictOldest,
+		},
+	))
+	require.NoError(t, err)
+	defer manager.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	taskID := "test-subscription-limits"
+	first, err := manager.OnSendTaskSubscribe(ctx, protocol.SendTaskParams{
+		ID: taskID,
+		Message: protocol.Message{
+			Role:  protocol.MessageRoleUser,
+			Parts: []protocol.Part{protocol.NewTextPart("cancel:long running task")},
+		},
+	})
+	require.NoError(t, err)
+	second, err := manager.OnResubscribe(ctx, protocol.TaskIDParams{ID: taskID})
+	require.NoError(t, err)
+
+	// The first stream is closed once drained.
+	for range first {
+	}
+	event, ok := <-second
+	require.True(t, ok, "Resubscribed stream should receive the current status")
+	assert.Equal(t, protocol.TaskStateWorking, event.(protocol.TaskStatusUpdateEvent).Status.State)
+
+	stats := manager.SubscriptionStats()
+	assert.Equal(t, int64(1), stats.Active)
+	assert.Equal(t, int64(2), stats.Accepted)
+	assert.Equal(t, int64(1), stats.Evicted)
+
+	// The remaining stream ends with the task, releasing its subscription.
+	_, err = manager.OnCancelTask(ctx, protocol.TaskIDParams{ID: taskID})
+	require.NoError(t, err)
+	for range second {
+	}
+	assert.Equal(t, int64(0), manager.SubscriptionStats().Active)
+}
+
+// Test replaying the events persisted in Redis to a resubscribing client
+func TestE2E_TaskResubscribeReplay(t *testing.T) {
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	defer mr.Close()
+	client := redis.NewUniversalClient(&redis.UniversalOptions{Addrs: []string{mr.Addr()}})
+	history := NewEventHistory(client, 0, time.Hour)
+	manager, err := NewRedisTaskManager(client, newTestProcessor(), WithEventReplay(history))
+	require.NoError(t, err)
+	defer manager.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	task, err := manager.OnSendTask(ctx, protocol.SendTaskParams{
+		ID: "test-replay-task",
+		Message: protocol.Message{
+			Role:  protocol.MessageRoleUser,
+			Parts: []protocol.Part{protocol.NewTextPart("artifacts:replay me")},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, protocol.TaskStateCompleted, task.Status.State)
+
+	// A new history over the same Redis sees the persisted events.
+	all, err := NewEventHistory(client, 0, time.Hour).Since(ctx, task.ID, "0")
+	require.NoError(t, err)
+	require.Greater(t, len(all), 2)
+
+	events, err := manager.OnResubscribe(ctx, protocol.TaskIDParams{ID: task.ID, LastEventID: "1"})
+	require.NoError(t, err)
+	var replayed []protocol.TaskEvent
+	for event := range events {
+		replayed = append(replayed, event)
+	}
+	require.Len(t, replayed, len(all)-1)
+	assert.Equal(t, "2", protocol.EventID(replayed[0]))
+	assert.True(t, replayed[len(replayed)-1].IsFinal())
+	for _, event := range replayed {
+		if artifact, ok := event.(protocol.TaskArtifactUpdateEvent); ok {
+			assert.NotEmpty(t, artifact.Artifact.Parts)
+		}
+	}
+
+	_, err = manager.OnResubscribe(ctx, protocol.TaskIDParams{ID: task.ID, LastEventID: "99"})
+	require.Error(t, err)
+}
+
+func TestPushConfigStore(t *testing.T) {
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	defer mr.Close()
+	client := redis.NewUniversalClient(&redis.UniversalOptions{Addrs: []string{mr.Addr()}})
+	ctx := context.Background()
+	config := protocol.PushNotificationConfig{URL: "http://example.com/hook"}
+	pending := protocol.TaskStatusUpdateEvent{
+		ID: "task-1", Status: protocol.TaskStatus{State: protocol.TaskStateCompleted}, Final: true,
+	}
+
+	store := NewPushConfigStore(client, time.Hour)
+	require.NoError(t, store.SavePushConfig(ctx, "task-1", taskmanager.PushConfigRecord{Config: config, Pending: &pending}))
+	mr.FastForward(30 * time.Minute)
+	require.NoError(t, store.SavePushConfig(ctx, "task-2", taskmanager.PushConfigRecord{Config: config}))
+
+	// A new store over the same Redis sees the persisted configurations.
+	records, err := NewPushConfigStore(client, time.Hour).LoadPushConfigs(ctx)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, config, records["task-2"].Config)
+	require.NotNil(t, records["task-1"].Pending)
+	assert.True(t, records["task-1"].Pending.Final)
+
+	// Expired configurations are dropped from the index.
+	mr.FastForward(45 * time.Minute)
+	records, err = store.LoadPushConfigs(ctx)
+	require.NoError(t, err)
+	assert.Len(t, records, 1)
+	members, err := client.SMembers(ctx, pushConfigIndexKey).Result()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"task-2"}, members)
+}
+
+func TestWorkQueue(t *testing.T) {
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	defer mr.Close()
+	client := redis.NewUniversalClient(&redis.UniversalOptions{Addrs: []string{mr.Addr()}})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	opts := WorkQueueOptions{Consumer: "worker-1", VisibilityTimeout: 50 * time.Millisecond}
+	queue := NewWorkQueue(client, opts)
+	opts.Consumer = "worker-2"
+	other := NewWorkQueue(client, opts)
+
+	require.NoError(t, client.XAdd(ctx, &redis.XAddArgs{
+		Stream: queueStreamKey,
+		Values: map[string]interface{}{queueTaskField: "not a task"},
+	}).Err())
+	require.NoError(t, queue.Enqueue(ctx, taskmanager.QueuedTask{Params: protocol.SendTaskParams{ID: "task-1"}}))
+
+	// Invalid entries are dropped; nacked tasks are delivered again after
+	// their delay.
+	delivery, err := queue.Dequeue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "task-1", delivery.Task().Params.ID)
+	assert.False(t, delivery.Task().EnqueuedAt.IsZero())
+	require.NoError(t, delivery.Nack(ctx, 20*time.Millisecond))
+	delivery, err = queue.Dequeue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "task-1", delivery.Task().Params.ID)
+
+	// Tasks not extended are claimed by another worker.
+	require.NoError(t, delivery.Extend(ctx))
+	claimed, err := other.Dequeue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "task-1", claimed.Task().Params.ID)
+	require.NoError(t, claimed.Ack(ctx))
+
+	empty, cancelEmpty := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancelEmpty()
+	_, err = queue.Dequeue(empty)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	length, err := client.XLen(ctx, queueStreamKey).Result()
+	require.NoError(t, err)
+	assert.Zero(t, length)
+}
+
+func TestEventBus(t *testing.T) {
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	defer mr.Close()
+	client := redis.NewUniversalClient(&redis.UniversalOptions{Addrs: []string{mr.Addr()}})
+	bus := NewEventBus(client)
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := bus.Subscribe(ctx, "task-1")
+	require.NoError(t, err)
+
+	require.NoError(t, bus.Publish(ctx, "task-2", protocol.TaskArtifactUpdateEvent{ID: "task-2"}))
+	require.NoError(t, bus.Publish(ctx, "task-1", protocol.TaskStatusUpdateEvent{
+		ID:     "task-1",
+		Status: protocol.TaskStatus{State: protocol.TaskStateCompleted},
+		Final:  true,
+	}))
+	event := <-events
+	require.IsType(t, protocol.TaskStatusUpdateEvent{}, event)
+	assert.True(t, event.IsFinal())
+	cancel()
+	for range events {
+	}
+
+	// The events published while a subscriber is not reading are kept.
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	events, err = bus.Subscribe(ctx, "task-1")
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, bus.Publish(ctx, "task-1", protocol.TaskArtifactUpdateEvent{ID: "task-1", Artifact: protocol.Artifact{Index: i}}))
+	}
+	for i := 0; i < 3; i++ {
+		event := <-events
+		require.IsType(t, protocol.TaskArtifactUpdateEvent{}, event)
+		assert.Equal(t, i, event.(protocol.TaskArtifactUpdateEvent).Artifact.Index)
+	}
+}
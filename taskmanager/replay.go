@@ -0,0 +1,156 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// eventSequencer numbers the events of each task when no EventHistory is kept.
+// It is safe for concurrent use.
+type eventSequencer struct {
+	mu   sync.Mutex
+	last map[string]uint64
+}
+
+// newEventSequencer creates a sequencer numbering the events of each task from 1.
+func newEventSequencer() *eventSequencer {
+	return &eventSequencer{last: make(map[string]uint64)}
+}
+
+// Next returns the ID of the next event of taskID.
+func (s *eventSequencer) Next(taskID string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.last[taskID]++
+	return strconv.FormatUint(s.last[taskID], 10)
+}
+
+// Forget restarts the numbering of the events of taskID.
+func (s *eventSequencer) Forget(taskID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.last, taskID)
+}
+
+// EventHistory keeps the events emitted for each task so that clients
+// resubscribing with the ID of the last event they received get the events
+// they missed replayed. Task managers record every event before delivering it
+// and pass its ID to clients under protocol.MetadataKeyEventID. IDs must be
+// the decimal sequence numbers of the events of each task, from 1.
+type EventHistory interface {
+	// Append records event as the latest event of taskID and returns its ID.
+	Append(ctx context.Context, taskID string, event protocol.TaskEvent) (string, error)
+	// Since returns the events of taskID recorded after the event lastEventID,
+	// oldest first, with their IDs in their metadata. It fails with
+	// ErrEventNotReplayable if lastEventID is unknown or no longer kept.
+	Since(ctx context.Context, taskID, lastEventID string) ([]protocol.TaskEvent, error)
+}
+
+// ReplayEvents returns the events of task that a client which received
+// lastEventID missed, read from history, and whether they end the stream since
+// the task is in a final state. In that case they end with a final status
+// event, the current status of the task if the history lacks one.
+func ReplayEvents(
+	ctx context.Context,
+	history EventHistory,
+	task *protocol.Task,
+	lastEventID string,
+) ([]protocol.TaskEvent, bool, error) {
+	events, err := history.Since(ctx, task.ID, lastEventID)
+	if err != nil {
+		return nil, false, err
+	}
+	for _, event := range events {
+		if event.IsFinal() {
+			return events, true, nil
+		}
+	}
+	if !isFinalState(task.Status.State) {
+		return events, false, nil
+	}
+	return append(events, protocol.TaskStatusUpdateEvent{
+		ID:     task.ID,
+		Status: task.Status,
+		Final:  true,
+	}), true, nil
+}
+
+// defaultMaxReplayEvents is the number of events kept per task by default.
+const defaultMaxReplayEvents = 256
+
+// taskEvents are the events kept for a task.
+type taskEvents struct {
+	last   uint64               // ID of the latest event.
+	events []protocol.TaskEvent // The latest events, with their IDs in metadata.
+}
+
+// MemoryEventHistory is an EventHistory kept in memory. Event IDs are the
+// sequence numbers of the events of each task. It is safe for concurrent use.
+type MemoryEventHistory struct {
+	maxEvents int
+
+	mu    sync.Mutex
+	tasks map[string]*taskEvents
+}
+
+// NewMemoryEventHistory creates a history keeping the last maxEvents events
+// of each task, 256 if maxEvents is not positive.
+func NewMemoryEventHistory(maxEvents int) *MemoryEventHistory {
+	if maxEvents <= 0 {
+		maxEvents = defaultMaxReplayEvents
+	}
+	return &MemoryEventHistory{maxEvents: maxEvents, tasks: make(map[string]*taskEvents)}
+}
+
+// Append implements EventHistory.
+func (h *MemoryEventHistory) Append(ctx context.Context, taskID string, event protocol.TaskEvent) (string, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	t, ok := h.tasks[taskID]
+	if !ok {
+		t = &taskEvents{}
+		h.tasks[taskID] = t
+	}
+	t.last++
+	id := strconv.FormatUint(t.last, 10)
+	t.events = append(t.events, protocol.WithEventID(event, id))
+	if len(t.events) > h.maxEvents {
+		t.events = t.events[len(t.events)-h.maxEvents:]
+	}
+	return id, nil
+}
+
+// Since implements EventHistory.
+func (h *MemoryEventHistory) Since(ctx context.Context, taskID, lastEventID string) ([]protocol.TaskEvent, error) {
+	seen, err := strconv.ParseUint(lastEventID, 10, 64)
+	if err != nil {
+		return nil, ErrEventNotReplayable(taskID, lastEventID)
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	t, ok := h.tasks[taskID]
+	if !ok || seen > t.last {
+		return nil, ErrEventNotReplayable(taskID, lastEventID)
+	}
+	missed := t.last - seen
+	if missed > uint64(len(t.events)) {
+		return nil, ErrEventNotReplayable(taskID, lastEventID)
+	}
+	return append([]protocol.TaskEvent(nil), t.events[uint64(len(t.events))-missed:]...), nil
+}
+
+// Forget drops the events kept for taskID.
+func (h *MemoryEventHistory) Forget(taskID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.tasks, taskID)
+}
@@ -0,0 +1,161 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+func TestMemoryEventHistory(t *testing.T) {
+	ctx := context.Background()
+	history := NewMemoryEventHistory(3)
+	for i := 0; i < 5; i++ {
+		id, err := history.Append(ctx, "t1", protocol.TaskStatusUpdateEvent{ID: "t1"})
+		require.NoError(t, err)
+		assert.Equal(t, strconv.Itoa(i+1), id)
+	}
+
+	events, err := history.Since(ctx, "t1", "3")
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, "4", protocol.EventID(events[0]))
+	assert.Equal(t, "5", protocol.EventID(events[1]))
+	events, err = history.Since(ctx, "t1", "2")
+	require.NoError(t, err)
+	assert.Len(t, events, 3)
+	events, err = history.Since(ctx, "t1", "5")
+	require.NoError(t, err)
+	assert.Empty(t, events)
+
+	for _, id := range []string{"1", "6", "x"} {
+		_, err = history.Since(ctx, "t1", id)
+		require.Error(t, err, id)
+		assert.Equal(t, ErrCodeEventNotReplayable, err.(*jsonrpc.Error).Code)
+	}
+	_, err = history.Since(ctx, "t2", "0")
+	assert.Error(t, err)
+
+	history.Forget("t1")
+	_, err = history.Since(ctx, "t1", "5")
+	assert.Error(t, err)
+}
+
+// blockingEventHistory is a MemoryEventHistory whose appends to the events of
+// a task block until released.
+type blockingEventHistory struct {
+	*MemoryEventHistory
+	taskID  string
+	release chan struct{}
+}
+
+func (h *blockingEventHistory) Append(ctx context.Context, taskID string, event protocol.TaskEvent) (string, error) {
+	if taskID == h.taskID {
+		<-h.release
+	}
+	return h.MemoryEventHistory.Append(ctx, taskID, event)
+}
+
+func TestMemoryTaskManager_EventHistoryOutsideSubscriberLock(t *testing.T) {
+	history := &blockingEventHistory{MemoryEventHistory: NewMemoryEventHistory(0), taskID: "slow", release: make(chan struct{})}
+	tm, err := NewMemoryTaskManager(&mockProcessor{}, WithEventReplay(history))
+	require.NoError(t, err)
+	require.NotSame(t, tm.eventLock("slow"), tm.eventLock("fast"))
+	fast := make(chan protocol.TaskEvent, 1)
+	tm.SubMutex.Lock()
+	tm.Subscribers["fast"] = []chan<- protocol.TaskEvent{fast}
+	tm.SubMutex.Unlock()
+
+	status := protocol.TaskStatusUpdateEvent{Status: protocol.TaskStatus{State: protocol.TaskStateWorking}}
+	recorded := make(chan struct{})
+	go func() {
+		tm.notifySubscribers("slow", status)
+		close(recorded)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	notified := make(chan struct{})
+	go func() {
+		tm.notifySubscribers("fast", status)
+		close(notified)
+	}()
+	select {
+	case <-notified:
+	case <-time.After(time.Second):
+		t.Fatal("recording the event of a task blocks the subscribers of the others")
+	}
+	assert.Equal(t, "1", protocol.EventID(<-fast))
+	close(history.release)
+	<-recorded
+}
+
+func TestMemoryTaskManager_ResubscribeReplay(t *testing.T) {
+	proceed := make(chan struct{})
+	processor := &mockProcessor{
+		processFunc: func(ctx context.Context, taskID string, msg protocol.Message, handle TaskHandle) error {
+			if err := handle.AddArtifact(protocol.Artifact{
+				Parts: []protocol.Part{protocol.NewTextPart("partial")},
+			}); err != nil {
+				return err
+			}
+			<-proceed
+			return handle.UpdateStatus(protocol.TaskStateCompleted, nil)
+		},
+	}
+	tm, err := NewMemoryTaskManager(processor, WithEventReplay(NewMemoryEventHistory(0)))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := tm.OnSendTaskSubscribe(ctx, protocol.SendTaskParams{
+		ID:      "replayed",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")}),
+	})
+	require.NoError(t, err)
+	// The client receives the working status, then disconnects.
+	first := <-events
+	assert.Equal(t, "1", protocol.EventID(first))
+	cancel()
+
+	require.Eventually(t, func() bool {
+		_, err := tm.events.Since(context.Background(), "replayed", "2")
+		return err == nil
+	}, time.Second, 5*time.Millisecond)
+
+	resubCtx, resubCancel := context.WithCancel(context.Background())
+	defer resubCancel()
+	replayed, err := tm.OnResubscribe(resubCtx, protocol.TaskIDParams{ID: "replayed", LastEventID: "1"})
+	require.NoError(t, err)
+	artifact := <-replayed
+	require.IsType(t, protocol.TaskArtifactUpdateEvent{}, artifact)
+	assert.Equal(t, "2", protocol.EventID(artifact))
+
+	// Live events follow the replay.
+	close(proceed)
+	final := <-replayed
+	assert.True(t, final.IsFinal())
+	assert.Equal(t, "3", protocol.EventID(final))
+
+	// Resubscribing to the ended task replays its end and closes the stream.
+	ended, err := tm.OnResubscribe(context.Background(), protocol.TaskIDParams{ID: "replayed", LastEventID: "1"})
+	require.NoError(t, err)
+	var ids []string
+	for event := range ended {
+		ids = append(ids, protocol.EventID(event))
+	}
+	assert.Equal(t, []string{"2", "3"}, ids)
+
+	_, err = tm.OnResubscribe(context.Background(), protocol.TaskIDParams{ID: "replayed", LastEventID: "9"})
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeEventNotReplayable, err.(*jsonrpc.Error).Code)
+}
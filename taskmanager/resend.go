@@ -0,0 +1,46 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// SendAction is what the task managers do with a tasks/send or
+// tasks/sendSubscribe request for a task.
+type SendAction int
+
+const (
+	// SendProcess stores the message in the history of the task and
+	// processes it: the task is new, was not started yet, or continues its
+	// conversation because it was waiting for input.
+	SendProcess SendAction = iota
+	// SendAppend only appends the message to the history of the task, which
+	// is being processed. The request returns the task, or streams its
+	// events, without processing it again.
+	SendAppend
+)
+
+// ResendAction returns the action of a request sending a message for task,
+// an existing task, processing telling whether the task manager is processing
+// the task:
+//
+//   - final tasks reject the request with ErrTaskFinalState;
+//   - tasks being processed get the message appended to their history
+//     (SendAppend), whatever their state;
+//   - other tasks process the message (SendProcess): input-required tasks
+//     continue their conversation with it, and submitted or working tasks
+//     whose processing did not start or was interrupted start it.
+func ResendAction(task *protocol.Task, processing bool) (SendAction, error) {
+	if state := task.Status.State; isFinalState(state) {
+		return SendProcess, ErrTaskFinalState(task.ID, state)
+	}
+	if processing {
+		return SendAppend, nil
+	}
+	return SendProcess, nil
+}
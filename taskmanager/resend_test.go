@@ -0,0 +1,200 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+func TestMemoryTaskManager_Resend(t *testing.T) {
+	release := make(chan struct{})
+	processor := &mockProcessor{processFunc: func(
+		ctx context.Context, taskID string, msg protocol.Message, handle TaskHandle,
+	) error {
+		switch msg.Parts[0].(protocol.TextPart).Text {
+		case "question":
+			return handle.UpdateStatus(protocol.TaskStateInputRequired, nil)
+		case "slow":
+			if err := handle.UpdateStatus(protocol.TaskStateWorking, nil); err != nil {
+				return err
+			}
+			<-release
+		}
+		return handle.UpdateStatus(protocol.TaskStateCompleted, nil)
+	}}
+	tm, err := NewMemoryTaskManager(processor)
+	require.NoError(t, err)
+	ctx := context.Background()
+	send := func(taskID, text string) (*protocol.Task, error) {
+		return tm.OnSendTask(ctx, protocol.SendTaskParams{
+			ID:      taskID,
+			Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart(text)}),
+		})
+	}
+	history := func(taskID string) []string {
+		historyLength := 0
+		task, err := tm.OnGetTask(ctx, protocol.TaskQueryParams{ID: taskID, HistoryLength: &historyLength})
+		require.NoError(t, err)
+		return historyTexts(task.History)
+	}
+
+	t.Run("input required continues", func(t *testing.T) {
+		task, err := send("conversation", "question")
+		require.NoError(t, err)
+		assert.Equal(t, protocol.TaskStateInputRequired, task.Status.State)
+		task, err = send("conversation", "answer")
+		require.NoError(t, err)
+		assert.Equal(t, protocol.TaskStateCompleted, task.Status.State)
+		assert.Equal(t, []string{"question", "answer"}, history("conversation"))
+	})
+
+	t.Run("final rejects", func(t *testing.T) {
+		calls := processor.callCount
+		_, err := send("conversation", "again")
+		var rpcErr *jsonrpc.Error
+		require.ErrorAs(t, err, &rpcErr)
+		assert.Equal(t, ErrCodeTaskFinal, rpcErr.Code)
+		assert.Equal(t, calls, processor.callCount, "the message is not processed")
+		assert.Equal(t, []string{"question", "answer"}, history("conversation"), "the message is not stored")
+	})
+
+	t.Run("working appends", func(t *testing.T) {
+		done := make(chan error, 1)
+		go func() {
+			_, err := send("busy", "slow")
+			done <- err
+		}()
+		require.Eventually(t, func() bool {
+			task, err := tm.OnGetTask(ctx, protocol.TaskQueryParams{ID: "busy"})
+			return err == nil && task.Status.State == protocol.TaskStateWorking
+		}, time.Second, time.Millisecond)
+		calls := processor.callCount
+		task, err := send("busy", "more context")
+		require.NoError(t, err)
+		assert.Equal(t, protocol.TaskStateWorking, task.Status.State)
+		assert.Equal(t, calls, processor.callCount, "the message is not processed")
+		assert.Equal(t, []string{"slow", "more context"}, history("busy"))
+		// Streams of the task follow the current processing and end with it.
+		events, err := tm.OnSendTaskSubscribe(ctx, protocol.SendTaskParams{
+			ID:      "busy",
+			Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("stream")}),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, calls, processor.callCount, "the message is not processed")
+		close(release)
+		require.NoError(t, <-done)
+		var last protocol.TaskEvent
+		for event := range events {
+			last = event
+		}
+		require.NotNil(t, last)
+		assert.True(t, last.IsFinal())
+		assert.Zero(t, tm.SubscriptionStats().Active, "the subscriber is released")
+	})
+
+	t.Run("interrupted working processes", func(t *testing.T) {
+		// A working task left without processing, e.g. by a restart.
+		interrupted := protocol.NewTask("interrupted", nil)
+		interrupted.Status.State = protocol.TaskStateWorking
+		require.NoError(t, tm.store.Put(ctx, interrupted))
+		calls := processor.callCount
+		task, err := send("interrupted", "again")
+		require.NoError(t, err)
+		assert.Equal(t, protocol.TaskStateCompleted, task.Status.State)
+		assert.Equal(t, calls+1, processor.callCount, "the message is processed")
+	})
+}
+
+// slowMissTaskStore is a mapTaskStore creating tasks atomically and slow to
+// report missing tasks on update, so that concurrent sends of a new task all
+// try to create it.
+type slowMissTaskStore struct {
+	mapTaskStore
+}
+
+func (s *slowMissTaskStore) Update(
+	ctx context.Context,
+	taskID string,
+	update func(task *protocol.Task) error,
+) (*protocol.Task, error) {
+	task, err := s.mapTaskStore.Update(ctx, taskID, update)
+	if IsTaskNotFound(err) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	return task, err
+}
+
+// Create implements TaskCreator.
+func (s *slowMissTaskStore) Create(ctx context.Context, task *protocol.Task) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.tasks[task.ID]; ok {
+		return ErrTaskExists(task.ID)
+	}
+	s.tasks[task.ID] = *task
+	return nil
+}
+
+func TestMemoryTaskManager_ConcurrentResend(t *testing.T) {
+	var mu sync.Mutex
+	processed := make(map[string]int)
+	processor := &mockProcessor{processFunc: func(
+		ctx context.Context, taskID string, msg protocol.Message, handle TaskHandle,
+	) error {
+		mu.Lock()
+		processed[taskID]++
+		mu.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		return handle.UpdateStatus(protocol.TaskStateCompleted, nil)
+	}}
+	tm, err := NewMemoryTaskManager(processor, WithTaskStore(&slowMissTaskStore{
+		mapTaskStore: mapTaskStore{tasks: make(map[string]protocol.Task)},
+	}))
+	require.NoError(t, err)
+	ctx := context.Background()
+	sends := map[string]func(params protocol.SendTaskParams){
+		"send": func(params protocol.SendTaskParams) {
+			_, _ = tm.OnSendTask(ctx, params)
+		},
+		"subscribe": func(params protocol.SendTaskParams) {
+			if events, err := tm.OnSendTaskSubscribe(ctx, params); err == nil {
+				for range events {
+				}
+			}
+		},
+	}
+	for name, send := range sends {
+		for i := 0; i < 20; i++ {
+			params := protocol.SendTaskParams{
+				ID:      fmt.Sprintf("%s-%d", name, i),
+				Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")}),
+			}
+			var wg sync.WaitGroup
+			for j := 0; j < 2; j++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					send(params)
+				}()
+			}
+			wg.Wait()
+			mu.Lock()
+			assert.Equal(t, 1, processed[params.ID], "task %s is processed once", params.ID)
+			mu.Unlock()
+		}
+	}
+}
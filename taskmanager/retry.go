@@ -0,0 +1,120 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// Defaults of RetryPolicy.
+const (
+	defaultRetryInitialBackoff = time.Second
+	defaultRetryMaxBackoff     = 30 * time.Second
+	defaultRetryMultiplier     = 2
+)
+
+// RetryPolicy makes task managers run the processor of a task again when it
+// fails with a retryable error, i.e. one TaskErrorFrom marks retryable, such as
+// a ProcessingError created with retryable set. Each retry is announced by a
+// working status update carrying the failed protocol.TaskAttempt under
+// protocol.MetadataKeyRetry, and the failed attempts are recorded in the task
+// metadata under protocol.MetadataKeyAttempts. Canceled tasks are not retried.
+type RetryPolicy struct {
+	// MaxAttempts is the number of times the processor runs at most, including
+	// the first. Values below 2 disable retries.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry. Defaults to 1s.
+	InitialBackoff time.Duration
+	// MaxBackoff bounds the delay between attempts. Defaults to 30s.
+	MaxBackoff time.Duration
+	// Multiplier is the factor applied to the delay after each retry. Defaults to 2.
+	Multiplier float64
+}
+
+// Backoff returns the delay before the attempt following the failed attempt
+// numbered attempt, from 1.
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	delay, maxDelay, multiplier := p.InitialBackoff, p.MaxBackoff, p.Multiplier
+	if delay <= 0 {
+		delay = defaultRetryInitialBackoff
+	}
+	if maxDelay <= 0 {
+		maxDelay = defaultRetryMaxBackoff
+	}
+	if multiplier < 1 {
+		multiplier = defaultRetryMultiplier
+	}
+	for i := 1; i < attempt && delay < maxDelay; i++ {
+		delay = time.Duration(float64(delay) * multiplier)
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	return delay
+}
+
+// Run calls process until it succeeds, fails with an error that is not
+// retryable, the attempts are exhausted or ctx is done, waiting for the backoff
+// between attempts. Before waiting it calls onRetry with the failed attempt; if
+// onRetry fails, the task is not retried. Run returns the error of the last
+// attempt.
+func (p RetryPolicy) Run(
+	ctx context.Context,
+	process func() error,
+	onRetry func(attempt protocol.TaskAttempt) error,
+) error {
+	for attempt := 1; ; attempt++ {
+		err := process()
+		if err == nil || attempt >= p.MaxAttempts || ctx.Err() != nil {
+			return err
+		}
+		taskErr := TaskErrorFrom(err)
+		if !taskErr.Retryable {
+			return err
+		}
+		delay := p.Backoff(attempt)
+		if retryErr := onRetry(protocol.TaskAttempt{
+			Attempt:      attempt,
+			Error:        taskErr,
+			FailedAt:     time.Now().UTC().Format(time.RFC3339),
+			RetryDelayMs: delay.Milliseconds(),
+		}); retryErr != nil {
+			log.Errorf("Failed to record retry of attempt %d: %v", attempt, retryErr)
+			return err
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// RetryStatus returns the working status and event metadata announcing the
+// retry of the failed attempt, out of maxAttempts.
+func RetryStatus(attempt protocol.TaskAttempt, maxAttempts int) (protocol.TaskStatus, map[string]interface{}) {
+	text := fmt.Sprintf("Attempt %d of %d failed, retrying in %s", attempt.Attempt, maxAttempts,
+		time.Duration(attempt.RetryDelayMs)*time.Millisecond)
+	if attempt.Error != nil {
+		text += ": " + attempt.Error.Message
+	}
+	status := protocol.TaskStatus{
+		State: protocol.TaskStateWorking,
+		Message: &protocol.Message{
+			Role:  protocol.MessageRoleAgent,
+			Parts: []protocol.Part{protocol.NewTextPart(text)},
+		},
+	}
+	return status, map[string]interface{}{protocol.MetadataKeyRetry: attempt}
+}
@@ -0,0 +1,109 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Multiplier: 3}
+	assert.Equal(t, 100*time.Millisecond, policy.Backoff(1))
+	assert.Equal(t, 300*time.Millisecond, policy.Backoff(2))
+	assert.Equal(t, 900*time.Millisecond, policy.Backoff(3))
+	assert.Equal(t, time.Second, policy.Backoff(4))
+	assert.Equal(t, time.Second, RetryPolicy{}.Backoff(1))
+}
+
+func TestRetryPolicy_Run(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+	retryable := NewProcessingError(protocol.TaskErrorCodeUnavailable, true, errors.New("down"))
+
+	calls := 0
+	var retries []int
+	err := policy.Run(context.Background(), func() error {
+		calls++
+		return retryable
+	}, func(attempt protocol.TaskAttempt) error {
+		retries = append(retries, attempt.Attempt)
+		assert.True(t, attempt.Error.Retryable)
+		return nil
+	})
+	assert.ErrorIs(t, err, retryable)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, []int{1, 2}, retries)
+
+	calls = 0
+	err = policy.Run(context.Background(), func() error {
+		calls++
+		return errors.New("permanent")
+	}, func(protocol.TaskAttempt) error { return nil })
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+
+	// Canceling during the backoff stops the retries.
+	ctx, cancel := context.WithCancel(context.Background())
+	calls = 0
+	err = RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Hour}.Run(ctx, func() error {
+		calls++
+		return retryable
+	}, func(protocol.TaskAttempt) error {
+		cancel()
+		return nil
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestMemoryTaskManager_Retry(t *testing.T) {
+	attempts := 0
+	processor := &mockProcessor{
+		processFunc: func(ctx context.Context, taskID string, msg protocol.Message, handle TaskHandle) error {
+			attempts++
+			if attempts < 3 {
+				return NewProcessingError(protocol.TaskErrorCodeUnavailable, true, errors.New("model overloaded"))
+			}
+			return handle.UpdateStatus(protocol.TaskStateCompleted, nil)
+		},
+	}
+	tm, err := NewMemoryTaskManager(processor,
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}))
+	require.NoError(t, err)
+	events, err := tm.OnSendTaskSubscribe(context.Background(), protocol.SendTaskParams{
+		ID:      "retried",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")}),
+	})
+	require.NoError(t, err)
+
+	var retries []int
+	for event := range events {
+		status := event.(protocol.TaskStatusUpdateEvent)
+		if attempt, ok := protocol.RetryFromMetadata(status.Metadata); ok {
+			retries = append(retries, attempt.Attempt)
+			assert.Equal(t, protocol.TaskStateWorking, status.Status.State)
+		}
+		if status.Final {
+			assert.Equal(t, protocol.TaskStateCompleted, status.Status.State)
+			break
+		}
+	}
+	assert.Equal(t, []int{1, 2}, retries)
+
+	task, err := tm.OnGetTask(context.Background(), protocol.TaskQueryParams{ID: "retried"})
+	require.NoError(t, err)
+	history := protocol.AttemptsFromMetadata(task.Metadata)
+	require.Len(t, history, 2)
+	assert.Equal(t, protocol.TaskErrorCodeUnavailable, history[1].Error.Code)
+}
@@ -0,0 +1,26 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import "context"
+
+// sandboxKey is the context key marking sandboxed processing.
+type sandboxKey struct{}
+
+// ContextWithSandbox marks ctx as sandboxed: the task processed with it is a
+// trial, such as a skill example run, whose effects are discarded.
+func ContextWithSandbox(ctx context.Context) context.Context {
+	return context.WithValue(ctx, sandboxKey{}, true)
+}
+
+// IsSandboxed reports whether ctx is sandboxed. Processors should then avoid
+// side effects outside the task, e.g. sending emails or charging accounts,
+// and may return canned results for them.
+func IsSandboxed(ctx context.Context) bool {
+	sandboxed, _ := ctx.Value(sandboxKey{}).(bool)
+	return sandboxed
+}
@@ -0,0 +1,65 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package simulator
+
+import (
+	"math/rand"
+)
+
+// Distribution produces random samples used to drive simulated behavior.
+// Samples are never negative.
+type Distribution interface {
+	// Sample draws a single value using the given random source.
+	Sample(r *rand.Rand) float64
+}
+
+// DistributionFunc adapts a function to the Distribution interface.
+type DistributionFunc func(r *rand.Rand) float64
+
+// Sample implements Distribution.
+func (f DistributionFunc) Sample(r *rand.Rand) float64 {
+	return nonNegative(f(r))
+}
+
+// Constant returns a distribution that always yields v.
+func Constant(v float64) Distribution {
+	return DistributionFunc(func(*rand.Rand) float64 { return v })
+}
+
+// Uniform returns a distribution yielding values uniformly in [min, max).
+func Uniform(min, max float64) Distribution {
+	if max < min {
+		min, max = max, min
+	}
+	return DistributionFunc(func(r *rand.Rand) float64 {
+		return min + r.Float64()*(max-min)
+	})
+}
+
+// Normal returns a normal distribution with the given mean and standard deviation.
+// Negative samples are clamped to zero.
+func Normal(mean, stddev float64) Distribution {
+	return DistributionFunc(func(r *rand.Rand) float64 {
+		return mean + r.NormFloat64()*stddev
+	})
+}
+
+// Exponential returns an exponential distribution with the given mean,
+// commonly used to model service latencies with a long tail.
+func Exponential(mean float64) Distribution {
+	return DistributionFunc(func(r *rand.Rand) float64 {
+		return r.ExpFloat64() * mean
+	})
+}
+
+// nonNegative clamps v to zero.
+func nonNegative(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	return v
+}
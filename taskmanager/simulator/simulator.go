@@ -0,0 +1,269 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+// Package simulator provides an embeddable agent simulator whose latency, error rate,
+// token streaming rate and artifact sizes are configured programmatically.
+// It is intended for capacity planning and for load-testing orchestrators
+// without real model backends.
+package simulator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+const (
+	defaultTokenSize    = 4
+	defaultArtifactName = "simulated-output"
+	// fillerText is repeated to produce artifact content of the requested size.
+	fillerText = "lorem ipsum dolor sit amet consectetur adipiscing elit "
+)
+
+// ErrSimulatedFailure is returned by the simulator when a task is chosen to fail
+// according to the configured error rate.
+var ErrSimulatedFailure = errors.New("simulated failure")
+
+// Profile describes the simulated behavior of an agent.
+type Profile struct {
+	// Latency is the distribution, in seconds, of the delay before the first
+	// output is produced. A nil Latency means no delay.
+	Latency Distribution
+	// ErrorRate is the probability in [0, 1] that a task fails.
+	ErrorRate float64
+	// TokensPerSecond is the rate at which artifact tokens are streamed.
+	// Zero or negative values stream tokens without pacing.
+	TokensPerSecond float64
+	// TokenSize is the number of bytes per streamed token. Defaults to 4.
+	TokenSize int
+	// ArtifactSize is the distribution of the total artifact size in bytes.
+	// A nil ArtifactSize produces no artifact.
+	ArtifactSize Distribution
+}
+
+// Stats holds counters describing the work performed by a Processor.
+type Stats struct {
+	// Started is the number of tasks that began processing.
+	Started int64
+	// Completed is the number of tasks that completed successfully.
+	Completed int64
+	// Failed is the number of tasks that failed, including simulated failures.
+	Failed int64
+	// Canceled is the number of tasks whose context was canceled during processing.
+	Canceled int64
+	// Tokens is the total number of tokens streamed.
+	Tokens int64
+	// Bytes is the total number of artifact bytes streamed.
+	Bytes int64
+}
+
+// Processor implements taskmanager.TaskProcessor by simulating an agent
+// according to a Profile. It is safe for concurrent use.
+type Processor struct {
+	mu      sync.Mutex
+	profile Profile
+	rng     *rand.Rand
+
+	started   atomic.Int64
+	completed atomic.Int64
+	failed    atomic.Int64
+	canceled  atomic.Int64
+	tokens    atomic.Int64
+	bytes     atomic.Int64
+}
+
+// Option configures a Processor.
+type Option func(*Processor)
+
+// WithSeed seeds the random source used for sampling, making runs reproducible.
+func WithSeed(seed int64) Option {
+	return func(p *Processor) {
+		p.rng = rand.New(rand.NewSource(seed))
+	}
+}
+
+// NewProcessor creates a new simulating Processor with the given profile.
+func NewProcessor(profile Profile, opts ...Option) *Processor {
+	p := &Processor{
+		profile: profile,
+		rng:     rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// NewTaskManager creates a MemoryTaskManager backed by a simulating Processor.
+// The returned Processor can be used to adjust the profile or read statistics.
+func NewTaskManager(profile Profile, opts ...Option) (*taskmanager.MemoryTaskManager, *Processor, error) {
+	processor := NewProcessor(profile, opts...)
+	manager, err := taskmanager.NewMemoryTaskManager(processor)
+	if err != nil {
+		return nil, nil, err
+	}
+	return manager, processor, nil
+}
+
+// SetProfile replaces the profile used for subsequently started tasks.
+func (p *Processor) SetProfile(profile Profile) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.profile = profile
+}
+
+// Profile returns the current profile.
+func (p *Processor) Profile() Profile {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.profile
+}
+
+// Stats returns a snapshot of the processor counters.
+func (p *Processor) Stats() Stats {
+	return Stats{
+		Started:   p.started.Load(),
+		Completed: p.completed.Load(),
+		Failed:    p.failed.Load(),
+		Canceled:  p.canceled.Load(),
+		Tokens:    p.tokens.Load(),
+		Bytes:     p.bytes.Load(),
+	}
+}
+
+// plan holds the sampled behavior for a single task.
+type plan struct {
+	latency      time.Duration
+	fail         bool
+	artifactSize int
+	tokenSize    int
+	tokenDelay   time.Duration
+}
+
+// newPlan samples the profile for a single task under the processor lock,
+// since rand.Rand is not safe for concurrent use.
+func (p *Processor) newPlan() plan {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	pl := plan{tokenSize: p.profile.TokenSize}
+	if pl.tokenSize <= 0 {
+		pl.tokenSize = defaultTokenSize
+	}
+	if p.profile.Latency != nil {
+		pl.latency = time.Duration(p.profile.Latency.Sample(p.rng) * float64(time.Second))
+	}
+	if p.profile.ErrorRate > 0 {
+		pl.fail = p.rng.Float64() < p.profile.ErrorRate
+	}
+	if p.profile.ArtifactSize != nil {
+		pl.artifactSize = int(p.profile.ArtifactSize.Sample(p.rng))
+	}
+	if p.profile.TokensPerSecond > 0 {
+		pl.tokenDelay = time.Duration(float64(time.Second) / p.profile.TokensPerSecond)
+	}
+	return pl
+}
+
+// Process implements taskmanager.TaskProcessor.
+func (p *Processor) Process(
+	ctx context.Context,
+	taskID string,
+	initialMsg protocol.Message,
+	handle taskmanager.TaskHandle,
+) error {
+	p.started.Add(1)
+	pl := p.newPlan()
+	if err := sleep(ctx, pl.latency); err != nil {
+		p.canceled.Add(1)
+		return err
+	}
+	if pl.fail {
+		p.failed.Add(1)
+		return fmt.Errorf("task %s: %w", taskID, ErrSimulatedFailure)
+	}
+	if err := p.streamArtifact(ctx, pl, handle); err != nil {
+		if ctx.Err() != nil {
+			p.canceled.Add(1)
+		} else {
+			p.failed.Add(1)
+		}
+		return err
+	}
+	msg := &protocol.Message{
+		Role:  protocol.MessageRoleAgent,
+		Parts: []protocol.Part{protocol.NewTextPart("simulated task completed")},
+	}
+	if err := handle.UpdateStatus(protocol.TaskStateCompleted, msg); err != nil {
+		p.failed.Add(1)
+		return err
+	}
+	p.completed.Add(1)
+	return nil
+}
+
+// streamArtifact emits the artifact as a sequence of token-sized chunks.
+func (p *Processor) streamArtifact(ctx context.Context, pl plan, handle taskmanager.TaskHandle) error {
+	if pl.artifactSize <= 0 {
+		return nil
+	}
+	content := generateContent(pl.artifactSize)
+	name := defaultArtifactName
+	for offset := 0; offset < len(content); offset += pl.tokenSize {
+		end := offset + pl.tokenSize
+		if end > len(content) {
+			end = len(content)
+		}
+		appendChunk := offset > 0
+		lastChunk := end == len(content)
+		artifact := protocol.Artifact{
+			Name:      &name,
+			Parts:     []protocol.Part{protocol.NewTextPart(content[offset:end])},
+			Index:     0,
+			Append:    &appendChunk,
+			LastChunk: &lastChunk,
+		}
+		if err := handle.AddArtifact(artifact); err != nil {
+			return err
+		}
+		p.tokens.Add(1)
+		p.bytes.Add(int64(end - offset))
+		if !lastChunk {
+			if err := sleep(ctx, pl.tokenDelay); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// generateContent returns deterministic filler text of exactly size bytes.
+func generateContent(size int) string {
+	repeats := size/len(fillerText) + 1
+	return strings.Repeat(fillerText, repeats)[:size]
+}
+
+// sleep waits for d or until ctx is done, whichever happens first.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
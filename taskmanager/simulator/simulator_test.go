@@ -0,0 +1,116 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package simulator
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+func newTestParams(id string) protocol.SendTaskParams {
+	return protocol.SendTaskParams{
+		ID: id,
+		Message: protocol.NewMessage(
+			protocol.MessageRoleUser,
+			[]protocol.Part{protocol.NewTextPart("hello")},
+		),
+	}
+}
+
+func TestProcessor_CompletesWithArtifact(t *testing.T) {
+	manager, processor, err := NewTaskManager(Profile{
+		ArtifactSize: Constant(10),
+		TokenSize:    3,
+	}, WithSeed(1))
+	require.NoError(t, err)
+
+	task, err := manager.OnSendTask(context.Background(), newTestParams("sim-1"))
+	require.NoError(t, err)
+	assert.Equal(t, protocol.TaskStateCompleted, task.Status.State)
+	require.Len(t, task.Artifacts, 4, "10 bytes in 3-byte tokens should yield 4 chunks")
+
+	var content strings.Builder
+	for i, artifact := range task.Artifacts {
+		require.NotNil(t, artifact.Append)
+		require.NotNil(t, artifact.LastChunk)
+		assert.Equal(t, i > 0, *artifact.Append)
+		assert.Equal(t, i == len(task.Artifacts)-1, *artifact.LastChunk)
+		content.WriteString(artifact.Parts[0].(protocol.TextPart).Text)
+	}
+	assert.Len(t, content.String(), 10)
+
+	stats := processor.Stats()
+	assert.Equal(t, int64(1), stats.Started)
+	assert.Equal(t, int64(1), stats.Completed)
+	assert.Equal(t, int64(4), stats.Tokens)
+	assert.Equal(t, int64(10), stats.Bytes)
+}
+
+func TestProcessor_ErrorRate(t *testing.T) {
+	manager, processor, err := NewTaskManager(Profile{ErrorRate: 1}, WithSeed(1))
+	require.NoError(t, err)
+
+	task, err := manager.OnSendTask(context.Background(), newTestParams("sim-fail"))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrSimulatedFailure))
+	assert.Equal(t, protocol.TaskStateFailed, task.Status.State)
+	assert.Equal(t, int64(1), processor.Stats().Failed)
+
+	processor.SetProfile(Profile{})
+	task, err = manager.OnSendTask(context.Background(), newTestParams("sim-ok"))
+	require.NoError(t, err)
+	assert.Equal(t, protocol.TaskStateCompleted, task.Status.State)
+}
+
+func TestProcessor_LatencyHonorsCancellation(t *testing.T) {
+	manager, processor, err := NewTaskManager(Profile{Latency: Constant(10)})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = manager.OnSendTask(ctx, newTestParams("sim-cancel"))
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, int64(1), processor.Stats().Canceled)
+}
+
+func TestProcessor_TokenPacing(t *testing.T) {
+	manager, _, err := NewTaskManager(Profile{
+		ArtifactSize:    Constant(8),
+		TokenSize:       2,
+		TokensPerSecond: 100,
+	})
+	require.NoError(t, err)
+
+	start := time.Now()
+	_, err = manager.OnSendTask(context.Background(), newTestParams("sim-pace"))
+	require.NoError(t, err)
+	// Four tokens at 100 tokens/s wait three 10ms intervals between them.
+	assert.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond)
+}
+
+func TestDistributions(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	assert.Equal(t, 5.0, Constant(5).Sample(r))
+	for i := 0; i < 100; i++ {
+		v := Uniform(2, 4).Sample(r)
+		assert.GreaterOrEqual(t, v, 2.0)
+		assert.Less(t, v, 4.0)
+		assert.GreaterOrEqual(t, Normal(0, 10).Sample(r), 0.0, "samples must be clamped to zero")
+		assert.GreaterOrEqual(t, Exponential(1).Sample(r), 0.0)
+	}
+}
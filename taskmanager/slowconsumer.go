@@ -0,0 +1,434 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// SlowConsumerPolicy determines what happens to an event when the channel of
+// a subscriber is full because the client reads its stream too slowly. No
+// policy blocks the task: events are always handed over without waiting.
+type SlowConsumerPolicy int
+
+const (
+	// SlowConsumerDropNewest drops the events that do not fit in the channel.
+	SlowConsumerDropNewest SlowConsumerPolicy = iota
+	// SlowConsumerDropOldest queues the events that do not fit in the channel,
+	// dropping the oldest queued event when the queue is full.
+	SlowConsumerDropOldest
+	// SlowConsumerDisconnect closes the channel of the subscriber, ending its
+	// stream; the client can resubscribe.
+	SlowConsumerDisconnect
+	// SlowConsumerBufferToDisk queues the events that do not fit in the channel
+	// and writes them to a file once the queue is full, so no event is lost.
+	// Subscribers whose file would exceed its size limit are disconnected.
+	SlowConsumerBufferToDisk
+)
+
+// Defaults of SlowConsumerConfig.
+const (
+	defaultSlowConsumerQueue = 64
+	defaultMaxSpillBytes     = 64 << 20
+)
+
+// SlowConsumerConfig configures how events are delivered to slow subscribers.
+type SlowConsumerConfig struct {
+	// Policy selects the behavior when a subscriber channel is full. Defaults
+	// to dropping the newest events.
+	Policy SlowConsumerPolicy
+	// MaxQueued is the number of events queued in memory per subscriber by
+	// SlowConsumerDropOldest and SlowConsumerBufferToDisk. Defaults to 64.
+	MaxQueued int
+	// SpillDir is the directory of the files of SlowConsumerBufferToDisk.
+	// Defaults to the temporary directory.
+	SpillDir string
+	// MaxSpillBytes bounds the file of each subscriber of
+	// SlowConsumerBufferToDisk. Defaults to 64 MiB.
+	MaxSpillBytes int64
+}
+
+// SlowConsumerStats holds slow consumer counters, suitable for exporting as metrics.
+type SlowConsumerStats struct {
+	// Queued is the number of events currently queued in memory or on disk.
+	Queued int64
+	// Dropped is the total number of events dropped.
+	Dropped int64
+	// Disconnected is the total number of subscribers disconnected.
+	Disconnected int64
+	// Spilled is the total number of events written to disk.
+	Spilled int64
+}
+
+// SlowConsumerHandler delivers events to subscriber channels according to a
+// SlowConsumerConfig. Task managers attach every subscriber channel, send
+// events through Send, and either detach channels before closing them or
+// finish them once their task ended. Attach, Send, Detach and Finish must be
+// serialized per channel, e.g. under the subscriber lock of the task manager;
+// Stats is safe for concurrent use.
+type SlowConsumerHandler struct {
+	cfg    SlowConsumerConfig
+	queues sync.Map // chan<- protocol.TaskEvent -> *subscriberQueue
+
+	queued       atomic.Int64
+	dropped      atomic.Int64
+	disconnected atomic.Int64
+	spilled      atomic.Int64
+}
+
+// NewSlowConsumerHandler creates a handler applying cfg.
+func NewSlowConsumerHandler(cfg SlowConsumerConfig) *SlowConsumerHandler {
+	if cfg.MaxQueued <= 0 {
+		cfg.MaxQueued = defaultSlowConsumerQueue
+	}
+	if cfg.MaxSpillBytes <= 0 {
+		cfg.MaxSpillBytes = defaultMaxSpillBytes
+	}
+	return &SlowConsumerHandler{cfg: cfg}
+}
+
+// Attach prepares the delivery of events to ch.
+func (h *SlowConsumerHandler) Attach(ch chan<- protocol.TaskEvent) {
+	if h.cfg.Policy == SlowConsumerDropOldest || h.cfg.Policy == SlowConsumerBufferToDisk {
+		h.queues.Store(ch, newSubscriberQueue(h, ch))
+	}
+}
+
+// Send hands event over to the subscriber of ch without blocking. It returns
+// false if the subscriber must be disconnected: the caller then detaches and
+// closes ch.
+func (h *SlowConsumerHandler) Send(taskID string, ch chan<- protocol.TaskEvent, event protocol.TaskEvent) bool {
+	if q, ok := h.queues.Load(ch); ok {
+		if q.(*subscriberQueue).push(event) {
+			return true
+		}
+		log.Warnf("Disconnecting slow subscriber of task %s: its event buffer is full.", taskID)
+		h.disconnected.Add(1)
+		return false
+	}
+	select {
+	case ch <- event:
+		return true
+	default:
+	}
+	if h.cfg.Policy == SlowConsumerDisconnect {
+		log.Warnf("Disconnecting slow subscriber of task %s: its channel is full.", taskID)
+		h.disconnected.Add(1)
+		return false
+	}
+	log.Warnf("Warning: Dropping event for task %s subscriber - channel full or closed.", taskID)
+	h.dropped.Add(1)
+	return true
+}
+
+// Detach stops the delivery of events to ch, discarding those still queued.
+// Once it returns, ch can be closed, unless Finish was called for ch, in which
+// case ch is closed already.
+func (h *SlowConsumerHandler) Detach(ch chan<- protocol.TaskEvent) {
+	if q, ok := h.queues.LoadAndDelete(ch); ok {
+		q.(*subscriberQueue).stop()
+	}
+}
+
+// Finish closes ch once the events still queued for it are delivered, so that
+// the subscriber receives the final event of its task, and then stops
+// delivering events to it. Detaching ch meanwhile discards the events left and
+// closes ch. No event can be sent to ch after, and the caller must not close
+// it.
+func (h *SlowConsumerHandler) Finish(ch chan<- protocol.TaskEvent) {
+	q, ok := h.queues.Load(ch)
+	if !ok {
+		close(ch)
+		return
+	}
+	close(q.(*subscriberQueue).finished)
+}
+
+// Stats returns a snapshot of the slow consumer counters.
+func (h *SlowConsumerHandler) Stats() SlowConsumerStats {
+	return SlowConsumerStats{
+		Queued:       h.queued.Load(),
+		Dropped:      h.dropped.Load(),
+		Disconnected: h.disconnected.Load(),
+		Spilled:      h.spilled.Load(),
+	}
+}
+
+// subscriberQueue queues the events of a subscriber while a goroutine feeds
+// them to its channel.
+type subscriberQueue struct {
+	handler  *SlowConsumerHandler
+	ch       chan<- protocol.TaskEvent
+	wake     chan struct{} // Signals queued events to the goroutine.
+	finished chan struct{} // Closed once no more event is queued.
+	done     chan struct{} // Closed to stop the goroutine.
+	exited   chan struct{} // Closed when the goroutine exits.
+
+	mu     sync.Mutex
+	events []protocol.TaskEvent
+	first  uint64     // Position of events[0] among the events pushed.
+	spill  *spillFile // Events queued after those in memory, if any.
+}
+
+// newSubscriberQueue creates a queue feeding ch and starts its goroutine.
+func newSubscriberQueue(h *SlowConsumerHandler, ch chan<- protocol.TaskEvent) *subscriberQueue {
+	q := &subscriberQueue{
+		handler:  h,
+		ch:       ch,
+		wake:     make(chan struct{}, 1),
+		finished: make(chan struct{}),
+		done:     make(chan struct{}),
+		exited:   make(chan struct{}),
+	}
+	go q.run()
+	return q
+}
+
+// push queues event, applying the policy if the queue is full. It returns
+// false if the subscriber must be disconnected.
+func (q *subscriberQueue) push(event protocol.TaskEvent) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	defer q.signal()
+	cfg := q.handler.cfg
+	if q.spill == nil && len(q.events) < cfg.MaxQueued {
+		q.events = append(q.events, event)
+		q.handler.queued.Add(1)
+		return true
+	}
+	if cfg.Policy == SlowConsumerDropOldest {
+		q.events = append(q.events[1:], event)
+		q.first++
+		q.handler.dropped.Add(1)
+		return true
+	}
+	if q.spill == nil {
+		spill, err := newSpillFile(cfg.SpillDir)
+		if err != nil {
+			log.Errorf("Failed to buffer events of slow subscriber to disk: %v", err)
+			return false
+		}
+		q.spill = spill
+	}
+	if err := q.spill.write(event, cfg.MaxSpillBytes); err != nil {
+		log.Errorf("Failed to buffer event of slow subscriber to disk: %v", err)
+		return false
+	}
+	q.handler.queued.Add(1)
+	q.handler.spilled.Add(1)
+	return true
+}
+
+// signal wakes the goroutine up, if it is not already signaled.
+func (q *subscriberQueue) signal() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// next returns the oldest queued event and its position, moving spilled
+// events to memory once the memory queue is drained.
+func (q *subscriberQueue) next() (protocol.TaskEvent, uint64, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.events) == 0 && q.spill != nil {
+		events, err := q.spill.readAll()
+		if err != nil {
+			log.Errorf("Failed to read events of slow subscriber from disk: %v", err)
+			lost := q.spill.count - int64(len(events))
+			q.handler.dropped.Add(lost)
+			q.handler.queued.Add(-lost)
+		}
+		q.spill.remove()
+		q.spill = nil
+		q.events = events
+	}
+	if len(q.events) == 0 {
+		return nil, 0, false
+	}
+	return q.events[0], q.first, true
+}
+
+// delivered removes the event at position, which was delivered, unless a
+// push dropped it meanwhile.
+func (q *subscriberQueue) delivered(position uint64) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.events) > 0 && q.first == position {
+		q.events = q.events[1:]
+		q.first++
+		q.handler.queued.Add(-1)
+	}
+}
+
+// run feeds the queued events to the channel until stopped, or until they are
+// all delivered once finished, closing the channel if finished.
+func (q *subscriberQueue) run() {
+	defer close(q.exited)
+	finishing := false
+	for {
+		event, position, ok := q.next()
+		if !ok {
+			if finishing {
+				// The queue is drained: nothing can be pushed after finishing.
+				q.handler.queues.CompareAndDelete(q.ch, q)
+				close(q.ch)
+				return
+			}
+			select {
+			case <-q.wake:
+			case <-q.finished:
+				finishing = true
+			case <-q.done:
+				q.closeIfFinished()
+				return
+			}
+			continue
+		}
+		select {
+		case q.ch <- event:
+			q.delivered(position)
+		case <-q.done:
+			q.closeIfFinished()
+			return
+		}
+	}
+}
+
+// closeIfFinished closes the channel if the queue was finished.
+func (q *subscriberQueue) closeIfFinished() {
+	select {
+	case <-q.finished:
+		close(q.ch)
+	default:
+	}
+}
+
+// stop stops the goroutine, waits for it and discards the queued events.
+func (q *subscriberQueue) stop() {
+	close(q.done)
+	<-q.exited
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	discarded := int64(len(q.events))
+	if q.spill != nil {
+		discarded += q.spill.count
+		q.spill.remove()
+		q.spill = nil
+	}
+	q.events = nil
+	q.handler.queued.Add(-discarded)
+}
+
+// spilledEvent is the form of an event in a spill file.
+type spilledEvent struct {
+	Type  string          `json:"type"`
+	Event json.RawMessage `json:"event"`
+}
+
+// spillFile holds the events of a subscriber written to disk.
+type spillFile struct {
+	file  *os.File
+	size  int64
+	count int64
+}
+
+// newSpillFile creates a spill file in dir.
+func newSpillFile(dir string) (*spillFile, error) {
+	file, err := os.CreateTemp(dir, "a2a-events-*.jsonl")
+	if err != nil {
+		return nil, err
+	}
+	return &spillFile{file: file}, nil
+}
+
+// write appends event, failing if the file would exceed maxBytes.
+func (f *spillFile) write(event protocol.TaskEvent, maxBytes int64) error {
+	var eventType string
+	switch event.(type) {
+	case protocol.TaskStatusUpdateEvent:
+		eventType = protocol.EventTaskStatusUpdate
+	case protocol.TaskArtifactUpdateEvent:
+		eventType = protocol.EventTaskArtifactUpdate
+	default:
+		return fmt.Errorf("unsupported event type: %T", event)
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	line, err := json.Marshal(spilledEvent{Type: eventType, Event: data})
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+	if f.size+int64(len(line)) > maxBytes {
+		return fmt.Errorf("buffer file would exceed %d bytes", maxBytes)
+	}
+	n, err := f.file.Write(line)
+	f.size += int64(n)
+	if err != nil {
+		return err
+	}
+	f.count++
+	return nil
+}
+
+// readAll reads back the events written.
+func (f *spillFile) readAll() ([]protocol.TaskEvent, error) {
+	if _, err := f.file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	events := make([]protocol.TaskEvent, 0, f.count)
+	scanner := bufio.NewScanner(f.file)
+	scanner.Buffer(nil, int(f.size)+1)
+	for scanner.Scan() {
+		event, err := decodeSpilledEvent(scanner.Bytes())
+		if err != nil {
+			return events, err
+		}
+		events = append(events, event)
+	}
+	return events, scanner.Err()
+}
+
+// decodeSpilledEvent decodes a line of a spill file.
+func decodeSpilledEvent(line []byte) (protocol.TaskEvent, error) {
+	var spilled spilledEvent
+	if err := json.Unmarshal(line, &spilled); err != nil {
+		return nil, err
+	}
+	switch spilled.Type {
+	case protocol.EventTaskStatusUpdate:
+		var event protocol.TaskStatusUpdateEvent
+		err := json.Unmarshal(spilled.Event, &event)
+		return event, err
+	case protocol.EventTaskArtifactUpdate:
+		var event protocol.TaskArtifactUpdateEvent
+		err := json.Unmarshal(spilled.Event, &event)
+		return event, err
+	}
+	return nil, fmt.Errorf("unsupported event type %q", spilled.Type)
+}
+
+// remove closes and deletes the file.
+func (f *spillFile) remove() {
+	f.file.Close()
+	if err := os.Remove(f.file.Name()); err != nil {
+		log.Warnf("Failed to remove event buffer file %s: %v", f.file.Name(), err)
+	}
+}
@@ -0,0 +1,241 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// numberedEvent returns a status update event numbered i.
+func numberedEvent(i int) protocol.TaskEvent {
+	return protocol.TaskStatusUpdateEvent{
+		ID:     "t1",
+		Status: protocol.TaskStatus{State: protocol.TaskStateWorking, Timestamp: strconv.Itoa(i)},
+	}
+}
+
+// receiveTimestamps reads n events from ch and returns their timestamps.
+func receiveTimestamps(t *testing.T, ch <-chan protocol.TaskEvent, n int) []string {
+	var got []string
+	for i := 0; i < n; i++ {
+		select {
+		case event := <-ch:
+			got = append(got, event.(protocol.TaskStatusUpdateEvent).Status.Timestamp)
+		case <-time.After(time.Second):
+			t.Fatalf("received %d of %d events", i, n)
+		}
+	}
+	return got
+}
+
+func TestSlowConsumerHandler_DropNewest(t *testing.T) {
+	h := NewSlowConsumerHandler(SlowConsumerConfig{})
+	ch := make(chan protocol.TaskEvent, 2)
+	h.Attach(ch)
+	for i := 0; i < 4; i++ {
+		assert.True(t, h.Send("t1", ch, numberedEvent(i)))
+	}
+	assert.Equal(t, []string{"0", "1"}, receiveTimestamps(t, ch, 2))
+	assert.Equal(t, SlowConsumerStats{Dropped: 2}, h.Stats())
+}
+
+func TestSlowConsumerHandler_DropOldest(t *testing.T) {
+	h := NewSlowConsumerHandler(SlowConsumerConfig{Policy: SlowConsumerDropOldest, MaxQueued: 2})
+	ch := make(chan protocol.TaskEvent)
+	h.Attach(ch)
+	// The goroutine may hold event 0 while waiting for the reader; the queue
+	// keeps the two newest events.
+	for i := 0; i < 5; i++ {
+		assert.True(t, h.Send("t1", ch, numberedEvent(i)))
+	}
+	got := receiveTimestamps(t, ch, 2)
+	if got[0] == "0" {
+		got = append(got[1:], receiveTimestamps(t, ch, 1)...)
+	}
+	assert.Equal(t, []string{"3", "4"}, got)
+	require.Eventually(t, func() bool { return h.Stats().Queued == 0 }, time.Second, 5*time.Millisecond)
+	assert.GreaterOrEqual(t, h.Stats().Dropped, int64(2))
+	h.Detach(ch)
+}
+
+func TestSlowConsumerHandler_Disconnect(t *testing.T) {
+	h := NewSlowConsumerHandler(SlowConsumerConfig{Policy: SlowConsumerDisconnect})
+	ch := make(chan protocol.TaskEvent, 1)
+	h.Attach(ch)
+	assert.True(t, h.Send("t1", ch, numberedEvent(0)))
+	assert.False(t, h.Send("t1", ch, numberedEvent(1)))
+	assert.Equal(t, int64(1), h.Stats().Disconnected)
+}
+
+func TestSlowConsumerHandler_BufferToDisk(t *testing.T) {
+	dir := t.TempDir()
+	h := NewSlowConsumerHandler(SlowConsumerConfig{
+		Policy:    SlowConsumerBufferToDisk,
+		MaxQueued: 2,
+		SpillDir:  dir,
+	})
+	ch := make(chan protocol.TaskEvent)
+	h.Attach(ch)
+	for i := 0; i < 10; i++ {
+		assert.True(t, h.Send("t1", ch, numberedEvent(i)))
+	}
+	assert.Positive(t, h.Stats().Spilled)
+
+	want := make([]string, 10)
+	for i := range want {
+		want[i] = strconv.Itoa(i)
+	}
+	assert.Equal(t, want, receiveTimestamps(t, ch, 10))
+	require.Eventually(t, func() bool { return h.Stats().Queued == 0 }, time.Second, 5*time.Millisecond)
+	assert.Zero(t, h.Stats().Dropped)
+	h.Detach(ch)
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, files)
+}
+
+func TestSlowConsumerHandler_BufferToDiskLimit(t *testing.T) {
+	h := NewSlowConsumerHandler(SlowConsumerConfig{
+		Policy:        SlowConsumerBufferToDisk,
+		MaxQueued:     1,
+		SpillDir:      t.TempDir(),
+		MaxSpillBytes: 1,
+	})
+	ch := make(chan protocol.TaskEvent)
+	h.Attach(ch)
+	disconnected := false
+	for i := 0; i < 4 && !disconnected; i++ {
+		disconnected = !h.Send("t1", ch, numberedEvent(i))
+	}
+	assert.True(t, disconnected)
+	assert.Equal(t, int64(1), h.Stats().Disconnected)
+	h.Detach(ch)
+	assert.Zero(t, h.Stats().Queued)
+}
+
+func TestSlowConsumerHandler_Finish(t *testing.T) {
+	h := NewSlowConsumerHandler(SlowConsumerConfig{Policy: SlowConsumerDropOldest})
+	ch := make(chan protocol.TaskEvent)
+	h.Attach(ch)
+	for i := 0; i < 3; i++ {
+		assert.True(t, h.Send("t1", ch, numberedEvent(i)))
+	}
+	h.Finish(ch)
+	// The queued events, the last one included, are delivered before the
+	// channel is closed.
+	var got []string
+	for event := range ch {
+		got = append(got, event.(protocol.TaskStatusUpdateEvent).Status.Timestamp)
+	}
+	assert.Equal(t, []string{"0", "1", "2"}, got)
+	assert.Zero(t, h.Stats().Queued)
+	_, attached := h.queues.Load((chan<- protocol.TaskEvent)(ch))
+	assert.False(t, attached)
+
+	// Detaching a finished channel discards the events left and closes it.
+	ch = make(chan protocol.TaskEvent)
+	h.Attach(ch)
+	for i := 0; i < 3; i++ {
+		assert.True(t, h.Send("t1", ch, numberedEvent(i)))
+	}
+	h.Finish(ch)
+	h.Detach(ch)
+	for range ch {
+	}
+	assert.Zero(t, h.Stats().Queued)
+
+	// Channels without queue are closed at once.
+	h = NewSlowConsumerHandler(SlowConsumerConfig{})
+	ch = make(chan protocol.TaskEvent, 1)
+	h.Attach(ch)
+	assert.True(t, h.Send("t1", ch, numberedEvent(0)))
+	h.Finish(ch)
+	assert.Equal(t, []string{"0"}, receiveTimestamps(t, ch, 1))
+	_, open := <-ch
+	assert.False(t, open)
+}
+
+func TestMemoryTaskManager_SlowConsumerDisconnect(t *testing.T) {
+	proceed := make(chan struct{})
+	processor := &mockProcessor{
+		processFunc: func(ctx context.Context, taskID string, msg protocol.Message, handle TaskHandle) error {
+			<-proceed
+			for i := 0; i < 20; i++ {
+				if err := handle.UpdateStatus(protocol.TaskStateWorking, nil); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	tm, err := NewMemoryTaskManager(processor,
+		WithSlowConsumerPolicy(SlowConsumerConfig{Policy: SlowConsumerDisconnect}))
+	require.NoError(t, err)
+
+	events, err := tm.OnSendTaskSubscribe(context.Background(), protocol.SendTaskParams{
+		ID:      "slow",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")}),
+	})
+	require.NoError(t, err)
+	// The subscriber does not read until its channel overflows.
+	close(proceed)
+	require.Eventually(t, func() bool {
+		return tm.SlowConsumerStats().Disconnected == 1
+	}, time.Second, 5*time.Millisecond)
+	count := 0
+	for range events {
+		count++
+	}
+	assert.Equal(t, cap(events), count)
+	assert.Zero(t, tm.SubscriptionStats().Active)
+}
+
+func TestMemoryTaskManager_SlowConsumerFinalEvent(t *testing.T) {
+	proceed := make(chan struct{})
+	processor := &mockProcessor{
+		processFunc: func(ctx context.Context, taskID string, msg protocol.Message, handle TaskHandle) error {
+			<-proceed
+			for i := 0; i < 20; i++ {
+				if err := handle.UpdateStatus(protocol.TaskStateWorking, nil); err != nil {
+					return err
+				}
+			}
+			return handle.UpdateStatus(protocol.TaskStateCompleted, nil)
+		},
+	}
+	tm, err := NewMemoryTaskManager(processor,
+		WithSlowConsumerPolicy(SlowConsumerConfig{Policy: SlowConsumerDropOldest, MaxQueued: 4}))
+	require.NoError(t, err)
+
+	events, err := tm.OnSendTaskSubscribe(context.Background(), protocol.SendTaskParams{
+		ID:      "slow",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")}),
+	})
+	require.NoError(t, err)
+	// The subscriber does not read until the task ended.
+	close(proceed)
+	require.Eventually(t, func() bool {
+		return tm.SubscriptionStats().Active == 0
+	}, time.Second, 5*time.Millisecond)
+	var last protocol.TaskEvent
+	for event := range events {
+		last = event
+	}
+	require.NotNil(t, last)
+	assert.True(t, last.IsFinal())
+	assert.Equal(t, protocol.TaskStateCompleted, last.(protocol.TaskStatusUpdateEvent).Status.State)
+	assert.Zero(t, tm.SlowConsumerStats().Queued)
+}
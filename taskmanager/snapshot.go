@@ -0,0 +1,120 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// TaskSnapshotVersion is the version of the TaskSnapshot format.
+const TaskSnapshotVersion = 1
+
+// TaskSnapshot is a portable JSON bundle of a task, to move it to another
+// server or inspect it offline.
+type TaskSnapshot struct {
+	// Version is the version of the format, TaskSnapshotVersion.
+	Version int `json:"version"`
+	// ExportedAt is when the snapshot was taken.
+	ExportedAt time.Time `json:"exportedAt"`
+	// Task is the task with its status and artifacts, without its history.
+	Task protocol.Task `json:"task"`
+	// History is the message history of the task.
+	History []protocol.Message `json:"history,omitempty"`
+	// PushNotification is the push notification configuration of the task,
+	// if any.
+	PushNotification *protocol.PushNotificationConfig `json:"pushNotification,omitempty"`
+}
+
+// TaskSnapshotter is implemented by task managers that can export their tasks
+// and import those of other instances, as used by the admin API of the server.
+type TaskSnapshotter interface {
+	// ExportTask returns a snapshot of the task taskID.
+	ExportTask(ctx context.Context, taskID string) (*TaskSnapshot, error)
+	// ImportTask creates the task of snapshot as it was exported, and returns
+	// it. Tasks with the ID of an existing task are rejected with
+	// ErrTaskExists. Imported tasks are not processed: those imported in
+	// progress keep their state until they are canceled or receive a message.
+	ImportTask(ctx context.Context, snapshot *TaskSnapshot) (*protocol.Task, error)
+}
+
+// ExportTask implements TaskSnapshotter.
+func (m *MemoryTaskManager) ExportTask(ctx context.Context, taskID string) (*TaskSnapshot, error) {
+	historyLength := 0
+	task, err := m.OnGetTask(ctx, protocol.TaskQueryParams{ID: taskID, HistoryLength: &historyLength})
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &TaskSnapshot{
+		Version:    TaskSnapshotVersion,
+		ExportedAt: time.Now().UTC(),
+		History:    task.History,
+	}
+	task.History = nil
+	snapshot.Task = *task
+	m.PushNotificationsMutex.RLock()
+	if config, exists := m.PushNotifications[taskID]; exists {
+		snapshot.PushNotification = &config
+	}
+	m.PushNotificationsMutex.RUnlock()
+	return snapshot, nil
+}
+
+// ImportTask implements TaskSnapshotter.
+func (m *MemoryTaskManager) ImportTask(ctx context.Context, snapshot *TaskSnapshot) (*protocol.Task, error) {
+	if err := checkTaskSnapshot(snapshot); err != nil {
+		return nil, err
+	}
+	taskID := snapshot.Task.ID
+	task := snapshot.Task
+	task.History = nil
+	// The task is created first, so that only one of concurrent imports of
+	// the same task goes on to record its push config and history.
+	if err := CreateTask(ctx, m.store, &task); err != nil {
+		return nil, err
+	}
+	if snapshot.PushNotification != nil {
+		if err := m.setPushConfig(ctx, taskID, *snapshot.PushNotification); err != nil {
+			if deleteErr := m.store.Delete(ctx, taskID); deleteErr != nil {
+				log.Errorf("Failed to delete task %s after failing to import it: %v", taskID, deleteErr)
+			}
+			return nil, err
+		}
+	}
+	if len(snapshot.History) > 0 {
+		m.MessagesMutex.Lock()
+		m.Messages[taskID] = append([]protocol.Message(nil), snapshot.History...)
+		m.MessagesMutex.Unlock()
+	}
+	if m.spill != nil {
+		for _, artifact := range task.Artifacts {
+			m.spill.grow(spillKey{taskID, spillArtifacts}, ArtifactSize(artifact))
+		}
+		m.spill.grow(spillKey{taskID, spillHistory}, historySize(snapshot.History...))
+		m.enforceSpillBudget()
+	}
+	log.Infof("Imported task %s in state %s", taskID, task.Status.State)
+	return m.getTaskInternal(taskID)
+}
+
+// checkTaskSnapshot checks that snapshot can be imported.
+func checkTaskSnapshot(snapshot *TaskSnapshot) error {
+	switch {
+	case snapshot == nil:
+		return jsonrpc.ErrInvalidParams("missing task snapshot")
+	case snapshot.Version != TaskSnapshotVersion:
+		return jsonrpc.ErrInvalidParams(fmt.Sprintf("unsupported task snapshot version %d", snapshot.Version))
+	case snapshot.Task.ID == "":
+		return jsonrpc.ErrInvalidParams("task snapshot without task ID")
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// slowGetTaskStore is a mapTaskStore creating tasks atomically and slow to
+// look them up.
+type slowGetTaskStore struct {
+	mapTaskStore
+}
+
+func (s *slowGetTaskStore) Get(ctx context.Context, taskID string) (*protocol.Task, error) {
+	task, err := s.mapTaskStore.Get(ctx, taskID)
+	time.Sleep(10 * time.Millisecond)
+	return task, err
+}
+
+// Create implements TaskCreator.
+func (s *slowGetTaskStore) Create(ctx context.Context, task *protocol.Task) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.tasks[task.ID]; ok {
+		return ErrTaskExists(task.ID)
+	}
+	s.tasks[task.ID] = *task
+	return nil
+}
+
+func TestMemoryTaskManager_Snapshots(t *testing.T) {
+	ctx := context.Background()
+	processor := &mockProcessor{
+		processFunc: func(ctx context.Context, taskID string, msg protocol.Message, handle TaskHandle) error {
+			if err := handle.AddArtifact(protocol.Artifact{Parts: msg.Parts}); err != nil {
+				return err
+			}
+			return handle.UpdateStatus(protocol.TaskStateInputRequired, nil)
+		},
+	}
+	source, err := NewMemoryTaskManager(processor)
+	require.NoError(t, err)
+	_, err = source.OnSendTask(ctx, protocol.SendTaskParams{
+		ID:      "moved",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")}),
+	})
+	require.NoError(t, err)
+	_, err = source.OnPushNotificationSet(ctx, protocol.TaskPushNotificationConfig{
+		ID:                     "moved",
+		PushNotificationConfig: protocol.PushNotificationConfig{URL: "https://hooks.example.com/a2a"},
+	})
+	require.NoError(t, err)
+
+	snapshot, err := source.ExportTask(ctx, "moved")
+	require.NoError(t, err)
+	assert.Equal(t, TaskSnapshotVersion, snapshot.Version)
+	assert.Nil(t, snapshot.Task.History)
+	require.Len(t, snapshot.History, 1)
+	require.NotNil(t, snapshot.PushNotification)
+
+	// Snapshots survive their JSON encoding.
+	data, err := json.Marshal(snapshot)
+	require.NoError(t, err)
+	var decoded TaskSnapshot
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	target, err := NewMemoryTaskManager(processor)
+	require.NoError(t, err)
+	task, err := target.ImportTask(ctx, &decoded)
+	require.NoError(t, err)
+	assert.Equal(t, protocol.TaskStateInputRequired, task.Status.State)
+	require.Len(t, task.Artifacts, 1)
+
+	historyLength := 0
+	task, err = target.OnGetTask(ctx, protocol.TaskQueryParams{ID: "moved", HistoryLength: &historyLength})
+	require.NoError(t, err)
+	assert.Equal(t, snapshot.History, task.History)
+	config, err := target.OnPushNotificationGet(ctx, protocol.TaskIDParams{ID: "moved"})
+	require.NoError(t, err)
+	assert.Equal(t, snapshot.PushNotification.URL, config.PushNotificationConfig.URL)
+
+	_, err = target.ImportTask(ctx, &decoded)
+	var rpcErr *jsonrpc.Error
+	require.True(t, errors.As(err, &rpcErr), "tasks are imported once")
+	assert.Equal(t, ErrCodeTaskExists, rpcErr.Code)
+
+	// Of concurrent imports of a task, only one succeeds, and the others do
+	// not replace its push config, even if they all look the task up before
+	// any creates it.
+	concurrent, err := NewMemoryTaskManager(processor, WithTaskStore(&slowGetTaskStore{
+		mapTaskStore: mapTaskStore{tasks: map[string]protocol.Task{}},
+	}))
+	require.NoError(t, err)
+	var wg sync.WaitGroup
+	var imported atomic.Int32
+	var winner atomic.Value
+	start := make(chan struct{})
+	for i := 0; i < 32; i++ {
+		snapshot := decoded
+		snapshot.PushNotification = &protocol.PushNotificationConfig{
+			URL: fmt.Sprintf("https://hooks.example.com/%d", i),
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			if _, err := concurrent.ImportTask(ctx, &snapshot); err == nil {
+				imported.Add(1)
+				winner.Store(snapshot.PushNotification.URL)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+	require.Equal(t, int32(1), imported.Load())
+	config, err = concurrent.OnPushNotificationGet(ctx, protocol.TaskIDParams{ID: "moved"})
+	require.NoError(t, err)
+	assert.Equal(t, winner.Load(), config.PushNotificationConfig.URL)
+
+	decoded.Version = TaskSnapshotVersion + 1
+	decoded.Task.ID = "future"
+	_, err = target.ImportTask(ctx, &decoded)
+	assert.Error(t, err)
+}
@@ -0,0 +1,405 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// defaultMaxResidentBytes is the default memory budget of spilled task data.
+const defaultMaxResidentBytes = 256 << 20
+
+// TaskSpillConfig configures how a MemoryTaskManager spills the artifacts and
+// histories of tasks to disk to bound its memory.
+type TaskSpillConfig struct {
+	// Dir is the directory in which the spill directory is created.
+	// Defaults to os.TempDir().
+	Dir string
+	// MaxResidentBytes bounds the size of the artifacts and histories kept in
+	// memory across tasks. Defaults to 256 MiB.
+	MaxResidentBytes int64
+	// Keys, if set, encrypts the artifacts and histories written to disk.
+	Keys KeyProvider
+}
+
+// TaskSpillStats holds the counters of a task spiller.
+type TaskSpillStats struct {
+	// ResidentBytes is the size of the artifacts and histories in memory.
+	ResidentBytes int64 `json:"residentBytes"`
+	// SpilledBytes is the size of the artifacts and histories on disk.
+	SpilledBytes int64 `json:"spilledBytes"`
+	// Spills is the number of artifact lists and histories written to disk.
+	Spills int64 `json:"spills"`
+	// Restores is the number of artifact lists and histories read back into
+	// memory to be updated.
+	Restores int64 `json:"restores"`
+}
+
+// spillKind identifies the data of a task held by a spill segment.
+type spillKind int
+
+const (
+	spillArtifacts spillKind = iota
+	spillHistory
+)
+
+// spillKey identifies a spill segment.
+type spillKey struct {
+	taskID string
+	kind   spillKind
+}
+
+// spillSegment tracks the artifacts or the history of a task.
+type spillSegment struct {
+	size     int64  // Content size, as computed by PartSize.
+	lastUsed uint64 // Logical time of the last update.
+	file     string // Spill file if the segment is on disk.
+}
+
+// taskSpiller tracks the size of the artifacts and histories of tasks, keeps
+// the most recently updated in memory within the budget and writes the others
+// to disk. Reads of spilled data are served from disk; updates bring the data
+// back into memory. It does not own the task data: the MemoryTaskManager moves
+// it in and out under its locks, always acquired before mu.
+type taskSpiller struct {
+	cfg TaskSpillConfig
+
+	mu       sync.Mutex
+	dir      string // Created on first spill.
+	segments map[spillKey]*spillSegment
+	clock    uint64
+	stats    TaskSpillStats
+}
+
+// newTaskSpiller creates a spiller for cfg.
+func newTaskSpiller(cfg TaskSpillConfig) *taskSpiller {
+	if cfg.MaxResidentBytes <= 0 {
+		cfg.MaxResidentBytes = defaultMaxResidentBytes
+	}
+	return &taskSpiller{cfg: cfg, segments: make(map[spillKey]*spillSegment)}
+}
+
+// grow records that delta bytes were added to the resident segment key.
+func (s *taskSpiller) grow(key spillKey, delta int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	seg, ok := s.segments[key]
+	if !ok {
+		seg = &spillSegment{}
+		s.segments[key] = seg
+	}
+	s.clock++
+	seg.lastUsed = s.clock
+	seg.size += delta
+	s.stats.ResidentBytes += delta
+}
+
+// overBudget reports whether the resident segments exceed the budget.
+func (s *taskSpiller) overBudget() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stats.ResidentBytes > s.cfg.MaxResidentBytes
+}
+
+// victims returns the least recently updated resident segments to spill to
+// get back within the budget.
+func (s *taskSpiller) victims() []spillKey {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var resident []spillKey
+	for key, seg := range s.segments {
+		if seg.file == "" && seg.size > 0 {
+			resident = append(resident, key)
+		}
+	}
+	sort.Slice(resident, func(i, j int) bool {
+		return s.segments[resident[i]].lastUsed < s.segments[resident[j]].lastUsed
+	})
+	var victims []spillKey
+	excess := s.stats.ResidentBytes - s.cfg.MaxResidentBytes
+	for _, key := range resident {
+		if excess <= 0 {
+			break
+		}
+		victims = append(victims, key)
+		excess -= s.segments[key].size
+	}
+	return victims
+}
+
+// spill writes v, the data of the resident segment key, to disk.
+func (s *taskSpiller) spill(key spillKey, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if s.cfg.Keys != nil {
+		env, err := seal(context.Background(), s.cfg.Keys, key.taskID, data)
+		if err != nil {
+			return err
+		}
+		if data, err = json.Marshal(env); err != nil {
+			return err
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	seg, ok := s.segments[key]
+	if !ok || seg.file != "" {
+		return fmt.Errorf("segment of task %s is not resident", key.taskID)
+	}
+	if s.dir == "" {
+		dir, err := os.MkdirTemp(s.cfg.Dir, "a2a-tasks-*")
+		if err != nil {
+			return err
+		}
+		s.dir = dir
+	}
+	file, err := os.CreateTemp(s.dir, "task-*.json")
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+	seg.file = file.Name()
+	s.stats.ResidentBytes -= seg.size
+	s.stats.SpilledBytes += seg.size
+	s.stats.Spills++
+	return nil
+}
+
+// read decodes the data of segment key into v if it is on disk, reporting
+// whether it was.
+func (s *taskSpiller) read(key spillKey, v interface{}) (bool, error) {
+	s.mu.Lock()
+	seg, ok := s.segments[key]
+	if !ok || seg.file == "" {
+		s.mu.Unlock()
+		return false, nil
+	}
+	path := seg.file
+	s.mu.Unlock()
+	// The caller's lock on the task data keeps the file from being restored.
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return true, err
+	}
+	if s.cfg.Keys != nil {
+		var env envelope
+		if err := json.Unmarshal(data, &env); err != nil {
+			return true, err
+		}
+		if data, err = env.open(context.Background(), s.cfg.Keys, key.taskID); err != nil {
+			return true, err
+		}
+	}
+	return true, json.Unmarshal(data, v)
+}
+
+// restore decodes the data of segment key into v if it is on disk and makes
+// the segment resident again, reporting whether it was on disk.
+func (s *taskSpiller) restore(key spillKey, v interface{}) (bool, error) {
+	spilled, err := s.read(key, v)
+	if !spilled || err != nil {
+		return spilled, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	seg := s.segments[key]
+	os.Remove(seg.file)
+	seg.file = ""
+	s.clock++
+	seg.lastUsed = s.clock
+	s.stats.SpilledBytes -= seg.size
+	s.stats.ResidentBytes += seg.size
+	s.stats.Restores++
+	return true, nil
+}
+
+// drop forgets segment key, removing its file if it is on disk. A resident
+// segment is only forgotten if resident is set.
+func (s *taskSpiller) drop(key spillKey, resident bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	seg, ok := s.segments[key]
+	if !ok || (seg.file == "" && !resident) {
+		return
+	}
+	delete(s.segments, key)
+	if seg.file == "" {
+		s.stats.ResidentBytes -= seg.size
+		return
+	}
+	if err := os.Remove(seg.file); err != nil {
+		log.Warnf("Failed to remove spill file %s: %v", seg.file, err)
+	}
+	s.stats.SpilledBytes -= seg.size
+}
+
+// Stats returns a snapshot of the counters.
+func (s *taskSpiller) Stats() TaskSpillStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stats
+}
+
+// close removes the spill files.
+func (s *taskSpiller) close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.dir == "" {
+		return nil
+	}
+	err := os.RemoveAll(s.dir)
+	s.dir = ""
+	s.segments = make(map[spillKey]*spillSegment)
+	s.stats = TaskSpillStats{}
+	return err
+}
+
+// historySize returns the content size of messages.
+func historySize(messages ...protocol.Message) int64 {
+	var size int64
+	for _, msg := range messages {
+		for _, part := range msg.Parts {
+			size += PartSize(part)
+		}
+	}
+	return size
+}
+
+// TaskSpillStats returns the counters of the artifacts and histories kept in
+// memory and spilled to disk. It returns zero values unless WithTaskSpill is set.
+func (m *MemoryTaskManager) TaskSpillStats() TaskSpillStats {
+	if m.spill == nil {
+		return TaskSpillStats{}
+	}
+	return m.spill.Stats()
+}
+
+// Close stops the push notification deliveries in progress, which stay
+// pending in the push config store, and removes the files of the artifacts and
+// histories spilled to disk. The task manager must not be used afterwards.
+func (m *MemoryTaskManager) Close() error {
+	m.push.stop()
+	if m.spill == nil {
+		return nil
+	}
+	return m.spill.close()
+}
+
+// readSpilledArtifacts fills in the artifacts of task, a copy of a task of
+// the manager, if they are on disk. The caller must hold TasksMutex.
+func (m *MemoryTaskManager) readSpilledArtifacts(task *protocol.Task) {
+	if m.spill == nil {
+		return
+	}
+	if _, err := m.spill.read(spillKey{task.ID, spillArtifacts}, &task.Artifacts); err != nil {
+		log.Errorf("Failed to read spilled artifacts of task %s: %v", task.ID, err)
+	}
+}
+
+// readSpilledHistory returns the history of taskID if it is on disk. The
+// caller must hold MessagesMutex.
+func (m *MemoryTaskManager) readSpilledHistory(taskID string) ([]protocol.Message, bool) {
+	if m.spill == nil {
+		return nil, false
+	}
+	var messages []protocol.Message
+	spilled, err := m.spill.read(spillKey{taskID, spillHistory}, &messages)
+	if err != nil {
+		log.Errorf("Failed to read spilled history of task %s: %v", taskID, err)
+	}
+	return messages, spilled
+}
+
+// restoreArtifacts brings the artifacts of task back into memory before they
+// are updated. The caller must hold TasksMutex for writing.
+func (m *MemoryTaskManager) restoreArtifacts(task *protocol.Task) {
+	if m.spill == nil {
+		return
+	}
+	if _, err := m.spill.restore(spillKey{task.ID, spillArtifacts}, &task.Artifacts); err != nil {
+		log.Errorf("Failed to restore spilled artifacts of task %s: %v", task.ID, err)
+	}
+}
+
+// restoreHistory brings the history of taskID back into memory before it is
+// updated. The caller must hold MessagesMutex for writing.
+func (m *MemoryTaskManager) restoreHistory(taskID string) {
+	if m.spill == nil {
+		return
+	}
+	var messages []protocol.Message
+	spilled, err := m.spill.restore(spillKey{taskID, spillHistory}, &messages)
+	if err != nil {
+		log.Errorf("Failed to restore spilled history of task %s: %v", taskID, err)
+	}
+	if spilled {
+		m.Messages[taskID] = messages
+	}
+}
+
+// dropSpilledTask forgets the artifacts of taskID, which was deleted or
+// replaced, and its history if it is on disk, removing their files. The caller
+// must hold TasksMutex for writing.
+func (m *MemoryTaskManager) dropSpilledTask(taskID string) {
+	if m.spill == nil {
+		return
+	}
+	m.spill.drop(spillKey{taskID, spillArtifacts}, true)
+	m.spill.drop(spillKey{taskID, spillHistory}, false)
+}
+
+// enforceSpillBudget spills the least recently updated artifacts and
+// histories to disk until the resident data fits in the budget. The caller
+// must not hold TasksMutex or MessagesMutex.
+func (m *MemoryTaskManager) enforceSpillBudget() {
+	if m.spill == nil || !m.spill.overBudget() {
+		return
+	}
+	m.TasksMutex.Lock()
+	defer m.TasksMutex.Unlock()
+	m.MessagesMutex.Lock()
+	defer m.MessagesMutex.Unlock()
+	for _, key := range m.spill.victims() {
+		var err error
+		switch key.kind {
+		case spillArtifacts:
+			task, exists := m.Tasks[key.taskID]
+			if !exists {
+				m.spill.drop(key, true)
+				continue
+			}
+			if err = m.spill.spill(key, task.Artifacts); err == nil {
+				task.Artifacts = nil
+			}
+		case spillHistory:
+			if err = m.spill.spill(key, m.Messages[key.taskID]); err == nil {
+				delete(m.Messages, key.taskID)
+			}
+		}
+		if err != nil {
+			log.Errorf("Failed to spill data of task %s to disk: %v", key.taskID, err)
+			return
+		}
+	}
+}
@@ -0,0 +1,95 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// artifactText returns the text of the first part of artifact.
+func artifactText(t *testing.T, artifact protocol.Artifact) string {
+	t.Helper()
+	require.NotEmpty(t, artifact.Parts)
+	part, ok := artifact.Parts[0].(protocol.TextPart)
+	require.True(t, ok, "part type %T", artifact.Parts[0])
+	return part.Text
+}
+
+func TestMemoryTaskManager_TaskSpill(t *testing.T) {
+	dir := t.TempDir()
+	processor := &mockProcessor{
+		processFunc: func(ctx context.Context, taskID string, msg protocol.Message, handle TaskHandle) error {
+			return handle.AddArtifact(protocol.Artifact{
+				Parts: []protocol.Part{protocol.NewTextPart(strings.Repeat(taskID, 100))},
+			})
+		},
+	}
+	tm, err := NewMemoryTaskManager(processor, WithTaskSpill(TaskSpillConfig{Dir: dir, MaxResidentBytes: 1000}))
+	require.NoError(t, err)
+
+	// Each task holds 600 bytes of artifact, so only the last one stays in memory.
+	for _, id := range []string{"aaaaaa", "bbbbbb", "cccccc"} {
+		_, err := tm.OnSendTask(context.Background(), tenantParams(id))
+		require.NoError(t, err)
+	}
+	stats := tm.TaskSpillStats()
+	assert.LessOrEqual(t, stats.ResidentBytes, int64(1000))
+	assert.GreaterOrEqual(t, stats.SpilledBytes, int64(1200))
+	tm.TasksMutex.RLock()
+	assert.Nil(t, tm.Tasks["aaaaaa"].Artifacts, "spilled artifacts are not held in memory")
+	tm.TasksMutex.RUnlock()
+
+	// Spilled data is read from disk transparently.
+	zero := 0
+	task, err := tm.OnGetTask(context.Background(), protocol.TaskQueryParams{ID: "aaaaaa", HistoryLength: &zero})
+	require.NoError(t, err)
+	require.Len(t, task.Artifacts, 1)
+	assert.Equal(t, strings.Repeat("aaaaaa", 100), artifactText(t, task.Artifacts[0]))
+	require.NotEmpty(t, task.History)
+	assert.Equal(t, protocol.MessageRoleUser, task.History[0].Role)
+	tasks, err := tm.ListTasks(context.Background(), TaskFilter{})
+	require.NoError(t, err)
+	for _, task := range tasks {
+		assert.Len(t, task.Artifacts, 1, "task %s", task.ID)
+	}
+
+	// Updating a spilled task brings it back into memory.
+	require.NoError(t, tm.AddArtifact("aaaaaa", protocol.Artifact{
+		Index: 1,
+		Parts: []protocol.Part{protocol.NewTextPart("more")},
+	}))
+	task, err = tm.OnGetTask(context.Background(), protocol.TaskQueryParams{ID: "aaaaaa"})
+	require.NoError(t, err)
+	require.Len(t, task.Artifacts, 2)
+	assert.Equal(t, "more", artifactText(t, task.Artifacts[1]))
+	assert.Positive(t, tm.TaskSpillStats().Restores)
+
+	// Deleting a task removes its spilled data.
+	spilledFiles := func() int {
+		matches, err := filepath.Glob(filepath.Join(dir, "a2a-tasks-*", "task-*.json"))
+		require.NoError(t, err)
+		return len(matches)
+	}
+	files, spilled := spilledFiles(), tm.TaskSpillStats().SpilledBytes
+	require.NoError(t, tm.store.Delete(context.Background(), "bbbbbb"))
+	assert.Less(t, spilledFiles(), files)
+	assert.Less(t, tm.TaskSpillStats().SpilledBytes, spilled)
+
+	require.NoError(t, tm.Close())
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "spill files are removed on close")
+}
@@ -0,0 +1,142 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"sync"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// Transition is a change of the status of a task.
+type Transition struct {
+	// TaskID is the ID of the task.
+	TaskID string
+	// From is the state of the task before the transition.
+	From protocol.TaskState
+	// To is the state of the task after the transition.
+	To protocol.TaskState
+	// Status is the new status of the task.
+	Status protocol.TaskStatus
+}
+
+// TransitionHook is called before a transition of a task status. An error
+// rejects the transition and is returned to whoever updated the status.
+type TransitionHook func(t Transition) error
+
+// StateMachine defines the legal transitions of the task states, enforced on
+// the status updates of processors, and runs hooks around every transition of
+// the tasks of a task manager, e.g. to attach side effects to them. The task
+// manager changes states itself outside the transitions of processors, e.g.
+// to restart a completed task receiving a new message or to cancel a task;
+// these changes are not validated, but run the hooks too.
+// A StateMachine is safe for concurrent use.
+type StateMachine struct {
+	mu      sync.RWMutex
+	allowed map[protocol.TaskState]map[protocol.TaskState]bool
+	before  []TransitionHook
+	after   []func(t Transition)
+}
+
+// NewStateMachine creates a state machine allowing processors to move tasks
+// from the submitted, working and input-required states to the working,
+// input-required, completed, failed and canceled states. The completed,
+// failed and canceled states are final.
+func NewStateMachine() *StateMachine {
+	sm := &StateMachine{allowed: make(map[protocol.TaskState]map[protocol.TaskState]bool)}
+	for _, from := range []protocol.TaskState{
+		protocol.TaskStateSubmitted,
+		protocol.TaskStateWorking,
+		protocol.TaskStateInputRequired,
+	} {
+		sm.Allow(from,
+			protocol.TaskStateWorking,
+			protocol.TaskStateInputRequired,
+			protocol.TaskStateCompleted,
+			protocol.TaskStateFailed,
+			protocol.TaskStateCanceled,
+		)
+	}
+	return sm
+}
+
+// Allow makes the transitions from the state from to the states to legal.
+func (sm *StateMachine) Allow(from protocol.TaskState, to ...protocol.TaskState) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	if sm.allowed[from] == nil {
+		sm.allowed[from] = make(map[protocol.TaskState]bool)
+	}
+	for _, state := range to {
+		sm.allowed[from][state] = true
+	}
+}
+
+// Forbid makes the transitions from the state from to the states to illegal.
+func (sm *StateMachine) Forbid(from protocol.TaskState, to ...protocol.TaskState) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	for _, state := range to {
+		delete(sm.allowed[from], state)
+	}
+}
+
+// CanTransition reports whether the transition from the state from to the
+// state to is legal.
+func (sm *StateMachine) CanTransition(from, to protocol.TaskState) bool {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.allowed[from][to]
+}
+
+// BeforeTransition adds a hook called before each transition, in the order
+// the hooks were added. The status of the task is locked while the hooks run,
+// so they must not call the task manager.
+func (sm *StateMachine) BeforeTransition(hook TransitionHook) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.before = append(sm.before, hook)
+}
+
+// AfterTransition adds a hook called after each transition, in the order the
+// hooks were added, once the new status is recorded.
+func (sm *StateMachine) AfterTransition(hook func(t Transition)) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.after = append(sm.after, hook)
+}
+
+// validate checks that a processor can make the transition t.
+func (sm *StateMachine) validate(t Transition) error {
+	if !sm.CanTransition(t.From, t.To) {
+		return ErrInvalidStateTransition(t.TaskID, t.From, t.To)
+	}
+	return nil
+}
+
+// runBefore runs the hooks called before t, stopping at the first error.
+func (sm *StateMachine) runBefore(t Transition) error {
+	sm.mu.RLock()
+	hooks := sm.before
+	sm.mu.RUnlock()
+	for _, hook := range hooks {
+		if err := hook(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runAfter runs the hooks called after t.
+func (sm *StateMachine) runAfter(t Transition) {
+	sm.mu.RLock()
+	hooks := sm.after
+	sm.mu.RUnlock()
+	for _, hook := range hooks {
+		hook(t)
+	}
+}
@@ -0,0 +1,92 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+func TestStateMachine(t *testing.T) {
+	sm := NewStateMachine()
+	assert.True(t, sm.CanTransition(protocol.TaskStateSubmitted, protocol.TaskStateWorking))
+	assert.True(t, sm.CanTransition(protocol.TaskStateWorking, protocol.TaskStateWorking))
+	assert.True(t, sm.CanTransition(protocol.TaskStateInputRequired, protocol.TaskStateCompleted))
+	assert.False(t, sm.CanTransition(protocol.TaskStateWorking, protocol.TaskStateSubmitted))
+	for _, final := range []protocol.TaskState{
+		protocol.TaskStateCompleted, protocol.TaskStateFailed, protocol.TaskStateCanceled,
+	} {
+		assert.False(t, sm.CanTransition(final, protocol.TaskStateWorking), final)
+	}
+
+	sm.Allow(protocol.TaskStateFailed, protocol.TaskStateWorking)
+	assert.True(t, sm.CanTransition(protocol.TaskStateFailed, protocol.TaskStateWorking))
+	sm.Forbid(protocol.TaskStateWorking, protocol.TaskStateCanceled)
+	assert.False(t, sm.CanTransition(protocol.TaskStateWorking, protocol.TaskStateCanceled))
+}
+
+func TestMemoryTaskManager_StateMachine(t *testing.T) {
+	sm := NewStateMachine()
+	var (
+		mu          sync.Mutex
+		transitions []Transition
+	)
+	sm.AfterTransition(func(t Transition) {
+		mu.Lock()
+		defer mu.Unlock()
+		transitions = append(transitions, t)
+	})
+	sm.BeforeTransition(func(t Transition) error {
+		if t.To == protocol.TaskStateInputRequired && t.Status.Message == nil {
+			return errors.New("input requests need a message")
+		}
+		return nil
+	})
+
+	var updateErrs []error
+	processor := &mockProcessor{
+		processFunc: func(ctx context.Context, taskID string, msg protocol.Message, handle TaskHandle) error {
+			updateErrs = append(updateErrs,
+				handle.UpdateStatus(protocol.TaskStateInputRequired, nil),
+				handle.UpdateStatus(protocol.TaskStateCompleted, nil),
+				handle.UpdateStatus(protocol.TaskStateWorking, nil))
+			return nil
+		},
+	}
+	tm, err := NewMemoryTaskManager(processor, WithStateMachine(sm))
+	require.NoError(t, err)
+	task, err := tm.OnSendTask(context.Background(), protocol.SendTaskParams{
+		ID:      "stateful",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")}),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, protocol.TaskStateCompleted, task.Status.State)
+
+	require.Len(t, updateErrs, 3)
+	assert.EqualError(t, updateErrs[0], "input requests need a message", "hooks reject transitions")
+	assert.NoError(t, updateErrs[1])
+	var rpcErr *jsonrpc.Error
+	require.True(t, errors.As(updateErrs[2], &rpcErr), "completed tasks are final")
+	assert.Equal(t, ErrCodeInvalidStateTransition, rpcErr.Code)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, transitions, 2)
+	assert.Equal(t, protocol.TaskStateSubmitted, transitions[0].From)
+	assert.Equal(t, protocol.TaskStateWorking, transitions[0].To)
+	assert.Equal(t, protocol.TaskStateWorking, transitions[1].From)
+	assert.Equal(t, protocol.TaskStateCompleted, transitions[1].To)
+	assert.NotEmpty(t, transitions[1].Status.Timestamp)
+}
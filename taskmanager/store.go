@@ -0,0 +1,164 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"errors"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// TaskStore stores the tasks of a MemoryTaskManager, which keeps the rest of
+// the task logic: the processing, subscriptions, message histories and state
+// machine of the tasks. Set with WithTaskStore, it lets the tasks live in a
+// database rather than in the memory of the server.
+// Stores hold their own copies of the tasks: the tasks passed to and returned
+// by a store are not retained by it. The History field of the tasks is not
+// used. Implementations must be safe for concurrent use.
+type TaskStore interface {
+	// Get returns the task taskID, or ErrTaskNotFound.
+	Get(ctx context.Context, taskID string) (*protocol.Task, error)
+	// Put stores task, replacing the task with the same ID, if any.
+	Put(ctx context.Context, task *protocol.Task) error
+	// List returns the tasks matched by filter, ignoring its limit, in any
+	// order.
+	List(ctx context.Context, filter TaskFilter) ([]protocol.Task, error)
+	// Delete deletes the task taskID, if any.
+	Delete(ctx context.Context, taskID string) error
+	// Update atomically applies update, e.g. a status update, to the task
+	// taskID and stores the result, unless update fails with an error, which
+	// Update returns. It returns the updated task, or ErrTaskNotFound.
+	Update(ctx context.Context, taskID string, update func(task *protocol.Task) error) (*protocol.Task, error)
+}
+
+// TaskCreator is implemented by the task stores that can create a task unless
+// one exists with its ID atomically, so that concurrent creations of the same
+// task, e.g. by two tasks/send requests, do not replace each other.
+type TaskCreator interface {
+	// Create stores task, or fails with ErrTaskExists if a task has its ID.
+	Create(ctx context.Context, task *protocol.Task) error
+}
+
+// CreateTask stores task in store, or fails with ErrTaskExists if a task has
+// its ID. The creation is atomic if store implements TaskCreator; otherwise
+// the task is looked up then put, and concurrent creations may replace each
+// other.
+func CreateTask(ctx context.Context, store TaskStore, task *protocol.Task) error {
+	if creator, ok := store.(TaskCreator); ok {
+		return creator.Create(ctx, task)
+	}
+	_, err := store.Get(ctx, task.ID)
+	switch {
+	case err == nil:
+		return ErrTaskExists(task.ID)
+	case !IsTaskNotFound(err):
+		return err
+	}
+	return store.Put(ctx, task)
+}
+
+// IsTaskNotFound reports whether err is, or wraps, an ErrTaskNotFound error.
+func IsTaskNotFound(err error) bool {
+	var rpcErr *jsonrpc.Error
+	return errors.As(err, &rpcErr) && rpcErr.Code == ErrCodeTaskNotFound
+}
+
+// isTaskExists reports whether err is, or wraps, an ErrTaskExists error.
+func isTaskExists(err error) bool {
+	var rpcErr *jsonrpc.Error
+	return errors.As(err, &rpcErr) && rpcErr.Code == ErrCodeTaskExists
+}
+
+// memoryTaskStore is the default TaskStore of a MemoryTaskManager, keeping the
+// tasks in its Tasks field, guarded by TasksMutex. Tasks read from it include
+// their artifacts spilled to disk, except those returned by Update.
+type memoryTaskStore struct {
+	m *MemoryTaskManager
+}
+
+// Get implements TaskStore.
+func (s memoryTaskStore) Get(ctx context.Context, taskID string) (*protocol.Task, error) {
+	s.m.TasksMutex.RLock()
+	defer s.m.TasksMutex.RUnlock()
+	task, exists := s.m.Tasks[taskID]
+	if !exists {
+		return nil, ErrTaskNotFound(taskID)
+	}
+	taskCopy := *task // Return a copy, taken under the lock.
+	s.m.readSpilledArtifacts(&taskCopy)
+	return &taskCopy, nil
+}
+
+// Put implements TaskStore.
+func (s memoryTaskStore) Put(ctx context.Context, task *protocol.Task) error {
+	taskCopy := *task
+	s.m.TasksMutex.Lock()
+	defer s.m.TasksMutex.Unlock()
+	if _, exists := s.m.Tasks[task.ID]; exists {
+		s.m.dropSpilledTask(task.ID)
+	}
+	s.m.Tasks[task.ID] = &taskCopy
+	return nil
+}
+
+// Create implements TaskCreator.
+func (s memoryTaskStore) Create(ctx context.Context, task *protocol.Task) error {
+	taskCopy := *task
+	s.m.TasksMutex.Lock()
+	defer s.m.TasksMutex.Unlock()
+	if _, exists := s.m.Tasks[task.ID]; exists {
+		return ErrTaskExists(task.ID)
+	}
+	s.m.Tasks[task.ID] = &taskCopy
+	return nil
+}
+
+// List implements TaskStore.
+func (s memoryTaskStore) List(ctx context.Context, filter TaskFilter) ([]protocol.Task, error) {
+	s.m.TasksMutex.RLock()
+	defer s.m.TasksMutex.RUnlock()
+	tasks := make([]protocol.Task, 0, len(s.m.Tasks))
+	for _, task := range s.m.Tasks {
+		if filter.Matches(task) {
+			taskCopy := *task
+			s.m.readSpilledArtifacts(&taskCopy)
+			tasks = append(tasks, taskCopy)
+		}
+	}
+	return tasks, nil
+}
+
+// Delete implements TaskStore.
+func (s memoryTaskStore) Delete(ctx context.Context, taskID string) error {
+	s.m.TasksMutex.Lock()
+	defer s.m.TasksMutex.Unlock()
+	delete(s.m.Tasks, taskID)
+	s.m.dropSpilledTask(taskID)
+	return nil
+}
+
+// Update implements TaskStore. update runs under TasksMutex.
+func (s memoryTaskStore) Update(
+	ctx context.Context,
+	taskID string,
+	update func(task *protocol.Task) error,
+) (*protocol.Task, error) {
+	s.m.TasksMutex.Lock()
+	defer s.m.TasksMutex.Unlock()
+	task, exists := s.m.Tasks[taskID]
+	if !exists {
+		return nil, ErrTaskNotFound(taskID)
+	}
+	updated := *task
+	if err := update(&updated); err != nil {
+		return nil, err
+	}
+	*task = updated
+	return &updated, nil
+}
@@ -0,0 +1,154 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// mapTaskStore is a TaskStore keeping tasks in a map, counting its updates.
+type mapTaskStore struct {
+	mu      sync.Mutex
+	tasks   map[string]protocol.Task
+	updates int
+}
+
+func (s *mapTaskStore) Get(ctx context.Context, taskID string) (*protocol.Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	task, ok := s.tasks[taskID]
+	if !ok {
+		return nil, ErrTaskNotFound(taskID)
+	}
+	return &task, nil
+}
+
+func (s *mapTaskStore) Put(ctx context.Context, task *protocol.Task) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tasks[task.ID] = *task
+	return nil
+}
+
+func (s *mapTaskStore) List(ctx context.Context, filter TaskFilter) ([]protocol.Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var tasks []protocol.Task
+	for _, task := range s.tasks {
+		if filter.Matches(&task) {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+func (s *mapTaskStore) Delete(ctx context.Context, taskID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.tasks, taskID)
+	return nil
+}
+
+func (s *mapTaskStore) Update(
+	ctx context.Context,
+	taskID string,
+	update func(task *protocol.Task) error,
+) (*protocol.Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	task, ok := s.tasks[taskID]
+	if !ok {
+		return nil, ErrTaskNotFound(taskID)
+	}
+	if err := update(&task); err != nil {
+		return nil, err
+	}
+	s.tasks[taskID] = task
+	s.updates++
+	return &task, nil
+}
+
+func TestMemoryTaskManager_ConcurrentCreate(t *testing.T) {
+	for _, store := range []TaskStore{nil, &mapTaskStore{tasks: make(map[string]protocol.Task)}} {
+		var opts []MemoryTaskManagerOption
+		if store != nil {
+			opts = append(opts, WithTaskStore(store))
+		}
+		tm, err := NewMemoryTaskManager(&mockProcessor{}, opts...)
+		require.NoError(t, err)
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, _, err := tm.upsertTask(protocol.SendTaskParams{
+					ID:       "task",
+					Message:  protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")}),
+					Metadata: map[string]interface{}{fmt.Sprint(i): true},
+				}, false)
+				assert.NoError(t, err)
+			}(i)
+		}
+		wg.Wait()
+		task, err := tm.store.Get(context.Background(), "task")
+		require.NoError(t, err)
+		if store == nil {
+			assert.Len(t, task.Metadata, 8, "no send replaces the task created by another")
+		} else {
+			assert.NotEmpty(t, task.Metadata, "stores without TaskCreator still create the task")
+		}
+	}
+}
+
+func TestMemoryTaskManager_TaskStore(t *testing.T) {
+	store := &mapTaskStore{tasks: make(map[string]protocol.Task)}
+	processor := &mockProcessor{
+		processFunc: func(ctx context.Context, taskID string, msg protocol.Message, handle TaskHandle) error {
+			if err := handle.AddArtifact(protocol.Artifact{
+				Parts: []protocol.Part{protocol.NewTextPart("result")},
+			}); err != nil {
+				return err
+			}
+			return handle.UpdateStatus(protocol.TaskStateCompleted, nil)
+		},
+	}
+	tm, err := NewMemoryTaskManager(processor, WithTaskStore(store))
+	require.NoError(t, err)
+
+	task, err := tm.OnSendTask(context.Background(), protocol.SendTaskParams{
+		ID:      "stored",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")}),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, protocol.TaskStateCompleted, task.Status.State)
+	assert.Empty(t, tm.Tasks, "tasks live in the store")
+
+	stored, err := store.Get(context.Background(), "stored")
+	require.NoError(t, err)
+	assert.Equal(t, protocol.TaskStateCompleted, stored.Status.State)
+	require.Len(t, stored.Artifacts, 1)
+	assert.Equal(t, 3, store.updates, "the working and completed statuses and the artifact")
+
+	tasks, err := tm.ListTasks(context.Background(), TaskFilter{States: []protocol.TaskState{protocol.TaskStateCompleted}})
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, "stored", tasks[0].ID)
+
+	_, err = tm.OnCancelTask(context.Background(), protocol.TaskIDParams{ID: "missing"})
+	assert.True(t, IsTaskNotFound(err))
+
+	_, err = NewMemoryTaskManager(processor, WithTaskStore(store), WithTaskSpill(TaskSpillConfig{}))
+	assert.Error(t, err, "spilled tasks must be in memory")
+}
@@ -0,0 +1,203 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"trpc.group/trpc-go/trpc-a2a-go/auth"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// SubscriptionOverflowPolicy determines what happens when a new subscription
+// would exceed one of the configured SubscriptionLimits.
+type SubscriptionOverflowPolicy int
+
+const (
+	// SubscriptionOverflowReject rejects the new subscription with ErrSubscriptionLimitExceeded.
+	SubscriptionOverflowReject SubscriptionOverflowPolicy = iota
+	// SubscriptionOverflowEvictOldest closes the oldest subscription covered by the
+	// exceeded limit to make room for the new one.
+	SubscriptionOverflowEvictOldest
+)
+
+// SubscriptionLimits bounds the number of simultaneous event streams
+// (tasks/sendSubscribe and tasks/resubscribe) open for a task.
+// Zero values mean no limit.
+type SubscriptionLimits struct {
+	// MaxPerTask is the maximum number of streams open for a single task.
+	MaxPerTask int
+	// MaxPerCaller is the maximum number of streams a single caller may have open
+	// for a single task. Callers that cannot be identified are not subject to it.
+	MaxPerCaller int
+	// Overflow selects the behavior when a limit is exceeded. Defaults to rejecting.
+	Overflow SubscriptionOverflowPolicy
+	// CallerFunc identifies the caller of a request.
+	// Defaults to CallerFromContext.
+	CallerFunc func(ctx context.Context) string
+}
+
+// SubscriptionStats holds subscription counters, suitable for exporting as metrics.
+type SubscriptionStats struct {
+	// Active is the number of currently open subscriptions.
+	Active int64
+	// Accepted is the total number of subscriptions accepted.
+	Accepted int64
+	// Rejected is the total number of subscriptions rejected because of a limit.
+	Rejected int64
+	// Evicted is the total number of subscriptions closed to make room for newer ones.
+	Evicted int64
+}
+
+// CallerFromContext returns the ID of the authenticated user stored in ctx by
+// auth.Middleware, or an empty string if the request is not authenticated.
+func CallerFromContext(ctx context.Context) string {
+	if user, ok := ctx.Value(auth.AuthUserKey).(*auth.User); ok && user != nil {
+		return user.ID
+	}
+	return ""
+}
+
+// subscription is a single stream tracked by a SubscriptionLimiter.
+type subscription struct {
+	ch     chan<- protocol.TaskEvent
+	caller string
+}
+
+// SubscriptionLimiter enforces SubscriptionLimits and collects SubscriptionStats.
+// Task managers call Acquire when adding a subscriber and Release when removing it.
+// It is safe for concurrent use.
+type SubscriptionLimiter struct {
+	limits SubscriptionLimits
+
+	mu   sync.Mutex
+	subs map[string][]subscription // Per task, oldest first.
+
+	active   atomic.Int64
+	accepted atomic.Int64
+	rejected atomic.Int64
+	evicted  atomic.Int64
+}
+
+// NewSubscriptionLimiter creates a limiter enforcing the given limits.
+func NewSubscriptionLimiter(limits SubscriptionLimits) *SubscriptionLimiter {
+	if limits.CallerFunc == nil {
+		limits.CallerFunc = CallerFromContext
+	}
+	return &SubscriptionLimiter{
+		limits: limits,
+		subs:   make(map[string][]subscription),
+	}
+}
+
+// Acquire registers ch as a subscription to taskID on behalf of the caller of ctx.
+// If a limit is exceeded it either returns ErrSubscriptionLimitExceeded or,
+// with SubscriptionOverflowEvictOldest, unregisters and returns the evicted channels.
+// The caller is responsible for detaching and closing evicted channels.
+func (l *SubscriptionLimiter) Acquire(
+	ctx context.Context,
+	taskID string,
+	ch chan<- protocol.TaskEvent,
+) ([]chan<- protocol.TaskEvent, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	caller := l.limits.CallerFunc(ctx)
+	subs := l.subs[taskID]
+	var evicted []chan<- protocol.TaskEvent
+	if caller != "" && l.limits.MaxPerCaller > 0 {
+		own := 0
+		for _, sub := range subs {
+			if sub.caller == caller {
+				own++
+			}
+		}
+		if own >= l.limits.MaxPerCaller {
+			if l.limits.Overflow != SubscriptionOverflowEvictOldest {
+				l.rejected.Add(1)
+				return nil, ErrSubscriptionLimitExceeded(taskID, "caller", l.limits.MaxPerCaller)
+			}
+			subs, evicted = evictOldest(subs, own-l.limits.MaxPerCaller+1, caller, evicted)
+		}
+	}
+	if l.limits.MaxPerTask > 0 && len(subs) >= l.limits.MaxPerTask {
+		if l.limits.Overflow != SubscriptionOverflowEvictOldest {
+			l.rejected.Add(1)
+			return nil, ErrSubscriptionLimitExceeded(taskID, "task", l.limits.MaxPerTask)
+		}
+		subs, evicted = evictOldest(subs, len(subs)-l.limits.MaxPerTask+1, "", evicted)
+	}
+	l.subs[taskID] = append(subs, subscription{ch: ch, caller: caller})
+	l.accepted.Add(1)
+	l.active.Add(int64(1 - len(evicted)))
+	l.evicted.Add(int64(len(evicted)))
+	return evicted, nil
+}
+
+// SetLimits replaces the enforced limits. Subscriptions over the new limits
+// are kept; the limits apply from the next Acquire.
+func (l *SubscriptionLimiter) SetLimits(limits SubscriptionLimits) {
+	if limits.CallerFunc == nil {
+		limits.CallerFunc = CallerFromContext
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.limits = limits
+}
+
+// Release unregisters ch from taskID. It reports whether ch was registered,
+// which is false if it had already been released or evicted.
+func (l *SubscriptionLimiter) Release(taskID string, ch chan<- protocol.TaskEvent) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	subs := l.subs[taskID]
+	for i, sub := range subs {
+		if sub.ch != ch {
+			continue
+		}
+		subs = append(subs[:i], subs[i+1:]...)
+		if len(subs) == 0 {
+			delete(l.subs, taskID)
+		} else {
+			l.subs[taskID] = subs
+		}
+		l.active.Add(-1)
+		return true
+	}
+	return false
+}
+
+// Stats returns a snapshot of the subscription counters.
+func (l *SubscriptionLimiter) Stats() SubscriptionStats {
+	return SubscriptionStats{
+		Active:   l.active.Load(),
+		Accepted: l.accepted.Load(),
+		Rejected: l.rejected.Load(),
+		Evicted:  l.evicted.Load(),
+	}
+}
+
+// evictOldest removes the n oldest subscriptions, restricted to the given caller
+// if it is not empty, and appends their channels to evicted.
+func evictOldest(
+	subs []subscription,
+	n int,
+	caller string,
+	evicted []chan<- protocol.TaskEvent,
+) ([]subscription, []chan<- protocol.TaskEvent) {
+	kept := make([]subscription, 0, len(subs))
+	for _, sub := range subs {
+		if n > 0 && (caller == "" || sub.caller == caller) {
+			evicted = append(evicted, sub.ch)
+			n--
+			continue
+		}
+		kept = append(kept, sub)
+	}
+	return kept, evicted
+}
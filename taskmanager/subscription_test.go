@@ -0,0 +1,158 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/auth"
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+func callerContext(id string) context.Context {
+	return context.WithValue(context.Background(), auth.AuthUserKey, &auth.User{ID: id})
+}
+
+func TestSubscriptionLimiter_Reject(t *testing.T) {
+	l := NewSubscriptionLimiter(SubscriptionLimits{MaxPerTask: 3, MaxPerCaller: 1})
+	a1, a2 := make(chan protocol.TaskEvent), make(chan protocol.TaskEvent)
+	b1, c1 := make(chan protocol.TaskEvent), make(chan protocol.TaskEvent)
+	anon1, anon2 := make(chan protocol.TaskEvent), make(chan protocol.TaskEvent)
+
+	_, err := l.Acquire(callerContext("alice"), "t1", a1)
+	require.NoError(t, err)
+	_, err = l.Acquire(callerContext("alice"), "t1", a2)
+	require.Error(t, err, "second stream from the same caller exceeds MaxPerCaller")
+	assert.Equal(t, ErrCodeSubscriptionLimit, err.(*jsonrpc.Error).Code)
+
+	_, err = l.Acquire(callerContext("alice"), "t2", a2)
+	assert.NoError(t, err, "limits apply per task")
+
+	_, err = l.Acquire(callerContext("bob"), "t1", b1)
+	require.NoError(t, err)
+	_, err = l.Acquire(context.Background(), "t1", anon1)
+	require.NoError(t, err, "anonymous callers are not subject to MaxPerCaller")
+	_, err = l.Acquire(context.Background(), "t1", anon2)
+	require.Error(t, err, "fourth stream exceeds MaxPerTask")
+
+	assert.True(t, l.Release("t1", b1))
+	assert.False(t, l.Release("t1", b1), "releasing twice reports false")
+	_, err = l.Acquire(callerContext("carol"), "t1", c1)
+	assert.NoError(t, err)
+
+	assert.Equal(t, SubscriptionStats{Active: 4, Accepted: 5, Rejected: 2}, l.Stats())
+}
+
+func TestSubscriptionLimiter_EvictOldest(t *testing.T) {
+	l := NewSubscriptionLimiter(SubscriptionLimits{
+		MaxPerTask:   2,
+		MaxPerCaller: 1,
+		Overflow:     SubscriptionOverflowEvictOldest,
+	})
+	a1, a2 := make(chan protocol.TaskEvent), make(chan protocol.TaskEvent)
+	b1, c1 := make(chan protocol.TaskEvent), make(chan protocol.TaskEvent)
+
+	_, err := l.Acquire(callerContext("alice"), "t1", a1)
+	require.NoError(t, err)
+	evicted, err := l.Acquire(callerContext("alice"), "t1", a2)
+	require.NoError(t, err)
+	assert.Equal(t, []chan<- protocol.TaskEvent{a1}, evicted, "caller's own oldest stream is evicted")
+
+	_, err = l.Acquire(callerContext("bob"), "t1", b1)
+	require.NoError(t, err)
+	evicted, err = l.Acquire(callerContext("carol"), "t1", c1)
+	require.NoError(t, err)
+	assert.Equal(t, []chan<- protocol.TaskEvent{a2}, evicted, "task's oldest stream is evicted")
+	assert.False(t, l.Release("t1", a2), "evicted streams are already released")
+
+	assert.Equal(t, SubscriptionStats{Active: 2, Accepted: 4, Evicted: 2}, l.Stats())
+}
+
+func TestMemoryTaskManager_SubscriptionLimits(t *testing.T) {
+	processor := &mockProcessor{
+		processFunc: func(ctx context.Context, taskID string, msg protocol.Message, handle TaskHandle) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}
+	params := protocol.SendTaskParams{
+		ID:      "limited-task",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")}),
+	}
+
+	t.Run("reject", func(t *testing.T) {
+		tm, err := NewMemoryTaskManager(processor, WithSubscriptionLimits(SubscriptionLimits{MaxPerTask: 1}))
+		require.NoError(t, err)
+		_, err = tm.OnSendTaskSubscribe(context.Background(), params)
+		require.NoError(t, err)
+		_, err = tm.OnResubscribe(context.Background(), protocol.TaskIDParams{ID: params.ID})
+		require.Error(t, err)
+		assert.Equal(t, ErrCodeSubscriptionLimit, err.(*jsonrpc.Error).Code)
+		assert.Equal(t, int64(1), tm.SubscriptionStats().Rejected)
+		_, err = tm.OnCancelTask(context.Background(), protocol.TaskIDParams{ID: params.ID})
+		require.NoError(t, err)
+	})
+
+	t.Run("evict oldest", func(t *testing.T) {
+		tm, err := NewMemoryTaskManager(processor, WithSubscriptionLimits(SubscriptionLimits{
+			MaxPerTask: 1,
+			Overflow:   SubscriptionOverflowEvictOldest,
+		}))
+		require.NoError(t, err)
+		first, err := tm.OnSendTaskSubscribe(context.Background(), params)
+		require.NoError(t, err)
+		second, err := tm.OnResubscribe(context.Background(), protocol.TaskIDParams{ID: params.ID})
+		require.NoError(t, err)
+
+		closed := make(chan struct{})
+		go func() {
+			for range first {
+			}
+			close(closed)
+		}()
+		select {
+		case <-closed:
+		case <-time.After(time.Second):
+			t.Fatal("evicted stream was not closed")
+		}
+		event := <-second
+		assert.Equal(t, protocol.TaskStateWorking, event.(protocol.TaskStatusUpdateEvent).Status.State)
+		assert.Equal(t, int64(1), tm.SubscriptionStats().Evicted)
+		_, err = tm.OnCancelTask(context.Background(), protocol.TaskIDParams{ID: params.ID})
+		require.NoError(t, err)
+	})
+	t.Run("release", func(t *testing.T) {
+		tm, err := NewMemoryTaskManager(processor, WithSubscriptionLimits(SubscriptionLimits{MaxPerTask: 1}))
+		require.NoError(t, err)
+		ctx, cancel := context.WithCancel(context.Background())
+		_, err = tm.OnSendTaskSubscribe(ctx, params)
+		require.NoError(t, err)
+		// The slot of a disconnected stream is released.
+		cancel()
+		require.Eventually(t, func() bool {
+			return tm.SubscriptionStats().Active == 0
+		}, time.Second, 5*time.Millisecond)
+		events, err := tm.OnResubscribe(context.Background(), protocol.TaskIDParams{ID: params.ID})
+		require.NoError(t, err)
+
+		// So is the slot of a stream whose task ended, which is closed.
+		_, err = tm.OnCancelTask(context.Background(), protocol.TaskIDParams{ID: params.ID})
+		require.NoError(t, err)
+		var last protocol.TaskEvent
+		for event := range events {
+			last = event
+		}
+		assert.True(t, last.IsFinal())
+		assert.Equal(t, int64(0), tm.SubscriptionStats().Active)
+	})
+}
@@ -7,6 +7,8 @@
 package taskmanager
 
 import (
+	"context"
+
 	"trpc.group/trpc-go/trpc-a2a-go/protocol"
 )
 
@@ -16,16 +18,32 @@ import (
 type memoryTaskHandle struct {
 	taskID  string
 	manager *MemoryTaskManager
+	// principal is charged for the artifacts of the task, if usage is accounted.
+	principal string
 }
 
-// UpdateStatus implements TaskHandle.
+// UpdateStatus implements TaskHandle. Transitions not allowed by the state
+// machine of the manager are rejected.
 func (h *memoryTaskHandle) UpdateStatus(state protocol.TaskState, msg *protocol.Message) error {
-	return h.manager.UpdateTaskStatus(h.taskID, state, msg)
+	return h.manager.transitionTask(context.Background(), h.taskID, protocol.TaskStatus{State: state, Message: msg}, nil, true)
+}
+
+// UpdateStatusWithMetadata implements StatusMetadataUpdater.
+func (h *memoryTaskHandle) UpdateStatusWithMetadata(
+	state protocol.TaskState,
+	msg *protocol.Message,
+	metadata map[string]interface{},
+) error {
+	return h.manager.transitionTask(context.Background(), h.taskID, protocol.TaskStatus{State: state, Message: msg}, metadata, true)
 }
 
 // AddArtifact implements TaskHandle.
 func (h *memoryTaskHandle) AddArtifact(artifact protocol.Artifact) error {
-	return h.manager.AddArtifact(h.taskID, artifact)
+	if err := h.manager.AddArtifact(h.taskID, artifact); err != nil {
+		return err
+	}
+	h.manager.quotas.RecordArtifact(h.principal, artifact)
+	return nil
 }
 
 // IsStreamingRequest checks if this task was initiated with a streaming request (OnSendTaskSubscribe).
@@ -44,3 +62,9 @@ func (h *memoryTaskHandle) IsStreamingRequest() bool {
 func isFinalState(state protocol.TaskState) bool {
 	return state == protocol.TaskStateCompleted || state == protocol.TaskStateFailed || state == protocol.TaskStateCanceled
 }
+
+// taskEnded reports whether event is the status update ending its task.
+func taskEnded(event protocol.TaskEvent) bool {
+	status, ok := event.(protocol.TaskStatusUpdateEvent)
+	return ok && isFinalState(status.Status.State)
+}
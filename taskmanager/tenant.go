@@ -0,0 +1,611 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/metadata"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// tenantSeparator separates the tenant from the task ID in namespaced IDs.
+const tenantSeparator = "/"
+
+// TenantResolver returns the tenant of the request of ctx, or an empty string
+// if it has none.
+type TenantResolver func(ctx context.Context) (string, error)
+
+// TenantFromCaller resolves the tenant as the authenticated user of the
+// request, see CallerFromContext.
+func TenantFromCaller(ctx context.Context) (string, error) {
+	return CallerFromContext(ctx), nil
+}
+
+// TenantFromMetadata returns a resolver reading the tenant from the incoming
+// metadata key, see the metadata package. Clients can set any metadata, so it
+// should only be used behind a gateway that sets the key itself.
+func TenantFromMetadata(key string) TenantResolver {
+	return func(ctx context.Context) (string, error) {
+		values := metadata.ValueFromIncomingContext(ctx, key)
+		if len(values) == 0 {
+			return "", nil
+		}
+		return values[0], nil
+	}
+}
+
+// tenantKey is the context key of the tenant of a request.
+type tenantKey struct{}
+
+// ContextWithTenant returns a context for requests of tenant. A
+// TenantTaskManager uses it instead of its resolver, and sets it on the
+// context it passes to the task manager it wraps, so processors can tell the
+// tenant of their tasks.
+func ContextWithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant set by ContextWithTenant.
+func TenantFromContext(ctx context.Context) (string, bool) {
+	tenant, ok := ctx.Value(tenantKey{}).(string)
+	return tenant, ok && tenant != ""
+}
+
+// TenantConfig configures a TenantTaskManager.
+type TenantConfig struct {
+	// Resolver determines the tenant of requests. Defaults to TenantFromCaller.
+	// Requests without tenant are rejected with ErrTenantRequired.
+	Resolver TenantResolver
+	// MaxActiveTasks, if positive, bounds the number of tasks of a tenant that
+	// are not in a final state. New tasks beyond it are rejected with
+	// ErrTenantQuotaExceeded. Tasks stop counting once they end, as seen
+	// through the wrapped task manager if it implements TaskWatcher, and
+	// otherwise when a request sees them ended or a tenant reaches the bound.
+	MaxActiveTasks int
+}
+
+// TenantTaskManager hosts the tasks of several tenants on a single
+// TaskManager. The tenant of each request namespaces the task IDs it refers
+// to, so tenants only see their own tasks, push notification configurations
+// and task graphs, and limits keyed by task ID apply per tenant. The wrapped
+// task manager, and its processor, see task IDs prefixed with the tenant and a
+// slash; tenant IDs cannot contain slashes.
+//
+// It implements TaskPlanner, TaskLister, TaskGraphRunner and
+// EventAcknowledger by delegating to the wrapped task manager when it does;
+// other optional interfaces of the wrapped task manager are not exposed.
+type TenantTaskManager struct {
+	tm  TaskManager
+	cfg TenantConfig
+
+	mu     sync.Mutex
+	active map[string]map[string]struct{} // Active task IDs per tenant.
+	// stopWatch stops watching the tasks of the wrapped task manager, which
+	// happens while tasks are active. Nil when not watching.
+	stopWatch context.CancelFunc
+}
+
+// NewTenantTaskManager wraps tm to isolate the tasks of tenants.
+func NewTenantTaskManager(tm TaskManager, cfg TenantConfig) *TenantTaskManager {
+	if cfg.Resolver == nil {
+		cfg.Resolver = TenantFromCaller
+	}
+	return &TenantTaskManager{tm: tm, cfg: cfg, active: make(map[string]map[string]struct{})}
+}
+
+// ActiveTasks returns the number of active tasks of tenant.
+func (t *TenantTaskManager) ActiveTasks(tenant string) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.active[tenant])
+}
+
+// tenant resolves the tenant of ctx and returns ctx carrying it.
+func (t *TenantTaskManager) tenant(ctx context.Context) (context.Context, string, error) {
+	tenant, ok := TenantFromContext(ctx)
+	if !ok {
+		var err error
+		if tenant, err = t.cfg.Resolver(ctx); err != nil {
+			return nil, "", ErrTenantRequired(fmt.Sprintf("failed to resolve the tenant: %v", err))
+		}
+	}
+	if tenant == "" {
+		return nil, "", ErrTenantRequired("the request has no tenant")
+	}
+	if strings.Contains(tenant, tenantSeparator) {
+		return nil, "", ErrTenantRequired(fmt.Sprintf("tenant '%s' must not contain '%s'", tenant, tenantSeparator))
+	}
+	return ContextWithTenant(ctx, tenant), tenant, nil
+}
+
+// namespaceID returns the ID of the wrapped task manager for the task of tenant.
+func namespaceID(tenant, id string) string {
+	return tenant + tenantSeparator + id
+}
+
+// stripNamespace returns the ID seen by tenant for the namespaced ID.
+func stripNamespace(tenant, id string) string {
+	return strings.TrimPrefix(id, tenant+tenantSeparator)
+}
+
+// stripTask returns a copy of task with the ID seen by tenant.
+func stripTask(tenant string, task *protocol.Task) *protocol.Task {
+	if task == nil {
+		return nil
+	}
+	stripped := *task
+	stripped.ID = stripNamespace(tenant, task.ID)
+	return &stripped
+}
+
+// stripError rewrites the namespaced ID in the data of JSON-RPC errors, such
+// as ErrTaskNotFound, so tenants do not see it.
+func stripError(tenant string, err error) error {
+	rpcErr, ok := err.(*jsonrpc.Error)
+	if !ok {
+		return err
+	}
+	data, ok := rpcErr.Data.(string)
+	if !ok {
+		return err
+	}
+	stripped := *rpcErr
+	stripped.Data = strings.ReplaceAll(data, tenant+tenantSeparator, "")
+	return &stripped
+}
+
+// acquire marks the task active, failing if the tenant has too many active
+// tasks. It returns whether the task was not active already. Before
+// rejecting the task, it releases the active tasks of the tenant that ended
+// unnoticed.
+func (t *TenantTaskManager) acquire(ctx context.Context, tenant, taskID string) (bool, error) {
+	acquired, full := t.tryAcquire(tenant, taskID)
+	if full {
+		t.releaseEndedTasks(ctx, tenant)
+		acquired, full = t.tryAcquire(tenant, taskID)
+	}
+	if full {
+		log.Infof("Rejecting task %s of tenant %s: %d tasks active", taskID, tenant, t.cfg.MaxActiveTasks)
+		return false, ErrTenantQuotaExceeded(tenant, t.cfg.MaxActiveTasks)
+	}
+	return acquired, nil
+}
+
+// tryAcquire marks the task active unless the tenant has too many active
+// tasks, in which case full is true.
+func (t *TenantTaskManager) tryAcquire(tenant, taskID string) (acquired, full bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	tasks := t.active[tenant]
+	if _, exists := tasks[taskID]; exists {
+		return false, false
+	}
+	if t.cfg.MaxActiveTasks > 0 && len(tasks) >= t.cfg.MaxActiveTasks {
+		return false, true
+	}
+	if tasks == nil {
+		tasks = make(map[string]struct{})
+		t.active[tenant] = tasks
+	}
+	tasks[taskID] = struct{}{}
+	t.startWatch()
+	return true, false
+}
+
+// release marks the task inactive.
+func (t *TenantTaskManager) release(tenant, taskID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.active[tenant], taskID)
+	if len(t.active[tenant]) == 0 {
+		delete(t.active, tenant)
+	}
+	if len(t.active) == 0 && t.stopWatch != nil {
+		t.stopWatch()
+		t.stopWatch = nil
+	}
+}
+
+// releaseEndedTasks releases the active tasks of tenant that ended or no
+// longer exist in the wrapped task manager.
+func (t *TenantTaskManager) releaseEndedTasks(ctx context.Context, tenant string) {
+	t.mu.Lock()
+	taskIDs := make([]string, 0, len(t.active[tenant]))
+	for taskID := range t.active[tenant] {
+		taskIDs = append(taskIDs, taskID)
+	}
+	t.mu.Unlock()
+	historyLength := 0
+	for _, taskID := range taskIDs {
+		task, err := t.tm.OnGetTask(ctx, protocol.TaskQueryParams{
+			ID:            namespaceID(tenant, taskID),
+			HistoryLength: &historyLength,
+		})
+		switch {
+		case IsTaskNotFound(err):
+			t.release(tenant, taskID)
+		case err == nil:
+			t.observe(tenant, stripTask(tenant, task))
+		}
+	}
+}
+
+// startWatch watches the tasks of the wrapped task manager, if it implements
+// TaskWatcher and they are not watched already, to release the active tasks
+// as soon as they end, whoever ends them. The caller must hold mu.
+func (t *TenantTaskManager) startWatch() {
+	watcher, ok := t.tm.(TaskWatcher)
+	if !ok || t.stopWatch != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	changes, err := watcher.WatchTasks(ctx)
+	if err != nil {
+		cancel()
+		log.Warnf("Failed to watch the tasks of tenants: %v", err)
+		return
+	}
+	t.stopWatch = cancel
+	go t.watch(ctx, watcher, changes)
+}
+
+// watch releases the active tasks ended or deleted according to changes,
+// watching again if the watcher falls behind, until ctx is done. Tasks whose
+// end is missed meanwhile are released once their tenant reaches its bound.
+func (t *TenantTaskManager) watch(ctx context.Context, watcher TaskWatcher, changes <-chan TaskChange) {
+	for {
+		for change := range changes {
+			tenant, taskID, ok := strings.Cut(change.TaskID, tenantSeparator)
+			if !ok {
+				continue
+			}
+			if change.Type == TaskDeleted || (change.Task != nil && isFinalState(change.Task.Status.State)) {
+				t.release(tenant, taskID)
+			}
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		var err error
+		if changes, err = watcher.WatchTasks(ctx); err != nil {
+			log.Warnf("Failed to watch the tasks of tenants again: %v", err)
+			return
+		}
+	}
+}
+
+// observe releases the task if it is in a final state.
+func (t *TenantTaskManager) observe(tenant string, task *protocol.Task) {
+	if task != nil && isFinalState(task.Status.State) {
+		t.release(tenant, task.ID)
+	}
+}
+
+// OnSendTask implements TaskManager.
+func (t *TenantTaskManager) OnSendTask(ctx context.Context, params protocol.SendTaskParams) (*protocol.Task, error) {
+	ctx, tenant, err := t.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	taskID := params.ID
+	acquired, err := t.acquire(ctx, tenant, taskID)
+	if err != nil {
+		return nil, err
+	}
+	params.ID = namespaceID(tenant, taskID)
+	task, err := t.tm.OnSendTask(ctx, params)
+	task = stripTask(tenant, task)
+	if task == nil && acquired {
+		t.release(tenant, taskID)
+	}
+	t.observe(tenant, task)
+	return task, stripError(tenant, err)
+}
+
+// OnSendTaskSubscribe implements TaskManager.
+func (t *TenantTaskManager) OnSendTaskSubscribe(
+	ctx context.Context,
+	params protocol.SendTaskParams,
+) (<-chan protocol.TaskEvent, error) {
+	ctx, tenant, err := t.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	taskID := params.ID
+	acquired, err := t.acquire(ctx, tenant, taskID)
+	if err != nil {
+		return nil, err
+	}
+	params.ID = namespaceID(tenant, taskID)
+	events, err := t.tm.OnSendTaskSubscribe(ctx, params)
+	if err != nil {
+		if acquired {
+			t.release(tenant, taskID)
+		}
+		return nil, stripError(tenant, err)
+	}
+	return t.relay(ctx, tenant, taskID, events), nil
+}
+
+// OnGetTask implements TaskManager.
+func (t *TenantTaskManager) OnGetTask(ctx context.Context, params protocol.TaskQueryParams) (*protocol.Task, error) {
+	ctx, tenant, err := t.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	params.ID = namespaceID(tenant, params.ID)
+	task, err := t.tm.OnGetTask(ctx, params)
+	if err != nil {
+		return nil, stripError(tenant, err)
+	}
+	task = stripTask(tenant, task)
+	t.observe(tenant, task)
+	return task, nil
+}
+
+// OnCancelTask implements TaskManager.
+func (t *TenantTaskManager) OnCancelTask(ctx context.Context, params protocol.TaskIDParams) (*protocol.Task, error) {
+	ctx, tenant, err := t.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	params.ID = namespaceID(tenant, params.ID)
+	task, err := t.tm.OnCancelTask(ctx, params)
+	if err != nil {
+		return nil, stripError(tenant, err)
+	}
+	task = stripTask(tenant, task)
+	t.observe(tenant, task)
+	return task, nil
+}
+
+// OnPushNotificationSet implements TaskManager.
+func (t *TenantTaskManager) OnPushNotificationSet(
+	ctx context.Context,
+	params protocol.TaskPushNotificationConfig,
+) (*protocol.TaskPushNotificationConfig, error) {
+	ctx, tenant, err := t.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	params.ID = namespaceID(tenant, params.ID)
+	config, err := t.tm.OnPushNotificationSet(ctx, params)
+	if err != nil {
+		return nil, stripError(tenant, err)
+	}
+	stripped := *config
+	stripped.ID = stripNamespace(tenant, config.ID)
+	return &stripped, nil
+}
+
+// OnPushNotificationGet implements TaskManager.
+func (t *TenantTaskManager) OnPushNotificationGet(
+	ctx context.Context,
+	params protocol.TaskIDParams,
+) (*protocol.TaskPushNotificationConfig, error) {
+	ctx, tenant, err := t.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	params.ID = namespaceID(tenant, params.ID)
+	config, err := t.tm.OnPushNotificationGet(ctx, params)
+	if err != nil {
+		return nil, stripError(tenant, err)
+	}
+	stripped := *config
+	stripped.ID = stripNamespace(tenant, config.ID)
+	return &stripped, nil
+}
+
+// OnResubscribe implements TaskManager.
+func (t *TenantTaskManager) OnResubscribe(
+	ctx context.Context,
+	params protocol.TaskIDParams,
+) (<-chan protocol.TaskEvent, error) {
+	ctx, tenant, err := t.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	taskID := params.ID
+	params.ID = namespaceID(tenant, taskID)
+	events, err := t.tm.OnResubscribe(ctx, params)
+	if err != nil {
+		return nil, stripError(tenant, err)
+	}
+	return t.relay(ctx, tenant, taskID, events), nil
+}
+
+// relay forwards the events of the task with the ID seen by tenant, releasing
+// the task on its final event. It stops once ctx is done, when the wrapped
+// task manager stops delivering events to the stream, releasing the task if
+// it ended meanwhile.
+func (t *TenantTaskManager) relay(
+	ctx context.Context,
+	tenant, taskID string,
+	events <-chan protocol.TaskEvent,
+) <-chan protocol.TaskEvent {
+	out := make(chan protocol.TaskEvent, cap(events))
+	go func() {
+		defer close(out)
+		for {
+			var event protocol.TaskEvent
+			select {
+			case e, ok := <-events:
+				if !ok {
+					return
+				}
+				event = e
+			case <-ctx.Done():
+				t.releaseEnded(ctx, tenant, taskID)
+				return
+			}
+			switch e := event.(type) {
+			case protocol.TaskStatusUpdateEvent:
+				e.ID = taskID
+				event = e
+			case protocol.TaskArtifactUpdateEvent:
+				e.ID = taskID
+				event = e
+			}
+			if event.IsFinal() {
+				t.release(tenant, taskID)
+			}
+			select {
+			case out <- event:
+			case <-ctx.Done():
+				t.releaseEnded(ctx, tenant, taskID)
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// releaseEnded releases the task of a stream ended with ctx if the task is
+// in a final state.
+func (t *TenantTaskManager) releaseEnded(ctx context.Context, tenant, taskID string) {
+	task, err := t.tm.OnGetTask(context.WithoutCancel(ctx), protocol.TaskQueryParams{ID: namespaceID(tenant, taskID)})
+	if err == nil {
+		task.ID = taskID
+		t.observe(tenant, task)
+	}
+}
+
+// PlanTask implements TaskPlanner.
+func (t *TenantTaskManager) PlanTask(ctx context.Context, params protocol.SendTaskParams) (*protocol.TaskPlan, error) {
+	ctx, tenant, err := t.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	planner, ok := t.tm.(TaskPlanner)
+	if !ok {
+		return &protocol.TaskPlan{}, nil
+	}
+	params.ID = namespaceID(tenant, params.ID)
+	plan, err := planner.PlanTask(ctx, params)
+	return plan, stripError(tenant, err)
+}
+
+// ListTasks implements TaskLister, returning the tasks of the tenant of ctx.
+func (t *TenantTaskManager) ListTasks(ctx context.Context, filter TaskFilter) ([]protocol.Task, error) {
+	ctx, tenant, err := t.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	lister, ok := t.tm.(TaskLister)
+	if !ok {
+		return nil, fmt.Errorf("task manager %T cannot list tasks", t.tm)
+	}
+	limit := filter.Limit
+	filter.Limit = 0
+	tasks, err := lister.ListTasks(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	var owned []protocol.Task
+	for _, task := range tasks {
+		if !strings.HasPrefix(task.ID, tenant+tenantSeparator) {
+			continue
+		}
+		task.ID = stripNamespace(tenant, task.ID)
+		owned = append(owned, task)
+		if limit > 0 && len(owned) == limit {
+			break
+		}
+	}
+	return owned, nil
+}
+
+// OnSendTaskGraph implements TaskGraphRunner.
+func (t *TenantTaskManager) OnSendTaskGraph(
+	ctx context.Context,
+	params protocol.SendTaskGraphParams,
+) (*protocol.TaskGraph, error) {
+	ctx, tenant, err := t.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	runner, ok := t.tm.(TaskGraphRunner)
+	if !ok {
+		return nil, jsonrpc.ErrMethodNotFound("task graphs are not supported by this agent")
+	}
+	params.ID = namespaceID(tenant, params.ID)
+	tasks := make([]protocol.TaskGraphNodeParams, 0, len(params.Tasks))
+	for _, node := range params.Tasks {
+		node.Task.ID = namespaceID(tenant, node.Task.ID)
+		deps := make([]string, 0, len(node.DependsOn))
+		for _, dep := range node.DependsOn {
+			deps = append(deps, namespaceID(tenant, dep))
+		}
+		node.DependsOn = deps
+		tasks = append(tasks, node)
+	}
+	params.Tasks = tasks
+	graph, err := runner.OnSendTaskGraph(ctx, params)
+	if err != nil {
+		return nil, stripError(tenant, err)
+	}
+	return stripGraph(tenant, graph), nil
+}
+
+// OnGetTaskGraph implements TaskGraphRunner.
+func (t *TenantTaskManager) OnGetTaskGraph(
+	ctx context.Context,
+	params protocol.TaskIDParams,
+) (*protocol.TaskGraph, error) {
+	ctx, tenant, err := t.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	runner, ok := t.tm.(TaskGraphRunner)
+	if !ok {
+		return nil, jsonrpc.ErrMethodNotFound("task graphs are not supported by this agent")
+	}
+	params.ID = namespaceID(tenant, params.ID)
+	graph, err := runner.OnGetTaskGraph(ctx, params)
+	if err != nil {
+		return nil, stripError(tenant, err)
+	}
+	return stripGraph(tenant, graph), nil
+}
+
+// stripGraph returns a copy of graph with the IDs seen by tenant.
+func stripGraph(tenant string, graph *protocol.TaskGraph) *protocol.TaskGraph {
+	stripped := *graph
+	stripped.ID = stripNamespace(tenant, graph.ID)
+	stripped.Nodes = make([]protocol.TaskGraphNode, 0, len(graph.Nodes))
+	for _, node := range graph.Nodes {
+		node.ID = stripNamespace(tenant, node.ID)
+		deps := make([]string, 0, len(node.DependsOn))
+		for _, dep := range node.DependsOn {
+			deps = append(deps, stripNamespace(tenant, dep))
+		}
+		node.DependsOn = deps
+		stripped.Nodes = append(stripped.Nodes, node)
+	}
+	return &stripped
+}
+
+// OnAckEvents implements EventAcknowledger.
+func (t *TenantTaskManager) OnAckEvents(ctx context.Context, ack protocol.AckEventsResult) error {
+	acknowledger, ok := t.tm.(EventAcknowledger)
+	if !ok {
+		return nil
+	}
+	ctx, tenant, err := t.tenant(ctx)
+	if err != nil {
+		return err
+	}
+	ack.ID = namespaceID(tenant, ack.ID)
+	return acknowledger.OnAckEvents(ctx, ack)
+}
@@ -0,0 +1,218 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/metadata"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// tenantParams returns send params for taskID.
+func tenantParams(taskID string) protocol.SendTaskParams {
+	return protocol.SendTaskParams{
+		ID:      taskID,
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")}),
+	}
+}
+
+// requireRPCCode asserts that err is a JSON-RPC error with code.
+func requireRPCCode(t *testing.T, err error, code int) *jsonrpc.Error {
+	t.Helper()
+	var rpcErr *jsonrpc.Error
+	require.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, code, rpcErr.Code)
+	return rpcErr
+}
+
+func TestTenantTaskManager_Isolation(t *testing.T) {
+	processor := &mockProcessor{}
+	memory, err := NewMemoryTaskManager(processor)
+	require.NoError(t, err)
+	tm := NewTenantTaskManager(memory, TenantConfig{})
+	acme := ContextWithTenant(context.Background(), "acme")
+	globex := ContextWithTenant(context.Background(), "globex")
+
+	task, err := tm.OnSendTask(acme, tenantParams("task-1"))
+	require.NoError(t, err)
+	assert.Equal(t, "task-1", task.ID)
+	assert.Equal(t, "acme/task-1", processor.lastTaskID)
+	_, err = tm.OnSendTask(globex, tenantParams("task-1"))
+	require.NoError(t, err)
+	assert.Equal(t, "globex/task-1", processor.lastTaskID)
+
+	_, err = tm.OnSendTask(acme, tenantParams("task-2"))
+	require.NoError(t, err)
+	rpcErr := requireRPCCode(t, func() error {
+		_, err := tm.OnGetTask(globex, protocol.TaskQueryParams{ID: "task-2"})
+		return err
+	}(), ErrCodeTaskNotFound)
+	assert.NotContains(t, rpcErr.Data, "globex/")
+
+	tasks, err := tm.ListTasks(acme, TaskFilter{})
+	require.NoError(t, err)
+	var ids []string
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+	assert.ElementsMatch(t, []string{"task-1", "task-2"}, ids)
+
+	config, err := tm.OnPushNotificationSet(acme, protocol.TaskPushNotificationConfig{
+		ID:                     "task-1",
+		PushNotificationConfig: protocol.PushNotificationConfig{URL: "https://acme.example/hook"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "task-1", config.ID)
+	_, err = tm.OnPushNotificationGet(globex, protocol.TaskIDParams{ID: "task-1"})
+	assert.Error(t, err, "push configurations are per tenant")
+
+	_, err = tm.OnGetTask(context.Background(), protocol.TaskQueryParams{ID: "task-1"})
+	requireRPCCode(t, err, ErrCodeTenantRequired)
+	_, err = tm.OnGetTask(ContextWithTenant(context.Background(), "a/b"), protocol.TaskQueryParams{ID: "task-1"})
+	requireRPCCode(t, err, ErrCodeTenantRequired)
+}
+
+func TestTenantTaskManager_Quota(t *testing.T) {
+	release := make(chan struct{})
+	processor := &mockProcessor{
+		processFunc: func(ctx context.Context, taskID string, msg protocol.Message, handle TaskHandle) error {
+			<-release
+			return handle.UpdateStatus(protocol.TaskStateCompleted, nil)
+		},
+	}
+	memory, err := NewMemoryTaskManager(processor)
+	require.NoError(t, err)
+	tm := NewTenantTaskManager(memory, TenantConfig{
+		Resolver:       TenantFromMetadata("tenant"),
+		MaxActiveTasks: 1,
+	})
+	acme := metadata.NewIncomingContext(context.Background(), metadata.Pairs("tenant", "acme"))
+	globex := metadata.NewIncomingContext(context.Background(), metadata.Pairs("tenant", "globex"))
+
+	events, err := tm.OnSendTaskSubscribe(acme, tenantParams("long"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, tm.ActiveTasks("acme"))
+	_, err = tm.OnSendTaskSubscribe(acme, tenantParams("other"))
+	requireRPCCode(t, err, ErrCodeTenantQuotaExceeded)
+
+	// Quotas are per tenant.
+	globexEvents, err := tm.OnSendTaskSubscribe(globex, tenantParams("long"))
+	require.NoError(t, err)
+
+	close(release)
+	for _, ch := range []<-chan protocol.TaskEvent{events, globexEvents} {
+		var last protocol.TaskEvent
+		for event := range ch {
+			if status, ok := event.(protocol.TaskStatusUpdateEvent); ok {
+				assert.Equal(t, "long", status.ID)
+			}
+			last = event
+			if event.IsFinal() {
+				break
+			}
+		}
+		require.NotNil(t, last)
+		assert.True(t, last.IsFinal())
+	}
+	assert.Equal(t, 0, tm.ActiveTasks("acme"))
+	_, err = tm.OnSendTask(acme, tenantParams("other"))
+	assert.NoError(t, err)
+}
+
+func TestTenantTaskManager_Graph(t *testing.T) {
+	memory, err := NewMemoryTaskManager(&mockProcessor{})
+	require.NoError(t, err)
+	tm := NewTenantTaskManager(memory, TenantConfig{})
+	acme := ContextWithTenant(context.Background(), "acme")
+
+	graph, err := tm.OnSendTaskGraph(acme, protocol.SendTaskGraphParams{
+		ID:    "g",
+		Tasks: []protocol.TaskGraphNodeParams{graphTask("a"), graphTask("b", "a")},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "g", graph.ID)
+	assert.Equal(t, "b", graph.Nodes[1].ID)
+	assert.Equal(t, []string{"a"}, graph.Nodes[1].DependsOn)
+
+	_, err = tm.OnGetTaskGraph(ContextWithTenant(context.Background(), "globex"), protocol.TaskIDParams{ID: "g"})
+	requireRPCCode(t, err, ErrCodeTaskGraphNotFound)
+}
+
+func TestTenantTaskManager_Disconnect(t *testing.T) {
+	memory, err := NewMemoryTaskManager(&mockProcessor{})
+	require.NoError(t, err)
+	tm := NewTenantTaskManager(memory, TenantConfig{})
+	ctx, cancel := context.WithCancel(ContextWithTenant(context.Background(), "acme"))
+	task, err := tm.OnSendTask(ctx, tenantParams("done"))
+	require.NoError(t, err)
+	require.Equal(t, protocol.TaskStateCompleted, task.Status.State)
+
+	// The relay stops with its stream, although the source is never closed.
+	source := make(chan protocol.TaskEvent)
+	tm.tryAcquire("acme", "done")
+	out := tm.relay(ctx, "acme", "done", source)
+	cancel()
+	for range out {
+	}
+	assert.Equal(t, 0, tm.ActiveTasks("acme"), "the ended task is released")
+}
+
+func TestTenantTaskManager_SubscriberGone(t *testing.T) {
+	tests := []struct {
+		name string
+		wrap func(*MemoryTaskManager) TaskManager
+	}{
+		// The watched task manager reports the end of the task.
+		{name: "watched", wrap: func(m *MemoryTaskManager) TaskManager { return m }},
+		// The task is found ended once the tenant reaches its bound.
+		{name: "unwatched", wrap: func(m *MemoryTaskManager) TaskManager { return struct{ TaskManager }{m} }},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			release := make(chan struct{})
+			processor := &mockProcessor{
+				processFunc: func(ctx context.Context, taskID string, msg protocol.Message, handle TaskHandle) error {
+					<-release
+					return handle.UpdateStatus(protocol.TaskStateCompleted, nil)
+				},
+			}
+			memory, err := NewMemoryTaskManager(processor)
+			require.NoError(t, err)
+			tm := NewTenantTaskManager(tc.wrap(memory), TenantConfig{MaxActiveTasks: 1})
+			ctx, cancel := context.WithCancel(ContextWithTenant(context.Background(), "acme"))
+
+			// The subscriber leaves while the task keeps running.
+			events, err := tm.OnSendTaskSubscribe(ctx, tenantParams("long"))
+			require.NoError(t, err)
+			cancel()
+			for range events {
+			}
+			assert.Equal(t, 1, tm.ActiveTasks("acme"), "the running task stays active")
+
+			close(release)
+			acme := ContextWithTenant(context.Background(), "acme")
+			require.Eventually(t, func() bool {
+				task, err := memory.OnGetTask(acme, protocol.TaskQueryParams{ID: namespaceID("acme", "long")})
+				return err == nil && task.Status.State == protocol.TaskStateCompleted
+			}, time.Second, 5*time.Millisecond)
+			if tc.name == "watched" {
+				require.Eventually(t, func() bool { return tm.ActiveTasks("acme") == 0 },
+					time.Second, 5*time.Millisecond)
+			}
+			task, err := tm.OnSendTask(acme, tenantParams("next"))
+			require.NoError(t, err)
+			assert.Equal(t, "next", task.ID)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// EventTransformer rewrites an event of a task before it is sent to the
+// subscribers of the task and to its push notification URL, e.g. to redact,
+// translate or count the tokens of its messages and artifacts. It returns the
+// event to send, or nil to drop it; final events cannot be dropped. The task
+// as stored is not affected.
+//
+// The message and artifact of the event are shared with the stored task, so
+// transformers must return a modified copy rather than modify them in place.
+type EventTransformer func(taskID string, event protocol.TaskEvent) protocol.TaskEvent
+
+// TransformParts returns a transformer applying transform to each part of the
+// status messages and artifacts of the events, on copies of them.
+func TransformParts(transform func(part protocol.Part) protocol.Part) EventTransformer {
+	parts := func(in []protocol.Part) []protocol.Part {
+		if in == nil {
+			return nil
+		}
+		out := make([]protocol.Part, len(in))
+		for i, part := range in {
+			out[i] = transform(part)
+		}
+		return out
+	}
+	return func(taskID string, event protocol.TaskEvent) protocol.TaskEvent {
+		switch e := event.(type) {
+		case protocol.TaskStatusUpdateEvent:
+			if e.Status.Message != nil {
+				message := *e.Status.Message
+				message.Parts = parts(message.Parts)
+				e.Status.Message = &message
+			}
+			return e
+		case protocol.TaskArtifactUpdateEvent:
+			e.Artifact.Parts = parts(e.Artifact.Parts)
+			return e
+		}
+		return event
+	}
+}
+
+// transformEvent runs event through the transformers of the task manager, in
+// order. It returns false if a transformer dropped the event.
+func (m *MemoryTaskManager) transformEvent(taskID string, event protocol.TaskEvent) (protocol.TaskEvent, bool) {
+	for _, transform := range m.transformers {
+		transformed := transform(taskID, event)
+		if transformed == nil {
+			if event.IsFinal() {
+				log.Warnf("Ignoring the drop of the final event of task %s by a transformer", taskID)
+				continue
+			}
+			return nil, false
+		}
+		event = transformed
+	}
+	return event, true
+}
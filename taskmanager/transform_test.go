@@ -0,0 +1,81 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+func TestMemoryTaskManager_EventTransformers(t *testing.T) {
+	processor := &mockProcessor{
+		processFunc: func(ctx context.Context, taskID string, msg protocol.Message, handle TaskHandle) error {
+			if err := handle.UpdateStatus(protocol.TaskStateWorking, nil); err != nil {
+				return err
+			}
+			if err := handle.AddArtifact(protocol.Artifact{
+				Parts: []protocol.Part{protocol.NewTextPart("card 4111-1111")},
+			}); err != nil {
+				return err
+			}
+			reply := protocol.NewMessage(protocol.MessageRoleAgent, []protocol.Part{protocol.NewTextPart("done 4111-1111")})
+			return handle.UpdateStatus(protocol.TaskStateCompleted, &reply)
+		},
+	}
+	redact := TransformParts(func(part protocol.Part) protocol.Part {
+		if text, ok := part.(protocol.TextPart); ok {
+			return protocol.NewTextPart(strings.ReplaceAll(text.Text, "4111-1111", "****"))
+		}
+		return part
+	})
+	dropWorking := func(taskID string, event protocol.TaskEvent) protocol.TaskEvent {
+		if e, ok := event.(protocol.TaskStatusUpdateEvent); ok && e.Status.State == protocol.TaskStateWorking {
+			return nil
+		}
+		return event
+	}
+	dropAll := func(taskID string, event protocol.TaskEvent) protocol.TaskEvent {
+		if event.IsFinal() {
+			return nil
+		}
+		return event
+	}
+	tm, err := NewMemoryTaskManager(processor, WithEventTransformers(redact, dropWorking, dropAll))
+	require.NoError(t, err)
+
+	events, err := tm.OnSendTaskSubscribe(context.Background(), protocol.SendTaskParams{
+		ID:      "redacted",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")}),
+	})
+	require.NoError(t, err)
+	var texts []string
+	for event := range events {
+		switch e := event.(type) {
+		case protocol.TaskStatusUpdateEvent:
+			assert.NotEqual(t, protocol.TaskStateWorking, e.Status.State, "dropped events are not sent")
+			require.NotNil(t, e.Status.Message)
+			texts = append(texts, e.Status.Message.Parts[0].(protocol.TextPart).Text)
+		case protocol.TaskArtifactUpdateEvent:
+			texts = append(texts, e.Artifact.Parts[0].(protocol.TextPart).Text)
+		}
+		if event.IsFinal() {
+			break
+		}
+	}
+	assert.Equal(t, []string{"card ****", "done ****"}, texts, "final events cannot be dropped")
+
+	task, err := tm.OnGetTask(context.Background(), protocol.TaskQueryParams{ID: "redacted"})
+	require.NoError(t, err)
+	assert.Equal(t, "card 4111-1111", task.Artifacts[0].Parts[0].(protocol.TextPart).Text,
+		"the stored task is not transformed")
+}
@@ -0,0 +1,161 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"fmt"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// Tokenizer counts the tokens of a text, typically by adapting the tokenizer of
+// the model an agent fronts (e.g. a tiktoken encoding).
+type Tokenizer interface {
+	CountTokens(text string) (int, error)
+}
+
+// TokenizerFunc adapts a function to the Tokenizer interface.
+type TokenizerFunc func(text string) (int, error)
+
+// CountTokens implements Tokenizer.
+func (f TokenizerFunc) CountTokens(text string) (int, error) {
+	return f(text)
+}
+
+// UsageRecorder is notified of every token usage change, e.g. to export metrics
+// or charge a quota. It is called synchronously while the task is updated, so it
+// must return quickly and must not call back into the task manager.
+type UsageRecorder interface {
+	RecordUsage(taskID string, delta, total protocol.TokenUsage)
+}
+
+// UsageRecorderFunc adapts a function to the UsageRecorder interface.
+type UsageRecorderFunc func(taskID string, delta, total protocol.TokenUsage)
+
+// RecordUsage implements UsageRecorder.
+func (f UsageRecorderFunc) RecordUsage(taskID string, delta, total protocol.TokenUsage) {
+	f(taskID, delta, total)
+}
+
+// TokenAccounting configures token accounting of the text parts of messages and artifacts.
+type TokenAccounting struct {
+	// Tokenizer counts tokens. Accounting is disabled if it is nil.
+	Tokenizer Tokenizer
+	// Recorder, if set, is notified of usage changes.
+	Recorder UsageRecorder
+	// MaxTaskTokens is the total token budget of a task. A message whose input
+	// tokens would take the task over budget is rejected; output is always
+	// recorded since it has already been produced. Zero means no budget.
+	MaxTaskTokens int
+}
+
+// UsageMeter counts the tokens exchanged for tasks and records them as
+// protocol.TokenUsage under protocol.MetadataKeyUsage in the task metadata.
+// It is shared by the task manager implementations and holds no per-task state,
+// so callers serialize the updates of each task.
+type UsageMeter struct {
+	cfg TokenAccounting
+}
+
+// NewUsageMeter creates a usage meter for cfg.
+func NewUsageMeter(cfg TokenAccounting) *UsageMeter {
+	return &UsageMeter{cfg: cfg}
+}
+
+// Enabled reports whether tokens are counted.
+func (u *UsageMeter) Enabled() bool {
+	return u.cfg.Tokenizer != nil
+}
+
+// Count returns the number of tokens in the text parts of parts.
+// It returns zero if accounting is disabled.
+func (u *UsageMeter) Count(parts []protocol.Part) (int, error) {
+	if !u.Enabled() {
+		return 0, nil
+	}
+	total := 0
+	for _, part := range parts {
+		var text string
+		switch p := part.(type) {
+		case protocol.TextPart:
+			text = p.Text
+		case *protocol.TextPart:
+			text = p.Text
+		default:
+			continue
+		}
+		n, err := u.cfg.Tokenizer.CountTokens(text)
+		if err != nil {
+			return 0, fmt.Errorf("failed to count tokens: %w", err)
+		}
+		total += n
+	}
+	return total, nil
+}
+
+// Charge adds delta to the usage recorded in metadata and returns a copy of
+// metadata holding the new total; metadata itself is not modified. If delta has
+// input tokens that would exceed the task budget, ErrTokenBudgetExceeded is
+// returned instead.
+func (u *UsageMeter) Charge(
+	taskID string,
+	metadata map[string]interface{},
+	delta protocol.TokenUsage,
+) (map[string]interface{}, error) {
+	if delta == (protocol.TokenUsage{}) {
+		return metadata, nil
+	}
+	current, _ := protocol.UsageFromMetadata(metadata)
+	total := current.Add(delta)
+	if limit := u.cfg.MaxTaskTokens; limit > 0 && delta.InputTokens > 0 && total.TotalTokens > limit {
+		return nil, ErrTokenBudgetExceeded(taskID, limit, current.TotalTokens, delta.InputTokens)
+	}
+	updated := make(map[string]interface{}, len(metadata)+1)
+	for k, v := range metadata {
+		updated[k] = v
+	}
+	updated[protocol.MetadataKeyUsage] = total
+	if u.cfg.Recorder != nil {
+		u.cfg.Recorder.RecordUsage(taskID, delta, total)
+	}
+	return updated, nil
+}
+
+// Plan returns the token plan of sending parts to the task with taskID and
+// metadata: the tokens the parts would be charged, the tokens already used and
+// the budget. It fails like Charge if the parts would exceed the budget, but
+// records nothing.
+func (u *UsageMeter) Plan(
+	taskID string,
+	metadata map[string]interface{},
+	parts []protocol.Part,
+) (*protocol.TaskPlan, error) {
+	tokens, err := u.Count(parts)
+	if err != nil {
+		return nil, err
+	}
+	current, _ := protocol.UsageFromMetadata(metadata)
+	limit := u.cfg.MaxTaskTokens
+	if limit > 0 && tokens > 0 && current.TotalTokens+tokens > limit {
+		return nil, ErrTokenBudgetExceeded(taskID, limit, current.TotalTokens, tokens)
+	}
+	return &protocol.TaskPlan{
+		InputTokens:   tokens,
+		UsedTokens:    current.TotalTokens,
+		MaxTaskTokens: limit,
+	}, nil
+}
+
+// InputUsage returns the usage of tokens sent to the agent.
+func InputUsage(tokens int) protocol.TokenUsage {
+	return protocol.TokenUsage{InputTokens: tokens, TotalTokens: tokens}
+}
+
+// OutputUsage returns the usage of tokens produced by the agent.
+func OutputUsage(tokens int) protocol.TokenUsage {
+	return protocol.TokenUsage{OutputTokens: tokens, TotalTokens: tokens}
+}
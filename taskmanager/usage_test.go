@@ -0,0 +1,146 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// wordTokenizer counts whitespace separated words as tokens.
+var wordTokenizer = TokenizerFunc(func(text string) (int, error) {
+	return len(strings.Fields(text)), nil
+})
+
+func TestUsageMeter_Charge(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		totals []protocol.TokenUsage
+	)
+	meter := NewUsageMeter(TokenAccounting{
+		Tokenizer: wordTokenizer,
+		Recorder: UsageRecorderFunc(func(taskID string, delta, total protocol.TokenUsage) {
+			mu.Lock()
+			defer mu.Unlock()
+			totals = append(totals, total)
+		}),
+		MaxTaskTokens: 5,
+	})
+
+	tokens, err := meter.Count([]protocol.Part{
+		protocol.NewTextPart("one two three"),
+		protocol.DataPart{Type: protocol.PartTypeData, Data: "not counted"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, tokens)
+
+	original := map[string]interface{}{"k": "v"}
+	md, err := meter.Charge("t1", original, InputUsage(tokens))
+	require.NoError(t, err)
+	assert.NotContains(t, original, protocol.MetadataKeyUsage, "metadata is copied")
+	md, err = meter.Charge("t1", md, OutputUsage(4))
+	require.NoError(t, err, "output is recorded even over budget")
+
+	_, err = meter.Charge("t1", md, InputUsage(1))
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeTokenBudgetExceeded, err.(*jsonrpc.Error).Code)
+
+	usage, ok := protocol.UsageFromMetadata(md)
+	require.True(t, ok)
+	assert.Equal(t, protocol.TokenUsage{InputTokens: 3, OutputTokens: 4, TotalTokens: 7}, usage)
+	assert.Equal(t, []protocol.TokenUsage{
+		{InputTokens: 3, TotalTokens: 3},
+		{InputTokens: 3, OutputTokens: 4, TotalTokens: 7},
+	}, totals)
+}
+
+func TestUsageMeter_Disabled(t *testing.T) {
+	meter := NewUsageMeter(TokenAccounting{})
+	assert.False(t, meter.Enabled())
+	tokens, err := meter.Count([]protocol.Part{protocol.NewTextPart("one two")})
+	require.NoError(t, err)
+	assert.Zero(t, tokens)
+	md := map[string]interface{}{"k": "v"}
+	got, err := meter.Charge("t1", md, InputUsage(tokens))
+	require.NoError(t, err)
+	assert.Equal(t, md, got)
+}
+
+func TestMemoryTaskManager_TokenAccounting(t *testing.T) {
+	processor := &mockProcessor{
+		processFunc: func(ctx context.Context, taskID string, msg protocol.Message, handle TaskHandle) error {
+			return handle.UpdateStatus(protocol.TaskStateInputRequired, &protocol.Message{
+				Role:  protocol.MessageRoleAgent,
+				Parts: []protocol.Part{protocol.NewTextPart("done")},
+			})
+		},
+	}
+	tm, err := NewMemoryTaskManager(processor, WithTokenAccounting(TokenAccounting{
+		Tokenizer:     wordTokenizer,
+		MaxTaskTokens: 6,
+	}))
+	require.NoError(t, err)
+
+	send := func(text string) (*protocol.Task, error) {
+		return tm.OnSendTask(context.Background(), protocol.SendTaskParams{
+			ID: "t1",
+			Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{
+				protocol.NewTextPart(text),
+			}),
+		})
+	}
+
+	task, err := send("hello there")
+	require.NoError(t, err)
+	usage, ok := protocol.UsageFromMetadata(task.Metadata)
+	require.True(t, ok)
+	assert.Equal(t, protocol.TokenUsage{InputTokens: 2, OutputTokens: 1, TotalTokens: 3}, usage)
+
+	_, err = send("this is far too long")
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeTokenBudgetExceeded, err.(*jsonrpc.Error).Code)
+	assert.Equal(t, 1, processor.callCount, "rejected messages are not processed")
+
+	task, err = tm.OnGetTask(context.Background(), protocol.TaskQueryParams{ID: "t1"})
+	require.NoError(t, err)
+	usage, _ = protocol.UsageFromMetadata(task.Metadata)
+	assert.Equal(t, 3, usage.TotalTokens, "rejected messages are not charged")
+}
+
+func TestMemoryTaskManager_PlanTask(t *testing.T) {
+	processor := &mockProcessor{}
+	tm, err := NewMemoryTaskManager(processor, WithTokenAccounting(TokenAccounting{
+		Tokenizer:     wordTokenizer,
+		MaxTaskTokens: 3,
+	}))
+	require.NoError(t, err)
+	params := func(text string) protocol.SendTaskParams {
+		return protocol.SendTaskParams{
+			ID:      "t1",
+			Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart(text)}),
+		}
+	}
+
+	plan, err := tm.PlanTask(context.Background(), params("hello there"))
+	require.NoError(t, err)
+	assert.Equal(t, &protocol.TaskPlan{InputTokens: 2, MaxTaskTokens: 3}, plan)
+	assert.Zero(t, processor.callCount)
+	_, err = tm.OnGetTask(context.Background(), protocol.TaskQueryParams{ID: "t1"})
+	assert.Error(t, err, "planning does not create the task")
+
+	_, err = tm.PlanTask(context.Background(), params("this is too long"))
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeTokenBudgetExceeded, err.(*jsonrpc.Error).Code)
+}
@@ -0,0 +1,60 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// expectedVersionKey is the context key of the version expected by a change
+// of a task.
+type expectedVersionKey struct{}
+
+// ContextWithExpectedVersion returns a context for changing a task only if it
+// is at version, its protocol.Task.Version. The changes of the task made
+// with it, such as OnCancelTask and RequeueTask, fail with
+// ErrTaskVersionConflict if another writer changed the task since it was
+// read at version. Version 0 expects the task not to exist.
+func ContextWithExpectedVersion(ctx context.Context, version uint64) context.Context {
+	return context.WithValue(ctx, expectedVersionKey{}, version)
+}
+
+// ExpectedVersionFromContext returns the version set by
+// ContextWithExpectedVersion.
+func ExpectedVersionFromContext(ctx context.Context) (uint64, bool) {
+	version, ok := ctx.Value(expectedVersionKey{}).(uint64)
+	return version, ok
+}
+
+// withoutExpectedVersion returns ctx without expected version, for the work
+// started by a change of a task that outlives it.
+func withoutExpectedVersion(ctx context.Context) context.Context {
+	if _, ok := ExpectedVersionFromContext(ctx); !ok {
+		return ctx
+	}
+	return context.WithValue(ctx, expectedVersionKey{}, nil)
+}
+
+// CheckTaskVersion returns ErrTaskVersionConflict if ctx expects another
+// version than the version of task, nil for a task that does not exist.
+// Task stores call it within their atomic updates.
+func CheckTaskVersion(ctx context.Context, taskID string, task *protocol.Task) error {
+	expected, ok := ExpectedVersionFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	var actual uint64
+	if task != nil {
+		actual = task.Version
+	}
+	if actual != expected {
+		return ErrTaskVersionConflict(taskID, expected, actual)
+	}
+	return nil
+}
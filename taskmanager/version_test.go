@@ -0,0 +1,134 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+func TestWatchableTaskStore_Versions(t *testing.T) {
+	ctx := context.Background()
+	store := NewWatchableTaskStore(&mapTaskStore{tasks: make(map[string]protocol.Task)})
+
+	task := protocol.NewTask("a", nil)
+	require.NoError(t, store.Put(ctx, task))
+	assert.Equal(t, uint64(1), task.Version)
+	updated, err := store.Update(ctx, "a", func(task *protocol.Task) error {
+		task.Status.State = protocol.TaskStateWorking
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), updated.Version)
+
+	// Changes expecting another version are rejected without effect.
+	_, err = store.Update(ContextWithExpectedVersion(ctx, 1), "a", func(task *protocol.Task) error {
+		task.Status.State = protocol.TaskStateCanceled
+		return nil
+	})
+	var rpcErr *jsonrpc.Error
+	require.True(t, errors.As(err, &rpcErr))
+	assert.Equal(t, ErrCodeTaskVersionConflict, rpcErr.Code)
+	stored, err := store.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, protocol.TaskStateWorking, stored.Status.State)
+	assert.Equal(t, uint64(2), stored.Version)
+
+	updated, err = store.Update(ContextWithExpectedVersion(ctx, 2), "a", func(task *protocol.Task) error {
+		task.Status.State = protocol.TaskStateCanceled
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), updated.Version)
+
+	// Version 0 expects the task not to exist.
+	err = store.Put(ContextWithExpectedVersion(ctx, 0), protocol.NewTask("a", nil))
+	require.True(t, errors.As(err, &rpcErr))
+	assert.Equal(t, ErrCodeTaskVersionConflict, rpcErr.Code)
+	require.NoError(t, store.Put(ContextWithExpectedVersion(ctx, 0), protocol.NewTask("b", nil)))
+}
+
+func TestMemoryTaskManager_ExpectedVersion(t *testing.T) {
+	processor := &mockProcessor{
+		processFunc: func(ctx context.Context, taskID string, msg protocol.Message, handle TaskHandle) error {
+			return handle.UpdateStatus(protocol.TaskStateInputRequired, nil)
+		},
+	}
+	tm, err := NewMemoryTaskManager(processor)
+	require.NoError(t, err)
+	ctx := context.Background()
+	task, err := tm.OnSendTask(ctx, protocol.SendTaskParams{
+		ID:      "versioned",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")}),
+	})
+	require.NoError(t, err)
+	require.NotZero(t, task.Version)
+
+	_, err = tm.OnCancelTask(ContextWithExpectedVersion(ctx, task.Version-1), protocol.TaskIDParams{ID: "versioned"})
+	var rpcErr *jsonrpc.Error
+	require.True(t, errors.As(err, &rpcErr))
+	assert.Equal(t, ErrCodeTaskVersionConflict, rpcErr.Code)
+
+	canceled, err := tm.OnCancelTask(ContextWithExpectedVersion(ctx, task.Version), protocol.TaskIDParams{ID: "versioned"})
+	require.NoError(t, err)
+	assert.Equal(t, protocol.TaskStateCanceled, canceled.Status.State)
+	assert.Equal(t, task.Version+1, canceled.Version)
+}
+
+func TestMemoryTaskManager_CancelVersionConflict(t *testing.T) {
+	release := make(chan struct{})
+	canceled := make(chan struct{})
+	processor := &mockProcessor{
+		processFunc: func(ctx context.Context, taskID string, msg protocol.Message, handle TaskHandle) error {
+			select {
+			case <-ctx.Done():
+				close(canceled)
+				return ctx.Err()
+			case <-release:
+				return handle.UpdateStatus(protocol.TaskStateCompleted, nil)
+			}
+		},
+	}
+	tm, err := NewMemoryTaskManager(processor)
+	require.NoError(t, err)
+	ctx := context.Background()
+	events, err := tm.OnSendTaskSubscribe(ctx, protocol.SendTaskParams{
+		ID:      "running",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")}),
+	})
+	require.NoError(t, err)
+	var task *protocol.Task
+	require.Eventually(t, func() bool {
+		task, err = tm.OnGetTask(ctx, protocol.TaskQueryParams{ID: "running"})
+		return err == nil && task.Status.State == protocol.TaskStateWorking
+	}, time.Second, 5*time.Millisecond)
+
+	// A conflicting cancellation leaves the processor running.
+	_, err = tm.OnCancelTask(ContextWithExpectedVersion(ctx, task.Version-1), protocol.TaskIDParams{ID: "running"})
+	var rpcErr *jsonrpc.Error
+	require.True(t, errors.As(err, &rpcErr))
+	assert.Equal(t, ErrCodeTaskVersionConflict, rpcErr.Code)
+	close(release)
+	for range events {
+	}
+	select {
+	case <-canceled:
+		t.Fatal("processor canceled by a conflicting cancellation")
+	default:
+	}
+	task, err = tm.OnGetTask(ctx, protocol.TaskQueryParams{ID: "running"})
+	require.NoError(t, err)
+	assert.Equal(t, protocol.TaskStateCompleted, task.Status.State)
+}
@@ -0,0 +1,61 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"sync"
+
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// warningsKey is the context key of the warnings of a request.
+type warningsKey struct{}
+
+// warningSink collects the warnings of a request.
+type warningSink struct {
+	mu       sync.Mutex
+	warnings []protocol.Warning
+}
+
+// ContextWithWarnings returns a copy of ctx collecting the warnings reported
+// with Warn. The server gives every request such a context.
+func ContextWithWarnings(ctx context.Context) context.Context {
+	return context.WithValue(ctx, warningsKey{}, &warningSink{})
+}
+
+// Warn reports non-fatal issues of the request handled with ctx, such as the
+// use of a fallback. The server returns them under protocol.MetadataKeyWarnings
+// of the task in the response, or of the next event of a stream, without
+// storing them with the task. Warn does nothing if ctx collects no warnings.
+func Warn(ctx context.Context, warnings ...protocol.Warning) {
+	sink, ok := ctx.Value(warningsKey{}).(*warningSink)
+	if !ok {
+		return
+	}
+	for _, w := range warnings {
+		log.Debugf("Request warning %s: %s", w.Code, w.Message)
+	}
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	sink.warnings = append(sink.warnings, warnings...)
+}
+
+// TakeWarnings returns the warnings reported with ctx since the last call and
+// forgets them.
+func TakeWarnings(ctx context.Context) []protocol.Warning {
+	sink, ok := ctx.Value(warningsKey{}).(*warningSink)
+	if !ok {
+		return nil
+	}
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	warnings := sink.warnings
+	sink.warnings = nil
+	return warnings
+}
@@ -0,0 +1,262 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// defaultWatchBuffer is the number of changes buffered for each watcher.
+const defaultWatchBuffer = 64
+
+// TaskChangeType is the kind of a change of a task store.
+type TaskChangeType string
+
+// TaskChangeType constants define the changes of a task store.
+const (
+	// TaskCreated is the change of a task stored for the first time.
+	TaskCreated TaskChangeType = "create"
+	// TaskUpdated is the change of an existing task.
+	TaskUpdated TaskChangeType = "update"
+	// TaskDeleted is the change of a deleted task.
+	TaskDeleted TaskChangeType = "delete"
+)
+
+// TaskChange is a change of a task store.
+type TaskChange struct {
+	// Revision numbers the changes of the store from 1, in their order.
+	Revision uint64 `json:"revision"`
+	// Type is the kind of change.
+	Type TaskChangeType `json:"type"`
+	// TaskID is the ID of the task changed.
+	TaskID string `json:"taskId"`
+	// Task is the task after the change, nil for deletions.
+	Task *protocol.Task `json:"task,omitempty"`
+	// Time is when the change was made.
+	Time time.Time `json:"time"`
+}
+
+// TaskWatcher is implemented by task managers and task stores that stream
+// the changes of their tasks, e.g. to maintain external indexes, dashboards or
+// replicas, as used by the admin API of the server.
+type TaskWatcher interface {
+	// WatchTasks returns the changes of the tasks made after the call, in
+	// their order, until ctx is done or the watcher falls behind, when the
+	// channel is closed. Watchers falling behind can list the tasks to resync
+	// and watch again.
+	WatchTasks(ctx context.Context) (<-chan TaskChange, error)
+}
+
+// maxTaskCreateAttempts is the number of times a task is looked up then
+// created, when other writers keep creating or deleting it in between.
+const maxTaskCreateAttempts = 3
+
+// WatchableTaskStore is a TaskStore streaming the changes made through it to
+// its watchers. The MemoryTaskManager wraps its store with it. While the store
+// is watched its changes are serialized, so that watchers receive them in
+// order; otherwise they run concurrently, as in the wrapped store.
+//
+// The store also versions the tasks: each change increments the Version of
+// the task, and changes made with a context of ContextWithExpectedVersion
+// fail with ErrTaskVersionConflict if the task is at another version. Updates,
+// and Puts replacing a task, check the version within the atomic Update of the
+// wrapped store, and Puts creating a task create it with CreateTask, so that
+// replicas sharing the wrapped store detect their conflicts as long as it
+// implements TaskCreator.
+type WatchableTaskStore struct {
+	store TaskStore
+
+	revision atomic.Uint64
+	watching atomic.Int64 // Number of watchers.
+
+	mu       sync.Mutex // Serializes changes while watched, guards watchers.
+	watchers map[chan TaskChange]struct{}
+}
+
+// NewWatchableTaskStore wraps store to stream its changes.
+func NewWatchableTaskStore(store TaskStore) *WatchableTaskStore {
+	return &WatchableTaskStore{store: store, watchers: make(map[chan TaskChange]struct{})}
+}
+
+// Get implements TaskStore.
+func (s *WatchableTaskStore) Get(ctx context.Context, taskID string) (*protocol.Task, error) {
+	return s.store.Get(ctx, taskID)
+}
+
+// Put implements TaskStore. It replaces the task with an Update of the wrapped
+// store to version it, and creates it with CreateTask if it does not exist.
+func (s *WatchableTaskStore) Put(ctx context.Context, task *protocol.Task) error {
+	watched := s.lock()
+	defer s.unlock(watched)
+	for attempt := 1; ; attempt++ {
+		updated, err := s.store.Update(ctx, task.ID, func(existing *protocol.Task) error {
+			if err := CheckTaskVersion(ctx, task.ID, existing); err != nil {
+				return err
+			}
+			version := existing.Version
+			*existing = *task
+			existing.Version = version + 1
+			return nil
+		})
+		if err == nil {
+			task.Version = updated.Version
+			s.publish(watched, TaskUpdated, task.ID, task)
+			return nil
+		}
+		if !IsTaskNotFound(err) {
+			return err
+		}
+		if err := CheckTaskVersion(ctx, task.ID, nil); err != nil {
+			return err
+		}
+		// Replace the task if another writer creates it first.
+		err = s.create(ctx, watched, task)
+		if !isTaskExists(err) || attempt == maxTaskCreateAttempts {
+			return err
+		}
+	}
+}
+
+// Create implements TaskCreator. The task created is at version 1.
+func (s *WatchableTaskStore) Create(ctx context.Context, task *protocol.Task) error {
+	watched := s.lock()
+	defer s.unlock(watched)
+	return s.create(ctx, watched, task)
+}
+
+// create creates task in the wrapped store and publishes it.
+func (s *WatchableTaskStore) create(ctx context.Context, watched bool, task *protocol.Task) error {
+	version := task.Version
+	task.Version = 1
+	if err := CreateTask(ctx, s.store, task); err != nil {
+		task.Version = version
+		return err
+	}
+	s.publish(watched, TaskCreated, task.ID, task)
+	return nil
+}
+
+// List implements TaskStore.
+func (s *WatchableTaskStore) List(ctx context.Context, filter TaskFilter) ([]protocol.Task, error) {
+	return s.store.List(ctx, filter)
+}
+
+// Delete implements TaskStore.
+func (s *WatchableTaskStore) Delete(ctx context.Context, taskID string) error {
+	watched := s.lock()
+	defer s.unlock(watched)
+	if err := s.store.Delete(ctx, taskID); err != nil {
+		return err
+	}
+	s.publish(watched, TaskDeleted, taskID, nil)
+	return nil
+}
+
+// Update implements TaskStore.
+func (s *WatchableTaskStore) Update(
+	ctx context.Context,
+	taskID string,
+	update func(task *protocol.Task) error,
+) (*protocol.Task, error) {
+	watched := s.lock()
+	defer s.unlock(watched)
+	task, err := s.store.Update(ctx, taskID, func(task *protocol.Task) error {
+		if err := CheckTaskVersion(ctx, taskID, task); err != nil {
+			return err
+		}
+		version := task.Version
+		if err := update(task); err != nil {
+			return err
+		}
+		task.Version = version + 1
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.publish(watched, TaskUpdated, taskID, task)
+	return task, nil
+}
+
+// WatchTasks implements TaskWatcher.
+func (s *WatchableTaskStore) WatchTasks(ctx context.Context) (<-chan TaskChange, error) {
+	ch := make(chan TaskChange, defaultWatchBuffer)
+	s.mu.Lock()
+	s.watchers[ch] = struct{}{}
+	s.watching.Add(1)
+	s.mu.Unlock()
+	context.AfterFunc(ctx, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.unwatch(ch)
+	})
+	return ch, nil
+}
+
+// lock serializes a change if the store is watched, and reports whether it
+// is.
+func (s *WatchableTaskStore) lock() bool {
+	if s.watching.Load() == 0 {
+		return false
+	}
+	s.mu.Lock()
+	return true
+}
+
+// unlock ends a change started by lock.
+func (s *WatchableTaskStore) unlock(watched bool) {
+	if watched {
+		s.mu.Unlock()
+	}
+}
+
+// publish numbers a change and, if the store is watched, sends a copy of the
+// task to the watchers, dropping those whose buffer is full. The caller must
+// hold mu if watched.
+func (s *WatchableTaskStore) publish(watched bool, changeType TaskChangeType, taskID string, task *protocol.Task) {
+	revision := s.revision.Add(1)
+	if !watched || len(s.watchers) == 0 {
+		return
+	}
+	change := TaskChange{
+		Revision: revision,
+		Type:     changeType,
+		TaskID:   taskID,
+		Task:     task.Clone(),
+		Time:     time.Now().UTC(),
+	}
+	for ch := range s.watchers {
+		select {
+		case ch <- change:
+		default:
+			log.Warnf("Task watcher fell behind at revision %d, closing it", change.Revision)
+			s.unwatch(ch)
+		}
+	}
+}
+
+// unwatch removes and closes the channel of a watcher, if it is still
+// watching. The caller must hold mu.
+func (s *WatchableTaskStore) unwatch(ch chan TaskChange) {
+	if _, ok := s.watchers[ch]; ok {
+		delete(s.watchers, ch)
+		s.watching.Add(-1)
+		close(ch)
+	}
+}
+
+// WatchTasks implements TaskWatcher, streaming the changes of the store of the
+// task manager.
+func (m *MemoryTaskManager) WatchTasks(ctx context.Context) (<-chan TaskChange, error) {
+	return m.watch.WatchTasks(ctx)
+}
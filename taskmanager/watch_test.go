@@ -0,0 +1,107 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/internal/jsonrpc"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+func TestMemoryTaskManager_WatchTasks(t *testing.T) {
+	processor := &mockProcessor{
+		processFunc: func(ctx context.Context, taskID string, msg protocol.Message, handle TaskHandle) error {
+			return handle.UpdateStatus(protocol.TaskStateCompleted, nil)
+		},
+	}
+	tm, err := NewMemoryTaskManager(processor)
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	changes, err := tm.WatchTasks(ctx)
+	require.NoError(t, err)
+
+	_, err = tm.OnSendTask(context.Background(), protocol.SendTaskParams{
+		ID:      "watched",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("hi")}),
+	})
+	require.NoError(t, err)
+	var states []protocol.TaskState
+	for i, want := range []TaskChangeType{TaskCreated, TaskUpdated, TaskUpdated} {
+		change := <-changes
+		assert.Equal(t, uint64(i+1), change.Revision)
+		assert.Equal(t, want, change.Type)
+		assert.Equal(t, "watched", change.TaskID)
+		states = append(states, change.Task.Status.State)
+	}
+	assert.Equal(t, []protocol.TaskState{
+		protocol.TaskStateSubmitted, protocol.TaskStateWorking, protocol.TaskStateCompleted,
+	}, states)
+
+	cancel()
+	for range changes {
+	}
+}
+
+func TestWatchableTaskStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewWatchableTaskStore(&mapTaskStore{tasks: make(map[string]protocol.Task)})
+	changes, err := store.WatchTasks(ctx)
+	require.NoError(t, err)
+
+	task := protocol.NewTask("a", nil)
+	require.NoError(t, store.Put(ctx, task))
+	require.NoError(t, store.Put(ctx, task))
+	require.NoError(t, store.Delete(ctx, "a"))
+	_, err = store.Update(ctx, "a", func(task *protocol.Task) error { return nil })
+	assert.True(t, IsTaskNotFound(err), "failed changes are not streamed")
+	for _, want := range []TaskChangeType{TaskCreated, TaskUpdated, TaskDeleted} {
+		change := <-changes
+		assert.Equal(t, want, change.Type)
+	}
+	assert.Empty(t, changes, "no more changes")
+
+	// The changes carry copies of the tasks.
+	task.Metadata = map[string]interface{}{"labels": []interface{}{"a"}}
+	require.NoError(t, store.Put(ctx, task))
+	_, err = store.Update(ctx, "a", func(task *protocol.Task) error {
+		task.Metadata["labels"].([]interface{})[0] = "b"
+		return nil
+	})
+	require.NoError(t, err)
+	change := <-changes
+	assert.Equal(t, TaskCreated, change.Type)
+	assert.Equal(t, []interface{}{"a"}, change.Task.Metadata["labels"])
+	<-changes
+
+	// Tasks are only created if they do not exist.
+	require.NoError(t, store.Create(ctx, protocol.NewTask("b", nil)))
+	err = store.Create(ctx, protocol.NewTask("b", nil))
+	var rpcErr *jsonrpc.Error
+	require.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, ErrCodeTaskExists, rpcErr.Code)
+	change = <-changes
+	assert.Equal(t, TaskCreated, change.Type)
+	assert.Equal(t, uint64(1), change.Task.Version)
+	assert.Empty(t, changes, "failed creations are not streamed")
+
+	// Watchers falling behind are closed.
+	slow, err := store.WatchTasks(ctx)
+	require.NoError(t, err)
+	for i := 0; i <= defaultWatchBuffer; i++ {
+		require.NoError(t, store.Put(ctx, task))
+	}
+	var received int
+	for range slow {
+		received++
+	}
+	assert.Equal(t, defaultWatchBuffer, received)
+}
@@ -0,0 +1,392 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// MetadataKeyWorkerResult is the metadata key of the status event a Worker
+// publishes when the processor of a task returns, holding the error message
+// of the processor, or "" if it succeeded. The status of the event is not
+// meant to be applied to the task.
+const MetadataKeyWorkerResult = "workerResult"
+
+// MetadataKeyWorkerArtifact is the metadata key of the artifact events a
+// Worker publishes, holding their number in the processing of the task, from
+// 1. A task delivered again publishes its artifacts again from 1, and the
+// QueueProcessor skips the artifacts it already applied.
+const MetadataKeyWorkerArtifact = "workerArtifact"
+
+const (
+	// defaultWorkerHeartbeat is the default interval of the deliveries
+	// extensions of a Worker.
+	defaultWorkerHeartbeat = 10 * time.Second
+	// defaultWorkerRetryDelay is the default delay before a Worker retries
+	// after a queue failure.
+	defaultWorkerRetryDelay = time.Second
+)
+
+// QueueProcessor is the TaskProcessor of the API tier of an agent split into
+// an API tier and a worker tier. Instead of processing the tasks, it enqueues
+// them into a WorkQueue, and applies the events the Worker processing them
+// publishes to an EventBus through the handle of the task manager, until the
+// processor of the worker returns:
+//
+//	queue, bus := natsjs.NewWorkQueue(js, natsjs.Config{}), natsjs.NewEventBus(js, natsjs.Config{})
+//	tm, err := taskmanager.NewMemoryTaskManager(taskmanager.NewQueueProcessor(queue, bus))
+//
+// The task manager thus keeps storing the tasks and streaming their events,
+// and the tasks canceled through it are canceled on their worker; a task
+// canceled before a worker picks it up is still processed, and its events
+// ignored. The API server relaying the events of a task must stay up until
+// it is processed.
+type QueueProcessor struct {
+	queue WorkQueue
+	bus   EventBus
+}
+
+// NewQueueProcessor creates a processor submitting the tasks to queue and
+// following their processing on bus.
+func NewQueueProcessor(queue WorkQueue, bus EventBus) *QueueProcessor {
+	return &QueueProcessor{queue: queue, bus: bus}
+}
+
+// Process implements TaskProcessor.
+func (p *QueueProcessor) Process(
+	ctx context.Context,
+	taskID string,
+	message protocol.Message,
+	handle TaskHandle,
+) error {
+	// Subscribe before enqueuing, not to miss the first events.
+	subCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancel()
+	events, err := p.bus.Subscribe(subCtx, taskID)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to the events of task %s: %w", taskID, err)
+	}
+	if err := p.queue.Enqueue(ctx, QueuedTask{
+		Params:    protocol.SendTaskParams{ID: taskID, Message: message},
+		Streaming: handle.IsStreamingRequest(),
+	}); err != nil {
+		return err
+	}
+	applier := &workerEventApplier{taskID: taskID, handle: handle}
+	for {
+		select {
+		case <-ctx.Done():
+			p.cancelTask(subCtx, taskID, ctx)
+			return context.Cause(ctx)
+		case event, ok := <-events:
+			if !ok {
+				return fmt.Errorf("events of task %s ended before its processing", taskID)
+			}
+			if done, err := applier.apply(event); done {
+				return err
+			}
+		}
+	}
+}
+
+// cancelTask publishes the cancellation of taskID to its worker, with the
+// reason of the cancellation of ctx.
+func (p *QueueProcessor) cancelTask(busCtx context.Context, taskID string, ctx context.Context) {
+	reason, ok := CancelReasonFromContext(ctx)
+	if !ok {
+		reason = protocol.CancelReasonUserRequested
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			reason = protocol.CancelReasonTimeout
+		}
+	}
+	event := protocol.TaskStatusUpdateEvent{
+		ID:     taskID,
+		Status: protocol.TaskStatus{State: protocol.TaskStateCanceled, CancelReason: reason},
+		Final:  true,
+	}
+	if err := p.bus.Publish(busCtx, taskID, event); err != nil {
+		log.Warnf("Failed to publish the cancellation of task %s to its worker: %v", taskID, err)
+	}
+}
+
+// workerEventApplier applies the events published by the worker of a task
+// to the task through the handle of the task manager.
+type workerEventApplier struct {
+	taskID string
+	handle TaskHandle
+	// artifacts is the number of artifacts applied.
+	artifacts int
+}
+
+// apply applies event. It reports whether the processor of the worker
+// returned, with its error.
+func (a *workerEventApplier) apply(event protocol.TaskEvent) (bool, error) {
+	var err error
+	switch e := event.(type) {
+	case protocol.TaskStatusUpdateEvent:
+		if result, ok := e.Metadata[MetadataKeyWorkerResult]; ok {
+			if message, _ := result.(string); message != "" {
+				return true, errors.New(message)
+			}
+			return true, nil
+		}
+		if e.Status.State == protocol.TaskStateCanceled {
+			// Cancellations are published to the worker, not by it.
+			return false, nil
+		}
+		if updater, ok := a.handle.(StatusMetadataUpdater); ok && len(e.Metadata) > 0 {
+			err = updater.UpdateStatusWithMetadata(e.Status.State, e.Status.Message, e.Metadata)
+		} else {
+			err = a.handle.UpdateStatus(e.Status.State, e.Status.Message)
+		}
+	case protocol.TaskArtifactUpdateEvent:
+		if n, ok := workerArtifactNumber(e.Metadata); ok {
+			if n <= a.artifacts {
+				// Published again by a redelivery of the task.
+				return false, nil
+			}
+			a.artifacts = n
+		}
+		err = a.handle.AddArtifact(e.Artifact)
+	}
+	if err != nil {
+		log.Warnf("Failed to apply an event of the worker of task %s: %v", a.taskID, err)
+	}
+	return false, nil
+}
+
+// workerArtifactNumber returns the number recorded under
+// MetadataKeyWorkerArtifact in metadata, as set or as decoded from JSON.
+func workerArtifactNumber(metadata map[string]interface{}) (int, bool) {
+	switch n := metadata[MetadataKeyWorkerArtifact].(type) {
+	case int:
+		return n, true
+	case float64:
+		return int(n), true
+	}
+	return 0, false
+}
+
+// WorkerConfig configures a Worker.
+type WorkerConfig struct {
+	// Concurrency is the number of tasks processed concurrently, 1 by default.
+	Concurrency int
+	// Heartbeat is the interval at which the deliveries being processed are
+	// extended, 10s by default. It must be shorter than the redelivery
+	// timeout of the queue.
+	Heartbeat time.Duration
+	// RetryDelay is the delay before dequeuing again after the queue failed,
+	// and before a task whose events could not be published is delivered
+	// again, 1s by default.
+	RetryDelay time.Duration
+}
+
+// Worker is the worker tier of an agent split into an API tier and a worker
+// tier, see QueueProcessor. It dequeues the tasks from a WorkQueue, runs
+// them with the processor of the agent, and publishes their events to an
+// EventBus. Tasks being processed when the worker stops are returned to the
+// queue.
+type Worker struct {
+	queue     WorkQueue
+	bus       EventBus
+	processor TaskProcessor
+	cfg       WorkerConfig
+}
+
+// NewWorker creates a worker processing the tasks of queue with processor.
+func NewWorker(queue WorkQueue, bus EventBus, processor TaskProcessor, cfg WorkerConfig) (*Worker, error) {
+	if queue == nil || bus == nil {
+		return nil, errors.New("taskmanager: worker requires a queue and an event bus")
+	}
+	if processor == nil {
+		return nil, errors.New("taskmanager: worker requires a processor")
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 1
+	}
+	if cfg.Heartbeat <= 0 {
+		cfg.Heartbeat = defaultWorkerHeartbeat
+	}
+	if cfg.RetryDelay <= 0 {
+		cfg.RetryDelay = defaultWorkerRetryDelay
+	}
+	return &Worker{queue: queue, bus: bus, processor: processor, cfg: cfg}, nil
+}
+
+// Run processes tasks until ctx is done, then returns once the tasks being
+// processed are returned to the queue, closing the queue if it is an
+// io.Closer.
+func (w *Worker) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	for i := 0; i < w.cfg.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.loop(ctx)
+		}()
+	}
+	wg.Wait()
+	if closer, ok := w.queue.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// loop processes tasks one at a time until ctx is done.
+func (w *Worker) loop(ctx context.Context) {
+	for ctx.Err() == nil {
+		delivery, err := w.queue.Dequeue(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Errorf("Failed to dequeue a task: %v", err)
+				sleep(ctx, w.cfg.RetryDelay)
+			}
+			continue
+		}
+		if err := w.Process(ctx, delivery); err != nil && ctx.Err() == nil {
+			log.Errorf("Failed to process task %s: %v", delivery.Task().Params.ID, err)
+		}
+	}
+}
+
+// Process runs the task of delivery and publishes its events, acknowledging
+// the delivery once done. If ctx is done before the processor returns, the
+// task is returned to the queue. The error of the processor is published to
+// the API tier, not returned.
+func (w *Worker) Process(ctx context.Context, delivery QueueDelivery) error {
+	task := delivery.Task()
+	taskID := task.Params.ID
+	taskCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	// Publishing and acknowledging outlive the cancellation of the task.
+	busCtx := context.WithoutCancel(ctx)
+
+	events, err := w.bus.Subscribe(taskCtx, taskID)
+	if err != nil {
+		w.nack(busCtx, delivery, w.cfg.RetryDelay)
+		return fmt.Errorf("failed to subscribe to the events of task %s: %w", taskID, err)
+	}
+	go func() {
+		for event := range events {
+			if e, ok := event.(protocol.TaskStatusUpdateEvent); ok && e.Status.State == protocol.TaskStateCanceled {
+				cancel(&CancelError{TaskID: taskID, Reason: NormalizeCancelReason(e.Status.CancelReason)})
+			}
+		}
+	}()
+	go w.heartbeat(taskCtx, delivery)
+
+	handle := &workerTaskHandle{ctx: busCtx, bus: w.bus, taskID: taskID, streaming: task.Streaming}
+	processErr := w.processor.Process(taskCtx, taskID, task.Params.Message, handle)
+	if ctx.Err() != nil {
+		// The worker is stopping: another worker processes the task again.
+		w.nack(busCtx, delivery, 0)
+		return ctx.Err()
+	}
+	var result string
+	if processErr != nil {
+		result = processErr.Error()
+	}
+	if err := w.bus.Publish(busCtx, taskID, protocol.TaskStatusUpdateEvent{
+		ID:       taskID,
+		Metadata: map[string]interface{}{MetadataKeyWorkerResult: result},
+	}); err != nil {
+		w.nack(busCtx, delivery, w.cfg.RetryDelay)
+		return fmt.Errorf("failed to publish the result of task %s: %w", taskID, err)
+	}
+	return delivery.Ack(busCtx)
+}
+
+// heartbeat extends delivery until ctx is done.
+func (w *Worker) heartbeat(ctx context.Context, delivery QueueDelivery) {
+	ticker := time.NewTicker(w.cfg.Heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := delivery.Extend(ctx); err != nil && ctx.Err() == nil {
+				log.Warnf("Failed to extend the delivery of task %s: %v", delivery.Task().Params.ID, err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// nack returns delivery to the queue after delay, logging failures.
+func (w *Worker) nack(ctx context.Context, delivery QueueDelivery, delay time.Duration) {
+	if err := delivery.Nack(ctx, delay); err != nil {
+		log.Errorf("Failed to return task %s to the queue: %v", delivery.Task().Params.ID, err)
+	}
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
+
+// workerTaskHandle is the TaskHandle of the tasks run by a Worker, which
+// publishes the updates to the event bus. The state transitions are checked
+// by the task manager of the API tier applying them.
+type workerTaskHandle struct {
+	ctx       context.Context
+	bus       EventBus
+	taskID    string
+	streaming bool
+
+	mu        sync.Mutex // Numbers the artifacts in order of publication.
+	artifacts int        // Number of artifacts published.
+}
+
+// UpdateStatus implements TaskHandle.
+func (h *workerTaskHandle) UpdateStatus(state protocol.TaskState, msg *protocol.Message) error {
+	return h.UpdateStatusWithMetadata(state, msg, nil)
+}
+
+// UpdateStatusWithMetadata implements StatusMetadataUpdater.
+func (h *workerTaskHandle) UpdateStatusWithMetadata(
+	state protocol.TaskState,
+	msg *protocol.Message,
+	metadata map[string]interface{},
+) error {
+	return h.bus.Publish(h.ctx, h.taskID, protocol.TaskStatusUpdateEvent{
+		ID:       h.taskID,
+		Status:   protocol.TaskStatus{State: state, Message: msg},
+		Final:    isFinalState(state),
+		Metadata: metadata,
+	})
+}
+
+// AddArtifact implements TaskHandle.
+func (h *workerTaskHandle) AddArtifact(artifact protocol.Artifact) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.artifacts++
+	return h.bus.Publish(h.ctx, h.taskID, protocol.TaskArtifactUpdateEvent{
+		ID:       h.taskID,
+		Artifact: artifact,
+		Final:    artifact.LastChunk != nil && *artifact.LastChunk,
+		Metadata: map[string]interface{}{MetadataKeyWorkerArtifact: h.artifacts},
+	})
+}
+
+// IsStreamingRequest implements TaskHandle.
+func (h *workerTaskHandle) IsStreamingRequest() bool {
+	return h.streaming
+}
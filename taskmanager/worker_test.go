@@ -0,0 +1,145 @@
+// Tencent is pleased to support the open source community by making trpc-a2a-go available.
+//
+// Copyright (C) 2025 THL A29 Limited, a Tencent company.  All rights reserved.
+//
+// trpc-a2a-go is licensed under the Apache License Version 2.0.
+
+package taskmanager
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// startWorker runs a worker of processor on queue and bus until the test ends.
+func startWorker(t *testing.T, queue WorkQueue, bus EventBus, processor TaskProcessor) {
+	worker, err := NewWorker(queue, bus, processor, WorkerConfig{Concurrency: 2, Heartbeat: 10 * time.Millisecond})
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- worker.Run(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		require.NoError(t, <-done)
+	})
+}
+
+func TestWorker(t *testing.T) {
+	queue, bus := NewMemoryWorkQueue(), NewMemoryEventBus()
+	waiting := make(chan struct{})
+	startWorker(t, queue, bus, &mockProcessor{
+		processFunc: func(ctx context.Context, taskID string, msg protocol.Message, handle TaskHandle) error {
+			switch text := msg.Parts[0].(protocol.TextPart).Text; text {
+			case "fail":
+				return errors.New("processor failed")
+			case "wait":
+				close(waiting)
+				<-ctx.Done()
+				reason, _ := CancelReasonFromContext(ctx)
+				return errors.New(string(reason))
+			default:
+				if err := handle.AddArtifact(protocol.Artifact{Parts: []protocol.Part{protocol.NewTextPart(text)}}); err != nil {
+					return err
+				}
+				return handle.UpdateStatus(protocol.TaskStateCompleted, nil)
+			}
+		},
+	})
+	tm, err := NewMemoryTaskManager(NewQueueProcessor(queue, bus))
+	require.NoError(t, err)
+	send := func(taskID, text string) (*protocol.Task, error) {
+		return tm.OnSendTask(context.Background(), protocol.SendTaskParams{
+			ID:      taskID,
+			Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart(text)}),
+		})
+	}
+
+	task, err := send("task-1", "echo")
+	require.NoError(t, err)
+	assert.Equal(t, protocol.TaskStateCompleted, task.Status.State)
+	require.Len(t, task.Artifacts, 1)
+	assert.Equal(t, "echo", task.Artifacts[0].Parts[0].(protocol.TextPart).Text)
+
+	task, err = send("task-2", "fail")
+	assert.EqualError(t, err, "processor failed")
+	assert.Equal(t, protocol.TaskStateFailed, task.Status.State)
+
+	// Tasks canceled by the API tier are canceled on their worker.
+	events, err := tm.OnSendTaskSubscribe(context.Background(), protocol.SendTaskParams{
+		ID:      "task-3",
+		Message: protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("wait")}),
+	})
+	require.NoError(t, err)
+	<-events // Working.
+	<-waiting
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	results, err := bus.Subscribe(ctx, "task-3")
+	require.NoError(t, err)
+	_, err = tm.OnCancelTask(context.Background(), protocol.TaskIDParams{ID: "task-3", Reason: protocol.CancelReasonPolicy})
+	require.NoError(t, err)
+	for event := range results {
+		status, ok := event.(protocol.TaskStatusUpdateEvent)
+		if ok && status.Metadata[MetadataKeyWorkerResult] != nil {
+			assert.Equal(t, string(protocol.CancelReasonPolicy), status.Metadata[MetadataKeyWorkerResult])
+			break
+		}
+	}
+}
+
+func TestWorker_Stop(t *testing.T) {
+	queue, bus := NewMemoryWorkQueue(), NewMemoryEventBus()
+	started := make(chan struct{})
+	worker, err := NewWorker(queue, bus, &mockProcessor{
+		processFunc: func(ctx context.Context, taskID string, msg protocol.Message, handle TaskHandle) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}, WorkerConfig{})
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- worker.Run(ctx) }()
+	require.NoError(t, queue.Enqueue(ctx, QueuedTask{Params: protocol.SendTaskParams{ID: "task-1"}}))
+	<-started
+	cancel()
+	require.NoError(t, <-done)
+
+	// The task being processed is returned to the queue.
+	next, cancelNext := context.WithTimeout(context.Background(), time.Second)
+	defer cancelNext()
+	delivery, err := queue.Dequeue(next)
+	require.NoError(t, err)
+	assert.Equal(t, "task-1", delivery.Task().Params.ID)
+}
+
+func TestWorkerEventApplier_Redelivery(t *testing.T) {
+	handle := &recordingHandle{}
+	applier := &workerEventApplier{taskID: "task-1", handle: handle}
+	artifact := func(n int, text string) protocol.TaskEvent {
+		return protocol.TaskArtifactUpdateEvent{
+			ID:       "task-1",
+			Artifact: protocol.Artifact{Parts: []protocol.Part{protocol.NewTextPart(text)}},
+			Metadata: map[string]interface{}{MetadataKeyWorkerArtifact: float64(n)},
+		}
+	}
+	// The task is delivered again after its first worker published two
+	// artifacts: the second worker publishes them again.
+	for _, event := range []protocol.TaskEvent{
+		artifact(1, "a"), artifact(2, "b"), artifact(1, "a"), artifact(2, "b"), artifact(3, "c"),
+	} {
+		done, err := applier.apply(event)
+		require.NoError(t, err)
+		assert.False(t, done)
+	}
+	require.Len(t, handle.artifacts, 3)
+	assert.Equal(t, protocol.NewTextPart("c"), handle.artifacts[2].Parts[0])
+}